@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/application/auth"
+	"github.com/esfisher/jiramd/internal/application/push"
+	"github.com/esfisher/jiramd/internal/config"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/jira"
+	"github.com/esfisher/jiramd/internal/infrastructure/keyring"
+	"github.com/esfisher/jiramd/internal/infrastructure/logging"
+	"github.com/esfisher/jiramd/internal/infrastructure/notify"
+	"github.com/esfisher/jiramd/internal/infrastructure/postgres"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+// defaultConfigPath describes the config file used when neither --config, JIRAMD_CONFIG,
+// nor a project-local .jiramd.yaml is found.
+const defaultConfigPath = "$XDG_CONFIG_HOME/jiramd/config.yaml (~/.config/jiramd/config.yaml)"
+
+// loadConfig loads and validates the configuration resolved from the global --config
+// and --set flags, JIRAMD_* environment variables, the config file, and defaults.
+func loadConfig() (*domain.Config, error) {
+	opts, err := configOptions()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadWith(opts)
+	if err != nil {
+		return nil, err
+	}
+	timeDisplay = cfg.Display
+	return cfg, nil
+}
+
+// configOptions returns where loadConfig resolves configuration from. The file is
+// --config, then $JIRAMD_CONFIG, then the nearest .jiramd.yaml above the working
+// directory, then the default path, which may be missing so configuration can come
+// from the environment alone.
+func configOptions() (config.Options, error) {
+	opts := config.Discover(configFile, ".")
+
+	if len(configSets) > 0 {
+		opts.Overrides = make(map[string]string, len(configSets))
+		for _, set := range configSets {
+			key, value, ok := strings.Cut(set, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return opts, fmt.Errorf("invalid --set %q: expected key=value", set)
+			}
+			opts.Overrides[strings.TrimSpace(key)] = value
+		}
+	}
+	return opts, nil
+}
+
+// nonInteractive is set by commands running unattended, e.g. sync --ci, so nothing
+// waits on a user: no progress bar is drawn and the keyring, which may ask to be
+// unlocked, is not used.
+var nonInteractive bool
+
+// openDatabase opens the state database configured in cfg and applies migrations.
+// The caller is responsible for closing the returned database.
+func openDatabase(ctx context.Context, cfg *domain.Config, logger *slog.Logger) (*sqlite.Database, error) {
+	dbConfig := sqlite.DefaultConfig()
+	dbConfig.Path = cfg.Storage.DBPath
+
+	if cfg.Storage.Encrypt {
+		if nonInteractive && os.Getenv(keyring.KeyEnvVar) == "" {
+			return nil, fmt.Errorf("%w: storage.encrypt needs the key in $%s when running non-interactively",
+				domain.ErrConfig, keyring.KeyEnvVar)
+		}
+		key, err := keyring.EncryptionKey(cfg.Storage.DBPath, sqlite.KeySize)
+		if err != nil {
+			return nil, err
+		}
+		if dbConfig.Cipher, err = sqlite.NewCipher(key); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := sqlite.NewDatabase(dbConfig, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+
+	if err := db.Migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Encrypt anything written before storage.encrypt was turned on
+	if _, err := db.EncryptPlaintext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := db.SetFilePermissions(); err != nil {
+		logger.Warn("could not restrict database file permissions", "error", err)
+	}
+
+	return db, nil
+}
+
+// openStateRepository returns the StateRepository selected by storage.driver.
+// db is the local database, which keeps the ticket cache and push queue with either driver.
+// The caller must call the returned close function when done.
+func openStateRepository(ctx context.Context, cfg *domain.Config, db *sqlite.Database, logger *slog.Logger) (repository.StateRepository, func() error, error) {
+	local := sqlite.NewStateRepository(db.DB(), logger)
+	if cfg.Storage.Driver != domain.StorageDriverPostgres {
+		return local, func() error { return nil }, nil
+	}
+
+	pg, err := postgres.NewDatabase(ctx, postgres.DefaultConfig(cfg.Storage.DSN), logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open postgres state database: %w", err)
+	}
+
+	if err := pg.Migrate(ctx); err != nil {
+		pg.Close()
+		return nil, nil, err
+	}
+
+	return &sharedStateRepository{
+		StateRepository: postgres.NewStateRepository(pg.DB(), logger),
+		local:           local,
+	}, pg.Close, nil
+}
+
+// sharedStateRepository stores sync state in PostgreSQL while keeping transactions
+// on the local database too, so services that queue operations or prune history in
+// the same transaction as a state change still do so atomically on each side.
+// The two commits are not atomic with each other: PostgreSQL commits first, and a
+// failure of the local commit afterwards leaves the shared state ahead of the queue.
+type sharedStateRepository struct {
+	*postgres.StateRepository
+	local *sqlite.StateRepository
+}
+
+// BeginTransaction starts a transaction on both databases.
+func (r *sharedStateRepository) BeginTransaction(ctx context.Context) (context.Context, error) {
+	localCtx, err := r.local.BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txCtx, err := r.StateRepository.BeginTransaction(localCtx)
+	if err != nil {
+		r.local.Rollback(localCtx)
+		return nil, err
+	}
+	return txCtx, nil
+}
+
+// Commit commits the shared state, then the local database.
+func (r *sharedStateRepository) Commit(ctx context.Context) error {
+	if err := r.StateRepository.Commit(ctx); err != nil {
+		return errors.Join(err, r.local.Rollback(ctx))
+	}
+	return r.local.Commit(ctx)
+}
+
+// Rollback rolls back both transactions.
+func (r *sharedStateRepository) Rollback(ctx context.Context) error {
+	return errors.Join(r.StateRepository.Rollback(ctx), r.local.Rollback(ctx))
+}
+
+// cliLogger returns the logger used by one-shot CLI commands.
+// Only warnings and errors are shown so command output stays readable (and valid JSON).
+func cliLogger() *slog.Logger {
+	return slog.New(logging.NewCorrelationHandler(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+}
+
+// discardLogger returns a logger that drops every record.
+func discardLogger() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// withState loads configuration, opens the state database and the configured
+// StateRepository, and runs fn with them. Both are closed when fn returns.
+func withState(ctx context.Context, fn func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error) error {
+	logger := cliLogger()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stateRepo, closeState, err := openStateRepository(ctx, cfg, db, logger)
+	if err != nil {
+		return err
+	}
+	defer closeState()
+
+	return fn(cfg, db, stateRepo)
+}
+
+// openAuthMonitor creates the monitor tracking whether Jira accepts the configured
+// credentials, loaded with the status stored in db and alerting through the configured
+// notify command.
+func openAuthMonitor(ctx context.Context, cfg *domain.Config, db *sqlite.Database, logger *slog.Logger) (*auth.Monitor, error) {
+	var notifier auth.Notifier
+	if cfg.Notify.Command != "" {
+		notifier = notify.NewCommand(cfg.Notify.Command)
+	}
+
+	monitor := auth.NewMonitor(sqlite.NewAuthStatusRepository(db.DB(), logger), notifier, logger)
+	if err := monitor.Load(ctx); err != nil {
+		return nil, err
+	}
+	return monitor, nil
+}
+
+// newMonitoredJiraClient creates the configured Jira client, reporting authentication
+// failures to the monitor stored in db.
+func newMonitoredJiraClient(ctx context.Context, cfg *domain.Config, db *sqlite.Database) (*jira.Client, error) {
+	logger := cliLogger()
+	monitor, err := openAuthMonitor(ctx, cfg, db, logger)
+	if err != nil {
+		return nil, err
+	}
+	return newJiraClient(cfg, monitor, logger)
+}
+
+// newJiraClient creates the configured Jira client, reporting authentication failures
+// to monitor.
+func newJiraClient(cfg *domain.Config, monitor *auth.Monitor, logger *slog.Logger) (*jira.Client, error) {
+	client, err := jira.NewClientFromConfig(cfg.Jira)
+	if err != nil {
+		return nil, err
+	}
+	return client.WithLogger(logger).
+		WithAuthObserver(monitor).
+		WithFieldDirections(cfg.Sync.FieldDirectionsFor).
+		WithStatusMap(cfg.Sync.Statuses).
+		WithPriorityMaps(cfg.Sync.PrioritiesFor).
+		WithRawFields(cfg.Markdown.RawFields).
+		WithAuthors(cfg.Markdown.Authors), nil
+}
+
+// newPushService creates the service applying the queued local changes to Jira through
+// client, held back while monitor says the credentials fail.
+func newPushService(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository, client *jira.Client, monitor *auth.Monitor, logger *slog.Logger) *push.Service {
+	queue := sqlite.NewPendingOperationRepository(db.DB(), logger).WithCipher(db.Cipher())
+	applier := jira.NewApplier(client).WithTickets(sqlite.NewTicketRepository(db.DB(), logger).WithCipher(db.Cipher()))
+	return push.NewService(queue, applier, sqlite.NewLockManager(db.DB(), logger), cfg.Sync.RetryPolicy(), logger).
+		WithAuthGate(monitor).
+		WithMode(cfg.Sync.Mode).
+		WithStates(stateRepo)
+}
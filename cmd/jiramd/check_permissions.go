@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/permission"
+	"github.com/esfisher/jiramd/internal/infrastructure/jira"
+)
+
+// errMissingPermissions makes check-permissions exit with exitAuthFailure when a permission is missing.
+var errMissingPermissions = errors.New("the Jira token is missing permissions jiramd needs")
+
+var checkPermissionsProject string
+
+// checkPermissionsCmd represents the check-permissions command
+var checkPermissionsCmd = &cobra.Command{
+	Use:   "check-permissions",
+	Short: "Check that the Jira token has the permissions syncing needs",
+	Long: `Ask Jira which project permissions the configured token has and report
+whether it can browse and create tickets, edit them, transition them,
+comment, and manage attachments.
+
+Run it after setting up a new token or project so that push failures are
+found before anything is queued. Exits with status 3 if a permission is
+missing.`,
+	Example: `  jiramd check-permissions
+  jiramd check-permissions --project OPS`,
+	Args: cobra.NoArgs,
+	RunE: runCheckPermissions,
+}
+
+func init() {
+	checkPermissionsCmd.Flags().StringVarP(&checkPermissionsProject, "project", "p", "", "Project key (default jira.project)")
+	checkPermissionsCmd.RegisterFlagCompletionFunc("project", completeProjectKeys)
+}
+
+// permissionEntry is one required permission and whether it is granted.
+type permissionEntry struct {
+	Key     string `json:"key"`
+	Purpose string `json:"purpose"`
+	Granted bool   `json:"granted"`
+}
+
+// checkPermissionsResult is the structured output of the check-permissions command.
+type checkPermissionsResult struct {
+	ProjectKey  string            `json:"project_key"`
+	Permissions []permissionEntry `json:"permissions"`
+	Missing     int               `json:"missing"`
+}
+
+func (r checkPermissionsResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Permissions in %s:\n", r.ProjectKey)
+	for _, p := range r.Permissions {
+		mark := "ok"
+		if !p.Granted {
+			mark = "MISSING"
+		}
+		fmt.Fprintf(w, "  %-7s %-22s %s\n", mark, p.Key, p.Purpose)
+	}
+
+	if r.Missing == 0 {
+		fmt.Fprintln(w, "All required permissions are granted.")
+	} else {
+		fmt.Fprintf(w, "%d permissions missing; ask a Jira admin to grant them in the project's permission scheme.\n", r.Missing)
+	}
+}
+
+// runCheckPermissions reports which of the permissions syncing needs the token has.
+func runCheckPermissions(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	projectKey := checkPermissionsProject
+	if projectKey == "" {
+		projectKey = cfg.Jira.Project
+	}
+
+	client, err := jira.NewClientFromConfig(cfg.Jira)
+	if err != nil {
+		return err
+	}
+
+	report, err := permission.NewService(client).Check(cmd.Context(), projectKey)
+	if err != nil {
+		return err
+	}
+
+	result := checkPermissionsResult{ProjectKey: report.ProjectKey, Missing: len(report.Missing())}
+	for _, r := range report.Results {
+		result.Permissions = append(result.Permissions, permissionEntry{Key: r.Key, Purpose: r.Purpose, Granted: r.Granted})
+	}
+	if err := render(cmd, result); err != nil {
+		return err
+	}
+
+	if result.Missing > 0 {
+		return withExitCode(exitAuthFailure, errMissingPermissions)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/user"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/jira"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate a shell completion script",
+	Long: `Generate a shell completion script for jiramd.
+
+Ticket keys and project keys are completed from the local state database,
+so completion reflects whatever has been synced so far. Users are completed
+from the cached user directory, which is refreshed from Jira's user search
+once a day per search.
+
+To load completions:
+
+Bash:
+  $ source <(jiramd completion bash)
+  # To load completions for each session, execute once:
+  $ jiramd completion bash > /etc/bash_completion.d/jiramd
+
+Zsh:
+  $ jiramd completion zsh > "${fpath[1]}/_jiramd"
+
+Fish:
+  $ jiramd completion fish > ~/.config/fish/completions/jiramd.fish
+
+PowerShell:
+  PS> jiramd completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+// runCompletion writes the completion script for the requested shell to stdout.
+func runCompletion(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	root := cmd.Root()
+
+	switch args[0] {
+	case "bash":
+		return root.GenBashCompletionV2(w, true)
+	case "zsh":
+		return root.GenZshCompletion(w)
+	case "fish":
+		return root.GenFishCompletion(w, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(w)
+	default:
+		return fmt.Errorf("unsupported shell %q", args[0])
+	}
+}
+
+// ticketKeyLister is implemented by state repositories that can list tracked ticket keys.
+type ticketKeyLister interface {
+	ListTicketKeys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// completeTicketKeys completes ticket key arguments from the local state database.
+// Completion must never fail loudly, so any error simply yields no suggestions.
+func completeTicketKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var keys []string
+
+	err := withCompletionState(cmd, func(cfg *domain.Config, stateRepo repository.StateRepository) error {
+		lister, ok := stateRepo.(ticketKeyLister)
+		if !ok {
+			return nil
+		}
+
+		var err error
+		keys, err = lister.ListTicketKeys(cmd.Context(), toComplete)
+		return err
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectKeys completes --project values with the configured project
+// and any project that has stored sync state.
+func completeProjectKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	seen := make(map[string]bool)
+
+	err := withCompletionState(cmd, func(cfg *domain.Config, stateRepo repository.StateRepository) error {
+		seen[cfg.Jira.Project] = true
+
+		states, err := stateRepo.GetAllProjectStates(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, s := range states {
+			seen[s.ProjectKey] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeUsers completes user arguments (e.g. assignees) with active users from the
+// user directory cache, searching Jira when the cached search of toComplete is stale.
+// Each suggestion is the name ticket fields use, described by the display name.
+func completeUsers(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := cmd.Context()
+	logger := discardLogger()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	db, err := openDatabase(ctx, cfg, logger)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer db.Close()
+	client, err := jira.NewClientFromConfig(cfg.Jira)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	users, err := user.NewService(client, sqlite.NewUserRepository(db.DB(), logger)).
+		WithLogger(logger).
+		SearchUsers(ctx, toComplete)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, 0, len(users))
+	for _, u := range users {
+		if u.Active && u.Name() != "" {
+			completions = append(completions, u.Name()+"\t"+u.DisplayName)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// withCompletionState is withState for completion functions.
+// Log output is discarded because anything written while completing ends up in the shell.
+func withCompletionState(cmd *cobra.Command, fn func(cfg *domain.Config, stateRepo repository.StateRepository) error) error {
+	ctx := cmd.Context()
+	logger := discardLogger()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stateRepo, closeState, err := openStateRepository(ctx, cfg, db, logger)
+	if err != nil {
+		return err
+	}
+	defer closeState()
+
+	return fn(cfg, stateRepo)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/config"
+	infraConfig "github.com/esfisher/jiramd/internal/infrastructure/config"
+	"github.com/esfisher/jiramd/internal/infrastructure/keyring"
+)
+
+var configShowResolved bool
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+	Long: `Inspect the configuration jiramd runs with.
+
+Each setting is taken from the first of these that sets it:
+  1. --set key=value flags
+  2. JIRAMD_<KEY> environment variables, with dots as underscores
+     (e.g. JIRAMD_SYNC_INTERVAL for sync.interval)
+  3. The config file: --config, then $JIRAMD_CONFIG, then the nearest
+     .jiramd.yaml in the current directory or its parents, then
+     ` + defaultConfigPath + `
+  4. Built-in defaults`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// configShowCmd prints the configuration
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the configuration with secrets masked",
+	Long: `Show the settings from the config file, or with --resolved the effective
+value of every setting and where it came from (flag, env, file, or default).
+
+Secrets such as jira.token and passwords in URLs are masked. With --resolved,
+a configuration that would fail validation is still shown, followed by the
+problem.`,
+	Example: `  # Show the effective configuration
+  jiramd config show --resolved
+
+  # Check what a flag override resolves to
+  jiramd --set sync.interval=1m config show --resolved`,
+	Args: cobra.NoArgs,
+	RunE: runConfigShow,
+}
+
+// configAPITokenCmd prints the control API token
+var configAPITokenCmd = &cobra.Command{
+	Use:   "api-token",
+	Short: "Print the control API bearer token",
+	Long: `Print the bearer token clients of the control API must send in an
+"Authorization: Bearer <token>" header, for configuring editors and scripts.
+
+The token is kept in the OS keyring, and generated on first use. On systems
+without a keyring, set it in $` + keyring.APITokenEnvVar + ` for both the daemon
+and its clients.`,
+	Example: `  # Query the daemon's health
+  curl -H "Authorization: Bearer $(jiramd config api-token)" http://127.0.0.1:7777/v1/health`,
+	Args: cobra.NoArgs,
+	RunE: runConfigAPIToken,
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configAPITokenCmd)
+
+	configShowCmd.Flags().BoolVar(&configShowResolved, "resolved", false, "Show every effective setting and its source")
+}
+
+// configShowResult is the structured output of the config show command.
+type configShowResult struct {
+	Path      string                `json:"path"`
+	FileFound bool                  `json:"file_found"`
+	Resolved  bool                  `json:"resolved"`
+	Settings  []infraConfig.Setting `json:"settings"`
+	Problem   string                `json:"problem,omitempty"`
+}
+
+func (r configShowResult) renderText(w io.Writer) {
+	if r.FileFound {
+		fmt.Fprintf(w, "Config file: %s\n", r.Path)
+	} else {
+		fmt.Fprintf(w, "Config file: %s (not found)\n", r.Path)
+	}
+
+	width := 0
+	for _, setting := range r.Settings {
+		width = max(width, len(setting.Key))
+	}
+	for _, setting := range r.Settings {
+		if r.Resolved {
+			fmt.Fprintf(w, "  %-*s = %-30s (%s)\n", width, setting.Key, setting.Value, setting.Source)
+		} else {
+			fmt.Fprintf(w, "  %-*s = %s\n", width, setting.Key, setting.Value)
+		}
+	}
+
+	if r.Problem != "" {
+		fmt.Fprintf(w, "\nProblem: %s\n", r.Problem)
+	}
+}
+
+// runConfigShow prints the configuration file's settings or the resolved configuration.
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	opts, err := configOptions()
+	if err != nil {
+		return err
+	}
+
+	resolution, err := config.Resolve(opts)
+	if err != nil {
+		return err
+	}
+
+	result := configShowResult{
+		Path:      resolution.Path,
+		FileFound: resolution.FileFound,
+		Resolved:  configShowResolved,
+		Settings:  []infraConfig.Setting{},
+	}
+	for _, setting := range resolution.Settings() {
+		if configShowResolved || setting.Source == infraConfig.SourceFile {
+			result.Settings = append(result.Settings, setting)
+		}
+	}
+	if configShowResolved {
+		if err := config.Check(resolution); err != nil {
+			result.Problem = err.Error()
+		}
+	}
+
+	return render(cmd, result)
+}
+
+// runConfigAPIToken prints the control API token of the configured database.
+func runConfigAPIToken(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	token, err := keyring.APIToken(cfg.Storage.DBPath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), token)
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+// conflictsCmd represents the conflicts command
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "List tickets with sync conflicts",
+	Long: `List tickets that were modified both locally and in Jira since their
+last successful sync. These tickets are not pushed or pulled until the
+conflict is resolved.
+
+In a shared vault (sync.shared_vault), each conflict names the people whose
+local changes are waiting to be pushed.
+
+Exits with status 2 when any ticket has a conflict.`,
+	RunE: runConflicts,
+}
+
+// conflictEntry describes a single conflicted ticket.
+type conflictEntry struct {
+	TicketKey         string     `json:"ticket_key"`
+	LastSynced        *time.Time `json:"last_synced"`
+	LastModifiedLocal *time.Time `json:"last_modified_local"`
+	LastModifiedJira  *time.Time `json:"last_modified_jira"`
+	LocalAuthors      []string   `json:"local_authors,omitempty"`
+}
+
+// conflictsResult is the structured output of the conflicts command.
+type conflictsResult struct {
+	Conflicts []conflictEntry `json:"conflicts"`
+}
+
+func (r conflictsResult) renderText(w io.Writer) {
+	if len(r.Conflicts) == 0 {
+		fmt.Fprintln(w, "No conflicts.")
+		return
+	}
+
+	fmt.Fprintf(w, "%d conflicted tickets:\n", len(r.Conflicts))
+	for _, c := range r.Conflicts {
+		fmt.Fprintf(w, "  %s  local %s, jira %s, last synced %s\n",
+			c.TicketKey,
+			formatTimePtr(c.LastModifiedLocal),
+			formatTimePtr(c.LastModifiedJira),
+			formatTimePtr(c.LastSynced))
+		if len(c.LocalAuthors) > 0 {
+			fmt.Fprintf(w, "    local changes by %s\n", strings.Join(c.LocalAuthors, ", "))
+		}
+	}
+}
+
+// runConflicts lists conflicted tickets from the state database.
+func runConflicts(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		states, err := stateRepo.GetConflictedTickets(ctx)
+		if err != nil {
+			return err
+		}
+
+		queue := sqlite.NewPendingOperationRepository(db.DB(), cliLogger()).WithCipher(db.Cipher())
+		result := conflictsResult{Conflicts: make([]conflictEntry, 0, len(states))}
+		for _, s := range states {
+			pending, err := queue.FindByTicketKey(ctx, s.TicketKey)
+			if err != nil {
+				return err
+			}
+			result.Conflicts = append(result.Conflicts, conflictEntry{
+				TicketKey:         s.TicketKey,
+				LastSynced:        optionalTime(s.LastSynced),
+				LastModifiedLocal: optionalTime(s.LastModifiedLocal),
+				LastModifiedJira:  optionalTime(s.LastModifiedJira),
+				LocalAuthors:      operationAuthors(pending),
+			})
+		}
+
+		if err := render(cmd, result); err != nil {
+			return err
+		}
+		if len(result.Conflicts) > 0 {
+			return conflictsFound(len(result.Conflicts))
+		}
+		return nil
+	})
+}
+
+// conflictsFound returns the error making a command exit with exitConflicts because n
+// tickets have sync conflicts.
+func conflictsFound(n int) error {
+	return withExitCode(exitConflicts, fmt.Errorf("%d tickets have sync conflicts", n))
+}
+
+// operationAuthors returns the distinct authors of ops in the order they first appear.
+func operationAuthors(ops []*domain.PendingOperation) []string {
+	var authors []string
+	seen := make(map[string]bool)
+	for _, op := range ops {
+		if op.Author != "" && !seen[op.Author] {
+			seen[op.Author] = true
+			authors = append(authors, op.Author)
+		}
+	}
+	return authors
+}
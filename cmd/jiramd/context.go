@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/contextpack"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	contextMaxTokens  int
+	contextComments   int
+	contextNoComments bool
+)
+
+// contextCmd represents the context command
+var contextCmd = &cobra.Command{
+	Use:   "context KEY [KEY...]",
+	Short: "Bundle tickets as compact markdown for an LLM prompt",
+	Long: `Print the given tickets as one compact markdown bundle, ready to paste
+into an LLM prompt: each ticket's summary and main fields, its description,
+and its latest comments.
+
+Repeated keys are included once, and text repeated across tickets (such as
+a description copied into a clone) is replaced by a pointer to where it
+first appears. With --max-tokens, descriptions and comments are trimmed so
+the bundle fits the budget, and tickets that do not fit at all are listed
+at the end instead. Token counts are estimated at four characters per
+token.
+
+Only the local cache is read; run jiramd sync or jiramd pull first for
+up-to-date data.`,
+	Example: `  jiramd context JMD-12 JMD-15
+  jiramd context JMD-12 JMD-15 JMD-20 --max-tokens 2000 | pbcopy
+  jiramd context JMD-12 --comments 10`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTicketKeys,
+	RunE:              runContext,
+}
+
+func init() {
+	contextCmd.Flags().IntVar(&contextMaxTokens, "max-tokens", 0, "Trim the bundle to about this many tokens (default no limit)")
+	contextCmd.Flags().IntVar(&contextComments, "comments", contextpack.DefaultComments, "Number of latest comments to include per ticket")
+	contextCmd.Flags().BoolVar(&contextNoComments, "no-comments", false, "Leave comments out")
+}
+
+// contextResult is the structured output of the context command.
+type contextResult struct {
+	Tickets []string `json:"tickets"`
+	Omitted []string `json:"omitted"`
+	Tokens  int      `json:"tokens"`
+	Text    string   `json:"text"`
+}
+
+func (r contextResult) renderText(w io.Writer) {
+	fmt.Fprint(w, r.Text)
+}
+
+// runContext prints the bundle of the tickets given as arguments.
+func runContext(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	opts := contextpack.Options{MaxTokens: contextMaxTokens, Comments: contextComments}
+	if contextNoComments || contextComments == 0 {
+		opts.Comments = -1
+	}
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		service := contextpack.NewService(
+			sqlite.NewTicketRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()),
+			sqlite.NewCommentRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()),
+		)
+		bundle, err := service.Pack(ctx, args, opts)
+		if err != nil {
+			return err
+		}
+
+		return render(cmd, contextResult{
+			Tickets: bundle.Tickets,
+			Omitted: bundle.Omitted,
+			Tokens:  bundle.Tokens,
+			Text:    bundle.Text,
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/digest"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var digestDate string
+
+// digestCmd writes a day's digest
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Write the digest of the tickets pulled and pushed in a day",
+	Long: `Write digest/<date>.md under the markdown directory, listing every ticket
+pulled or pushed that day with a one-line summary of what changed: a cheap way
+to catch up without diffing ticket files.
+
+Digests are kept up to date as tickets are pulled and changed through jiramd;
+this command rewrites one, today's by default. Days start in the display time
+zone. The activity digests are written from is pruned by jiramd gc.`,
+	Example: `  jiramd digest
+  jiramd digest --date 2025-01-15`,
+	Args: cobra.NoArgs,
+	RunE: runDigest,
+}
+
+func init() {
+	digestCmd.Flags().StringVar(&digestDate, "date", "", "Day to write the digest of, as YYYY-MM-DD (default today)")
+}
+
+// digestResult is the structured output of the digest command.
+type digestResult struct {
+	Path    string `json:"path"`
+	Changed bool   `json:"changed"`
+}
+
+func (r digestResult) renderText(w io.Writer) {
+	verb := "Unchanged"
+	if r.Changed {
+		verb = "Wrote"
+	}
+	fmt.Fprintf(w, "%s %s\n", verb, r.Path)
+}
+
+// runDigest writes the digest of one day.
+func runDigest(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		var day time.Time
+		if digestDate != "" {
+			location := cfg.Display.Location
+			if location == nil {
+				location = time.Local
+			}
+			parsed, err := time.ParseInLocation(domain.DigestDateLayout, digestDate, location)
+			if err != nil {
+				return fmt.Errorf("%w: --date must be a day as YYYY-MM-DD, got %q", domain.ErrInvalidInput, digestDate)
+			}
+			day = parsed
+		}
+
+		name, changed, err := newDigestService(cfg, db, cliLogger()).Write(ctx, day)
+		if err != nil {
+			return err
+		}
+		return render(cmd, digestResult{
+			Path:    filepath.Join(cfg.Sync.MarkdownDir, domain.DigestDir, name),
+			Changed: changed,
+		})
+	})
+}
+
+// newDigestService returns the service recording pull and push activity in db and
+// writing the daily digests under cfg's markdown directory.
+func newDigestService(cfg *domain.Config, db *sqlite.Database, logger *slog.Logger) *digest.Service {
+	return digest.NewService(
+		sqlite.NewActivityRepository(db.DB(), logger).WithCipher(db.Cipher()),
+		markdown.NewReportWriter(filepath.Join(cfg.Sync.MarkdownDir, domain.DigestDir)),
+		logger,
+	).WithDisplay(cfg.Display)
+}
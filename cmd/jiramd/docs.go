@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var docsManDir string
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate documentation for jiramd",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// docsManCmd generates man pages for every command
+var docsManCmd = &cobra.Command{
+	Use:   "man",
+	Short: "Generate man pages",
+	Long: `Generate a man page for jiramd and each of its subcommands.
+
+Pages are written in section 1 to the directory given by --dir, which is
+created if it does not exist.`,
+	Args: cobra.NoArgs,
+	RunE: runDocsMan,
+}
+
+func init() {
+	docsCmd.AddCommand(docsManCmd)
+
+	docsManCmd.Flags().StringVarP(&docsManDir, "dir", "d", "man", "Directory to write man pages to")
+	docsManCmd.MarkFlagDirname("dir")
+}
+
+// runDocsMan writes the man page tree for the root command.
+func runDocsMan(cmd *cobra.Command, args []string) error {
+	if err := os.MkdirAll(docsManDir, 0755); err != nil {
+		return fmt.Errorf("failed to create man page directory: %w", err)
+	}
+
+	header := &doc.GenManHeader{
+		Title:   "JIRAMD",
+		Section: "1",
+		Source:  "jiramd " + version,
+		Manual:  "jiramd Manual",
+	}
+
+	root := cmd.Root()
+	root.DisableAutoGenTag = true
+	if err := doc.GenManTree(root, header, docsManDir); err != nil {
+		return fmt.Errorf("failed to generate man pages: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Man pages written to %s\n", docsManDir)
+	return nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/config"
+	infraConfig "github.com/esfisher/jiramd/internal/infrastructure/config"
+)
+
+// errDoctorFailed makes doctor exit non-zero when a check fails.
+var errDoctorFailed = errors.New("jiramd is not set up correctly; see the failed checks above")
+
+// Doctor check outcomes.
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "FAIL"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Show the files jiramd uses and check the local setup",
+	Long: `Show which config file, state database, and markdown directory jiramd uses,
+and why, then check that the configuration is valid and the paths are usable.
+
+The config file is --config, then $JIRAMD_CONFIG, then the nearest
+.jiramd.yaml in the current directory or its parents, then
+` + defaultConfigPath + `.
+The state database defaults to $XDG_DATA_HOME/jiramd/state.db
+(~/.local/share/jiramd/state.db) when storage.db_path is not set.
+
+Also warns about projects and markdown files beyond sync.guardrails: more
+tickets than expected (usually a sync scoped wider than intended), files
+larger than expected, and projects that have not synced for too long.
+Checks sync.priority_map against the priorities cached by refresh-metadata.
+
+Exits non-zero if a check fails, with status 5 when the configuration is
+invalid. Jira itself is not contacted; the daemon
+checks it on start, and check-permissions checks the token.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+// doctorCheck is the outcome of one doctor check.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// doctorResult is the structured output of the doctor command.
+type doctorResult struct {
+	ConfigPath    string        `json:"config_path"`
+	ConfigOrigin  string        `json:"config_origin"`
+	ConfigFound   bool          `json:"config_found"`
+	ProjectConfig string        `json:"project_config,omitempty"`
+	DataDir       string        `json:"data_dir"`
+	DBPath        string        `json:"db_path,omitempty"`
+	MarkdownDir   string        `json:"markdown_dir,omitempty"`
+	Checks        []doctorCheck `json:"checks"`
+}
+
+func (r doctorResult) renderText(w io.Writer) {
+	found := "found"
+	if !r.ConfigFound {
+		found = "not found"
+	}
+	project := r.ProjectConfig
+	if project == "" {
+		project = "none above the current directory"
+	}
+
+	fmt.Fprintln(w, "Paths:")
+	fmt.Fprintf(w, "  Config file:     %s (%s, %s)\n", r.ConfigPath, r.ConfigOrigin, found)
+	fmt.Fprintf(w, "  Project config:  %s\n", project)
+	fmt.Fprintf(w, "  Data directory:  %s\n", r.DataDir)
+	fmt.Fprintf(w, "  State database:  %s\n", r.DBPath)
+	fmt.Fprintf(w, "  Markdown dir:    %s\n", r.MarkdownDir)
+
+	fmt.Fprintln(w, "Checks:")
+	for _, check := range r.Checks {
+		fmt.Fprintf(w, "  %-5s %-15s %s\n", check.Status, check.Name, check.Detail)
+	}
+}
+
+// failed reports whether any check failed.
+func (r doctorResult) failed() bool {
+	for _, check := range r.Checks {
+		if check.Status == doctorFail {
+			return true
+		}
+	}
+	return false
+}
+
+// runDoctor reports the paths jiramd uses and checks them.
+func runDoctor(cmd *cobra.Command, args []string) error {
+	opts, err := configOptions()
+	if err != nil {
+		return err
+	}
+
+	result := doctorResult{
+		ConfigPath:   opts.Path,
+		ConfigOrigin: opts.Origin,
+		DataDir:      infraConfig.DefaultDataDir(),
+	}
+	if path, ok := infraConfig.FindProjectConfig("."); ok {
+		result.ProjectConfig = path
+	}
+
+	resolution, err := config.Resolve(opts)
+	if err != nil {
+		result.Checks = append(result.Checks, doctorCheck{Name: "config", Status: doctorFail, Detail: err.Error()})
+		if err := render(cmd, result); err != nil {
+			return err
+		}
+		return withExitCode(exitConfigInvalid, errDoctorFailed)
+	}
+
+	cfg := resolution.Config
+	result.ConfigPath = resolution.Path
+	result.ConfigFound = resolution.FileFound
+	result.DBPath = cfg.Storage.DBPath
+	result.MarkdownDir = cfg.Sync.MarkdownDir
+
+	configCheck := doctorCheck{Name: "config", Status: doctorOK, Detail: "valid"}
+	if err := config.Check(resolution); err != nil {
+		configCheck = doctorCheck{Name: "config", Status: doctorFail, Detail: err.Error()}
+	} else if !resolution.FileFound {
+		configCheck.Detail = "valid (no config file; settings come from the environment and defaults)"
+	}
+	result.Checks = append(result.Checks,
+		configCheck,
+		checkDirectory("markdown dir", cfg.Sync.MarkdownDir, "created on the first sync"),
+		checkDirectory("state database", filepath.Dir(cfg.Storage.DBPath), "created on first use"),
+	)
+	if configCheck.Status == doctorOK {
+		result.Checks = append(result.Checks, checkGuardrails(cmd.Context(), cfg)...)
+		result.Checks = append(result.Checks, checkPriorityMaps(cmd.Context(), cfg)...)
+	}
+
+	if err := render(cmd, result); err != nil {
+		return err
+	}
+	switch {
+	case configCheck.Status == doctorFail:
+		return withExitCode(exitConfigInvalid, errDoctorFailed)
+	case result.failed():
+		return errDoctorFailed
+	}
+	return nil
+}
+
+// checkDirectory checks that dir can be used, warning with missing when it does not
+// exist yet (jiramd creates it) and failing when something else is in the way.
+func checkDirectory(name, dir, missing string) doctorCheck {
+	if dir == "" || dir == "." {
+		return doctorCheck{Name: name, Status: doctorFail, Detail: "not configured"}
+	}
+
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return doctorCheck{Name: name, Status: doctorWarn, Detail: dir + " does not exist yet; " + missing}
+	case err != nil:
+		return doctorCheck{Name: name, Status: doctorFail, Detail: err.Error()}
+	case !info.IsDir():
+		return doctorCheck{Name: name, Status: doctorFail, Detail: dir + " is not a directory"}
+	}
+
+	probe, err := os.CreateTemp(dir, ".jiramd-doctor-*")
+	if err != nil {
+		return doctorCheck{Name: name, Status: doctorFail, Detail: dir + " is not writable: " + err.Error()}
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return doctorCheck{Name: name, Status: doctorOK, Detail: dir + " is writable"}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Exit codes of jiramd commands, so CI jobs and scripts can branch on outcomes. They are
+// documented in the root command's help (exitCodesHelp); keep the two in sync.
+const (
+	// exitOK means the command succeeded
+	exitOK = 0
+
+	// exitError means the command failed for any reason without a code of its own
+	exitError = 1
+
+	// exitConflicts means tickets have sync conflicts to resolve
+	exitConflicts = 2
+
+	// exitAuthFailure means Jira rejected the credentials or denied a permission
+	exitAuthFailure = 3
+
+	// exitPartialFailure means the command ran to the end, but some tickets failed
+	exitPartialFailure = 4
+
+	// exitConfigInvalid means the configuration is invalid
+	exitConfigInvalid = 5
+)
+
+// exitCodesHelp documents the exit codes in the root command's help and man page.
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  any other error
+  2  tickets have sync conflicts (conflicts, status, sync)
+  3  Jira rejected the credentials or denied a permission
+  4  some tickets failed to sync, import, upgrade, or move (sync, import,
+     migrate-files, transition)
+  5  the configuration is invalid`
+
+// exitCodeError makes jiramd exit with code when a command fails with it.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+// withExitCode returns err making jiramd exit with code.
+func withExitCode(code int, err error) error {
+	return &exitCodeError{code: code, err: err}
+}
+
+// Error implements the error interface.
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error the exit code was given to.
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns the code jiramd exits with when a command returns err: the code given
+// with withExitCode, or the one matching the domain error err wraps.
+func exitCode(err error) int {
+	var coded *exitCodeError
+	var configErr *domain.ConfigError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &coded):
+		return coded.code
+	case errors.Is(err, domain.ErrUnauthorized), domain.IsPermissionDenied(err):
+		return exitAuthFailure
+	case errors.As(err, &configErr), errors.Is(err, domain.ErrConfig):
+		return exitConfigInvalid
+	case errors.Is(err, domain.ErrSyncConflict):
+		return exitConflicts
+	default:
+		return exitError
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", want: exitOK},
+		{name: "other error", err: errors.New("boom"), want: exitError},
+		{name: "rejected credentials", err: &domain.JiraError{Err: domain.ErrUnauthorized, Status: 401}, want: exitAuthFailure},
+		{name: "denied permission", err: &domain.JiraError{Err: domain.ErrUnauthorized, Status: 403}, want: exitAuthFailure},
+		{
+			name: "denied permission mapped to another error",
+			err:  fmt.Errorf("failed to pull JMD-1: %w", &domain.JiraError{Err: errors.New("403 Forbidden"), Status: 403}),
+			want: exitAuthFailure,
+		},
+		{name: "not found", err: &domain.JiraError{Err: domain.ErrNotFound, Status: 404}, want: exitError},
+		{name: "invalid config", err: &domain.ConfigError{Message: "sync.mode is invalid"}, want: exitConfigInvalid},
+		{name: "conflicts", err: fmt.Errorf("JMD-1: %w", domain.ErrSyncConflict), want: exitConflicts},
+		{name: "explicit code", err: withExitCode(exitPartialFailure, &domain.JiraError{Status: 403}), want: exitPartialFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+// expandCmd writes a summarized ticket file in full
+var expandCmd = &cobra.Command{
+	Use:   "expand KEY",
+	Short: "Write a summarized ticket's file in full",
+	Long: `Pull a ticket from Jira and write its file in full: its whole description
+and every comment, ignoring markdown.max_description_length,
+markdown.max_comments, and markdown.project_limits.
+
+Files of tickets over those limits are summarized: the description is cut
+short with a note that it is truncated, and only the latest comments are
+kept. A truncated description is never pushed, so expand a ticket before
+editing its description.
+
+The file stays expanded until the ticket changes in Jira and a sync writes
+it again with the limits.`,
+	Example:           `  jiramd expand JMD-42`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArgTicketKey,
+	RunE:              runExpand,
+}
+
+// expandResult is the structured output of the expand command.
+type expandResult struct {
+	Key      string `json:"key"`
+	Path     string `json:"path"`
+	Comments int    `json:"comments"`
+}
+
+func (r expandResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Expanded %s with %d comments\n", r.Key, r.Comments)
+	fmt.Fprintf(w, "Updated %s\n", r.Path)
+}
+
+// runExpand pulls one ticket from Jira with its whole description and every comment.
+func runExpand(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	key, err := domain.NewTicketKey(args[0])
+	if err != nil {
+		return err
+	}
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		client, err := newMonitoredJiraClient(ctx, cfg, db)
+		if err != nil {
+			return err
+		}
+		parser, err := newMarkdownParser(cfg)
+		if err != nil {
+			return err
+		}
+		service := newPullServiceWithParser(cfg, db, stateRepo, client, parser.WithContentLimits(nil)).
+			WithComments(client)
+
+		result, err := service.Pull(ctx, key.String(), key.ProjectKey() != cfg.Jira.Project)
+		if err != nil {
+			return err
+		}
+		return render(cmd, expandResult{
+			Key:      result.Ticket.Key.String(),
+			Path:     result.Path,
+			Comments: len(result.Ticket.Comments),
+		})
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/export"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	exportFormat string
+	exportFilter string
+	exportFile   string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export cached tickets as CSV or JSON",
+	Long: `Export cached tickets for spreadsheets and reporting.
+
+Each ticket is exported with its key, summary, status, people, labels,
+timestamps, and custom fields. CSV exports have one column per custom field.
+Only the local cache is read; run jiramd sync first for up-to-date data.
+
+--filter narrows the export with the JQL subset supported by
+jiramd query --local, including ORDER BY.`,
+	Example: `  jiramd export --format csv --file tickets.csv
+  jiramd export --format json --filter 'project = JMD AND status != Done'`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", string(export.FormatCSV), "Export format: csv or json")
+	exportCmd.Flags().StringVar(&exportFilter, "filter", "", "Only export tickets matching this JQL")
+	exportCmd.Flags().StringVar(&exportFile, "file", "", "Write the export to a file instead of stdout")
+	exportCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{string(export.FormatCSV), string(export.FormatJSON)}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// exportResult is the structured output of the export command when writing to a file.
+type exportResult struct {
+	File    string `json:"file"`
+	Format  string `json:"format"`
+	Tickets int    `json:"tickets"`
+}
+
+func (r exportResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Exported %d tickets to %s\n", r.Tickets, r.File)
+}
+
+// runExport writes the cached tickets to stdout or a file.
+func runExport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	format, err := export.ParseFormat(exportFormat)
+	if err != nil {
+		return err
+	}
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		service := export.NewService(sqlite.NewTicketRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()), cfg.Jira.Email)
+
+		if exportFile == "" {
+			_, err := service.Export(ctx, cmd.OutOrStdout(), format, exportFilter)
+			return err
+		}
+
+		file, err := os.Create(exportFile)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+
+		count, err := service.Export(ctx, file, format, exportFilter)
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write export file: %w", closeErr)
+		}
+		if err != nil {
+			os.Remove(exportFile)
+			return err
+		}
+
+		return render(cmd, exportResult{File: exportFile, Format: string(format), Tickets: count})
+	})
+}
@@ -2,10 +2,16 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"sort"
 
 	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/field"
 )
 
+var fieldProject string
+
 // fieldCmd represents the field command
 var fieldCmd = &cobra.Command{
 	Use:   "field",
@@ -18,15 +24,79 @@ Subcommands allow you to:
   - Remove a field mapping
   - View field mapping details`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// TODO: Implement field command or show help
-		fmt.Println("field command not yet implemented")
 		cmd.Help()
 	},
 }
 
+// fieldListCmd lists field mappings for a project
+var fieldListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List field mappings",
+	RunE:  runFieldList,
+}
+
 func init() {
 	// Add subcommands for field management
-	// fieldCmd.AddCommand(fieldListCmd)
+	fieldCmd.AddCommand(fieldListCmd)
 	// fieldCmd.AddCommand(fieldAddCmd)
 	// fieldCmd.AddCommand(fieldRemoveCmd)
+
+	fieldCmd.PersistentFlags().StringVarP(&fieldProject, "project", "p", "", "Project key (default jira.project)")
+	fieldCmd.RegisterFlagCompletionFunc("project", completeProjectKeys)
+}
+
+// fieldMappingEntry describes how one Jira field maps to markdown.
+type fieldMappingEntry struct {
+	JiraField   string `json:"jira_field"`
+	MarkdownKey string `json:"markdown_key"`
+	Standard    bool   `json:"standard"`
+}
+
+// fieldListResult is the structured output of the field list command.
+type fieldListResult struct {
+	ProjectKey string              `json:"project_key"`
+	Fields     []fieldMappingEntry `json:"fields"`
+}
+
+func (r fieldListResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Field mappings for %s:\n", r.ProjectKey)
+	for _, f := range r.Fields {
+		kind := "custom"
+		if f.Standard {
+			kind = "standard"
+		}
+		fmt.Fprintf(w, "  %-20s -> %-20s (%s)\n", f.JiraField, f.MarkdownKey, kind)
+	}
+}
+
+// runFieldList renders the field mapping of a project.
+func runFieldList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	projectKey := fieldProject
+	if projectKey == "" {
+		projectKey = cfg.Jira.Project
+	}
+
+	mapping, err := field.NewService().GetMapping(cmd.Context(), projectKey)
+	if err != nil {
+		return err
+	}
+
+	result := fieldListResult{ProjectKey: projectKey, Fields: make([]fieldMappingEntry, 0, len(mapping))}
+	for jiraField, markdownKey := range mapping {
+		result.Fields = append(result.Fields, fieldMappingEntry{
+			JiraField:   jiraField,
+			MarkdownKey: markdownKey,
+			Standard:    field.IsStandardField(jiraField),
+		})
+	}
+	sort.Slice(result.Fields, func(i, j int) bool {
+		return result.Fields[i].JiraField < result.Fields[j].JiraField
+	})
+
+	return render(cmd, result)
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/gc"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	gcDryRun    bool
+	gcRetention time.Duration
+	gcVacuum    bool
+)
+
+// gcCmd represents the gc command
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Prune old state from the state database",
+	Long: `Prune state that is older than the retention period from the state database:
+completed push operations, tombstones of tickets deleted from Jira, the
+history of sync runs, processed webhook events, and the activity daily digests
+are written from. Digest files already written are kept.
+
+The retention period is storage.retention (90 days by default). The daemon
+runs this automatically every storage.gc_interval; run it by hand to prune
+immediately or with a different retention.
+
+Pruning frees space for reuse but does not shrink the database file; use
+--vacuum to also compact the file.`,
+	Example: `  # Show what would be pruned
+  jiramd gc --dry-run
+
+  # Prune everything older than 30 days and compact the database
+  jiramd gc --retention 720h --vacuum`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+func init() {
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Report what would be pruned without removing anything")
+	gcCmd.Flags().DurationVar(&gcRetention, "retention", 0, "Prune state older than this (default storage.retention)")
+	gcCmd.Flags().BoolVar(&gcVacuum, "vacuum", false, "Compact the database file after pruning")
+}
+
+// gcResult is the structured output of the gc command.
+type gcResult struct {
+	Cutoff      time.Time `json:"cutoff"`
+	DryRun      bool      `json:"dry_run"`
+	Operations  int       `json:"operations"`
+	Tombstones  int       `json:"tombstones"`
+	SyncHistory int       `json:"sync_history"`
+	InboxEvents int       `json:"inbox_events"`
+	Activities  int       `json:"activities"`
+	Vacuumed    bool      `json:"vacuumed"`
+}
+
+func (r gcResult) renderText(w io.Writer) {
+	verb := "Pruned"
+	if r.DryRun {
+		verb = "Would prune"
+	}
+	fmt.Fprintf(w, "%s state older than %s:\n", verb, formatTime(r.Cutoff))
+	fmt.Fprintf(w, "  Completed operations: %d\n", r.Operations)
+	fmt.Fprintf(w, "  Ticket tombstones:    %d\n", r.Tombstones)
+	fmt.Fprintf(w, "  Sync history entries: %d\n", r.SyncHistory)
+	fmt.Fprintf(w, "  Webhook events:       %d\n", r.InboxEvents)
+	fmt.Fprintf(w, "  Digest activities:    %d\n", r.Activities)
+	if r.Vacuumed {
+		fmt.Fprintln(w, "Database compacted")
+	}
+}
+
+// runGC prunes expired state.
+func runGC(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if gcRetention < 0 {
+		return fmt.Errorf("%w: --retention cannot be negative", domain.ErrInvalidInput)
+	}
+	if gcDryRun && gcVacuum {
+		return fmt.Errorf("%w: --vacuum cannot be combined with --dry-run", domain.ErrInvalidInput)
+	}
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		retention := cfg.Storage.Retention
+		if gcRetention > 0 {
+			retention = gcRetention
+		}
+
+		logger := cliLogger()
+		service := gc.NewService(
+			stateRepo,
+			sqlite.NewPendingOperationRepository(db.DB(), logger).WithCipher(db.Cipher()),
+			sqlite.NewSyncHistoryRepository(db.DB(), logger),
+			retention,
+			logger,
+		).WithInbox(sqlite.NewInboxRepository(db.DB(), logger).WithCipher(db.Cipher())).
+			WithActivity(sqlite.NewActivityRepository(db.DB(), logger).WithCipher(db.Cipher()))
+
+		report, err := service.Collect(ctx, gcDryRun)
+		if err != nil {
+			return err
+		}
+
+		if gcVacuum {
+			if err := db.Vacuum(ctx); err != nil {
+				return err
+			}
+		}
+
+		return render(cmd, gcResult{
+			Cutoff:      report.Cutoff,
+			DryRun:      report.DryRun,
+			Operations:  report.Operations,
+			Tombstones:  report.Tombstones,
+			SyncHistory: report.SyncHistory,
+			InboxEvents: report.InboxEvents,
+			Activities:  report.Activities,
+			Vacuumed:    gcVacuum,
+		})
+	})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+)
+
+// maxListedLargeFiles is how many oversized markdown files are named in guardrail
+// warnings before the rest are only counted.
+const maxListedLargeFiles = 5
+
+// guardrailWarnings checks the synced projects and the markdown directory against
+// sync.guardrails, returning a warning for every limit exceeded.
+func guardrailWarnings(cfg *domain.Config, states []*repository.ProjectSyncState, now time.Time) ([]string, error) {
+	guardrails := cfg.Sync.Guardrails
+	warnings := make([]string, 0)
+
+	for _, state := range states {
+		for _, warning := range []string{
+			guardrails.CheckTicketCount(state.ProjectKey, state.TicketCount),
+			guardrails.CheckStaleness(state.ProjectKey, state.LastIncrementalSync, now),
+		} {
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+
+	if guardrails.MaxFileSize == 0 {
+		return warnings, nil
+	}
+	large, err := markdown.FindLargeFiles(cfg.Sync.MarkdownDir, guardrails.MaxFileSize)
+	if err != nil {
+		return warnings, err
+	}
+	for i, file := range large {
+		if i == maxListedLargeFiles {
+			warnings = append(warnings, fmt.Sprintf("%d more markdown files are larger than sync.guardrails.max_file_size",
+				len(large)-maxListedLargeFiles))
+			break
+		}
+		warnings = append(warnings, guardrails.CheckFileSize(file.Path, file.Size))
+	}
+	return warnings, nil
+}
+
+// checkGuardrails is the doctor check of sync.guardrails. Project limits are only
+// checked when the state database exists, so doctor never creates it.
+func checkGuardrails(ctx context.Context, cfg *domain.Config) []doctorCheck {
+	const name = "guardrails"
+
+	var states []*repository.ProjectSyncState
+	if _, err := os.Stat(cfg.Storage.DBPath); err == nil {
+		if states, err = readProjectStates(ctx, cfg); err != nil {
+			return []doctorCheck{{Name: name, Status: doctorWarn, Detail: "could not read sync state: " + err.Error()}}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return []doctorCheck{{Name: name, Status: doctorWarn, Detail: "could not read sync state: " + err.Error()}}
+	}
+
+	warnings, err := guardrailWarnings(cfg, states, time.Now())
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	if len(warnings) == 0 {
+		return []doctorCheck{{Name: name, Status: doctorOK, Detail: "no limits exceeded"}}
+	}
+
+	checks := make([]doctorCheck, 0, len(warnings))
+	for _, warning := range warnings {
+		checks = append(checks, doctorCheck{Name: name, Status: doctorWarn, Detail: warning})
+	}
+	return checks
+}
+
+// readProjectStates reads the sync state of every project from the state database.
+func readProjectStates(ctx context.Context, cfg *domain.Config) ([]*repository.ProjectSyncState, error) {
+	db, err := openDatabase(ctx, cfg, discardLogger())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	stateRepo, closeState, err := openStateRepository(ctx, cfg, db, discardLogger())
+	if err != nil {
+		return nil, err
+	}
+	defer closeState()
+
+	return stateRepo.GetAllProjectStates(ctx)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/importer"
+	"github.com/esfisher/jiramd/internal/application/ticket"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/infrastructure/jira"
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+)
+
+var (
+	importProject    string
+	importIssueType  string
+	importDryRun     bool
+	importBatchSize  int
+	importBatchDelay time.Duration
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import DIR",
+	Short: "Create Jira tickets from existing markdown notes",
+	Long: `Bulk-create Jira tickets from the markdown files under DIR.
+
+Each .md file becomes one ticket:
+  - the summary is the first "# " heading (or the frontmatter title,
+    or the file name)
+  - the description is the rest of the file
+  - labels come from the frontmatter labels and tags
+  - the frontmatter type and priority are used when present
+
+Files whose frontmatter already has a key are Jira tickets and are skipped.
+Tickets are created in batches with a pause in between to stay within
+Jira's rate limits. Use --dry-run to preview the tickets first. Exits with
+status 4 when some notes could not be imported.`,
+	Example: `  jiramd import --dry-run ./notes
+  jiramd import --project JMD --type Story ./notes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVarP(&importProject, "project", "p", "", "Project to create the tickets in (default jira.project)")
+	importCmd.Flags().StringVarP(&importIssueType, "type", "t", ticket.DefaultIssueType, "Issue type for notes without a type")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Preview the tickets without creating them")
+	importCmd.Flags().IntVar(&importBatchSize, "batch-size", importer.DefaultBatchSize, "Tickets created per Jira request (at most 50)")
+	importCmd.Flags().DurationVar(&importBatchDelay, "batch-delay", importer.DefaultBatchDelay, "Pause between batches")
+	importCmd.RegisterFlagCompletionFunc("project", completeProjectKeys)
+}
+
+// importEntry is the outcome for one note.
+type importEntry struct {
+	File    string   `json:"file"`
+	Summary string   `json:"summary"`
+	Type    string   `json:"type"`
+	Labels  []string `json:"labels"`
+	Status  string   `json:"status"` // created, would_create, skipped, or failed
+	Key     string   `json:"key,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// importResult is the structured output of the import command.
+type importResult struct {
+	Directory string        `json:"directory"`
+	Project   string        `json:"project"`
+	DryRun    bool          `json:"dry_run"`
+	Created   int           `json:"created"`
+	Skipped   int           `json:"skipped"`
+	Failed    int           `json:"failed"`
+	Tickets   []importEntry `json:"tickets"`
+}
+
+func (r importResult) renderText(w io.Writer) {
+	if len(r.Tickets) == 0 {
+		fmt.Fprintf(w, "No markdown files found in %s.\n", r.Directory)
+		return
+	}
+
+	for _, t := range r.Tickets {
+		switch t.Status {
+		case "created":
+			fmt.Fprintf(w, "  %-12s %s  (%s)\n", t.Key, t.Summary, t.File)
+		case "would_create":
+			fmt.Fprintf(w, "  %-12s %s  [%s] %s\n", "new "+t.Type, t.Summary, valueOrNone(strings.Join(t.Labels, ", ")), t.File)
+		case "skipped":
+			fmt.Fprintf(w, "  %-12s %s  (%s: already ticket %s)\n", "skipped", t.Summary, t.File, t.Key)
+		default:
+			fmt.Fprintf(w, "  %-12s %s  (%s): %s\n", "FAILED", t.Summary, t.File, t.Error)
+		}
+	}
+
+	fmt.Fprintln(w)
+	if r.DryRun {
+		fmt.Fprintf(w, "Dry run: %d tickets would be created in %s, %d skipped, %d invalid.\n",
+			len(r.Tickets)-r.Skipped-r.Failed, r.Project, r.Skipped, r.Failed)
+		return
+	}
+	fmt.Fprintf(w, "Created %d tickets in %s, %d skipped, %d failed.\n", r.Created, r.Project, r.Skipped, r.Failed)
+}
+
+// runImport reads the notes under the directory and creates tickets from them.
+func runImport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	dir := args[0]
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	projectKey := importProject
+	if projectKey == "" {
+		projectKey = cfg.Jira.Project
+	}
+
+	notes, err := markdown.ReadNotes(dir)
+	if err != nil {
+		return err
+	}
+
+	// Entries follow the notes' file order; drafted[i] is the entry of drafts[i]
+	result := importResult{Directory: dir, Project: projectKey, DryRun: importDryRun, Tickets: make([]importEntry, len(notes))}
+	drafts := make([]*domain.TicketDraft, 0, len(notes))
+	drafted := make([]int, 0, len(notes))
+	for i, note := range notes {
+		if note.Key != "" {
+			result.Skipped++
+			result.Tickets[i] = importEntry{
+				File:    note.Path,
+				Summary: note.Title,
+				Labels:  note.Labels,
+				Status:  "skipped",
+				Key:     note.Key,
+			}
+			continue
+		}
+		drafts = append(drafts, note.Draft(projectKey, importIssueType))
+		drafted = append(drafted, i)
+	}
+
+	client, err := jira.NewClientFromConfig(cfg.Jira)
+	if err != nil {
+		return err
+	}
+	report, importErr := importer.NewService(client).WithProgress(cliProgress()).Import(ctx, drafts, importer.Options{
+		DryRun:     importDryRun,
+		BatchSize:  importBatchSize,
+		BatchDelay: importBatchDelay,
+	})
+
+	for j, r := range report.Results {
+		entry := importEntry{
+			File:    r.Draft.Source,
+			Summary: r.Draft.Summary,
+			Type:    r.Draft.IssueType,
+			Labels:  r.Draft.Labels,
+		}
+		switch {
+		case r.Err != nil:
+			entry.Status = "failed"
+			entry.Error = r.Err.Error()
+		case importDryRun:
+			entry.Status = "would_create"
+		default:
+			entry.Status = "created"
+			entry.Key = r.Key.String()
+		}
+		result.Tickets[drafted[j]] = entry
+	}
+	result.Created = report.Created()
+	result.Failed = report.Failed()
+
+	if err := render(cmd, result); err != nil {
+		return err
+	}
+	if importErr != nil {
+		return importErr
+	}
+	if result.Failed > 0 {
+		return withExitCode(exitPartialFailure, errors.New("some notes could not be imported"))
+	}
+	return nil
+}
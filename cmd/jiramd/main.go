@@ -3,8 +3,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 )
@@ -12,6 +15,10 @@ import (
 var (
 	// version is set at build time using ldflags
 	version = "dev"
+
+	// configFile and configSets are the global --config and --set flags
+	configFile string
+	configSets []string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -20,17 +27,44 @@ var rootCmd = &cobra.Command{
 	Short: "jiramd - Jira markdown sync daemon",
 	Long: `jiramd is a background daemon that bidirectionally syncs Jira Cloud tickets
 to local markdown files. It eliminates AI token usage for Jira ticket
-management by maintaining a local markdown cache.`,
+management by maintaining a local markdown cache.
+
+Each setting is taken from the first of these that sets it: --set key=value
+flags, JIRAMD_<KEY> environment variables (e.g. JIRAMD_SYNC_INTERVAL for
+sync.interval), the config file, and built-in defaults. The config file is
+--config, then $JIRAMD_CONFIG, then the nearest .jiramd.yaml in the current
+directory or its parents, then ` + defaultConfigPath + `.
+Run "jiramd config show --resolved" to see the effective settings, and
+"jiramd doctor" to see which files and directories are used.
+
+` + exitCodesHelp,
 	Version: version,
+	// main reports errors itself; usage is only shown for --help
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	// Validate global flags before any subcommand runs
+	PersistentPreRunE: validateOutputFormat,
+	// Replaced by our own completion command, which documents the supported shells
+	CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	// Ctrl-C or SIGTERM cancels the command's context so it can stop cleanly, e.g. between
+	// migrations or while waiting out a backoff; a second signal kills the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -41,7 +75,35 @@ func init() {
 	rootCmd.AddCommand(projectCmd)
 	rootCmd.AddCommand(fieldCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(conflictsCmd)
+	rootCmd.AddCommand(ticketCmd)
+	rootCmd.AddCommand(transitionCmd)
+	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(expandCmd)
+	rootCmd.AddCommand(queryCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(reindexCmd)
+	rootCmd.AddCommand(migrateFilesCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(contextCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(reportsCmd)
+	rootCmd.AddCommand(digestCmd)
+	rootCmd.AddCommand(releaseNotesCmd)
+	rootCmd.AddCommand(renderCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(checkPermissionsCmd)
+	rootCmd.AddCommand(refreshMetadataCmd)
+	rootCmd.AddCommand(gcCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(docsCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(doctorCmd)
 
-	// Global flags can be added here if needed
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.jiramd.yaml)")
+	// Global flags
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to config file (default $JIRAMD_CONFIG, a .jiramd.yaml above the current directory, or "+defaultConfigPath+")")
+	rootCmd.PersistentFlags().StringArrayVar(&configSets, "set", nil, "Override a setting, e.g. --set sync.interval=1m (repeatable)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "Output format: text or json")
 }
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+)
+
+var migrateFilesDryRun bool
+
+// migrateFilesCmd represents the migrate-files command
+var migrateFilesCmd = &cobra.Command{
+	Use:   "migrate-files",
+	Short: "Upgrade ticket files to the current frontmatter schema",
+	Long: `Upgrade the frontmatter of the ticket files in the markdown directory
+to the schema this version of jiramd writes.
+
+Ticket files record the schema they were written with in jiramd_schema.
+When a release changes the frontmatter (renamed keys, new required keys),
+files written before it are upgraded here rather than left behind: keys
+are renamed, missing ones are added, and the body and any keys you added
+are kept as they are. Files already on the current schema are not touched,
+and files from a newer jiramd are reported rather than changed.
+
+Files without jiramd_schema predate schema versioning and are upgraded
+from version 0. Exits with status 4 when some files could not be upgraded.`,
+	Example: `  jiramd migrate-files --dry-run
+  jiramd migrate-files`,
+	Args: cobra.NoArgs,
+	RunE: runMigrateFiles,
+}
+
+func init() {
+	migrateFilesCmd.Flags().BoolVar(&migrateFilesDryRun, "dry-run", false, "List the files that would be upgraded without changing them")
+}
+
+// migrateFilesEntry is the outcome for one ticket file in migrate-files output.
+type migrateFilesEntry struct {
+	File   string `json:"file"`
+	From   int    `json:"from"`
+	Status string `json:"status"` // upgraded, would_upgrade, or failed
+	Error  string `json:"error,omitempty"`
+}
+
+// migrateFilesResult is the structured output of the migrate-files command.
+type migrateFilesResult struct {
+	Directory string              `json:"directory"`
+	Schema    int                 `json:"schema"`
+	DryRun    bool                `json:"dry_run"`
+	Upgraded  int                 `json:"upgraded"`
+	Failed    int                 `json:"failed"`
+	Files     []migrateFilesEntry `json:"files"`
+}
+
+func (r migrateFilesResult) renderText(w io.Writer) {
+	if len(r.Files) == 0 {
+		fmt.Fprintf(w, "All ticket files in %s are on schema %d.\n", r.Directory, r.Schema)
+		return
+	}
+
+	for _, f := range r.Files {
+		switch f.Status {
+		case "failed":
+			fmt.Fprintf(w, "  %-8s %s: %s\n", "FAILED", f.File, f.Error)
+		default:
+			fmt.Fprintf(w, "  %-8s %s  (schema %d -> %d)\n", "upgrade", f.File, f.From, r.Schema)
+		}
+	}
+
+	fmt.Fprintln(w)
+	if r.DryRun {
+		fmt.Fprintf(w, "Dry run: %d files would be upgraded to schema %d, %d cannot be.\n", r.Upgraded, r.Schema, r.Failed)
+		return
+	}
+	fmt.Fprintf(w, "Upgraded %d files to schema %d, %d failed.\n", r.Upgraded, r.Schema, r.Failed)
+}
+
+// runMigrateFiles upgrades the ticket files in the markdown directory.
+func runMigrateFiles(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	codec, err := markdown.NewFrontmatterCodec(cfg.Markdown.Frontmatter)
+	if err != nil {
+		return err
+	}
+
+	files, err := markdown.NewFileMigrator(cfg.Sync.MarkdownDir, codec).Migrate(cmd.Context(), migrateFilesDryRun)
+	if err != nil {
+		return err
+	}
+
+	result := migrateFilesResult{
+		Directory: cfg.Sync.MarkdownDir,
+		Schema:    markdown.SchemaVersion,
+		DryRun:    migrateFilesDryRun,
+		Files:     make([]migrateFilesEntry, 0, len(files)),
+	}
+	for _, f := range files {
+		entry := migrateFilesEntry{File: f.Path, From: f.From}
+		switch {
+		case f.Err != nil:
+			entry.Status = "failed"
+			entry.Error = f.Err.Error()
+			result.Failed++
+		case migrateFilesDryRun:
+			entry.Status = "would_upgrade"
+			result.Upgraded++
+		default:
+			entry.Status = "upgraded"
+			result.Upgraded++
+		}
+		result.Files = append(result.Files, entry)
+	}
+
+	if err := render(cmd, result); err != nil {
+		return err
+	}
+	if result.Failed > 0 {
+		return withExitCode(exitPartialFailure, errors.New("some ticket files could not be upgraded"))
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/editor"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	openPrint bool
+	editPrint bool
+)
+
+// openCmd opens a ticket in Jira
+var openCmd = &cobra.Command{
+	Use:   "open KEY",
+	Short: "Open a ticket in Jira in the browser",
+	Long: `Open a ticket's page in Jira in the default browser ($BROWSER when set).
+
+With --print the address is printed instead, e.g. for editor integrations.`,
+	Example: `  jiramd open JMD-42
+  jiramd open JMD-42 --print`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArgTicketKey,
+	RunE:              runOpen,
+}
+
+// editCmd opens a ticket's markdown file in the editor
+var editCmd = &cobra.Command{
+	Use:   "edit KEY",
+	Short: "Open a ticket's markdown file in the editor",
+	Long: `Open a ticket's markdown file in $VISUAL or $EDITOR.
+
+The file is found where the ticket was last written, or anywhere under the
+markdown directory if it was moved. A ticket without a file is pulled from
+Jira first, like jiramd pull does (ad hoc for tickets outside the synced
+project), which writes its file and caches it.
+
+With --print the path is printed instead, e.g. for editor integrations.`,
+	Example: `  jiramd edit JMD-42
+  code "$(jiramd edit JMD-42 --print)"`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArgTicketKey,
+	RunE:              runEdit,
+}
+
+func init() {
+	openCmd.Flags().BoolVar(&openPrint, "print", false, "Print the address instead of opening it")
+	editCmd.Flags().BoolVar(&editPrint, "print", false, "Print the path instead of opening it")
+}
+
+// openResult is the structured output of the open command with --print.
+type openResult struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+func (r openResult) renderText(w io.Writer) {
+	fmt.Fprintln(w, r.URL)
+}
+
+// editResult is the structured output of the edit command with --print.
+type editResult struct {
+	Key    string `json:"key"`
+	Path   string `json:"path"`
+	Pulled bool   `json:"pulled"`
+}
+
+func (r editResult) renderText(w io.Writer) {
+	fmt.Fprintln(w, r.Path)
+}
+
+// runOpen opens a ticket's page in Jira.
+func runOpen(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	key, err := domain.NewTicketKey(args[0])
+	if err != nil {
+		return err
+	}
+
+	url := key.BrowseURL(cfg.Jira.BaseURL)
+	if openPrint {
+		return render(cmd, openResult{Key: key.String(), URL: url})
+	}
+	return runExternal(browserCommand(url))
+}
+
+// runEdit opens a ticket's markdown file, pulling the ticket first if it has none.
+func runEdit(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		parser, err := newMarkdownParser(cfg)
+		if err != nil {
+			return err
+		}
+		service := editor.NewService(stateRepo, markdown.NewTicketFiles(cfg.Sync.MarkdownDir, parser))
+
+		// Without a usable Jira client, only tickets that already have a file can be edited
+		if client, err := newMonitoredJiraClient(ctx, cfg, db); err == nil {
+			puller, err := newPullService(cfg, db, stateRepo, client)
+			if err != nil {
+				return err
+			}
+			service.WithPuller(puller)
+		}
+
+		path, pulled, err := service.LocalFile(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if editPrint {
+			return render(cmd, editResult{Key: args[0], Path: path, Pulled: pulled})
+		}
+		if pulled {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Pulled %s from Jira into %s\n", args[0], path)
+		}
+
+		editorCmd := editorCommand(path)
+		editorCmd.Stdin = os.Stdin
+		editorCmd.Stdout = cmd.OutOrStdout()
+		editorCmd.Stderr = cmd.ErrOrStderr()
+		return runExternal(editorCmd)
+	})
+}
+
+// newMarkdownParser returns a parser generating ticket files as cfg.Markdown configures.
+func newMarkdownParser(cfg *domain.Config) (*markdown.Parser, error) {
+	codec, err := markdown.NewFrontmatterCodec(cfg.Markdown.Frontmatter)
+	if err != nil {
+		return nil, err
+	}
+	parser := markdown.NewParser().
+		WithFlavor(cfg.Markdown.Flavor).
+		WithFrontmatter(codec).
+		WithKeyOrder(cfg.Markdown.KeyOrder).
+		WithDisplay(cfg.Display).
+		WithContentLimits(cfg.Markdown.LimitsFor)
+	if cfg.Markdown.DownloadMedia {
+		parser.WithAttachmentLinks(markdown.AttachmentDir)
+	}
+	return parser, nil
+}
+
+// browserCommand returns the command opening url in $BROWSER, or the platform's default
+// browser.
+func browserCommand(url string) *exec.Cmd {
+	if browser := strings.Fields(os.Getenv("BROWSER")); len(browser) > 0 {
+		return exec.Command(browser[0], append(browser[1:], url)...)
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url)
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		return exec.Command("xdg-open", url)
+	}
+}
+
+// editorCommand returns the command opening path in $VISUAL or $EDITOR (which may carry
+// arguments, e.g. "code --wait"), or the platform's default editor.
+func editorCommand(path string) *exec.Cmd {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if command := strings.Fields(os.Getenv(name)); len(command) > 0 {
+			return exec.Command(command[0], append(command[1:], path)...)
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return exec.Command("notepad", path)
+	}
+	return exec.Command("vi", path)
+}
+
+// runExternal runs an external command, naming it in the error if it fails.
+func runExternal(c *exec.Cmd) error {
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", c.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/progress"
+	"github.com/esfisher/jiramd/internal/domain"
+	infraprogress "github.com/esfisher/jiramd/internal/infrastructure/progress"
+)
+
+// Supported values for the global --output flag.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// outputFormat holds the value of the global --output flag.
+var outputFormat = outputText
+
+// textRenderer is implemented by every command result so it can be printed for humans.
+// The same value is marshalled as-is for --output json, so result types carry json tags.
+type textRenderer interface {
+	renderText(w io.Writer)
+}
+
+// validateOutputFormat rejects unknown --output values before any command runs.
+func validateOutputFormat(cmd *cobra.Command, args []string) error {
+	switch outputFormat {
+	case outputText, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid --output %q (expected %s or %s)", outputFormat, outputText, outputJSON)
+	}
+}
+
+// render writes a command result to stdout in the selected output format.
+func render(cmd *cobra.Command, result textRenderer) error {
+	w := cmd.OutOrStdout()
+
+	if outputFormat == outputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	result.renderText(w)
+	return nil
+}
+
+// cliProgress returns a progress bar on stderr for long-running commands. Progress is
+// only shown for text output to an interactive terminal, so scripts and JSON consumers
+// see nothing extra.
+func cliProgress() progress.Progress {
+	if outputFormat != outputText || nonInteractive {
+		return progress.Nop()
+	}
+	info, err := os.Stderr.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return progress.Nop()
+	}
+	return infraprogress.NewBar(os.Stderr)
+}
+
+// timeDisplay is how text output shows timestamps; loadConfig sets it from the display
+// configuration.
+var timeDisplay = domain.DisplayConfig{Location: time.Local, DateFormat: domain.DefaultDateFormat}
+
+// formatTime renders a timestamp for text output, using "never" for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return timeDisplay.FormatTime(t)
+}
+
+// optionalTime converts a zero time to nil so JSON output uses null rather than year 1.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
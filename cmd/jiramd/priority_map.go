@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+// checkPriorityMaps is the doctor check of sync.priority_map: every project's map is
+// compared with the priorities in its cached metadata (see refresh-metadata), warning
+// about names the project does not have. It is only checked when the state database
+// exists, so doctor never creates it.
+func checkPriorityMaps(ctx context.Context, cfg *domain.Config) []doctorCheck {
+	const name = "priority map"
+
+	if len(cfg.Sync.Priorities) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(cfg.Storage.DBPath); errors.Is(err, os.ErrNotExist) {
+		return []doctorCheck{{Name: name, Status: doctorWarn, Detail: "no cached metadata to check against; run jiramd refresh-metadata"}}
+	} else if err != nil {
+		return []doctorCheck{{Name: name, Status: doctorWarn, Detail: "could not read cached metadata: " + err.Error()}}
+	}
+
+	db, err := openDatabase(ctx, cfg, discardLogger())
+	if err != nil {
+		return []doctorCheck{{Name: name, Status: doctorWarn, Detail: "could not read cached metadata: " + err.Error()}}
+	}
+	defer db.Close()
+	metadataRepo := sqlite.NewProjectMetadataRepository(db.DB(), discardLogger())
+
+	projects := make([]string, 0, len(cfg.Sync.Priorities))
+	for project := range cfg.Sync.Priorities {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	var checks []doctorCheck
+	for _, project := range projects {
+		metadata, err := metadataRepo.FindProjectMetadata(ctx, project)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			checks = append(checks, doctorCheck{Name: name, Status: doctorWarn,
+				Detail: fmt.Sprintf("%s: no cached metadata to check against; run jiramd refresh-metadata --project %s", project, project)})
+			continue
+		case err != nil:
+			checks = append(checks, doctorCheck{Name: name, Status: doctorWarn,
+				Detail: fmt.Sprintf("%s: could not read cached metadata: %v", project, err)})
+			continue
+		}
+
+		priorities := cfg.Sync.Priorities[project]
+		for _, local := range priorities.Unknown(metadata) {
+			checks = append(checks, doctorCheck{Name: name, Status: doctorWarn,
+				Detail: fmt.Sprintf("%s: %s maps to %q, which is not a priority of the project (known: %s)",
+					project, local, priorities[local], strings.Join(metadata.Priorities, ", "))})
+		}
+	}
+	if len(checks) == 0 {
+		return []doctorCheck{{Name: name, Status: doctorOK, Detail: "every mapped priority exists"}}
+	}
+	return checks
+}
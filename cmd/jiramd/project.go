@@ -1,9 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
 )
 
 // projectCmd represents the project command
@@ -18,15 +24,84 @@ Subcommands allow you to:
   - Remove a project from sync
   - View project details`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// TODO: Implement project command or show help
-		fmt.Println("project command not yet implemented")
 		cmd.Help()
 	},
 }
 
+// projectListCmd lists configured projects
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured projects",
+	RunE:  runProjectList,
+}
+
 func init() {
 	// Add subcommands for project management
-	// projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectListCmd)
 	// projectCmd.AddCommand(projectAddCmd)
 	// projectCmd.AddCommand(projectRemoveCmd)
 }
+
+// projectEntry describes a configured project and its sync state.
+type projectEntry struct {
+	projectStatus
+	Configured bool `json:"configured"`
+}
+
+// projectListResult is the structured output of the project list command.
+type projectListResult struct {
+	Projects []projectEntry `json:"projects"`
+}
+
+func (r projectListResult) renderText(w io.Writer) {
+	for _, p := range r.Projects {
+		note := ""
+		if !p.Configured {
+			note = " (no longer configured)"
+		}
+		fmt.Fprintf(w, "%s%s: %d tickets, last sync %s\n",
+			p.ProjectKey, note, p.TicketCount, formatTimePtr(p.LastIncrementalSync))
+	}
+}
+
+// runProjectList lists the configured project plus any other projects with stored state.
+func runProjectList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		result := projectListResult{Projects: make([]projectEntry, 0)}
+
+		configured := projectEntry{projectStatus: projectStatus{ProjectKey: cfg.Jira.Project}, Configured: true}
+		state, err := stateRepo.GetProjectState(ctx, cfg.Jira.Project)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		if state != nil {
+			configured.LastFullSync = optionalTime(state.LastFullSync)
+			configured.LastIncrementalSync = optionalTime(state.LastIncrementalSync)
+			configured.TicketCount = state.TicketCount
+		}
+		result.Projects = append(result.Projects, configured)
+
+		// Projects that were synced in the past but are no longer configured
+		states, err := stateRepo.GetAllProjectStates(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range states {
+			if s.ProjectKey == cfg.Jira.Project {
+				continue
+			}
+			result.Projects = append(result.Projects, projectEntry{
+				projectStatus: projectStatus{
+					ProjectKey:          s.ProjectKey,
+					LastFullSync:        optionalTime(s.LastFullSync),
+					LastIncrementalSync: optionalTime(s.LastIncrementalSync),
+					TicketCount:         s.TicketCount,
+				},
+			})
+		}
+
+		return render(cmd, result)
+	})
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/pull"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/jira"
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+	"github.com/esfisher/jiramd/internal/infrastructure/mediacache"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var pullAdhoc bool
+
+// pullCmd pulls a single ticket from Jira
+var pullCmd = &cobra.Command{
+	Use:   "pull KEY",
+	Short: "Pull a single ticket from Jira now",
+	Long: `Pull a single ticket from Jira into its markdown file and the local cache,
+without waiting for the next sync. An existing file is rewritten where it is,
+keeping local notes. Its comments are only fetched again, and its comments
+section rewritten, when they changed in Jira since the last pull.
+
+A ticket with local changes that are not pushed yet is only pulled when
+sync.conflicts lets Jira win; the local file is saved to
+.jiramd/local-versions/ first.
+
+With --adhoc, a ticket outside the synced project is pulled, as long as you can
+see it in Jira. It is written under adhoc/ and tracked as pull-only: pulling it
+again refreshes it, but local changes to it are never pushed, and its project is
+not added to the sync.`,
+	Example: `  jiramd pull JMD-42
+  jiramd pull OPS-7 --adhoc`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArgTicketKey,
+	RunE:              runPull,
+}
+
+func init() {
+	pullCmd.Flags().BoolVar(&pullAdhoc, "adhoc", false, "Pull a ticket outside the synced project as pull-only")
+}
+
+// pullResult is the structured output of the pull command.
+type pullResult struct {
+	Key          string `json:"key"`
+	Summary      string `json:"summary"`
+	Path         string `json:"path"`
+	Created      bool   `json:"created"`
+	Adhoc        bool   `json:"adhoc"`
+	LocalVersion string `json:"local_version,omitempty"`
+}
+
+func (r pullResult) renderText(w io.Writer) {
+	verb := "Updated"
+	if r.Created {
+		verb = "Created"
+	}
+	fmt.Fprintf(w, "Pulled %s: %s\n", r.Key, r.Summary)
+	fmt.Fprintf(w, "%s %s\n", verb, r.Path)
+	if r.LocalVersion != "" {
+		fmt.Fprintf(w, "Your local changes were saved to %s before pulling the Jira version.\n", r.LocalVersion)
+	}
+	if r.Adhoc {
+		fmt.Fprintln(w, "The ticket is pull-only: local changes to it are not pushed.")
+	}
+}
+
+// runPull pulls one ticket from Jira.
+func runPull(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		client, err := newMonitoredJiraClient(ctx, cfg, db)
+		if err != nil {
+			return err
+		}
+		service, err := newPullService(cfg, db, stateRepo, client)
+		if err != nil {
+			return err
+		}
+
+		result, err := service.Pull(ctx, args[0], pullAdhoc)
+		if err != nil {
+			return err
+		}
+		return render(cmd, pullResult{
+			Key:          result.Ticket.Key.String(),
+			Summary:      result.Ticket.Summary,
+			Path:         result.Path,
+			Created:      result.Created,
+			Adhoc:        result.Adhoc,
+			LocalVersion: result.LocalVersion,
+		})
+	})
+}
+
+// newPullService returns the service pulling single tickets from client into the files
+// and cache of cfg's project, with the comments that changed since their last pull.
+func newPullService(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository, client *jira.Client) (*pull.Service, error) {
+	parser, err := newMarkdownParser(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newPullServiceWithParser(cfg, db, stateRepo, client, parser).
+		WithComments(client).
+		WithCommentStates(sqlite.NewCommentStateRepository(db.DB(), cliLogger()), client), nil
+}
+
+// newPullServiceWithParser returns the service pulling single tickets like
+// newPullService, writing their files with parser.
+func newPullServiceWithParser(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository, client *jira.Client, parser *markdown.Parser) *pull.Service {
+	logger := cliLogger()
+	service := pull.NewService(
+		client,
+		stateRepo,
+		sqlite.NewTicketRepository(db.DB(), logger).WithCipher(db.Cipher()),
+		markdown.NewTicketFiles(cfg.Sync.MarkdownDir, parser),
+		func() repository.UnitOfWork { return markdown.NewUnitOfWork(stateRepo, logger) },
+		cfg.Jira.Project,
+	).WithLocks(sqlite.NewLockManager(db.DB(), logger)).
+		WithActivity(newDigestService(cfg, db, logger)).
+		WithFetchedTickets(sqlite.NewFetchedTicketRepository(db.DB(), logger).WithCipher(db.Cipher())).
+		WithPolicy(cfg.Sync.Policy()).
+		WithFieldDirections(cfg.Sync.FieldDirectionsFor).
+		WithAuthors(cfg.Markdown.Authors).
+		WithStatusHistory(client, sqlite.NewStatusHistoryRepository(db.DB(), logger)).
+		WithLocalVersions(markdown.NewLocalVersionWriter(cfg.Sync.MarkdownDir), sqlite.NewLocalVersionRepository(db.DB(), logger)).
+		WithLogger(logger)
+	if cfg.Markdown.DownloadMedia {
+		// Cached by attachment, so other vaults and deleted files do not download it again
+		cache := mediacache.New(cfg.Storage.CacheDir, cfg.Storage.CacheMaxSize)
+		service.WithMedia(markdown.NewMediaWriter(cfg.Sync.MarkdownDir, cache.Attachments(client)))
+	}
+	return service
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/query"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	queryLocal bool
+	queryLimit int
+)
+
+// queryCmd represents the query command
+var queryCmd = &cobra.Command{
+	Use:   "query JQL",
+	Short: "Find tickets with JQL",
+	Long: `Run a JQL query and list the matching tickets.
+
+By default the query is sent to Jira as-is. With --local it is evaluated
+against the local ticket cache instead, which works offline but supports
+only common JQL: field comparisons (=, !=, ~, IN, IS EMPTY, date ranges),
+AND/OR/NOT, currentUser(), now(), and ORDER BY.`,
+	Example: `  jiramd query 'assignee = currentUser() AND status != Done'
+  jiramd query --local 'project = JMD AND updated >= -7d ORDER BY updated DESC'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuery,
+}
+
+func init() {
+	queryCmd.Flags().BoolVar(&queryLocal, "local", false, "Evaluate the query against the local cache instead of Jira")
+	queryCmd.Flags().IntVarP(&queryLimit, "limit", "n", 50, "Maximum number of tickets to list (0 for all)")
+}
+
+// queryTicket is one matching ticket.
+type queryTicket struct {
+	Key      string `json:"key"`
+	Summary  string `json:"summary"`
+	Status   string `json:"status"`
+	Assignee string `json:"assignee"`
+}
+
+// queryResult is the structured output of the query command.
+type queryResult struct {
+	JQL     string        `json:"jql"`
+	Source  string        `json:"source"`
+	Tickets []queryTicket `json:"tickets"`
+}
+
+func (r queryResult) renderText(w io.Writer) {
+	if len(r.Tickets) == 0 {
+		fmt.Fprintln(w, "No matching tickets.")
+		return
+	}
+
+	for _, t := range r.Tickets {
+		fmt.Fprintf(w, "%-12s %-14s %s\n", t.Key, t.Status, t.Summary)
+	}
+}
+
+// runQuery runs the JQL query remotely or locally and lists the matches.
+func runQuery(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		client, err := newMonitoredJiraClient(ctx, cfg, db)
+		if err != nil {
+			return err
+		}
+		service := query.NewService(
+			client,
+			sqlite.NewTicketRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()),
+			cfg.Jira.Email,
+		)
+
+		var tickets []*domain.Ticket
+		source := "jira"
+		if queryLocal {
+			source = "local"
+			tickets, err = service.Local(ctx, args[0], queryLimit)
+		} else {
+			tickets, err = service.Remote(ctx, args[0], queryLimit)
+		}
+		if err != nil {
+			return err
+		}
+
+		result := queryResult{JQL: args[0], Source: source, Tickets: make([]queryTicket, 0, len(tickets))}
+		for _, t := range tickets {
+			result.Tickets = append(result.Tickets, queryTicket{
+				Key:      t.Key.String(),
+				Summary:  t.Summary,
+				Status:   t.Status,
+				Assignee: t.Assignee,
+			})
+		}
+
+		return render(cmd, result)
+	})
+}
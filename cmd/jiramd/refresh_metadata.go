@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/metadata"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var refreshMetadataProject string
+
+// refreshMetadataCmd represents the refresh-metadata command
+var refreshMetadataCmd = &cobra.Command{
+	Use:   "refresh-metadata",
+	Short: "Refresh the cached issue types, priorities, statuses, and transitions of a project",
+	Long: `Fetch a project's issue types, priorities, statuses, components, and
+workflow transitions from Jira and replace the cached copy.
+
+Edits to ticket frontmatter are validated against the cache, which is
+refreshed automatically once it is older than sync.metadata_ttl (24h by
+default). Run this after changing the project's configuration in Jira to
+pick up the change right away.
+
+Jira only lists transitions per ticket, so they are learned from one cached
+ticket in each status; sync the project first for a complete map.`,
+	Example: `  jiramd refresh-metadata
+  jiramd refresh-metadata --project OPS --output json`,
+	Args: cobra.NoArgs,
+	RunE: runRefreshMetadata,
+}
+
+func init() {
+	refreshMetadataCmd.Flags().StringVarP(&refreshMetadataProject, "project", "p", "", "Project key (default jira.project)")
+	refreshMetadataCmd.RegisterFlagCompletionFunc("project", completeProjectKeys)
+}
+
+// refreshMetadataResult is the structured output of the refresh-metadata command.
+type refreshMetadataResult struct {
+	ProjectKey  string              `json:"project_key"`
+	Name        string              `json:"name"`
+	IssueTypes  []string            `json:"issue_types"`
+	Priorities  []string            `json:"priorities"`
+	Statuses    []string            `json:"statuses"`
+	Components  []string            `json:"components"`
+	Transitions map[string][]string `json:"transitions"`
+	FetchedAt   time.Time           `json:"fetched_at"`
+}
+
+func (r refreshMetadataResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Refreshed metadata of %s", r.ProjectKey)
+	if r.Name != "" {
+		fmt.Fprintf(w, " (%s)", r.Name)
+	}
+	fmt.Fprintln(w)
+
+	for _, field := range []struct {
+		title  string
+		values []string
+	}{
+		{"Issue types", r.IssueTypes},
+		{"Priorities", r.Priorities},
+		{"Statuses", r.Statuses},
+		{"Components", r.Components},
+	} {
+		fmt.Fprintf(w, "  %-12s %d", field.title+":", len(field.values))
+		if len(field.values) > 0 {
+			fmt.Fprintf(w, " (%s)", strings.Join(field.values, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+
+	statuses := make([]string, 0, len(r.Transitions))
+	for status := range r.Transitions {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	fmt.Fprintf(w, "  %-12s known from %d of %d statuses\n", "Transitions:", len(statuses), len(r.Statuses))
+	for _, status := range statuses {
+		fmt.Fprintf(w, "    %s -> %s\n", status, strings.Join(r.Transitions[status], ", "))
+	}
+}
+
+// runRefreshMetadata fetches a project's metadata from Jira into the cache.
+func runRefreshMetadata(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, _ repository.StateRepository) error {
+		projectKey := refreshMetadataProject
+		if projectKey == "" {
+			projectKey = cfg.Jira.Project
+		}
+
+		client, err := newMonitoredJiraClient(ctx, cfg, db)
+		if err != nil {
+			return err
+		}
+
+		service := metadata.NewService(client,
+			sqlite.NewProjectMetadataRepository(db.DB(), cliLogger()),
+			sqlite.NewTicketRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()),
+		).WithTTL(cfg.Sync.MetadataTTL).WithLogger(cliLogger())
+
+		refreshed, err := service.Refresh(ctx, projectKey)
+		if err != nil {
+			return err
+		}
+		return render(cmd, refreshMetadataResult{
+			ProjectKey:  refreshed.ProjectKey,
+			Name:        refreshed.Name,
+			IssueTypes:  refreshed.IssueTypes,
+			Priorities:  refreshed.Priorities,
+			Statuses:    refreshed.Statuses,
+			Components:  refreshed.Components,
+			Transitions: refreshed.Transitions,
+			FetchedAt:   refreshed.FetchedAt,
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/search"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+// reindexCmd represents the reindex command
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the full-text search index",
+	Long: `Rebuild the full-text search index used by jiramd search from the
+local ticket cache.
+
+The index is updated automatically whenever tickets or comments are cached.
+Rebuilding is only needed after bulk imports or other changes made to the
+state database outside jiramd.`,
+	Args: cobra.NoArgs,
+	RunE: runReindex,
+}
+
+// reindexResult is the structured output of the reindex command.
+type reindexResult struct {
+	Tickets    int   `json:"tickets"`
+	DurationMS int64 `json:"duration_ms"`
+}
+
+func (r reindexResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Indexed %d tickets in %dms\n", r.Tickets, r.DurationMS)
+}
+
+// runReindex rebuilds the search index.
+func runReindex(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		service := search.NewService(sqlite.NewSearchRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()))
+
+		start := time.Now()
+		indexed, err := service.Reindex(ctx)
+		if err != nil {
+			return err
+		}
+
+		return render(cmd, reindexResult{Tickets: indexed, DurationMS: time.Since(start).Milliseconds()})
+	})
+}
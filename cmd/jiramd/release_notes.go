@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/releasenotes"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	releaseNotesVersion  string
+	releaseNotesTemplate string
+	releaseNotesFilter   string
+	releaseNotesFile     string
+)
+
+// releaseNotesCmd represents the release-notes command
+var releaseNotesCmd = &cobra.Command{
+	Use:   "release-notes",
+	Short: "Write release notes for the tickets fixed in a version",
+	Long: `Write markdown release notes for the cached tickets whose Fix Version is
+--version, grouped by issue type.
+
+--template replaces the built-in layout with a Go text/template file. It is
+executed with .Version, .Date, .Total, and .Groups; each group has
+.IssueType and .Tickets, and each ticket .Key, .Summary, .Status,
+.Priority, .Assignee, and .Labels.
+
+Only the local cache is read; run jiramd sync first for up-to-date data.
+--filter narrows the notes with the JQL subset supported by
+jiramd query --local, e.g. to one project.`,
+	Example: `  jiramd release-notes --version 1.4.0
+  jiramd release-notes --version 1.4.0 --filter 'project = JMD' --file RELEASE-1.4.0.md
+  jiramd release-notes --version 1.4.0 --template release.tmpl`,
+	Args: cobra.NoArgs,
+	RunE: runReleaseNotes,
+}
+
+func init() {
+	releaseNotesCmd.Flags().StringVar(&releaseNotesVersion, "version", "", "Fix version to write release notes for (required)")
+	releaseNotesCmd.Flags().StringVar(&releaseNotesTemplate, "template", "", "Render with this text/template file instead of the built-in layout")
+	releaseNotesCmd.Flags().StringVar(&releaseNotesFilter, "filter", "", "Only include tickets matching this JQL")
+	releaseNotesCmd.Flags().StringVar(&releaseNotesFile, "file", "", "Write the release notes to a file instead of stdout")
+	releaseNotesCmd.MarkFlagRequired("version")
+}
+
+// releaseNotesResult is the structured output of the release-notes command when
+// writing to a file.
+type releaseNotesResult struct {
+	File    string `json:"file"`
+	Version string `json:"version"`
+	Tickets int    `json:"tickets"`
+}
+
+func (r releaseNotesResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Wrote release notes for %s (%d tickets) to %s\n", r.Version, r.Tickets, r.File)
+}
+
+// runReleaseNotes writes the release notes to stdout or a file.
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	var text string
+	if releaseNotesTemplate != "" {
+		data, err := os.ReadFile(releaseNotesTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to read release notes template: %w", err)
+		}
+		text = string(data)
+	}
+	tmpl, err := releasenotes.ParseTemplate(text)
+	if err != nil {
+		return err
+	}
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		service := releasenotes.NewService(sqlite.NewTicketRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()), cfg.Jira.Email)
+
+		if releaseNotesFile == "" {
+			_, err := service.Write(ctx, cmd.OutOrStdout(), tmpl, releaseNotesVersion, releaseNotesFilter)
+			return err
+		}
+
+		file, err := os.Create(releaseNotesFile)
+		if err != nil {
+			return fmt.Errorf("failed to create release notes file: %w", err)
+		}
+
+		notes, err := service.Write(ctx, file, tmpl, releaseNotesVersion, releaseNotesFilter)
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write release notes file: %w", closeErr)
+		}
+		if err != nil {
+			os.Remove(releaseNotesFile)
+			return err
+		}
+
+		return render(cmd, releaseNotesResult{File: releaseNotesFile, Version: notes.Version, Tickets: notes.Total})
+	})
+}
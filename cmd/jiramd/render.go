@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/site"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/htmlsite"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	renderOut    string
+	renderFilter string
+	renderTitle  string
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render the cached tickets as a static HTML site",
+	Long: `Render the cached tickets as a small static HTML site, for sharing a
+read-only view with people who do not use Jira.
+
+The site has an index of every ticket, with a search box that matches
+tickets containing every term in their summary, description, or comments
+(like jiramd search), and a page per ticket with its fields and comments.
+It needs no server: open index.html, or publish the directory anywhere.
+Pages of tickets no longer in the cache are removed on the next render.
+
+Only the local cache is read; run jiramd sync first for up-to-date data.
+--filter narrows the site with the JQL subset supported by
+jiramd query --local.`,
+	Example: `  jiramd render --out ./site
+  jiramd render --out ./site --filter 'project = JMD AND status != Done' --title "JMD roadmap"`,
+	Args: cobra.NoArgs,
+	RunE: runRender,
+}
+
+func init() {
+	renderCmd.Flags().StringVar(&renderOut, "out", "site", "Directory to write the site to")
+	renderCmd.Flags().StringVar(&renderFilter, "filter", "", "Only render tickets matching this JQL")
+	renderCmd.Flags().StringVar(&renderTitle, "title", "", "Site title (default: the configured project)")
+	renderCmd.MarkFlagDirname("out")
+}
+
+// renderResult is the structured output of the render command.
+type renderResult struct {
+	Dir     string `json:"dir"`
+	Tickets int    `json:"tickets"`
+}
+
+func (r renderResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Rendered %d tickets to %s\n", r.Tickets, filepath.Join(r.Dir, "index.html"))
+}
+
+// runRender writes the static site.
+func runRender(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		title := renderTitle
+		if title == "" {
+			title = cfg.Jira.Project + " tickets"
+		}
+
+		service := site.NewService(
+			sqlite.NewTicketRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()),
+			sqlite.NewCommentRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()),
+			cfg.Jira.Email,
+		)
+		rendered, err := service.Render(ctx, htmlsite.NewRenderer(renderOut).WithDisplay(cfg.Display), title, renderFilter)
+		if err != nil {
+			return err
+		}
+
+		return render(cmd, renderResult{Dir: renderOut, Tickets: len(rendered.Pages)})
+	})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/reports"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+// reportsCmd writes the markdown reports now
+var reportsCmd = &cobra.Command{
+	Use:   "reports",
+	Short: "Write the burndown and aging WIP markdown reports",
+	Long: `Write the markdown reports into reports.dir (reports/ under the markdown
+directory by default):
+
+  - aging-wip.md lists the tickets in reports.wip_statuses, longest in their
+    status first, with the days they have been in it
+  - burndown-<sprint>.md charts, day by day, how many tickets of each active
+    sprint of sync.sprint.board_id were not yet in reports.done_statuses,
+    next to an even burndown to the sprint's end
+
+Reports are computed from the cached tickets and their status changelogs; run
+jiramd sync first for up-to-date data. Only the active sprints are looked up
+in Jira. Reports that did not change are left alone.
+
+The daemon writes the reports on the reports.schedule cron expression.`,
+	Example: `  jiramd reports`,
+	Args:    cobra.NoArgs,
+	RunE:    runReports,
+}
+
+// reportsResult is the structured output of the reports command.
+type reportsResult struct {
+	Dir     string           `json:"dir"`
+	Reports []reports.Report `json:"reports"`
+}
+
+func (r reportsResult) renderText(w io.Writer) {
+	for _, report := range r.Reports {
+		verb := "Unchanged"
+		if report.Changed {
+			verb = "Wrote"
+		}
+		fmt.Fprintf(w, "%s %s\n", verb, filepath.Join(r.Dir, report.Name))
+	}
+}
+
+// runReports writes the reports once.
+func runReports(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		service := newReportsService(cfg, db, cliLogger())
+		if cfg.Sync.Sprint.Enabled() {
+			client, err := newMonitoredJiraClient(ctx, cfg, db)
+			if err != nil {
+				return err
+			}
+			service.WithSprints(client, cfg.Sync.Sprint.BoardID)
+		}
+
+		written, err := service.Generate(ctx)
+		if err != nil {
+			return err
+		}
+		return render(cmd, reportsResult{Dir: cfg.Reports.Dir, Reports: written})
+	})
+}
+
+// newReportsService returns the service writing the reports cfg configures from the
+// ticket cache in db. Burndowns need the sprints set with WithSprints.
+func newReportsService(cfg *domain.Config, db *sqlite.Database, logger *slog.Logger) *reports.Service {
+	return reports.NewService(
+		sqlite.NewTicketRepository(db.DB(), logger).WithCipher(db.Cipher()),
+		sqlite.NewStatusHistoryRepository(db.DB(), logger),
+		cfg.Reports,
+		markdown.NewReportWriter(cfg.Reports.Dir),
+		logger,
+	).WithDisplay(cfg.Display)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/search"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var searchLimit int
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search TEXT...",
+	Short: "Full-text search the local ticket cache",
+	Long: `Find cached tickets whose summary, description, or comments contain
+every search term. Results are ranked by relevance, with matches in the
+summary counting most.
+
+Search uses the local index only and never contacts Jira; run jiramd sync
+to refresh it, or jiramd reindex to rebuild it. Use "double quotes" for
+exact phrases and a trailing * for prefix matches.`,
+	Example: `  jiramd search payment timeout
+  jiramd search '"payment gateway" retr*'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20, "Maximum number of tickets to list (0 for all)")
+}
+
+// searchHit is one matching ticket.
+type searchHit struct {
+	Key     string  `json:"key"`
+	Summary string  `json:"summary"`
+	Status  string  `json:"status"`
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"`
+}
+
+// searchResult is the structured output of the search command.
+type searchResult struct {
+	Query string      `json:"query"`
+	Hits  []searchHit `json:"hits"`
+}
+
+func (r searchResult) renderText(w io.Writer) {
+	if len(r.Hits) == 0 {
+		fmt.Fprintln(w, "No matching tickets.")
+		return
+	}
+
+	for _, h := range r.Hits {
+		fmt.Fprintf(w, "%-12s %-14s %s\n", h.Key, h.Status, h.Summary)
+		// Snippets can span lines of a description or comment; show them on one
+		fmt.Fprintf(w, "%-12s %s\n\n", "", strings.Join(strings.Fields(h.Snippet), " "))
+	}
+}
+
+// runSearch searches the local full-text index and lists the matches.
+func runSearch(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	text := strings.Join(args, " ")
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		service := search.NewService(sqlite.NewSearchRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()))
+
+		hits, err := service.Search(ctx, text, searchLimit)
+		if err != nil {
+			return err
+		}
+
+		result := searchResult{Query: text, Hits: make([]searchHit, 0, len(hits))}
+		for _, h := range hits {
+			result.Hits = append(result.Hits, searchHit{
+				Key:     h.TicketKey,
+				Summary: h.Summary,
+				Status:  h.Status,
+				Snippet: h.Snippet,
+				Score:   h.Score,
+			})
+		}
+
+		return render(cmd, result)
+	})
+}
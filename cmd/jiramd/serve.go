@@ -1,11 +1,42 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/auth"
+	"github.com/esfisher/jiramd/internal/application/gc"
+	"github.com/esfisher/jiramd/internal/application/inbox"
+	"github.com/esfisher/jiramd/internal/application/reload"
+	"github.com/esfisher/jiramd/internal/application/scheduler"
+	appsync "github.com/esfisher/jiramd/internal/application/sync"
+	"github.com/esfisher/jiramd/internal/config"
+	"github.com/esfisher/jiramd/internal/domain"
+	infraConfig "github.com/esfisher/jiramd/internal/infrastructure/config"
+	"github.com/esfisher/jiramd/internal/infrastructure/httpapi"
+	"github.com/esfisher/jiramd/internal/infrastructure/jira"
+	"github.com/esfisher/jiramd/internal/infrastructure/keyring"
+	"github.com/esfisher/jiramd/internal/infrastructure/logging"
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+	"github.com/esfisher/jiramd/internal/infrastructure/progress"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	serveSkipSelfTest bool
 )
 
+// errSelfTestFailed stops the daemon when Jira cannot be used as configured.
+var errSelfTestFailed = errors.New("jira self-test failed; fix the problem above, or start with --skip-self-test")
+
 // serveCmd represents the serve command
 var serveCmd = &cobra.Command{
 	Use:   "serve",
@@ -13,19 +44,251 @@ var serveCmd = &cobra.Command{
 	Long: `Start the jiramd daemon which watches for changes to markdown files
 and Jira tickets, synchronizing them bidirectionally.
 
+On start, the daemon checks that Jira is reachable and usable: that the
+host resolves, a TLS connection can be made, the credentials are accepted,
+and the project is visible and searchable. It stops with a hint for fixing
+the first check that fails, unless --skip-self-test is given.
+
 The daemon will:
   - Watch local markdown files for changes
-  - Poll Jira for ticket updates
-  - Synchronize changes bidirectionally
-  - Maintain conflict resolution state`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// TODO: Implement serve command
-		fmt.Println("serve command not yet implemented")
-	},
+  - Poll Jira for ticket updates every sync.interval
+  - Run full syncs on the sync.full_sync_schedule cron expression
+    (catching up immediately if a scheduled run was missed)
+  - Push the changes queued by the ticket commands before every sync
+  - Maintain conflict resolution state
+  - Prune completed operations, tombstones, and sync history older than
+    storage.retention every storage.gc_interval
+  - Write the burndown and aging WIP reports on the reports.schedule cron
+    expression
+  - Serve the local control API when api.enabled is set
+
+Sending SIGHUP, or saving the config file, reloads sync.interval,
+sync.full_sync_schedule, sync.filters, sync.field_directions,
+sync.project_field_directions, and log.level without a restart; the next sync
+uses them. A config that fails to load or validate is rejected and the running
+one is kept; changes to other settings are logged as needing a restart.`,
+	RunE: runServe,
 }
 
 func init() {
 	// Add flags specific to serve command
-	// serveCmd.Flags().StringP("config", "c", "", "Path to config file")
+	serveCmd.Flags().BoolVar(&serveSkipSelfTest, "skip-self-test", false, "Start without checking that Jira is reachable and usable")
 	// serveCmd.Flags().IntP("poll-interval", "p", 60, "Jira poll interval in seconds")
 }
+
+// runServe wires up the daemon and blocks until interrupted.
+func runServe(cmd *cobra.Command, args []string) error {
+	opts, err := configOptions()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadWith(opts)
+	if err != nil {
+		return err
+	}
+
+	// The level is a variable so config reloads can change it on the running daemon
+	level := new(slog.LevelVar)
+	level.Set(reload.ParseLevel(cfg.Log.Level))
+	logger := slog.New(logging.NewCorrelationHandler(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := openDatabase(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stateRepo, closeState, err := openStateRepository(ctx, cfg, db, logger)
+	if err != nil {
+		return err
+	}
+	defer closeState()
+
+	authMonitor, err := openAuthMonitor(ctx, cfg, db, logger)
+	if err != nil {
+		return err
+	}
+
+	if !serveSkipSelfTest {
+		if err := runSelfTest(ctx, cmd.ErrOrStderr(), cfg, authMonitor, logger); err != nil {
+			return err
+		}
+	}
+	if status := authMonitor.Status(); status.Failed() {
+		logger.Error("AUTH FAILED: Jira rejected the configured credentials before the daemon started; update jira.token",
+			"failing_since", status.FailingSince,
+			"error", status.LastError)
+	}
+
+	client, err := newJiraClient(cfg, authMonitor, logger)
+	if err != nil {
+		return err
+	}
+
+	puller, err := newPullService(cfg, db, stateRepo, client)
+	if err != nil {
+		return err
+	}
+	historyRepo := sqlite.NewSyncHistoryRepository(db.DB(), logger)
+	syncService := appsync.NewService(sqlite.NewTicketRepository(db.DB(), logger).WithCipher(db.Cipher()), nil, nil, stateRepo, historyRepo, sqlite.NewLockManager(db.DB(), logger)).
+		WithProgress(progress.NewLogger(logger, progress.DefaultLogInterval)).
+		WithFieldDirections(cfg.Sync.FieldDirectionsFor).
+		WithPolicy(cfg.Sync.Policy()).
+		WithFilter(cfg.Sync.Filter, cfg.Jira.Email).
+		WithGuardrails(cfg.Sync.Guardrails).
+		WithTickets(client, puller).
+		WithBacklinks(markdown.NewBacklinkWriter(cfg.Sync.MarkdownDir, cfg.Sync.Sprint.ArchiveDir)).
+		WithIndexes(markdown.NewIndexWriter(cfg.Sync.MarkdownDir, cfg.Sync.Sprint.ArchiveDir), cfg.Sync.Indexes).
+		WithPusher(newPushService(cfg, db, stateRepo, client, authMonitor, logger)).
+		WithLogger(logger)
+	reportsService := newReportsService(cfg, db, logger)
+	if cfg.Sync.Sprint.Enabled() || len(cfg.Sync.Indexes.Filters) > 0 || len(cfg.Watchlist) > 0 {
+		syncService.WithSavedFilters(client)
+		if cfg.Sync.Sprint.Enabled() {
+			syncService.WithSprintScope(cfg.Sync.Sprint, client).
+				WithArchiver(markdown.NewArchiver(cfg.Sync.MarkdownDir, cfg.Sync.Sprint.ArchiveDir))
+			reportsService.WithSprints(client, cfg.Sync.Sprint.BoardID)
+		}
+		if len(cfg.Watchlist) > 0 {
+			syncService.WithWatchlist(cfg.Watchlist, puller)
+		}
+	}
+	schedulerService := scheduler.NewService(syncService, stateRepo, cfg.Jira.Project, cfg.Sync, logger)
+	inboxRepo := sqlite.NewInboxRepository(db.DB(), logger).WithCipher(db.Cipher())
+	inboxService := inbox.NewService(inboxRepo, schedulerService, cfg.Jira.Project, logger)
+	gcService := gc.NewService(stateRepo, sqlite.NewPendingOperationRepository(db.DB(), logger).WithCipher(db.Cipher()), historyRepo, cfg.Storage.Retention, logger).
+		WithInbox(inboxRepo).
+		WithActivity(sqlite.NewActivityRepository(db.DB(), logger).WithCipher(db.Cipher()))
+
+	logger.Info("jiramd daemon started",
+		"project", cfg.Jira.Project,
+		"storage_driver", cfg.Storage.Driver,
+		"interval", cfg.Sync.Interval,
+		"full_sync_schedule", cfg.Sync.FullSyncSchedule.String(),
+		"reports_schedule", cfg.Reports.Schedule.String(),
+		"gc_interval", cfg.Storage.GCInterval)
+
+	// A control API failure (e.g., address in use) shuts the whole daemon down
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	apiErrCh := make(chan error, 1)
+	if cfg.API.Enabled {
+		apiServer := httpapi.NewServer(schedulerService, syncService, stateRepo, logger).
+			WithAuthStatus(authMonitor).
+			WithInbox(inboxService).
+			WithWebhookSecret(cfg.API.WebhookSecret)
+		if cfg.API.RequiresToken() {
+			token, err := keyring.APIToken(cfg.Storage.DBPath)
+			if err != nil {
+				return err
+			}
+			apiServer.WithToken(token)
+		}
+		go func() {
+			err := apiServer.ListenAndServe(ctx, cfg.API)
+			if err != nil {
+				cancel()
+			}
+			apiErrCh <- err
+		}()
+	} else {
+		apiErrCh <- nil
+	}
+
+	// Reload the config on SIGHUP or when the file changes; rejected reloads keep the
+	// running config
+	reloadService := reload.NewService(config.NewLoader(opts), infraConfig.NewValidator(), opts.Path, cfg, schedulerService, level, logger)
+	reloadService.WithSyncer(syncService)
+	go reloadService.Run(ctx, reloadTriggers(ctx, opts.Path))
+
+	// Process webhook events received by the control API, starting with any left
+	// pending by a previous run
+	go inboxService.Run(ctx)
+
+	// Prune expired state in the background; gc failures never stop the daemon
+	if cfg.Storage.GCInterval > 0 {
+		go gcService.Run(ctx, cfg.Storage.GCInterval)
+	}
+
+	// Write the reports on their schedule; report failures never stop the daemon
+	if !cfg.Reports.Schedule.IsZero() {
+		go reportsService.Run(ctx)
+	}
+
+	if err := schedulerService.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	if err := <-apiErrCh; err != nil {
+		return err
+	}
+
+	logger.Info("jiramd daemon stopped")
+	return nil
+}
+
+// reloadTriggers merges SIGHUP and changes to the config file at path into one channel
+// of reload requests, closed when ctx is cancelled.
+func reloadTriggers(ctx context.Context, path string) <-chan struct{} {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	fileChanges := infraConfig.WatchFile(ctx, path, infraConfig.DefaultWatchInterval)
+
+	triggers := make(chan struct{}, 1)
+	go func() {
+		defer signal.Stop(hup)
+		defer close(triggers)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+			case <-fileChanges:
+			}
+
+			select {
+			case triggers <- struct{}{}:
+			default:
+				// A reload is already pending and will read the latest file
+			}
+		}
+	}()
+	return triggers
+}
+
+// runSelfTest checks that Jira can be used as configured, printing the failed check and
+// a hint for fixing it to w. Responses are reported to monitor, so valid credentials
+// clear an earlier authentication failure.
+func runSelfTest(ctx context.Context, w io.Writer, cfg *domain.Config, monitor *auth.Monitor, logger *slog.Logger) error {
+	client, err := jira.NewClientFromConfig(cfg.Jira)
+	if err != nil {
+		return err
+	}
+
+	report := client.WithAuthObserver(monitor).SelfTest(ctx, cfg.Jira.Project)
+	failed := report.Failed()
+	if failed == nil {
+		for _, check := range report.Checks {
+			logger.Debug("self-test check passed", "check", check.Name, "detail", check.Detail)
+		}
+		logger.Info("jira self-test passed", "base_url", cfg.Jira.BaseURL, "project", cfg.Jira.Project)
+		return nil
+	}
+
+	fmt.Fprintln(w, "Jira self-test:")
+	for _, check := range report.Checks {
+		if check.Err == nil {
+			fmt.Fprintf(w, "  ok    %-8s %s\n", check.Name, check.Detail)
+			continue
+		}
+		fmt.Fprintf(w, "  FAIL  %-8s %v\n", check.Name, check.Err)
+		fmt.Fprintf(w, "        hint: %s\n", check.Hint)
+	}
+	// Exit as the failed check would have, e.g. with exitAuthFailure for rejected credentials
+	return withExitCode(exitCode(failed.Err), errSelfTestFailed)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/stats"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+// Supported values for the stats --format flag.
+const (
+	statsFormatText     = "text"
+	statsFormatMarkdown = "markdown"
+)
+
+var (
+	statsFormat    string
+	statsFilter    string
+	statsCycleTime bool
+	statsDone      []string
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show ticket counts by status, assignee, type, and age, or cycle times",
+	Long: `Summarize the cached tickets: how many there are by status, assignee,
+issue type, and age since creation.
+
+--format markdown writes the summary as markdown tables, ready to paste into
+standup notes or a weekly snapshot. --output json writes it for scripts.
+Only the local cache is read; run jiramd sync first for up-to-date data.
+
+--filter narrows the summary with the JQL subset supported by
+jiramd query --local.
+
+--cycle-time reports flow metrics from the cached status changelogs instead:
+the time tickets spent in each status, and the cycle time of done tickets,
+from first leaving the status they were created in to last entering a done
+status. --done sets the done statuses (default Done).`,
+	Example: `  jiramd stats
+  jiramd stats --format markdown --filter 'project = JMD AND status != Done'
+  jiramd stats --cycle-time --done Done --done Closed --filter 'updated >= -30d'`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().StringVarP(&statsFormat, "format", "f", statsFormatText, "Summary format: text or markdown")
+	statsCmd.Flags().StringVar(&statsFilter, "filter", "", "Only count tickets matching this JQL")
+	statsCmd.Flags().BoolVar(&statsCycleTime, "cycle-time", false, "Report time in status and cycle times instead")
+	statsCmd.Flags().StringSliceVar(&statsDone, "done", stats.DefaultDoneStatuses, "Status tickets are done in, for --cycle-time (repeatable)")
+	statsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{statsFormatText, statsFormatMarkdown}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// statsResult is the structured output of the stats command.
+type statsResult struct {
+	*stats.Report
+}
+
+func (r statsResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Tickets: %d\n", r.Total)
+	if r.Filter != "" {
+		fmt.Fprintf(w, "Filter:  %s\n", r.Filter)
+	}
+
+	for _, section := range []struct {
+		title  string
+		counts []stats.Count
+	}{
+		{"By status", r.ByStatus},
+		{"By assignee", r.ByAssignee},
+		{"By issue type", r.ByIssueType},
+		{"By age", r.ByAge},
+	} {
+		fmt.Fprintf(w, "\n%s:\n", section.title)
+		for _, count := range section.counts {
+			fmt.Fprintf(w, "  %-30s %6d\n", count.Name, count.Count)
+		}
+	}
+}
+
+// cycleTimeResult is the structured output of the stats --cycle-time command.
+type cycleTimeResult struct {
+	*stats.CycleTimeReport
+}
+
+func (r cycleTimeResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Tickets: %d (%d done in %s)\n", r.Tickets, len(r.Cycles), strings.Join(r.DoneIn, ", "))
+	if r.Filter != "" {
+		fmt.Fprintf(w, "Filter:  %s\n", r.Filter)
+	}
+	if len(r.Cycles) > 0 {
+		fmt.Fprintf(w, "Cycle time: average %s, median %s, 85th percentile %s\n",
+			domain.FormatElapsed(r.Average), domain.FormatElapsed(r.Median), domain.FormatElapsed(r.P85))
+	}
+
+	fmt.Fprintf(w, "\nTime in status:\n")
+	for _, summary := range r.ByStatus {
+		fmt.Fprintf(w, "  %-30s %6d tickets  %10s total  %10s average\n", summary.Status, summary.Tickets,
+			domain.FormatElapsed(summary.Total), domain.FormatElapsed(summary.Average))
+	}
+
+	if len(r.Cycles) > 0 {
+		fmt.Fprintf(w, "\nDone tickets:\n")
+		for _, cycle := range r.Cycles {
+			fmt.Fprintf(w, "  %-12s %10s  %s\n", cycle.Key, domain.FormatElapsed(cycle.CycleTime), cycle.Summary)
+		}
+	}
+}
+
+// runStats summarizes the cached tickets.
+func runStats(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	switch statsFormat {
+	case statsFormatText:
+	case statsFormatMarkdown:
+		if outputFormat == outputJSON {
+			return fmt.Errorf("--format %s cannot be combined with --output %s", statsFormatMarkdown, outputJSON)
+		}
+	default:
+		return fmt.Errorf("%w: unknown stats format %q (use %s or %s)",
+			domain.ErrInvalidInput, statsFormat, statsFormatText, statsFormatMarkdown)
+	}
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		service := stats.NewService(sqlite.NewTicketRepository(db.DB(), cliLogger()).WithCipher(db.Cipher()), cfg.Jira.Email).
+			WithStatusHistory(sqlite.NewStatusHistoryRepository(db.DB(), cliLogger()))
+
+		if statsCycleTime {
+			report, err := service.CycleTime(ctx, statsFilter, statsDone)
+			if err != nil {
+				return err
+			}
+			if statsFormat == statsFormatMarkdown {
+				return stats.WriteCycleTimeMarkdown(cmd.OutOrStdout(), report)
+			}
+			return render(cmd, cycleTimeResult{CycleTimeReport: report})
+		}
+
+		report, err := service.Report(ctx, statsFilter)
+		if err != nil {
+			return err
+		}
+
+		if statsFormat == statsFormatMarkdown {
+			return stats.WriteMarkdown(cmd.OutOrStdout(), report)
+		}
+		return render(cmd, statsResult{Report: report})
+	})
+}
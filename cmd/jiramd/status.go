@@ -1,11 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/httpapi"
+	"github.com/esfisher/jiramd/internal/infrastructure/keyring"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
 )
 
+// daemonProbeTimeout bounds the control API health check made by the status command.
+const daemonProbeTimeout = time.Second
+
 // statusCmd represents the status command
 var statusCmd = &cobra.Command{
 	Use:   "status",
@@ -17,11 +32,12 @@ Displays:
   - Last sync timestamp
   - Number of tickets synchronized
   - Any pending changes or conflicts
-  - Daemon running status`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// TODO: Implement status command
-		fmt.Println("status command not yet implemented")
-	},
+  - Daemon running status
+  - Warnings for projects and files beyond sync.guardrails
+
+Exits with status 3 while Jira keeps rejecting the configured credentials,
+and 2 when tickets have sync conflicts.`,
+	RunE: runStatus,
 }
 
 func init() {
@@ -29,3 +45,197 @@ func init() {
 	// statusCmd.Flags().BoolP("verbose", "v", false, "Show detailed status information")
 	// statusCmd.Flags().StringP("project", "p", "", "Show status for specific project only")
 }
+
+// projectStatus is the sync status of a single project.
+type projectStatus struct {
+	ProjectKey          string     `json:"project_key"`
+	LastFullSync        *time.Time `json:"last_full_sync"`
+	LastIncrementalSync *time.Time `json:"last_incremental_sync"`
+	TicketCount         int        `json:"ticket_count"`
+}
+
+// authFailure describes credentials Jira keeps rejecting.
+type authFailure struct {
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	FailingSince        time.Time `json:"failing_since"`
+	LastError           string    `json:"last_error"`
+}
+
+// statusResult is the structured output of the status command.
+type statusResult struct {
+	AuthFailed    *authFailure    `json:"auth_failed"`
+	Projects      []projectStatus `json:"projects"`
+	DirtyTickets  int             `json:"dirty_tickets"`
+	Conflicts     int             `json:"conflicts"`
+	DaemonRunning *bool           `json:"daemon_running"`
+	Warnings      []string        `json:"warnings"`
+}
+
+func (r statusResult) renderText(w io.Writer) {
+	if r.AuthFailed != nil {
+		fmt.Fprintf(w, "AUTH FAILED: Jira rejected the configured credentials %d times in a row since %s.\n",
+			r.AuthFailed.ConsecutiveFailures, formatTime(r.AuthFailed.FailingSince))
+		fmt.Fprintf(w, "  Last error: %s\n", r.AuthFailed.LastError)
+		fmt.Fprintln(w, "  Pushes are paused. Update jira.token, then run 'jiramd check-permissions'.")
+		fmt.Fprintln(w)
+	}
+
+	if len(r.Projects) == 0 {
+		fmt.Fprintln(w, "No projects have been synced yet.")
+	}
+	for _, p := range r.Projects {
+		fmt.Fprintf(w, "Project %s\n", p.ProjectKey)
+		fmt.Fprintf(w, "  Last full sync:        %s\n", formatTimePtr(p.LastFullSync))
+		fmt.Fprintf(w, "  Last incremental sync: %s\n", formatTimePtr(p.LastIncrementalSync))
+		fmt.Fprintf(w, "  Tickets tracked:       %d\n", p.TicketCount)
+	}
+
+	fmt.Fprintf(w, "Dirty tickets: %d\n", r.DirtyTickets)
+	fmt.Fprintf(w, "Conflicts:     %d\n", r.Conflicts)
+
+	switch {
+	case r.DaemonRunning == nil:
+		fmt.Fprintln(w, "Daemon:        unknown (control API disabled)")
+	case *r.DaemonRunning:
+		fmt.Fprintln(w, "Daemon:        running")
+	default:
+		fmt.Fprintln(w, "Daemon:        not running")
+	}
+
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+}
+
+// runStatus gathers sync state from the state database and renders it.
+func runStatus(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		projectStates, err := stateRepo.GetAllProjectStates(ctx)
+		if err != nil {
+			return err
+		}
+		dirty, err := stateRepo.GetDirtyTickets(ctx)
+		if err != nil {
+			return err
+		}
+		conflicts, err := stateRepo.GetConflictedTickets(ctx)
+		if err != nil {
+			return err
+		}
+
+		authStatus, err := sqlite.NewAuthStatusRepository(db.DB(), cliLogger()).GetAuthStatus(ctx)
+		if err != nil {
+			return err
+		}
+
+		result := statusResult{
+			Projects:     make([]projectStatus, 0, len(projectStates)),
+			DirtyTickets: len(dirty),
+			Conflicts:    len(conflicts),
+		}
+		if authStatus.Failed() {
+			result.AuthFailed = &authFailure{
+				ConsecutiveFailures: authStatus.ConsecutiveFailures,
+				FailingSince:        authStatus.FailingSince,
+				LastError:           authStatus.LastError,
+			}
+		}
+		for _, ps := range projectStates {
+			result.Projects = append(result.Projects, projectStatus{
+				ProjectKey:          ps.ProjectKey,
+				LastFullSync:        optionalTime(ps.LastFullSync),
+				LastIncrementalSync: optionalTime(ps.LastIncrementalSync),
+				TicketCount:         ps.TicketCount,
+			})
+		}
+
+		if result.Warnings, err = guardrailWarnings(cfg, projectStates, time.Now()); err != nil {
+			return err
+		}
+
+		if cfg.API.Enabled {
+			running := probeDaemon(ctx, cfg)
+			result.DaemonRunning = &running
+		}
+
+		if err := render(cmd, result); err != nil {
+			return err
+		}
+		switch {
+		case result.AuthFailed != nil:
+			return withExitCode(exitAuthFailure, errors.New("Jira is rejecting the configured credentials"))
+		case result.Conflicts > 0:
+			return conflictsFound(result.Conflicts)
+		default:
+			return nil
+		}
+	})
+}
+
+// probeDaemon checks whether the daemon's control API answers its health endpoint.
+func probeDaemon(ctx context.Context, cfg *domain.Config) bool {
+	ctx, cancel := context.WithTimeout(ctx, daemonProbeTimeout)
+	defer cancel()
+
+	client, baseURL, err := controlAPIClient(cfg)
+	if err != nil {
+		return false
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/v1/health", nil)
+	if err != nil {
+		return false
+	}
+	if cfg.API.RequiresToken() {
+		token, err := keyring.APIToken(cfg.Storage.DBPath)
+		if err != nil {
+			return false
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
+// controlAPIClient returns an HTTP client and base URL for talking to the daemon's control API,
+// dialing the unix socket when one is configured and speaking TLS when the API does.
+func controlAPIClient(cfg *domain.Config) (*http.Client, string, error) {
+	tlsConfig, err := httpapi.ClientTLSConfig(cfg.API.TLS)
+	if err != nil {
+		return nil, "", err
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+
+	transport := &http.Transport{TLSClientConfig: tlsConfig}
+	if cfg.API.SocketPath == "" {
+		return &http.Client{Transport: transport}, scheme + "://" + cfg.API.Address, nil
+	}
+
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", cfg.API.SocketPath)
+	}
+	if tlsConfig != nil {
+		// The socket has no host name for the certificate to match
+		tlsConfig.ServerName = "localhost"
+	}
+	return &http.Client{Transport: transport}, scheme + "://jiramd", nil
+}
+
+// formatTimePtr renders an optional timestamp for text output.
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return formatTime(*t)
+}
@@ -1,9 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	appsync "github.com/esfisher/jiramd/internal/application/sync"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/keyring"
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	syncFull    bool
+	syncProject string
+	syncCI      bool
 )
 
 // syncCmd represents the sync command
@@ -16,15 +32,230 @@ and Jira tickets.
 This is useful for:
   - Initial setup and data population
   - Forcing a sync without running the daemon
-  - Testing synchronization logic`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// TODO: Implement sync command
-		fmt.Println("sync command not yet implemented")
-	},
+  - Testing synchronization logic
+
+Changes queued by the ticket commands are pushed to Jira first, unless
+sync.mode is pull_only; changes that fail to push are reported as warnings
+and retried by later syncs.
+
+Exits with status 4 when some tickets failed to sync, and 2 when some have
+sync conflicts.
+
+With --ci, sync runs unattended, e.g. in a scheduled job mirroring Jira
+into a docs repository: the report is printed to stdout as JSON, nothing
+prompts or draws progress (with storage.encrypt, the key must be in
+$` + keyring.KeyEnvVar + `), and tickets with unresolved conflicts stop the
+sync before it starts, exiting with status 2.`,
+	Example: `  jiramd sync
+  jiramd sync --full --project OPS
+  jiramd sync --ci`,
+	RunE: runSync,
 }
 
 func init() {
 	// Add flags specific to sync command
 	// syncCmd.Flags().StringP("direction", "d", "both", "Sync direction: 'to-jira', 'from-jira', or 'both'")
-	// syncCmd.Flags().StringP("project", "p", "", "Limit sync to specific project key")
+	syncCmd.Flags().StringVarP(&syncProject, "project", "p", "", "Limit sync to specific project key (default jira.project)")
+	syncCmd.Flags().BoolVar(&syncFull, "full", false, "Re-pull every ticket instead of only recently updated ones")
+	syncCmd.Flags().BoolVar(&syncCI, "ci", false, "Run unattended: JSON report, no prompts, and fail fast on conflicts")
+	syncCmd.RegisterFlagCompletionFunc("project", completeProjectKeys)
+}
+
+// syncTicketResult is the outcome of syncing a single ticket.
+type syncTicketResult struct {
+	TicketKey  string   `json:"ticket_key"`
+	Success    bool     `json:"success"`
+	Error      string   `json:"error,omitempty"`
+	Conflict   bool     `json:"conflict"`
+	Operations []string `json:"operations"`
+}
+
+// syncResult is the structured output of the sync command.
+type syncResult struct {
+	ProjectKey string             `json:"project_key"`
+	Full       bool               `json:"full"`
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt *time.Time         `json:"finished_at"`
+	Succeeded  int                `json:"succeeded"`
+	Failed     int                `json:"failed"`
+	Conflicts  int                `json:"conflicts"`
+	Error      string             `json:"error,omitempty"`
+	Warnings   []string           `json:"warnings"`
+	Tickets    []syncTicketResult `json:"tickets"`
+}
+
+// newSyncResult converts a domain sync report into the command's output type.
+func newSyncResult(report *domain.SyncReport) syncResult {
+	result := syncResult{
+		ProjectKey: report.ProjectKey,
+		Full:       report.Full,
+		StartedAt:  report.StartedAt.Time(),
+		FinishedAt: optionalTime(report.FinishedAt.Time()),
+		Succeeded:  report.Succeeded(),
+		Failed:     report.Failed(),
+		Conflicts:  report.Conflicts(),
+		Error:      report.Error,
+		Warnings:   append([]string{}, report.Warnings...),
+		Tickets:    make([]syncTicketResult, 0, len(report.Results)),
+	}
+	for _, r := range report.Results {
+		result.Tickets = append(result.Tickets, syncTicketResult{
+			TicketKey:  r.TicketKey.String(),
+			Success:    r.Success,
+			Error:      r.Error,
+			Conflict:   r.ConflictDetected,
+			Operations: r.OperationsPerformed,
+		})
+	}
+	return result
+}
+
+func (r syncResult) renderText(w io.Writer) {
+	kind := "Incremental"
+	if r.Full {
+		kind = "Full"
+	}
+	fmt.Fprintf(w, "%s sync of %s: %d succeeded, %d failed, %d conflicts\n",
+		kind, r.ProjectKey, r.Succeeded, r.Failed, r.Conflicts)
+
+	for _, t := range r.Tickets {
+		switch {
+		case t.Conflict:
+			fmt.Fprintf(w, "  ! %s conflict\n", t.TicketKey)
+		case !t.Success:
+			fmt.Fprintf(w, "  x %s %s\n", t.TicketKey, t.Error)
+		}
+	}
+
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+	if r.Error != "" {
+		fmt.Fprintf(w, "Error: %s\n", r.Error)
+	}
+}
+
+// runSync performs a one-time sync and renders the resulting report.
+func runSync(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if syncCI {
+		outputFormat = outputJSON
+		nonInteractive = true
+	}
+
+	return withState(ctx, func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		projectKey := syncProject
+		if projectKey == "" {
+			projectKey = cfg.Jira.Project
+		}
+
+		if syncCI {
+			report, err := unresolvedConflicts(ctx, stateRepo, projectKey)
+			if err != nil {
+				return err
+			}
+			if report.Conflicts() > 0 {
+				if err := render(cmd, newSyncResult(report)); err != nil {
+					return err
+				}
+				return conflictsFound(report.Conflicts())
+			}
+		}
+
+		logger := cliLogger()
+		monitor, err := openAuthMonitor(ctx, cfg, db, logger)
+		if err != nil {
+			return err
+		}
+		client, err := newJiraClient(cfg, monitor, logger)
+		if err != nil {
+			return err
+		}
+
+		// The tickets of the project synced are pulled as those of the synced project
+		pullCfg := *cfg
+		pullCfg.Jira.Project = projectKey
+		puller, err := newPullService(&pullCfg, db, stateRepo, client)
+		if err != nil {
+			return err
+		}
+		historyRepo := sqlite.NewSyncHistoryRepository(db.DB(), logger)
+		syncService := appsync.NewService(sqlite.NewTicketRepository(db.DB(), logger).WithCipher(db.Cipher()), nil, nil, stateRepo, historyRepo, sqlite.NewLockManager(db.DB(), logger)).
+			WithProgress(cliProgress()).
+			WithFieldDirections(cfg.Sync.FieldDirectionsFor).
+			WithPolicy(cfg.Sync.Policy()).
+			WithFilter(cfg.Sync.Filter, cfg.Jira.Email).
+			WithGuardrails(cfg.Sync.Guardrails).
+			WithTickets(client, puller).
+			WithBacklinks(markdown.NewBacklinkWriter(cfg.Sync.MarkdownDir, cfg.Sync.Sprint.ArchiveDir)).
+			WithIndexes(markdown.NewIndexWriter(cfg.Sync.MarkdownDir, cfg.Sync.Sprint.ArchiveDir), cfg.Sync.Indexes).
+			WithPusher(newPushService(cfg, db, stateRepo, client, monitor, logger))
+		if cfg.Sync.Sprint.Enabled() || len(cfg.Sync.Indexes.Filters) > 0 || len(cfg.Watchlist) > 0 {
+			syncService.WithSavedFilters(client)
+			if cfg.Sync.Sprint.Enabled() {
+				syncService.WithSprintScope(cfg.Sync.Sprint, client).
+					WithArchiver(markdown.NewArchiver(cfg.Sync.MarkdownDir, cfg.Sync.Sprint.ArchiveDir))
+			}
+			if len(cfg.Watchlist) > 0 {
+				syncService.WithWatchlist(cfg.Watchlist, puller)
+			}
+		}
+
+		var syncErr error
+		if syncFull {
+			syncErr = syncService.FullSyncProject(ctx, projectKey)
+		} else {
+			syncErr = syncService.SyncProject(ctx, projectKey)
+		}
+
+		report := syncService.LastReport()
+		if report != nil {
+			if err := render(cmd, newSyncResult(report)); err != nil {
+				return err
+			}
+		}
+		if syncErr != nil || report == nil {
+			return syncErr
+		}
+		return syncOutcome(report)
+	})
+}
+
+// unresolvedConflicts returns a report of the tickets of a project with sync conflicts
+// that have not been resolved, which CI mode refuses to sync over. The report is empty
+// when there are none.
+func unresolvedConflicts(ctx context.Context, stateRepo repository.StateRepository, projectKey string) (*domain.SyncReport, error) {
+	states, err := stateRepo.GetConflictedTickets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := domain.NewSyncReport(projectKey, syncFull)
+	for _, state := range states {
+		key, err := domain.NewTicketKey(state.TicketKey)
+		if err != nil || key.ProjectKey() != report.ProjectKey {
+			continue
+		}
+		result := domain.NewSyncResult(key)
+		result.MarkConflict()
+		result.MarkFailed(fmt.Errorf("%w: resolve the conflict before syncing", domain.ErrSyncConflict))
+		report.AddResult(result)
+	}
+	if n := report.Conflicts(); n > 0 {
+		report.Finish(fmt.Errorf("%d tickets have unresolved sync conflicts; run \"jiramd conflicts\" to list them", n))
+	}
+	return report, nil
+}
+
+// syncOutcome returns the error making a sync that ran to the end exit non-zero when
+// some of its tickets failed or conflicted, or nil when all of them synced.
+func syncOutcome(report *domain.SyncReport) error {
+	switch {
+	case report.Failed() > 0:
+		return withExitCode(exitPartialFailure, fmt.Errorf("%d of %d tickets failed to sync", report.Failed(), len(report.Results)))
+	case report.Conflicts() > 0:
+		return conflictsFound(report.Conflicts())
+	default:
+		return nil
+	}
 }
@@ -0,0 +1,632 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/events"
+	"github.com/esfisher/jiramd/internal/application/metadata"
+	"github.com/esfisher/jiramd/internal/application/ticket"
+	"github.com/esfisher/jiramd/internal/application/user"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/markdown"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+var (
+	ticketCreateProject     string
+	ticketCreateSummary     string
+	ticketCreateType        string
+	ticketCreateDescription string
+	ticketCreatePriority    string
+	ticketCreateAssignee    string
+	ticketCreateLabels      []string
+
+	ticketCommentVisibility string
+
+	ticketRetypeFields []string
+
+	ticketLabelAdd    []string
+	ticketLabelRemove []string
+)
+
+// ticketCmd represents the ticket command
+var ticketCmd = &cobra.Command{
+	Use:   "ticket",
+	Short: "View and change tickets",
+	Long: `View and change tickets without opening Jira.
+
+Tickets are read from the local cache. Changes are applied to the cache
+immediately and queued; the next sync, run by "jiramd sync" or the daemon,
+pushes them to Jira before pulling. Changes that fail to push stay queued and
+are retried; "jiramd ticket view" lists a ticket's queued changes with their
+errors.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// ticketViewCmd shows a cached ticket
+var ticketViewCmd = &cobra.Command{
+	Use:               "view KEY",
+	Short:             "Show a ticket from the local cache",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArgTicketKey,
+	RunE:              runTicketView,
+}
+
+// ticketCreateCmd queues a new ticket
+var ticketCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Queue a new ticket for creation",
+	Long: `Queue a new ticket for creation in Jira.
+
+The ticket is created by the next sync, which is also when Jira assigns its key.
+The issue type and priority are checked against the project's cached metadata
+(see refresh-metadata) before the ticket is queued.`,
+	Args: cobra.NoArgs,
+	RunE: runTicketCreate,
+}
+
+// ticketTransitionCmd changes a ticket's status
+var ticketTransitionCmd = &cobra.Command{
+	Use:   "transition KEY STATUS",
+	Short: "Move a ticket to another status",
+	Long: `Move a ticket to another status; the next sync pushes the change to Jira.
+
+The status is checked against the project's cached metadata (see
+refresh-metadata): it must exist, and be reachable from the ticket's current
+status where its transitions are known.`,
+	Example:           `  jiramd ticket transition JMD-42 "In Review"`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeFirstArgTicketKey,
+	RunE:              runTicketTransition,
+}
+
+// ticketRetypeCmd changes a ticket's issue type
+var ticketRetypeCmd = &cobra.Command{
+	Use:   "retype KEY TYPE",
+	Short: "Change the issue type of a ticket",
+	Long: `Change a ticket's issue type, e.g. from Story to Task; the next sync pushes
+the change to Jira.
+
+The type is checked against the project's cached metadata (see
+refresh-metadata) and, when Jira can be reached, against the fields the new
+type requires. Required fields the ticket has no value for are asked for when
+run in a terminal, or can be given with --field ID=VALUE.
+
+A type edited in a ticket's frontmatter is pushed the same way, except that
+nothing can be asked for: when the new type requires more fields, the push
+fails naming them, and this command sets them.
+
+Jira's API only changes a type to one of the same level, so a sub-task cannot
+become a standard issue type or the other way round; use Move in Jira instead.`,
+	Example: `  jiramd ticket retype JMD-42 Task
+  jiramd ticket retype JMD-42 Story --field customfield_10016=5`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeFirstArgTicketKey,
+	RunE:              runTicketRetype,
+}
+
+// ticketAssignCmd changes a ticket's assignee
+var ticketAssignCmd = &cobra.Command{
+	Use:   "assign KEY USER",
+	Short: "Assign a ticket to a user",
+	Long: `Assign a ticket to a user; the next sync pushes the change to Jira.
+
+USER is an email, display name, or the start of either. It must name exactly
+one user who can be assigned tickets in the project; otherwise the command
+fails and suggests close matches. Without Jira access, users are looked up in
+the local user cache instead.`,
+	Example: `  jiramd ticket assign JMD-42 alice@example.com
+  jiramd ticket assign JMD-42 "Alice Smith"`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTicketAssignArgs,
+	RunE:              runTicketAssign,
+}
+
+// ticketLabelCmd adds and removes labels
+var ticketLabelCmd = &cobra.Command{
+	Use:   "label KEY",
+	Short: "Add or remove labels of a ticket",
+	Long: `Add labels to a ticket and remove others; the next sync pushes the change to
+Jira.
+
+Only the labels given are added or removed, so labels others added to or
+removed from the ticket in Jira since the last sync are kept. A label given to
+both --add and --remove is added.`,
+	Example: `  jiramd ticket label JMD-42 --add backend --add urgent
+  jiramd ticket label JMD-42 --add needs-review --remove in-progress`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFirstArgTicketKey,
+	RunE:              runTicketLabel,
+}
+
+// ticketCommentCmd stages a comment
+var ticketCommentCmd = &cobra.Command{
+	Use:   "comment KEY BODY",
+	Short: "Queue a comment on a ticket",
+	Long: `Queue a comment on a ticket; the next sync posts it to Jira.
+
+--visibility restricts who can see the comment: role:NAME for a project role,
+group:NAME for a group, or internal for a Jira Service Management internal
+note that portal customers never see (combine as internal,role:NAME).
+
+Comments are posted as the owner of jira.token. When jira.comments.author is
+set, they are attributed to that person: with jira.comments.footer appended,
+or posted with their jira.comments.impersonation_tokens entry on Jira Server
+and Data Center.`,
+	Example: `  jiramd ticket comment JMD-42 "Deployed to staging"
+  jiramd ticket comment JMD-42 "Root cause is the cache key" --visibility role:Developers
+  jiramd ticket comment SD-7 "Customer is on the legacy plan" --visibility internal`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeFirstArgTicketKey,
+	RunE:              runTicketComment,
+}
+
+func init() {
+	ticketCmd.AddCommand(ticketViewCmd)
+	ticketCmd.AddCommand(ticketCreateCmd)
+	ticketCmd.AddCommand(ticketTransitionCmd)
+	ticketCmd.AddCommand(ticketRetypeCmd)
+	ticketCmd.AddCommand(ticketAssignCmd)
+	ticketCmd.AddCommand(ticketLabelCmd)
+	ticketCmd.AddCommand(ticketCommentCmd)
+
+	ticketCreateCmd.Flags().StringVarP(&ticketCreateProject, "project", "p", "", "Project key (default jira.project)")
+	ticketCreateCmd.Flags().StringVarP(&ticketCreateSummary, "summary", "s", "", "Ticket summary (required)")
+	ticketCreateCmd.Flags().StringVarP(&ticketCreateType, "type", "t", ticket.DefaultIssueType, "Issue type")
+	ticketCreateCmd.Flags().StringVarP(&ticketCreateDescription, "description", "d", "", "Ticket description")
+	ticketCreateCmd.Flags().StringVar(&ticketCreatePriority, "priority", "", "Ticket priority")
+	ticketCreateCmd.Flags().StringVar(&ticketCreateAssignee, "assignee", "", "User to assign the ticket to")
+	ticketCreateCmd.Flags().StringSliceVarP(&ticketCreateLabels, "label", "l", nil, "Label to add (repeatable)")
+	ticketCreateCmd.MarkFlagRequired("summary")
+	ticketCreateCmd.RegisterFlagCompletionFunc("project", completeProjectKeys)
+	ticketCreateCmd.RegisterFlagCompletionFunc("assignee", completeUsers)
+
+	ticketRetypeCmd.Flags().StringArrayVar(&ticketRetypeFields, "field", nil,
+		"Value of a field the new type requires, as ID=VALUE (repeatable)")
+
+	ticketLabelCmd.Flags().StringSliceVarP(&ticketLabelAdd, "add", "a", nil, "Label to add (repeatable)")
+	ticketLabelCmd.Flags().StringSliceVarP(&ticketLabelRemove, "remove", "r", nil, "Label to remove (repeatable)")
+
+	ticketCommentCmd.Flags().StringVar(&ticketCommentVisibility, "visibility", "",
+		"Who can see the comment: role:NAME, group:NAME, or internal (default everyone)")
+}
+
+// completeFirstArgTicketKey completes the KEY argument of ticket subcommands.
+func completeFirstArgTicketKey(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeTicketKeys(cmd, args, toComplete)
+}
+
+// completeTicketAssignArgs completes the KEY and USER arguments of ticket assign.
+func completeTicketAssignArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeTicketKeys(cmd, args, toComplete)
+	case 1:
+		return completeUsers(cmd, args, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// pendingOperationEntry describes a change waiting to be pushed to Jira.
+type pendingOperationEntry struct {
+	ID        int64     `json:"id"`
+	TicketKey string    `json:"ticket_key,omitempty"`
+	Operation string    `json:"operation"`
+	Payload   string    `json:"payload"`
+	QueuedAt  time.Time `json:"queued_at"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	Failed    bool      `json:"failed,omitempty"`
+
+	// Author is who made the change, in a shared vault
+	Author string `json:"author,omitempty"`
+
+	// PushedAt is when the change was applied to Jira (nil while queued)
+	PushedAt *time.Time `json:"pushed_at,omitempty"`
+
+	// CorrelationID finds the change in the logs and in the Jira requests pushing it
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// newPendingOperationEntry converts a queued domain operation into the command output type.
+func newPendingOperationEntry(op *domain.PendingOperation) pendingOperationEntry {
+	return pendingOperationEntry{
+		ID:        op.ID,
+		TicketKey: op.TicketKey.String(),
+		Operation: string(op.Operation),
+		Payload:   op.Payload,
+		QueuedAt:  op.CreatedAt.Time(),
+		Attempts:  op.Attempts,
+		LastError: op.LastError,
+		Failed:    op.Failed,
+		Author:    op.Author,
+		PushedAt:  optionalTime(op.CompletedAt.Time()),
+
+		CorrelationID: op.CorrelationID.String(),
+	}
+}
+
+// byAuthor names the author of a change for text output, or "" when unknown.
+func byAuthor(author string) string {
+	if author == "" {
+		return ""
+	}
+	return " by " + author
+}
+
+// ticketViewResult is the structured output of the ticket view command.
+type ticketViewResult struct {
+	Key         string                  `json:"key"`
+	Summary     string                  `json:"summary"`
+	Status      string                  `json:"status"`
+	IssueType   string                  `json:"issue_type"`
+	Priority    string                  `json:"priority"`
+	Assignee    string                  `json:"assignee"`
+	Reporter    string                  `json:"reporter"`
+	Labels      []string                `json:"labels"`
+	Created     time.Time               `json:"created"`
+	Updated     time.Time               `json:"updated"`
+	Description string                  `json:"description"`
+	LastSynced  *time.Time              `json:"last_synced"`
+	Dirty       bool                    `json:"dirty"`
+	Conflict    bool                    `json:"conflict"`
+	Pending     []pendingOperationEntry `json:"pending"`
+	Pushed      []pendingOperationEntry `json:"pushed"`
+}
+
+func (r ticketViewResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "%s  %s\n\n", r.Key, r.Summary)
+	fmt.Fprintf(w, "  Status:    %s\n", r.Status)
+	fmt.Fprintf(w, "  Type:      %s\n", r.IssueType)
+	fmt.Fprintf(w, "  Priority:  %s\n", valueOrNone(r.Priority))
+	fmt.Fprintf(w, "  Assignee:  %s\n", valueOrNone(r.Assignee))
+	fmt.Fprintf(w, "  Reporter:  %s\n", valueOrNone(r.Reporter))
+	fmt.Fprintf(w, "  Labels:    %s\n", valueOrNone(strings.Join(r.Labels, ", ")))
+	fmt.Fprintf(w, "  Created:   %s\n", formatTime(r.Created))
+	fmt.Fprintf(w, "  Updated:   %s\n", formatTime(r.Updated))
+	fmt.Fprintf(w, "  Synced:    %s\n", formatTimePtr(r.LastSynced))
+
+	if r.Conflict {
+		fmt.Fprintln(w, "\n  ! Modified both locally and in Jira since the last sync")
+	}
+	if len(r.Pending) > 0 {
+		fmt.Fprintf(w, "\n  %d changes waiting to be pushed:\n", len(r.Pending))
+		for _, p := range r.Pending {
+			fmt.Fprintf(w, "    #%d %s %s%s\n", p.ID, p.Operation, p.Payload, byAuthor(p.Author))
+			switch {
+			case p.Failed:
+				fmt.Fprintf(w, "       failed permanently: %s\n", p.LastError)
+			case p.LastError != "":
+				fmt.Fprintf(w, "       failed %d times, will be retried: %s\n", p.Attempts, p.LastError)
+			}
+		}
+	}
+	if len(r.Pushed) > 0 {
+		fmt.Fprintln(w, "\n  Recently pushed:")
+		for _, p := range r.Pushed {
+			fmt.Fprintf(w, "    #%d %s %s, %s%s\n", p.ID, p.Operation, p.Payload, formatTimePtr(p.PushedAt), byAuthor(p.Author))
+		}
+	}
+
+	if r.Description != "" {
+		fmt.Fprintf(w, "\n%s\n", r.Description)
+	}
+}
+
+// queuedOperationResult is the structured output of commands that queue a change.
+type queuedOperationResult struct {
+	Message   string                `json:"message"`
+	Operation pendingOperationEntry `json:"operation"`
+
+	// PushDisabled is set when sync.mode is pull_only, so the change stays queued
+	PushDisabled bool `json:"push_disabled"`
+}
+
+func (r queuedOperationResult) renderText(w io.Writer) {
+	if r.PushDisabled {
+		fmt.Fprintf(w, "%s (queued as #%d; not pushed to Jira while sync.mode is pull_only)\n", r.Message, r.Operation.ID)
+		return
+	}
+	fmt.Fprintf(w, "%s (queued as #%d; pushed to Jira by the next jiramd sync or daemon run)\n", r.Message, r.Operation.ID)
+}
+
+// newQueuedOperationResult describes an operation queued under cfg.
+func newQueuedOperationResult(cfg *domain.Config, message string, op *domain.PendingOperation) queuedOperationResult {
+	return queuedOperationResult{
+		Message:      message,
+		Operation:    newPendingOperationEntry(op),
+		PushDisabled: !cfg.Sync.Mode.CanPush(),
+	}
+}
+
+// localChangeResult reports a change of a local_only field, which is never pushed.
+type localChangeResult struct {
+	Message string `json:"message"`
+	Field   string `json:"field"`
+}
+
+func (r localChangeResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "%s (kept locally; %s is not synced to Jira)\n", r.Message, r.Field)
+}
+
+// renderChange renders the outcome of a change of field, which queued op for the next
+// push unless the field is local_only (nil op).
+func renderChange(cmd *cobra.Command, cfg *domain.Config, message, field string, op *domain.PendingOperation) error {
+	if op == nil {
+		return render(cmd, localChangeResult{Message: message, Field: field})
+	}
+	return render(cmd, newQueuedOperationResult(cfg, message, op))
+}
+
+// withTicketService runs fn with a ticket service backed by the local state database.
+func withTicketService(cmd *cobra.Command, fn func(cfg *domain.Config, service *ticket.Service) error) error {
+	return withState(cmd.Context(), func(cfg *domain.Config, db *sqlite.Database, stateRepo repository.StateRepository) error {
+		logger := cliLogger()
+		bus := events.NewBus()
+		bus.Subscribe(events.Log(logger))
+		ticketRepo := sqlite.NewTicketRepository(db.DB(), logger).WithCipher(db.Cipher())
+		bus.Subscribe(newDigestService(cfg, db, logger).WithTickets(ticketRepo).HandleEvent)
+		service := ticket.NewService(
+			ticketRepo,
+			stateRepo,
+			sqlite.NewPendingOperationRepository(db.DB(), logger).WithCipher(db.Cipher()),
+			sqlite.NewLockManager(db.DB(), logger),
+		).WithFieldDirections(cfg.Sync.FieldDirectionsFor).WithStatuses(cfg.Sync.Statuses).
+			WithEvents(bus).
+			WithCurrentUser(cfg.Jira.Email).
+			WithAuthor(cfg.Jira.Comments.Author)
+		if sources := cfg.Sync.SharedVault.AuthorSources; len(sources) > 0 {
+			parser, err := newMarkdownParser(cfg)
+			if err != nil {
+				return err
+			}
+			files := markdown.NewTicketFiles(cfg.Sync.MarkdownDir, parser)
+			service.WithChangeAuthors(markdown.NewChangeAuthors(files, stateRepo, sources, logger))
+		}
+
+		// Edits are validated against Jira's metadata and user directory through their
+		// caches, refreshed when a client can be created and served as cached otherwise
+		var metadataSource metadata.Source
+		client, err := newMonitoredJiraClient(cmd.Context(), cfg, db)
+		if err == nil {
+			metadataSource = client
+			service.WithRequiredFields(client)
+			service.WithAssignees(user.NewService(client, sqlite.NewUserRepository(db.DB(), logger)).WithLogger(logger))
+		}
+		service.WithMetadata(metadata.NewService(metadataSource,
+			sqlite.NewProjectMetadataRepository(db.DB(), logger),
+			sqlite.NewTicketRepository(db.DB(), logger).WithCipher(db.Cipher()),
+		).WithTTL(cfg.Sync.MetadataTTL).WithLogger(logger))
+		return fn(cfg, service)
+	})
+}
+
+// runTicketView renders a ticket from the local cache.
+func runTicketView(cmd *cobra.Command, args []string) error {
+	return withTicketService(cmd, func(cfg *domain.Config, service *ticket.Service) error {
+		details, err := service.View(cmd.Context(), args[0])
+		if err != nil {
+			if domain.IsNotFoundError(err) {
+				return fmt.Errorf("%w (run jiramd sync to refresh the cache)", err)
+			}
+			return err
+		}
+
+		t := details.Ticket
+		result := ticketViewResult{
+			Key:         t.Key.String(),
+			Summary:     t.Summary,
+			Status:      t.Status,
+			IssueType:   t.IssueType,
+			Priority:    t.Priority,
+			Assignee:    t.Assignee,
+			Reporter:    t.Reporter,
+			Labels:      t.Labels,
+			Created:     t.Created,
+			Updated:     t.Updated,
+			Description: t.Description,
+			Pending:     make([]pendingOperationEntry, 0, len(details.Pending)),
+			Pushed:      make([]pendingOperationEntry, 0, len(details.Pushed)),
+		}
+		if details.State != nil {
+			result.LastSynced = optionalTime(details.State.LastSynced)
+			result.Dirty = details.State.IsDirty
+			result.Conflict = details.State.ConflictDetected
+		}
+		for _, op := range details.Pending {
+			result.Pending = append(result.Pending, newPendingOperationEntry(op))
+		}
+		for _, op := range details.Pushed {
+			result.Pushed = append(result.Pushed, newPendingOperationEntry(op))
+		}
+
+		return render(cmd, result)
+	})
+}
+
+// runTicketCreate queues a new ticket.
+func runTicketCreate(cmd *cobra.Command, args []string) error {
+	return withTicketService(cmd, func(cfg *domain.Config, service *ticket.Service) error {
+		projectKey := ticketCreateProject
+		if projectKey == "" {
+			projectKey = cfg.Jira.Project
+		}
+
+		op, err := service.Create(cmd.Context(), projectKey, ticket.CreatePayload{
+			Summary:     ticketCreateSummary,
+			IssueType:   ticketCreateType,
+			Description: ticketCreateDescription,
+			Priority:    ticketCreatePriority,
+			Assignee:    ticketCreateAssignee,
+			Labels:      ticketCreateLabels,
+		})
+		if err != nil {
+			return err
+		}
+
+		return render(cmd, newQueuedOperationResult(cfg,
+			fmt.Sprintf("New %s in %s: %s", ticketCreateType, projectKey, ticketCreateSummary), op))
+	})
+}
+
+// runTicketTransition moves a ticket to another status.
+func runTicketTransition(cmd *cobra.Command, args []string) error {
+	return withTicketService(cmd, func(cfg *domain.Config, service *ticket.Service) error {
+		op, err := service.Transition(cmd.Context(), args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		return renderChange(cmd, cfg, fmt.Sprintf("Moved %s to %s", args[0], args[1]), "status", op)
+	})
+}
+
+// runTicketRetype changes the issue type of a ticket, asking for the fields the new type
+// requires when run in a terminal.
+func runTicketRetype(cmd *cobra.Command, args []string) error {
+	fields := make(map[string]string, len(ticketRetypeFields))
+	for _, field := range ticketRetypeFields {
+		id, value, ok := strings.Cut(field, "=")
+		if !ok || strings.TrimSpace(id) == "" {
+			return fmt.Errorf("%w: --field %q is not ID=VALUE", domain.ErrInvalidInput, field)
+		}
+		fields[strings.TrimSpace(id)] = strings.TrimSpace(value)
+	}
+
+	return withTicketService(cmd, func(cfg *domain.Config, service *ticket.Service) error {
+		op, err := service.ChangeType(cmd.Context(), args[0], args[1], fields)
+		var missing *domain.MissingFieldsError
+		if errors.As(err, &missing) && canPrompt() {
+			if err := promptRequiredFields(cmd, missing, fields); err != nil {
+				return err
+			}
+			op, err = service.ChangeType(cmd.Context(), args[0], args[1], fields)
+		}
+		if errors.As(err, &missing) {
+			return fmt.Errorf("%w (set them with --field ID=VALUE)", err)
+		} else if err != nil {
+			return err
+		}
+
+		// The type as resolved against the project's metadata, e.g. "Bug" for "bug"
+		issueType := args[1]
+		var payload ticket.FieldPayload
+		if op != nil && json.Unmarshal([]byte(op.Payload), &payload) == nil {
+			issueType = payload.Value
+		} else if op == nil {
+			if details, err := service.View(cmd.Context(), args[0]); err == nil {
+				issueType = details.Ticket.IssueType
+			}
+		}
+		return renderChange(cmd, cfg, fmt.Sprintf("Changed %s to type %s", args[0], issueType), "issuetype", op)
+	})
+}
+
+// canPrompt reports whether the command may ask for input: text output from a run in
+// a terminal that is not unattended.
+func canPrompt() bool {
+	if outputFormat != outputText || nonInteractive {
+		return false
+	}
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// promptRequiredFields asks for the values of the fields missing names, adding them to
+// values by field ID.
+func promptRequiredFields(cmd *cobra.Command, missing *domain.MissingFieldsError, values map[string]string) error {
+	w := cmd.ErrOrStderr()
+	fmt.Fprintf(w, "A %s needs more fields set:\n", missing.IssueType)
+	reader := bufio.NewReader(cmd.InOrStdin())
+	for _, field := range missing.Fields {
+		fmt.Fprintf(w, "  %s: ", field)
+		line, err := reader.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+			return fmt.Errorf("failed to read %s: %w", field, err)
+		}
+		values[field.ID] = strings.TrimSpace(line)
+	}
+	return nil
+}
+
+// runTicketAssign assigns a ticket to a user.
+func runTicketAssign(cmd *cobra.Command, args []string) error {
+	return withTicketService(cmd, func(cfg *domain.Config, service *ticket.Service) error {
+		op, err := service.Assign(cmd.Context(), args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		assignee := args[1]
+		var payload ticket.FieldPayload
+		if op != nil && json.Unmarshal([]byte(op.Payload), &payload) == nil {
+			assignee = payload.Value
+		}
+		return renderChange(cmd, cfg, fmt.Sprintf("Assigned %s to %s", args[0], assignee), "assignee", op)
+	})
+}
+
+// runTicketLabel adds and removes labels of a ticket.
+func runTicketLabel(cmd *cobra.Command, args []string) error {
+	return withTicketService(cmd, func(cfg *domain.Config, service *ticket.Service) error {
+		op, err := service.Label(cmd.Context(), args[0], ticketLabelAdd, ticketLabelRemove)
+		if err != nil {
+			return err
+		}
+
+		var changes []string
+		add := domain.NormalizeLabels(ticketLabelAdd)
+		for _, label := range add {
+			changes = append(changes, "+"+label)
+		}
+		for _, label := range domain.NormalizeLabels(ticketLabelRemove) {
+			if !slices.Contains(add, label) {
+				changes = append(changes, "-"+label)
+			}
+		}
+		return renderChange(cmd, cfg, fmt.Sprintf("Labeled %s %s", args[0], strings.Join(changes, " ")), "labels", op)
+	})
+}
+
+// runTicketComment queues a comment on a ticket.
+func runTicketComment(cmd *cobra.Command, args []string) error {
+	return withTicketService(cmd, func(cfg *domain.Config, service *ticket.Service) error {
+		op, err := service.Comment(cmd.Context(), args[0], args[1], ticketCommentVisibility)
+		if err != nil {
+			if domain.IsNotFoundError(err) {
+				return fmt.Errorf("%w (run jiramd sync to refresh the cache)", err)
+			}
+			return err
+		}
+
+		message := "Commented on " + args[0]
+		if visibility, _ := domain.ParseCommentVisibility(ticketCommentVisibility); !visibility.IsPublic() {
+			message += " (" + visibility.Label() + ")"
+		}
+		return render(cmd, newQueuedOperationResult(cfg, message, op))
+	})
+}
+
+// valueOrNone renders empty values as "none" in text output.
+func valueOrNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/esfisher/jiramd/internal/application/ticket"
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+var (
+	transitionFilter string
+	transitionTo     string
+	transitionDryRun bool
+)
+
+// transitionCmd moves every ticket matching a filter to a status
+var transitionCmd = &cobra.Command{
+	Use:   "transition --filter JQL --to STATUS",
+	Short: "Move every ticket matching a filter to another status",
+	Long: `Move every cached ticket matching --filter to the status --to; the next
+sync pushes the changes to Jira.
+
+The filter is JQL evaluated against the local cache, as with query --local,
+so run jiramd sync first for up-to-date statuses. Each ticket is moved as
+with ticket transition: the status is checked against the project's cached
+metadata and must be reachable from the ticket's current status where its
+transitions are known. A ticket that cannot be moved is reported and does not
+stop the others; tickets already in the status are skipped.
+
+Use --dry-run to list the matching tickets and check that each can be moved,
+without changing anything. Exits with status 4 when some tickets could not be
+moved, or would not be in a dry run.`,
+	Example: `  jiramd transition --filter 'status = "In Review" AND label = approved' --to Done
+  jiramd transition --filter 'status = Done AND updated <= -30d' --to Closed --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runTransition,
+}
+
+func init() {
+	transitionCmd.Flags().StringVarP(&transitionFilter, "filter", "f", "", "JQL selecting the cached tickets to move (required)")
+	transitionCmd.Flags().StringVar(&transitionTo, "to", "", "Status to move the tickets to (required)")
+	transitionCmd.Flags().BoolVar(&transitionDryRun, "dry-run", false, "List the matching tickets without moving them")
+	transitionCmd.MarkFlagRequired("filter")
+	transitionCmd.MarkFlagRequired("to")
+}
+
+// transitionEntry is the outcome for one ticket.
+type transitionEntry struct {
+	Key       string                 `json:"key"`
+	Summary   string                 `json:"summary"`
+	From      string                 `json:"from"`
+	Status    string                 `json:"status"` // queued, kept_locally, would_move, skipped, or failed
+	Operation *pendingOperationEntry `json:"operation,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// transitionResult is the structured output of the transition command.
+type transitionResult struct {
+	Filter  string            `json:"filter"`
+	To      string            `json:"to"`
+	DryRun  bool              `json:"dry_run"`
+	Moved   int               `json:"moved"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Tickets []transitionEntry `json:"tickets"`
+
+	// PushDisabled is set when sync.mode is pull_only, so the changes stay queued
+	PushDisabled bool `json:"push_disabled"`
+}
+
+func (r transitionResult) renderText(w io.Writer) {
+	if len(r.Tickets) == 0 {
+		fmt.Fprintln(w, "No matching tickets.")
+		return
+	}
+
+	for _, t := range r.Tickets {
+		switch t.Status {
+		case "queued":
+			fmt.Fprintf(w, "  %-12s %s -> %s  (queued as #%d)  %s\n", t.Key, t.From, r.To, t.Operation.ID, t.Summary)
+		case "kept_locally":
+			fmt.Fprintf(w, "  %-12s %s -> %s  (kept locally)  %s\n", t.Key, t.From, r.To, t.Summary)
+		case "would_move":
+			fmt.Fprintf(w, "  %-12s %s -> %s  %s\n", t.Key, t.From, r.To, t.Summary)
+		case "skipped":
+			fmt.Fprintf(w, "  %-12s already %s  %s\n", t.Key, t.From, t.Summary)
+		default:
+			fmt.Fprintf(w, "  %-12s FAILED: %s\n", t.Key, t.Error)
+		}
+	}
+
+	fmt.Fprintln(w)
+	switch {
+	case r.DryRun:
+		fmt.Fprintf(w, "Dry run: %d tickets would be moved to %s, %d already there, %d could not be moved.\n",
+			r.Moved, r.To, r.Skipped, r.Failed)
+	case r.PushDisabled:
+		fmt.Fprintf(w, "Moved %d tickets to %s, %d skipped, %d failed (not pushed to Jira while sync.mode is pull_only).\n",
+			r.Moved, r.To, r.Skipped, r.Failed)
+	default:
+		fmt.Fprintf(w, "Moved %d tickets to %s, %d skipped, %d failed (queued; pushed to Jira by the next jiramd sync or daemon run).\n",
+			r.Moved, r.To, r.Skipped, r.Failed)
+	}
+}
+
+// runTransition moves the tickets matching the filter and reports each of them.
+func runTransition(cmd *cobra.Command, args []string) error {
+	return withTicketService(cmd, func(cfg *domain.Config, service *ticket.Service) error {
+		report, err := service.TransitionMatching(cmd.Context(), transitionFilter, transitionTo, transitionDryRun)
+		if err != nil {
+			return err
+		}
+
+		result := newTransitionResult(transitionFilter, report, !cfg.Sync.Mode.CanPush())
+		if err := render(cmd, result); err != nil {
+			return err
+		}
+		return result.exitError()
+	})
+}
+
+// newTransitionResult describes the outcome of moving the tickets matching filter.
+func newTransitionResult(filter string, report *ticket.TransitionReport, pushDisabled bool) transitionResult {
+	result := transitionResult{
+		Filter:       filter,
+		To:           report.Status,
+		DryRun:       report.DryRun,
+		Moved:        report.Moved(),
+		Skipped:      report.Skipped(),
+		Failed:       report.Failed(),
+		Tickets:      make([]transitionEntry, 0, len(report.Results)),
+		PushDisabled: pushDisabled,
+	}
+	for _, r := range report.Results {
+		entry := transitionEntry{Key: r.Key.String(), Summary: r.Summary, From: r.From}
+		switch {
+		case r.Err != nil:
+			entry.Status = "failed"
+			entry.Error = r.Err.Error()
+		case r.Skipped:
+			entry.Status = "skipped"
+		case report.DryRun:
+			entry.Status = "would_move"
+		case r.Operation == nil:
+			entry.Status = "kept_locally"
+		default:
+			entry.Status = "queued"
+			op := newPendingOperationEntry(r.Operation)
+			entry.Operation = &op
+		}
+		result.Tickets = append(result.Tickets, entry)
+	}
+	return result
+}
+
+// exitError returns the error the command exits with: exitPartialFailure when some
+// tickets could not be moved, or nil.
+func (r transitionResult) exitError() error {
+	if r.Failed == 0 {
+		return nil
+	}
+	return withExitCode(exitPartialFailure, fmt.Errorf("%d of %d tickets could not be moved", r.Failed, len(r.Tickets)))
+}
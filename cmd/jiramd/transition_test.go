@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/application/ticket"
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestNewTransitionResult(t *testing.T) {
+	key := func(k string) domain.TicketKey {
+		ticketKey, err := domain.NewTicketKey(k)
+		if err != nil {
+			t.Fatalf("NewTicketKey(%s) failed: %v", k, err)
+		}
+		return ticketKey
+	}
+	op, err := domain.NewPendingOperation("JMD", key("JMD-1"), domain.OpPushStatus, `{"status":"Done"}`)
+	if err != nil {
+		t.Fatalf("NewPendingOperation failed: %v", err)
+	}
+	op.ID = 7
+
+	moved := ticket.TransitionResult{Key: key("JMD-1"), Summary: "Ship it", From: "In Review", Operation: op}
+	skipped := ticket.TransitionResult{Key: key("JMD-2"), Summary: "Shipped", From: "Done", Skipped: true}
+	failed := ticket.TransitionResult{Key: key("JMD-3"), Summary: "Blocked", From: "To Do",
+		Err: errors.New(`status "Done" is not reachable from "To Do"`)}
+
+	tests := []struct {
+		name         string
+		report       *ticket.TransitionReport
+		wantStatuses []string
+		wantText     []string
+		wantExit     int
+	}{
+		{
+			name:         "match and skip",
+			report:       &ticket.TransitionReport{Status: "Done", Results: []ticket.TransitionResult{moved, skipped}},
+			wantStatuses: []string{"queued", "skipped"},
+			wantText: []string{
+				"JMD-1        In Review -> Done  (queued as #7)  Ship it",
+				"JMD-2        already Done  Shipped",
+				"Moved 1 tickets to Done, 1 skipped, 0 failed (queued; pushed to Jira by the next jiramd sync or daemon run).",
+			},
+			wantExit: exitOK,
+		},
+		{
+			name:         "failure",
+			report:       &ticket.TransitionReport{Status: "Done", Results: []ticket.TransitionResult{moved, failed}},
+			wantStatuses: []string{"queued", "failed"},
+			wantText:     []string{`JMD-3        FAILED: status "Done" is not reachable from "To Do"`},
+			wantExit:     exitPartialFailure,
+		},
+		{
+			name: "dry run failure",
+			report: &ticket.TransitionReport{Status: "Done", DryRun: true, Results: []ticket.TransitionResult{
+				{Key: moved.Key, Summary: moved.Summary, From: moved.From}, skipped, failed,
+			}},
+			wantStatuses: []string{"would_move", "skipped", "failed"},
+			wantText:     []string{"Dry run: 1 tickets would be moved to Done, 1 already there, 1 could not be moved."},
+			wantExit:     exitPartialFailure,
+		},
+		{
+			name:     "no matches",
+			report:   &ticket.TransitionReport{Status: "Done"},
+			wantText: []string{"No matching tickets."},
+			wantExit: exitOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTransitionResult("project = JMD", tt.report, false)
+
+			var statuses []string
+			for _, entry := range result.Tickets {
+				statuses = append(statuses, entry.Status)
+			}
+			if strings.Join(statuses, ",") != strings.Join(tt.wantStatuses, ",") {
+				t.Errorf("statuses = %v, want %v", statuses, tt.wantStatuses)
+			}
+
+			var text strings.Builder
+			result.renderText(&text)
+			for _, want := range tt.wantText {
+				if !strings.Contains(text.String(), want) {
+					t.Errorf("text = %q, want it to contain %q", text.String(), want)
+				}
+			}
+
+			if got := exitCode(result.exitError()); got != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", got, tt.wantExit)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package auth contains the use case for noticing when Jira stops accepting the
+// configured credentials, e.g. because the API token expired, so work that needs Jira
+// pauses and the user is alerted instead of retries being used up silently.
+package auth
+
+import (
+	"context"
+	"log/slog"
+	gosync "sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Notifier alerts the user when the credentials fail and when they work again.
+type Notifier interface {
+	// AuthFailed is called once when the credentials are marked failed.
+	AuthFailed(ctx context.Context, status domain.AuthStatus) error
+
+	// AuthRecovered is called once when failed credentials work again.
+	AuthRecovered(ctx context.Context) error
+}
+
+// Monitor tracks the outcome of Jira requests and marks the credentials failed after
+// domain.AuthFailureThreshold authentication failures in a row. The status is stored
+// in repo so every process sharing it sees the failure.
+type Monitor struct {
+	repo     repository.AuthStatusRepository
+	notifier Notifier
+	logger   *slog.Logger
+	now      func() time.Time
+
+	// mu guards status, which is updated by concurrent requests
+	mu     gosync.Mutex
+	status domain.AuthStatus
+}
+
+// NewMonitor creates a new authentication monitor. notifier may be nil to only log
+// failures.
+func NewMonitor(repo repository.AuthStatusRepository, notifier Notifier, logger *slog.Logger) *Monitor {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Monitor{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Load reads the stored status, so a failure recorded by another process is known
+// before this one makes a request.
+func (m *Monitor) Load(ctx context.Context) error {
+	status, err := m.repo.GetAuthStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = status
+	return nil
+}
+
+// Observe records the outcome of a Jira request: nil for success, or the request's error.
+// Errors other than authentication failures are ignored.
+func (m *Monitor) Observe(ctx context.Context, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	previous := m.status
+	m.status.Record(err, m.now())
+	if m.status == previous {
+		return
+	}
+
+	if saveErr := m.repo.SaveAuthStatus(ctx, m.status); saveErr != nil {
+		m.logger.Warn("failed to save auth status", "error", saveErr)
+	}
+
+	switch {
+	case m.status.Failed() && !previous.Failed():
+		m.logger.Error("AUTH FAILED: Jira rejected the configured credentials; pushes are paused until they work again",
+			"consecutive_failures", m.status.ConsecutiveFailures,
+			"failing_since", m.status.FailingSince,
+			"error", m.status.LastError)
+		if m.notifier != nil {
+			if notifyErr := m.notifier.AuthFailed(ctx, m.status); notifyErr != nil {
+				m.logger.Warn("failed to send auth failure notification", "error", notifyErr)
+			}
+		}
+	case previous.Failed() && !m.status.Failed():
+		m.logger.Info("Jira accepts the configured credentials again; pushes resume")
+		if m.notifier != nil {
+			if notifyErr := m.notifier.AuthRecovered(ctx); notifyErr != nil {
+				m.logger.Warn("failed to send auth recovery notification", "error", notifyErr)
+			}
+		}
+	}
+}
+
+// Status returns the current authentication status.
+func (m *Monitor) Status() domain.AuthStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Paused reports whether work that needs Jira should wait because the credentials
+// failed (see domain.AuthStatus.Paused).
+func (m *Monitor) Paused() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status.Paused(m.now())
+}
@@ -0,0 +1,436 @@
+// Package contextpack contains use cases for packing cached tickets into a compact
+// markdown bundle to paste into an LLM prompt. Bundles are built from the local cache
+// only and never contact Jira.
+package contextpack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+const (
+	// DefaultComments is how many of a ticket's latest comments a bundle includes when
+	// Options.Comments is zero.
+	DefaultComments = 3
+
+	// DefaultDescriptionTokens caps a ticket's description when
+	// Options.DescriptionTokens is zero.
+	DefaultDescriptionTokens = 500
+
+	// charsPerToken is the rough number of characters per token of English text and
+	// markdown, used to estimate token counts without a model-specific tokenizer.
+	charsPerToken = 4
+
+	// minTextTokens is the least budget worth spending on a trimmed description or
+	// comment.
+	minTextTokens = 10
+
+	// ellipsis marks text cut to fit the budget.
+	ellipsis = "…"
+)
+
+// Options shape a bundle.
+type Options struct {
+	// MaxTokens caps the estimated size of the bundle (zero for no cap). Descriptions and
+	// comments are trimmed to fit it, and tickets that do not fit even without them are
+	// left out.
+	MaxTokens int
+
+	// Comments is how many of each ticket's latest comments are included (zero for
+	// DefaultComments, negative for none)
+	Comments int
+
+	// DescriptionTokens caps each ticket's description (zero for
+	// DefaultDescriptionTokens)
+	DescriptionTokens int
+}
+
+// Bundle is a set of tickets packed for an LLM prompt.
+type Bundle struct {
+	// Text is the bundle as markdown
+	Text string
+
+	// Tickets are the keys of the tickets in the bundle, in the order asked for
+	Tickets []string
+
+	// Omitted are the keys of the tickets left out because they did not fit MaxTokens
+	Omitted []string
+
+	// Tokens is the estimated size of Text in tokens
+	Tokens int
+}
+
+// Service handles context bundle use cases against the local ticket cache.
+//
+// Error contract: Methods return domain.ErrInvalidInput for malformed keys and options,
+// domain.ErrNotFound when a ticket is not cached, and wrapped errors for storage
+// failures.
+type Service struct {
+	ticketRepo  repository.TicketRepository
+	commentRepo repository.CommentRepository
+	now         func() time.Time
+}
+
+// NewService creates a new context bundle service.
+func NewService(ticketRepo repository.TicketRepository, commentRepo repository.CommentRepository) *Service {
+	return &Service{
+		ticketRepo:  ticketRepo,
+		commentRepo: commentRepo,
+		now:         time.Now,
+	}
+}
+
+// entry is a ticket being packed: its header, always included, and the description and
+// latest comments trimmed to its share of the budget.
+type entry struct {
+	key      string
+	header   string
+	comments []comment
+
+	// description is the compacted description, or a pointer to where the same text
+	// first appears in the bundle
+	description string
+
+	// descriptionRef reports whether description is such a pointer
+	descriptionRef bool
+
+	// total is the number of comments on the ticket, of which comments are the latest
+	total int
+}
+
+// comment is one of the latest comments of a ticket being packed.
+type comment struct {
+	// prefix names the author and date
+	prefix string
+
+	// text is the compacted body, or a pointer to where the same text first appears
+	text string
+}
+
+// Pack bundles the cached tickets with keys, in order and each once, as markdown: a
+// header with the summary and main fields, then the description and latest comments.
+// Text repeated across tickets, such as a description copied into a clone, is only
+// included the first time.
+//
+// With a token cap, headers are fitted first, and the tickets whose header does not fit
+// are left out. What remains is shared between the tickets, smallest first, so the
+// share a small ticket does not use goes to the larger ones.
+func (s *Service) Pack(ctx context.Context, keys []string, opts Options) (*Bundle, error) {
+	if opts.MaxTokens < 0 {
+		return nil, fmt.Errorf("%w: max tokens cannot be negative", domain.ErrInvalidInput)
+	}
+	if opts.Comments == 0 {
+		opts.Comments = DefaultComments
+	}
+	if opts.DescriptionTokens <= 0 {
+		opts.DescriptionTokens = DefaultDescriptionTokens
+	}
+
+	entries, err := s.load(ctx, keys, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no ticket keys given", domain.ErrInvalidInput)
+	}
+
+	title := fmt.Sprintf("# Jira tickets (snapshot of %s)\n", s.now().UTC().Format("2006-01-02 15:04 MST"))
+	bundle := &Bundle{Tickets: []string{}, Omitted: []string{}}
+	budget := opts.MaxTokens - estimateTokens(title)
+
+	included, footer := entries, ""
+	if opts.MaxTokens > 0 {
+		included, bundle.Omitted, footer, budget = fitHeaders(entries, budget)
+	}
+	for _, e := range included {
+		bundle.Tickets = append(bundle.Tickets, e.key)
+	}
+	dedupe(included)
+
+	bodies := make(map[*entry]string, len(included))
+	bySize := slices.Clone(included)
+	if opts.MaxTokens > 0 {
+		sort.SliceStable(bySize, func(i, j int) bool {
+			return bySize[i].size(opts.DescriptionTokens) < bySize[j].size(opts.DescriptionTokens)
+		})
+	}
+	for i, e := range bySize {
+		share := -1
+		if opts.MaxTokens > 0 {
+			share = max(budget/(len(bySize)-i), 0)
+		}
+		bodies[e] = e.body(share, opts.DescriptionTokens)
+		budget -= estimateTokens(bodies[e])
+	}
+
+	var b strings.Builder
+	b.WriteString(title)
+	for _, e := range included {
+		b.WriteString("\n")
+		b.WriteString(e.header)
+		b.WriteString(bodies[e])
+	}
+	b.WriteString(footer)
+
+	bundle.Text = b.String()
+	bundle.Tokens = estimateTokens(bundle.Text)
+	return bundle, nil
+}
+
+// fitHeaders returns the entries whose headers fit in budget tokens, in order, along with
+// the keys of the others and a note naming them, and the budget left. The note is paid
+// for out of the budget too, which may leave out more tickets.
+func fitHeaders(entries []*entry, budget int) (included []*entry, omitted []string, footer string, left int) {
+	reserve := 0
+	for {
+		included, omitted, left = nil, []string{}, budget-reserve
+		for _, e := range entries {
+			if cost := estimateTokens(e.header); cost <= left {
+				left -= cost
+				included = append(included, e)
+			} else {
+				omitted = append(omitted, e.key)
+			}
+		}
+
+		footer = ""
+		if len(omitted) > 0 {
+			footer = fmt.Sprintf("\n(Left out to fit the token budget: %s.)\n", strings.Join(omitted, ", "))
+		}
+		// Leaving out more tickets only lengthens the note, so this ends once every
+		// ticket is left out at the latest
+		if estimateTokens(footer) <= reserve {
+			return included, omitted, footer, left
+		}
+		reserve = estimateTokens(footer)
+	}
+}
+
+// load reads the tickets with keys from the cache, skipping repeated keys, with their
+// latest comments.
+func (s *Service) load(ctx context.Context, keys []string, opts Options) ([]*entry, error) {
+	var entries []*entry
+	loaded := make(map[string]bool)
+	for _, raw := range keys {
+		key, err := domain.NewTicketKey(raw)
+		if err != nil {
+			return nil, err
+		}
+		if loaded[key.String()] {
+			continue
+		}
+		loaded[key.String()] = true
+
+		ticket, err := s.ticketRepo.FindByKey(ctx, key.String())
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s is not cached; run jiramd pull %s first", domain.ErrNotFound, key, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", key, err)
+		}
+
+		comments, err := s.commentRepo.FindByTicketKey(ctx, key.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read comments of %s: %w", key, err)
+		}
+		sort.SliceStable(comments, func(i, j int) bool {
+			return comments[i].Created.Before(comments[j].Created)
+		})
+
+		e := &entry{
+			key:         key.String(),
+			header:      header(ticket),
+			description: compact(ticket.Description),
+			total:       len(comments),
+		}
+		if opts.Comments > 0 {
+			for _, c := range comments[max(len(comments)-opts.Comments, 0):] {
+				e.comments = append(e.comments, comment{
+					prefix: fmt.Sprintf("- %s, %s: ", strings.TrimSpace(c.Author), formatDate(c.Created)),
+					text:   compact(c.Body),
+				})
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// dedupe replaces descriptions and comments whose text already appeared earlier in
+// entries with a pointer to where it did.
+func dedupe(entries []*entry) {
+	seen := make(map[string]string)
+	for _, e := range entries {
+		if e.description != "" {
+			if where, ok := seen[e.description]; ok {
+				e.description = "(Description same as " + where + ".)"
+				e.descriptionRef = true
+			} else {
+				seen[e.description] = e.key + "'s description"
+			}
+		}
+		for i, c := range e.comments {
+			if c.text == "" {
+				continue
+			}
+			if where, ok := seen[c.text]; ok {
+				e.comments[i].text = "(same as " + where + ")"
+			} else {
+				seen[c.text] = "a comment on " + e.key
+			}
+		}
+	}
+}
+
+// header returns the heading and main fields of a ticket, leaving out empty ones.
+func header(t *domain.Ticket) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s: %s\n", t.Key, strings.TrimSpace(t.Summary))
+
+	var fields []string
+	for _, field := range []struct{ name, value string }{
+		{"Status", t.Status},
+		{"Type", t.IssueType},
+		{"Priority", t.Priority},
+		{"Assignee", t.Assignee},
+		{"Labels", strings.Join(t.Labels, ", ")},
+		{"Updated", formatDate(t.Updated)},
+	} {
+		if value := strings.TrimSpace(field.value); value != "" {
+			fields = append(fields, field.name+": "+value)
+		}
+	}
+	if len(fields) > 0 {
+		b.WriteString(strings.Join(fields, " | "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// size returns the tokens the body of the ticket takes without a token cap.
+func (e *entry) size(descriptionTokens int) int {
+	return estimateTokens(e.body(-1, descriptionTokens))
+}
+
+// body returns the description and comments of the ticket in at most share tokens (no
+// limit when share is negative), the description capped at descriptionTokens. The
+// latest comments are fitted first, as they matter most, and listed in order.
+func (e *entry) body(share, descriptionTokens int) string {
+	left := share
+	spend := func(text string) {
+		if left >= 0 {
+			left = max(left-estimateTokens(text), 0)
+		}
+	}
+
+	var description string
+	if e.description != "" {
+		limit := descriptionTokens
+		if left >= 0 {
+			// Leave room for comments, which the description must not crowd out
+			if len(e.comments) > 0 {
+				limit = min(limit, left/2)
+			} else {
+				limit = min(limit, left)
+			}
+		}
+		switch {
+		case e.descriptionRef && (left < 0 || estimateTokens(e.description) <= left):
+			description = "\n" + e.description + "\n"
+		case !e.descriptionRef && limit >= minTextTokens:
+			description = "\n" + trim(e.description, limit) + "\n"
+		}
+		spend(description)
+	}
+	if len(e.comments) == 0 {
+		return description
+	}
+
+	// The heading is reserved before fitting the comments it introduces
+	heading := func(n int) string {
+		if n == e.total {
+			return "\nComments:\n"
+		}
+		return fmt.Sprintf("\nComments (latest %d of %d):\n", n, e.total)
+	}
+	spend(heading(len(e.comments)))
+
+	var lines []string
+	for i := len(e.comments) - 1; i >= 0; i-- {
+		c := e.comments[i]
+		line := c.prefix + c.text + "\n"
+		if left >= 0 && estimateTokens(line) > left {
+			room := left - estimateTokens(c.prefix)
+			if room < minTextTokens {
+				break
+			}
+			line = c.prefix + trim(c.text, room) + "\n"
+		}
+		lines = append(lines, line)
+		spend(line)
+	}
+	if len(lines) == 0 {
+		return description
+	}
+
+	slices.Reverse(lines)
+	return description + heading(len(lines)) + strings.Join(lines, "")
+}
+
+// estimateTokens returns the rough number of tokens text takes in a prompt.
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// trim returns text cut to about tokens tokens at a word boundary, marked with an
+// ellipsis when cut.
+func trim(text string, tokens int) string {
+	if estimateTokens(text) <= tokens {
+		return text
+	}
+	limit := max(tokens*charsPerToken-utf8.RuneCountInString(ellipsis), 0)
+	runes := []rune(text)
+	cut := string(runes[:min(limit, len(runes))])
+	if i := strings.LastIndexAny(cut, " \n"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + ellipsis
+}
+
+// compact returns text without trailing spaces, runs of blank lines, or surrounding
+// whitespace, which take tokens without adding anything.
+func compact(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	kept := lines[:0]
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// formatDate returns the date of t, or "" for the zero time.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,303 @@
+package contextpack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/domain/repository/fakes"
+)
+
+// commentStore is a read-only repository.CommentRepository of comments by ticket key.
+type commentStore map[string][]*domain.Comment
+
+func (s commentStore) Save(ctx context.Context, comment *domain.Comment) error {
+	return errors.New("comment store is read-only")
+}
+
+func (s commentStore) FindByTicketKey(ctx context.Context, ticketKey string) ([]*domain.Comment, error) {
+	return slices.Clone(s[ticketKey]), nil
+}
+
+func (s commentStore) FindByID(ctx context.Context, id string) (*domain.Comment, error) {
+	return nil, fmt.Errorf("%w: comment %s", domain.ErrNotFound, id)
+}
+
+func (s commentStore) Delete(ctx context.Context, id string) error {
+	return errors.New("comment store is read-only")
+}
+
+var _ repository.CommentRepository = commentStore(nil)
+
+// testTicket returns the cached ticket key with the given description.
+func testTicket(t *testing.T, key, description string) *domain.Ticket {
+	t.Helper()
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		t.Fatalf("NewTicketKey(%s) failed: %v", key, err)
+	}
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := domain.NewTicket(ticketKey, "Ticket "+key, updated, updated)
+	ticket.Status = "In Progress"
+	ticket.Description = description
+	return ticket
+}
+
+func TestService_Pack(t *testing.T) {
+	long := strings.Repeat("The export job retries each failed batch. ", 20)
+	created := time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)
+	tickets := fakes.NewTicketRepository(
+		testTicket(t, "JMD-1", long),
+		testTicket(t, "JMD-2", long),
+		testTicket(t, "JMD-3", "Short and separate."),
+	)
+	comments := commentStore{"JMD-1": {
+		{ID: "1", Author: "ana", Body: "Reproduced on staging.", Created: created},
+		{ID: "2", Author: "ben", Body: "Fixed in the retry loop.", Created: created.Add(time.Hour)},
+	}}
+	service := NewService(tickets, comments)
+	service.now = func() time.Time { return time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name        string
+		keys        []string
+		opts        Options
+		wantTickets []string
+		wantOmitted []string
+		wantText    []string
+		wantErr     error
+	}{
+		{
+			name:        "no cap",
+			keys:        []string{"JMD-1", "JMD-2", "JMD-1"},
+			wantTickets: []string{"JMD-1", "JMD-2"},
+			wantOmitted: []string{},
+			wantText: []string{
+				"# Jira tickets (snapshot of 2024-03-05 12:00 UTC)\n",
+				"## JMD-1: Ticket JMD-1\nStatus: In Progress | Updated: 2024-03-01\n",
+				"\nComments:\n- ana, 2024-03-02: Reproduced on staging.\n- ben, 2024-03-02: Fixed in the retry loop.\n",
+				"\n(Description same as JMD-1's description.)\n",
+			},
+		},
+		{
+			name:        "latest comments only",
+			keys:        []string{"JMD-1"},
+			opts:        Options{Comments: 1},
+			wantTickets: []string{"JMD-1"},
+			wantOmitted: []string{},
+			wantText:    []string{"\nComments (latest 1 of 2):\n- ben, 2024-03-02: Fixed in the retry loop.\n"},
+		},
+		{
+			name:        "descriptions trimmed to fit",
+			keys:        []string{"JMD-1", "JMD-3"},
+			opts:        Options{MaxTokens: 120},
+			wantTickets: []string{"JMD-1", "JMD-3"},
+			wantOmitted: []string{},
+			wantText:    []string{ellipsis, "Short and separate."},
+		},
+		{
+			name:        "budget overflow",
+			keys:        []string{"JMD-1", "JMD-2", "JMD-3"},
+			opts:        Options{MaxTokens: 45},
+			wantTickets: []string{"JMD-1"},
+			wantOmitted: []string{"JMD-2", "JMD-3"},
+			wantText:    []string{"\n(Left out to fit the token budget: JMD-2, JMD-3.)\n"},
+		},
+		{name: "negative cap", keys: []string{"JMD-1"}, opts: Options{MaxTokens: -1}, wantErr: domain.ErrInvalidInput},
+		{name: "no keys", wantErr: domain.ErrInvalidInput},
+		{name: "not cached", keys: []string{"JMD-1", "JMD-9"}, wantErr: domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle, err := service.Pack(context.Background(), tt.keys, tt.opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Pack() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Pack failed: %v", err)
+			}
+
+			if !slices.Equal(bundle.Tickets, tt.wantTickets) {
+				t.Errorf("Tickets = %v, want %v", bundle.Tickets, tt.wantTickets)
+			}
+			if !slices.Equal(bundle.Omitted, tt.wantOmitted) {
+				t.Errorf("Omitted = %v, want %v", bundle.Omitted, tt.wantOmitted)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(bundle.Text, want) {
+					t.Errorf("Text = %q, want it to contain %q", bundle.Text, want)
+				}
+			}
+			if got := strings.Count(bundle.Text, long); got > 1 {
+				t.Errorf("Text repeats the shared description %d times", got)
+			}
+			if bundle.Tokens != estimateTokens(bundle.Text) {
+				t.Errorf("Tokens = %d, want %d", bundle.Tokens, estimateTokens(bundle.Text))
+			}
+			if tt.opts.MaxTokens > 0 && bundle.Tokens > tt.opts.MaxTokens {
+				t.Errorf("Tokens = %d, over the cap of %d", bundle.Tokens, tt.opts.MaxTokens)
+			}
+		})
+	}
+}
+
+// headerEntry returns an entry of ticket key whose header takes tokens tokens.
+func headerEntry(key string, tokens int) *entry {
+	return &entry{key: key, header: strings.Repeat("h", tokens*charsPerToken)}
+}
+
+func TestFitHeaders(t *testing.T) {
+	tests := []struct {
+		name         string
+		sizes        []int
+		budget       int
+		wantIncluded []string
+		wantOmitted  []string
+		wantLeft     int
+	}{
+		{
+			name:         "all fit",
+			sizes:        []int{10, 10, 10},
+			budget:       30,
+			wantIncluded: []string{"JMD-1", "JMD-2", "JMD-3"},
+			wantOmitted:  []string{},
+			wantLeft:     0,
+		},
+		{
+			// JMD-3 does not fit, and the note naming it then crowds out JMD-2
+			name:         "footer leaves out more",
+			sizes:        []int{10, 10, 10},
+			budget:       29,
+			wantIncluded: []string{"JMD-1"},
+			wantOmitted:  []string{"JMD-2", "JMD-3"},
+			wantLeft:     6,
+		},
+		{
+			// Headers are fitted in the order asked for, not by size
+			name:         "later headers fill the gap",
+			sizes:        []int{30, 10, 10},
+			budget:       35,
+			wantIncluded: []string{"JMD-2", "JMD-3"},
+			wantOmitted:  []string{"JMD-1"},
+			wantLeft:     2,
+		},
+		{
+			name:        "nothing fits",
+			sizes:       []int{10, 10, 10},
+			budget:      5,
+			wantOmitted: []string{"JMD-1", "JMD-2", "JMD-3"},
+			wantLeft:    -10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []*entry
+			for i, size := range tt.sizes {
+				entries = append(entries, headerEntry(fmt.Sprintf("JMD-%d", i+1), size))
+			}
+
+			included, omitted, footer, left := fitHeaders(entries, tt.budget)
+			var keys []string
+			for _, e := range included {
+				keys = append(keys, e.key)
+			}
+			if !slices.Equal(keys, tt.wantIncluded) {
+				t.Errorf("included = %v, want %v", keys, tt.wantIncluded)
+			}
+			if !slices.Equal(omitted, tt.wantOmitted) {
+				t.Errorf("omitted = %v, want %v", omitted, tt.wantOmitted)
+			}
+			if left != tt.wantLeft {
+				t.Errorf("left = %d, want %d", left, tt.wantLeft)
+			}
+
+			wantFooter := ""
+			if len(tt.wantOmitted) > 0 {
+				wantFooter = "\n(Left out to fit the token budget: " + strings.Join(tt.wantOmitted, ", ") + ".)\n"
+			}
+			if footer != wantFooter {
+				t.Errorf("footer = %q, want %q", footer, wantFooter)
+			}
+		})
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	tests := []struct {
+		name             string
+		entries          []*entry
+		wantDescriptions []string
+		wantRefs         []bool
+		wantComments     [][]string
+	}{
+		{
+			name: "cloned description",
+			entries: []*entry{
+				{key: "JMD-1", description: "Same text"},
+				{key: "JMD-2", description: "Same text"},
+				{key: "JMD-3", description: "Same text"},
+			},
+			wantDescriptions: []string{"Same text", "(Description same as JMD-1's description.)", "(Description same as JMD-1's description.)"},
+			wantRefs:         []bool{false, true, true},
+			wantComments:     [][]string{nil, nil, nil},
+		},
+		{
+			name: "repeated comments",
+			entries: []*entry{
+				{key: "JMD-1", comments: []comment{{text: "Blocked on review"}}},
+				{key: "JMD-2", comments: []comment{{text: "Blocked on review"}, {text: "Merged"}}},
+			},
+			wantDescriptions: []string{"", ""},
+			wantRefs:         []bool{false, false},
+			wantComments:     [][]string{{"Blocked on review"}, {"(same as a comment on JMD-1)", "Merged"}},
+		},
+		{
+			name: "description quoted in a comment",
+			entries: []*entry{
+				{key: "JMD-1", comments: []comment{{text: "Steps to reproduce"}}},
+				{key: "JMD-2", description: "Steps to reproduce", comments: []comment{{text: "Steps to reproduce"}}},
+			},
+			wantDescriptions: []string{"", "(Description same as a comment on JMD-1.)"},
+			wantRefs:         []bool{false, true},
+			wantComments:     [][]string{{"Steps to reproduce"}, {"(same as a comment on JMD-1)"}},
+		},
+		{
+			name: "empty text kept",
+			entries: []*entry{
+				{key: "JMD-1", comments: []comment{{text: ""}}},
+				{key: "JMD-2", comments: []comment{{text: ""}}},
+			},
+			wantDescriptions: []string{"", ""},
+			wantRefs:         []bool{false, false},
+			wantComments:     [][]string{{""}, {""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dedupe(tt.entries)
+
+			for i, e := range tt.entries {
+				if e.description != tt.wantDescriptions[i] || e.descriptionRef != tt.wantRefs[i] {
+					t.Errorf("%s description = %q (ref %v), want %q (ref %v)",
+						e.key, e.description, e.descriptionRef, tt.wantDescriptions[i], tt.wantRefs[i])
+				}
+				var texts []string
+				for _, c := range e.comments {
+					texts = append(texts, c.text)
+				}
+				if !slices.Equal(texts, tt.wantComments[i]) {
+					t.Errorf("%s comments = %q, want %q", e.key, texts, tt.wantComments[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package digest contains use cases for the daily digest: a markdown file per day
+// listing every ticket pulled or pushed that day with one-line summaries of what changed,
+// so users and assistants can catch up without diffing ticket files.
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Writer writes digest files (implemented by markdown.ReportWriter).
+type Writer interface {
+	// WriteReport writes the file name with content, reporting whether the file changed
+	WriteReport(ctx context.Context, name string, content []byte) (bool, error)
+}
+
+// Service records the activity of pulls and pushes and writes the daily digests from it.
+//
+// Error contract: Write returns wrapped errors for storage and write failures. Record
+// and HandleEvent log their failures instead, since the change they record is already
+// saved.
+type Service struct {
+	activity repository.ActivityRepository
+	writer   Writer
+	logger   *slog.Logger
+
+	// tickets looks up the summaries of tickets changed locally (nil leaves them empty)
+	tickets repository.TicketRepository
+
+	// location is the time zone days start in
+	location *time.Location
+
+	// now is the clock Write defaults to (overridable in tests)
+	now func() time.Time
+}
+
+// NewService creates a new digest service recording activity in activity and writing
+// digests through writer.
+func NewService(activity repository.ActivityRepository, writer Writer, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{
+		activity: activity,
+		writer:   writer,
+		logger:   logger,
+		location: time.Local,
+		now:      time.Now,
+	}
+}
+
+// WithDisplay makes days start in the display time zone, so a digest covers the day the
+// user sees in timestamps.
+func (s *Service) WithDisplay(display domain.DisplayConfig) *Service {
+	if display.Location != nil {
+		s.location = display.Location
+	}
+	return s
+}
+
+// WithTickets makes HandleEvent look up the summaries of changed tickets in tickets.
+func (s *Service) WithTickets(tickets repository.TicketRepository) *Service {
+	s.tickets = tickets
+	return s
+}
+
+// Record records activities and rewrites the digest of each day they happened on.
+// Failures are logged.
+func (s *Service) Record(ctx context.Context, activities ...*domain.Activity) {
+	days := make(map[string]time.Time)
+	for _, activity := range activities {
+		if err := s.activity.RecordActivity(ctx, activity); err != nil {
+			s.logger.Warn("failed to record digest activity", "ticket_key", activity.TicketKey.String(), "error", err)
+			continue
+		}
+		day := activity.At.In(s.location)
+		days[day.Format(domain.DigestDateLayout)] = day
+	}
+	for _, day := range days {
+		if _, _, err := s.Write(ctx, day); err != nil {
+			s.logger.Warn("failed to write digest", "day", day.Format(domain.DigestDateLayout), "error", err)
+		}
+	}
+}
+
+// HandleEvent records a local change to a ticket as pushed activity. It has the
+// signature of an events.Handler, to subscribe to the bus ticket changes are published on.
+func (s *Service) HandleEvent(ctx context.Context, event domain.Event) {
+	var summary string
+	if s.tickets != nil {
+		ticket, err := s.tickets.FindByKey(ctx, event.EventTicket().String())
+		switch {
+		case err == nil:
+			summary = ticket.Summary
+		case !errors.Is(err, domain.ErrNotFound):
+			s.logger.Warn("failed to look up ticket for digest", "ticket_key", event.EventTicket().String(), "error", err)
+		}
+	}
+	if activity := domain.PushedActivity(event, summary); activity != nil {
+		s.Record(ctx, activity)
+	}
+}
+
+// Write writes the digest of the day of t, in the digest time zone, from the activity
+// recorded that day, and returns its file name and whether it changed. A zero t writes
+// today's digest.
+func (s *Service) Write(ctx context.Context, t time.Time) (name string, changed bool, err error) {
+	if t.IsZero() {
+		t = s.now()
+	}
+	t = t.In(s.location)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location)
+	end := start.AddDate(0, 0, 1)
+
+	activities, err := s.activity.FindActivities(ctx, start, end)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read digest activity: %w", err)
+	}
+
+	name = start.Format(domain.DigestDateLayout) + ".md"
+	changed, err = s.writer.WriteReport(ctx, name, []byte(render(start, domain.BuildDigest(activities))))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to write digest %s: %w", name, err)
+	}
+	return name, changed, nil
+}
+
+// render returns the markdown of the digest of day.
+func render(day time.Time, entries []domain.DigestEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Digest: %s\n\n", day.Format(domain.DigestDateLayout))
+	b.WriteString("*This file is generated by jiramd from the day's pulls and pushes. Edits are overwritten.*\n\n")
+	if len(entries) == 0 {
+		b.WriteString("No tickets were pulled or pushed.\n")
+		return b.String()
+	}
+
+	pulled, pushed := 0, 0
+	for _, entry := range entries {
+		if len(entry.Pulled) > 0 {
+			pulled++
+		}
+		if len(entry.Pushed) > 0 {
+			pushed++
+		}
+	}
+	fmt.Fprintf(&b, "%s: %d pulled, %d pushed.\n\n", plural(len(entries), "ticket"), pulled, pushed)
+
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "- **%s**", entry.TicketKey)
+		if entry.Summary != "" {
+			fmt.Fprintf(&b, " %s", strings.Join(strings.Fields(entry.Summary), " "))
+		}
+		var parts []string
+		if len(entry.Pulled) > 0 {
+			parts = append(parts, "pulled "+strings.Join(entry.Pulled, "; "))
+		}
+		if len(entry.Pushed) > 0 {
+			parts = append(parts, "pushed "+strings.Join(entry.Pushed, "; "))
+		}
+		fmt.Fprintf(&b, ": %s\n", strings.Join(parts, ". "))
+	}
+	return b.String()
+}
+
+// plural returns n with noun, pluralized unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,96 @@
+// Package editor contains use cases for handing single tickets to editors: resolving a
+// ticket's local markdown file, pulling the ticket on demand when it has no file yet.
+package editor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Puller pulls single tickets from Jira into their files (implemented by the pull
+// service).
+type Puller interface {
+	// PullTicket pulls a ticket and returns the path of its file
+	PullTicket(ctx context.Context, key domain.TicketKey) (string, error)
+}
+
+// TicketFiles locates the markdown files of single tickets (implemented by
+// markdown.TicketFiles).
+type TicketFiles interface {
+	// Locate returns the path of a ticket's file, trying the recorded path (relative to
+	// the markdown directory) first, or "" if the ticket has no file
+	Locate(ctx context.Context, key domain.TicketKey, recorded string) (string, error)
+}
+
+// Service resolves where the files of tickets are.
+//
+// Error contract: Methods return domain.ErrInvalidInput for malformed keys,
+// domain.ErrNotFound when a ticket was deleted from Jira or has no file and cannot be
+// pulled, and wrapped errors for storage and Jira failures.
+type Service struct {
+	stateRepo repository.StateRepository
+	files     TicketFiles
+
+	// puller pulls tickets without a file (nil pulls none)
+	puller Puller
+}
+
+// NewService creates a new editor service finding files through the paths recorded in
+// stateRepo.
+func NewService(stateRepo repository.StateRepository, files TicketFiles) *Service {
+	return &Service{
+		stateRepo: stateRepo,
+		files:     files,
+	}
+}
+
+// WithPuller makes LocalFile pull tickets without a file through puller.
+func (s *Service) WithPuller(puller Puller) *Service {
+	s.puller = puller
+	return s
+}
+
+// LocalFile returns the path of a ticket's markdown file, found through the path
+// recorded in its sync state. A ticket without a file is pulled from Jira first, which
+// writes its file and records it; pulled reports whether that happened.
+func (s *Service) LocalFile(ctx context.Context, key string) (path string, pulled bool, err error) {
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return "", false, err
+	}
+
+	path, err = s.locate(ctx, ticketKey)
+	if err != nil || path != "" {
+		return path, false, err
+	}
+	if s.puller == nil {
+		return "", false, fmt.Errorf("%w: %s has no local file; run jiramd sync first", domain.ErrNotFound, ticketKey)
+	}
+
+	if path, err = s.puller.PullTicket(ctx, ticketKey); err != nil {
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// locate returns the path of the ticket's file, or "" if it has none. Returns
+// ErrNotFound for tickets deleted from Jira, whose stale files must not be edited.
+func (s *Service) locate(ctx context.Context, key domain.TicketKey) (string, error) {
+	state, err := s.stateRepo.GetTicketState(ctx, key.String())
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return "", fmt.Errorf("failed to get sync state of %s: %w", key, err)
+	}
+
+	var recorded string
+	if state != nil {
+		if state.IsTombstone() {
+			return "", fmt.Errorf("%w: %s was deleted from Jira", domain.ErrNotFound, key)
+		}
+		recorded = state.FilePath
+	}
+	return s.files.Locate(ctx, key, recorded)
+}
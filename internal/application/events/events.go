@@ -0,0 +1,95 @@
+// Package events defines how the domain events recorded by the Ticket aggregate leave
+// the domain. Use cases drain a ticket's events once its change is saved and hand them
+// to a Publisher, which delivers them to whatever listens: an audit log, hooks, or
+// notifications.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Publisher delivers domain events. Events are published after the change that
+// recorded them is saved, so a publisher cannot undo it; implementations handle their
+// own failures. Implementations must be safe for concurrent use.
+type Publisher interface {
+	// Publish delivers events, oldest first.
+	Publish(ctx context.Context, events []domain.Event)
+}
+
+// Nop returns a Publisher that discards every event, for callers nobody listens to.
+func Nop() Publisher {
+	return nop{}
+}
+
+// OrNop returns p, or Nop() if p is nil.
+func OrNop(p Publisher) Publisher {
+	if p == nil {
+		return Nop()
+	}
+	return p
+}
+
+// nop is the Publisher returned by Nop.
+type nop struct{}
+
+func (nop) Publish(context.Context, []domain.Event) {}
+
+// Handler receives one published event.
+type Handler func(ctx context.Context, event domain.Event)
+
+// Bus is an in-process Publisher that hands each event to every subscribed handler,
+// in the order they subscribed.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewBus creates a bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds a handler that receives every event published from now on.
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish implements Publisher.
+func (b *Bus) Publish(ctx context.Context, events []domain.Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, event := range events {
+		for _, handler := range handlers {
+			handler(ctx, event)
+		}
+	}
+}
+
+// Log returns a Handler that writes each event to logger at info level, as an audit
+// trail of local changes.
+func Log(logger *slog.Logger) Handler {
+	return func(ctx context.Context, event domain.Event) {
+		attrs := []slog.Attr{
+			slog.String("event", event.EventName()),
+			slog.String("ticket", event.EventTicket().String()),
+			slog.Time("at", event.OccurredAt()),
+		}
+		switch e := event.(type) {
+		case domain.StatusChanged:
+			attrs = append(attrs, slog.String("from", e.From), slog.String("to", e.To))
+		case domain.FieldChanged:
+			attrs = append(attrs, slog.String("field", e.Field), slog.String("from", e.From), slog.String("to", e.To))
+		case domain.CommentAdded:
+			attrs = append(attrs, slog.String("comment", e.CommentID), slog.String("author", e.Author))
+		}
+		logger.LogAttrs(ctx, slog.LevelInfo, "ticket event", attrs...)
+	}
+}
@@ -0,0 +1,247 @@
+// Package export contains use cases for exporting cached tickets for spreadsheets and reporting.
+// Exports read the local ticket cache only and never contact Jira.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Format is an export file format.
+type Format string
+
+const (
+	// FormatCSV writes one row per ticket with a column per custom field
+	FormatCSV Format = "csv"
+
+	// FormatJSON writes an array of ticket objects
+	FormatJSON Format = "json"
+)
+
+// ParseFormat validates an export format name.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(name))); format {
+	case FormatCSV, FormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("%w: unknown export format %q (use csv or json)", domain.ErrInvalidInput, name)
+	}
+}
+
+// csvColumns are the fixed leading columns of a CSV export; custom fields follow in name order.
+var csvColumns = []string{
+	"key",
+	"project",
+	"summary",
+	"status",
+	"issue_type",
+	"priority",
+	"assignee",
+	"reporter",
+	"labels",
+	"created",
+	"updated",
+}
+
+// Record is the exported form of a ticket.
+type Record struct {
+	Key          string                 `json:"key"`
+	Project      string                 `json:"project"`
+	Summary      string                 `json:"summary"`
+	Status       string                 `json:"status"`
+	IssueType    string                 `json:"issue_type"`
+	Priority     string                 `json:"priority"`
+	Assignee     string                 `json:"assignee"`
+	Reporter     string                 `json:"reporter"`
+	Labels       []string               `json:"labels"`
+	Created      time.Time              `json:"created"`
+	Updated      time.Time              `json:"updated"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+// newRecord converts a cached ticket into its exported form.
+func newRecord(t *domain.Ticket) Record {
+	labels := t.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	customFields := make(map[string]interface{}, len(t.CustomFields))
+	for name, value := range t.CustomFields {
+		customFields[name] = value.Raw()
+	}
+
+	return Record{
+		Key:          t.Key.String(),
+		Project:      t.Key.ProjectKey(),
+		Summary:      t.Summary,
+		Status:       t.Status,
+		IssueType:    t.IssueType,
+		Priority:     t.Priority,
+		Assignee:     t.Assignee,
+		Reporter:     t.Reporter,
+		Labels:       labels,
+		Created:      t.Created,
+		Updated:      t.Updated,
+		CustomFields: customFields,
+	}
+}
+
+// Service handles export use cases against the local ticket cache.
+//
+// Error contract: Methods return domain.ErrInvalidInput for unknown formats or malformed
+// filters, and wrapped errors for storage and write failures.
+type Service struct {
+	ticketRepo  repository.TicketRepository
+	currentUser string
+	now         func() time.Time
+}
+
+// NewService creates a new export service.
+// currentUser is what currentUser() resolves to in filters (the configured Jira email).
+func NewService(ticketRepo repository.TicketRepository, currentUser string) *Service {
+	return &Service{
+		ticketRepo:  ticketRepo,
+		currentUser: currentUser,
+		now:         time.Now,
+	}
+}
+
+// Export writes the cached tickets matching filter to w and returns how many were written.
+// filter uses the local JQL subset of jiramd query --local; an empty filter exports every ticket.
+func (s *Service) Export(ctx context.Context, w io.Writer, format Format, filter string) (int, error) {
+	ticketFilter, err := domain.ParseTicketFilter(filter, domain.FilterOptions{
+		CurrentUser: s.currentUser,
+		Now:         s.now(),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ticket cache: %w", err)
+	}
+
+	records := make([]Record, 0, len(tickets))
+	for _, t := range ticketFilter.Apply(tickets) {
+		records = append(records, newRecord(t))
+	}
+
+	switch format {
+	case FormatCSV:
+		err = writeCSV(w, records)
+	case FormatJSON:
+		err = writeJSON(w, records)
+	default:
+		_, err = ParseFormat(string(format))
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return len(records), nil
+}
+
+// writeCSV writes records with a header row. Custom fields become columns named after
+// the field, in name order, covering every field used by any exported ticket.
+func writeCSV(w io.Writer, records []Record) error {
+	fieldSet := make(map[string]bool)
+	for _, r := range records {
+		for name := range r.CustomFields {
+			fieldSet[name] = true
+		}
+	}
+	fields := make([]string, 0, len(fieldSet))
+	for name := range fieldSet {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+
+	out := csv.NewWriter(w)
+	if err := out.Write(append(append([]string(nil), csvColumns...), fields...)); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	for _, r := range records {
+		row := []string{
+			r.Key,
+			r.Project,
+			r.Summary,
+			r.Status,
+			r.IssueType,
+			r.Priority,
+			r.Assignee,
+			r.Reporter,
+			strings.Join(r.Labels, ";"),
+			formatCSVTime(r.Created),
+			formatCSVTime(r.Updated),
+		}
+		for _, name := range fields {
+			row = append(row, csvValue(r.CustomFields[name]))
+		}
+		if err := out.Write(row); err != nil {
+			return fmt.Errorf("failed to write csv row for %s: %w", r.Key, err)
+		}
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes records as an indented JSON array.
+func writeJSON(w io.Writer, records []Record) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(records); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
+
+// formatCSVTime formats timestamps as RFC 3339, which spreadsheets parse as dates.
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// csvValue flattens a custom field value into a cell.
+// Lists are joined with semicolons, like labels; objects are written as JSON.
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, csvValue(item))
+		}
+		return strings.Join(items, ";")
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
@@ -4,30 +4,93 @@ package field
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain"
 )
 
+// standardMappings maps built-in Jira field IDs to their markdown frontmatter keys.
+// These apply to every project and cannot be overridden.
+var standardMappings = map[string]string{
+	"summary":     "summary",
+	"description": "description",
+	"status":      "status",
+	"issuetype":   "type",
+	"priority":    "priority",
+	"assignee":    "assignee",
+	"reporter":    "reporter",
+	"labels":      "labels",
+	"created":     "created",
+	"updated":     "updated",
+}
+
 // Service handles field mapping use cases.
 // It manages the mapping between Jira custom fields and markdown representation.
 type Service struct {
-	// TODO: Add dependencies for field mapping storage
+	// TODO: Persist custom mappings instead of keeping them in memory
+	mu             sync.RWMutex
+	customMappings map[string]map[string]string
 }
 
 // NewService creates a new field service.
 func NewService() *Service {
-	return &Service{}
+	return &Service{
+		customMappings: make(map[string]map[string]string),
+	}
 }
 
 // GetMapping retrieves the field mapping for a project.
-// This is a placeholder for the actual implementation.
+// The result maps Jira field IDs to markdown frontmatter keys and always includes
+// the standard fields followed by any project-specific custom mappings.
 func (s *Service) GetMapping(ctx context.Context, projectKey string) (map[string]string, error) {
-	// TODO: Implement field mapping retrieval
-	return nil, errors.New("field.Service.GetMapping not implemented")
+	projectKey = strings.TrimSpace(projectKey)
+	if projectKey == "" {
+		return nil, fmt.Errorf("%w: project key is required", domain.ErrEmptyKey)
+	}
+
+	mapping := make(map[string]string, len(standardMappings))
+	for jiraField, markdownKey := range standardMappings {
+		mapping[jiraField] = markdownKey
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for jiraField, markdownKey := range s.customMappings[projectKey] {
+		mapping[jiraField] = markdownKey
+	}
+
+	return mapping, nil
 }
 
-// SetMapping sets the field mapping for a project.
-// This is a placeholder for the actual implementation.
+// SetMapping sets the custom field mapping for a project, replacing any previous custom mappings.
+// Returns ErrInvalidInput if the mapping tries to redefine a standard field.
 func (s *Service) SetMapping(ctx context.Context, projectKey string, mapping map[string]string) error {
-	// TODO: Implement field mapping storage
-	return errors.New("field.Service.SetMapping not implemented")
+	projectKey = strings.TrimSpace(projectKey)
+	if projectKey == "" {
+		return fmt.Errorf("%w: project key is required", domain.ErrEmptyKey)
+	}
+
+	custom := make(map[string]string, len(mapping))
+	for jiraField, markdownKey := range mapping {
+		if _, ok := standardMappings[jiraField]; ok {
+			return fmt.Errorf("%w: %s is a standard field and cannot be remapped", domain.ErrInvalidInput, jiraField)
+		}
+		if strings.TrimSpace(markdownKey) == "" {
+			return fmt.Errorf("%w: markdown key for %s is required", domain.ErrInvalidInput, jiraField)
+		}
+		custom[jiraField] = markdownKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.customMappings[projectKey] = custom
+	return nil
+}
+
+// IsStandardField reports whether a Jira field ID is one of the built-in mapped fields.
+func IsStandardField(jiraField string) bool {
+	_, ok := standardMappings[jiraField]
+	return ok
 }
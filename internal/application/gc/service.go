@@ -0,0 +1,194 @@
+// Package gc contains use cases for pruning state that is no longer needed.
+// Completed push operations, tombstones of deleted tickets, sync history, processed
+// webhook events, and the activity behind daily digests are kept for a retention period
+// so they can be inspected, then removed to keep the state database bounded over years
+// of use.
+package gc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Report summarizes one garbage collection run.
+type Report struct {
+	// Cutoff is the retention boundary; state older than it was pruned
+	Cutoff time.Time
+
+	// DryRun indicates nothing was actually removed
+	DryRun bool
+
+	// Operations is the number of completed pending operations pruned
+	Operations int
+
+	// Tombstones is the number of tombstoned ticket states pruned
+	Tombstones int
+
+	// SyncHistory is the number of sync history entries pruned
+	SyncHistory int
+
+	// InboxEvents is the number of processed webhook events pruned
+	InboxEvents int
+
+	// Activities is the number of digest activities pruned
+	Activities int
+}
+
+// Total returns the number of records pruned.
+func (r *Report) Total() int {
+	return r.Operations + r.Tombstones + r.SyncHistory + r.InboxEvents + r.Activities
+}
+
+// Service handles garbage collection of expired state.
+//
+// Error contract: Methods return wrapped errors for storage failures; a failed
+// run removes nothing.
+type Service struct {
+	stateRepo   repository.StateRepository
+	opRepo      repository.PendingOperationRepository
+	historyRepo repository.SyncHistoryRepository
+	inboxRepo   repository.InboxRepository
+	activity    repository.ActivityRepository
+	retention   time.Duration
+	logger      *slog.Logger
+
+	// now is the clock used to compute the cutoff (overridable in tests)
+	now func() time.Time
+}
+
+// NewService creates a new gc service that prunes state older than retention
+// (domain.DefaultRetention when retention <= 0).
+func NewService(
+	stateRepo repository.StateRepository,
+	opRepo repository.PendingOperationRepository,
+	historyRepo repository.SyncHistoryRepository,
+	retention time.Duration,
+	logger *slog.Logger,
+) *Service {
+	if retention <= 0 {
+		retention = domain.DefaultRetention
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{
+		stateRepo:   stateRepo,
+		opRepo:      opRepo,
+		historyRepo: historyRepo,
+		retention:   retention,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// WithInbox makes the service prune processed webhook events from inbox too (nil
+// leaves the inbox alone).
+func (s *Service) WithInbox(inbox repository.InboxRepository) *Service {
+	s.inboxRepo = inbox
+	return s
+}
+
+// WithActivity makes the service prune the activity of daily digests too (nil leaves it
+// alone).
+func (s *Service) WithActivity(activity repository.ActivityRepository) *Service {
+	s.activity = activity
+	return s
+}
+
+// Collect prunes completed operations, ticket tombstones, sync history, processed
+// webhook events, and digest activity older than the retention period in a single transaction. With dryRun
+// the transaction is rolled back, so the report shows what would be pruned without
+// removing anything.
+func (s *Service) Collect(ctx context.Context, dryRun bool) (report *Report, err error) {
+	report = &Report{
+		Cutoff: s.now().Add(-s.retention).UTC(),
+		DryRun: dryRun,
+	}
+
+	txCtx, err := s.stateRepo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gc failed: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if rbErr := s.stateRepo.Rollback(txCtx); rbErr != nil && err == nil {
+				err = fmt.Errorf("gc failed: %w", rbErr)
+			}
+		}
+	}()
+
+	if report.Operations, err = s.opRepo.PruneCompleted(txCtx, report.Cutoff); err != nil {
+		return nil, fmt.Errorf("gc failed: %w", err)
+	}
+	if report.Tombstones, err = s.stateRepo.PruneTombstones(txCtx, report.Cutoff); err != nil {
+		return nil, fmt.Errorf("gc failed: %w", err)
+	}
+	if report.SyncHistory, err = s.historyRepo.PruneBefore(txCtx, report.Cutoff); err != nil {
+		return nil, fmt.Errorf("gc failed: %w", err)
+	}
+	if s.inboxRepo != nil {
+		if report.InboxEvents, err = s.inboxRepo.PruneInboxEvents(txCtx, report.Cutoff); err != nil {
+			return nil, fmt.Errorf("gc failed: %w", err)
+		}
+	}
+	if s.activity != nil {
+		if report.Activities, err = s.activity.PruneActivities(txCtx, report.Cutoff); err != nil {
+			return nil, fmt.Errorf("gc failed: %w", err)
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	if err := s.stateRepo.Commit(txCtx); err != nil {
+		return nil, fmt.Errorf("gc failed: %w", err)
+	}
+	committed = true
+
+	return report, nil
+}
+
+// Run collects garbage immediately and then every interval until the context is
+// cancelled. Failures are logged and retried at the next interval.
+// Returns ctx.Err() when the context is cancelled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: gc interval must be positive", domain.ErrConfig)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect runs one garbage collection and logs the outcome.
+func (s *Service) collect(ctx context.Context) {
+	report, err := s.Collect(ctx, false)
+	if err != nil {
+		s.logger.Error("garbage collection failed", "error", err)
+		return
+	}
+	s.logger.Info("garbage collection complete",
+		"cutoff", report.Cutoff,
+		"operations", report.Operations,
+		"tombstones", report.Tombstones,
+		"sync_history", report.SyncHistory,
+		"inbox_events", report.InboxEvents,
+		"activities", report.Activities)
+}
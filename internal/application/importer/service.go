@@ -0,0 +1,174 @@
+// Package importer contains use cases for bulk-creating Jira tickets from existing notes.
+// Tickets are created in batches with a pause between them to stay within Jira's rate limits.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/application/progress"
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+const (
+	// DefaultBatchSize is the number of tickets created per Jira request (Jira's bulk maximum).
+	DefaultBatchSize = 50
+
+	// DefaultBatchDelay is the pause between batches.
+	DefaultBatchDelay = 2 * time.Second
+)
+
+// ErrStopped is the error recorded for drafts that were not attempted because
+// an earlier batch failed as a whole.
+var ErrStopped = errors.New("not attempted: import stopped after an earlier error")
+
+// Creator creates tickets in Jira in bulk.
+type Creator interface {
+	// CreateTickets creates up to DefaultBatchSize tickets in one request.
+	// keys[i] is the key of drafts[i], or zero when failures[i] holds its error.
+	// err is set only when the request as a whole failed.
+	CreateTickets(ctx context.Context, drafts []*domain.TicketDraft) (keys []domain.TicketKey, failures []error, err error)
+}
+
+// Options control an import.
+type Options struct {
+	// DryRun validates the drafts without creating anything
+	DryRun bool
+
+	// BatchSize is the number of tickets per request (DefaultBatchSize when <= 0)
+	BatchSize int
+
+	// BatchDelay is the pause between batches (none when <= 0)
+	BatchDelay time.Duration
+}
+
+// Result is the outcome of importing one draft.
+type Result struct {
+	// Draft is the imported draft
+	Draft *domain.TicketDraft
+
+	// Key is the created ticket's key (zero for dry runs and failures)
+	Key domain.TicketKey
+
+	// Err is why the draft was not created (nil on success)
+	Err error
+}
+
+// Report is the outcome of an import, with one result per draft in input order.
+type Report struct {
+	DryRun  bool
+	Results []Result
+}
+
+// Created returns the number of tickets created.
+func (r *Report) Created() int {
+	count := 0
+	for _, result := range r.Results {
+		if !result.Key.IsZero() {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns the number of drafts that were rejected or not created.
+func (r *Report) Failed() int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Service handles import use cases.
+//
+// Error contract: Import returns a partial report together with the error when a batch
+// request fails as a whole (e.g. domain.ErrUnauthorized); per-ticket failures are only
+// recorded in the report.
+type Service struct {
+	creator  Creator
+	progress progress.Progress
+}
+
+// NewService creates a new import service.
+func NewService(creator Creator) *Service {
+	return &Service{creator: creator, progress: progress.Nop()}
+}
+
+// WithProgress sets where imports report their progress (nil reports nothing).
+func (s *Service) WithProgress(p progress.Progress) *Service {
+	s.progress = progress.OrNop(p)
+	return s
+}
+
+// Import validates the drafts and creates the valid ones in Jira in batches.
+// Invalid drafts are reported as failed and never sent.
+func (s *Service) Import(ctx context.Context, drafts []*domain.TicketDraft, opts Options) (*Report, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || batchSize > DefaultBatchSize {
+		batchSize = DefaultBatchSize
+	}
+
+	report := &Report{DryRun: opts.DryRun, Results: make([]Result, len(drafts))}
+	pending := make([]int, 0, len(drafts))
+	for i, draft := range drafts {
+		report.Results[i].Draft = draft
+		if err := draft.Validate(); err != nil {
+			report.Results[i].Err = err
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	s.progress.Start("Creating tickets", len(pending))
+	defer s.progress.Finish()
+
+	for start := 0; start < len(pending); start += batchSize {
+		if start > 0 && opts.BatchDelay > 0 {
+			select {
+			case <-ctx.Done():
+				s.stop(report, pending[start:])
+				return report, ctx.Err()
+			case <-time.After(opts.BatchDelay):
+			}
+		}
+
+		batch := pending[start:min(start+batchSize, len(pending))]
+		batchDrafts := make([]*domain.TicketDraft, len(batch))
+		for j, i := range batch {
+			batchDrafts[j] = drafts[i]
+		}
+
+		keys, failures, err := s.creator.CreateTickets(ctx, batchDrafts)
+		if err != nil {
+			for _, i := range batch {
+				report.Results[i].Err = err
+			}
+			s.stop(report, pending[start+len(batch):])
+			return report, fmt.Errorf("failed to create tickets: %w", err)
+		}
+
+		for j, i := range batch {
+			report.Results[i].Key = keys[j]
+			report.Results[i].Err = failures[j]
+		}
+		s.progress.Advance(len(batch))
+	}
+
+	return report, nil
+}
+
+// stop marks the drafts at the given indexes as not attempted.
+func (s *Service) stop(report *Report, indexes []int) {
+	for _, i := range indexes {
+		report.Results[i].Err = ErrStopped
+	}
+}
@@ -0,0 +1,152 @@
+// Package inbox contains use cases for processing webhook events asynchronously.
+// Deliveries are persisted to a durable inbox and acknowledged at once; the service
+// drains the inbox in the background, so bursts of events and daemon restarts never drop
+// a change notification.
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// batchSize bounds how many pending events are processed together.
+const batchSize = 100
+
+// retryInterval is how often Run checks the inbox when no delivery woke it, so events
+// left pending by a failed run are retried.
+const retryInterval = time.Minute
+
+// SyncTrigger requests on-demand syncs. It is satisfied by the scheduler service.
+type SyncTrigger interface {
+	// TriggerThen queues a sync (full or incremental) and calls done with its outcome
+	// once it has run; returns false if one is already pending, in which case done is
+	// called when that one has run.
+	TriggerThen(full bool, done func(error)) bool
+}
+
+// Service persists webhook events and processes them asynchronously.
+//
+// Processing semantics: the pending events of a batch that concern the project queue a
+// single incremental sync, so a burst of events costs one sync (or join a queued one,
+// which has not started and will see the change). The events of a batch are marked
+// processed once that sync has succeeded; when it fails, they stay pending and are
+// retried. Events are processed once by ID, however often Jira redelivers them.
+type Service struct {
+	repo       repository.InboxRepository
+	trigger    SyncTrigger
+	projectKey string
+	logger     *slog.Logger
+
+	// wake signals Run that an event was received
+	wake chan struct{}
+
+	// now is the clock used to stamp processed events (overridable in tests)
+	now func() time.Time
+}
+
+// NewService creates a new inbox service that queues syncs of projectKey with trigger.
+func NewService(repo repository.InboxRepository, trigger SyncTrigger, projectKey string, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{
+		repo:       repo,
+		trigger:    trigger,
+		projectKey: projectKey,
+		logger:     logger,
+		wake:       make(chan struct{}, 1),
+		now:        time.Now,
+	}
+}
+
+// Receive persists a delivered event and wakes the processor. Returns false, without
+// processing it again, if an event with the same ID was already received.
+func (s *Service) Receive(ctx context.Context, event *domain.InboxEvent) (bool, error) {
+	added, err := s.repo.AddInboxEvent(ctx, event)
+	if err != nil {
+		return false, fmt.Errorf("failed to receive webhook event: %w", err)
+	}
+	if !added {
+		s.logger.Debug("ignored redelivered webhook event", "event_id", event.ID)
+		return false, nil
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+		// A wake-up is already pending; it will pick up this event
+	}
+	return true, nil
+}
+
+// Process handles the pending events, batch by batch, until none are left, waiting for
+// the sync each batch queues. Returns the number of events processed, and the sync's
+// error when a batch's sync failed, leaving that batch pending.
+func (s *Service) Process(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		events, err := s.repo.FindPendingInboxEvents(ctx, batchSize)
+		if err != nil {
+			return processed, fmt.Errorf("failed to read inbox: %w", err)
+		}
+		if len(events) == 0 {
+			return processed, nil
+		}
+
+		ids := make([]string, 0, len(events))
+		relevant := 0
+		for _, event := range events {
+			ids = append(ids, event.ID)
+			if event.ConcernsProject(s.projectKey) {
+				relevant++
+			}
+		}
+		if relevant > 0 {
+			synced := make(chan error, 1)
+			queued := s.trigger.TriggerThen(false, func(err error) { synced <- err })
+			s.logger.Info("webhook events received",
+				"events", relevant,
+				"sync_queued", queued)
+
+			select {
+			case err := <-synced:
+				if err != nil {
+					return processed, fmt.Errorf("sync for webhook events failed: %w", err)
+				}
+			case <-ctx.Done():
+				return processed, ctx.Err()
+			}
+		}
+
+		if err := s.repo.MarkInboxEventsProcessed(ctx, ids, s.now().UTC()); err != nil {
+			return processed, fmt.Errorf("failed to mark inbox events processed: %w", err)
+		}
+		processed += len(events)
+	}
+}
+
+// Run processes the events left pending by a previous run, then each event as it is
+// received, until the context is cancelled. Failures are logged and retried later.
+// Returns ctx.Err() when the context is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Process(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("webhook event processing failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
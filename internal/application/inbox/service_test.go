@@ -0,0 +1,95 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// memoryInbox is an in-memory repository.InboxRepository.
+type memoryInbox struct {
+	events []*domain.InboxEvent
+}
+
+func (m *memoryInbox) AddInboxEvent(ctx context.Context, event *domain.InboxEvent) (bool, error) {
+	for _, e := range m.events {
+		if e.ID == event.ID {
+			return false, nil
+		}
+	}
+	m.events = append(m.events, event)
+	return true, nil
+}
+
+func (m *memoryInbox) FindPendingInboxEvents(ctx context.Context, limit int) ([]*domain.InboxEvent, error) {
+	var pending []*domain.InboxEvent
+	for _, e := range m.events {
+		if !e.Processed() && len(pending) < limit {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memoryInbox) MarkInboxEventsProcessed(ctx context.Context, ids []string, at time.Time) error {
+	for _, e := range m.events {
+		if slices.Contains(ids, e.ID) {
+			e.ProcessedAt = at
+		}
+	}
+	return nil
+}
+
+func (m *memoryInbox) PruneInboxEvents(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+var _ repository.InboxRepository = (*memoryInbox)(nil)
+
+// syncOutcome is a SyncTrigger whose syncs run at once and end with err.
+type syncOutcome struct {
+	err      error
+	triggers int
+}
+
+func (s *syncOutcome) TriggerThen(full bool, done func(error)) bool {
+	s.triggers++
+	done(s.err)
+	return true
+}
+
+func TestService_Process(t *testing.T) {
+	key, _ := domain.NewTicketKey("JMD-1")
+	received := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	repo := &memoryInbox{}
+	trigger := &syncOutcome{err: errors.New("jira unavailable")}
+	service := NewService(repo, trigger, "JMD", nil)
+	ctx := context.Background()
+
+	if _, err := service.Receive(ctx, domain.NewInboxEvent("delivery-1", "jira:issue_updated", key, []byte(`{}`), received)); err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+
+	// A failed sync leaves the event pending for the next run
+	processed, err := service.Process(ctx)
+	if err == nil || processed != 0 {
+		t.Fatalf("Process() = %d, %v, want the sync's error and nothing processed", processed, err)
+	}
+	if repo.events[0].Processed() {
+		t.Error("event was marked processed although its sync failed")
+	}
+
+	trigger.err = nil
+	processed, err = service.Process(ctx)
+	if err != nil || processed != 1 {
+		t.Fatalf("Process() = %d, %v, want 1 processed", processed, err)
+	}
+	if !repo.events[0].Processed() || trigger.triggers != 2 {
+		t.Errorf("event processed = %v after %d syncs, want processed after 2", repo.events[0].Processed(), trigger.triggers)
+	}
+}
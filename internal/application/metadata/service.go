@@ -0,0 +1,166 @@
+// Package metadata contains use cases for the cached metadata of Jira projects: their
+// issue types, priorities, statuses, components, and workflow transitions. The cache
+// lets edits be validated offline and spares Jira repeated metadata calls.
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Source fetches project metadata from Jira (implemented by the Jira client).
+type Source interface {
+	// FetchProjectMetadata returns a project's issue types, priorities, statuses, and
+	// components, without transitions
+	FetchProjectMetadata(ctx context.Context, projectKey string) (*domain.ProjectMetadata, error)
+
+	// AvailableTransitions returns the statuses a ticket can be moved to from its
+	// current one
+	AvailableTransitions(ctx context.Context, key string) ([]string, error)
+}
+
+// Service handles project metadata use cases.
+//
+// Error contract: Methods return domain.ErrEmptyKey for an empty project key,
+// domain.ErrNotFound when metadata is neither cached nor fetchable, and wrapped errors
+// for Jira and storage failures.
+type Service struct {
+	source     Source
+	cache      repository.ProjectMetadataRepository
+	ticketRepo repository.TicketRepository
+	ttl        time.Duration
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewService creates a new metadata service fetching from source, which may be nil to
+// only ever read the cache (e.g. offline). Transitions are learned from one cached
+// ticket in each status, since Jira only lists them per ticket.
+func NewService(source Source, cache repository.ProjectMetadataRepository, ticketRepo repository.TicketRepository) *Service {
+	return &Service{
+		source:     source,
+		cache:      cache,
+		ticketRepo: ticketRepo,
+		ttl:        domain.DefaultMetadataTTL,
+		logger:     slog.Default(),
+		now:        time.Now,
+	}
+}
+
+// WithTTL sets how long cached metadata is used before Get refreshes it
+// (domain.DefaultMetadataTTL when ttl <= 0).
+func (s *Service) WithTTL(ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = domain.DefaultMetadataTTL
+	}
+	s.ttl = ttl
+	return s
+}
+
+// WithLogger sets where refresh failures are logged.
+func (s *Service) WithLogger(logger *slog.Logger) *Service {
+	if logger != nil {
+		s.logger = logger
+	}
+	return s
+}
+
+// Get returns the metadata of a project, from the cache unless it is older than the
+// TTL. When a refresh fails, stale cached metadata is returned and the failure logged,
+// so validation keeps working offline.
+func (s *Service) Get(ctx context.Context, projectKey string) (*domain.ProjectMetadata, error) {
+	cached, err := s.cache.FindProjectMetadata(ctx, projectKey)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if cached != nil && (s.source == nil || !cached.Stale(s.now(), s.ttl)) {
+		return cached, nil
+	}
+	if s.source == nil {
+		return nil, err
+	}
+
+	refreshed, err := s.Refresh(ctx, projectKey)
+	if err != nil {
+		if cached != nil {
+			s.logger.Warn("failed to refresh project metadata, using cached metadata",
+				"project", projectKey, "fetched_at", cached.FetchedAt, "error", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	return refreshed, nil
+}
+
+// Refresh fetches the metadata of a project from Jira and replaces the cached copy.
+// Transitions are listed for one cached ticket in each status; those of statuses no
+// cached ticket is in are kept from the previous copy.
+func (s *Service) Refresh(ctx context.Context, projectKey string) (*domain.ProjectMetadata, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("%w: project key is required", domain.ErrEmptyKey)
+	}
+	if s.source == nil {
+		return nil, fmt.Errorf("%w: no Jira connection to refresh project metadata from", domain.ErrInvalidOperation)
+	}
+
+	metadata, err := s.source.FetchProjectMetadata(ctx, projectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata of project %s: %w", projectKey, err)
+	}
+	metadata.ProjectKey = projectKey
+	metadata.FetchedAt = s.now().UTC()
+	if metadata.Transitions == nil {
+		metadata.Transitions = make(map[string][]string)
+	}
+
+	if previous, err := s.cache.FindProjectMetadata(ctx, projectKey); err == nil {
+		for status, targets := range previous.Transitions {
+			metadata.Transitions[status] = targets
+		}
+	}
+	if err := s.learnTransitions(ctx, metadata); err != nil {
+		return nil, err
+	}
+	metadata.Normalize()
+
+	if err := s.cache.SaveProjectMetadata(ctx, metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// learnTransitions lists the transitions of one cached ticket of the project in each
+// status. A ticket whose transitions cannot be listed is skipped with a warning.
+func (s *Service) learnTransitions(ctx context.Context, metadata *domain.ProjectMetadata) error {
+	if s.ticketRepo == nil {
+		return nil
+	}
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list cached tickets: %w", err)
+	}
+
+	sampled := make(map[string]bool)
+	for _, ticket := range tickets {
+		if ticket.Key.ProjectKey() != metadata.ProjectKey || ticket.Status == "" || sampled[ticket.Status] {
+			continue
+		}
+		targets, err := s.source.AvailableTransitions(ctx, ticket.Key.String())
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.Warn("failed to list transitions", "ticket", ticket.Key, "error", err)
+			continue
+		}
+		sampled[ticket.Status] = true
+		metadata.Transitions[ticket.Status] = targets
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package permission contains the use case for checking, before anything is pushed,
+// that the Jira token is allowed to do what syncing needs.
+package permission
+
+import (
+	"context"
+	"fmt"
+)
+
+// Permission is a Jira project permission syncing relies on.
+type Permission struct {
+	// Key is Jira's permission key (e.g. "EDIT_ISSUES")
+	Key string
+
+	// Purpose is what jiramd needs the permission for
+	Purpose string
+}
+
+// Required lists the permissions checked by Check, in report order.
+var Required = []Permission{
+	{Key: "BROWSE_PROJECTS", Purpose: "pull tickets"},
+	{Key: "CREATE_ISSUES", Purpose: "create tickets"},
+	{Key: "EDIT_ISSUES", Purpose: "push field changes"},
+	{Key: "TRANSITION_ISSUES", Purpose: "push status changes"},
+	{Key: "ADD_COMMENTS", Purpose: "push comments"},
+	{Key: "CREATE_ATTACHMENTS", Purpose: "upload attachments"},
+	{Key: "DELETE_OWN_ATTACHMENTS", Purpose: "remove attachments"},
+}
+
+// Checker looks up the authenticated user's permissions in Jira.
+type Checker interface {
+	// MyPermissions reports which of the given permission keys the user has in the project.
+	MyPermissions(ctx context.Context, projectKey string, permissions []string) (map[string]bool, error)
+}
+
+// Result is whether one required permission is granted.
+type Result struct {
+	Permission
+	Granted bool
+}
+
+// Report lists the required permissions and whether each is granted.
+type Report struct {
+	ProjectKey string
+	Results    []Result
+}
+
+// Missing returns the results of the permissions that are not granted.
+func (r *Report) Missing() []Result {
+	var missing []Result
+	for _, result := range r.Results {
+		if !result.Granted {
+			missing = append(missing, result)
+		}
+	}
+	return missing
+}
+
+// Service handles permission check use cases.
+//
+// Error contract: Check returns domain.ErrUnauthorized when the token is rejected
+// outright and wrapped errors for other Jira failures; missing permissions are only
+// reported.
+type Service struct {
+	checker Checker
+}
+
+// NewService creates a new permission check service.
+func NewService(checker Checker) *Service {
+	return &Service{checker: checker}
+}
+
+// Check reports which of the Required permissions the token has in the project.
+func (s *Service) Check(ctx context.Context, projectKey string) (*Report, error) {
+	keys := make([]string, len(Required))
+	for i, permission := range Required {
+		keys[i] = permission.Key
+	}
+
+	granted, err := s.checker.MyPermissions(ctx, projectKey, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check permissions in %s: %w", projectKey, err)
+	}
+
+	report := &Report{ProjectKey: projectKey, Results: make([]Result, len(Required))}
+	for i, permission := range Required {
+		report.Results[i] = Result{Permission: permission, Granted: granted[permission.Key]}
+	}
+	return report, nil
+}
@@ -0,0 +1,42 @@
+// Package progress defines how long-running use cases report their progress.
+// Implementations live in the infrastructure layer: a terminal progress bar for the
+// CLI and periodic log records for the daemon.
+package progress
+
+// Progress receives updates from a long-running operation, such as pulling every
+// ticket of a large project, so the user is not left watching a silent process.
+//
+// An operation calls Start once, Advance as work completes, and Finish when it is
+// done, whether or not it succeeded. Implementations must be safe for concurrent
+// use, since work may complete on several goroutines.
+type Progress interface {
+	// Start begins a task with the given description. total is the number of units of
+	// work, or zero or less when it is not known up front.
+	Start(task string, total int)
+
+	// Advance records that n more units of work are done.
+	Advance(n int)
+
+	// Finish ends the current task.
+	Finish()
+}
+
+// Nop returns a Progress that discards every update, for callers that do not report progress.
+func Nop() Progress {
+	return nop{}
+}
+
+// OrNop returns p, or Nop() if p is nil.
+func OrNop(p Progress) Progress {
+	if p == nil {
+		return Nop()
+	}
+	return p
+}
+
+// nop is the Progress returned by Nop.
+type nop struct{}
+
+func (nop) Start(string, int) {}
+func (nop) Advance(int)       {}
+func (nop) Finish()           {}
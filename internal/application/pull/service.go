@@ -0,0 +1,720 @@
+// Package pull contains use cases for pulling single tickets rather than whole projects:
+// refreshing a synced ticket now, fetching a ticket from another project ad hoc, and
+// keeping the tickets of the watch list in sync.
+package pull
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// TicketSource fetches single tickets from Jira (implemented by the Jira client).
+type TicketSource interface {
+	// GetTicket returns the ticket with the given key
+	GetTicket(ctx context.Context, key string) (*domain.Ticket, error)
+}
+
+// TicketFiles locates and writes the markdown files of single tickets (implemented by
+// markdown.TicketFiles).
+type TicketFiles interface {
+	// Locate returns the path of a ticket's file, trying the recorded path (relative to
+	// the markdown directory) first, or "" if the ticket has no file
+	Locate(ctx context.Context, key domain.TicketKey, recorded string) (string, error)
+
+	// Rel returns the path to record for the ticket file at path
+	Rel(path string) (string, error)
+
+	// Stage stages writing ticket's file at the recorded path, keeping the notes of an
+	// existing file
+	Stage(ctx context.Context, uow repository.UnitOfWork, ticket *domain.Ticket, recorded string) error
+
+	// StageStreamed stages writing ticket's file like Stage, with the comments each
+	// streams instead of ticket.Comments
+	StageStreamed(ctx context.Context, uow repository.UnitOfWork, ticket *domain.Ticket, recorded string, each func(fn func(comment *domain.Comment) error) error) error
+}
+
+// CommentSource fetches the comments of single tickets (implemented by the Jira client).
+type CommentSource interface {
+	// FetchComments returns every comment of a ticket, oldest first
+	FetchComments(ctx context.Context, ticketKey string) ([]*domain.Comment, error)
+
+	// ForEachComment calls fn with each comment of a ticket, oldest first, fetching one
+	// page of comments at a time
+	ForEachComment(ctx context.Context, ticketKey string, fn func(comment *domain.Comment) error) error
+}
+
+// streamCommentsOver is the comment count above which pulls stream a ticket's comments
+// into its file a page at a time, rather than holding them all (and caching them for the
+// next pull of the same revision).
+const streamCommentsOver = 500
+
+// CommentCounter counts the comments of single tickets (implemented by the Jira client).
+type CommentCounter interface {
+	// CountComments returns how many comments a ticket has
+	CountComments(ctx context.Context, ticketKey string) (int, error)
+}
+
+// StatusHistorySource fetches the status changes of single tickets (implemented by the
+// Jira client).
+type StatusHistorySource interface {
+	// FetchStatusHistory returns the status changes in a ticket's changelog, oldest first
+	FetchStatusHistory(ctx context.Context, ticketKey string) ([]domain.StatusChange, error)
+}
+
+// LocalVersionSaver saves a copy of a ticket file with local changes before a pull
+// overwrites it with the Jira version, returning nil if there is no file to save
+// (implemented by markdown.LocalVersionWriter).
+type LocalVersionSaver interface {
+	SaveLocalVersion(ctx context.Context, key domain.TicketKey, path, jiraVersion string) (*domain.LocalVersion, error)
+}
+
+// MediaWriter downloads the attachments pulled tickets reference, so their files render
+// offline (implemented by markdown.MediaWriter).
+type MediaWriter interface {
+	// WriteMedia downloads the attachments referenced by the ticket's description and
+	// comments that are missing, returning how many it downloaded
+	WriteMedia(ctx context.Context, ticket *domain.Ticket) (int, error)
+}
+
+// ActivityRecorder records what pulls changed for the daily digest (implemented by the
+// digest service).
+type ActivityRecorder interface {
+	// Record records activities, logging failures
+	Record(ctx context.Context, activities ...*domain.Activity)
+}
+
+// Result describes one pulled ticket.
+type Result struct {
+	// Ticket is the ticket as pulled from Jira
+	Ticket *domain.Ticket
+
+	// Path is the ticket's markdown file
+	Path string
+
+	// Created reports whether the file was new, rather than rewritten
+	Created bool
+
+	// Adhoc reports whether the ticket is outside the sync scope and tracked pull-only
+	Adhoc bool
+
+	// LocalVersion is where the local changes the pull overwrote were saved (empty when
+	// the file had none)
+	LocalVersion string
+}
+
+// Service pulls single tickets from Jira into their markdown files and the local cache.
+//
+// Error contract: Methods return domain.ErrInvalidInput for malformed keys and keys on
+// the wrong side of the sync scope for the adhoc flag, domain.ErrNotFound for tickets
+// deleted from Jira, domain.ErrConflict when the ticket has local changes not pushed yet
+// that the sync policy does not let Jira overwrite, and wrapped errors for storage and
+// Jira failures.
+type Service struct {
+	source     TicketSource
+	stateRepo  repository.StateRepository
+	ticketRepo repository.TicketRepository
+	files      TicketFiles
+
+	// newUnitOfWork starts the unit of work each pull is written through
+	newUnitOfWork func() repository.UnitOfWork
+
+	// projectKey is the synced project; tickets of other projects are pulled ad hoc
+	projectKey string
+
+	// locks serializes pulls with syncs (nil when no sync runs concurrently)
+	locks repository.LockManager
+
+	// activity records what each pull changed (nil records nothing)
+	activity ActivityRecorder
+
+	// comments fetches the comments of pulled tickets (nil keeps the comments of their
+	// files as they are)
+	comments CommentSource
+
+	// policy decides whether Jira overwrites a ticket with local changes; localVersions
+	// saves, and localVersionRepo records, the files it overwrites (nil refuses to
+	// overwrite them)
+	policy           domain.SyncPolicy
+	localVersions    LocalVersionSaver
+	localVersionRepo repository.LocalVersionRepository
+
+	// commentStates records each comment as last pulled, and counter counts comments in
+	// Jira, so pulls skip fetching comments that cannot have changed (nil fetches the
+	// comments of every pull)
+	commentStates repository.CommentStateRepository
+	counter       CommentCounter
+
+	// fetched caches tickets as they were fetched from Jira with their comments, so pulls
+	// of unchanged tickets skip fetching the comments again (nil fetches them every time)
+	fetched repository.FetchedTicketRepository
+
+	// history fetches the status history of pulled tickets, and historyRepo caches it for
+	// stats and reports (nil caches none)
+	history     StatusHistorySource
+	historyRepo repository.StatusHistoryRepository
+
+	// authors records the profiles of the authors of pulled comments on their tickets
+	// (see domain.AuthorsField)
+	authors bool
+
+	// media downloads the attachments pulled tickets reference (nil downloads none)
+	media MediaWriter
+
+	// fieldDirections returns the field direction overrides of a project; pulls keep the
+	// cached values of its local_only fields (nil keeps none)
+	fieldDirections func(projectKey string) domain.FieldDirections
+
+	// logger logs the cache failures pulls recover from
+	logger *slog.Logger
+
+	// now is the clock pulls are recorded with (overridable in tests)
+	now func() time.Time
+}
+
+// NewService creates a new pull service for the synced project projectKey. Pulled
+// tickets are cached in ticketRepo, with their file path recorded in stateRepo, through
+// units of work from newUnitOfWork.
+func NewService(
+	source TicketSource,
+	stateRepo repository.StateRepository,
+	ticketRepo repository.TicketRepository,
+	files TicketFiles,
+	newUnitOfWork func() repository.UnitOfWork,
+	projectKey string,
+) *Service {
+	return &Service{
+		source:        source,
+		stateRepo:     stateRepo,
+		ticketRepo:    ticketRepo,
+		files:         files,
+		newUnitOfWork: newUnitOfWork,
+		projectKey:    projectKey,
+		logger:        slog.New(slog.DiscardHandler),
+		now:           time.Now,
+	}
+}
+
+// WithLocks makes pulls hold the ticket's lock from locks.
+func (s *Service) WithLocks(locks repository.LockManager) *Service {
+	s.locks = locks
+	return s
+}
+
+// WithActivity makes pulls record what they changed in activity.
+func (s *Service) WithActivity(activity ActivityRecorder) *Service {
+	s.activity = activity
+	return s
+}
+
+// WithComments makes pulls fetch every comment of the ticket from comments and rewrite
+// the comments section of its file with them, e.g. to expand a file summarized by
+// content limits. Without it, the comments of the file are kept as they are.
+func (s *Service) WithComments(comments CommentSource) *Service {
+	s.comments = comments
+	return s
+}
+
+// WithPolicy sets the sync policy deciding whether pulls overwrite tickets with local
+// changes not pushed yet, e.g. when Jira wins conflicts (see WithLocalVersions).
+func (s *Service) WithPolicy(policy domain.SyncPolicy) *Service {
+	s.policy = policy
+	return s
+}
+
+// WithLocalVersions sets where pulls save the ticket files with local changes they
+// overwrite, and where the saved copies are recorded. Without them, pulls never
+// overwrite a file with local changes.
+func (s *Service) WithLocalVersions(saver LocalVersionSaver, versions repository.LocalVersionRepository) *Service {
+	s.localVersions = saver
+	s.localVersionRepo = versions
+	return s
+}
+
+// WithCommentStates sets where the state of each pulled comment is recorded, and where
+// comments are counted in Jira. Pulls with WithComments then only fetch the comments of
+// tickets updated or with a different comment count since they were last pulled, and
+// only rewrite the comments sections of files whose comments changed.
+func (s *Service) WithCommentStates(states repository.CommentStateRepository, counter CommentCounter) *Service {
+	s.commentStates = states
+	s.counter = counter
+	return s
+}
+
+// WithFetchedTickets sets the cache of tickets as they were last fetched from Jira.
+// Pulls reuse the cached comments of a ticket Jira has not updated since it was cached,
+// instead of fetching them again, and PullFound reuses the cached ticket itself (nil
+// fetches both on every pull).
+func (s *Service) WithFetchedTickets(fetched repository.FetchedTicketRepository) *Service {
+	s.fetched = fetched
+	return s
+}
+
+// WithStatusHistory makes pulls fetch the status history of the ticket from source and
+// cache it in repo along with the ticket, for cycle times and reports.
+func (s *Service) WithStatusHistory(source StatusHistorySource, repo repository.StatusHistoryRepository) *Service {
+	s.history = source
+	s.historyRepo = repo
+	return s
+}
+
+// WithAuthors makes pulls record the profiles of the authors of the comments they fetch
+// on the ticket, along with its assignee and reporter, as markdown.authors enables.
+func (s *Service) WithAuthors(enabled bool) *Service {
+	s.authors = enabled
+	return s
+}
+
+// WithMedia makes pulls download the attachments the description and comments of the
+// ticket reference through media, as markdown.download_media enables. Failed downloads
+// are logged rather than failing the pull; the next pull retries them.
+func (s *Service) WithMedia(media MediaWriter) *Service {
+	s.media = media
+	return s
+}
+
+// WithFieldDirections sets the field direction overrides pulls honor, usually
+// domain.SyncConfig.FieldDirectionsFor: local_only fields keep their cached values
+// rather than taking Jira's.
+func (s *Service) WithFieldDirections(directions func(projectKey string) domain.FieldDirections) *Service {
+	s.fieldDirections = directions
+	return s
+}
+
+// WithLogger sets where the cache failures pulls recover from are logged (nil logs
+// nothing).
+func (s *Service) WithLogger(logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	s.logger = logger
+	return s
+}
+
+// mode selects how a pull files and tracks a ticket.
+type mode int
+
+const (
+	// modeSynced pulls a ticket of the synced project
+	modeSynced mode = iota
+
+	// modeAdhoc pulls a ticket from outside the sync scope, tracked pull-only
+	modeAdhoc
+
+	// modeWatched pulls a ticket of the watch list
+	modeWatched
+
+	// modeForced pulls a ticket of the synced project found by a search, even when it is
+	// at the revision found
+	modeForced
+)
+
+// Pull fetches a ticket from Jira and writes its file, cache entry, and sync state
+// together. The file is rewritten where it is, keeping local notes; a ticket without one
+// gets <KEY>.md, or adhoc/<KEY>.md when pulled ad hoc. adhoc must be set exactly for
+// tickets outside the synced project, which are tracked pull-only: pulling them again
+// refreshes them, but they are never pushed. Tickets on the watch list are pulled as
+// such, with or without adhoc.
+func (s *Service) Pull(ctx context.Context, key string, adhoc bool) (*Result, error) {
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if adhoc && ticketKey.ProjectKey() == s.projectKey {
+		return nil, fmt.Errorf("%w: %s is in the synced project %s; pull it without --adhoc",
+			domain.ErrInvalidInput, ticketKey, s.projectKey)
+	}
+
+	if adhoc {
+		return s.pull(ctx, ticketKey, modeAdhoc, nil)
+	}
+	return s.pull(ctx, ticketKey, modeSynced, nil)
+}
+
+// PullTicket pulls a ticket, ad hoc when it is outside the synced project, and returns
+// the path of its file.
+func (s *Service) PullTicket(ctx context.Context, key domain.TicketKey) (string, error) {
+	result, err := s.Pull(ctx, key.String(), key.ProjectKey() != s.projectKey)
+	if err != nil {
+		return "", err
+	}
+	return result.Path, nil
+}
+
+// PullWatched pulls a ticket of the watch list, writing a ticket without a file to
+// watchlist/<KEY>.md, and marks it watched.
+func (s *Service) PullWatched(ctx context.Context, key domain.TicketKey) error {
+	_, err := s.pull(ctx, key, modeWatched, nil)
+	return err
+}
+
+// PullFound pulls a ticket of the synced project that a search found at the revision of
+// found, and reports whether it did. Unless force is set, a ticket whose file was written
+// at that revision, and that has no local changes, is left as it is without asking Jira
+// for it again. The details of a ticket the fetched ticket cache holds at that revision
+// are not fetched again either way (see WithFetchedTickets).
+func (s *Service) PullFound(ctx context.Context, found *domain.Ticket, force bool) (bool, error) {
+	mode := modeSynced
+	if force {
+		mode = modeForced
+	}
+	result, err := s.pull(ctx, found.Key, mode, found)
+	return result != nil, err
+}
+
+// pull pulls a ticket in mode while holding its lock. found is the ticket as a search
+// found it, or nil when the ticket was not searched for; pull returns a nil result when
+// the ticket is already pulled at that revision.
+func (s *Service) pull(ctx context.Context, key domain.TicketKey, mode mode, found *domain.Ticket) (result *Result, err error) {
+	if s.locks != nil {
+		unlock, lockErr := s.locks.Lock(ctx, key.String())
+		if lockErr != nil {
+			return nil, fmt.Errorf("failed to lock ticket %s: %w", key, lockErr)
+		}
+		defer func() {
+			err = errors.Join(err, unlock())
+		}()
+	}
+
+	state, err := s.stateRepo.GetTicketState(ctx, key.String())
+	if errors.Is(err, domain.ErrNotFound) {
+		state = &repository.TicketSyncState{TicketKey: key.String()}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get sync state of %s: %w", key, err)
+	}
+	force := mode == modeForced
+	if force {
+		mode = modeSynced
+	}
+	if state.Watched {
+		mode = modeWatched
+	}
+	if found != nil && !force && state.FilePath != "" && !state.IsDirty && state.LastModifiedJira.Equal(found.Updated) {
+		return nil, nil
+	}
+	if mode == modeSynced && key.ProjectKey() != s.projectKey {
+		return nil, fmt.Errorf("%w: %s is outside the synced project %s; pull it with --adhoc",
+			domain.ErrInvalidInput, key, s.projectKey)
+	}
+	if state.IsTombstone() {
+		return nil, fmt.Errorf("%w: %s was deleted from Jira", domain.ErrNotFound, key)
+	}
+	dirty := state.IsDirty && !state.PullOnly
+	if dirty && (s.localVersions == nil || s.localVersionRepo == nil) {
+		return nil, fmt.Errorf("%w: %s has local changes that are not pushed yet; push them first",
+			domain.ErrConflict, key)
+	}
+
+	ticket, err := s.fetch(ctx, key, found)
+	if err != nil {
+		return nil, err
+	}
+	if dirty && !s.policy.ShouldPull(s.ticketState(state, ticket)) {
+		return nil, fmt.Errorf("%w: %s has local changes that are not pushed yet, and the sync policy does not let Jira overwrite them; push them first",
+			domain.ErrConflict, key)
+	}
+	streamed, unchanged := false, false
+	if s.comments != nil {
+		if stale, count := s.commentsStale(ctx, ticket, state.LastModifiedJira); stale {
+			if streamed, err = s.pullComments(ctx, ticket, count); err != nil {
+				return nil, err
+			}
+			if !streamed && s.authors {
+				ticket.AddCommentAuthors()
+			}
+			unchanged = !streamed && s.commentStates != nil && s.recordComments(ctx, ticket).IsEmpty()
+		}
+	}
+	s.writeMedia(ctx, ticket)
+	if unchanged {
+		// Nil keeps the comments section of the file as it is
+		ticket.Comments = nil
+	}
+
+	var history []domain.StatusChange
+	if s.history != nil && s.historyRepo != nil {
+		if history, err = s.history.FetchStatusHistory(ctx, key.String()); err != nil {
+			return nil, fmt.Errorf("failed to pull status history of %s: %w", key, err)
+		}
+	}
+
+	path, err := s.files.Locate(ctx, key, state.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	var recorded string
+	switch {
+	case path != "":
+		if recorded, err = s.files.Rel(path); err != nil {
+			return nil, err
+		}
+	case mode == modeAdhoc:
+		recorded = key.FilePath(domain.AdhocDir)
+	case mode == modeWatched:
+		recorded = key.FilePath(domain.WatchlistDir)
+	default:
+		recorded = key.FilePath("")
+	}
+
+	// The cached revision holds the values of local-only fields, and is what the pull
+	// changed, for the digest
+	var localOnly []string
+	if s.fieldDirections != nil {
+		localOnly = s.fieldDirections(key.ProjectKey()).LocalOnly()
+	}
+	var base *domain.Ticket
+	if s.activity != nil || len(localOnly) > 0 {
+		base, err = s.ticketRepo.FindByKey(ctx, key.String())
+		if errors.Is(err, domain.ErrNotFound) {
+			base = nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read cached %s: %w", key, err)
+		}
+	}
+	if base != nil {
+		ticket.KeepLocalFields(base, localOnly)
+	}
+
+	var version *domain.LocalVersion
+	if dirty && path != "" {
+		// Saved before anything is staged, so the local work survives whatever happens
+		// to the overwrite
+		if version, err = s.localVersions.SaveLocalVersion(ctx, key, path, ticket.Version()); err != nil {
+			return nil, fmt.Errorf("failed to save local version of %s: %w", key, err)
+		}
+	}
+
+	uow := s.newUnitOfWork()
+	if streamed {
+		err = s.stageStreamed(ctx, uow, ticket, recorded)
+	} else {
+		err = s.files.Stage(ctx, uow, ticket, recorded)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if version != nil {
+		uow.Stage(func(ctx context.Context) error {
+			return s.localVersionRepo.SaveLocalVersion(ctx, version)
+		})
+	}
+	uow.Stage(func(ctx context.Context) error {
+		if err := s.ticketRepo.Save(ctx, ticket); err != nil {
+			return fmt.Errorf("failed to cache %s: %w", key, err)
+		}
+		if history != nil {
+			if err := s.historyRepo.ReplaceStatusHistory(ctx, key.String(), history); err != nil {
+				return fmt.Errorf("failed to cache status history of %s: %w", key, err)
+			}
+		}
+		state.RecordSynced(ticket, s.now())
+		state.FilePath = recorded
+		state.PullOnly = mode == modeAdhoc
+		state.Watched = mode == modeWatched
+		return s.stateRepo.SaveTicketState(ctx, state)
+	})
+	if err := uow.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if s.activity != nil {
+		s.activity.Record(ctx, domain.PulledActivities(ticket, base, s.now())...)
+	}
+
+	created := path == ""
+	if created {
+		if path, err = s.files.Locate(ctx, key, recorded); err != nil {
+			return nil, err
+		}
+	}
+	result = &Result{Ticket: ticket, Path: path, Created: created, Adhoc: state.PullOnly}
+	if version != nil {
+		result.LocalVersion = version.Path
+	}
+	return result, nil
+}
+
+// ticketState returns the state the sync policy decides on for a ticket with local
+// changes, whose sync state is state, pulled from Jira as ticket.
+func (s *Service) ticketState(state *repository.TicketSyncState, ticket *domain.Ticket) *domain.TicketState {
+	localModified := state.LastModifiedLocal
+	if localModified.IsZero() {
+		// Changed at some point since the last sync; now is the latest it can have been
+		localModified = s.now()
+	}
+	modified := domain.NewSyncTimestamp(localModified)
+	return &domain.TicketState{
+		ProjectKey:    ticket.Key.ProjectKey(),
+		TicketKey:     ticket.Key,
+		JiraUpdated:   domain.NewSyncTimestamp(ticket.Updated),
+		LocalModified: &modified,
+		LastSynced:    domain.NewSyncTimestamp(state.LastSynced),
+		ContentHash:   state.ContentHash,
+		Status:        domain.SyncStatusLocalModified,
+	}
+}
+
+// fetch returns a ticket as Jira has it: the copy the fetched ticket cache holds at the
+// revision a search found it at, if found is set and there is one, or else the ticket
+// fetched from Jira, which is cached for the next search that finds it unchanged.
+func (s *Service) fetch(ctx context.Context, key domain.TicketKey, found *domain.Ticket) (*domain.Ticket, error) {
+	if found != nil {
+		if cached := s.cachedFetch(ctx, found); cached != nil {
+			return cached, nil
+		}
+	}
+	ticket, err := s.source.GetTicket(ctx, key.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull %s: %w", key, err)
+	}
+	if s.cachedFetch(ctx, ticket) == nil {
+		// Not replacing a copy cached with its comments
+		s.rememberFetch(ctx, ticket)
+	}
+	return ticket, nil
+}
+
+// pullComments sets the comments of ticket, just fetched from Jira with count comments
+// (-1 if unknown), to those cached when Jira last updated it at the same revision, or
+// else fetches and caches them. A ticket with more than streamCommentsOver comments is
+// left without them, and reported streamed: its comments are streamed into its file
+// instead (see stageStreamed).
+func (s *Service) pullComments(ctx context.Context, ticket *domain.Ticket, count int) (streamed bool, err error) {
+	if cached := s.cachedFetch(ctx, ticket); cached != nil && cached.Comments != nil {
+		ticket.Comments = cached.Comments
+		return false, nil
+	}
+	if count > streamCommentsOver {
+		return true, nil
+	}
+
+	comments, err := s.comments.FetchComments(ctx, ticket.Key.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to pull comments of %s: %w", ticket.Key, err)
+	}
+	// An empty list, unlike nil, clears the comments section
+	ticket.Comments = append(make([]*domain.Comment, 0, len(comments)), comments...)
+	s.rememberFetch(ctx, ticket)
+	return false, nil
+}
+
+// stageStreamed stages writing ticket's file at recorded with its comments streamed from
+// Jira a page at a time, adding the profiles of their authors to the ticket, downloading
+// the media they reference, and recording their states as they pass, so none of them is
+// held.
+func (s *Service) stageStreamed(ctx context.Context, uow repository.UnitOfWork, ticket *domain.Ticket, recorded string) error {
+	key := ticket.Key.String()
+	var states []domain.CommentState
+	err := s.files.StageStreamed(ctx, uow, ticket, recorded, func(fn func(comment *domain.Comment) error) error {
+		return s.comments.ForEachComment(ctx, key, func(comment *domain.Comment) error {
+			states = append(states, domain.NewCommentState(comment))
+			if s.authors {
+				ticket.AddAuthors(comment.AuthorProfile)
+			}
+			if s.media != nil {
+				commented := *ticket
+				commented.Description = ""
+				commented.Comments = []*domain.Comment{comment}
+				s.writeMedia(ctx, &commented)
+			}
+			return fn(comment)
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to pull comments of %s: %w", ticket.Key, err)
+	}
+	if s.commentStates != nil {
+		if err := s.commentStates.ReplaceCommentStates(ctx, key, states); err != nil {
+			s.logger.WarnContext(ctx, "failed to record comment states", "ticket_key", key, "error", err)
+		}
+	}
+	return nil
+}
+
+// writeMedia downloads the attachments ticket references when pulls download media,
+// logging failures: the file links them all the same, and the next pull retries them.
+func (s *Service) writeMedia(ctx context.Context, ticket *domain.Ticket) {
+	if s.media == nil {
+		return
+	}
+	if _, err := s.media.WriteMedia(ctx, ticket); err != nil {
+		s.logger.WarnContext(ctx, "failed to download media", "ticket_key", ticket.Key.String(), "error", err)
+	}
+}
+
+// commentsStale reports whether the comments of ticket, just fetched from Jira, must be
+// fetched because they may have changed since they were pulled at pulledAt (the Jira
+// revision last pulled), and how many comments it has in Jira (-1 if unknown). Without
+// comment states, or when they or the comment count cannot be read, they must.
+func (s *Service) commentsStale(ctx context.Context, ticket *domain.Ticket, pulledAt time.Time) (bool, int) {
+	if s.counter == nil {
+		return true, -1
+	}
+	key := ticket.Key.String()
+	count, err := s.counter.CountComments(ctx, key)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to count comments", "ticket_key", key, "error", err)
+		return true, -1
+	}
+	if s.commentStates == nil {
+		return true, count
+	}
+	recorded, err := s.commentStates.FindCommentStates(ctx, key)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to read comment states", "ticket_key", key, "error", err)
+		return true, count
+	}
+	return domain.CommentsStale(recorded, count, ticket.Updated, pulledAt), count
+}
+
+// recordComments records the state of the comments of ticket, just fetched from Jira, for
+// commentsStale, and returns how they changed since they were last pulled. Failures are
+// logged and reported as every comment being added, so the file is rewritten.
+func (s *Service) recordComments(ctx context.Context, ticket *domain.Ticket) domain.CommentChanges {
+	key := ticket.Key.String()
+	recorded, err := s.commentStates.FindCommentStates(ctx, key)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to read comment states", "ticket_key", key, "error", err)
+		recorded = nil
+	}
+	changes := domain.DiffComments(recorded, ticket.Comments)
+	if err == nil && changes.IsEmpty() {
+		return changes
+	}
+	if err := s.commentStates.ReplaceCommentStates(ctx, key, domain.CommentStates(ticket.Comments)); err != nil {
+		s.logger.WarnContext(ctx, "failed to record comment states", "ticket_key", key, "error", err)
+	}
+	return changes
+}
+
+// cachedFetch returns the cached copy of ticket, fetched when Jira last updated it at
+// the same revision, or nil if there is none. Cache failures are logged and treated as
+// misses.
+func (s *Service) cachedFetch(ctx context.Context, ticket *domain.Ticket) *domain.Ticket {
+	if s.fetched == nil || ticket.Updated.IsZero() {
+		return nil
+	}
+	cached, err := s.fetched.FindFetchedTicket(ctx, ticket.Key.String(), ticket.Updated)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			s.logger.WarnContext(ctx, "failed to read fetched ticket cache", "ticket_key", ticket.Key.String(), "error", err)
+		}
+		return nil
+	}
+	return cached
+}
+
+// rememberFetch caches ticket, fetched from Jira with its comments, for cachedFetch.
+// Cache failures are logged: the next pull fetches the comments again.
+func (s *Service) rememberFetch(ctx context.Context, ticket *domain.Ticket) {
+	if s.fetched == nil || ticket.Updated.IsZero() {
+		return
+	}
+	if err := s.fetched.SaveFetchedTicket(ctx, ticket); err != nil {
+		s.logger.WarnContext(ctx, "failed to cache fetched ticket", "ticket_key", ticket.Key.String(), "error", err)
+	}
+}
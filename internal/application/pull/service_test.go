@@ -0,0 +1,575 @@
+package pull
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/domain/repository/fakes"
+)
+
+// fakeJira serves tickets and their comments, counting the ticket and comment fetches.
+type fakeJira struct {
+	tickets  map[string]*domain.Ticket
+	comments map[string][]*domain.Comment
+
+	ticketFetches  int
+	commentFetches int
+	commentStreams int
+}
+
+func (j *fakeJira) GetTicket(ctx context.Context, key string) (*domain.Ticket, error) {
+	j.ticketFetches++
+	ticket, ok := j.tickets[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, key)
+	}
+	c := *ticket
+	return &c, nil
+}
+
+func (j *fakeJira) FetchComments(ctx context.Context, ticketKey string) ([]*domain.Comment, error) {
+	j.commentFetches++
+	return j.comments[ticketKey], nil
+}
+
+func (j *fakeJira) ForEachComment(ctx context.Context, ticketKey string, fn func(comment *domain.Comment) error) error {
+	j.commentStreams++
+	for _, comment := range j.comments[ticketKey] {
+		if err := fn(comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *fakeJira) CountComments(ctx context.Context, ticketKey string) (int, error) {
+	return len(j.comments[ticketKey]), nil
+}
+
+// fakeFiles writes each ticket file as the comments section it would have.
+type fakeFiles struct {
+	files map[string]string
+}
+
+func (f *fakeFiles) Locate(ctx context.Context, key domain.TicketKey, recorded string) (string, error) {
+	if _, ok := f.files[recorded]; ok {
+		return recorded, nil
+	}
+	return "", nil
+}
+
+func (f *fakeFiles) Rel(path string) (string, error) {
+	return path, nil
+}
+
+func (f *fakeFiles) Stage(ctx context.Context, uow repository.UnitOfWork, ticket *domain.Ticket, recorded string) error {
+	content := f.files[recorded]
+	if ticket.Comments != nil {
+		content = ""
+		for _, comment := range ticket.Comments {
+			content += comment.Body + "\n"
+		}
+	}
+	uow.WriteFile(recorded, []byte(content))
+	return nil
+}
+
+func (f *fakeFiles) StageStreamed(ctx context.Context, uow repository.UnitOfWork, ticket *domain.Ticket, recorded string, each func(fn func(comment *domain.Comment) error) error) error {
+	var content strings.Builder
+	err := each(func(comment *domain.Comment) error {
+		content.WriteString(comment.Body + "\n")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	uow.WriteFile(recorded, []byte(content.String()))
+	return nil
+}
+
+// fakeUnitOfWork applies the staged changes in order when committed.
+type fakeUnitOfWork struct {
+	files  *fakeFiles
+	writes map[string]string
+	staged []func(ctx context.Context) error
+}
+
+func (u *fakeUnitOfWork) WriteFile(path string, content []byte) {
+	u.writes[path] = string(content)
+}
+
+func (u *fakeUnitOfWork) RemoveFile(path string) {
+	u.writes[path] = ""
+}
+
+func (u *fakeUnitOfWork) Stage(fn func(ctx context.Context) error) {
+	u.staged = append(u.staged, fn)
+}
+
+func (u *fakeUnitOfWork) Commit(ctx context.Context) error {
+	for _, fn := range u.staged {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	for path, content := range u.writes {
+		u.files.files[path] = content
+	}
+	return nil
+}
+
+// newTestService returns a pull service of project JMD over jira and files.
+func newTestService(jira *fakeJira, files *fakeFiles, states *fakes.StateRepository) *Service {
+	return NewService(jira, states, fakes.NewTicketRepository(), files,
+		func() repository.UnitOfWork {
+			return &fakeUnitOfWork{files: files, writes: make(map[string]string)}
+		}, "JMD")
+}
+
+// testTicket returns ticket key as last updated in Jira at updated.
+func testTicket(t *testing.T, key string, updated time.Time) *domain.Ticket {
+	t.Helper()
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		t.Fatalf("NewTicketKey(%s) failed: %v", key, err)
+	}
+	return domain.NewTicket(ticketKey, "Ticket "+key, updated, updated)
+}
+
+func TestService_Pull_FetchedTickets(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := testTicket(t, "JMD-1", updated)
+	jira := &fakeJira{
+		tickets:  map[string]*domain.Ticket{"JMD-1": ticket},
+		comments: map[string][]*domain.Comment{"JMD-1": {{ID: "1", TicketKey: ticket.Key, Body: "First"}}},
+	}
+	files := &fakeFiles{files: make(map[string]string)}
+	fetched := fakes.NewFetchedTicketRepository()
+	service := newTestService(jira, files, fakes.NewStateRepository()).
+		WithComments(jira).
+		WithFetchedTickets(fetched)
+	ctx := context.Background()
+
+	pull := func(wantFetches int, wantFile string) {
+		t.Helper()
+		result, err := service.Pull(ctx, "JMD-1", false)
+		if err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+		if jira.commentFetches != wantFetches {
+			t.Errorf("comments fetched %d times, want %d", jira.commentFetches, wantFetches)
+		}
+		if got := files.files[result.Path]; got != wantFile {
+			t.Errorf("file = %q, want %q", got, wantFile)
+		}
+	}
+
+	pull(1, "First\n")
+	if _, err := fetched.FindFetchedTicket(ctx, "JMD-1", updated); err != nil {
+		t.Errorf("pulled ticket is not cached: %v", err)
+	}
+
+	// Unchanged in Jira, so the cached comments are reused
+	pull(1, "First\n")
+
+	// A new comment updates the ticket, so its comments are fetched again
+	jira.tickets["JMD-1"] = testTicket(t, "JMD-1", updated.Add(time.Hour))
+	jira.comments["JMD-1"] = append(jira.comments["JMD-1"], &domain.Comment{ID: "2", TicketKey: ticket.Key, Body: "Second"})
+	pull(2, "First\nSecond\n")
+
+	// A cache that fails is skipped
+	fetched.FailWith(fakes.AnyMethod, domain.ErrUnavailable)
+	pull(3, "First\nSecond\n")
+}
+
+func TestService_PullFound(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := testTicket(t, "JMD-1", updated)
+	jira := &fakeJira{tickets: map[string]*domain.Ticket{"JMD-1": ticket}}
+	files := &fakeFiles{files: make(map[string]string)}
+	service := newTestService(jira, files, fakes.NewStateRepository()).WithFetchedTickets(fakes.NewFetchedTicketRepository())
+	ctx := context.Background()
+
+	pullFound := func(found *domain.Ticket, force, wantPulled bool, wantFetches int) {
+		t.Helper()
+		pulled, err := service.PullFound(ctx, found, force)
+		if err != nil {
+			t.Fatalf("PullFound failed: %v", err)
+		}
+		if pulled != wantPulled {
+			t.Errorf("pulled = %v, want %v", pulled, wantPulled)
+		}
+		if jira.ticketFetches != wantFetches {
+			t.Errorf("ticket fetched %d times, want %d", jira.ticketFetches, wantFetches)
+		}
+	}
+
+	pullFound(ticket, false, true, 1)
+	if _, ok := files.files["JMD-1.md"]; !ok {
+		t.Fatalf("files = %v, want JMD-1.md written", files.files)
+	}
+
+	// Found at the revision already pulled, so Jira is not asked again
+	pullFound(ticket, false, false, 1)
+
+	// Forced, the file is rewritten from the cached fetch of that revision
+	delete(files.files, "JMD-1.md")
+	pullFound(ticket, true, true, 1)
+	if _, ok := files.files["JMD-1.md"]; !ok {
+		t.Errorf("files = %v, want JMD-1.md rewritten", files.files)
+	}
+
+	// Found at a newer revision, so its details are fetched
+	jira.tickets["JMD-1"] = testTicket(t, "JMD-1", updated.Add(time.Hour))
+	pullFound(jira.tickets["JMD-1"], false, true, 2)
+}
+
+func TestService_Pull_CommentStates(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := testTicket(t, "JMD-1", updated)
+	jira := &fakeJira{
+		tickets: map[string]*domain.Ticket{"JMD-1": ticket},
+		comments: map[string][]*domain.Comment{"JMD-1": {
+			{ID: "1", TicketKey: ticket.Key, Body: "First", Updated: updated},
+			{ID: "2", TicketKey: ticket.Key, Body: "Second", Updated: updated},
+		}},
+	}
+	files := &fakeFiles{files: make(map[string]string)}
+	service := newTestService(jira, files, fakes.NewStateRepository()).
+		WithComments(jira).
+		WithCommentStates(fakes.NewCommentStateRepository(), jira)
+	ctx := context.Background()
+
+	pull := func(wantFetches int, wantFile string) {
+		t.Helper()
+		result, err := service.Pull(ctx, "JMD-1", false)
+		if err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+		if jira.commentFetches != wantFetches {
+			t.Errorf("comments fetched %d times, want %d", jira.commentFetches, wantFetches)
+		}
+		if got := files.files[result.Path]; got != wantFile {
+			t.Errorf("file = %q, want %q", got, wantFile)
+		}
+	}
+
+	pull(1, "First\nSecond\n")
+
+	// Neither updated nor recounted in Jira, so the comments section is left alone
+	files.files["JMD-1.md"] = "First\nSecond\nkept\n"
+	pull(1, "First\nSecond\nkept\n")
+
+	// Updated in Jira without touching the comments: fetched, but nothing to rewrite
+	jira.tickets["JMD-1"] = testTicket(t, "JMD-1", updated.Add(time.Hour))
+	pull(2, "First\nSecond\nkept\n")
+
+	// Deleting a comment does not update the ticket, but changes the count
+	jira.comments["JMD-1"] = jira.comments["JMD-1"][:1]
+	pull(3, "First\n")
+}
+
+// fakeSaver saves local versions as copies of the fake files.
+type fakeSaver struct {
+	files *fakeFiles
+	saved map[string]string
+}
+
+func (f *fakeSaver) SaveLocalVersion(ctx context.Context, key domain.TicketKey, path, jiraVersion string) (*domain.LocalVersion, error) {
+	content, ok := f.files.files[path]
+	if !ok {
+		return nil, nil
+	}
+	savedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	saved := domain.LocalVersionPath(key, savedAt)
+	f.saved[saved] = content
+	return domain.NewLocalVersion(key, saved, []byte(content), jiraVersion, savedAt), nil
+}
+
+func TestService_Pull_DirtyOverwrite(t *testing.T) {
+	synced := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	dirty := func() *repository.TicketSyncState {
+		return &repository.TicketSyncState{
+			TicketKey:         "JMD-1",
+			LastSynced:        synced,
+			LastModifiedJira:  synced,
+			LastModifiedLocal: synced.Add(time.Hour),
+			IsDirty:           true,
+			FilePath:          "JMD-1.md",
+		}
+	}
+	tests := []struct {
+		name        string
+		conflicts   domain.ConflictStrategy
+		jiraUpdated time.Time
+		noVersions  bool
+		wantErr     error
+	}{
+		{name: "nowhere to save the local version", conflicts: domain.ConflictJiraWins, jiraUpdated: synced.Add(2 * time.Hour), noVersions: true, wantErr: domain.ErrConflict},
+		{name: "manual conflicts", conflicts: domain.ConflictManual, jiraUpdated: synced.Add(2 * time.Hour), wantErr: domain.ErrConflict},
+		{name: "jira wins", conflicts: domain.ConflictJiraWins, jiraUpdated: synced.Add(2 * time.Hour)},
+		{name: "local changes newer", conflicts: domain.ConflictNewestWins, jiraUpdated: synced.Add(30 * time.Minute), wantErr: domain.ErrConflict},
+		{name: "jira unchanged", conflicts: domain.ConflictJiraWins, jiraUpdated: synced, wantErr: domain.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := testTicket(t, "JMD-1", tt.jiraUpdated)
+			ticket.Summary = "Jira version"
+			jira := &fakeJira{
+				tickets:  map[string]*domain.Ticket{"JMD-1": ticket},
+				comments: map[string][]*domain.Comment{"JMD-1": {{ID: "1", TicketKey: ticket.Key, Body: "From Jira"}}},
+			}
+			files := &fakeFiles{files: map[string]string{"JMD-1.md": "Local edit\n"}}
+			states := fakes.NewStateRepository(dirty())
+			saver := &fakeSaver{files: files, saved: make(map[string]string)}
+			versions := fakes.NewLocalVersionRepository()
+			service := newTestService(jira, files, states).
+				WithComments(jira).
+				WithPolicy(domain.SyncPolicy{Conflicts: tt.conflicts})
+			if !tt.noVersions {
+				service.WithLocalVersions(saver, versions)
+			}
+			ctx := context.Background()
+
+			result, err := service.Pull(ctx, "JMD-1", false)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Pull() error = %v, want %v", err, tt.wantErr)
+				}
+				if got := files.files["JMD-1.md"]; got != "Local edit\n" {
+					t.Errorf("file = %q, want the local edit kept", got)
+				}
+				if len(saver.saved) != 0 {
+					t.Errorf("saved %d local versions, want none", len(saver.saved))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Pull failed: %v", err)
+			}
+
+			if got := files.files["JMD-1.md"]; got != "From Jira\n" {
+				t.Errorf("file = %q, want the Jira version", got)
+			}
+			if result.LocalVersion == "" || saver.saved[result.LocalVersion] != "Local edit\n" {
+				t.Errorf("LocalVersion = %q holding %q, want the local edit", result.LocalVersion, saver.saved[result.LocalVersion])
+			}
+			recorded, err := versions.FindLocalVersions(ctx, "JMD-1")
+			if err != nil || len(recorded) != 1 || recorded[0].Path != result.LocalVersion {
+				t.Errorf("recorded local versions = %v, %v, want %s", recorded, err, result.LocalVersion)
+			}
+			state, err := states.GetTicketState(ctx, "JMD-1")
+			if err != nil || state.IsDirty {
+				t.Errorf("state after overwrite = %+v, %v, want clean", state, err)
+			}
+		})
+	}
+}
+
+func TestService_Pull_LocalOnlyFields(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	remote := testTicket(t, "JMD-1", updated.Add(time.Hour))
+	remote.Summary = "Summary from Jira"
+	remote.Description = "Description from Jira"
+	jira := &fakeJira{tickets: map[string]*domain.Ticket{"JMD-1": remote}}
+
+	cached := testTicket(t, "JMD-1", updated)
+	cached.Description = "Private notes"
+	tickets := fakes.NewTicketRepository(cached)
+	files := &fakeFiles{files: make(map[string]string)}
+	sync := domain.SyncConfig{FieldDirections: domain.FieldDirections{"description": domain.SyncLocalOnly}}
+	service := NewService(jira, fakes.NewStateRepository(), tickets, files,
+		func() repository.UnitOfWork {
+			return &fakeUnitOfWork{files: files, writes: make(map[string]string)}
+		}, "JMD").WithFieldDirections(sync.FieldDirectionsFor)
+	ctx := context.Background()
+
+	result, err := service.Pull(ctx, "JMD-1", false)
+	if err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+
+	// The local-only description survives; the rest comes from Jira
+	saved, err := tickets.FindByKey(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindByKey failed: %v", err)
+	}
+	for name, ticket := range map[string]*domain.Ticket{"result": result.Ticket, "cache": saved} {
+		if ticket.Description != "Private notes" || ticket.Summary != "Summary from Jira" {
+			t.Errorf("%s = %q, %q, want Jira's summary and the local description", name, ticket.Summary, ticket.Description)
+		}
+	}
+}
+
+func TestService_Pull_CommentAuthors(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := testTicket(t, "JMD-1", updated)
+	ana := &domain.User{DisplayName: "Ana Lima", Email: "ana@example.com"}
+	jira := &fakeJira{
+		tickets:  map[string]*domain.Ticket{"JMD-1": ticket},
+		comments: map[string][]*domain.Comment{"JMD-1": {{ID: "1", TicketKey: ticket.Key, Author: ana.Name(), AuthorProfile: ana, Body: "First"}}},
+	}
+	files := &fakeFiles{files: make(map[string]string)}
+	service := newTestService(jira, files, fakes.NewStateRepository()).
+		WithComments(jira).
+		WithAuthors(true)
+
+	result, err := service.Pull(context.Background(), "JMD-1", false)
+	if err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	if author, ok := result.Ticket.Author("ana@example.com"); !ok || author.DisplayName != "Ana Lima" {
+		t.Errorf("Author(ana@example.com) = %+v, %v, want the comment author's profile", author, ok)
+	}
+}
+
+// recordedMedia is a MediaWriter recording the texts of the tickets it is given, failing
+// with err.
+type recordedMedia struct {
+	texts []string
+	err   error
+}
+
+func (m *recordedMedia) WriteMedia(ctx context.Context, ticket *domain.Ticket) (int, error) {
+	m.texts = append(m.texts, ticket.Description)
+	for _, comment := range ticket.Comments {
+		m.texts = append(m.texts, comment.Body)
+	}
+	return len(m.texts), m.err
+}
+
+func TestService_Pull_Media(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := testTicket(t, "JMD-1", updated)
+	ticket.Description = "See !shot.png!"
+	jira := &fakeJira{
+		tickets:  map[string]*domain.Ticket{"JMD-1": ticket},
+		comments: map[string][]*domain.Comment{"JMD-1": {{ID: "1", TicketKey: ticket.Key, Body: "And !logs.txt!"}}},
+	}
+	media := &recordedMedia{err: errors.New("attachment unavailable")}
+	service := newTestService(jira, &fakeFiles{files: make(map[string]string)}, fakes.NewStateRepository()).
+		WithComments(jira).
+		WithMedia(media)
+
+	// A failed download does not fail the pull
+	if _, err := service.Pull(context.Background(), "JMD-1", false); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	if strings.Join(media.texts, "|") != "See !shot.png!|And !logs.txt!" {
+		t.Errorf("media written for %q, want the description and the comment", media.texts)
+	}
+}
+
+func TestService_Pull_StreamedComments(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := testTicket(t, "JMD-1", updated)
+	ana := &domain.User{DisplayName: "Ana Lima", Email: "ana@example.com"}
+	var comments []*domain.Comment
+	var want strings.Builder
+	for i := 1; i <= streamCommentsOver+1; i++ {
+		body := fmt.Sprintf("Comment %d", i)
+		comments = append(comments, &domain.Comment{ID: fmt.Sprint(i), TicketKey: ticket.Key, Author: ana.Name(), AuthorProfile: ana, Body: body})
+		want.WriteString(body + "\n")
+	}
+	jira := &fakeJira{
+		tickets:  map[string]*domain.Ticket{"JMD-1": ticket},
+		comments: map[string][]*domain.Comment{"JMD-1": comments},
+	}
+	files := &fakeFiles{files: make(map[string]string)}
+	commentStates := fakes.NewCommentStateRepository()
+	media := &recordedMedia{}
+	service := newTestService(jira, files, fakes.NewStateRepository()).
+		WithComments(jira).
+		WithCommentStates(commentStates, jira).
+		WithAuthors(true).
+		WithMedia(media)
+	ctx := context.Background()
+
+	result, err := service.Pull(ctx, "JMD-1", false)
+	if err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+
+	// Too many to hold, so they are streamed into the file
+	if jira.commentFetches != 0 || jira.commentStreams != 1 {
+		t.Errorf("comments fetched %d times and streamed %d times, want streamed once", jira.commentFetches, jira.commentStreams)
+	}
+	if got := files.files[result.Path]; got != want.String() {
+		t.Errorf("file has %d lines, want %d", strings.Count(got, "\n"), len(comments))
+	}
+	if _, ok := result.Ticket.Author("ana@example.com"); !ok {
+		t.Error("streamed comment author was not recorded")
+	}
+	if last := fmt.Sprintf("Comment %d", len(comments)); !slices.Contains(media.texts, last) {
+		t.Errorf("media not written for streamed %q", last)
+	}
+	states, err := commentStates.FindCommentStates(ctx, "JMD-1")
+	if err != nil || len(states) != len(comments) {
+		t.Errorf("recorded %d comment states (%v), want %d", len(states), err, len(comments))
+	}
+
+	// Recorded as streamed, so an unchanged ticket is not streamed again
+	if _, err := service.Pull(ctx, "JMD-1", false); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	if jira.commentStreams != 1 {
+		t.Errorf("comments streamed %d times, want once", jira.commentStreams)
+	}
+}
+
+// statusHistory serves and caches status histories by ticket key.
+type statusHistory struct {
+	jira   map[string][]domain.StatusChange
+	cached map[string][]domain.StatusChange
+}
+
+func (h *statusHistory) FetchStatusHistory(ctx context.Context, ticketKey string) ([]domain.StatusChange, error) {
+	return append([]domain.StatusChange{}, h.jira[ticketKey]...), nil
+}
+
+func (h *statusHistory) ReplaceStatusHistory(ctx context.Context, ticketKey string, history []domain.StatusChange) error {
+	h.cached[ticketKey] = history
+	return nil
+}
+
+func (h *statusHistory) FindStatusHistory(ctx context.Context, ticketKey string) ([]domain.StatusChange, error) {
+	return h.cached[ticketKey], nil
+}
+
+func (h *statusHistory) FindAllStatusHistory(ctx context.Context) (map[string][]domain.StatusChange, error) {
+	return h.cached, nil
+}
+
+var _ repository.StatusHistoryRepository = (*statusHistory)(nil)
+
+func TestService_Pull_StatusHistory(t *testing.T) {
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	jira := &fakeJira{tickets: map[string]*domain.Ticket{"JMD-1": testTicket(t, "JMD-1", updated)}}
+	history := &statusHistory{
+		jira: map[string][]domain.StatusChange{"JMD-1": {
+			{ID: "1", From: "To Do", To: "In Progress", At: updated.Add(-2 * time.Hour)},
+			{ID: "2", From: "In Progress", To: "Done", At: updated},
+		}},
+		cached: make(map[string][]domain.StatusChange),
+	}
+	files := &fakeFiles{files: make(map[string]string)}
+	service := newTestService(jira, files, fakes.NewStateRepository()).WithStatusHistory(history, history)
+
+	if _, err := service.Pull(context.Background(), "JMD-1", false); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	cached, err := history.FindStatusHistory(context.Background(), "JMD-1")
+	if err != nil {
+		t.Fatalf("FindStatusHistory failed: %v", err)
+	}
+	if len(cached) != 2 || cached[1].To != "Done" {
+		t.Errorf("cached status history = %+v, want the 2 changes from Jira", cached)
+	}
+}
@@ -0,0 +1,501 @@
+// Package push contains the use case for draining the push queue into Jira.
+// This layer orchestrates domain logic and depends only on domain interfaces.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	gosync "sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/application/progress"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// DefaultConcurrency is how many tickets are pushed in parallel when none is configured.
+const DefaultConcurrency = 4
+
+// MinBulkSize is the fewest identical operations applied with one BulkApplier.ApplyBulk
+// call; smaller groups are applied one by one.
+const MinBulkSize = 10
+
+// Applier applies a single queued operation to Jira.
+// The error it returns is classified with domain.ClassifyError to decide whether the
+// operation is retried.
+type Applier interface {
+	Apply(ctx context.Context, op *domain.PendingOperation) error
+}
+
+// BulkApplier is an Applier that can also apply the same change to many tickets at once,
+// e.g. through Jira's bulk edit API.
+type BulkApplier interface {
+	Applier
+
+	// Bulkable reports whether op may be applied together with other operations that
+	// have the same Operation and Payload.
+	Bulkable(op *domain.PendingOperation) bool
+
+	// ApplyBulk applies operations that share their Operation and Payload. failures[i]
+	// is the error for ops[i], or nil if it was applied; err is set when the whole
+	// request failed.
+	ApplyBulk(ctx context.Context, ops []*domain.PendingOperation) (failures []error, err error)
+}
+
+// AuthGate pauses pushes while Jira rejects the credentials. It is satisfied by
+// auth.Monitor.
+type AuthGate interface {
+	// Paused reports whether pushes should wait for the credentials to be fixed.
+	Paused() bool
+}
+
+// Report summarizes one pass over the push queue.
+type Report struct {
+	// Applied is how many operations were applied to Jira
+	Applied int
+
+	// Retrying is how many operations failed and will be retried by a later pass
+	Retrying int
+
+	// Failed is how many operations failed and will not be retried
+	Failed int
+
+	// Deferred is how many operations were left queued because they, or an earlier
+	// operation of the same ticket, are waiting out a retry backoff, because Jira
+	// rejected the credentials, or because pushing is disabled by the sync mode
+	Deferred int
+}
+
+// add adds the counts of other to r.
+func (r *Report) add(other Report) {
+	r.Applied += other.Applied
+	r.Retrying += other.Retrying
+	r.Failed += other.Failed
+	r.Deferred += other.Deferred
+}
+
+// Service drains the push queue.
+//
+// Operations of one ticket are applied strictly in the order they were queued: an
+// operation that fails and will be retried holds back the rest of its ticket's queue.
+// Different tickets are independent and are pushed in parallel, higher priority
+// operations first (see domain.PlanQueue). When the applier is a BulkApplier and at
+// least MinBulkSize tickets have the same change up next, that change is applied to
+// all of them in one bulk request first.
+//
+// An operation Jira rejects because the credentials are invalid (401) stays queued
+// without using up an attempt, and while the AuthGate is paused nothing is pushed.
+// In pull-only mode nothing is pushed either; operations stay queued until the mode changes.
+type Service struct {
+	queue       repository.PendingOperationRepository
+	applier     Applier
+	locks       repository.LockManager
+	states      repository.StateRepository
+	authGate    AuthGate
+	mode        domain.SyncMode
+	policy      domain.RetryPolicy
+	concurrency int
+	progress    progress.Progress
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+// NewService creates a new push service that applies operations with applier and retries
+// them according to policy. Each ticket's operations are applied while holding its lock
+// from locks, which may be nil when nothing else touches the tickets concurrently.
+func NewService(
+	queue repository.PendingOperationRepository,
+	applier Applier,
+	locks repository.LockManager,
+	policy domain.RetryPolicy,
+	logger *slog.Logger,
+) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{
+		queue:       queue,
+		applier:     applier,
+		locks:       locks,
+		mode:        domain.SyncModeBidirectional,
+		policy:      policy,
+		concurrency: DefaultConcurrency,
+		progress:    progress.Nop(),
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// WithConcurrency sets how many tickets are pushed in parallel (at least one).
+func (s *Service) WithConcurrency(n int) *Service {
+	if n < 1 {
+		n = 1
+	}
+	s.concurrency = n
+	return s
+}
+
+// WithStates sets where the sync state of tickets is kept: a ticket whose queued
+// operations were all applied is no longer marked dirty, so pulls update it again
+// (nil leaves the marks as they are).
+func (s *Service) WithStates(states repository.StateRepository) *Service {
+	s.states = states
+	return s
+}
+
+// WithAuthGate sets the gate that pauses pushes while the credentials fail (nil never pauses).
+func (s *Service) WithAuthGate(gate AuthGate) *Service {
+	s.authGate = gate
+	return s
+}
+
+// WithMode sets the sync mode; in pull-only mode drains push nothing.
+func (s *Service) WithMode(mode domain.SyncMode) *Service {
+	if mode != "" {
+		s.mode = mode
+	}
+	return s
+}
+
+// paused reports whether the auth gate is holding pushes back.
+func (s *Service) paused() bool {
+	return s.authGate != nil && s.authGate.Paused()
+}
+
+// WithProgress sets where drains report their progress, counted in queued operations
+// (nil reports nothing).
+func (s *Service) WithProgress(p progress.Progress) *Service {
+	s.progress = progress.OrNop(p)
+	return s
+}
+
+// Drain makes one pass over the project's queued operations.
+// Failures of individual operations are recorded on the operations and counted in the
+// report; the returned error reports only failures to read or update the queue.
+// The pass runs under the correlation ID of ctx, or a new one, and each operation is
+// applied and logged under its own (see domain.PendingOperation.CorrelationID).
+func (s *Service) Drain(ctx context.Context, projectKey string) (*Report, error) {
+	ctx = domain.EnsureCorrelationID(ctx)
+	ops, err := s.queue.FindByProject(ctx, projectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read push queue: %w", err)
+	}
+
+	var (
+		mu     gosync.Mutex
+		report Report
+		errs   []error
+		wg     gosync.WaitGroup
+	)
+	lanes := domain.PlanQueue(ops)
+
+	total := 0
+	for _, lane := range lanes {
+		total += len(lane.Operations)
+	}
+	s.progress.Start(fmt.Sprintf("Pushing %s", projectKey), total)
+	defer s.progress.Finish()
+
+	if s.paused() {
+		s.logger.WarnContext(ctx, "push paused: Jira rejected the configured credentials",
+			"project_key", projectKey,
+			"deferred", total)
+		return &Report{Deferred: total}, nil
+	}
+	if !s.mode.CanPush() {
+		if total > 0 {
+			s.logger.WarnContext(ctx, "push disabled: local changes stay queued while sync.mode is "+string(s.mode),
+				"project_key", projectKey,
+				"deferred", total)
+		}
+		return &Report{Deferred: total}, nil
+	}
+
+	var bulkApplied map[int64]bool
+	if bulk, ok := s.applier.(BulkApplier); ok {
+		var bulkReport Report
+		bulkApplied, bulkReport, err = s.applyBulk(ctx, bulk, lanes)
+		report.add(bulkReport)
+		if err != nil {
+			return &report, err
+		}
+	}
+
+	slots := make(chan struct{}, s.concurrency)
+
+	// Lanes are started in priority order, so with more lanes than slots the
+	// higher priority ones run first
+	for _, lane := range lanes {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return &report, errors.Join(append(errs, ctx.Err())...)
+		}
+
+		wg.Add(1)
+		go func(lane domain.QueueLane) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			laneReport, err := s.drainLane(ctx, lane, bulkApplied)
+			s.progress.Advance(len(lane.Operations))
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.add(laneReport)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}(lane)
+	}
+	wg.Wait()
+
+	s.logger.InfoContext(ctx, "drained push queue",
+		"project_key", projectKey,
+		"applied", report.Applied,
+		"retrying", report.Retrying,
+		"failed", report.Failed,
+		"deferred", report.Deferred)
+
+	return &report, errors.Join(errs...)
+}
+
+// drainLane applies a ticket's operations in order, stopping at the first one that
+// has to wait for a retry. Operations in bulkApplied were already attempted by this pass.
+func (s *Service) drainLane(ctx context.Context, lane domain.QueueLane, bulkApplied map[int64]bool) (report Report, err error) {
+	// Ticket creations have no key to lock until Jira assigns one
+	if s.locks != nil && !lane.TicketKey.IsZero() {
+		unlock, err := s.locks.Lock(ctx, lane.TicketKey.String())
+		if err != nil {
+			return report, fmt.Errorf("failed to lock ticket %s: %w", lane.TicketKey, err)
+		}
+		defer func() {
+			err = errors.Join(err, unlock())
+		}()
+	}
+
+	for i, op := range lane.Operations {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		// Operations that were given up on no longer hold back the ticket
+		if !op.ShouldRetryWith(s.policy) {
+			continue
+		}
+
+		if s.paused() {
+			report.Deferred += len(lane.Operations) - i
+			return report, nil
+		}
+
+		if bulkApplied[op.ID] {
+			if op.IsCompleted() && i == len(lane.Operations)-1 {
+				break
+			}
+			report.Deferred += len(lane.Operations) - i - 1
+			return report, nil
+		}
+
+		if s.now().Before(op.NextAttemptAt(s.policy)) {
+			report.Deferred += len(lane.Operations) - i
+			return report, nil
+		}
+
+		opCtx := domain.WithCorrelationID(ctx, op.CorrelationID)
+		if err := s.record(opCtx, op, s.applier.Apply(opCtx, op), &report); err != nil {
+			return report, err
+		}
+		if op.ShouldRetryWith(s.policy) {
+			report.Deferred += len(lane.Operations) - i - 1
+			return report, nil
+		}
+	}
+
+	for _, op := range lane.Operations {
+		if !op.IsCompleted() {
+			return report, nil
+		}
+	}
+	return report, s.markPushed(ctx, lane.TicketKey)
+}
+
+// markPushed clears the dirty mark of a ticket whose queued operations were all applied.
+func (s *Service) markPushed(ctx context.Context, key domain.TicketKey) error {
+	if s.states == nil || key.IsZero() {
+		return nil
+	}
+	state, err := s.states.GetTicketState(ctx, key.String())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get state of %s: %w", key, err)
+	}
+	if !state.IsDirty {
+		return nil
+	}
+	state.IsDirty = false
+	if err := s.states.SaveTicketState(ctx, state); err != nil {
+		return fmt.Errorf("failed to save state of %s: %w", key, err)
+	}
+	return nil
+}
+
+// record saves the outcome of an attempt to apply op and counts it in report.
+// An attempt cut short by ctx or rejected for invalid credentials is not recorded, so
+// it does not use up the operation's retries.
+func (s *Service) record(ctx context.Context, op *domain.PendingOperation, applyErr error, report *Report) error {
+	if applyErr != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// Invalid credentials say nothing about the operation, so it keeps its attempts
+	if domain.IsAuthFailure(applyErr) {
+		s.logger.WarnContext(ctx, "push rejected by Jira authentication, will retry",
+			"id", op.ID,
+			"ticket_key", op.TicketKey.String(),
+			"operation", op.Operation,
+			"author", op.Author,
+			"error", applyErr)
+		report.Deferred++
+		return nil
+	}
+
+	if applyErr == nil {
+		op.Complete(s.now())
+	} else {
+		op.RecordFailure(applyErr, s.now(), s.policy)
+	}
+	if err := s.queue.Update(ctx, op); err != nil {
+		return fmt.Errorf("failed to update operation %d: %w", op.ID, err)
+	}
+
+	switch {
+	case applyErr == nil:
+		report.Applied++
+	case op.ShouldRetryWith(s.policy):
+		s.logger.WarnContext(ctx, "push failed, will retry",
+			"id", op.ID,
+			"ticket_key", op.TicketKey.String(),
+			"operation", op.Operation,
+			"author", op.Author,
+			"attempts", op.Attempts,
+			"error", applyErr)
+		report.Retrying++
+	default:
+		s.logger.ErrorContext(ctx, "push failed, giving up",
+			"id", op.ID,
+			"ticket_key", op.TicketKey.String(),
+			"operation", op.Operation,
+			"author", op.Author,
+			"attempts", op.Attempts,
+			"error", applyErr)
+		report.Failed++
+	}
+	return nil
+}
+
+// bulkKey identifies operations that make the same change.
+type bulkKey struct {
+	operation domain.OperationType
+	payload   string
+}
+
+// applyBulk applies the next operation of each lane in bulk wherever at least MinBulkSize
+// lanes have the same change up next, and returns the IDs of the operations it attempted.
+func (s *Service) applyBulk(ctx context.Context, bulk BulkApplier, lanes []domain.QueueLane) (map[int64]bool, Report, error) {
+	var report Report
+	groups := make(map[bulkKey][]*domain.PendingOperation)
+	var order []bulkKey
+	for _, lane := range lanes {
+		op := s.nextDue(lane)
+		if op == nil || lane.TicketKey.IsZero() || !bulk.Bulkable(op) {
+			continue
+		}
+		key := bulkKey{operation: op.Operation, payload: op.Payload}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], op)
+	}
+
+	attempted := make(map[int64]bool)
+	for _, key := range order {
+		ops := groups[key]
+		if len(ops) < MinBulkSize {
+			continue
+		}
+		if err := s.applyBulkGroup(ctx, bulk, ops, &report); err != nil {
+			return attempted, report, err
+		}
+		for _, op := range ops {
+			attempted[op.ID] = true
+		}
+	}
+	return attempted, report, nil
+}
+
+// applyBulkGroup applies operations with the same change in one bulk request, holding the
+// locks of all their tickets.
+func (s *Service) applyBulkGroup(ctx context.Context, bulk BulkApplier, ops []*domain.PendingOperation, report *Report) (err error) {
+	if s.locks != nil {
+		// Locking in key order keeps two bulk pushes from deadlocking each other
+		keys := make([]string, 0, len(ops))
+		for _, op := range ops {
+			keys = append(keys, op.TicketKey.String())
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			unlock, err := s.locks.Lock(ctx, key)
+			if err != nil {
+				return fmt.Errorf("failed to lock ticket %s: %w", key, err)
+			}
+			defer func() {
+				err = errors.Join(err, unlock())
+			}()
+		}
+	}
+
+	// The bulk request is made under the pass's correlation ID, logged with the
+	// operations' own so each can be traced to it
+	correlationIDs := make([]string, 0, len(ops))
+	for _, op := range ops {
+		correlationIDs = append(correlationIDs, op.CorrelationID.String())
+	}
+	failures, applyErr := bulk.ApplyBulk(ctx, ops)
+	s.logger.InfoContext(ctx, "applied operations in bulk",
+		"operation", ops[0].Operation,
+		"tickets", len(ops),
+		"correlation_ids", correlationIDs,
+		"error", applyErr)
+
+	for i, op := range ops {
+		opErr := applyErr
+		if opErr == nil && i < len(failures) {
+			opErr = failures[i]
+		}
+		if err := s.record(domain.WithCorrelationID(ctx, op.CorrelationID), op, opErr, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextDue returns the lane's next operation if it may be attempted now, or nil.
+func (s *Service) nextDue(lane domain.QueueLane) *domain.PendingOperation {
+	for _, op := range lane.Operations {
+		if !op.ShouldRetryWith(s.policy) {
+			continue
+		}
+		if s.now().Before(op.NextAttemptAt(s.policy)) {
+			return nil
+		}
+		return op
+	}
+	return nil
+}
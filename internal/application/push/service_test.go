@@ -0,0 +1,223 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/domain/repository/fakes"
+)
+
+// fakeApplier records the operations it applies, failing those given an error in fail.
+type fakeApplier struct {
+	delay time.Duration
+
+	mu       gosync.Mutex
+	fail     map[int64]error
+	applied  []int64
+	inFlight int
+	maxLoad  int
+}
+
+func (a *fakeApplier) Apply(ctx context.Context, op *domain.PendingOperation) error {
+	a.mu.Lock()
+	a.inFlight++
+	a.maxLoad = max(a.maxLoad, a.inFlight)
+	a.mu.Unlock()
+
+	time.Sleep(a.delay)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.inFlight--
+	a.applied = append(a.applied, op.ID)
+	return a.fail[op.ID]
+}
+
+// newOp returns an operation of type operation on ticket key.
+func newOp(t *testing.T, key string, operation domain.OperationType) *domain.PendingOperation {
+	t.Helper()
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		t.Fatalf("NewTicketKey(%s) failed: %v", key, err)
+	}
+	payload, _ := json.Marshal(map[string]string{"value": key})
+	op, err := domain.NewPendingOperation(ticketKey.ProjectKey(), ticketKey, operation, string(payload))
+	if err != nil {
+		t.Fatalf("NewPendingOperation failed: %v", err)
+	}
+	return op
+}
+
+// dirtyState returns the sync state of a ticket with unpushed local changes.
+func dirtyState(key string) *repository.TicketSyncState {
+	return &repository.TicketSyncState{TicketKey: key, ProjectKey: "JMD", IsDirty: true}
+}
+
+func TestService_Drain_TicketOrder(t *testing.T) {
+	ops := []*domain.PendingOperation{
+		newOp(t, "JMD-1", domain.OpPushStatus),
+		newOp(t, "JMD-1", domain.OpPushField),
+		newOp(t, "JMD-1", domain.OpPostComment),
+		newOp(t, "JMD-2", domain.OpPushStatus),
+		newOp(t, "JMD-2", domain.OpPostComment),
+	}
+	queue := fakes.NewPendingOperationRepository(ops...)
+	states := fakes.NewStateRepository(dirtyState("JMD-1"), dirtyState("JMD-2"))
+	applier := &fakeApplier{fail: map[int64]error{ops[1].ID: domain.ErrUnavailable}}
+	ctx := context.Background()
+
+	service := NewService(queue, applier, fakes.NewLockManager(), domain.DefaultRetryPolicy(), nil).
+		WithConcurrency(1).
+		WithStates(states)
+	report, err := service.Drain(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if want := (Report{Applied: 3, Retrying: 1, Deferred: 1}); *report != want {
+		t.Errorf("report = %+v, want %+v", *report, want)
+	}
+
+	// The comment on JMD-1 waits for the field edit queued before it
+	want := []int64{ops[0].ID, ops[1].ID, ops[3].ID, ops[4].ID}
+	if !slices.Equal(applier.applied, want) {
+		t.Errorf("applied %v, want %v", applier.applied, want)
+	}
+	if op := queue.Operation(ops[2].ID); op.Attempts != 0 || op.IsCompleted() {
+		t.Errorf("held back comment = %+v, want untouched", op)
+	}
+	if op := queue.Operation(ops[1].ID); op.Attempts != 1 || op.LastError == "" {
+		t.Errorf("failed field edit = %+v, want one failed attempt", op)
+	}
+
+	for key, dirty := range map[string]bool{"JMD-1": true, "JMD-2": false} {
+		state, err := states.GetTicketState(ctx, key)
+		if err != nil {
+			t.Fatalf("GetTicketState(%s) failed: %v", key, err)
+		}
+		if state.IsDirty != dirty {
+			t.Errorf("%s IsDirty = %v, want %v", key, state.IsDirty, dirty)
+		}
+	}
+
+	// The retry waits out its backoff, holding back the rest of JMD-1
+	report, err = service.Drain(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("second Drain failed: %v", err)
+	}
+	if want := (Report{Deferred: 2}); *report != want {
+		t.Errorf("second report = %+v, want %+v", *report, want)
+	}
+}
+
+func TestService_Drain_Concurrency(t *testing.T) {
+	tests := []struct {
+		concurrency int
+		want        int
+	}{
+		{concurrency: 1, want: 1},
+		{concurrency: 3, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("concurrency %d", tt.concurrency), func(t *testing.T) {
+			var ops []*domain.PendingOperation
+			for i := 1; i <= 6; i++ {
+				ops = append(ops, newOp(t, fmt.Sprintf("JMD-%d", i), domain.OpPushStatus))
+			}
+			applier := &fakeApplier{delay: 20 * time.Millisecond}
+			service := NewService(fakes.NewPendingOperationRepository(ops...), applier, fakes.NewLockManager(), domain.DefaultRetryPolicy(), nil).
+				WithConcurrency(tt.concurrency)
+
+			report, err := service.Drain(context.Background(), "JMD")
+			if err != nil {
+				t.Fatalf("Drain failed: %v", err)
+			}
+			if report.Applied != len(ops) {
+				t.Errorf("Applied = %d, want %d", report.Applied, len(ops))
+			}
+			if applier.maxLoad != tt.want {
+				t.Errorf("%d tickets pushed at once, want %d", applier.maxLoad, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Drain_Priority(t *testing.T) {
+	// Queued in the reverse order of their priority
+	ops := []*domain.PendingOperation{
+		newOp(t, "JMD-1", domain.OpPullTicket),
+		newOp(t, "JMD-2", domain.OpPostComment),
+		newOp(t, "JMD-3", domain.OpPushField),
+		newOp(t, "JMD-4", domain.OpPostComment),
+		newOp(t, "JMD-5", domain.OpPushStatus),
+	}
+	applier := &fakeApplier{}
+	service := NewService(fakes.NewPendingOperationRepository(ops...), applier, nil, domain.DefaultRetryPolicy(), nil).
+		WithConcurrency(1)
+
+	if _, err := service.Drain(context.Background(), "JMD"); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	want := []int64{ops[2].ID, ops[4].ID, ops[1].ID, ops[3].ID, ops[0].ID}
+	if !slices.Equal(applier.applied, want) {
+		t.Errorf("applied %v, want %v", applier.applied, want)
+	}
+}
+
+func TestService_Drain_Deferred(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    domain.SyncMode
+		gate    AuthGate
+		fail    error
+		want    Report
+		applied int
+	}{
+		{name: "pull-only mode", mode: domain.SyncModePullOnly, want: Report{Deferred: 2}},
+		{name: "auth paused", gate: pausedGate(true), want: Report{Deferred: 2}},
+		{name: "credentials rejected", fail: &domain.JiraError{Err: domain.ErrUnauthorized, Status: 401}, want: Report{Deferred: 2}, applied: 2},
+		{name: "permanent failure", fail: domain.ErrInvalidInput, want: Report{Failed: 2}, applied: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := []*domain.PendingOperation{
+				newOp(t, "JMD-1", domain.OpPushStatus),
+				newOp(t, "JMD-2", domain.OpPushStatus),
+			}
+			queue := fakes.NewPendingOperationRepository(ops...)
+			applier := &fakeApplier{fail: map[int64]error{ops[0].ID: tt.fail, ops[1].ID: tt.fail}}
+			service := NewService(queue, applier, nil, domain.DefaultRetryPolicy(), nil).
+				WithMode(tt.mode).
+				WithAuthGate(tt.gate)
+
+			report, err := service.Drain(context.Background(), "JMD")
+			if err != nil {
+				t.Fatalf("Drain failed: %v", err)
+			}
+			if *report != tt.want {
+				t.Errorf("report = %+v, want %+v", *report, tt.want)
+			}
+			if len(applier.applied) != tt.applied {
+				t.Errorf("applied %d operations, want %d", len(applier.applied), tt.applied)
+			}
+			if domain.IsAuthFailure(tt.fail) {
+				if op := queue.Operation(ops[0].ID); op.Attempts != 0 {
+					t.Errorf("Attempts = %d after rejected credentials, want 0", op.Attempts)
+				}
+			}
+		})
+	}
+}
+
+// pausedGate is an AuthGate that is always or never paused.
+type pausedGate bool
+
+func (g pausedGate) Paused() bool {
+	return bool(g)
+}
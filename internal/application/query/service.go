@@ -0,0 +1,72 @@
+// Package query contains use cases for finding tickets with JQL.
+// Queries run either against Jira or, for offline use, against the local ticket cache.
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Searcher runs JQL queries against Jira.
+type Searcher interface {
+	// SearchTickets returns at most limit matching tickets (all when limit <= 0).
+	SearchTickets(ctx context.Context, jql string, limit int) ([]*domain.Ticket, error)
+}
+
+// Service handles JQL query use cases.
+//
+// Error contract: Methods return domain.ErrInvalidInput for malformed or unsupported
+// queries, domain.ErrUnauthorized for Jira auth failures, and wrapped errors otherwise.
+type Service struct {
+	searcher    Searcher
+	ticketRepo  repository.TicketRepository
+	currentUser string
+	now         func() time.Time
+}
+
+// NewService creates a new query service.
+// currentUser is what currentUser() resolves to in local queries (the configured Jira email).
+func NewService(searcher Searcher, ticketRepo repository.TicketRepository, currentUser string) *Service {
+	return &Service{
+		searcher:    searcher,
+		ticketRepo:  ticketRepo,
+		currentUser: currentUser,
+		now:         time.Now,
+	}
+}
+
+// Remote runs arbitrary JQL against Jira and returns at most limit tickets (all when limit <= 0).
+func (s *Service) Remote(ctx context.Context, jql string, limit int) ([]*domain.Ticket, error) {
+	tickets, err := s.searcher.SearchTickets(ctx, jql, limit)
+	if err != nil {
+		return nil, fmt.Errorf("jira search failed: %w", err)
+	}
+	return tickets, nil
+}
+
+// Local evaluates the supported JQL subset against the local ticket cache
+// and returns at most limit tickets (all when limit <= 0).
+func (s *Service) Local(ctx context.Context, jql string, limit int) ([]*domain.Ticket, error) {
+	filter, err := domain.ParseTicketFilter(jql, domain.FilterOptions{
+		CurrentUser: s.currentUser,
+		Now:         s.now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket cache: %w", err)
+	}
+
+	matched := filter.Apply(tickets)
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
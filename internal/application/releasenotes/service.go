@@ -0,0 +1,161 @@
+// Package releasenotes contains use cases for writing release notes from the cached
+// tickets fixed in a release. Release notes read the local ticket cache only and never
+// contact Jira.
+package releasenotes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// DefaultTemplate renders release notes as markdown with a section per issue type.
+const DefaultTemplate = `# Release {{.Version}}
+
+Released {{.Date.Format "2006-01-02"}} with {{.Total}} tickets.
+{{range .Groups}}
+## {{.IssueType}}
+
+{{range .Tickets}}- **{{.Key}}** {{.Summary}}
+{{end}}{{end}}`
+
+// Entry is a ticket in the release notes.
+type Entry struct {
+	Key      string
+	Summary  string
+	Status   string
+	Priority string
+	Assignee string
+	Labels   []string
+}
+
+// Group is the tickets of one issue type.
+type Group struct {
+	IssueType string
+	Tickets   []Entry
+}
+
+// Notes is the data release notes templates are executed with.
+type Notes struct {
+	// Version is the fix version the notes are for
+	Version string
+
+	// Date is when the notes were generated
+	Date time.Time
+
+	// Total is the number of tickets in the release
+	Total int
+
+	// Groups are the tickets by issue type, in issue type order; tickets are in key order
+	Groups []Group
+}
+
+// Service handles release notes use cases against the local ticket cache.
+//
+// Error contract: Methods return domain.ErrInvalidInput for malformed templates or
+// filters, domain.ErrNotFound when no cached ticket has the fix version, and wrapped
+// errors for storage and write failures.
+type Service struct {
+	ticketRepo  repository.TicketRepository
+	currentUser string
+	now         func() time.Time
+}
+
+// NewService creates a new release notes service.
+// currentUser is what currentUser() resolves to in filters (the configured Jira email).
+func NewService(ticketRepo repository.TicketRepository, currentUser string) *Service {
+	return &Service{
+		ticketRepo:  ticketRepo,
+		currentUser: currentUser,
+		now:         time.Now,
+	}
+}
+
+// ParseTemplate parses a release notes template (DefaultTemplate if text is empty).
+// Templates use text/template syntax and are executed with a Notes.
+func ParseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("release-notes").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid release notes template: %v", domain.ErrInvalidInput, err)
+	}
+	return tmpl, nil
+}
+
+// Write renders the release notes of the cached tickets fixed in version, and matching
+// filter, to w with tmpl. filter uses the local JQL subset of jiramd query --local; an
+// empty filter includes every ticket. Returns the notes that were rendered.
+func (s *Service) Write(ctx context.Context, w io.Writer, tmpl *template.Template, version, filter string) (*Notes, error) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return nil, fmt.Errorf("%w: a fix version is required", domain.ErrInvalidInput)
+	}
+
+	now := s.now()
+	ticketFilter, err := domain.ParseTicketFilter(filter, domain.FilterOptions{
+		CurrentUser: s.currentUser,
+		Now:         now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket cache: %w", err)
+	}
+
+	notes := &Notes{Version: version, Date: now}
+	groups := make(map[string][]*domain.Ticket)
+	for _, t := range ticketFilter.Apply(tickets) {
+		if !t.HasFixVersion(version) {
+			continue
+		}
+		issueType := t.IssueType
+		if issueType == "" {
+			issueType = "Other"
+		}
+		groups[issueType] = append(groups[issueType], t)
+		notes.Total++
+	}
+	if notes.Total == 0 {
+		return nil, fmt.Errorf("%w: no cached tickets have fix version %q (run jiramd sync first?)", domain.ErrNotFound, version)
+	}
+
+	issueTypes := make([]string, 0, len(groups))
+	for issueType := range groups {
+		issueTypes = append(issueTypes, issueType)
+	}
+	sort.Strings(issueTypes)
+	for _, issueType := range issueTypes {
+		group := Group{IssueType: issueType}
+		sort.Slice(groups[issueType], func(i, j int) bool {
+			return groups[issueType][i].Key.Compare(groups[issueType][j].Key) < 0
+		})
+		for _, t := range groups[issueType] {
+			group.Tickets = append(group.Tickets, Entry{
+				Key:      t.Key.String(),
+				Summary:  t.Summary,
+				Status:   t.Status,
+				Priority: t.Priority,
+				Assignee: t.Assignee,
+				Labels:   t.Labels,
+			})
+		}
+		notes.Groups = append(notes.Groups, group)
+	}
+
+	if err := tmpl.Execute(w, notes); err != nil {
+		return nil, fmt.Errorf("failed to render release notes: %w", err)
+	}
+	return notes, nil
+}
@@ -0,0 +1,251 @@
+// Package reload contains use cases for applying configuration changes to a running
+// daemon. Settings that only steer timing, scope, and logging are swapped in place;
+// settings wired into connections and storage at startup need a restart and are reported.
+package reload
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Scheduler receives reloaded sync timing.
+// It is satisfied by the scheduler application service.
+type Scheduler interface {
+	// Reconfigure replaces the sync interval and full sync schedule.
+	Reconfigure(cfg domain.SyncConfig) error
+}
+
+// Syncer receives the reloaded sync scope.
+// It is satisfied by the sync application service.
+type Syncer interface {
+	// Rescope replaces the sync filter and the field direction overrides.
+	Rescope(filter domain.SyncFilter, directions func(projectKey string) domain.FieldDirections)
+}
+
+// LevelSetter receives reloaded log levels.
+// It is satisfied by *slog.LevelVar.
+type LevelSetter interface {
+	Set(level slog.Level)
+}
+
+// Service reloads the configuration file and applies what can change without a restart:
+// the sync interval, the full sync schedule, the sync filters, the field directions, and
+// the log level.
+//
+// Error contract: a file that fails to load or validate is rejected as a whole and the
+// running configuration is kept; Reload returns the loader or validator error.
+type Service struct {
+	loader    domain.ConfigLoader
+	validator domain.ConfigValidator
+	path      string
+	scheduler Scheduler
+	syncer    Syncer
+	level     LevelSetter
+	logger    *slog.Logger
+
+	// mu serializes reloads and guards current
+	mu      sync.Mutex
+	current *domain.Config
+}
+
+// NewService creates a new reload service for the configuration file at path, starting
+// from the configuration the daemon is running with. A nil scheduler or level leaves
+// the corresponding settings untouched.
+func NewService(
+	loader domain.ConfigLoader,
+	validator domain.ConfigValidator,
+	path string,
+	current *domain.Config,
+	scheduler Scheduler,
+	level LevelSetter,
+	logger *slog.Logger,
+) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{
+		loader:    loader,
+		validator: validator,
+		path:      path,
+		current:   current,
+		scheduler: scheduler,
+		level:     level,
+		logger:    logger,
+	}
+}
+
+// WithSyncer sets the sync service reloaded filters and field directions are applied
+// to. Without it, they are only recorded.
+func (s *Service) WithSyncer(syncer Syncer) *Service {
+	s.syncer = syncer
+	return s
+}
+
+// Current returns the configuration the daemon is running with.
+func (s *Service) Current() *domain.Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// Run reloads the configuration each time triggers fires until ctx is cancelled or
+// triggers is closed. Rejected reloads are logged and do not stop the loop.
+func (s *Service) Run(ctx context.Context, triggers <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-triggers:
+			if !ok {
+				return
+			}
+			// Reload logs its own outcome
+			_ = s.Reload(ctx)
+		}
+	}
+}
+
+// Reload reads and validates the configuration file, then applies the reloadable
+// settings that changed.
+func (s *Service) Reload(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	next, err := s.loader.Load(s.path)
+	if err == nil {
+		err = s.validator.Validate(next)
+	}
+	if err != nil {
+		s.logger.Error("config reload rejected; keeping the running config", "path", s.path, "error", err)
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	if restart := restartOnly(s.current, next); len(restart) > 0 {
+		s.logger.Warn("config changes need a daemon restart to take effect",
+			"settings", strings.Join(restart, ", "))
+	}
+
+	applied := s.current
+	changed := make([]string, 0, 5)
+
+	if next.Sync.Interval != applied.Sync.Interval ||
+		next.Sync.FullSyncSchedule.String() != applied.Sync.FullSyncSchedule.String() {
+		if s.scheduler != nil {
+			if err := s.scheduler.Reconfigure(next.Sync); err != nil {
+				s.logger.Error("config reload rejected; keeping the running config", "path", s.path, "error", err)
+				return fmt.Errorf("failed to reschedule syncs: %w", err)
+			}
+		}
+		if next.Sync.Interval != applied.Sync.Interval {
+			changed = append(changed, "sync.interval="+next.Sync.Interval.String())
+		}
+		if next.Sync.FullSyncSchedule.String() != applied.Sync.FullSyncSchedule.String() {
+			changed = append(changed, "sync.full_sync_schedule="+next.Sync.FullSyncSchedule.String())
+		}
+	}
+
+	filterChanged := !reflect.DeepEqual(next.Sync.Filter, applied.Sync.Filter)
+	directionsChanged := !reflect.DeepEqual(next.Sync.FieldDirections, applied.Sync.FieldDirections) ||
+		!reflect.DeepEqual(next.Sync.ProjectFieldDirections, applied.Sync.ProjectFieldDirections)
+	if filterChanged || directionsChanged {
+		if s.syncer != nil {
+			s.syncer.Rescope(next.Sync.Filter, next.Sync.FieldDirectionsFor)
+		}
+		if filterChanged {
+			changed = append(changed, "sync.filters")
+		}
+		if directionsChanged {
+			changed = append(changed, "sync.field_directions")
+		}
+	}
+
+	if next.Log.Level != applied.Log.Level {
+		if s.level != nil {
+			s.level.Set(ParseLevel(next.Log.Level))
+		}
+		changed = append(changed, "log.level="+next.Log.Level)
+	}
+
+	s.current = next
+	if len(changed) == 0 {
+		s.logger.Info("config reloaded; no reloadable settings changed", "path", s.path)
+		return nil
+	}
+	s.logger.Info("config reloaded", "path", s.path, "changed", strings.Join(changed, ", "))
+	return nil
+}
+
+// ParseLevel converts a validated log.level value to an slog level (info when empty).
+func ParseLevel(level string) slog.Level {
+	switch level {
+	case domain.LogLevelDebug:
+		return slog.LevelDebug
+	case domain.LogLevelWarn:
+		return slog.LevelWarn
+	case domain.LogLevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// restartOnly lists the changed settings that are only read at startup.
+func restartOnly(current, next *domain.Config) []string {
+	var settings []string
+	if !reflect.DeepEqual(current.Jira, next.Jira) {
+		settings = append(settings, "jira")
+	}
+	if current.Sync.MarkdownDir != next.Sync.MarkdownDir {
+		settings = append(settings, "sync.markdown_dir")
+	}
+	if current.Sync.WatchEnabled != next.Sync.WatchEnabled {
+		settings = append(settings, "sync.watch_enabled")
+	}
+	if current.Sync.Mode != next.Sync.Mode {
+		settings = append(settings, "sync.mode")
+	}
+	if current.Sync.Conflicts != next.Sync.Conflicts {
+		settings = append(settings, "sync.conflicts")
+	}
+	if current.Sync.Sprint != next.Sync.Sprint {
+		settings = append(settings, "sync.sprint")
+	}
+	if current.Sync.Guardrails != next.Sync.Guardrails {
+		settings = append(settings, "sync.guardrails")
+	}
+	if !reflect.DeepEqual(current.Sync.Retry, next.Sync.Retry) {
+		settings = append(settings, "sync.retry")
+	}
+	if !reflect.DeepEqual(current.Sync.Statuses, next.Sync.Statuses) {
+		settings = append(settings, "sync.status_map")
+	}
+	if !reflect.DeepEqual(current.Sync.Priorities, next.Sync.Priorities) {
+		settings = append(settings, "sync.priority_map")
+	}
+	if !reflect.DeepEqual(current.Storage, next.Storage) {
+		settings = append(settings, "storage")
+	}
+	if !reflect.DeepEqual(current.API, next.API) {
+		settings = append(settings, "api")
+	}
+	if !reflect.DeepEqual(current.Notify, next.Notify) {
+		settings = append(settings, "notify")
+	}
+	if !reflect.DeepEqual(current.Markdown, next.Markdown) {
+		settings = append(settings, "markdown")
+	}
+	if current.Display.TimezoneName() != next.Display.TimezoneName() || current.Display.DateFormat != next.Display.DateFormat {
+		settings = append(settings, "display")
+	}
+	return settings
+}
@@ -0,0 +1,84 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// fakeLoader returns the config it holds, or err.
+type fakeLoader struct {
+	cfg *domain.Config
+	err error
+}
+
+func (l *fakeLoader) Load(path string) (*domain.Config, error) {
+	return l.cfg, l.err
+}
+
+// acceptAll is a validator accepting every config.
+type acceptAll struct{}
+
+func (acceptAll) Validate(config *domain.Config) error {
+	return nil
+}
+
+// fakeSyncer records the scope it was given.
+type fakeSyncer struct {
+	rescoped   int
+	filter     domain.SyncFilter
+	directions func(projectKey string) domain.FieldDirections
+}
+
+func (s *fakeSyncer) Rescope(filter domain.SyncFilter, directions func(projectKey string) domain.FieldDirections) {
+	s.rescoped++
+	s.filter = filter
+	s.directions = directions
+}
+
+func TestService_Reload_Rescope(t *testing.T) {
+	current := &domain.Config{Sync: domain.SyncConfig{Filter: domain.SyncFilter{IssueTypes: []string{"Bug"}}}}
+	next := &domain.Config{Sync: domain.SyncConfig{
+		Filter:          domain.SyncFilter{IssueTypes: []string{"Story"}},
+		FieldDirections: domain.FieldDirections{"story_points": domain.SyncLocalOnly},
+	}}
+	loader := &fakeLoader{cfg: next}
+	syncer := &fakeSyncer{}
+	service := NewService(loader, acceptAll{}, "config.yaml", current, nil, nil, nil).WithSyncer(syncer)
+	ctx := context.Background()
+
+	if err := service.Reload(ctx); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if syncer.rescoped != 1 {
+		t.Fatalf("Rescope called %d times, want 1", syncer.rescoped)
+	}
+	if got := syncer.filter.IssueTypes; len(got) != 1 || got[0] != "Story" {
+		t.Errorf("filter issue types = %v, want [Story]", got)
+	}
+	if got := syncer.directions("JMD")["story_points"]; got != domain.SyncLocalOnly {
+		t.Errorf("story_points direction = %q, want local_only", got)
+	}
+	if restart := restartOnly(current, next); len(restart) != 0 {
+		t.Errorf("restartOnly() = %v, want none", restart)
+	}
+
+	// Reloading the same scope leaves the sync service alone
+	if err := service.Reload(ctx); err != nil {
+		t.Fatalf("second Reload failed: %v", err)
+	}
+	if syncer.rescoped != 1 {
+		t.Errorf("Rescope called %d times after an unchanged reload, want 1", syncer.rescoped)
+	}
+
+	// A rejected config keeps the running scope
+	loader.err = domain.ErrConfig
+	if err := service.Reload(ctx); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("Reload() error = %v, want ErrConfig", err)
+	}
+	if syncer.rescoped != 1 || service.Current() != next {
+		t.Errorf("rejected reload changed the running config")
+	}
+}
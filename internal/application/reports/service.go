@@ -0,0 +1,249 @@
+// Package reports contains use cases for writing periodic markdown reports from the
+// local ticket cache: the burndown of each active sprint and an aging WIP table of the
+// tickets in progress. Reports are computed from cached status changelogs; only the
+// active sprints are looked up in Jira.
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// SprintSource looks up the sprints of a Jira Software board (implemented by the Jira
+// client).
+type SprintSource interface {
+	// ActiveSprints returns the sprints in progress on a board
+	ActiveSprints(ctx context.Context, boardID int) ([]*domain.Sprint, error)
+}
+
+// Writer writes report files (implemented by markdown.ReportWriter).
+type Writer interface {
+	// WriteReport writes the report file name with content, reporting whether the file
+	// changed
+	WriteReport(ctx context.Context, name string, content []byte) (bool, error)
+}
+
+// Report is one report file written by Generate.
+type Report struct {
+	Name string `json:"name"`
+
+	// Changed reports whether the file was written, rather than already up to date
+	Changed bool `json:"changed"`
+}
+
+// Service writes the markdown reports configured by domain.ReportsConfig.
+//
+// Error contract: Methods return wrapped errors for storage, Jira, and write failures.
+type Service struct {
+	ticketRepo  repository.TicketRepository
+	historyRepo repository.StatusHistoryRepository
+	cfg         domain.ReportsConfig
+	writer      Writer
+	logger      *slog.Logger
+
+	// sprints looks up the active sprints of boardID (nil writes no burndowns)
+	sprints SprintSource
+	boardID int
+
+	// display is how dates in reports are shown
+	display domain.DisplayConfig
+
+	// now is the clock reports are computed at (overridable in tests)
+	now func() time.Time
+}
+
+// NewService creates a new reports service writing the reports cfg configures through
+// writer, from the tickets in ticketRepo and their changelogs in historyRepo.
+func NewService(
+	ticketRepo repository.TicketRepository,
+	historyRepo repository.StatusHistoryRepository,
+	cfg domain.ReportsConfig,
+	writer Writer,
+	logger *slog.Logger,
+) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{
+		ticketRepo:  ticketRepo,
+		historyRepo: historyRepo,
+		cfg:         cfg,
+		writer:      writer,
+		logger:      logger,
+		display:     domain.DisplayConfig{Location: time.Local},
+		now:         time.Now,
+	}
+}
+
+// WithSprints makes Generate write a burndown for each active sprint of boardID, looked
+// up through sprints.
+func (s *Service) WithSprints(sprints SprintSource, boardID int) *Service {
+	s.sprints = sprints
+	s.boardID = boardID
+	return s
+}
+
+// WithDisplay sets the time zone dates in reports are shown in.
+func (s *Service) WithDisplay(display domain.DisplayConfig) *Service {
+	s.display = display
+	return s
+}
+
+// Run blocks, writing the reports on the configured schedule until the context is
+// cancelled. Failures are logged and do not stop the schedule. Returns ctx.Err() when
+// the context is cancelled, immediately if no schedule is configured.
+func (s *Service) Run(ctx context.Context) error {
+	for {
+		now := s.now()
+		next := s.cfg.Schedule.Next(now)
+		if next.IsZero() {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		reports, err := s.Generate(ctx)
+		if err != nil {
+			s.logger.Error("writing reports failed", "error", err)
+			continue
+		}
+		s.logger.Debug("reports written", "reports", len(reports))
+	}
+}
+
+// Generate writes the aging WIP report and the burndown of each active sprint now, and
+// returns the reports in the order they were written. Files whose content did not change
+// are left alone.
+func (s *Service) Generate(ctx context.Context) ([]Report, error) {
+	now := s.now()
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket cache: %w", err)
+	}
+	history, err := s.historyRepo.FindAllStatusHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status history: %w", err)
+	}
+	for _, ticket := range tickets {
+		ticket.StatusHistory = history[ticket.Key.String()]
+	}
+
+	var sprints []*domain.Sprint
+	if s.sprints != nil {
+		if sprints, err = s.sprints.ActiveSprints(ctx, s.boardID); err != nil {
+			return nil, fmt.Errorf("failed to look up active sprints of board %d: %w", s.boardID, err)
+		}
+	}
+
+	var reports []Report
+	var errs []error
+	write := func(name string, content string) {
+		changed, err := s.writer.WriteReport(ctx, name, []byte(content))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to write report %s: %w", name, err))
+			return
+		}
+		reports = append(reports, Report{Name: name, Changed: changed})
+	}
+
+	write(domain.AgingWIPReport, s.agingWIP(tickets, now))
+	for _, sprint := range sprints {
+		var planned []*domain.Ticket
+		for _, ticket := range tickets {
+			if slices.Contains(ticket.Sprints(), sprint.Name) {
+				planned = append(planned, ticket)
+			}
+		}
+		write(domain.BurndownReport(sprint), s.burndown(sprint, planned, now))
+	}
+	return reports, errors.Join(errs...)
+}
+
+// agingWIP renders the aging WIP report.
+func (s *Service) agingWIP(tickets []*domain.Ticket, now time.Time) string {
+	aging := domain.AgingWIP(tickets, s.cfg.WIPStatuses, now)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Aging WIP (%s)\n\n", s.date(now))
+	fmt.Fprintf(&b, "Tickets in %s, longest in their status first.\n\n", strings.Join(s.cfg.WIPStatuses, ", "))
+	if len(aging) == 0 {
+		b.WriteString("No tickets in progress.\n")
+		return b.String()
+	}
+	b.WriteString("| Ticket | Summary | Status | Assignee | Since | Days |\n|---|---|---|---|---|---:|\n")
+	for _, entry := range aging {
+		ticket := entry.Ticket
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %d |\n",
+			ticket.Key, escapeCell(ticket.Summary), escapeCell(ticket.Status), escapeCell(orUnassigned(ticket.Assignee)),
+			s.date(entry.Since), int(entry.Age/(24*time.Hour)))
+	}
+	return b.String()
+}
+
+// burndown renders the burndown report of a sprint.
+func (s *Service) burndown(sprint *domain.Sprint, tickets []*domain.Ticket, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Burndown: %s (%s)\n\n", sprint.Name, s.date(now))
+	if !sprint.Start.IsZero() {
+		fmt.Fprintf(&b, "Sprint from %s", s.date(sprint.Start))
+		if !sprint.End.IsZero() {
+			fmt.Fprintf(&b, " to %s", s.date(sprint.End))
+		}
+		b.WriteString(". ")
+	}
+	fmt.Fprintf(&b, "%d tickets, done in %s.\n\n", len(tickets), strings.Join(s.cfg.DoneStatuses, ", "))
+
+	points := domain.Burndown(sprint, tickets, s.cfg.DoneStatuses, now)
+	if len(points) == 0 {
+		b.WriteString("The sprint has not started.\n")
+		return b.String()
+	}
+	b.WriteString("| Day | Remaining | Ideal |\n|---|---:|---:|\n")
+	for i, point := range points {
+		day := s.date(point.At)
+		if i == len(points)-1 && point.At.Equal(now) {
+			// The sprint is still running; the last point is not a day boundary
+			day = "Now"
+		}
+		fmt.Fprintf(&b, "| %s | %d | %.1f |\n", day, point.Remaining, math.Round(point.Ideal*10)/10)
+	}
+	return b.String()
+}
+
+// date formats the day of t in the display time zone.
+func (s *Service) date(t time.Time) string {
+	location := s.display.Location
+	if location == nil {
+		location = time.Local
+	}
+	return t.In(location).Format("2006-01-02")
+}
+
+// orUnassigned names an empty assignee.
+func orUnassigned(assignee string) string {
+	if assignee == "" {
+		return "Unassigned"
+	}
+	return assignee
+}
+
+// escapeCell escapes a value for a markdown table cell.
+func escapeCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
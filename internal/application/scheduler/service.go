@@ -0,0 +1,264 @@
+// Package scheduler contains use cases for time-based sync triggering.
+// This layer decides when incremental and full syncs run; the sync service decides how.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Syncer performs the synchronizations triggered by the scheduler.
+// It is satisfied by the sync application service.
+type Syncer interface {
+	// SyncProject runs an incremental sync for a project.
+	SyncProject(ctx context.Context, projectKey string) error
+
+	// FullSyncProject runs a full sync for a project and records LastFullSync.
+	FullSyncProject(ctx context.Context, projectKey string) error
+}
+
+// Service runs incremental syncs on a fixed interval and full syncs on a cron schedule.
+//
+// Catch-up semantics: on start, if a scheduled full sync was missed while the daemon
+// was not running (based on the project's persisted LastFullSync), a full sync runs
+// immediately. Only one catch-up run happens no matter how many activations were missed.
+type Service struct {
+	syncer     Syncer
+	stateRepo  repository.StateRepository
+	projectKey string
+	logger     *slog.Logger
+
+	// mu guards interval and schedule, which Reconfigure replaces while Run is looping,
+	// and waiters
+	mu       sync.Mutex
+	interval time.Duration
+	schedule domain.CronSchedule
+
+	// reconfigured wakes the Run loop to re-arm its ticker and timer after Reconfigure
+	reconfigured chan struct{}
+
+	// triggers carries on-demand sync requests (true = full sync) into the Run loop,
+	// serializing them with scheduled runs
+	triggers chan bool
+
+	// waiters are told the outcome of the next triggered sync (guarded by mu)
+	waiters []func(error)
+
+	// now is the clock used for schedule evaluation (overridable in tests)
+	now func() time.Time
+}
+
+// NewService creates a new scheduler service for a project using the sync configuration.
+func NewService(syncer Syncer, stateRepo repository.StateRepository, projectKey string, cfg domain.SyncConfig, logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Service{
+		syncer:     syncer,
+		stateRepo:  stateRepo,
+		projectKey: projectKey,
+		interval:   cfg.Interval,
+		schedule:   cfg.FullSyncSchedule,
+		logger:     logger,
+		triggers:   make(chan bool, 1),
+		now:        time.Now,
+
+		reconfigured: make(chan struct{}, 1),
+	}
+}
+
+// Reconfigure replaces the sync interval and full sync schedule of a running scheduler.
+// The new interval starts counting from the call; the next full sync is recomputed from
+// the new schedule. Returns ErrConfig, keeping the current settings, if the interval is
+// not positive.
+func (s *Service) Reconfigure(cfg domain.SyncConfig) error {
+	if cfg.Interval <= 0 {
+		return fmt.Errorf("%w: sync interval must be positive", domain.ErrConfig)
+	}
+
+	s.mu.Lock()
+	s.interval = cfg.Interval
+	s.schedule = cfg.FullSyncSchedule
+	s.mu.Unlock()
+
+	select {
+	case s.reconfigured <- struct{}{}:
+	default:
+		// A wake-up is already pending; it will pick up these settings
+	}
+	return nil
+}
+
+// settings returns the current interval and full sync schedule.
+func (s *Service) settings() (time.Duration, domain.CronSchedule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interval, s.schedule
+}
+
+// Trigger requests an immediate sync outside the regular schedule.
+// The sync runs asynchronously on the Run loop so it never overlaps a scheduled run.
+// Returns false if a triggered sync is already pending.
+func (s *Service) Trigger(full bool) bool {
+	return s.TriggerThen(full, nil)
+}
+
+// TriggerThen requests an immediate sync like Trigger, and calls done with its outcome
+// once it has run (nil done is not called). If a triggered sync is already pending,
+// done is called when that one has run instead, and false is returned.
+func (s *Service) TriggerThen(full bool, done func(error)) bool {
+	if done != nil {
+		s.mu.Lock()
+		s.waiters = append(s.waiters, done)
+		s.mu.Unlock()
+	}
+
+	select {
+	case s.triggers <- full:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run blocks, triggering syncs until the context is cancelled.
+// Sync failures are logged and do not stop the scheduler.
+// Returns ctx.Err() when the context is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	interval, _ := s.settings()
+	if interval <= 0 {
+		return fmt.Errorf("%w: sync interval must be positive", domain.ErrConfig)
+	}
+
+	if err := s.catchUp(ctx); err != nil {
+		s.logger.Error("full sync catch-up failed", "project", s.projectKey, "error", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	fullSyncTimer, fullSyncC := s.nextFullSyncTimer()
+	defer func() {
+		if fullSyncTimer != nil {
+			fullSyncTimer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+			s.runIncremental(ctx)
+
+		case <-fullSyncC:
+			s.runFull(ctx)
+			fullSyncTimer, fullSyncC = s.nextFullSyncTimer()
+
+		case <-s.reconfigured:
+			interval, _ := s.settings()
+			ticker.Reset(interval)
+			if fullSyncTimer != nil {
+				fullSyncTimer.Stop()
+			}
+			fullSyncTimer, fullSyncC = s.nextFullSyncTimer()
+
+		case full := <-s.triggers:
+			// Waiters registered from here on are served by the next triggered sync
+			s.mu.Lock()
+			waiters := s.waiters
+			s.waiters = nil
+			s.mu.Unlock()
+
+			var err error
+			if full {
+				err = s.runFull(ctx)
+			} else {
+				err = s.runIncremental(ctx)
+			}
+			for _, done := range waiters {
+				done(err)
+			}
+		}
+	}
+}
+
+// catchUp runs a full sync immediately if a scheduled activation was missed.
+func (s *Service) catchUp(ctx context.Context) error {
+	_, schedule := s.settings()
+	if schedule.IsZero() {
+		return nil
+	}
+
+	var lastFullSync time.Time
+	state, err := s.stateRepo.GetProjectState(ctx, s.projectKey)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		// Never synced; zero time triggers catch-up
+	case err != nil:
+		return fmt.Errorf("failed to get project state: %w", err)
+	default:
+		lastFullSync = state.LastFullSync
+	}
+
+	if !schedule.MissedSince(lastFullSync, s.now()) {
+		return nil
+	}
+
+	s.logger.Info("catching up missed full sync",
+		"project", s.projectKey,
+		"schedule", schedule.String(),
+		"last_full_sync", lastFullSync)
+	s.runFull(ctx)
+	return nil
+}
+
+// nextFullSyncTimer arms a timer for the next scheduled full sync.
+// Returns a nil channel (which blocks forever in select) when no schedule is configured.
+func (s *Service) nextFullSyncTimer() (*time.Timer, <-chan time.Time) {
+	_, schedule := s.settings()
+	now := s.now()
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return nil, nil
+	}
+
+	s.logger.Debug("next full sync scheduled", "project", s.projectKey, "at", next)
+	timer := time.NewTimer(next.Sub(now))
+	return timer, timer.C
+}
+
+// runIncremental runs an incremental sync under a new correlation ID, logs the outcome,
+// and returns the sync's error.
+func (s *Service) runIncremental(ctx context.Context) error {
+	ctx = domain.WithCorrelationID(ctx, domain.NewCorrelationID())
+	start := s.now()
+	if err := s.syncer.SyncProject(ctx, s.projectKey); err != nil {
+		s.logger.ErrorContext(ctx, "incremental sync failed", "project", s.projectKey, "error", err)
+		return err
+	}
+	s.logger.DebugContext(ctx, "incremental sync complete", "project", s.projectKey, "duration", s.now().Sub(start))
+	return nil
+}
+
+// runFull runs a full sync under a new correlation ID, logs the outcome, and returns
+// the sync's error.
+func (s *Service) runFull(ctx context.Context) error {
+	ctx = domain.WithCorrelationID(ctx, domain.NewCorrelationID())
+	start := s.now()
+	s.logger.InfoContext(ctx, "starting full sync", "project", s.projectKey)
+	if err := s.syncer.FullSyncProject(ctx, s.projectKey); err != nil {
+		s.logger.ErrorContext(ctx, "full sync failed", "project", s.projectKey, "error", err)
+		return err
+	}
+	s.logger.InfoContext(ctx, "full sync complete", "project", s.projectKey, "duration", s.now().Sub(start))
+	return nil
+}
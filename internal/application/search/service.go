@@ -0,0 +1,51 @@
+// Package search contains use cases for full-text search over the local ticket cache.
+// Searches never contact Jira, so they work offline and return instantly.
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Service handles full-text search use cases.
+//
+// Error contract: Methods return domain.ErrInvalidInput for empty search text
+// and wrapped errors for storage failures.
+type Service struct {
+	index repository.SearchRepository
+}
+
+// NewService creates a new search service.
+func NewService(index repository.SearchRepository) *Service {
+	return &Service{index: index}
+}
+
+// Search finds cached tickets whose summary, description, or comments contain every
+// term of text, most relevant first. It returns at most limit hits (all when limit <= 0).
+func (s *Service) Search(ctx context.Context, text string, limit int) ([]*repository.SearchHit, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("%w: search text is required", domain.ErrInvalidInput)
+	}
+
+	hits, err := s.index.Search(ctx, text, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	return hits, nil
+}
+
+// Reindex rebuilds the search index from the local cache and returns the number of
+// tickets indexed. The index is kept current automatically; this is only needed after
+// bulk changes made outside jiramd.
+func (s *Service) Reindex(ctx context.Context) (int, error) {
+	indexed, err := s.index.Rebuild(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("reindex failed: %w", err)
+	}
+	return indexed, nil
+}
@@ -0,0 +1,99 @@
+// Package site contains use cases for publishing the local ticket cache as a read-only
+// static site, for stakeholders who do not use Jira. Sites are built from the local
+// cache only and never contact Jira.
+package site
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Page is a ticket with its comments, oldest first.
+type Page struct {
+	Ticket   *domain.Ticket
+	Comments []*domain.Comment
+}
+
+// Site is everything a static site is rendered from.
+type Site struct {
+	// Title names the site (e.g., the project)
+	Title string
+
+	// GeneratedAt is when the site was built
+	GeneratedAt time.Time
+
+	// Pages are the tickets in key order
+	Pages []*Page
+}
+
+// Renderer writes a site out, e.g. as HTML files.
+type Renderer interface {
+	Render(site *Site) error
+}
+
+// Service handles static site use cases against the local ticket cache.
+//
+// Error contract: Methods return domain.ErrInvalidInput for malformed filters, and
+// wrapped errors for storage and rendering failures.
+type Service struct {
+	ticketRepo  repository.TicketRepository
+	commentRepo repository.CommentRepository
+	currentUser string
+	now         func() time.Time
+}
+
+// NewService creates a new site service.
+// currentUser is what currentUser() resolves to in filters (the configured Jira email).
+func NewService(ticketRepo repository.TicketRepository, commentRepo repository.CommentRepository, currentUser string) *Service {
+	return &Service{
+		ticketRepo:  ticketRepo,
+		commentRepo: commentRepo,
+		currentUser: currentUser,
+		now:         time.Now,
+	}
+}
+
+// Render builds a site titled title from the cached tickets matching filter and writes
+// it with renderer. filter uses the local JQL subset of jiramd query --local; an empty
+// filter includes every ticket. Returns the site that was rendered.
+func (s *Service) Render(ctx context.Context, renderer Renderer, title, filter string) (*Site, error) {
+	now := s.now()
+	ticketFilter, err := domain.ParseTicketFilter(filter, domain.FilterOptions{
+		CurrentUser: s.currentUser,
+		Now:         now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket cache: %w", err)
+	}
+	tickets = ticketFilter.Apply(tickets)
+	sort.SliceStable(tickets, func(i, j int) bool {
+		return tickets[i].Key.Compare(tickets[j].Key) < 0
+	})
+
+	site := &Site{Title: title, GeneratedAt: now, Pages: make([]*Page, 0, len(tickets))}
+	for _, ticket := range tickets {
+		comments, err := s.commentRepo.FindByTicketKey(ctx, ticket.Key.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read comments of %s: %w", ticket.Key, err)
+		}
+		sort.SliceStable(comments, func(i, j int) bool {
+			return comments[i].Created.Before(comments[j].Created)
+		})
+		site.Pages = append(site.Pages, &Page{Ticket: ticket, Comments: comments})
+	}
+
+	if err := renderer.Render(site); err != nil {
+		return nil, fmt.Errorf("failed to render site: %w", err)
+	}
+	return site, nil
+}
@@ -0,0 +1,195 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// DefaultDoneStatuses are the statuses a ticket is done in unless others are given.
+var DefaultDoneStatuses = domain.DefaultDoneStatuses
+
+// StatusSummary is the time the reported tickets spent in one status.
+type StatusSummary struct {
+	Status string `json:"status"`
+
+	// Tickets is how many tickets were ever in the status
+	Tickets int `json:"tickets"`
+
+	// Total is the time all tickets spent in the status, Average the time per ticket
+	Total   time.Duration `json:"total"`
+	Average time.Duration `json:"average"`
+}
+
+// TicketCycle is the cycle time of one done ticket.
+type TicketCycle struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+
+	// Started is when the ticket first left the status it was created in, Done when it
+	// last entered a done status
+	Started   time.Time     `json:"started"`
+	Done      time.Time     `json:"done"`
+	CycleTime time.Duration `json:"cycle_time"`
+}
+
+// CycleTimeReport summarizes how long the cached tickets took to get done and where the
+// time went, from their status changelogs. Durations are in nanoseconds in JSON.
+type CycleTimeReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Filter      string    `json:"filter"`
+	DoneIn      []string  `json:"done_statuses"`
+
+	// Tickets is how many tickets were considered
+	Tickets int `json:"tickets"`
+
+	// Average, Median, and P85 summarize the cycle times of the done tickets
+	Average time.Duration `json:"average"`
+	Median  time.Duration `json:"median"`
+	P85     time.Duration `json:"p85"`
+
+	// ByStatus is ordered by total time, largest first
+	ByStatus []StatusSummary `json:"by_status"`
+
+	// Cycles are the done tickets, most recently done first
+	Cycles []TicketCycle `json:"cycles"`
+}
+
+// CycleTime reports the time in status and cycle times of the cached tickets matching
+// filter (the local JQL subset of jiramd query --local; empty for every ticket). A
+// ticket is done when it is in one of the done statuses (DefaultDoneStatuses if none).
+// Tickets without a cached changelog count as in their status since they were created.
+func (s *Service) CycleTime(ctx context.Context, filter string, done []string) (*CycleTimeReport, error) {
+	if len(done) == 0 {
+		done = DefaultDoneStatuses
+	}
+
+	now := s.now()
+	ticketFilter, err := domain.ParseTicketFilter(filter, domain.FilterOptions{
+		CurrentUser: s.currentUser,
+		Now:         now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket cache: %w", err)
+	}
+	tickets = ticketFilter.Apply(tickets)
+
+	history := make(map[string][]domain.StatusChange)
+	if s.historyRepo != nil {
+		if history, err = s.historyRepo.FindAllStatusHistory(ctx); err != nil {
+			return nil, fmt.Errorf("failed to read status history: %w", err)
+		}
+	}
+
+	report := &CycleTimeReport{
+		GeneratedAt: now,
+		Filter:      filter,
+		DoneIn:      done,
+		Tickets:     len(tickets),
+		ByStatus:    make([]StatusSummary, 0),
+		Cycles:      make([]TicketCycle, 0),
+	}
+	statuses := make(map[string]*StatusSummary)
+	for _, t := range tickets {
+		t.StatusHistory = history[t.Key.String()]
+
+		for _, spent := range t.TimeInStatus(now) {
+			summary, ok := statuses[spent.Status]
+			if !ok {
+				summary = &StatusSummary{Status: spent.Status}
+				statuses[spent.Status] = summary
+			}
+			summary.Tickets++
+			summary.Total += spent.Duration
+		}
+
+		if started, finished, ok := t.CycleTime(done); ok {
+			report.Cycles = append(report.Cycles, TicketCycle{
+				Key:       t.Key.String(),
+				Summary:   t.Summary,
+				Started:   started,
+				Done:      finished,
+				CycleTime: finished.Sub(started),
+			})
+		}
+	}
+
+	for _, summary := range statuses {
+		summary.Average = summary.Total / time.Duration(summary.Tickets)
+		report.ByStatus = append(report.ByStatus, *summary)
+	}
+	sort.Slice(report.ByStatus, func(i, j int) bool {
+		if report.ByStatus[i].Total != report.ByStatus[j].Total {
+			return report.ByStatus[i].Total > report.ByStatus[j].Total
+		}
+		return report.ByStatus[i].Status < report.ByStatus[j].Status
+	})
+	sort.Slice(report.Cycles, func(i, j int) bool {
+		return report.Cycles[i].Done.After(report.Cycles[j].Done)
+	})
+
+	if len(report.Cycles) > 0 {
+		durations := make([]time.Duration, 0, len(report.Cycles))
+		var total time.Duration
+		for _, cycle := range report.Cycles {
+			durations = append(durations, cycle.CycleTime)
+			total += cycle.CycleTime
+		}
+		slices.Sort(durations)
+		report.Average = total / time.Duration(len(durations))
+		report.Median = percentile(durations, 50)
+		report.P85 = percentile(durations, 85)
+	}
+	return report, nil
+}
+
+// WriteCycleTimeMarkdown writes the cycle time report as markdown tables.
+func WriteCycleTimeMarkdown(w io.Writer, report *CycleTimeReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Cycle time (%s)\n\n", report.GeneratedAt.Local().Format("2006-01-02"))
+	if report.Filter != "" {
+		fmt.Fprintf(&b, "Filter: `%s`\n\n", report.Filter)
+	}
+	fmt.Fprintf(&b, "**%d of %d tickets done** (in %s)\n", len(report.Cycles), report.Tickets, strings.Join(report.DoneIn, ", "))
+	if len(report.Cycles) > 0 {
+		fmt.Fprintf(&b, "\nCycle time: average %s, median %s, 85th percentile %s\n",
+			domain.FormatElapsed(report.Average), domain.FormatElapsed(report.Median), domain.FormatElapsed(report.P85))
+	}
+
+	b.WriteString("\n### Time in status\n\n| Status | Tickets | Total | Average |\n|---|---:|---:|---:|\n")
+	for _, summary := range report.ByStatus {
+		fmt.Fprintf(&b, "| %s | %d | %s | %s |\n", escapeCell(summary.Status), summary.Tickets,
+			domain.FormatElapsed(summary.Total), domain.FormatElapsed(summary.Average))
+	}
+
+	if len(report.Cycles) > 0 {
+		b.WriteString("\n### Done tickets\n\n| Ticket | Summary | Started | Done | Cycle time |\n|---|---|---|---|---:|\n")
+		for _, cycle := range report.Cycles {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", cycle.Key, escapeCell(cycle.Summary),
+				cycle.Started.Local().Format("2006-01-02"), cycle.Done.Local().Format("2006-01-02"),
+				domain.FormatElapsed(cycle.CycleTime))
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write cycle time report: %w", err)
+	}
+	return nil
+}
+
+// percentile returns the p-th percentile of sorted durations, by the nearest rank.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
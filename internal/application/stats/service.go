@@ -0,0 +1,201 @@
+// Package stats contains use cases for summarizing cached tickets, for reports and
+// standup notes. Statistics read the local ticket cache only and never contact Jira.
+package stats
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Unassigned is the assignee name tickets without an assignee are counted under.
+const Unassigned = "Unassigned"
+
+// AgeBucket is a range of ticket ages, from its MaxAge exclusive back to the previous
+// bucket's.
+type AgeBucket struct {
+	Name   string
+	MaxAge time.Duration
+}
+
+// AgeBuckets are the ranges tickets are counted in by age since creation, youngest
+// first. The last bucket has no upper limit.
+var AgeBuckets = []AgeBucket{
+	{Name: "< 1 week", MaxAge: 7 * 24 * time.Hour},
+	{Name: "1-4 weeks", MaxAge: 28 * 24 * time.Hour},
+	{Name: "1-3 months", MaxAge: 90 * 24 * time.Hour},
+	{Name: "3-12 months", MaxAge: 365 * 24 * time.Hour},
+	{Name: "> 1 year"},
+}
+
+// Count is the number of tickets with one value of a field.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Report summarizes the cached tickets.
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Filter      string    `json:"filter"`
+	Total       int       `json:"total"`
+
+	// ByStatus, ByAssignee, and ByIssueType are ordered by count, largest first
+	ByStatus    []Count `json:"by_status"`
+	ByAssignee  []Count `json:"by_assignee"`
+	ByIssueType []Count `json:"by_issue_type"`
+
+	// ByAge counts tickets by age since creation, in AgeBuckets order
+	ByAge []Count `json:"by_age"`
+}
+
+// Service handles statistics use cases against the local ticket cache.
+//
+// Error contract: Methods return domain.ErrInvalidInput for malformed filters, and
+// wrapped errors for storage and write failures.
+type Service struct {
+	ticketRepo  repository.TicketRepository
+	historyRepo repository.StatusHistoryRepository
+	currentUser string
+	now         func() time.Time
+}
+
+// NewService creates a new stats service.
+// currentUser is what currentUser() resolves to in filters (the configured Jira email).
+func NewService(ticketRepo repository.TicketRepository, currentUser string) *Service {
+	return &Service{
+		ticketRepo:  ticketRepo,
+		currentUser: currentUser,
+		now:         time.Now,
+	}
+}
+
+// WithStatusHistory sets the cached status changelogs CycleTime reads (without them,
+// tickets count as in their status since they were created).
+func (s *Service) WithStatusHistory(historyRepo repository.StatusHistoryRepository) *Service {
+	s.historyRepo = historyRepo
+	return s
+}
+
+// Report summarizes the cached tickets matching filter, which uses the local JQL subset
+// of jiramd query --local; an empty filter summarizes every ticket.
+func (s *Service) Report(ctx context.Context, filter string) (*Report, error) {
+	now := s.now()
+	ticketFilter, err := domain.ParseTicketFilter(filter, domain.FilterOptions{
+		CurrentUser: s.currentUser,
+		Now:         now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket cache: %w", err)
+	}
+	tickets = ticketFilter.Apply(tickets)
+
+	statuses := make(map[string]int)
+	assignees := make(map[string]int)
+	issueTypes := make(map[string]int)
+	ages := make([]int, len(AgeBuckets))
+	for _, t := range tickets {
+		statuses[orNone(t.Status)]++
+		issueTypes[orNone(t.IssueType)]++
+		if t.Assignee == "" {
+			assignees[Unassigned]++
+		} else {
+			assignees[t.Assignee]++
+		}
+		ages[ageBucket(now.Sub(t.Created))]++
+	}
+
+	report := &Report{
+		GeneratedAt: now,
+		Filter:      filter,
+		Total:       len(tickets),
+		ByStatus:    sortedCounts(statuses),
+		ByAssignee:  sortedCounts(assignees),
+		ByIssueType: sortedCounts(issueTypes),
+		ByAge:       make([]Count, 0, len(AgeBuckets)),
+	}
+	for i, bucket := range AgeBuckets {
+		report.ByAge = append(report.ByAge, Count{Name: bucket.Name, Count: ages[i]})
+	}
+	return report, nil
+}
+
+// WriteMarkdown writes the report as markdown tables, for pasting into standup notes.
+func WriteMarkdown(w io.Writer, report *Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Ticket stats (%s)\n\n", report.GeneratedAt.Local().Format("2006-01-02"))
+	if report.Filter != "" {
+		fmt.Fprintf(&b, "Filter: `%s`\n\n", report.Filter)
+	}
+	fmt.Fprintf(&b, "**%d tickets**\n", report.Total)
+
+	for _, section := range []struct {
+		title  string
+		counts []Count
+	}{
+		{"Status", report.ByStatus},
+		{"Assignee", report.ByAssignee},
+		{"Issue type", report.ByIssueType},
+		{"Age", report.ByAge},
+	} {
+		fmt.Fprintf(&b, "\n### By %s\n\n", strings.ToLower(section.title))
+		fmt.Fprintf(&b, "| %s | Tickets |\n|---|---:|\n", section.title)
+		for _, count := range section.counts {
+			fmt.Fprintf(&b, "| %s | %d |\n", escapeCell(count.Name), count.Count)
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write stats: %w", err)
+	}
+	return nil
+}
+
+// ageBucket returns the index in AgeBuckets of a ticket age.
+func ageBucket(age time.Duration) int {
+	for i, bucket := range AgeBuckets {
+		if bucket.MaxAge == 0 || age < bucket.MaxAge {
+			return i
+		}
+	}
+	return len(AgeBuckets) - 1
+}
+
+// sortedCounts orders counts by count, largest first, then by name.
+func sortedCounts(counts map[string]int) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		sorted = append(sorted, Count{Name: name, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// orNone names an empty field value.
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// escapeCell escapes a value for a markdown table cell.
+func escapeCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
@@ -4,47 +4,1006 @@ package sync
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	gosync "sync"
+	"time"
 
+	"github.com/esfisher/jiramd/internal/application/progress"
+	"github.com/esfisher/jiramd/internal/application/push"
+	"github.com/esfisher/jiramd/internal/domain"
 	"github.com/esfisher/jiramd/internal/domain/repository"
 )
 
+// SprintSource looks up the sprints of a Jira Software board (implemented by the Jira client).
+type SprintSource interface {
+	// ActiveSprints returns the sprints in progress on a board
+	ActiveSprints(ctx context.Context, boardID int) ([]*domain.Sprint, error)
+
+	// SprintTicketKeys returns the keys of every ticket in a sprint
+	SprintTicketKeys(ctx context.Context, sprintID int) ([]string, error)
+}
+
+// Archiver moves the markdown file of a ticket that left the sync scope out of the
+// synced directory. A ticket without a file is not an error.
+type Archiver interface {
+	Archive(ctx context.Context, key domain.TicketKey) error
+}
+
+// BacklinkWriter writes the "Referenced by" section of each ticket's markdown file,
+// listing the tickets that link to or mention it.
+type BacklinkWriter interface {
+	WriteBacklinks(ctx context.Context, backlinks domain.Backlinks) error
+}
+
+// IndexWriter writes the per-sprint, per-assignee, and saved filter index files,
+// rewriting only those whose content changed, and returns how many files it wrote or
+// removed.
+type IndexWriter interface {
+	WriteIndexes(ctx context.Context, indexes []*domain.TicketIndex) (int, error)
+}
+
+// FilterSource runs Jira saved filters (implemented by the Jira client).
+type FilterSource interface {
+	// GetFilter returns a saved filter with its JQL
+	GetFilter(ctx context.Context, id string) (*domain.SavedFilter, error)
+
+	// SearchTickets returns at most limit tickets matching a JQL query, in Jira's order
+	SearchTickets(ctx context.Context, jql string, limit int) ([]*domain.Ticket, error)
+}
+
+// TicketSearcher searches Jira for the tickets of a project (implemented by the Jira client).
+type TicketSearcher interface {
+	// ForEachTicket calls fn with each ticket matching a JQL query, fetching one page of
+	// tickets at a time. If fn returns an error, iteration stops and returns it.
+	ForEachTicket(ctx context.Context, jql string, fn func(ticket *domain.Ticket) error) error
+}
+
+// TicketPuller pulls the tickets a project search found (implemented by the pull service).
+type TicketPuller interface {
+	// PullFound pulls a ticket found by a search and reports whether it did; unless force
+	// is set, a ticket already pulled at the revision found is left as it is. Returns
+	// domain.ErrConflict when the ticket has local changes that are not pushed yet.
+	PullFound(ctx context.Context, found *domain.Ticket, force bool) (bool, error)
+}
+
+// WatchPuller pulls the tickets of the watch list (implemented by the pull service).
+type WatchPuller interface {
+	// PullWatched pulls a ticket into its file and marks it watched. Returns
+	// domain.ErrConflict when the ticket has local changes that are not pushed yet.
+	PullWatched(ctx context.Context, key domain.TicketKey) error
+}
+
+// Pusher applies a project's queued local changes to Jira (implemented by the push service).
+type Pusher interface {
+	// Drain makes one pass over the project's push queue. Failures of single operations
+	// are counted in the report; the error reports failures to read or update the queue.
+	Drain(ctx context.Context, projectKey string) (*push.Report, error)
+}
+
+// maxFilterIndexTickets caps the tickets listed in a saved filter index, so a filter
+// matching a whole instance does not page through all of it on every run.
+const maxFilterIndexTickets = 1000
+
 // Service handles synchronization use cases between Jira and local storage.
 // It orchestrates the synchronization logic using domain entities and repository interfaces.
 //
-// TODO: Add Jira client abstraction (interface) to avoid tight coupling to infrastructure.
-// The service should inject a JiraClient interface defined in the application layer.
-//
 // Error contract: Methods return domain.ErrNotFound when resources don't exist,
 // domain.ErrUnauthorized for auth failures, and wrapped errors for other infra issues.
 type Service struct {
 	ticketRepo  repository.TicketRepository
 	commentRepo repository.CommentRepository
 	projectRepo repository.ProjectRepository
+	stateRepo   repository.StateRepository
+	historyRepo repository.SyncHistoryRepository
+	locks       repository.LockManager
+	progress    progress.Progress
+
+	// fieldDirections returns the field direction overrides of a project (guarded by mu,
+	// as Rescope replaces it)
+	fieldDirections func(projectKey string) domain.FieldDirections
+
+	// policy decides whether runs pull and push; runs that cannot push warn about
+	// unpushed local changes
+	policy domain.SyncPolicy
+	logger *slog.Logger
+
+	// filter limits which tickets are synced (guarded by mu, as Rescope replaces it);
+	// currentUser is what its "me" stands for
+	filter      domain.SyncFilter
+	currentUser string
+
+	// sprintScope limits syncing to the active sprints of a board, looked up in sprints;
+	// archiver moves the files of tickets removed from the cache (nil leaves them)
+	sprintScope domain.SprintScope
+	sprints     SprintSource
+	archiver    Archiver
+
+	// backlinks writes the "Referenced by" sections of ticket files (nil writes none)
+	backlinks BacklinkWriter
+
+	// indexes writes the index files selected by indexOptions (nil writes none)
+	indexes      IndexWriter
+	indexOptions domain.IndexOptions
+
+	// filters runs the saved filters of filter indexes (nil leaves their files as they are)
+	filters FilterSource
+
+	// searcher finds the project's tickets in the sync scope, and puller pulls them into
+	// their files (nil pulls none)
+	searcher TicketSearcher
+	puller   TicketPuller
+
+	// pusher applies the queued local changes before every run pulls (nil pushes nothing)
+	pusher Pusher
+
+	// guardrails flag projects with more tickets than expected
+	guardrails domain.Guardrails
+
+	// watchlist lists single tickets every run pulls through watchPuller, whether or not
+	// their projects are synced (nil pulls none)
+	watchlist   []domain.TicketKey
+	watchPuller WatchPuller
+
+	// mu guards lastReport, which is read concurrently by the control API,
+	// activeSprints, the names of the sprints the last run synced, and the filter and
+	// field directions Rescope replaces while runs may be in progress
+	mu            gosync.RWMutex
+	lastReport    *domain.SyncReport
+	activeSprints string
 }
 
 // NewService creates a new sync service with the required repositories.
+// Finished runs are recorded in historyRepo, which may be nil to keep no history.
+// Work on each ticket holds its lock from locks, which may be nil when nothing else
+// touches the tickets concurrently.
 func NewService(
 	ticketRepo repository.TicketRepository,
 	commentRepo repository.CommentRepository,
 	projectRepo repository.ProjectRepository,
+	stateRepo repository.StateRepository,
+	historyRepo repository.SyncHistoryRepository,
+	locks repository.LockManager,
 ) *Service {
 	return &Service{
 		ticketRepo:  ticketRepo,
 		commentRepo: commentRepo,
 		projectRepo: projectRepo,
+		stateRepo:   stateRepo,
+		historyRepo: historyRepo,
+		locks:       locks,
+		progress:    progress.Nop(),
+		fieldDirections: func(string) domain.FieldDirections {
+			return nil
+		},
+		logger: slog.New(slog.DiscardHandler),
 	}
 }
 
-// SyncTicket synchronizes a single ticket between Jira and local storage.
-// This is a placeholder for the actual implementation.
-func (s *Service) SyncTicket(ctx context.Context, ticketKey string) error {
-	// TODO: Implement ticket synchronization logic
-	return nil
+// WithPolicy sets the sync policy. Runs it does not let pull skip pulling; runs it does
+// not let push warn when tickets have local changes that will not be pushed.
+func (s *Service) WithPolicy(policy domain.SyncPolicy) *Service {
+	s.policy = policy
+	return s
+}
+
+// WithFilter limits syncing to the tickets matching filter. Pulls only ask Jira for
+// matching tickets, and every pulling run removes the cached tickets that no longer
+// match. currentUser (the configured Jira email) is what an assignee of "me" matches.
+func (s *Service) WithFilter(filter domain.SyncFilter, currentUser string) *Service {
+	s.filter = filter
+	s.currentUser = currentUser
+	return s
+}
+
+// Rescope replaces the sync filter and the field direction overrides, e.g. when the
+// daemon reloads its config; runs started afterwards use the new ones.
+func (s *Service) Rescope(filter domain.SyncFilter, directions func(projectKey string) domain.FieldDirections) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filter = filter
+	if directions != nil {
+		s.fieldDirections = directions
+	}
+}
+
+// currentFilter returns the sync filter.
+func (s *Service) currentFilter() domain.SyncFilter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.filter
+}
+
+// WithSprintScope limits syncing to the tickets in the active sprints of scope's board,
+// looked up through sprints on every pulling run, so a sprint that starts is picked up
+// without a restart. Cached tickets that are in none of the active sprints are removed
+// (see WithArchiver). A disabled scope or a nil source syncs regardless of sprints.
+func (s *Service) WithSprintScope(scope domain.SprintScope, sprints SprintSource) *Service {
+	s.sprintScope = scope
+	s.sprints = sprints
+	return s
+}
+
+// WithGuardrails sets the limits runs check, warning in their report when a project has
+// more tickets than expected (the zero value checks nothing).
+func (s *Service) WithGuardrails(guardrails domain.Guardrails) *Service {
+	s.guardrails = guardrails
+	return s
+}
+
+// WithArchiver sets where the markdown files of tickets removed from the cache go
+// (nil leaves them in place).
+func (s *Service) WithArchiver(archiver Archiver) *Service {
+	s.archiver = archiver
+	return s
+}
+
+// WithBacklinks sets the writer that refreshes the "Referenced by" sections of ticket
+// files after every pulling run (nil writes none).
+func (s *Service) WithBacklinks(writer BacklinkWriter) *Service {
+	s.backlinks = writer
+	return s
+}
+
+// WithIndexes sets the writer of the index files selected by options, refreshed after
+// every pulling run (nil, or options selecting no index, writes none).
+func (s *Service) WithIndexes(writer IndexWriter, options domain.IndexOptions) *Service {
+	s.indexes = writer
+	s.indexOptions = options
+	return s
+}
+
+// WithSavedFilters sets where the saved filters of the configured filter indexes are
+// run. Without it, filter index files are left as they are.
+func (s *Service) WithSavedFilters(filters FilterSource) *Service {
+	s.filters = filters
+	return s
+}
+
+// WithPusher sets what applies the project's queued local changes to Jira. Every run
+// pushes them before pulling, unless the sync mode disables pushing.
+func (s *Service) WithPusher(pusher Pusher) *Service {
+	s.pusher = pusher
+	return s
+}
+
+// WithTickets sets where runs search for the project's tickets in the sync scope, and
+// what pulls the tickets they find into their files. Without it, runs pull no tickets of
+// the project.
+func (s *Service) WithTickets(searcher TicketSearcher, puller TicketPuller) *Service {
+	s.searcher = searcher
+	s.puller = puller
+	return s
+}
+
+// WithWatchlist makes runs pull the tickets of watchlist through puller, even those
+// outside the sync scope, which are written to their own directory.
+func (s *Service) WithWatchlist(watchlist []domain.TicketKey, puller WatchPuller) *Service {
+	s.watchlist = watchlist
+	s.watchPuller = puller
+	return s
+}
+
+// WithLogger sets where report warnings are logged as they are raised (nil logs nothing),
+// for the daemon, which has nobody to show the reports to.
+func (s *Service) WithLogger(logger *slog.Logger) *Service {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	s.logger = logger
+	return s
+}
+
+// WithFieldDirections sets the field direction overrides pulls honor, usually
+// domain.SyncConfig.FieldDirectionsFor: local_only fields keep their local values.
+func (s *Service) WithFieldDirections(directions func(projectKey string) domain.FieldDirections) *Service {
+	if directions != nil {
+		s.fieldDirections = directions
+	}
+	return s
+}
+
+// WithProgress sets where project syncs report their progress (nil reports nothing).
+func (s *Service) WithProgress(p progress.Progress) *Service {
+	s.progress = progress.OrNop(p)
+	return s
+}
+
+// withTicketLock runs fn while holding the lock on ticketKey, so pulls, pushes, and
+// conflict resolutions of the same ticket never interleave.
+func (s *Service) withTicketLock(ctx context.Context, ticketKey string, fn func() error) (err error) {
+	if s.locks == nil {
+		return fn()
+	}
+
+	unlock, err := s.locks.Lock(ctx, ticketKey)
+	if err != nil {
+		return fmt.Errorf("failed to lock ticket %s: %w", ticketKey, err)
+	}
+	defer func() {
+		err = errors.Join(err, unlock())
+	}()
+
+	return fn()
 }
 
-// SyncProject synchronizes all tickets for a project.
-// This is a placeholder for the actual implementation.
+// SyncProject synchronizes all tickets for a project, under the correlation ID of ctx or
+// a new one: it pushes the queued local changes, then pulls the tickets in the sync
+// scope that changed in Jira since they were last pulled.
 func (s *Service) SyncProject(ctx context.Context, projectKey string) error {
-	// TODO: Implement project synchronization logic
+	ctx = domain.EnsureCorrelationID(ctx)
+	report := domain.NewSyncReport(projectKey, false)
+	report.CorrelationID = domain.CorrelationIDFrom(ctx)
+	s.progress.Start(fmt.Sprintf("Syncing %s", projectKey), 0)
+	defer s.progress.Finish()
+	err := s.checkMode(ctx, report)
+	if err == nil {
+		err = s.pushQueued(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.pullProject(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.removeOutOfScope(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.syncWatchlist(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.updateBacklinks(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.updateIndexes(ctx, report)
+	}
+	if err == nil {
+		err = s.upgradeHashes(ctx, report)
+	}
+	if err == nil {
+		err = s.checkGuardrails(ctx, report)
+	}
+	report.Finish(err)
+	return errors.Join(err, s.finishRun(ctx, report))
+}
+
+// FullSyncProject re-pulls every ticket in the sync scope regardless of modification
+// time and records the completion time as the project's LastFullSync. Like SyncProject, it
+// runs under the correlation ID of ctx or a new one.
+func (s *Service) FullSyncProject(ctx context.Context, projectKey string) error {
+	ctx = domain.EnsureCorrelationID(ctx)
+	report := domain.NewSyncReport(projectKey, true)
+	report.CorrelationID = domain.CorrelationIDFrom(ctx)
+	s.progress.Start(fmt.Sprintf("Full sync of %s", projectKey), 0)
+	defer s.progress.Finish()
+	err := s.checkMode(ctx, report)
+	if err == nil {
+		err = s.pushQueued(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.pullProject(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.removeOutOfScope(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.syncWatchlist(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.updateBacklinks(ctx, report)
+	}
+	if err == nil && s.policy.CanPull() {
+		err = s.updateIndexes(ctx, report)
+	}
+	if err == nil {
+		err = s.upgradeHashes(ctx, report)
+	}
+	if err == nil {
+		err = s.checkGuardrails(ctx, report)
+	}
+	report.Finish(err)
+	return errors.Join(err, s.finishRun(ctx, report))
+}
+
+// pullProject pulls the project's tickets in the sync scope, searching Jira for them a
+// page at a time so memory use does not grow with the project, and records the pull in
+// the project's sync state. Incremental runs skip the tickets already pulled at the
+// revision found; full runs rewrite every one. Tickets with local changes that are not
+// pushed yet are not pulled, with a warning, so no edit is lost.
+func (s *Service) pullProject(ctx context.Context, report *domain.SyncReport) error {
+	if s.searcher == nil || s.puller == nil {
+		return nil
+	}
+
+	jql, err := s.projectJQL(ctx, report)
+	if err != nil {
+		return err
+	}
+	if jql != "" {
+		complete, err := s.pullMatching(ctx, report, jql)
+		if err != nil || !complete {
+			return err
+		}
+	}
+	return s.recordPull(ctx, report)
+}
+
+// projectJQL returns the JQL selecting the project's tickets in the sync filter and, with
+// a sprint scope, in the board's active sprints, or "" when there is no active sprint to
+// pull from.
+func (s *Service) projectJQL(ctx context.Context, report *domain.SyncReport) (string, error) {
+	jql := s.currentFilter().ProjectJQL(report.ProjectKey)
+	if !s.sprintScope.Enabled() || s.sprints == nil {
+		return jql, nil
+	}
+
+	sprints, err := s.sprints.ActiveSprints(ctx, s.sprintScope.BoardID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get active sprints of board %d: %w", s.sprintScope.BoardID, err)
+	}
+	if len(sprints) == 0 {
+		// removeOutOfScope warns about it
+		return "", nil
+	}
+	return jql + " AND " + domain.SprintJQL(sprints), nil
+}
+
+// errTooManyTickets stops a project search that found more tickets than the guardrails
+// allow.
+var errTooManyTickets = errors.New("too many tickets")
+
+// pullMatching pulls the tickets matching jql, and reports whether it pulled them all
+// rather than stopping, with a warning, once the search found more than the guardrails
+// allow. Failures to pull single tickets are recorded in the report.
+func (s *Service) pullMatching(ctx context.Context, report *domain.SyncReport, jql string) (bool, error) {
+	var (
+		found int
+		kept  []string
+	)
+	err := s.searcher.ForEachTicket(ctx, jql, func(ticket *domain.Ticket) error {
+		found++
+		if limit := s.guardrails.MaxTicketsPerProject; limit > 0 && found > limit {
+			return errTooManyTickets
+		}
+		defer s.progress.Advance(1)
+
+		result := domain.NewSyncResult(ticket.Key)
+		pulled, err := s.puller.PullFound(ctx, ticket, report.Full)
+		switch {
+		case errors.Is(err, domain.ErrConflict):
+			kept = append(kept, ticket.Key.String())
+			return nil
+		case err != nil:
+			result.MarkFailed(err)
+		case pulled:
+			result.AddOperation("pulled")
+		default:
+			return nil
+		}
+		report.AddResult(result)
+		return nil
+	})
+
+	if len(kept) > 0 {
+		s.warn(ctx, report, "%d tickets have unpushed local changes, so they were not pulled: %s",
+			len(kept), strings.Join(kept, ", "))
+	}
+	if errors.Is(err, errTooManyTickets) {
+		s.warn(ctx, report, "%s has more than the %d tickets allowed by sync.guardrails.max_tickets_per_project, so pulling stopped; check that the sync is scoped as intended (%s)",
+			report.ProjectKey, s.guardrails.MaxTicketsPerProject, jql)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to search tickets of %s: %w", report.ProjectKey, err)
+	}
+	return true, nil
+}
+
+// recordPull records in the project's sync state that the run pulled its tickets, and
+// how many tickets it now tracks.
+func (s *Service) recordPull(ctx context.Context, report *domain.SyncReport) error {
+	state, err := s.stateRepo.GetProjectState(ctx, report.ProjectKey)
+	if errors.Is(err, domain.ErrNotFound) {
+		state = &repository.ProjectSyncState{ProjectKey: report.ProjectKey}
+	} else if err != nil {
+		return fmt.Errorf("failed to get project state: %w", err)
+	}
+	tickets, err := s.stateRepo.GetTicketStatesByProject(ctx, report.ProjectKey)
+	if err != nil {
+		return fmt.Errorf("failed to list ticket states: %w", err)
+	}
+
+	state.TicketCount = 0
+	for _, ticket := range tickets {
+		if !ticket.IsTombstone() {
+			state.TicketCount++
+		}
+	}
+	now := time.Now().UTC()
+	state.LastIncrementalSync = now
+	if report.Full {
+		state.LastFullSync = now
+	}
+	if err := s.stateRepo.SaveProjectState(ctx, state); err != nil {
+		return fmt.Errorf("failed to save project state: %w", err)
+	}
+	return nil
+}
+
+// updateBacklinks recomputes which cached tickets reference each other, through issue
+// links and key mentions, and rewrites the "Referenced by" sections of their files.
+// A failure to write them is only a warning: the cache itself is up to date.
+func (s *Service) updateBacklinks(ctx context.Context, report *domain.SyncReport) error {
+	if s.backlinks == nil {
+		return nil
+	}
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list cached tickets: %w", err)
+	}
+	if err := s.backlinks.WriteBacklinks(ctx, domain.ComputeBacklinks(tickets)); err != nil {
+		s.warn(ctx, report, "failed to update backlinks: %v", err)
+	}
+	return nil
+}
+
+// updateIndexes regenerates the per-sprint and per-assignee index files from the cached
+// tickets, and the saved filter indexes by running each filter's JQL in Jira. Only the
+// indexes whose listing changed are rewritten, so a run that changed a few tickets
+// touches a few files. A failure to run a filter or write the files is only a warning;
+// the index of a filter that failed is left as it was.
+func (s *Service) updateIndexes(ctx context.Context, report *domain.SyncReport) error {
+	if s.indexes == nil || !s.indexOptions.Enabled() {
+		return nil
+	}
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list cached tickets: %w", err)
+	}
+	indexes := domain.BuildIndexes(tickets, s.indexOptions)
+	for _, config := range s.indexOptions.Filters {
+		indexes = append(indexes, s.filterIndex(ctx, report, config))
+	}
+
+	changed, err := s.indexes.WriteIndexes(ctx, indexes)
+	if err != nil {
+		s.warn(ctx, report, "failed to update index files: %v", err)
+	}
+	if changed > 0 {
+		s.logger.InfoContext(ctx, "updated index files", "project", report.ProjectKey, "files", changed)
+	}
+	return nil
+}
+
+// filterIndex runs a saved filter and returns its index, or an index keeping the
+// existing file if the filter could not be run.
+func (s *Service) filterIndex(ctx context.Context, report *domain.SyncReport, config domain.FilterIndex) *domain.TicketIndex {
+	keep := domain.NewFilterIndex(config, nil, nil)
+	keep.Keep = true
+	if s.filters == nil {
+		return keep
+	}
+
+	filter, err := s.filters.GetFilter(ctx, config.ID)
+	if err != nil {
+		s.warn(ctx, report, "failed to get saved filter %s: %v", config.ID, err)
+		return keep
+	}
+	tickets, err := s.filters.SearchTickets(ctx, filter.JQL, maxFilterIndexTickets)
+	if err != nil {
+		s.warn(ctx, report, "failed to run saved filter %s: %v", config.ID, err)
+		return keep
+	}
+	return domain.NewFilterIndex(config, filter, tickets)
+}
+
+// upgradeHashes replaces the content hashes the project's sync states recorded with an
+// older algorithm, for tickets whose cached content still matches them. Hashes of
+// tickets changed since their last sync are replaced when they next sync. A failure is
+// only a warning: legacy hashes are still accepted.
+func (s *Service) upgradeHashes(ctx context.Context, report *domain.SyncReport) error {
+	states, err := s.stateRepo.GetTicketStatesByProject(ctx, report.ProjectKey)
+	if err != nil {
+		return fmt.Errorf("failed to list ticket states: %w", err)
+	}
+
+	upgraded := 0
+	for _, state := range states {
+		if !state.NeedsHashUpgrade() || state.IsTombstone() {
+			continue
+		}
+		err := s.withTicketLock(ctx, state.TicketKey, func() error {
+			// Read again under the lock, so a concurrent sync of the ticket is not undone
+			state, err := s.stateRepo.GetTicketState(ctx, state.TicketKey)
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to get ticket state: %w", err)
+			}
+			ticket, err := s.ticketRepo.FindByKey(ctx, state.TicketKey)
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to get cached ticket: %w", err)
+			}
+			if !state.UpgradeHash(ticket) {
+				return nil
+			}
+			if err := s.stateRepo.SaveTicketState(ctx, state); err != nil {
+				return fmt.Errorf("failed to save ticket state: %w", err)
+			}
+			upgraded++
+			return nil
+		})
+		if err != nil {
+			s.warn(ctx, report, "failed to upgrade the content hash of %s: %v", state.TicketKey, err)
+		}
+	}
+	if upgraded > 0 {
+		s.logger.InfoContext(ctx, "upgraded legacy content hashes", "project", report.ProjectKey, "tickets", upgraded)
+	}
+	return nil
+}
+
+// checkGuardrails warns in the report when the project tracks more tickets than the
+// guardrails allow, which usually means the sync is scoped wider than intended.
+func (s *Service) checkGuardrails(ctx context.Context, report *domain.SyncReport) error {
+	if s.guardrails.MaxTicketsPerProject == 0 {
+		return nil
+	}
+
+	state, err := s.stateRepo.GetProjectState(ctx, report.ProjectKey)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get project state: %w", err)
+	}
+	if warning := s.guardrails.CheckTicketCount(report.ProjectKey, state.TicketCount); warning != "" {
+		s.warn(ctx, report, "%s", warning)
+	}
+	return nil
+}
+
+// checkMode warns in the report about what the sync policy leaves undone: pulls in
+// push-only mode, and the project's unpushed local changes in pull-only mode.
+func (s *Service) checkMode(ctx context.Context, report *domain.SyncReport) error {
+	if !s.policy.CanPull() {
+		s.warn(ctx, report, "pulling from Jira is disabled (sync.mode is %s)", s.policy.Mode)
+	}
+	if s.policy.CanPush() {
+		return nil
+	}
+
+	states, err := s.stateRepo.GetDirtyTickets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get dirty tickets: %w", err)
+	}
+	dirty := 0
+	for _, state := range states {
+		if key, err := domain.NewTicketKey(state.TicketKey); err == nil && key.ProjectKey() == report.ProjectKey {
+			dirty++
+		}
+	}
+	if dirty > 0 {
+		s.warn(ctx, report, "%d tickets in %s have local changes that will not be pushed (sync.mode is %s)",
+			dirty, report.ProjectKey, s.policy.Mode)
+	}
+	return nil
+}
+
+// pushQueued applies the project's queued local changes to Jira, so the pulls that follow
+// see them. Changes that fail to push are reported as warnings; only failures to read or
+// update the queue stop the run.
+func (s *Service) pushQueued(ctx context.Context, report *domain.SyncReport) error {
+	if s.pusher == nil || !s.policy.CanPush() {
+		return nil
+	}
+
+	pushed, err := s.pusher.Drain(ctx, report.ProjectKey)
+	if err != nil {
+		return fmt.Errorf("failed to push queued changes: %w", err)
+	}
+	if pushed.Retrying > 0 {
+		s.warn(ctx, report, "%d queued changes failed to push to Jira and will be retried", pushed.Retrying)
+	}
+	if pushed.Failed > 0 {
+		s.warn(ctx, report, "%d queued changes failed to push to Jira and will not be retried; \"jiramd ticket view\" shows their errors", pushed.Failed)
+	}
+	return nil
+}
+
+// removeOutOfScope removes the project's cached tickets that are out of the sync scope
+// (no longer matching the sync filter, or in none of the active sprints) and not on the
+// watch list, together with their sync state. Tickets with local changes that are not yet pushed are kept, with a
+// warning, so no edit is lost.
+func (s *Service) removeOutOfScope(ctx context.Context, report *domain.SyncReport) error {
+	inScope, err := s.scope(ctx, report)
+	if err != nil || inScope == nil {
+		return err
+	}
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list cached tickets: %w", err)
+	}
+
+	var kept []string
+	for _, ticket := range tickets {
+		if ticket.Key.ProjectKey() != report.ProjectKey || inScope(ticket) || s.watched(ticket.Key) {
+			continue
+		}
+
+		result := domain.NewSyncResult(ticket.Key)
+		removed := false
+		err := s.withTicketLock(ctx, ticket.Key.String(), func() error {
+			var err error
+			removed, err = s.removeTicket(ctx, ticket.Key)
+			return err
+		})
+		switch {
+		case err != nil:
+			result.MarkFailed(err)
+		case removed:
+			result.AddOperation("removed_out_of_scope")
+		default:
+			kept = append(kept, ticket.Key.String())
+			continue
+		}
+		report.AddResult(result)
+	}
+
+	if len(kept) > 0 {
+		s.warn(ctx, report, "%d tickets are out of the sync scope but have unpushed local changes, so they are kept: %s",
+			len(kept), strings.Join(kept, ", "))
+	}
+	return nil
+}
+
+// watched reports whether key is on the watch list.
+func (s *Service) watched(key domain.TicketKey) bool {
+	for _, watched := range s.watchlist {
+		if watched == key {
+			return true
+		}
+	}
+	return false
+}
+
+// syncWatchlist pulls the tickets of the watch list, then stops watching the tickets
+// dropped from it: those of the synced project are left to the project's sync, others
+// are removed from the cache with their sync state. Tickets with local changes that are
+// not pushed yet are neither pulled nor removed, with a warning, so no edit is lost.
+func (s *Service) syncWatchlist(ctx context.Context, report *domain.SyncReport) error {
+	var kept []string
+	if s.watchPuller != nil {
+		for _, key := range s.watchlist {
+			result := domain.NewSyncResult(key)
+			err := s.watchPuller.PullWatched(ctx, key)
+			switch {
+			case errors.Is(err, domain.ErrConflict):
+				kept = append(kept, key.String())
+				continue
+			case err != nil:
+				result.MarkFailed(err)
+			default:
+				result.AddOperation("pulled_watched")
+			}
+			report.AddResult(result)
+		}
+	}
+	if len(kept) > 0 {
+		s.warn(ctx, report, "%d watched tickets have unpushed local changes, so they were not pulled: %s",
+			len(kept), strings.Join(kept, ", "))
+	}
+
+	states, err := s.stateRepo.GetWatchedTickets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get watched tickets: %w", err)
+	}
+	kept = nil
+	for _, state := range states {
+		key, err := domain.NewTicketKey(state.TicketKey)
+		if err != nil || s.watched(key) {
+			continue
+		}
+
+		result := domain.NewSyncResult(key)
+		removed := false
+		err = s.withTicketLock(ctx, key.String(), func() error {
+			if key.ProjectKey() == report.ProjectKey {
+				return s.unwatch(ctx, key)
+			}
+			var err error
+			removed, err = s.removeTicket(ctx, key)
+			return err
+		})
+		switch {
+		case err != nil:
+			result.MarkFailed(err)
+		case key.ProjectKey() == report.ProjectKey:
+			result.AddOperation("unwatched")
+		case removed:
+			result.AddOperation("removed_unwatched")
+		default:
+			kept = append(kept, key.String())
+			continue
+		}
+		report.AddResult(result)
+	}
+
+	if len(kept) > 0 {
+		s.warn(ctx, report, "%d tickets were dropped from the watch list but have unpushed local changes, so they are kept: %s",
+			len(kept), strings.Join(kept, ", "))
+	}
+	return nil
+}
+
+// unwatch clears the watched mark of a ticket's sync state.
+func (s *Service) unwatch(ctx context.Context, key domain.TicketKey) error {
+	state, err := s.stateRepo.GetTicketState(ctx, key.String())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get ticket state: %w", err)
+	}
+	state.Watched = false
+	if err := s.stateRepo.SaveTicketState(ctx, state); err != nil {
+		return fmt.Errorf("failed to save ticket state: %w", err)
+	}
+	return nil
+}
+
+// scope returns whether a cached ticket is within the sync filter and the active
+// sprints, or nil when every ticket is.
+func (s *Service) scope(ctx context.Context, report *domain.SyncReport) (func(*domain.Ticket) bool, error) {
+	var filter *domain.TicketFilter
+	if syncFilter := s.currentFilter(); !syncFilter.IsZero() {
+		var err error
+		if filter, err = syncFilter.Scope(domain.FilterOptions{CurrentUser: s.currentUser}); err != nil {
+			return nil, fmt.Errorf("invalid sync filter: %w", err)
+		}
+	}
+
+	sprintKeys, err := s.sprintTicketKeys(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter == nil && sprintKeys == nil {
+		return nil, nil
+	}
+	return func(ticket *domain.Ticket) bool {
+		if filter != nil && !filter.Matches(ticket) {
+			return false
+		}
+		return sprintKeys == nil || sprintKeys[ticket.Key.String()]
+	}, nil
+}
+
+// sprintTicketKeys returns the keys of the tickets in the active sprints of the sprint
+// scope's board, or nil when syncing is not limited to sprints. With no active sprint
+// (e.g., between one sprint closing and the next starting) it warns and returns nil, so
+// the local files are kept rather than all archived.
+func (s *Service) sprintTicketKeys(ctx context.Context, report *domain.SyncReport) (map[string]bool, error) {
+	if !s.sprintScope.Enabled() || s.sprints == nil {
+		return nil, nil
+	}
+
+	sprints, err := s.sprints.ActiveSprints(ctx, s.sprintScope.BoardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active sprints of board %d: %w", s.sprintScope.BoardID, err)
+	}
+	if len(sprints) == 0 {
+		s.warn(ctx, report, "board %d has no active sprint, so the local tickets are kept as they are", s.sprintScope.BoardID)
+		return nil, nil
+	}
+	s.noteActiveSprints(ctx, sprints)
+
+	keys := make(map[string]bool)
+	for _, sprint := range sprints {
+		sprintKeys, err := s.sprints.SprintTicketKeys(ctx, sprint.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tickets of sprint %s: %w", sprint.Name, err)
+		}
+		for _, key := range sprintKeys {
+			keys[key] = true
+		}
+	}
+	return keys, nil
+}
+
+// noteActiveSprints logs the active sprints when they differ from the last run's, e.g.
+// when a new sprint has started.
+func (s *Service) noteActiveSprints(ctx context.Context, sprints []*domain.Sprint) {
+	names := domain.SprintNames(sprints)
+
+	s.mu.Lock()
+	changed := names != s.activeSprints
+	s.activeSprints = names
+	s.mu.Unlock()
+
+	if changed {
+		s.logger.InfoContext(ctx, "syncing active sprints", "board", s.sprintScope.BoardID, "sprints", names)
+	}
+}
+
+// removeTicket deletes a cached ticket and its sync state in one transaction, and
+// archives its markdown file if an archiver is set (the file is left in place
+// otherwise, as it may hold local notes), unless the ticket has unpushed local changes.
+// Reports whether it was removed.
+func (s *Service) removeTicket(ctx context.Context, key domain.TicketKey) (removed bool, err error) {
+	txCtx, err := s.stateRepo.BeginTransaction(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			s.stateRepo.Rollback(txCtx)
+		}
+	}()
+
+	state, err := s.stateRepo.GetTicketState(txCtx, key.String())
+	if errors.Is(err, domain.ErrNotFound) {
+		state = nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get ticket state: %w", err)
+	}
+	if state != nil && state.IsDirty {
+		return false, s.stateRepo.Rollback(txCtx)
+	}
+
+	if err = s.ticketRepo.Delete(txCtx, key.String()); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return false, fmt.Errorf("failed to delete cached ticket: %w", err)
+	}
+	if state != nil {
+		if err = s.stateRepo.DeleteTicketState(txCtx, key.String()); err != nil {
+			return false, fmt.Errorf("failed to delete ticket state: %w", err)
+		}
+	}
+
+	if s.archiver != nil {
+		// Archived before committing, so a failure keeps the ticket cached and it is
+		// retried on the next run
+		if err = s.archiver.Archive(txCtx, key); err != nil {
+			return false, fmt.Errorf("failed to archive markdown: %w", err)
+		}
+	}
+	if err = s.stateRepo.Commit(txCtx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// warn records a warning in the report and logs it.
+func (s *Service) warn(ctx context.Context, report *domain.SyncReport, format string, args ...interface{}) {
+	report.Warn(format, args...)
+	s.logger.WarnContext(ctx, report.Warnings[len(report.Warnings)-1], "project", report.ProjectKey)
+}
+
+// LastReport returns the report of the most recent sync run, or nil if none has run yet.
+func (s *Service) LastReport() *domain.SyncReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastReport
+}
+
+// setLastReport records the report of a completed sync run.
+func (s *Service) setLastReport(report *domain.SyncReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastReport = report
+}
+
+// finishRun publishes the report of a finished sync run and records it in the sync history.
+func (s *Service) finishRun(ctx context.Context, report *domain.SyncReport) error {
+	s.setLastReport(report)
+	if s.historyRepo == nil {
+		return nil
+	}
+	if err := s.historyRepo.Record(ctx, repository.NewSyncHistoryEntry(report)); err != nil {
+		return fmt.Errorf("failed to record sync history: %w", err)
+	}
 	return nil
 }
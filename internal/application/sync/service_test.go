@@ -0,0 +1,213 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/domain/repository/fakes"
+)
+
+// recordedSearch is a TicketSearcher finding the same tickets for every query, recording
+// the queries.
+type recordedSearch struct {
+	tickets []*domain.Ticket
+	queries []string
+}
+
+func (r *recordedSearch) ForEachTicket(ctx context.Context, jql string, fn func(ticket *domain.Ticket) error) error {
+	r.queries = append(r.queries, jql)
+	for _, ticket := range r.tickets {
+		if err := fn(ticket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordedPuller is a TicketPuller recording the tickets it pulls, tracking each in
+// states as a file at its key.
+type recordedPuller struct {
+	states *fakes.StateRepository
+	pulled []string
+	forced bool
+}
+
+func (p *recordedPuller) PullFound(ctx context.Context, found *domain.Ticket, force bool) (bool, error) {
+	p.pulled = append(p.pulled, found.Key.String())
+	p.forced = force
+	state := &repository.TicketSyncState{TicketKey: found.Key.String(), FilePath: found.Key.FilePath("")}
+	state.RecordSynced(found, time.Now())
+	return true, p.states.SaveTicketState(ctx, state)
+}
+
+// activeSprints is a SprintSource whose board has the given active sprints, with no
+// tickets in them.
+type activeSprints []*domain.Sprint
+
+func (a activeSprints) ActiveSprints(ctx context.Context, boardID int) ([]*domain.Sprint, error) {
+	return a, nil
+}
+
+func (a activeSprints) SprintTicketKeys(ctx context.Context, sprintID int) ([]string, error) {
+	return nil, nil
+}
+
+// searchTickets returns tickets JMD-1 to JMD-n as found by a search.
+func searchTickets(t *testing.T, n int) []*domain.Ticket {
+	t.Helper()
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	tickets := make([]*domain.Ticket, 0, n)
+	for i := 1; i <= n; i++ {
+		key, err := domain.NewTicketKey(fmt.Sprintf("JMD-%d", i))
+		if err != nil {
+			t.Fatalf("NewTicketKey failed: %v", err)
+		}
+		tickets = append(tickets, domain.NewTicket(key, "Ticket "+key.String(), updated, updated))
+	}
+	return tickets
+}
+
+func TestService_SyncProject_SearchScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  domain.SyncFilter
+		sprints activeSprints
+		full    bool
+		want    []string
+	}{
+		{
+			name: "whole project",
+			want: []string{`project = "JMD"`},
+		},
+		{
+			name: "filter",
+			filter: domain.SyncFilter{
+				OnlyAssignee:  "me",
+				IssueTypes:    []string{"Bug", "Story"},
+				ExcludeLabels: []string{"wontfix"},
+			},
+			want: []string{`project = "JMD" AND assignee = currentUser() AND issuetype IN ("Bug", "Story") AND (labels IS EMPTY OR labels NOT IN ("wontfix"))`},
+		},
+		{
+			name:    "active sprints",
+			filter:  domain.SyncFilter{IssueTypes: []string{"Bug"}},
+			sprints: activeSprints{{ID: 7, Name: "Sprint 7"}, {ID: 8, Name: "Sprint 8"}},
+			want:    []string{`project = "JMD" AND issuetype IN ("Bug") AND sprint IN (7, 8)`},
+		},
+		{
+			name:    "no active sprint",
+			sprints: activeSprints{},
+		},
+		{
+			name: "full",
+			full: true,
+			want: []string{`project = "JMD"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := fakes.NewStateRepository()
+			search := &recordedSearch{tickets: searchTickets(t, 2)}
+			puller := &recordedPuller{states: states}
+			service := NewService(fakes.NewTicketRepository(), nil, nil, states, nil, fakes.NewLockManager()).
+				WithFilter(tt.filter, "me@example.com").
+				WithTickets(search, puller)
+			if tt.sprints != nil {
+				service.WithSprintScope(domain.SprintScope{BoardID: 3}, tt.sprints)
+			}
+			ctx := context.Background()
+
+			run := service.SyncProject
+			if tt.full {
+				run = service.FullSyncProject
+			}
+			if err := run(ctx, "JMD"); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+
+			if strings.Join(search.queries, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("searched %q, want %q", search.queries, tt.want)
+			}
+			if len(tt.want) == 0 {
+				if len(puller.pulled) != 0 {
+					t.Errorf("pulled %v, want nothing", puller.pulled)
+				}
+				return
+			}
+			if strings.Join(puller.pulled, ",") != "JMD-1,JMD-2" || puller.forced != tt.full {
+				t.Errorf("pulled %v (forced %v), want JMD-1,JMD-2 (forced %v)", puller.pulled, puller.forced, tt.full)
+			}
+
+			state, err := states.GetProjectState(ctx, "JMD")
+			if err != nil {
+				t.Fatalf("GetProjectState failed: %v", err)
+			}
+			if state.TicketCount != 2 || state.LastIncrementalSync.IsZero() || state.LastFullSync.IsZero() != !tt.full {
+				t.Errorf("project state = %+v, want 2 tickets synced (full %v)", state, tt.full)
+			}
+		})
+	}
+}
+
+func TestService_SyncProject_Guardrails(t *testing.T) {
+	states := fakes.NewStateRepository()
+	puller := &recordedPuller{states: states}
+	service := NewService(fakes.NewTicketRepository(), nil, nil, states, nil, fakes.NewLockManager()).
+		WithGuardrails(domain.Guardrails{MaxTicketsPerProject: 2}).
+		WithTickets(&recordedSearch{tickets: searchTickets(t, 5)}, puller)
+	ctx := context.Background()
+
+	if err := service.SyncProject(ctx, "JMD"); err != nil {
+		t.Fatalf("SyncProject failed: %v", err)
+	}
+
+	// Pulling stops at the limit, with a warning, and the pull is not recorded
+	if strings.Join(puller.pulled, ",") != "JMD-1,JMD-2" {
+		t.Errorf("pulled %v, want JMD-1,JMD-2", puller.pulled)
+	}
+	report := service.LastReport()
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "max_tickets_per_project") {
+		t.Errorf("warnings = %q, want one about max_tickets_per_project", report.Warnings)
+	}
+	if _, err := states.GetProjectState(ctx, "JMD"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetProjectState error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestService_SyncProject_RemovesOutOfScope(t *testing.T) {
+	tickets := searchTickets(t, 3)
+	tickets[0].IssueType = "Bug"
+	tickets[1].IssueType = "Task"
+	tickets[2].IssueType = "Task"
+	ticketRepo := fakes.NewTicketRepository(tickets...)
+	states := fakes.NewStateRepository(
+		&repository.TicketSyncState{TicketKey: "JMD-1"},
+		&repository.TicketSyncState{TicketKey: "JMD-2"},
+		&repository.TicketSyncState{TicketKey: "JMD-3", IsDirty: true},
+	)
+	service := NewService(ticketRepo, nil, nil, states, nil, fakes.NewLockManager()).
+		WithFilter(domain.SyncFilter{IssueTypes: []string{"Bug"}}, "")
+	ctx := context.Background()
+
+	if err := service.SyncProject(ctx, "JMD"); err != nil {
+		t.Fatalf("SyncProject failed: %v", err)
+	}
+
+	// JMD-2 fell out of scope; JMD-3 did too, but has local changes
+	for key, want := range map[string]bool{"JMD-1": true, "JMD-2": false, "JMD-3": true} {
+		_, err := ticketRepo.FindByKey(ctx, key)
+		if cached := err == nil; cached != want {
+			t.Errorf("%s cached = %v, want %v", key, cached, want)
+		}
+	}
+	report := service.LastReport()
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "JMD-3") {
+		t.Errorf("warnings = %q, want one keeping JMD-3", report.Warnings)
+	}
+}
@@ -0,0 +1,125 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// TransitionResult is the outcome of moving one ticket in TransitionMatching.
+type TransitionResult struct {
+	// Key is the ticket's key
+	Key domain.TicketKey
+
+	// Summary and From are the ticket's summary and status before the move
+	Summary string
+	From    string
+
+	// Operation is the queued status push (nil when the ticket was not moved, or
+	// status is local_only)
+	Operation *domain.PendingOperation
+
+	// Skipped is set when the ticket was already in the status
+	Skipped bool
+
+	// Err is why the ticket was not moved (nil on success)
+	Err error
+}
+
+// TransitionReport is the outcome of a bulk transition, with one result per matching
+// ticket in the filter's order.
+type TransitionReport struct {
+	Status  string
+	DryRun  bool
+	Results []TransitionResult
+}
+
+// Moved returns the number of tickets moved, or that would be moved in a dry run.
+func (r *TransitionReport) Moved() int {
+	count := 0
+	for _, result := range r.Results {
+		if !result.Skipped && result.Err == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Skipped returns the number of tickets already in the status.
+func (r *TransitionReport) Skipped() int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Skipped {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns the number of tickets that could not be moved.
+func (r *TransitionReport) Failed() int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// TransitionMatching moves every cached ticket matching filter, JQL evaluated against
+// the local cache (see domain.ParseTicketFilter), to status, queuing a status push for
+// each as Transition does. Tickets are moved one by one, so a ticket that cannot be
+// moved, e.g. because its workflow has no transition to status, does not stop the
+// others: its result carries the error. Tickets already in status are skipped. A dry run
+// checks each ticket as Transition would, against the field directions, the project's
+// metadata and the ticket's available transitions, without changing anything.
+// Returns domain.ErrInvalidInput for a malformed filter or an empty status.
+func (s *Service) TransitionMatching(ctx context.Context, filter, status string, dryRun bool) (*TransitionReport, error) {
+	status = s.statuses.Resolve(status)
+	if status == "" {
+		return nil, fmt.Errorf("%w: status is required", domain.ErrInvalidInput)
+	}
+	parsed, err := domain.ParseTicketFilter(filter, domain.FilterOptions{CurrentUser: s.currentUser, Now: s.now()})
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := s.ticketRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket cache: %w", err)
+	}
+
+	report := &TransitionReport{Status: status, DryRun: dryRun}
+	for _, ticket := range parsed.Apply(tickets) {
+		result := TransitionResult{Key: ticket.Key, Summary: ticket.Summary, From: ticket.Status}
+		switch {
+		case strings.EqualFold(ticket.Status, status):
+			result.Skipped = true
+		case dryRun:
+			result.Err = s.checkTransition(ctx, ticket, status)
+		default:
+			result.Operation, result.Err = s.Transition(ctx, ticket.Key.String(), status)
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// checkTransition makes the checks Transition makes before moving ticket to status,
+// without changing anything.
+func (s *Service) checkTransition(ctx context.Context, ticket *domain.Ticket, status string) error {
+	if _, err := s.checkEditable(ctx, ticket.Key, "status"); err != nil {
+		return err
+	}
+	metadata := s.projectMetadata(ctx, ticket.Key.ProjectKey())
+	if metadata == nil {
+		return nil
+	}
+	status, err := metadata.AllowedValue(domain.MetadataFieldStatus, status)
+	if err != nil {
+		return err
+	}
+	return metadata.CheckTransition(ticket.Status, status)
+}
@@ -0,0 +1,123 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/domain/repository/fakes"
+)
+
+// staticMetadata is a MetadataSource serving the same metadata for every project.
+type staticMetadata domain.ProjectMetadata
+
+func (m *staticMetadata) Get(ctx context.Context, projectKey string) (*domain.ProjectMetadata, error) {
+	metadata := domain.ProjectMetadata(*m)
+	metadata.ProjectKey = projectKey
+	return &metadata, nil
+}
+
+// statusTicket returns ticket key in status.
+func statusTicket(t *testing.T, key, status string) *domain.Ticket {
+	t.Helper()
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		t.Fatalf("NewTicketKey(%s) failed: %v", key, err)
+	}
+	updated := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := domain.NewTicket(ticketKey, "Ticket "+key, updated, updated)
+	ticket.Status = status
+	return ticket
+}
+
+func TestService_TransitionMatching(t *testing.T) {
+	metadata := &staticMetadata{
+		Statuses: []string{"To Do", "In Progress", "In Review", "Done"},
+		Transitions: map[string][]string{
+			"In Progress": {"In Review"},
+			"In Review":   {"Done", "In Progress"},
+		},
+	}
+	type want struct {
+		key     string
+		skipped bool
+		err     error
+	}
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "dry run", dryRun: true},
+		{name: "move"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets := fakes.NewTicketRepository(
+				statusTicket(t, "JMD-1", "In Review"),
+				statusTicket(t, "JMD-2", "Done"),
+				statusTicket(t, "JMD-3", "In Progress"),
+				statusTicket(t, "JMD-4", "In Review"),
+				statusTicket(t, "OPS-1", "In Review"),
+			)
+			states := fakes.NewStateRepository(&repository.TicketSyncState{TicketKey: "JMD-4", PullOnly: true})
+			queue := fakes.NewPendingOperationRepository()
+			service := NewService(tickets, states, queue, fakes.NewLockManager()).WithMetadata(metadata)
+			ctx := context.Background()
+
+			report, err := service.TransitionMatching(ctx, "project = JMD", "done", tt.dryRun)
+			if err != nil {
+				t.Fatalf("TransitionMatching failed: %v", err)
+			}
+
+			// The dry run reports the same failures the move does
+			wants := []want{
+				{key: "JMD-1"},
+				{key: "JMD-2", skipped: true},
+				{key: "JMD-3", err: domain.ErrInvalidFieldValue},
+				{key: "JMD-4", err: domain.ErrInvalidInput},
+			}
+			if len(report.Results) != len(wants) {
+				t.Fatalf("got %d results, want %d: %+v", len(report.Results), len(wants), report.Results)
+			}
+			for i, w := range wants {
+				result := report.Results[i]
+				if result.Key.String() != w.key || result.Skipped != w.skipped {
+					t.Errorf("result %d = %s (skipped %v), want %s (skipped %v)", i, result.Key, result.Skipped, w.key, w.skipped)
+				}
+				if (w.err == nil) != (result.Err == nil) || (w.err != nil && !errors.Is(result.Err, w.err)) {
+					t.Errorf("%s error = %v, want %v", w.key, result.Err, w.err)
+				}
+			}
+			if report.Moved() != 1 || report.Skipped() != 1 || report.Failed() != 2 {
+				t.Errorf("moved %d, skipped %d, failed %d, want 1, 1, 2", report.Moved(), report.Skipped(), report.Failed())
+			}
+
+			moved, err := tickets.FindByKey(ctx, "JMD-1")
+			if err != nil {
+				t.Fatalf("FindByKey failed: %v", err)
+			}
+			queued, err := queue.FindByProject(ctx, "JMD")
+			if err != nil {
+				t.Fatalf("FindByProject failed: %v", err)
+			}
+			if tt.dryRun {
+				if moved.Status != "In Review" || len(queued) != 0 || report.Results[0].Operation != nil {
+					t.Errorf("dry run moved JMD-1 to %q and queued %d operations, want nothing changed", moved.Status, len(queued))
+				}
+				return
+			}
+
+			if moved.Status != "Done" {
+				t.Errorf("JMD-1 status = %q, want Done", moved.Status)
+			}
+			var payload StatusPayload
+			if len(queued) != 1 || json.Unmarshal([]byte(queued[0].Payload), &payload) != nil || payload.Status != "Done" {
+				t.Errorf("queued %v, want one push of status Done", queued)
+			}
+		})
+	}
+}
@@ -0,0 +1,699 @@
+// Package ticket contains use cases for working with individual tickets from the CLI.
+// Changes are applied to the local cache immediately and queued for the next push to Jira.
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/application/events"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// DefaultIssueType is used by Create when no issue type is given.
+const DefaultIssueType = "Task"
+
+// StatusPayload is the payload of a queued domain.OpPushStatus operation.
+type StatusPayload struct {
+	Status string `json:"status"`
+}
+
+// FieldPayload is the payload of a queued domain.OpPushField operation.
+type FieldPayload struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+
+	// Fields are the values of further fields set along with the change, by Jira field
+	// ID: those the new issue type of a type change requires (see ChangeType)
+	Fields map[string]string `json:"fields,omitempty"`
+
+	// Add and Remove are the labels a label edit adds and removes, for the field
+	// "labels" (see Label), which is pushed as such rather than as the whole list
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// CommentPayload is the payload of a queued domain.OpPostComment operation.
+type CommentPayload struct {
+	Body string `json:"body"`
+
+	// Visibility restricts who can see the comment, as written by
+	// domain.CommentVisibility.String ("" for public)
+	Visibility string `json:"visibility,omitempty"`
+
+	// Author is the person the comment is posted on behalf of, as
+	// domain.Comment.OnBehalfOf ("" for unattributed)
+	Author string `json:"author,omitempty"`
+}
+
+// CreatePayload is the payload of a queued domain.OpCreateTicket operation.
+type CreatePayload struct {
+	Summary     string   `json:"summary"`
+	IssueType   string   `json:"issue_type"`
+	Description string   `json:"description,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	Assignee    string   `json:"assignee,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+}
+
+// Details is everything known locally about a ticket.
+type Details struct {
+	// Ticket is the cached ticket content, including unpushed local changes
+	Ticket *domain.Ticket
+
+	// State is the ticket's sync state (nil if the ticket has never been synced)
+	State *repository.TicketSyncState
+
+	// Pending lists local changes not yet pushed to Jira, oldest first
+	Pending []*domain.PendingOperation
+
+	// Pushed lists the local changes last pushed to Jira, most recent first, at most
+	// PushHistoryLimit of them
+	Pushed []*domain.PendingOperation
+}
+
+// PushHistoryLimit is how many pushed changes View lists.
+const PushHistoryLimit = 10
+
+// MetadataSource provides the project metadata edits are validated against
+// (implemented by the metadata service, which serves it from the cache).
+type MetadataSource interface {
+	Get(ctx context.Context, projectKey string) (*domain.ProjectMetadata, error)
+}
+
+// AssigneeResolver finds the user an assignee name refers to among those who can be
+// assigned tickets in a project (implemented by the user service).
+type AssigneeResolver interface {
+	// ResolveAssignee returns domain.ErrInvalidFieldValue when name is ambiguous or
+	// nobody assignable matches it, and other errors when that cannot be told
+	ResolveAssignee(ctx context.Context, projectKey, name string) (*domain.User, error)
+}
+
+// RequiredFieldSource tells which fields a ticket needs values for before its issue type
+// can change (implemented by the Jira client).
+type RequiredFieldSource interface {
+	// MissingFieldsForType returns domain.ErrInvalidInput when Jira cannot make the
+	// change, and domain.ErrInvalidFieldValue for unknown issue types
+	MissingFieldsForType(ctx context.Context, key, issueType string) ([]domain.RequiredField, error)
+}
+
+// ChangeAuthors tells who made the local changes to a ticket when several people edit the
+// markdown directory (implemented by markdown.ChangeAuthors).
+type ChangeAuthors interface {
+	// ChangeAuthor returns the author of the latest local change to the ticket, or ""
+	// when it cannot be told
+	ChangeAuthor(ctx context.Context, key domain.TicketKey) string
+}
+
+// Service handles ticket use cases against the local cache and push queue.
+//
+// Error contract: Methods return domain.ErrNotFound when the ticket is not cached,
+// domain.ErrInvalidInput for invalid arguments and changes to pull-only tickets,
+// domain.ErrInvalidFieldValue for values the project's metadata does not allow,
+// domain.ErrReadOnlyField for edits of fields that only sync from Jira, and wrapped
+// errors for storage failures.
+type Service struct {
+	ticketRepo repository.TicketRepository
+	stateRepo  repository.StateRepository
+	queue      repository.PendingOperationRepository
+	locks      repository.LockManager
+	now        func() time.Time
+
+	// fieldDirections returns the field direction overrides of a project (nil for none)
+	fieldDirections func(projectKey string) domain.FieldDirections
+
+	// statuses resolves status aliases to local status names
+	statuses domain.StatusMap
+
+	// events receives the domain events of each saved change
+	events events.Publisher
+
+	// currentUser is the Jira user changes are made as, the author of staged comments
+	currentUser string
+
+	// author is the person local changes are made by, unless changeAuthors names
+	// another ("" for unattributed changes)
+	author string
+
+	// changeAuthors tells who made each change in a shared vault (nil uses author)
+	changeAuthors ChangeAuthors
+
+	// metadata validates edited values before they are queued (nil skips validation)
+	metadata MetadataSource
+
+	// assignees validates edited assignees before they are queued (nil skips validation)
+	assignees AssigneeResolver
+
+	// requiredFields checks issue type changes before they are queued (nil skips the check)
+	requiredFields RequiredFieldSource
+}
+
+// NewService creates a new ticket service.
+// The queue must share transactions with stateRepo so a change and its queued push are atomic.
+// Changes hold the ticket's lock from locks, which may be nil when no sync runs concurrently.
+func NewService(
+	ticketRepo repository.TicketRepository,
+	stateRepo repository.StateRepository,
+	queue repository.PendingOperationRepository,
+	locks repository.LockManager,
+) *Service {
+	return &Service{
+		ticketRepo: ticketRepo,
+		stateRepo:  stateRepo,
+		queue:      queue,
+		locks:      locks,
+		now:        time.Now,
+		events:     events.Nop(),
+	}
+}
+
+// WithFieldDirections sets the field direction overrides edits honor, usually
+// domain.SyncConfig.FieldDirectionsFor. Edits of jira_to_local fields are refused and
+// edits of local_only fields are kept in the cache without queuing a push.
+func (s *Service) WithFieldDirections(directions func(projectKey string) domain.FieldDirections) *Service {
+	s.fieldDirections = directions
+	return s
+}
+
+// WithStatuses sets the status names Transition accepts: a status may be given by an
+// alias, which is stored and pushed as the local name it stands for.
+func (s *Service) WithStatuses(statuses domain.StatusMap) *Service {
+	s.statuses = statuses
+	return s
+}
+
+// WithEvents sets the publisher that receives the domain events of each change once it
+// is saved, e.g. for an audit log or hooks.
+func (s *Service) WithEvents(publisher events.Publisher) *Service {
+	s.events = events.OrNop(publisher)
+	return s
+}
+
+// WithCurrentUser sets the Jira user (usually jira.email) changes are made as, which
+// authors staged comments until Jira creates them.
+func (s *Service) WithCurrentUser(user string) *Service {
+	s.currentUser = user
+	return s
+}
+
+// WithAuthor sets the person local changes are made by. The author is recorded with
+// each queued push, and attributes staged comments when currentUser is an account
+// shared by a team (see domain.CommentAttribution).
+func (s *Service) WithAuthor(author string) *Service {
+	s.author = author
+	return s
+}
+
+// WithChangeAuthors makes changes to a ticket attributed to the person changeAuthors
+// names, e.g. from git history when the markdown directory is shared; changes it cannot
+// tell the author of stay attributed to the author set with WithAuthor.
+func (s *Service) WithChangeAuthors(changeAuthors ChangeAuthors) *Service {
+	s.changeAuthors = changeAuthors
+	return s
+}
+
+// WithMetadata sets the project metadata that edited statuses, priorities, and issue
+// types are checked against before a push is queued, so invalid values are reported
+// precisely instead of failing the push. Values are not checked while a project's
+// metadata is unavailable.
+func (s *Service) WithMetadata(metadata MetadataSource) *Service {
+	s.metadata = metadata
+	return s
+}
+
+// WithAssignees sets how Assign checks that the new assignee can be assigned tickets in
+// the project, storing them under their canonical name (see domain.User.Name). Names are
+// not checked when the resolver cannot tell, e.g. offline with an empty user cache.
+func (s *Service) WithAssignees(assignees AssigneeResolver) *Service {
+	s.assignees = assignees
+	return s
+}
+
+// WithRequiredFields sets how ChangeType finds the fields a new issue type requires, so
+// a change Jira would reject is not queued.
+func (s *Service) WithRequiredFields(requiredFields RequiredFieldSource) *Service {
+	s.requiredFields = requiredFields
+	return s
+}
+
+// View returns the cached ticket together with its sync state and queued changes.
+func (s *Service) View(ctx context.Context, key string) (*Details, error) {
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := s.ticketRepo.FindByKey(ctx, ticketKey.String())
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.stateRepo.GetTicketState(ctx, ticketKey.String())
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get ticket state: %w", err)
+	}
+
+	pending, err := s.queue.FindByTicketKey(ctx, ticketKey.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending operations: %w", err)
+	}
+	pushed, err := s.queue.FindCompletedByTicketKey(ctx, ticketKey.String(), PushHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push history: %w", err)
+	}
+
+	return &Details{Ticket: ticket, State: state, Pending: pending, Pushed: pushed}, nil
+}
+
+// Create queues a new ticket for creation in Jira.
+// The ticket only enters the local cache once Jira has assigned it a key during the next push.
+func (s *Service) Create(ctx context.Context, projectKey string, payload CreatePayload) (*domain.PendingOperation, error) {
+	payload.Summary = strings.TrimSpace(payload.Summary)
+	if payload.Summary == "" {
+		return nil, fmt.Errorf("%w: summary is required", domain.ErrInvalidInput)
+	}
+	payload.IssueType = strings.TrimSpace(payload.IssueType)
+	if payload.IssueType == "" {
+		payload.IssueType = DefaultIssueType
+	}
+	payload.Priority = strings.TrimSpace(payload.Priority)
+	if payload.Assignee = strings.TrimSpace(payload.Assignee); payload.Assignee != "" {
+		var err error
+		if payload.Assignee, err = s.resolveAssignee(ctx, projectKey, payload.Assignee); err != nil {
+			return nil, err
+		}
+	}
+
+	if metadata := s.projectMetadata(ctx, projectKey); metadata != nil {
+		var err error
+		if payload.IssueType, err = metadata.AllowedValue(domain.MetadataFieldIssueType, payload.IssueType); err != nil {
+			return nil, err
+		}
+		if payload.Priority != "" {
+			if payload.Priority, err = metadata.AllowedValue(domain.MetadataFieldPriority, payload.Priority); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	op, err := s.newOperation(ctx, projectKey, domain.TicketKey{}, domain.OpCreateTicket, payload, s.author)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.queue.Enqueue(ctx, op); err != nil {
+		return nil, fmt.Errorf("failed to queue ticket creation: %w", err)
+	}
+
+	return op, nil
+}
+
+// Transition moves a cached ticket to a new status, given by its local name or an alias
+// (see WithStatuses), and queues the status push.
+// Returns a nil operation when status is local_only, so nothing is pushed.
+func (s *Service) Transition(ctx context.Context, key, status string) (*domain.PendingOperation, error) {
+	status = s.statuses.Resolve(status)
+	if status == "" {
+		return nil, fmt.Errorf("%w: status is required", domain.ErrInvalidInput)
+	}
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, err
+	}
+	metadata := s.projectMetadata(ctx, ticketKey.ProjectKey())
+	if metadata != nil {
+		if status, err = metadata.AllowedValue(domain.MetadataFieldStatus, status); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.change(ctx, key, "status", func(ticket *domain.Ticket) (domain.OperationType, interface{}, error) {
+		if metadata != nil {
+			if err := metadata.CheckTransition(ticket.Status, status); err != nil {
+				return "", nil, err
+			}
+		}
+		if err := ticket.ChangeStatus(status, s.now()); err != nil {
+			return "", nil, err
+		}
+		return domain.OpPushStatus, StatusPayload{Status: status}, nil
+	})
+}
+
+// Assign sets the assignee of a cached ticket and queues the field push.
+// Returns a nil operation when assignee is local_only, so nothing is pushed.
+func (s *Service) Assign(ctx context.Context, key, assignee string) (*domain.PendingOperation, error) {
+	assignee = strings.TrimSpace(assignee)
+	if assignee == "" {
+		return nil, fmt.Errorf("%w: assignee is required", domain.ErrInvalidInput)
+	}
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if assignee, err = s.resolveAssignee(ctx, ticketKey.ProjectKey(), assignee); err != nil {
+		return nil, err
+	}
+
+	return s.change(ctx, key, "assignee", func(ticket *domain.Ticket) (domain.OperationType, interface{}, error) {
+		if ticket.Assignee == assignee {
+			return "", nil, fmt.Errorf("%w: %s is already assigned to %s", domain.ErrInvalidInput, ticket.Key, assignee)
+		}
+		if err := ticket.ChangeField("assignee", assignee, s.now()); err != nil {
+			return "", nil, err
+		}
+		return domain.OpPushField, FieldPayload{Field: "assignee", Value: assignee}, nil
+	})
+}
+
+// ChangeType changes the issue type of a cached ticket and queues the push, along with
+// fields, the values of the fields the new type requires by Jira field ID. The type is
+// checked against the project's metadata, and with Jira when a required-field source is
+// set (see WithRequiredFields): ChangeType then returns a *domain.MissingFieldsError
+// naming the required fields fields lacks, and domain.ErrInvalidInput for changes Jira
+// cannot make, such as turning a sub-task into a Story. When Jira cannot be reached, the
+// change is queued unchecked and checked again when it is pushed.
+// Returns a nil operation when issuetype is local_only, so nothing is pushed.
+func (s *Service) ChangeType(ctx context.Context, key, issueType string, fields map[string]string) (*domain.PendingOperation, error) {
+	issueType = strings.TrimSpace(issueType)
+	if issueType == "" {
+		return nil, fmt.Errorf("%w: issue type is required", domain.ErrInvalidInput)
+	}
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if metadata := s.projectMetadata(ctx, ticketKey.ProjectKey()); metadata != nil {
+		if issueType, err = metadata.AllowedValue(domain.MetadataFieldIssueType, issueType); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkRequiredFields(ctx, ticketKey, issueType, fields); err != nil {
+		return nil, err
+	}
+
+	return s.change(ctx, key, "issuetype", func(ticket *domain.Ticket) (domain.OperationType, interface{}, error) {
+		if err := ticket.ChangeField("issuetype", issueType, s.now()); err != nil {
+			return "", nil, err
+		}
+		return domain.OpPushField, FieldPayload{Field: "issuetype", Value: issueType, Fields: fields}, nil
+	})
+}
+
+// Label adds the labels add to a cached ticket and removes the labels remove, and
+// queues the push of the edit rather than of the whole list, so labels added or removed
+// in Jira since the last sync are kept. A label in both lists is added.
+// Returns a nil operation when labels is local_only, so nothing is pushed.
+func (s *Service) Label(ctx context.Context, key string, add, remove []string) (*domain.PendingOperation, error) {
+	add = domain.NormalizeLabels(add)
+	remove = slices.DeleteFunc(domain.NormalizeLabels(remove), func(label string) bool {
+		return slices.Contains(add, label)
+	})
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, fmt.Errorf("%w: labels to add or remove are required", domain.ErrInvalidInput)
+	}
+
+	return s.change(ctx, key, "labels", func(ticket *domain.Ticket) (domain.OperationType, interface{}, error) {
+		if err := ticket.EditLabels(add, remove, s.now()); err != nil {
+			return "", nil, err
+		}
+		return domain.OpPushField, FieldPayload{Field: "labels", Add: add, Remove: remove}, nil
+	})
+}
+
+// checkRequiredFields returns a *domain.MissingFieldsError when fields lacks a value for
+// a field issueType requires, and the errors of the required-field source telling that
+// the change is invalid. Other failures, such as Jira being unreachable, are ignored.
+func (s *Service) checkRequiredFields(ctx context.Context, key domain.TicketKey, issueType string, fields map[string]string) error {
+	if s.requiredFields == nil {
+		return nil
+	}
+	required, err := s.requiredFields.MissingFieldsForType(ctx, key.String(), issueType)
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrInvalidFieldValue):
+		return err
+	case err != nil:
+		return nil
+	}
+
+	var missing []domain.RequiredField
+	for _, field := range required {
+		if strings.TrimSpace(fields[field.ID]) == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return &domain.MissingFieldsError{Key: key, IssueType: issueType, Fields: missing}
+	}
+	return nil
+}
+
+// Comment stages a comment on a cached ticket and queues it for posting, restricted to
+// visibility (see domain.ParseCommentVisibility; "" for public).
+// Returns domain.ErrInvalidInput for an empty body or unknown visibility, or when no
+// current user is set (see WithCurrentUser).
+func (s *Service) Comment(ctx context.Context, key, body, visibility string) (*domain.PendingOperation, error) {
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, err
+	}
+	restriction, err := domain.ParseCommentVisibility(visibility)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPushable(ctx, ticketKey); err != nil {
+		return nil, err
+	}
+
+	ticket, err := s.ticketRepo.FindByKey(ctx, ticketKey.String())
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	comment := &domain.Comment{
+		TicketKey:  ticketKey,
+		Author:     s.currentUser,
+		Body:       strings.TrimSpace(body),
+		Created:    now,
+		Updated:    now,
+		Visibility: restriction,
+		OnBehalfOf: s.authorOf(ctx, ticketKey),
+	}
+	if err := ticket.AddComment(comment); err != nil {
+		return nil, err
+	}
+
+	op, err := s.newOperation(ctx, ticketKey.ProjectKey(), ticketKey, domain.OpPostComment, CommentPayload{
+		Body:       comment.Body,
+		Visibility: restriction.String(),
+		Author:     comment.OnBehalfOf,
+	}, comment.OnBehalfOf)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.queue.Enqueue(ctx, op); err != nil {
+		return nil, fmt.Errorf("failed to queue comment: %w", err)
+	}
+	s.events.Publish(ctx, ticket.DrainEvents())
+
+	return op, nil
+}
+
+// change applies a local edit of field to a cached ticket, marks it dirty, and queues the
+// push, all in one transaction while holding the ticket's lock, so a concurrent sync of
+// the ticket cannot overwrite the edit half-way. Edits of local_only fields are only
+// cached and return a nil operation. The events the edit recorded are published once
+// the transaction is committed.
+func (s *Service) change(
+	ctx context.Context,
+	key string,
+	field string,
+	edit func(ticket *domain.Ticket) (domain.OperationType, interface{}, error),
+) (op *domain.PendingOperation, err error) {
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	direction, err := s.checkEditable(ctx, ticketKey, field)
+	if err != nil {
+		return nil, err
+	}
+	author := s.authorOf(ctx, ticketKey)
+
+	if s.locks != nil {
+		unlock, lockErr := s.locks.Lock(ctx, ticketKey.String())
+		if lockErr != nil {
+			return nil, fmt.Errorf("failed to lock ticket %s: %w", ticketKey, lockErr)
+		}
+		defer func() {
+			err = errors.Join(err, unlock())
+		}()
+	}
+
+	txCtx, err := s.stateRepo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.stateRepo.Rollback(txCtx)
+		}
+	}()
+
+	ticket, err := s.ticketRepo.FindByKey(txCtx, ticketKey.String())
+	if err != nil {
+		return nil, err
+	}
+
+	operation, payload, err := edit(ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.ticketRepo.Update(txCtx, ticket); err != nil {
+		return nil, fmt.Errorf("failed to update cached ticket: %w", err)
+	}
+
+	if direction == domain.SyncLocalOnly {
+		if err = s.stateRepo.Commit(txCtx); err != nil {
+			return nil, err
+		}
+		s.events.Publish(ctx, ticket.DrainEvents())
+		return nil, nil
+	}
+
+	if err = s.markDirty(txCtx, ticketKey); err != nil {
+		return nil, err
+	}
+
+	op, err = s.newOperation(txCtx, ticketKey.ProjectKey(), ticketKey, operation, payload, author)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.queue.Enqueue(txCtx, op); err != nil {
+		return nil, fmt.Errorf("failed to queue change: %w", err)
+	}
+
+	if err = s.stateRepo.Commit(txCtx); err != nil {
+		return nil, err
+	}
+	s.events.Publish(ctx, ticket.DrainEvents())
+
+	return op, nil
+}
+
+// projectMetadata returns the metadata of a project to validate edits against, or nil
+// when there is none to validate with: no source is set, or the metadata is neither
+// cached nor fetchable (e.g. offline before the first fetch).
+func (s *Service) projectMetadata(ctx context.Context, projectKey string) *domain.ProjectMetadata {
+	if s.metadata == nil {
+		return nil
+	}
+	metadata, err := s.metadata.Get(ctx, projectKey)
+	if err != nil {
+		return nil
+	}
+	return metadata
+}
+
+// resolveAssignee returns the canonical name of the assignable user name refers to in a
+// project, or name unchanged when there is no resolver or it cannot tell.
+// Returns domain.ErrInvalidFieldValue when name is ambiguous or not assignable.
+func (s *Service) resolveAssignee(ctx context.Context, projectKey, name string) (string, error) {
+	if s.assignees == nil {
+		return name, nil
+	}
+	user, err := s.assignees.ResolveAssignee(ctx, projectKey, name)
+	switch {
+	case err == nil:
+		return user.Name(), nil
+	case errors.Is(err, domain.ErrInvalidFieldValue):
+		return "", err
+	}
+	return name, nil
+}
+
+// checkEditable refuses changes of field that cannot be made locally: fields synced
+// jira_to_local, and any field of a ticket that is not pushable (see checkPushable).
+// Returns the direction field is synced in otherwise.
+func (s *Service) checkEditable(ctx context.Context, key domain.TicketKey, field string) (domain.SyncDirection, error) {
+	direction := domain.SyncBidirectional
+	if s.fieldDirections != nil {
+		direction = s.fieldDirections(key.ProjectKey()).Direction(field)
+	}
+	if direction == domain.SyncJiraToLocal {
+		return "", fmt.Errorf("%w; change it in Jira instead", domain.ReadOnlyFieldError(key, []string{field}))
+	}
+	if err := s.checkPushable(ctx, key); err != nil {
+		return "", err
+	}
+	return direction, nil
+}
+
+// checkPushable refuses changes to tickets pulled ad hoc from outside the sync scope,
+// which are never pushed.
+func (s *Service) checkPushable(ctx context.Context, key domain.TicketKey) error {
+	state, err := s.stateRepo.GetTicketState(ctx, key.String())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get ticket state: %w", err)
+	}
+	if state.PullOnly {
+		return fmt.Errorf("%w: %s was pulled ad hoc and is pull-only; change it in Jira instead",
+			domain.ErrInvalidInput, key)
+	}
+	return nil
+}
+
+// markDirty records that a ticket has local changes waiting to be pushed.
+func (s *Service) markDirty(ctx context.Context, key domain.TicketKey) error {
+	state, err := s.stateRepo.GetTicketState(ctx, key.String())
+	if errors.Is(err, domain.ErrNotFound) {
+		state = &repository.TicketSyncState{TicketKey: key.String()}
+	} else if err != nil {
+		return fmt.Errorf("failed to get ticket state: %w", err)
+	}
+
+	state.IsDirty = true
+	state.LastModifiedLocal = s.now().UTC()
+
+	if err := s.stateRepo.SaveTicketState(ctx, state); err != nil {
+		return fmt.Errorf("failed to save ticket state: %w", err)
+	}
+	return nil
+}
+
+// authorOf returns the person the latest local change to a ticket is attributed to.
+func (s *Service) authorOf(ctx context.Context, key domain.TicketKey) string {
+	if s.changeAuthors != nil {
+		if author := s.changeAuthors.ChangeAuthor(ctx, key); author != "" {
+			return author
+		}
+	}
+	return s.author
+}
+
+// newOperation builds a pending operation made by author, with a JSON-encoded payload.
+// Its correlation ID extends the one of ctx, if any, so the command or sync that queued
+// it can be found from its logs.
+func (s *Service) newOperation(ctx context.Context, projectKey string, key domain.TicketKey, operation domain.OperationType, payload interface{}, author string) (*domain.PendingOperation, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", operation, err)
+	}
+	op, err := domain.NewPendingOperation(projectKey, key, operation, string(data))
+	if err != nil {
+		return nil, err
+	}
+	op.Author = author
+	op.CorrelationID = domain.CorrelationIDFrom(ctx).Child()
+	return op, nil
+}
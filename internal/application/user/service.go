@@ -0,0 +1,229 @@
+// Package user contains use cases for looking up Jira users, e.g. to complete assignees
+// or resolve mentions. Lookups are served from a local directory cache, refreshed from
+// Jira once entries are older than a TTL, so they don't hammer the user search API.
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// DefaultTTL is how long looked up users and search results are served from the cache
+// before they are fetched from Jira again.
+const DefaultTTL = 24 * time.Hour
+
+// Directory looks users up in Jira (implemented by the Jira client).
+type Directory interface {
+	// SearchUsers returns the users whose name or email starts with query
+	SearchUsers(ctx context.Context, query string) ([]*domain.User, error)
+
+	// SearchAssignableUsers returns the users whose name or email starts with query
+	// and who can be assigned tickets in a project
+	SearchAssignableUsers(ctx context.Context, projectKey, query string) ([]*domain.User, error)
+
+	// GetUser returns the user with an account ID
+	GetUser(ctx context.Context, accountID string) (*domain.User, error)
+}
+
+// maxSuggestions is how many users an assignee error suggests at most.
+const maxSuggestions = 5
+
+// Service handles user lookup use cases.
+//
+// Error contract: Methods return domain.ErrEmptyKey for an empty account ID,
+// domain.ErrNotFound for unknown users, domain.ErrInvalidFieldValue for assignees that
+// cannot be assigned, and wrapped errors for Jira and storage failures. A lookup whose refresh fails falls back to stale cached entries when there
+// are any, logging the failure.
+type Service struct {
+	directory Directory
+	cache     repository.UserRepository
+	ttl       time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewService creates a new user service looking users up in directory and caching them
+// in cache.
+func NewService(directory Directory, cache repository.UserRepository) *Service {
+	return &Service{
+		directory: directory,
+		cache:     cache,
+		ttl:       DefaultTTL,
+		logger:    slog.Default(),
+		now:       time.Now,
+	}
+}
+
+// WithTTL sets how long cached entries are served before they are refreshed from Jira
+// (DefaultTTL when ttl <= 0).
+func (s *Service) WithTTL(ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	s.ttl = ttl
+	return s
+}
+
+// WithLogger sets where refresh failures are logged.
+func (s *Service) WithLogger(logger *slog.Logger) *Service {
+	if logger != nil {
+		s.logger = logger
+	}
+	return s
+}
+
+// SearchUsers returns the users whose name or email starts with query, ordered by
+// display name. A query searched in Jira within the TTL is answered from the cache; an
+// empty query lists every cached user without contacting Jira.
+func (s *Service) SearchUsers(ctx context.Context, query string) ([]*domain.User, error) {
+	query = domain.NormalizeUserQuery(query)
+	if query == "" {
+		return s.cache.SearchUsers(ctx, "")
+	}
+
+	searchedAt, err := s.cache.UserSearchedAt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if !searchedAt.IsZero() && now.Sub(searchedAt) < s.ttl {
+		return s.cache.SearchUsers(ctx, query)
+	}
+
+	found, err := s.directory.SearchUsers(ctx, query)
+	if err != nil {
+		return s.staleSearch(ctx, query, err)
+	}
+	if err := s.cache.SaveUsers(ctx, found, now); err != nil {
+		return nil, err
+	}
+	if err := s.cache.RecordUserSearch(ctx, query, now); err != nil {
+		return nil, err
+	}
+	return s.cache.SearchUsers(ctx, query)
+}
+
+// GetUser returns the user with an account ID, from the cache if it was fetched within
+// the TTL.
+func (s *Service) GetUser(ctx context.Context, accountID string) (*domain.User, error) {
+	accountID = strings.TrimSpace(accountID)
+	if accountID == "" {
+		return nil, fmt.Errorf("%w: account ID cannot be empty", domain.ErrEmptyKey)
+	}
+
+	cached, fetchedAt, err := s.cache.FindUser(ctx, accountID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	now := s.now()
+	if cached != nil && now.Sub(fetchedAt) < s.ttl {
+		return cached, nil
+	}
+
+	user, err := s.directory.GetUser(ctx, accountID)
+	if err != nil {
+		if cached != nil && !errors.Is(err, domain.ErrNotFound) {
+			s.logger.Warn("failed to refresh user, using cached entry", "account_id", accountID, "error", err)
+			return cached, nil
+		}
+		return nil, fmt.Errorf("failed to get user %s: %w", accountID, err)
+	}
+	if err := s.cache.SaveUsers(ctx, []*domain.User{user}, now); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ResolveAssignee returns the user name refers to among those who can be assigned
+// tickets in a project, by Jira's assignable user search: the one it identifies exactly
+// (by email, the part of it before the @, display name, or account ID), or the only
+// active one it matches as a search query. When Jira can't be reached, the cached
+// directory is searched instead, which cannot tell who is assignable.
+// Returns domain.ErrInvalidFieldValue, suggesting close matches, when name is ambiguous
+// or no assignable user matches it, and a wrapped Jira error when neither Jira nor the
+// cache can tell.
+func (s *Service) ResolveAssignee(ctx context.Context, projectKey, name string) (*domain.User, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: assignee is required", domain.ErrInvalidInput)
+	}
+
+	candidates, err := s.directory.SearchAssignableUsers(ctx, projectKey, name)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("failed to search assignable users of %s: %w", projectKey, err)
+		}
+		cached, cacheErr := s.cache.SearchUsers(ctx, domain.NormalizeUserQuery(name))
+		if cacheErr != nil || len(cached) == 0 {
+			return nil, fmt.Errorf("failed to search assignable users of %s: %w", projectKey, err)
+		}
+		s.logger.Warn("failed to search assignable users, using cached users", "project", projectKey, "query", name, "error", err)
+		candidates = cached
+	} else if err := s.cache.SaveUsers(ctx, candidates, s.now()); err != nil {
+		return nil, err
+	}
+
+	user, ambiguous := domain.FindUser(name, activeUsers(candidates))
+	if user != nil {
+		return user, nil
+	}
+	if len(ambiguous) > 0 {
+		return nil, fmt.Errorf("%w: assignee %q is ambiguous in %s; did you mean %s?",
+			domain.ErrInvalidFieldValue, name, projectKey, describeUsers(ambiguous[:min(maxSuggestions, len(ambiguous))]))
+	}
+
+	notAssignable := fmt.Errorf("%w: assignee %q cannot be assigned in %s", domain.ErrInvalidFieldValue, name, projectKey)
+	known, err := s.cache.SearchUsers(ctx, "")
+	if err != nil {
+		return nil, notAssignable
+	}
+	if suggestions := domain.SuggestUsers(name, activeUsers(known), maxSuggestions); len(suggestions) > 0 {
+		return nil, fmt.Errorf("%w; did you mean %s?", notAssignable, describeUsers(suggestions))
+	}
+	return nil, notAssignable
+}
+
+// staleSearch answers a search whose refresh failed with err from the cache, if any
+// cached user matches.
+func (s *Service) staleSearch(ctx context.Context, query string, err error) ([]*domain.User, error) {
+	cached, cacheErr := s.cache.SearchUsers(ctx, query)
+	if cacheErr != nil || len(cached) == 0 {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	s.logger.Warn("failed to search users, using cached entries", "query", query, "error", err)
+	return cached, nil
+}
+
+// activeUsers returns the users whose account is active.
+func activeUsers(users []*domain.User) []*domain.User {
+	active := make([]*domain.User, 0, len(users))
+	for _, user := range users {
+		if user.Active {
+			active = append(active, user)
+		}
+	}
+	return active
+}
+
+// describeUsers lists users for an error message, e.g.
+// "Alice Smith <alice@example.com> or Alicia Jones <alicia@example.com>".
+func describeUsers(users []*domain.User) string {
+	labels := make([]string, 0, len(users))
+	for _, user := range users {
+		labels = append(labels, user.Label())
+	}
+	if len(labels) <= 2 {
+		return strings.Join(labels, " or ")
+	}
+	return strings.Join(labels[:len(labels)-1], ", ") + ", or " + labels[len(labels)-1]
+}
@@ -3,6 +3,8 @@
 package config
 
 import (
+	"os"
+
 	"github.com/esfisher/jiramd/internal/domain"
 	infraConfig "github.com/esfisher/jiramd/internal/infrastructure/config"
 )
@@ -32,3 +34,91 @@ func Load(path string) (*domain.Config, error) {
 
 	return cfg, nil
 }
+
+// Origins of the config file path chosen by Discover.
+const (
+	// OriginFlag is a path given with --config
+	OriginFlag = "flag"
+
+	// OriginEnv is a path given in $JIRAMD_CONFIG
+	OriginEnv = "env"
+
+	// OriginProject is a project-local .jiramd.yaml found above the working directory
+	OriginProject = "project"
+
+	// OriginDefault is the per-user XDG config file
+	OriginDefault = "default"
+)
+
+// PathEnvVar names the environment variable that chooses the config file.
+const PathEnvVar = "JIRAMD_CONFIG"
+
+// Options selects where configuration is resolved from.
+type Options struct {
+	// Path is the YAML config file
+	Path string
+
+	// Origin records why Path was chosen, one of the Origin constants
+	Origin string
+
+	// Overrides are settings by dotted key (e.g. "sync.interval") that take precedence
+	// over environment variables and the file, typically from command-line flags
+	Overrides map[string]string
+
+	// OptionalFile lets a missing file contribute nothing instead of failing
+	OptionalFile bool
+}
+
+// Discover chooses the config file: flagPath when given, then $JIRAMD_CONFIG, then the
+// nearest .jiramd.yaml in dir or its parents, then the per-user default, which may be
+// missing so configuration can come from the environment alone.
+func Discover(flagPath, dir string) Options {
+	if flagPath != "" {
+		return Options{Path: flagPath, Origin: OriginFlag}
+	}
+	if path := os.Getenv(PathEnvVar); path != "" {
+		return Options{Path: path, Origin: OriginEnv}
+	}
+	if path, ok := infraConfig.FindProjectConfig(dir); ok {
+		return Options{Path: path, Origin: OriginProject}
+	}
+	return Options{Path: infraConfig.DefaultConfigPath(), Origin: OriginDefault, OptionalFile: true}
+}
+
+// NewLoader creates the loader that resolves configuration as described by opts:
+// overrides, then JIRAMD_* environment variables, then the file, then defaults.
+func NewLoader(opts Options) *infraConfig.Loader {
+	loader := infraConfig.NewLoader().WithOverrides(opts.Overrides)
+	if opts.OptionalFile {
+		loader = loader.WithOptionalFile()
+	}
+	return loader
+}
+
+// LoadWith loads and validates configuration resolved as described by opts.
+// Every problem in the configuration is reported in one domain.ConfigError, with the
+// config file line of each setting involved.
+func LoadWith(opts Options) (*domain.Config, error) {
+	resolution, err := Resolve(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Check(resolution); err != nil {
+		return nil, err
+	}
+
+	return resolution.Config, nil
+}
+
+// Resolve resolves configuration as described by opts and reports where each setting
+// came from. The result is not validated, so an incomplete configuration can still be
+// inspected; see Check.
+func Resolve(opts Options) (*infraConfig.Resolution, error) {
+	return NewLoader(opts).Resolve(opts.Path)
+}
+
+// Check reports every problem found while resolving or validating a configuration.
+func Check(resolution *infraConfig.Resolution) error {
+	return resolution.Check(infraConfig.NewValidator())
+}
@@ -110,3 +110,37 @@ func TestLoad_FileNotFound(t *testing.T) {
 		t.Error("Load() expected error for non-existent file, got nil")
 	}
 }
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("failed to create dirs: %v", err)
+	}
+	projectConfig := filepath.Join(root, ".jiramd.yaml")
+	if err := os.WriteFile(projectConfig, []byte("jira: {}\n"), 0644); err != nil {
+		t.Fatalf("failed to write project config: %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	t.Setenv(PathEnvVar, "")
+
+	if opts := Discover("/etc/jiramd.yaml", nested); opts.Path != "/etc/jiramd.yaml" || opts.Origin != OriginFlag {
+		t.Errorf("Discover() with a flag = %+v", opts)
+	}
+
+	if opts := Discover("", nested); opts.Path != projectConfig || opts.Origin != OriginProject {
+		t.Errorf("Discover() below a project = %+v, want %s", opts, projectConfig)
+	}
+
+	t.Setenv(PathEnvVar, "/env/config.yaml")
+	if opts := Discover("", nested); opts.Path != "/env/config.yaml" || opts.Origin != OriginEnv {
+		t.Errorf("Discover() with $%s = %+v", PathEnvVar, opts)
+	}
+	t.Setenv(PathEnvVar, "")
+
+	opts := Discover("", t.TempDir())
+	want := filepath.Join(root, "xdg", "jiramd", "config.yaml")
+	if opts.Path != want || opts.Origin != OriginDefault || !opts.OptionalFile {
+		t.Errorf("Discover() elsewhere = %+v, want optional %s", opts, want)
+	}
+}
@@ -0,0 +1,110 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AttachmentsField is the CustomFields key of the files attached to a ticket in Jira: a
+// list of maps with the keys id, filename, mime_type, size, and url. It is read-only.
+const AttachmentsField = "attachments"
+
+// Attachment is a file attached to a ticket in Jira.
+type Attachment struct {
+	ID       string
+	Filename string
+	MimeType string
+	Size     int64
+
+	// URL is where Jira serves the file's content
+	URL string
+}
+
+// AttachmentsValue returns the CustomFields value of a ticket's attachments, or the zero
+// value if it has none.
+func AttachmentsValue(attachments []Attachment) FieldValue {
+	if len(attachments) == 0 {
+		return FieldValue{}
+	}
+	list := make([]interface{}, 0, len(attachments))
+	for _, a := range attachments {
+		list = append(list, map[string]interface{}{
+			"id":        a.ID,
+			"filename":  a.Filename,
+			"mime_type": a.MimeType,
+			"size":      a.Size,
+			"url":       a.URL,
+		})
+	}
+	return NewFieldValue(list)
+}
+
+// Attachments returns the files attached to the ticket in Jira, in Jira's order.
+func (t *Ticket) Attachments() []Attachment {
+	list, _ := t.CustomFields[AttachmentsField].Raw().([]interface{})
+	attachments := make([]Attachment, 0, len(list))
+	for _, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		attachment := Attachment{
+			ID:       NewFieldValue(fields["id"]).String(),
+			Filename: NewFieldValue(fields["filename"]).String(),
+			MimeType: NewFieldValue(fields["mime_type"]).String(),
+			URL:      NewFieldValue(fields["url"]).String(),
+		}
+		attachment.Size, _ = NewFieldValue(fields["size"]).Int()
+		if attachment.ID != "" && attachment.Filename != "" {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments
+}
+
+// Attachment returns the attachment named filename, and whether the ticket has one.
+// When several share the name, the last one attached wins, as in Jira.
+func (t *Ticket) Attachment(filename string) (Attachment, bool) {
+	var found Attachment
+	ok := false
+	for _, attachment := range t.Attachments() {
+		if attachment.Filename == filename {
+			found, ok = attachment, true
+		}
+	}
+	return found, ok
+}
+
+// mediaRefPattern matches a Jira wiki media reference, !name! or !name|options! (e.g.
+// !screenshot.png|thumbnail!), capturing the name and the options.
+var mediaRefPattern = regexp.MustCompile(`!([^!|\s][^!|\n]*?)(\|[^!\n]*)?!`)
+
+// MediaRefs returns the names of the media text references with !name!, in order of
+// first reference, each once.
+func MediaRefs(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range mediaRefPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.TrimSpace(match[1])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ReplaceMediaRefs returns text with each media reference replaced by what replace
+// returns for its name and full reference (e.g. "screenshot.png|thumbnail"); references
+// for which replace returns false are left as they are.
+func ReplaceMediaRefs(text string, replace func(name, ref string) (string, bool)) string {
+	return mediaRefPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := mediaRefPattern.FindStringSubmatch(match)
+		if replacement, ok := replace(strings.TrimSpace(groups[1]), match[1:len(match)-1]); ok {
+			return replacement
+		}
+		return match
+	})
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTicket_Attachments(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	ticket := NewTicket(key, "Screenshots", time.Now(), time.Now())
+	if got := ticket.Attachments(); len(got) != 0 {
+		t.Errorf("Attachments() = %v, want none", got)
+	}
+
+	first := Attachment{ID: "10001", Filename: "shot.png", MimeType: "image/png", Size: 2048, URL: "https://x/10001"}
+	second := Attachment{ID: "10002", Filename: "shot.png", MimeType: "image/png", Size: 4096}
+	ticket.CustomFields[AttachmentsField] = AttachmentsValue([]Attachment{first, second})
+
+	if got := ticket.Attachments(); !slices.Equal(got, []Attachment{first, second}) {
+		t.Errorf("Attachments() = %v", got)
+	}
+	if got, ok := ticket.Attachment("shot.png"); !ok || got.ID != "10002" {
+		t.Errorf("Attachment(shot.png) = %v, %v; want the last one attached", got, ok)
+	}
+	if _, ok := ticket.Attachment("missing.png"); ok {
+		t.Error("Attachment(missing.png) found one")
+	}
+
+	// Values read back from the cache hold JSON numbers
+	ticket.CustomFields[AttachmentsField] = NewFieldValue([]interface{}{
+		map[string]interface{}{"id": "10003", "filename": "log.txt", "size": float64(12)},
+		"not an attachment",
+	})
+	if got := ticket.Attachments(); len(got) != 1 || got[0].Size != 12 {
+		t.Errorf("Attachments() = %v, want log.txt of 12 bytes", got)
+	}
+	if direction := (FieldDirections{AttachmentsField: SyncBidirectional}).Direction(AttachmentsField); direction != SyncJiraToLocal {
+		t.Errorf("Direction(%s) = %s, want read-only", AttachmentsField, direction)
+	}
+}
+
+func TestMediaRefs(t *testing.T) {
+	text := "See !shot.png! and !diagram v2.png|thumbnail!.\nWow! Such excitement! Again !shot.png!"
+	if got := MediaRefs(text); !slices.Equal(got, []string{"shot.png", "diagram v2.png"}) {
+		t.Errorf("MediaRefs() = %q", got)
+	}
+
+	got := ReplaceMediaRefs(text, func(name, ref string) (string, bool) {
+		if name != "diagram v2.png" {
+			return "", false
+		}
+		return "[" + ref + "]", true
+	})
+	if want := "See !shot.png! and [diagram v2.png|thumbnail].\nWow! Such excitement! Again !shot.png!"; got != want {
+		t.Errorf("ReplaceMediaRefs() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// statusUnauthorized is the HTTP status Jira answers requests with bad credentials with.
+const statusUnauthorized = 401
+
+// statusForbidden is the HTTP status Jira answers requests the user lacks a permission for.
+const statusForbidden = 403
+
+// AuthFailureThreshold is how many consecutive requests Jira must reject as
+// unauthenticated before the credentials are considered failed, e.g. because the API
+// token expired or was revoked.
+const AuthFailureThreshold = 3
+
+// AuthRecheckInterval is how long work that needs Jira stays paused after an
+// authentication failure once the credentials have failed, before it is tried again to
+// see whether they were fixed.
+const AuthRecheckInterval = 5 * time.Minute
+
+// AuthStatus tracks whether Jira accepts the configured credentials.
+type AuthStatus struct {
+	// ConsecutiveFailures is how many requests in a row were rejected with 401
+	ConsecutiveFailures int
+
+	// FailingSince is when the current streak of failures started (zero if none)
+	FailingSince time.Time
+
+	// LastFailureAt is when the most recent failure of the streak happened (zero if none)
+	LastFailureAt time.Time
+
+	// LastError is the most recent authentication failure
+	LastError string
+}
+
+// Failed reports whether the credentials have failed AuthFailureThreshold times in a row.
+func (s AuthStatus) Failed() bool {
+	return s.ConsecutiveFailures >= AuthFailureThreshold
+}
+
+// Paused reports whether work that needs Jira should wait at the given time: the
+// credentials have failed and AuthRecheckInterval has not passed since the last failure.
+func (s AuthStatus) Paused(at time.Time) bool {
+	return s.Failed() && at.Before(s.LastFailureAt.Add(AuthRecheckInterval))
+}
+
+// Record updates the status with the outcome of a Jira request at the given time:
+// nil resets the streak, and a rejection of the credentials (IsAuthFailure) extends it.
+// Other errors say nothing about the credentials and are ignored.
+func (s *AuthStatus) Record(err error, at time.Time) {
+	switch {
+	case err == nil:
+		*s = AuthStatus{}
+	case IsAuthFailure(err):
+		if s.ConsecutiveFailures == 0 {
+			s.FailingSince = at.UTC()
+		}
+		s.ConsecutiveFailures++
+		s.LastFailureAt = at.UTC()
+		s.LastError = err.Error()
+	}
+}
+
+// IsAuthFailure reports whether err is Jira rejecting the credentials themselves (401),
+// as opposed to denying a permission on one resource (403).
+func IsAuthFailure(err error) bool {
+	var jiraErr *JiraError
+	return errors.As(err, &jiraErr) && jiraErr.Status == statusUnauthorized
+}
+
+// IsPermissionDenied reports whether err is Jira denying the user a permission (403),
+// whatever domain error the failure was mapped to.
+func IsPermissionDenied(err error) bool {
+	var jiraErr *JiraError
+	return errors.As(err, &jiraErr) && jiraErr.Status == statusForbidden
+}
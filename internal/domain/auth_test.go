@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAuthStatus_Record(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	unauthenticated := fmt.Errorf("push failed: %w", &JiraError{Err: ErrUnauthorized, Status: 401})
+	forbidden := &JiraError{Err: ErrUnauthorized, Status: 403}
+
+	var status AuthStatus
+	for i := 0; i < AuthFailureThreshold-1; i++ {
+		status.Record(unauthenticated, start.Add(time.Duration(i)*time.Minute))
+	}
+	if status.Failed() {
+		t.Fatalf("Failed() after %d failures, want only at %d", status.ConsecutiveFailures, AuthFailureThreshold)
+	}
+
+	// Permission denials and outages do not break or extend the streak
+	status.Record(forbidden, start)
+	status.Record(ErrUnavailable, start)
+	status.Record(errors.New("connection refused"), start)
+	if status.ConsecutiveFailures != AuthFailureThreshold-1 {
+		t.Errorf("ConsecutiveFailures = %d, want %d", status.ConsecutiveFailures, AuthFailureThreshold-1)
+	}
+
+	status.Record(unauthenticated, start.Add(time.Hour))
+	if !status.Failed() {
+		t.Fatal("Failed() = false after reaching the threshold")
+	}
+	if !status.FailingSince.Equal(start) {
+		t.Errorf("FailingSince = %s, want the first failure at %s", status.FailingSince, start)
+	}
+	if status.LastError != unauthenticated.Error() {
+		t.Errorf("LastError = %q", status.LastError)
+	}
+
+	if !status.Paused(start.Add(time.Hour)) || status.Paused(start.Add(time.Hour+AuthRecheckInterval)) {
+		t.Errorf("Paused() should hold for AuthRecheckInterval after the last failure")
+	}
+
+	status.Record(nil, start.Add(2*time.Hour))
+	if status.Failed() || status.Paused(start.Add(2*time.Hour)) || status.ConsecutiveFailures != 0 || !status.FailingSince.IsZero() {
+		t.Errorf("status after a success = %+v, want reset", status)
+	}
+}
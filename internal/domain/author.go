@@ -0,0 +1,77 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+// AuthorsField is the CustomFields key of the profiles of the people on a ticket (its
+// assignee, reporter, and comment authors), keyed by how the ticket names them (see
+// User.Name): a map of maps with the keys name, email, and avatar_url, so who's who can
+// be shown without asking Jira. Only set when authors are enabled (see
+// MarkdownConfig.Authors). It is read-only.
+const AuthorsField = "authors"
+
+// Authors returns the profiles of the people on the ticket, keyed by how the ticket
+// names them, or nil if none were recorded.
+func (t *Ticket) Authors() map[string]User {
+	recorded, _ := t.CustomFields[AuthorsField].Raw().(map[string]interface{})
+	if len(recorded) == 0 {
+		return nil
+	}
+	authors := make(map[string]User, len(recorded))
+	for name, item := range recorded {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		authors[name] = User{
+			DisplayName: NewFieldValue(fields["name"]).String(),
+			Email:       NewFieldValue(fields["email"]).String(),
+			AvatarURL:   NewFieldValue(fields["avatar_url"]).String(),
+		}
+	}
+	return authors
+}
+
+// Author returns the profile of the person the ticket names name (e.g. its Assignee),
+// and whether one was recorded.
+func (t *Ticket) Author(name string) (User, bool) {
+	author, ok := t.Authors()[name]
+	return author, ok
+}
+
+// AddAuthors records the profiles of users on the ticket, replacing what was recorded
+// for the same people. Nil users and users without a name are skipped.
+func (t *Ticket) AddAuthors(users ...*User) {
+	recorded, _ := t.CustomFields[AuthorsField].Raw().(map[string]interface{})
+	authors := make(map[string]interface{}, len(recorded)+len(users))
+	for name, author := range recorded {
+		authors[name] = author
+	}
+	for _, user := range users {
+		if user == nil || user.Name() == "" {
+			continue
+		}
+		author := map[string]interface{}{"name": user.DisplayName}
+		if user.Email != "" {
+			author["email"] = user.Email
+		}
+		if user.AvatarURL != "" {
+			author["avatar_url"] = user.AvatarURL
+		}
+		authors[user.Name()] = author
+	}
+	if len(authors) == 0 {
+		return
+	}
+	if t.CustomFields == nil {
+		t.CustomFields = make(map[string]FieldValue)
+	}
+	t.CustomFields[AuthorsField] = NewFieldValue(authors)
+}
+
+// AddCommentAuthors records the profiles of the authors of the ticket's comments that
+// were read from Jira (see Comment.AuthorProfile).
+func (t *Ticket) AddCommentAuthors() {
+	for _, comment := range t.Comments {
+		t.AddAuthors(comment.AuthorProfile)
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestTicket_AddAuthors(t *testing.T) {
+	ticket := &Ticket{}
+	if ticket.Authors() != nil {
+		t.Errorf("Authors() = %v, want nil before any are added", ticket.Authors())
+	}
+
+	ticket.AddAuthors(
+		&User{AccountID: "acc-alice", DisplayName: "Alice Smith", Email: "alice@example.com", AvatarURL: "https://avatars.example.com/alice.png"},
+		&User{AccountID: "acc-bob", DisplayName: "Bob"},
+		nil,
+		&User{AccountID: "acc-nameless"},
+	)
+	ticket.Comments = []*Comment{
+		{Author: "Bob", AuthorProfile: &User{DisplayName: "Bob", AvatarURL: "https://avatars.example.com/bob.png"}},
+		{Author: "carol@example.com"},
+	}
+	ticket.AddCommentAuthors()
+
+	authors := ticket.Authors()
+	if len(authors) != 2 {
+		t.Fatalf("Authors() = %v, want alice and bob", authors)
+	}
+	alice, ok := ticket.Author("alice@example.com")
+	if !ok || alice.DisplayName != "Alice Smith" || alice.Email != "alice@example.com" || alice.AvatarURL != "https://avatars.example.com/alice.png" {
+		t.Errorf("Author(alice) = %+v, %v", alice, ok)
+	}
+	if bob, ok := ticket.Author("Bob"); !ok || bob.AvatarURL != "https://avatars.example.com/bob.png" {
+		t.Errorf("Author(Bob) = %+v, %v, want the comment author's profile", bob, ok)
+	}
+	if _, ok := ticket.Author("carol@example.com"); ok {
+		t.Errorf("Author(carol) found, want nothing recorded without a profile")
+	}
+
+	if got := (FieldDirections{AuthorsField: SyncBidirectional}).Direction(AuthorsField); got != SyncJiraToLocal {
+		t.Errorf("Direction(%s) = %s, want read-only", AuthorsField, got)
+	}
+}
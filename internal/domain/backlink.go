@@ -0,0 +1,81 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// IssueLinksField is the CustomFields key of the keys of the tickets a ticket is linked
+// to in Jira (its issue links, in either direction), a list of strings.
+const IssueLinksField = "issue_links"
+
+// mentionPattern finds ticket keys mentioned in text.
+var mentionPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]{1,9}-\d+\b`)
+
+// LinkedKeys returns the keys of the tickets the ticket is linked to in Jira.
+func (t *Ticket) LinkedKeys() []TicketKey {
+	var keys []TicketKey
+	for _, value := range stringValues(t.CustomFields[IssueLinksField].Raw()) {
+		if key, err := NewTicketKey(value); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// MentionedKeys returns the keys of the other tickets mentioned in the ticket's summary
+// or description, in order of first mention.
+func (t *Ticket) MentionedKeys() []TicketKey {
+	var keys []TicketKey
+	for _, text := range []string{t.Summary, t.Description} {
+		for _, match := range mentionPattern.FindAllString(text, -1) {
+			key, err := NewTicketKey(match)
+			if err != nil || key == t.Key || slices.Contains(keys, key) {
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// References returns the keys of the tickets the ticket links to or mentions.
+func (t *Ticket) References() []TicketKey {
+	references := t.LinkedKeys()
+	for _, key := range t.MentionedKeys() {
+		if !slices.Contains(references, key) {
+			references = append(references, key)
+		}
+	}
+	return slices.DeleteFunc(references, func(key TicketKey) bool { return key == t.Key })
+}
+
+// Backlinks maps a ticket key to the tickets that reference it (see Ticket.References).
+type Backlinks map[TicketKey][]*Ticket
+
+// ComputeBacklinks finds, for each of tickets, the other tickets among them that link to
+// or mention it. Referrers are in key order; tickets nothing references are absent.
+func ComputeBacklinks(tickets []*Ticket) Backlinks {
+	known := make(map[TicketKey]bool, len(tickets))
+	for _, ticket := range tickets {
+		known[ticket.Key] = true
+	}
+
+	backlinks := make(Backlinks)
+	for _, ticket := range tickets {
+		for _, key := range ticket.References() {
+			if known[key] {
+				backlinks[key] = append(backlinks[key], ticket)
+			}
+		}
+	}
+	for _, referrers := range backlinks {
+		sort.Slice(referrers, func(i, j int) bool {
+			return referrers[i].Key.Compare(referrers[j].Key) < 0
+		})
+	}
+	return backlinks
+}
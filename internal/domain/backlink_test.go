@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComputeBacklinks(t *testing.T) {
+	now := time.Now()
+	ticket := func(key, description string, links ...interface{}) *Ticket {
+		k, err := NewTicketKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ticket := NewTicket(k, "Ticket "+key, now, now)
+		ticket.Description = description
+		if links != nil {
+			ticket.CustomFields[IssueLinksField] = NewFieldValue(links)
+		}
+		return ticket
+	}
+
+	one := ticket("JMD-1", "Follow-up of JMD-10, see also OPS-7 and JMD-1 itself")
+	two := ticket("JMD-2", "", "JMD-10", "JMD-2")
+	ten := ticket("JMD-10", "Blocks JMD-2. Mentions JMD-2 twice and JMD-999.")
+	tickets := []*Ticket{ten, two, one}
+
+	if got, want := keyStrings(one.References()), []string{"JMD-10", "OPS-7"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("References() = %v, want %v", got, want)
+	}
+
+	backlinks := ComputeBacklinks(tickets)
+	want := map[string][]string{
+		"JMD-10": {"JMD-1", "JMD-2"},
+		"JMD-2":  {"JMD-10"},
+	}
+	got := make(map[string][]string, len(backlinks))
+	for key, referrers := range backlinks {
+		for _, referrer := range referrers {
+			got[key.String()] = append(got[key.String()], referrer.Key.String())
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeBacklinks() = %v, want %v", got, want)
+	}
+}
+
+func keyStrings(keys []TicketKey) []string {
+	strings := make([]string, 0, len(keys))
+	for _, key := range keys {
+		strings = append(strings, key.String())
+	}
+	return strings
+}
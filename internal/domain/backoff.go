@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Backoff is how one kind of failed Jira request is retried: a number of retries,
+// waiting an exponentially growing, randomly jittered time before each.
+// This is a value object.
+type Backoff struct {
+	// MaxRetries is how many times a failed request is sent again (zero never retries)
+	MaxRetries int
+
+	// BaseDelay bounds the wait before the first retry, doubled for each further one
+	BaseDelay time.Duration
+
+	// MaxDelay caps the bound of the wait before a retry
+	MaxDelay time.Duration
+}
+
+// Delay returns the wait before retry number retry (counting from 1): a random duration
+// below BaseDelay doubled retry-1 times, capped at MaxDelay ("full jitter"), so clients
+// failing together do not retry together. random returns a number in [0, 1), like
+// math/rand.Float64.
+func (b Backoff) Delay(retry int, random func() float64) time.Duration {
+	if retry < 1 || b.BaseDelay <= 0 {
+		return 0
+	}
+
+	bound := math.Min(float64(b.BaseDelay)*math.Pow(2, float64(retry-1)), float64(b.MaxDelay))
+	return time.Duration(random() * bound)
+}
+
+// validate checks that the backoff is usable, naming it in errors.
+func (b Backoff) validate(name string) error {
+	if b.MaxRetries < 0 {
+		return fmt.Errorf("%w: %s max retries cannot be negative", ErrInvalidInput, name)
+	}
+	if b.BaseDelay < 0 || b.MaxDelay < 0 {
+		return fmt.Errorf("%w: %s delays cannot be negative", ErrInvalidInput, name)
+	}
+	if b.MaxDelay < b.BaseDelay {
+		return fmt.Errorf("%w: %s max delay %s is less than base delay %s", ErrInvalidInput, name, b.MaxDelay, b.BaseDelay)
+	}
+	return nil
+}
+
+// HTTPRetryPolicy is how a request to Jira is retried after a transient failure, with a
+// Backoff for each kind of failure. This is a value object.
+//
+// Only requests that are safe to send twice are retried after a 502 or 504 or a timeout,
+// since Jira may have applied them: reads, searches, and PUT and DELETE requests. A 429
+// or 503 means Jira did not handle the request, so every request is retried after one.
+type HTTPRetryPolicy struct {
+	// RateLimited retries requests Jira rejected for exceeding its rate limit (429),
+	// waiting as long as its Retry-After header asks when it sends one
+	RateLimited Backoff
+
+	// Unavailable retries requests Jira or a proxy in front of it failed to handle
+	// (502, 503, and 504)
+	Unavailable Backoff
+
+	// Timeouts retries requests that timed out without a response
+	Timeouts Backoff
+
+	// Budget caps the time one request may take, from its first attempt, before it is
+	// no longer retried: a retry whose wait would end past the budget is not made
+	Budget time.Duration
+}
+
+// DefaultHTTPRetryPolicy returns the policy used when jira.http.retry is not configured:
+// three retries of rate-limited requests, two of unavailable ones, and one of timeouts,
+// within two minutes per request.
+func DefaultHTTPRetryPolicy() HTTPRetryPolicy {
+	return HTTPRetryPolicy{
+		RateLimited: Backoff{MaxRetries: 3, BaseDelay: 5 * time.Second, MaxDelay: time.Minute},
+		Unavailable: Backoff{MaxRetries: 2, BaseDelay: time.Second, MaxDelay: 10 * time.Second},
+		Timeouts:    Backoff{MaxRetries: 1, BaseDelay: time.Second, MaxDelay: 10 * time.Second},
+		Budget:      2 * time.Minute,
+	}
+}
+
+// Validate checks that the policy is usable.
+func (p HTTPRetryPolicy) Validate() error {
+	if err := p.RateLimited.validate("rate_limited"); err != nil {
+		return err
+	}
+	if err := p.Unavailable.validate("unavailable"); err != nil {
+		return err
+	}
+	if err := p.Timeouts.validate("timeouts"); err != nil {
+		return err
+	}
+	if p.Budget <= 0 {
+		return fmt.Errorf("%w: retry budget must be positive", ErrInvalidInput)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	backoff := Backoff{MaxRetries: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	highest := func() float64 { return 1 }
+	half := func() float64 { return 0.5 }
+
+	tests := []struct {
+		retry  int
+		random func() float64
+		want   time.Duration
+	}{
+		{0, highest, 0},
+		{1, highest, time.Second},
+		{2, highest, 2 * time.Second},
+		{4, highest, 8 * time.Second},
+		{5, highest, 10 * time.Second},
+		{500, highest, 10 * time.Second},
+		{3, half, 2 * time.Second},
+		{3, func() float64 { return 0 }, 0},
+	}
+
+	for _, tt := range tests {
+		if got := backoff.Delay(tt.retry, tt.random); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPRetryPolicy_Validate(t *testing.T) {
+	if err := DefaultHTTPRetryPolicy().Validate(); err != nil {
+		t.Fatalf("DefaultHTTPRetryPolicy().Validate() = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *HTTPRetryPolicy)
+	}{
+		{"negative retries", func(p *HTTPRetryPolicy) { p.Timeouts.MaxRetries = -1 }},
+		{"negative delay", func(p *HTTPRetryPolicy) { p.Unavailable.BaseDelay = -time.Second }},
+		{"max below base", func(p *HTTPRetryPolicy) { p.RateLimited.MaxDelay = time.Second }},
+		{"no budget", func(p *HTTPRetryPolicy) { p.Budget = 0 }},
+	}
+	for _, tt := range tests {
+		policy := DefaultHTTPRetryPolicy()
+		tt.modify(&policy)
+		if err := policy.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil, want an error", tt.name)
+		}
+	}
+
+	var http HTTPConfig
+	if http.RetryPolicy() != DefaultHTTPRetryPolicy() {
+		t.Errorf("RetryPolicy() of an unconfigured HTTPConfig = %+v, want the default", http.RetryPolicy())
+	}
+}
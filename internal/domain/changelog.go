@@ -0,0 +1,188 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StatusChange is one entry of a ticket's changelog: a move from one status to another,
+// as recorded by Jira.
+type StatusChange struct {
+	// ID identifies the changelog entry in Jira
+	ID string
+
+	// From and To are the statuses before and after the change
+	From string
+	To   string
+
+	// Author is who made the change
+	Author string
+
+	// At is when the change happened (UTC)
+	At time.Time
+}
+
+// StatusPeriod is a stretch of time a ticket spent in one status.
+type StatusPeriod struct {
+	Status string
+	Start  time.Time
+
+	// End is when the ticket left the status, or zero if it is still in it
+	End time.Time
+}
+
+// StatusTime is the total time a ticket spent in one status.
+type StatusTime struct {
+	Status string `json:"status"`
+
+	// Duration is the time of every visit to the status, the current one up to now
+	Duration time.Duration `json:"duration"`
+
+	// Visits is how many times the ticket entered the status
+	Visits int `json:"visits"`
+
+	// Since is when the ticket entered the status if it is still in it, zero otherwise
+	Since time.Time `json:"since,omitzero"`
+}
+
+// SortStatusHistory orders status changes oldest first, keeping the order of changes
+// made at the same time.
+func SortStatusHistory(history []StatusChange) {
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].At.Before(history[j].At)
+	})
+}
+
+// StatusPeriods splits the ticket's life into the periods it spent in each status,
+// oldest first, from its StatusHistory. The first period starts when the ticket was
+// created; the last one is the current status and has no End. Without history the
+// ticket has been in its current status since it was created.
+func (t *Ticket) StatusPeriods() []StatusPeriod {
+	history := slices.Clone(t.StatusHistory)
+	SortStatusHistory(history)
+
+	if len(history) == 0 {
+		return []StatusPeriod{{Status: t.Status, Start: t.Created}}
+	}
+
+	initial := history[0].From
+	if initial == "" {
+		initial = t.Status
+	}
+	periods := make([]StatusPeriod, 0, len(history)+1)
+	periods = append(periods, StatusPeriod{Status: initial, Start: t.Created, End: history[0].At})
+	for i, change := range history {
+		period := StatusPeriod{Status: change.To, Start: change.At}
+		if i+1 < len(history) {
+			period.End = history[i+1].At
+		}
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+// StatusAt returns the status the ticket was in at the given time, from its
+// StatusHistory, or "" if it was not created yet.
+func (t *Ticket) StatusAt(at time.Time) string {
+	if at.Before(t.Created) {
+		return ""
+	}
+	periods := t.StatusPeriods()
+	status := periods[0].Status
+	for _, period := range periods[1:] {
+		if period.Start.After(at) {
+			break
+		}
+		status = period.Status
+	}
+	return status
+}
+
+// HasStatus reports whether status is one of statuses, matched case-insensitively.
+func HasStatus(statuses []string, status string) bool {
+	return slices.ContainsFunc(statuses, func(s string) bool { return strings.EqualFold(s, status) })
+}
+
+// TimeInStatus returns the total time the ticket spent in each status, in the order the
+// statuses were first entered, counting the current status up to now.
+func (t *Ticket) TimeInStatus(now time.Time) []StatusTime {
+	var times []StatusTime
+	index := make(map[string]int)
+	for _, period := range t.StatusPeriods() {
+		end := period.End
+		if end.IsZero() {
+			end = now
+		}
+
+		i, ok := index[period.Status]
+		if !ok {
+			i = len(times)
+			index[period.Status] = i
+			times = append(times, StatusTime{Status: period.Status})
+		}
+		times[i].Visits++
+		if elapsed := end.Sub(period.Start); elapsed > 0 {
+			times[i].Duration += elapsed
+		}
+		if period.End.IsZero() {
+			times[i].Since = period.Start.UTC()
+		}
+	}
+	return times
+}
+
+// CycleTime returns when work on the ticket started, the first time it left the status
+// it was created in, and when it was done, the last time it entered one of the done
+// statuses (matched case-insensitively).
+// Returns ok false unless the ticket is currently in a done status and has left its
+// initial status at least once.
+func (t *Ticket) CycleTime(done []string) (start, end time.Time, ok bool) {
+	isDone := func(status string) bool {
+		return HasStatus(done, status)
+	}
+
+	periods := t.StatusPeriods()
+	current := periods[len(periods)-1]
+	if len(periods) < 2 || !isDone(current.Status) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start = periods[1].Start
+	end = current.Start
+	for i := len(periods) - 1; i > 0 && isDone(periods[i-1].Status); i-- {
+		// Moves between done statuses (e.g. Resolved to Closed) don't extend the cycle
+		end = periods[i-1].Start
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start.UTC(), end.UTC(), true
+}
+
+// FormatElapsed formats a duration for reports in days, hours, and minutes, keeping the
+// two largest units (e.g. "3d 4h", "5h 12m", "40m").
+func FormatElapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// historyTicket builds a ticket created at a fixed time in status, moved through the
+// given changes, each hours after creation.
+func historyTicket(t *testing.T, status string, changes ...StatusChange) (*Ticket, time.Time) {
+	t.Helper()
+	key, _ := NewTicketKey("JMD-1")
+	created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	ticket := NewTicket(key, "Changelog", created, created)
+	ticket.Status = status
+	ticket.StatusHistory = changes
+	return ticket, created
+}
+
+func hoursAfter(base time.Time, hours int) time.Time {
+	return base.Add(time.Duration(hours) * time.Hour)
+}
+
+func TestTicket_StatusPeriods(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("no history", func(t *testing.T) {
+		ticket, created := historyTicket(t, "To Do")
+		want := []StatusPeriod{{Status: "To Do", Start: created}}
+		if got := ticket.StatusPeriods(); !reflect.DeepEqual(got, want) {
+			t.Errorf("StatusPeriods() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("out of order history", func(t *testing.T) {
+		ticket, created := historyTicket(t, "Done",
+			StatusChange{From: "In Progress", To: "Done", At: hoursAfter(base, 5)},
+			StatusChange{From: "To Do", To: "In Progress", At: hoursAfter(base, 2)},
+		)
+		want := []StatusPeriod{
+			{Status: "To Do", Start: created, End: hoursAfter(base, 2)},
+			{Status: "In Progress", Start: hoursAfter(base, 2), End: hoursAfter(base, 5)},
+			{Status: "Done", Start: hoursAfter(base, 5)},
+		}
+		if got := ticket.StatusPeriods(); !reflect.DeepEqual(got, want) {
+			t.Errorf("StatusPeriods() = %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestTicket_TimeInStatus(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	ticket, _ := historyTicket(t, "In Progress",
+		StatusChange{From: "To Do", To: "In Progress", At: hoursAfter(base, 2)},
+		StatusChange{From: "In Progress", To: "In Review", At: hoursAfter(base, 5)},
+		StatusChange{From: "In Review", To: "In Progress", At: hoursAfter(base, 6)},
+	)
+
+	want := []StatusTime{
+		{Status: "To Do", Duration: 2 * time.Hour, Visits: 1},
+		{Status: "In Progress", Duration: 7 * time.Hour, Visits: 2, Since: hoursAfter(base, 6)},
+		{Status: "In Review", Duration: time.Hour, Visits: 1},
+	}
+	if got := ticket.TimeInStatus(hoursAfter(base, 10)); !reflect.DeepEqual(got, want) {
+		t.Errorf("TimeInStatus() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTicket_CycleTime(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	done := []string{"done", "Closed"}
+
+	tests := []struct {
+		name      string
+		status    string
+		changes   []StatusChange
+		wantStart time.Time
+		wantEnd   time.Time
+		wantOK    bool
+	}{
+		{name: "never moved", status: "Done"},
+		{
+			name:   "in progress",
+			status: "In Progress",
+			changes: []StatusChange{
+				{From: "To Do", To: "In Progress", At: hoursAfter(base, 2)},
+			},
+		},
+		{
+			name:   "done",
+			status: "Done",
+			changes: []StatusChange{
+				{From: "To Do", To: "In Progress", At: hoursAfter(base, 2)},
+				{From: "In Progress", To: "Done", At: hoursAfter(base, 8)},
+			},
+			wantStart: hoursAfter(base, 2), wantEnd: hoursAfter(base, 8), wantOK: true,
+		},
+		{
+			name:   "reopened and done again",
+			status: "Done",
+			changes: []StatusChange{
+				{From: "To Do", To: "Done", At: hoursAfter(base, 2)},
+				{From: "Done", To: "In Progress", At: hoursAfter(base, 4)},
+				{From: "In Progress", To: "Done", At: hoursAfter(base, 9)},
+			},
+			wantStart: hoursAfter(base, 2), wantEnd: hoursAfter(base, 9), wantOK: true,
+		},
+		{
+			name:   "moved between done statuses",
+			status: "Closed",
+			changes: []StatusChange{
+				{From: "To Do", To: "In Progress", At: hoursAfter(base, 2)},
+				{From: "In Progress", To: "Done", At: hoursAfter(base, 8)},
+				{From: "Done", To: "Closed", At: hoursAfter(base, 30)},
+			},
+			wantStart: hoursAfter(base, 2), wantEnd: hoursAfter(base, 8), wantOK: true,
+		},
+		{
+			name:   "created done",
+			status: "Closed",
+			changes: []StatusChange{
+				{From: "Done", To: "Closed", At: hoursAfter(base, 2)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket, _ := historyTicket(t, tt.status, tt.changes...)
+			start, end, ok := ticket.CycleTime(done)
+			if ok != tt.wantOK || !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("CycleTime() = %v, %v, %v, want %v, %v, %v", start, end, ok, tt.wantStart, tt.wantEnd, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{-time.Hour, "0m"},
+		{40 * time.Minute, "40m"},
+		{5 * time.Hour, "5h"},
+		{5*time.Hour + 12*time.Minute, "5h 12m"},
+		{72 * time.Hour, "3d"},
+		{76*time.Hour + 30*time.Minute, "3d 4h"},
+	}
+	for _, tt := range tests {
+		if got := FormatElapsed(tt.d); got != tt.want {
+			t.Errorf("FormatElapsed(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -28,6 +28,18 @@ type Comment struct {
 
 	// Updated is when the comment was last updated (always UTC)
 	Updated time.Time
+
+	// Visibility restricts who can see the comment (zero for everyone who can see the ticket)
+	Visibility CommentVisibility
+
+	// AuthorProfile is the author's Jira profile when the comment was read from Jira, and
+	// nil when it was loaded from the cache, which only keeps Author
+	AuthorProfile *User
+
+	// OnBehalfOf is the person a comment staged through jiramd was written by, who may
+	// not own the Jira account it is posted with (see CommentAttribution; empty when
+	// unattributed, and for comments read from Jira or the cache)
+	OnBehalfOf string
 }
 
 // NewComment creates a new Comment with required fields.
@@ -66,5 +78,107 @@ func (c *Comment) Validate() error {
 	if c.Updated.IsZero() {
 		return fmt.Errorf("%w: updated timestamp is required", ErrInvalidTimestamp)
 	}
+	return c.Visibility.Validate()
+}
+
+// VisibilityType is what a restricted comment is restricted to.
+type VisibilityType string
+
+const (
+	// VisibilityRole restricts a comment to the members of a project role
+	VisibilityRole VisibilityType = "role"
+
+	// VisibilityGroup restricts a comment to the members of a group
+	VisibilityGroup VisibilityType = "group"
+)
+
+// CommentVisibility says who can see a comment. Jira comments can be restricted to a
+// project role or a group, and Jira Service Management comments can be internal notes,
+// which customers on the portal never see. The zero value is a public comment.
+type CommentVisibility struct {
+	// Type is what the comment is restricted to, or empty if it is not restricted
+	Type VisibilityType
+
+	// Value is the name of the role or group
+	Value string
+
+	// Internal marks a Service Management internal note
+	Internal bool
+}
+
+// ParseCommentVisibility parses a visibility as written by CommentVisibility.String:
+// "" or "public", "internal", "role:NAME", or "group:NAME", with the restriction
+// optionally after "internal," (e.g. "internal,role:Developers").
+// Returns ErrInvalidInput for anything else.
+func ParseCommentVisibility(s string) (CommentVisibility, error) {
+	var v CommentVisibility
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "internal"); ok && (rest == "" || strings.HasPrefix(rest, ",")) {
+		v.Internal = true
+		s = strings.TrimSpace(strings.TrimPrefix(rest, ","))
+	}
+	if s == "" || (s == "public" && !v.Internal) {
+		return v, nil
+	}
+
+	kind, name, ok := strings.Cut(s, ":")
+	v.Type, v.Value = VisibilityType(strings.TrimSpace(kind)), strings.TrimSpace(name)
+	if !ok {
+		v.Type = ""
+	}
+	if err := v.Validate(); err != nil || v.Type == "" {
+		return CommentVisibility{}, fmt.Errorf("%w: comment visibility %q (expected public, internal, role:NAME, or group:NAME)",
+			ErrInvalidInput, s)
+	}
+	return v, nil
+}
+
+// String returns the visibility in the form ParseCommentVisibility reads, "" for public.
+func (v CommentVisibility) String() string {
+	var parts []string
+	if v.Internal {
+		parts = append(parts, "internal")
+	}
+	if v.Type != "" {
+		parts = append(parts, string(v.Type)+":"+v.Value)
+	}
+	return strings.Join(parts, ",")
+}
+
+// IsPublic returns true if everyone who can see the ticket can see the comment.
+func (v CommentVisibility) IsPublic() bool {
+	return v == CommentVisibility{}
+}
+
+// Label describes the visibility for display, e.g. "Internal note" or "Role: Developers",
+// or returns "" for a public comment.
+func (v CommentVisibility) Label() string {
+	var parts []string
+	if v.Internal {
+		parts = append(parts, "Internal note")
+	}
+	switch v.Type {
+	case VisibilityRole:
+		parts = append(parts, "Role: "+v.Value)
+	case VisibilityGroup:
+		parts = append(parts, "Group: "+v.Value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Validate checks that a restriction names a known type and a role or group.
+func (v CommentVisibility) Validate() error {
+	switch v.Type {
+	case "":
+		if v.Value != "" {
+			return fmt.Errorf("%w: comment visibility %q has no type", ErrInvalidInput, v.Value)
+		}
+	case VisibilityRole, VisibilityGroup:
+		if strings.TrimSpace(v.Value) == "" {
+			return fmt.Errorf("%w: comment visibility %s needs a name", ErrInvalidInput, v.Type)
+		}
+	default:
+		return fmt.Errorf("%w: unknown comment visibility type %q (expected role or group)", ErrInvalidInput, v.Type)
+	}
 	return nil
 }
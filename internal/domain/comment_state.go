@@ -0,0 +1,106 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// CommentState is what was last pulled of a comment: enough to tell whether it changed
+// in Jira without keeping its content.
+type CommentState struct {
+	// ID is the comment's Jira ID
+	ID string
+
+	// Updated is when the comment was last updated in Jira
+	Updated time.Time
+
+	// Hash is the comment's ContentHash
+	Hash string
+}
+
+// NewCommentState returns the state of a comment as pulled.
+func NewCommentState(c *Comment) CommentState {
+	return CommentState{ID: c.ID, Updated: c.Updated.UTC(), Hash: c.ContentHash()}
+}
+
+// CommentStates returns the states of comments, in their order.
+func CommentStates(comments []*Comment) []CommentState {
+	states := make([]CommentState, 0, len(comments))
+	for _, c := range comments {
+		states = append(states, NewCommentState(c))
+	}
+	return states
+}
+
+// ContentHash computes a SHA-256 hash of what a comment's block in the ticket file is
+// written from: its author, body, and visibility.
+func (c *Comment) ContentHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "author:%s\n", c.Author)
+	fmt.Fprintf(h, "visibility:%s\n", c.Visibility)
+	fmt.Fprintf(h, "body:%s\n", c.Body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CommentChanges is how the comments of a ticket differ from what was last pulled.
+type CommentChanges struct {
+	// Added are the comments that were not pulled before, in order
+	Added []*Comment
+
+	// Changed are the comments that were edited since they were pulled, in order
+	Changed []*Comment
+
+	// Removed are the IDs of the comments pulled before that were deleted in Jira
+	Removed []string
+
+	// Unchanged is how many comments are as they were pulled
+	Unchanged int
+}
+
+// IsEmpty returns true if no comment was added, changed, or removed.
+func (c CommentChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+// DiffComments compares the comments fetched from Jira with the states recorded when
+// they were last pulled. A comment counts as changed when its content hash differs, so
+// an edit that leaves the content as it was (or only bumps Updated) is not a change.
+func DiffComments(recorded []CommentState, fetched []*Comment) CommentChanges {
+	var changes CommentChanges
+	known := make(map[string]CommentState, len(recorded))
+	for _, state := range recorded {
+		known[state.ID] = state
+	}
+	seen := make(map[string]bool, len(fetched))
+	for _, c := range fetched {
+		seen[c.ID] = true
+		state, ok := known[c.ID]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, c)
+		case state.Hash != c.ContentHash():
+			changes.Changed = append(changes.Changed, c)
+		default:
+			changes.Unchanged++
+		}
+	}
+	for _, state := range recorded {
+		if !seen[state.ID] {
+			changes.Removed = append(changes.Removed, state.ID)
+		}
+	}
+	return changes
+}
+
+// CommentsStale reports whether a ticket's comments may have changed since they were
+// pulled at pulledAt with the states recorded, so they must be fetched again: when the
+// ticket was updated in Jira since, or its comment count differs. Jira bumps a ticket's
+// updated timestamp when a comment is added or edited but not when one is deleted, which
+// the count catches.
+func CommentsStale(recorded []CommentState, count int, ticketUpdated, pulledAt time.Time) bool {
+	return count != len(recorded) || pulledAt.IsZero() || ticketUpdated.After(pulledAt)
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComment_ContentHash(t *testing.T) {
+	base := Comment{ID: "10001", Author: "alice@example.com", Body: "Looking into it",
+		Updated: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)}
+	hash := base.ContentHash()
+
+	same := base
+	same.Updated = same.Updated.Add(time.Hour)
+	if same.ContentHash() != hash {
+		t.Error("ContentHash() changed with Updated alone")
+	}
+
+	for name, edit := range map[string]func(c *Comment){
+		"body":       func(c *Comment) { c.Body = "Fixed" },
+		"author":     func(c *Comment) { c.Author = "bob@example.com" },
+		"visibility": func(c *Comment) { c.Visibility = CommentVisibility{Internal: true} },
+	} {
+		edited := base
+		edit(&edited)
+		if edited.ContentHash() == hash {
+			t.Errorf("ContentHash() did not change with the %s", name)
+		}
+	}
+}
+
+func TestDiffComments(t *testing.T) {
+	at := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	kept := &Comment{ID: "1", Author: "alice@example.com", Body: "First", Updated: at}
+	edited := &Comment{ID: "2", Author: "alice@example.com", Body: "Second", Updated: at}
+	deleted := &Comment{ID: "3", Author: "bob@example.com", Body: "Third", Updated: at}
+	recorded := CommentStates([]*Comment{kept, edited, deleted})
+
+	editedNow := *edited
+	editedNow.Body = "Second, edited"
+	editedNow.Updated = at.Add(time.Hour)
+	touched := *kept
+	touched.Updated = at.Add(time.Hour)
+	added := &Comment{ID: "4", Author: "carol@example.com", Body: "Fourth", Updated: at.Add(2 * time.Hour)}
+
+	got := DiffComments(recorded, []*Comment{&touched, &editedNow, added})
+	want := CommentChanges{
+		Added:     []*Comment{added},
+		Changed:   []*Comment{&editedNow},
+		Removed:   []string{"3"},
+		Unchanged: 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffComments() = %+v, want %+v", got, want)
+	}
+	if got.IsEmpty() {
+		t.Error("IsEmpty() = true, want false")
+	}
+
+	if unchanged := DiffComments(recorded, []*Comment{kept, edited, deleted}); !unchanged.IsEmpty() || unchanged.Unchanged != 3 {
+		t.Errorf("DiffComments(same) = %+v, want 3 unchanged", unchanged)
+	}
+}
+
+func TestCommentsStale(t *testing.T) {
+	pulled := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	recorded := []CommentState{{ID: "1"}, {ID: "2"}}
+
+	tests := []struct {
+		name     string
+		count    int
+		updated  time.Time
+		pulledAt time.Time
+		want     bool
+	}{
+		{"unchanged", 2, pulled, pulled, false},
+		{"updated before pull", 2, pulled.Add(-time.Hour), pulled, false},
+		{"updated since pull", 2, pulled.Add(time.Second), pulled, true},
+		{"comment deleted", 1, pulled, pulled, true},
+		{"comment added", 3, pulled, pulled, true},
+		{"never pulled", 2, pulled, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommentsStale(recorded, tt.count, tt.updated, tt.pulledAt); got != tt.want {
+				t.Errorf("CommentsStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -219,3 +220,80 @@ func TestComment_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCommentVisibility(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      CommentVisibility
+		wantLabel string
+		wantErr   bool
+	}{
+		{input: "", want: CommentVisibility{}},
+		{input: "public", want: CommentVisibility{}},
+		{input: "internal", want: CommentVisibility{Internal: true}, wantLabel: "Internal note"},
+		{input: "role:Developers", want: CommentVisibility{Type: VisibilityRole, Value: "Developers"}, wantLabel: "Role: Developers"},
+		{input: "group:jira-admins", want: CommentVisibility{Type: VisibilityGroup, Value: "jira-admins"}, wantLabel: "Group: jira-admins"},
+		{
+			input:     "internal,role:Developers",
+			want:      CommentVisibility{Type: VisibilityRole, Value: "Developers", Internal: true},
+			wantLabel: "Internal note, Role: Developers",
+		},
+		{input: "role:", wantErr: true},
+		{input: "team:Backend", wantErr: true},
+		{input: "Developers", wantErr: true},
+		{input: "internal,public", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCommentVisibility(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("ParseCommentVisibility() error = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCommentVisibility() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCommentVisibility() = %+v, want %+v", got, tt.want)
+			}
+			if label := got.Label(); label != tt.wantLabel {
+				t.Errorf("Label() = %q, want %q", label, tt.wantLabel)
+			}
+			if got.IsPublic() != (tt.wantLabel == "") {
+				t.Errorf("IsPublic() = %v, want %v", got.IsPublic(), tt.wantLabel == "")
+			}
+
+			roundTrip, err := ParseCommentVisibility(got.String())
+			if err != nil || roundTrip != got {
+				t.Errorf("ParseCommentVisibility(%q) = %+v, %v, want %+v", got.String(), roundTrip, err, got)
+			}
+		})
+	}
+}
+
+func TestCommentAttribution(t *testing.T) {
+	attribution := CommentAttribution{
+		Footer:              "— posted via jiramd on behalf of {author}",
+		ImpersonationTokens: map[string]string{"alice": "alice-pat"},
+	}
+
+	if got, want := attribution.FooterFor("bob"), "— posted via jiramd on behalf of bob"; got != want {
+		t.Errorf("FooterFor(bob) = %q, want %q", got, want)
+	}
+	if got := attribution.FooterFor(""); got != "" {
+		t.Errorf("FooterFor(\"\") = %q, want no footer for unattributed comments", got)
+	}
+	if got := (CommentAttribution{}).FooterFor("bob"); got != "" {
+		t.Errorf("FooterFor(bob) without a footer = %q, want \"\"", got)
+	}
+
+	if got := attribution.ImpersonationToken("alice"); got != "alice-pat" {
+		t.Errorf("ImpersonationToken(alice) = %q, want alice-pat", got)
+	}
+	if got := attribution.ImpersonationToken("bob"); got != "" {
+		t.Errorf("ImpersonationToken(bob) = %q, want \"\"", got)
+	}
+}
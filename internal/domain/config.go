@@ -3,6 +3,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
@@ -12,6 +13,22 @@ type Config struct {
 	Jira    JiraConfig
 	Sync    SyncConfig
 	Storage StorageConfig
+	API     APIConfig
+	Notify  NotifyConfig
+	Log     LogConfig
+
+	// Markdown controls how ticket files are written
+	Markdown MarkdownConfig
+
+	// Display controls how timestamps are shown
+	Display DisplayConfig
+
+	// Reports controls the markdown reports written from the ticket cache
+	Reports ReportsConfig
+
+	// Watchlist lists single tickets, possibly of other projects, kept in sync without
+	// syncing their whole projects
+	Watchlist []TicketKey
 }
 
 // JiraConfig contains Jira-specific configuration.
@@ -20,6 +37,145 @@ type JiraConfig struct {
 	Email   string
 	Token   string
 	Project string
+
+	// HTTP controls how requests reach Jira
+	HTTP HTTPConfig
+
+	// Comments controls how comments posted through jiramd show who wrote them
+	Comments CommentAttribution
+}
+
+// AuthorPlaceholder is replaced by the author's name in CommentAttribution.Footer.
+const AuthorPlaceholder = "{author}"
+
+// CommentAttribution controls how comments posted through jiramd show who wrote them.
+// Jira shows every comment as written by the owner of jira.token, so when a team shares
+// one account the person behind a comment is recorded by a footer or, on Jira Server
+// and Data Center, by posting it with their own personal access token.
+type CommentAttribution struct {
+	// Author is the person comments staged with this configuration are written by
+	// (empty for unattributed comments)
+	Author string
+
+	// Footer is appended to the body of comments posted on behalf of an author, with
+	// AuthorPlaceholder replaced by the author (empty for no footer)
+	Footer string
+
+	// ImpersonationTokens maps authors to their personal access tokens on Jira Server
+	// and Data Center; their comments are posted with them, so Jira shows them as the
+	// comment's author, and get no footer
+	ImpersonationTokens map[string]string
+}
+
+// FooterFor returns the footer of a comment posted on behalf of author, or "" when
+// there is no author or no footer is configured.
+func (a CommentAttribution) FooterFor(author string) string {
+	if author == "" || a.Footer == "" {
+		return ""
+	}
+	return strings.ReplaceAll(a.Footer, AuthorPlaceholder, author)
+}
+
+// ImpersonationToken returns the personal access token author's comments are posted
+// with, or "" to post them with jira.token.
+func (a CommentAttribution) ImpersonationToken(author string) string {
+	if author == "" {
+		return ""
+	}
+	return a.ImpersonationTokens[author]
+}
+
+// DefaultHTTPTimeout bounds each request to Jira when no timeout is configured.
+const DefaultHTTPTimeout = 30 * time.Second
+
+// DefaultConnectTimeout bounds connecting to Jira, including the TLS handshake,
+// when jira.http.connect_timeout is not configured.
+const DefaultConnectTimeout = 10 * time.Second
+
+// DefaultRateLimit is the most requests per second sent to Jira when
+// jira.http.rate_limit is not configured.
+const DefaultRateLimit = 10.0
+
+// HTTPConfig controls the HTTP connection to Jira, for networks that need a proxy or
+// intercept TLS with their own certificate authority.
+type HTTPConfig struct {
+	// ReadTimeout bounds each request that only reads from Jira, such as fetches and
+	// searches (zero means DefaultHTTPTimeout)
+	ReadTimeout time.Duration
+
+	// WriteTimeout bounds each request that changes Jira, such as creates, edits, and
+	// bulk edits (zero means DefaultHTTPTimeout)
+	WriteTimeout time.Duration
+
+	// ConnectTimeout bounds establishing a connection, including the TLS handshake
+	// (zero means DefaultConnectTimeout)
+	ConnectTimeout time.Duration
+
+	// Proxy is the URL of the proxy requests go through; empty uses the HTTPS_PROXY,
+	// HTTP_PROXY, and NO_PROXY environment variables
+	Proxy string
+
+	// CAFile is a PEM bundle of certificate authorities trusted in addition to the
+	// system ones, e.g. a corporate proxy's
+	CAFile string
+
+	// ClientCertFile and ClientKeyFile are a PEM certificate and key presented to
+	// servers that require client certificates (both or neither)
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// RateLimit is the most requests per second sent to Jira, a budget shared by every
+	// worker of the process (zero means no limit)
+	RateLimit float64
+
+	// Retry controls how requests are retried after transient failures
+	// (the zero value means DefaultHTTPRetryPolicy, see RetryPolicy)
+	Retry HTTPRetryPolicy
+}
+
+// RetryPolicy returns the configured request retry policy, or DefaultHTTPRetryPolicy if
+// none is configured.
+func (c HTTPConfig) RetryPolicy() HTTPRetryPolicy {
+	if c.Retry == (HTTPRetryPolicy{}) {
+		return DefaultHTTPRetryPolicy()
+	}
+	return c.Retry
+}
+
+// DefaultSyncInterval is how often the daemon polls Jira when sync.interval is not configured.
+const DefaultSyncInterval = 5 * time.Minute
+
+// SyncMode selects which ways sync moves changes between Jira and the local files.
+type SyncMode string
+
+const (
+	// SyncModeBidirectional pulls changes from Jira and pushes local changes (the default)
+	SyncModeBidirectional SyncMode = "bidirectional"
+
+	// SyncModePullOnly mirrors Jira locally and never pushes, even if files are edited
+	SyncModePullOnly SyncMode = "pull_only"
+
+	// SyncModePushOnly pushes local changes and never pulls
+	SyncModePushOnly SyncMode = "push_only"
+)
+
+// IsValid returns true if the mode is one of the known sync modes.
+func (m SyncMode) IsValid() bool {
+	switch m {
+	case SyncModeBidirectional, SyncModePullOnly, SyncModePushOnly:
+		return true
+	}
+	return false
+}
+
+// CanPull returns true if the mode pulls changes from Jira.
+func (m SyncMode) CanPull() bool {
+	return m != SyncModePushOnly
+}
+
+// CanPush returns true if the mode pushes local changes to Jira.
+func (m SyncMode) CanPush() bool {
+	return m != SyncModePullOnly
 }
 
 // SyncConfig contains synchronization-specific configuration.
@@ -27,11 +183,447 @@ type SyncConfig struct {
 	Interval     time.Duration
 	MarkdownDir  string
 	WatchEnabled bool
+
+	// Mode selects whether syncs pull, push, or both (empty means SyncModeBidirectional)
+	Mode SyncMode
+
+	// Conflicts decides which side wins for tickets changed both locally and in Jira
+	// (empty means ConflictManual)
+	Conflicts ConflictStrategy
+
+	// Filter limits which tickets are synced (the zero value syncs every ticket)
+	Filter SyncFilter
+
+	// Sprint limits syncing to a board's active sprints (the zero value disables it)
+	Sprint SprintScope
+
+	// Indexes selects the per-sprint and per-assignee index files written under
+	// MarkdownDir (the zero value writes none)
+	Indexes IndexOptions
+
+	// MetadataTTL is how long cached project metadata (issue types, priorities,
+	// statuses, and transitions) is used before it is fetched again
+	MetadataTTL time.Duration
+
+	// Guardrails flag projects and files that are larger or staler than expected
+	// (the zero value disables every check; see DefaultGuardrails)
+	Guardrails Guardrails
+
+	// FullSyncSchedule triggers periodic full syncs (zero value disables them)
+	FullSyncSchedule CronSchedule
+
+	// Retry controls how failed pending operations are retried
+	// (zero MaxAttempts means DefaultRetryPolicy, see RetryPolicy)
+	Retry RetryPolicy
+
+	// FieldDirections overrides the sync direction of fields in every project
+	FieldDirections FieldDirections
+
+	// ProjectFieldDirections overrides the sync direction of fields in single projects,
+	// keyed by project key; they take precedence over FieldDirections
+	ProjectFieldDirections map[string]FieldDirections
+
+	// Statuses translates Jira status names to local ones and back
+	// (the zero value uses Jira's names)
+	Statuses StatusMap
+
+	// Priorities translates the priority names of single projects' schemes in Jira to
+	// canonical local ones and back, keyed by project key (projects without an entry
+	// use Jira's names)
+	Priorities map[string]PriorityMap
+
+	// SharedVault attributes local changes when several people edit the markdown
+	// directory, e.g. a git repository shared by a team
+	SharedVault SharedVaultConfig
 }
 
+// AuthorSource is where the author of a local change to a ticket file is read from.
+type AuthorSource string
+
+const (
+	// AuthorFromFrontmatter reads the edited_by key of the ticket file's frontmatter
+	AuthorFromFrontmatter AuthorSource = "frontmatter"
+
+	// AuthorFromGit reads the author of the last commit of the ticket file, or the git
+	// user while the file has uncommitted edits
+	AuthorFromGit AuthorSource = "git"
+)
+
+// IsValid returns true if the source is one of the known author sources.
+func (s AuthorSource) IsValid() bool {
+	return s == AuthorFromFrontmatter || s == AuthorFromGit
+}
+
+// SharedVaultConfig attributes local changes to the people who made them, when several
+// people edit the markdown directory. Authors are recorded with queued pushes, shown in
+// their history and on conflicts, and attribute posted comments.
+type SharedVaultConfig struct {
+	// AuthorSources are tried in order for the author of a change, the first to name
+	// one winning; changes none names are attributed to jira.comments.author (empty
+	// attributes every change to it)
+	AuthorSources []AuthorSource
+}
+
+// Policy returns the policy deciding what syncs do with each ticket, from the
+// configured mode and conflict strategy.
+func (c SyncConfig) Policy() SyncPolicy {
+	return SyncPolicy{Mode: c.Mode, Conflicts: c.Conflicts}
+}
+
+// FieldDirectionsFor returns the field direction overrides that apply to a project:
+// its own overrides on top of the global ones.
+func (c SyncConfig) FieldDirectionsFor(projectKey string) FieldDirections {
+	directions := make(FieldDirections, len(c.FieldDirections))
+	for field, direction := range c.FieldDirections {
+		directions[field] = direction
+	}
+	for field, direction := range c.ProjectFieldDirections[projectKey] {
+		directions[field] = direction
+	}
+	return directions
+}
+
+// PrioritiesFor returns the priority map of a project (nil if it uses Jira's names).
+func (c SyncConfig) PrioritiesFor(projectKey string) PriorityMap {
+	return c.Priorities[projectKey]
+}
+
+// RetryPolicy returns the configured retry policy, or DefaultRetryPolicy if none is configured.
+func (c SyncConfig) RetryPolicy() RetryPolicy {
+	if c.Retry.MaxAttempts == 0 {
+		return DefaultRetryPolicy()
+	}
+	return c.Retry
+}
+
+// DefaultRetention is how long completed operations, tombstones, and sync history are kept
+// when storage.retention is not configured.
+const DefaultRetention = 90 * 24 * time.Hour
+
+// DefaultGCInterval is how often the daemon prunes expired state
+// when storage.gc_interval is not configured.
+const DefaultGCInterval = 24 * time.Hour
+
+// DefaultCacheMaxSize is the size the media cache is kept under when
+// storage.cache_max_size is not configured: 512 MiB.
+const DefaultCacheMaxSize = 512 << 20
+
+// StorageDriver selects the backend that stores sync state.
+type StorageDriver string
+
+const (
+	// StorageDriverSQLite keeps sync state in the local SQLite database (the default)
+	StorageDriverSQLite StorageDriver = "sqlite"
+
+	// StorageDriverPostgres keeps sync state in a PostgreSQL database shared by several instances
+	StorageDriverPostgres StorageDriver = "postgres"
+)
+
 // StorageConfig contains storage-specific configuration.
 type StorageConfig struct {
+	// DBPath is the local SQLite database, which holds the ticket cache, search index, and
+	// push queue regardless of Driver
 	DBPath string
+
+	// Encrypt encrypts ticket content, comments, and queued payloads in DBPath at rest,
+	// with a key kept in the OS keyring
+	Encrypt bool
+
+	// Driver selects where sync state is stored (empty means StorageDriverSQLite)
+	Driver StorageDriver
+
+	// DSN is the PostgreSQL connection string, required when Driver is StorageDriverPostgres
+	DSN string
+
+	// Retention is how long completed operations, ticket tombstones, and sync history are kept
+	// (zero means DefaultRetention)
+	Retention time.Duration
+
+	// GCInterval is how often the daemon prunes state older than Retention (zero disables it)
+	GCInterval time.Duration
+
+	// CacheDir is the directory downloaded media (attachments) is cached in, by content,
+	// so syncs don't download it again
+	CacheDir string
+
+	// CacheMaxSize is the size in bytes the cache is kept under by evicting the least
+	// recently used files (zero means no limit)
+	CacheMaxSize int64
+}
+
+// APIConfig contains configuration for the daemon's local control API.
+type APIConfig struct {
+	// Enabled turns the control API on
+	Enabled bool
+
+	// Address is the loopback host:port to listen on (e.g., "127.0.0.1:7777")
+	Address string
+
+	// SocketPath is a unix socket path to listen on instead of Address
+	SocketPath string
+
+	// Auth is how clients authenticate (empty means APIAuthToken)
+	Auth APIAuth
+
+	// WebhookSecret is the secret Jira signs webhook deliveries with, which are checked
+	// against it instead of the bearer token (empty checks no signature)
+	WebhookSecret string
+
+	// TLS serves the API over TLS, and with a client CA, only to clients presenting a
+	// certificate it signed (mutual TLS)
+	TLS APITLSConfig
+}
+
+// APIAuth selects how control API clients authenticate.
+type APIAuth string
+
+const (
+	// APIAuthToken requires every request to carry the daemon's bearer token, kept in the
+	// OS keyring (the default)
+	APIAuthToken APIAuth = "token"
+
+	// APIAuthNone accepts every request; only allowed on a unix socket, which only its
+	// owner can connect to, or with mutual TLS
+	APIAuthNone APIAuth = "none"
+)
+
+// IsValid returns true if the auth is one of the known API auth modes.
+func (a APIAuth) IsValid() bool {
+	switch a {
+	case APIAuthToken, APIAuthNone:
+		return true
+	}
+	return false
+}
+
+// RequiresToken returns true if requests must carry the bearer token.
+func (c APIConfig) RequiresToken() bool {
+	return c.Auth != APIAuthNone
+}
+
+// APITLSConfig contains the certificates the control API is served over TLS with.
+type APITLSConfig struct {
+	// CertFile and KeyFile are the server's certificate and private key, PEM-encoded
+	// (both empty serves plain HTTP)
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile is the PEM bundle of CAs client certificates must be signed by
+	// (empty accepts clients without a certificate)
+	ClientCAFile string
+
+	// ClientCertFile and ClientKeyFile are the certificate jiramd's own commands (e.g.
+	// status) present to the daemon when ClientCAFile is set
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+// Enabled returns true if the API is served over TLS.
+func (c APITLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// Mutual returns true if clients must present a certificate.
+func (c APITLSConfig) Mutual() bool {
+	return c.Enabled() && c.ClientCAFile != ""
+}
+
+// NotifyConfig controls how jiramd alerts the user to problems that need attention,
+// such as Jira rejecting the configured credentials.
+type NotifyConfig struct {
+	// Command is a shell command run for each alert (empty only logs alerts). It receives
+	// the alert in JIRAMD_EVENT and JIRAMD_MESSAGE environment variables.
+	Command string
+}
+
+// Log levels accepted in LogConfig.Level.
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// LogConfig controls the daemon's logging.
+type LogConfig struct {
+	// Level is the least severe level logged, one of the LogLevel constants
+	// (empty means LogLevelInfo)
+	Level string
+}
+
+// MarkdownFlavor selects the markdown dialect ticket files are written in.
+type MarkdownFlavor string
+
+const (
+	// MarkdownFlavorPlain writes CommonMark that renders anywhere (the default)
+	MarkdownFlavorPlain MarkdownFlavor = "plain"
+
+	// MarkdownFlavorObsidian also writes the ticket's fields as Dataview inline fields
+	// (status:: In Progress), so Obsidian Dataview queries can run on them
+	MarkdownFlavorObsidian MarkdownFlavor = "obsidian"
+)
+
+// IsValid returns true if the flavor is one of the known markdown flavors.
+func (f MarkdownFlavor) IsValid() bool {
+	switch f {
+	case MarkdownFlavorPlain, MarkdownFlavorObsidian:
+		return true
+	}
+	return false
+}
+
+// FrontmatterFormat selects how the frontmatter of ticket files is stored.
+type FrontmatterFormat string
+
+const (
+	// FrontmatterYAML writes frontmatter as a "---" delimited YAML block (the default)
+	FrontmatterYAML FrontmatterFormat = "yaml"
+
+	// FrontmatterTOML writes frontmatter as a "+++" delimited TOML block, as Hugo and
+	// Zola expect
+	FrontmatterTOML FrontmatterFormat = "toml"
+
+	// FrontmatterJSON writes frontmatter to a JSON sidecar file next to the ticket file
+	// (KEY.json), leaving the markdown without frontmatter
+	FrontmatterJSON FrontmatterFormat = "json"
+)
+
+// IsValid returns true if the format is one of the known frontmatter formats.
+func (f FrontmatterFormat) IsValid() bool {
+	switch f {
+	case FrontmatterYAML, FrontmatterTOML, FrontmatterJSON:
+		return true
+	}
+	return false
+}
+
+// MarkdownConfig controls how ticket files are written.
+type MarkdownConfig struct {
+	// Flavor is the markdown dialect of ticket files (empty means MarkdownFlavorPlain)
+	Flavor MarkdownFlavor
+
+	// Frontmatter is how ticket metadata is stored (empty means FrontmatterYAML)
+	Frontmatter FrontmatterFormat
+
+	// KeyOrder lists frontmatter keys in the order they are written; keys not listed
+	// follow in their default order (empty means the default order)
+	KeyOrder []string
+
+	// RawFields also writes the raw values of the Jira fields jiramd does not map under
+	// a read-only jira_fields key, so nothing shown in Jira is missing from the file
+	RawFields bool
+
+	// DownloadMedia downloads the Jira attachments descriptions and comments reference
+	// (e.g. !screenshot.png!) under attachments/ and links them as markdown images, so
+	// files render offline
+	DownloadMedia bool
+
+	// Authors also writes the profiles (display name, email, avatar URL) of a ticket's
+	// assignee, reporter, and comment authors under a read-only authors key, so sites and
+	// tools can show who's who without asking Jira
+	Authors bool
+
+	// MaxComments is how many of a ticket's most recent comments its file holds, after a
+	// note saying how many older ones are in Jira (0 means every comment)
+	MaxComments int
+
+	// MaxDescriptionLength is how many characters of a ticket's description its file
+	// holds, followed by a note that the rest is in Jira (0 means the whole description)
+	MaxDescriptionLength int
+
+	// ProjectLimits replaces MaxComments and MaxDescriptionLength for single projects,
+	// keyed by project key
+	ProjectLimits map[string]ContentLimits
+}
+
+// ContentLimits bounds how much of a ticket its file holds, so huge descriptions and
+// comment threads do not bloat the vault; what is left out stays in Jira and is fetched
+// on demand. Zero values mean no limit. This is a value object.
+type ContentLimits struct {
+	// MaxDescriptionLength is how many characters of the description are written
+	MaxDescriptionLength int
+
+	// MaxComments is how many of the most recent comments are written
+	MaxComments int
+}
+
+// LimitsFor returns the content limits of a project's ticket files: its entry in
+// ProjectLimits, or the global limits if it has none.
+func (c MarkdownConfig) LimitsFor(projectKey string) ContentLimits {
+	if limits, ok := c.ProjectLimits[projectKey]; ok {
+		return limits
+	}
+	return ContentLimits{MaxDescriptionLength: c.MaxDescriptionLength, MaxComments: c.MaxComments}
+}
+
+// ReportDir is the directory, under the markdown directory, that reports are written to
+// when reports.dir is not configured.
+const ReportDir = "reports"
+
+// DefaultDoneStatuses are the statuses a ticket is done in when reports.done_statuses is
+// not configured.
+var DefaultDoneStatuses = []string{"Done"}
+
+// DefaultWIPStatuses are the statuses a ticket is in progress in when
+// reports.wip_statuses is not configured.
+var DefaultWIPStatuses = []string{"In Progress"}
+
+// ReportsConfig controls the markdown reports written from the ticket cache: the
+// burndown of each active sprint and an aging WIP table of the tickets in progress.
+type ReportsConfig struct {
+	// Schedule is when the daemon writes the reports (zero value writes them only on
+	// jiramd reports)
+	Schedule CronSchedule
+
+	// Dir is where reports are written
+	Dir string
+
+	// DoneStatuses are the statuses a ticket is done in, for burndowns
+	DoneStatuses []string
+
+	// WIPStatuses are the statuses a ticket is in progress in, for the aging WIP report
+	WIPStatuses []string
+}
+
+// DefaultDateFormat is the layout timestamps are shown with when display.date_format is
+// not configured.
+const DefaultDateFormat = "2006-01-02 15:04:05 MST"
+
+// DisplayConfig controls how timestamps are shown to people: in command output, the
+// body of ticket files, and rendered sites. It only applies when rendering; timestamps
+// are stored, compared, and hashed in UTC, and frontmatter keeps them in RFC 3339 UTC.
+type DisplayConfig struct {
+	// Location is the time zone timestamps are shown in (nil means UTC)
+	Location *time.Location
+
+	// DateFormat is the Go time layout timestamps are shown with
+	// (empty means RFC 3339)
+	DateFormat string
+}
+
+// FormatTime formats t for display, or returns "" for the zero time. The zero
+// DisplayConfig shows timestamps in RFC 3339 UTC.
+func (c DisplayConfig) FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	location := c.Location
+	if location == nil {
+		location = time.UTC
+	}
+	layout := c.DateFormat
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return t.In(location).Format(layout)
+}
+
+// TimezoneName returns the name of the time zone timestamps are shown in.
+func (c DisplayConfig) TimezoneName() string {
+	if c.Location == nil {
+		return time.UTC.String()
+	}
+	return c.Location.String()
 }
 
 // ConfigLoader defines the interface for loading configuration.
@@ -0,0 +1,23 @@
+package domain
+
+import "testing"
+
+func TestMarkdownConfig_LimitsFor(t *testing.T) {
+	markdown := MarkdownConfig{
+		MaxComments:          50,
+		MaxDescriptionLength: 4000,
+		ProjectLimits: map[string]ContentLimits{
+			"OPS": {MaxComments: 10},
+		},
+	}
+
+	if got, want := markdown.LimitsFor("JMD"), (ContentLimits{MaxDescriptionLength: 4000, MaxComments: 50}); got != want {
+		t.Errorf("LimitsFor(JMD) = %+v, want the global limits %+v", got, want)
+	}
+	if got, want := markdown.LimitsFor("OPS"), (ContentLimits{MaxComments: 10}); got != want {
+		t.Errorf("LimitsFor(OPS) = %+v, want the project limits %+v", got, want)
+	}
+	if got := (MarkdownConfig{}).LimitsFor("JMD"); got != (ContentLimits{}) {
+		t.Errorf("LimitsFor() without limits = %+v, want none", got)
+	}
+}
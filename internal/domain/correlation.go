@@ -0,0 +1,69 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// CorrelationID identifies a unit of work, such as a sync cycle or a queued operation,
+// across the components taking part in it: it is logged with every line about the work
+// and sent with every Jira request made for it, so a failure can be traced from the
+// change that caused it to the request Jira rejected.
+//
+// The ID of work done as part of other work extends the ID of that work (see Child), so
+// searching the logs for a sync cycle's ID also finds the operations it pushed.
+type CorrelationID string
+
+// NewCorrelationID returns a new random correlation ID.
+func NewCorrelationID() CorrelationID {
+	var b [8]byte
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b[:])
+	return CorrelationID(hex.EncodeToString(b[:]))
+}
+
+// Child returns a new correlation ID for work done as part of the work id identifies:
+// id followed by a random suffix, or a new ID when id is empty.
+func (id CorrelationID) Child() CorrelationID {
+	if id == "" {
+		return NewCorrelationID()
+	}
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return id + "." + CorrelationID(hex.EncodeToString(b[:]))
+}
+
+// String returns the ID.
+func (id CorrelationID) String() string {
+	return string(id)
+}
+
+// correlationKey is the context key of the correlation ID of the work being done.
+type correlationKey struct{}
+
+// WithCorrelationID returns a context carrying id as the correlation ID of the work
+// done with it. An empty id leaves ctx unchanged.
+func WithCorrelationID(ctx context.Context, id CorrelationID) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationIDFrom returns the correlation ID ctx carries, or "" if it has none.
+func CorrelationIDFrom(ctx context.Context) CorrelationID {
+	id, _ := ctx.Value(correlationKey{}).(CorrelationID)
+	return id
+}
+
+// EnsureCorrelationID returns ctx with a correlation ID: the one it already carries,
+// so work started as part of other work keeps its ID, or a new one.
+func EnsureCorrelationID(ctx context.Context) context.Context {
+	if CorrelationIDFrom(ctx) != "" {
+		return ctx
+	}
+	return WithCorrelationID(ctx, NewCorrelationID())
+}
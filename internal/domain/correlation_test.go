@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCorrelationID(t *testing.T) {
+	id := NewCorrelationID()
+	if len(id) != 16 || id == NewCorrelationID() {
+		t.Fatalf("NewCorrelationID() = %q, want 16 random hex digits", id)
+	}
+
+	child := id.Child()
+	if !strings.HasPrefix(child.String(), id.String()+".") || child == id.Child() {
+		t.Errorf("Child() = %q, want %q followed by a random suffix", child, id)
+	}
+	if orphan := CorrelationID("").Child(); len(orphan) != 16 {
+		t.Errorf("Child() of no ID = %q, want a new ID", orphan)
+	}
+}
+
+func TestCorrelationIDContext(t *testing.T) {
+	ctx := context.Background()
+	if got := CorrelationIDFrom(ctx); got != "" {
+		t.Errorf("CorrelationIDFrom(background) = %q, want \"\"", got)
+	}
+	if WithCorrelationID(ctx, "") != ctx {
+		t.Error("WithCorrelationID(\"\") changed the context")
+	}
+
+	ensured := EnsureCorrelationID(ctx)
+	id := CorrelationIDFrom(ensured)
+	if id == "" {
+		t.Fatal("EnsureCorrelationID() added no ID")
+	}
+	if got := CorrelationIDFrom(EnsureCorrelationID(ensured)); got != id {
+		t.Errorf("EnsureCorrelationID() replaced %q with %q", id, got)
+	}
+}
@@ -0,0 +1,204 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DigestDir is the directory, under the markdown directory, that daily digests are
+// written to (e.g. digest/2025-01-15.md).
+const DigestDir = "digest"
+
+// DigestDateLayout is the layout of the day in digest file names.
+const DigestDateLayout = "2006-01-02"
+
+// noFieldChanges is the change of a pull that changed no field.
+const noFieldChanges = "no field changes"
+
+// maxDescribedValue is the longest field value quoted in an activity; longer values are
+// cut short.
+const maxDescribedValue = 60
+
+// ActivityDirection is which way a change to a ticket went.
+type ActivityDirection string
+
+const (
+	// ActivityPulled is a change pulled from Jira into the local files
+	ActivityPulled ActivityDirection = "pulled"
+
+	// ActivityPushed is a local change made through jiramd, queued for pushing to Jira
+	// unless the field is local only
+	ActivityPushed ActivityDirection = "pushed"
+)
+
+// Activity is one change to a ticket that went through jiramd, as listed in the daily
+// digest.
+type Activity struct {
+	// ID is the unique identifier assigned when the activity is recorded
+	ID int64
+
+	TicketKey TicketKey
+
+	// Summary is the ticket's summary when the change happened
+	Summary string
+
+	Direction ActivityDirection
+
+	// Change is a one-line description of what changed (e.g. "status: To Do → Done")
+	Change string
+
+	At time.Time
+}
+
+// Validate checks that the activity can be recorded.
+func (a *Activity) Validate() error {
+	if a.TicketKey.IsZero() {
+		return fmt.Errorf("%w: activity ticket key is required", ErrInvalidInput)
+	}
+	if a.Direction != ActivityPulled && a.Direction != ActivityPushed {
+		return fmt.Errorf("%w: unknown activity direction %q", ErrInvalidInput, a.Direction)
+	}
+	if a.At.IsZero() {
+		return fmt.Errorf("%w: activity time is required", ErrInvalidInput)
+	}
+	return nil
+}
+
+// PulledActivities returns the activities of pulling ticket from Jira at at, over base,
+// its cached revision (nil for a ticket pulled for the first time): one per changed
+// field, or a single one saying nothing changed.
+func PulledActivities(ticket, base *Ticket, at time.Time) []*Activity {
+	activity := func(change string) *Activity {
+		return &Activity{TicketKey: ticket.Key, Summary: ticket.Summary, Direction: ActivityPulled, Change: change, At: at.UTC()}
+	}
+	if base == nil {
+		return []*Activity{activity("new ticket")}
+	}
+
+	changes := ticket.Diff(base)
+	if len(changes) == 0 {
+		return []*Activity{activity(noFieldChanges)}
+	}
+	activities := make([]*Activity, 0, len(changes))
+	for _, change := range changes {
+		activities = append(activities, activity(DescribeChange(change.Field, describeValue(change.From), describeValue(change.To))))
+	}
+	return activities
+}
+
+// PushedActivity returns the activity of a local change recorded as event, to the
+// ticket with the given summary, or nil for events that are not listed.
+func PushedActivity(event Event, summary string) *Activity {
+	var change string
+	switch e := event.(type) {
+	case StatusChanged:
+		change = DescribeChange("status", e.From, e.To)
+	case FieldChanged:
+		change = DescribeChange(e.Field, e.From, e.To)
+	case CommentAdded:
+		change = "comment added"
+	default:
+		return nil
+	}
+	return &Activity{
+		TicketKey: event.EventTicket(),
+		Summary:   summary,
+		Direction: ActivityPushed,
+		Change:    change,
+		At:        event.OccurredAt().UTC(),
+	}
+}
+
+// DescribeChange returns a one-line description of a field changing from one value to
+// another. Descriptions are only said to be edited, since they do not fit on a line.
+func DescribeChange(field, from, to string) string {
+	switch {
+	case field == "description":
+		return "description edited"
+	case from == "":
+		return fmt.Sprintf("%s set to %s", field, shorten(to))
+	case to == "":
+		return fmt.Sprintf("%s cleared (was %s)", field, shorten(from))
+	default:
+		return fmt.Sprintf("%s: %s → %s", field, shorten(from), shorten(to))
+	}
+}
+
+// describeValue returns a field value as shown in an activity, lists joined by commas.
+func describeValue(value FieldValue) string {
+	if values, ok := value.StringSlice(); ok {
+		return strings.Join(values, ", ")
+	}
+	return value.String()
+}
+
+// shorten cuts value to one line of at most maxDescribedValue characters.
+func shorten(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if utf8.RuneCountInString(value) <= maxDescribedValue {
+		return value
+	}
+	return string([]rune(value)[:maxDescribedValue-1]) + "…"
+}
+
+// DigestEntry is what happened to one ticket in a day's digest.
+type DigestEntry struct {
+	TicketKey TicketKey
+
+	// Summary is the ticket's summary after its last change of the day
+	Summary string
+
+	// Pulled and Pushed describe the changes in each direction, oldest first, each once
+	Pulled []string
+	Pushed []string
+}
+
+// BuildDigest groups activities by ticket, in ticket key order, listing each distinct
+// change once.
+func BuildDigest(activities []*Activity) []DigestEntry {
+	sorted := slices.Clone(activities)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	entries := make(map[TicketKey]*DigestEntry)
+	for _, activity := range sorted {
+		entry, ok := entries[activity.TicketKey]
+		if !ok {
+			entry = &DigestEntry{TicketKey: activity.TicketKey}
+			entries[activity.TicketKey] = entry
+		}
+		if activity.Summary != "" {
+			entry.Summary = activity.Summary
+		}
+		switch activity.Direction {
+		case ActivityPulled:
+			entry.Pulled = appendOnce(entry.Pulled, activity.Change)
+		case ActivityPushed:
+			entry.Pushed = appendOnce(entry.Pushed, activity.Change)
+		}
+	}
+
+	digest := make([]DigestEntry, 0, len(entries))
+	for _, entry := range entries {
+		// A pull that changed nothing is only worth listing if nothing else was pulled
+		if len(entry.Pulled) > 1 {
+			entry.Pulled = slices.DeleteFunc(entry.Pulled, func(change string) bool { return change == noFieldChanges })
+		}
+		digest = append(digest, *entry)
+	}
+	sort.Slice(digest, func(i, j int) bool { return digest[i].TicketKey.Compare(digest[j].TicketKey) < 0 })
+	return digest
+}
+
+// appendOnce appends value to values unless it is already there.
+func appendOnce(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
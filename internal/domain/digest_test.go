@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDescribeChange(t *testing.T) {
+	tests := []struct {
+		field, from, to string
+		want            string
+	}{
+		{field: "status", from: "To Do", to: "In Progress", want: "status: To Do → In Progress"},
+		{field: "assignee", to: "alice@example.com", want: "assignee set to alice@example.com"},
+		{field: "priority", from: "High", want: "priority cleared (was High)"},
+		{field: "description", from: "old", to: "new", want: "description edited"},
+		{field: "summary", from: "Login\nflow", to: strings.Repeat("x", 70), want: "summary: Login flow → " + strings.Repeat("x", 59) + "…"},
+	}
+	for _, tt := range tests {
+		if got := DescribeChange(tt.field, tt.from, tt.to); got != tt.want {
+			t.Errorf("DescribeChange(%q, %q, %q) = %q, want %q", tt.field, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestPulledActivities(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	at := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	base := NewTicket(key, "Login", at, at)
+	base.Status = "To Do"
+	base.Labels = []string{"auth"}
+
+	changes := func(activities []*Activity) []string {
+		var described []string
+		for _, activity := range activities {
+			if activity.Direction != ActivityPulled || activity.TicketKey != key || !activity.At.Equal(at) {
+				t.Errorf("activity = %+v, want pulled JMD-1 at %s", activity, at)
+			}
+			described = append(described, activity.Change)
+		}
+		return described
+	}
+
+	if got := changes(PulledActivities(base, nil, at)); !reflect.DeepEqual(got, []string{"new ticket"}) {
+		t.Errorf("PulledActivities(new) = %v", got)
+	}
+	if got := changes(PulledActivities(base, base, at)); !reflect.DeepEqual(got, []string{"no field changes"}) {
+		t.Errorf("PulledActivities(unchanged) = %v", got)
+	}
+
+	pulled := NewTicket(key, "Login", at, at)
+	pulled.Status = "Done"
+	pulled.Labels = []string{"auth", "web"}
+	want := []string{"labels: auth → auth, web", "status: To Do → Done"}
+	if got := changes(PulledActivities(pulled, base, at)); !reflect.DeepEqual(got, want) {
+		t.Errorf("PulledActivities(changed) = %v, want %v", got, want)
+	}
+}
+
+func TestPushedActivity(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	at := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	activity := PushedActivity(StatusChanged{Key: key, From: "To Do", To: "Done", At: at}, "Login")
+	want := &Activity{TicketKey: key, Summary: "Login", Direction: ActivityPushed, Change: "status: To Do → Done", At: at}
+	if !reflect.DeepEqual(activity, want) {
+		t.Errorf("PushedActivity() = %+v, want %+v", activity, want)
+	}
+	if activity := PushedActivity(CommentAdded{Key: key, At: at}, "Login"); activity.Change != "comment added" {
+		t.Errorf("PushedActivity(comment) = %+v", activity)
+	}
+}
+
+func TestActivity_Validate(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	at := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	if err := (&Activity{TicketKey: key, Direction: ActivityPulled, At: at}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	for _, activity := range []*Activity{
+		{Direction: ActivityPulled, At: at},
+		{TicketKey: key, Direction: "sideways", At: at},
+		{TicketKey: key, Direction: ActivityPushed},
+	} {
+		if err := activity.Validate(); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Validate(%+v) error = %v, want ErrInvalidInput", activity, err)
+		}
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	at := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	activity := func(key string, direction ActivityDirection, change string, hours int) *Activity {
+		ticketKey, _ := NewTicketKey(key)
+		return &Activity{
+			TicketKey: ticketKey,
+			Summary:   key + " summary " + change,
+			Direction: direction,
+			Change:    change,
+			At:        at.Add(time.Duration(hours) * time.Hour),
+		}
+	}
+
+	digest := BuildDigest([]*Activity{
+		activity("JMD-10", ActivityPulled, "new ticket", 1),
+		activity("JMD-2", ActivityPulled, "status: To Do → Done", 3),
+		activity("JMD-2", ActivityPulled, "no field changes", 1),
+		activity("JMD-2", ActivityPushed, "comment added", 2),
+		activity("JMD-2", ActivityPushed, "comment added", 4),
+	})
+
+	if len(digest) != 2 || digest[0].TicketKey.String() != "JMD-2" || digest[1].TicketKey.String() != "JMD-10" {
+		t.Fatalf("BuildDigest() = %+v, want JMD-2 and JMD-10", digest)
+	}
+	entry := digest[0]
+	if entry.Summary != "JMD-2 summary comment added" {
+		t.Errorf("Summary = %q, want the summary of the last change", entry.Summary)
+	}
+	if want := []string{"status: To Do → Done"}; !reflect.DeepEqual(entry.Pulled, want) {
+		t.Errorf("Pulled = %v, want %v", entry.Pulled, want)
+	}
+	if want := []string{"comment added"}; !reflect.DeepEqual(entry.Pushed, want) {
+		t.Errorf("Pushed = %v, want %v", entry.Pushed, want)
+	}
+}
@@ -9,7 +9,7 @@
 // # Architecture Rules
 //
 //   - NO imports from application or infrastructure layers
-//   - NO external dependencies (only stdlib: time, strings, fmt, regexp, crypto/md5, encoding/hex, errors)
+//   - NO external dependencies (only stdlib: time, strings, fmt, regexp, crypto/md5, crypto/sha256, hash, encoding/hex, errors)
 //   - All domain logic is self-contained and testable in isolation
 //   - Entities and value objects are immutable where appropriate
 //   - All timestamps are stored in UTC
@@ -24,6 +24,9 @@
 //   - Ticket: A Jira ticket (aggregate root), identified by TicketKey
 //   - Comment: A comment on a ticket, identified by ID
 //   - Project: A Jira project, identified by project key
+//   - User: A Jira user from the user directory, identified by account ID
+//   - ProjectMetadata: The cached issue types, priorities, statuses, and transitions
+//     of a project, identified by project key
 //   - SyncState: Sync state for a project, identified by project key
 //   - TicketState: Sync state for a ticket, identified by (project key, ticket key)
 //   - PendingOperation: A queued sync operation, identified by ID
@@ -35,15 +38,33 @@
 //
 //   - TicketKey: A validated Jira ticket identifier (e.g., "JMD-123")
 //   - SyncTimestamp: A UTC timestamp for sync operations
-//   - FieldValue: A field's value with type-safe access
+//   - FieldValue: A field's value with typed accessors (Int, Float, Bool, Time, StringSlice), Kind, and Equal
 //   - CustomField: Configuration for a custom field
 //   - DerivedField: A field computed from other fields
 //   - SyncResult: Result of a sync operation
+//   - CronSchedule: A parsed cron expression for scheduled full syncs
+//   - RetryPolicy: When failed pending operations are tried again
+//   - SyncPolicy: Whether a ticket is pulled or pushed, and which side wins a conflict
+//   - StatusChange: An entry of a ticket's status changelog, from which TimeInStatus and
+//     CycleTime are computed
+//   - BurndownPoint, AgingTicket: Report rows computed from status changelogs by
+//     Burndown and AgingWIP
 //
 // ## Aggregates
 //
 // Ticket is an aggregate root that owns Comments and CustomFields.
 // External references should only hold the TicketKey, not the full Ticket.
+// Business rules for changing a ticket live on it: ChangeStatus and ApplyTransition
+// for workflow moves, ChangeField and SetCustomField (checked against the field's
+// CustomField configuration) for edits, AddComment for comments, and Diff for what
+// changed between two revisions.
+//
+// ## Domain Events
+//
+// Ticket mutations (ChangeStatus, ApplyTransition, ChangeField, SetCustomField,
+// AddComment) record typed events (StatusChanged, FieldChanged, CommentAdded) on
+// the aggregate. The application layer drains them with DrainEvents once the change is saved and publishes them,
+// so audit logs, hooks, and notifications never reach into the domain.
 //
 // # Ubiquitous Language
 //
@@ -60,7 +81,7 @@
 //   - Derived Field: Field computed from other fields using DSL
 //   - Bidirectional: Syncs both directions (Jira ↔ Local)
 //   - Local-Only: Never synced to Jira
-//   - Content Hash: MD5 hash for conflict detection
+//   - Content Hash: SHA-256 hash for conflict detection (MD5 for legacy state)
 //
 // # Domain Errors
 //
@@ -75,6 +96,11 @@
 //   - ErrInvalidTimestamp: Invalid or zero timestamp
 //   - ErrEmptyKey: Empty or whitespace-only key
 //   - ErrInvalidOperation: Invalid operation type
+//   - ErrRateLimited: Jira rejected a request for exceeding its rate limit
+//   - ErrUnavailable: Jira failed to handle a request or is unavailable
+//
+// Failed Jira requests are returned as *JiraError, which wraps one of these errors and
+// adds the HTTP status, endpoint, JQL, Atlassian request ID, and number of attempts.
 //
 // # Invariants
 //
@@ -86,7 +112,8 @@
 //   - Ticket must have Key, Summary, Created, and Updated
 //   - Comment must have ID, TicketKey, Author, Created, and Updated
 //   - CustomField must have Name, DisplayName, Source, and SyncDirection
-//   - PendingOperation attempts must be <= max retries (3)
+//   - PendingOperation attempts must be <= the retry policy's max attempts (3 by default)
+//   - PendingOperation has a TicketKey unless it creates a ticket
 //
 // # Usage Example
 //
@@ -2,7 +2,11 @@
 // This layer has zero dependencies on application or infrastructure layers.
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // Domain errors represent business rule violations and core domain concerns.
 // These errors should be used by domain entities and checked by application layer.
@@ -42,16 +46,85 @@ var (
 
 	// ErrInvalidOperation indicates an invalid pending operation type
 	ErrInvalidOperation = errors.New("invalid operation type")
+
+	// ErrRateLimited indicates Jira rejected a request for exceeding its rate limit
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrUnavailable indicates Jira failed to handle a request or is unavailable
+	ErrUnavailable = errors.New("service unavailable")
+
+	// ErrReadOnlyField indicates a local edit of a field that only syncs from Jira
+	ErrReadOnlyField = errors.New("read-only field")
 )
 
 // ConfigError represents a configuration-specific error with details.
 type ConfigError struct {
 	Message string
+
+	// File is the config file that Line numbers in Problems refer to
+	File string
+
+	// Problems lists every problem found when the configuration was checked as a whole
+	// (empty for a single problem described by Message)
+	Problems []ConfigProblem
+}
+
+// ConfigProblem is one problem found in the configuration.
+type ConfigProblem struct {
+	// Key is the dotted setting the problem is about, e.g. sync.interval
+	// (empty when it is not about one setting)
+	Key string
+
+	// Line is the line of the config file that sets Key (zero when unknown, e.g. for
+	// settings from flags or the environment)
+	Line int
+
+	// Message describes the problem
+	Message string
 }
 
 // Error implements the error interface.
 func (e *ConfigError) Error() string {
-	return e.Message
+	switch len(e.Problems) {
+	case 0:
+		return e.Message
+	case 1:
+		return e.location(e.Problems[0]) + e.Problems[0].Message
+	}
+
+	var b strings.Builder
+	b.WriteString(e.Message)
+	for _, problem := range e.Problems {
+		b.WriteString("\n  ")
+		b.WriteString(e.location(problem))
+		b.WriteString(problem.Message)
+	}
+	return b.String()
+}
+
+// location returns the "file:line: " prefix of a problem, or "" when its line is unknown.
+func (e *ConfigError) location(problem ConfigProblem) string {
+	switch {
+	case problem.Line == 0:
+		return ""
+	case e.File == "":
+		return fmt.Sprintf("line %d: ", problem.Line)
+	default:
+		return fmt.Sprintf("%s:%d: ", e.File, problem.Line)
+	}
+}
+
+// WithLines returns a copy of the error whose problems without a line take the line
+// that sets their key in file.
+func (e *ConfigError) WithLines(file string, lines map[string]int) *ConfigError {
+	annotated := &ConfigError{Message: e.Message, File: file, Problems: make([]ConfigProblem, len(e.Problems))}
+	for i, problem := range e.Problems {
+		if problem.Line == 0 {
+			problem.Line = lines[problem.Key]
+		}
+		annotated.Problems[i] = problem
+	}
+	return annotated
 }
 
 // NewConfigError creates a new ConfigError.
@@ -59,6 +132,113 @@ func NewConfigError(message string) error {
 	return &ConfigError{Message: message}
 }
 
+// NewConfigProblems creates a ConfigError reporting every problem found, or returns nil
+// when there are none.
+func NewConfigProblems(problems []ConfigProblem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ConfigError{
+		Message:  fmt.Sprintf("configuration has %d problems:", len(problems)),
+		Problems: problems,
+	}
+}
+
+// JiraError is a failed Jira request. It wraps the domain error the failure maps to
+// (e.g. ErrUnauthorized), so errors.Is works as usual, and carries the request details
+// needed to debug permission and field configuration problems. A request retried until
+// it gave up reports its last attempt.
+type JiraError struct {
+	// Err is the domain error the failure maps to, or the transport error of a request
+	// that got no response
+	Err error
+
+	// Status is the HTTP status code Jira last answered with (zero when the request got
+	// no response, e.g. it timed out)
+	Status int
+
+	// Attempts is how many times the request was sent, more than once when it was retried
+	// (zero when unknown)
+	Attempts int
+
+	// Method and Endpoint are the HTTP method and path of the request
+	Method   string
+	Endpoint string
+
+	// JQL is the query of a failed search (empty for other requests)
+	JQL string
+
+	// RequestID is Atlassian's ID for the request, which their support can look up
+	// (empty if Jira did not send one)
+	RequestID string
+}
+
+// Error implements the error interface, appending the request details to Err's message.
+func (e *JiraError) Error() string {
+	var b strings.Builder
+	if e.Status == 0 {
+		fmt.Fprintf(&b, "%v (%s %s: no response", e.Err, e.Method, e.Endpoint)
+	} else {
+		fmt.Fprintf(&b, "%v (%s %s: %d", e.Err, e.Method, e.Endpoint, e.Status)
+	}
+	if e.Attempts > 1 {
+		fmt.Fprintf(&b, " after %d attempts", e.Attempts)
+	}
+	if e.JQL != "" {
+		fmt.Fprintf(&b, "; jql: %s", e.JQL)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, "; request id: %s", e.RequestID)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// Unwrap returns the domain error the failure maps to.
+func (e *JiraError) Unwrap() error {
+	return e.Err
+}
+
+// RequiredField is a field Jira requires a value for, such as a field the new issue type
+// of a ticket demands.
+type RequiredField struct {
+	// ID is the field's Jira ID (e.g. customfield_10016)
+	ID string
+
+	// Name is the field's display name (e.g. Story Points)
+	Name string
+}
+
+// String returns the field's name followed by its ID.
+func (f RequiredField) String() string {
+	if f.Name == "" || f.Name == f.ID {
+		return f.ID
+	}
+	return fmt.Sprintf("%s (%s)", f.Name, f.ID)
+}
+
+// MissingFieldsError reports that a ticket's issue type cannot change until the fields
+// the new type requires have values. It matches ErrInvalidFieldValue.
+type MissingFieldsError struct {
+	Key       TicketKey
+	IssueType string
+	Fields    []RequiredField
+}
+
+// Error implements the error interface, naming the missing fields.
+func (e *MissingFieldsError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		names = append(names, field.String())
+	}
+	return fmt.Sprintf("%v: %s needs %s set to become a %s", ErrInvalidFieldValue, e.Key, strings.Join(names, ", "), e.IssueType)
+}
+
+// Unwrap returns ErrInvalidFieldValue.
+func (e *MissingFieldsError) Unwrap() error {
+	return ErrInvalidFieldValue
+}
+
 // IsNotFoundError checks if an error is or wraps ErrNotFound.
 func IsNotFoundError(err error) bool {
 	return errors.Is(err, ErrNotFound)
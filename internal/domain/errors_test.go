@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJiraError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *JiraError
+		want string
+	}{
+		{
+			name: "edit",
+			err:  &JiraError{Err: ErrUnauthorized, Status: 403, Method: "PUT", Endpoint: "/rest/api/3/issue/JMD-1"},
+			want: "unauthorized (PUT /rest/api/3/issue/JMD-1: 403)",
+		},
+		{
+			name: "search",
+			err: &JiraError{
+				Err:       ErrInvalidInput,
+				Status:    400,
+				Method:    "POST",
+				Endpoint:  "/rest/api/3/search/jql",
+				JQL:       "project = JMD AND sprint = 7",
+				RequestID: "a1b2c3",
+			},
+			want: "invalid input (POST /rest/api/3/search/jql: 400; jql: project = JMD AND sprint = 7; request id: a1b2c3)",
+		},
+		{
+			name: "retried",
+			err:  &JiraError{Err: ErrUnavailable, Status: 503, Attempts: 3, Method: "GET", Endpoint: "/rest/api/3/issue/JMD-1"},
+			want: "service unavailable (GET /rest/api/3/issue/JMD-1: 503 after 3 attempts)",
+		},
+		{
+			name: "no response",
+			err:  &JiraError{Err: errors.New("timeout awaiting response headers"), Attempts: 2, Method: "GET", Endpoint: "/rest/api/3/issue/JMD-1"},
+			want: "timeout awaiting response headers (GET /rest/api/3/issue/JMD-1: no response after 2 attempts)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			if !errors.Is(tt.err, tt.err.Err) {
+				t.Errorf("errors.Is(%v, %v) = false", tt.err, tt.err.Err)
+			}
+		})
+	}
+}
+
+func TestMissingFieldsError(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	err := &MissingFieldsError{
+		Key:       key,
+		IssueType: "Story",
+		Fields:    []RequiredField{{ID: "customfield_10016", Name: "Story Points"}, {ID: "customfield_10020"}},
+	}
+
+	want := "invalid field value: JMD-1 needs Story Points (customfield_10016), customfield_10020 set to become a Story"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, ErrInvalidFieldValue) {
+		t.Errorf("errors.Is(%v, ErrInvalidFieldValue) = false", err)
+	}
+}
+
+func TestConfigError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ConfigError
+		want string
+	}{
+		{
+			name: "message",
+			err:  &ConfigError{Message: "failed to read config file"},
+			want: "failed to read config file",
+		},
+		{
+			name: "one problem",
+			err: &ConfigError{
+				Message:  "configuration has 1 problems:",
+				File:     "config.yaml",
+				Problems: []ConfigProblem{{Key: "sync.interval", Line: 4, Message: "sync.interval must be positive"}},
+			},
+			want: "config.yaml:4: sync.interval must be positive",
+		},
+		{
+			name: "several problems",
+			err: &ConfigError{
+				Message: "configuration has 2 problems:",
+				File:    "config.yaml",
+				Problems: []ConfigProblem{
+					{Key: "jira.projet", Line: 5, Message: "unknown setting jira.projet"},
+					{Key: "jira.project", Message: "jira.project is required"},
+				},
+			},
+			want: "configuration has 2 problems:\n  config.yaml:5: unknown setting jira.projet\n  jira.project is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigError_WithLines(t *testing.T) {
+	err := NewConfigProblems([]ConfigProblem{
+		{Key: "log.level", Message: "log.level must be debug, info, warn, or error"},
+		{Key: "jira.token", Message: "jira.token is required"},
+	})
+
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("NewConfigProblems() = %T, want *ConfigError", err)
+	}
+
+	annotated := configErr.WithLines("config.yaml", map[string]int{"log.level": 12})
+	if annotated.Problems[0].Line != 12 || annotated.Problems[1].Line != 0 {
+		t.Errorf("WithLines() problems = %+v", annotated.Problems)
+	}
+	if configErr.Problems[0].Line != 0 {
+		t.Error("WithLines() modified the original error")
+	}
+
+	if NewConfigProblems(nil) != nil {
+		t.Error("NewConfigProblems(nil) != nil")
+	}
+}
@@ -0,0 +1,97 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"time"
+)
+
+// Event names, as returned by Event.EventName.
+const (
+	EventStatusChanged = "ticket.status_changed"
+	EventFieldChanged  = "ticket.field_changed"
+	EventCommentAdded  = "ticket.comment_added"
+)
+
+// Event is something that happened to a ticket. The Ticket aggregate records events as
+// its mutations happen; the application layer drains them once the change is saved
+// and publishes them, e.g. to an audit log, hooks, or notifications.
+type Event interface {
+	// EventName returns the kind of event, one of the Event* constants
+	EventName() string
+
+	// EventTicket returns the key of the ticket the event happened to
+	EventTicket() TicketKey
+
+	// OccurredAt returns when the event happened (UTC)
+	OccurredAt() time.Time
+}
+
+// StatusChanged records that a ticket moved to another status.
+type StatusChanged struct {
+	Key      TicketKey
+	From, To string
+	At       time.Time
+}
+
+// EventName implements Event.
+func (e StatusChanged) EventName() string { return EventStatusChanged }
+
+// EventTicket implements Event.
+func (e StatusChanged) EventTicket() TicketKey { return e.Key }
+
+// OccurredAt implements Event.
+func (e StatusChanged) OccurredAt() time.Time { return e.At }
+
+// FieldChanged records that a ticket field other than the status changed. Field is
+// named as in Ticket.ChangedFields.
+type FieldChanged struct {
+	Key      TicketKey
+	Field    string
+	From, To string
+	At       time.Time
+}
+
+// EventName implements Event.
+func (e FieldChanged) EventName() string { return EventFieldChanged }
+
+// EventTicket implements Event.
+func (e FieldChanged) EventTicket() TicketKey { return e.Key }
+
+// OccurredAt implements Event.
+func (e FieldChanged) OccurredAt() time.Time { return e.At }
+
+// CommentAdded records that a comment was added to a ticket.
+type CommentAdded struct {
+	Key       TicketKey
+	CommentID string
+	Author    string
+	At        time.Time
+}
+
+// EventName implements Event.
+func (e CommentAdded) EventName() string { return EventCommentAdded }
+
+// EventTicket implements Event.
+func (e CommentAdded) EventTicket() TicketKey { return e.Key }
+
+// OccurredAt implements Event.
+func (e CommentAdded) OccurredAt() time.Time { return e.At }
+
+// Events returns the events recorded since they were last drained, oldest first.
+func (t *Ticket) Events() []Event {
+	return append([]Event(nil), t.events...)
+}
+
+// DrainEvents returns the events recorded since they were last drained, oldest first,
+// and forgets them, so each is published once.
+func (t *Ticket) DrainEvents() []Event {
+	events := t.events
+	t.events = nil
+	return events
+}
+
+// record appends an event to the ticket's recorded events.
+func (t *Ticket) record(event Event) {
+	t.events = append(t.events, event)
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTicket_Events(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2024, 2, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	ticket := NewTicket(key, "Summary", created, created)
+	ticket.Status = "To Do"
+
+	if err := ticket.ChangeStatus("In Progress", at); err != nil {
+		t.Fatalf("ChangeStatus() error = %v", err)
+	}
+	if err := ticket.ChangeField("assignee", "alice", at); err != nil {
+		t.Fatalf("ChangeField() error = %v", err)
+	}
+	comment, err := NewComment("10001", key, "bob", "Looks good", at, at)
+	if err != nil {
+		t.Fatalf("NewComment() error = %v", err)
+	}
+	if err := ticket.AddComment(comment); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	if ticket.Status != "In Progress" || ticket.Assignee != "alice" {
+		t.Errorf("ticket = status %q, assignee %q; want In Progress, alice", ticket.Status, ticket.Assignee)
+	}
+
+	want := []Event{
+		StatusChanged{Key: key, From: "To Do", To: "In Progress", At: at.UTC()},
+		FieldChanged{Key: key, Field: "assignee", From: "", To: "alice", At: at.UTC()},
+		CommentAdded{Key: key, CommentID: "10001", Author: "bob", At: at.UTC()},
+	}
+	if got := ticket.Events(); len(got) != len(want) {
+		t.Fatalf("Events() = %v, want %v", got, want)
+	}
+	got := ticket.DrainEvents()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %#v, want %#v", i, got[i], want[i])
+		}
+		if got[i].EventTicket() != key || !got[i].OccurredAt().Equal(at) {
+			t.Errorf("event %d = ticket %s at %v", i, got[i].EventTicket(), got[i].OccurredAt())
+		}
+	}
+	if got[0].EventName() != EventStatusChanged || got[1].EventName() != EventFieldChanged || got[2].EventName() != EventCommentAdded {
+		t.Errorf("event names = %s, %s, %s", got[0].EventName(), got[1].EventName(), got[2].EventName())
+	}
+
+	if events := ticket.DrainEvents(); len(events) != 0 {
+		t.Errorf("DrainEvents() after drain = %v, want none", events)
+	}
+}
+
+func TestTicket_MutationsRejectNoOps(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	other, _ := NewTicketKey("JMD-2")
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticket := NewTicket(key, "Summary", created, created)
+	ticket.Status = "Done"
+	comment, _ := NewComment("10001", other, "bob", "Wrong ticket", created, created)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "empty status", err: ticket.ChangeStatus(" ", created)},
+		{name: "same status", err: ticket.ChangeStatus("done", created)},
+		{name: "same field value", err: ticket.ChangeField("summary", "Summary", created)},
+		{name: "status as field", err: ticket.ChangeField("status", "To Do", created)},
+		{name: "unknown field", err: ticket.ChangeField("labels", "x", created)},
+		{name: "nil comment", err: ticket.AddComment(nil)},
+		{name: "comment of another ticket", err: ticket.AddComment(comment)},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, ErrInvalidInput) {
+			t.Errorf("%s: error = %v, want ErrInvalidInput", tt.name, tt.err)
+		}
+	}
+	if events := ticket.Events(); len(events) != 0 {
+		t.Errorf("Events() = %v, want none", events)
+	}
+}
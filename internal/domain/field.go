@@ -4,7 +4,12 @@ package domain
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // SyncDirection defines which direction a field should be synchronized.
@@ -21,6 +26,72 @@ const (
 	SyncLocalOnly SyncDirection = "local_only"
 )
 
+// IsValid returns true if the direction is one of the known sync directions.
+func (d SyncDirection) IsValid() bool {
+	switch d {
+	case SyncBidirectional, SyncJiraToLocal, SyncLocalOnly:
+		return true
+	}
+	return false
+}
+
+// FieldDirections overrides the sync direction of ticket fields, keyed by field name as
+// in Ticket.ChangedFields (e.g. "labels"). Fields not listed sync in both directions,
+// except the read-only fields jiramd records from Jira, which always sync from Jira only.
+type FieldDirections map[string]SyncDirection
+
+// readOnlyFields are the CustomFields keys recorded from Jira that are never pushed,
+// whatever their configured direction.
+var readOnlyFields = map[string]bool{
+	JiraFieldsField:  true,
+	AttachmentsField: true,
+	AuthorsField:     true,
+}
+
+// Direction returns the sync direction of field.
+func (d FieldDirections) Direction(field string) SyncDirection {
+	if readOnlyFields[field] {
+		return SyncJiraToLocal
+	}
+	if direction, ok := d[field]; ok {
+		return direction
+	}
+	return SyncBidirectional
+}
+
+// Pushable splits changed, the names of locally edited fields, into the fields to push
+// to Jira and the read-only (jira_to_local) fields whose local edits must be flagged
+// instead. Local-only fields are in neither list; their edits never leave the machine.
+func (d FieldDirections) Pushable(changed []string) (push, readOnly []string) {
+	for _, field := range changed {
+		switch d.Direction(field) {
+		case SyncBidirectional:
+			push = append(push, field)
+		case SyncJiraToLocal:
+			readOnly = append(readOnly, field)
+		}
+	}
+	return push, readOnly
+}
+
+// LocalOnly returns the sorted names of the fields that are never synced with Jira,
+// whose local values a pull must keep (see Ticket.KeepLocalFields).
+func (d FieldDirections) LocalOnly() []string {
+	var fields []string
+	for field := range d {
+		if d.Direction(field) == SyncLocalOnly {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ReadOnlyFieldError reports local edits of fields that only sync from Jira.
+func ReadOnlyFieldError(key TicketKey, fields []string) error {
+	return fmt.Errorf("%w: %s of %s (synced from Jira only)", ErrReadOnlyField, strings.Join(fields, ", "), key)
+}
+
 // FieldValue is a value object representing a field's value with type safety.
 // It wraps the underlying value and provides type-safe access methods.
 type FieldValue struct {
@@ -51,6 +122,237 @@ func (fv FieldValue) IsZero() bool {
 	return fv.raw == nil
 }
 
+// FieldKind is the kind of value a FieldValue holds.
+type FieldKind string
+
+const (
+	// FieldKindNone is the kind of the zero FieldValue
+	FieldKindNone FieldKind = "none"
+
+	// FieldKindString is the kind of strings
+	FieldKindString FieldKind = "string"
+
+	// FieldKindInt is the kind of integers of any size
+	FieldKindInt FieldKind = "int"
+
+	// FieldKindFloat is the kind of floating-point numbers, including whole numbers
+	// decoded from JSON
+	FieldKindFloat FieldKind = "float"
+
+	// FieldKindBool is the kind of booleans
+	FieldKindBool FieldKind = "bool"
+
+	// FieldKindTime is the kind of time.Time values
+	FieldKindTime FieldKind = "time"
+
+	// FieldKindList is the kind of lists ([]string or []interface{})
+	FieldKindList FieldKind = "list"
+
+	// FieldKindOther is the kind of any other value, such as a map
+	FieldKindOther FieldKind = "other"
+)
+
+// Kind returns the kind of value the field holds.
+func (fv FieldValue) Kind() FieldKind {
+	switch fv.raw.(type) {
+	case nil:
+		return FieldKindNone
+	case string:
+		return FieldKindString
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return FieldKindInt
+	case float32, float64:
+		return FieldKindFloat
+	case bool:
+		return FieldKindBool
+	case time.Time:
+		return FieldKindTime
+	case []string, []interface{}:
+		return FieldKindList
+	}
+	return FieldKindOther
+}
+
+// Int returns the value as an integer. Whole floats (as decoded from JSON) and strings
+// holding an integer convert; ok is false for any other value.
+func (fv FieldValue) Int() (int64, bool) {
+	switch v := fv.raw.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float32:
+		return wholeFloat(float64(v))
+	case float64:
+		return wholeFloat(v)
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// wholeFloat returns f as an integer if it is a whole number in range.
+func wholeFloat(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// Float returns the value as a floating-point number. Integers and strings holding a
+// number convert; ok is false for any other value.
+func (fv FieldValue) Float() (float64, bool) {
+	switch v := fv.raw.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	if n, ok := fv.Int(); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// Bool returns the value as a boolean. Strings such as "true" and "false" convert (see
+// strconv.ParseBool); ok is false for any other value.
+func (fv FieldValue) Bool() (bool, bool) {
+	switch v := fv.raw.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		return b, err == nil
+	}
+	return false, false
+}
+
+// fieldTimeLayouts are the layouts of times held as strings: as written to storage
+// (RFC 3339), as returned by Jira, and Jira's date fields.
+var fieldTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.000-0700", time.DateOnly}
+
+// Time returns the value as a UTC time. Strings in RFC 3339, Jira's timestamp format,
+// or as a date (2006-01-02) convert; ok is false for any other value.
+func (fv FieldValue) Time() (time.Time, bool) {
+	switch v := fv.raw.(type) {
+	case time.Time:
+		return v.UTC(), true
+	case string:
+		for _, layout := range fieldTimeLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t.UTC(), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// StringSlice returns a copy of the strings of a list value, as pulled from Jira
+// ([]string) or read back from storage ([]interface{}). ok is false for values that are
+// not lists of strings.
+func (fv FieldValue) StringSlice() ([]string, bool) {
+	switch v := fv.raw.(type) {
+	case []string:
+		return slices.Clone(v), true
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			s, ok := value.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// Equal returns true if both values are the same regardless of how they are held, so a
+// value that went through storage compares equal to the value pulled from Jira: numbers
+// compare by value (3 equals 3.0), lists of strings element by element, and times by
+// instant. Values of different kinds are otherwise not equal.
+func (fv FieldValue) Equal(other FieldValue) bool {
+	kind, otherKind := fv.Kind(), other.Kind()
+	switch {
+	case kind == FieldKindInt && otherKind == FieldKindInt:
+		a, aok := fv.Int()
+		b, bok := other.Int()
+		if aok && bok {
+			return a == b
+		}
+		return fmt.Sprint(fv.raw) == fmt.Sprint(other.raw)
+	case isNumberKind(kind) && isNumberKind(otherKind):
+		a, _ := fv.Float()
+		b, _ := other.Float()
+		return a == b
+	case kind != otherKind:
+		return false
+	}
+
+	switch kind {
+	case FieldKindNone:
+		return true
+	case FieldKindTime:
+		a, _ := fv.Time()
+		b, _ := other.Time()
+		return a.Equal(b)
+	case FieldKindList:
+		a, aok := fv.StringSlice()
+		b, bok := other.StringSlice()
+		if aok && bok {
+			return slices.Equal(a, b)
+		}
+	}
+	return fmt.Sprint(fv.raw) == fmt.Sprint(other.raw)
+}
+
+// isNumberKind returns true for the kinds of numbers.
+func isNumberKind(kind FieldKind) bool {
+	return kind == FieldKindInt || kind == FieldKindFloat
+}
+
+// stringValues returns the strings of a list custom field value, as pulled from Jira
+// ([]string) or read back from storage ([]interface{}).
+func stringValues(raw interface{}) []string {
+	var values []string
+	switch raw := raw.(type) {
+	case []string:
+		values = append(values, raw...)
+	case []interface{}:
+		for _, value := range raw {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	case string:
+		if raw != "" {
+			values = append(values, raw)
+		}
+	}
+	return values
+}
+
 // CustomField represents a user-defined custom field configuration.
 // This is a value object that defines how a custom field should behave.
 type CustomField struct {
@@ -106,10 +408,7 @@ func (cf *CustomField) Validate() error {
 	}
 
 	// Validate sync direction
-	switch cf.SyncDirection {
-	case SyncBidirectional, SyncJiraToLocal, SyncLocalOnly:
-		// Valid
-	default:
+	if !cf.SyncDirection.IsValid() {
 		return fmt.Errorf("%w: invalid sync direction: %s", ErrInvalidInput, cf.SyncDirection)
 	}
 
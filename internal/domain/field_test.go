@@ -1,7 +1,11 @@
 package domain
 
 import (
+	"errors"
+	"math"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestFieldValue(t *testing.T) {
@@ -56,6 +60,173 @@ func TestFieldValue(t *testing.T) {
 	}
 }
 
+func TestFieldValue_Kind(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  FieldKind
+	}{
+		{nil, FieldKindNone},
+		{"text", FieldKindString},
+		{int64(3), FieldKindInt},
+		{uint8(3), FieldKindInt},
+		{3.0, FieldKindFloat},
+		{true, FieldKindBool},
+		{time.Now(), FieldKindTime},
+		{[]string{"a"}, FieldKindList},
+		{[]interface{}{"a", 1}, FieldKindList},
+		{map[string]interface{}{"id": "1"}, FieldKindOther},
+	}
+	for _, tt := range tests {
+		if got := NewFieldValue(tt.value).Kind(); got != tt.want {
+			t.Errorf("Kind(%#v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFieldValue_Accessors(t *testing.T) {
+	t.Run("Int", func(t *testing.T) {
+		tests := []struct {
+			value  interface{}
+			want   int64
+			wantOK bool
+		}{
+			{5, 5, true},
+			{int64(-2), -2, true},
+			{uint64(7), 7, true},
+			{uint64(math.MaxUint64), 0, false},
+			{5.0, 5, true},
+			{5.5, 0, false},
+			{math.Inf(1), 0, false},
+			{" 42 ", 42, true},
+			{"4.2", 0, false},
+			{true, 0, false},
+			{nil, 0, false},
+		}
+		for _, tt := range tests {
+			got, ok := NewFieldValue(tt.value).Int()
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("Int(%#v) = %d, %v; want %d, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		}
+	})
+
+	t.Run("Float", func(t *testing.T) {
+		tests := []struct {
+			value  interface{}
+			want   float64
+			wantOK bool
+		}{
+			{2.5, 2.5, true},
+			{float32(0.5), 0.5, true},
+			{3, 3, true},
+			{"1.25", 1.25, true},
+			{"many", 0, false},
+			{[]string{"1"}, 0, false},
+		}
+		for _, tt := range tests {
+			got, ok := NewFieldValue(tt.value).Float()
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("Float(%#v) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		}
+	})
+
+	t.Run("Bool", func(t *testing.T) {
+		tests := []struct {
+			value  interface{}
+			want   bool
+			wantOK bool
+		}{
+			{true, true, true},
+			{"false", false, true},
+			{"yes", false, false},
+			{1, false, false},
+		}
+		for _, tt := range tests {
+			got, ok := NewFieldValue(tt.value).Bool()
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Bool(%#v) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		}
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		want := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+		tests := []struct {
+			value  interface{}
+			want   time.Time
+			wantOK bool
+		}{
+			{want.In(time.FixedZone("CET", 3600)), want, true},
+			{"2024-03-01T09:30:00Z", want, true},
+			{"2024-03-01T10:30:00.000+0100", want, true},
+			{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), true},
+			{"next week", time.Time{}, false},
+			{1709285400, time.Time{}, false},
+		}
+		for _, tt := range tests {
+			got, ok := NewFieldValue(tt.value).Time()
+			if ok != tt.wantOK || !got.Equal(tt.want) || (ok && got.Location() != time.UTC) {
+				t.Errorf("Time(%#v) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		}
+	})
+
+	t.Run("StringSlice", func(t *testing.T) {
+		original := []string{"a", "b"}
+		got, ok := NewFieldValue(original).StringSlice()
+		if !ok || !slices.Equal(got, original) {
+			t.Errorf("StringSlice([]string) = %v, %v", got, ok)
+		}
+		got[0] = "changed"
+		if original[0] != "a" {
+			t.Error("StringSlice() returned the underlying slice, want a copy")
+		}
+
+		if got, ok := NewFieldValue([]interface{}{"a", "b"}).StringSlice(); !ok || !slices.Equal(got, original) {
+			t.Errorf("StringSlice([]interface{}) = %v, %v", got, ok)
+		}
+		for _, value := range []interface{}{[]interface{}{"a", 1}, "a", nil} {
+			if got, ok := NewFieldValue(value).StringSlice(); ok {
+				t.Errorf("StringSlice(%#v) = %v, want not ok", value, got)
+			}
+		}
+	})
+}
+
+func TestFieldValue_Equal(t *testing.T) {
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		a, b interface{}
+		want bool
+	}{
+		{"both zero", nil, nil, true},
+		{"zero and empty string", nil, "", false},
+		{"same string", "core", "core", true},
+		{"different string", "core", "web", false},
+		{"int and JSON float", 3, 3.0, true},
+		{"int sizes", int32(3), int64(3), true},
+		{"int and fraction", 3, 3.5, false},
+		{"number and numeric string", 3, "3", false},
+		{"string list and stored list", []string{"a", "b"}, []interface{}{"a", "b"}, true},
+		{"list order", []string{"a", "b"}, []string{"b", "a"}, false},
+		{"times in different zones", at, at.In(time.FixedZone("CET", 3600)), true},
+		{"different times", at, at.Add(time.Second), false},
+		{"same bool", true, true, true},
+		{"maps", map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "1"}, true},
+	}
+	for _, tt := range tests {
+		a, b := NewFieldValue(tt.a), NewFieldValue(tt.b)
+		if got := a.Equal(b); got != tt.want {
+			t.Errorf("%s: Equal() = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := b.Equal(a); got != tt.want {
+			t.Errorf("%s: reversed Equal() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestNewCustomField(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -325,3 +496,54 @@ func TestDerivedField(t *testing.T) {
 		}
 	})
 }
+
+func TestFieldDirections(t *testing.T) {
+	sync := SyncConfig{
+		FieldDirections: FieldDirections{"labels": SyncJiraToLocal, "notes": SyncLocalOnly, JiraFieldsField: SyncLocalOnly},
+		ProjectFieldDirections: map[string]FieldDirections{
+			"OPS": {"labels": SyncBidirectional, "priority": SyncJiraToLocal},
+		},
+	}
+
+	if got := sync.FieldDirectionsFor("JMD").Direction("labels"); got != SyncJiraToLocal {
+		t.Errorf("JMD labels = %s, want the global override", got)
+	}
+	if got := sync.FieldDirectionsFor("OPS").Direction("labels"); got != SyncBidirectional {
+		t.Errorf("OPS labels = %s, want the project override", got)
+	}
+	if got := sync.FieldDirectionsFor("JMD").Direction("summary"); got != SyncBidirectional {
+		t.Errorf("JMD summary = %s, want bidirectional by default", got)
+	}
+
+	if got := sync.FieldDirectionsFor("JMD").Direction(JiraFieldsField); got != SyncJiraToLocal {
+		t.Errorf("JMD %s = %s, want read-only despite the override", JiraFieldsField, got)
+	}
+
+	push, readOnly := sync.FieldDirectionsFor("OPS").Pushable([]string{"labels", "notes", "priority", "summary", JiraFieldsField})
+	if !slices.Equal(push, []string{"labels", "summary"}) {
+		t.Errorf("Pushable() push = %v, want [labels summary]", push)
+	}
+	if !slices.Equal(readOnly, []string{"priority", JiraFieldsField}) {
+		t.Errorf("Pushable() readOnly = %v, want [priority %s]", readOnly, JiraFieldsField)
+	}
+
+	if got := sync.FieldDirectionsFor("OPS").LocalOnly(); !slices.Equal(got, []string{"notes"}) {
+		t.Errorf("LocalOnly() = %v, want [notes]", got)
+	}
+
+	key, _ := NewTicketKey("OPS-1")
+	if err := ReadOnlyFieldError(key, readOnly); !errors.Is(err, ErrReadOnlyField) {
+		t.Errorf("ReadOnlyFieldError() = %v, want ErrReadOnlyField", err)
+	}
+}
+
+func TestSyncDirection_IsValid(t *testing.T) {
+	for _, direction := range []SyncDirection{SyncBidirectional, SyncJiraToLocal, SyncLocalOnly} {
+		if !direction.IsValid() {
+			t.Errorf("%s.IsValid() = false", direction)
+		}
+	}
+	if SyncDirection("push_only").IsValid() {
+		t.Error("push_only.IsValid() = true")
+	}
+}
@@ -0,0 +1,740 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FilterOptions supplies the values JQL functions resolve to when filtering locally.
+type FilterOptions struct {
+	// CurrentUser is what currentUser() matches (typically the configured Jira email)
+	CurrentUser string
+
+	// Now is the reference time for now() and relative dates such as "-7d"
+	Now time.Time
+}
+
+// TicketFilter is a parsed JQL query that can be evaluated against local tickets.
+// It supports the commonly used subset of JQL:
+//
+//   - Fields: project, key, summary, description, text, status, type, priority,
+//     assignee, reporter, labels, created, updated, and custom fields by name
+//   - Operators: =, !=, ~, !~, IN, NOT IN, IS [NOT] EMPTY, and >, >=, <, <= on dates
+//   - Logic: AND, OR, NOT, and parentheses
+//   - Functions: currentUser() and now()
+//   - ORDER BY with ASC/DESC
+//
+// TicketFilter is immutable after parsing.
+type TicketFilter struct {
+	query string
+	where filterExpr
+	order []filterOrder
+}
+
+// ParseTicketFilter parses a JQL query for local evaluation.
+// An empty query matches every ticket.
+// Returns ErrInvalidInput if the query is malformed or uses unsupported JQL.
+func ParseTicketFilter(query string, opts FilterOptions) (*TicketFilter, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	tokens, err := tokenizeFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens, opts: opts}
+	filter := &TicketFilter{query: strings.TrimSpace(query)}
+
+	if !p.atEnd() && !p.peekKeyword("ORDER") {
+		if filter.where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peekKeyword("ORDER") {
+		if filter.order, err = p.parseOrderBy(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !p.atEnd() {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+
+	return filter, nil
+}
+
+// String returns the query the filter was parsed from.
+func (f *TicketFilter) String() string {
+	return f.query
+}
+
+// Matches reports whether a ticket satisfies the filter's conditions.
+func (f *TicketFilter) Matches(t *Ticket) bool {
+	if f.where == nil {
+		return true
+	}
+	return f.where.matches(t)
+}
+
+// Apply returns the tickets matching the filter, sorted by its ORDER BY clause.
+// Without ORDER BY the input order is preserved.
+func (f *TicketFilter) Apply(tickets []*Ticket) []*Ticket {
+	matched := make([]*Ticket, 0, len(tickets))
+	for _, t := range tickets {
+		if f.Matches(t) {
+			matched = append(matched, t)
+		}
+	}
+
+	if len(f.order) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			for _, o := range f.order {
+				c := o.compare(matched[i], matched[j])
+				if c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+	}
+
+	return matched
+}
+
+// filterExpr is a node of the parsed WHERE clause.
+type filterExpr interface {
+	matches(t *Ticket) bool
+}
+
+type andExpr struct{ left, right filterExpr }
+
+func (e andExpr) matches(t *Ticket) bool { return e.left.matches(t) && e.right.matches(t) }
+
+type orExpr struct{ left, right filterExpr }
+
+func (e orExpr) matches(t *Ticket) bool { return e.left.matches(t) || e.right.matches(t) }
+
+type notExpr struct{ expr filterExpr }
+
+func (e notExpr) matches(t *Ticket) bool { return !e.expr.matches(t) }
+
+// filterField names a ticket field a clause can refer to.
+type filterField struct {
+	name   string // canonical lower-case name, or the custom field name
+	custom bool
+	date   bool
+}
+
+// filterFieldAliases maps accepted JQL field names to their canonical names.
+var filterFieldAliases = map[string]string{
+	"project":     "project",
+	"key":         "key",
+	"issuekey":    "key",
+	"summary":     "summary",
+	"description": "description",
+	"text":        "text",
+	"status":      "status",
+	"type":        "type",
+	"issuetype":   "type",
+	"priority":    "priority",
+	"assignee":    "assignee",
+	"reporter":    "reporter",
+	"labels":      "labels",
+	"label":       "labels",
+	"created":     "created",
+	"createddate": "created",
+	"updated":     "updated",
+	"updateddate": "updated",
+}
+
+// newFilterField resolves a JQL field name, treating unknown names as custom fields.
+func newFilterField(name string) filterField {
+	canonical, ok := filterFieldAliases[strings.ToLower(name)]
+	if !ok {
+		return filterField{name: name, custom: true}
+	}
+	return filterField{name: canonical, date: canonical == "created" || canonical == "updated"}
+}
+
+// values returns the field's string values on a ticket; empty fields yield none.
+func (f filterField) values(t *Ticket) []string {
+	var values []string
+	add := func(s string) {
+		if s != "" {
+			values = append(values, s)
+		}
+	}
+
+	if f.custom {
+		for name, value := range t.CustomFields {
+			if strings.EqualFold(name, f.name) {
+				add(value.String())
+			}
+		}
+		return values
+	}
+
+	switch f.name {
+	case "project":
+		add(t.Key.ProjectKey())
+	case "key":
+		add(t.Key.String())
+	case "summary":
+		add(t.Summary)
+	case "description":
+		add(t.Description)
+	case "text":
+		add(t.Summary)
+		add(t.Description)
+	case "status":
+		add(t.Status)
+	case "type":
+		add(t.IssueType)
+	case "priority":
+		add(t.Priority)
+	case "assignee":
+		add(t.Assignee)
+	case "reporter":
+		add(t.Reporter)
+	case "labels":
+		for _, label := range t.Labels {
+			add(label)
+		}
+	}
+	return values
+}
+
+// timeValue returns a date field's value on a ticket.
+func (f filterField) timeValue(t *Ticket) time.Time {
+	if f.name == "created" {
+		return t.Created
+	}
+	return t.Updated
+}
+
+// compareClause is a field/operator/value condition such as status != Done.
+type compareClause struct {
+	field   filterField
+	op      string // =, !=, ~, !~, in, not in
+	targets []string
+}
+
+func (c compareClause) matches(t *Ticket) bool {
+	values := c.field.values(t)
+
+	switch c.op {
+	case "=", "in":
+		return anyValue(values, c.targets, strings.EqualFold)
+	case "!=", "not in":
+		// Like Jira, negative operators never match empty fields
+		return len(values) > 0 && !anyValue(values, c.targets, strings.EqualFold)
+	case "~":
+		return anyValue(values, c.targets, containsFold)
+	case "!~":
+		return !anyValue(values, c.targets, containsFold)
+	}
+	return false
+}
+
+// emptyClause is an IS [NOT] EMPTY condition.
+type emptyClause struct {
+	field filterField
+	not   bool
+}
+
+func (c emptyClause) matches(t *Ticket) bool {
+	var empty bool
+	if c.field.date {
+		empty = c.field.timeValue(t).IsZero()
+	} else {
+		empty = len(c.field.values(t)) == 0
+	}
+	return empty != c.not
+}
+
+// dateClause compares a date field with a point in time.
+type dateClause struct {
+	field filterField
+	op    string // >, >=, <, <=
+	at    time.Time
+}
+
+func (c dateClause) matches(t *Ticket) bool {
+	v := c.field.timeValue(t)
+	if v.IsZero() {
+		return false
+	}
+
+	switch c.op {
+	case ">":
+		return v.After(c.at)
+	case ">=":
+		return !v.Before(c.at)
+	case "<":
+		return v.Before(c.at)
+	case "<=":
+		return !v.After(c.at)
+	}
+	return false
+}
+
+// filterOrder is one ORDER BY term.
+type filterOrder struct {
+	field filterField
+	desc  bool
+}
+
+// compare orders two tickets by the term's field, returning -1, 0, or 1.
+func (o filterOrder) compare(a, b *Ticket) int {
+	var c int
+	switch {
+	case o.field.date:
+		c = o.field.timeValue(a).Compare(o.field.timeValue(b))
+	case o.field.name == "key" && !o.field.custom:
+		c = a.Key.Compare(b.Key)
+	default:
+		c = strings.Compare(
+			strings.ToLower(strings.Join(o.field.values(a), ",")),
+			strings.ToLower(strings.Join(o.field.values(b), ",")))
+	}
+
+	if o.desc {
+		return -c
+	}
+	return c
+}
+
+func anyValue(values, targets []string, match func(value, target string) bool) bool {
+	for _, v := range values {
+		for _, target := range targets {
+			if match(v, target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsFold(value, target string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(target))
+}
+
+// filterToken is a lexical token of a JQL query.
+type filterToken struct {
+	text   string
+	quoted bool // string literal; never treated as a keyword or operator
+	pos    int
+}
+
+// filterOperators lists symbol operators, longest first so "!=" wins over "!".
+var filterOperators = []string{"!=", "!~", ">=", "<=", "=", "~", ">", "<", "(", ")", ","}
+
+// tokenizeFilter splits a JQL query into words, string literals, and symbols.
+func tokenizeFilter(query string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated string at position %d", ErrInvalidInput, start+1)
+			}
+			i++
+			tokens = append(tokens, filterToken{text: sb.String(), quoted: true, pos: start})
+
+		default:
+			if op := matchFilterOperator(runes[i:]); op != "" {
+				tokens = append(tokens, filterToken{text: op, pos: i})
+				i += len(op)
+				continue
+			}
+
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '"' && runes[i] != '\'' &&
+				matchFilterOperator(runes[i:]) == "" {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidInput, string(r), start+1)
+			}
+			tokens = append(tokens, filterToken{text: string(runes[start:i]), pos: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+// matchFilterOperator returns the operator at the start of runes, if any.
+// '-' and '+' are deliberately not operators so keys (JMD-1) and offsets (-7d) stay one word.
+func matchFilterOperator(runes []rune) string {
+	for _, op := range filterOperators {
+		if strings.HasPrefix(string(runes[:min(len(runes), len(op))]), op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// filterParser is a recursive-descent parser over filter tokens.
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+	opts   FilterOptions
+}
+
+func (p *filterParser) atEnd() bool { return p.pos >= len(p.tokens) }
+
+func (p *filterParser) peek() filterToken {
+	if p.atEnd() {
+		return filterToken{pos: -1}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// peekKeyword reports whether the next token is the given unquoted keyword.
+func (p *filterParser) peekKeyword(keyword string) bool {
+	t := p.peek()
+	return !p.atEnd() && !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+// peekSymbol reports whether the next token is the given unquoted symbol.
+func (p *filterParser) peekSymbol(symbol string) bool {
+	t := p.peek()
+	return !p.atEnd() && !t.quoted && t.text == symbol
+}
+
+func (p *filterParser) expectSymbol(symbol string) error {
+	if !p.peekSymbol(symbol) {
+		return p.errorf("expected %q", symbol)
+	}
+	p.pos++
+	return nil
+}
+
+// errorf reports a parse error at the current token.
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if p.atEnd() {
+		return fmt.Errorf("%w: %s at end of query", ErrInvalidInput, msg)
+	}
+	return fmt.Errorf("%w: %s at position %d", ErrInvalidInput, msg, p.peek().pos+1)
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("AND") {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (filterExpr, error) {
+	if p.peekKeyword("NOT") {
+		p.pos++
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+	}
+
+	if p.peekSymbol("(") {
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+
+	return p.parseClause()
+}
+
+// parseClause parses "field operator value".
+func (p *filterParser) parseClause() (filterExpr, error) {
+	if p.atEnd() {
+		return nil, p.errorf("expected a field")
+	}
+	fieldToken := p.next()
+	if !fieldToken.quoted && matchFilterOperator([]rune(fieldToken.text)) != "" {
+		p.pos--
+		return nil, p.errorf("expected a field, got %q", fieldToken.text)
+	}
+	field := newFilterField(fieldToken.text)
+
+	switch {
+	case p.peekKeyword("IS"):
+		p.pos++
+		not := false
+		if p.peekKeyword("NOT") {
+			p.pos++
+			not = true
+		}
+		if !p.peekKeyword("EMPTY") && !p.peekKeyword("NULL") {
+			return nil, p.errorf("expected EMPTY after IS")
+		}
+		p.pos++
+		return emptyClause{field: field, not: not}, nil
+
+	case p.peekKeyword("IN"):
+		p.pos++
+		targets, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return p.newCompareClause(field, "in", targets)
+
+	case p.peekKeyword("NOT"):
+		p.pos++
+		if !p.peekKeyword("IN") {
+			return nil, p.errorf("expected IN after NOT")
+		}
+		p.pos++
+		targets, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return p.newCompareClause(field, "not in", targets)
+	}
+
+	opToken := p.peek()
+	if p.atEnd() || opToken.quoted {
+		return nil, p.errorf("expected an operator after %s", fieldToken.text)
+	}
+	op := opToken.text
+
+	switch op {
+	case "=", "!=", "~", "!~":
+		p.pos++
+		if p.peekKeyword("EMPTY") || p.peekKeyword("NULL") {
+			p.pos++
+			return emptyClause{field: field, not: op == "!=" || op == "!~"}, nil
+		}
+		target, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return p.newCompareClause(field, op, []string{target})
+
+	case ">", ">=", "<", "<=":
+		if !field.date {
+			return nil, p.errorf("operator %s is only supported on created and updated", op)
+		}
+		p.pos++
+		valuePos := p.peek().pos
+		target, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		at, err := parseFilterDate(target, p.opts.Now)
+		if err != nil {
+			return nil, fmt.Errorf("%w at position %d", err, valuePos+1)
+		}
+		return dateClause{field: field, op: op, at: at}, nil
+	}
+
+	return nil, p.errorf("unsupported operator %q", op)
+}
+
+// newCompareClause builds a comparison, rejecting operators that make no sense on dates.
+func (p *filterParser) newCompareClause(field filterField, op string, targets []string) (filterExpr, error) {
+	if field.date {
+		return nil, p.errorf("use >, >=, <, or <= to compare %s", field.name)
+	}
+	return compareClause{field: field, op: op, targets: targets}, nil
+}
+
+// parseList parses a parenthesized, comma-separated list of values.
+func (p *filterParser) parseList() ([]string, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		if p.peekSymbol(",") {
+			p.pos++
+			continue
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+}
+
+// parseValue parses a literal or a supported function call.
+func (p *filterParser) parseValue() (string, error) {
+	if p.atEnd() {
+		return "", p.errorf("expected a value")
+	}
+
+	t := p.peek()
+	if t.quoted {
+		p.pos++
+		return t.text, nil
+	}
+	if matchFilterOperator([]rune(t.text)) != "" {
+		return "", p.errorf("expected a value, got %q", t.text)
+	}
+	p.pos++
+
+	if !p.peekSymbol("(") {
+		return t.text, nil
+	}
+
+	// Function call; only argument-less functions are supported locally
+	p.pos++
+	if err := p.expectSymbol(")"); err != nil {
+		return "", err
+	}
+
+	switch strings.ToLower(t.text) {
+	case "currentuser":
+		if p.opts.CurrentUser == "" {
+			return "", fmt.Errorf("%w: currentUser() is unknown", ErrInvalidInput)
+		}
+		return p.opts.CurrentUser, nil
+	case "now":
+		return p.opts.Now.UTC().Format(time.RFC3339), nil
+	}
+
+	return "", fmt.Errorf("%w: function %s() is not supported locally at position %d", ErrInvalidInput, t.text, t.pos+1)
+}
+
+// parseOrderBy parses "ORDER BY field [ASC|DESC], ...".
+func (p *filterParser) parseOrderBy() ([]filterOrder, error) {
+	p.pos++ // ORDER
+	if !p.peekKeyword("BY") {
+		return nil, p.errorf("expected BY after ORDER")
+	}
+	p.pos++
+
+	var order []filterOrder
+	for {
+		if p.atEnd() {
+			return nil, p.errorf("expected a field")
+		}
+		term := filterOrder{field: newFilterField(p.next().text)}
+
+		if p.peekKeyword("ASC") {
+			p.pos++
+		} else if p.peekKeyword("DESC") {
+			p.pos++
+			term.desc = true
+		}
+		order = append(order, term)
+
+		if !p.peekSymbol(",") {
+			return order, nil
+		}
+		p.pos++
+	}
+}
+
+// filterDateLayouts are the absolute date formats accepted in date comparisons.
+var filterDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// parseFilterDate parses an absolute date or a relative offset such as "-7d" or "2w".
+// Absolute dates without a zone are interpreted in now's location, as Jira uses the user's zone.
+func parseFilterDate(s string, now time.Time) (time.Time, error) {
+	for _, layout := range filterDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	// Relative offsets: optional sign, number, unit (w, d, h, m)
+	if len(s) >= 2 {
+		unit := s[len(s)-1]
+		amount, err := strconv.Atoi(s[:len(s)-1])
+		if err == nil {
+			var d time.Duration
+			switch unit {
+			case 'w':
+				d = 7 * 24 * time.Hour
+			case 'd':
+				d = 24 * time.Hour
+			case 'h':
+				d = time.Hour
+			case 'm':
+				d = time.Minute
+			}
+			if d != 0 {
+				return now.Add(time.Duration(amount) * d), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: invalid date %q (expected YYYY-MM-DD or an offset like -7d)", ErrInvalidInput, s)
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// filterTestTickets returns a small fixed set of tickets for filter tests.
+func filterTestTickets(t *testing.T, now time.Time) []*Ticket {
+	t.Helper()
+
+	newTicket := func(key, summary, status, assignee string, updated time.Time, labels ...string) *Ticket {
+		k, err := NewTicketKey(key)
+		if err != nil {
+			t.Fatalf("NewTicketKey(%q) failed: %v", key, err)
+		}
+		ticket := NewTicket(k, summary, updated.Add(-time.Hour), updated)
+		ticket.Status = status
+		ticket.Assignee = assignee
+		ticket.IssueType = "Story"
+		ticket.Labels = append(ticket.Labels, labels...)
+		return ticket
+	}
+
+	bug := newTicket("JMD-10", "Crash on login", "In Progress", "me@example.com", now.Add(-2*24*time.Hour), "backend")
+	bug.IssueType = "Bug"
+	bug.Priority = "High"
+	bug.CustomFields["team"] = NewFieldValue("Platform")
+
+	return []*Ticket{
+		newTicket("JMD-2", "Add export command", "To Do", "", now.Add(-10*24*time.Hour)),
+		bug,
+		newTicket("JMD-3", "Write docs", "Done", "me@example.com", now.Add(-time.Hour), "docs", "backend"),
+		newTicket("OPS-1", "Rotate keys", "To Do", "ops@example.com", now.Add(-30*24*time.Hour)),
+	}
+}
+
+func TestTicketFilter_Apply(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tickets := filterTestTickets(t, now)
+	opts := FilterOptions{CurrentUser: "me@example.com", Now: now}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"empty query", "", []string{"JMD-2", "JMD-10", "JMD-3", "OPS-1"}},
+		{"equality is case-insensitive", "status = 'to do'", []string{"JMD-2", "OPS-1"}},
+		{"not equal skips empty fields", "assignee != ops@example.com", []string{"JMD-10", "JMD-3"}},
+		{"currentUser and AND", "assignee = currentUser() AND status != Done", []string{"JMD-10"}},
+		{"project and OR", "project = OPS OR type = Bug", []string{"JMD-10", "OPS-1"}},
+		{"parentheses", "project = JMD AND (status = Done OR priority = High)", []string{"JMD-10", "JMD-3"}},
+		{"NOT", "NOT project = JMD", []string{"OPS-1"}},
+		{"IN", `status IN ("To Do", Done)`, []string{"JMD-2", "JMD-3", "OPS-1"}},
+		{"NOT IN", "status NOT IN (Done, 'In Progress')", []string{"JMD-2", "OPS-1"}},
+		{"IS EMPTY", "assignee IS EMPTY", []string{"JMD-2"}},
+		{"IS NOT EMPTY", "labels is not empty", []string{"JMD-10", "JMD-3"}},
+		{"labels match any value", "labels = docs", []string{"JMD-3"}},
+		{"contains", "summary ~ LOG", []string{"JMD-10"}},
+		{"text searches summary and description", "text ~ docs", []string{"JMD-3"}},
+		{"relative date", "updated >= -7d", []string{"JMD-10", "JMD-3"}},
+		{"absolute date", "created < 2026-10-01", []string{"OPS-1"}},
+		{"custom field", "team = platform", []string{"JMD-10"}},
+		{"key order is numeric", "project = JMD ORDER BY key DESC", []string{"JMD-10", "JMD-3", "JMD-2"}},
+		{"order without condition", "ORDER BY updated DESC, key", []string{"JMD-3", "JMD-10", "JMD-2", "OPS-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseTicketFilter(tt.query, opts)
+			if err != nil {
+				t.Fatalf("ParseTicketFilter(%q) error = %v", tt.query, err)
+			}
+
+			got := filter.Apply(tickets)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply(%q) returned %d tickets, want %v", tt.query, len(got), tt.want)
+			}
+			for i, ticket := range got {
+				if ticket.Key.String() != tt.want[i] {
+					t.Errorf("Apply(%q)[%d] = %s, want %s", tt.query, i, ticket.Key, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseTicketFilter_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  FilterOptions
+	}{
+		{"missing value", "status =", FilterOptions{}},
+		{"unterminated string", `summary ~ "crash`, FilterOptions{}},
+		{"unbalanced parentheses", "(status = Done", FilterOptions{}},
+		{"trailing tokens", "status = Done Done", FilterOptions{}},
+		{"comparison on text field", "status > Done", FilterOptions{}},
+		{"equality on date", "created = 2026-01-01", FilterOptions{}},
+		{"invalid date", "updated > yesterday", FilterOptions{}},
+		{"unsupported function", "sprint in openSprints()", FilterOptions{}},
+		{"unknown current user", "assignee = currentUser()", FilterOptions{}},
+		{"missing BY", "ORDER updated", FilterOptions{}},
+		{"IS without EMPTY", "assignee IS bob", FilterOptions{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTicketFilter(tt.query, tt.opts)
+			if err == nil {
+				t.Fatalf("ParseTicketFilter(%q) expected error", tt.query)
+			}
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("ParseTicketFilter(%q) error = %v, want ErrInvalidInput", tt.query, err)
+			}
+		})
+	}
+}
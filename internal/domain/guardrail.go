@@ -0,0 +1,91 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultMaxTicketsPerProject is the ticket count above which a project is reported
+// as probably mis-scoped when sync.guardrails.max_tickets_per_project is not configured.
+const DefaultMaxTicketsPerProject = 10000
+
+// DefaultMaxFileSize is the markdown file size above which a file is reported when
+// sync.guardrails.max_file_size is not configured.
+const DefaultMaxFileSize = 1 << 20
+
+// DefaultStaleAfter is how long a project may go without an incremental sync before it
+// is reported as stale when sync.guardrails.stale_after is not configured.
+const DefaultStaleAfter = 24 * time.Hour
+
+// Guardrails are limits that flag a sync that has gone wrong, e.g. a mis-scoped JQL
+// mirroring a 100k-issue project, or a daemon that stopped syncing. Exceeding one
+// produces a warning; a zero limit disables its check.
+type Guardrails struct {
+	// MaxTicketsPerProject is the most tickets a project is expected to have
+	MaxTicketsPerProject int
+
+	// MaxFileSize is the largest a ticket's markdown file is expected to be, in bytes
+	MaxFileSize int64
+
+	// StaleAfter is how long a project may go without an incremental sync
+	StaleAfter time.Duration
+}
+
+// DefaultGuardrails returns the guardrails used when none are configured.
+func DefaultGuardrails() Guardrails {
+	return Guardrails{
+		MaxTicketsPerProject: DefaultMaxTicketsPerProject,
+		MaxFileSize:          DefaultMaxFileSize,
+		StaleAfter:           DefaultStaleAfter,
+	}
+}
+
+// Validate checks that no limit is negative.
+func (g Guardrails) Validate() error {
+	switch {
+	case g.MaxTicketsPerProject < 0:
+		return fmt.Errorf("max_tickets_per_project must not be negative, got %d", g.MaxTicketsPerProject)
+	case g.MaxFileSize < 0:
+		return fmt.Errorf("max_file_size must not be negative, got %d", g.MaxFileSize)
+	case g.StaleAfter < 0:
+		return fmt.Errorf("stale_after must not be negative, got %s", g.StaleAfter)
+	}
+	return nil
+}
+
+// CheckTicketCount returns a warning if a project has more tickets than expected,
+// or "" if it does not.
+func (g Guardrails) CheckTicketCount(projectKey string, count int) string {
+	if g.MaxTicketsPerProject == 0 || count <= g.MaxTicketsPerProject {
+		return ""
+	}
+	return fmt.Sprintf("%s has %d tickets, more than the %d allowed by sync.guardrails.max_tickets_per_project; check that the sync is scoped as intended",
+		projectKey, count, g.MaxTicketsPerProject)
+}
+
+// CheckStaleness returns a warning if a project's last incremental sync is older than
+// StaleAfter at now, or "" if it is not. A project that never had an incremental sync
+// is not checked.
+func (g Guardrails) CheckStaleness(projectKey string, lastIncrementalSync, now time.Time) string {
+	if g.StaleAfter == 0 || lastIncrementalSync.IsZero() {
+		return ""
+	}
+	age := now.Sub(lastIncrementalSync)
+	if age <= g.StaleAfter {
+		return ""
+	}
+	return fmt.Sprintf("%s was last synced %s ago, longer than sync.guardrails.stale_after (%s); is the daemon running?",
+		projectKey, age.Truncate(time.Minute), g.StaleAfter)
+}
+
+// CheckFileSize returns a warning if a markdown file is larger than expected, or "" if
+// it is not.
+func (g Guardrails) CheckFileSize(path string, size int64) string {
+	if g.MaxFileSize == 0 || size <= g.MaxFileSize {
+		return ""
+	}
+	return fmt.Sprintf("%s is %d bytes, larger than the %d allowed by sync.guardrails.max_file_size",
+		path, size, g.MaxFileSize)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuardrails(t *testing.T) {
+	g := Guardrails{MaxTicketsPerProject: 100, MaxFileSize: 1024, StaleAfter: time.Hour}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	if got := g.CheckTicketCount("JMD", 100); got != "" {
+		t.Errorf("CheckTicketCount(100) = %q, want no warning", got)
+	}
+	if got := g.CheckTicketCount("JMD", 101); got == "" {
+		t.Error("CheckTicketCount(101) gave no warning")
+	}
+
+	if got := g.CheckStaleness("JMD", now.Add(-time.Hour), now); got != "" {
+		t.Errorf("CheckStaleness(1h ago) = %q, want no warning", got)
+	}
+	if got := g.CheckStaleness("JMD", time.Time{}, now); got != "" {
+		t.Errorf("CheckStaleness(never) = %q, want no warning", got)
+	}
+	if got := g.CheckStaleness("JMD", now.Add(-3*time.Hour), now); got == "" {
+		t.Error("CheckStaleness(3h ago) gave no warning")
+	}
+
+	if got := g.CheckFileSize("JMD-1.md", 1024); got != "" {
+		t.Errorf("CheckFileSize(1024) = %q, want no warning", got)
+	}
+	if got := g.CheckFileSize("JMD-1.md", 1025); got == "" {
+		t.Error("CheckFileSize(1025) gave no warning")
+	}
+
+	// Zero limits disable their checks
+	var off Guardrails
+	if off.CheckTicketCount("JMD", 1e6) != "" || off.CheckFileSize("JMD-1.md", 1<<30) != "" ||
+		off.CheckStaleness("JMD", now.AddDate(-1, 0, 0), now) != "" {
+		t.Error("zero Guardrails gave a warning")
+	}
+
+	if err := DefaultGuardrails().Validate(); err != nil {
+		t.Errorf("DefaultGuardrails().Validate() error = %v", err)
+	}
+	if err := (Guardrails{MaxFileSize: -1}).Validate(); err == nil {
+		t.Error("Validate() accepted a negative max file size")
+	}
+}
@@ -0,0 +1,74 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InboxEvent is a change notification delivered by a Jira webhook, persisted as soon as
+// it arrives so it can be acknowledged at once and processed later, surviving bursts and
+// daemon restarts.
+type InboxEvent struct {
+	// ID identifies the delivery; redeliveries of an event share it, so it is processed once
+	ID string
+
+	// Type is the webhook event, e.g. "jira:issue_updated"
+	Type string
+
+	// TicketKey is the ticket the event is about (zero for events about no ticket)
+	TicketKey TicketKey
+
+	// Payload is the delivered JSON body
+	Payload []byte
+
+	// ReceivedAt is when the event was received (UTC)
+	ReceivedAt time.Time
+
+	// ProcessedAt is when the event was processed (UTC; zero while pending)
+	ProcessedAt time.Time
+}
+
+// NewInboxEvent returns the event delivered with payload at receivedAt. The ID is the
+// delivery identifier Jira sent with it, or the SHA-256 of the payload when there was
+// none, so a redelivered body is still recognized.
+func NewInboxEvent(id, eventType string, key TicketKey, payload []byte, receivedAt time.Time) *InboxEvent {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		sum := sha256.Sum256(payload)
+		id = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return &InboxEvent{
+		ID:         id,
+		Type:       eventType,
+		TicketKey:  key,
+		Payload:    payload,
+		ReceivedAt: receivedAt.UTC(),
+	}
+}
+
+// Processed returns true if the event has been processed.
+func (e *InboxEvent) Processed() bool {
+	return !e.ProcessedAt.IsZero()
+}
+
+// ConcernsProject returns true if the event may affect the tickets of projectKey: it is
+// about one of them, or about no ticket in particular (e.g. a sprint or version change).
+func (e *InboxEvent) ConcernsProject(projectKey string) bool {
+	return e.TicketKey.IsZero() || e.TicketKey.ProjectKey() == projectKey
+}
+
+// Validate checks that the event has an ID and receipt time.
+func (e *InboxEvent) Validate() error {
+	if strings.TrimSpace(e.ID) == "" {
+		return fmt.Errorf("%w: inbox event ID is required", ErrInvalidInput)
+	}
+	if e.ReceivedAt.IsZero() {
+		return fmt.Errorf("%w: inbox event %s has no receipt time", ErrInvalidTimestamp, e.ID)
+	}
+	return nil
+}
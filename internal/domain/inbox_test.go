@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewInboxEvent(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	at := time.Date(2024, 1, 2, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	payload := []byte(`{"webhookEvent":"jira:issue_updated"}`)
+
+	event := NewInboxEvent(" delivery-1 ", "jira:issue_updated", key, payload, at)
+	if event.ID != "delivery-1" {
+		t.Errorf("ID = %q, want delivery-1", event.ID)
+	}
+	if event.ReceivedAt.Location() != time.UTC || event.Processed() {
+		t.Errorf("ReceivedAt = %v, Processed() = %v; want UTC and pending", event.ReceivedAt, event.Processed())
+	}
+	if err := event.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	// Without a delivery ID, identical bodies get the same ID
+	first := NewInboxEvent("", "jira:issue_updated", key, payload, at)
+	again := NewInboxEvent("", "jira:issue_updated", key, payload, at.Add(time.Minute))
+	other := NewInboxEvent("", "jira:issue_updated", key, []byte(`{}`), at)
+	if first.ID != again.ID || first.ID == other.ID {
+		t.Errorf("IDs = %q, %q, %q; want the first two equal", first.ID, again.ID, other.ID)
+	}
+
+	for name, edit := range map[string]func(e *InboxEvent){
+		"no ID":           func(e *InboxEvent) { e.ID = " " },
+		"no receipt time": func(e *InboxEvent) { e.ReceivedAt = time.Time{} },
+	} {
+		invalid := *event
+		edit(&invalid)
+		if err := invalid.Validate(); !errors.Is(err, ErrInvalidInput) && !errors.Is(err, ErrInvalidTimestamp) {
+			t.Errorf("Validate(%s) error = %v, want invalid", name, err)
+		}
+	}
+}
+
+func TestInboxEvent_ConcernsProject(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	at := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	if !NewInboxEvent("1", "jira:issue_updated", key, nil, at).ConcernsProject("JMD") {
+		t.Error("ConcernsProject(JMD) = false for a JMD ticket")
+	}
+	if NewInboxEvent("2", "jira:issue_updated", key, nil, at).ConcernsProject("OPS") {
+		t.Error("ConcernsProject(OPS) = true for a JMD ticket")
+	}
+	if !NewInboxEvent("3", "sprint_started", TicketKey{}, nil, at).ConcernsProject("OPS") {
+		t.Error("ConcernsProject(OPS) = false for an event about no ticket")
+	}
+}
@@ -0,0 +1,225 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SprintsField is the CustomFields key of the names of the sprints a ticket was planned
+// in (Jira Software's sprint field), a list of strings, oldest first.
+const SprintsField = "sprints"
+
+// Sprints returns the names of the sprints the ticket was planned in, oldest first.
+func (t *Ticket) Sprints() []string {
+	return stringValues(t.CustomFields[SprintsField].Raw())
+}
+
+// IndexOptions selects the index files generated besides the ticket files.
+// The zero value generates none.
+type IndexOptions struct {
+	// BySprint generates one index per sprint, listing the tickets planned in it
+	BySprint bool
+
+	// ByAssignee generates one index per assignee, and one of the unassigned tickets
+	ByAssignee bool
+
+	// Filters generates one index per Jira saved filter, listing the tickets its JQL
+	// matches when the index is refreshed
+	Filters []FilterIndex
+}
+
+// Enabled returns true if any index is generated.
+func (o IndexOptions) Enabled() bool {
+	return o.BySprint || o.ByAssignee || len(o.Filters) > 0
+}
+
+// FilterIndex configures an index file mirroring a Jira saved filter, e.g. the filter
+// behind a team dashboard.
+type FilterIndex struct {
+	// ID is the saved filter's ID in Jira
+	ID string
+
+	// Name titles the index and names its file; empty uses the filter's name in Jira as
+	// the title and its ID as the file name
+	Name string
+}
+
+// Slug returns the name of the index file: the slug of the configured name, or the
+// filter ID. It does not depend on the filter's name in Jira, so renaming the filter
+// there keeps the file in place.
+func (f FilterIndex) Slug() string {
+	if strings.TrimSpace(f.Name) != "" {
+		return slugify(f.Name)
+	}
+	return slugify(f.ID)
+}
+
+// SavedFilter is a Jira saved filter: a named, shared JQL query.
+type SavedFilter struct {
+	ID   string
+	Name string
+	JQL  string
+}
+
+// Index groups of TicketIndex.Group.
+const (
+	IndexGroupSprint   = "sprint"
+	IndexGroupAssignee = "assignee"
+	IndexGroupFilter   = "filter"
+)
+
+// UnassignedIndex is the name of the assignee index of unassigned tickets.
+const UnassignedIndex = "Unassigned"
+
+// TicketIndex is an index file listing a group of tickets, e.g. the tickets of a sprint.
+type TicketIndex struct {
+	// Group is what the tickets have in common, one of the IndexGroup* constants
+	Group string
+
+	// Name is the sprint or assignee the index lists the tickets of
+	Name string
+
+	// Slug names the index file within its group, unique in the group
+	Slug string
+
+	// Tickets are the listed tickets in key order, or in the filter's order for saved
+	// filter indexes
+	Tickets []*Ticket
+
+	// Keep leaves the existing index file as it is instead of rewriting it from Tickets,
+	// e.g. when the saved filter it mirrors could not be run
+	Keep bool
+}
+
+// Path returns the path of the index file relative to the index directory, e.g.
+// "assignee/alice.md".
+func (i *TicketIndex) Path() string {
+	return i.Group + "/" + i.Slug + ".md"
+}
+
+// BuildIndexes groups tickets into the indexes selected by options: sprint indexes for
+// every sprint a ticket was planned in, then assignee indexes, each group ordered by
+// slug. Assignees are named by the part of their email before the @ where that is
+// unambiguous (e.g. "alice" for alice@example.com).
+func BuildIndexes(tickets []*Ticket, options IndexOptions) []*TicketIndex {
+	sorted := append([]*Ticket(nil), tickets...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Key.Compare(sorted[j].Key) < 0
+	})
+
+	var indexes []*TicketIndex
+	if options.BySprint {
+		indexes = append(indexes, groupTickets(IndexGroupSprint, sorted, func(t *Ticket) []string {
+			return t.Sprints()
+		})...)
+	}
+	if options.ByAssignee {
+		indexes = append(indexes, groupTickets(IndexGroupAssignee, sorted, func(t *Ticket) []string {
+			if strings.TrimSpace(t.Assignee) == "" {
+				return []string{UnassignedIndex}
+			}
+			return []string{t.Assignee}
+		})...)
+	}
+	return indexes
+}
+
+// NewFilterIndex builds the index mirroring a saved filter that matched tickets, listed
+// in the order the filter returned them.
+func NewFilterIndex(config FilterIndex, filter *SavedFilter, tickets []*Ticket) *TicketIndex {
+	name := strings.TrimSpace(config.Name)
+	if name == "" && filter != nil {
+		name = filter.Name
+	}
+	if name == "" {
+		name = "Filter " + config.ID
+	}
+	return &TicketIndex{Group: IndexGroupFilter, Name: name, Slug: config.Slug(), Tickets: tickets}
+}
+
+// groupTickets builds an index of group for each name that names returns for a ticket.
+func groupTickets(group string, tickets []*Ticket, names func(t *Ticket) []string) []*TicketIndex {
+	byName := make(map[string]*TicketIndex)
+	var ordered []*TicketIndex
+	for _, ticket := range tickets {
+		for _, name := range names(ticket) {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			index, ok := byName[name]
+			if !ok {
+				index = &TicketIndex{Group: group, Name: name}
+				byName[name] = index
+				ordered = append(ordered, index)
+			}
+			if n := len(index.Tickets); n == 0 || index.Tickets[n-1] != ticket {
+				index.Tickets = append(index.Tickets, ticket)
+			}
+		}
+	}
+
+	assignSlugs(ordered)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Slug < ordered[j].Slug
+	})
+	return ordered
+}
+
+// assignSlugs gives each index a unique file name: the slug of its name without any
+// email domain, or of its whole name where that would be ambiguous.
+func assignSlugs(indexes []*TicketIndex) {
+	short := make(map[string]int)
+	for _, index := range indexes {
+		short[slugify(emailLocalPart(index.Name))]++
+	}
+
+	used := make(map[string]bool)
+	for _, index := range indexes {
+		slug := slugify(emailLocalPart(index.Name))
+		if short[slug] > 1 {
+			slug = slugify(index.Name)
+		}
+		base := slug
+		for n := 2; used[slug]; n++ {
+			slug = base + "-" + strconv.Itoa(n)
+		}
+		used[slug] = true
+		index.Slug = slug
+	}
+}
+
+// emailLocalPart returns the part of an email address before the @, or name unchanged
+// if it is not an email address.
+func emailLocalPart(name string) string {
+	if local, _, ok := strings.Cut(name, "@"); ok && local != "" {
+		return local
+	}
+	return name
+}
+
+// slugify lowercases name and replaces every run of characters other than letters and
+// digits with a single dash, for use as a file name ("index" if nothing is left).
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if b.Len() == 0 {
+		return "index"
+	}
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func indexTicket(t *testing.T, key, assignee string, sprints ...string) *Ticket {
+	t.Helper()
+	ticketKey, err := NewTicketKey(key)
+	if err != nil {
+		t.Fatalf("NewTicketKey(%q) error = %v", key, err)
+	}
+	ticket := NewTicket(ticketKey, "Ticket "+key, time.Now(), time.Now())
+	ticket.Assignee = assignee
+	if len(sprints) > 0 {
+		ticket.CustomFields[SprintsField] = NewFieldValue(sprints)
+	}
+	return ticket
+}
+
+func TestBuildIndexes(t *testing.T) {
+	tickets := []*Ticket{
+		indexTicket(t, "JMD-10", "alice@example.com", "Sprint 1", "Sprint 2"),
+		indexTicket(t, "JMD-2", "alice@example.com", "Sprint 2"),
+		indexTicket(t, "JMD-3", "bob@example.com"),
+		indexTicket(t, "JMD-4", "bob@other.example", "Sprint 2"),
+		indexTicket(t, "JMD-5", ""),
+	}
+
+	indexes := BuildIndexes(tickets, IndexOptions{BySprint: true, ByAssignee: true})
+
+	want := []struct {
+		path string
+		name string
+		keys []string
+	}{
+		{"sprint/sprint-1.md", "Sprint 1", []string{"JMD-10"}},
+		{"sprint/sprint-2.md", "Sprint 2", []string{"JMD-2", "JMD-4", "JMD-10"}},
+		{"assignee/alice.md", "alice@example.com", []string{"JMD-2", "JMD-10"}},
+		{"assignee/bob-example-com.md", "bob@example.com", []string{"JMD-3"}},
+		{"assignee/bob-other-example.md", "bob@other.example", []string{"JMD-4"}},
+		{"assignee/unassigned.md", UnassignedIndex, []string{"JMD-5"}},
+	}
+	if len(indexes) != len(want) {
+		t.Fatalf("BuildIndexes() returned %d indexes, want %d", len(indexes), len(want))
+	}
+	for i, index := range indexes {
+		if index.Path() != want[i].path || index.Name != want[i].name {
+			t.Errorf("index %d = %s (%s), want %s (%s)", i, index.Path(), index.Name, want[i].path, want[i].name)
+			continue
+		}
+		var keys []string
+		for _, ticket := range index.Tickets {
+			keys = append(keys, ticket.Key.String())
+		}
+		if len(keys) != len(want[i].keys) {
+			t.Errorf("%s lists %v, want %v", index.Path(), keys, want[i].keys)
+			continue
+		}
+		for j := range keys {
+			if keys[j] != want[i].keys[j] {
+				t.Errorf("%s lists %v, want %v", index.Path(), keys, want[i].keys)
+				break
+			}
+		}
+	}
+}
+
+func TestBuildIndexes_Options(t *testing.T) {
+	tickets := []*Ticket{indexTicket(t, "JMD-1", "alice@example.com", "Sprint 1")}
+
+	if got := BuildIndexes(tickets, IndexOptions{}); len(got) != 0 {
+		t.Errorf("BuildIndexes() with no options = %d indexes, want none", len(got))
+	}
+	if got := BuildIndexes(tickets, IndexOptions{ByAssignee: true}); len(got) != 1 || got[0].Group != IndexGroupAssignee {
+		t.Errorf("BuildIndexes(ByAssignee) = %+v, want only the assignee index", got)
+	}
+}
+
+func TestNewFilterIndex(t *testing.T) {
+	filter := &SavedFilter{ID: "10042", Name: "Team board", JQL: "project = JMD"}
+	tickets := []*Ticket{indexTicket(t, "JMD-9", ""), indexTicket(t, "JMD-1", "")}
+
+	index := NewFilterIndex(FilterIndex{ID: "10042"}, filter, tickets)
+	if index.Path() != "filter/10042.md" || index.Name != "Team board" {
+		t.Errorf("NewFilterIndex() = %s (%s), want filter/10042.md (Team board)", index.Path(), index.Name)
+	}
+	if index.Tickets[0].Key.String() != "JMD-9" {
+		t.Errorf("NewFilterIndex() reordered tickets, want the filter's order")
+	}
+
+	named := NewFilterIndex(FilterIndex{ID: "10042", Name: "My Dashboard"}, nil, nil)
+	if named.Path() != "filter/my-dashboard.md" || named.Name != "My Dashboard" {
+		t.Errorf("NewFilterIndex() with a name = %s (%s), want filter/my-dashboard.md (My Dashboard)", named.Path(), named.Name)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"JMD Sprint 12":   "jmd-sprint-12",
+		"  Über / Team  ": "über-team",
+		"a--b":            "a-b",
+		"!!!":             "index",
+	}
+	for name, want := range tests {
+		if got := slugify(name); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import "strings"
+
+// NormalizeLabels returns labels trimmed, without empty labels and duplicates, in the
+// order they first appear. Labels are a set in Jira, but local copies may list one twice
+// (e.g. frontmatter edited by hand). Labels are case-sensitive, as in Jira.
+func NormalizeLabels(labels []string) []string {
+	normalized := make([]string, 0, len(labels))
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	return normalized
+}
+
+// DiffLabels returns the labels to add to base to get local and the labels to remove
+// from it, each once, in the order they appear in local and base. Edits push these
+// rather than the whole list, which would drop labels added in Jira since base.
+func DiffLabels(base, local []string) (add, remove []string) {
+	base, local = NormalizeLabels(base), NormalizeLabels(local)
+	inBase := make(map[string]bool, len(base))
+	for _, label := range base {
+		inBase[label] = true
+	}
+	inLocal := make(map[string]bool, len(local))
+	for _, label := range local {
+		inLocal[label] = true
+		if !inBase[label] {
+			add = append(add, label)
+		}
+	}
+	for _, label := range base {
+		if !inLocal[label] {
+			remove = append(remove, label)
+		}
+	}
+	return add, remove
+}
+
+// SameLabels reports whether a and b hold the same labels, in any order, with
+// duplicates counted once.
+func SameLabels(a, b []string) bool {
+	add, remove := DiffLabels(a, b)
+	return len(add) == 0 && len(remove) == 0
+}
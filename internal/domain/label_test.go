@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeLabels(t *testing.T) {
+	got := NormalizeLabels([]string{"backend", " api ", "", "backend", "API", "api"})
+	if want := []string{"backend", "api", "API"}; !slices.Equal(got, want) {
+		t.Errorf("NormalizeLabels() = %v, want %v", got, want)
+	}
+	if got := NormalizeLabels(nil); got == nil || len(got) != 0 {
+		t.Errorf("NormalizeLabels(nil) = %#v, want an empty list", got)
+	}
+}
+
+func TestDiffLabels(t *testing.T) {
+	tests := []struct {
+		name        string
+		base, local []string
+		add, remove []string
+	}{
+		{name: "unchanged", base: []string{"a", "b"}, local: []string{"a", "b"}},
+		{name: "reordered", base: []string{"a", "b"}, local: []string{"b", "a"}},
+		{name: "duplicated", base: []string{"a"}, local: []string{"a", "a"}},
+		{name: "added", base: []string{"a"}, local: []string{"a", "c", "b", "c"}, add: []string{"c", "b"}},
+		{name: "removed", base: []string{"a", "b", "c"}, local: []string{"b"}, remove: []string{"a", "c"}},
+		{name: "replaced", base: []string{"a"}, local: []string{"b"}, add: []string{"b"}, remove: []string{"a"}},
+		{name: "cleared", base: []string{"a"}, local: nil, remove: []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, remove := DiffLabels(tt.base, tt.local)
+			if !slices.Equal(add, tt.add) || !slices.Equal(remove, tt.remove) {
+				t.Errorf("DiffLabels() = %v, %v, want %v, %v", add, remove, tt.add, tt.remove)
+			}
+			if same := SameLabels(tt.base, tt.local); same != (len(tt.add) == 0 && len(tt.remove) == 0) {
+				t.Errorf("SameLabels() = %v", same)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// LocalVersionsDir is the directory, relative to the markdown directory, that ticket files
+// with local changes are saved in before a pull overwrites them. It is hidden, so imports
+// and vault tools skip it.
+const LocalVersionsDir = ".jiramd/local-versions"
+
+// LocalVersion is a copy of a ticket file with local changes, saved before a pull
+// overwrote the file with the Jira version (e.g. under the jira_wins conflict strategy),
+// so the local work is never lost.
+type LocalVersion struct {
+	TicketKey TicketKey
+
+	// Path is where the copy was saved
+	Path string
+
+	// ContentHash is the SHA-256 of the saved content, in hex
+	ContentHash string
+
+	// JiraVersion is the Ticket.Version the file was overwritten with
+	JiraVersion string
+
+	// SavedAt is when the copy was saved (UTC)
+	SavedAt time.Time
+}
+
+// LocalVersionName returns the file name the local version of a ticket saved at at is
+// written to, e.g. "JMD-1.20240102T090000.000Z.md". It is not a ticket file name, so
+// the copy is never taken for the ticket's file.
+func LocalVersionName(key TicketKey, at time.Time) string {
+	return fmt.Sprintf("%s.%s.md", key, at.UTC().Format("20060102T150405.000Z"))
+}
+
+// LocalVersionPath returns where, relative to the markdown directory, the local version
+// of a ticket saved at at is written, with forward slashes.
+func LocalVersionPath(key TicketKey, at time.Time) string {
+	return path.Join(LocalVersionsDir, LocalVersionName(key, at))
+}
+
+// NewLocalVersion returns the record of content, the local version of a ticket's file
+// saved to path at savedAt before it was overwritten with the ticket's jiraVersion.
+func NewLocalVersion(key TicketKey, path string, content []byte, jiraVersion string, savedAt time.Time) *LocalVersion {
+	sum := sha256.Sum256(content)
+	return &LocalVersion{
+		TicketKey:   key,
+		Path:        path,
+		ContentHash: hex.EncodeToString(sum[:]),
+		JiraVersion: jiraVersion,
+		SavedAt:     savedAt.UTC(),
+	}
+}
+
+// Validate checks that the version names its ticket, copy, and save time.
+func (v *LocalVersion) Validate() error {
+	if v.TicketKey.IsZero() {
+		return fmt.Errorf("%w: ticket key is required", ErrInvalidInput)
+	}
+	if strings.TrimSpace(v.Path) == "" {
+		return fmt.Errorf("%w: local version of %s has no path", ErrInvalidInput, v.TicketKey)
+	}
+	if v.SavedAt.IsZero() {
+		return fmt.Errorf("%w: local version of %s has no save time", ErrInvalidTimestamp, v.TicketKey)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLocalVersionPath(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	at := time.Date(2024, 1, 2, 9, 0, 0, 123000000, time.FixedZone("CET", 3600))
+
+	if got, want := LocalVersionPath(key, at), ".jiramd/local-versions/JMD-1.20240102T080000.123Z.md"; got != want {
+		t.Errorf("LocalVersionPath() = %q, want %q", got, want)
+	}
+	if _, err := NewTicketKey(LocalVersionName(key, at)[:len(LocalVersionName(key, at))-len(".md")]); err == nil {
+		t.Error("LocalVersionName() is a ticket file name")
+	}
+}
+
+func TestNewLocalVersion(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	at := time.Date(2024, 1, 2, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	v := NewLocalVersion(key, "copy.md", []byte("local notes"), "2024-01-02T08:30:00Z", at)
+	if want := "f15030cd1cc60786d258f8c44b95859bbedcc21af9f0a74d69bb0735c0b1691e"; v.ContentHash != want {
+		t.Errorf("ContentHash = %q, want %q", v.ContentHash, want)
+	}
+	if v.SavedAt.Location() != time.UTC {
+		t.Errorf("SavedAt = %v, want UTC", v.SavedAt)
+	}
+	if err := v.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	for name, edit := range map[string]func(v *LocalVersion){
+		"no key":       func(v *LocalVersion) { v.TicketKey = TicketKey{} },
+		"no path":      func(v *LocalVersion) { v.Path = " " },
+		"no save time": func(v *LocalVersion) { v.SavedAt = time.Time{} },
+	} {
+		invalid := *v
+		edit(&invalid)
+		if err := invalid.Validate(); !errors.Is(err, ErrInvalidInput) && !errors.Is(err, ErrInvalidTimestamp) {
+			t.Errorf("Validate(%s) error = %v, want invalid", name, err)
+		}
+	}
+}
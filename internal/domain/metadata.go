@@ -0,0 +1,167 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMetadataTTL is how long cached project metadata is used before it is fetched
+// from Jira again.
+const DefaultMetadataTTL = 24 * time.Hour
+
+// ProjectMetadata is what Jira allows in a project's tickets: its issue types,
+// priorities, statuses, components, and the workflow transitions between statuses.
+// It is cached so edits can be validated offline.
+type ProjectMetadata struct {
+	ProjectKey string
+	Name       string
+
+	// IssueTypes, Statuses, and Components are the allowed names, sorted. Statuses are
+	// under their local names (see StatusMap).
+	IssueTypes []string
+	Statuses   []string
+	Components []string
+
+	// Priorities are the allowed priorities in Jira's order, highest first
+	Priorities []string
+
+	// FieldOptions are the allowed values of the project's select custom fields in
+	// Jira's order, keyed by field ID (e.g. customfield_10001)
+	FieldOptions map[string][]string
+
+	// Transitions maps a status to the statuses a ticket in it can be moved to. Statuses
+	// without an entry have unknown transitions (no cached ticket was in them).
+	Transitions map[string][]string
+
+	// FetchedAt is when the metadata was fetched from Jira (UTC)
+	FetchedAt time.Time
+}
+
+// Stale returns true if the metadata was fetched ttl or longer before now.
+func (m *ProjectMetadata) Stale(now time.Time, ttl time.Duration) bool {
+	return now.Sub(m.FetchedAt) >= ttl
+}
+
+// TransitionCount returns the number of known transitions between statuses.
+func (m *ProjectMetadata) TransitionCount() int {
+	n := 0
+	for _, targets := range m.Transitions {
+		n += len(targets)
+	}
+	return n
+}
+
+// Normalize sorts and deduplicates the allowed names and transition targets, so equal
+// metadata compares and stores the same whatever order Jira returned it in. Priorities
+// and field options are only deduplicated, since their order is meaningful.
+func (m *ProjectMetadata) Normalize() {
+	m.IssueTypes = sortedUnique(m.IssueTypes)
+	m.Priorities = unique(m.Priorities)
+	m.Statuses = sortedUnique(m.Statuses)
+	m.Components = sortedUnique(m.Components)
+	for field, options := range m.FieldOptions {
+		m.FieldOptions[field] = unique(options)
+	}
+	for status, targets := range m.Transitions {
+		m.Transitions[status] = sortedUnique(targets)
+	}
+}
+
+// Fields whose values ProjectMetadata.AllowedValue checks, named as frontmatter keys.
+// Select custom fields are checked by field ID instead.
+const (
+	MetadataFieldStatus     = "status"
+	MetadataFieldPriority   = "priority"
+	MetadataFieldIssueType  = "type"
+	MetadataFieldComponents = "components"
+)
+
+// maxListedValues caps how many allowed values an error lists.
+const maxListedValues = 20
+
+// AllowedValue checks value against the values Jira allows for field, one of the
+// MetadataField* constants or the ID of a select custom field. Values match
+// case-insensitively and are returned spelled as in Jira. Fields without cached allowed
+// values accept any value.
+// Returns ErrInvalidFieldValue listing the allowed values otherwise, e.g.
+// priority "Urgent" not valid; allowed: Highest, High, Medium, Low, Lowest.
+func (m *ProjectMetadata) AllowedValue(field, value string) (string, error) {
+	var allowed []string
+	label := field
+	switch field {
+	case MetadataFieldStatus:
+		allowed = m.Statuses
+	case MetadataFieldPriority:
+		allowed = m.Priorities
+	case MetadataFieldIssueType:
+		allowed, label = m.IssueTypes, "issue type"
+	case MetadataFieldComponents:
+		allowed, label = m.Components, "component"
+	default:
+		allowed = m.FieldOptions[field]
+	}
+	if len(allowed) == 0 {
+		return value, nil
+	}
+
+	trimmed := strings.TrimSpace(value)
+	for _, candidate := range allowed {
+		if strings.EqualFold(candidate, trimmed) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s %q not valid; allowed: %s", ErrInvalidFieldValue, label, trimmed, listValues(allowed))
+}
+
+// CheckTransition checks that a ticket in status from can be moved to status to, by
+// the cached transitions of from. Moves from statuses with unknown transitions are
+// allowed.
+// Returns ErrInvalidFieldValue listing the statuses reachable from from otherwise.
+func (m *ProjectMetadata) CheckTransition(from, to string) error {
+	targets, ok := m.Transitions[from]
+	if !ok || strings.EqualFold(from, to) {
+		return nil
+	}
+	for _, target := range targets {
+		if strings.EqualFold(target, to) {
+			return nil
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("%w: status %q not reachable from %q; no transitions leave it", ErrInvalidFieldValue, to, from)
+	}
+	return fmt.Errorf("%w: status %q not reachable from %q; allowed: %s", ErrInvalidFieldValue, to, from, listValues(targets))
+}
+
+// listValues joins values for an error message, eliding all but the first
+// maxListedValues.
+func listValues(values []string) string {
+	if len(values) <= maxListedValues {
+		return strings.Join(values, ", ")
+	}
+	return fmt.Sprintf("%s, … (%d more)", strings.Join(values[:maxListedValues], ", "), len(values)-maxListedValues)
+}
+
+// sortedUnique returns the non-empty values sorted without duplicates, never nil.
+func sortedUnique(values []string) []string {
+	sorted := unique(values)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// unique returns the non-empty values without duplicates in their order, never nil.
+func unique(values []string) []string {
+	kept := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
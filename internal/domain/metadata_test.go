@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProjectMetadata_Stale(t *testing.T) {
+	fetched := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	metadata := &ProjectMetadata{ProjectKey: "JMD", FetchedAt: fetched}
+
+	if metadata.Stale(fetched.Add(23*time.Hour), DefaultMetadataTTL) {
+		t.Error("Stale() within the TTL = true, want false")
+	}
+	if !metadata.Stale(fetched.Add(DefaultMetadataTTL), DefaultMetadataTTL) {
+		t.Error("Stale() at the TTL = false, want true")
+	}
+}
+
+func TestProjectMetadata_Normalize(t *testing.T) {
+	metadata := &ProjectMetadata{
+		IssueTypes: []string{"Task", "Bug", "Task"},
+		Priorities: []string{"High", "Medium", "High", "Low"},
+		Statuses:   []string{"To Do", "", "Done"},
+		Transitions: map[string][]string{
+			"To Do": {"In Progress", "Done", "In Progress"},
+			"Done":  {"To Do"},
+		},
+	}
+	metadata.Normalize()
+
+	want := &ProjectMetadata{
+		IssueTypes: []string{"Bug", "Task"},
+		Priorities: []string{"High", "Medium", "Low"},
+		Statuses:   []string{"Done", "To Do"},
+		Components: []string{},
+		Transitions: map[string][]string{
+			"To Do": {"Done", "In Progress"},
+			"Done":  {"To Do"},
+		},
+	}
+	if !reflect.DeepEqual(metadata, want) {
+		t.Errorf("Normalize() = %+v, want %+v", metadata, want)
+	}
+	if got := metadata.TransitionCount(); got != 3 {
+		t.Errorf("TransitionCount() = %d, want 3", got)
+	}
+}
+
+func TestProjectMetadata_AllowedValue(t *testing.T) {
+	metadata := &ProjectMetadata{
+		IssueTypes:   []string{"Bug", "Task"},
+		Priorities:   []string{"Highest", "High", "Medium", "Low", "Lowest"},
+		Statuses:     []string{"Done", "To Do"},
+		FieldOptions: map[string][]string{"customfield_10001": {"Red", "Blue"}},
+	}
+
+	tests := []struct {
+		name    string
+		field   string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "exact", field: MetadataFieldPriority, value: "High", want: "High"},
+		{name: "case-insensitive", field: MetadataFieldIssueType, value: " bug ", want: "Bug"},
+		{name: "custom field option", field: "customfield_10001", value: "blue", want: "Blue"},
+		{name: "no cached values", field: MetadataFieldComponents, value: "Backend", want: "Backend"},
+		{name: "unknown custom field", field: "customfield_10002", value: "Any", want: "Any"},
+		{
+			name:    "invalid priority",
+			field:   MetadataFieldPriority,
+			value:   "Urgent",
+			wantErr: `priority "Urgent" not valid; allowed: Highest, High, Medium, Low, Lowest`,
+		},
+		{
+			name:    "invalid issue type",
+			field:   MetadataFieldIssueType,
+			value:   "Epic",
+			wantErr: `issue type "Epic" not valid; allowed: Bug, Task`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := metadata.AllowedValue(tt.field, tt.value)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidFieldValue) || !strings.HasSuffix(err.Error(), tt.wantErr) {
+					t.Errorf("AllowedValue() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("AllowedValue() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectMetadata_CheckTransition(t *testing.T) {
+	metadata := &ProjectMetadata{
+		Transitions: map[string][]string{
+			"To Do":   {"Done", "In Progress"},
+			"Blocked": {},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		wantErr  string
+	}{
+		{name: "known transition", from: "To Do", to: "in progress"},
+		{name: "unknown transitions", from: "In Review", to: "Done"},
+		{name: "same status", from: "To Do", to: "To Do"},
+		{name: "unreachable", from: "To Do", to: "In Review", wantErr: `status "In Review" not reachable from "To Do"; allowed: Done, In Progress`},
+		{name: "dead end", from: "Blocked", to: "Done", wantErr: `status "Done" not reachable from "Blocked"; no transitions leave it`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := metadata.CheckTransition(tt.from, tt.to)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckTransition() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidFieldValue) || !strings.HasSuffix(err.Error(), tt.wantErr) {
+				t.Errorf("CheckTransition() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
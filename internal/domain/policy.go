@@ -0,0 +1,187 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"fmt"
+)
+
+// ConflictStrategy decides which side wins when a ticket changed both locally and in
+// Jira since it was last synced.
+type ConflictStrategy string
+
+const (
+	// ConflictManual leaves both sides as they are and reports the conflict for the
+	// user to resolve
+	ConflictManual ConflictStrategy = "manual"
+
+	// ConflictJiraWins pulls the Jira revision, discarding the local edits
+	ConflictJiraWins ConflictStrategy = "jira_wins"
+
+	// ConflictLocalWins pushes the local edits over the Jira revision
+	ConflictLocalWins ConflictStrategy = "local_wins"
+
+	// ConflictNewestWins keeps whichever side changed last, and falls back to
+	// ConflictManual when both changed at the same time
+	ConflictNewestWins ConflictStrategy = "newest_wins"
+)
+
+// IsValid returns true if the strategy is one of the known conflict strategies.
+func (s ConflictStrategy) IsValid() bool {
+	switch s {
+	case ConflictManual, ConflictJiraWins, ConflictLocalWins, ConflictNewestWins:
+		return true
+	}
+	return false
+}
+
+// SyncAction is what a sync should do with a ticket.
+type SyncAction string
+
+const (
+	// SyncActionNone means the ticket is left as it is
+	SyncActionNone SyncAction = "none"
+
+	// SyncActionPull means the Jira revision overwrites the local one
+	SyncActionPull SyncAction = "pull"
+
+	// SyncActionPush means the local edits are sent to Jira
+	SyncActionPush SyncAction = "push"
+
+	// SyncActionConflict means both sides changed and the user must choose
+	SyncActionConflict SyncAction = "conflict"
+)
+
+// SyncPolicy decides what a sync does with a ticket, given its TicketState: whether to
+// pull, whether to push, and which side wins a conflict.
+//
+// Direction says which way tickets sync: SyncBidirectional (the default), SyncJiraToLocal
+// (Jira is the source of truth; local edits are never pushed and a Jira change overwrites
+// them), or SyncLocalOnly (nothing syncs). Mode, the configured sync.mode, further
+// disables pulling or pushing (empty means SyncModeBidirectional). Conflicts is the
+// strategy for tickets changed on both sides, ConflictManual by default. The zero value
+// is the default policy.
+type SyncPolicy struct {
+	Direction SyncDirection
+	Mode      SyncMode
+	Conflicts ConflictStrategy
+}
+
+// NewSyncPolicy creates a policy, defaulting an empty direction to SyncBidirectional and
+// an empty strategy to ConflictManual.
+// Returns ErrInvalidInput for unknown directions or strategies.
+func NewSyncPolicy(direction SyncDirection, conflicts ConflictStrategy) (SyncPolicy, error) {
+	policy := SyncPolicy{Direction: direction, Conflicts: conflicts}
+	if err := policy.Validate(); err != nil {
+		return SyncPolicy{}, err
+	}
+	return SyncPolicy{Direction: policy.direction(), Conflicts: policy.conflicts()}, nil
+}
+
+// Validate checks that the direction, mode, and strategy are known or empty.
+func (p SyncPolicy) Validate() error {
+	if !p.direction().IsValid() {
+		return fmt.Errorf("%w: invalid sync direction: %s", ErrInvalidInput, p.Direction)
+	}
+	if p.Mode != "" && !p.Mode.IsValid() {
+		return fmt.Errorf("%w: invalid sync mode: %s", ErrInvalidInput, p.Mode)
+	}
+	if !p.conflicts().IsValid() {
+		return fmt.Errorf("%w: invalid conflict strategy: %s (expected manual, jira_wins, local_wins, or newest_wins)",
+			ErrInvalidInput, p.Conflicts)
+	}
+	return nil
+}
+
+// CanPull returns true if the policy lets Jira changes be pulled at all.
+func (p SyncPolicy) CanPull() bool {
+	return p.direction() != SyncLocalOnly && p.Mode.CanPull()
+}
+
+// CanPush returns true if the policy lets local changes be pushed at all.
+func (p SyncPolicy) CanPush() bool {
+	return p.direction() == SyncBidirectional && p.Mode.CanPush()
+}
+
+// Decide returns what to do with the ticket whose sync state is state. A nil state means
+// the ticket was never synced, so it is pulled. A side the policy cannot sync (see
+// CanPull and CanPush) never wins: its changes wait, and the ticket is left as it is.
+func (p SyncPolicy) Decide(state *TicketState) SyncAction {
+	canPull, canPush := p.CanPull(), p.CanPush()
+	if !canPull && !canPush {
+		return SyncActionNone
+	}
+	if state == nil {
+		if canPull {
+			return SyncActionPull
+		}
+		return SyncActionNone
+	}
+
+	localChanged := state.LocalModified != nil && state.LocalModified.After(state.LastSynced)
+	remoteChanged := state.JiraUpdated.After(state.LastSynced)
+
+	var action SyncAction
+	switch {
+	case remoteChanged && p.direction() == SyncJiraToLocal:
+		action = SyncActionPull
+	case localChanged && remoteChanged:
+		action = p.resolve(*state.LocalModified, state.JiraUpdated)
+	case remoteChanged:
+		action = SyncActionPull
+	case localChanged:
+		action = SyncActionPush
+	default:
+		return SyncActionNone
+	}
+
+	if (action == SyncActionPull && !canPull) || (action == SyncActionPush && !canPush) {
+		return SyncActionNone
+	}
+	return action
+}
+
+// ShouldPull returns true if the Jira revision of the ticket should overwrite the local one.
+func (p SyncPolicy) ShouldPull(state *TicketState) bool {
+	return p.Decide(state) == SyncActionPull
+}
+
+// ShouldPush returns true if the local edits of the ticket should be pushed to Jira.
+func (p SyncPolicy) ShouldPush(state *TicketState) bool {
+	return p.Decide(state) == SyncActionPush
+}
+
+// resolve returns the action for a ticket changed locally at local and in Jira at
+// remote, according to the conflict strategy.
+func (p SyncPolicy) resolve(local, remote SyncTimestamp) SyncAction {
+	switch p.conflicts() {
+	case ConflictJiraWins:
+		return SyncActionPull
+	case ConflictLocalWins:
+		return SyncActionPush
+	case ConflictNewestWins:
+		if local.After(remote) {
+			return SyncActionPush
+		}
+		if remote.After(local) {
+			return SyncActionPull
+		}
+	}
+	return SyncActionConflict
+}
+
+// direction returns the policy's direction, SyncBidirectional if unset.
+func (p SyncPolicy) direction() SyncDirection {
+	if p.Direction == "" {
+		return SyncBidirectional
+	}
+	return p.Direction
+}
+
+// conflicts returns the policy's conflict strategy, ConflictManual if unset.
+func (p SyncPolicy) conflicts() ConflictStrategy {
+	if p.Conflicts == "" {
+		return ConflictManual
+	}
+	return p.Conflicts
+}
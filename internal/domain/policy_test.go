@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// policyState builds the sync state of a ticket last synced at a fixed time, changed
+// locally localOffset after it and in Jira remoteOffset after it (negative for before).
+func policyState(t *testing.T, localOffset, remoteOffset time.Duration, localModified bool) *TicketState {
+	t.Helper()
+	key, _ := NewTicketKey("JMD-1")
+	synced := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	state, err := NewTicketState("JMD", key, synced.Add(remoteOffset))
+	if err != nil {
+		t.Fatalf("NewTicketState() error = %v", err)
+	}
+	state.LastSynced = NewSyncTimestamp(synced)
+	if localModified {
+		state.MarkLocalModified(synced.Add(localOffset))
+	}
+	return state
+}
+
+func TestSyncPolicy_Decide(t *testing.T) {
+	situations := []struct {
+		name         string
+		local        bool
+		localOffset  time.Duration
+		remoteOffset time.Duration
+	}{
+		{name: "unchanged", remoteOffset: -time.Hour},
+		{name: "local edit before last sync", local: true, localOffset: -time.Minute, remoteOffset: -time.Hour},
+		{name: "local changed", local: true, localOffset: time.Minute, remoteOffset: -time.Hour},
+		{name: "remote changed", remoteOffset: time.Minute},
+		{name: "both, local newer", local: true, localOffset: 2 * time.Minute, remoteOffset: time.Minute},
+		{name: "both, remote newer", local: true, localOffset: time.Minute, remoteOffset: 2 * time.Minute},
+		{name: "both at once", local: true, localOffset: time.Minute, remoteOffset: time.Minute},
+	}
+
+	// want lists the action for each situation above, in order
+	tests := []struct {
+		direction SyncDirection
+		conflicts ConflictStrategy
+		want      []SyncAction
+	}{
+		{"", "", []SyncAction{SyncActionNone, SyncActionNone, SyncActionPush, SyncActionPull, SyncActionConflict, SyncActionConflict, SyncActionConflict}},
+		{SyncBidirectional, ConflictManual, []SyncAction{SyncActionNone, SyncActionNone, SyncActionPush, SyncActionPull, SyncActionConflict, SyncActionConflict, SyncActionConflict}},
+		{SyncBidirectional, ConflictJiraWins, []SyncAction{SyncActionNone, SyncActionNone, SyncActionPush, SyncActionPull, SyncActionPull, SyncActionPull, SyncActionPull}},
+		{SyncBidirectional, ConflictLocalWins, []SyncAction{SyncActionNone, SyncActionNone, SyncActionPush, SyncActionPull, SyncActionPush, SyncActionPush, SyncActionPush}},
+		{SyncBidirectional, ConflictNewestWins, []SyncAction{SyncActionNone, SyncActionNone, SyncActionPush, SyncActionPull, SyncActionPush, SyncActionPull, SyncActionConflict}},
+		{SyncJiraToLocal, ConflictManual, []SyncAction{SyncActionNone, SyncActionNone, SyncActionNone, SyncActionPull, SyncActionPull, SyncActionPull, SyncActionPull}},
+		{SyncJiraToLocal, ConflictJiraWins, []SyncAction{SyncActionNone, SyncActionNone, SyncActionNone, SyncActionPull, SyncActionPull, SyncActionPull, SyncActionPull}},
+		{SyncJiraToLocal, ConflictLocalWins, []SyncAction{SyncActionNone, SyncActionNone, SyncActionNone, SyncActionPull, SyncActionPull, SyncActionPull, SyncActionPull}},
+		{SyncJiraToLocal, ConflictNewestWins, []SyncAction{SyncActionNone, SyncActionNone, SyncActionNone, SyncActionPull, SyncActionPull, SyncActionPull, SyncActionPull}},
+		{SyncLocalOnly, ConflictManual, []SyncAction{SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone}},
+		{SyncLocalOnly, ConflictJiraWins, []SyncAction{SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone}},
+		{SyncLocalOnly, ConflictLocalWins, []SyncAction{SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone}},
+		{SyncLocalOnly, ConflictNewestWins, []SyncAction{SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone, SyncActionNone}},
+	}
+	for _, tt := range tests {
+		policy := SyncPolicy{Direction: tt.direction, Conflicts: tt.conflicts}
+		for i, situation := range situations {
+			state := policyState(t, situation.localOffset, situation.remoteOffset, situation.local)
+			got := policy.Decide(state)
+			if got != tt.want[i] {
+				t.Errorf("%s/%s, %s: Decide() = %s, want %s", tt.direction, tt.conflicts, situation.name, got, tt.want[i])
+			}
+			if policy.ShouldPull(state) != (got == SyncActionPull) || policy.ShouldPush(state) != (got == SyncActionPush) {
+				t.Errorf("%s/%s, %s: ShouldPull/ShouldPush disagree with Decide() = %s", tt.direction, tt.conflicts, situation.name, got)
+			}
+		}
+	}
+}
+
+func TestSyncPolicy_Decide_NeverSynced(t *testing.T) {
+	for _, direction := range []SyncDirection{"", SyncBidirectional, SyncJiraToLocal} {
+		if got := (SyncPolicy{Direction: direction}).Decide(nil); got != SyncActionPull {
+			t.Errorf("%s: Decide(nil) = %s, want pull", direction, got)
+		}
+	}
+	if got := (SyncPolicy{Direction: SyncLocalOnly}).Decide(nil); got != SyncActionNone {
+		t.Errorf("local_only: Decide(nil) = %s, want none", got)
+	}
+}
+
+func TestSyncPolicy_Decide_Mode(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy SyncPolicy
+		local  bool
+		remote time.Duration
+		want   SyncAction
+	}{
+		{"pull-only keeps local changes", SyncPolicy{Mode: SyncModePullOnly}, true, -time.Hour, SyncActionNone},
+		{"pull-only pulls", SyncPolicy{Mode: SyncModePullOnly}, false, time.Minute, SyncActionPull},
+		{"pull-only, local wins waits", SyncPolicy{Mode: SyncModePullOnly, Conflicts: ConflictLocalWins}, true, time.Minute, SyncActionNone},
+		{"pull-only, jira wins pulls", SyncPolicy{Mode: SyncModePullOnly, Conflicts: ConflictJiraWins}, true, time.Minute, SyncActionPull},
+		{"push-only pushes", SyncPolicy{Mode: SyncModePushOnly}, true, -time.Hour, SyncActionPush},
+		{"push-only keeps Jira changes", SyncPolicy{Mode: SyncModePushOnly}, false, time.Minute, SyncActionNone},
+		{"push-only, jira wins waits", SyncPolicy{Mode: SyncModePushOnly, Conflicts: ConflictJiraWins}, true, time.Minute, SyncActionNone},
+		{"push-only from Jira syncs nothing", SyncPolicy{Direction: SyncJiraToLocal, Mode: SyncModePushOnly}, false, time.Minute, SyncActionNone},
+	}
+	for _, tt := range tests {
+		state := policyState(t, 2*time.Minute, tt.remote, tt.local)
+		if got := tt.policy.Decide(state); got != tt.want {
+			t.Errorf("%s: Decide() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if got := (SyncPolicy{Mode: SyncModePushOnly}).Decide(nil); got != SyncActionNone {
+		t.Errorf("push-only: Decide(nil) = %s, want none", got)
+	}
+}
+
+func TestSyncPolicy_CanPullCanPush(t *testing.T) {
+	tests := []struct {
+		policy           SyncPolicy
+		canPull, canPush bool
+	}{
+		{SyncPolicy{}, true, true},
+		{SyncPolicy{Mode: SyncModePullOnly}, true, false},
+		{SyncPolicy{Mode: SyncModePushOnly}, false, true},
+		{SyncPolicy{Direction: SyncJiraToLocal}, true, false},
+		{SyncPolicy{Direction: SyncLocalOnly}, false, false},
+	}
+	for _, tt := range tests {
+		if tt.policy.CanPull() != tt.canPull || tt.policy.CanPush() != tt.canPush {
+			t.Errorf("%+v: CanPull, CanPush = %v, %v, want %v, %v",
+				tt.policy, tt.policy.CanPull(), tt.policy.CanPush(), tt.canPull, tt.canPush)
+		}
+	}
+}
+
+func TestSyncPolicy_DecideLeavesStateUnchanged(t *testing.T) {
+	state := policyState(t, time.Minute, time.Minute, true)
+	SyncPolicy{}.Decide(state)
+	if state.Status != SyncStatusLocalModified {
+		t.Errorf("Status = %s, want it unchanged", state.Status)
+	}
+}
+
+func TestNewSyncPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction SyncDirection
+		conflicts ConflictStrategy
+		want      SyncPolicy
+		wantErr   bool
+	}{
+		{name: "defaults", want: SyncPolicy{Direction: SyncBidirectional, Conflicts: ConflictManual}},
+		{name: "explicit", direction: SyncJiraToLocal, conflicts: ConflictNewestWins, want: SyncPolicy{Direction: SyncJiraToLocal, Conflicts: ConflictNewestWins}},
+		{name: "unknown direction", direction: "sideways", wantErr: true},
+		{name: "unknown strategy", conflicts: "coin_flip", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSyncPolicy(tt.direction, tt.conflicts)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("NewSyncPolicy() error = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSyncPolicy() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NewSyncPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConflictStrategy_IsValid(t *testing.T) {
+	for _, strategy := range []ConflictStrategy{ConflictManual, ConflictJiraWins, ConflictLocalWins, ConflictNewestWins} {
+		if !strategy.IsValid() {
+			t.Errorf("%s.IsValid() = false, want true", strategy)
+		}
+	}
+	for _, strategy := range []ConflictStrategy{"", "Manual", "coin_flip"} {
+		if strategy.IsValid() {
+			t.Errorf("%q.IsValid() = true, want false", strategy)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PriorityMap translates between the canonical priority names used in ticket files and
+// commands (e.g. P0 to P3) and the names of one project's priority scheme in Jira (e.g.
+// Blocker, Critical), so tickets of projects with different schemes read and filter
+// alike. It maps local names to Jira names. Pulls store the local name; pushes send the
+// Jira name. Names match case-insensitively, and names that are not mapped are used as
+// they are. The zero value uses Jira's names as is.
+type PriorityMap map[string]string
+
+// ToLocal returns the local name of the Jira priority jiraName.
+func (m PriorityMap) ToLocal(jiraName string) string {
+	for local, mapped := range m {
+		if strings.EqualFold(mapped, jiraName) {
+			return local
+		}
+	}
+	return jiraName
+}
+
+// ToJira returns the Jira name of the priority with the local name name.
+func (m PriorityMap) ToJira(name string) string {
+	if jiraName, ok := lookupName(m, strings.TrimSpace(name)); ok {
+		return jiraName
+	}
+	return name
+}
+
+// Validate checks that every name is set and that each Jira name is mapped from one
+// local name, so priorities can be mapped back from Jira.
+func (m PriorityMap) Validate() error {
+	var problems []string
+	jiraNames := make(map[string]string, len(m))
+	locals := make(map[string]string, len(m))
+	for _, local := range sortedNames(m) {
+		jiraName := m[local]
+		if strings.TrimSpace(local) == "" || strings.TrimSpace(jiraName) == "" {
+			problems = append(problems, fmt.Sprintf("%q: %q maps an empty priority name", local, jiraName))
+			continue
+		}
+		if other, ok := locals[strings.ToLower(local)]; ok {
+			problems = append(problems, fmt.Sprintf("%q and %q differ only in case", other, local))
+			continue
+		}
+		locals[strings.ToLower(local)] = local
+		if other, ok := jiraNames[strings.ToLower(jiraName)]; ok {
+			problems = append(problems, fmt.Sprintf("%q and %q both map to %q", other, local, jiraName))
+			continue
+		}
+		jiraNames[strings.ToLower(jiraName)] = local
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Unknown returns the local names, in order, whose Jira name is not a priority of
+// metadata, the project's cached metadata: those mapped to a priority that does not
+// exist or was misspelled. Metadata fetched with the map lists the local names of the
+// priorities it maps; metadata fetched before lists the Jira names. Metadata without
+// priorities knows none, so nothing is reported.
+func (m PriorityMap) Unknown(metadata *ProjectMetadata) []string {
+	if len(metadata.Priorities) == 0 {
+		return nil
+	}
+	known := func(name string) bool {
+		return slices.ContainsFunc(metadata.Priorities, func(priority string) bool {
+			return strings.EqualFold(priority, name)
+		})
+	}
+
+	var unknown []string
+	for _, local := range sortedNames(m) {
+		if !known(m[local]) && !known(local) {
+			unknown = append(unknown, local)
+		}
+	}
+	return unknown
+}
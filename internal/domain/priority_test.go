@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPriorityMap(t *testing.T) {
+	priorities := PriorityMap{"P0": "Blocker", "P1": "Critical"}
+
+	tests := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{name: "mapped to local", got: priorities.ToLocal("Blocker"), expect: "P0"},
+		{name: "mapped to local ignoring case", got: priorities.ToLocal("critical"), expect: "P1"},
+		{name: "unmapped to local", got: priorities.ToLocal("Trivial"), expect: "Trivial"},
+		{name: "local name to Jira", got: priorities.ToJira("p1"), expect: "Critical"},
+		{name: "unmapped to Jira", got: priorities.ToJira("Trivial"), expect: "Trivial"},
+		{name: "zero value", got: PriorityMap(nil).ToJira("P0"), expect: "P0"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expect {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.expect)
+		}
+	}
+}
+
+func TestPriorityMap_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		priorities PriorityMap
+		wantErr    bool
+	}{
+		{name: "zero value", priorities: nil},
+		{name: "valid", priorities: PriorityMap{"P0": "Highest", "P1": "High"}},
+		{name: "empty Jira name", priorities: PriorityMap{"P0": " "}, wantErr: true},
+		{name: "two local names for one Jira name", priorities: PriorityMap{"P0": "Highest", "P1": "highest"}, wantErr: true},
+		{name: "local names differing in case", priorities: PriorityMap{"P0": "Highest", "p0": "High"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := tt.priorities.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidInput", tt.name, err)
+		}
+	}
+}
+
+func TestPriorityMap_Unknown(t *testing.T) {
+	priorities := PriorityMap{"P0": "Blocker", "P1": "Critical", "P2": "Majr"}
+
+	// Metadata fetched before the map lists Jira's names, after it the local names
+	for _, metadata := range []*ProjectMetadata{
+		{Priorities: []string{"Blocker", "Critical", "Major"}},
+		{Priorities: []string{"P0", "P1", "Major"}},
+	} {
+		if got := priorities.Unknown(metadata); !slices.Equal(got, []string{"P2"}) {
+			t.Errorf("Unknown(%v) = %v, want [P2]", metadata.Priorities, got)
+		}
+	}
+	if got := priorities.Unknown(&ProjectMetadata{}); got != nil {
+		t.Errorf("Unknown() without cached priorities = %v, want none", got)
+	}
+}
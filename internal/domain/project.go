@@ -11,6 +11,11 @@ import (
 // projectKeyPattern defines the valid format for Jira project keys (2-10 uppercase letters/numbers)
 var projectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
 
+// IsValidProjectKey returns true if key has the format of a Jira project key.
+func IsValidProjectKey(key string) bool {
+	return projectKeyPattern.MatchString(key)
+}
+
 // Project represents a Jira project entity.
 // This is a core domain entity that represents a Jira project being synced.
 type Project struct {
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"sort"
+)
+
+// OperationPriority orders independent work in the push queue; lower values go first.
+type OperationPriority int
+
+const (
+	// PriorityHigh covers changes to a ticket's state that other people act on:
+	// creations, transitions, and field edits
+	PriorityHigh OperationPriority = iota
+
+	// PriorityNormal covers comments
+	PriorityNormal
+
+	// PriorityLow covers pulls, which only refresh local copies
+	PriorityLow
+)
+
+// Priority returns the priority class of an operation type.
+func (o OperationType) Priority() OperationPriority {
+	switch o {
+	case OpCreateTicket, OpPushStatus, OpPushField:
+		return PriorityHigh
+	case OpPostComment:
+		return PriorityNormal
+	default:
+		return PriorityLow
+	}
+}
+
+// QueueLane is the queued operations of one ticket, in the order they were queued.
+// Operations in a lane must be applied one after another, so a comment posted after
+// a transition lands after it in Jira too; different lanes are independent.
+type QueueLane struct {
+	// TicketKey is the ticket the lane's operations affect (zero for a ticket creation)
+	TicketKey TicketKey
+
+	// Operations are the lane's operations, oldest first
+	Operations []*PendingOperation
+}
+
+// Priority returns the priority class of the lane's next operation.
+func (l QueueLane) Priority() OperationPriority {
+	if len(l.Operations) == 0 {
+		return PriorityLow
+	}
+	return l.Operations[0].Operation.Priority()
+}
+
+// PlanQueue splits queued operations into lanes, one per ticket, for draining the
+// queue. Each ticket creation gets a lane of its own. Lanes are ordered by the priority
+// of their next operation, then by how long it has been queued.
+func PlanQueue(ops []*PendingOperation) []QueueLane {
+	sorted := make([]*PendingOperation, len(ops))
+	copy(sorted, ops)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var lanes []QueueLane
+	byTicket := make(map[TicketKey]int)
+	for _, op := range sorted {
+		if !op.TicketKey.IsZero() {
+			if i, ok := byTicket[op.TicketKey]; ok {
+				lanes[i].Operations = append(lanes[i].Operations, op)
+				continue
+			}
+			byTicket[op.TicketKey] = len(lanes)
+		}
+		lanes = append(lanes, QueueLane{TicketKey: op.TicketKey, Operations: []*PendingOperation{op}})
+	}
+
+	// Lanes were created in order of their oldest operation, so a stable sort by
+	// priority keeps lanes of the same priority oldest first
+	sort.SliceStable(lanes, func(i, j int) bool {
+		return lanes[i].Priority() < lanes[j].Priority()
+	})
+	return lanes
+}
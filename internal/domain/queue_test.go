@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestPlanQueue(t *testing.T) {
+	newOp := func(id int64, key string, operation OperationType) *PendingOperation {
+		var ticketKey TicketKey
+		if key != "" {
+			ticketKey, _ = NewTicketKey(key)
+		}
+		op, err := NewPendingOperation("JMD", ticketKey, operation, "{}")
+		if err != nil {
+			t.Fatalf("NewPendingOperation failed: %v", err)
+		}
+		op.ID = id
+		return op
+	}
+
+	ops := []*PendingOperation{
+		newOp(6, "JMD-2", OpPushStatus),
+		newOp(1, "JMD-1", OpPostComment),
+		newOp(2, "JMD-2", OpPullTicket),
+		newOp(3, "JMD-1", OpPushStatus),
+		newOp(4, "", OpCreateTicket),
+		newOp(5, "JMD-3", OpPostComment),
+		newOp(7, "", OpCreateTicket),
+	}
+
+	lanes := PlanQueue(ops)
+
+	// Creations first, then tickets by the priority of their oldest operation
+	want := []struct {
+		key string
+		ids []int64
+	}{
+		{"", []int64{4}},
+		{"", []int64{7}},
+		{"JMD-1", []int64{1, 3}},
+		{"JMD-3", []int64{5}},
+		{"JMD-2", []int64{2, 6}},
+	}
+
+	if len(lanes) != len(want) {
+		t.Fatalf("PlanQueue() returned %d lanes, want %d", len(lanes), len(want))
+	}
+	for i, lane := range lanes {
+		if lane.TicketKey.String() != want[i].key {
+			t.Errorf("lane %d ticket = %q, want %q", i, lane.TicketKey.String(), want[i].key)
+		}
+		var ids []int64
+		for _, op := range lane.Operations {
+			ids = append(ids, op.ID)
+		}
+		if len(ids) != len(want[i].ids) {
+			t.Errorf("lane %d operations = %v, want %v", i, ids, want[i].ids)
+			continue
+		}
+		for j := range ids {
+			if ids[j] != want[i].ids[j] {
+				t.Errorf("lane %d operations = %v, want %v", i, ids, want[i].ids)
+				break
+			}
+		}
+	}
+
+	if len(PlanQueue(nil)) != 0 {
+		t.Error("PlanQueue(nil) should return no lanes")
+	}
+}
+
+func TestOperationType_Priority(t *testing.T) {
+	tests := []struct {
+		operation OperationType
+		want      OperationPriority
+	}{
+		{OpCreateTicket, PriorityHigh},
+		{OpPushStatus, PriorityHigh},
+		{OpPushField, PriorityHigh},
+		{OpPostComment, PriorityNormal},
+		{OpPullTicket, PriorityLow},
+		{OpPullComments, PriorityLow},
+	}
+
+	for _, tt := range tests {
+		if got := tt.operation.Priority(); got != tt.want {
+			t.Errorf("%s.Priority() = %d, want %d", tt.operation, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// AgingWIPReport is the file name of the aging WIP report.
+const AgingWIPReport = "aging-wip.md"
+
+// BurndownReport returns the file name of a sprint's burndown report.
+func BurndownReport(sprint *Sprint) string {
+	return "burndown-" + slugify(sprint.Name) + ".md"
+}
+
+// BurndownPoint is how many of a sprint's tickets were left to do at one time.
+type BurndownPoint struct {
+	At time.Time `json:"at"`
+
+	// Remaining is how many tickets existed and were not in a done status
+	Remaining int `json:"remaining"`
+
+	// Ideal is how many would be left burning down evenly from the sprint's start to
+	// its end
+	Ideal float64 `json:"ideal"`
+}
+
+// Burndown returns the burndown of a sprint from the status changelogs of its tickets:
+// a point at the start of each day of the sprint, and a last one at its end, or now
+// while it is still running. A ticket counts from when it was created until it enters
+// one of the done statuses (matched case-insensitively), so tickets added during the
+// sprint show as scope added. Returns nil for sprints without a start or not started.
+func Burndown(sprint *Sprint, tickets []*Ticket, done []string, now time.Time) []BurndownPoint {
+	if sprint.Start.IsZero() || !sprint.Start.Before(now) {
+		return nil
+	}
+	last := now
+	if !sprint.End.IsZero() && sprint.End.Before(now) {
+		last = sprint.End
+	}
+
+	remaining := func(at time.Time) int {
+		count := 0
+		for _, ticket := range tickets {
+			if status := ticket.StatusAt(at); status != "" && !HasStatus(done, status) {
+				count++
+			}
+		}
+		return count
+	}
+	initial := float64(remaining(sprint.Start))
+	ideal := func(at time.Time) float64 {
+		if !sprint.End.After(sprint.Start) {
+			// Without a planned end there is no pace to compare with
+			return initial
+		}
+		left := initial * float64(sprint.End.Sub(at)) / float64(sprint.End.Sub(sprint.Start))
+		return max(left, 0)
+	}
+
+	var points []BurndownPoint
+	for at := sprint.Start; at.Before(last); at = at.Add(24 * time.Hour) {
+		points = append(points, BurndownPoint{At: at.UTC(), Remaining: remaining(at), Ideal: ideal(at)})
+	}
+	return append(points, BurndownPoint{At: last.UTC(), Remaining: remaining(last), Ideal: ideal(last)})
+}
+
+// AgingTicket is a ticket in progress and how long it has been in its current status.
+type AgingTicket struct {
+	Ticket *Ticket
+
+	// Since is when the ticket entered its current status
+	Since time.Time
+
+	// Age is the time since then
+	Age time.Duration
+}
+
+// AgingWIP returns the tickets in one of the wip statuses (matched case-insensitively),
+// longest in their current status first, from their status changelogs.
+func AgingWIP(tickets []*Ticket, wip []string, now time.Time) []AgingTicket {
+	var aging []AgingTicket
+	for _, ticket := range tickets {
+		if !HasStatus(wip, ticket.Status) {
+			continue
+		}
+		periods := ticket.StatusPeriods()
+		since := periods[len(periods)-1].Start
+		aging = append(aging, AgingTicket{Ticket: ticket, Since: since.UTC(), Age: max(now.Sub(since), 0)})
+	}
+	sort.SliceStable(aging, func(i, j int) bool {
+		if aging[i].Age != aging[j].Age {
+			return aging[i].Age > aging[j].Age
+		}
+		return aging[i].Ticket.Key.Compare(aging[j].Ticket.Key) < 0
+	})
+	return aging
+}
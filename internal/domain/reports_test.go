@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTicket_StatusAt(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	ticket, created := historyTicket(t, "Done",
+		StatusChange{From: "To Do", To: "In Progress", At: hoursAfter(base, 2)},
+		StatusChange{From: "In Progress", To: "Done", At: hoursAfter(base, 5)},
+	)
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{at: created.Add(-time.Minute), want: ""},
+		{at: created, want: "To Do"},
+		{at: hoursAfter(base, 2), want: "In Progress"},
+		{at: hoursAfter(base, 4), want: "In Progress"},
+		{at: hoursAfter(base, 9), want: "Done"},
+	}
+	for _, tt := range tests {
+		if got := ticket.StatusAt(tt.at); got != tt.want {
+			t.Errorf("StatusAt(%s) = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestBurndownReport(t *testing.T) {
+	if got := BurndownReport(&Sprint{Name: "JMD Sprint 12"}); got != "burndown-jmd-sprint-12.md" {
+		t.Errorf("BurndownReport() = %q, want burndown-jmd-sprint-12.md", got)
+	}
+}
+
+func TestBurndown(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.Add(time.Duration(n) * 24 * time.Hour) }
+	ticket := func(key string, created time.Time, changes ...StatusChange) *Ticket {
+		ticketKey, _ := NewTicketKey(key)
+		ticket := NewTicket(ticketKey, key, created, created)
+		ticket.Status = "To Do"
+		ticket.StatusHistory = changes
+		return ticket
+	}
+	tickets := []*Ticket{
+		ticket("JMD-1", day(-3), StatusChange{From: "To Do", To: "Done", At: day(1).Add(time.Hour)}),
+		ticket("JMD-2", day(-3), StatusChange{From: "To Do", To: "Closed", At: day(2).Add(time.Hour)}),
+		ticket("JMD-3", day(-3)),
+		ticket("JMD-4", day(1).Add(2*time.Hour)),
+	}
+	sprint := &Sprint{Name: "Sprint 1", Start: start, End: day(4)}
+	done := []string{"done", "closed"}
+
+	t.Run("running sprint", func(t *testing.T) {
+		got := Burndown(sprint, tickets, done, day(2).Add(6*time.Hour))
+		want := []BurndownPoint{
+			{At: day(0), Remaining: 3, Ideal: 3},
+			{At: day(1), Remaining: 3, Ideal: 2.25},
+			{At: day(2), Remaining: 3, Ideal: 1.5},
+			{At: day(2).Add(6 * time.Hour), Remaining: 2, Ideal: 1.3125},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Burndown() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("ended sprint", func(t *testing.T) {
+		got := Burndown(sprint, tickets, done, day(10))
+		if len(got) != 5 {
+			t.Fatalf("Burndown() = %+v, want 5 points", got)
+		}
+		if last := got[4]; last.At != day(4) || last.Remaining != 2 || last.Ideal != 0 {
+			t.Errorf("last point = %+v, want 2 remaining at the end", last)
+		}
+	})
+
+	t.Run("not started", func(t *testing.T) {
+		if got := Burndown(sprint, tickets, done, day(-1)); got != nil {
+			t.Errorf("Burndown() = %+v, want nil", got)
+		}
+		if got := Burndown(&Sprint{Name: "Unplanned"}, tickets, done, day(1)); got != nil {
+			t.Errorf("Burndown(no start) = %+v, want nil", got)
+		}
+	})
+}
+
+func TestAgingWIP(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	ticket := func(key, status string, changes ...StatusChange) *Ticket {
+		ticketKey, _ := NewTicketKey(key)
+		ticket := NewTicket(ticketKey, key, base, base)
+		ticket.Status = status
+		ticket.StatusHistory = changes
+		return ticket
+	}
+	tickets := []*Ticket{
+		ticket("JMD-1", "In Progress", StatusChange{From: "To Do", To: "In Progress", At: hoursAfter(base, 20)}),
+		ticket("JMD-2", "in review",
+			StatusChange{From: "To Do", To: "In Progress", At: hoursAfter(base, 1)},
+			StatusChange{From: "In Progress", To: "in review", At: hoursAfter(base, 2)},
+		),
+		ticket("JMD-3", "To Do"),
+		ticket("JMD-4", "In Progress", StatusChange{From: "To Do", To: "In Progress", At: hoursAfter(base, 2)}),
+	}
+
+	got := AgingWIP(tickets, []string{"In Progress", "In Review"}, hoursAfter(base, 26))
+	var keys []string
+	var ages []time.Duration
+	for _, aging := range got {
+		keys = append(keys, aging.Ticket.Key.String())
+		ages = append(ages, aging.Age)
+	}
+	if want := []string{"JMD-2", "JMD-4", "JMD-1"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("AgingWIP() keys = %v, want %v", keys, want)
+	}
+	if want := []time.Duration{24 * time.Hour, 24 * time.Hour, 6 * time.Hour}; !reflect.DeepEqual(ages, want) {
+		t.Errorf("AgingWIP() ages = %v, want %v", ages, want)
+	}
+	if got[0].Since != hoursAfter(base, 2) {
+		t.Errorf("AgingWIP()[0].Since = %s, want %s", got[0].Since, hoursAfter(base, 2))
+	}
+}
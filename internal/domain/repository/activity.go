@@ -0,0 +1,33 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// ActivityRepository defines the interface for the log of changes pulled and pushed
+// through jiramd, from which the daily digests are written.
+//
+// Implementations must:
+//   - Assign a unique, increasing ID to every recorded activity
+//   - Participate in transactions started by StateRepository.BeginTransaction
+//
+// Domain errors that methods should return:
+//   - ErrInvalidInput: when the activity is nil or fails domain.Activity.Validate
+type ActivityRepository interface {
+	// RecordActivity appends an activity to the log and sets its ID.
+	RecordActivity(ctx context.Context, activity *domain.Activity) error
+
+	// FindActivities retrieves the activities at or after from and before to, oldest
+	// first. Returns empty slice if there are none.
+	FindActivities(ctx context.Context, from, to time.Time) ([]*domain.Activity, error)
+
+	// PruneActivities removes activities before the given time.
+	// Returns the number of activities removed.
+	PruneActivities(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// AuthStatusRepository stores whether Jira accepts the configured credentials, so a
+// failure seen by the daemon or one CLI command is visible to all of them.
+//
+// Error contract: Methods return wrapped errors for storage failures.
+type AuthStatusRepository interface {
+	// GetAuthStatus returns the stored status (the zero value if none was saved).
+	GetAuthStatus(ctx context.Context) (domain.AuthStatus, error)
+
+	// SaveAuthStatus replaces the stored status.
+	SaveAuthStatus(ctx context.Context, status domain.AuthStatus) error
+}
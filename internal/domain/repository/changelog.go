@@ -0,0 +1,32 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// StatusHistoryRepository defines the interface for the cached status changelogs of
+// tickets, from which time in status and cycle times are computed offline.
+//
+// Implementations must:
+//   - Return changes oldest first
+//   - Participate in transactions started by StateRepository.BeginTransaction
+//
+// Domain errors that methods should return:
+//   - ErrEmptyKey: when the ticket key is empty
+type StatusHistoryRepository interface {
+	// ReplaceStatusHistory replaces the cached changelog of a ticket with history, as
+	// pulled from Jira.
+	ReplaceStatusHistory(ctx context.Context, ticketKey string, history []domain.StatusChange) error
+
+	// FindStatusHistory retrieves the cached changelog of a ticket.
+	// Returns empty slice if none is cached.
+	FindStatusHistory(ctx context.Context, ticketKey string) ([]domain.StatusChange, error)
+
+	// FindAllStatusHistory retrieves the cached changelogs of every ticket, by ticket key.
+	FindAllStatusHistory(ctx context.Context) (map[string][]domain.StatusChange, error)
+}
@@ -0,0 +1,31 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// CommentStateRepository defines the interface for the per-comment state recorded when a
+// ticket's comments were last pulled: each comment's ID, updated timestamp, and content
+// hash. Pulls compare it with Jira to skip fetching comments that cannot have changed
+// and to rewrite only the comment blocks that did.
+//
+// Implementations must:
+//   - Return states in the order they were recorded
+//   - Participate in transactions started by StateRepository.BeginTransaction
+//
+// Domain errors that methods should return:
+//   - ErrEmptyKey: when the ticket key is empty
+type CommentStateRepository interface {
+	// ReplaceCommentStates replaces the recorded comment states of a ticket with states,
+	// as pulled from Jira.
+	ReplaceCommentStates(ctx context.Context, ticketKey string, states []domain.CommentState) error
+
+	// FindCommentStates retrieves the recorded comment states of a ticket.
+	// Returns empty slice if none are recorded.
+	FindCommentStates(ctx context.Context, ticketKey string) ([]domain.CommentState, error)
+}
@@ -18,7 +18,7 @@
 //
 // # Repository Interfaces
 //
-// This package defines three primary repository interfaces:
+// This package defines nine primary repository interfaces, plus LockManager and UnitOfWork:
 //
 // ## JiraRepository
 //
@@ -43,8 +43,103 @@
 //   - Tracking sync timestamps for conflict detection
 //   - Recording local modifications
 //   - Managing project metadata
+//   - Keeping tombstones of deleted tickets until they are pruned
 //   - Transaction support for atomic updates
 //
+// ## PendingOperationRepository
+//
+// Abstracts the push queue of local changes waiting to be applied to Jira.
+// Implementations handle:
+//   - Ordering operations by the time they were queued
+//   - Tracking attempts and the last error for retries
+//   - Keeping completed operations, with their authors, until they are pruned
+//   - Listing a ticket's completed operations as its push history
+//   - Sharing transactions with StateRepository
+//
+// ## SearchRepository
+//
+// Abstracts full-text search over the local ticket cache. Implementations handle:
+//   - Indexing ticket summaries, descriptions, and comments as they are cached
+//   - Ranking matches by relevance
+//   - Excerpting the matching text
+//
+// ## SyncHistoryRepository
+//
+// Abstracts the history of sync runs. Implementations handle:
+//   - Recording a summary of every finished run
+//   - Listing recent runs
+//   - Pruning runs older than the retention period
+//
+// ## StatusHistoryRepository
+//
+// Abstracts the cached status changelogs of tickets. Implementations handle:
+//   - Replacing a ticket's changelog as it is pulled
+//   - Listing the changelog of one ticket, or of every ticket for reports
+//
+// ## UserRepository
+//
+// Abstracts the cached Jira user directory. Implementations handle:
+//   - Storing users and the searches that found them, with when they were fetched
+//   - Looking up a user by account ID, or the users matching a query
+//
+// ## ProjectMetadataRepository
+//
+// Abstracts the cached metadata of projects. Implementations handle:
+//   - Replacing a project's issue types, priorities, statuses, components, and
+//     transitions when they are refreshed from Jira
+//   - Reading them back, with when they were fetched, for offline validation
+//
+// ## FetchedTicketRepository
+//
+// Abstracts the cache of tickets as they were last fetched from Jira. Implementations handle:
+//   - Storing each ticket with its comments and status history, keyed by its Jira revision
+//   - Returning a ticket only when asked for the revision it was fetched at, so full syncs
+//     skip the detail fetches of tickets that did not change
+//
+// ## CommentStateRepository
+//
+// Records the ID, updated timestamp, and content hash of each comment as last pulled.
+// Implementations handle:
+//   - Replacing a ticket's states as a whole, in order
+//   - Removing a ticket's states when it leaves the cache
+//
+// ## LocalVersionRepository
+//
+// Records the copies of ticket files with local changes saved before a pull overwrote
+// them. Implementations handle:
+//   - Recording each copy in the transaction that overwrites its file
+//   - Listing a ticket's copies newest first
+//
+// ## InboxRepository
+//
+// Persists webhook events as they are received, before they are processed. Implementations
+// handle:
+//   - Deduplicating deliveries by event ID
+//   - Handing out pending events oldest first, including those left by a restart
+//   - Pruning processed events after the retention period
+//
+// ## ActivityRepository
+//
+// Logs the changes pulled from and pushed to Jira, for the daily digests. Implementations
+// handle:
+//   - Listing the activities of a time range oldest first
+//   - Pruning activities after the retention period
+//
+// ## LockManager
+//
+// Serializes work on individual tickets across goroutines and processes.
+// Implementations handle:
+//   - Blocking until a ticket's lock is free or the context is done
+//   - Expiring locks left behind by crashed processes
+//
+// ## UnitOfWork
+//
+// Applies the markdown file writes and state changes of one operation together.
+// Implementations handle:
+//   - Staging changes until Commit
+//   - Running state changes in a single transaction
+//   - Restoring changed files when the state changes fail
+//
 // ## Legacy Interfaces (ticket.go)
 //
 // The TicketRepository, CommentRepository, and ProjectRepository interfaces
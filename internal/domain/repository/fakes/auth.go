@@ -0,0 +1,50 @@
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// AuthStatusRepository is an in-memory repository.AuthStatusRepository.
+type AuthStatusRepository struct {
+	Behavior
+
+	mu     sync.Mutex
+	status domain.AuthStatus
+}
+
+// Verify that AuthStatusRepository implements the repository.AuthStatusRepository interface
+var _ repository.AuthStatusRepository = (*AuthStatusRepository)(nil)
+
+// NewAuthStatusRepository creates a fake auth status store holding status.
+func NewAuthStatusRepository(status domain.AuthStatus) *AuthStatusRepository {
+	return &AuthStatusRepository{status: status}
+}
+
+// GetAuthStatus returns the stored status.
+// Implements repository.AuthStatusRepository.GetAuthStatus.
+func (r *AuthStatusRepository) GetAuthStatus(ctx context.Context) (domain.AuthStatus, error) {
+	if err := r.call(ctx, "GetAuthStatus"); err != nil {
+		return domain.AuthStatus{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status, nil
+}
+
+// SaveAuthStatus replaces the stored status.
+// Implements repository.AuthStatusRepository.SaveAuthStatus.
+func (r *AuthStatusRepository) SaveAuthStatus(ctx context.Context, status domain.AuthStatus) error {
+	if err := r.call(ctx, "SaveAuthStatus"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package fakes provides in-memory implementations of the repository interfaces for
+// tests of the application layer and of extensions built on it.
+//
+// Every fake embeds a Behavior, which injects errors and latency into its methods and
+// counts calls, so failure handling can be tested without a real backend:
+//
+//	jira := fakes.NewJiraRepository(ticket)
+//	jira.FailNext("UpdateTicket", domain.ErrUnavailable)
+//	jira.SetLatency(50 * time.Millisecond)
+//
+// Methods are named as in the interface ("FetchTicket", "SaveTicketState", ...).
+// Fakes are safe for concurrent use and store copies of what they are given.
+package fakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// AnyMethod makes FailWith and FailNext apply to every method of a fake.
+const AnyMethod = ""
+
+// Behavior configures the failures and latency of a fake and records its calls.
+// The zero value has no failures or latency.
+type Behavior struct {
+	mu      sync.Mutex
+	errs    map[string]error
+	queued  map[string][]error
+	latency time.Duration
+	calls   map[string]int
+}
+
+// FailWith makes every call of method (AnyMethod for all) return err until it is
+// called again with a nil err.
+func (b *Behavior) FailWith(method string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.errs == nil {
+		b.errs = make(map[string]error)
+	}
+	if err == nil {
+		delete(b.errs, method)
+		return
+	}
+	b.errs[method] = err
+}
+
+// FailNext makes the next call of method (AnyMethod for the next call of any method)
+// return err. Repeated calls queue further failures for the calls after it.
+func (b *Behavior) FailNext(method string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.queued == nil {
+		b.queued = make(map[string][]error)
+	}
+	b.queued[method] = append(b.queued[method], err)
+}
+
+// SetLatency delays every call by d; calls whose context ends first return ctx.Err().
+func (b *Behavior) SetLatency(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.latency = d
+}
+
+// Calls returns how many times method has been called, including failed calls.
+func (b *Behavior) Calls(method string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.calls[method]
+}
+
+// Reset removes all failures and latency and clears the call counts.
+func (b *Behavior) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.errs = nil
+	b.queued = nil
+	b.latency = 0
+	b.calls = nil
+}
+
+// call records a call of method, waits out the latency, and returns the error injected
+// for it, if any. Fakes call it before doing anything else.
+func (b *Behavior) call(ctx context.Context, method string) error {
+	b.mu.Lock()
+	if b.calls == nil {
+		b.calls = make(map[string]int)
+	}
+	b.calls[method]++
+	latency := b.latency
+	err := b.injected(method)
+	b.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// injected pops the next queued failure for method, falling back to the persistent
+// ones. Callers hold mu.
+func (b *Behavior) injected(method string) error {
+	for _, key := range []string{method, AnyMethod} {
+		if queue := b.queued[key]; len(queue) > 0 {
+			b.queued[key] = queue[1:]
+			return queue[0]
+		}
+	}
+	if err, ok := b.errs[method]; ok {
+		return err
+	}
+	return b.errs[AnyMethod]
+}
+
+// cloneTicket returns a copy of ticket that shares no slices or maps with it.
+func cloneTicket(ticket *domain.Ticket) *domain.Ticket {
+	if ticket == nil {
+		return nil
+	}
+	c := *ticket
+	c.Labels = append(make([]string, 0, len(ticket.Labels)), ticket.Labels...)
+	c.CustomFields = make(map[string]domain.FieldValue, len(ticket.CustomFields))
+	for key, value := range ticket.CustomFields {
+		c.CustomFields[key] = value
+	}
+	return &c
+}
+
+// cloneComment returns a copy of comment.
+func cloneComment(comment *domain.Comment) *domain.Comment {
+	c := *comment
+	return &c
+}
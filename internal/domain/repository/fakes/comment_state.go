@@ -0,0 +1,51 @@
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// CommentStateRepository is an in-memory repository.CommentStateRepository, the state of
+// each comment as last pulled.
+type CommentStateRepository struct {
+	Behavior
+
+	mu     sync.Mutex
+	states map[string][]domain.CommentState
+}
+
+// Verify that CommentStateRepository implements the repository.CommentStateRepository interface
+var _ repository.CommentStateRepository = (*CommentStateRepository)(nil)
+
+// NewCommentStateRepository creates an empty fake comment state store.
+func NewCommentStateRepository() *CommentStateRepository {
+	return &CommentStateRepository{states: make(map[string][]domain.CommentState)}
+}
+
+// ReplaceCommentStates stores a copy of states as the ticket's comment states.
+// Implements repository.CommentStateRepository.ReplaceCommentStates.
+func (r *CommentStateRepository) ReplaceCommentStates(ctx context.Context, ticketKey string, states []domain.CommentState) error {
+	if err := r.call(ctx, "ReplaceCommentStates"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[ticketKey] = append([]domain.CommentState{}, states...)
+	return nil
+}
+
+// FindCommentStates returns a copy of the ticket's comment states.
+// Implements repository.CommentStateRepository.FindCommentStates.
+func (r *CommentStateRepository) FindCommentStates(ctx context.Context, ticketKey string) ([]domain.CommentState, error) {
+	if err := r.call(ctx, "FindCommentStates"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.CommentState{}, r.states[ticketKey]...), nil
+}
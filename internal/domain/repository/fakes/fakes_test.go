@@ -0,0 +1,156 @@
+package fakes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+func ticket(t *testing.T, key string, updated time.Time) *domain.Ticket {
+	t.Helper()
+
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		t.Fatalf("NewTicketKey(%q) error = %v", key, err)
+	}
+	return domain.NewTicket(ticketKey, "Ticket "+key, updated, updated)
+}
+
+func TestBehavior_InjectsErrors(t *testing.T) {
+	ctx := context.Background()
+	jira := NewJiraRepository(ticket(t, "JMD-1", time.Now()))
+
+	jira.FailNext("FetchTicket", domain.ErrUnavailable)
+	jira.FailNext("FetchTicket", domain.ErrRateLimited)
+	if _, err := jira.FetchTicket(ctx, "JMD-1"); !errors.Is(err, domain.ErrUnavailable) {
+		t.Errorf("first call error = %v, want ErrUnavailable", err)
+	}
+	if _, err := jira.FetchTicket(ctx, "JMD-1"); !errors.Is(err, domain.ErrRateLimited) {
+		t.Errorf("second call error = %v, want ErrRateLimited", err)
+	}
+	if _, err := jira.FetchTicket(ctx, "JMD-1"); err != nil {
+		t.Errorf("third call error = %v, want the canned ticket", err)
+	}
+
+	jira.FailWith(AnyMethod, domain.ErrUnauthorized)
+	if _, err := jira.FetchProjects(ctx); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("FetchProjects() error = %v, want ErrUnauthorized", err)
+	}
+	jira.FailWith(AnyMethod, nil)
+	if _, err := jira.FetchProjects(ctx); err != nil {
+		t.Errorf("FetchProjects() after clearing error = %v", err)
+	}
+
+	if got := jira.Calls("FetchTicket"); got != 3 {
+		t.Errorf("Calls(FetchTicket) = %d, want 3", got)
+	}
+}
+
+func TestBehavior_LatencyHonorsContext(t *testing.T) {
+	state := NewStateRepository()
+	state.SetLatency(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := state.GetDirtyTickets(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetDirtyTickets() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestJiraRepository_UpdateDetectsConflicts(t *testing.T) {
+	ctx := context.Background()
+	jira := NewJiraRepository(ticket(t, "JMD-1", time.Now().Add(-time.Hour)))
+
+	local, err := jira.FetchTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FetchTicket() error = %v", err)
+	}
+	local.Summary = "Edited"
+
+	updated, err := jira.UpdateTicket(ctx, local)
+	if err != nil {
+		t.Fatalf("UpdateTicket() error = %v", err)
+	}
+	if updated.Version() == local.Version() {
+		t.Error("UpdateTicket() kept the version")
+	}
+	if got := jira.Ticket("JMD-1").Summary; got != "Edited" {
+		t.Errorf("stored summary = %q", got)
+	}
+
+	if _, err := jira.UpdateTicket(ctx, local); !errors.Is(err, domain.ErrSyncConflict) {
+		t.Errorf("stale UpdateTicket() error = %v, want ErrSyncConflict", err)
+	}
+}
+
+func TestStateRepository_Rollback(t *testing.T) {
+	ctx := context.Background()
+	state := NewStateRepository(&repository.TicketSyncState{TicketKey: "JMD-1", ProjectKey: "JMD"})
+
+	txCtx, err := state.BeginTransaction(ctx)
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	if _, err := state.BeginTransaction(txCtx); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("nested BeginTransaction() error = %v, want ErrInvalidInput", err)
+	}
+
+	if err := state.SaveTicketState(txCtx, &repository.TicketSyncState{TicketKey: "JMD-2", IsDirty: true}); err != nil {
+		t.Fatalf("SaveTicketState() error = %v", err)
+	}
+	if err := state.DeleteTicketState(txCtx, "JMD-1"); err != nil {
+		t.Fatalf("DeleteTicketState() error = %v", err)
+	}
+	if err := state.Rollback(txCtx); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if _, err := state.GetTicketState(ctx, "JMD-1"); err != nil {
+		t.Errorf("JMD-1 after rollback: %v", err)
+	}
+	if _, err := state.GetTicketState(ctx, "JMD-2"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("JMD-2 after rollback: %v, want ErrNotFound", err)
+	}
+	if err := state.Commit(txCtx); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Commit() after rollback error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestLockManager_Serializes(t *testing.T) {
+	locks := NewLockManager()
+
+	unlock, err := locks.Lock(context.Background(), "JMD-1")
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := locks.Lock(ctx, "JMD-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second Lock() error = %v, want DeadlineExceeded while held", err)
+	}
+
+	acquired := make(chan error)
+	go func() {
+		unlockAgain, err := locks.Lock(context.Background(), "JMD-1")
+		if err == nil {
+			err = unlockAgain()
+		}
+		acquired <- err
+	}()
+
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock() error = %v", err)
+	}
+	if err := <-acquired; err != nil {
+		t.Errorf("Lock() after release error = %v", err)
+	}
+	if locks.Held("JMD-1") {
+		t.Error("lock still held after both holders released it")
+	}
+}
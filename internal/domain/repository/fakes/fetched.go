@@ -0,0 +1,84 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// FetchedTicketRepository is an in-memory repository.FetchedTicketRepository, the cache
+// of tickets as last fetched from Jira.
+type FetchedTicketRepository struct {
+	Behavior
+
+	mu      sync.Mutex
+	tickets map[string]*domain.Ticket
+}
+
+// Verify that FetchedTicketRepository implements the repository.FetchedTicketRepository interface
+var _ repository.FetchedTicketRepository = (*FetchedTicketRepository)(nil)
+
+// NewFetchedTicketRepository creates a fake fetched ticket cache holding the given
+// tickets, each at the revision of its Updated timestamp.
+func NewFetchedTicketRepository(tickets ...*domain.Ticket) *FetchedTicketRepository {
+	r := &FetchedTicketRepository{tickets: make(map[string]*domain.Ticket)}
+	for _, ticket := range tickets {
+		r.tickets[ticket.Key.String()] = cloneFetchedTicket(ticket)
+	}
+	return r
+}
+
+// SaveFetchedTicket stores a copy of the ticket with its comments, replacing any with
+// the same key.
+// Implements repository.FetchedTicketRepository.SaveFetchedTicket.
+func (r *FetchedTicketRepository) SaveFetchedTicket(ctx context.Context, ticket *domain.Ticket) error {
+	if err := r.call(ctx, "SaveFetchedTicket"); err != nil {
+		return err
+	}
+	if ticket == nil || ticket.Updated.IsZero() {
+		return fmt.Errorf("%w: fetched ticket needs an updated timestamp", domain.ErrInvalidInput)
+	}
+	if ticket.Key.String() == "" {
+		return fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tickets[ticket.Key.String()] = cloneFetchedTicket(ticket)
+	return nil
+}
+
+// FindFetchedTicket returns a copy of the cached ticket if it was cached at updated.
+// Implements repository.FetchedTicketRepository.FindFetchedTicket.
+func (r *FetchedTicketRepository) FindFetchedTicket(ctx context.Context, key string, updated time.Time) (*domain.Ticket, error) {
+	if err := r.call(ctx, "FindFetchedTicket"); err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ticket, ok := r.tickets[key]
+	if !ok || !ticket.Updated.Equal(updated) {
+		return nil, fmt.Errorf("%w: ticket %s is not cached at %s", domain.ErrNotFound, key, updated)
+	}
+	return cloneFetchedTicket(ticket), nil
+}
+
+// cloneFetchedTicket returns a copy of ticket with copies of its comments.
+func cloneFetchedTicket(ticket *domain.Ticket) *domain.Ticket {
+	c := cloneTicket(ticket)
+	if ticket.Comments != nil {
+		c.Comments = make([]*domain.Comment, len(ticket.Comments))
+		for i, comment := range ticket.Comments {
+			c.Comments[i] = cloneComment(comment)
+		}
+	}
+	return c
+}
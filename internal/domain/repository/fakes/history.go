@@ -0,0 +1,100 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// SyncHistoryRepository is an in-memory repository.SyncHistoryRepository.
+type SyncHistoryRepository struct {
+	Behavior
+
+	mu      sync.Mutex
+	entries []*repository.SyncHistoryEntry
+	nextID  int64
+}
+
+// Verify that SyncHistoryRepository implements the repository.SyncHistoryRepository interface
+var _ repository.SyncHistoryRepository = (*SyncHistoryRepository)(nil)
+
+// NewSyncHistoryRepository creates a fake sync history with no entries.
+func NewSyncHistoryRepository() *SyncHistoryRepository {
+	return &SyncHistoryRepository{}
+}
+
+// Record stores a copy of the entry and sets its ID.
+// Implements repository.SyncHistoryRepository.Record.
+func (r *SyncHistoryRepository) Record(ctx context.Context, entry *repository.SyncHistoryEntry) error {
+	if err := r.call(ctx, "Record"); err != nil {
+		return err
+	}
+	if entry == nil {
+		return fmt.Errorf("%w: history entry cannot be nil", domain.ErrInvalidInput)
+	}
+	if entry.ProjectKey == "" {
+		return fmt.Errorf("%w: history entry needs a project key", domain.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	entry.ID = r.nextID
+	e := *entry
+	r.entries = append(r.entries, &e)
+	return nil
+}
+
+// Recent returns copies of at most limit entries, most recently finished first.
+// Implements repository.SyncHistoryRepository.Recent.
+func (r *SyncHistoryRepository) Recent(ctx context.Context, limit int) ([]*repository.SyncHistoryEntry, error) {
+	if err := r.call(ctx, "Recent"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]*repository.SyncHistoryEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		e := *entry
+		entries = append(entries, &e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].FinishedAt.Equal(entries[j].FinishedAt) {
+			return entries[i].FinishedAt.After(entries[j].FinishedAt)
+		}
+		return entries[i].ID > entries[j].ID
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// PruneBefore removes the entries of runs that finished before the given time.
+// Implements repository.SyncHistoryRepository.PruneBefore.
+func (r *SyncHistoryRepository) PruneBefore(ctx context.Context, before time.Time) (int, error) {
+	if err := r.call(ctx, "PruneBefore"); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.entries[:0]
+	for _, entry := range r.entries {
+		if !entry.FinishedAt.Before(before) {
+			kept = append(kept, entry)
+		}
+	}
+	pruned := len(r.entries) - len(kept)
+	r.entries = kept
+	return pruned, nil
+}
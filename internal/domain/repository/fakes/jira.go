@@ -0,0 +1,365 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// jqlProject extracts the project of a "project = KEY" clause.
+var jqlProject = regexp.MustCompile(`(?i)\bproject\s*=\s*"?([A-Z][A-Z0-9]*)"?`)
+
+// JiraRepository is an in-memory repository.JiraRepository holding canned tickets,
+// comments, projects, and users.
+type JiraRepository struct {
+	Behavior
+
+	mu       sync.Mutex
+	tickets  map[string]*domain.Ticket
+	comments map[string][]*domain.Comment
+	projects map[string]*domain.Project
+	users    map[string]*domain.User
+	nextID   int
+}
+
+// Verify that JiraRepository implements the repository.JiraRepository interface
+var _ repository.JiraRepository = (*JiraRepository)(nil)
+
+// NewJiraRepository creates a fake Jira holding the given tickets.
+func NewJiraRepository(tickets ...*domain.Ticket) *JiraRepository {
+	r := &JiraRepository{
+		tickets:  make(map[string]*domain.Ticket),
+		comments: make(map[string][]*domain.Comment),
+		projects: make(map[string]*domain.Project),
+		users:    make(map[string]*domain.User),
+	}
+	for _, ticket := range tickets {
+		r.AddTicket(ticket)
+	}
+	return r
+}
+
+// AddTicket stores a copy of ticket, replacing any with the same key.
+func (r *JiraRepository) AddTicket(ticket *domain.Ticket) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tickets[ticket.Key.String()] = cloneTicket(ticket)
+}
+
+// AddProject stores a copy of project, replacing any with the same key.
+func (r *JiraRepository) AddProject(project *domain.Project) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := *project
+	r.projects[project.Key] = &p
+}
+
+// AddUser stores a copy of user, replacing any with the same account ID.
+func (r *JiraRepository) AddUser(user *domain.User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := *user
+	r.users[user.AccountID] = &u
+}
+
+// Ticket returns a copy of the stored ticket with the given key, or nil if there is none.
+func (r *JiraRepository) Ticket(key string) *domain.Ticket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return cloneTicket(r.tickets[key])
+}
+
+// FetchTicket returns a copy of the stored ticket.
+// Implements repository.JiraRepository.FetchTicket.
+func (r *JiraRepository) FetchTicket(ctx context.Context, key string) (*domain.Ticket, error) {
+	if err := r.call(ctx, "FetchTicket"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ticket, ok := r.tickets[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: ticket %s", domain.ErrNotFound, key)
+	}
+	return cloneTicket(ticket), nil
+}
+
+// FetchTicketsModifiedSince returns the project's tickets updated at or after since,
+// least recently updated first.
+// Implements repository.JiraRepository.FetchTicketsModifiedSince.
+func (r *JiraRepository) FetchTicketsModifiedSince(ctx context.Context, projectKey string, since time.Time) ([]*domain.Ticket, error) {
+	if err := r.call(ctx, "FetchTicketsModifiedSince"); err != nil {
+		return nil, err
+	}
+
+	tickets := r.projectTickets(projectKey, func(t *domain.Ticket) bool { return !t.Updated.Before(since) })
+	sort.SliceStable(tickets, func(i, j int) bool { return tickets[i].Updated.Before(tickets[j].Updated) })
+	return tickets, nil
+}
+
+// FetchAllTickets returns the project's tickets, most recently updated first.
+// Implements repository.JiraRepository.FetchAllTickets.
+func (r *JiraRepository) FetchAllTickets(ctx context.Context, projectKey string) ([]*domain.Ticket, error) {
+	if err := r.call(ctx, "FetchAllTickets"); err != nil {
+		return nil, err
+	}
+
+	tickets := r.projectTickets(projectKey, nil)
+	sort.SliceStable(tickets, func(i, j int) bool { return tickets[i].Updated.After(tickets[j].Updated) })
+	return tickets, nil
+}
+
+// ForEachTicket calls fn with each ticket of the project named in a "project = KEY"
+// clause of jql, or with every ticket if there is none, ordered by key. The rest of
+// the query is not evaluated.
+// Implements repository.JiraRepository.ForEachTicket.
+func (r *JiraRepository) ForEachTicket(ctx context.Context, jql string, fn func(ticket *domain.Ticket) error) error {
+	if err := r.call(ctx, "ForEachTicket"); err != nil {
+		return err
+	}
+
+	projectKey := ""
+	if m := jqlProject.FindStringSubmatch(jql); m != nil {
+		projectKey = m[1]
+	}
+	for _, ticket := range r.projectTickets(projectKey, nil) {
+		if err := fn(ticket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectTickets returns copies of the tickets in the project (all projects when
+// projectKey is empty) that match keep (all when nil), ordered by key.
+func (r *JiraRepository) projectTickets(projectKey string, keep func(t *domain.Ticket) bool) []*domain.Ticket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tickets := make([]*domain.Ticket, 0)
+	for _, ticket := range r.tickets {
+		if projectKey != "" && ticket.Key.ProjectKey() != projectKey {
+			continue
+		}
+		if keep == nil || keep(ticket) {
+			tickets = append(tickets, cloneTicket(ticket))
+		}
+	}
+	sortTickets(tickets)
+	return tickets
+}
+
+// UpdateTicket stores the ticket with a later Updated time, if the stored ticket is
+// still at the ticket's version.
+// Implements repository.JiraRepository.UpdateTicket.
+func (r *JiraRepository) UpdateTicket(ctx context.Context, ticket *domain.Ticket) (*domain.Ticket, error) {
+	if err := r.call(ctx, "UpdateTicket"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := ticket.Key.String()
+	stored, ok := r.tickets[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: ticket %s", domain.ErrNotFound, key)
+	}
+	if stored.Version() != ticket.Version() {
+		return nil, fmt.Errorf("%w: %w: %s was updated at %s, after the local copy (%s)",
+			domain.ErrConflict, domain.ErrSyncConflict, key, stored.Version(), ticket.Version())
+	}
+
+	updated := cloneTicket(ticket)
+	updated.Updated = later(stored.Updated)
+	r.tickets[key] = updated
+	return cloneTicket(updated), nil
+}
+
+// FetchComments returns copies of the ticket's comments in the order they were added.
+// Implements repository.JiraRepository.FetchComments.
+func (r *JiraRepository) FetchComments(ctx context.Context, ticketKey string) ([]*domain.Comment, error) {
+	if err := r.call(ctx, "FetchComments"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tickets[ticketKey]; !ok {
+		return nil, fmt.Errorf("%w: ticket %s", domain.ErrNotFound, ticketKey)
+	}
+	comments := make([]*domain.Comment, 0, len(r.comments[ticketKey]))
+	for _, comment := range r.comments[ticketKey] {
+		comments = append(comments, cloneComment(comment))
+	}
+	return comments, nil
+}
+
+// AddComment stores a copy of the comment with a new ID and the current time.
+// Implements repository.JiraRepository.AddComment.
+func (r *JiraRepository) AddComment(ctx context.Context, ticketKey string, comment *domain.Comment) (*domain.Comment, error) {
+	if err := r.call(ctx, "AddComment"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tickets[ticketKey]; !ok {
+		return nil, fmt.Errorf("%w: ticket %s", domain.ErrNotFound, ticketKey)
+	}
+
+	r.nextID++
+	added := cloneComment(comment)
+	added.ID = strconv.Itoa(10000 + r.nextID)
+	added.Created = time.Now().UTC()
+	added.Updated = added.Created
+	r.comments[ticketKey] = append(r.comments[ticketKey], added)
+	return cloneComment(added), nil
+}
+
+// FetchProject returns a copy of the stored project.
+// Implements repository.JiraRepository.FetchProject.
+func (r *JiraRepository) FetchProject(ctx context.Context, projectKey string) (*domain.Project, error) {
+	if err := r.call(ctx, "FetchProject"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	project, ok := r.projects[projectKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: project %s", domain.ErrNotFound, projectKey)
+	}
+	p := *project
+	return &p, nil
+}
+
+// FetchProjects returns copies of the stored projects, ordered by key.
+// Implements repository.JiraRepository.FetchProjects.
+func (r *JiraRepository) FetchProjects(ctx context.Context) ([]*domain.Project, error) {
+	if err := r.call(ctx, "FetchProjects"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	projects := make([]*domain.Project, 0, len(r.projects))
+	for _, project := range r.projects {
+		p := *project
+		projects = append(projects, &p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Key < projects[j].Key })
+	return projects, nil
+}
+
+// SearchUsers returns copies of the stored users matching query, ordered by display name.
+// Implements repository.JiraRepository.SearchUsers.
+func (r *JiraRepository) SearchUsers(ctx context.Context, query string) ([]*domain.User, error) {
+	if err := r.call(ctx, "SearchUsers"); err != nil {
+		return nil, err
+	}
+	if domain.NormalizeUserQuery(query) == "" {
+		return nil, fmt.Errorf("%w: user search query is empty", domain.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := make([]*domain.User, 0)
+	for _, user := range r.users {
+		if user.Matches(query) {
+			u := *user
+			users = append(users, &u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].DisplayName < users[j].DisplayName })
+	return users, nil
+}
+
+// SearchAssignableUsers returns copies of the stored active users matching query, who
+// are assignable in every project, ordered by display name.
+// Implements repository.JiraRepository.SearchAssignableUsers.
+func (r *JiraRepository) SearchAssignableUsers(ctx context.Context, projectKey, query string) ([]*domain.User, error) {
+	if err := r.call(ctx, "SearchAssignableUsers"); err != nil {
+		return nil, err
+	}
+	if domain.NormalizeUserQuery(query) == "" {
+		return nil, fmt.Errorf("%w: user search query is empty", domain.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := make([]*domain.User, 0)
+	for _, user := range r.users {
+		if user.Active && user.Matches(query) {
+			u := *user
+			users = append(users, &u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].DisplayName < users[j].DisplayName })
+	return users, nil
+}
+
+// GetUser returns a copy of the stored user.
+// Implements repository.JiraRepository.GetUser.
+func (r *JiraRepository) GetUser(ctx context.Context, accountID string) (*domain.User, error) {
+	if err := r.call(ctx, "GetUser"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[accountID]
+	if !ok {
+		return nil, fmt.Errorf("%w: user %s", domain.ErrNotFound, accountID)
+	}
+	u := *user
+	return &u, nil
+}
+
+// sortTickets orders tickets by project and issue number.
+func sortTickets(tickets []*domain.Ticket) {
+	sort.Slice(tickets, func(i, j int) bool {
+		pi, pj := tickets[i].Key.ProjectKey(), tickets[j].Key.ProjectKey()
+		if pi != pj {
+			return pi < pj
+		}
+		return issueNumber(tickets[i].Key.String()) < issueNumber(tickets[j].Key.String())
+	})
+}
+
+// issueNumber returns the number of an issue key, e.g. 42 for "JMD-42".
+func issueNumber(key string) int {
+	for i := len(key) - 1; i >= 0; i-- {
+		if key[i] == '-' {
+			n, _ := strconv.Atoi(key[i+1:])
+			return n
+		}
+	}
+	return 0
+}
+
+// later returns the current time, or a millisecond after t if that is not later, so
+// every update changes a ticket's version.
+func later(t time.Time) time.Time {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if !now.After(t) {
+		return t.Add(time.Millisecond)
+	}
+	return now
+}
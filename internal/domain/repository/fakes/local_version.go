@@ -0,0 +1,67 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// LocalVersionRepository is an in-memory repository.LocalVersionRepository, the record of
+// the local versions saved before pulls overwrote them.
+type LocalVersionRepository struct {
+	Behavior
+
+	mu       sync.Mutex
+	versions []*domain.LocalVersion
+}
+
+// Verify that LocalVersionRepository implements the repository.LocalVersionRepository interface
+var _ repository.LocalVersionRepository = (*LocalVersionRepository)(nil)
+
+// NewLocalVersionRepository creates an empty fake local version record.
+func NewLocalVersionRepository() *LocalVersionRepository {
+	return &LocalVersionRepository{}
+}
+
+// SaveLocalVersion stores a copy of version.
+// Implements repository.LocalVersionRepository.SaveLocalVersion.
+func (r *LocalVersionRepository) SaveLocalVersion(ctx context.Context, version *domain.LocalVersion) error {
+	if err := r.call(ctx, "SaveLocalVersion"); err != nil {
+		return err
+	}
+	if version == nil {
+		return fmt.Errorf("%w: local version cannot be nil", domain.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := *version
+	r.versions = append(r.versions, &c)
+	return nil
+}
+
+// FindLocalVersions returns copies of the ticket's local versions, newest first.
+// Implements repository.LocalVersionRepository.FindLocalVersions.
+func (r *LocalVersionRepository) FindLocalVersions(ctx context.Context, ticketKey string) ([]*domain.LocalVersion, error) {
+	if err := r.call(ctx, "FindLocalVersions"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := []*domain.LocalVersion{}
+	for _, version := range r.versions {
+		if version.TicketKey.String() == ticketKey {
+			c := *version
+			versions = append(versions, &c)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].SavedAt.After(versions[j].SavedAt)
+	})
+	return versions, nil
+}
@@ -0,0 +1,73 @@
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// LockManager is an in-process repository.LockManager.
+type LockManager struct {
+	Behavior
+
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// Verify that LockManager implements the repository.LockManager interface
+var _ repository.LockManager = (*LockManager)(nil)
+
+// NewLockManager creates a fake lock manager with no locks held.
+func NewLockManager() *LockManager {
+	return &LockManager{locks: make(map[string]chan struct{})}
+}
+
+// Held reports whether the lock on ticketKey is held.
+func (m *LockManager) Held(ticketKey string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.locks[ticketKey]
+	return ok
+}
+
+// Lock blocks until it holds the lock on ticketKey or ctx is done.
+// Implements repository.LockManager.Lock.
+func (m *LockManager) Lock(ctx context.Context, ticketKey string) (func() error, error) {
+	if err := m.call(ctx, "Lock"); err != nil {
+		return nil, err
+	}
+
+	for {
+		m.mu.Lock()
+		released, held := m.locks[ticketKey]
+		if !held {
+			done := make(chan struct{})
+			m.locks[ticketKey] = done
+			m.mu.Unlock()
+			return m.unlocker(ticketKey, done), nil
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// unlocker returns the function releasing the lock on ticketKey; calls after the
+// first do nothing.
+func (m *LockManager) unlocker(ticketKey string, done chan struct{}) func() error {
+	var once sync.Once
+	return func() error {
+		once.Do(func() {
+			m.mu.Lock()
+			delete(m.locks, ticketKey)
+			m.mu.Unlock()
+			close(done)
+		})
+		return nil
+	}
+}
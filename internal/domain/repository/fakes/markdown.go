@@ -0,0 +1,168 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// MarkdownRepository is an in-memory repository.MarkdownRepository that keeps tickets
+// and comments by file path instead of writing files.
+type MarkdownRepository struct {
+	Behavior
+
+	mu       sync.Mutex
+	tickets  map[string]*domain.Ticket
+	comments map[string][]*domain.Comment
+	indexes  map[string][]*domain.Ticket
+}
+
+// Verify that MarkdownRepository implements the repository.MarkdownRepository interface
+var _ repository.MarkdownRepository = (*MarkdownRepository)(nil)
+
+// NewMarkdownRepository creates a fake markdown store with no files.
+func NewMarkdownRepository() *MarkdownRepository {
+	return &MarkdownRepository{
+		tickets:  make(map[string]*domain.Ticket),
+		comments: make(map[string][]*domain.Comment),
+		indexes:  make(map[string][]*domain.Ticket),
+	}
+}
+
+// Index returns the tickets of the last index generated at indexPath (none if it was
+// never generated).
+func (r *MarkdownRepository) Index(indexPath string) []*domain.Ticket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tickets := make([]*domain.Ticket, 0, len(r.indexes[indexPath]))
+	for _, ticket := range r.indexes[indexPath] {
+		tickets = append(tickets, cloneTicket(ticket))
+	}
+	return tickets
+}
+
+// ReadTicket returns a copy of the ticket written to filePath.
+// Implements repository.MarkdownRepository.ReadTicket.
+func (r *MarkdownRepository) ReadTicket(ctx context.Context, filePath string) (*domain.Ticket, error) {
+	if err := r.call(ctx, "ReadTicket"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ticket, ok := r.tickets[filePath]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, filePath)
+	}
+	return cloneTicket(ticket), nil
+}
+
+// WriteTicket stores a copy of the ticket at filePath.
+// Implements repository.MarkdownRepository.WriteTicket.
+func (r *MarkdownRepository) WriteTicket(ctx context.Context, filePath string, ticket *domain.Ticket) error {
+	if err := r.call(ctx, "WriteTicket"); err != nil {
+		return err
+	}
+	if ticket == nil {
+		return fmt.Errorf("%w: ticket is nil", domain.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tickets[filePath] = cloneTicket(ticket)
+	return nil
+}
+
+// ReadComments returns copies of the comments written to filePath.
+// Implements repository.MarkdownRepository.ReadComments.
+func (r *MarkdownRepository) ReadComments(ctx context.Context, filePath string) ([]*domain.Comment, error) {
+	if err := r.call(ctx, "ReadComments"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tickets[filePath]; !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, filePath)
+	}
+	comments := make([]*domain.Comment, 0, len(r.comments[filePath]))
+	for _, comment := range r.comments[filePath] {
+		comments = append(comments, cloneComment(comment))
+	}
+	return comments, nil
+}
+
+// WriteComments replaces the comments of the ticket at filePath.
+// Implements repository.MarkdownRepository.WriteComments.
+func (r *MarkdownRepository) WriteComments(ctx context.Context, filePath string, comments []*domain.Comment) error {
+	if err := r.call(ctx, "WriteComments"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tickets[filePath]; !ok {
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, filePath)
+	}
+	stored := make([]*domain.Comment, 0, len(comments))
+	for _, comment := range comments {
+		stored = append(stored, cloneComment(comment))
+	}
+	r.comments[filePath] = stored
+	return nil
+}
+
+// ListTicketFiles returns the sorted paths of the tickets written in directory or below it.
+// Implements repository.MarkdownRepository.ListTicketFiles.
+func (r *MarkdownRepository) ListTicketFiles(ctx context.Context, directory string) ([]string, error) {
+	if err := r.call(ctx, "ListTicketFiles"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prefix := path.Clean(directory) + "/"
+	files := make([]string, 0)
+	for filePath := range r.tickets {
+		if strings.HasPrefix(path.Clean(filePath), prefix) {
+			files = append(files, filePath)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// GenerateIndex records copies of the tickets as the index at indexPath (see Index).
+// Implements repository.MarkdownRepository.GenerateIndex.
+func (r *MarkdownRepository) GenerateIndex(ctx context.Context, indexPath string, tickets []*domain.Ticket) error {
+	if err := r.call(ctx, "GenerateIndex"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := make([]*domain.Ticket, 0, len(tickets))
+	for _, ticket := range tickets {
+		index = append(index, cloneTicket(ticket))
+	}
+	r.indexes[indexPath] = index
+	return nil
+}
+
+// ValidateTemplate accepts every template; inject a failure to test invalid ones.
+// Implements repository.MarkdownRepository.ValidateTemplate.
+func (r *MarkdownRepository) ValidateTemplate(ctx context.Context, templatePath string) error {
+	return r.call(ctx, "ValidateTemplate")
+}
@@ -0,0 +1,181 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// PendingOperationRepository is an in-memory repository.PendingOperationRepository, the
+// push queue.
+type PendingOperationRepository struct {
+	Behavior
+
+	mu     sync.Mutex
+	ops    map[int64]*domain.PendingOperation
+	nextID int64
+}
+
+// Verify that PendingOperationRepository implements the repository.PendingOperationRepository interface
+var _ repository.PendingOperationRepository = (*PendingOperationRepository)(nil)
+
+// NewPendingOperationRepository creates a fake push queue holding copies of ops, which
+// are given IDs in order.
+func NewPendingOperationRepository(ops ...*domain.PendingOperation) *PendingOperationRepository {
+	r := &PendingOperationRepository{ops: make(map[int64]*domain.PendingOperation)}
+	for _, op := range ops {
+		r.nextID++
+		op.ID = r.nextID
+		c := *op
+		r.ops[op.ID] = &c
+	}
+	return r
+}
+
+// Operation returns a copy of the operation with the given ID, completed or not, or nil
+// if there is none.
+func (r *PendingOperationRepository) Operation(id int64) *domain.PendingOperation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	op, ok := r.ops[id]
+	if !ok {
+		return nil
+	}
+	c := *op
+	return &c
+}
+
+// Enqueue stores a copy of op under the next ID and sets op's ID.
+// Implements repository.PendingOperationRepository.Enqueue.
+func (r *PendingOperationRepository) Enqueue(ctx context.Context, op *domain.PendingOperation) error {
+	if err := r.call(ctx, "Enqueue"); err != nil {
+		return err
+	}
+	if op == nil || op.ProjectKey == "" {
+		return fmt.Errorf("%w: operation needs a project key", domain.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	op.ID = r.nextID
+	c := *op
+	r.ops[op.ID] = &c
+	return nil
+}
+
+// FindByProject returns copies of the project's queued operations, oldest first.
+// Implements repository.PendingOperationRepository.FindByProject.
+func (r *PendingOperationRepository) FindByProject(ctx context.Context, projectKey string) ([]*domain.PendingOperation, error) {
+	if err := r.call(ctx, "FindByProject"); err != nil {
+		return nil, err
+	}
+	return r.find(func(op *domain.PendingOperation) bool {
+		return op.ProjectKey == projectKey && !op.IsCompleted()
+	}), nil
+}
+
+// FindByTicketKey returns copies of the ticket's queued operations, oldest first.
+// Implements repository.PendingOperationRepository.FindByTicketKey.
+func (r *PendingOperationRepository) FindByTicketKey(ctx context.Context, ticketKey string) ([]*domain.PendingOperation, error) {
+	if err := r.call(ctx, "FindByTicketKey"); err != nil {
+		return nil, err
+	}
+	return r.find(func(op *domain.PendingOperation) bool {
+		return op.TicketKey.String() == ticketKey && !op.IsCompleted()
+	}), nil
+}
+
+// FindCompletedByTicketKey returns copies of the ticket's completed operations, most
+// recently completed first.
+// Implements repository.PendingOperationRepository.FindCompletedByTicketKey.
+func (r *PendingOperationRepository) FindCompletedByTicketKey(ctx context.Context, ticketKey string, limit int) ([]*domain.PendingOperation, error) {
+	if err := r.call(ctx, "FindCompletedByTicketKey"); err != nil {
+		return nil, err
+	}
+	ops := r.find(func(op *domain.PendingOperation) bool {
+		return op.TicketKey.String() == ticketKey && op.IsCompleted()
+	})
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].CompletedAt.Time().After(ops[j].CompletedAt.Time())
+	})
+	if limit > 0 && len(ops) > limit {
+		ops = ops[:limit]
+	}
+	return ops, nil
+}
+
+// Update stores a copy of op over the operation with its ID.
+// Implements repository.PendingOperationRepository.Update.
+func (r *PendingOperationRepository) Update(ctx context.Context, op *domain.PendingOperation) error {
+	if err := r.call(ctx, "Update"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ops[op.ID]; !ok {
+		return fmt.Errorf("%w: operation %d", domain.ErrNotFound, op.ID)
+	}
+	c := *op
+	r.ops[op.ID] = &c
+	return nil
+}
+
+// Delete removes the operation with the given ID.
+// Implements repository.PendingOperationRepository.Delete.
+func (r *PendingOperationRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.call(ctx, "Delete"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ops[id]; !ok {
+		return fmt.Errorf("%w: operation %d", domain.ErrNotFound, id)
+	}
+	delete(r.ops, id)
+	return nil
+}
+
+// PruneCompleted removes the operations completed before before.
+// Implements repository.PendingOperationRepository.PruneCompleted.
+func (r *PendingOperationRepository) PruneCompleted(ctx context.Context, before time.Time) (int, error) {
+	if err := r.call(ctx, "PruneCompleted"); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pruned := 0
+	for id, op := range r.ops {
+		if op.IsCompleted() && op.CompletedAt.Time().Before(before) {
+			delete(r.ops, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// find returns copies of the operations matching match, in queue order.
+func (r *PendingOperationRepository) find(match func(*domain.PendingOperation) bool) []*domain.PendingOperation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ops := []*domain.PendingOperation{}
+	for _, op := range r.ops {
+		if match(op) {
+			c := *op
+			ops = append(ops, &c)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].ID < ops[j].ID
+	})
+	return ops
+}
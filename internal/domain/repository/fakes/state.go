@@ -0,0 +1,376 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// stateTxKey is the context key of a StateRepository transaction.
+type stateTxKey struct{}
+
+// stateSnapshot is the content of a StateRepository, saved when a transaction begins.
+type stateSnapshot struct {
+	tickets  map[string]repository.TicketSyncState
+	projects map[string]repository.ProjectSyncState
+}
+
+// StateRepository is an in-memory repository.StateRepository.
+//
+// Transactions are not isolated: writes made in one are visible at once, and Rollback
+// restores the content from when it began.
+type StateRepository struct {
+	Behavior
+
+	mu       sync.Mutex
+	tickets  map[string]*repository.TicketSyncState
+	projects map[string]*repository.ProjectSyncState
+	tx       *stateSnapshot
+}
+
+// Verify that StateRepository implements the repository.StateRepository interface
+var _ repository.StateRepository = (*StateRepository)(nil)
+
+// NewStateRepository creates a fake state store holding the given ticket states.
+func NewStateRepository(states ...*repository.TicketSyncState) *StateRepository {
+	r := &StateRepository{
+		tickets:  make(map[string]*repository.TicketSyncState),
+		projects: make(map[string]*repository.ProjectSyncState),
+	}
+	for _, state := range states {
+		r.tickets[state.TicketKey] = cloneTicketState(state)
+	}
+	return r
+}
+
+// SaveTicketState stores a copy of the state, deriving its project key when empty.
+// Implements repository.StateRepository.SaveTicketState.
+func (r *StateRepository) SaveTicketState(ctx context.Context, state *repository.TicketSyncState) error {
+	if err := r.call(ctx, "SaveTicketState"); err != nil {
+		return err
+	}
+	if state == nil {
+		return fmt.Errorf("%w: state cannot be nil", domain.ErrInvalidInput)
+	}
+	if state.TicketKey == "" {
+		return fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+	if state.ProjectKey == "" {
+		state.ProjectKey, _, _ = strings.Cut(state.TicketKey, "-")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tickets[state.TicketKey] = cloneTicketState(state)
+	return nil
+}
+
+// GetTicketState returns a copy of the ticket's state.
+// Implements repository.StateRepository.GetTicketState.
+func (r *StateRepository) GetTicketState(ctx context.Context, ticketKey string) (*repository.TicketSyncState, error) {
+	if err := r.call(ctx, "GetTicketState"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.tickets[ticketKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: ticket state %s", domain.ErrNotFound, ticketKey)
+	}
+	return cloneTicketState(state), nil
+}
+
+// GetTicketsModifiedSince returns the states modified locally after since, most
+// recently modified first.
+// Implements repository.StateRepository.GetTicketsModifiedSince.
+func (r *StateRepository) GetTicketsModifiedSince(ctx context.Context, since time.Time) ([]*repository.TicketSyncState, error) {
+	if err := r.call(ctx, "GetTicketsModifiedSince"); err != nil {
+		return nil, err
+	}
+	return r.ticketStates(func(s *repository.TicketSyncState) bool { return s.LastModifiedLocal.After(since) }), nil
+}
+
+// GetDirtyTickets returns the states with unsynced local changes, most recently
+// modified first.
+// Implements repository.StateRepository.GetDirtyTickets.
+func (r *StateRepository) GetDirtyTickets(ctx context.Context) ([]*repository.TicketSyncState, error) {
+	if err := r.call(ctx, "GetDirtyTickets"); err != nil {
+		return nil, err
+	}
+	return r.ticketStates(func(s *repository.TicketSyncState) bool { return s.IsDirty }), nil
+}
+
+// GetConflictedTickets returns the states with conflicts, most recently modified first.
+// Implements repository.StateRepository.GetConflictedTickets.
+func (r *StateRepository) GetConflictedTickets(ctx context.Context) ([]*repository.TicketSyncState, error) {
+	if err := r.call(ctx, "GetConflictedTickets"); err != nil {
+		return nil, err
+	}
+	return r.ticketStates(func(s *repository.TicketSyncState) bool { return s.ConflictDetected }), nil
+}
+
+// GetWatchedTickets returns the watched states, most recently modified first.
+// Implements repository.StateRepository.GetWatchedTickets.
+func (r *StateRepository) GetWatchedTickets(ctx context.Context) ([]*repository.TicketSyncState, error) {
+	if err := r.call(ctx, "GetWatchedTickets"); err != nil {
+		return nil, err
+	}
+	return r.ticketStates(func(s *repository.TicketSyncState) bool { return s.Watched }), nil
+}
+
+// ticketStates returns copies of the states that match keep, most recently modified
+// locally first.
+func (r *StateRepository) ticketStates(keep func(s *repository.TicketSyncState) bool) []*repository.TicketSyncState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states := make([]*repository.TicketSyncState, 0)
+	for _, state := range r.tickets {
+		if keep(state) {
+			states = append(states, cloneTicketState(state))
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if !states[i].LastModifiedLocal.Equal(states[j].LastModifiedLocal) {
+			return states[i].LastModifiedLocal.After(states[j].LastModifiedLocal)
+		}
+		return states[i].TicketKey < states[j].TicketKey
+	})
+	return states
+}
+
+// GetTicketStatesByProject returns the project's states in issue number order.
+// Implements repository.StateRepository.GetTicketStatesByProject.
+func (r *StateRepository) GetTicketStatesByProject(ctx context.Context, projectKey string) ([]*repository.TicketSyncState, error) {
+	if err := r.call(ctx, "GetTicketStatesByProject"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states := make([]*repository.TicketSyncState, 0)
+	for _, state := range r.tickets {
+		if state.ProjectKey == projectKey {
+			states = append(states, cloneTicketState(state))
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return issueNumber(states[i].TicketKey) < issueNumber(states[j].TicketKey)
+	})
+	return states, nil
+}
+
+// DeleteTicketState removes the ticket's state.
+// Implements repository.StateRepository.DeleteTicketState.
+func (r *StateRepository) DeleteTicketState(ctx context.Context, ticketKey string) error {
+	if err := r.call(ctx, "DeleteTicketState"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tickets[ticketKey]; !ok {
+		return fmt.Errorf("%w: ticket state %s", domain.ErrNotFound, ticketKey)
+	}
+	delete(r.tickets, ticketKey)
+	return nil
+}
+
+// PruneTombstones removes the states tombstoned before the given time.
+// Implements repository.StateRepository.PruneTombstones.
+func (r *StateRepository) PruneTombstones(ctx context.Context, before time.Time) (int, error) {
+	if err := r.call(ctx, "PruneTombstones"); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pruned := 0
+	for key, state := range r.tickets {
+		if state.IsTombstone() && state.DeletedAt.Before(before) {
+			delete(r.tickets, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// SaveProjectState stores a copy of the project state.
+// Implements repository.StateRepository.SaveProjectState.
+func (r *StateRepository) SaveProjectState(ctx context.Context, state *repository.ProjectSyncState) error {
+	if err := r.call(ctx, "SaveProjectState"); err != nil {
+		return err
+	}
+	if state == nil {
+		return fmt.Errorf("%w: state cannot be nil", domain.ErrInvalidInput)
+	}
+	if state.ProjectKey == "" {
+		return fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := *state
+	r.projects[state.ProjectKey] = &s
+	return nil
+}
+
+// GetProjectState returns a copy of the project's state.
+// Implements repository.StateRepository.GetProjectState.
+func (r *StateRepository) GetProjectState(ctx context.Context, projectKey string) (*repository.ProjectSyncState, error) {
+	if err := r.call(ctx, "GetProjectState"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.projects[projectKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: project state %s", domain.ErrNotFound, projectKey)
+	}
+	s := *state
+	return &s, nil
+}
+
+// GetAllProjectStates returns copies of all project states, ordered by key.
+// Implements repository.StateRepository.GetAllProjectStates.
+func (r *StateRepository) GetAllProjectStates(ctx context.Context) ([]*repository.ProjectSyncState, error) {
+	if err := r.call(ctx, "GetAllProjectStates"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states := make([]*repository.ProjectSyncState, 0, len(r.projects))
+	for _, state := range r.projects {
+		s := *state
+		states = append(states, &s)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].ProjectKey < states[j].ProjectKey })
+	return states, nil
+}
+
+// DeleteProjectState removes the project's state and the states of its tickets.
+// Implements repository.StateRepository.DeleteProjectState.
+func (r *StateRepository) DeleteProjectState(ctx context.Context, projectKey string) error {
+	if err := r.call(ctx, "DeleteProjectState"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.projects[projectKey]; !ok {
+		return fmt.Errorf("%w: project state %s", domain.ErrNotFound, projectKey)
+	}
+	delete(r.projects, projectKey)
+	for key, state := range r.tickets {
+		if state.ProjectKey == projectKey {
+			delete(r.tickets, key)
+		}
+	}
+	return nil
+}
+
+// BeginTransaction saves the content so Rollback can restore it. Only one transaction
+// can be active at a time.
+// Implements repository.StateRepository.BeginTransaction.
+func (r *StateRepository) BeginTransaction(ctx context.Context) (context.Context, error) {
+	if err := r.call(ctx, "BeginTransaction"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tx != nil {
+		return nil, fmt.Errorf("%w: transaction already active", domain.ErrInvalidInput)
+	}
+
+	snapshot := &stateSnapshot{
+		tickets:  make(map[string]repository.TicketSyncState, len(r.tickets)),
+		projects: make(map[string]repository.ProjectSyncState, len(r.projects)),
+	}
+	for key, state := range r.tickets {
+		snapshot.tickets[key] = *state
+	}
+	for key, state := range r.projects {
+		snapshot.projects[key] = *state
+	}
+	r.tx = snapshot
+	return context.WithValue(ctx, stateTxKey{}, snapshot), nil
+}
+
+// Commit ends the transaction, keeping its writes.
+// Implements repository.StateRepository.Commit.
+func (r *StateRepository) Commit(ctx context.Context) error {
+	if err := r.call(ctx, "Commit"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.activeTransaction(ctx); err != nil {
+		return err
+	}
+	r.tx = nil
+	return nil
+}
+
+// Rollback ends the transaction, restoring the content from when it began.
+// Implements repository.StateRepository.Rollback.
+func (r *StateRepository) Rollback(ctx context.Context) error {
+	if err := r.call(ctx, "Rollback"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.activeTransaction(ctx); err != nil {
+		return err
+	}
+
+	r.tickets = make(map[string]*repository.TicketSyncState, len(r.tx.tickets))
+	for key, state := range r.tx.tickets {
+		s := state
+		r.tickets[key] = &s
+	}
+	r.projects = make(map[string]*repository.ProjectSyncState, len(r.tx.projects))
+	for key, state := range r.tx.projects {
+		s := state
+		r.projects[key] = &s
+	}
+	r.tx = nil
+	return nil
+}
+
+// activeTransaction returns ErrInvalidInput unless ctx carries the active transaction.
+// Callers hold mu.
+func (r *StateRepository) activeTransaction(ctx context.Context) error {
+	snapshot, _ := ctx.Value(stateTxKey{}).(*stateSnapshot)
+	if snapshot == nil || snapshot != r.tx {
+		return fmt.Errorf("%w: no active transaction", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// cloneTicketState returns a copy of state.
+func cloneTicketState(state *repository.TicketSyncState) *repository.TicketSyncState {
+	s := *state
+	return &s
+}
@@ -0,0 +1,119 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// TicketRepository is an in-memory repository.TicketRepository, the local ticket cache.
+type TicketRepository struct {
+	Behavior
+
+	mu      sync.Mutex
+	tickets map[string]*domain.Ticket
+}
+
+// Verify that TicketRepository implements the repository.TicketRepository interface
+var _ repository.TicketRepository = (*TicketRepository)(nil)
+
+// NewTicketRepository creates a fake ticket cache holding the given tickets.
+func NewTicketRepository(tickets ...*domain.Ticket) *TicketRepository {
+	r := &TicketRepository{tickets: make(map[string]*domain.Ticket)}
+	for _, ticket := range tickets {
+		r.tickets[ticket.Key.String()] = cloneTicket(ticket)
+	}
+	return r
+}
+
+// Save stores a copy of the ticket, replacing any with the same key.
+// Implements repository.TicketRepository.Save.
+func (r *TicketRepository) Save(ctx context.Context, ticket *domain.Ticket) error {
+	if err := r.call(ctx, "Save"); err != nil {
+		return err
+	}
+	if ticket == nil {
+		return fmt.Errorf("%w: ticket is nil", domain.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tickets[ticket.Key.String()] = cloneTicket(ticket)
+	return nil
+}
+
+// FindByKey returns a copy of the cached ticket.
+// Implements repository.TicketRepository.FindByKey.
+func (r *TicketRepository) FindByKey(ctx context.Context, key string) (*domain.Ticket, error) {
+	if err := r.call(ctx, "FindByKey"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ticket, ok := r.tickets[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: ticket %s is not in the local cache", domain.ErrNotFound, key)
+	}
+	return cloneTicket(ticket), nil
+}
+
+// FindAll returns copies of all cached tickets, ordered by key.
+// Implements repository.TicketRepository.FindAll.
+func (r *TicketRepository) FindAll(ctx context.Context) ([]*domain.Ticket, error) {
+	if err := r.call(ctx, "FindAll"); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tickets := make([]*domain.Ticket, 0, len(r.tickets))
+	for _, ticket := range r.tickets {
+		tickets = append(tickets, cloneTicket(ticket))
+	}
+	sortTickets(tickets)
+	return tickets, nil
+}
+
+// Delete removes the cached ticket.
+// Implements repository.TicketRepository.Delete.
+func (r *TicketRepository) Delete(ctx context.Context, key string) error {
+	if err := r.call(ctx, "Delete"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tickets[key]; !ok {
+		return fmt.Errorf("%w: ticket %s is not in the local cache", domain.ErrNotFound, key)
+	}
+	delete(r.tickets, key)
+	return nil
+}
+
+// Update replaces the cached ticket.
+// Implements repository.TicketRepository.Update.
+func (r *TicketRepository) Update(ctx context.Context, ticket *domain.Ticket) error {
+	if err := r.call(ctx, "Update"); err != nil {
+		return err
+	}
+	if ticket == nil {
+		return fmt.Errorf("%w: ticket is nil", domain.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := ticket.Key.String()
+	if _, ok := r.tickets[key]; !ok {
+		return fmt.Errorf("%w: ticket %s is not in the local cache", domain.ErrNotFound, key)
+	}
+	r.tickets[key] = cloneTicket(ticket)
+	return nil
+}
@@ -0,0 +1,35 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// FetchedTicketRepository defines the interface for the cache of tickets as they were
+// last fetched from Jira, with their comments and status history, keyed by the Jira
+// revision they were fetched at. A full sync whose search finds a ticket at a cached
+// revision reuses the cached ticket instead of fetching its details again.
+//
+// Implementations must:
+//   - Keep one entry per ticket, replaced whenever the ticket is fetched at a newer revision
+//   - Only return an entry when it was fetched at exactly the revision asked for
+//
+// Domain errors that methods should return:
+//   - ErrInvalidInput: when the ticket is nil or has no Updated timestamp
+//   - ErrEmptyKey: when the ticket key is empty
+//   - ErrNotFound: when the ticket is not cached at the revision asked for
+type FetchedTicketRepository interface {
+	// SaveFetchedTicket inserts or replaces the cached copy of a ticket fetched from Jira,
+	// at the revision of its Updated timestamp.
+	SaveFetchedTicket(ctx context.Context, ticket *domain.Ticket) error
+
+	// FindFetchedTicket retrieves the cached copy of a ticket if it was fetched when Jira
+	// last updated it at updated.
+	// Returns ErrNotFound if the ticket is not cached, or was cached at another revision.
+	FindFetchedTicket(ctx context.Context, key string, updated time.Time) (*domain.Ticket, error)
+}
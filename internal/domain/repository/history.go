@@ -0,0 +1,79 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// SyncHistoryEntry is the recorded summary of one finished sync run.
+// Per-ticket results are not kept; only their counts are.
+type SyncHistoryEntry struct {
+	// ID is the unique identifier assigned when the entry is recorded
+	ID int64
+
+	// ProjectKey identifies which project was synced
+	ProjectKey string
+
+	// Full indicates whether this was a full sync
+	Full bool
+
+	// StartedAt is when the sync run began
+	StartedAt time.Time
+
+	// FinishedAt is when the sync run ended
+	FinishedAt time.Time
+
+	// Succeeded is the number of tickets that synced successfully
+	Succeeded int
+
+	// Failed is the number of tickets that failed to sync
+	Failed int
+
+	// Conflicts is the number of tickets with detected conflicts
+	Conflicts int
+
+	// Error contains the run-level error message if the sync aborted
+	Error string
+}
+
+// NewSyncHistoryEntry summarizes a finished sync report for the history.
+func NewSyncHistoryEntry(report *domain.SyncReport) *SyncHistoryEntry {
+	return &SyncHistoryEntry{
+		ProjectKey: report.ProjectKey,
+		Full:       report.Full,
+		StartedAt:  report.StartedAt.Time(),
+		FinishedAt: report.FinishedAt.Time(),
+		Succeeded:  report.Succeeded(),
+		Failed:     report.Failed(),
+		Conflicts:  report.Conflicts(),
+		Error:      report.Error,
+	}
+}
+
+// SyncHistoryRepository defines the interface for the history of sync runs.
+// Entries accumulate with every sync and are pruned once older than the retention period.
+//
+// Implementations must:
+//   - Assign a unique, increasing ID to every recorded entry
+//   - Participate in transactions started by StateRepository.BeginTransaction
+//
+// Domain errors that methods should return:
+//   - ErrInvalidInput: when entry data is invalid
+type SyncHistoryRepository interface {
+	// Record appends an entry to the history and sets its ID.
+	// Returns ErrInvalidInput if the entry is nil or has no project key.
+	Record(ctx context.Context, entry *SyncHistoryEntry) error
+
+	// Recent retrieves at most limit entries, most recently finished first
+	// (all entries when limit <= 0). Returns empty slice if nothing is recorded.
+	Recent(ctx context.Context, limit int) ([]*SyncHistoryEntry, error)
+
+	// PruneBefore removes entries of runs that finished before the given time.
+	// Returns the number of entries removed.
+	PruneBefore(ctx context.Context, before time.Time) (int, error)
+}
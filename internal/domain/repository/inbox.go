@@ -0,0 +1,38 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// InboxRepository defines the interface for the durable inbox of webhook events, which
+// decouples acknowledging a delivery from processing it.
+//
+// Implementations must:
+//   - Store each event ID once, so redeliveries are recognized until the event is pruned
+//   - Keep pending events across restarts until they are marked processed
+//   - Participate in transactions started by StateRepository.BeginTransaction
+//
+// Domain errors that methods should return:
+//   - ErrInvalidInput: when the event fails domain.InboxEvent.Validate or limit is not positive
+type InboxRepository interface {
+	// AddInboxEvent stores a received event unless an event with its ID is already
+	// stored. Returns whether it was added.
+	AddInboxEvent(ctx context.Context, event *domain.InboxEvent) (bool, error)
+
+	// FindPendingInboxEvents retrieves up to limit unprocessed events, oldest first.
+	// Returns empty slice if none are pending.
+	FindPendingInboxEvents(ctx context.Context, limit int) ([]*domain.InboxEvent, error)
+
+	// MarkInboxEventsProcessed records that the events with the given IDs were processed at at.
+	MarkInboxEventsProcessed(ctx context.Context, ids []string, at time.Time) error
+
+	// PruneInboxEvents removes events processed before the given time.
+	// Returns the number of events removed.
+	PruneInboxEvents(ctx context.Context, before time.Time) (int, error)
+}
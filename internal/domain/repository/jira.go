@@ -21,7 +21,7 @@ import (
 //   - Handle rate limiting and retries appropriately
 //
 // Domain errors that methods should return:
-//   - ErrNotFound: when a ticket, comment, or project is not found
+//   - ErrNotFound: when a ticket, comment, project, or user is not found
 //   - ErrUnauthorized: when authentication fails or user lacks permissions
 //   - ErrInvalidInput: when provided data fails validation
 //   - ErrConflict: when there's an optimistic locking conflict
@@ -40,13 +40,26 @@ type JiraRepository interface {
 	// FetchAllTickets retrieves all tickets for a project.
 	// Uses JQL: "project = X ORDER BY updated DESC"
 	// Results should be paginated to avoid memory issues with large result sets.
+	// Full syncs of large projects should use ForEachTicket instead.
 	FetchAllTickets(ctx context.Context, projectKey string) ([]*domain.Ticket, error)
 
+	// ForEachTicket runs a JQL query and calls fn with each matching ticket in Jira's order,
+	// fetching one page of results at a time, so memory use does not grow with the number
+	// of matches and callers can write each ticket out as soon as it arrives.
+	// If fn returns an error, iteration stops and ForEachTicket returns that error.
+	// Returns ErrInvalidInput if Jira rejects the query.
+	ForEachTicket(ctx context.Context, jql string, fn func(ticket *domain.Ticket) error) error
+
 	// UpdateTicket pushes local ticket changes to Jira.
-	// Only updates fields that have changed to minimize API calls.
+	// Only sends fields that changed since the revision the ticket was pulled at, so edits
+	// made in Jira to other fields are kept.
 	// Returns the updated ticket with the authoritative Jira timestamp for version tracking.
 	// Returns ErrNotFound if the ticket no longer exists in Jira.
-	// Returns ErrConflict if the ticket was modified by another user since last fetch.
+	// The ticket's Version identifies the Jira revision the local changes were made on.
+	// Returns ErrConflict, also matching ErrSyncConflict, if the ticket was modified in Jira
+	// since that revision; nothing is written in that case.
+	// Changed fields the user may not edit, e.g. hidden from the ticket's edit screen, are
+	// left out with a warning rather than failing the update.
 	// Returns ErrUnauthorized if the user lacks permission to edit the ticket.
 	UpdateTicket(ctx context.Context, ticket *domain.Ticket) (*domain.Ticket, error)
 
@@ -69,4 +82,21 @@ type JiraRepository interface {
 	// FetchProjects retrieves all projects the authenticated user can access.
 	// Returns empty slice if the user has no accessible projects.
 	FetchProjects(ctx context.Context) ([]*domain.Project, error)
+
+	// SearchUsers retrieves the users whose name or email starts with query, as Jira's
+	// user search matches them.
+	// Returns empty slice if no user matches.
+	// Returns ErrInvalidInput if query is empty.
+	SearchUsers(ctx context.Context, query string) ([]*domain.User, error)
+
+	// SearchAssignableUsers retrieves the users whose name or email starts with query
+	// and who can be assigned tickets in a project.
+	// Returns empty slice if no assignable user matches.
+	// Returns ErrInvalidInput if query is empty, and ErrNotFound if the project doesn't
+	// exist.
+	SearchAssignableUsers(ctx context.Context, projectKey, query string) ([]*domain.User, error)
+
+	// GetUser retrieves a user by account ID.
+	// Returns ErrNotFound if no user has the account ID.
+	GetUser(ctx context.Context, accountID string) (*domain.User, error)
 }
@@ -0,0 +1,29 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// LocalVersionRepository defines the interface for the record of local versions of ticket
+// files saved before pulls overwrote them, so users can find and restore their work.
+//
+// Implementations must:
+//   - Keep every saved version until it is deleted, even after its ticket leaves the cache
+//   - Participate in transactions started by StateRepository.BeginTransaction
+//
+// Domain errors that methods should return:
+//   - ErrInvalidInput: when the version fails domain.LocalVersion.Validate
+//   - ErrEmptyKey: when the ticket key is empty
+type LocalVersionRepository interface {
+	// SaveLocalVersion records a local version saved before a pull overwrote its file.
+	SaveLocalVersion(ctx context.Context, version *domain.LocalVersion) error
+
+	// FindLocalVersions retrieves the local versions saved of a ticket, newest first.
+	// Returns empty slice if none were saved.
+	FindLocalVersions(ctx context.Context, ticketKey string) ([]*domain.LocalVersion, error)
+}
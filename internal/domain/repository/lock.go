@@ -0,0 +1,21 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+)
+
+// LockManager serializes work on individual tickets, so a pull, a push, and a conflict
+// resolution of the same ticket never interleave, whether they run in the daemon's
+// watcher and poller or in a CLI process next to it.
+//
+// Locks are not reentrant: locking a ticket that the caller already holds blocks until
+// ctx is done.
+type LockManager interface {
+	// Lock blocks until it holds the lock on ticketKey or ctx is done, in which case
+	// it returns ctx.Err(). The returned function releases the lock.
+	// Lock must not be called inside a transaction.
+	Lock(ctx context.Context, ticketKey string) (unlock func() error, err error)
+}
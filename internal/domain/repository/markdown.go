@@ -24,13 +24,18 @@ import (
 //   - ErrInvalidInput: when markdown content is malformed or unparseable
 type MarkdownRepository interface {
 	// ReadTicket reads and parses a markdown file into a Ticket entity.
-	// Parses YAML frontmatter for metadata and extracts description from markdown body.
+	// Parses YAML frontmatter for metadata and extracts the description from the managed
+	// zone of the description section (<!-- jiramd-managed-start --> to
+	// <!-- jiramd-managed-end -->), or the whole section in files without zones.
+	// Local zones (<!-- jiramd-local-start --> to <!-- jiramd-local-end -->) are never read.
 	// Returns ErrNotFound if the file doesn't exist.
 	// Returns ErrInvalidInput if the markdown is malformed or missing required fields.
 	ReadTicket(ctx context.Context, filePath string) (*domain.Ticket, error)
 
 	// WriteTicket generates and writes a Ticket entity to a markdown file.
 	// Uses the configured template to generate the markdown content.
+	// When the file exists, only the managed zone of its description section is
+	// replaced; local zones and other notes around it are kept as they are.
 	// Creates parent directories if they don't exist.
 	// Returns ErrInvalidInput if the ticket data is invalid.
 	WriteTicket(ctx context.Context, filePath string, ticket *domain.Ticket) error
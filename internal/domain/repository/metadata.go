@@ -0,0 +1,30 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// ProjectMetadataRepository defines the interface for cached project metadata (issue
+// types, priorities, statuses, components, and transitions), so edits can be validated
+// without contacting Jira.
+//
+// Implementations must:
+//   - Keep one entry per project, replaced as a whole when it is refreshed
+//   - Preserve FetchedAt, which callers compare against their TTL
+//
+// Domain errors that methods should return:
+//   - ErrEmptyKey: when the project key is empty
+//   - ErrNotFound: when no metadata is cached for the project
+type ProjectMetadataRepository interface {
+	// SaveProjectMetadata inserts or replaces the cached metadata of a project.
+	SaveProjectMetadata(ctx context.Context, metadata *domain.ProjectMetadata) error
+
+	// FindProjectMetadata retrieves the cached metadata of a project.
+	// Returns ErrNotFound if none is cached.
+	FindProjectMetadata(ctx context.Context, projectKey string) (*domain.ProjectMetadata, error)
+}
@@ -0,0 +1,56 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// PendingOperationRepository defines the interface for the push queue.
+// Local changes made through the CLI are queued as PendingOperations and
+// applied to Jira by the next push, in queue order for each ticket.
+//
+// Implementations must:
+//   - Assign a unique, increasing ID to every queued operation
+//   - Return operations in the order they were queued
+//   - Keep completed operations out of the queue until they are pruned
+//   - Participate in transactions started by StateRepository.BeginTransaction
+//
+// Domain errors that methods should return:
+//   - ErrNotFound: when an operation doesn't exist
+//   - ErrInvalidInput: when operation data is invalid
+type PendingOperationRepository interface {
+	// Enqueue adds an operation to the end of the queue and sets its ID.
+	// Returns ErrInvalidInput if the operation is nil or has no project key.
+	Enqueue(ctx context.Context, op *domain.PendingOperation) error
+
+	// FindByProject retrieves all queued (not completed) operations for a project, oldest first.
+	// Returns empty slice if nothing is queued.
+	FindByProject(ctx context.Context, projectKey string) ([]*domain.PendingOperation, error)
+
+	// FindByTicketKey retrieves all queued (not completed) operations for a ticket, oldest first.
+	// Returns empty slice if nothing is queued.
+	FindByTicketKey(ctx context.Context, ticketKey string) ([]*domain.PendingOperation, error)
+
+	// FindCompletedByTicketKey retrieves the operations of a ticket that were applied to
+	// Jira and not yet pruned, most recently completed first: the ticket's push history.
+	// At most limit operations are returned (all when limit <= 0).
+	FindCompletedByTicketKey(ctx context.Context, ticketKey string, limit int) ([]*domain.PendingOperation, error)
+
+	// Update persists the attempt count, last error, retry state, and completion time of an operation.
+	// Saving a completed operation (see PendingOperation.Complete) removes it from the queue.
+	// Returns ErrNotFound if the operation doesn't exist.
+	Update(ctx context.Context, op *domain.PendingOperation) error
+
+	// Delete removes an operation outright, e.g. when it is abandoned.
+	// Returns ErrNotFound if the operation doesn't exist.
+	Delete(ctx context.Context, id int64) error
+
+	// PruneCompleted removes operations completed before the given time.
+	// Returns the number of operations removed.
+	PruneCompleted(ctx context.Context, before time.Time) (int, error)
+}
@@ -7,18 +7,17 @@ import (
 
 	"github.com/esfisher/jiramd/internal/domain"
 	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/domain/repository/fakes"
 )
 
-// txKey is used as a context key for transaction state in tests.
-type txKey struct{}
-
 // TestJiraRepositoryInterface verifies that the JiraRepository interface
-// can be satisfied by a mock implementation and that the interface compiles.
+// is satisfied by its fake and that the interface compiles.
 func TestJiraRepositoryInterface(t *testing.T) {
-	var _ repository.JiraRepository = (*mockJiraRepository)(nil)
+	var _ repository.JiraRepository = (*fakes.JiraRepository)(nil)
 
 	ctx := context.Background()
-	mock := &mockJiraRepository{}
+	mock := fakes.NewJiraRepository(testTicket(t))
+	mock.AddProject(&domain.Project{Key: "JMD", Name: "Test Project"})
 
 	// Test FetchTicket
 	ticket, err := mock.FetchTicket(ctx, "JMD-1")
@@ -64,8 +63,12 @@ func TestJiraRepositoryInterface(t *testing.T) {
 	}
 
 	// Test AddComment
+	ticketKey, err := domain.NewTicketKey("JMD-1")
+	if err != nil {
+		t.Fatalf("NewTicketKey failed: %v", err)
+	}
 	comment := &domain.Comment{
-		TicketKey: "JMD-1",
+		TicketKey: ticketKey,
 		Author:    "test",
 		Body:      "test comment",
 	}
@@ -94,15 +97,49 @@ func TestJiraRepositoryInterface(t *testing.T) {
 	if projects == nil {
 		t.Error("FetchProjects returned nil slice")
 	}
+
+	// Test SearchUsers
+	mock.AddUser(&domain.User{AccountID: "abc", DisplayName: "Alice Smith", Email: "alice@example.com", Active: true})
+	users, err := mock.SearchUsers(ctx, "ali")
+	if err != nil {
+		t.Errorf("SearchUsers failed: %v", err)
+	}
+	if len(users) != 1 {
+		t.Errorf("SearchUsers returned %d users, want 1", len(users))
+	}
+
+	// Test SearchAssignableUsers
+	mock.AddUser(&domain.User{AccountID: "def", DisplayName: "Alicia Jones", Email: "alicia@example.com"})
+	assignable, err := mock.SearchAssignableUsers(ctx, "TEST", "ali")
+	if err != nil {
+		t.Errorf("SearchAssignableUsers failed: %v", err)
+	}
+	if len(assignable) != 1 {
+		t.Errorf("SearchAssignableUsers returned %d users, want only the active one", len(assignable))
+	}
+
+	// Test GetUser
+	user, err := mock.GetUser(ctx, "abc")
+	if err != nil {
+		t.Errorf("GetUser failed: %v", err)
+	}
+	if user == nil {
+		t.Error("GetUser returned nil user")
+	}
 }
 
 // TestMarkdownRepositoryInterface verifies that the MarkdownRepository interface
-// can be satisfied by a mock implementation and that the interface compiles.
+// is satisfied by its fake and that the interface compiles.
 func TestMarkdownRepositoryInterface(t *testing.T) {
-	var _ repository.MarkdownRepository = (*mockMarkdownRepository)(nil)
+	var _ repository.MarkdownRepository = (*fakes.MarkdownRepository)(nil)
 
 	ctx := context.Background()
-	mock := &mockMarkdownRepository{}
+	mock := fakes.NewMarkdownRepository()
+
+	// Test WriteTicket
+	if err := mock.WriteTicket(ctx, "tickets/JMD-1.md", testTicket(t)); err != nil {
+		t.Errorf("WriteTicket failed: %v", err)
+	}
 
 	// Test ReadTicket
 	ticket, err := mock.ReadTicket(ctx, "tickets/JMD-1.md")
@@ -113,11 +150,6 @@ func TestMarkdownRepositoryInterface(t *testing.T) {
 		t.Error("ReadTicket returned nil ticket")
 	}
 
-	// Test WriteTicket
-	if err := mock.WriteTicket(ctx, "tickets/JMD-1.md", ticket); err != nil {
-		t.Errorf("WriteTicket failed: %v", err)
-	}
-
 	// Test ReadComments
 	comments, err := mock.ReadComments(ctx, "tickets/JMD-1.md")
 	if err != nil {
@@ -154,12 +186,12 @@ func TestMarkdownRepositoryInterface(t *testing.T) {
 }
 
 // TestStateRepositoryInterface verifies that the StateRepository interface
-// can be satisfied by a mock implementation and that the interface compiles.
+// is satisfied by its fake and that the interface compiles.
 func TestStateRepositoryInterface(t *testing.T) {
-	var _ repository.StateRepository = (*mockStateRepository)(nil)
+	var _ repository.StateRepository = (*fakes.StateRepository)(nil)
 
 	ctx := context.Background()
-	mock := &mockStateRepository{}
+	mock := fakes.NewStateRepository()
 
 	// Test SaveTicketState
 	ticketState := &repository.TicketSyncState{
@@ -293,6 +325,79 @@ func TestTicketSyncStateStruct(t *testing.T) {
 	}
 }
 
+// TestTicketSyncState_ChangeDetection verifies hash and version comparison after a sync.
+func TestTicketSyncState_ChangeDetection(t *testing.T) {
+	key, _ := domain.NewTicketKey("JMD-1")
+	updated := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	ticket := domain.NewTicket(key, "Original", updated, updated)
+
+	state := repository.TicketSyncState{TicketKey: "JMD-1", IsDirty: true, ConflictDetected: true}
+
+	// Legacy states without a hash or version count as changed
+	if !state.LocalChanged(ticket) || !state.RemoteChanged(ticket.Version()) {
+		t.Error("state without hash and version should report changes")
+	}
+
+	syncedAt := updated.Add(time.Minute)
+	state.RecordSynced(ticket, syncedAt)
+
+	if state.IsDirty || state.ConflictDetected {
+		t.Error("RecordSynced should clear the dirty and conflict flags")
+	}
+	if !state.LastSynced.Equal(syncedAt) || !state.LastModifiedJira.Equal(updated) {
+		t.Errorf("timestamps = %v, %v; want %v, %v", state.LastSynced, state.LastModifiedJira, syncedAt, updated)
+	}
+	if state.LocalChanged(ticket) {
+		t.Error("LocalChanged should be false for the synced content")
+	}
+	if state.RemoteChanged(ticket.Version()) {
+		t.Error("RemoteChanged should be false for the synced version")
+	}
+
+	ticket.Summary = "Edited"
+	if !state.LocalChanged(ticket) {
+		t.Error("LocalChanged should be true after editing the content")
+	}
+
+	ticket.Updated = updated.Add(time.Hour)
+	if !state.RemoteChanged(ticket.Version()) {
+		t.Error("RemoteChanged should be true after Jira updated the ticket")
+	}
+}
+
+// TestTicketSyncState_LegacyHash verifies MD5 hashes recorded before versioning are
+// accepted and upgraded only while the content is unchanged.
+func TestTicketSyncState_LegacyHash(t *testing.T) {
+	key, _ := domain.NewTicketKey("JMD-1")
+	updated := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	ticket := domain.NewTicket(key, "Original", updated, updated)
+
+	state := repository.TicketSyncState{TicketKey: "JMD-1", ContentHash: ticket.ContentHashWith(domain.HashMD5)}
+	if state.LocalChanged(ticket) {
+		t.Error("LocalChanged should accept the legacy hash of the synced content")
+	}
+	if !state.NeedsHashUpgrade() {
+		t.Error("NeedsHashUpgrade should be true for a legacy hash")
+	}
+
+	edited := *ticket
+	edited.Summary = "Edited"
+	if state.UpgradeHash(&edited) {
+		t.Error("UpgradeHash should leave the hash of changed content alone")
+	}
+
+	if !state.UpgradeHash(ticket) {
+		t.Fatal("UpgradeHash should upgrade the hash of unchanged content")
+	}
+	if state.ContentHash != ticket.ContentHash() || state.HashVersion != domain.CurrentHashVersion {
+		t.Errorf("upgraded state = %q (version %d), want %q (version %d)",
+			state.ContentHash, state.HashVersion, ticket.ContentHash(), domain.CurrentHashVersion)
+	}
+	if state.LocalChanged(ticket) || state.NeedsHashUpgrade() || state.UpgradeHash(ticket) {
+		t.Error("upgraded state should match the content and need no upgrade")
+	}
+}
+
 // TestProjectSyncStateStruct verifies the ProjectSyncState struct compiles.
 func TestProjectSyncStateStruct(t *testing.T) {
 	now := time.Now()
@@ -311,123 +416,14 @@ func TestProjectSyncStateStruct(t *testing.T) {
 	}
 }
 
-// Mock implementations for testing interface contracts
+// testTicket returns the ticket JMD-1 used by the interface tests.
+func testTicket(t *testing.T) *domain.Ticket {
+	t.Helper()
 
-type mockJiraRepository struct{}
-
-func (m *mockJiraRepository) FetchTicket(ctx context.Context, key string) (*domain.Ticket, error) {
-	return &domain.Ticket{Key: key, Summary: "Test Ticket"}, nil
-}
-
-func (m *mockJiraRepository) FetchTicketsModifiedSince(ctx context.Context, projectKey string, since time.Time) ([]*domain.Ticket, error) {
-	return []*domain.Ticket{}, nil
-}
-
-func (m *mockJiraRepository) FetchAllTickets(ctx context.Context, projectKey string) ([]*domain.Ticket, error) {
-	return []*domain.Ticket{}, nil
-}
-
-func (m *mockJiraRepository) UpdateTicket(ctx context.Context, ticket *domain.Ticket) (*domain.Ticket, error) {
-	return ticket, nil
-}
-
-func (m *mockJiraRepository) FetchComments(ctx context.Context, ticketKey string) ([]*domain.Comment, error) {
-	return []*domain.Comment{}, nil
-}
-
-func (m *mockJiraRepository) AddComment(ctx context.Context, ticketKey string, comment *domain.Comment) (*domain.Comment, error) {
-	comment.ID = "12345"
-	return comment, nil
-}
-
-func (m *mockJiraRepository) FetchProject(ctx context.Context, projectKey string) (*domain.Project, error) {
-	return &domain.Project{Key: projectKey, Name: "Test Project"}, nil
-}
-
-func (m *mockJiraRepository) FetchProjects(ctx context.Context) ([]*domain.Project, error) {
-	return []*domain.Project{}, nil
-}
-
-type mockMarkdownRepository struct{}
-
-func (m *mockMarkdownRepository) ReadTicket(ctx context.Context, filePath string) (*domain.Ticket, error) {
-	return &domain.Ticket{Key: "JMD-1", Summary: "Test Ticket"}, nil
-}
-
-func (m *mockMarkdownRepository) WriteTicket(ctx context.Context, filePath string, ticket *domain.Ticket) error {
-	return nil
-}
-
-func (m *mockMarkdownRepository) ReadComments(ctx context.Context, filePath string) ([]*domain.Comment, error) {
-	return []*domain.Comment{}, nil
-}
-
-func (m *mockMarkdownRepository) WriteComments(ctx context.Context, filePath string, comments []*domain.Comment) error {
-	return nil
-}
-
-func (m *mockMarkdownRepository) ListTicketFiles(ctx context.Context, directory string) ([]string, error) {
-	return []string{}, nil
-}
-
-func (m *mockMarkdownRepository) GenerateIndex(ctx context.Context, indexPath string, tickets []*domain.Ticket) error {
-	return nil
-}
-
-func (m *mockMarkdownRepository) ValidateTemplate(ctx context.Context, templatePath string) error {
-	return nil
-}
-
-type mockStateRepository struct{}
-
-func (m *mockStateRepository) SaveTicketState(ctx context.Context, state *repository.TicketSyncState) error {
-	return nil
-}
-
-func (m *mockStateRepository) GetTicketState(ctx context.Context, ticketKey string) (*repository.TicketSyncState, error) {
-	return &repository.TicketSyncState{TicketKey: ticketKey}, nil
-}
-
-func (m *mockStateRepository) GetTicketsModifiedSince(ctx context.Context, since time.Time) ([]*repository.TicketSyncState, error) {
-	return []*repository.TicketSyncState{}, nil
-}
-
-func (m *mockStateRepository) GetDirtyTickets(ctx context.Context) ([]*repository.TicketSyncState, error) {
-	return []*repository.TicketSyncState{}, nil
-}
-
-func (m *mockStateRepository) GetConflictedTickets(ctx context.Context) ([]*repository.TicketSyncState, error) {
-	return []*repository.TicketSyncState{}, nil
-}
-
-func (m *mockStateRepository) DeleteTicketState(ctx context.Context, ticketKey string) error {
-	return nil
-}
-
-func (m *mockStateRepository) SaveProjectState(ctx context.Context, state *repository.ProjectSyncState) error {
-	return nil
-}
-
-func (m *mockStateRepository) GetProjectState(ctx context.Context, projectKey string) (*repository.ProjectSyncState, error) {
-	return &repository.ProjectSyncState{ProjectKey: projectKey}, nil
-}
-
-func (m *mockStateRepository) GetAllProjectStates(ctx context.Context) ([]*repository.ProjectSyncState, error) {
-	return []*repository.ProjectSyncState{}, nil
-}
-
-func (m *mockStateRepository) DeleteProjectState(ctx context.Context, projectKey string) error {
-	return nil
-}
-
-func (m *mockStateRepository) BeginTransaction(ctx context.Context) (context.Context, error) {
-	return context.WithValue(ctx, txKey{}, true), nil
-}
-
-func (m *mockStateRepository) Commit(ctx context.Context) error {
-	return nil
-}
-
-func (m *mockStateRepository) Rollback(ctx context.Context) error {
-	return nil
+	key, err := domain.NewTicketKey("JMD-1")
+	if err != nil {
+		t.Fatalf("NewTicketKey failed: %v", err)
+	}
+	now := time.Now()
+	return domain.NewTicket(key, "Test Ticket", now, now)
 }
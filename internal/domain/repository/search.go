@@ -0,0 +1,46 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import "context"
+
+// SearchHit is a cached ticket matched by a full-text search.
+type SearchHit struct {
+	// TicketKey is the key of the matching ticket
+	TicketKey string
+
+	// Summary is the ticket summary
+	Summary string
+
+	// Status is the ticket status
+	Status string
+
+	// Snippet is an excerpt of the best matching text with matches in [brackets]
+	Snippet string
+
+	// Score ranks the hit against the others; higher is more relevant
+	Score float64
+}
+
+// SearchRepository defines the interface for full-text search over cached tickets.
+// The index covers ticket summaries, descriptions, and comments.
+//
+// Implementations must:
+//   - Keep the index current as tickets and comments are cached
+//   - Answer searches without contacting Jira
+//   - Participate in transactions started by StateRepository.BeginTransaction
+//
+// Domain errors that methods should return:
+//   - ErrInvalidInput: when the search text contains no searchable terms
+type SearchRepository interface {
+	// Search returns at most limit tickets containing every term of text,
+	// most relevant first (all matches when limit <= 0).
+	// Double-quoted phrases must match exactly and a trailing * matches any suffix.
+	// Returns empty slice if nothing matches.
+	Search(ctx context.Context, text string, limit int) ([]*SearchHit, error)
+
+	// Rebuild discards the index and rebuilds it from the cached tickets and comments,
+	// returning the number of tickets indexed. Used after bulk changes that bypassed it.
+	Rebuild(ctx context.Context) (int, error)
+}
@@ -6,6 +6,8 @@ package repository
 import (
 	"context"
 	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
 )
 
 // TicketSyncState represents the synchronization state of a single ticket.
@@ -14,6 +16,9 @@ type TicketSyncState struct {
 	// TicketKey is the unique Jira ticket identifier
 	TicketKey string
 
+	// ProjectKey identifies the ticket's project (derived from TicketKey when empty on save)
+	ProjectKey string
+
 	// LastSynced is when the ticket was last successfully synced with Jira
 	LastSynced time.Time
 
@@ -28,6 +33,90 @@ type TicketSyncState struct {
 
 	// ConflictDetected indicates if both local and Jira were modified since last sync
 	ConflictDetected bool
+
+	// ContentHash is the domain.Ticket ContentHash of the content as last synced.
+	// Comparing it with the current content detects real changes regardless of clocks.
+	ContentHash string
+
+	// HashVersion is the algorithm ContentHash was computed with; zero means the state
+	// predates versioned hashes, whose hashes are MD5
+	HashVersion domain.HashVersion
+
+	// JiraVersion is the domain.Ticket Version of the Jira revision last pulled.
+	// Push compares it with Jira's current version for optimistic concurrency.
+	JiraVersion string
+
+	// FilePath is where the ticket's markdown file was last written, relative to the
+	// markdown directory with forward slashes (empty when unknown)
+	FilePath string
+
+	// PullOnly marks a ticket pulled ad hoc from outside the sync scope: jiramd pull
+	// refreshes it, but local edits to it are never pushed
+	PullOnly bool
+
+	// Watched marks a ticket of the watch list, which every sync pulls whether or not
+	// its project is synced
+	Watched bool
+
+	// DeletedAt is when the ticket was deleted from Jira (zero unless this is a tombstone).
+	// Tombstones stop a stale local file from being pushed back as a new ticket.
+	DeletedAt time.Time
+}
+
+// IsTombstone reports whether the state records a ticket deleted from Jira.
+func (s *TicketSyncState) IsTombstone() bool {
+	return !s.DeletedAt.IsZero()
+}
+
+// RecordSynced updates the state after the ticket was successfully synced at the given time,
+// remembering its content hash and Jira version and clearing the dirty and conflict flags.
+func (s *TicketSyncState) RecordSynced(ticket *domain.Ticket, at time.Time) {
+	s.LastSynced = at.UTC()
+	s.LastModifiedJira = ticket.Updated.UTC()
+	s.ContentHash = ticket.ContentHash()
+	s.HashVersion = domain.CurrentHashVersion
+	s.JiraVersion = ticket.Version()
+	s.IsDirty = false
+	s.ConflictDetected = false
+}
+
+// LocalChanged reports whether the ticket's content differs from the content as last
+// synced, comparing hashes with the algorithm the recorded one was computed with. A
+// state without a recorded hash predates hashing, so any content counts as changed.
+func (s *TicketSyncState) LocalChanged(ticket *domain.Ticket) bool {
+	return s.ContentHash == "" || s.ContentHash != ticket.ContentHashWith(s.hashVersion())
+}
+
+// UpgradeHash replaces a hash recorded with an older algorithm by the current one,
+// provided the ticket's content is still the content as last synced, and reports
+// whether it did. Hashes of content that changed since are replaced by the next
+// RecordSynced instead.
+func (s *TicketSyncState) UpgradeHash(ticket *domain.Ticket) bool {
+	if !s.NeedsHashUpgrade() || s.LocalChanged(ticket) {
+		return false
+	}
+	s.ContentHash = ticket.ContentHash()
+	s.HashVersion = domain.CurrentHashVersion
+	return true
+}
+
+// NeedsHashUpgrade reports whether the recorded hash was computed with an older algorithm.
+func (s *TicketSyncState) NeedsHashUpgrade() bool {
+	return s.ContentHash != "" && s.hashVersion() != domain.CurrentHashVersion
+}
+
+// hashVersion returns the algorithm of ContentHash.
+func (s *TicketSyncState) hashVersion() domain.HashVersion {
+	if s.HashVersion == 0 {
+		return domain.HashMD5
+	}
+	return s.HashVersion
+}
+
+// RemoteChanged reports whether Jira's current version differs from the version last
+// pulled. A state without a recorded version predates versioning, so Jira counts as changed.
+func (s *TicketSyncState) RemoteChanged(jiraVersion string) bool {
+	return s.JiraVersion == "" || s.JiraVersion != jiraVersion
 }
 
 // ProjectSyncState represents the synchronization state of a project.
@@ -83,11 +172,24 @@ type StateRepository interface {
 	// Returns empty slice if no conflicts exist.
 	GetConflictedTickets(ctx context.Context) ([]*TicketSyncState, error)
 
+	// GetWatchedTickets retrieves the states of all tickets marked as watched, including
+	// those no longer on the watch list.
+	// Returns empty slice if no ticket is watched.
+	GetWatchedTickets(ctx context.Context) ([]*TicketSyncState, error)
+
+	// GetTicketStatesByProject retrieves the states of all tickets in a project,
+	// in issue number order. Returns empty slice if the project has no tickets.
+	GetTicketStatesByProject(ctx context.Context, projectKey string) ([]*TicketSyncState, error)
+
 	// DeleteTicketState removes the synchronization state for a ticket.
 	// Used when a ticket is deleted from both Jira and local storage.
 	// Returns ErrNotFound if the state doesn't exist.
 	DeleteTicketState(ctx context.Context, ticketKey string) error
 
+	// PruneTombstones removes ticket states that were tombstoned before the given time.
+	// Returns the number of states removed.
+	PruneTombstones(ctx context.Context, before time.Time) (int, error)
+
 	// SaveProjectState persists the synchronization state of a project.
 	// Creates a new record if the project state doesn't exist, updates if it does.
 	SaveProjectState(ctx context.Context, state *ProjectSyncState) error
@@ -0,0 +1,35 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+)
+
+// UnitOfWork stages the markdown file writes and state changes of one operation (e.g.,
+// pulling a ticket) and applies them together, so a failure cannot leave a file that
+// disagrees with the recorded sync state.
+//
+// Implementations must:
+//   - Apply nothing until Commit
+//   - Run all staged state changes in one StateRepository transaction
+//   - Restore every file they changed when the state changes fail
+//
+// A UnitOfWork is used once: stage changes, then call Commit.
+type UnitOfWork interface {
+	// WriteFile stages replacing the file at path with content.
+	WriteFile(path string, content []byte)
+
+	// RemoveFile stages removing the file at path. Removing a missing file is not an error.
+	RemoveFile(path string)
+
+	// Stage stages a state change. fn runs inside the state transaction and must
+	// use the context it is given.
+	Stage(fn func(ctx context.Context) error)
+
+	// Commit applies the staged state changes and files. If anything fails, the state
+	// transaction is rolled back, files already written are restored, and the error
+	// is returned.
+	Commit(ctx context.Context) error
+}
@@ -0,0 +1,45 @@
+// Package repository defines interfaces for data access.
+// These interfaces are part of the domain layer and define contracts
+// that infrastructure implementations must fulfill.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// UserRepository defines the interface for the cached Jira user directory, which spares
+// Jira's user search API when completing assignees and resolving mentions.
+//
+// Implementations must:
+//   - Record when each user and each search query was fetched from Jira, so callers can
+//     refresh entries older than their TTL
+//   - Match queries the way domain.User.Matches does
+//
+// Domain errors that methods should return:
+//   - ErrEmptyKey: when an account ID is empty
+//   - ErrNotFound: when a user is not cached
+type UserRepository interface {
+	// SaveUsers inserts or replaces users, recording fetchedAt as when they were fetched
+	// from Jira.
+	SaveUsers(ctx context.Context, users []*domain.User, fetchedAt time.Time) error
+
+	// FindUser retrieves a cached user and when it was fetched from Jira.
+	// Returns ErrNotFound if the user is not cached.
+	FindUser(ctx context.Context, accountID string) (*domain.User, time.Time, error)
+
+	// SearchUsers retrieves the cached users matching query, ordered by display name.
+	// Every cached user matches an empty query.
+	// Returns empty slice if no user matches.
+	SearchUsers(ctx context.Context, query string) ([]*domain.User, error)
+
+	// RecordUserSearch records that query was searched in Jira at searchedAt, and its
+	// matches saved with SaveUsers.
+	RecordUserSearch(ctx context.Context, query string, searchedAt time.Time) error
+
+	// UserSearchedAt returns when query was last searched in Jira, or the zero time if it
+	// never was.
+	UserSearchedAt(ctx context.Context, query string) (time.Time, error)
+}
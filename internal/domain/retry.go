@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// ErrorClass groups push failures by whether trying again can help.
+type ErrorClass string
+
+const (
+	// ErrorClassNetwork covers transport failures and other errors without a more specific class
+	ErrorClassNetwork ErrorClass = "network"
+
+	// ErrorClassRateLimit covers Jira rejecting a request for exceeding its rate limit (429)
+	ErrorClassRateLimit ErrorClass = "rate_limit"
+
+	// ErrorClassServer covers Jira failing or being unavailable (5xx)
+	ErrorClassServer ErrorClass = "server"
+
+	// ErrorClassConflict covers a concurrent change on the Jira side (409)
+	ErrorClassConflict ErrorClass = "conflict"
+
+	// ErrorClassPermanent covers requests Jira will never accept as sent: invalid input (400),
+	// missing authorization (401/403), a ticket that no longer exists (404), or a change
+	// made on a revision Jira has since moved past (ErrSyncConflict), which needs conflict
+	// resolution rather than another attempt. Permanent errors are never retried.
+	ErrorClassPermanent ErrorClass = "permanent"
+)
+
+// RetryableErrorClasses lists the error classes a RetryPolicy may retry on.
+var RetryableErrorClasses = []ErrorClass{
+	ErrorClassNetwork,
+	ErrorClassRateLimit,
+	ErrorClassServer,
+	ErrorClassConflict,
+}
+
+// ClassifyError returns the class of a push failure, based on the domain error it wraps.
+func ClassifyError(err error) ErrorClass {
+	switch {
+	case errors.Is(err, ErrInvalidInput),
+		errors.Is(err, ErrInvalidFieldValue),
+		errors.Is(err, ErrUnauthorized),
+		errors.Is(err, ErrNotFound),
+		errors.Is(err, ErrReadOnlyField),
+		errors.Is(err, ErrSyncConflict):
+		return ErrorClassPermanent
+	case errors.Is(err, ErrRateLimited):
+		return ErrorClassRateLimit
+	case errors.Is(err, ErrUnavailable):
+		return ErrorClassServer
+	case errors.Is(err, ErrConflict):
+		return ErrorClassConflict
+	default:
+		return ErrorClassNetwork
+	}
+}
+
+// RetryPolicy decides whether and when a failed pending operation is tried again.
+// This is a value object.
+type RetryPolicy struct {
+	// MaxAttempts is how many times an operation is attempted before it is given up on
+	MaxAttempts int
+
+	// InitialBackoff is the wait after the first failed attempt
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts
+	MaxBackoff time.Duration
+
+	// Multiplier grows the wait after each further failed attempt
+	Multiplier float64
+
+	// RetryOn lists the error classes worth retrying; failures of any other class
+	// give up on the operation immediately
+	RetryOn []ErrorClass
+}
+
+// DefaultRetryPolicy returns the policy used when sync.retry is not configured:
+// three attempts, backing off from 30 seconds to at most 15 minutes, retrying every
+// error class except permanent errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     15 * time.Minute,
+		Multiplier:     2,
+		RetryOn:        slices.Clone(RetryableErrorClasses),
+	}
+}
+
+// Validate checks that the policy is usable.
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("%w: max attempts must be at least 1", ErrInvalidInput)
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return fmt.Errorf("%w: backoff cannot be negative", ErrInvalidInput)
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		return fmt.Errorf("%w: max backoff %s is less than initial backoff %s", ErrInvalidInput, p.MaxBackoff, p.InitialBackoff)
+	}
+	if p.Multiplier < 1 {
+		return fmt.Errorf("%w: backoff multiplier must be at least 1", ErrInvalidInput)
+	}
+	for _, class := range p.RetryOn {
+		if !slices.Contains(RetryableErrorClasses, class) {
+			return fmt.Errorf("%w: cannot retry on error class %q", ErrInvalidInput, class)
+		}
+	}
+	return nil
+}
+
+// Retryable reports whether a failure with err is worth another attempt.
+func (p RetryPolicy) Retryable(err error) bool {
+	return slices.Contains(p.RetryOn, ClassifyError(err))
+}
+
+// Backoff returns how long to wait before the next attempt of an operation that has
+// failed attempts times: InitialBackoff after the first failure, multiplied by
+// Multiplier for each further one, capped at MaxBackoff.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempts-1))
+	if delay >= float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(delay)
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorClass
+	}{
+		{fmt.Errorf("%w: bad field", ErrInvalidInput), ErrorClassPermanent},
+		{fmt.Errorf("%w: forbidden", ErrUnauthorized), ErrorClassPermanent},
+		{fmt.Errorf("%w: no such ticket", ErrNotFound), ErrorClassPermanent},
+		{fmt.Errorf("%w: slow down", ErrRateLimited), ErrorClassRateLimit},
+		{fmt.Errorf("%w: bad gateway", ErrUnavailable), ErrorClassServer},
+		{fmt.Errorf("%w: edited concurrently", ErrConflict), ErrorClassConflict},
+		{fmt.Errorf("%w: %w: updated in Jira since the last pull", ErrConflict, ErrSyncConflict), ErrorClassPermanent},
+		{ReadOnlyFieldError(TicketKey{}, []string{"labels"}), ErrorClassPermanent},
+		{errors.New("connection refused"), ErrorClassNetwork},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyError(tt.err); got != tt.want {
+			t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Minute,
+		Multiplier:     3,
+	}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 10 * time.Second},
+		{2, 30 * time.Second},
+		{3, time.Minute},
+		{50, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	if err := DefaultRetryPolicy().Validate(); err != nil {
+		t.Fatalf("DefaultRetryPolicy().Validate() = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *RetryPolicy)
+	}{
+		{"no attempts", func(p *RetryPolicy) { p.MaxAttempts = 0 }},
+		{"negative backoff", func(p *RetryPolicy) { p.InitialBackoff = -time.Second }},
+		{"max below initial", func(p *RetryPolicy) { p.MaxBackoff = time.Second }},
+		{"shrinking backoff", func(p *RetryPolicy) { p.Multiplier = 0.5 }},
+		{"retry permanent errors", func(p *RetryPolicy) { p.RetryOn = []ErrorClass{ErrorClassPermanent} }},
+		{"unknown class", func(p *RetryPolicy) { p.RetryOn = []ErrorClass{"timeouts"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := DefaultRetryPolicy()
+			tt.modify(&policy)
+			if err := policy.Validate(); !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("Validate() = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
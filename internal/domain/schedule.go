@@ -0,0 +1,255 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronDescriptors maps the supported @-shorthands to their five-field equivalents.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronMonthNames and cronDayNames allow symbolic values in the month and day-of-week fields.
+var (
+	cronMonthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	cronDayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+)
+
+// cronSearchLimit bounds how far Next searches before giving up on an unsatisfiable
+// expression (e.g., "0 0 30 2 *" never fires).
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// CronSchedule is a value object representing a standard five-field cron expression:
+// minute, hour, day-of-month, month, and day-of-week (e.g., "0 3 * * *" for 03:00 daily).
+// Fields support "*", single values, ranges ("1-5"), steps ("*/15", "0-30/10"), lists
+// ("1,15"), and month/day names ("JAN", "MON"). The @yearly, @monthly, @weekly, @daily,
+// @midnight, and @hourly shorthands are also accepted.
+//
+// The zero value represents "no schedule" and never fires.
+type CronSchedule struct {
+	expr string
+
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// domRestricted and dowRestricted track whether the day fields were restricted;
+	// when both are, a day matches if EITHER field matches (standard cron semantics).
+	domRestricted bool
+	dowRestricted bool
+}
+
+// ParseCronSchedule parses a cron expression into a CronSchedule.
+// Returns ErrInvalidInput if the expression is malformed or a value is out of range.
+func ParseCronSchedule(expr string) (CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return CronSchedule{}, fmt.Errorf("%w: cron expression is required", ErrInvalidInput)
+	}
+
+	normalized := expr
+	if strings.HasPrefix(expr, "@") {
+		descriptor, ok := cronDescriptors[strings.ToLower(expr)]
+		if !ok {
+			return CronSchedule{}, fmt.Errorf("%w: unknown cron descriptor %q", ErrInvalidInput, expr)
+		}
+		normalized = descriptor
+	}
+
+	fields := strings.Fields(normalized)
+	if len(fields) != 5 {
+		return CronSchedule{}, fmt.Errorf("%w: cron expression %q must have 5 fields (minute hour day-of-month month day-of-week)", ErrInvalidInput, expr)
+	}
+
+	cs := CronSchedule{expr: expr}
+	var err error
+
+	if cs.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return CronSchedule{}, fmt.Errorf("%w: minute field: %v", ErrInvalidInput, err)
+	}
+	if cs.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return CronSchedule{}, fmt.Errorf("%w: hour field: %v", ErrInvalidInput, err)
+	}
+	if cs.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return CronSchedule{}, fmt.Errorf("%w: day-of-month field: %v", ErrInvalidInput, err)
+	}
+	if cs.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return CronSchedule{}, fmt.Errorf("%w: month field: %v", ErrInvalidInput, err)
+	}
+	// Day-of-week accepts 0-7 where both 0 and 7 mean Sunday
+	if cs.dow, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return CronSchedule{}, fmt.Errorf("%w: day-of-week field: %v", ErrInvalidInput, err)
+	}
+	if cs.dow&(1<<7) != 0 {
+		cs.dow = (cs.dow | 1) &^ (1 << 7)
+	}
+
+	cs.domRestricted = fields[2] != "*" && fields[2] != "?"
+	cs.dowRestricted = fields[4] != "*" && fields[4] != "?"
+
+	return cs, nil
+}
+
+// parseCronField parses a single comma-separated cron field into a bitset of allowed values.
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty list element in %q", field)
+		}
+
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			s, err := strconv.Atoi(part[idx+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = s
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			// Full range
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = parseCronValue(bounds[0], names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(bounds[1], names); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := parseCronValue(rangePart, names)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// "5/10" means "starting at 5, every 10" through the end of the range
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range in %q (allowed %d-%d)", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// parseCronValue parses a numeric or symbolic cron value.
+func parseCronValue(s string, names map[string]int) (int, error) {
+	if names != nil {
+		if v, ok := names[strings.ToUpper(s)]; ok {
+			return v, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// String returns the original cron expression.
+func (cs CronSchedule) String() string {
+	return cs.expr
+}
+
+// IsZero returns true if no schedule is configured.
+func (cs CronSchedule) IsZero() bool {
+	return cs.expr == ""
+}
+
+// Next returns the first activation time strictly after the given time.
+// The schedule is evaluated in the location of the given time, so callers pass
+// time.Now() to get wall-clock semantics. Returns the zero time if the schedule
+// is zero or cannot be satisfied.
+func (cs CronSchedule) Next(after time.Time) time.Time {
+	if cs.IsZero() {
+		return time.Time{}
+	}
+
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		if !cronHas(cs.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !cs.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !cronHas(cs.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !cronHas(cs.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// MissedSince reports whether at least one activation fell between last (exclusive)
+// and now (inclusive). A zero last time counts as missed, so a schedule that has
+// never run catches up immediately.
+func (cs CronSchedule) MissedSince(last, now time.Time) bool {
+	if cs.IsZero() {
+		return false
+	}
+	if last.IsZero() {
+		return true
+	}
+	next := cs.Next(last.In(now.Location()))
+	return !next.IsZero() && !next.After(now)
+}
+
+// dayMatches applies cron's day-of-month / day-of-week rules to the given date.
+func (cs CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := cronHas(cs.dom, t.Day())
+	dowMatch := cronHas(cs.dow, int(t.Weekday()))
+
+	if cs.domRestricted && cs.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// cronHas reports whether value is set in the bitset.
+func cronHas(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "nightly", expr: "0 3 * * *", wantErr: false},
+		{name: "steps and ranges", expr: "*/15 9-17 * * 1-5", wantErr: false},
+		{name: "lists", expr: "0,30 8,20 1,15 * *", wantErr: false},
+		{name: "names", expr: "0 6 * JAN-MAR MON,FRI", wantErr: false},
+		{name: "sunday as 7", expr: "0 0 * * 7", wantErr: false},
+		{name: "descriptor", expr: "@daily", wantErr: false},
+		{name: "empty", expr: "", wantErr: true},
+		{name: "too few fields", expr: "0 3 * *", wantErr: true},
+		{name: "too many fields", expr: "0 0 3 * * *", wantErr: true},
+		{name: "minute out of range", expr: "60 * * * *", wantErr: true},
+		{name: "hour out of range", expr: "0 24 * * *", wantErr: true},
+		{name: "day of month zero", expr: "0 0 0 * *", wantErr: true},
+		{name: "inverted range", expr: "0 5-1 * * *", wantErr: true},
+		{name: "zero step", expr: "*/0 * * * *", wantErr: true},
+		{name: "garbage value", expr: "x * * * *", wantErr: true},
+		{name: "unknown descriptor", expr: "@sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, err := ParseCronSchedule(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCronSchedule(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("ParseCronSchedule(%q) error should wrap ErrInvalidInput, got %v", tt.expr, err)
+				}
+				return
+			}
+			if cs.String() != tt.expr {
+				t.Errorf("String() = %q, want %q", cs.String(), tt.expr)
+			}
+			if cs.IsZero() {
+				t.Error("parsed schedule should not be zero")
+			}
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// 2025-01-15 is a Wednesday
+	base := time.Date(2025, 1, 15, 10, 17, 42, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "nightly later today is tomorrow",
+			expr:  "0 3 * * *",
+			after: base,
+			want:  time.Date(2025, 1, 16, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "every 15 minutes",
+			expr:  "*/15 * * * *",
+			after: base,
+			want:  time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "strictly after exact match",
+			expr:  "30 10 * * *",
+			after: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+			want:  time.Date(2025, 1, 16, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "weekdays only skips weekend",
+			expr:  "0 9 * * MON-FRI",
+			after: time.Date(2025, 1, 17, 12, 0, 0, 0, time.UTC), // Friday
+			want:  time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),  // Monday
+		},
+		{
+			name:  "first of next month",
+			expr:  "@monthly",
+			after: base,
+			want:  time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "month rollover into next year",
+			expr:  "0 0 1 JAN *",
+			after: base,
+			want:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "day of month OR day of week when both restricted",
+			expr:  "0 0 20 * SUN",
+			after: base,
+			want:  time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC), // Sunday comes before the 20th
+		},
+		{
+			name:  "sunday as 7",
+			expr:  "0 0 * * 7",
+			after: base,
+			want:  time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "leap day",
+			expr:  "0 0 29 2 *",
+			after: base,
+			want:  time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, err := ParseCronSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule(%q) error = %v", tt.expr, err)
+			}
+			got := cs.Next(tt.after)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_Next_Unsatisfiable(t *testing.T) {
+	cs, err := ParseCronSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCronSchedule() error = %v", err)
+	}
+	if got := cs.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() for February 30th = %v, want zero time", got)
+	}
+}
+
+func TestCronSchedule_Next_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	cs, err := ParseCronSchedule("0 3 * * *")
+	if err != nil {
+		t.Fatalf("ParseCronSchedule() error = %v", err)
+	}
+
+	after := time.Date(2025, 1, 15, 0, 0, 0, 0, loc)
+	got := cs.Next(after)
+	want := time.Date(2025, 1, 15, 3, 0, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v (evaluated in caller's location)", got, want)
+	}
+}
+
+func TestCronSchedule_MissedSince(t *testing.T) {
+	cs, err := ParseCronSchedule("0 3 * * *")
+	if err != nil {
+		t.Fatalf("ParseCronSchedule() error = %v", err)
+	}
+
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		last time.Time
+		want bool
+	}{
+		{name: "never run", last: time.Time{}, want: true},
+		{name: "ran after today's activation", last: time.Date(2025, 1, 15, 3, 5, 0, 0, time.UTC), want: false},
+		{name: "last ran yesterday afternoon", last: time.Date(2025, 1, 14, 15, 0, 0, 0, time.UTC), want: true},
+		{name: "down for several days", last: time.Date(2025, 1, 10, 3, 0, 0, 0, time.UTC), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cs.MissedSince(tt.last, now); got != tt.want {
+				t.Errorf("MissedSince(%v, %v) = %v, want %v", tt.last, now, got, tt.want)
+			}
+		})
+	}
+
+	var zero CronSchedule
+	if zero.MissedSince(time.Time{}, now) {
+		t.Error("zero schedule should never report a missed activation")
+	}
+	if !zero.Next(now).IsZero() {
+		t.Error("zero schedule should never have a next activation")
+	}
+}
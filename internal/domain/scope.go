@@ -0,0 +1,84 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"strings"
+)
+
+// CurrentUserAlias is the SyncFilter.OnlyAssignee value that stands for the configured
+// Jira user, translated to currentUser() in JQL.
+const CurrentUserAlias = "me"
+
+// SyncFilter limits which tickets of a project are synced. Its conditions are sent to
+// Jira as JQL, so out-of-scope tickets are never pulled, and also evaluated locally
+// (see Scope), so cached tickets that fall out of scope can be removed.
+// The zero value syncs every ticket.
+type SyncFilter struct {
+	// OnlyAssignee limits syncing to tickets assigned to this user
+	// (CurrentUserAlias for the configured Jira user; empty means any assignee)
+	OnlyAssignee string
+
+	// IssueTypes limits syncing to tickets of these types (empty means every type)
+	IssueTypes []string
+
+	// ExcludeLabels skips tickets that have any of these labels
+	ExcludeLabels []string
+}
+
+// IsZero returns true if the filter has no conditions.
+func (f SyncFilter) IsZero() bool {
+	return f.OnlyAssignee == "" && len(f.IssueTypes) == 0 && len(f.ExcludeLabels) == 0
+}
+
+// JQL returns the filter's conditions joined by AND, or "" when it has none.
+func (f SyncFilter) JQL() string {
+	var clauses []string
+
+	switch assignee := strings.TrimSpace(f.OnlyAssignee); {
+	case strings.EqualFold(assignee, CurrentUserAlias):
+		clauses = append(clauses, "assignee = currentUser()")
+	case assignee != "":
+		clauses = append(clauses, "assignee = "+quoteJQL(assignee))
+	}
+
+	if len(f.IssueTypes) > 0 {
+		clauses = append(clauses, "issuetype IN "+jqlList(f.IssueTypes))
+	}
+
+	if len(f.ExcludeLabels) > 0 {
+		// labels NOT IN alone would also exclude every ticket without labels
+		clauses = append(clauses, "(labels IS EMPTY OR labels NOT IN "+jqlList(f.ExcludeLabels)+")")
+	}
+
+	return strings.Join(clauses, " AND ")
+}
+
+// ProjectJQL returns the JQL selecting a project's tickets within the filter.
+func (f SyncFilter) ProjectJQL(projectKey string) string {
+	jql := "project = " + quoteJQL(projectKey)
+	if conditions := f.JQL(); conditions != "" {
+		jql += " AND " + conditions
+	}
+	return jql
+}
+
+// Scope returns the filter for evaluating tickets locally. opts.CurrentUser must be set
+// when OnlyAssignee is CurrentUserAlias.
+func (f SyncFilter) Scope(opts FilterOptions) (*TicketFilter, error) {
+	return ParseTicketFilter(f.JQL(), opts)
+}
+
+// jqlList formats values as a parenthesized JQL list.
+func jqlList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, quoteJQL(value))
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+// quoteJQL quotes a JQL value, escaping quotes and backslashes.
+func quoteJQL(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSyncFilter(t *testing.T) {
+	filter := SyncFilter{
+		OnlyAssignee:  "me",
+		IssueTypes:    []string{"Bug", "Story"},
+		ExcludeLabels: []string{"wontfix"},
+	}
+
+	want := `project = "JMD" AND assignee = currentUser() AND issuetype IN ("Bug", "Story") AND (labels IS EMPTY OR labels NOT IN ("wontfix"))`
+	if got := filter.ProjectJQL("JMD"); got != want {
+		t.Errorf("ProjectJQL() = %s\nwant %s", got, want)
+	}
+	if got := (SyncFilter{}).ProjectJQL("JMD"); got != `project = "JMD"` {
+		t.Errorf("zero ProjectJQL() = %s", got)
+	}
+
+	scope, err := filter.Scope(FilterOptions{CurrentUser: "me@example.com"})
+	if err != nil {
+		t.Fatalf("Scope() error = %v", err)
+	}
+
+	key, _ := NewTicketKey("JMD-1")
+	now := time.Now()
+	ticket := func(assignee, issueType string, labels ...string) *Ticket {
+		t := NewTicket(key, "Ticket", now, now)
+		t.Assignee = assignee
+		t.IssueType = issueType
+		t.Labels = labels
+		return t
+	}
+
+	tests := []struct {
+		name   string
+		ticket *Ticket
+		want   bool
+	}{
+		{"in scope without labels", ticket("me@example.com", "Bug"), true},
+		{"in scope with other labels", ticket("me@example.com", "Story", "backend"), true},
+		{"assigned to someone else", ticket("you@example.com", "Bug"), false},
+		{"other issue type", ticket("me@example.com", "Task"), false},
+		{"excluded label", ticket("me@example.com", "Bug", "backend", "wontfix"), false},
+	}
+	for _, tt := range tests {
+		if got := scope.Matches(tt.ticket); got != tt.want {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
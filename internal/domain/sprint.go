@@ -0,0 +1,80 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SprintState is the lifecycle state of a Jira sprint.
+type SprintState string
+
+const (
+	// SprintFuture is a planned sprint that has not started
+	SprintFuture SprintState = "future"
+
+	// SprintActive is a sprint in progress
+	SprintActive SprintState = "active"
+
+	// SprintClosed is a completed sprint
+	SprintClosed SprintState = "closed"
+)
+
+// Sprint is a Jira Software sprint of a board.
+type Sprint struct {
+	// ID is Jira's sprint ID, as used in JQL (sprint IN (...))
+	ID int
+
+	// Name is the sprint name (e.g., "JMD Sprint 12")
+	Name string
+
+	// State is where the sprint is in its lifecycle
+	State SprintState
+
+	// BoardID is the board the sprint was created on
+	BoardID int
+
+	// Start and End are when the sprint started and is planned to end (zero if not set)
+	Start time.Time
+	End   time.Time
+}
+
+// SprintScope limits syncing to the tickets in the active sprints of a board, so the
+// local files follow the current sprint: a sprint that starts is picked up on the next
+// sync, and tickets that roll out of the sprint are archived.
+type SprintScope struct {
+	// BoardID is the Jira Software board whose active sprints are synced
+	// (zero disables sprint scoping)
+	BoardID int
+
+	// ArchiveDir is where the markdown of tickets that left the sprint is moved
+	ArchiveDir string
+}
+
+// Enabled returns true if syncing is limited to a board's active sprints.
+func (s SprintScope) Enabled() bool {
+	return s.BoardID != 0
+}
+
+// SprintJQL returns the JQL clause selecting the tickets of sprints, or "" if there are none.
+func SprintJQL(sprints []*Sprint) string {
+	if len(sprints) == 0 {
+		return ""
+	}
+	ids := make([]string, 0, len(sprints))
+	for _, sprint := range sprints {
+		ids = append(ids, strconv.Itoa(sprint.ID))
+	}
+	return "sprint IN (" + strings.Join(ids, ", ") + ")"
+}
+
+// SprintNames returns the names of sprints joined for messages.
+func SprintNames(sprints []*Sprint) string {
+	names := make([]string, 0, len(sprints))
+	for _, sprint := range sprints {
+		names = append(names, sprint.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "testing"
+
+func TestSprintJQL(t *testing.T) {
+	if got := SprintJQL(nil); got != "" {
+		t.Errorf("SprintJQL(nil) = %q, want empty", got)
+	}
+
+	sprints := []*Sprint{
+		{ID: 12, Name: "Sprint 12", State: SprintActive},
+		{ID: 40, Name: "Ops 3", State: SprintActive},
+	}
+	if got, want := SprintJQL(sprints), "sprint IN (12, 40)"; got != want {
+		t.Errorf("SprintJQL() = %q, want %q", got, want)
+	}
+	if got, want := SprintNames(sprints), "Sprint 12, Ops 3"; got != want {
+		t.Errorf("SprintNames() = %q, want %q", got, want)
+	}
+}
+
+func TestSprintScope_Enabled(t *testing.T) {
+	if (SprintScope{}).Enabled() {
+		t.Error("zero SprintScope is enabled")
+	}
+	if !(SprintScope{BoardID: 7}).Enabled() {
+		t.Error("SprintScope with a board is not enabled")
+	}
+}
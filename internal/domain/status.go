@@ -0,0 +1,116 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StatusMap translates between the status names of a Jira instance, which may be
+// localized or customized (e.g. "In Bearbeitung"), and the names used in ticket files
+// and commands (e.g. "In Progress"). Pulls store the local name; transition pushes send
+// the Jira name. Names match case-insensitively. The zero value uses Jira's names as is.
+type StatusMap struct {
+	// Names maps Jira status names to local names
+	Names map[string]string
+
+	// Aliases maps other names accepted for a status, e.g. "wip", to its local name
+	Aliases map[string]string
+}
+
+// IsZero returns true if no names are mapped and no aliases are set.
+func (m StatusMap) IsZero() bool {
+	return len(m.Names) == 0 && len(m.Aliases) == 0
+}
+
+// ToLocal returns the local name of the Jira status jiraName.
+func (m StatusMap) ToLocal(jiraName string) string {
+	if local, ok := lookupName(m.Names, jiraName); ok {
+		return local
+	}
+	return jiraName
+}
+
+// Resolve returns the local name of a status given by its local name or an alias.
+func (m StatusMap) Resolve(name string) string {
+	name = strings.TrimSpace(name)
+	if local, ok := lookupName(m.Aliases, name); ok {
+		return local
+	}
+	return name
+}
+
+// ToJira returns the Jira name of a status given by its local name or an alias.
+func (m StatusMap) ToJira(name string) string {
+	local := m.Resolve(name)
+	for jiraName, mapped := range m.Names {
+		if strings.EqualFold(mapped, local) {
+			return jiraName
+		}
+	}
+	return local
+}
+
+// Validate checks that every name is set and that each local name and alias stands for
+// one status, so statuses can be mapped back to Jira.
+func (m StatusMap) Validate() error {
+	var problems []string
+	locals := make(map[string]string, len(m.Names))
+	for _, jiraName := range sortedNames(m.Names) {
+		local := m.Names[jiraName]
+		if strings.TrimSpace(jiraName) == "" || strings.TrimSpace(local) == "" {
+			problems = append(problems, fmt.Sprintf("%q: %q maps an empty status name", jiraName, local))
+			continue
+		}
+		if other, ok := locals[strings.ToLower(local)]; ok {
+			problems = append(problems, fmt.Sprintf("%q and %q both map to %q", other, jiraName, local))
+			continue
+		}
+		locals[strings.ToLower(local)] = jiraName
+	}
+
+	aliases := make(map[string]string, len(m.Aliases))
+	for _, alias := range sortedNames(m.Aliases) {
+		local := m.Aliases[alias]
+		if strings.TrimSpace(alias) == "" || strings.TrimSpace(local) == "" {
+			problems = append(problems, fmt.Sprintf("alias %q: %q has an empty status name", alias, local))
+			continue
+		}
+		if other, ok := aliases[strings.ToLower(alias)]; ok {
+			problems = append(problems, fmt.Sprintf("aliases %q and %q differ only in case", other, alias))
+			continue
+		}
+		aliases[strings.ToLower(alias)] = alias
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// lookupName returns the value of name in names, matching case-insensitively when there
+// is no exact match.
+func lookupName(names map[string]string, name string) (string, bool) {
+	if value, ok := names[name]; ok {
+		return value, true
+	}
+	for key, value := range names {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// sortedNames returns the keys of names in order.
+func sortedNames(names map[string]string) []string {
+	keys := make([]string, 0, len(names))
+	for key := range names {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
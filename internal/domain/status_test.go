@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatusMap(t *testing.T) {
+	statuses := StatusMap{
+		Names:   map[string]string{"In Bearbeitung": "In Progress", "Erledigt": "Done"},
+		Aliases: map[string]string{"wip": "In Progress"},
+	}
+
+	tests := []struct {
+		name   string
+		got    string
+		expect string
+	}{
+		{name: "mapped to local", got: statuses.ToLocal("In Bearbeitung"), expect: "In Progress"},
+		{name: "mapped to local ignoring case", got: statuses.ToLocal("erledigt"), expect: "Done"},
+		{name: "unmapped to local", got: statuses.ToLocal("Offen"), expect: "Offen"},
+		{name: "alias resolved", got: statuses.Resolve(" WIP "), expect: "In Progress"},
+		{name: "local name resolved", got: statuses.Resolve("Done"), expect: "Done"},
+		{name: "local name to Jira", got: statuses.ToJira("done"), expect: "Erledigt"},
+		{name: "alias to Jira", got: statuses.ToJira("wip"), expect: "In Bearbeitung"},
+		{name: "unmapped to Jira", got: statuses.ToJira("Blocked"), expect: "Blocked"},
+		{name: "zero value", got: StatusMap{}.ToJira("Done"), expect: "Done"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expect {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.expect)
+		}
+	}
+}
+
+func TestStatusMap_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses StatusMap
+		wantErr  bool
+	}{
+		{name: "zero value", statuses: StatusMap{}},
+		{name: "valid", statuses: StatusMap{Names: map[string]string{"Erledigt": "Done"}, Aliases: map[string]string{"done": "Done"}}},
+		{name: "empty local name", statuses: StatusMap{Names: map[string]string{"Erledigt": ""}}, wantErr: true},
+		{name: "two Jira names for one local name", statuses: StatusMap{Names: map[string]string{"Erledigt": "Done", "Fertig": "done"}}, wantErr: true},
+		{name: "aliases differing in case", statuses: StatusMap{Aliases: map[string]string{"wip": "In Progress", "WIP": "In Progress"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := tt.statuses.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidInput", tt.name, err)
+		}
+	}
+}
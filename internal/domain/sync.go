@@ -121,7 +121,7 @@ type TicketState struct {
 	// LastSynced is when this ticket was last successfully synced
 	LastSynced SyncTimestamp
 
-	// ContentHash is the MD5 hash of the ticket content at last sync
+	// ContentHash is the Ticket ContentHash of the content at last sync
 	ContentHash string
 
 	// Status is the current sync status
@@ -232,6 +232,94 @@ func (sr *SyncResult) AddOperation(operation string) {
 	sr.OperationsPerformed = append(sr.OperationsPerformed, operation)
 }
 
+// SyncReport summarizes a single project sync run.
+// This is a value object aggregating the per-ticket SyncResults of one run.
+type SyncReport struct {
+	// ProjectKey identifies which project was synced
+	ProjectKey string
+
+	// Full indicates whether this was a full sync (as opposed to incremental)
+	Full bool
+
+	// StartedAt is when the sync run began
+	StartedAt SyncTimestamp
+
+	// FinishedAt is when the sync run ended (zero while still running)
+	FinishedAt SyncTimestamp
+
+	// Results contains the outcome for each ticket touched by the run
+	Results []*SyncResult
+
+	// Error contains the run-level error message if the sync aborted
+	Error string
+
+	// Warnings describe problems the run did not fail on but the user should know
+	// about, such as local changes that are not pushed
+	Warnings []string
+
+	// CorrelationID identifies the run in the logs and the Jira requests it made
+	// (empty when the run had none)
+	CorrelationID CorrelationID
+}
+
+// NewSyncReport creates a new SyncReport for a run starting now.
+func NewSyncReport(projectKey string, full bool) *SyncReport {
+	return &SyncReport{
+		ProjectKey: strings.TrimSpace(projectKey),
+		Full:       full,
+		StartedAt:  NewSyncTimestamp(time.Now()),
+		Results:    make([]*SyncResult, 0),
+	}
+}
+
+// AddResult appends a per-ticket result to the report.
+func (r *SyncReport) AddResult(result *SyncResult) {
+	if result == nil {
+		return
+	}
+	r.Results = append(r.Results, result)
+}
+
+// Warn records a warning for the user.
+func (r *SyncReport) Warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Finish marks the run as complete, recording a run-level error if any.
+func (r *SyncReport) Finish(err error) {
+	r.FinishedAt = NewSyncTimestamp(time.Now())
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// Succeeded returns the number of tickets that synced successfully.
+func (r *SyncReport) Succeeded() int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Success {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns the number of tickets that failed to sync.
+func (r *SyncReport) Failed() int {
+	return len(r.Results) - r.Succeeded()
+}
+
+// Conflicts returns the number of tickets with detected conflicts.
+func (r *SyncReport) Conflicts() int {
+	count := 0
+	for _, result := range r.Results {
+		if result.ConflictDetected {
+			count++
+		}
+	}
+	return count
+}
+
 // OperationType defines the type of pending operation.
 type OperationType string
 
@@ -250,6 +338,10 @@ const (
 
 	// OpPullComments indicates a comments pull operation
 	OpPullComments OperationType = "pull_comments"
+
+	// OpCreateTicket indicates a ticket creation operation.
+	// The ticket key is unknown until Jira assigns one, so these operations have a zero TicketKey.
+	OpCreateTicket OperationType = "create_ticket"
 )
 
 // PendingOperation represents a queued sync operation that needs to be performed.
@@ -261,7 +353,7 @@ type PendingOperation struct {
 	// ProjectKey identifies which project this operation belongs to
 	ProjectKey string
 
-	// TicketKey identifies which ticket this operation affects
+	// TicketKey identifies which ticket this operation affects (zero for OpCreateTicket)
 	TicketKey TicketKey
 
 	// Operation specifies what type of operation to perform
@@ -278,22 +370,44 @@ type PendingOperation struct {
 
 	// LastError contains the error from the last attempt (if any)
 	LastError string
+
+	// LastAttemptAt is when the operation last failed (zero if it has not been attempted)
+	LastAttemptAt SyncTimestamp
+
+	// Failed is set when the last attempt failed with an error the retry policy
+	// does not retry, so the operation is given up on regardless of Attempts
+	Failed bool
+
+	// CompletedAt is when the operation was applied to Jira (zero while still queued)
+	CompletedAt SyncTimestamp
+
+	// Author is the person who made the local change, when several people edit the
+	// markdown directory (empty when unknown)
+	Author string
+
+	// CorrelationID traces the operation from the change that queued it to the Jira
+	// requests that push it (see CorrelationID)
+	CorrelationID CorrelationID
 }
 
 // NewPendingOperation creates a new pending operation.
+// A ticket key is required for every operation except OpCreateTicket, which must not have one.
 func NewPendingOperation(projectKey string, ticketKey TicketKey, operation OperationType, payload string) (*PendingOperation, error) {
 	projectKey = strings.TrimSpace(projectKey)
 	if projectKey == "" {
 		return nil, fmt.Errorf("%w: project key is required", ErrEmptyKey)
 	}
-	if ticketKey.IsZero() {
-		return nil, fmt.Errorf("%w: ticket key is required", ErrInvalidTicketKey)
-	}
 
 	// Validate operation type
 	switch operation {
 	case OpPushStatus, OpPushField, OpPostComment, OpPullTicket, OpPullComments:
-		// Valid
+		if ticketKey.IsZero() {
+			return nil, fmt.Errorf("%w: ticket key is required", ErrInvalidTicketKey)
+		}
+	case OpCreateTicket:
+		if !ticketKey.IsZero() {
+			return nil, fmt.Errorf("%w: ticket key is assigned by Jira on creation", ErrInvalidInput)
+		}
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrInvalidOperation, operation)
 	}
@@ -306,6 +420,8 @@ func NewPendingOperation(projectKey string, ticketKey TicketKey, operation Opera
 		CreatedAt:  NewSyncTimestamp(time.Now()),
 		Attempts:   0,
 		LastError:  "",
+
+		CorrelationID: NewCorrelationID(),
 	}, nil
 }
 
@@ -317,9 +433,43 @@ func (po *PendingOperation) RecordAttempt(err error) {
 	}
 }
 
-// ShouldRetry determines if this operation should be retried.
-// Returns true if attempts < max retries (currently 3).
+// Complete marks the operation as applied to Jira at the given time.
+// Completed operations leave the queue but are kept until pruned by retention.
+func (po *PendingOperation) Complete(at time.Time) {
+	po.CompletedAt = NewSyncTimestamp(at)
+	po.LastError = ""
+}
+
+// IsCompleted reports whether the operation has been applied to Jira.
+func (po *PendingOperation) IsCompleted() bool {
+	return !po.CompletedAt.IsZero()
+}
+
+// RecordFailure records a failed attempt at the given time. If policy does not retry
+// errors like err (see RetryPolicy.Retryable), the operation is marked Failed so no
+// further attempts are made.
+func (po *PendingOperation) RecordFailure(err error, at time.Time, policy RetryPolicy) {
+	po.RecordAttempt(err)
+	po.LastAttemptAt = NewSyncTimestamp(at)
+	po.Failed = !policy.Retryable(err)
+}
+
+// ShouldRetry determines if this operation should be retried under DefaultRetryPolicy.
+// Returns true if attempts < max retries (3) and the operation has not failed permanently.
 func (po *PendingOperation) ShouldRetry() bool {
-	const maxRetries = 3
-	return po.Attempts < maxRetries
+	return po.ShouldRetryWith(DefaultRetryPolicy())
+}
+
+// ShouldRetryWith determines if this operation should be retried under policy.
+func (po *PendingOperation) ShouldRetryWith(policy RetryPolicy) bool {
+	return !po.Failed && !po.IsCompleted() && po.Attempts < policy.MaxAttempts
+}
+
+// NextAttemptAt returns the earliest time the operation should be attempted again under
+// policy. An operation that has not failed yet can be attempted right away (zero time).
+func (po *PendingOperation) NextAttemptAt(policy RetryPolicy) time.Time {
+	if po.LastAttemptAt.IsZero() {
+		return time.Time{}
+	}
+	return po.LastAttemptAt.Time().Add(policy.Backoff(po.Attempts))
 }
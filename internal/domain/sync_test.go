@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -354,6 +355,87 @@ func TestSyncResult_AddOperation(t *testing.T) {
 	}
 }
 
+func TestSyncReport(t *testing.T) {
+	report := NewSyncReport(" JMD ", true)
+
+	if report.ProjectKey != "JMD" {
+		t.Errorf("ProjectKey = %q, want JMD", report.ProjectKey)
+	}
+	if !report.Full {
+		t.Error("Full should be true")
+	}
+	if report.StartedAt.IsZero() {
+		t.Error("StartedAt should be set")
+	}
+	if !report.FinishedAt.IsZero() {
+		t.Error("FinishedAt should be zero before Finish")
+	}
+
+	key1, _ := NewTicketKey("JMD-1")
+	key2, _ := NewTicketKey("JMD-2")
+	key3, _ := NewTicketKey("JMD-3")
+
+	ok := NewSyncResult(key1)
+	failed := NewSyncResult(key2)
+	failed.MarkFailed(ErrUnauthorized)
+	conflicted := NewSyncResult(key3)
+	conflicted.MarkConflict()
+
+	report.AddResult(ok)
+	report.AddResult(failed)
+	report.AddResult(conflicted)
+	report.AddResult(nil)
+
+	if len(report.Results) != 3 {
+		t.Errorf("Results length = %d, want 3", len(report.Results))
+	}
+	if report.Succeeded() != 2 {
+		t.Errorf("Succeeded() = %d, want 2", report.Succeeded())
+	}
+	if report.Failed() != 1 {
+		t.Errorf("Failed() = %d, want 1", report.Failed())
+	}
+	if report.Conflicts() != 1 {
+		t.Errorf("Conflicts() = %d, want 1", report.Conflicts())
+	}
+
+	report.Warn("%d tickets have local changes", 2)
+	if len(report.Warnings) != 1 || report.Warnings[0] != "2 tickets have local changes" {
+		t.Errorf("Warnings = %v, want the formatted warning", report.Warnings)
+	}
+
+	report.Finish(ErrSyncConflict)
+	if report.FinishedAt.IsZero() {
+		t.Error("FinishedAt should be set after Finish")
+	}
+	if report.Error != ErrSyncConflict.Error() {
+		t.Errorf("Error = %q, want %q", report.Error, ErrSyncConflict.Error())
+	}
+}
+
+func TestSyncMode(t *testing.T) {
+	tests := []struct {
+		mode             SyncMode
+		canPull, canPush bool
+	}{
+		{SyncModeBidirectional, true, true},
+		{"", true, true},
+		{SyncModePullOnly, true, false},
+		{SyncModePushOnly, false, true},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.CanPull(); got != tt.canPull {
+			t.Errorf("%q.CanPull() = %v, want %v", tt.mode, got, tt.canPull)
+		}
+		if got := tt.mode.CanPush(); got != tt.canPush {
+			t.Errorf("%q.CanPush() = %v, want %v", tt.mode, got, tt.canPush)
+		}
+	}
+	if SyncMode("read_only").IsValid() {
+		t.Error("read_only.IsValid() = true")
+	}
+}
+
 func TestNewPendingOperation(t *testing.T) {
 	key, _ := NewTicketKey("JMD-123")
 
@@ -397,6 +479,22 @@ func TestNewPendingOperation(t *testing.T) {
 			payload:    "{}",
 			wantErr:    true,
 		},
+		{
+			name:       "create ticket without key",
+			projectKey: "JMD",
+			ticketKey:  TicketKey{},
+			operation:  OpCreateTicket,
+			payload:    `{"summary":"New"}`,
+			wantErr:    false,
+		},
+		{
+			name:       "create ticket with key",
+			projectKey: "JMD",
+			ticketKey:  key,
+			operation:  OpCreateTicket,
+			payload:    `{"summary":"New"}`,
+			wantErr:    true,
+		},
 		{
 			name:       "invalid operation type",
 			projectKey: "JMD",
@@ -455,6 +553,29 @@ func TestPendingOperation_RecordAttempt(t *testing.T) {
 	}
 }
 
+func TestPendingOperation_Complete(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	po, _ := NewPendingOperation("JMD", key, OpPushStatus, "{}")
+	po.RecordAttempt(ErrSyncConflict)
+
+	if po.IsCompleted() {
+		t.Fatal("IsCompleted() should be false for a queued operation")
+	}
+
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	po.Complete(at)
+
+	if !po.IsCompleted() {
+		t.Error("IsCompleted() should be true after Complete()")
+	}
+	if !po.CompletedAt.Time().Equal(at) {
+		t.Errorf("CompletedAt = %v, want %v", po.CompletedAt.Time(), at)
+	}
+	if po.LastError != "" {
+		t.Errorf("LastError = %q, want it cleared", po.LastError)
+	}
+}
+
 func TestPendingOperation_ShouldRetry(t *testing.T) {
 	key, _ := NewTicketKey("JMD-123")
 	po, _ := NewPendingOperation("JMD", key, OpPushStatus, "{}")
@@ -480,3 +601,39 @@ func TestPendingOperation_ShouldRetry(t *testing.T) {
 		t.Error("ShouldRetry() should be false after 3 attempts")
 	}
 }
+
+func TestPendingOperation_RecordFailure(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	policy := DefaultRetryPolicy()
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	po, _ := NewPendingOperation("JMD", key, OpPushStatus, "{}")
+	if !po.NextAttemptAt(policy).IsZero() {
+		t.Error("NextAttemptAt() should be zero before the first attempt")
+	}
+
+	po.RecordFailure(fmt.Errorf("%w: jira returned 503", ErrUnavailable), at, policy)
+	if po.Failed || !po.ShouldRetryWith(policy) {
+		t.Error("server errors should be retried")
+	}
+	if got, want := po.NextAttemptAt(policy), at.Add(policy.InitialBackoff); !got.Equal(want) {
+		t.Errorf("NextAttemptAt() = %v, want %v", got, want)
+	}
+
+	// Permanent errors give up without using the remaining attempts
+	po.RecordFailure(fmt.Errorf("%w: field 'priority' is invalid", ErrInvalidInput), at, policy)
+	if !po.Failed || po.ShouldRetryWith(policy) || po.ShouldRetry() {
+		t.Error("permanent errors should not be retried")
+	}
+	if po.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", po.Attempts)
+	}
+
+	// Error classes left out of RetryOn are not retried either
+	po, _ = NewPendingOperation("JMD", key, OpPushStatus, "{}")
+	policy.RetryOn = []ErrorClass{ErrorClassServer}
+	po.RecordFailure(fmt.Errorf("%w: too many requests", ErrRateLimited), at, policy)
+	if po.ShouldRetryWith(policy) {
+		t.Error("rate limit errors should not be retried when only server errors are")
+	}
+}
@@ -4,12 +4,17 @@ package domain
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // ticketKeyPattern defines the valid format for Jira ticket keys (PROJECT-123)
@@ -52,11 +57,48 @@ func (tk TicketKey) ProjectKey() string {
 	return ""
 }
 
+// Compare orders keys by project, then numerically (JMD-2 before JMD-10), returning a
+// negative number, zero, or a positive number.
+func (tk TicketKey) Compare(other TicketKey) int {
+	if c := strings.Compare(tk.ProjectKey(), other.ProjectKey()); c != 0 {
+		return c
+	}
+	return tk.number() - other.number()
+}
+
+// number returns the numeric part of the ticket key.
+func (tk TicketKey) number() int {
+	n, _ := strconv.Atoi(tk.value[strings.LastIndex(tk.value, "-")+1:])
+	return n
+}
+
 // IsZero returns true if this is the zero value (empty ticket key).
 func (tk TicketKey) IsZero() bool {
 	return tk.value == ""
 }
 
+// BrowseURL returns the address of the ticket's page on the Jira site at baseURL.
+func (tk TicketKey) BrowseURL(baseURL string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/browse/" + tk.value
+}
+
+// AdhocDir is the directory, under the markdown directory, that tickets pulled ad hoc
+// from outside the sync scope are written to.
+const AdhocDir = "adhoc"
+
+// WatchlistDir is the directory, under the markdown directory, that the tickets of the
+// watch list are written to.
+const WatchlistDir = "watchlist"
+
+// FilePath returns where a new markdown file of the ticket is written in dir (e.g.
+// AdhocDir, or "" for the top), relative to the markdown directory with forward slashes.
+func (tk TicketKey) FilePath(dir string) string {
+	if dir == "" {
+		return tk.value + ".md"
+	}
+	return dir + "/" + tk.value + ".md"
+}
+
 // Ticket represents a Jira ticket entity.
 // This is a core domain entity (aggregate root) that encapsulates ticket state and behavior.
 // Ticket has identity defined by its TicketKey and maintains its lifecycle.
@@ -96,6 +138,20 @@ type Ticket struct {
 
 	// CustomFields contains custom field values (flexible storage for extension)
 	CustomFields map[string]FieldValue
+
+	// DescriptionTruncated reports that Description holds only the start of the ticket's
+	// description, as read back from a file summarized by MarkdownConfig.LimitsFor; such
+	// a description is never pushed
+	DescriptionTruncated bool
+
+	// Comments are the ticket's comments, oldest first, when loaded with it (nil otherwise)
+	Comments []*Comment
+
+	// StatusHistory is the ticket's status changelog when loaded with it (nil otherwise)
+	StatusHistory []StatusChange
+
+	// events are the domain events recorded by mutations and not yet drained
+	events []Event
 }
 
 // NewTicket creates a new Ticket with required fields.
@@ -111,10 +167,36 @@ func NewTicket(key TicketKey, summary string, created, updated time.Time) *Ticke
 	}
 }
 
-// ContentHash computes an MD5 hash of the ticket content for conflict detection.
+// HashVersion identifies the algorithm of a ticket content hash.
+type HashVersion int
+
+const (
+	// HashMD5 is the legacy algorithm of content hashes recorded before hashes were
+	// versioned; they are still accepted until replaced
+	HashMD5 HashVersion = 1
+
+	// HashSHA256 is the algorithm of ContentHash
+	HashSHA256 HashVersion = 2
+)
+
+// CurrentHashVersion is the algorithm ContentHash uses.
+const CurrentHashVersion = HashSHA256
+
+// ContentHash computes a SHA-256 hash of the ticket content for conflict detection.
 // This includes all mutable fields that can be modified locally.
 func (t *Ticket) ContentHash() string {
-	h := md5.New()
+	return t.ContentHashWith(CurrentHashVersion)
+}
+
+// ContentHashWith computes the content hash with the algorithm of version, so content
+// can be compared with hashes recorded by older versions (HashMD5).
+func (t *Ticket) ContentHashWith(version HashVersion) string {
+	var h hash.Hash
+	if version == HashMD5 {
+		h = md5.New()
+	} else {
+		h = sha256.New()
+	}
 	// Include all fields that can be modified
 	fmt.Fprintf(h, "summary:%s\n", t.Summary)
 	fmt.Fprintf(h, "description:%s\n", t.Description)
@@ -139,6 +221,316 @@ func (t *Ticket) ContentHash() string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// Version returns an opaque token identifying this revision of the ticket in Jira.
+// Jira bumps the updated timestamp on every change, so its exact value serves as the
+// version for optimistic concurrency. Returns "" if the ticket was never fetched.
+func (t *Ticket) Version() string {
+	if t.Updated.IsZero() {
+		return ""
+	}
+	return t.Updated.UTC().Format(time.RFC3339Nano)
+}
+
+// FieldChange is the difference in one field between two revisions of a ticket.
+type FieldChange struct {
+	// Field is named as in Ticket.ChangedFields
+	Field string
+
+	// From is the value in the earlier revision and To the value in the later one;
+	// either is zero when the field is unset. Labels are a []string.
+	From, To FieldValue
+}
+
+// Diff returns the fields that differ between the ticket and base, an earlier revision
+// of it, with their values in both, sorted by field name as in ChangedFields.
+// A truncated description (see DescriptionTruncated) never differs, and labels only
+// differ when a label was added or removed (see SameLabels).
+func (t *Ticket) Diff(base *Ticket) []FieldChange {
+	var changes []FieldChange
+	for _, field := range []struct {
+		name        string
+		local, base string
+	}{
+		{"summary", t.Summary, base.Summary},
+		{"description", t.Description, base.Description},
+		{"status", t.Status, base.Status},
+		{"issuetype", t.IssueType, base.IssueType},
+		{"priority", t.Priority, base.Priority},
+		{"assignee", t.Assignee, base.Assignee},
+	} {
+		if field.name == "description" && (t.DescriptionTruncated || base.DescriptionTruncated) {
+			continue
+		}
+		if field.local != field.base {
+			changes = append(changes, FieldChange{Field: field.name, From: NewFieldValue(field.base), To: NewFieldValue(field.local)})
+		}
+	}
+	if !SameLabels(t.Labels, base.Labels) {
+		changes = append(changes, FieldChange{
+			Field: "labels",
+			From:  NewFieldValue(slices.Clone(base.Labels)),
+			To:    NewFieldValue(slices.Clone(t.Labels)),
+		})
+	}
+
+	for name, value := range t.CustomFields {
+		if baseValue, ok := base.CustomFields[name]; !ok || !value.Equal(baseValue) {
+			changes = append(changes, FieldChange{Field: name, From: base.CustomFields[name], To: value})
+		}
+	}
+	for name, baseValue := range base.CustomFields {
+		if _, ok := t.CustomFields[name]; !ok {
+			changes = append(changes, FieldChange{Field: name, From: baseValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// ChangedFields returns the names of the fields that differ between the ticket and base,
+// an earlier revision of it, so a push can send only what was actually edited.
+// Standard fields use their Jira names ("summary", "description", "status", "issuetype",
+// "priority", "assignee", "labels"); custom fields use their key in CustomFields. Names are sorted.
+func (t *Ticket) ChangedFields(base *Ticket) []string {
+	var changed []string
+	for _, change := range t.Diff(base) {
+		changed = append(changed, change.Field)
+	}
+	return changed
+}
+
+// KeepLocalFields sets the named fields of the ticket to their values in local, so a
+// ticket pulled from Jira does not overwrite fields that are never synced (see
+// FieldDirections.LocalOnly). Fields are named as in ChangedFields.
+func (t *Ticket) KeepLocalFields(local *Ticket, fields []string) {
+	for _, field := range fields {
+		switch field {
+		case "summary":
+			t.Summary = local.Summary
+		case "description":
+			t.Description = local.Description
+		case "status":
+			t.Status = local.Status
+		case "issuetype":
+			t.IssueType = local.IssueType
+		case "priority":
+			t.Priority = local.Priority
+		case "assignee":
+			t.Assignee = local.Assignee
+		case "labels":
+			t.Labels = slices.Clone(local.Labels)
+		default:
+			if value, ok := local.CustomFields[field]; ok {
+				if t.CustomFields == nil {
+					t.CustomFields = make(map[string]FieldValue)
+				}
+				t.CustomFields[field] = value
+			} else {
+				delete(t.CustomFields, field)
+			}
+		}
+	}
+}
+
+// ChangeStatus moves the ticket to status and records a StatusChanged event at at.
+// Returns ErrInvalidInput if status is empty or the ticket is already in it.
+func (t *Ticket) ChangeStatus(status string, at time.Time) error {
+	status = strings.TrimSpace(status)
+	if status == "" {
+		return fmt.Errorf("%w: status is required", ErrInvalidInput)
+	}
+	if strings.EqualFold(t.Status, status) {
+		return fmt.Errorf("%w: %s is already in status %q", ErrInvalidInput, t.Key, status)
+	}
+
+	from := t.Status
+	t.Status = status
+	t.record(StatusChanged{Key: t.Key, From: from, To: status, At: at.UTC()})
+	return nil
+}
+
+// ChangeField sets a standard text field, named as in ChangedFields ("summary",
+// "description", "issuetype", "priority", "assignee"), and records a FieldChanged event at at.
+// Returns ErrInvalidInput for other fields (use ChangeStatus for the status) and when
+// the field already has value.
+func (t *Ticket) ChangeField(field, value string, at time.Time) error {
+	var target *string
+	switch field {
+	case "summary":
+		target = &t.Summary
+	case "description":
+		target = &t.Description
+	case "issuetype":
+		target = &t.IssueType
+	case "priority":
+		target = &t.Priority
+	case "assignee":
+		target = &t.Assignee
+	default:
+		return fmt.Errorf("%w: %q is not a text field of a ticket", ErrInvalidInput, field)
+	}
+	if *target == value {
+		return fmt.Errorf("%w: %s already has %s %q", ErrInvalidInput, t.Key, field, value)
+	}
+
+	from := *target
+	*target = value
+	t.record(FieldChanged{Key: t.Key, Field: field, From: from, To: value, At: at.UTC()})
+	return nil
+}
+
+// EditLabels adds the labels add to the ticket and removes the labels remove, keeping its
+// other labels, and records a FieldChanged event of "labels" at at, with the labels
+// before and after joined by ", ". A label in both lists is added.
+// Returns ErrInvalidInput if the labels would not change.
+func (t *Ticket) EditLabels(add, remove []string, at time.Time) error {
+	add, remove = NormalizeLabels(add), NormalizeLabels(remove)
+	labels := slices.DeleteFunc(NormalizeLabels(t.Labels), func(label string) bool {
+		return slices.Contains(remove, label) && !slices.Contains(add, label)
+	})
+	for _, label := range add {
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	if SameLabels(t.Labels, labels) {
+		return fmt.Errorf("%w: %s already has these labels", ErrInvalidInput, t.Key)
+	}
+
+	from := strings.Join(t.Labels, ", ")
+	t.Labels = labels
+	t.record(FieldChanged{Key: t.Key, Field: "labels", From: from, To: strings.Join(labels, ", "), At: at.UTC()})
+	return nil
+}
+
+// Transition is a workflow transition that moves a ticket to another status.
+type Transition struct {
+	// Name is the transition's name in the workflow (e.g. "Start Progress")
+	Name string
+
+	// From is the status the transition leaves, or empty if it is available from any status
+	From string
+
+	// To is the status the transition leads to
+	To string
+}
+
+// ApplyTransition moves the ticket along transition, recording a StatusChanged event at at.
+// Returns ErrInvalidInput if the transition does not leave the ticket's current status
+// or leads nowhere new (see ChangeStatus).
+func (t *Ticket) ApplyTransition(transition Transition, at time.Time) error {
+	if transition.From != "" && !strings.EqualFold(transition.From, t.Status) {
+		return fmt.Errorf("%w: %s is in status %q, but transition %q leaves %q",
+			ErrInvalidInput, t.Key, t.Status, transition.Name, transition.From)
+	}
+	return t.ChangeStatus(transition.To, at)
+}
+
+// SetCustomField sets the value of a custom field, keyed by its name, and records a
+// FieldChanged event at at. A zero value clears the field. Setting the value the field
+// already has does nothing.
+// Returns ErrInvalidInput if field is not a valid custom field, and ErrInvalidFieldValue
+// if the value, or any value of a list, is not one of the field's ValidValues.
+func (t *Ticket) SetCustomField(field *CustomField, value FieldValue, at time.Time) error {
+	if field == nil {
+		return fmt.Errorf("%w: custom field is required", ErrInvalidInput)
+	}
+	if err := field.Validate(); err != nil {
+		return err
+	}
+	if !value.IsZero() {
+		values := []string{value.String()}
+		switch value.Raw().(type) {
+		case []string, []interface{}:
+			values = stringValues(value.Raw())
+		}
+		for _, v := range values {
+			if err := field.ValidateValue(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	current := t.CustomFields[field.Name]
+	if current.Equal(value) {
+		return nil
+	}
+
+	if value.IsZero() {
+		delete(t.CustomFields, field.Name)
+	} else {
+		if t.CustomFields == nil {
+			t.CustomFields = make(map[string]FieldValue)
+		}
+		t.CustomFields[field.Name] = value
+	}
+	t.record(FieldChanged{Key: t.Key, Field: field.Name, From: current.String(), To: value.String(), At: at.UTC()})
+	return nil
+}
+
+// AddComment adds comment to the ticket's comments and records a CommentAdded event.
+// Comments not yet created in Jira have no ID.
+// Returns ErrInvalidInput if the comment belongs to another ticket, has no author or
+// body, or has the ID of a comment the ticket already has.
+func (t *Ticket) AddComment(comment *Comment) error {
+	if comment == nil {
+		return fmt.Errorf("%w: comment is required", ErrInvalidInput)
+	}
+	if comment.TicketKey != t.Key {
+		return fmt.Errorf("%w: comment belongs to %s, not %s", ErrInvalidInput, comment.TicketKey, t.Key)
+	}
+	if strings.TrimSpace(comment.Author) == "" {
+		return fmt.Errorf("%w: comment author is required", ErrInvalidInput)
+	}
+	if strings.TrimSpace(comment.Body) == "" {
+		return fmt.Errorf("%w: comment body is required", ErrInvalidInput)
+	}
+	if comment.ID != "" {
+		for _, existing := range t.Comments {
+			if existing.ID == comment.ID {
+				return fmt.Errorf("%w: %s already has comment %s", ErrInvalidInput, t.Key, comment.ID)
+			}
+		}
+	}
+
+	t.Comments = append(t.Comments, comment)
+	t.record(CommentAdded{Key: t.Key, CommentID: comment.ID, Author: comment.Author, At: comment.Created.UTC()})
+	return nil
+}
+
+// FixVersionsField is the CustomFields key of the names of the releases a ticket is
+// fixed in (Jira's fixVersions), a list of strings.
+const FixVersionsField = "fix_versions"
+
+// FixVersions returns the names of the releases the ticket is fixed in.
+func (t *Ticket) FixVersions() []string {
+	return stringValues(t.CustomFields[FixVersionsField].Raw())
+}
+
+// JiraFieldsField is the CustomFields key of the raw values of the Jira fields jiramd
+// does not map, keyed by field name: a map decoded from Jira's JSON, only set when raw
+// fields are enabled (see MarkdownConfig.RawFields). It is read-only.
+const JiraFieldsField = "jira_fields"
+
+// JiraFields returns the raw values of the Jira fields jiramd does not map, keyed by
+// field name, or nil if they were not fetched.
+func (t *Ticket) JiraFields() map[string]interface{} {
+	fields, _ := t.CustomFields[JiraFieldsField].Raw().(map[string]interface{})
+	return fields
+}
+
+// HasFixVersion returns true if the ticket is fixed in the named release.
+func (t *Ticket) HasFixVersion(version string) bool {
+	version = strings.TrimSpace(version)
+	for _, name := range t.FixVersions() {
+		if strings.TrimSpace(name) == version {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate checks if the ticket has all required fields populated.
 func (t *Ticket) Validate() error {
 	if t.Key.IsZero() {
@@ -155,3 +547,57 @@ func (t *Ticket) Validate() error {
 	}
 	return nil
 }
+
+// MaxSummaryLength is the longest summary Jira accepts.
+const MaxSummaryLength = 255
+
+// TicketDraft is a ticket that has not been created in Jira yet.
+// It has no key or timestamps until Jira creates it.
+type TicketDraft struct {
+	// ProjectKey is the project to create the ticket in
+	ProjectKey string
+
+	// Summary is the ticket title (single line, at most MaxSummaryLength characters)
+	Summary string
+
+	// IssueType is the issue type name (e.g., "Task", "Bug")
+	IssueType string
+
+	// Description is the ticket description text
+	Description string
+
+	// Priority is the priority name (optional)
+	Priority string
+
+	// Labels are the labels to create the ticket with
+	Labels []string
+
+	// Source describes where the draft came from (e.g., an imported file path)
+	Source string
+}
+
+// Validate checks that the draft can be created in Jira.
+func (d *TicketDraft) Validate() error {
+	if !projectKeyPattern.MatchString(d.ProjectKey) {
+		return fmt.Errorf("%w: project key '%s' (expected format: 2-10 uppercase letters/numbers)", ErrInvalidProject, d.ProjectKey)
+	}
+	summary := strings.TrimSpace(d.Summary)
+	if summary == "" {
+		return fmt.Errorf("%w: ticket summary is required", ErrInvalidInput)
+	}
+	if strings.ContainsAny(summary, "\r\n") {
+		return fmt.Errorf("%w: ticket summary must be a single line", ErrInvalidInput)
+	}
+	if n := len([]rune(summary)); n > MaxSummaryLength {
+		return fmt.Errorf("%w: ticket summary is %d characters (at most %d allowed)", ErrInvalidInput, n, MaxSummaryLength)
+	}
+	if strings.TrimSpace(d.IssueType) == "" {
+		return fmt.Errorf("%w: issue type is required", ErrInvalidInput)
+	}
+	for _, label := range d.Labels {
+		if label == "" || strings.ContainsFunc(label, unicode.IsSpace) {
+			return fmt.Errorf("%w: label %q must be non-empty and contain no spaces", ErrInvalidInput, label)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -140,6 +142,25 @@ func TestTicketKey_IsZero(t *testing.T) {
 	}
 }
 
+func TestTicketKey_BrowseURL(t *testing.T) {
+	key, _ := NewTicketKey("JMD-42")
+	for _, baseURL := range []string{"https://example.atlassian.net", "https://example.atlassian.net/"} {
+		if got, want := key.BrowseURL(baseURL), "https://example.atlassian.net/browse/JMD-42"; got != want {
+			t.Errorf("BrowseURL(%q) = %q, want %q", baseURL, got, want)
+		}
+	}
+}
+
+func TestTicketKey_FilePath(t *testing.T) {
+	key, _ := NewTicketKey("OPS-7")
+	if got := key.FilePath(""); got != "OPS-7.md" {
+		t.Errorf("FilePath() = %q, want OPS-7.md", got)
+	}
+	if got := key.FilePath(WatchlistDir); got != "watchlist/OPS-7.md" {
+		t.Errorf("FilePath(WatchlistDir) = %q, want watchlist/OPS-7.md", got)
+	}
+}
+
 func TestNewTicket(t *testing.T) {
 	key, _ := NewTicketKey("JMD-123")
 	created := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
@@ -276,9 +297,311 @@ func TestTicket_ContentHash(t *testing.T) {
 		t.Error("Different tickets should have different hashes")
 	}
 
-	// Hash should be 32 hex characters (MD5)
-	if len(hash1) != 32 {
-		t.Errorf("Hash length = %d, want 32", len(hash1))
+	// Hash should be 64 hex characters (SHA-256)
+	if len(hash1) != 64 {
+		t.Errorf("Hash length = %d, want 64", len(hash1))
+	}
+
+	// Legacy hashes are 32 hex characters (MD5)
+	if legacy := ticket1.ContentHashWith(HashMD5); len(legacy) != 32 || legacy == hash1 {
+		t.Errorf("ContentHashWith(HashMD5) = %q, want a 32 character MD5 hash", legacy)
+	}
+}
+
+func TestTicket_Version(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	updated := time.Date(2025, 3, 1, 12, 30, 0, 123000000, time.FixedZone("CET", 3600))
+
+	ticket := NewTicket(key, "Test", updated, updated)
+	if got := ticket.Version(); got != "2025-03-01T11:30:00.123Z" {
+		t.Errorf("Version() = %q, want %q", got, "2025-03-01T11:30:00.123Z")
+	}
+
+	ticket.Updated = updated.Add(time.Millisecond)
+	if ticket.Version() == "2025-03-01T11:30:00.123Z" {
+		t.Error("Version() should change when the ticket is updated")
+	}
+
+	ticket.Updated = time.Time{}
+	if got := ticket.Version(); got != "" {
+		t.Errorf("Version() = %q, want empty for a ticket never fetched", got)
+	}
+}
+
+func TestTicket_ChangedFields(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	now := time.Now()
+
+	base := NewTicket(key, "Test", now, now)
+	base.Priority = "High"
+	base.CustomFields["story_points"] = NewFieldValue(3)
+	base.CustomFields["team"] = NewFieldValue("core")
+
+	local := NewTicket(key, "Test", now, now)
+	local.Priority = "High"
+	local.CustomFields["story_points"] = NewFieldValue(3)
+	local.CustomFields["team"] = NewFieldValue("core")
+	local.Labels = nil
+
+	if changed := local.ChangedFields(base); len(changed) != 0 {
+		t.Errorf("ChangedFields() = %v, want none for an unchanged ticket", changed)
+	}
+
+	local.Summary = "Edited"
+	local.Labels = []string{"backend"}
+	local.CustomFields["story_points"] = NewFieldValue(5)
+	delete(local.CustomFields, "team")
+
+	want := []string{"labels", "story_points", "summary", "team"}
+	changed := local.ChangedFields(base)
+	if len(changed) != len(want) {
+		t.Fatalf("ChangedFields() = %v, want %v", changed, want)
+	}
+	for i := range want {
+		if changed[i] != want[i] {
+			t.Fatalf("ChangedFields() = %v, want %v", changed, want)
+		}
+	}
+}
+
+func TestTicket_KeepLocalFields(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	now := time.Now()
+
+	local := NewTicket(key, "Local summary", now, now)
+	local.Labels = []string{"mine"}
+	local.CustomFields["notes"] = NewFieldValue("local notes")
+
+	pulled := NewTicket(key, "Jira summary", now, now)
+	pulled.Labels = []string{"theirs"}
+	pulled.CustomFields["notes"] = NewFieldValue("jira notes")
+
+	pulled.KeepLocalFields(local, []string{"labels", "notes"})
+
+	if pulled.Summary != "Jira summary" {
+		t.Errorf("Summary = %q, want the pulled value", pulled.Summary)
+	}
+	if len(pulled.Labels) != 1 || pulled.Labels[0] != "mine" {
+		t.Errorf("Labels = %v, want the local labels", pulled.Labels)
+	}
+	if got := pulled.CustomFields["notes"].String(); got != "local notes" {
+		t.Errorf("notes = %q, want the local value", got)
+	}
+}
+
+func TestTicket_Diff(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	now := time.Now()
+
+	base := NewTicket(key, "Test", now, now)
+	base.Status = "To Do"
+	base.IssueType = "Story"
+	base.CustomFields["team"] = NewFieldValue("core")
+
+	local := NewTicket(key, "Test", now, now)
+	local.Status = "Done"
+	local.IssueType = "Task"
+	local.Labels = []string{"backend"}
+	local.CustomFields["story_points"] = NewFieldValue(5)
+
+	changes := local.Diff(base)
+	want := []struct {
+		field    string
+		from, to string
+	}{
+		{"issuetype", "Story", "Task"},
+		{"labels", "[]", "[backend]"},
+		{"status", "To Do", "Done"},
+		{"story_points", "", "5"},
+		{"team", "core", ""},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Diff() = %v, want %v", changes, want)
+	}
+	for i, w := range want {
+		c := changes[i]
+		if c.Field != w.field || c.From.String() != w.from || c.To.String() != w.to {
+			t.Errorf("change %d = %s %q -> %q, want %s %q -> %q", i, c.Field, c.From, c.To, w.field, w.from, w.to)
+		}
+	}
+	if !changes[4].To.IsZero() {
+		t.Errorf("removed field To = %v, want zero", changes[4].To.Raw())
+	}
+}
+
+func TestTicket_Diff_TruncatedDescription(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	now := time.Now()
+
+	base := NewTicket(key, "Test", now, now)
+	base.Description = "The whole description, as it is in Jira."
+
+	local := NewTicket(key, "Test", now, now)
+	local.Description = "The whole description"
+	local.DescriptionTruncated = true
+	if changes := local.Diff(base); len(changes) != 0 {
+		t.Errorf("Diff() = %v, want a truncated description never to differ", changes)
+	}
+
+	local.Summary = "Edited"
+	if changed := local.ChangedFields(base); len(changed) != 1 || changed[0] != "summary" {
+		t.Errorf("ChangedFields() = %v, want [summary]", changed)
+	}
+}
+
+func TestTicket_ApplyTransition(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		transition Transition
+		wantStatus string
+		wantErr    bool
+	}{
+		{name: "from current status", transition: Transition{Name: "Start", From: "to do", To: "In Progress"}, wantStatus: "In Progress"},
+		{name: "global transition", transition: Transition{Name: "Close", To: "Done"}, wantStatus: "Done"},
+		{name: "from another status", transition: Transition{Name: "Reopen", From: "Done", To: "To Do"}, wantStatus: "To Do", wantErr: true},
+		{name: "to current status", transition: Transition{Name: "Reset", To: "To Do"}, wantStatus: "To Do", wantErr: true},
+		{name: "to no status", transition: Transition{Name: "Broken"}, wantStatus: "To Do", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := NewTicket(key, "Test", now, now)
+			ticket.Status = "To Do"
+
+			err := ticket.ApplyTransition(tt.transition, now)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("ApplyTransition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("ApplyTransition() error = %v, want ErrInvalidInput", err)
+			}
+			if ticket.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", ticket.Status, tt.wantStatus)
+			}
+			if wantEvents := len(ticket.Events()) == 1; wantEvents == tt.wantErr {
+				t.Errorf("Events() = %v", ticket.Events())
+			}
+		})
+	}
+}
+
+func TestTicket_EditLabels(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		add, remove []string
+		wantLabels  string
+		wantErr     bool
+	}{
+		{name: "add", add: []string{"api", " ui "}, wantLabels: "backend, urgent, api, ui"},
+		{name: "remove", remove: []string{"urgent", "missing"}, wantLabels: "backend"},
+		{name: "add and remove", add: []string{"api"}, remove: []string{"backend"}, wantLabels: "urgent, api"},
+		{name: "label in both lists is added", add: []string{"urgent", "api"}, remove: []string{"urgent"}, wantLabels: "backend, urgent, api"},
+		{name: "already labeled", add: []string{"backend"}, remove: []string{"missing"}, wantLabels: "backend, urgent", wantErr: true},
+		{name: "nothing to change", wantLabels: "backend, urgent", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket := NewTicket(key, "Test", now, now)
+			ticket.Labels = []string{"backend", "urgent"}
+
+			err := ticket.EditLabels(tt.add, tt.remove, now)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("EditLabels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("EditLabels() error = %v, want ErrInvalidInput", err)
+			}
+			if got := strings.Join(ticket.Labels, ", "); got != tt.wantLabels {
+				t.Errorf("Labels = %q, want %q", got, tt.wantLabels)
+			}
+
+			events := ticket.Events()
+			if tt.wantErr {
+				if len(events) != 0 {
+					t.Errorf("Events() = %v, want none", events)
+				}
+				return
+			}
+			want := FieldChanged{Key: key, Field: "labels", From: "backend, urgent", To: tt.wantLabels, At: now.UTC()}
+			if len(events) != 1 || events[0] != want {
+				t.Errorf("Events() = %v, want %v", events, want)
+			}
+		})
+	}
+}
+
+func TestTicket_SetCustomField(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	now := time.Now()
+	team := &CustomField{
+		Name:          "team",
+		DisplayName:   "Team",
+		Source:        "customfield_10010",
+		ValidValues:   []string{"core", "web"},
+		SyncDirection: SyncBidirectional,
+	}
+
+	ticket := NewTicket(key, "Test", now, now)
+	if err := ticket.SetCustomField(team, NewFieldValue("core"), now); err != nil {
+		t.Fatalf("SetCustomField() error = %v", err)
+	}
+	if err := ticket.SetCustomField(team, NewFieldValue([]string{"core", "web"}), now); err != nil {
+		t.Fatalf("SetCustomField() list error = %v", err)
+	}
+	if err := ticket.SetCustomField(team, NewFieldValue([]string{"core", "web"}), now); err != nil {
+		t.Fatalf("SetCustomField() same value error = %v", err)
+	}
+	if err := ticket.SetCustomField(team, NewFieldValue("mobile"), now); !errors.Is(err, ErrInvalidFieldValue) {
+		t.Errorf("SetCustomField() invalid value error = %v, want ErrInvalidFieldValue", err)
+	}
+	if err := ticket.SetCustomField(team, NewFieldValue([]string{"core", "mobile"}), now); !errors.Is(err, ErrInvalidFieldValue) {
+		t.Errorf("SetCustomField() invalid list value error = %v, want ErrInvalidFieldValue", err)
+	}
+	if err := ticket.SetCustomField(&CustomField{Name: "team"}, NewFieldValue("core"), now); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("SetCustomField() invalid field error = %v, want ErrInvalidInput", err)
+	}
+	if err := ticket.SetCustomField(team, FieldValue{}, now); err != nil {
+		t.Fatalf("SetCustomField() clear error = %v", err)
+	}
+
+	if _, ok := ticket.CustomFields["team"]; ok {
+		t.Errorf("CustomFields[team] = %v, want cleared", ticket.CustomFields["team"])
+	}
+	// Setting the same value again and rejected values record nothing
+	if events := ticket.Events(); len(events) != 3 {
+		t.Errorf("Events() = %v, want 3", events)
+	}
+}
+
+func TestTicket_AddComment(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	now := time.Now()
+	ticket := NewTicket(key, "Test", now, now)
+
+	first, _ := NewComment("10001", key, "alice", "First", now, now)
+	if err := ticket.AddComment(first); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	staged := &Comment{TicketKey: key, Author: "alice", Body: "Not pushed yet", Created: now, Updated: now}
+	if err := ticket.AddComment(staged); err != nil {
+		t.Fatalf("AddComment() without ID error = %v", err)
+	}
+
+	duplicate, _ := NewComment("10001", key, "bob", "Again", now, now)
+	empty := &Comment{TicketKey: key, Author: "bob", Body: " "}
+	for name, comment := range map[string]*Comment{"duplicate ID": duplicate, "empty body": empty} {
+		if err := ticket.AddComment(comment); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("AddComment(%s) error = %v, want ErrInvalidInput", name, err)
+		}
+	}
+
+	if len(ticket.Comments) != 2 || ticket.Comments[0] != first || ticket.Comments[1] != staged {
+		t.Errorf("Comments = %v, want the first and staged comments", ticket.Comments)
 	}
 }
 
@@ -303,3 +626,81 @@ func TestTicket_ContentHash_Deterministic(t *testing.T) {
 		}
 	}
 }
+
+func TestTicket_ContentHash_StoredFieldValues(t *testing.T) {
+	key, _ := NewTicketKey("JMD-123")
+	now := time.Now()
+
+	// Custom fields read back from storage hold JSON types; Equal values hash the same
+	pulled := NewTicket(key, "Test", now, now)
+	pulled.CustomFields["story_points"] = NewFieldValue(3)
+	pulled.CustomFields["teams"] = NewFieldValue([]string{"core", "web"})
+
+	stored := NewTicket(key, "Test", now, now)
+	stored.CustomFields["story_points"] = NewFieldValue(3.0)
+	stored.CustomFields["teams"] = NewFieldValue([]interface{}{"core", "web"})
+
+	if changed := stored.ChangedFields(pulled); len(changed) != 0 {
+		t.Errorf("ChangedFields() = %v, want none", changed)
+	}
+	if pulled.ContentHash() != stored.ContentHash() {
+		t.Error("ContentHash() differs for equal custom field values")
+	}
+}
+
+func TestTicketDraft_Validate(t *testing.T) {
+	valid := func() *TicketDraft {
+		return &TicketDraft{
+			ProjectKey: "JMD",
+			Summary:    "Import notes",
+			IssueType:  "Task",
+			Labels:     []string{"imported"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(d *TicketDraft)
+		wantErr error
+	}{
+		{"valid", func(d *TicketDraft) {}, nil},
+		{"invalid project", func(d *TicketDraft) { d.ProjectKey = "jmd" }, ErrInvalidProject},
+		{"empty summary", func(d *TicketDraft) { d.Summary = "  " }, ErrInvalidInput},
+		{"multi-line summary", func(d *TicketDraft) { d.Summary = "one\ntwo" }, ErrInvalidInput},
+		{"long summary", func(d *TicketDraft) { d.Summary = strings.Repeat("x", MaxSummaryLength+1) }, ErrInvalidInput},
+		{"max summary", func(d *TicketDraft) { d.Summary = strings.Repeat("é", MaxSummaryLength) }, nil},
+		{"missing issue type", func(d *TicketDraft) { d.IssueType = "" }, ErrInvalidInput},
+		{"label with space", func(d *TicketDraft) { d.Labels = []string{"two words"} }, ErrInvalidInput},
+		{"empty label", func(d *TicketDraft) { d.Labels = []string{""} }, ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft := valid()
+			tt.modify(draft)
+			err := draft.Validate()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate() unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTicket_FixVersions(t *testing.T) {
+	key, _ := NewTicketKey("JMD-1")
+	ticket := NewTicket(key, "Ticket", time.Now(), time.Now())
+	if ticket.FixVersions() != nil || ticket.HasFixVersion("1.4.0") {
+		t.Errorf("ticket without fix versions has %v", ticket.FixVersions())
+	}
+
+	// As pulled from Jira, and as read back from storage
+	for _, raw := range []interface{}{[]string{"1.3.0", "1.4.0"}, []interface{}{"1.3.0", "1.4.0"}} {
+		ticket.CustomFields[FixVersionsField] = NewFieldValue(raw)
+		if !ticket.HasFixVersion(" 1.4.0 ") || ticket.HasFixVersion("1.4") {
+			t.Errorf("HasFixVersion() with %T got fix versions %v", raw, ticket.FixVersions())
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package domain contains the core business logic and entities.
+// This layer has zero dependencies on application or infrastructure layers.
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// User is a Jira user, as found in the user directory (e.g. to complete assignees or
+// resolve mentions).
+type User struct {
+	// AccountID identifies the user in Jira
+	AccountID string
+
+	// DisplayName is the user's full name
+	DisplayName string
+
+	// Email is the user's email address, empty unless their profile makes it visible
+	Email string
+
+	// Active is false for deactivated accounts
+	Active bool
+
+	// AvatarURL is the user's profile picture, empty when not known
+	AvatarURL string
+}
+
+// Name returns how the user is named in ticket fields such as Assignee: the email when
+// visible, the display name otherwise.
+func (u *User) Name() string {
+	if u.Email != "" {
+		return u.Email
+	}
+	return u.DisplayName
+}
+
+// Matches returns true if query is a prefix of the user's email or of any word of their
+// display name, ignoring case, the way Jira's user search matches. Every user matches an
+// empty query.
+func (u *User) Matches(query string) bool {
+	query = NormalizeUserQuery(query)
+	if query == "" {
+		return true
+	}
+	if strings.HasPrefix(strings.ToLower(u.Email), query) || strings.HasPrefix(strings.ToLower(u.DisplayName), query) {
+		return true
+	}
+	for _, word := range strings.Fields(strings.ToLower(u.DisplayName)) {
+		if strings.HasPrefix(word, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeUserQuery trims and lowercases a user search query, so searches differing
+// only in case share a cache entry.
+func NormalizeUserQuery(query string) string {
+	return strings.ToLower(strings.TrimSpace(query))
+}
+
+// Identifies returns true if name names the user exactly, ignoring case: their account
+// ID, email, the part of their email before the @, or display name.
+func (u *User) Identifies(name string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false
+	}
+	local, _, _ := strings.Cut(u.Email, "@")
+	for _, candidate := range []string{u.AccountID, u.Email, local, u.DisplayName} {
+		if candidate != "" && strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Label describes the user for messages: their display name with their email, if
+// visible (e.g. "Alice Smith <alice@example.com>").
+func (u *User) Label() string {
+	switch {
+	case u.Email == "":
+		return u.DisplayName
+	case u.DisplayName == "":
+		return u.Email
+	}
+	return u.DisplayName + " <" + u.Email + ">"
+}
+
+// FindUser picks the user name refers to among users: the only one it identifies, or
+// else the only one it matches as a search query (see Matches). Otherwise returns the
+// users it could refer to, none or several.
+func FindUser(name string, users []*User) (*User, []*User) {
+	var identified, matched []*User
+	for _, user := range users {
+		if user.Identifies(name) {
+			identified = append(identified, user)
+		} else if user.Matches(name) {
+			matched = append(matched, user)
+		}
+	}
+	switch {
+	case len(identified) == 1:
+		return identified[0], nil
+	case len(identified) > 1:
+		return nil, identified
+	case len(matched) == 1:
+		return matched[0], nil
+	}
+	return nil, matched
+}
+
+// SuggestUsers returns at most limit users whose email or display name is close to name,
+// allowing a few typos, closest first.
+func SuggestUsers(name string, users []*User, limit int) []*User {
+	name = NormalizeUserQuery(name)
+	if name == "" {
+		return nil
+	}
+	maxDistance := max(1, len([]rune(name))/3)
+
+	type suggestion struct {
+		user     *User
+		distance int
+	}
+	var suggestions []suggestion
+	for _, user := range users {
+		local, _, _ := strings.Cut(user.Email, "@")
+		candidates := append([]string{user.Email, local, user.DisplayName}, strings.Fields(user.DisplayName)...)
+		best := -1
+		for _, candidate := range candidates {
+			if candidate == "" {
+				continue
+			}
+			if d := editDistance(name, strings.ToLower(candidate)); best < 0 || d < best {
+				best = d
+			}
+		}
+		if best >= 0 && best <= maxDistance {
+			suggestions = append(suggestions, suggestion{user: user, distance: best})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].user.DisplayName < suggestions[j].user.DisplayName
+	})
+
+	suggested := make([]*User, 0, min(limit, len(suggestions)))
+	for _, s := range suggestions[:min(limit, len(suggestions))] {
+		suggested = append(suggested, s.user)
+	}
+	return suggested
+}
+
+// editDistance returns the number of rune insertions, deletions, substitutions, and
+// swaps of adjacent runes that turn a into b (the optimal string alignment distance).
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
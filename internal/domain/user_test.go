@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUser_Name(t *testing.T) {
+	if got := (&User{DisplayName: "Alice Smith", Email: "alice@example.com"}).Name(); got != "alice@example.com" {
+		t.Errorf("Name() = %q, want the email", got)
+	}
+	if got := (&User{DisplayName: "Alice Smith"}).Name(); got != "Alice Smith" {
+		t.Errorf("Name() without an email = %q, want the display name", got)
+	}
+}
+
+func TestUser_Matches(t *testing.T) {
+	user := &User{AccountID: "abc", DisplayName: "Alice van Dijk", Email: "asmith@example.com"}
+
+	tests := map[string]bool{
+		"":        true,
+		"ali":     true,
+		" VAN ":   true,
+		"dij":     true,
+		"asmith@": true,
+		"smith":   false,
+		"bob":     false,
+	}
+	for query, want := range tests {
+		if got := user.Matches(query); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestUser_Identifies(t *testing.T) {
+	user := &User{AccountID: "abc", DisplayName: "Alice Smith", Email: "asmith@example.com"}
+
+	tests := map[string]bool{
+		"abc":                true,
+		"ASMITH@example.com": true,
+		"asmith":             true,
+		" alice smith ":      true,
+		"alice":              false,
+		"":                   false,
+	}
+	for name, want := range tests {
+		if got := user.Identifies(name); got != want {
+			t.Errorf("Identifies(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFindUser(t *testing.T) {
+	alice := &User{AccountID: "a", DisplayName: "Alice Smith", Email: "alice@example.com"}
+	alicia := &User{AccountID: "b", DisplayName: "Alicia Jones", Email: "alicia@example.com"}
+	bob := &User{AccountID: "c", DisplayName: "Bob Smith", Email: "bob@example.com"}
+	users := []*User{alice, alicia, bob}
+
+	tests := []struct {
+		name       string
+		query      string
+		want       *User
+		candidates []*User
+	}{
+		{name: "identified despite other matches", query: "alice", want: alice},
+		{name: "single match", query: "bob s", want: bob},
+		{name: "ambiguous", query: "ali", candidates: []*User{alice, alicia}},
+		{name: "ambiguous last name", query: "smith", candidates: []*User{alice, bob}},
+		{name: "unknown", query: "carol"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, candidates := FindUser(tt.query, users)
+			if got != tt.want || !reflect.DeepEqual(candidates, tt.candidates) {
+				t.Errorf("FindUser(%q) = %v, %v, want %v, %v", tt.query, got, candidates, tt.want, tt.candidates)
+			}
+		})
+	}
+}
+
+func TestSuggestUsers(t *testing.T) {
+	alice := &User{DisplayName: "Alice Smith", Email: "alice@example.com"}
+	alicia := &User{DisplayName: "Alicia Jones", Email: "alicia@example.com"}
+	bob := &User{DisplayName: "Bob Smith", Email: "bob@example.com"}
+	users := []*User{bob, alicia, alice}
+
+	if got := SuggestUsers("alcie", users, 5); !reflect.DeepEqual(got, []*User{alice}) {
+		t.Errorf("SuggestUsers(alcie) = %v, want alice", got)
+	}
+	if got := SuggestUsers("alicja", users, 5); !reflect.DeepEqual(got, []*User{alicia, alice}) {
+		t.Errorf("SuggestUsers(alicja) = %v, want alicia then alice, closest first", got)
+	}
+	if got := SuggestUsers("alica", users, 5); !reflect.DeepEqual(got, []*User{alice, alicia}) {
+		t.Errorf("SuggestUsers(alica) = %v, want alice and alicia, by display name", got)
+	}
+	if got := SuggestUsers("smyth", users, 1); !reflect.DeepEqual(got, []*User{alice}) {
+		t.Errorf("SuggestUsers(smyth, limit 1) = %v, want the first by display name", got)
+	}
+	if got := SuggestUsers("zed", users, 5); len(got) != 0 {
+		t.Errorf("SuggestUsers(zed) = %v, want none", got)
+	}
+}
@@ -3,10 +3,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,71 +24,307 @@ type yamlConfig struct {
 	Jira    yamlJiraConfig    `yaml:"jira"`
 	Sync    yamlSyncConfig    `yaml:"sync"`
 	Storage yamlStorageConfig `yaml:"storage"`
+	API     yamlAPIConfig     `yaml:"api"`
+	Notify  yamlNotifyConfig  `yaml:"notify"`
+	Log     yamlLogConfig     `yaml:"log"`
+
+	Markdown  yamlMarkdownConfig `yaml:"markdown"`
+	Display   yamlDisplayConfig  `yaml:"display"`
+	Reports   yamlReportsConfig  `yaml:"reports"`
+	Watchlist []string           `yaml:"watchlist"`
 }
 
 type yamlJiraConfig struct {
-	BaseURL string `yaml:"base_url"`
-	Email   string `yaml:"email"`
-	Token   string `yaml:"token"`
-	Project string `yaml:"project"`
+	BaseURL string         `yaml:"base_url"`
+	Email   string         `yaml:"email"`
+	Token   string         `yaml:"token"`
+	Project string         `yaml:"project"`
+	HTTP    yamlHTTPConfig `yaml:"http"`
+
+	Comments yamlCommentsConfig `yaml:"comments"`
+}
+
+type yamlCommentsConfig struct {
+	Author              string            `yaml:"author"`
+	Footer              string            `yaml:"footer"`
+	ImpersonationTokens map[string]string `yaml:"impersonation_tokens"`
+}
+
+type yamlHTTPConfig struct {
+	Timeout        string `yaml:"timeout"`
+	ReadTimeout    string `yaml:"read_timeout"`
+	WriteTimeout   string `yaml:"write_timeout"`
+	ConnectTimeout string `yaml:"connect_timeout"`
+	Proxy          string `yaml:"proxy"`
+	CAFile         string `yaml:"ca_file"`
+	ClientCert     string `yaml:"client_cert"`
+	ClientKey      string `yaml:"client_key"`
+	RateLimit      string `yaml:"rate_limit"`
+
+	Retry yamlHTTPRetryConfig `yaml:"retry"`
+}
+
+type yamlHTTPRetryConfig struct {
+	Budget      string            `yaml:"budget"`
+	RateLimited yamlBackoffConfig `yaml:"rate_limited"`
+	Unavailable yamlBackoffConfig `yaml:"unavailable"`
+	Timeouts    yamlBackoffConfig `yaml:"timeouts"`
+}
+
+type yamlBackoffConfig struct {
+	MaxRetries string `yaml:"max_retries"`
+	BaseDelay  string `yaml:"base_delay"`
+	MaxDelay   string `yaml:"max_delay"`
 }
 
 type yamlSyncConfig struct {
-	Interval     string `yaml:"interval"`
-	MarkdownDir  string `yaml:"markdown_dir"`
-	WatchEnabled bool   `yaml:"watch_enabled"`
+	Interval         string          `yaml:"interval"`
+	MarkdownDir      string          `yaml:"markdown_dir"`
+	WatchEnabled     bool            `yaml:"watch_enabled"`
+	Mode             string          `yaml:"mode"`
+	Conflicts        string          `yaml:"conflicts"`
+	FullSyncSchedule string          `yaml:"full_sync_schedule"`
+	Retry            yamlRetryConfig `yaml:"retry"`
+	Filters          yamlSyncFilters `yaml:"filters"`
+
+	FieldDirections        map[string]string            `yaml:"field_directions"`
+	ProjectFieldDirections map[string]map[string]string `yaml:"project_field_directions"`
+	StatusMap              map[string]string            `yaml:"status_map"`
+	StatusAliases          map[string]string            `yaml:"status_aliases"`
+	PriorityMap            map[string]map[string]string `yaml:"priority_map"`
+	Sprint                 yamlSprintConfig             `yaml:"sprint"`
+	Indexes                yamlIndexesConfig            `yaml:"indexes"`
+	MetadataTTL            string                       `yaml:"metadata_ttl"`
+	Guardrails             yamlGuardrailsConfig         `yaml:"guardrails"`
+	SharedVault            yamlSharedVaultConfig        `yaml:"shared_vault"`
+}
+
+type yamlSharedVaultConfig struct {
+	AuthorSources []string `yaml:"author_sources"`
+}
+
+type yamlSyncFilters struct {
+	OnlyAssignee  string   `yaml:"only_assignee"`
+	IssueTypes    []string `yaml:"issue_types"`
+	ExcludeLabels []string `yaml:"exclude_labels"`
+}
+
+type yamlSprintConfig struct {
+	BoardID    int    `yaml:"board_id"`
+	ArchiveDir string `yaml:"archive_dir"`
+}
+
+type yamlIndexesConfig struct {
+	BySprint   bool                    `yaml:"by_sprint"`
+	ByAssignee bool                    `yaml:"by_assignee"`
+	Filters    []yamlFilterIndexConfig `yaml:"filters"`
+}
+
+type yamlFilterIndexConfig struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+}
+
+type yamlGuardrailsConfig struct {
+	MaxTicketsPerProject int    `yaml:"max_tickets_per_project"`
+	MaxFileSize          string `yaml:"max_file_size"`
+	StaleAfter           string `yaml:"stale_after"`
+}
+
+type yamlRetryConfig struct {
+	MaxAttempts    int      `yaml:"max_attempts"`
+	InitialBackoff string   `yaml:"initial_backoff"`
+	MaxBackoff     string   `yaml:"max_backoff"`
+	Multiplier     float64  `yaml:"multiplier"`
+	RetryOn        []string `yaml:"retry_on"`
 }
 
 type yamlStorageConfig struct {
-	DBPath string `yaml:"db_path"`
+	DBPath     string `yaml:"db_path"`
+	Encrypt    bool   `yaml:"encrypt"`
+	Driver     string `yaml:"driver"`
+	DSN        string `yaml:"dsn"`
+	Retention  string `yaml:"retention"`
+	GCInterval string `yaml:"gc_interval"`
+
+	CacheDir     string `yaml:"cache_dir"`
+	CacheMaxSize string `yaml:"cache_max_size"`
+}
+
+type yamlAPIConfig struct {
+	Enabled       bool             `yaml:"enabled"`
+	Address       string           `yaml:"address"`
+	Socket        string           `yaml:"socket"`
+	Auth          string           `yaml:"auth"`
+	WebhookSecret string           `yaml:"webhook_secret"`
+	TLS           yamlAPITLSConfig `yaml:"tls"`
+}
+
+type yamlAPITLSConfig struct {
+	Cert       string `yaml:"cert"`
+	Key        string `yaml:"key"`
+	ClientCA   string `yaml:"client_ca"`
+	ClientCert string `yaml:"client_cert"`
+	ClientKey  string `yaml:"client_key"`
+}
+
+type yamlNotifyConfig struct {
+	Command string `yaml:"command"`
+}
+
+type yamlLogConfig struct {
+	Level string `yaml:"level"`
+}
+
+type yamlDisplayConfig struct {
+	Timezone   string `yaml:"timezone"`
+	DateFormat string `yaml:"date_format"`
+}
+
+type yamlReportsConfig struct {
+	Schedule     string   `yaml:"schedule"`
+	Dir          string   `yaml:"dir"`
+	DoneStatuses []string `yaml:"done_statuses"`
+	WIPStatuses  []string `yaml:"wip_statuses"`
+}
+
+type yamlMarkdownConfig struct {
+	Flavor               string                       `yaml:"flavor"`
+	Frontmatter          string                       `yaml:"frontmatter"`
+	KeyOrder             []string                     `yaml:"key_order"`
+	RawFields            bool                         `yaml:"raw_fields"`
+	DownloadMedia        bool                         `yaml:"download_media"`
+	Authors              bool                         `yaml:"authors"`
+	MaxComments          int                          `yaml:"max_comments"`
+	MaxDescriptionLength int                          `yaml:"max_description_length"`
+	ProjectLimits        map[string]map[string]string `yaml:"project_limits"`
 }
 
 // Loader implements domain.ConfigLoader interface.
-type Loader struct{}
+//
+// Settings are resolved from four layers, each overriding the ones after it:
+// overrides given with WithOverrides (command-line flags), JIRAMD_* environment
+// variables (see EnvName), the YAML file, and defaults.
+type Loader struct {
+	lookupEnv    func(string) (string, bool)
+	overrides    map[string]string
+	optionalFile bool
+}
 
-// NewLoader creates a new configuration loader.
+// NewLoader creates a new configuration loader that reads overrides from the process
+// environment.
 func NewLoader() *Loader {
-	return &Loader{}
+	return &Loader{lookupEnv: os.LookupEnv}
+}
+
+// WithEnv replaces the environment lookup used for JIRAMD_* overrides (nil disables them).
+func (l *Loader) WithEnv(lookup func(string) (string, bool)) *Loader {
+	l.lookupEnv = lookup
+	return l
+}
+
+// WithOverrides sets settings, keyed by dotted name (e.g. "sync.interval"), that take
+// precedence over the environment and the file.
+func (l *Loader) WithOverrides(overrides map[string]string) *Loader {
+	l.overrides = overrides
+	return l
+}
+
+// WithOptionalFile makes a missing config file contribute nothing instead of failing,
+// so configuration can come from the environment and defaults alone.
+func (l *Loader) WithOptionalFile() *Loader {
+	l.optionalFile = true
+	return l
 }
 
 // Load loads configuration from the specified YAML file path.
 // It performs the following operations:
 // 1. Reads and parses YAML file
-// 2. Expands environment variables (${VAR} syntax)
-// 3. Expands home directory (~)
-// 4. Converts YAML structure to domain.Config
+// 2. Applies environment variable and flag overrides
+// 3. Expands environment variables (${VAR} syntax)
+// 4. Expands home directory (~)
+// 5. Converts YAML structure to domain.Config
 // Returns domain error if loading or parsing fails.
 func (l *Loader) Load(path string) (*domain.Config, error) {
+	resolution, err := l.Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolution.Err(); err != nil {
+		return nil, err
+	}
+	return resolution.Config, nil
+}
+
+// Resolve loads configuration like Load and also reports where each setting came from.
+// Problems with individual settings are collected in Resolution.Problems, with the
+// best-effort Config, instead of being returned; only an unreadable or unparsable file
+// is an error.
+func (l *Loader) Resolve(path string) (*Resolution, error) {
 	// Expand home directory in path
 	expandedPath, err := expandHomePath(path)
 	if err != nil {
 		return nil, domain.NewConfigError(fmt.Sprintf("failed to expand path: %v", err))
 	}
 
+	resolution := &Resolution{
+		Path:    expandedPath,
+		Sources: make(map[string]Source),
+	}
+
 	// Read YAML file
+	var yamlCfg yamlConfig
 	data, err := os.ReadFile(expandedPath)
-	if err != nil {
+	switch {
+	case err == nil:
+		resolution.FileFound = true
+	case errors.Is(err, fs.ErrNotExist) && l.optionalFile:
+		// Nothing from the file; other layers and defaults apply
+	default:
 		return nil, domain.NewConfigError(fmt.Sprintf("failed to read config file: %v", err))
 	}
 
-	// Parse YAML
-	var yamlCfg yamlConfig
-	if err := yaml.Unmarshal(data, &yamlCfg); err != nil {
-		return nil, domain.NewConfigError(fmt.Sprintf("failed to parse YAML: %v", err))
+	var found problems
+	if resolution.FileFound {
+		// Parse YAML, then decode it strictly so unknown keys and wrong types are reported
+		var document yaml.Node
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, domain.NewConfigError(fmt.Sprintf("failed to parse YAML: %v", err))
+		}
+		resolution.Lines = decodeStrict(&document, &yamlCfg, &found)
+		for _, key := range Keys() {
+			if _, ok := resolution.Lines[key]; ok {
+				resolution.Sources[key] = SourceFile
+			}
+		}
+	}
+
+	// Apply overrides, environment first so flags win
+	applySettings(&yamlCfg, envSettings(l.lookupEnv), SourceEnv, resolution.Sources, &found)
+	applySettings(&yamlCfg, l.overrides, SourceFlag, resolution.Sources, &found)
+
+	// Default paths that nothing set, before ~ is expanded
+	if strings.TrimSpace(yamlCfg.Storage.DBPath) == "" {
+		yamlCfg.Storage.DBPath = DefaultDBPath()
+	}
+	if strings.TrimSpace(yamlCfg.Storage.CacheDir) == "" {
+		yamlCfg.Storage.CacheDir = DefaultCacheDir()
 	}
 
 	// Expand environment variables in all string fields
 	if err := expandEnvVars(&yamlCfg); err != nil {
-		return nil, domain.NewConfigError(fmt.Sprintf("failed to expand env vars: %v", err))
+		found.add("", "failed to expand env vars: %v", err)
 	}
 
-	// Convert to domain config
-	cfg, err := toDomainConfig(&yamlCfg)
-	if err != nil {
-		return nil, domain.NewConfigError(fmt.Sprintf("failed to convert config: %v", err))
+	// Convert to domain config, reporting every value that does not parse
+	resolution.Config = toDomainConfig(&yamlCfg, &found)
+	for i := range found {
+		if found[i].Line == 0 && resolution.Sources[found[i].Key] == SourceFile {
+			found[i].Line = resolution.Lines[found[i].Key]
+		}
 	}
-
-	return cfg, nil
+	resolution.Problems = found
+	return resolution, nil
 }
 
 // expandHomePath expands ~ to the user's home directory.
@@ -121,25 +361,77 @@ func expandEnvVars(cfg *yamlConfig) error {
 	cfg.Jira.Email = expandString(cfg.Jira.Email, envVarPattern)
 	cfg.Jira.Token = expandString(cfg.Jira.Token, envVarPattern)
 	cfg.Jira.Project = expandString(cfg.Jira.Project, envVarPattern)
+	cfg.Jira.HTTP.Proxy = expandString(cfg.Jira.HTTP.Proxy, envVarPattern)
+	cfg.Jira.HTTP.CAFile = expandString(cfg.Jira.HTTP.CAFile, envVarPattern)
+	cfg.Jira.HTTP.ClientCert = expandString(cfg.Jira.HTTP.ClientCert, envVarPattern)
+	cfg.Jira.HTTP.ClientKey = expandString(cfg.Jira.HTTP.ClientKey, envVarPattern)
+	cfg.Jira.Comments.Author = expandString(cfg.Jira.Comments.Author, envVarPattern)
+	for author, token := range cfg.Jira.Comments.ImpersonationTokens {
+		cfg.Jira.Comments.ImpersonationTokens[author] = expandString(token, envVarPattern)
+	}
 
 	// Expand Sync config fields
 	cfg.Sync.MarkdownDir = expandString(cfg.Sync.MarkdownDir, envVarPattern)
+	cfg.Sync.Sprint.ArchiveDir = expandString(cfg.Sync.Sprint.ArchiveDir, envVarPattern)
+	cfg.Reports.Dir = expandString(cfg.Reports.Dir, envVarPattern)
 
 	// Expand Storage config fields
 	cfg.Storage.DBPath = expandString(cfg.Storage.DBPath, envVarPattern)
+	cfg.Storage.DSN = expandString(cfg.Storage.DSN, envVarPattern)
+	cfg.Storage.CacheDir = expandString(cfg.Storage.CacheDir, envVarPattern)
+
+	// Expand API config fields
+	cfg.API.Address = expandString(cfg.API.Address, envVarPattern)
+	cfg.API.Socket = expandString(cfg.API.Socket, envVarPattern)
+	cfg.API.WebhookSecret = expandString(cfg.API.WebhookSecret, envVarPattern)
+	apiTLSPaths := []*string{&cfg.API.TLS.Cert, &cfg.API.TLS.Key, &cfg.API.TLS.ClientCA, &cfg.API.TLS.ClientCert, &cfg.API.TLS.ClientKey}
+	for _, path := range apiTLSPaths {
+		*path = expandString(*path, envVarPattern)
+	}
 
 	// Expand home directory paths
 	var err error
+	for _, path := range []*string{&cfg.Jira.HTTP.CAFile, &cfg.Jira.HTTP.ClientCert, &cfg.Jira.HTTP.ClientKey} {
+		if *path, err = expandHomePath(*path); err != nil {
+			return fmt.Errorf("failed to expand jira.http path: %w", err)
+		}
+	}
+
 	cfg.Sync.MarkdownDir, err = expandHomePath(cfg.Sync.MarkdownDir)
 	if err != nil {
 		return fmt.Errorf("failed to expand markdown_dir: %w", err)
 	}
 
+	cfg.Sync.Sprint.ArchiveDir, err = expandHomePath(cfg.Sync.Sprint.ArchiveDir)
+	if err != nil {
+		return fmt.Errorf("failed to expand sprint archive_dir: %w", err)
+	}
+
+	cfg.Reports.Dir, err = expandHomePath(cfg.Reports.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to expand reports dir: %w", err)
+	}
+
 	cfg.Storage.DBPath, err = expandHomePath(cfg.Storage.DBPath)
 	if err != nil {
 		return fmt.Errorf("failed to expand db_path: %w", err)
 	}
 
+	cfg.Storage.CacheDir, err = expandHomePath(cfg.Storage.CacheDir)
+	if err != nil {
+		return fmt.Errorf("failed to expand cache_dir: %w", err)
+	}
+
+	cfg.API.Socket, err = expandHomePath(cfg.API.Socket)
+	if err != nil {
+		return fmt.Errorf("failed to expand api.socket: %w", err)
+	}
+	for _, path := range apiTLSPaths {
+		if *path, err = expandHomePath(*path); err != nil {
+			return fmt.Errorf("failed to expand api.tls path: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -161,30 +453,586 @@ func expandString(s string, pattern *regexp.Regexp) string {
 	})
 }
 
-// toDomainConfig converts yamlConfig to domain.Config.
-func toDomainConfig(yamlCfg *yamlConfig) (*domain.Config, error) {
-	// Parse sync interval
-	interval, err := time.ParseDuration(yamlCfg.Sync.Interval)
+// toDomainConfig converts yamlConfig to domain.Config, recording every value that does
+// not parse in found. Such settings are left at their zero value.
+func toDomainConfig(yamlCfg *yamlConfig, found *problems) *domain.Config {
+	// Parse sync interval, which defaults when omitted
+	interval, err := parseDuration(yamlCfg.Sync.Interval, domain.DefaultSyncInterval)
+	if err != nil {
+		found.add("sync.interval", "invalid sync interval '%s': %v", yamlCfg.Sync.Interval, err)
+	}
+
+	metadataTTL, err := parseDuration(yamlCfg.Sync.MetadataTTL, domain.DefaultMetadataTTL)
+	if err != nil {
+		found.add("sync.metadata_ttl", "invalid sync metadata_ttl '%s': %v", yamlCfg.Sync.MetadataTTL, err)
+	} else if metadataTTL < 0 {
+		found.add("sync.metadata_ttl", "sync metadata_ttl must not be negative, got '%s'", yamlCfg.Sync.MetadataTTL)
+	}
+
+	// Parse optional full sync cron schedule
+	var fullSyncSchedule domain.CronSchedule
+	if strings.TrimSpace(yamlCfg.Sync.FullSyncSchedule) != "" {
+		fullSyncSchedule, err = domain.ParseCronSchedule(yamlCfg.Sync.FullSyncSchedule)
+		if err != nil {
+			found.add("sync.full_sync_schedule", "invalid sync full_sync_schedule '%s': %v", yamlCfg.Sync.FullSyncSchedule, err)
+		}
+	}
+
+	retry := toRetryPolicy(&yamlCfg.Sync.Retry, found)
+	httpConfig := toHTTPConfig(&yamlCfg.Jira.HTTP, found)
+
+	// Parse retention settings, which default when omitted
+	retention, err := parseDays(yamlCfg.Storage.Retention, domain.DefaultRetention)
+	if err != nil {
+		found.add("storage.retention", "invalid storage retention '%s': %v", yamlCfg.Storage.Retention, err)
+	}
+
+	gcInterval, err := parseDays(yamlCfg.Storage.GCInterval, domain.DefaultGCInterval)
+	if err != nil {
+		found.add("storage.gc_interval", "invalid storage gc_interval '%s': %v", yamlCfg.Storage.GCInterval, err)
+	}
+
+	cacheMaxSize, err := parseSize(yamlCfg.Storage.CacheMaxSize, domain.DefaultCacheMaxSize)
 	if err != nil {
-		return nil, fmt.Errorf("invalid sync interval '%s': %w", yamlCfg.Sync.Interval, err)
+		found.add("storage.cache_max_size", "invalid storage cache_max_size '%s': %v", yamlCfg.Storage.CacheMaxSize, err)
+	}
+
+	driver := domain.StorageDriver(strings.ToLower(strings.TrimSpace(yamlCfg.Storage.Driver)))
+	if driver == "" {
+		driver = domain.StorageDriverSQLite
+	}
+
+	mode := domain.SyncMode(strings.ToLower(strings.TrimSpace(yamlCfg.Sync.Mode)))
+	if mode == "" {
+		mode = domain.SyncModeBidirectional
+	}
+
+	sprintArchiveDir := yamlCfg.Sync.Sprint.ArchiveDir
+	if sprintArchiveDir == "" && yamlCfg.Sync.Sprint.BoardID != 0 && yamlCfg.Sync.MarkdownDir != "" {
+		sprintArchiveDir = filepath.Join(yamlCfg.Sync.MarkdownDir, "archive")
 	}
 
-	cfg := &domain.Config{
+	logLevel := strings.ToLower(strings.TrimSpace(yamlCfg.Log.Level))
+	if logLevel == "" {
+		logLevel = domain.LogLevelInfo
+	}
+
+	flavor := domain.MarkdownFlavor(strings.ToLower(strings.TrimSpace(yamlCfg.Markdown.Flavor)))
+	if flavor == "" {
+		flavor = domain.MarkdownFlavorPlain
+	}
+
+	frontmatter := domain.FrontmatterFormat(strings.ToLower(strings.TrimSpace(yamlCfg.Markdown.Frontmatter)))
+	if frontmatter == "" {
+		frontmatter = domain.FrontmatterYAML
+	}
+
+	return &domain.Config{
 		Jira: domain.JiraConfig{
 			BaseURL: yamlCfg.Jira.BaseURL,
 			Email:   yamlCfg.Jira.Email,
 			Token:   yamlCfg.Jira.Token,
 			Project: yamlCfg.Jira.Project,
+			HTTP:    httpConfig,
+			Comments: domain.CommentAttribution{
+				Author:              strings.TrimSpace(yamlCfg.Jira.Comments.Author),
+				Footer:              strings.TrimSpace(yamlCfg.Jira.Comments.Footer),
+				ImpersonationTokens: trimNames(yamlCfg.Jira.Comments.ImpersonationTokens),
+			},
 		},
 		Sync: domain.SyncConfig{
-			Interval:     interval,
-			MarkdownDir:  yamlCfg.Sync.MarkdownDir,
-			WatchEnabled: yamlCfg.Sync.WatchEnabled,
+			Interval:         interval,
+			MarkdownDir:      yamlCfg.Sync.MarkdownDir,
+			WatchEnabled:     yamlCfg.Sync.WatchEnabled,
+			Mode:             mode,
+			Conflicts:        domain.ConflictStrategy(strings.ToLower(strings.TrimSpace(yamlCfg.Sync.Conflicts))),
+			FullSyncSchedule: fullSyncSchedule,
+			Retry:            retry,
+			Filter: domain.SyncFilter{
+				OnlyAssignee:  strings.TrimSpace(yamlCfg.Sync.Filters.OnlyAssignee),
+				IssueTypes:    trimAll(yamlCfg.Sync.Filters.IssueTypes),
+				ExcludeLabels: trimAll(yamlCfg.Sync.Filters.ExcludeLabels),
+			},
+			Sprint: domain.SprintScope{
+				BoardID:    yamlCfg.Sync.Sprint.BoardID,
+				ArchiveDir: sprintArchiveDir,
+			},
+			Indexes: domain.IndexOptions{
+				BySprint:   yamlCfg.Sync.Indexes.BySprint,
+				ByAssignee: yamlCfg.Sync.Indexes.ByAssignee,
+				Filters:    toFilterIndexes(yamlCfg.Sync.Indexes.Filters, found),
+			},
+			MetadataTTL: metadataTTL,
+			Guardrails:  toGuardrails(&yamlCfg.Sync.Guardrails, found),
+
+			FieldDirections:        toFieldDirections(yamlCfg.Sync.FieldDirections),
+			ProjectFieldDirections: toProjectFieldDirections(yamlCfg.Sync.ProjectFieldDirections),
+			Statuses: domain.StatusMap{
+				Names:   trimNames(yamlCfg.Sync.StatusMap),
+				Aliases: trimNames(yamlCfg.Sync.StatusAliases),
+			},
+			Priorities: toPriorityMaps(yamlCfg.Sync.PriorityMap),
+			SharedVault: domain.SharedVaultConfig{
+				AuthorSources: toAuthorSources(yamlCfg.Sync.SharedVault.AuthorSources),
+			},
 		},
 		Storage: domain.StorageConfig{
-			DBPath: yamlCfg.Storage.DBPath,
+			DBPath:       yamlCfg.Storage.DBPath,
+			Encrypt:      yamlCfg.Storage.Encrypt,
+			Driver:       driver,
+			DSN:          yamlCfg.Storage.DSN,
+			Retention:    retention,
+			GCInterval:   gcInterval,
+			CacheDir:     strings.TrimSpace(yamlCfg.Storage.CacheDir),
+			CacheMaxSize: cacheMaxSize,
+		},
+		API: domain.APIConfig{
+			Enabled:       yamlCfg.API.Enabled,
+			Address:       yamlCfg.API.Address,
+			SocketPath:    yamlCfg.API.Socket,
+			Auth:          domain.APIAuth(strings.TrimSpace(yamlCfg.API.Auth)),
+			WebhookSecret: yamlCfg.API.WebhookSecret,
+			TLS: domain.APITLSConfig{
+				CertFile:       yamlCfg.API.TLS.Cert,
+				KeyFile:        yamlCfg.API.TLS.Key,
+				ClientCAFile:   yamlCfg.API.TLS.ClientCA,
+				ClientCertFile: yamlCfg.API.TLS.ClientCert,
+				ClientKeyFile:  yamlCfg.API.TLS.ClientKey,
+			},
+		},
+		Notify: domain.NotifyConfig{
+			Command: strings.TrimSpace(yamlCfg.Notify.Command),
 		},
+		Log: domain.LogConfig{
+			Level: logLevel,
+		},
+		Markdown: domain.MarkdownConfig{
+			Flavor:               flavor,
+			Frontmatter:          frontmatter,
+			KeyOrder:             trimAll(yamlCfg.Markdown.KeyOrder),
+			RawFields:            yamlCfg.Markdown.RawFields,
+			DownloadMedia:        yamlCfg.Markdown.DownloadMedia,
+			Authors:              yamlCfg.Markdown.Authors,
+			MaxComments:          yamlCfg.Markdown.MaxComments,
+			MaxDescriptionLength: yamlCfg.Markdown.MaxDescriptionLength,
+			ProjectLimits:        toProjectLimits(yamlCfg.Markdown.ProjectLimits, found),
+		},
+		Display:   toDisplayConfig(&yamlCfg.Display, found),
+		Reports:   toReportsConfig(&yamlCfg.Reports, yamlCfg.Sync.MarkdownDir, found),
+		Watchlist: toWatchlist(yamlCfg.Watchlist, found),
+	}
+}
+
+// toDisplayConfig converts the display section. Timestamps are shown in the local time
+// zone with domain.DefaultDateFormat unless configured otherwise.
+func toDisplayConfig(yamlDisplay *yamlDisplayConfig, found *problems) domain.DisplayConfig {
+	display := domain.DisplayConfig{Location: time.Local, DateFormat: domain.DefaultDateFormat}
+
+	if timezone := strings.TrimSpace(yamlDisplay.Timezone); timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			found.add("display.timezone", "invalid display timezone '%s': %v", yamlDisplay.Timezone, err)
+		} else {
+			display.Location = location
+		}
+	}
+	if yamlDisplay.DateFormat != "" {
+		display.DateFormat = yamlDisplay.DateFormat
+	}
+	return display
+}
+
+// toReportsConfig converts the reports section. Reports are written to the reports
+// directory under markdownDir, and count "Done" as done and "In Progress" as in
+// progress, unless configured otherwise.
+func toReportsConfig(yamlReports *yamlReportsConfig, markdownDir string, found *problems) domain.ReportsConfig {
+	reports := domain.ReportsConfig{
+		Dir:          strings.TrimSpace(yamlReports.Dir),
+		DoneStatuses: trimAll(yamlReports.DoneStatuses),
+		WIPStatuses:  trimAll(yamlReports.WIPStatuses),
+	}
+
+	if strings.TrimSpace(yamlReports.Schedule) != "" {
+		schedule, err := domain.ParseCronSchedule(yamlReports.Schedule)
+		if err != nil {
+			found.add("reports.schedule", "invalid reports schedule '%s': %v", yamlReports.Schedule, err)
+		}
+		reports.Schedule = schedule
+	}
+	if reports.Dir == "" && markdownDir != "" {
+		reports.Dir = filepath.Join(markdownDir, domain.ReportDir)
+	}
+	if reports.DoneStatuses == nil {
+		reports.DoneStatuses = domain.DefaultDoneStatuses
+	}
+	if reports.WIPStatuses == nil {
+		reports.WIPStatuses = domain.DefaultWIPStatuses
+	}
+	return reports
+}
+
+// toRetryPolicy converts sync.retry to a retry policy. Settings that are omitted keep
+// their value from domain.DefaultRetryPolicy; an empty retry_on list retries nothing.
+func toRetryPolicy(yamlRetry *yamlRetryConfig, found *problems) domain.RetryPolicy {
+	policy := domain.DefaultRetryPolicy()
+
+	if yamlRetry.MaxAttempts != 0 {
+		policy.MaxAttempts = yamlRetry.MaxAttempts
+	}
+	if yamlRetry.Multiplier != 0 {
+		policy.Multiplier = yamlRetry.Multiplier
+	}
+
+	var err error
+	if value := strings.TrimSpace(yamlRetry.InitialBackoff); value != "" {
+		if policy.InitialBackoff, err = time.ParseDuration(value); err != nil {
+			found.add("sync.retry.initial_backoff", "invalid sync retry initial_backoff '%s': %v", yamlRetry.InitialBackoff, err)
+		}
+	}
+	if value := strings.TrimSpace(yamlRetry.MaxBackoff); value != "" {
+		if policy.MaxBackoff, err = time.ParseDuration(value); err != nil {
+			found.add("sync.retry.max_backoff", "invalid sync retry max_backoff '%s': %v", yamlRetry.MaxBackoff, err)
+		}
+	}
+
+	if yamlRetry.RetryOn != nil {
+		policy.RetryOn = make([]domain.ErrorClass, 0, len(yamlRetry.RetryOn))
+		for _, class := range yamlRetry.RetryOn {
+			policy.RetryOn = append(policy.RetryOn, domain.ErrorClass(strings.ToLower(strings.TrimSpace(class))))
+		}
 	}
 
-	return cfg, nil
+	return policy
+}
+
+// toGuardrails converts sync.guardrails to guardrails. Settings that are omitted keep
+// their value from domain.DefaultGuardrails; a negative max_tickets_per_project and a
+// max_file_size or stale_after of 0 disable their check.
+func toGuardrails(yamlGuardrails *yamlGuardrailsConfig, found *problems) domain.Guardrails {
+	guardrails := domain.DefaultGuardrails()
+
+	switch {
+	case yamlGuardrails.MaxTicketsPerProject < 0:
+		guardrails.MaxTicketsPerProject = 0
+	case yamlGuardrails.MaxTicketsPerProject > 0:
+		guardrails.MaxTicketsPerProject = yamlGuardrails.MaxTicketsPerProject
+	}
+
+	var err error
+	if guardrails.MaxFileSize, err = parseSize(yamlGuardrails.MaxFileSize, domain.DefaultMaxFileSize); err != nil {
+		found.add("sync.guardrails.max_file_size", "invalid sync guardrails max_file_size '%s': %v", yamlGuardrails.MaxFileSize, err)
+	}
+	if guardrails.StaleAfter, err = parseDays(yamlGuardrails.StaleAfter, domain.DefaultStaleAfter); err != nil {
+		found.add("sync.guardrails.stale_after", "invalid sync guardrails stale_after '%s': %v", yamlGuardrails.StaleAfter, err)
+	}
+
+	return guardrails
+}
+
+// toFilterIndexes converts the saved filter index entries, skipping those without a
+// filter ID and those whose index file another entry already names.
+func toFilterIndexes(yamlFilters []yamlFilterIndexConfig, found *problems) []domain.FilterIndex {
+	var filters []domain.FilterIndex
+	slugs := make(map[string]string)
+	for i, yamlFilter := range yamlFilters {
+		key := fmt.Sprintf("sync.indexes.filters[%d]", i)
+		filter := domain.FilterIndex{ID: strings.TrimSpace(yamlFilter.ID), Name: strings.TrimSpace(yamlFilter.Name)}
+		if filter.ID == "" {
+			found.add(key+".id", "sync indexes filter %d has no id", i)
+			continue
+		}
+		if other, ok := slugs[filter.Slug()]; ok {
+			found.add(key, "sync indexes filters %s and %s would both write %s.md; give them different names", other, filter.ID, filter.Slug())
+			continue
+		}
+		slugs[filter.Slug()] = filter.ID
+		filters = append(filters, filter)
+	}
+	return filters
+}
+
+// toWatchlist converts the watch list to ticket keys, skipping invalid and repeated keys.
+func toWatchlist(values []string, found *problems) []domain.TicketKey {
+	var keys []domain.TicketKey
+	seen := make(map[domain.TicketKey]bool)
+	for i, value := range values {
+		key, err := domain.NewTicketKey(strings.TrimSpace(value))
+		if err != nil {
+			found.add(fmt.Sprintf("watchlist[%d]", i), "invalid watchlist ticket key '%s'", value)
+			continue
+		}
+		if seen[key] {
+			found.add(fmt.Sprintf("watchlist[%d]", i), "watchlist lists %s more than once", key)
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// trimAll trims every value and drops the empty ones, returning nil if none remain.
+func trimAll(values []string) []string {
+	var trimmed []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
+
+// toFieldDirections converts a field_directions mapping, normalizing the directions
+// (validated by Validator).
+func toFieldDirections(yamlDirections map[string]string) domain.FieldDirections {
+	if len(yamlDirections) == 0 {
+		return nil
+	}
+	directions := make(domain.FieldDirections, len(yamlDirections))
+	for field, direction := range yamlDirections {
+		directions[strings.TrimSpace(field)] = domain.SyncDirection(strings.ToLower(strings.TrimSpace(direction)))
+	}
+	return directions
+}
+
+// toProjectFieldDirections converts sync.project_field_directions, keyed by project key.
+func toProjectFieldDirections(yamlProjects map[string]map[string]string) map[string]domain.FieldDirections {
+	if len(yamlProjects) == 0 {
+		return nil
+	}
+	projects := make(map[string]domain.FieldDirections, len(yamlProjects))
+	for project, yamlDirections := range yamlProjects {
+		projects[strings.TrimSpace(project)] = toFieldDirections(yamlDirections)
+	}
+	return projects
+}
+
+// toProjectLimits converts markdown.project_limits, keyed by project key. Limits that
+// are not whole numbers and unknown limits are recorded in found.
+func toProjectLimits(yamlProjects map[string]map[string]string, found *problems) map[string]domain.ContentLimits {
+	if len(yamlProjects) == 0 {
+		return nil
+	}
+	const key = "markdown.project_limits"
+	projects := make(map[string]domain.ContentLimits, len(yamlProjects))
+	for _, project := range sortedKeys(yamlProjects) {
+		var limits domain.ContentLimits
+		for _, name := range sortedKeys(yamlProjects[project]) {
+			value := strings.TrimSpace(yamlProjects[project][name])
+			var target *int
+			switch strings.TrimSpace(name) {
+			case "max_description_length":
+				target = &limits.MaxDescriptionLength
+			case "max_comments":
+				target = &limits.MaxComments
+			default:
+				found.add(key, "%s.%s: unknown limit %s (expected max_description_length or max_comments)", key, project, name)
+				continue
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				found.add(key, "%s.%s.%s must be a whole number, got '%s'", key, project, name, value)
+				continue
+			}
+			*target = n
+		}
+		projects[strings.TrimSpace(project)] = limits
+	}
+	return projects
+}
+
+// toPriorityMaps converts sync.priority_map, keyed by project key.
+func toPriorityMaps(yamlProjects map[string]map[string]string) map[string]domain.PriorityMap {
+	if len(yamlProjects) == 0 {
+		return nil
+	}
+	projects := make(map[string]domain.PriorityMap, len(yamlProjects))
+	for project, names := range yamlProjects {
+		projects[strings.TrimSpace(project)] = trimNames(names)
+	}
+	return projects
+}
+
+// trimNames trims the keys and values of a name mapping, returning nil when it is empty.
+func trimNames(yamlNames map[string]string) map[string]string {
+	if len(yamlNames) == 0 {
+		return nil
+	}
+	names := make(map[string]string, len(yamlNames))
+	for name, value := range yamlNames {
+		names[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return names
+}
+
+// toAuthorSources converts sync.shared_vault.author_sources, lowercased.
+func toAuthorSources(yamlSources []string) []domain.AuthorSource {
+	var sources []domain.AuthorSource
+	for _, source := range yamlSources {
+		if source = strings.ToLower(strings.TrimSpace(source)); source != "" {
+			sources = append(sources, domain.AuthorSource(source))
+		}
+	}
+	return sources
+}
+
+// toHTTPConfig converts jira.http to the HTTP settings. timeout sets both read_timeout and
+// write_timeout, which override it; omitted timeouts use the domain defaults.
+func toHTTPConfig(yamlHTTP *yamlHTTPConfig, found *problems) domain.HTTPConfig {
+	cfg := domain.HTTPConfig{
+		Proxy:          strings.TrimSpace(yamlHTTP.Proxy),
+		CAFile:         yamlHTTP.CAFile,
+		ClientCertFile: yamlHTTP.ClientCert,
+		ClientKeyFile:  yamlHTTP.ClientKey,
+	}
+
+	timeout, err := parseDuration(yamlHTTP.Timeout, domain.DefaultHTTPTimeout)
+	if err != nil {
+		found.add("jira.http.timeout", "invalid jira http timeout '%s': %v", yamlHTTP.Timeout, err)
+		timeout = domain.DefaultHTTPTimeout
+	}
+	if cfg.ReadTimeout, err = parseDuration(yamlHTTP.ReadTimeout, timeout); err != nil {
+		found.add("jira.http.read_timeout", "invalid jira http read_timeout '%s': %v", yamlHTTP.ReadTimeout, err)
+	}
+	if cfg.WriteTimeout, err = parseDuration(yamlHTTP.WriteTimeout, timeout); err != nil {
+		found.add("jira.http.write_timeout", "invalid jira http write_timeout '%s': %v", yamlHTTP.WriteTimeout, err)
+	}
+	if cfg.ConnectTimeout, err = parseDuration(yamlHTTP.ConnectTimeout, domain.DefaultConnectTimeout); err != nil {
+		found.add("jira.http.connect_timeout", "invalid jira http connect_timeout '%s': %v", yamlHTTP.ConnectTimeout, err)
+	}
+	if cfg.RateLimit, err = parseRate(yamlHTTP.RateLimit, domain.DefaultRateLimit); err != nil {
+		found.add("jira.http.rate_limit", "invalid jira http rate_limit '%s': %v", yamlHTTP.RateLimit, err)
+	}
+	cfg.Retry = toHTTPRetryPolicy(&yamlHTTP.Retry, found)
+
+	return cfg
+}
+
+// toHTTPRetryPolicy converts jira.http.retry to a request retry policy. Settings that
+// are omitted keep their value from domain.DefaultHTTPRetryPolicy.
+func toHTTPRetryPolicy(yamlRetry *yamlHTTPRetryConfig, found *problems) domain.HTTPRetryPolicy {
+	policy := domain.DefaultHTTPRetryPolicy()
+
+	var err error
+	if policy.Budget, err = parseDuration(yamlRetry.Budget, policy.Budget); err != nil {
+		found.add("jira.http.retry.budget", "invalid jira http retry budget '%s': %v", yamlRetry.Budget, err)
+	}
+	policy.RateLimited = toBackoff(&yamlRetry.RateLimited, policy.RateLimited, "rate_limited", found)
+	policy.Unavailable = toBackoff(&yamlRetry.Unavailable, policy.Unavailable, "unavailable", found)
+	policy.Timeouts = toBackoff(&yamlRetry.Timeouts, policy.Timeouts, "timeouts", found)
+	return policy
+}
+
+// toBackoff converts the jira.http.retry backoff of the given name, omitted settings
+// keeping their value from fallback.
+func toBackoff(yamlBackoff *yamlBackoffConfig, fallback domain.Backoff, name string, found *problems) domain.Backoff {
+	backoff := fallback
+	key := "jira.http.retry." + name
+
+	if value := strings.TrimSpace(yamlBackoff.MaxRetries); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			found.add(key+".max_retries", "invalid jira http retry %s max_retries '%s': expected a whole number", name, yamlBackoff.MaxRetries)
+		} else {
+			backoff.MaxRetries = n
+		}
+	}
+
+	var err error
+	if backoff.BaseDelay, err = parseDuration(yamlBackoff.BaseDelay, fallback.BaseDelay); err != nil {
+		found.add(key+".base_delay", "invalid jira http retry %s base_delay '%s': %v", name, yamlBackoff.BaseDelay, err)
+	}
+	if backoff.MaxDelay, err = parseDuration(yamlBackoff.MaxDelay, fallback.MaxDelay); err != nil {
+		found.add(key+".max_delay", "invalid jira http retry %s max_delay '%s': %v", name, yamlBackoff.MaxDelay, err)
+	}
+	return backoff
+}
+
+// parseDuration parses a duration, yielding fallback for an empty value.
+func parseDuration(value string, fallback time.Duration) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// parseDays parses a duration that may also be given in whole days (e.g., "90d").
+// An empty value yields fallback.
+func parseDays(value string, fallback time.Duration) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("expected a whole number of days")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+// parseRate parses a rate in requests per second such as "10" or "0.5" (0 means no
+// limit). Empty values use fallback.
+func parseRate(value string, fallback float64) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback, nil
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return 0, fmt.Errorf("expected requests per second such as 10 or 0.5, or 0 for no limit")
+	}
+	return rate, nil
+}
+
+// sizeUnits are the units accepted by parseSize, largest first.
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseSize parses a size such as "512KB", "2MB", or "2MiB" (units of 1024,
+// case-insensitive; a bare number is bytes). Empty values use fallback.
+func parseSize(value string, fallback int64) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return fallback, nil
+	}
+	if number, ok := strings.CutSuffix(value, "IB"); ok {
+		// MiB and friends are the same units
+		value = number + "B"
+	}
+
+	unit := int64(1)
+	for _, u := range sizeUnits {
+		if number, ok := strings.CutSuffix(value, u.suffix); ok {
+			value, unit = strings.TrimSpace(number), u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("expected a size such as 512KB or 2MB")
+	}
+	return n * unit, nil
+}
+
+// formatSize formats a size in the largest unit parseSize reads back exactly.
+func formatSize(size int64) string {
+	for _, u := range sizeUnits {
+		if size != 0 && size%u.bytes == 0 {
+			return fmt.Sprintf("%d%s", size/u.bytes, u.suffix)
+		}
+	}
+	return "0"
 }
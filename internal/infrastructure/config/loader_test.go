@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -258,8 +259,1231 @@ storage:
 	}
 }
 
+func TestLoader_Load_FullSyncSchedule(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+  full_sync_schedule: "0 3 * * *"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	loader := NewLoader()
+	cfg, err := loader.Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Sync.FullSyncSchedule.String() != "0 3 * * *" {
+		t.Errorf("Sync.FullSyncSchedule = %q, want %q", cfg.Sync.FullSyncSchedule.String(), "0 3 * * *")
+	}
+}
+
+func TestLoader_Load_Retry(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+  retry:
+    max_attempts: 5
+    max_backoff: 1h
+    retry_on: [Server, rate_limit]
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	retry := cfg.Sync.Retry
+	defaults := domain.DefaultRetryPolicy()
+	if retry.MaxAttempts != 5 || retry.MaxBackoff != time.Hour {
+		t.Errorf("Retry = %+v, want max_attempts 5 and max_backoff 1h", retry)
+	}
+	if retry.InitialBackoff != defaults.InitialBackoff || retry.Multiplier != defaults.Multiplier {
+		t.Errorf("Retry = %+v, want default initial_backoff and multiplier", retry)
+	}
+	want := []domain.ErrorClass{domain.ErrorClassServer, domain.ErrorClassRateLimit}
+	if !reflect.DeepEqual(retry.RetryOn, want) {
+		t.Errorf("Retry.RetryOn = %v, want %v", retry.RetryOn, want)
+	}
+}
+
+func TestLoader_Load_HTTP(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+  http:
+    timeout: 1m
+    write_timeout: 2m
+    proxy: "http://proxy.corp.example:3128"
+    ca_file: "/etc/ssl/corp-ca.pem"
+    rate_limit: 2.5
+    retry:
+      budget: 30s
+      unavailable:
+        max_retries: 0
+      timeouts:
+        base_delay: 2s
+        max_delay: 20s
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := domain.HTTPConfig{
+		ReadTimeout:    time.Minute,
+		WriteTimeout:   2 * time.Minute,
+		ConnectTimeout: domain.DefaultConnectTimeout,
+		Proxy:          "http://proxy.corp.example:3128",
+		CAFile:         "/etc/ssl/corp-ca.pem",
+		RateLimit:      2.5,
+		Retry:          domain.DefaultHTTPRetryPolicy(),
+	}
+	want.Retry.Budget = 30 * time.Second
+	want.Retry.Unavailable.MaxRetries = 0
+	want.Retry.Timeouts = domain.Backoff{MaxRetries: 1, BaseDelay: 2 * time.Second, MaxDelay: 20 * time.Second}
+	if cfg.Jira.HTTP != want {
+		t.Errorf("Jira.HTTP = %+v, want %+v", cfg.Jira.HTTP, want)
+	}
+}
+
+func TestLoader_Load_Retention(t *testing.T) {
+	tests := []struct {
+		name           string
+		storage        string
+		wantRetention  time.Duration
+		wantGCInterval time.Duration
+		wantErr        bool
+	}{
+		{
+			name:           "defaults",
+			storage:        ``,
+			wantRetention:  domain.DefaultRetention,
+			wantGCInterval: domain.DefaultGCInterval,
+		},
+		{
+			name:           "days",
+			storage:        "  retention: 30d\n  gc_interval: 1d\n",
+			wantRetention:  30 * 24 * time.Hour,
+			wantGCInterval: 24 * time.Hour,
+		},
+		{
+			name:           "durations",
+			storage:        "  retention: 720h\n  gc_interval: 0s\n",
+			wantRetention:  720 * time.Hour,
+			wantGCInterval: 0,
+		},
+		{
+			name:    "invalid days",
+			storage: "  retention: 1.5d\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			storage: "  gc_interval: daily\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+			configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+` + tt.storage
+
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+
+			cfg, err := NewLoader().Load(configPath)
+			if tt.wantErr {
+				if !isConfigError(err) {
+					t.Errorf("Load() error = %v, want *domain.ConfigError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if cfg.Storage.Retention != tt.wantRetention {
+				t.Errorf("Storage.Retention = %v, want %v", cfg.Storage.Retention, tt.wantRetention)
+			}
+			if cfg.Storage.GCInterval != tt.wantGCInterval {
+				t.Errorf("Storage.GCInterval = %v, want %v", cfg.Storage.GCInterval, tt.wantGCInterval)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_MediaCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	base := "jira:\n  base_url: \"https://example.atlassian.net\"\n  email: \"test@example.com\"\n  token: \"test-token\"\n" +
+		"sync:\n  markdown_dir: \"/tmp/tickets\"\nstorage:\n  db_path: \"/tmp/jiramd.db\"\n"
+	tests := []struct {
+		name     string
+		storage  string
+		wantDir  string
+		wantSize int64
+		wantErr  bool
+	}{
+		{name: "defaults", wantDir: "/xdg/cache/jiramd", wantSize: domain.DefaultCacheMaxSize},
+		{name: "configured", storage: "  cache_dir: /var/cache/jiramd\n  cache_max_size: 2GB\n", wantDir: "/var/cache/jiramd", wantSize: 2 << 30},
+		{name: "no limit", storage: "  cache_max_size: 0\n", wantDir: "/xdg/cache/jiramd", wantSize: 0},
+		{name: "invalid size", storage: "  cache_max_size: lots\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(base+tt.storage), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+
+			cfg, err := NewLoader().Load(configPath)
+			if tt.wantErr {
+				if !isConfigError(err) {
+					t.Errorf("Load() error = %v, want *domain.ConfigError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Storage.CacheDir != tt.wantDir || cfg.Storage.CacheMaxSize != tt.wantSize {
+				t.Errorf("Storage cache = %q, %d, want %q, %d", cfg.Storage.CacheDir, cfg.Storage.CacheMaxSize, tt.wantDir, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_StorageDriver(t *testing.T) {
+	t.Setenv("JIRAMD_TEST_DSN", "postgres://jiramd@db.example.com/jiramd")
+
+	tests := []struct {
+		name        string
+		storage     string
+		wantDriver  domain.StorageDriver
+		wantDSN     string
+		wantEncrypt bool
+	}{
+		{
+			name:       "defaults to sqlite",
+			storage:    ``,
+			wantDriver: domain.StorageDriverSQLite,
+		},
+		{
+			name:       "postgres with expanded dsn",
+			storage:    "  driver: Postgres\n  dsn: ${JIRAMD_TEST_DSN}\n",
+			wantDriver: domain.StorageDriverPostgres,
+			wantDSN:    "postgres://jiramd@db.example.com/jiramd",
+		},
+		{
+			name:        "encrypted sqlite",
+			storage:     "  encrypt: true\n",
+			wantDriver:  domain.StorageDriverSQLite,
+			wantEncrypt: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+			configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+` + tt.storage
+
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+
+			cfg, err := NewLoader().Load(configPath)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if cfg.Storage.Driver != tt.wantDriver {
+				t.Errorf("Storage.Driver = %q, want %q", cfg.Storage.Driver, tt.wantDriver)
+			}
+			if cfg.Storage.DSN != tt.wantDSN {
+				t.Errorf("Storage.DSN = %q, want %q", cfg.Storage.DSN, tt.wantDSN)
+			}
+			if cfg.Storage.Encrypt != tt.wantEncrypt {
+				t.Errorf("Storage.Encrypt = %v, want %v", cfg.Storage.Encrypt, tt.wantEncrypt)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_InvalidFullSyncSchedule(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+  full_sync_schedule: "0 25 * * *"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	loader := NewLoader()
+	_, err := loader.Load(configPath)
+	if err == nil {
+		t.Error("Load() expected error for invalid full_sync_schedule, got nil")
+	}
+
+	if !isConfigError(err) {
+		t.Errorf("Load() error type = %T, want *domain.ConfigError", err)
+	}
+}
+
 // Helper function to check if error is a ConfigError
 func isConfigError(err error) bool {
 	_, ok := err.(*domain.ConfigError)
 	return ok
 }
+
+func TestLoader_Load_LogLevel(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+
+log:
+  level: " DEBUG "
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Log.Level != domain.LogLevelDebug {
+		t.Errorf("Log.Level = %q, want %q", cfg.Log.Level, domain.LogLevelDebug)
+	}
+}
+
+func TestLoader_Load_Markdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     domain.MarkdownConfig
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			markdown: ``,
+			want:     domain.MarkdownConfig{Flavor: domain.MarkdownFlavorPlain, Frontmatter: domain.FrontmatterYAML},
+		},
+		{
+			name:     "obsidian with toml",
+			markdown: "markdown:\n  flavor: \" Obsidian \"\n  frontmatter: TOML\n  key_order: [title, \" key \"]\n  raw_fields: true\n  download_media: true\n  authors: true\n  max_comments: 200\n",
+			want: domain.MarkdownConfig{
+				Flavor:        domain.MarkdownFlavorObsidian,
+				Frontmatter:   domain.FrontmatterTOML,
+				KeyOrder:      []string{"title", "key"},
+				RawFields:     true,
+				DownloadMedia: true,
+				Authors:       true,
+				MaxComments:   200,
+			},
+		},
+		{
+			name:     "summarized",
+			markdown: "markdown:\n  max_description_length: 4000\n  max_comments: 50\n  project_limits:\n    OPS:\n      max_comments: 10\n      max_description_length: 1000\n",
+			want: domain.MarkdownConfig{
+				Flavor:               domain.MarkdownFlavorPlain,
+				Frontmatter:          domain.FrontmatterYAML,
+				MaxComments:          50,
+				MaxDescriptionLength: 4000,
+				ProjectLimits: map[string]domain.ContentLimits{
+					"OPS": {MaxDescriptionLength: 1000, MaxComments: 10},
+				},
+			},
+		},
+		{
+			name:     "limit not a number",
+			markdown: "markdown:\n  project_limits:\n    OPS:\n      max_comments: many\n",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown limit",
+			markdown: "markdown:\n  project_limits:\n    OPS:\n      max_attachments: 3\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+			configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+
+` + tt.markdown
+
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+
+			cfg, err := NewLoader().Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(cfg.Markdown, tt.want) {
+				t.Errorf("Markdown = %+v, want %+v", cfg.Markdown, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_API(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+
+api:
+  enabled: true
+  address: "127.0.0.1:7777"
+  auth: " none "
+  webhook_secret: "${JIRAMD_TEST_WEBHOOK_SECRET}"
+  tls:
+    cert: "~/.config/jiramd/server.crt"
+    key: "/etc/jiramd/server.key"
+    client_ca: "/etc/jiramd/ca.crt"
+    client_cert: "/etc/jiramd/client.crt"
+    client_key: "/etc/jiramd/client.key"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	t.Setenv("JIRAMD_TEST_WEBHOOK_SECRET", "hook-secret")
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := domain.APIConfig{
+		Enabled:       true,
+		Address:       "127.0.0.1:7777",
+		Auth:          domain.APIAuthNone,
+		WebhookSecret: "hook-secret",
+		TLS: domain.APITLSConfig{
+			CertFile:       filepath.Join(homeDir, ".config/jiramd/server.crt"),
+			KeyFile:        "/etc/jiramd/server.key",
+			ClientCAFile:   "/etc/jiramd/ca.crt",
+			ClientCertFile: "/etc/jiramd/client.crt",
+			ClientKeyFile:  "/etc/jiramd/client.key",
+		},
+	}
+	if !reflect.DeepEqual(cfg.API, want) {
+		t.Errorf("API = %+v, want %+v", cfg.API, want)
+	}
+}
+
+func TestLoader_Load_Display(t *testing.T) {
+	tests := []struct {
+		name         string
+		display      string
+		wantTimezone string
+		wantFormat   string
+		wantErr      bool
+	}{
+		{
+			name:         "defaults",
+			wantTimezone: time.Local.String(),
+			wantFormat:   domain.DefaultDateFormat,
+		},
+		{
+			name:         "configured",
+			display:      "display:\n  timezone: \" Europe/Berlin \"\n  date_format: \"02 Jan 15:04\"\n",
+			wantTimezone: "Europe/Berlin",
+			wantFormat:   "02 Jan 15:04",
+		},
+		{
+			name:    "unknown timezone",
+			display: "display:\n  timezone: Mars/Olympus\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+			configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+
+` + tt.display
+
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+
+			cfg, err := NewLoader().Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := cfg.Display.TimezoneName(); got != tt.wantTimezone {
+				t.Errorf("Display timezone = %q, want %q", got, tt.wantTimezone)
+			}
+			if cfg.Display.DateFormat != tt.wantFormat {
+				t.Errorf("Display.DateFormat = %q, want %q", cfg.Display.DateFormat, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_SyncFilters(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+  mode: Pull_Only
+  filters:
+    only_assignee: " me "
+    issue_types: [Bug, " Story ", ""]
+    exclude_labels: [wontfix]
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Sync.Mode != domain.SyncModePullOnly {
+		t.Errorf("Sync.Mode = %q, want %q", cfg.Sync.Mode, domain.SyncModePullOnly)
+	}
+
+	want := `project = "TEST" AND assignee = currentUser() AND issuetype IN ("Bug", "Story") AND (labels IS EMPTY OR labels NOT IN ("wontfix"))`
+	if got := cfg.Sync.Filter.ProjectJQL("TEST"); got != want {
+		t.Errorf("Sync.Filter.ProjectJQL() = %s\nwant %s", got, want)
+	}
+}
+
+func TestLoader_Load_StatusMap(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  markdown_dir: "/tmp/tickets"
+  status_map:
+    " In Bearbeitung ": "In Progress "
+  status_aliases:
+    wip: In Progress
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := domain.StatusMap{
+		Names:   map[string]string{"In Bearbeitung": "In Progress"},
+		Aliases: map[string]string{"wip": "In Progress"},
+	}
+	if !reflect.DeepEqual(cfg.Sync.Statuses, want) {
+		t.Errorf("Sync.Statuses = %+v, want %+v", cfg.Sync.Statuses, want)
+	}
+	if got := cfg.Sync.Statuses.ToJira("WIP"); got != "In Bearbeitung" {
+		t.Errorf("Sync.Statuses.ToJira(WIP) = %q, want In Bearbeitung", got)
+	}
+}
+
+func TestLoader_Load_PriorityMap(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  markdown_dir: "/tmp/tickets"
+  priority_map:
+    OPS:
+      " P0 ": "Blocker "
+      P1: Critical
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]domain.PriorityMap{"OPS": {"P0": "Blocker", "P1": "Critical"}}
+	if !reflect.DeepEqual(cfg.Sync.Priorities, want) {
+		t.Errorf("Sync.Priorities = %+v, want %+v", cfg.Sync.Priorities, want)
+	}
+	if got := cfg.Sync.PrioritiesFor("OPS").ToLocal("critical"); got != "P1" {
+		t.Errorf("PrioritiesFor(OPS).ToLocal(critical) = %q, want P1", got)
+	}
+	if got := cfg.Sync.PrioritiesFor("TEST").ToJira("P1"); got != "P1" {
+		t.Errorf("PrioritiesFor(TEST).ToJira(P1) = %q, want P1", got)
+	}
+}
+
+func TestLoader_Load_SprintScope(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+  sprint:
+    board_id: 42
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Sync.Sprint.Enabled() || cfg.Sync.Sprint.BoardID != 42 {
+		t.Errorf("Sync.Sprint.BoardID = %d, want 42", cfg.Sync.Sprint.BoardID)
+	}
+	if want := filepath.Join("/tmp/tickets", "archive"); cfg.Sync.Sprint.ArchiveDir != want {
+		t.Errorf("Sync.Sprint.ArchiveDir = %q, want default %q", cfg.Sync.Sprint.ArchiveDir, want)
+	}
+}
+
+func TestLoader_Load_Indexes(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+  indexes:
+    by_assignee: true
+    filters:
+      - id: 10042
+      - id: "10043"
+        name: Team board
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := domain.IndexOptions{
+		ByAssignee: true,
+		Filters:    []domain.FilterIndex{{ID: "10042"}, {ID: "10043", Name: "Team board"}},
+	}
+	if !reflect.DeepEqual(cfg.Sync.Indexes, want) {
+		t.Errorf("Sync.Indexes = %+v, want %+v", cfg.Sync.Indexes, want)
+	}
+}
+
+func TestLoader_Load_MetadataTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", want: domain.DefaultMetadataTTL},
+		{name: "configured", setting: "  metadata_ttl: 2h\n", want: 2 * time.Hour},
+		{name: "invalid", setting: "  metadata_ttl: often\n", wantErr: true},
+		{name: "negative", setting: "  metadata_ttl: -1h\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+` + tt.setting + `
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+
+			cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+			if tt.wantErr {
+				var configErr *domain.ConfigError
+				if !errors.As(err, &configErr) || len(configErr.Problems) != 1 || configErr.Problems[0].Key != "sync.metadata_ttl" {
+					t.Errorf("Load() error = %v, want a sync.metadata_ttl problem", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Sync.MetadataTTL != tt.want {
+				t.Errorf("Sync.MetadataTTL = %v, want %v", cfg.Sync.MetadataTTL, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_InvalidFilterIndexes(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+  indexes:
+    filters:
+      - name: No ID
+      - id: 10042
+        name: Board
+      - id: 10043
+        name: board
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	_, err := NewLoader().WithEnv(nil).Load(configPath)
+	var configErr *domain.ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 2 {
+		t.Errorf("Load() error = %v, want a ConfigError with the missing id and the clashing name", err)
+	}
+}
+
+func TestLoader_Load_Watchlist(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+
+watchlist:
+  - OPS-7
+  - " TEST-12 "
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var got []string
+	for _, key := range cfg.Watchlist {
+		got = append(got, key.String())
+	}
+	if want := []string{"OPS-7", "TEST-12"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Watchlist = %v, want %v", got, want)
+	}
+
+	invalid := configContent + "  - not a key\n  - OPS-7\n"
+	if err := os.WriteFile(configPath, []byte(invalid), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	_, err = NewLoader().WithEnv(nil).Load(configPath)
+	var configErr *domain.ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 2 {
+		t.Errorf("Load() error = %v, want a ConfigError with the invalid and the repeated key", err)
+	}
+}
+
+func TestLoader_Load_Reports(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+
+	load := func(content string) (*domain.Config, error) {
+		t.Helper()
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test config: %v", err)
+		}
+		return NewLoader().WithEnv(nil).Load(configPath)
+	}
+
+	cfg, err := load(configContent)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := domain.ReportsConfig{
+		Dir:          filepath.Join("/tmp/tickets", domain.ReportDir),
+		DoneStatuses: domain.DefaultDoneStatuses,
+		WIPStatuses:  domain.DefaultWIPStatuses,
+	}
+	if !reflect.DeepEqual(cfg.Reports, want) {
+		t.Errorf("Reports = %+v, want defaults %+v", cfg.Reports, want)
+	}
+
+	cfg, err = load(configContent + `
+reports:
+  schedule: "0 7 * * 1-5"
+  dir: "/tmp/reports"
+  done_statuses: [Done, " Won't Do "]
+  wip_statuses: [In Progress, In Review]
+`)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Reports.Schedule.String() != "0 7 * * 1-5" || cfg.Reports.Dir != "/tmp/reports" {
+		t.Errorf("Reports = %+v, want the configured schedule and dir", cfg.Reports)
+	}
+	if want := []string{"Done", "Won't Do"}; !reflect.DeepEqual(cfg.Reports.DoneStatuses, want) {
+		t.Errorf("DoneStatuses = %v, want %v", cfg.Reports.DoneStatuses, want)
+	}
+	if want := []string{"In Progress", "In Review"}; !reflect.DeepEqual(cfg.Reports.WIPStatuses, want) {
+		t.Errorf("WIPStatuses = %v, want %v", cfg.Reports.WIPStatuses, want)
+	}
+
+	_, err = load(configContent + "\nreports:\n  schedule: \"every day\"\n")
+	var configErr *domain.ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 1 || configErr.Problems[0].Key != "reports.schedule" {
+		t.Errorf("Load() error = %v, want a ConfigError about reports.schedule", err)
+	}
+}
+
+func TestLoader_Load_Guardrails(t *testing.T) {
+	tests := []struct {
+		name       string
+		guardrails string
+		want       domain.Guardrails
+		wantErr    bool
+	}{
+		{
+			name:       "defaults",
+			guardrails: ``,
+			want:       domain.DefaultGuardrails(),
+		},
+		{
+			name:       "limits",
+			guardrails: "  guardrails:\n    max_tickets_per_project: 500\n    max_file_size: 256kb\n    stale_after: 2d\n",
+			want: domain.Guardrails{
+				MaxTicketsPerProject: 500,
+				MaxFileSize:          256 << 10,
+				StaleAfter:           48 * time.Hour,
+			},
+		},
+		{
+			name:       "disabled",
+			guardrails: "  guardrails:\n    max_tickets_per_project: -1\n    max_file_size: 0\n    stale_after: 0\n",
+			want:       domain.Guardrails{},
+		},
+		{
+			name:       "mebibytes",
+			guardrails: "  guardrails:\n    max_file_size: 2MiB\n",
+			want: domain.Guardrails{
+				MaxTicketsPerProject: domain.DefaultMaxTicketsPerProject,
+				MaxFileSize:          2 << 20,
+				StaleAfter:           domain.DefaultStaleAfter,
+			},
+		},
+		{
+			name:       "invalid size",
+			guardrails: "  guardrails:\n    max_file_size: big\n",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+			configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+` + tt.guardrails
+
+			if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+
+			cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+			if tt.wantErr {
+				if !isConfigError(err) {
+					t.Errorf("Load() error = %v, want *domain.ConfigError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if cfg.Sync.Guardrails != tt.want {
+				t.Errorf("Sync.Guardrails = %+v, want %+v", cfg.Sync.Guardrails, tt.want)
+			}
+
+			// config show writes the effective guardrails back in a form that loads the same
+			shown := fromDomainConfig(cfg).Sync.Guardrails
+			if got := toGuardrails(&shown, &problems{}); got != tt.want {
+				t.Errorf("round trip = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_CommentAttribution(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://jira.corp.example"
+  email: "team@example.com"
+  token: "test-token"
+  project: "TEST"
+  comments:
+    author: " ${TEST_COMMENT_AUTHOR} "
+    footer: "— posted via jiramd on behalf of {author}"
+    impersonation_tokens:
+      alice: "${TEST_ALICE_PAT}"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	t.Setenv("TEST_COMMENT_AUTHOR", "bob")
+	t.Setenv("TEST_ALICE_PAT", "alice-pat")
+
+	cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := domain.CommentAttribution{
+		Author:              "bob",
+		Footer:              "— posted via jiramd on behalf of {author}",
+		ImpersonationTokens: map[string]string{"alice": "alice-pat"},
+	}
+	if !reflect.DeepEqual(cfg.Jira.Comments, want) {
+		t.Errorf("Comments = %+v, want %+v", cfg.Jira.Comments, want)
+	}
+}
+
+func TestLoader_Load_SharedVault(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+  shared_vault:
+    author_sources: [" Frontmatter ", "git"]
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []domain.AuthorSource{domain.AuthorFromFrontmatter, domain.AuthorFromGit}
+	if !reflect.DeepEqual(cfg.Sync.SharedVault.AuthorSources, want) {
+		t.Errorf("AuthorSources = %v, want %v", cfg.Sync.SharedVault.AuthorSources, want)
+	}
+}
+
+func TestLoader_Load_SyncPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  project: "TEST"
+
+sync:
+  interval: 5m
+  markdown_dir: "/tmp/tickets"
+  mode: pull_only
+  conflicts: " Newest_Wins "
+
+storage:
+  db_path: "/tmp/jiramd.db"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := NewLoader().WithEnv(nil).Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := domain.SyncPolicy{Mode: domain.SyncModePullOnly, Conflicts: domain.ConflictNewestWins}
+	if got := cfg.Sync.Policy(); got != want {
+		t.Errorf("Policy() = %+v, want %+v", got, want)
+	}
+}
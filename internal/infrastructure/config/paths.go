@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ProjectFileName is the project-local config file found by FindProjectConfig.
+const ProjectFileName = ".jiramd.yaml"
+
+// DefaultConfigPath returns the per-user config file, following the XDG base directory
+// specification: $XDG_CONFIG_HOME/jiramd/config.yaml, or ~/.config/jiramd/config.yaml
+// when XDG_CONFIG_HOME is unset.
+func DefaultConfigPath() string {
+	return filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), "jiramd", "config.yaml")
+}
+
+// DefaultDataDir returns the per-user directory for jiramd's data: $XDG_DATA_HOME/jiramd,
+// or ~/.local/share/jiramd when XDG_DATA_HOME is unset.
+func DefaultDataDir() string {
+	return filepath.Join(xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share")), "jiramd")
+}
+
+// DefaultDBPath returns the state database used when storage.db_path is not configured.
+func DefaultDBPath() string {
+	return filepath.Join(DefaultDataDir(), "state.db")
+}
+
+// DefaultCacheDir returns the directory downloaded media is cached in when
+// storage.cache_dir is not configured: $XDG_CACHE_HOME/jiramd, or ~/.cache/jiramd when
+// XDG_CACHE_HOME is unset.
+func DefaultCacheDir() string {
+	return filepath.Join(xdgDir("XDG_CACHE_HOME", ".cache"), "jiramd")
+}
+
+// FindProjectConfig searches dir and its parents for ProjectFileName, returning the
+// path of the nearest one.
+func FindProjectConfig(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// xdgDir returns the directory named by the XDG environment variable, or fallback under
+// the home directory when it is unset or not absolute (as the specification requires).
+func xdgDir(envVar, fallback string) string {
+	if dir := os.Getenv(envVar); filepath.IsAbs(dir) {
+		return dir
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, fallback)
+	}
+	// Left for expandHomePath to report when the path is used
+	return filepath.Join("~", fallback)
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultPaths(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+
+	if got := DefaultConfigPath(); got != "/xdg/config/jiramd/config.yaml" {
+		t.Errorf("DefaultConfigPath() = %q", got)
+	}
+	if got := DefaultDBPath(); got != "/xdg/data/jiramd/state.db" {
+		t.Errorf("DefaultDBPath() = %q", got)
+	}
+	if got := DefaultCacheDir(); got != "/xdg/cache/jiramd" {
+		t.Errorf("DefaultCacheDir() = %q", got)
+	}
+
+	// Relative XDG directories are ignored, as the specification requires
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "relative/data")
+	if got, want := DefaultDataDir(), filepath.Join(home, ".local", "share", "jiramd"); got != want {
+		t.Errorf("DefaultDataDir() = %q, want %q", got, want)
+	}
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("failed to create dirs: %v", err)
+	}
+
+	if path, ok := FindProjectConfig(nested); ok {
+		t.Fatalf("FindProjectConfig() found %q before one was written", path)
+	}
+
+	want := filepath.Join(root, "a", ProjectFileName)
+	if err := os.WriteFile(want, []byte("jira: {}\n"), 0644); err != nil {
+		t.Fatalf("failed to write project config: %v", err)
+	}
+	if path, ok := FindProjectConfig(nested); !ok || path != want {
+		t.Errorf("FindProjectConfig() = %q, %v, want %q", path, ok, want)
+	}
+}
+
+func TestLoader_Load_DefaultDBPath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+
+	cfg, err := NewLoader().WithEnv(nil).WithOptionalFile().Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Storage.DBPath != "/xdg/data/jiramd/state.db" {
+		t.Errorf("Storage.DBPath = %q, want the XDG default", cfg.Storage.DBPath)
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// problems collects configuration problems so every one can be reported at once
+// instead of stopping at the first.
+type problems []domain.ConfigProblem
+
+// add records a problem with the setting key.
+func (p *problems) add(key, format string, args ...any) {
+	*p = append(*p, domain.ConfigProblem{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// addAt records a problem with the setting key on a line of the config file.
+func (p *problems) addAt(key string, line int, format string, args ...any) {
+	*p = append(*p, domain.ConfigProblem{Key: key, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// has reports whether a problem with the setting key was recorded.
+func (p problems) has(key string) bool {
+	for _, problem := range p {
+		if problem.Key == key {
+			return true
+		}
+	}
+	return false
+}
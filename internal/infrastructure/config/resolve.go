@@ -0,0 +1,649 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Source is the layer a resolved setting came from. Layers take precedence in the order
+// flag > env > file > default.
+type Source string
+
+const (
+	// SourceDefault marks a setting nobody set; it has jiramd's default value
+	SourceDefault Source = "default"
+
+	// SourceFile marks a setting read from the config file
+	SourceFile Source = "file"
+
+	// SourceEnv marks a setting read from a JIRAMD_* environment variable
+	SourceEnv Source = "env"
+
+	// SourceFlag marks a setting given on the command line
+	SourceFlag Source = "flag"
+)
+
+// EnvPrefix starts the name of every environment variable that overrides a setting.
+const EnvPrefix = "JIRAMD_"
+
+// envAliases are the short environment variables documented before every setting had
+// one, accepted in addition to EnvName of the setting. The canonical name wins when both
+// are set.
+var envAliases = map[string]string{
+	"jira.base_url": "JIRAMD_BASE_URL",
+	"jira.email":    "JIRAMD_EMAIL",
+	"jira.token":    "JIRAMD_API_TOKEN",
+	"jira.project":  "JIRAMD_PROJECT",
+}
+
+// secretKeys are settings whose values are masked by Resolution.Settings.
+var secretKeys = map[string]bool{
+	"jira.token":                         true,
+	"jira.http.proxy":                    true,
+	"jira.comments.impersonation_tokens": true,
+	"storage.dsn":                        true,
+}
+
+// derivedKeys map settings to the shorthand setting that provides their value when
+// they are not set themselves.
+var derivedKeys = map[string]string{
+	"jira.http.read_timeout":  "jira.http.timeout",
+	"jira.http.write_timeout": "jira.http.timeout",
+}
+
+// Mask replaces secret values in Resolution.Settings.
+const Mask = "********"
+
+// EnvName returns the environment variable that overrides the setting key, e.g.
+// JIRAMD_SYNC_INTERVAL for sync.interval.
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Keys returns the dotted keys of every setting, in config file order.
+func Keys() []string {
+	var keys []string
+	walkSettings(reflect.ValueOf(&yamlConfig{}).Elem(), "", func(key string, _ reflect.Value) {
+		keys = append(keys, key)
+	})
+	return keys
+}
+
+// Setting is one setting of a resolved configuration.
+type Setting struct {
+	// Key is the dotted setting name, e.g. sync.interval
+	Key string `json:"key"`
+
+	// Value is the effective value, masked for secrets (lists are comma-separated)
+	Value string `json:"value"`
+
+	// Source is the layer the value came from
+	Source Source `json:"source"`
+}
+
+// Resolution is a configuration resolved from flags, environment variables, the config
+// file, and defaults.
+type Resolution struct {
+	// Config is the effective configuration
+	Config *domain.Config
+
+	// Path is the config file path with ~ expanded
+	Path string
+
+	// FileFound reports whether the config file existed; a missing optional file
+	// contributes nothing
+	FileFound bool
+
+	// Sources records the layer of every setting that was set; other settings have
+	// their default
+	Sources map[string]Source
+
+	// Lines records the line of the config file that sets each setting and section
+	// it sets
+	Lines map[string]int
+
+	// Problems lists every problem found while resolving, such as unknown settings and
+	// values that do not parse; Config is best-effort when there are any
+	Problems []domain.ConfigProblem
+}
+
+// Err returns the problems found while resolving as one domain.ConfigError, or nil.
+func (r *Resolution) Err() error {
+	return report(r.Problems, r.Path)
+}
+
+// Check validates the resolved configuration with validator, returning every problem
+// found while resolving or validating as one domain.ConfigError with the file lines of
+// the settings involved. A setting that already failed to resolve is not reported
+// again by validation.
+func (r *Resolution) Check(validator domain.ConfigValidator) error {
+	all := append([]domain.ConfigProblem(nil), r.Problems...)
+
+	var configErr *domain.ConfigError
+	if err := validator.Validate(r.Config); errors.As(err, &configErr) {
+		resolved := problems(r.Problems)
+		for _, problem := range configErr.WithLines(r.Path, r.fileLines()).Problems {
+			if problem.Key == "" || !resolved.has(problem.Key) {
+				all = append(all, problem)
+			}
+		}
+	} else if err != nil {
+		return err
+	}
+
+	return report(all, r.Path)
+}
+
+// fileLines returns the lines of the sections and settings whose value came from the
+// file, leaving out settings overridden by the environment or flags.
+func (r *Resolution) fileLines() map[string]int {
+	lines := make(map[string]int, len(r.Lines))
+	for key, line := range r.Lines {
+		if source, ok := r.Sources[key]; !ok || source == SourceFile {
+			lines[key] = line
+		}
+	}
+	return lines
+}
+
+// report returns the problems as one domain.ConfigError for file, in file order with
+// problems not tied to a line last, or nil when there are none.
+func report(found []domain.ConfigProblem, file string) error {
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Line == 0 || found[j].Line == 0 {
+			return found[i].Line != 0 && found[j].Line == 0
+		}
+		return found[i].Line < found[j].Line
+	})
+
+	err := domain.NewConfigProblems(found)
+	var configErr *domain.ConfigError
+	if errors.As(err, &configErr) {
+		configErr.File = file
+	}
+	return err
+}
+
+// Source returns the layer the setting key came from.
+func (r *Resolution) Source(key string) Source {
+	if source, ok := r.Sources[key]; ok {
+		return source
+	}
+	if shorthand, ok := derivedKeys[key]; ok {
+		if source, ok := r.Sources[shorthand]; ok {
+			return source
+		}
+	}
+	return SourceDefault
+}
+
+// Settings returns the effective value and source of every setting in config file
+// order, with secrets masked. Shorthand settings are omitted in favor of the settings
+// they expand to.
+func (r *Resolution) Settings() []Setting {
+	effective := fromDomainConfig(r.Config)
+
+	shorthand := make(map[string]bool, len(derivedKeys))
+	for _, key := range derivedKeys {
+		shorthand[key] = true
+	}
+
+	var settings []Setting
+	walkSettings(reflect.ValueOf(effective).Elem(), "", func(key string, field reflect.Value) {
+		if shorthand[key] {
+			return
+		}
+		value := formatSetting(field)
+		if secretKeys[key] {
+			value = maskSecret(value)
+		}
+		settings = append(settings, Setting{Key: key, Value: value, Source: r.Source(key)})
+	})
+	return settings
+}
+
+// walkSettings calls fn for each leaf field of the yaml struct v with its dotted key.
+func walkSettings(v reflect.Value, prefix string, fn func(key string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			walkSettings(field, key, fn)
+			continue
+		}
+		fn(key, field)
+	}
+}
+
+// applySettings sets the yaml fields named in values, recording source for each one.
+// Unknown keys and values of the wrong type are recorded in found.
+func applySettings(cfg *yamlConfig, values map[string]string, source Source, sources map[string]Source, found *problems) {
+	if len(values) == 0 {
+		return
+	}
+
+	fields := make(map[string]reflect.Value)
+	walkSettings(reflect.ValueOf(cfg).Elem(), "", func(key string, field reflect.Value) {
+		fields[key] = field
+	})
+
+	// Apply in a stable order so problems are reported in the same order every time
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		origin := "--set " + key
+		if source == SourceEnv {
+			origin = EnvName(key)
+		}
+
+		field, ok := fields[key]
+		if !ok {
+			found.add(key, "%s: unknown setting %s", origin, key)
+			continue
+		}
+		if err := parseSetting(field, values[key]); err != nil {
+			found.add(key, "%s: invalid %s '%s': %v", origin, key, values[key], err)
+			continue
+		}
+		sources[key] = source
+	}
+}
+
+// envSettings collects the settings overridden by environment variables.
+func envSettings(lookup func(string) (string, bool)) map[string]string {
+	values := make(map[string]string)
+	if lookup == nil {
+		return values
+	}
+	for _, key := range Keys() {
+		if value, ok := lookup(EnvName(key)); ok {
+			values[key] = value
+		} else if alias, ok := envAliases[key]; ok {
+			if value, ok := lookup(alias); ok {
+				values[key] = value
+			}
+		}
+	}
+	return values
+}
+
+// parseSetting sets a yaml field from its string form. Lists are comma-separated.
+func parseSetting(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("expected true or false")
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("expected a whole number")
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("expected a number")
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		items := make([]string, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		entries := reflect.MakeMap(field.Type())
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			if err := setMapEntry(entries, item); err != nil {
+				return err
+			}
+		}
+		field.Set(entries)
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Kind())
+	}
+	return nil
+}
+
+// formatSetting returns the string form of a yaml field, as accepted by parseSetting.
+func formatSetting(field reflect.Value) string {
+	switch field.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool())
+	case reflect.Int:
+		return strconv.FormatInt(field.Int(), 10)
+	case reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'g', -1, 64)
+	case reflect.Slice:
+		items := make([]string, field.Len())
+		for i := range items {
+			items[i] = field.Index(i).String()
+		}
+		return strings.Join(items, ",")
+	case reflect.Map:
+		var entries []string
+		iter := field.MapRange()
+		for iter.Next() {
+			if iter.Value().Kind() == reflect.Map {
+				// Nested mappings are flattened to outer.inner=value
+				for _, entry := range strings.Split(formatSetting(iter.Value()), ",") {
+					if entry != "" {
+						entries = append(entries, iter.Key().String()+"."+entry)
+					}
+				}
+				continue
+			}
+			entries = append(entries, iter.Key().String()+"="+iter.Value().String())
+		}
+		sort.Strings(entries)
+		return strings.Join(entries, ",")
+	default:
+		return field.String()
+	}
+}
+
+// setMapEntry adds a name=value entry to a mapping setting. In a mapping of mappings
+// the name is outer.inner, e.g. OPS.labels=jira_to_local.
+func setMapEntry(entries reflect.Value, item string) error {
+	name, value, ok := strings.Cut(item, "=")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value entries, got '%s'", item)
+	}
+
+	if entries.Type().Elem().Kind() != reflect.Map {
+		entries.SetMapIndex(reflect.ValueOf(name), reflect.ValueOf(value))
+		return nil
+	}
+
+	outer, inner, ok := strings.Cut(name, ".")
+	if !ok || outer == "" || inner == "" {
+		return fmt.Errorf("expected outer.name=value entries, got '%s'", item)
+	}
+	nested := entries.MapIndex(reflect.ValueOf(outer))
+	if !nested.IsValid() {
+		nested = reflect.MakeMap(entries.Type().Elem())
+		entries.SetMapIndex(reflect.ValueOf(outer), nested)
+	}
+	return setMapEntry(nested, inner+"="+value)
+}
+
+// dsnPasswordPattern matches the password of a key=value PostgreSQL connection string.
+var dsnPasswordPattern = regexp.MustCompile(`(password=)('[^']*'|\S+)`)
+
+// maskSecret hides a secret value. Credentials embedded in a URL or connection string
+// are masked and the rest is kept, so the host stays visible.
+func maskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+			return strings.Replace(u.String(), "xxxxx", Mask, 1)
+		}
+		return value
+	}
+	if dsnPasswordPattern.MatchString(value) {
+		return dsnPasswordPattern.ReplaceAllString(value, "${1}"+Mask)
+	}
+	return Mask
+}
+
+// fromDomainConfig converts an effective domain.Config back to its yaml form, so every
+// setting can be shown with defaults applied.
+func fromDomainConfig(cfg *domain.Config) *yamlConfig {
+	retry := cfg.Sync.RetryPolicy()
+	httpRetry := cfg.Jira.HTTP.RetryPolicy()
+	retryOn := make([]string, 0, len(retry.RetryOn))
+	for _, class := range retry.RetryOn {
+		retryOn = append(retryOn, string(class))
+	}
+
+	// A disabled ticket limit is written the way it is configured, since 0 means the default
+	maxTicketsPerProject := cfg.Sync.Guardrails.MaxTicketsPerProject
+	if maxTicketsPerProject == 0 {
+		maxTicketsPerProject = -1
+	}
+
+	return &yamlConfig{
+		Jira: yamlJiraConfig{
+			BaseURL: cfg.Jira.BaseURL,
+			Email:   cfg.Jira.Email,
+			Token:   cfg.Jira.Token,
+			Project: cfg.Jira.Project,
+			HTTP: yamlHTTPConfig{
+				ReadTimeout:    cfg.Jira.HTTP.ReadTimeout.String(),
+				WriteTimeout:   cfg.Jira.HTTP.WriteTimeout.String(),
+				ConnectTimeout: cfg.Jira.HTTP.ConnectTimeout.String(),
+				Proxy:          cfg.Jira.HTTP.Proxy,
+				CAFile:         cfg.Jira.HTTP.CAFile,
+				ClientCert:     cfg.Jira.HTTP.ClientCertFile,
+				ClientKey:      cfg.Jira.HTTP.ClientKeyFile,
+				RateLimit:      strconv.FormatFloat(cfg.Jira.HTTP.RateLimit, 'f', -1, 64),
+				Retry: yamlHTTPRetryConfig{
+					Budget:      httpRetry.Budget.String(),
+					RateLimited: fromBackoff(httpRetry.RateLimited),
+					Unavailable: fromBackoff(httpRetry.Unavailable),
+					Timeouts:    fromBackoff(httpRetry.Timeouts),
+				},
+			},
+			Comments: yamlCommentsConfig{
+				Author:              cfg.Jira.Comments.Author,
+				Footer:              cfg.Jira.Comments.Footer,
+				ImpersonationTokens: cfg.Jira.Comments.ImpersonationTokens,
+			},
+		},
+		Sync: yamlSyncConfig{
+			Interval:         cfg.Sync.Interval.String(),
+			MarkdownDir:      cfg.Sync.MarkdownDir,
+			WatchEnabled:     cfg.Sync.WatchEnabled,
+			Mode:             string(cfg.Sync.Mode),
+			Conflicts:        string(cfg.Sync.Conflicts),
+			FullSyncSchedule: cfg.Sync.FullSyncSchedule.String(),
+			Retry: yamlRetryConfig{
+				MaxAttempts:    retry.MaxAttempts,
+				InitialBackoff: retry.InitialBackoff.String(),
+				MaxBackoff:     retry.MaxBackoff.String(),
+				Multiplier:     retry.Multiplier,
+				RetryOn:        retryOn,
+			},
+			Filters: yamlSyncFilters{
+				OnlyAssignee:  cfg.Sync.Filter.OnlyAssignee,
+				IssueTypes:    cfg.Sync.Filter.IssueTypes,
+				ExcludeLabels: cfg.Sync.Filter.ExcludeLabels,
+			},
+			Sprint: yamlSprintConfig{
+				BoardID:    cfg.Sync.Sprint.BoardID,
+				ArchiveDir: cfg.Sync.Sprint.ArchiveDir,
+			},
+			Indexes: yamlIndexesConfig{
+				BySprint:   cfg.Sync.Indexes.BySprint,
+				ByAssignee: cfg.Sync.Indexes.ByAssignee,
+				Filters:    fromFilterIndexes(cfg.Sync.Indexes.Filters),
+			},
+			MetadataTTL: cfg.Sync.MetadataTTL.String(),
+			Guardrails: yamlGuardrailsConfig{
+				MaxTicketsPerProject: maxTicketsPerProject,
+				MaxFileSize:          formatSize(cfg.Sync.Guardrails.MaxFileSize),
+				StaleAfter:           cfg.Sync.Guardrails.StaleAfter.String(),
+			},
+			FieldDirections:        fromFieldDirections(cfg.Sync.FieldDirections),
+			ProjectFieldDirections: fromProjectFieldDirections(cfg.Sync.ProjectFieldDirections),
+			StatusMap:              cfg.Sync.Statuses.Names,
+			StatusAliases:          cfg.Sync.Statuses.Aliases,
+			PriorityMap:            fromPriorityMaps(cfg.Sync.Priorities),
+			SharedVault: yamlSharedVaultConfig{
+				AuthorSources: fromAuthorSources(cfg.Sync.SharedVault.AuthorSources),
+			},
+		},
+		Storage: yamlStorageConfig{
+			DBPath:       cfg.Storage.DBPath,
+			Encrypt:      cfg.Storage.Encrypt,
+			Driver:       string(cfg.Storage.Driver),
+			DSN:          cfg.Storage.DSN,
+			Retention:    formatDays(cfg.Storage.Retention),
+			GCInterval:   formatDays(cfg.Storage.GCInterval),
+			CacheDir:     cfg.Storage.CacheDir,
+			CacheMaxSize: formatSize(cfg.Storage.CacheMaxSize),
+		},
+		API: yamlAPIConfig{
+			Enabled: cfg.API.Enabled,
+			Address: cfg.API.Address,
+			Socket:  cfg.API.SocketPath,
+			Auth:    string(cfg.API.Auth),
+			TLS: yamlAPITLSConfig{
+				Cert:       cfg.API.TLS.CertFile,
+				Key:        cfg.API.TLS.KeyFile,
+				ClientCA:   cfg.API.TLS.ClientCAFile,
+				ClientCert: cfg.API.TLS.ClientCertFile,
+				ClientKey:  cfg.API.TLS.ClientKeyFile,
+			},
+		},
+		Notify: yamlNotifyConfig{
+			Command: cfg.Notify.Command,
+		},
+		Log: yamlLogConfig{
+			Level: cfg.Log.Level,
+		},
+		Markdown: yamlMarkdownConfig{
+			Flavor:               string(cfg.Markdown.Flavor),
+			Frontmatter:          string(cfg.Markdown.Frontmatter),
+			KeyOrder:             cfg.Markdown.KeyOrder,
+			RawFields:            cfg.Markdown.RawFields,
+			DownloadMedia:        cfg.Markdown.DownloadMedia,
+			Authors:              cfg.Markdown.Authors,
+			MaxComments:          cfg.Markdown.MaxComments,
+			MaxDescriptionLength: cfg.Markdown.MaxDescriptionLength,
+			ProjectLimits:        fromProjectLimits(cfg.Markdown.ProjectLimits),
+		},
+		Display: yamlDisplayConfig{
+			Timezone:   cfg.Display.TimezoneName(),
+			DateFormat: cfg.Display.DateFormat,
+		},
+		Reports: yamlReportsConfig{
+			Schedule:     cfg.Reports.Schedule.String(),
+			Dir:          cfg.Reports.Dir,
+			DoneStatuses: cfg.Reports.DoneStatuses,
+			WIPStatuses:  cfg.Reports.WIPStatuses,
+		},
+		Watchlist: fromWatchlist(cfg.Watchlist),
+	}
+}
+
+// fromWatchlist converts the watch list back to its yaml form.
+func fromWatchlist(keys []domain.TicketKey) []string {
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, key.String())
+	}
+	return values
+}
+
+// fromAuthorSources converts the shared vault's author sources back to their yaml form.
+func fromAuthorSources(sources []domain.AuthorSource) []string {
+	values := make([]string, 0, len(sources))
+	for _, source := range sources {
+		values = append(values, string(source))
+	}
+	return values
+}
+
+// fromFieldDirections converts field direction overrides back to their yaml form.
+func fromFieldDirections(directions domain.FieldDirections) map[string]string {
+	yamlDirections := make(map[string]string, len(directions))
+	for field, direction := range directions {
+		yamlDirections[field] = string(direction)
+	}
+	return yamlDirections
+}
+
+// fromProjectFieldDirections converts per-project field direction overrides back to their yaml form.
+func fromProjectFieldDirections(projects map[string]domain.FieldDirections) map[string]map[string]string {
+	yamlProjects := make(map[string]map[string]string, len(projects))
+	for project, directions := range projects {
+		yamlProjects[project] = fromFieldDirections(directions)
+	}
+	return yamlProjects
+}
+
+// fromPriorityMaps converts per-project priority maps back to their yaml form.
+func fromPriorityMaps(projects map[string]domain.PriorityMap) map[string]map[string]string {
+	yamlProjects := make(map[string]map[string]string, len(projects))
+	for project, priorities := range projects {
+		yamlProjects[project] = priorities
+	}
+	return yamlProjects
+}
+
+// fromProjectLimits converts per-project content limits back to their yaml form.
+func fromProjectLimits(projects map[string]domain.ContentLimits) map[string]map[string]string {
+	yamlProjects := make(map[string]map[string]string, len(projects))
+	for project, limits := range projects {
+		yamlProjects[project] = map[string]string{
+			"max_description_length": strconv.Itoa(limits.MaxDescriptionLength),
+			"max_comments":           strconv.Itoa(limits.MaxComments),
+		}
+	}
+	return yamlProjects
+}
+
+// fromFilterIndexes converts saved filter indexes back to their yaml form.
+func fromFilterIndexes(filters []domain.FilterIndex) []yamlFilterIndexConfig {
+	var yamlFilters []yamlFilterIndexConfig
+	for _, filter := range filters {
+		yamlFilters = append(yamlFilters, yamlFilterIndexConfig{ID: filter.ID, Name: filter.Name})
+	}
+	return yamlFilters
+}
+
+// formatDays formats a duration in whole days when it is one, the inverse of parseDays.
+func formatDays(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d > 0 && d%day == 0 {
+		return strconv.FormatInt(int64(d/day), 10) + "d"
+	}
+	return d.String()
+}
+
+// fromBackoff converts a request retry backoff back to its yaml form.
+func fromBackoff(backoff domain.Backoff) yamlBackoffConfig {
+	return yamlBackoffConfig{
+		MaxRetries: strconv.Itoa(backoff.MaxRetries),
+		BaseDelay:  backoff.BaseDelay.String(),
+		MaxDelay:   backoff.MaxDelay.String(),
+	}
+}
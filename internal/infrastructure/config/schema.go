@@ -0,0 +1,231 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeStrict decodes a parsed config file into cfg, checking it against the known
+// settings. Unknown keys (with a suggestion when one is probably misspelled),
+// duplicate keys, and values of the wrong type are all recorded in found rather than
+// stopping at the first. Returns the line that sets each known setting and section.
+func decodeStrict(document *yaml.Node, cfg *yamlConfig, found *problems) map[string]int {
+	lines := make(map[string]int)
+	if document == nil || len(document.Content) == 0 {
+		// Empty file
+		return lines
+	}
+
+	root := document.Content[0]
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return lines
+	}
+	decodeSection(root, reflect.ValueOf(cfg).Elem(), "", lines, found)
+	return lines
+}
+
+// decodeSection decodes a mapping node into the yaml struct v, whose settings are
+// named with prefix.
+func decodeSection(node *yaml.Node, v reflect.Value, prefix string, lines map[string]int, found *problems) {
+	if node.Kind != yaml.MappingNode {
+		found.addAt(prefix, node.Line, "%s must be a mapping of settings", sectionName(prefix))
+		return
+	}
+
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			fields[name] = v.Field(i)
+		}
+	}
+
+	seen := make(map[string]int)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		name := keyNode.Value
+		key := joinKey(prefix, name)
+
+		if first, ok := seen[name]; ok {
+			found.addAt(key, keyNode.Line, "%s is set twice (first on line %d)", key, first)
+			continue
+		}
+		seen[name] = keyNode.Line
+
+		field, ok := fields[name]
+		if !ok {
+			found.addAt(key, keyNode.Line, "unknown setting %s%s", key, suggestKey(prefix, name, fields))
+			continue
+		}
+
+		lines[key] = keyNode.Line
+		if field.Kind() == reflect.Struct {
+			if valueNode.Tag == "!!null" {
+				// An empty section, e.g. "http:" with everything commented out
+				continue
+			}
+			decodeSection(valueNode, field, key, lines, found)
+			continue
+		}
+
+		if valueNode.Tag == "!!null" {
+			continue
+		}
+		if err := decodeSetting(valueNode, field); err != nil {
+			found.addAt(key, valueNode.Line, "%s %s", key, err)
+		}
+	}
+}
+
+// decodeSetting decodes a leaf value node into the yaml field, describing the
+// expected type when the value does not fit.
+func decodeSetting(node *yaml.Node, field reflect.Value) error {
+	var want string
+	switch field.Kind() {
+	case reflect.String:
+		want = "must be a single value"
+	case reflect.Bool:
+		want = "must be true or false"
+	case reflect.Int:
+		want = "must be a whole number"
+	case reflect.Float64:
+		want = "must be a number"
+	case reflect.Slice:
+		want = "must be a list"
+	case reflect.Map:
+		want = "must map names to values"
+		if field.Type().Elem().Kind() == reflect.Map {
+			want = "must map names to mappings of names to values"
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return fmt.Errorf("%s, got %s", want, describeNode(node))
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s, got %s", want, describeNode(node))
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			return fmt.Errorf("%s, got %s", want, describeNode(node))
+		}
+	}
+
+	target := reflect.New(field.Type())
+	if err := node.Decode(target.Interface()); err != nil {
+		if field.Kind() == reflect.Map {
+			// The mapping itself is fine; one of its entries is not
+			return fmt.Errorf("%s", want)
+		}
+		return fmt.Errorf("%s, got %s", want, describeNode(node))
+	}
+	field.Set(target.Elem())
+	return nil
+}
+
+// describeNode describes a value node for error messages.
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return fmt.Sprintf("'%s'", node.Value)
+	}
+}
+
+// suggestKey returns a " (did you mean ...?)" hint for an unknown setting name: a
+// sibling it is a likely misspelling of, or a setting of that name in another section.
+func suggestKey(prefix, name string, siblings map[string]reflect.Value) string {
+	best, bestDistance := "", 0
+	for sibling := range siblings {
+		distance := editDistance(strings.ToLower(name), sibling)
+		if distance <= maxTypos(sibling) && (best == "" || distance < bestDistance || (distance == bestDistance && sibling < best)) {
+			best, bestDistance = sibling, distance
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf(" (did you mean %s?)", joinKey(prefix, best))
+	}
+
+	for _, key := range Keys() {
+		if strings.HasSuffix(key, "."+name) || key == name {
+			return fmt.Sprintf(" (did you mean %s?)", key)
+		}
+	}
+	for _, section := range sectionKeys() {
+		if strings.HasSuffix(section, "."+name) || section == name {
+			return fmt.Sprintf(" (did you mean %s?)", section)
+		}
+	}
+	return ""
+}
+
+// sectionKeys returns the dotted names of every section, e.g. sync.retry.
+func sectionKeys() []string {
+	var sections []string
+	seen := make(map[string]bool)
+	for _, key := range Keys() {
+		for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+			section := key[:i]
+			if !seen[section] {
+				seen[section] = true
+				sections = append(sections, section)
+			}
+		}
+	}
+	return sections
+}
+
+// maxTypos is how many edits a name may be from a setting to be suggested as it.
+func maxTypos(name string) int {
+	if len(name) <= 4 {
+		return 1
+	}
+	return 2
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// joinKey appends a setting name to a section prefix.
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// sectionName names a section for error messages ("the config file" for the root).
+func sectionName(prefix string) string {
+	if prefix == "" {
+		return "the config file"
+	}
+	return prefix
+}
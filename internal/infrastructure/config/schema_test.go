@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestLoader_Load_Strict(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `jira:
+  base_url: "https://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+  projet: "TEST"
+sync:
+  intervall: 5m
+  markdown_dir: "/tmp/tickets"
+  watch_enabled: maybe
+  retry:
+    retry_on: network
+storage:
+  db_path: "/tmp/jiramd.db"
+  db_path: "/tmp/other.db"
+interval: 5m
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	_, err := NewLoader().WithEnv(nil).Load(configPath)
+	var configErr *domain.ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("Load() error = %v, want *domain.ConfigError", err)
+	}
+
+	want := []domain.ConfigProblem{
+		{Key: "jira.projet", Line: 5, Message: "unknown setting jira.projet (did you mean jira.project?)"},
+		{Key: "sync.intervall", Line: 7, Message: "unknown setting sync.intervall (did you mean sync.interval?)"},
+		{Key: "sync.watch_enabled", Line: 9, Message: "sync.watch_enabled must be true or false, got 'maybe'"},
+		{Key: "sync.retry.retry_on", Line: 11, Message: "sync.retry.retry_on must be a list, got 'network'"},
+		{Key: "storage.db_path", Line: 14, Message: "storage.db_path is set twice (first on line 13)"},
+		{Key: "interval", Line: 15, Message: "unknown setting interval (did you mean sync.interval?)"},
+	}
+	if len(configErr.Problems) != len(want) {
+		t.Fatalf("Load() problems = %+v, want %d", configErr.Problems, len(want))
+	}
+	for i, problem := range configErr.Problems {
+		if problem != want[i] {
+			t.Errorf("problem %d = %+v, want %+v", i, problem, want[i])
+		}
+	}
+	if configErr.File != configPath {
+		t.Errorf("File = %q, want %q", configErr.File, configPath)
+	}
+	if !strings.Contains(err.Error(), configPath+":5: unknown setting jira.projet") {
+		t.Errorf("Error() = %q, want file and line context", err.Error())
+	}
+}
+
+func TestResolution_Check(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `jira:
+  base_url: "http://example.atlassian.net"
+  email: "test@example.com"
+  token: "test-token"
+sync:
+  interval: soon
+  markdown_dir: "/tmp/tickets"
+log:
+  level: loud
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	resolution, err := NewLoader().WithEnv(nil).Resolve(configPath)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	var configErr *domain.ConfigError
+	if err := resolution.Check(NewValidator()); !errors.As(err, &configErr) {
+		t.Fatalf("Check() error = %v, want *domain.ConfigError", err)
+	}
+
+	// The unparsable interval is reported once, not again as not positive
+	wantLines := map[string]int{
+		"jira.base_url": 2,
+		"sync.interval": 6,
+		"log.level":     9,
+		"jira.project":  0,
+	}
+	if len(configErr.Problems) != len(wantLines) {
+		t.Fatalf("Check() problems = %+v, want %d", configErr.Problems, len(wantLines))
+	}
+	for _, problem := range configErr.Problems {
+		line, ok := wantLines[problem.Key]
+		if !ok || problem.Line != line {
+			t.Errorf("problem %+v, want line %d", problem, line)
+		}
+	}
+	if last := configErr.Problems[len(configErr.Problems)-1]; last.Key != "jira.project" {
+		t.Errorf("last problem = %+v, want the one without a line", last)
+	}
+}
@@ -2,9 +2,11 @@
 package config
 
 import (
-	"fmt"
+	"net"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/esfisher/jiramd/internal/domain"
 )
@@ -18,89 +20,338 @@ func NewValidator() *Validator {
 }
 
 // Validate validates the configuration according to business rules.
-// Returns domain error if validation fails.
+// Every problem is reported in one domain.ConfigError rather than only the first.
 func (v *Validator) Validate(config *domain.Config) error {
-	if err := v.validateJira(&config.Jira); err != nil {
-		return err
+	var found problems
+	v.validateJira(&config.Jira, &found)
+	v.validateSync(&config.Sync, &found)
+	v.validateStorage(&config.Storage, &found)
+	v.validateAPI(&config.API, &found)
+	v.validateLog(&config.Log, &found)
+	v.validateMarkdown(&config.Markdown, &found)
+	v.validateDisplay(&config.Display, &found)
+	return domain.NewConfigProblems(found)
+}
+
+// validateJira validates Jira configuration fields.
+func (v *Validator) validateJira(jira *domain.JiraConfig, found *problems) {
+	// Validate BaseURL is present, valid, and uses HTTPS
+	if jira.BaseURL == "" {
+		found.add("jira.base_url", "jira.base_url is required")
+	} else if _, err := url.Parse(jira.BaseURL); err != nil {
+		found.add("jira.base_url", "jira.base_url is not a valid URL: %v", err)
+	} else if !strings.HasPrefix(jira.BaseURL, "https://") {
+		found.add("jira.base_url", "jira.base_url must use https:// protocol for security")
 	}
 
-	if err := v.validateSync(&config.Sync); err != nil {
-		return err
+	// Validate Email is present, with basic format validation
+	if jira.Email == "" {
+		found.add("jira.email", "jira.email is required")
+	} else if !strings.Contains(jira.Email, "@") {
+		found.add("jira.email", "jira.email must be a valid email address")
 	}
 
-	if err := v.validateStorage(&config.Storage); err != nil {
-		return err
+	// Validate Token is present (critical for security)
+	if jira.Token == "" {
+		found.add("jira.token", "jira.token is required (set JIRAMD_API_TOKEN environment variable)")
 	}
 
-	return nil
+	// Validate Project is present and has a key's length
+	if jira.Project == "" {
+		found.add("jira.project", "jira.project is required")
+	} else if len(jira.Project) < 2 || len(jira.Project) > 10 {
+		found.add("jira.project", "jira.project must be between 2 and 10 characters")
+	}
+
+	v.validateHTTP(&jira.HTTP, found)
+	v.validateComments(jira, found)
 }
 
-// validateJira validates Jira configuration fields.
-func (v *Validator) validateJira(jira *domain.JiraConfig) error {
-	// Validate BaseURL is present and valid
-	if jira.BaseURL == "" {
-		return domain.NewConfigError("jira.base_url is required")
+// validateComments validates the attribution of comments posted through jiramd.
+func (v *Validator) validateComments(jira *domain.JiraConfig, found *problems) {
+	comments := &jira.Comments
+	for author, token := range comments.ImpersonationTokens {
+		if author == "" || token == "" {
+			found.add("jira.comments.impersonation_tokens", "jira.comments.impersonation_tokens needs an author and a token in every entry")
+			break
+		}
 	}
-
-	// Validate BaseURL format
-	if _, err := url.Parse(jira.BaseURL); err != nil {
-		return domain.NewConfigError(fmt.Sprintf("jira.base_url is not a valid URL: %v", err))
+	if len(comments.ImpersonationTokens) > 0 && isCloudSite(jira.BaseURL) {
+		found.add("jira.comments.impersonation_tokens",
+			"jira.comments.impersonation_tokens needs Jira Server or Data Center personal access tokens, which Jira Cloud does not accept")
 	}
+}
 
-	// Ensure BaseURL uses HTTPS
-	if !strings.HasPrefix(jira.BaseURL, "https://") {
-		return domain.NewConfigError("jira.base_url must use https:// protocol for security")
+// isCloudSite reports whether baseURL is a Jira Cloud site.
+func isCloudSite(baseURL string) bool {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return false
 	}
+	host := strings.ToLower(parsed.Hostname())
+	return strings.HasSuffix(host, ".atlassian.net") || strings.HasSuffix(host, ".jira.com")
+}
 
-	// Validate Email is present
-	if jira.Email == "" {
-		return domain.NewConfigError("jira.email is required")
+// validateHTTP validates the Jira HTTP connection settings.
+func (v *Validator) validateHTTP(http *domain.HTTPConfig, found *problems) {
+	if http.ReadTimeout < 0 || http.WriteTimeout < 0 || http.ConnectTimeout < 0 {
+		found.add("jira.http.timeout", "jira.http timeouts cannot be negative")
 	}
 
-	// Basic email format validation
-	if !strings.Contains(jira.Email, "@") {
-		return domain.NewConfigError("jira.email must be a valid email address")
+	// Zero sends requests without a limit
+	if http.RateLimit < 0 {
+		found.add("jira.http.rate_limit", "jira.http.rate_limit cannot be negative")
 	}
 
-	// Validate Token is present (critical for security)
-	if jira.Token == "" {
-		return domain.NewConfigError("jira.token is required (set JIRAMD_API_TOKEN environment variable)")
+	if err := http.RetryPolicy().Validate(); err != nil {
+		found.add("jira.http.retry", "jira.http.retry is invalid: %v", err)
 	}
 
-	// Validate Project is present
-	if jira.Project == "" {
-		return domain.NewConfigError("jira.project is required")
+	if http.Proxy != "" {
+		proxy, err := url.Parse(http.Proxy)
+		switch {
+		case err != nil:
+			found.add("jira.http.proxy", "jira.http.proxy is not a valid URL: %v", err)
+		case proxy.Scheme != "http" && proxy.Scheme != "https" && proxy.Scheme != "socks5":
+			found.add("jira.http.proxy", "jira.http.proxy must be an http://, https://, or socks5:// URL")
+		case proxy.Host == "":
+			found.add("jira.http.proxy", "jira.http.proxy must include a host")
+		}
 	}
 
-	// Validate Project key format (Jira project keys are typically uppercase letters)
-	if len(jira.Project) < 2 || len(jira.Project) > 10 {
-		return domain.NewConfigError("jira.project must be between 2 and 10 characters")
+	if (http.ClientCertFile == "") != (http.ClientKeyFile == "") {
+		key := "jira.http.client_key"
+		if http.ClientCertFile == "" {
+			key = "jira.http.client_cert"
+		}
+		found.add(key, "jira.http.client_cert and jira.http.client_key must be set together")
 	}
-
-	return nil
 }
 
 // validateSync validates Sync configuration fields.
-func (v *Validator) validateSync(sync *domain.SyncConfig) error {
+func (v *Validator) validateSync(sync *domain.SyncConfig, found *problems) {
 	// Validate Interval is positive
 	if sync.Interval <= 0 {
-		return domain.NewConfigError("sync.interval must be positive")
+		found.add("sync.interval", "sync.interval must be positive")
 	}
 
 	// Validate MarkdownDir is present
 	if sync.MarkdownDir == "" {
-		return domain.NewConfigError("sync.markdown_dir is required")
+		found.add("sync.markdown_dir", "sync.markdown_dir is required")
+	}
+
+	if sync.Mode != "" && !sync.Mode.IsValid() {
+		found.add("sync.mode", "sync.mode must be bidirectional, pull_only, or push_only, got '%s'", sync.Mode)
+	}
+
+	if sync.Conflicts != "" && !sync.Conflicts.IsValid() {
+		found.add("sync.conflicts", "sync.conflicts must be manual, jira_wins, local_wins, or newest_wins, got '%s'", sync.Conflicts)
+	}
+
+	for _, source := range sync.SharedVault.AuthorSources {
+		if !source.IsValid() {
+			found.add("sync.shared_vault.author_sources", "sync.shared_vault.author_sources must list frontmatter or git, got '%s'", source)
+		}
+	}
+
+	if sync.Sprint.BoardID < 0 {
+		found.add("sync.sprint.board_id", "sync.sprint.board_id must not be negative, got %d", sync.Sprint.BoardID)
+	}
+
+	if err := sync.Guardrails.Validate(); err != nil {
+		found.add("sync.guardrails", "sync.guardrails is invalid: %v", err)
+	}
+
+	if err := sync.RetryPolicy().Validate(); err != nil {
+		found.add("sync.retry", "sync.retry is invalid: %v", err)
 	}
 
-	return nil
+	v.validateFieldDirections("sync.field_directions", "sync.field_directions", sync.FieldDirections, found)
+	for _, project := range sortedKeys(sync.ProjectFieldDirections) {
+		const key = "sync.project_field_directions"
+		if !domain.IsValidProjectKey(project) {
+			found.add(key, "%s has invalid project key '%s' (expected 2-10 uppercase letters/numbers)", key, project)
+		}
+		v.validateFieldDirections(key, key+"."+project, sync.ProjectFieldDirections[project], found)
+	}
+
+	if err := (domain.StatusMap{Names: sync.Statuses.Names}).Validate(); err != nil {
+		found.add("sync.status_map", "sync.status_map is invalid: %v", err)
+	}
+	if err := (domain.StatusMap{Aliases: sync.Statuses.Aliases}).Validate(); err != nil {
+		found.add("sync.status_aliases", "sync.status_aliases is invalid: %v", err)
+	}
+	for _, project := range sortedKeys(sync.Priorities) {
+		const key = "sync.priority_map"
+		if !domain.IsValidProjectKey(project) {
+			found.add(key, "%s has invalid project key '%s' (expected 2-10 uppercase letters/numbers)", key, project)
+		}
+		if err := sync.Priorities[project].Validate(); err != nil {
+			found.add(key, "%s.%s is invalid: %v", key, project, err)
+		}
+	}
+}
+
+// validateFieldDirections validates field direction overrides, reported as setting key
+// and named name in messages (the project's overrides within a per-project setting).
+func (v *Validator) validateFieldDirections(key, name string, directions domain.FieldDirections, found *problems) {
+	for _, field := range sortedKeys(directions) {
+		if field == "" {
+			found.add(key, "%s has an empty field name", name)
+		}
+		if direction := directions[field]; !direction.IsValid() {
+			found.add(key, "%s.%s must be bidirectional, jira_to_local, or local_only, got '%s'", name, field, direction)
+		}
+	}
 }
 
 // validateStorage validates Storage configuration fields.
-func (v *Validator) validateStorage(storage *domain.StorageConfig) error {
+func (v *Validator) validateStorage(storage *domain.StorageConfig, found *problems) {
 	// Validate DBPath is present
 	if storage.DBPath == "" {
-		return domain.NewConfigError("storage.db_path is required")
+		found.add("storage.db_path", "storage.db_path is required")
+	}
+
+	// An empty driver means the SQLite default
+	switch storage.Driver {
+	case "", domain.StorageDriverSQLite:
+	case domain.StorageDriverPostgres:
+		if storage.DSN == "" {
+			found.add("storage.dsn", "storage.dsn is required when storage.driver is postgres")
+		}
+	default:
+		found.add("storage.driver", "storage.driver must be sqlite or postgres, got '%s'", storage.Driver)
 	}
 
-	return nil
+	if storage.Retention < 0 {
+		found.add("storage.retention", "storage.retention cannot be negative")
+	}
+
+	// Zero disables automatic garbage collection in the daemon
+	if storage.GCInterval < 0 {
+		found.add("storage.gc_interval", "storage.gc_interval cannot be negative")
+	}
+}
+
+// validateAPI validates control API configuration fields.
+func (v *Validator) validateAPI(api *domain.APIConfig, found *problems) {
+	if !api.Enabled {
+		return
+	}
+
+	if api.Address == "" && api.SocketPath == "" {
+		found.add("api.enabled", "api.address or api.socket is required when api.enabled is true")
+		return
+	}
+
+	if api.Address != "" && api.SocketPath != "" {
+		found.add("api.socket", "api.address and api.socket are mutually exclusive")
+	}
+
+	if api.Address != "" {
+		host, _, err := net.SplitHostPort(api.Address)
+		if err != nil {
+			found.add("api.address", "api.address is not a valid host:port: %v", err)
+			return
+		}
+
+		// Even with a token, never expose the control API beyond the local machine
+		if host != "localhost" {
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsLoopback() {
+				found.add("api.address", "api.address must bind to a loopback address (127.0.0.1, ::1, or localhost)")
+			}
+		}
+	}
+
+	if api.Auth != "" && !api.Auth.IsValid() {
+		found.add("api.auth", "invalid api.auth: %s (expected token or none)", api.Auth)
+	}
+	// Any local user can connect to a loopback port; only the socket's permissions or
+	// client certificates may stand in for the token
+	if api.Auth == domain.APIAuthNone && api.SocketPath == "" && !api.TLS.Mutual() {
+		found.add("api.auth", "api.auth: none requires api.socket or api.tls.client_ca")
+	}
+
+	tls := api.TLS
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		found.add("api.tls.cert", "api.tls.cert and api.tls.key must be set together")
+	}
+	if tls.ClientCAFile != "" && tls.CertFile == "" {
+		found.add("api.tls.client_ca", "api.tls.client_ca requires api.tls.cert and api.tls.key")
+	}
+	if (tls.ClientCertFile == "") != (tls.ClientKeyFile == "") {
+		found.add("api.tls.client_cert", "api.tls.client_cert and api.tls.client_key must be set together")
+	}
+}
+
+// validateLog validates logging configuration fields.
+func (v *Validator) validateLog(log *domain.LogConfig, found *problems) {
+	switch log.Level {
+	case "", domain.LogLevelDebug, domain.LogLevelInfo, domain.LogLevelWarn, domain.LogLevelError:
+	default:
+		found.add("log.level", "log.level must be debug, info, warn, or error, got '%s'", log.Level)
+	}
+}
+
+// validateMarkdown validates markdown output configuration fields.
+func (v *Validator) validateMarkdown(markdown *domain.MarkdownConfig, found *problems) {
+	if markdown.Flavor != "" && !markdown.Flavor.IsValid() {
+		found.add("markdown.flavor", "markdown.flavor must be plain or obsidian, got '%s'", markdown.Flavor)
+	}
+	if markdown.Frontmatter != "" && !markdown.Frontmatter.IsValid() {
+		found.add("markdown.frontmatter", "markdown.frontmatter must be yaml, toml, or json, got '%s'", markdown.Frontmatter)
+	}
+
+	if markdown.MaxComments < 0 {
+		found.add("markdown.max_comments", "markdown.max_comments must not be negative, got %d", markdown.MaxComments)
+	}
+	if markdown.MaxDescriptionLength < 0 {
+		found.add("markdown.max_description_length", "markdown.max_description_length must not be negative, got %d", markdown.MaxDescriptionLength)
+	}
+	for _, project := range sortedKeys(markdown.ProjectLimits) {
+		const key = "markdown.project_limits"
+		if !domain.IsValidProjectKey(project) {
+			found.add(key, "%s has invalid project key '%s' (expected 2-10 uppercase letters/numbers)", key, project)
+		}
+		limits := markdown.ProjectLimits[project]
+		if limits.MaxDescriptionLength < 0 {
+			found.add(key, "%s.%s.max_description_length must not be negative, got %d", key, project, limits.MaxDescriptionLength)
+		}
+		if limits.MaxComments < 0 {
+			found.add(key, "%s.%s.max_comments must not be negative, got %d", key, project, limits.MaxComments)
+		}
+	}
+
+	seen := make(map[string]bool, len(markdown.KeyOrder))
+	for _, key := range markdown.KeyOrder {
+		if seen[key] {
+			found.add("markdown.key_order", "markdown.key_order lists %s more than once", key)
+		}
+		seen[key] = true
+	}
+}
+
+// validateDisplay validates timestamp display configuration fields.
+func (v *Validator) validateDisplay(display *domain.DisplayConfig, found *problems) {
+	// A layout without any time elements shows every timestamp the same
+	if display.DateFormat != "" && layoutProbes[0].Format(display.DateFormat) == layoutProbes[1].Format(display.DateFormat) {
+		found.add("display.date_format", "display.date_format must be a Go time layout such as '2006-01-02 15:04', got '%s'", display.DateFormat)
+	}
+}
+
+// layoutProbes are two times that differ in every element a time layout can show.
+var layoutProbes = [2]time.Time{
+	time.Date(2001, time.February, 3, 4, 5, 6, 100_000_000, time.FixedZone("AAA", 60*60)),
+	time.Date(2012, time.November, 14, 22, 17, 28, 900_000_000, time.FixedZone("BBB", -2*60*60)),
+}
+
+// sortedKeys returns the keys of m in order, so problems are reported in a stable order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -330,3 +332,668 @@ func TestValidator_Validate_MissingStorageDBPath(t *testing.T) {
 		t.Error("Validate() expected error for missing db_path, got nil")
 	}
 }
+
+func TestValidator_Validate_API(t *testing.T) {
+	tests := []struct {
+		name    string
+		api     domain.APIConfig
+		wantErr bool
+	}{
+		{
+			name:    "disabled ignores address",
+			api:     domain.APIConfig{Enabled: false, Address: "0.0.0.0:7777"},
+			wantErr: false,
+		},
+		{
+			name:    "loopback address",
+			api:     domain.APIConfig{Enabled: true, Address: "127.0.0.1:7777"},
+			wantErr: false,
+		},
+		{
+			name:    "localhost address",
+			api:     domain.APIConfig{Enabled: true, Address: "localhost:7777"},
+			wantErr: false,
+		},
+		{
+			name:    "ipv6 loopback address",
+			api:     domain.APIConfig{Enabled: true, Address: "[::1]:7777"},
+			wantErr: false,
+		},
+		{
+			name:    "unix socket",
+			api:     domain.APIConfig{Enabled: true, SocketPath: "/tmp/jiramd.sock"},
+			wantErr: false,
+		},
+		{
+			name:    "enabled without listener",
+			api:     domain.APIConfig{Enabled: true},
+			wantErr: true,
+		},
+		{
+			name:    "both address and socket",
+			api:     domain.APIConfig{Enabled: true, Address: "127.0.0.1:7777", SocketPath: "/tmp/jiramd.sock"},
+			wantErr: true,
+		},
+		{
+			name:    "non-loopback address",
+			api:     domain.APIConfig{Enabled: true, Address: "0.0.0.0:7777"},
+			wantErr: true,
+		},
+		{
+			name:    "missing port",
+			api:     domain.APIConfig{Enabled: true, Address: "127.0.0.1"},
+			wantErr: true,
+		},
+		{
+			name:    "token auth",
+			api:     domain.APIConfig{Enabled: true, Address: "127.0.0.1:7777", Auth: domain.APIAuthToken},
+			wantErr: false,
+		},
+		{
+			name:    "unknown auth",
+			api:     domain.APIConfig{Enabled: true, Address: "127.0.0.1:7777", Auth: "password"},
+			wantErr: true,
+		},
+		{
+			name:    "no auth on a unix socket",
+			api:     domain.APIConfig{Enabled: true, SocketPath: "/tmp/jiramd.sock", Auth: domain.APIAuthNone},
+			wantErr: false,
+		},
+		{
+			name:    "no auth on a loopback port",
+			api:     domain.APIConfig{Enabled: true, Address: "127.0.0.1:7777", Auth: domain.APIAuthNone},
+			wantErr: true,
+		},
+		{
+			name: "no auth with mutual TLS",
+			api: domain.APIConfig{Enabled: true, Address: "127.0.0.1:7777", Auth: domain.APIAuthNone,
+				TLS: domain.APITLSConfig{CertFile: "server.crt", KeyFile: "server.key", ClientCAFile: "ca.crt"}},
+			wantErr: false,
+		},
+		{
+			name: "tls cert without key",
+			api: domain.APIConfig{Enabled: true, Address: "127.0.0.1:7777",
+				TLS: domain.APITLSConfig{CertFile: "server.crt"}},
+			wantErr: true,
+		},
+		{
+			name: "client CA without server certificate",
+			api: domain.APIConfig{Enabled: true, Address: "127.0.0.1:7777",
+				TLS: domain.APITLSConfig{ClientCAFile: "ca.crt"}},
+			wantErr: true,
+		},
+		{
+			name: "client cert without key",
+			api: domain.APIConfig{Enabled: true, Address: "127.0.0.1:7777",
+				TLS: domain.APITLSConfig{CertFile: "server.crt", KeyFile: "server.key", ClientCAFile: "ca.crt", ClientCertFile: "client.crt"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewValidator()
+
+			cfg := &domain.Config{
+				Jira: domain.JiraConfig{
+					BaseURL: "https://example.atlassian.net",
+					Email:   "test@example.com",
+					Token:   "test-token",
+					Project: "TEST",
+				},
+				Sync: domain.SyncConfig{
+					Interval:    5 * time.Minute,
+					MarkdownDir: "/tmp/tickets",
+				},
+				Storage: domain.StorageConfig{
+					DBPath: "/tmp/jiramd.db",
+				},
+				API: tt.api,
+			}
+
+			err := validator.Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_Retention(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage domain.StorageConfig
+		wantErr bool
+	}{
+		{
+			name:    "unset uses defaults",
+			storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+		},
+		{
+			name:    "gc disabled",
+			storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db", Retention: 24 * time.Hour},
+		},
+		{
+			name:    "negative retention",
+			storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db", Retention: -time.Hour},
+			wantErr: true,
+		},
+		{
+			name:    "negative gc interval",
+			storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db", GCInterval: -time.Hour},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &domain.Config{
+				Jira: domain.JiraConfig{
+					BaseURL: "https://example.atlassian.net",
+					Email:   "test@example.com",
+					Token:   "test-token",
+					Project: "TEST",
+				},
+				Sync: domain.SyncConfig{
+					Interval:    5 * time.Minute,
+					MarkdownDir: "/tmp/tickets",
+				},
+				Storage: tt.storage,
+			}
+
+			err := NewValidator().Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_StorageDriver(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage domain.StorageConfig
+		wantErr bool
+	}{
+		{
+			name:    "unset uses sqlite",
+			storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+		},
+		{
+			name:    "sqlite",
+			storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db", Driver: domain.StorageDriverSQLite},
+		},
+		{
+			name: "postgres with dsn",
+			storage: domain.StorageConfig{
+				DBPath: "/tmp/jiramd.db",
+				Driver: domain.StorageDriverPostgres,
+				DSN:    "postgres://jiramd@db.example.com/jiramd",
+			},
+		},
+		{
+			name:    "postgres without dsn",
+			storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db", Driver: domain.StorageDriverPostgres},
+			wantErr: true,
+		},
+		{
+			name:    "unknown driver",
+			storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db", Driver: "mysql"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &domain.Config{
+				Jira: domain.JiraConfig{
+					BaseURL: "https://example.atlassian.net",
+					Email:   "test@example.com",
+					Token:   "test-token",
+					Project: "TEST",
+				},
+				Sync: domain.SyncConfig{
+					Interval:    5 * time.Minute,
+					MarkdownDir: "/tmp/tickets",
+				},
+				Storage: tt.storage,
+			}
+
+			err := NewValidator().Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_Retry(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   domain.RetryPolicy
+		wantErr bool
+	}{
+		{
+			name: "unset uses default",
+		},
+		{
+			name:  "custom",
+			retry: domain.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: time.Minute, Multiplier: 2},
+		},
+		{
+			name:    "shrinking backoff",
+			retry:   domain.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: time.Minute, Multiplier: 0.5},
+			wantErr: true,
+		},
+		{
+			name: "retry on permanent errors",
+			retry: domain.RetryPolicy{
+				MaxAttempts:    5,
+				InitialBackoff: time.Second,
+				MaxBackoff:     time.Minute,
+				Multiplier:     2,
+				RetryOn:        []domain.ErrorClass{domain.ErrorClassPermanent},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &domain.Config{
+				Jira: domain.JiraConfig{
+					BaseURL: "https://example.atlassian.net",
+					Email:   "test@example.com",
+					Token:   "test-token",
+					Project: "TEST",
+				},
+				Sync: domain.SyncConfig{
+					Interval:    5 * time.Minute,
+					MarkdownDir: "/tmp/tickets",
+					Retry:       tt.retry,
+				},
+				Storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+			}
+
+			err := NewValidator().Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_SyncMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      domain.SyncMode
+		conflicts domain.ConflictStrategy
+		wantErr   bool
+	}{
+		{name: "unset uses bidirectional"},
+		{name: "pull only", mode: domain.SyncModePullOnly},
+		{name: "push only", mode: domain.SyncModePushOnly},
+		{name: "unknown mode", mode: "read_only", wantErr: true},
+		{name: "jira wins conflicts", conflicts: domain.ConflictJiraWins},
+		{name: "unknown conflict strategy", conflicts: "mine", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &domain.Config{
+				Jira: domain.JiraConfig{
+					BaseURL: "https://example.atlassian.net",
+					Email:   "test@example.com",
+					Token:   "test-token",
+					Project: "TEST",
+				},
+				Sync: domain.SyncConfig{
+					Interval:    5 * time.Minute,
+					MarkdownDir: "/tmp/tickets",
+					Mode:        tt.mode,
+					Conflicts:   tt.conflicts,
+				},
+				Storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+			}
+
+			err := NewValidator().Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_HTTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		http    domain.HTTPConfig
+		wantErr bool
+	}{
+		{
+			name: "unset uses defaults",
+		},
+		{
+			name: "proxy and client certificate",
+			http: domain.HTTPConfig{
+				Proxy:          "http://proxy.corp.example:3128",
+				ClientCertFile: "/etc/jiramd/client.pem",
+				ClientKeyFile:  "/etc/jiramd/client-key.pem",
+			},
+		},
+		{
+			name:    "negative timeout",
+			http:    domain.HTTPConfig{ReadTimeout: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "negative rate limit",
+			http:    domain.HTTPConfig{RateLimit: -1},
+			wantErr: true,
+		},
+		{
+			name: "retry budget without retries",
+			http: domain.HTTPConfig{Retry: domain.HTTPRetryPolicy{Budget: time.Minute}},
+		},
+		{
+			name: "retry max delay below base delay",
+			http: domain.HTTPConfig{Retry: domain.HTTPRetryPolicy{
+				RateLimited: domain.Backoff{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: time.Second},
+				Budget:      time.Minute,
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "proxy without scheme",
+			http:    domain.HTTPConfig{Proxy: "proxy.corp.example:3128"},
+			wantErr: true,
+		},
+		{
+			name:    "client certificate without key",
+			http:    domain.HTTPConfig{ClientCertFile: "/etc/jiramd/client.pem"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &domain.Config{
+				Jira: domain.JiraConfig{
+					BaseURL: "https://example.atlassian.net",
+					Email:   "test@example.com",
+					Token:   "test-token",
+					Project: "TEST",
+					HTTP:    tt.http,
+				},
+				Sync: domain.SyncConfig{
+					Interval:    5 * time.Minute,
+					MarkdownDir: "/tmp/tickets",
+				},
+				Storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+			}
+
+			err := NewValidator().Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_CommentAttribution(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseURL  string
+		comments domain.CommentAttribution
+		wantErr  bool
+	}{
+		{
+			name:     "footer on cloud",
+			baseURL:  "https://example.atlassian.net",
+			comments: domain.CommentAttribution{Author: "bob", Footer: "— via jiramd for {author}"},
+		},
+		{
+			name:     "impersonation on server",
+			baseURL:  "https://jira.corp.example",
+			comments: domain.CommentAttribution{ImpersonationTokens: map[string]string{"alice": "alice-pat"}},
+		},
+		{
+			name:     "impersonation on cloud",
+			baseURL:  "https://example.atlassian.net",
+			comments: domain.CommentAttribution{ImpersonationTokens: map[string]string{"alice": "alice-pat"}},
+			wantErr:  true,
+		},
+		{
+			name:     "impersonation without a token",
+			baseURL:  "https://jira.corp.example",
+			comments: domain.CommentAttribution{ImpersonationTokens: map[string]string{"alice": ""}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &domain.Config{
+				Jira: domain.JiraConfig{
+					BaseURL:  tt.baseURL,
+					Email:    "test@example.com",
+					Token:    "test-token",
+					Project:  "TEST",
+					Comments: tt.comments,
+				},
+				Sync: domain.SyncConfig{
+					Interval:    5 * time.Minute,
+					MarkdownDir: "/tmp/tickets",
+				},
+				Storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+			}
+
+			err := NewValidator().Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_SharedVault(t *testing.T) {
+	for _, tt := range []struct {
+		sources []domain.AuthorSource
+		wantErr bool
+	}{
+		{},
+		{sources: []domain.AuthorSource{domain.AuthorFromGit, domain.AuthorFromFrontmatter}},
+		{sources: []domain.AuthorSource{"blame"}, wantErr: true},
+	} {
+		cfg := &domain.Config{
+			Jira: domain.JiraConfig{
+				BaseURL: "https://example.atlassian.net",
+				Email:   "test@example.com",
+				Token:   "test-token",
+				Project: "TEST",
+			},
+			Sync: domain.SyncConfig{
+				Interval:    5 * time.Minute,
+				MarkdownDir: "/tmp/tickets",
+				SharedVault: domain.SharedVaultConfig{AuthorSources: tt.sources},
+			},
+			Storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+		}
+
+		err := NewValidator().Validate(cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with author sources %v error = %v, wantErr %v", tt.sources, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidator_Validate_LogLevel(t *testing.T) {
+	for _, tt := range []struct {
+		level   string
+		wantErr bool
+	}{
+		{level: ""},
+		{level: domain.LogLevelDebug},
+		{level: domain.LogLevelError},
+		{level: "verbose", wantErr: true},
+	} {
+		cfg := &domain.Config{
+			Jira: domain.JiraConfig{
+				BaseURL: "https://example.atlassian.net",
+				Email:   "test@example.com",
+				Token:   "test-token",
+				Project: "TEST",
+			},
+			Sync: domain.SyncConfig{
+				Interval:    5 * time.Minute,
+				MarkdownDir: "/tmp/tickets",
+			},
+			Storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+			Log:     domain.LogConfig{Level: tt.level},
+		}
+
+		err := NewValidator().Validate(cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with level %q error = %v, wantErr %v", tt.level, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidator_Validate_Markdown(t *testing.T) {
+	for _, tt := range []struct {
+		markdown domain.MarkdownConfig
+		wantErr  bool
+	}{
+		{markdown: domain.MarkdownConfig{}},
+		{markdown: domain.MarkdownConfig{Flavor: domain.MarkdownFlavorPlain, Frontmatter: domain.FrontmatterYAML}},
+		{markdown: domain.MarkdownConfig{Flavor: domain.MarkdownFlavorObsidian, Frontmatter: domain.FrontmatterJSON}},
+		{markdown: domain.MarkdownConfig{Flavor: "logseq"}, wantErr: true},
+		{markdown: domain.MarkdownConfig{Frontmatter: "xml"}, wantErr: true},
+		{markdown: domain.MarkdownConfig{KeyOrder: []string{"title", "key"}}},
+		{markdown: domain.MarkdownConfig{KeyOrder: []string{"key", "title", "key"}}, wantErr: true},
+		{markdown: domain.MarkdownConfig{MaxComments: 200}},
+		{markdown: domain.MarkdownConfig{MaxComments: -1}, wantErr: true},
+		{markdown: domain.MarkdownConfig{MaxDescriptionLength: 4000}},
+		{markdown: domain.MarkdownConfig{MaxDescriptionLength: -1}, wantErr: true},
+		{markdown: domain.MarkdownConfig{ProjectLimits: map[string]domain.ContentLimits{"OPS": {MaxComments: 10}}}},
+		{markdown: domain.MarkdownConfig{ProjectLimits: map[string]domain.ContentLimits{"OPS": {MaxComments: -1}}}, wantErr: true},
+		{markdown: domain.MarkdownConfig{ProjectLimits: map[string]domain.ContentLimits{"OPS": {MaxDescriptionLength: -1}}}, wantErr: true},
+		{markdown: domain.MarkdownConfig{ProjectLimits: map[string]domain.ContentLimits{"ops": {MaxComments: 10}}}, wantErr: true},
+	} {
+		cfg := &domain.Config{
+			Jira: domain.JiraConfig{
+				BaseURL: "https://example.atlassian.net",
+				Email:   "test@example.com",
+				Token:   "test-token",
+				Project: "TEST",
+			},
+			Sync: domain.SyncConfig{
+				Interval:    5 * time.Minute,
+				MarkdownDir: "/tmp/tickets",
+			},
+			Storage:  domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+			Markdown: tt.markdown,
+		}
+
+		err := NewValidator().Validate(cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with %+v error = %v, wantErr %v", tt.markdown, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidator_Validate_PriorityMap(t *testing.T) {
+	for _, tt := range []struct {
+		priorities map[string]domain.PriorityMap
+		wantErr    bool
+	}{
+		{priorities: nil},
+		{priorities: map[string]domain.PriorityMap{"OPS": {"P0": "Blocker", "P1": "Critical"}}},
+		{priorities: map[string]domain.PriorityMap{"ops": {"P0": "Blocker"}}, wantErr: true},
+		{priorities: map[string]domain.PriorityMap{"OPS": {"P0": "Blocker", "P1": "blocker"}}, wantErr: true},
+		{priorities: map[string]domain.PriorityMap{"OPS": {"P0": ""}}, wantErr: true},
+	} {
+		cfg := &domain.Config{
+			Jira: domain.JiraConfig{
+				BaseURL: "https://example.atlassian.net",
+				Email:   "test@example.com",
+				Token:   "test-token",
+				Project: "TEST",
+			},
+			Sync: domain.SyncConfig{
+				Interval:    5 * time.Minute,
+				MarkdownDir: "/tmp/tickets",
+				Priorities:  tt.priorities,
+			},
+			Storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+		}
+
+		err := NewValidator().Validate(cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with %+v error = %v, wantErr %v", tt.priorities, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidator_Validate_Display(t *testing.T) {
+	for _, tt := range []struct {
+		display domain.DisplayConfig
+		wantErr bool
+	}{
+		{display: domain.DisplayConfig{}},
+		{display: domain.DisplayConfig{Location: time.UTC, DateFormat: domain.DefaultDateFormat}},
+		{display: domain.DisplayConfig{DateFormat: "Jan 2"}},
+		{display: domain.DisplayConfig{DateFormat: "YYYY-MM-DD"}, wantErr: true},
+	} {
+		cfg := &domain.Config{
+			Jira: domain.JiraConfig{
+				BaseURL: "https://example.atlassian.net",
+				Email:   "test@example.com",
+				Token:   "test-token",
+				Project: "TEST",
+			},
+			Sync: domain.SyncConfig{
+				Interval:    5 * time.Minute,
+				MarkdownDir: "/tmp/tickets",
+			},
+			Storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db"},
+			Display: tt.display,
+		}
+
+		err := NewValidator().Validate(cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with %+v error = %v, wantErr %v", tt.display, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidator_Validate_ReportsEveryProblem(t *testing.T) {
+	cfg := &domain.Config{
+		Jira: domain.JiraConfig{
+			BaseURL: "http://example.atlassian.net",
+			Email:   "not-an-email",
+			Token:   "test-token",
+			Project: "TEST",
+		},
+		Sync: domain.SyncConfig{
+			Interval:    5 * time.Minute,
+			MarkdownDir: "/tmp/tickets",
+		},
+		Storage: domain.StorageConfig{DBPath: "/tmp/jiramd.db", Retention: -time.Hour},
+	}
+
+	err := NewValidator().Validate(cfg)
+	var configErr *domain.ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("Validate() error = %v, want *domain.ConfigError", err)
+	}
+
+	var keys []string
+	for _, problem := range configErr.Problems {
+		keys = append(keys, problem.Key)
+	}
+	want := []string{"jira.base_url", "jira.email", "storage.retention"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("Validate() problem keys = %v, want %v", keys, want)
+	}
+}
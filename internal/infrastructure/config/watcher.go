@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// DefaultWatchInterval is how often WatchFile checks the configuration file.
+const DefaultWatchInterval = 2 * time.Second
+
+// WatchFile polls the file at path every interval and sends on the returned channel
+// when its modification time or size changes, including when it is replaced by an
+// editor's rename. Notifications are coalesced: at most one is pending at a time.
+// The channel is closed when ctx is cancelled. A leading ~ in path is expanded as by
+// Loader.Load.
+//
+// Polling is used instead of filesystem events so a single code path works on every
+// platform and survives the file being deleted and recreated.
+func WatchFile(ctx context.Context, path string, interval time.Duration) <-chan struct{} {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	if expanded, err := expandHomePath(path); err == nil {
+		path = expanded
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+
+		last, lastOK := statFile(path)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current, ok := statFile(path)
+			if !ok {
+				// Missing mid-save; keep the last stamp so the rewritten file is seen
+				continue
+			}
+			if lastOK && current == last {
+				continue
+			}
+			last, lastOK = current, true
+
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return changes
+}
+
+// fileStamp identifies a version of a file well enough to notice edits.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFile returns the stamp of the file at path, or false if it cannot be read.
+func statFile(path string) (fileStamp, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, false
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, true
+}
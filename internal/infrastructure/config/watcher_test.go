@@ -0,0 +1,48 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("sync:\n  interval: 5m\n"), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := WatchFile(ctx, configPath, 5*time.Millisecond)
+
+	select {
+	case <-changes:
+		t.Fatal("WatchFile() reported a change before the file was edited")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	if err := os.WriteFile(configPath, []byte("sync:\n  interval: 10m\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite test config: %v", err)
+	}
+
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("WatchFile() did not report the edit")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-changes:
+		if ok {
+			// A coalesced notification may still be buffered; the close follows it
+			if _, ok := <-changes; ok {
+				t.Error("channel still open after cancel")
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("WatchFile() did not close the channel after cancel")
+	}
+}
@@ -0,0 +1,232 @@
+// Package htmlsite renders a site of cached tickets as static HTML files: an index with
+// search, and a page per ticket. The pages work when opened straight from disk.
+package htmlsite
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/application/site"
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// ticketDir is the directory of the ticket pages, relative to the output directory.
+const ticketDir = "tickets"
+
+// timeLayout formats timestamps on the pages unless a display is set.
+const timeLayout = "2006-01-02 15:04"
+
+// templates are the parsed page templates.
+var templates = template.Must(template.New("").
+	Funcs(template.FuncMap{"join": strings.Join}).
+	ParseFS(templateFS, "templates/*.html"))
+
+// indexRow is a ticket in the index table.
+type indexRow struct {
+	Key       string
+	Href      string
+	Summary   string
+	Status    string
+	IssueType string
+	Assignee  string
+	Updated   string
+}
+
+// indexData is what index.html is executed with.
+type indexData struct {
+	Title       string
+	GeneratedAt string
+	Tickets     []indexRow
+
+	// Search maps each ticket key to its lowercased searchable text
+	Search map[string]string
+}
+
+// personData is someone on a ticket page, with their profile when the ticket recorded it
+// (see domain.AuthorsField).
+type personData struct {
+	// Name is the display name, or how the ticket names them without a profile
+	Name      string
+	Email     string
+	AvatarURL string
+}
+
+// commentData is a comment on a ticket page.
+type commentData struct {
+	Author  personData
+	Created string
+	Body    string
+
+	// Visibility describes who can see a restricted comment ("" for public)
+	Visibility string
+}
+
+// ticketData is what ticket.html is executed with.
+type ticketData struct {
+	SiteTitle   string
+	GeneratedAt string
+	Key         string
+	Summary     string
+	Description string
+	Status      string
+	IssueType   string
+	Priority    string
+	Assignee    personData
+	Reporter    personData
+	Labels      []string
+	FixVersions []string
+	Created     string
+	Updated     string
+	Comments    []commentData
+}
+
+// Renderer writes sites as HTML into a directory.
+type Renderer struct {
+	outDir string
+
+	// display formats the timestamps on the pages
+	display domain.DisplayConfig
+}
+
+// NewRenderer creates a renderer writing into outDir, which is created if needed.
+// Timestamps are shown in local time.
+func NewRenderer(outDir string) *Renderer {
+	return &Renderer{
+		outDir:  outDir,
+		display: domain.DisplayConfig{Location: time.Local, DateFormat: timeLayout},
+	}
+}
+
+// WithDisplay sets the time zone and format timestamps are shown in.
+func (r *Renderer) WithDisplay(display domain.DisplayConfig) *Renderer {
+	r.display = display
+	return r
+}
+
+// Verify that Renderer implements the site.Renderer interface
+var _ site.Renderer = (*Renderer)(nil)
+
+// Render writes index.html and a page per ticket under tickets/, removing the pages of
+// tickets that are no longer in the site. Implements site.Renderer.Render.
+func (r *Renderer) Render(s *site.Site) error {
+	if err := os.MkdirAll(filepath.Join(r.outDir, ticketDir), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	index := indexData{
+		Title:       s.Title,
+		GeneratedAt: r.formatTime(s.GeneratedAt),
+		Tickets:     make([]indexRow, 0, len(s.Pages)),
+		Search:      make(map[string]string, len(s.Pages)),
+	}
+	pages := make(map[string]bool, len(s.Pages))
+	for _, page := range s.Pages {
+		t := page.Ticket
+		name := t.Key.String() + ".html"
+		pages[name] = true
+
+		index.Tickets = append(index.Tickets, indexRow{
+			Key:       t.Key.String(),
+			Href:      ticketDir + "/" + name,
+			Summary:   t.Summary,
+			Status:    t.Status,
+			IssueType: t.IssueType,
+			Assignee:  t.Assignee,
+			Updated:   r.formatTime(t.Updated),
+		})
+
+		text := []string{t.Key.String(), t.Summary, t.Description}
+		data := ticketData{
+			SiteTitle:   s.Title,
+			GeneratedAt: r.formatTime(s.GeneratedAt),
+			Key:         t.Key.String(),
+			Summary:     t.Summary,
+			Description: t.Description,
+			Status:      t.Status,
+			IssueType:   t.IssueType,
+			Priority:    t.Priority,
+			Assignee:    person(t, t.Assignee),
+			Reporter:    person(t, t.Reporter),
+			Labels:      t.Labels,
+			FixVersions: t.FixVersions(),
+			Created:     r.formatTime(t.Created),
+			Updated:     r.formatTime(t.Updated),
+		}
+		for _, comment := range page.Comments {
+			data.Comments = append(data.Comments, commentData{
+				Author:  person(t, comment.Author),
+				Created: r.formatTime(comment.Created),
+				Body:    comment.Body,
+
+				Visibility: comment.Visibility.Label(),
+			})
+			text = append(text, comment.Body)
+		}
+		index.Search[t.Key.String()] = strings.ToLower(strings.Join(text, "\n"))
+
+		if err := r.write(filepath.Join(ticketDir, name), "ticket.html", data); err != nil {
+			return err
+		}
+	}
+
+	if err := r.write("index.html", "index.html", index); err != nil {
+		return err
+	}
+	return r.removeStale(pages)
+}
+
+// person returns who the ticket names name, with their recorded profile if any.
+func person(ticket *domain.Ticket, name string) personData {
+	author, ok := ticket.Author(name)
+	if !ok {
+		return personData{Name: name}
+	}
+	data := personData{Name: author.DisplayName, Email: author.Email, AvatarURL: author.AvatarURL}
+	if data.Name == "" {
+		data.Name = name
+	}
+	return data
+}
+
+// write executes a template into a file under the output directory.
+func (r *Renderer) write(name, tmpl string, data interface{}) error {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, tmpl, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	if err := os.WriteFile(filepath.Join(r.outDir, name), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// removeStale removes ticket pages left by an earlier render that are not in pages.
+func (r *Renderer) removeStale(pages map[string]bool) error {
+	entries, err := os.ReadDir(filepath.Join(r.outDir, ticketDir))
+	if err != nil {
+		return fmt.Errorf("failed to list ticket pages: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".html" || pages[entry.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(r.outDir, ticketDir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove stale page: %w", err)
+		}
+	}
+	return nil
+}
+
+// formatTime formats a timestamp for the pages, or returns "" for the zero time.
+func (r *Renderer) formatTime(t time.Time) string {
+	return r.display.FormatTime(t)
+}
@@ -0,0 +1,79 @@
+package htmlsite
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/application/site"
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestRenderer_Render(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	key, _ := domain.NewTicketKey("JMD-1")
+	ticket := domain.NewTicket(key, "Fix <script> injection", now, now)
+	ticket.Status = "In Progress"
+	ticket.Description = "Steps:\n1. Open"
+	ticket.Assignee = "bob@example.com"
+	ticket.AddAuthors(&domain.User{DisplayName: "Bob Jones", Email: "bob@example.com", AvatarURL: "https://avatars.example.com/bob.png"})
+	comment, err := domain.NewComment("100", key, "alice@example.com", "Reproduced on Firefox", now, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A page left by an earlier render of a ticket that is gone
+	stale := filepath.Join(dir, ticketDir, "JMD-9.html")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewRenderer(dir).Render(&site.Site{
+		Title:       "JMD",
+		GeneratedAt: now,
+		Pages:       []*site.Page{{Ticket: ticket, Comments: []*domain.Comment{comment}}},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	index := readFile(t, filepath.Join(dir, "index.html"))
+	for _, want := range []string{`href="tickets/JMD-1.html"`, "Fix &lt;script&gt; injection", `"JMD-1":"jmd-1\nfix \u003cscript\u003e injection`, "reproduced on firefox"} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html does not contain %s", want)
+		}
+	}
+
+	page := readFile(t, filepath.Join(dir, ticketDir, "JMD-1.html"))
+	for _, want := range []string{
+		"In Progress", "Steps:\n1. Open", "alice@example.com", "Reproduced on Firefox",
+		`<img class="avatar" src="https://avatars.example.com/bob.png" alt=""><span title="bob@example.com">Bob Jones</span>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("JMD-1.html does not contain %q", want)
+		}
+	}
+	if strings.Contains(page, "<script>") {
+		t.Error("JMD-1.html contains the unescaped summary")
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale page not removed (err = %v)", err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// webhookSignatureHeader carries the HMAC-SHA256 signature of a webhook body, as
+// "sha256=<hex>", which Jira sends for webhooks registered with a secret.
+const webhookSignatureHeader = "X-Hub-Signature"
+
+// requireToken returns next behind a check that every request carries token as a bearer
+// token, answering 401 Unauthorized otherwise.
+func requireToken(token string, next http.Handler) http.Handler {
+	want := []byte(token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, got, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="jiramd"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validSignature reports whether signature, a webhookSignatureHeader value, is the
+// HMAC-SHA256 of body under secret.
+func validSignature(secret string, body []byte, signature string) bool {
+	method, digest, ok := strings.Cut(signature, "=")
+	if !ok || !strings.EqualFold(method, "sha256") {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimSpace(digest))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
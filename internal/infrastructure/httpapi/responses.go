@@ -0,0 +1,106 @@
+// Package httpapi provides the daemon's local HTTP control API.
+package httpapi
+
+import (
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Values of healthResponse.Status.
+const (
+	healthOK         = "ok"
+	healthAuthFailed = "auth_failed"
+)
+
+// healthResponse is the JSON representation of the daemon's health.
+type healthResponse struct {
+	Status string              `json:"status"`
+	Auth   *authHealthResponse `json:"auth,omitempty"`
+}
+
+// authHealthResponse describes failed Jira credentials.
+type authHealthResponse struct {
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	FailingSince        time.Time `json:"failing_since"`
+	LastError           string    `json:"last_error"`
+}
+
+// ticketStateResponse is the JSON representation of a ticket's sync state.
+type ticketStateResponse struct {
+	TicketKey         string    `json:"ticket_key"`
+	LastSynced        time.Time `json:"last_synced"`
+	LastModifiedLocal time.Time `json:"last_modified_local"`
+	LastModifiedJira  time.Time `json:"last_modified_jira"`
+	IsDirty           bool      `json:"is_dirty"`
+	ConflictDetected  bool      `json:"conflict_detected"`
+}
+
+// newTicketStateResponse converts a repository state into its JSON representation.
+func newTicketStateResponse(state *repository.TicketSyncState) ticketStateResponse {
+	return ticketStateResponse{
+		TicketKey:         state.TicketKey,
+		LastSynced:        state.LastSynced,
+		LastModifiedLocal: state.LastModifiedLocal,
+		LastModifiedJira:  state.LastModifiedJira,
+		IsDirty:           state.IsDirty,
+		ConflictDetected:  state.ConflictDetected,
+	}
+}
+
+// syncResultResponse is the JSON representation of a per-ticket sync result.
+type syncResultResponse struct {
+	TicketKey           string   `json:"ticket_key"`
+	Success             bool     `json:"success"`
+	Error               string   `json:"error,omitempty"`
+	ConflictDetected    bool     `json:"conflict_detected"`
+	OperationsPerformed []string `json:"operations_performed"`
+}
+
+// reportResponse is the JSON representation of a sync report.
+type reportResponse struct {
+	ProjectKey    string               `json:"project_key"`
+	Full          bool                 `json:"full"`
+	StartedAt     time.Time            `json:"started_at"`
+	FinishedAt    *time.Time           `json:"finished_at,omitempty"`
+	Succeeded     int                  `json:"succeeded"`
+	Failed        int                  `json:"failed"`
+	Conflicts     int                  `json:"conflicts"`
+	Error         string               `json:"error,omitempty"`
+	CorrelationID string               `json:"correlation_id,omitempty"`
+	Results       []syncResultResponse `json:"results"`
+}
+
+// newReportResponse converts a domain report into its JSON representation.
+func newReportResponse(report *domain.SyncReport) reportResponse {
+	response := reportResponse{
+		ProjectKey: report.ProjectKey,
+		Full:       report.Full,
+		StartedAt:  report.StartedAt.Time(),
+		Succeeded:  report.Succeeded(),
+		Failed:     report.Failed(),
+		Conflicts:  report.Conflicts(),
+		Error:      report.Error,
+		Results:    make([]syncResultResponse, 0, len(report.Results)),
+
+		CorrelationID: report.CorrelationID.String(),
+	}
+
+	if !report.FinishedAt.IsZero() {
+		finishedAt := report.FinishedAt.Time()
+		response.FinishedAt = &finishedAt
+	}
+
+	for _, result := range report.Results {
+		response.Results = append(response.Results, syncResultResponse{
+			TicketKey:           result.TicketKey.String(),
+			Success:             result.Success,
+			Error:               result.Error,
+			ConflictDetected:    result.ConflictDetected,
+			OperationsPerformed: result.OperationsPerformed,
+		})
+	}
+
+	return response
+}
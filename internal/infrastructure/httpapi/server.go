@@ -0,0 +1,306 @@
+// Package httpapi provides the daemon's local HTTP control API.
+// This infrastructure layer exposes sync triggering and state queries to editors and
+// scripts over a loopback TCP port or a unix socket, behind a bearer token and
+// optionally mutual TLS.
+package httpapi
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// shutdownTimeout bounds how long in-flight requests may run after the context is cancelled.
+const shutdownTimeout = 5 * time.Second
+
+// SyncTrigger requests on-demand syncs. It is satisfied by the scheduler service.
+type SyncTrigger interface {
+	// Trigger queues a sync (full or incremental); returns false if one is already pending.
+	Trigger(full bool) bool
+}
+
+// ReportProvider exposes the most recent sync report. It is satisfied by the sync service.
+type ReportProvider interface {
+	// LastReport returns the latest sync report, or nil if no sync has run yet.
+	LastReport() *domain.SyncReport
+}
+
+// AuthStatusProvider exposes whether Jira accepts the configured credentials.
+// It is satisfied by the auth monitor.
+type AuthStatusProvider interface {
+	// Status returns the current authentication status.
+	Status() domain.AuthStatus
+}
+
+// Server is the local control API server.
+//
+// Endpoints:
+//
+//	GET  /v1/health                 liveness check, with status "auth_failed" while Jira
+//	                                rejects the configured credentials
+//	POST /v1/sync?full=true|false   queue a sync (202 Accepted, 409 if one is already queued)
+//	GET  /v1/tickets/{key}/state    sync state of a single ticket
+//	GET  /v1/conflicts              tickets with detected conflicts
+//	GET  /v1/reports/last           report of the most recent sync run
+//	POST /v1/webhooks/jira          persist a Jira webhook delivery for asynchronous
+//	                                processing (202 Accepted, also for redeliveries);
+//	                                served only with an inbox
+//
+// With a token, every request must carry it in an "Authorization: Bearer" header, except
+// webhook deliveries: Jira cannot send the token, so with a webhook secret they must
+// carry Jira's signature of the body instead, and without one the webhook endpoint is
+// only served when no token is required.
+type Server struct {
+	trigger   SyncTrigger
+	reports   ReportProvider
+	stateRepo repository.StateRepository
+	auth      AuthStatusProvider
+	inbox     InboxReceiver
+	token     string
+	secret    string
+	logger    *slog.Logger
+}
+
+// NewServer creates a new control API server.
+func NewServer(trigger SyncTrigger, reports ReportProvider, stateRepo repository.StateRepository, logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Server{
+		trigger:   trigger,
+		reports:   reports,
+		stateRepo: stateRepo,
+		logger:    logger,
+	}
+}
+
+// WithAuthStatus sets where the health endpoint reads the authentication status from
+// (nil leaves it out).
+func (s *Server) WithAuthStatus(auth AuthStatusProvider) *Server {
+	s.auth = auth
+	return s
+}
+
+// WithInbox makes the server accept Jira webhook deliveries into inbox (nil leaves the
+// webhook endpoint out).
+func (s *Server) WithInbox(inbox InboxReceiver) *Server {
+	s.inbox = inbox
+	return s
+}
+
+// WithToken makes every request require token as a bearer token (empty requires none).
+func (s *Server) WithToken(token string) *Server {
+	s.token = token
+	return s
+}
+
+// WithWebhookSecret makes webhook deliveries require a signature made with secret, the
+// secret the Jira webhook is registered with (empty requires none).
+func (s *Server) WithWebhookSecret(secret string) *Server {
+	s.secret = secret
+	return s
+}
+
+// Handler returns the HTTP handler serving all API routes.
+func (s *Server) Handler() http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /v1/health", s.handleHealth)
+	api.HandleFunc("POST /v1/sync", s.handleSync)
+	api.HandleFunc("GET /v1/tickets/{key}/state", s.handleTicketState)
+	api.HandleFunc("GET /v1/conflicts", s.handleConflicts)
+	api.HandleFunc("GET /v1/reports/last", s.handleLastReport)
+
+	var handler http.Handler = api
+	if s.token != "" {
+		handler = requireToken(s.token, api)
+	}
+	if s.inbox == nil || (s.token != "" && s.secret == "") {
+		return handler
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	mux.HandleFunc("POST /v1/webhooks/jira", s.handleJiraWebhook)
+	return mux
+}
+
+// ListenAndServe listens according to cfg, over TLS when cfg.TLS enables it, and serves
+// until the context is cancelled, then shuts down gracefully. Returns nil on clean shutdown.
+func (s *Server) ListenAndServe(ctx context.Context, cfg domain.APIConfig) error {
+	tlsConfig, err := ServerTLSConfig(cfg.TLS)
+	if err != nil {
+		return err
+	}
+	listener, err := Listen(cfg)
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the API on an existing listener until the context is cancelled.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("control API listening", "address", listener.Addr().String())
+		errCh <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("control API server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down control API: %w", err)
+	}
+
+	s.logger.Info("control API stopped")
+	return nil
+}
+
+// Listen opens the listener described by cfg: a unix socket (owner-only permissions,
+// replacing any stale socket file) when SocketPath is set, otherwise a TCP listener on Address.
+func Listen(cfg domain.APIConfig) (net.Listener, error) {
+	if cfg.SocketPath == "" {
+		listener, err := net.Listen("tcp", cfg.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", cfg.Address, err)
+		}
+		return listener, nil
+	}
+
+	// Remove a stale socket left behind by an unclean shutdown
+	if err := os.Remove(cfg.SocketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale socket %s: %w", cfg.SocketPath, err)
+	}
+
+	listener, err := net.Listen("unix", cfg.SocketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on unix socket %s: %w", cfg.SocketPath, err)
+	}
+
+	if err := os.Chmod(cfg.SocketPath, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+
+	return listener, nil
+}
+
+// handleHealth reports that the daemon is running and whether Jira accepts its credentials.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	response := healthResponse{Status: healthOK}
+	if s.auth != nil {
+		if status := s.auth.Status(); status.Failed() {
+			response.Status = healthAuthFailed
+			response.Auth = &authHealthResponse{
+				ConsecutiveFailures: status.ConsecutiveFailures,
+				FailingSince:        status.FailingSince,
+				LastError:           status.LastError,
+			}
+		}
+	}
+
+	// The daemon is alive either way; clients check Status for degraded states
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleSync queues an on-demand sync.
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	full := r.URL.Query().Get("full") == "true"
+
+	if !s.trigger.Trigger(full) {
+		writeError(w, http.StatusConflict, errors.New("a sync is already queued"))
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]any{"queued": true, "full": full})
+}
+
+// handleTicketState returns the sync state of a single ticket.
+func (s *Server) handleTicketState(w http.ResponseWriter, r *http.Request) {
+	state, err := s.stateRepo.GetTicketState(r.Context(), r.PathValue("key"))
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTicketStateResponse(state))
+}
+
+// handleConflicts lists all tickets with detected conflicts.
+func (s *Server) handleConflicts(w http.ResponseWriter, r *http.Request) {
+	states, err := s.stateRepo.GetConflictedTickets(r.Context())
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	response := make([]ticketStateResponse, 0, len(states))
+	for _, state := range states {
+		response = append(response, newTicketStateResponse(state))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleLastReport returns the most recent sync report.
+func (s *Server) handleLastReport(w http.ResponseWriter, r *http.Request) {
+	report := s.reports.LastReport()
+	if report == nil {
+		writeError(w, http.StatusNotFound, errors.New("no sync has run yet"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newReportResponse(report))
+}
+
+// writeDomainError maps domain errors to HTTP status codes.
+func (s *Server) writeDomainError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrEmptyKey):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		s.logger.Error("control API request failed", "error", err)
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// writeError writes a JSON error body.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,396 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+	"github.com/esfisher/jiramd/internal/infrastructure/sqlite"
+)
+
+type fakeTrigger struct {
+	calls   []bool
+	pending bool
+}
+
+func (f *fakeTrigger) Trigger(full bool) bool {
+	if f.pending {
+		return false
+	}
+	f.calls = append(f.calls, full)
+	f.pending = true
+	return true
+}
+
+type fakeReports struct {
+	report *domain.SyncReport
+}
+
+func (f *fakeReports) LastReport() *domain.SyncReport {
+	return f.report
+}
+
+// setupServer creates a server backed by an in-memory state database.
+func setupServer(t *testing.T) (*Server, *fakeTrigger, *fakeReports, repository.StateRepository) {
+	t.Helper()
+
+	db, err := sqlite.NewDatabase(sqlite.DatabaseConfig{
+		Path:         ":memory:",
+		MaxOpenConns: 1,
+		BusyTimeout:  5 * time.Second,
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	stateRepo := sqlite.NewStateRepository(db.DB(), nil)
+	trigger := &fakeTrigger{}
+	reports := &fakeReports{}
+
+	return NewServer(trigger, reports, stateRepo, nil), trigger, reports, stateRepo
+}
+
+func TestServer_Health(t *testing.T) {
+	server, _, _, _ := setupServer(t)
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+// fakeAuth is a fixed authentication status.
+type fakeAuth struct {
+	status domain.AuthStatus
+}
+
+func (f fakeAuth) Status() domain.AuthStatus {
+	return f.status
+}
+
+func TestServer_HealthAuthFailed(t *testing.T) {
+	server, _, _, _ := setupServer(t)
+	failingSince := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	server.WithAuthStatus(fakeAuth{domain.AuthStatus{
+		ConsecutiveFailures: domain.AuthFailureThreshold,
+		FailingSince:        failingSince,
+		LastError:           "unauthorized: token expired",
+	}})
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var body healthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Status != healthAuthFailed || body.Auth == nil || !body.Auth.FailingSince.Equal(failingSince) {
+		t.Errorf("health = %+v, want auth_failed with details", body)
+	}
+
+	// A streak below the threshold is still healthy
+	server.WithAuthStatus(fakeAuth{domain.AuthStatus{ConsecutiveFailures: 1}})
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Status != healthOK {
+		t.Errorf("health status = %q, want %q", body.Status, healthOK)
+	}
+}
+
+func TestServer_Token(t *testing.T) {
+	server, trigger, _, _ := setupServer(t)
+	handler := server.WithToken("s3cret").Handler()
+
+	for name, header := range map[string]string{
+		"no header":    "",
+		"wrong token":  "Bearer nope",
+		"wrong scheme": "Basic s3cret",
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/sync", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: status = %d, want %d with a challenge", name, rec.Code, http.StatusUnauthorized)
+		}
+	}
+	if len(trigger.calls) != 0 {
+		t.Errorf("unauthorized requests triggered syncs: %v", trigger.calls)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sync", nil)
+	req.Header.Set("Authorization", "bearer s3cret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted || len(trigger.calls) != 1 {
+		t.Errorf("status = %d, calls = %v; want the sync queued", rec.Code, trigger.calls)
+	}
+}
+
+func TestServer_TriggerSync(t *testing.T) {
+	server, trigger, _, _ := setupServer(t)
+	handler := server.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/sync?full=true", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if len(trigger.calls) != 1 || !trigger.calls[0] {
+		t.Errorf("trigger calls = %v, want [true]", trigger.calls)
+	}
+
+	// A second request while one is pending is rejected
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/sync", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	// GET is not allowed
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/sync", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServer_TicketState(t *testing.T) {
+	server, _, _, stateRepo := setupServer(t)
+	handler := server.Handler()
+
+	state := &repository.TicketSyncState{
+		TicketKey:         "JMD-1",
+		LastSynced:        time.Now().UTC().Truncate(time.Millisecond),
+		LastModifiedLocal: time.Now().UTC().Truncate(time.Millisecond),
+		LastModifiedJira:  time.Now().UTC().Truncate(time.Millisecond),
+		IsDirty:           true,
+	}
+	if err := stateRepo.SaveTicketState(context.Background(), state); err != nil {
+		t.Fatalf("SaveTicketState failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tickets/JMD-1/state", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var got ticketStateResponse
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.TicketKey != "JMD-1" || !got.IsDirty {
+		t.Errorf("response = %+v, want dirty JMD-1", got)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/tickets/JMD-404/state", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestServer_Conflicts(t *testing.T) {
+	server, _, _, stateRepo := setupServer(t)
+	ctx := context.Background()
+
+	for _, s := range []*repository.TicketSyncState{
+		{TicketKey: "JMD-1", LastSynced: time.Now(), ConflictDetected: true},
+		{TicketKey: "JMD-2", LastSynced: time.Now(), ConflictDetected: false},
+	} {
+		if err := stateRepo.SaveTicketState(ctx, s); err != nil {
+			t.Fatalf("SaveTicketState failed: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/conflicts", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var got []ticketStateResponse
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(got) != 1 || got[0].TicketKey != "JMD-1" {
+		t.Errorf("conflicts = %+v, want only JMD-1", got)
+	}
+}
+
+func TestServer_LastReport(t *testing.T) {
+	server, _, reports, _ := setupServer(t)
+	handler := server.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/reports/last", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status before any sync = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	key, _ := domain.NewTicketKey("JMD-1")
+	report := domain.NewSyncReport("JMD", false)
+	report.AddResult(domain.NewSyncResult(key))
+	report.Finish(nil)
+	reports.report = report
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/reports/last", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var got reportResponse
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.ProjectKey != "JMD" || got.Succeeded != 1 || got.FinishedAt == nil {
+		t.Errorf("report = %+v, want finished JMD report with 1 success", got)
+	}
+}
+
+// fakeInbox records received events, deduplicating them by ID.
+type fakeInbox struct {
+	events []*domain.InboxEvent
+}
+
+func (f *fakeInbox) Receive(ctx context.Context, event *domain.InboxEvent) (bool, error) {
+	for _, received := range f.events {
+		if received.ID == event.ID {
+			return false, nil
+		}
+	}
+	f.events = append(f.events, event)
+	return true, nil
+}
+
+func TestServer_JiraWebhook(t *testing.T) {
+	server, _, _, _ := setupServer(t)
+
+	// Without an inbox the endpoint is not served
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/webhooks/jira", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status without inbox = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	inbox := &fakeInbox{}
+	handler := server.WithInbox(inbox).Handler()
+	deliver := func(id, body string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/webhooks/jira", strings.NewReader(body))
+		if id != "" {
+			req.Header.Set(webhookIDHeader, id)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		var response map[string]any
+		_ = json.NewDecoder(rec.Body).Decode(&response)
+		return rec.Code, response
+	}
+
+	body := `{"webhookEvent":"jira:issue_updated","issue":{"key":"JMD-1","fields":{}}}`
+	if code, response := deliver("delivery-1", body); code != http.StatusAccepted || response["duplicate"] != false {
+		t.Errorf("delivery = %d %v, want %d, not a duplicate", code, response, http.StatusAccepted)
+	}
+	if code, response := deliver("delivery-1", body); code != http.StatusAccepted || response["duplicate"] != true {
+		t.Errorf("redelivery = %d %v, want %d, a duplicate", code, response, http.StatusAccepted)
+	}
+	if len(inbox.events) != 1 {
+		t.Fatalf("received %d events, want 1", len(inbox.events))
+	}
+	event := inbox.events[0]
+	if event.ID != "delivery-1" || event.Type != "jira:issue_updated" || event.TicketKey.String() != "JMD-1" || string(event.Payload) != body {
+		t.Errorf("event = %+v, want the delivered issue update", event)
+	}
+
+	// Events about no ticket are accepted too
+	if code, _ := deliver("", `{"webhookEvent":"sprint_started","sprint":{"id":7}}`); code != http.StatusAccepted {
+		t.Errorf("sprint event status = %d, want %d", code, http.StatusAccepted)
+	}
+	if len(inbox.events) != 2 || !inbox.events[1].TicketKey.IsZero() {
+		t.Errorf("events = %+v, want a second event without a ticket", inbox.events)
+	}
+
+	for name, body := range map[string]string{
+		"malformed":   `{"webhookEvent":`,
+		"invalid key": `{"webhookEvent":"jira:issue_updated","issue":{"key":"not a key"}}`,
+	} {
+		if code, _ := deliver("bad", body); code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", name, code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestServer_JiraWebhookAuth(t *testing.T) {
+	server, _, _, _ := setupServer(t)
+	inbox := &fakeInbox{}
+	body := `{"webhookEvent":"jira:issue_updated","issue":{"key":"JMD-1"}}`
+	mac := hmac.New(sha256.New, []byte("hook-secret"))
+	mac.Write([]byte(body))
+	signed := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		want      int
+	}{
+		{name: "signed", secret: "hook-secret", signature: signed, want: http.StatusAccepted},
+		{name: "unsigned", secret: "hook-secret", want: http.StatusUnauthorized},
+		{name: "signed with another secret", secret: "other-secret", signature: signed, want: http.StatusUnauthorized},
+		// Without a secret, deliveries could not be authenticated at all
+		{name: "no secret", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := server.WithInbox(inbox).WithToken("api-token").WithWebhookSecret(tt.secret).Handler()
+
+			// Jira cannot send the bearer token, so webhooks go without it
+			req := httptest.NewRequest(http.MethodPost, "/v1/webhooks/jira", strings.NewReader(body))
+			req.Header.Set(webhookIDHeader, "delivery-"+tt.name)
+			if tt.signature != "" {
+				req.Header.Set(webhookSignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+
+			// The rest of the API still requires the token
+			rec = httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("health status without token = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+	if len(inbox.events) != 1 {
+		t.Errorf("received %d events, want only the signed one", len(inbox.events))
+	}
+}
@@ -0,0 +1,74 @@
+package httpapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// ServerTLSConfig returns the TLS configuration the API is served with: the server
+// certificate of cfg, and with a client CA, requiring and verifying client certificates.
+// Returns nil if cfg does not enable TLS.
+func ServerTLSConfig(cfg domain.APITLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to load control API certificate: %v", domain.ErrConfig, err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.Mutual() {
+		pool, err := loadCertPool(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// ClientTLSConfig returns the TLS configuration jiramd's own commands connect to the API
+// with: trusting the server certificate of cfg, and with mutual TLS, presenting the
+// client certificate of cfg. Returns nil if cfg does not enable TLS.
+func ClientTLSConfig(cfg domain.APITLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	roots, err := loadCertPool(cfg.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		RootCAs:    roots,
+		MinVersion: tls.VersionTLS12,
+	}
+	if cfg.Mutual() && cfg.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to load control API client certificate: %v", domain.ErrConfig, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// loadCertPool reads a PEM bundle of certificates into a pool.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read certificates: %v", domain.ErrConfig, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: %s holds no PEM certificates", domain.ErrConfig, path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,146 @@
+package httpapi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// testCert is a certificate and key written to PEM files.
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+// newTestCert issues a certificate for 127.0.0.1 signed by parent, or self-signed if
+// parent is nil, and writes it to dir.
+func newTestCert(t *testing.T, dir, name string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &testCert{cert: cert, key: key, certFile: filepath.Join(dir, name+".crt"), keyFile: filepath.Join(dir, name+".key")}
+	if err := os.WriteFile(c.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(c.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestTLSConfig_Mutual(t *testing.T) {
+	dir := t.TempDir()
+	server := newTestCert(t, dir, "server", nil, false)
+	ca := newTestCert(t, dir, "ca", nil, true)
+	client := newTestCert(t, dir, "client", ca, false)
+	stranger := newTestCert(t, dir, "stranger", nil, false)
+
+	cfg := domain.APITLSConfig{
+		CertFile:       server.certFile,
+		KeyFile:        server.keyFile,
+		ClientCAFile:   ca.certFile,
+		ClientCertFile: client.certFile,
+		ClientKeyFile:  client.keyFile,
+	}
+	serverConfig, err := ServerTLSConfig(cfg)
+	if err != nil {
+		t.Fatalf("ServerTLSConfig() error = %v", err)
+	}
+	api := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	api.TLS = serverConfig
+	api.StartTLS()
+	defer api.Close()
+
+	get := func(cfg domain.APITLSConfig) error {
+		clientConfig, err := ClientTLSConfig(cfg)
+		if err != nil {
+			return err
+		}
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+		resp, err := httpClient.Get(api.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			return errors.New(resp.Status)
+		}
+		return nil
+	}
+
+	if err := get(cfg); err != nil {
+		t.Errorf("client with a certificate signed by the CA: %v", err)
+	}
+	withoutCert := cfg
+	withoutCert.ClientCertFile, withoutCert.ClientKeyFile = "", ""
+	if err := get(withoutCert); err == nil {
+		t.Error("client without a certificate was accepted")
+	}
+	withStranger := cfg
+	withStranger.ClientCertFile, withStranger.ClientKeyFile = stranger.certFile, stranger.keyFile
+	if err := get(withStranger); err == nil {
+		t.Error("client with a certificate from another CA was accepted")
+	}
+}
+
+func TestTLSConfig_Disabled(t *testing.T) {
+	if config, err := ServerTLSConfig(domain.APITLSConfig{}); config != nil || err != nil {
+		t.Errorf("ServerTLSConfig(disabled) = %v, %v; want nil", config, err)
+	}
+	if config, err := ClientTLSConfig(domain.APITLSConfig{}); config != nil || err != nil {
+		t.Errorf("ClientTLSConfig(disabled) = %v, %v; want nil", config, err)
+	}
+	missing := domain.APITLSConfig{CertFile: "missing.crt", KeyFile: "missing.key"}
+	if _, err := ServerTLSConfig(missing); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("ServerTLSConfig(missing files) error = %v, want ErrConfig", err)
+	}
+}
@@ -0,0 +1,90 @@
+// Package httpapi provides the daemon's local HTTP control API.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// maxWebhookBody bounds the size of a webhook delivery read into memory.
+const maxWebhookBody = 1 << 20
+
+// webhookIDHeader carries Jira's identifier of a webhook delivery, which stays the same
+// when Jira retries it.
+const webhookIDHeader = "X-Atlassian-Webhook-Identifier"
+
+// InboxReceiver persists webhook events for asynchronous processing. It is satisfied by
+// the inbox service.
+type InboxReceiver interface {
+	// Receive persists an event; returns false if an event with its ID was already received.
+	Receive(ctx context.Context, event *domain.InboxEvent) (bool, error)
+}
+
+// jiraWebhook is the part of a Jira webhook body needed to route the event.
+type jiraWebhook struct {
+	WebhookEvent string `json:"webhookEvent"`
+	Issue        *struct {
+		Key string `json:"key"`
+	} `json:"issue"`
+}
+
+// parseWebhook returns the event delivered with body under the delivery ID id.
+func parseWebhook(id string, body []byte, receivedAt time.Time) (*domain.InboxEvent, error) {
+	var webhook jiraWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, fmt.Errorf("%w: malformed webhook body: %v", domain.ErrInvalidInput, err)
+	}
+
+	var key domain.TicketKey
+	if webhook.Issue != nil && webhook.Issue.Key != "" {
+		parsed, err := domain.NewTicketKey(webhook.Issue.Key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: webhook for invalid ticket key %q", domain.ErrInvalidInput, webhook.Issue.Key)
+		}
+		key = parsed
+	}
+
+	return domain.NewInboxEvent(id, webhook.WebhookEvent, key, body, receivedAt), nil
+}
+
+// handleJiraWebhook persists a Jira webhook delivery and acknowledges it at once; the
+// event is processed asynchronously. Redeliveries are acknowledged without being stored again.
+// With a webhook secret, deliveries without a valid signature are rejected with 401.
+func (s *Server) handleJiraWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if s.secret != "" && !validSignature(s.secret, body, r.Header.Get(webhookSignatureHeader)) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid webhook signature"))
+		return
+	}
+
+	event, err := parseWebhook(r.Header.Get(webhookIDHeader), body, time.Now())
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	added, err := s.inbox.Receive(r.Context(), event)
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]any{"accepted": true, "duplicate": !added})
+}
@@ -0,0 +1,304 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/esfisher/jiramd/internal/application/push"
+	"github.com/esfisher/jiramd/internal/application/ticket"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// Applier applies the operations queued by the ticket service to Jira through a Client,
+// the same label edit of many tickets through Jira's bulk edit API.
+type Applier struct {
+	client  *Client
+	tickets repository.TicketRepository
+}
+
+// NewApplier creates an applier pushing queued operations through client.
+func NewApplier(client *Client) *Applier {
+	return &Applier{client: client}
+}
+
+// WithTickets sets the cache of the tickets the queued edits were made on. Field edits
+// are then only pushed while Jira still has the revision of the ticket the cache holds
+// (see Client.UpdateTicket), and the applier records in the cache the revision each of
+// its own writes leaves in Jira, so the next queued edit of the ticket is not taken for
+// a conflict. Without it, edits are pushed unchecked.
+func (a *Applier) WithTickets(tickets repository.TicketRepository) *Applier {
+	a.tickets = tickets
+	return a
+}
+
+// Verify that Applier implements the push.BulkApplier interface
+var _ push.BulkApplier = (*Applier)(nil)
+
+// Apply applies one queued operation to Jira. Operations that cannot be applied at all,
+// such as payloads that do not decode or operation types that are never pushed, fail
+// with ErrInvalidInput, so they are not retried. With the ticket cache (see
+// WithTickets), a field edit of a ticket changed in Jira since it was cached fails with
+// ErrConflict, also matching ErrSyncConflict, without writing anything.
+// Implements push.Applier.Apply.
+func (a *Applier) Apply(ctx context.Context, op *domain.PendingOperation) error {
+	key := op.TicketKey.String()
+	switch op.Operation {
+	case domain.OpPushStatus:
+		var payload ticket.StatusPayload
+		if err := decodePayload(op, &payload); err != nil {
+			return err
+		}
+		if err := a.client.TransitionTicket(ctx, key, payload.Status); err != nil {
+			return err
+		}
+		a.recordVersion(ctx, key)
+		return nil
+
+	case domain.OpPushField:
+		var payload ticket.FieldPayload
+		if err := decodePayload(op, &payload); err != nil {
+			return err
+		}
+		return a.pushField(ctx, op.TicketKey, payload)
+
+	case domain.OpPostComment:
+		var payload ticket.CommentPayload
+		if err := decodePayload(op, &payload); err != nil {
+			return err
+		}
+		visibility, err := domain.ParseCommentVisibility(payload.Visibility)
+		if err != nil {
+			return err
+		}
+		if _, err := a.client.AddComment(ctx, key, &domain.Comment{
+			Body:       payload.Body,
+			Visibility: visibility,
+			OnBehalfOf: payload.Author,
+		}); err != nil {
+			return err
+		}
+		a.recordVersion(ctx, key)
+		return nil
+
+	case domain.OpCreateTicket:
+		var payload ticket.CreatePayload
+		if err := decodePayload(op, &payload); err != nil {
+			return err
+		}
+		_, failures, err := a.client.CreateTickets(ctx, []*domain.TicketDraft{{
+			ProjectKey:  op.ProjectKey,
+			Summary:     payload.Summary,
+			IssueType:   payload.IssueType,
+			Description: payload.Description,
+			Priority:    payload.Priority,
+			Labels:      payload.Labels,
+		}})
+		if err != nil {
+			return err
+		}
+		if len(failures) > 0 {
+			return failures[0]
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w: operation %s cannot be pushed to Jira", domain.ErrInvalidInput, op.Operation)
+}
+
+// diffedFields are the fields whose queued edits are only pushed while the cached
+// ticket still differs from Jira in them.
+var diffedFields = []string{"summary", "description", "issuetype", "priority", "assignee", "labels"}
+
+// writtenFields are the fields whose queued edits are pushed as the difference between
+// the cached ticket and Jira, rather than as the queued value (see Client.UpdateTicket).
+var writtenFields = []string{"summary", "description", "priority", "labels"}
+
+// pushField pushes a queued field edit of the ticket key, unless the field directions of
+// the client (see Client.WithFieldDirections) keep the field local, or make it read-only:
+// then it fails with ErrReadOnlyField without writing anything. With the ticket cache, it is
+// only pushed while Jira still has the revision of the ticket the edit was made on, and
+// the field still differs from Jira's; writtenFields are written as they differ, so
+// several queued edits of a ticket are pushed with the first and the rest write nothing.
+func (a *Applier) pushField(ctx context.Context, ticketKey domain.TicketKey, payload ticket.FieldPayload) error {
+	key := ticketKey.String()
+	switch a.direction(ticketKey.ProjectKey(), payload.Field) {
+	case domain.SyncLocalOnly:
+		// Edits of local-only fields never leave the machine
+		return nil
+	case domain.SyncJiraToLocal:
+		return fmt.Errorf("%w; revert the local edit to push %s", domain.ReadOnlyFieldError(ticketKey, []string{payload.Field}), key)
+	}
+
+	if a.tickets != nil {
+		cached, err := a.tickets.FindByKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to get cached ticket %s: %w", key, err)
+		}
+		remote, err := a.client.checkVersion(ctx, cached)
+		if err != nil {
+			return err
+		}
+
+		// Jira is still at the revision the edit was made on, so it is the base to diff against
+		if slices.Contains(diffedFields, payload.Field) && !slices.Contains(cached.ChangedFields(remote), payload.Field) {
+			return nil
+		}
+		if slices.Contains(writtenFields, payload.Field) {
+			written, err := a.client.writeChanges(ctx, cached, remote, []string{payload.Field})
+			if err == nil && written {
+				a.recordVersion(ctx, key)
+			}
+			return err
+		}
+	}
+
+	var err error
+	switch payload.Field {
+	case "issuetype":
+		err = a.client.ChangeIssueType(ctx, key, payload.Value, fieldEdits(payload.Fields))
+	case "labels":
+		err = a.client.UpdateLabels(ctx, key, payload.Add, payload.Remove)
+	default:
+		err = a.client.UpdateFields(ctx, key, []FieldEdit{{FieldID: payload.Field, Value: domain.NewFieldValue(payload.Value)}})
+	}
+	if err != nil {
+		return err
+	}
+	a.recordVersion(ctx, key)
+	return nil
+}
+
+// direction returns how field syncs for projectKey's tickets under the field directions
+// of the client.
+func (a *Applier) direction(projectKey, field string) domain.SyncDirection {
+	if a.client.fieldDirections == nil {
+		return domain.FieldDirections(nil).Direction(field)
+	}
+	return a.client.fieldDirections(projectKey).Direction(field)
+}
+
+// recordVersion stores in the cached ticket key the revision Jira has after a write of
+// the applier, which the next queued edit of the ticket is checked against. The write
+// already succeeded, so failures are only logged: the next edit then fails as a conflict
+// rather than the write being repeated.
+func (a *Applier) recordVersion(ctx context.Context, key string) {
+	if a.tickets == nil {
+		return
+	}
+	err := func() error {
+		remote, err := a.client.fetchFreshTicket(ctx, key)
+		if err != nil {
+			return err
+		}
+		cached, err := a.tickets.FindByKey(ctx, key)
+		if err != nil {
+			return err
+		}
+		cached.Updated = remote.Updated
+		return a.tickets.Update(ctx, cached)
+	}()
+	if err != nil {
+		a.client.logger.WarnContext(ctx, "failed to record the Jira revision of a pushed ticket",
+			"ticket_key", key,
+			"error", err)
+	}
+}
+
+// Bulkable reports whether op is a label edit, which Jira's bulk edit API can apply to
+// many tickets at once, of a project whose labels are pushed to Jira.
+// Implements push.BulkApplier.Bulkable.
+func (a *Applier) Bulkable(op *domain.PendingOperation) bool {
+	if op.Operation != domain.OpPushField {
+		return false
+	}
+	var payload ticket.FieldPayload
+	if err := decodePayload(op, &payload); err != nil {
+		return false
+	}
+	return payload.Field == "labels" && len(payload.Add)+len(payload.Remove) > 0 &&
+		a.direction(op.ProjectKey, payload.Field) == domain.SyncBidirectional
+}
+
+// ApplyBulk applies the same label edit to the tickets of ops through Jira's bulk edit
+// API: one bulk edit adds the labels and another removes them from the tickets the
+// first did not fail for. The edits keep the labels they do not name, so they are not
+// checked against the cached revisions (see WithTickets).
+// Implements push.BulkApplier.ApplyBulk.
+func (a *Applier) ApplyBulk(ctx context.Context, ops []*domain.PendingOperation) (failures []error, err error) {
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	var payload ticket.FieldPayload
+	if err := decodePayload(ops[0], &payload); err != nil {
+		return nil, err
+	}
+	if payload.Field != "labels" {
+		return nil, fmt.Errorf("%w: field %s cannot be pushed in bulk", domain.ErrInvalidInput, payload.Field)
+	}
+
+	failures = make([]error, len(ops))
+	for _, edit := range []struct {
+		mode   LabelEditMode
+		labels []string
+	}{
+		{mode: LabelsAdd, labels: payload.Add},
+		{mode: LabelsRemove, labels: payload.Remove},
+	} {
+		if len(edit.labels) == 0 {
+			continue
+		}
+
+		var keys []domain.TicketKey
+		var index []int
+		for i, op := range ops {
+			if failures[i] == nil {
+				keys = append(keys, op.TicketKey)
+				index = append(index, i)
+			}
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		editFailures, err := a.client.BulkEditLabels(ctx, keys, edit.mode, edit.labels)
+		for j, i := range index {
+			if j < len(editFailures) {
+				failures[i] = editFailures[j]
+			}
+		}
+		if err != nil {
+			return failures, err
+		}
+	}
+	for i, op := range ops {
+		if failures[i] == nil {
+			a.recordVersion(ctx, op.TicketKey.String())
+		}
+	}
+	return failures, nil
+}
+
+// decodePayload decodes the JSON payload of op into v.
+func decodePayload(op *domain.PendingOperation, v interface{}) error {
+	if err := json.Unmarshal([]byte(op.Payload), v); err != nil {
+		return fmt.Errorf("%w: payload of operation %d: %v", domain.ErrInvalidInput, op.ID, err)
+	}
+	return nil
+}
+
+// fieldEdits converts field values by Jira field ID into edits setting them, sorted by
+// field ID.
+func fieldEdits(fields map[string]string) []FieldEdit {
+	edits := make([]FieldEdit, 0, len(fields))
+	for id, value := range fields {
+		edits = append(edits, FieldEdit{FieldID: id, Value: domain.NewFieldValue(value)})
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].FieldID < edits[j].FieldID
+	})
+	return edits
+}
@@ -0,0 +1,288 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/application/ticket"
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository/fakes"
+)
+
+// queuedOp returns a queued operation of JMD-1 with payload encoded as JSON.
+func queuedOp(t *testing.T, operation domain.OperationType, payload interface{}) *domain.PendingOperation {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+	key, _ := domain.NewTicketKey("JMD-1")
+	op, err := domain.NewPendingOperation("JMD", key, operation, string(data))
+	if err != nil {
+		t.Fatalf("NewPendingOperation failed: %v", err)
+	}
+	return op
+}
+
+func TestApplier_Apply(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/JMD-1/transitions":
+			json.NewEncoder(w).Encode(transitionsResponse{Transitions: []transition{
+				{ID: "31", Name: "Finish", To: namedField{Name: "Done"}},
+			}})
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/3/issue/JMD-1/transitions":
+			var req transitionRequest
+			if err := json.Unmarshal(body, &req); err != nil || req.Transition.ID != "31" {
+				t.Errorf("transition request = %s, want transition 31", body)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/3/issue/JMD-1/comment":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "10001"})
+		default:
+			http.Error(w, "unexpected request", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	applier := NewApplier(NewClient(server.URL, "me@example.com", "secret"))
+	ctx := context.Background()
+
+	if err := applier.Apply(ctx, queuedOp(t, domain.OpPushStatus, ticket.StatusPayload{Status: "Done"})); err != nil {
+		t.Errorf("Apply(status) failed: %v", err)
+	}
+	if err := applier.Apply(ctx, queuedOp(t, domain.OpPostComment, ticket.CommentPayload{Body: "Shipped"})); err != nil {
+		t.Errorf("Apply(comment) failed: %v", err)
+	}
+	want := []string{
+		"GET /rest/api/3/issue/JMD-1/transitions",
+		"POST /rest/api/3/issue/JMD-1/transitions",
+		"POST /rest/api/3/issue/JMD-1/comment",
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("requests = %v, want %v", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("request %d = %s, want %s", i, requests[i], want[i])
+		}
+	}
+
+	if err := applier.Apply(ctx, queuedOp(t, domain.OpPushStatus, ticket.StatusPayload{Status: "Archived"})); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Apply(unavailable status) error = %v, want ErrInvalidInput", err)
+	}
+
+	broken := queuedOp(t, domain.OpPushStatus, nil)
+	broken.Payload = "{"
+	if err := applier.Apply(ctx, broken); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Apply(broken payload) error = %v, want ErrInvalidInput", err)
+	}
+	if err := applier.Apply(ctx, queuedOp(t, domain.OpPullTicket, nil)); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Apply(pull) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestApplier_Apply_Labels(t *testing.T) {
+	state := &editServer{updated: time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	applier := NewApplier(NewClient(server.URL, "me@example.com", "secret"))
+	op := queuedOp(t, domain.OpPushField, ticket.FieldPayload{Field: "labels", Add: []string{"api"}, Remove: []string{"old"}})
+	if err := applier.Apply(context.Background(), op); err != nil {
+		t.Fatalf("Apply(labels) failed: %v", err)
+	}
+
+	// The edit adds and removes the labels rather than replacing the list
+	if len(state.edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(state.edits))
+	}
+	got, _ := json.Marshal(state.edits[0])
+	if want := `{"update":{"labels":[{"add":"api"},{"remove":"old"}]}}`; string(got) != want {
+		t.Errorf("edit = %s, want %s", got, want)
+	}
+}
+
+func TestApplier_Apply_FieldDirections(t *testing.T) {
+	state := &editServer{updated: time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	sync := domain.SyncConfig{
+		FieldDirections: domain.FieldDirections{"description": domain.SyncLocalOnly},
+		ProjectFieldDirections: map[string]domain.FieldDirections{
+			"JMD": {"labels": domain.SyncJiraToLocal},
+		},
+	}
+	applier := NewApplier(NewClient(server.URL, "me@example.com", "secret").WithFieldDirections(sync.FieldDirectionsFor))
+	ctx := context.Background()
+
+	labels := queuedOp(t, domain.OpPushField, ticket.FieldPayload{Field: "labels", Add: []string{"api"}})
+	if err := applier.Apply(ctx, labels); !errors.Is(err, domain.ErrReadOnlyField) {
+		t.Errorf("Apply(read-only labels) error = %v, want ErrReadOnlyField", err)
+	}
+	if applier.Bulkable(labels) {
+		t.Error("Bulkable(read-only labels) = true, want false")
+	}
+
+	description := queuedOp(t, domain.OpPushField, ticket.FieldPayload{Field: "description", Value: "Private notes"})
+	if err := applier.Apply(ctx, description); err != nil {
+		t.Errorf("Apply(local-only description) failed: %v", err)
+	}
+	if len(state.edits) != 0 {
+		t.Errorf("wrote %d edits, want none", len(state.edits))
+	}
+}
+
+func TestApplier_Apply_StaleTicket(t *testing.T) {
+	pulled := time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)
+	state := &editServer{updated: pulled}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+	cached, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket failed: %v", err)
+	}
+	// The local edits the queued operations were made with
+	cached.Summary = "Local summary"
+	cached.Labels = append(cached.Labels, "api")
+	tickets := fakes.NewTicketRepository(cached)
+	applier := NewApplier(client).WithTickets(tickets)
+	ops := []*domain.PendingOperation{
+		queuedOp(t, domain.OpPushField, ticket.FieldPayload{Field: "labels", Add: []string{"api"}}),
+		queuedOp(t, domain.OpPushField, ticket.FieldPayload{Field: "summary", Value: "Local summary"}),
+	}
+
+	// Someone edits the ticket in Jira after it was cached
+	state.updated = pulled.Add(time.Hour)
+	if err := applier.Apply(ctx, ops[0]); !errors.Is(err, domain.ErrConflict) || !errors.Is(err, domain.ErrSyncConflict) {
+		t.Fatalf("Apply(stale edit) error = %v, want ErrConflict and ErrSyncConflict", err)
+	}
+	if len(state.edits) != 0 {
+		t.Fatalf("stale edit wrote %d edits to Jira", len(state.edits))
+	}
+
+	// Once the ticket is pulled again, its edits go through as they differ from Jira,
+	// and the applier's own writes are not taken for conflicts
+	cached.Updated = state.updated
+	if err := tickets.Update(ctx, cached); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	for _, op := range ops {
+		if err := applier.Apply(ctx, op); err != nil {
+			t.Fatalf("Apply(%s) failed: %v", op.Payload, err)
+		}
+	}
+	if len(state.edits) != 2 {
+		t.Fatalf("got %d edits, want 2", len(state.edits))
+	}
+	for i, want := range []string{
+		`{"update":{"labels":[{"add":"api"}]}}`,
+		`{"fields":{"summary":"Local summary"}}`,
+	} {
+		if got, _ := json.Marshal(state.edits[i]); string(got) != want {
+			t.Errorf("edit %d = %s, want %s", i, got, want)
+		}
+	}
+	recorded, err := tickets.FindByKey(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindByKey failed: %v", err)
+	}
+	if !recorded.Updated.Equal(state.updated) {
+		t.Errorf("cached Updated = %v, want Jira's %v after the pushes", recorded.Updated, state.updated)
+	}
+}
+
+func TestApplier_ApplyBulk(t *testing.T) {
+	var edits []bulkEditRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"issues": []map[string]string{
+					{"id": "10001", "key": "JMD-1"}, {"id": "10002", "key": "JMD-2"}, {"id": "10003", "key": "JMD-3"},
+				},
+				"isLast": true,
+			})
+		case "/rest/api/3/bulk/issues/fields":
+			var edit bulkEditRequest
+			json.NewDecoder(r.Body).Decode(&edit)
+			edits = append(edits, edit)
+			json.NewEncoder(w).Encode(map[string]string{"taskId": fmt.Sprint(len(edits))})
+		case "/rest/api/3/bulk/queue/1":
+			// Adding fails for JMD-2
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status":                 "COMPLETE",
+				"successfulIssues":       []int64{10001, 10003},
+				"failedAccessibleIssues": map[string][]string{"10002": {"Field 'labels' cannot be set"}},
+			})
+		case "/rest/api/3/bulk/queue/2":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status":           "COMPLETE",
+				"successfulIssues": []int64{10001, 10003},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	applier := NewApplier(NewClient(server.URL, "me@example.com", "secret"))
+	payload := ticket.FieldPayload{Field: "labels", Add: []string{"q3"}, Remove: []string{"q2"}}
+	var ops []*domain.PendingOperation
+	for _, k := range []string{"JMD-1", "JMD-2", "JMD-3"} {
+		op := queuedOp(t, domain.OpPushField, payload)
+		op.TicketKey, _ = domain.NewTicketKey(k)
+		ops = append(ops, op)
+		if !applier.Bulkable(op) {
+			t.Errorf("Bulkable(%s label edit) = false, want true", k)
+		}
+	}
+
+	failures, err := applier.ApplyBulk(context.Background(), ops)
+	if err != nil {
+		t.Fatalf("ApplyBulk failed: %v", err)
+	}
+	if len(failures) != 3 || failures[0] != nil || !errors.Is(failures[1], domain.ErrInvalidInput) || failures[2] != nil {
+		t.Errorf("failures = %v, want only JMD-2 to fail", failures)
+	}
+
+	// The labels are removed only from the tickets they were added to
+	if len(edits) != 2 {
+		t.Fatalf("got %d bulk edits, want 2", len(edits))
+	}
+	for i, want := range []struct {
+		mode LabelEditMode
+		ids  string
+	}{
+		{mode: LabelsAdd, ids: "[10001 10002 10003]"},
+		{mode: LabelsRemove, ids: "[10001 10003]"},
+	} {
+		field := edits[i].EditedFieldsInput.LabelsFields[0]
+		if field.Mode != want.mode || fmt.Sprint(edits[i].SelectedIssueIdsOrKeys) != want.ids {
+			t.Errorf("bulk edit %d = %s of %v, want %s of %s", i, field.Mode, edits[i].SelectedIssueIdsOrKeys, want.mode, want.ids)
+		}
+	}
+
+	for name, op := range map[string]*domain.PendingOperation{
+		"status":   queuedOp(t, domain.OpPushStatus, ticket.StatusPayload{Status: "Done"}),
+		"assignee": queuedOp(t, domain.OpPushField, ticket.FieldPayload{Field: "assignee", Value: "ana"}),
+	} {
+		if applier.Bulkable(op) {
+			t.Errorf("Bulkable(%s) = true, want false", name)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// DownloadAttachment writes the content of a ticket's attachment to w. Jira redirects
+// the request to its media service, which the HTTP client follows.
+// Returns ErrNotFound if the attachment was deleted or isn't visible to the user.
+func (c *Client) DownloadAttachment(ctx context.Context, attachment domain.Attachment, w io.Writer) error {
+	path := "/rest/api/3/attachment/content/" + url.PathEscape(attachment.ID)
+	resp, err := c.send(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return c.responseError(ctx, resp, nil)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download attachment %s: %w", attachment.Filename, err)
+	}
+	return nil
+}
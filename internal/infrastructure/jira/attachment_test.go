@@ -0,0 +1,40 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestClient_DownloadAttachment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/attachment/content/10":
+			http.Redirect(w, r, "/media/10", http.StatusSeeOther)
+		case "/media/10":
+			w.Write([]byte("png bytes"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+
+	var content bytes.Buffer
+	if err := client.DownloadAttachment(ctx, domain.Attachment{ID: "10", Filename: "shot.png"}, &content); err != nil {
+		t.Fatalf("DownloadAttachment() error = %v", err)
+	}
+	if content.String() != "png bytes" {
+		t.Errorf("DownloadAttachment() wrote %q, want %q", content.String(), "png bytes")
+	}
+
+	if err := client.DownloadAttachment(ctx, domain.Attachment{ID: "11", Filename: "gone.png"}, &content); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DownloadAttachment() of a deleted attachment error = %v, want ErrNotFound", err)
+	}
+}
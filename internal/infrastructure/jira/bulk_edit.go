@@ -0,0 +1,238 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// BulkEditLimit is the most issues Jira edits in one bulk edit task.
+const BulkEditLimit = 1000
+
+// bulkEditPollInterval is how often a running bulk edit task is checked.
+const bulkEditPollInterval = time.Second
+
+// LabelEditMode selects how BulkEditLabels changes each ticket's labels.
+type LabelEditMode string
+
+const (
+	// LabelsAdd adds the labels, keeping the ticket's other labels
+	LabelsAdd LabelEditMode = "ADD"
+
+	// LabelsRemove removes the labels, keeping the ticket's other labels
+	LabelsRemove LabelEditMode = "REMOVE"
+
+	// LabelsReplace replaces the ticket's labels with the given ones
+	LabelsReplace LabelEditMode = "REPLACE"
+)
+
+// bulkLabel is a label in a bulk edit request.
+type bulkLabel struct {
+	Name string `json:"name"`
+}
+
+// bulkLabelsField is the change to the labels field in a bulk edit request.
+type bulkLabelsField struct {
+	FieldID string        `json:"fieldId"`
+	Mode    LabelEditMode `json:"bulkEditMultiSelectFieldOption"`
+	Labels  []bulkLabel   `json:"labels"`
+}
+
+// bulkEditRequest is the body of POST /rest/api/3/bulk/issues/fields.
+type bulkEditRequest struct {
+	SelectedIssueIdsOrKeys []string `json:"selectedIssueIdsOrKeys"`
+	SelectedActions        []string `json:"selectedActions"`
+	EditedFieldsInput      struct {
+		LabelsFields []bulkLabelsField `json:"labelsFields"`
+	} `json:"editedFieldsInput"`
+	SendBulkNotification bool `json:"sendBulkNotification"`
+}
+
+// bulkEditResponse identifies the task Jira runs a bulk edit in.
+type bulkEditResponse struct {
+	TaskID string `json:"taskId"`
+}
+
+// bulkTaskResponse is the progress of a bulk edit task, from GET /rest/api/3/bulk/queue/{taskId}.
+// Issues are identified by ID rather than key.
+type bulkTaskResponse struct {
+	Status                 string              `json:"status"`
+	SuccessfulIssues       []int64             `json:"successfulIssues"`
+	FailedAccessibleIssues map[string][]string `json:"failedAccessibleIssues"`
+}
+
+// done reports whether the task has stopped running.
+func (r *bulkTaskResponse) done() bool {
+	switch r.Status {
+	case "ENQUEUED", "RUNNING":
+		return false
+	default:
+		return true
+	}
+}
+
+// issueRef is the identity of an issue in search results.
+type issueRef struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// BulkEditLabels applies the same label change to many tickets through Jira's bulk edit
+// API, in tasks of at most BulkEditLimit tickets, instead of editing them one by one.
+// failures[i] holds the error for keys[i], or nil if it was edited; tickets that do not
+// exist or are not visible fail with ErrNotFound. err is set only when a request as a
+// whole failed, in which case tickets of the failed and later tasks may not be edited.
+func (c *Client) BulkEditLabels(ctx context.Context, keys []domain.TicketKey, mode LabelEditMode, labels []string) (failures []error, err error) {
+	switch mode {
+	case LabelsAdd, LabelsRemove, LabelsReplace:
+	default:
+		return nil, fmt.Errorf("%w: unknown label edit mode %q", domain.ErrInvalidInput, mode)
+	}
+
+	failures = make([]error, len(keys))
+	for start := 0; start < len(keys); start += BulkEditLimit {
+		end := min(start+BulkEditLimit, len(keys))
+		if err := c.bulkEditLabels(ctx, keys[start:end], mode, labels, failures[start:end]); err != nil {
+			return failures, err
+		}
+	}
+	return failures, nil
+}
+
+// bulkEditLabels runs one bulk edit task and records the result of each ticket in failures.
+func (c *Client) bulkEditLabels(ctx context.Context, keys []domain.TicketKey, mode LabelEditMode, labels []string, failures []error) error {
+	ids, err := c.issueIDs(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	req := bulkEditRequest{SelectedActions: []string{"labels"}}
+	for i, key := range keys {
+		id, ok := ids[key.String()]
+		if !ok {
+			failures[i] = fmt.Errorf("%w: %s", domain.ErrNotFound, key)
+			continue
+		}
+		req.SelectedIssueIdsOrKeys = append(req.SelectedIssueIdsOrKeys, id)
+	}
+	if len(req.SelectedIssueIdsOrKeys) == 0 {
+		return nil
+	}
+
+	field := bulkLabelsField{FieldID: "labels", Mode: mode, Labels: make([]bulkLabel, 0, len(labels))}
+	for _, label := range labels {
+		field.Labels = append(field.Labels, bulkLabel{Name: label})
+	}
+	req.EditedFieldsInput.LabelsFields = append(req.EditedFieldsInput.LabelsFields, field)
+
+	var task bulkEditResponse
+	if err := c.doRequest(ctx, http.MethodPost, "/rest/api/3/bulk/issues/fields", req, &task); err != nil {
+		return err
+	}
+
+	result, err := c.waitForBulkTask(ctx, task.TaskID)
+	if err != nil {
+		return err
+	}
+
+	succeeded := make(map[string]bool, len(result.SuccessfulIssues))
+	for _, id := range result.SuccessfulIssues {
+		succeeded[fmt.Sprint(id)] = true
+	}
+	for i, key := range keys {
+		if failures[i] != nil {
+			continue
+		}
+		id := ids[key.String()]
+		switch {
+		case succeeded[id]:
+		case len(result.FailedAccessibleIssues[id]) > 0:
+			failures[i] = fmt.Errorf("%w: %s", domain.ErrInvalidInput, strings.Join(result.FailedAccessibleIssues[id], "; "))
+		default:
+			failures[i] = fmt.Errorf("jira bulk edit task %s finished with status %s without editing %s", task.TaskID, result.Status, key)
+		}
+	}
+	return nil
+}
+
+// waitForBulkTask polls a bulk edit task until it stops running.
+func (c *Client) waitForBulkTask(ctx context.Context, taskID string) (*bulkTaskResponse, error) {
+	path := "/rest/api/3/bulk/queue/" + url.PathEscape(taskID)
+	for {
+		var result bulkTaskResponse
+		if err := c.doRequest(ctx, http.MethodGet, path, nil, &result); err != nil {
+			return nil, err
+		}
+		if result.done() {
+			return &result, nil
+		}
+
+		if err := sleep(ctx, bulkEditPollInterval); err != nil {
+			return nil, fmt.Errorf("waiting for jira bulk edit task %s: %w", taskID, err)
+		}
+	}
+}
+
+// issueIDs looks up the IDs of tickets by key, which bulk edit results refer to.
+// Tickets that do not exist or are not visible are missing from the result.
+//
+// The IDs are searched for in one query, but Jira rejects a "key in" query naming a
+// key it does not know with 400, so then they are looked up one key at a time.
+func (c *Client) issueIDs(ctx context.Context, keys []domain.TicketKey) (map[string]string, error) {
+	ids, err := c.searchIssueIDs(ctx, keys)
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		return ids, err
+	}
+
+	ids = make(map[string]string, len(keys))
+	for _, key := range keys {
+		var ref issueRef
+		err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(key.String())+"?fields=key", nil, &ref)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		ids[key.String()] = ref.ID
+	}
+	return ids, nil
+}
+
+// searchIssueIDs looks up the IDs of tickets by key in a single search.
+func (c *Client) searchIssueIDs(ctx context.Context, keys []domain.TicketKey) (map[string]string, error) {
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, key.String())
+	}
+
+	ids := make(map[string]string, len(keys))
+	req := searchRequest{
+		JQL:        "key in (" + strings.Join(names, ",") + ")",
+		Fields:     []string{"key"},
+		MaxResults: searchPageSize,
+	}
+	for {
+		var page struct {
+			Issues        []issueRef `json:"issues"`
+			NextPageToken string     `json:"nextPageToken"`
+			IsLast        bool       `json:"isLast"`
+		}
+		if err := c.doRequest(ctx, http.MethodPost, "/rest/api/3/search/jql", req, &page); err != nil {
+			return nil, err
+		}
+		for _, ref := range page.Issues {
+			ids[ref.Key] = ref.ID
+		}
+		if page.IsLast || page.NextPageToken == "" || len(page.Issues) == 0 {
+			return ids, nil
+		}
+		req.NextPageToken = page.NextPageToken
+	}
+}
@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestClient_BulkEditLabels(t *testing.T) {
+	var edit bulkEditRequest
+	polls, searches := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			// JMD-3 does not exist, so Jira rejects the query naming it
+			searches++
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string][]string{
+				"errorMessages": {"An issue with key 'JMD-3' does not exist for field 'key'."},
+			})
+		case "/rest/api/3/issue/JMD-1":
+			json.NewEncoder(w).Encode(map[string]string{"id": "10001", "key": "JMD-1"})
+		case "/rest/api/3/issue/JMD-2":
+			json.NewEncoder(w).Encode(map[string]string{"id": "10002", "key": "JMD-2"})
+		case "/rest/api/3/bulk/issues/fields":
+			json.NewDecoder(r.Body).Decode(&edit)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]string{"taskId": "42"})
+		case "/rest/api/3/bulk/queue/42":
+			polls++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status":                 "COMPLETE",
+				"successfulIssues":       []int64{10001},
+				"failedAccessibleIssues": map[string][]string{"10002": {"Field 'labels' cannot be set"}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	keys := make([]domain.TicketKey, 0, 3)
+	for _, k := range []string{"JMD-1", "JMD-2", "JMD-3"} {
+		key, _ := domain.NewTicketKey(k)
+		keys = append(keys, key)
+	}
+
+	failures, err := NewClient(server.URL, "me@example.com", "secret").BulkEditLabels(context.Background(), keys, LabelsAdd, []string{"q3"})
+	if err != nil {
+		t.Fatalf("BulkEditLabels failed: %v", err)
+	}
+
+	if len(edit.SelectedIssueIdsOrKeys) != 2 || edit.EditedFieldsInput.LabelsFields[0].Mode != LabelsAdd ||
+		edit.EditedFieldsInput.LabelsFields[0].Labels[0].Name != "q3" {
+		t.Errorf("bulk edit request = %+v", edit)
+	}
+	if searches != 1 {
+		t.Errorf("searched %d times, want 1 before looking keys up one by one", searches)
+	}
+	if polls != 1 {
+		t.Errorf("task polled %d times, want 1", polls)
+	}
+
+	if failures[0] != nil {
+		t.Errorf("JMD-1 failure = %v, want nil", failures[0])
+	}
+	if !errors.Is(failures[1], domain.ErrInvalidInput) {
+		t.Errorf("JMD-2 failure = %v, want ErrInvalidInput", failures[1])
+	}
+	if !errors.Is(failures[2], domain.ErrNotFound) {
+		t.Errorf("JMD-3 failure = %v, want ErrNotFound", failures[2])
+	}
+
+	if _, err := NewClient(server.URL, "me@example.com", "secret").BulkEditLabels(context.Background(), keys, "MERGE", nil); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("unknown mode error = %v, want ErrInvalidInput", err)
+	}
+}
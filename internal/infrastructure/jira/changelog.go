@@ -0,0 +1,85 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// changelogPageSize is the number of changelog entries requested per page (Jira's maximum).
+const changelogPageSize = 100
+
+// changelogItem is one field changed by a changelog entry.
+type changelogItem struct {
+	Field      string `json:"field"`
+	FieldID    string `json:"fieldId"`
+	FromString string `json:"fromString"`
+	ToString   string `json:"toString"`
+}
+
+// changelogEntry is one entry of an issue's changelog: the fields changed together.
+type changelogEntry struct {
+	ID      string          `json:"id"`
+	Author  *user           `json:"author"`
+	Created string          `json:"created"`
+	Items   []changelogItem `json:"items"`
+}
+
+// changelogPage is a page of GET /rest/api/3/issue/{key}/changelog.
+type changelogPage struct {
+	StartAt int              `json:"startAt"`
+	Total   int              `json:"total"`
+	IsLast  bool             `json:"isLast"`
+	Values  []changelogEntry `json:"values"`
+}
+
+// FetchStatusHistory returns the status changes in a ticket's changelog, oldest first,
+// with statuses under their local names (see WithStatusMap).
+// Returns ErrNotFound if the ticket doesn't exist.
+func (c *Client) FetchStatusHistory(ctx context.Context, ticketKey string) ([]domain.StatusChange, error) {
+	key, err := domain.NewTicketKey(ticketKey)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]domain.StatusChange, 0)
+	for startAt := 0; ; {
+		query := url.Values{
+			"startAt":    {fmt.Sprint(startAt)},
+			"maxResults": {fmt.Sprint(changelogPageSize)},
+		}
+		path := "/rest/api/3/issue/" + url.PathEscape(key.String()) + "/changelog?" + query.Encode()
+
+		var page changelogPage
+		if err := c.doRequest(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, entry := range page.Values {
+			for _, item := range entry.Items {
+				if item.FieldID != "status" && item.Field != "status" {
+					continue
+				}
+				at, err := parseJiraTime(entry.Created)
+				if err != nil {
+					return nil, fmt.Errorf("invalid time on changelog entry %s: %w", entry.ID, err)
+				}
+				history = append(history, domain.StatusChange{
+					ID:     entry.ID,
+					From:   c.statuses.ToLocal(item.FromString),
+					To:     c.statuses.ToLocal(item.ToString),
+					Author: entry.Author.name(),
+					At:     at.UTC(),
+				})
+			}
+		}
+
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 || startAt >= page.Total {
+			domain.SortStatusHistory(history)
+			return history, nil
+		}
+	}
+}
@@ -3,50 +3,615 @@
 package jira
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/esfisher/jiramd/internal/domain"
 )
 
+// maxRetryAfter caps the wait Jira can ask for before a retry.
+const maxRetryAfter = time.Minute
+
 // Client represents a Jira API client.
 // It implements communication with Jira Cloud REST API.
 //
-// TODO: Inject http.Client (or interface) and logger via NewClient for better testability
-// and control over timeouts/retries. Map HTTP status codes to domain errors (404 -> ErrNotFound,
-// 401/403 -> ErrUnauthorized).
+// TODO: Inject a logger via NewClient to trace requests and retries.
 type Client struct {
 	baseURL string
 	email   string
 	token   string
+
+	// readClient sends requests that only read from Jira and writeClient those that
+	// change it, so each can have its own timeout
+	readClient  *http.Client
+	writeClient *http.Client
+
+	// authObserver is told the outcome of every response (nil for none)
+	authObserver AuthObserver
+
+	// ticketFetches coalesces concurrent fetches of the same ticket
+	ticketFetches flightGroup
+
+	// limiter bounds the requests sent, shared with the other clients of the account
+	// (nil for no limit)
+	limiter *RateLimiter
+
+	// fieldDirections returns the field direction overrides of a project (nil for none)
+	fieldDirections func(projectKey string) domain.FieldDirections
+
+	// statuses translates Jira status names to local ones and back
+	statuses domain.StatusMap
+
+	// priorities returns how a project's priority names are translated (nil for none)
+	priorities func(projectKey string) domain.PriorityMap
+
+	// fields caches the site's field definitions, which field edits are built by
+	fields fieldDefinitions
+
+	// rawFields fetches every field of issues, keeping the ones toTicket does not map
+	// under domain.JiraFieldsField
+	rawFields bool
+
+	// authors records the profiles of the assignee and reporter of issues under
+	// domain.AuthorsField
+	authors bool
+
+	// attribution records who wrote the comments AddComment posts
+	attribution domain.CommentAttribution
+
+	// retry is how failed requests are retried
+	retry domain.HTTPRetryPolicy
+
+	// random jitters retry delays (overridable in tests)
+	random func() float64
+
+	// logger reports what requests leave out to succeed, such as fields the user may
+	// not edit
+	logger *slog.Logger
+}
+
+// AuthObserver is told the outcome of Jira responses, nil for success or the request's
+// error, so authentication failures can be tracked. It is satisfied by auth.Monitor.
+type AuthObserver interface {
+	Observe(ctx context.Context, err error)
 }
 
-// NewClient creates a new Jira API client.
+// NewClient creates a new Jira API client using the default transport and timeouts.
 func NewClient(baseURL, email, token string) *Client {
 	return &Client{
-		baseURL: baseURL,
-		email:   email,
-		token:   token,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		email:       email,
+		token:       token,
+		readClient:  &http.Client{Timeout: domain.DefaultHTTPTimeout},
+		writeClient: &http.Client{Timeout: domain.DefaultHTTPTimeout},
+		retry:       domain.DefaultHTTPRetryPolicy(),
+		random:      rand.Float64,
+		logger:      slog.Default(),
+	}
+}
+
+// NewClientFromConfig creates a Jira API client for cfg, connecting as configured in
+// cfg.HTTP (see NewTransport). Every client created for the same account shares the
+// rate limit of cfg.HTTP.RateLimit.
+func NewClientFromConfig(cfg domain.JiraConfig) (*Client, error) {
+	transport, err := NewTransport(cfg.HTTP)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewClient(cfg.BaseURL, cfg.Email, cfg.Token)
+	c.readClient = &http.Client{Transport: transport, Timeout: orDefaultTimeout(cfg.HTTP.ReadTimeout)}
+	c.writeClient = &http.Client{Transport: transport, Timeout: orDefaultTimeout(cfg.HTTP.WriteTimeout)}
+	c.limiter = sharedRateLimiter(c.baseURL, cfg.Email, cfg.HTTP.RateLimit)
+	c.retry = cfg.HTTP.RetryPolicy()
+	c.attribution = cfg.Comments
+	return c, nil
+}
+
+// WithRateLimiter sets the limiter every request waits for, which may be shared with
+// other clients (nil for no limit).
+func (c *Client) WithRateLimiter(limiter *RateLimiter) *Client {
+	c.limiter = limiter
+	return c
+}
+
+// WithRetryPolicy sets how failed requests are retried.
+func (c *Client) WithRetryPolicy(policy domain.HTTPRetryPolicy) *Client {
+	c.retry = policy
+	return c
+}
+
+// WithAuthObserver sets the observer told the outcome of every response.
+func (c *Client) WithAuthObserver(observer AuthObserver) *Client {
+	c.authObserver = observer
+	return c
+}
+
+// WithLogger sets the logger warnings are reported to (nil for slog.Default).
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	c.logger = logger
+	return c
+}
+
+// WithFieldDirections sets the field direction overrides UpdateTicket honors, usually
+// domain.SyncConfig.FieldDirectionsFor.
+func (c *Client) WithFieldDirections(directions func(projectKey string) domain.FieldDirections) *Client {
+	c.fieldDirections = directions
+	return c
+}
+
+// WithStatusMap sets how status names are translated: tickets read from Jira carry the
+// local name of their status, and TransitionTicket sends the Jira name.
+func (c *Client) WithStatusMap(statuses domain.StatusMap) *Client {
+	c.statuses = statuses
+	return c
+}
+
+// WithPriorityMaps sets how each project's priority names are translated: tickets and
+// metadata read from Jira carry the local name of their priorities, and edits and new
+// tickets send the Jira name. priorities is typically domain.SyncConfig.PrioritiesFor.
+func (c *Client) WithPriorityMaps(priorities func(projectKey string) domain.PriorityMap) *Client {
+	c.priorities = priorities
+	return c
+}
+
+// priorityMap returns the priority map of a project.
+func (c *Client) priorityMap(projectKey string) domain.PriorityMap {
+	if c.priorities == nil {
+		return nil
+	}
+	return c.priorities(projectKey)
+}
+
+// WithRawFields sets whether tickets read from Jira also carry the raw values of the
+// fields jiramd does not map (see domain.JiraFieldsField). Fetching every field makes
+// issue responses larger.
+func (c *Client) WithRawFields(enabled bool) *Client {
+	c.rawFields = enabled
+	return c
+}
+
+// WithAuthors sets whether tickets read from Jira also carry the profiles (display
+// name, email, avatar) of their assignee and reporter (see domain.AuthorsField).
+func (c *Client) WithAuthors(enabled bool) *Client {
+	c.authors = enabled
+	return c
+}
+
+// WithCommentAttribution sets how AddComment records who wrote comments posted on
+// behalf of someone (see domain.Comment.OnBehalfOf).
+func (c *Client) WithCommentAttribution(attribution domain.CommentAttribution) *Client {
+	c.attribution = attribution
+	return c
+}
+
+// impersonationKey is the context key of the personal access token requests are sent
+// with instead of the client's credentials.
+type impersonationKey struct{}
+
+// withImpersonation returns a context whose requests authenticate with the personal
+// access token of another user (Jira Server and Data Center), so Jira records them as
+// that user's.
+func withImpersonation(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, impersonationKey{}, token)
+}
+
+// authenticate sets the credentials of req: the personal access token of the user
+// impersonated through ctx, or the client's email and token.
+func (c *Client) authenticate(ctx context.Context, req *http.Request) {
+	if token, ok := ctx.Value(impersonationKey{}).(string); ok {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return
 	}
+	req.SetBasicAuth(c.email, c.token)
 }
 
-// GetTicket retrieves a ticket from Jira.
-// This is a placeholder for the actual implementation.
+// observe reports the outcome of a response to the auth observer, if any. Responses to
+// impersonated requests say nothing about the client's credentials and are not reported.
+func (c *Client) observe(ctx context.Context, err error) {
+	if _, impersonated := ctx.Value(impersonationKey{}).(string); impersonated {
+		return
+	}
+	if c.authObserver != nil {
+		c.authObserver.Observe(ctx, err)
+	}
+}
+
+// orDefaultTimeout returns timeout, or domain.DefaultHTTPTimeout if it is not set.
+func orDefaultTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return domain.DefaultHTTPTimeout
+	}
+	return timeout
+}
+
+// httpClient returns the client for a request: searches are sent with POST but only
+// read, so they get the read timeout too.
+func (c *Client) httpClient(method, path string) *http.Client {
+	if method == http.MethodGet || strings.HasPrefix(path, "/rest/api/3/search") {
+		return c.readClient
+	}
+	return c.writeClient
+}
+
+// GetTicket retrieves a ticket from Jira. Concurrent calls for the same ticket share one
+// request, and each gets its own copy of the ticket.
+// Returns ErrNotFound if the ticket doesn't exist or isn't visible to the user.
 func (c *Client) GetTicket(ctx context.Context, key string) (*domain.Ticket, error) {
-	// TODO: Implement Jira API call to get ticket
-	return nil, fmt.Errorf("jira.Client.GetTicket not implemented")
+	path := c.ticketPath(key)
+	data, err := c.ticketFetches.do(ctx, path, func(ctx context.Context) ([]byte, error) {
+		var raw json.RawMessage
+		err := c.doRequest(ctx, http.MethodGet, path, nil, &raw)
+		return raw, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var body issue
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("failed to decode jira response: %w", err)
+	}
+	return c.toTicket(ctx, &body)
+}
+
+// fetchFreshTicket retrieves a ticket from Jira with a request of its own, for reads that
+// must not see a fetch sent before them, such as checking for conflicts before a write.
+func (c *Client) fetchFreshTicket(ctx context.Context, key string) (*domain.Ticket, error) {
+	var body issue
+	if err := c.doRequest(ctx, http.MethodGet, c.ticketPath(key), nil, &body); err != nil {
+		return nil, err
+	}
+	return c.toTicket(ctx, &body)
+}
+
+// ticketPath returns the request path fetching a ticket with the fields toTicket maps.
+func (c *Client) ticketPath(key string) string {
+	return "/rest/api/3/issue/" + url.PathEscape(key) + "?fields=" + url.QueryEscape(strings.Join(c.requestedFields(), ","))
+}
+
+// toTicket maps a Jira issue to a domain ticket with the local names of its status and
+// priority, and the raw values of its unmapped fields and the profiles of its people if
+// enabled.
+func (c *Client) toTicket(ctx context.Context, i *issue) (*domain.Ticket, error) {
+	ticket, err := i.toTicket()
+	if err != nil {
+		return nil, err
+	}
+	ticket.Status = c.statuses.ToLocal(ticket.Status)
+	ticket.Priority = c.priorityMap(ticket.Key.ProjectKey()).ToLocal(ticket.Priority)
+
+	if c.rawFields {
+		raw, err := c.unmappedFields(ctx, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the fields of %s: %w", i.Key, err)
+		}
+		if len(raw) > 0 {
+			ticket.CustomFields[domain.JiraFieldsField] = domain.NewFieldValue(raw)
+		}
+	}
+	if c.authors {
+		ticket.AddAuthors(i.Fields.Assignee.toUser(), i.Fields.Reporter.toUser())
+	}
+	return ticket, nil
 }
 
-// UpdateTicket updates a ticket in Jira.
-// This is a placeholder for the actual implementation.
-func (c *Client) UpdateTicket(ctx context.Context, ticket *domain.Ticket) error {
-	// TODO: Implement Jira API call to update ticket
-	return fmt.Errorf("jira.Client.UpdateTicket not implemented")
+// projectResponse is the subset of a Jira project jiramd reads.
+type projectResponse struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 // GetProject retrieves a project from Jira.
-// This is a placeholder for the actual implementation.
+// Returns ErrNotFound if the project doesn't exist or isn't visible to the user.
 func (c *Client) GetProject(ctx context.Context, key string) (*domain.Project, error) {
-	// TODO: Implement Jira API call to get project
-	return nil, fmt.Errorf("jira.Client.GetProject not implemented")
+	var body projectResponse
+	if err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(key), nil, &body); err != nil {
+		return nil, err
+	}
+
+	project, err := domain.NewProject(body.Key, body.Name)
+	if err != nil {
+		return nil, err
+	}
+	project.Description = body.Description
+	return project, nil
+}
+
+// doRequest sends an authenticated request to the Jira REST API and decodes the JSON response.
+// body and out may be nil. Non-2xx responses are mapped to domain errors by responseError.
+func (c *Client) doRequest(ctx context.Context, method, path string, body, out interface{}) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return c.responseError(ctx, resp, body)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode jira response: %w", err)
+	}
+	return nil
+}
+
+// send sends an authenticated JSON request to the Jira REST API and returns the response
+// for the caller to close. Every attempt waits for the rate limiter first. Failed attempts
+// are retried as the client's domain.HTTPRetryPolicy says, each kind of failure with its
+// own backoff, until the policy's budget for the request runs out: rate-limited requests
+// (429) after the delay Jira asks for in Retry-After, pausing the rate limiter for every
+// other request meanwhile, 502, 503, and 504 responses, and timeouts. The response of
+// the last attempt is returned, and failures without one are returned as a
+// *domain.JiraError carrying the number of attempts.
+func (c *Client) send(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	start := time.Now()
+	retries := make(map[*domain.Backoff]int)
+	for attempt := 1; ; attempt++ {
+		var reader io.Reader
+		if data != nil {
+			reader = bytes.NewReader(data)
+		}
+
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("jira request %s %s failed: %w", method, path, err)
+		}
+
+		req, err := http.NewRequestWithContext(context.WithValue(ctx, attemptKey{}, attempt), method, c.baseURL+path, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		c.authenticate(ctx, req)
+		req.Header.Set("Accept", "application/json")
+		if id := domain.CorrelationIDFrom(ctx); id != "" {
+			req.Header.Set(CorrelationHeader, id.String())
+		}
+		if data != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.httpClient(method, path).Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("jira request %s %s failed: %w", method, path, err)
+			}
+			backoff := c.retryBackoff(method, path, 0, err)
+			delay, ok := c.retryDelay(backoff, retries, start, "")
+			if !ok {
+				endpoint, _, _ := strings.Cut(path, "?")
+				return nil, &domain.JiraError{Err: err, Method: method, Endpoint: endpoint, Attempts: attempt}
+			}
+			if err := sleep(ctx, delay); err != nil {
+				return nil, fmt.Errorf("jira request %s %s failed: %w", method, path, err)
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+			c.observe(ctx, nil)
+		}
+		backoff := c.retryBackoff(method, path, resp.StatusCode, nil)
+		delay, ok := c.retryDelay(backoff, retries, start, resp.Header.Get("Retry-After"))
+		if !ok {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusTooManyRequests {
+			c.limiter.Pause(delay)
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("jira request %s %s failed: %w", method, path, err)
+		}
+	}
+}
+
+// CorrelationHeader is the header requests carry the correlation ID of the work they are
+// made for in (see domain.CorrelationID), so they can be found in the logs of Jira and
+// of proxies in front of it.
+const CorrelationHeader = "X-Correlation-ID"
+
+// attemptKey is the context key of the number of a request's attempt, counting from 1.
+type attemptKey struct{}
+
+// retryBackoff returns the backoff of the retry policy that applies to a request that
+// failed with status, or with err when it got no response, or nil if it is not retried.
+// Only requests that are safe to send twice are retried when Jira may have handled them.
+func (c *Client) retryBackoff(method, path string, status int, err error) *domain.Backoff {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return &c.retry.RateLimited
+	case status == http.StatusServiceUnavailable:
+		return &c.retry.Unavailable
+	case !idempotent(method, path):
+		return nil
+	case status == http.StatusBadGateway, status == http.StatusGatewayTimeout:
+		return &c.retry.Unavailable
+	case err != nil && isTimeout(err):
+		return &c.retry.Timeouts
+	default:
+		return nil
+	}
+}
+
+// retryDelay returns how long to wait before retrying with backoff, given the retries
+// made so far by backoff and the time the request started, and counts the retry. The
+// wait is the Retry-After header when Jira sent one, and the backoff's jittered delay
+// otherwise. It reports false when backoff is nil, out of retries, or the wait would
+// end past the retry budget.
+func (c *Client) retryDelay(backoff *domain.Backoff, retries map[*domain.Backoff]int, start time.Time, header string) (time.Duration, bool) {
+	if backoff == nil || retries[backoff] >= backoff.MaxRetries {
+		return 0, false
+	}
+
+	delay, ok := retryAfter(header)
+	if !ok {
+		delay = backoff.Delay(retries[backoff]+1, c.random)
+	}
+	if time.Since(start)+delay > c.retry.Budget {
+		return 0, false
+	}
+	retries[backoff]++
+	return delay, true
+}
+
+// idempotent reports whether sending a request twice has the same effect as sending it
+// once: reads, searches, and PUT and DELETE requests.
+func idempotent(method, path string) bool {
+	return method != http.MethodPost || strings.HasPrefix(path, "/rest/api/3/search")
+}
+
+// isTimeout reports whether err is a request timing out without a response.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleep waits for d, returning ctx.Err() early if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds, capped at maxRetryAfter. It
+// reports false for a missing or unparseable header.
+func retryAfter(header string) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	delay := time.Duration(seconds) * time.Second
+	if delay > maxRetryAfter {
+		return maxRetryAfter, true
+	}
+	return delay, true
+}
+
+// errorResponse is the error body returned by the Jira REST API.
+type errorResponse struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+// message joins the error messages and field errors, or returns "" if there are none.
+// Field errors are sorted by field name so messages are stable.
+func (e errorResponse) message() string {
+	messages := append([]string(nil), e.ErrorMessages...)
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		messages = append(messages, field+": "+e.Errors[field])
+	}
+	return strings.Join(messages, "; ")
+}
+
+// requestIDHeader is the response header carrying Atlassian's ID for a request.
+const requestIDHeader = "X-Arequestid"
+
+// responseError converts a failed Jira response to the request with the given body into
+// a *domain.JiraError wrapping the domain error for its status code and JSON error body
+// (see statusError), and reports it to the auth observer.
+func (c *Client) responseError(ctx context.Context, resp *http.Response, body interface{}) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	err := requestError(resp, body, statusError(resp.StatusCode, parseErrorResponse(data), resp.Status))
+	c.observe(ctx, err)
+	return err
+}
+
+// requestError wraps err, the domain error for a failed response, with the details of
+// the request it answered.
+func requestError(resp *http.Response, body interface{}, err error) error {
+	jiraErr := &domain.JiraError{
+		Err:       err,
+		Status:    resp.StatusCode,
+		RequestID: resp.Header.Get(requestIDHeader),
+	}
+	if resp.Request != nil {
+		jiraErr.Method = resp.Request.Method
+		jiraErr.Endpoint = resp.Request.URL.Path
+		jiraErr.Attempts, _ = resp.Request.Context().Value(attemptKey{}).(int)
+	}
+	if search, ok := body.(searchRequest); ok {
+		jiraErr.JQL = search.JQL
+	}
+	return jiraErr
+}
+
+// parseErrorResponse decodes a Jira error body; bodies that are not Jira errors (e.g.
+// a proxy's HTML error page) yield an empty errorResponse.
+func parseErrorResponse(data []byte) errorResponse {
+	var body errorResponse
+	if json.Unmarshal(data, &body) != nil {
+		return errorResponse{}
+	}
+	return body
+}
+
+// statusError maps a Jira HTTP status code and error body to a domain error.
+// 404 maps to ErrNotFound, 401/403 to ErrUnauthorized, 400 to ErrInvalidInput (and also
+// ErrInvalidFieldValue when Jira rejected specific fields), 409 to ErrConflict, 429 to
+// ErrRateLimited, and 5xx to ErrUnavailable. The error text is Jira's messages, or
+// fallback when the body has none.
+func statusError(status int, body errorResponse, fallback string) error {
+	message := body.message()
+	if message == "" {
+		message = fallback
+	}
+
+	switch {
+	case status == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, message)
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return fmt.Errorf("%w: %s", domain.ErrUnauthorized, message)
+	case status == http.StatusBadRequest && len(body.Errors) > 0:
+		return fmt.Errorf("%w: %w: %s", domain.ErrInvalidInput, domain.ErrInvalidFieldValue, message)
+	case status == http.StatusBadRequest, status == http.StatusRequestEntityTooLarge:
+		return fmt.Errorf("%w: %s", domain.ErrInvalidInput, message)
+	case status == http.StatusConflict:
+		return fmt.Errorf("%w: %s", domain.ErrConflict, message)
+	case status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", domain.ErrRateLimited, message)
+	case status >= 500:
+		return fmt.Errorf("%w: %s", domain.ErrUnavailable, message)
+	default:
+		return fmt.Errorf("jira returned %d: %s", status, message)
+	}
 }
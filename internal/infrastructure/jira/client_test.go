@@ -0,0 +1,453 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// issueJSON returns a minimal Jira issue payload for tests.
+func issueJSON(key, summary string) map[string]interface{} {
+	return map[string]interface{}{
+		"key": key,
+		"fields": map[string]interface{}{
+			"summary": summary,
+			"description": map[string]interface{}{
+				"type": "doc",
+				"content": []interface{}{
+					map[string]interface{}{
+						"type":    "paragraph",
+						"content": []interface{}{map[string]interface{}{"type": "text", "text": "First line"}},
+					},
+					map[string]interface{}{
+						"type":    "paragraph",
+						"content": []interface{}{map[string]interface{}{"type": "text", "text": "Second line"}},
+					},
+				},
+			},
+			"status":    map[string]interface{}{"name": "In Progress"},
+			"issuetype": map[string]interface{}{"name": "Bug"},
+			"priority":  map[string]interface{}{"name": "High"},
+			"assignee":  map[string]interface{}{"displayName": "Alice", "emailAddress": "alice@example.com"},
+			"reporter":  map[string]interface{}{"displayName": "Bob"},
+			"labels":    []string{"backend"},
+			"created":   "2026-10-01T09:00:00.000+0200",
+			"updated":   "2026-10-02T10:30:00.000+0000",
+			"fixVersions": []interface{}{
+				map[string]interface{}{"id": "10001", "name": "1.4.0"},
+			},
+			"issuelinks": []interface{}{
+				map[string]interface{}{"type": map[string]interface{}{"name": "Blocks"}, "outwardIssue": map[string]interface{}{"key": "JMD-20"}},
+				map[string]interface{}{"type": map[string]interface{}{"name": "Relates"}, "inwardIssue": map[string]interface{}{"key": "OPS-3"}},
+			},
+			"customfield_10020": []interface{}{
+				map[string]interface{}{"id": 11, "name": "JMD Sprint 11", "state": "closed", "boardId": 42},
+				map[string]interface{}{"id": 12, "name": "JMD Sprint 12", "state": "active", "boardId": 42},
+			},
+		},
+	}
+}
+
+func TestClient_SearchTickets(t *testing.T) {
+	var requests []searchRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/3/search/jql" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "secret" {
+			t.Errorf("missing or wrong basic auth: %q %q", user, pass)
+		}
+
+		var req searchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		requests = append(requests, req)
+
+		// Two pages: JMD-1..JMD-2, then JMD-3
+		resp := map[string]interface{}{}
+		if req.NextPageToken == "" {
+			resp["issues"] = []interface{}{issueJSON("JMD-1", "One"), issueJSON("JMD-2", "Two")}
+			resp["nextPageToken"] = "page2"
+		} else {
+			resp["issues"] = []interface{}{issueJSON("JMD-3", "Three")}
+			resp["isLast"] = true
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "me@example.com", "secret")
+
+	tickets, err := client.SearchTickets(context.Background(), "project = JMD", 0)
+	if err != nil {
+		t.Fatalf("SearchTickets failed: %v", err)
+	}
+
+	if len(tickets) != 3 || len(requests) != 2 {
+		t.Fatalf("got %d tickets in %d requests, want 3 in 2", len(tickets), len(requests))
+	}
+	if requests[0].JQL != "project = JMD" || requests[1].NextPageToken != "page2" {
+		t.Errorf("unexpected requests: %+v", requests)
+	}
+
+	got := tickets[0]
+	if got.Key.String() != "JMD-1" || got.Summary != "One" {
+		t.Errorf("unexpected ticket %s %q", got.Key, got.Summary)
+	}
+	if got.Description != "First line\nSecond line" {
+		t.Errorf("Description = %q", got.Description)
+	}
+	if got.Status != "In Progress" || got.IssueType != "Bug" || got.Priority != "High" {
+		t.Errorf("unexpected status/type/priority: %q %q %q", got.Status, got.IssueType, got.Priority)
+	}
+	if got.Assignee != "alice@example.com" || got.Reporter != "Bob" {
+		t.Errorf("unexpected assignee/reporter: %q %q", got.Assignee, got.Reporter)
+	}
+	if got.Created.Hour() != 7 || got.Updated.Minute() != 30 {
+		t.Errorf("timestamps not normalized to UTC: %v %v", got.Created, got.Updated)
+	}
+	if !got.HasFixVersion("1.4.0") {
+		t.Errorf("FixVersions() = %v, want [1.4.0]", got.FixVersions())
+	}
+	if linked := got.LinkedKeys(); len(linked) != 2 || linked[0].String() != "JMD-20" || linked[1].String() != "OPS-3" {
+		t.Errorf("LinkedKeys() = %v, want [JMD-20 OPS-3]", linked)
+	}
+	if sprints := got.Sprints(); len(sprints) != 2 || sprints[1] != "JMD Sprint 12" {
+		t.Errorf("Sprints() = %v, want [JMD Sprint 11 JMD Sprint 12]", sprints)
+	}
+}
+
+func TestClient_SearchTickets_Limit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req searchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.MaxResults != 2 {
+			t.Errorf("MaxResults = %d, want 2", req.MaxResults)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issues":        []interface{}{issueJSON("JMD-1", "One"), issueJSON("JMD-2", "Two")},
+			"nextPageToken": "more",
+		})
+	}))
+	defer server.Close()
+
+	tickets, err := NewClient(server.URL, "me@example.com", "secret").SearchTickets(context.Background(), "project = JMD", 2)
+	if err != nil {
+		t.Fatalf("SearchTickets failed: %v", err)
+	}
+	if len(tickets) != 2 {
+		t.Errorf("got %d tickets, want 2", len(tickets))
+	}
+}
+
+func TestClient_ForEachTicket(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req searchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests++
+
+		resp := map[string]interface{}{}
+		if req.NextPageToken == "" {
+			resp["issues"] = []interface{}{issueJSON("JMD-1", "One"), issueJSON("JMD-2", "Two")}
+			resp["nextPageToken"] = "page2"
+		} else {
+			resp["issues"] = []interface{}{issueJSON("JMD-3", "Three")}
+			resp["isLast"] = true
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+
+	var keys []string
+	err := client.ForEachTicket(ctx, "project = JMD", func(ticket *domain.Ticket) error {
+		keys = append(keys, ticket.Key.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachTicket failed: %v", err)
+	}
+	if fmt.Sprint(keys) != "[JMD-1 JMD-2 JMD-3]" || requests != 2 {
+		t.Errorf("visited %v in %d requests, want all three tickets in 2", keys, requests)
+	}
+
+	// An error from fn stops the iteration before the next page is fetched
+	requests = 0
+	stop := errors.New("stop")
+	visited := 0
+	err = client.ForEachTicket(ctx, "project = JMD", func(ticket *domain.Ticket) error {
+		visited++
+		return stop
+	})
+	if !errors.Is(err, stop) || visited != 1 || requests != 1 {
+		t.Errorf("ForEachTicket() = %v after %d tickets and %d requests, want stop after 1 and 1", err, visited, requests)
+	}
+}
+
+func TestClient_MapsHTTPErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, domain.ErrInvalidInput},
+		{http.StatusUnauthorized, domain.ErrUnauthorized},
+		{http.StatusForbidden, domain.ErrUnauthorized},
+		{http.StatusNotFound, domain.ErrNotFound},
+		{http.StatusConflict, domain.ErrConflict},
+		{http.StatusServiceUnavailable, domain.ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-AREQUESTID", "a1b2c3")
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"errorMessages": []string{"Error in the JQL Query"},
+				})
+			}))
+			defer server.Close()
+
+			// Without retries, so the 503 is mapped as first answered
+			client := NewClient(server.URL, "me@example.com", "secret").WithRetryPolicy(domain.HTTPRetryPolicy{Budget: time.Minute})
+			_, err := client.SearchTickets(context.Background(), "bad jql", 0)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			want := fmt.Sprintf("%v: Error in the JQL Query (POST /rest/api/3/search/jql: %d; jql: bad jql; request id: a1b2c3)", tt.want, tt.status)
+			if got := err.Error(); got != want {
+				t.Errorf("error message = %q, want %q", got, want)
+			}
+
+			var jiraErr *domain.JiraError
+			if !errors.As(err, &jiraErr) || jiraErr.Status != tt.status || jiraErr.RequestID != "a1b2c3" {
+				t.Errorf("error = %#v, want a *domain.JiraError with the status and request ID", err)
+			}
+		})
+	}
+}
+
+func TestClient_RetriesByStatus(t *testing.T) {
+	// Retries wait at most a millisecond, with no jitter
+	backoff := domain.Backoff{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	policy := domain.HTTPRetryPolicy{RateLimited: backoff, Unavailable: backoff, Timeouts: backoff, Budget: time.Minute}
+
+	tests := []struct {
+		name         string
+		method       string
+		status       int
+		wantAttempts int
+	}{
+		{name: "unavailable read", method: http.MethodGet, status: http.StatusBadGateway, wantAttempts: 3},
+		{name: "unavailable write", method: http.MethodPost, status: http.StatusServiceUnavailable, wantAttempts: 3},
+		{name: "bad gateway write", method: http.MethodPost, status: http.StatusBadGateway, wantAttempts: 1},
+		{name: "gateway timeout edit", method: http.MethodPut, status: http.StatusGatewayTimeout, wantAttempts: 3},
+		{name: "server error", method: http.MethodGet, status: http.StatusInternalServerError, wantAttempts: 1},
+		{name: "not found", method: http.MethodGet, status: http.StatusNotFound, wantAttempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "me@example.com", "secret").WithRetryPolicy(policy)
+			client.random = func() float64 { return 1 }
+			err := client.doRequest(context.Background(), tt.method, "/rest/api/3/issue/JMD-1", nil, nil)
+
+			var jiraErr *domain.JiraError
+			if !errors.As(err, &jiraErr) || jiraErr.Status != tt.status {
+				t.Fatalf("error = %v, want a *domain.JiraError with status %d", err, tt.status)
+			}
+			if attempts != tt.wantAttempts || jiraErr.Attempts != tt.wantAttempts {
+				t.Errorf("sent %d attempts, error reports %d; want %d", attempts, jiraErr.Attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestClient_RetriesTimeouts(t *testing.T) {
+	// The handler of the timed out attempt still runs while the retry is served
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		json.NewEncoder(w).Encode(issueJSON("JMD-1", "Slow"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret").WithRetryPolicy(domain.HTTPRetryPolicy{
+		Timeouts: domain.Backoff{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		Budget:   time.Minute,
+	})
+	client.readClient.Timeout = 50 * time.Millisecond
+
+	ticket, err := client.GetTicket(context.Background(), "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v, want the timed out request retried", err)
+	}
+	if ticket.Summary != "Slow" || attempts.Load() != 2 {
+		t.Errorf("GetTicket() = %q after %d attempts, want Slow after 2", ticket.Summary, attempts.Load())
+	}
+
+	// Out of retries, the timeout is returned with the attempts made
+	attempts.Store(0)
+	client.retry.Timeouts.MaxRetries = 0
+	_, err = client.GetTicket(context.Background(), "JMD-2")
+	var jiraErr *domain.JiraError
+	if !errors.As(err, &jiraErr) || jiraErr.Status != 0 || jiraErr.Attempts != 1 || !isTimeout(err) {
+		t.Errorf("error = %v, want a timeout reported as a *domain.JiraError without status", err)
+	}
+}
+
+func TestClient_RetryBudget(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	// A retry waiting 30 seconds would end past the budget, so none is made
+	client := NewClient(server.URL, "me@example.com", "secret").WithRetryPolicy(domain.HTTPRetryPolicy{
+		RateLimited: domain.Backoff{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: time.Minute},
+		Budget:      10 * time.Second,
+	})
+	start := time.Now()
+	err := client.doRequest(context.Background(), http.MethodGet, "/rest/api/3/issue/JMD-1", nil, nil)
+	if !errors.Is(err, domain.ErrRateLimited) || attempts != 1 {
+		t.Errorf("error = %v after %d attempts, want ErrRateLimited after 1", err, attempts)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request returned after %s, want no wait past the budget", elapsed)
+	}
+}
+
+func TestClient_CorrelationHeader(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(CorrelationHeader))
+		json.NewEncoder(w).Encode(issueJSON("JMD-1", "Login"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := domain.WithCorrelationID(context.Background(), "0123456789abcdef.89abcdef")
+	if _, err := client.GetTicket(ctx, "JMD-1"); err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if _, err := client.GetTicket(context.Background(), "JMD-1"); err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+
+	want := []string{"0123456789abcdef.89abcdef", ""}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s headers = %q, want %q", CorrelationHeader, got, want)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		want        []error
+		wantMessage string
+	}{
+		{
+			name:        "numbers in the message do not change the mapping",
+			status:      http.StatusBadRequest,
+			body:        `{"errorMessages":["Issue 404 does not exist or you do not have permission"]}`,
+			want:        []error{domain.ErrInvalidInput},
+			wantMessage: "invalid input: Issue 404 does not exist or you do not have permission",
+		},
+		{
+			name:        "field errors",
+			status:      http.StatusBadRequest,
+			body:        `{"errors":{"priority":"Priority name 'Urgent' is not valid","duedate":"Invalid date"}}`,
+			want:        []error{domain.ErrInvalidInput, domain.ErrInvalidFieldValue},
+			wantMessage: "invalid input: invalid field value: duedate: Invalid date; priority: Priority name 'Urgent' is not valid",
+		},
+		{
+			name:        "body that is not a jira error",
+			status:      http.StatusBadGateway,
+			body:        `<html>Bad Gateway</html>`,
+			want:        []error{domain.ErrUnavailable},
+			wantMessage: "service unavailable: 502 Bad Gateway",
+		},
+		{
+			name:        "unmapped status",
+			status:      http.StatusMethodNotAllowed,
+			body:        `{"errorMessages":["Method not allowed"]}`,
+			wantMessage: "jira returned 405: Method not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := statusError(tt.status, parseErrorResponse([]byte(tt.body)), fmt.Sprintf("%d %s", tt.status, http.StatusText(tt.status)))
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("error = %v, want it to wrap %v", err, want)
+				}
+			}
+			if got := err.Error(); got != tt.wantMessage {
+				t.Errorf("error message = %q, want %q", got, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestClient_WithAuthors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issue := issueJSON("JMD-1", "One")
+		fields := issue["fields"].(map[string]interface{})
+		fields["assignee"].(map[string]interface{})["avatarUrls"] = map[string]string{
+			"16x16": "https://avatars.example.com/alice-16.png",
+			"48x48": "https://avatars.example.com/alice-48.png",
+		}
+		json.NewEncoder(w).Encode(issue)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ticket, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if authors := ticket.Authors(); authors != nil {
+		t.Errorf("Authors() = %v, want none unless enabled", authors)
+	}
+
+	ticket, err = client.WithAuthors(true).GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	alice, ok := ticket.Author(ticket.Assignee)
+	if !ok || alice.DisplayName != "Alice" || alice.AvatarURL != "https://avatars.example.com/alice-48.png" {
+		t.Errorf("Author(%s) = %+v, %v", ticket.Assignee, alice, ok)
+	}
+	if bob, ok := ticket.Author(ticket.Reporter); !ok || bob.DisplayName != "Bob" || bob.AvatarURL != "" {
+		t.Errorf("Author(%s) = %+v, %v", ticket.Reporter, bob, ok)
+	}
+}
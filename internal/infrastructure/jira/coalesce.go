@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup coalesces concurrent identical requests, so the watcher, the poller, and
+// commands asking for the same ticket at once share one HTTP request. Its zero value is
+// ready to use.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a request in progress, whose result is set before done is closed.
+type flight struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// do returns the result of fn for key, calling it only if no call for key is in
+// progress and otherwise waiting for that call's result. Callers get the raw response,
+// so each decodes its own copy and none sees another's changes.
+//
+// fn runs with the values of the first caller's context but not its cancellation, so one
+// caller giving up doesn't fail the others; each caller stops waiting when its own
+// context is done.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if !ok {
+		if g.flights == nil {
+			g.flights = make(map[string]*flight)
+		}
+		f = &flight{done: make(chan struct{})}
+		g.flights[key] = f
+		go g.run(context.WithoutCancel(ctx), key, f, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.data, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run calls fn for the flight, then forgets it so later calls send a new request.
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) ([]byte, error)) {
+	f.data, f.err = fn(ctx)
+
+	g.mu.Lock()
+	delete(g.flights, key)
+	g.mu.Unlock()
+	close(f.done)
+}
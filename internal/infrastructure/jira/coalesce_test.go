@@ -0,0 +1,73 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_GetTicket_Coalesced(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(`{"key":"JMD-1","fields":{"summary":"One","labels":["backend"]}}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "me@example.com", "secret")
+
+	// A caller giving up doesn't fail the others
+	ctx, cancel := context.WithCancel(context.Background())
+	gaveUp := make(chan error)
+	go func() {
+		_, err := client.GetTicket(ctx, "JMD-1")
+		gaveUp <- err
+	}()
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	var wg sync.WaitGroup
+	labels := make([][]string, 4)
+	for i := range labels {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ticket, err := client.GetTicket(context.Background(), "JMD-1")
+			if err != nil {
+				t.Errorf("GetTicket() error = %v", err)
+				return
+			}
+			labels[i] = ticket.Labels
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-gaveUp; !errors.Is(err, context.Canceled) {
+		t.Errorf("GetTicket() of the caller that gave up error = %v, want context.Canceled", err)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server got %d requests, want 1 shared by every caller", n)
+	}
+	labels[0][0] = "changed"
+	if labels[1][0] != "backend" {
+		t.Errorf("callers share the ticket's labels, want a copy each")
+	}
+
+	// Once done, the next fetch sends a new request
+	if _, err := client.GetTicket(context.Background(), "JMD-1"); err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests, want a new one after the first finished", n)
+	}
+}
@@ -0,0 +1,207 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// commentPageSize is the number of comments requested per page (Jira's maximum is 5000,
+// but large pages of rich bodies are slow).
+const commentPageSize = 100
+
+// internalCommentProperty is the comment property Jira Service Management uses to mark
+// internal notes, which portal customers never see.
+const internalCommentProperty = "sd.public.comment"
+
+// commentVisibility restricts a comment to a project role or group.
+type commentVisibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// jiraComment is the subset of a Jira REST v3 comment that jiramd maps to a domain.Comment.
+type jiraComment struct {
+	ID         string             `json:"id"`
+	Author     *user              `json:"author"`
+	Body       json.RawMessage    `json:"body"`
+	Created    string             `json:"created"`
+	Updated    string             `json:"updated"`
+	Visibility *commentVisibility `json:"visibility"`
+
+	// JSDPublic is false for Service Management internal notes, and unset outside
+	// Service Management projects
+	JSDPublic *bool `json:"jsdPublic"`
+}
+
+// commentPage is a page of GET /rest/api/3/issue/{key}/comment.
+type commentPage struct {
+	StartAt  int           `json:"startAt"`
+	Total    int           `json:"total"`
+	Comments []jiraComment `json:"comments"`
+}
+
+// commentProperty is an entity property set on a new comment.
+type commentProperty struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// addCommentRequest is the body of POST /rest/api/3/issue/{key}/comment.
+type addCommentRequest struct {
+	Body       *adfNode           `json:"body"`
+	Visibility *commentVisibility `json:"visibility,omitempty"`
+	Properties []commentProperty  `json:"properties,omitempty"`
+}
+
+// toComment maps a Jira comment on the ticket key to a domain comment.
+func (c *jiraComment) toComment(key domain.TicketKey) (*domain.Comment, error) {
+	created, err := parseJiraTime(c.Created)
+	if err != nil {
+		return nil, fmt.Errorf("invalid created time on comment %s: %w", c.ID, err)
+	}
+	updated, err := parseJiraTime(c.Updated)
+	if err != nil {
+		return nil, fmt.Errorf("invalid updated time on comment %s: %w", c.ID, err)
+	}
+
+	result := &domain.Comment{
+		ID:            c.ID,
+		TicketKey:     key,
+		Author:        c.Author.name(),
+		AuthorProfile: c.Author.toUser(),
+		Body:          adfText(c.Body),
+		Created:       created.UTC(),
+		Updated:       updated.UTC(),
+	}
+	if c.Visibility != nil {
+		result.Visibility.Type = domain.VisibilityType(c.Visibility.Type)
+		result.Visibility.Value = c.Visibility.Value
+	}
+	result.Visibility.Internal = c.JSDPublic != nil && !*c.JSDPublic
+	return result, nil
+}
+
+// FetchComments returns every comment of a ticket, oldest first, with who can see it.
+// Tickets with thousands of comments should use ForEachComment instead.
+// Returns ErrNotFound if the ticket doesn't exist.
+func (c *Client) FetchComments(ctx context.Context, ticketKey string) ([]*domain.Comment, error) {
+	comments := make([]*domain.Comment, 0)
+	err := c.ForEachComment(ctx, ticketKey, func(comment *domain.Comment) error {
+		comments = append(comments, comment)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// ForEachComment calls fn with each comment of a ticket, oldest first, fetching one page
+// of comments at a time, so memory use does not grow with the number of comments and
+// callers can write each comment out as soon as it arrives.
+// If fn returns an error, iteration stops and ForEachComment returns that error.
+// Returns ErrNotFound if the ticket doesn't exist.
+func (c *Client) ForEachComment(ctx context.Context, ticketKey string, fn func(comment *domain.Comment) error) error {
+	key, err := domain.NewTicketKey(ticketKey)
+	if err != nil {
+		return err
+	}
+
+	for startAt := 0; ; {
+		query := url.Values{
+			"startAt":    {fmt.Sprint(startAt)},
+			"maxResults": {fmt.Sprint(commentPageSize)},
+			"orderBy":    {"created"},
+		}
+		path := "/rest/api/3/issue/" + url.PathEscape(key.String()) + "/comment?" + query.Encode()
+
+		var page commentPage
+		if err := c.doRequest(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return err
+		}
+		for i := range page.Comments {
+			comment, err := page.Comments[i].toComment(key)
+			if err != nil {
+				return err
+			}
+			if err := fn(comment); err != nil {
+				return err
+			}
+		}
+
+		startAt += len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			return nil
+		}
+	}
+}
+
+// CountComments returns how many comments a ticket has, with a single request for at most
+// one comment, so pulls can tell whether comments were deleted before fetching them all.
+// Returns ErrNotFound if the ticket doesn't exist.
+func (c *Client) CountComments(ctx context.Context, ticketKey string) (int, error) {
+	key, err := domain.NewTicketKey(ticketKey)
+	if err != nil {
+		return 0, err
+	}
+
+	query := url.Values{"maxResults": {"1"}}
+	path := "/rest/api/3/issue/" + url.PathEscape(key.String()) + "/comment?" + query.Encode()
+
+	var page commentPage
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &page); err != nil {
+		return 0, err
+	}
+	return page.Total, nil
+}
+
+// AddComment posts a comment on a ticket, restricted to the role or group of its
+// Visibility and as a Service Management internal note if it is one, and returns the
+// created comment with its Jira ID. A comment written on behalf of someone is posted
+// with their impersonation token if one is configured, and otherwise gets the configured
+// attribution footer (see WithCommentAttribution).
+// Returns ErrNotFound if the ticket doesn't exist and ErrInvalidInput if the comment
+// has no body or Jira rejects its visibility (e.g. an unknown role).
+func (c *Client) AddComment(ctx context.Context, ticketKey string, comment *domain.Comment) (*domain.Comment, error) {
+	key, err := domain.NewTicketKey(ticketKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := comment.Visibility.Validate(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(comment.Body) == "" {
+		return nil, fmt.Errorf("%w: comment body is required", domain.ErrInvalidInput)
+	}
+	body := comment.Body
+	if token := c.attribution.ImpersonationToken(comment.OnBehalfOf); token != "" {
+		ctx = withImpersonation(ctx, token)
+	} else if footer := c.attribution.FooterFor(comment.OnBehalfOf); footer != "" {
+		body += "\n\n" + footer
+	}
+	req := addCommentRequest{Body: textToADF(body)}
+	if v := comment.Visibility; v.Type != "" {
+		req.Visibility = &commentVisibility{Type: string(v.Type), Value: v.Value}
+	}
+	if comment.Visibility.Internal {
+		req.Properties = []commentProperty{{Key: internalCommentProperty, Value: map[string]bool{"internal": true}}}
+	}
+
+	var created jiraComment
+	path := "/rest/api/3/issue/" + url.PathEscape(key.String()) + "/comment"
+	if err := c.doRequest(ctx, http.MethodPost, path, req, &created); err != nil {
+		return nil, err
+	}
+	if created.JSDPublic == nil && comment.Visibility.Internal {
+		// The created comment is returned without jsdPublic
+		internal := false
+		created.JSDPublic = &internal
+	}
+	return created.toComment(key)
+}
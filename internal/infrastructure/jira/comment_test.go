@@ -0,0 +1,76 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// observerFunc adapts a function to an AuthObserver.
+type observerFunc func(ctx context.Context, err error)
+
+func (f observerFunc) Observe(ctx context.Context, err error) { f(ctx, err) }
+
+func TestClient_AddComment_Attribution(t *testing.T) {
+	var gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		var req struct {
+			Body json.RawMessage `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		gotBody = adfText(req.Body)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":      "10001",
+			"body":    req.Body,
+			"created": "2026-10-01T09:00:00.000+0000",
+			"updated": "2026-10-01T09:00:00.000+0000",
+		})
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	observed := 0
+	client := NewClient(server.URL, "team@example.com", "secret").
+		WithAuthObserver(observerFunc(func(context.Context, error) { observed++ })).
+		WithCommentAttribution(domain.CommentAttribution{
+			Footer:              "— posted via jiramd on behalf of {author}",
+			ImpersonationTokens: map[string]string{"alice": "alice-pat"},
+		})
+
+	tests := []struct {
+		name         string
+		onBehalfOf   string
+		wantBody     string
+		wantBearer   bool
+		wantObserved bool
+	}{
+		{name: "unattributed", wantBody: "Looks good", wantObserved: true},
+		{name: "footer", onBehalfOf: "bob", wantBody: "Looks good\n— posted via jiramd on behalf of bob", wantObserved: true},
+		{name: "impersonated", onBehalfOf: "alice", wantBody: "Looks good", wantBearer: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observed = 0
+			comment := &domain.Comment{Body: "Looks good", OnBehalfOf: tt.onBehalfOf}
+			if _, err := client.AddComment(ctx, "JMD-1", comment); err != nil {
+				t.Fatalf("AddComment() error = %v", err)
+			}
+			if gotBody != tt.wantBody {
+				t.Errorf("posted body = %q, want %q", gotBody, tt.wantBody)
+			}
+			if bearer := gotAuth == "Bearer alice-pat"; bearer != tt.wantBearer {
+				t.Errorf("Authorization = %q, want bearer token %v", gotAuth, tt.wantBearer)
+			}
+			if (observed > 0) != tt.wantObserved {
+				t.Errorf("auth observer told %d times, want observed %v", observed, tt.wantObserved)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// BulkCreateLimit is the most issues Jira creates in one bulk request.
+const BulkCreateLimit = 50
+
+// projectRef references a project by key in request bodies.
+type projectRef struct {
+	Key string `json:"key"`
+}
+
+// createFields are the fields of a new issue.
+type createFields struct {
+	Project     projectRef  `json:"project"`
+	Summary     string      `json:"summary"`
+	IssueType   namedField  `json:"issuetype"`
+	Description *adfNode    `json:"description,omitempty"`
+	Priority    *namedField `json:"priority,omitempty"`
+	Labels      []string    `json:"labels,omitempty"`
+}
+
+// issueUpdate is one issue of a bulk create request.
+type issueUpdate struct {
+	Fields createFields `json:"fields"`
+}
+
+// bulkCreateRequest is the body of POST /rest/api/3/issue/bulk.
+type bulkCreateRequest struct {
+	IssueUpdates []issueUpdate `json:"issueUpdates"`
+}
+
+// bulkCreateResponse lists the created issues, in request order, and the failed ones by index.
+type bulkCreateResponse struct {
+	Issues []struct {
+		Key string `json:"key"`
+	} `json:"issues"`
+	Errors []struct {
+		Status              int           `json:"status"`
+		ElementErrors       errorResponse `json:"elementErrors"`
+		FailedElementNumber int           `json:"failedElementNumber"`
+	} `json:"errors"`
+}
+
+// newCreateFields converts a draft into the fields of a create request, with its
+// priority under its Jira name in priorities.
+func newCreateFields(draft *domain.TicketDraft, priorities domain.PriorityMap) createFields {
+	fields := createFields{
+		Project:     projectRef{Key: draft.ProjectKey},
+		Summary:     draft.Summary,
+		IssueType:   namedField{Name: draft.IssueType},
+		Description: textToADF(draft.Description),
+		Labels:      draft.Labels,
+	}
+	if draft.Priority != "" {
+		fields.Priority = &namedField{Name: priorities.ToJira(draft.Priority)}
+	}
+	return fields
+}
+
+// CreateTickets creates up to BulkCreateLimit tickets in one request.
+// keys[i] is the key Jira assigned to drafts[i]; when that draft failed, keys[i] is zero
+// and failures[i] holds its error. err is set only when the request as a whole failed,
+// in which case no tickets were created.
+func (c *Client) CreateTickets(ctx context.Context, drafts []*domain.TicketDraft) (keys []domain.TicketKey, failures []error, err error) {
+	if len(drafts) == 0 {
+		return nil, nil, nil
+	}
+	if len(drafts) > BulkCreateLimit {
+		return nil, nil, fmt.Errorf("%w: cannot create %d tickets in one request (at most %d)", domain.ErrInvalidInput, len(drafts), BulkCreateLimit)
+	}
+
+	req := bulkCreateRequest{IssueUpdates: make([]issueUpdate, 0, len(drafts))}
+	for _, draft := range drafts {
+		req.IssueUpdates = append(req.IssueUpdates, issueUpdate{Fields: newCreateFields(draft, c.priorityMap(draft.ProjectKey))})
+	}
+
+	resp, err := c.send(ctx, http.MethodPost, "/rest/api/3/issue/bulk", req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	// Jira answers 400 with the same body when every issue failed
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+		return nil, nil, c.responseError(ctx, resp, req)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read jira response: %w", err)
+	}
+	var body bulkCreateResponse
+	if err := json.Unmarshal(data, &body); err != nil || (resp.StatusCode == http.StatusBadRequest && len(body.Errors) == 0) {
+		if resp.StatusCode == http.StatusBadRequest {
+			// A request-level error rather than a per-issue failure report
+			return nil, nil, requestError(resp, req, statusError(resp.StatusCode, parseErrorResponse(data), resp.Status))
+		}
+		return nil, nil, fmt.Errorf("failed to decode jira response: %w", err)
+	}
+
+	keys = make([]domain.TicketKey, len(drafts))
+	failures = make([]error, len(drafts))
+	for _, e := range body.Errors {
+		if e.FailedElementNumber < 0 || e.FailedElementNumber >= len(drafts) {
+			continue
+		}
+		failures[e.FailedElementNumber] = statusError(e.Status, e.ElementErrors, http.StatusText(e.Status))
+	}
+
+	created := body.Issues
+	for i := range drafts {
+		if failures[i] != nil {
+			continue
+		}
+		if len(created) == 0 {
+			failures[i] = fmt.Errorf("jira did not report a result for %q", drafts[i].Summary)
+			continue
+		}
+		key, err := domain.NewTicketKey(created[0].Key)
+		created = created[1:]
+		if err != nil {
+			failures[i] = fmt.Errorf("jira returned an invalid key for %q: %w", drafts[i].Summary, err)
+			continue
+		}
+		keys[i] = key
+	}
+
+	return keys, failures, nil
+}
@@ -0,0 +1,270 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// testDrafts returns a valid draft in the JMD project for each summary.
+func testDrafts(summaries ...string) []*domain.TicketDraft {
+	drafts := make([]*domain.TicketDraft, 0, len(summaries))
+	for _, summary := range summaries {
+		drafts = append(drafts, &domain.TicketDraft{ProjectKey: "JMD", Summary: summary, IssueType: "Task"})
+	}
+	return drafts
+}
+
+func TestClient_CreateTickets(t *testing.T) {
+	var got bulkCreateRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/3/issue/bulk" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		// The second issue fails; the others are created in order
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issues": []interface{}{
+				map[string]interface{}{"key": "JMD-10"},
+				map[string]interface{}{"key": "JMD-11"},
+			},
+			"errors": []interface{}{
+				map[string]interface{}{
+					"status":              400,
+					"failedElementNumber": 1,
+					"elementErrors": map[string]interface{}{
+						"errors": map[string]string{"priority": "Priority name 'Urgent' is not valid"},
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	drafts := testDrafts("First", "Second", "Third")
+	drafts[0].Description = "Line one\nLine two\n\nNext paragraph"
+	drafts[0].Labels = []string{"imported"}
+	drafts[1].Priority = "Urgent"
+
+	keys, failures, err := NewClient(server.URL, "me@example.com", "secret").CreateTickets(context.Background(), drafts)
+	if err != nil {
+		t.Fatalf("CreateTickets failed: %v", err)
+	}
+
+	if keys[0].String() != "JMD-10" || !keys[1].IsZero() || keys[2].String() != "JMD-11" {
+		t.Errorf("keys = %v", keys)
+	}
+	if failures[0] != nil || failures[2] != nil {
+		t.Errorf("unexpected failures: %v", failures)
+	}
+	if !errors.Is(failures[1], domain.ErrInvalidInput) {
+		t.Errorf("failures[1] = %v, want ErrInvalidInput", failures[1])
+	}
+
+	if len(got.IssueUpdates) != 3 {
+		t.Fatalf("sent %d issues, want 3", len(got.IssueUpdates))
+	}
+	first := got.IssueUpdates[0].Fields
+	if first.Project.Key != "JMD" || first.Summary != "First" || first.IssueType.Name != "Task" {
+		t.Errorf("first issue fields = %+v", first)
+	}
+	if len(first.Labels) != 1 || first.Labels[0] != "imported" {
+		t.Errorf("labels = %v", first.Labels)
+	}
+	if first.Description == nil || len(first.Description.Content) != 2 {
+		t.Fatalf("description = %+v, want two paragraphs", first.Description)
+	}
+	if got := adfText(mustJSON(t, first.Description)); got != "Line one\nLine two\nNext paragraph" {
+		t.Errorf("description text = %q", got)
+	}
+	if first.Priority != nil || got.IssueUpdates[1].Fields.Priority.Name != "Urgent" {
+		t.Errorf("priority should only be sent when set")
+	}
+}
+
+func TestNewCreateFields_PriorityMap(t *testing.T) {
+	drafts := testDrafts("Mapped", "Unmapped")
+	drafts[0].Priority = "P0"
+	drafts[1].Priority = "Medium"
+	priorities := domain.PriorityMap{"P0": "Blocker"}
+
+	if got := newCreateFields(drafts[0], priorities).Priority.Name; got != "Blocker" {
+		t.Errorf("mapped priority = %q, want Blocker", got)
+	}
+	if got := newCreateFields(drafts[1], priorities).Priority.Name; got != "Medium" {
+		t.Errorf("unmapped priority = %q, want Medium", got)
+	}
+}
+
+func TestClient_CreateTickets_RequestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errorMessages": []string{"issueUpdates is required"},
+		})
+	}))
+	defer server.Close()
+
+	_, _, err := NewClient(server.URL, "me@example.com", "secret").CreateTickets(context.Background(), testDrafts("One"))
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestClient_CreateTickets_TooMany(t *testing.T) {
+	summaries := make([]string, BulkCreateLimit+1)
+	for i := range summaries {
+		summaries[i] = "Ticket"
+	}
+
+	_, _, err := NewClient("http://jira.invalid", "me@example.com", "secret").CreateTickets(context.Background(), testDrafts(summaries...))
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestClient_RetriesRateLimitedRequests(t *testing.T) {
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"issues": []interface{}{}, "isLast": true})
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL, "me@example.com", "secret").SearchTickets(context.Background(), "project = JMD", 0); err != nil {
+		t.Fatalf("SearchTickets failed: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestClient_RateLimitRetriesAreBounded(t *testing.T) {
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "me@example.com", "secret").SearchTickets(context.Background(), "project = JMD", 0)
+	if err == nil {
+		t.Fatal("expected an error once retries are exhausted")
+	}
+	want := domain.DefaultHTTPRetryPolicy().RateLimited.MaxRetries + 1
+	if attempts != want {
+		t.Errorf("attempts = %d, want %d", attempts, want)
+	}
+	var jiraErr *domain.JiraError
+	if !errors.As(err, &jiraErr) || jiraErr.Status != http.StatusTooManyRequests || jiraErr.Attempts != want {
+		t.Errorf("error = %v, want the last status and %d attempts", err, want)
+	}
+}
+
+func TestClient_RateLimitWaitHonorsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewClient(server.URL, "me@example.com", "secret").SearchTickets(ctx, "project = JMD", 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("SearchTickets returned after %s, want it to stop waiting when the context ends", elapsed)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"abc", 0, false},
+		{"-1", 0, false},
+		{"2", 2 * time.Second, true},
+		{"3600", maxRetryAfter, true},
+	}
+
+	for _, tt := range tests {
+		if got, ok := retryAfter(tt.header); got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %s, %v; want %s, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTextToADF(t *testing.T) {
+	if doc := textToADF("  \n "); doc != nil {
+		t.Errorf("blank text should have no document, got %+v", doc)
+	}
+
+	doc := textToADF("a\r\nb\n\n\n\nc\n")
+	if doc.Type != "doc" || doc.Version != 1 || len(doc.Content) != 2 {
+		t.Fatalf("doc = %+v", doc)
+	}
+	first := doc.Content[0].Content
+	if len(first) != 3 || first[0].Text != "a" || first[1].Type != "hardBreak" || first[2].Text != "b" {
+		t.Errorf("first paragraph = %+v", first)
+	}
+}
+
+func TestTextToADF_TaskList(t *testing.T) {
+	text := "Checklist:\n- [ ] write tests\n* [X] ship it\n- [ ]\n\nThanks"
+	doc := textToADF(text)
+	want := `{"type":"doc","version":1,"content":[` +
+		`{"type":"paragraph","content":[{"type":"text","text":"Checklist:"}]},` +
+		`{"type":"taskList","attrs":{"localId":"task-1"},"content":[` +
+		`{"type":"taskItem","attrs":{"localId":"task-2","state":"TODO"},"content":[{"type":"text","text":"write tests"}]},` +
+		`{"type":"taskItem","attrs":{"localId":"task-3","state":"DONE"},"content":[{"type":"text","text":"ship it"}]},` +
+		`{"type":"taskItem","attrs":{"localId":"task-4","state":"TODO"}}]},` +
+		`{"type":"paragraph","content":[{"type":"text","text":"Thanks"}]}]}`
+	if got := string(mustJSON(t, doc)); got != want {
+		t.Errorf("textToADF() = %s, want %s", got, want)
+	}
+
+	// Checked state survives the way back, and the text converts to the same document
+	back := adfText(mustJSON(t, doc))
+	if back != "Checklist:\n- [ ] write tests\n- [x] ship it\n- [ ] \nThanks" {
+		t.Errorf("adfText() = %q", back)
+	}
+	if again := string(mustJSON(t, textToADF(back))); again != want {
+		t.Errorf("textToADF(adfText()) = %s, want %s", again, want)
+	}
+}
+
+// mustJSON encodes v for feeding back into adfText.
+func mustJSON(t *testing.T, v interface{}) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return data
+}
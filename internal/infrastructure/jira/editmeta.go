@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// editMetaResponse is the body of GET /rest/api/3/issue/{key}/editmeta: the fields of the
+// issue's edit screen that the user may edit, keyed by field ID.
+type editMetaResponse struct {
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+// editableFields returns the IDs of the fields of a ticket the user may edit. Fields
+// hidden from the ticket's edit screen, or that the user lacks permission to change,
+// are not listed, and Jira rejects an edit including any of them.
+func (c *Client) editableFields(ctx context.Context, key string) (map[string]bool, error) {
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "/editmeta"
+	var meta editMetaResponse
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &meta); err != nil {
+		return nil, fmt.Errorf("failed to fetch edit metadata of %s: %w", key, err)
+	}
+
+	editable := make(map[string]bool, len(meta.Fields))
+	for id := range meta.Fields {
+		editable[id] = true
+	}
+	return editable, nil
+}
+
+// dropUneditable returns the fields of ids the user may edit on a ticket, in order, and
+// logs a warning naming the others, so one field hidden from the edit screen does not
+// fail the whole edit.
+func (c *Client) dropUneditable(ctx context.Context, key string, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	editable, err := c.editableFields(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept, dropped []string
+	for _, id := range ids {
+		if editable[id] {
+			kept = append(kept, id)
+		} else {
+			dropped = append(dropped, id)
+		}
+	}
+	if len(dropped) > 0 {
+		c.logger.Warn("not pushing fields the user cannot edit in Jira",
+			"ticket_key", key, "fields", dropped)
+	}
+	return kept, nil
+}
@@ -0,0 +1,455 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Schema types of Jira fields (FieldSchema.Type and Items) that field edits are built for.
+const (
+	SchemaString          = "string"
+	SchemaNumber          = "number"
+	SchemaDate            = "date"
+	SchemaDateTime        = "datetime"
+	SchemaOption          = "option"
+	SchemaCascadingOption = "option-with-child"
+	SchemaUser            = "user"
+	SchemaArray           = "array"
+	SchemaPriority        = "priority"
+	SchemaComponent       = "component"
+	SchemaVersion         = "version"
+)
+
+// textareaCustomType is the FieldSchema.Custom of multi-line text fields, whose values
+// are Atlassian Document Format documents rather than plain strings.
+const textareaCustomType = "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+
+// FieldSchema describes the values of a Jira field, as listed by GET /rest/api/3/field.
+type FieldSchema struct {
+	// Type is the type of the field's value, one of the Schema* constants for the fields
+	// edits can be built for
+	Type string `json:"type"`
+
+	// Items is the type of each value of array fields
+	Items string `json:"items,omitempty"`
+
+	// Custom identifies the type of custom fields, e.g.
+	// com.atlassian.jira.plugin.system.customfieldtypes:multiselect
+	Custom string `json:"custom,omitempty"`
+}
+
+// FieldDefinition is a field of the Jira site.
+type FieldDefinition struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Custom bool        `json:"custom"`
+	Schema FieldSchema `json:"schema"`
+}
+
+// fieldDefinitions caches the site's field definitions, which change rarely, for the
+// lifetime of a client.
+type fieldDefinitions struct {
+	mu   sync.Mutex
+	byID map[string]FieldDefinition
+}
+
+// Fields returns the definitions of the fields of the Jira site, ordered by ID. They are
+// fetched once per client.
+func (c *Client) Fields(ctx context.Context) ([]FieldDefinition, error) {
+	byID, err := c.fieldDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]FieldDefinition, 0, len(byID))
+	for _, field := range byID {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].ID < fields[j].ID })
+	return fields, nil
+}
+
+// fieldDefinitions returns the site's field definitions by ID, fetching them on first use.
+func (c *Client) fieldDefinitions(ctx context.Context) (map[string]FieldDefinition, error) {
+	c.fields.mu.Lock()
+	defer c.fields.mu.Unlock()
+	if c.fields.byID != nil {
+		return c.fields.byID, nil
+	}
+
+	var fields []FieldDefinition
+	if err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/field", nil, &fields); err != nil {
+		return nil, fmt.Errorf("failed to list fields: %w", err)
+	}
+	byID := make(map[string]FieldDefinition, len(fields))
+	for _, field := range fields {
+		byID[field.ID] = field
+	}
+	c.fields.byID = byID
+	return byID, nil
+}
+
+// FieldEditOp is how a FieldEdit changes a field.
+type FieldEditOp string
+
+const (
+	// FieldSet replaces the field's value (a zero value clears it)
+	FieldSet FieldEditOp = "set"
+
+	// FieldAdd adds values to a multi-value field, keeping its other values
+	FieldAdd FieldEditOp = "add"
+
+	// FieldRemove removes values from a multi-value field, keeping its other values
+	FieldRemove FieldEditOp = "remove"
+)
+
+// FieldEdit is a change to one field of a ticket, by field ID (e.g. customfield_10001).
+//
+// Values are given the way they appear in frontmatter and are converted to the JSON
+// shape the field's schema type requires: options by their value, users by email,
+// display name, or account ID, dates as 2006-01-02, and cascading selects as
+// "Parent > Child" or a [parent, child] list. Multi-value fields take a list, or a single
+// value for one item.
+type FieldEdit struct {
+	FieldID string
+	Value   domain.FieldValue
+
+	// Op is how Value changes the field (FieldSet when empty)
+	Op FieldEditOp
+}
+
+// UpdateFields applies edits to a ticket's fields in one request. Values are converted
+// by each field's schema type (see FieldEdit): sets go in the request's fields and adds
+// and removes in its update operations.
+// Returns ErrInvalidInput for unknown fields, schema types edits cannot be built for,
+// and values that do not fit the field's type, without writing anything. Edits of
+// fields the user may not edit on the ticket, such as fields hidden from its edit
+// screen, are then left out with a warning; if none are left, nothing is written.
+func (c *Client) UpdateFields(ctx context.Context, key string, edits []FieldEdit) error {
+	if len(edits) == 0 {
+		return nil
+	}
+	req, err := c.newFieldEditRequest(ctx, key, edits)
+	if err != nil {
+		return err
+	}
+
+	editable, err := c.dropUneditable(ctx, key, req.fieldIDs())
+	if err != nil {
+		return err
+	}
+	if !req.keep(editable) {
+		return nil
+	}
+
+	path := "/rest/api/3/issue/" + url.PathEscape(key)
+	return c.doRequest(ctx, http.MethodPut, path, req, nil)
+}
+
+// newFieldEditRequest builds the request applying edits to a ticket's fields.
+// Returns ErrInvalidInput for unknown fields and values that do not fit their field.
+func (c *Client) newFieldEditRequest(ctx context.Context, key string, edits []FieldEdit) (fieldEditRequest, error) {
+	definitions, err := c.fieldDefinitions(ctx)
+	if err != nil {
+		return fieldEditRequest{}, err
+	}
+	builder := payloadBuilder{accountID: c.accountID}
+	req := fieldEditRequest{}
+	for _, edit := range edits {
+		definition, ok := definitions[edit.FieldID]
+		if !ok {
+			return fieldEditRequest{}, fmt.Errorf("%w: unknown field %s", domain.ErrInvalidInput, edit.FieldID)
+		}
+		if err := builder.add(ctx, &req, definition, edit); err != nil {
+			return fieldEditRequest{}, fmt.Errorf("failed to edit %s (%s) of %s: %w", definition.Name, definition.ID, key, err)
+		}
+	}
+	return req, nil
+}
+
+// accountID returns the account ID of the active user name identifies (see
+// domain.User.Identifies), or the only active user it matches.
+// Returns ErrInvalidInput when that is not exactly one user.
+func (c *Client) accountID(ctx context.Context, name string) (string, error) {
+	users, err := c.SearchUsers(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	active := make([]*domain.User, 0, len(users))
+	for _, user := range users {
+		if user.Active {
+			active = append(active, user)
+		}
+	}
+	user, candidates := domain.FindUser(name, active)
+	switch {
+	case user != nil:
+		return user.AccountID, nil
+	case len(candidates) > 0:
+		return "", fmt.Errorf("%w: user %q is ambiguous", domain.ErrInvalidInput, name)
+	}
+	return "", fmt.Errorf("%w: no active user %q", domain.ErrInvalidInput, name)
+}
+
+// fieldEditRequest is the body of PUT /rest/api/3/issue/{key} for field edits: values
+// to set in Fields, and add and remove operations on multi-value fields in Update.
+type fieldEditRequest struct {
+	Fields map[string]interface{}              `json:"fields,omitempty"`
+	Update map[string][]map[string]interface{} `json:"update,omitempty"`
+}
+
+// fieldIDs returns the IDs of the fields the request edits, sorted.
+func (r *fieldEditRequest) fieldIDs() []string {
+	ids := make([]string, 0, len(r.Fields)+len(r.Update))
+	for id := range r.Fields {
+		ids = append(ids, id)
+	}
+	for id := range r.Update {
+		if _, ok := r.Fields[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// keep removes the edits of fields not in ids and reports whether any edit is left.
+func (r *fieldEditRequest) keep(ids []string) bool {
+	for id := range r.Fields {
+		if !slices.Contains(ids, id) {
+			delete(r.Fields, id)
+		}
+	}
+	for id := range r.Update {
+		if !slices.Contains(ids, id) {
+			delete(r.Update, id)
+		}
+	}
+	return len(r.Fields) > 0 || len(r.Update) > 0
+}
+
+// itemBuilder converts one local value to the JSON value of a field of a schema type.
+type itemBuilder func(ctx context.Context, b payloadBuilder, schema FieldSchema, value domain.FieldValue) (interface{}, error)
+
+// itemBuilders build field values by schema type. Array fields build each of their
+// values with the builder of their item type.
+var itemBuilders = map[string]itemBuilder{
+	SchemaString:          buildString,
+	SchemaNumber:          buildNumber,
+	SchemaDate:            buildDate,
+	SchemaDateTime:        buildDateTime,
+	SchemaOption:          buildOption,
+	SchemaCascadingOption: buildCascadingOption,
+	SchemaUser:            buildUser,
+	SchemaPriority:        buildNamed,
+	SchemaComponent:       buildNamed,
+	SchemaVersion:         buildNamed,
+}
+
+// payloadBuilder builds the bodies of field edits.
+type payloadBuilder struct {
+	// accountID resolves a user name to an account ID
+	accountID func(ctx context.Context, name string) (string, error)
+}
+
+// add adds an edit of the field described by definition to req.
+func (b payloadBuilder) add(ctx context.Context, req *fieldEditRequest, definition FieldDefinition, edit FieldEdit) error {
+	schema := definition.Schema
+	op := edit.Op
+	if op == "" {
+		op = FieldSet
+	}
+
+	if schema.Type != SchemaArray {
+		if op != FieldSet {
+			return fmt.Errorf("%w: only multi-value fields can %s values", domain.ErrInvalidInput, op)
+		}
+		value, err := b.item(ctx, schema.Type, schema, edit.Value)
+		if err != nil {
+			return err
+		}
+		if req.Fields == nil {
+			req.Fields = make(map[string]interface{})
+		}
+		req.Fields[definition.ID] = value
+		return nil
+	}
+
+	items, err := b.items(ctx, schema, edit.Value)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case FieldSet:
+		if req.Fields == nil {
+			req.Fields = make(map[string]interface{})
+		}
+		req.Fields[definition.ID] = items
+	case FieldAdd, FieldRemove:
+		if req.Update == nil {
+			req.Update = make(map[string][]map[string]interface{})
+		}
+		for _, item := range items {
+			req.Update[definition.ID] = append(req.Update[definition.ID], map[string]interface{}{string(op): item})
+		}
+	default:
+		return fmt.Errorf("%w: unknown field edit %q", domain.ErrInvalidInput, op)
+	}
+	return nil
+}
+
+// items builds the values of an array field, never nil so setting no values clears it.
+func (b payloadBuilder) items(ctx context.Context, schema FieldSchema, value domain.FieldValue) ([]interface{}, error) {
+	var values []domain.FieldValue
+	switch raw := value.Raw().(type) {
+	case nil:
+	case []string:
+		for _, v := range raw {
+			values = append(values, domain.NewFieldValue(v))
+		}
+	case []interface{}:
+		for _, v := range raw {
+			values = append(values, domain.NewFieldValue(v))
+		}
+	default:
+		values = append(values, value)
+	}
+
+	items := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if v.IsZero() {
+			continue
+		}
+		item, err := b.item(ctx, schema.Items, schema, v)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// item builds one value of a field with the builder of schemaType. A zero value is
+// JSON null, which clears the field.
+func (b payloadBuilder) item(ctx context.Context, schemaType string, schema FieldSchema, value domain.FieldValue) (interface{}, error) {
+	if value.IsZero() {
+		return nil, nil
+	}
+	build, ok := itemBuilders[schemaType]
+	if !ok {
+		return nil, fmt.Errorf("%w: fields of type %q cannot be edited", domain.ErrInvalidInput, schemaType)
+	}
+	return build(ctx, b, schema, value)
+}
+
+// scalar returns a single value as a trimmed string, rejecting lists and objects.
+func scalar(value domain.FieldValue) (string, error) {
+	switch value.Kind() {
+	case domain.FieldKindList, domain.FieldKindOther:
+		return "", fmt.Errorf("%w: %v is not a single value", domain.ErrInvalidInput, value.Raw())
+	}
+	return strings.TrimSpace(value.String()), nil
+}
+
+// buildString builds a text field value: a document for multi-line text fields, the
+// string otherwise.
+func buildString(_ context.Context, _ payloadBuilder, schema FieldSchema, value domain.FieldValue) (interface{}, error) {
+	text, err := scalar(value)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Custom == textareaCustomType {
+		return textToADF(text), nil
+	}
+	return text, nil
+}
+
+// buildNumber builds a number field value.
+func buildNumber(_ context.Context, _ payloadBuilder, _ FieldSchema, value domain.FieldValue) (interface{}, error) {
+	n, ok := value.Float()
+	if !ok {
+		return nil, fmt.Errorf("%w: %v is not a number", domain.ErrInvalidInput, value.Raw())
+	}
+	return n, nil
+}
+
+// buildDate builds a date field value, e.g. "2024-03-05".
+func buildDate(_ context.Context, _ payloadBuilder, _ FieldSchema, value domain.FieldValue) (interface{}, error) {
+	t, ok := value.Time()
+	if !ok {
+		return nil, fmt.Errorf("%w: %v is not a date (use 2006-01-02)", domain.ErrInvalidInput, value.Raw())
+	}
+	return t.Format("2006-01-02"), nil
+}
+
+// buildDateTime builds a date-time field value in Jira's timestamp format.
+func buildDateTime(_ context.Context, _ payloadBuilder, _ FieldSchema, value domain.FieldValue) (interface{}, error) {
+	t, ok := value.Time()
+	if !ok {
+		return nil, fmt.Errorf("%w: %v is not a date and time (use RFC 3339)", domain.ErrInvalidInput, value.Raw())
+	}
+	return t.Format(jiraTimeLayout), nil
+}
+
+// buildOption builds a select field value, identified by the option's value.
+func buildOption(_ context.Context, _ payloadBuilder, _ FieldSchema, value domain.FieldValue) (interface{}, error) {
+	option, err := scalar(value)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"value": option}, nil
+}
+
+// cascadeSeparator separates the parent and child option of a cascading select value.
+const cascadeSeparator = ">"
+
+// buildCascadingOption builds a cascading select value from "Parent > Child", a
+// [parent, child] list, or a parent alone.
+func buildCascadingOption(_ context.Context, _ payloadBuilder, _ FieldSchema, value domain.FieldValue) (interface{}, error) {
+	parts, ok := value.StringSlice()
+	if !ok {
+		text, err := scalar(value)
+		if err != nil {
+			return nil, err
+		}
+		parts = strings.SplitN(text, cascadeSeparator, 2)
+	}
+	if len(parts) == 0 || len(parts) > 2 || strings.TrimSpace(parts[0]) == "" {
+		return nil, fmt.Errorf("%w: %v is not a cascading option (use \"Parent > Child\")", domain.ErrInvalidInput, value.Raw())
+	}
+
+	option := map[string]interface{}{"value": strings.TrimSpace(parts[0])}
+	if len(parts) == 2 && strings.TrimSpace(parts[1]) != "" {
+		option["child"] = map[string]interface{}{"value": strings.TrimSpace(parts[1])}
+	}
+	return option, nil
+}
+
+// buildUser builds a user field value, identified by account ID.
+func buildUser(ctx context.Context, b payloadBuilder, _ FieldSchema, value domain.FieldValue) (interface{}, error) {
+	name, err := scalar(value)
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := b.accountID(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"accountId": accountID}, nil
+}
+
+// buildNamed builds a value identified by name, such as a priority, component, or version.
+func buildNamed(_ context.Context, _ payloadBuilder, _ FieldSchema, value domain.FieldValue) (interface{}, error) {
+	name, err := scalar(value)
+	if err != nil {
+		return nil, err
+	}
+	return namedField{Name: name}, nil
+}
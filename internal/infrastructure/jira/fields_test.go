@@ -0,0 +1,213 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// fieldsServerDefinitions are the fields fieldsServer lists, one of every supported
+// schema type.
+var fieldsServerDefinitions = []FieldDefinition{
+	{ID: "customfield_1", Name: "Team", Custom: true, Schema: FieldSchema{Type: SchemaOption}},
+	{ID: "customfield_2", Name: "Platforms", Custom: true, Schema: FieldSchema{Type: SchemaArray, Items: SchemaOption}},
+	{ID: "customfield_3", Name: "Reviewer", Custom: true, Schema: FieldSchema{Type: SchemaUser}},
+	{ID: "customfield_4", Name: "Launch", Custom: true, Schema: FieldSchema{Type: SchemaDate}},
+	{ID: "customfield_5", Name: "Location", Custom: true, Schema: FieldSchema{Type: SchemaCascadingOption}},
+	{ID: "customfield_6", Name: "Estimate", Custom: true, Schema: FieldSchema{Type: SchemaNumber}},
+	{ID: "customfield_7", Name: "Notes", Custom: true, Schema: FieldSchema{Type: SchemaString, Custom: textareaCustomType}},
+	{ID: "customfield_8", Name: "Deployed", Custom: true, Schema: FieldSchema{Type: SchemaDateTime}},
+	{ID: "customfield_9", Name: "Checklist", Custom: true, Schema: FieldSchema{Type: "any"}},
+	{ID: "fixVersions", Name: "Fix versions", Schema: FieldSchema{Type: SchemaArray, Items: SchemaVersion}},
+}
+
+// fieldsServer lists a field of every supported schema type, finds one user, and records
+// the body of each issue edit.
+type fieldsServer struct {
+	fieldLists int
+	edits      []string
+
+	// hidden are the fields missing from the issue's edit screen
+	hidden []string
+}
+
+func (s *fieldsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/JMD-1/editmeta":
+		fields := make(map[string]interface{})
+		for _, definition := range fieldsServerDefinitions {
+			if !slices.Contains(s.hidden, definition.ID) {
+				fields[definition.ID] = map[string]interface{}{"name": definition.Name}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"fields": fields})
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/field":
+		s.fieldLists++
+		json.NewEncoder(w).Encode(fieldsServerDefinitions)
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/user/search":
+		json.NewEncoder(w).Encode([]user{
+			{AccountID: "acc-alice", DisplayName: "Alice Smith", EmailAddress: "alice@example.com", Active: true},
+		})
+	case r.Method == http.MethodPut && r.URL.Path == "/rest/api/3/issue/JMD-1":
+		body, _ := io.ReadAll(r.Body)
+		s.edits = append(s.edits, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestClient_UpdateFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		edits []FieldEdit
+		want  string
+	}{
+		{
+			name:  "select",
+			edits: []FieldEdit{{FieldID: "customfield_1", Value: domain.NewFieldValue(" Red ")}},
+			want:  `{"fields":{"customfield_1":{"value":"Red"}}}`,
+		},
+		{
+			name:  "multi-select",
+			edits: []FieldEdit{{FieldID: "customfield_2", Value: domain.NewFieldValue([]interface{}{"iOS", "Android"})}},
+			want:  `{"fields":{"customfield_2":[{"value":"iOS"},{"value":"Android"}]}}`,
+		},
+		{
+			name:  "multi-select cleared",
+			edits: []FieldEdit{{FieldID: "customfield_2", Value: domain.NewFieldValue(nil)}},
+			want:  `{"fields":{"customfield_2":[]}}`,
+		},
+		{
+			name: "multi-select add and remove",
+			edits: []FieldEdit{
+				{FieldID: "customfield_2", Value: domain.NewFieldValue("Web"), Op: FieldAdd},
+				{FieldID: "customfield_2", Value: domain.NewFieldValue([]string{"iOS"}), Op: FieldRemove},
+			},
+			want: `{"update":{"customfield_2":[{"add":{"value":"Web"}},{"remove":{"value":"iOS"}}]}}`,
+		},
+		{
+			name:  "user",
+			edits: []FieldEdit{{FieldID: "customfield_3", Value: domain.NewFieldValue("alice@example.com")}},
+			want:  `{"fields":{"customfield_3":{"accountId":"acc-alice"}}}`,
+		},
+		{
+			name:  "date",
+			edits: []FieldEdit{{FieldID: "customfield_4", Value: domain.NewFieldValue("2026-03-05")}},
+			want:  `{"fields":{"customfield_4":"2026-03-05"}}`,
+		},
+		{
+			name:  "date time",
+			edits: []FieldEdit{{FieldID: "customfield_8", Value: domain.NewFieldValue(time.Date(2026, 3, 5, 14, 30, 0, 0, time.UTC))}},
+			want:  `{"fields":{"customfield_8":"2026-03-05T14:30:00.000+0000"}}`,
+		},
+		{
+			name:  "cascading select",
+			edits: []FieldEdit{{FieldID: "customfield_5", Value: domain.NewFieldValue("Europe > Berlin")}},
+			want:  `{"fields":{"customfield_5":{"child":{"value":"Berlin"},"value":"Europe"}}}`,
+		},
+		{
+			name:  "cascading select parent only",
+			edits: []FieldEdit{{FieldID: "customfield_5", Value: domain.NewFieldValue([]string{"Europe"})}},
+			want:  `{"fields":{"customfield_5":{"value":"Europe"}}}`,
+		},
+		{
+			name: "number, text area, and cleared value",
+			edits: []FieldEdit{
+				{FieldID: "customfield_6", Value: domain.NewFieldValue("2.5")},
+				{FieldID: "customfield_7", Value: domain.NewFieldValue("Ship it")},
+				{FieldID: "customfield_1", Value: domain.NewFieldValue(nil)},
+			},
+			want: `{"fields":{"customfield_1":null,"customfield_6":2.5,"customfield_7":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"Ship it"}]}]}}}`,
+		},
+		{
+			name:  "versions",
+			edits: []FieldEdit{{FieldID: "fixVersions", Value: domain.NewFieldValue([]string{"1.2"})}},
+			want:  `{"fields":{"fixVersions":[{"name":"1.2"}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &fieldsServer{}
+			server := httptest.NewServer(state)
+			defer server.Close()
+
+			client := NewClient(server.URL, "me@example.com", "secret")
+			if err := client.UpdateFields(context.Background(), "JMD-1", tt.edits); err != nil {
+				t.Fatalf("UpdateFields() error = %v", err)
+			}
+			if len(state.edits) != 1 || state.edits[0] != tt.want {
+				t.Errorf("UpdateFields() sent %q, want %s", state.edits, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateFields_UneditableFields(t *testing.T) {
+	state := &fieldsServer{hidden: []string{"customfield_6"}}
+	server := httptest.NewServer(state)
+	defer server.Close()
+	client := NewClient(server.URL, "me@example.com", "secret").WithLogger(slog.New(slog.DiscardHandler))
+	ctx := context.Background()
+
+	edits := []FieldEdit{
+		{FieldID: "customfield_6", Value: domain.NewFieldValue("2.5")},
+		{FieldID: "fixVersions", Value: domain.NewFieldValue([]string{"1.2"})},
+	}
+	if err := client.UpdateFields(ctx, "JMD-1", edits); err != nil {
+		t.Fatalf("UpdateFields() error = %v", err)
+	}
+	if want := `{"fields":{"fixVersions":[{"name":"1.2"}]}}`; len(state.edits) != 1 || state.edits[0] != want {
+		t.Errorf("UpdateFields() sent %q, want only the editable field %s", state.edits, want)
+	}
+
+	if err := client.UpdateFields(ctx, "JMD-1", edits[:1]); err != nil {
+		t.Fatalf("UpdateFields() of an uneditable field error = %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Errorf("uneditable edit wrote to Jira: %q", state.edits)
+	}
+}
+
+func TestClient_UpdateFields_Invalid(t *testing.T) {
+	state := &fieldsServer{}
+	server := httptest.NewServer(state)
+	defer server.Close()
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		edit FieldEdit
+	}{
+		{name: "unknown field", edit: FieldEdit{FieldID: "customfield_99", Value: domain.NewFieldValue("x")}},
+		{name: "unsupported type", edit: FieldEdit{FieldID: "customfield_9", Value: domain.NewFieldValue("x")}},
+		{name: "not a number", edit: FieldEdit{FieldID: "customfield_6", Value: domain.NewFieldValue("lots")}},
+		{name: "not a date", edit: FieldEdit{FieldID: "customfield_4", Value: domain.NewFieldValue("soon")}},
+		{name: "list for a single value", edit: FieldEdit{FieldID: "customfield_1", Value: domain.NewFieldValue([]string{"Red", "Blue"})}},
+		{name: "add to a single value", edit: FieldEdit{FieldID: "customfield_1", Value: domain.NewFieldValue("Red"), Op: FieldAdd}},
+		{name: "unknown user", edit: FieldEdit{FieldID: "customfield_3", Value: domain.NewFieldValue("bob")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := client.UpdateFields(ctx, "JMD-1", []FieldEdit{tt.edit}); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("UpdateFields() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+	if len(state.edits) != 0 {
+		t.Errorf("UpdateFields() sent %q, want nothing written", state.edits)
+	}
+	if state.fieldLists != 1 {
+		t.Errorf("fields listed %d times, want once per client", state.fieldLists)
+	}
+}
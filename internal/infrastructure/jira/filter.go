@@ -0,0 +1,34 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// filterJSON is a saved filter as returned by GET /rest/api/3/filter/{id}.
+type filterJSON struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	JQL  string `json:"jql"`
+}
+
+// GetFilter returns a saved filter with its JQL.
+// Returns ErrInvalidInput for an empty ID, and ErrNotFound if the filter doesn't exist
+// or is not shared with the user.
+func (c *Client) GetFilter(ctx context.Context, id string) (*domain.SavedFilter, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("%w: filter ID is empty", domain.ErrInvalidInput)
+	}
+
+	var filter filterJSON
+	if err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/filter/"+url.PathEscape(id), nil, &filter); err != nil {
+		return nil, err
+	}
+	return &domain.SavedFilter{ID: filter.ID, Name: filter.Name, JQL: filter.JQL}, nil
+}
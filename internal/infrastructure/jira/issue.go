@@ -0,0 +1,353 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// jiraTimeLayout is the timestamp format used in Jira issue fields.
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// sprintFieldID is the ID of Jira Software's sprint field, the same on every Jira Cloud site.
+const sprintFieldID = "customfield_10020"
+
+// issueFields lists the fields requested for every issue, matching what toTicket maps.
+var issueFields = []string{
+	"summary",
+	"description",
+	"status",
+	"issuetype",
+	"priority",
+	"assignee",
+	"reporter",
+	"labels",
+	"fixVersions",
+	"issuelinks",
+	"attachment",
+	sprintFieldID,
+	"created",
+	"updated",
+}
+
+// issue is the subset of a Jira REST v3 issue that jiramd maps to a domain.Ticket.
+type issue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string          `json:"summary"`
+		Description json.RawMessage `json:"description"`
+		Status      *namedField     `json:"status"`
+		IssueType   *namedField     `json:"issuetype"`
+		Priority    *namedField     `json:"priority"`
+		Assignee    *user           `json:"assignee"`
+		Reporter    *user           `json:"reporter"`
+		Labels      []string        `json:"labels"`
+		FixVersions []namedField    `json:"fixVersions"`
+		IssueLinks  []issueLink     `json:"issuelinks"`
+		Attachments []attachment    `json:"attachment"`
+		Sprints     []namedField    `json:"customfield_10020"`
+		Created     string          `json:"created"`
+		Updated     string          `json:"updated"`
+	} `json:"fields"`
+
+	// rawFields is the JSON of every field returned, for the raw fields toTicket does
+	// not map (see Client.WithRawFields)
+	rawFields json.RawMessage
+}
+
+// UnmarshalJSON decodes an issue, keeping the JSON of its fields in rawFields.
+func (i *issue) UnmarshalJSON(data []byte) error {
+	var body struct {
+		Key    string          `json:"key"`
+		Fields json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	i.Key = body.Key
+	i.rawFields = body.Fields
+	if len(body.Fields) == 0 || string(body.Fields) == "null" {
+		return nil
+	}
+	return json.Unmarshal(body.Fields, &i.Fields)
+}
+
+// namedField is a Jira field object identified by name (status, issue type, priority,
+// version, sprint).
+type namedField struct {
+	Name string `json:"name"`
+}
+
+// issueLink is a link from an issue to another, which is either its inward or its
+// outward issue depending on the link's direction.
+type issueLink struct {
+	InwardIssue  *linkedIssue `json:"inwardIssue"`
+	OutwardIssue *linkedIssue `json:"outwardIssue"`
+}
+
+// attachment is a file attached to an issue.
+type attachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mimeType"`
+	Size     int64  `json:"size"`
+	Content  string `json:"content"`
+}
+
+// linkedIssue is the other end of an issue link.
+type linkedIssue struct {
+	Key string `json:"key"`
+}
+
+// user is a Jira user reference.
+type user struct {
+	AccountID    string `json:"accountId"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+	Active       bool   `json:"active"`
+
+	// AvatarURLs are the user's profile picture in several sizes, keyed by size
+	// (e.g. "48x48")
+	AvatarURLs map[string]string `json:"avatarUrls"`
+}
+
+// avatarSize is the size of the avatar jiramd records, the largest Jira serves.
+const avatarSize = "48x48"
+
+// name returns the user's email when visible, falling back to the display name.
+func (u *user) name() string {
+	if u == nil {
+		return ""
+	}
+	if u.EmailAddress != "" {
+		return u.EmailAddress
+	}
+	return u.DisplayName
+}
+
+// toTicket maps a Jira issue to a domain ticket.
+func (i *issue) toTicket() (*domain.Ticket, error) {
+	key, err := domain.NewTicketKey(i.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := parseJiraTime(i.Fields.Created)
+	if err != nil {
+		return nil, fmt.Errorf("invalid created time on %s: %w", i.Key, err)
+	}
+	updated, err := parseJiraTime(i.Fields.Updated)
+	if err != nil {
+		return nil, fmt.Errorf("invalid updated time on %s: %w", i.Key, err)
+	}
+
+	ticket := domain.NewTicket(key, i.Fields.Summary, created, updated)
+	ticket.Description = adfText(i.Fields.Description)
+	if i.Fields.Status != nil {
+		ticket.Status = i.Fields.Status.Name
+	}
+	if i.Fields.IssueType != nil {
+		ticket.IssueType = i.Fields.IssueType.Name
+	}
+	if i.Fields.Priority != nil {
+		ticket.Priority = i.Fields.Priority.Name
+	}
+	ticket.Assignee = i.Fields.Assignee.name()
+	ticket.Reporter = i.Fields.Reporter.name()
+	if i.Fields.Labels != nil {
+		ticket.Labels = i.Fields.Labels
+	}
+	if len(i.Fields.FixVersions) > 0 {
+		versions := make([]string, 0, len(i.Fields.FixVersions))
+		for _, version := range i.Fields.FixVersions {
+			versions = append(versions, version.Name)
+		}
+		ticket.CustomFields[domain.FixVersionsField] = domain.NewFieldValue(versions)
+	}
+	if len(i.Fields.IssueLinks) > 0 {
+		linked := make([]string, 0, len(i.Fields.IssueLinks))
+		for _, link := range i.Fields.IssueLinks {
+			switch {
+			case link.InwardIssue != nil:
+				linked = append(linked, link.InwardIssue.Key)
+			case link.OutwardIssue != nil:
+				linked = append(linked, link.OutwardIssue.Key)
+			}
+		}
+		ticket.CustomFields[domain.IssueLinksField] = domain.NewFieldValue(linked)
+	}
+	if len(i.Fields.Attachments) > 0 {
+		attachments := make([]domain.Attachment, 0, len(i.Fields.Attachments))
+		for _, a := range i.Fields.Attachments {
+			attachments = append(attachments, domain.Attachment{
+				ID:       a.ID,
+				Filename: a.Filename,
+				MimeType: a.MimeType,
+				Size:     a.Size,
+				URL:      a.Content,
+			})
+		}
+		ticket.CustomFields[domain.AttachmentsField] = domain.AttachmentsValue(attachments)
+	}
+	if len(i.Fields.Sprints) > 0 {
+		sprints := make([]string, 0, len(i.Fields.Sprints))
+		for _, sprint := range i.Fields.Sprints {
+			sprints = append(sprints, sprint.Name)
+		}
+		ticket.CustomFields[domain.SprintsField] = domain.NewFieldValue(sprints)
+	}
+
+	return ticket, nil
+}
+
+// parseJiraTime parses a Jira timestamp; an empty string yields the zero time.
+func parseJiraTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(jiraTimeLayout, s)
+}
+
+// adfNode is a node of an Atlassian Document Format document.
+type adfNode struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Content []adfNode              `json:"content,omitempty"`
+}
+
+// ADF task item states (taskItem attrs.state).
+const (
+	adfTaskTodo = "TODO"
+	adfTaskDone = "DONE"
+)
+
+// taskItemPattern matches a markdown task list item, "- [ ] text" or "- [x] text",
+// capturing its check mark and text.
+var taskItemPattern = regexp.MustCompile(`^\s*[-*+] \[([ xX])\](?:\s+(.*))?$`)
+
+// textToADF converts plain text into an ADF document for request bodies.
+// Blank lines separate paragraphs and single newlines become hard breaks. Markdown
+// task list items ("- [ ] text", "- [x] text") become task lists, checked or not.
+// Returns nil for blank text.
+func textToADF(text string) *adfNode {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return nil
+	}
+
+	doc := &adfNode{Type: "doc", Version: 1}
+	localIDs := 0
+	for _, block := range strings.Split(text, "\n\n") {
+		block = strings.Trim(block, "\n")
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+
+		var nodes []adfNode
+		for _, line := range strings.Split(block, "\n") {
+			last := len(nodes) - 1
+			if match := taskItemPattern.FindStringSubmatch(line); match != nil {
+				if last < 0 || nodes[last].Type != "taskList" {
+					localIDs++
+					nodes = append(nodes, adfNode{Type: "taskList", Attrs: map[string]interface{}{"localId": fmt.Sprintf("task-%d", localIDs)}})
+					last++
+				}
+				localIDs++
+				item := adfNode{Type: "taskItem", Attrs: map[string]interface{}{"localId": fmt.Sprintf("task-%d", localIDs), "state": adfTaskTodo}}
+				if match[1] != " " {
+					item.Attrs["state"] = adfTaskDone
+				}
+				if match[2] != "" {
+					item.Content = []adfNode{{Type: "text", Text: match[2]}}
+				}
+				nodes[last].Content = append(nodes[last].Content, item)
+				continue
+			}
+
+			if last < 0 || nodes[last].Type != "paragraph" {
+				nodes = append(nodes, adfNode{Type: "paragraph"})
+				last++
+			} else {
+				nodes[last].Content = append(nodes[last].Content, adfNode{Type: "hardBreak"})
+			}
+			if line != "" {
+				nodes[last].Content = append(nodes[last].Content, adfNode{Type: "text", Text: line})
+			}
+		}
+		doc.Content = append(doc.Content, nodes...)
+	}
+	return doc
+}
+
+// adfText flattens an Atlassian Document Format value to plain text,
+// separating block-level nodes with newlines. Media (images and other attached files)
+// become Jira wiki references to their file name, !name!.
+// Plain JSON strings (as returned by older APIs) are passed through.
+func adfText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeADF(&sb, doc)
+	return strings.TrimSpace(sb.String())
+}
+
+// writeADF appends the text of an ADF node and its children.
+func writeADF(sb *strings.Builder, node adfNode) {
+	switch node.Type {
+	case "text":
+		sb.WriteString(node.Text)
+		return
+	case "hardBreak":
+		sb.WriteString("\n")
+		return
+	case "media":
+		if name := mediaName(node); name != "" {
+			sb.WriteString("!" + name + "!")
+		}
+		return
+	case "taskItem":
+		if state, _ := node.Attrs["state"].(string); state == adfTaskDone {
+			sb.WriteString("- [x] ")
+		} else {
+			sb.WriteString("- [ ] ")
+		}
+	}
+
+	for _, child := range node.Content {
+		writeADF(sb, child)
+	}
+
+	switch node.Type {
+	case "paragraph", "heading", "listItem", "taskItem", "codeBlock", "blockquote", "rule", "mediaSingle", "mediaGroup":
+		sb.WriteString("\n")
+	}
+}
+
+// mediaName returns the name a media node is referenced by: its alt text, which Jira
+// sets to the attachment's file name, or its media ID when it has none.
+func mediaName(node adfNode) string {
+	for _, attr := range []string{"alt", "id"} {
+		if name, _ := node.Attrs[attr].(string); strings.TrimSpace(name) != "" {
+			return strings.TrimSpace(name)
+		}
+	}
+	return ""
+}
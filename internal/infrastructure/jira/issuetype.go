@@ -0,0 +1,167 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// typeChange is a change of a ticket's issue type as Jira allows it: the type it changes
+// to and the fields that type requires a value for that the ticket has none for.
+type typeChange struct {
+	target  issueTypeInfo
+	missing []domain.RequiredField
+}
+
+// MissingFieldsForType returns the fields a ticket needs values for before its issue
+// type can change to issueType: the fields the type's create screen requires that have
+// no default and no value on the ticket. None are returned when the ticket already has
+// the type.
+//
+// Jira's REST API only changes an issue type to one of the same level, so a Story can
+// become a Task but a sub-task cannot become a Story, nor the other way round; such
+// changes need Jira's Move and return ErrInvalidInput. Returns ErrInvalidFieldValue if
+// the project has no issue type issueType.
+func (c *Client) MissingFieldsForType(ctx context.Context, key, issueType string) ([]domain.RequiredField, error) {
+	change, err := c.planTypeChange(ctx, key, issueType)
+	if err != nil {
+		return nil, err
+	}
+	return change.missing, nil
+}
+
+// ChangeIssueType changes a ticket's issue type to issueType, setting the fields the
+// new type requires from edits (see MissingFieldsForType and FieldEdit) in the same
+// edit. Returns a *domain.MissingFieldsError, without writing anything, when edits
+// leave a required field without a value, and the errors of MissingFieldsForType.
+func (c *Client) ChangeIssueType(ctx context.Context, key, issueType string, edits []FieldEdit) error {
+	change, err := c.planTypeChange(ctx, key, issueType)
+	if err != nil {
+		return err
+	}
+	if err := change.check(key, edits); err != nil {
+		return err
+	}
+
+	req, err := c.newFieldEditRequest(ctx, key, edits)
+	if err != nil {
+		return err
+	}
+	if req.Fields == nil {
+		req.Fields = make(map[string]interface{})
+	}
+	req.Fields["issuetype"] = map[string]string{"id": change.target.ID}
+
+	return c.doRequest(ctx, http.MethodPut, "/rest/api/3/issue/"+url.PathEscape(key), req, nil)
+}
+
+// check returns a *domain.MissingFieldsError naming the required fields edits do not set.
+func (t typeChange) check(key string, edits []FieldEdit) error {
+	var missing []domain.RequiredField
+	for _, field := range t.missing {
+		set := false
+		for _, edit := range edits {
+			set = set || (edit.FieldID == field.ID && !edit.Value.IsZero())
+		}
+		if !set {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	ticketKey, _ := domain.NewTicketKey(key)
+	return &domain.MissingFieldsError{Key: ticketKey, IssueType: t.target.Name, Fields: missing}
+}
+
+// planTypeChange finds the issue type a ticket changes to and the required fields it
+// has no value for (see MissingFieldsForType).
+func (c *Client) planTypeChange(ctx context.Context, key, issueType string) (typeChange, error) {
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return typeChange{}, err
+	}
+	projectKey := ticketKey.ProjectKey()
+
+	var project projectMetadataResponse
+	if err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(projectKey), nil, &project); err != nil {
+		return typeChange{}, err
+	}
+	target, ok := findIssueType(project.IssueTypes, issueType)
+	if !ok {
+		names := make([]string, 0, len(project.IssueTypes))
+		for _, t := range project.IssueTypes {
+			names = append(names, t.Name)
+		}
+		return typeChange{}, fmt.Errorf("%w: %s has no issue type %q (types: %s)",
+			domain.ErrInvalidFieldValue, projectKey, issueType, strings.Join(names, ", "))
+	}
+
+	fields, err := c.createMetaFields(ctx, projectKey, target.ID)
+	if err != nil {
+		return typeChange{}, err
+	}
+	var required []createMetaField
+	for _, field := range fields {
+		if field.Required && !field.HasDefaultValue {
+			required = append(required, field)
+		}
+	}
+
+	ids := []string{"issuetype"}
+	for _, field := range required {
+		ids = append(ids, field.FieldID)
+	}
+	var current struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "?fields=" + url.QueryEscape(strings.Join(ids, ","))
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &current); err != nil {
+		return typeChange{}, err
+	}
+	var from issueTypeInfo
+	if err := json.Unmarshal(current.Fields["issuetype"], &from); err != nil {
+		return typeChange{}, fmt.Errorf("failed to decode the issue type of %s: %w", key, err)
+	}
+
+	change := typeChange{target: target}
+	if strings.EqualFold(from.Name, target.Name) {
+		return change, nil
+	}
+	if from.Subtask != target.Subtask {
+		return typeChange{}, fmt.Errorf("%w: Jira's API cannot change %s from %s to %s, since only one of them is a sub-task type; use Move in Jira instead",
+			domain.ErrInvalidInput, key, from.Name, target.Name)
+	}
+	for _, field := range required {
+		if isEmptyJSON(current.Fields[field.FieldID]) {
+			change.missing = append(change.missing, domain.RequiredField{ID: field.FieldID, Name: field.Name})
+		}
+	}
+	return change, nil
+}
+
+// findIssueType returns the issue type of types named name, ignoring case.
+func findIssueType(types []issueTypeInfo, name string) (issueTypeInfo, bool) {
+	name = strings.TrimSpace(name)
+	for _, t := range types {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	return issueTypeInfo{}, false
+}
+
+// isEmptyJSON reports whether a field value from Jira is unset: missing, null, or an
+// empty string, list, or object.
+func isEmptyJSON(value json.RawMessage) bool {
+	switch strings.TrimSpace(string(value)) {
+	case "", "null", `""`, "[]", "{}":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// typeServer serves the issue of editServer (a Bug) in a project where Stories require
+// story points.
+type typeServer struct {
+	editServer
+}
+
+func (s *typeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/rest/api/3/project/JMD":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"key": "JMD",
+			"issueTypes": []map[string]interface{}{
+				{"id": "1", "name": "Bug"},
+				{"id": "2", "name": "Story"},
+				{"id": "3", "name": "Task"},
+				{"id": "4", "name": "Sub-task", "subtask": true},
+			},
+		})
+	case strings.HasPrefix(r.URL.Path, "/rest/api/3/issue/createmeta/JMD/issuetypes/"):
+		fields := []map[string]interface{}{
+			{"fieldId": "summary", "name": "Summary", "required": true},
+			{"fieldId": "priority", "name": "Priority", "required": true, "hasDefaultValue": true},
+		}
+		if strings.HasSuffix(r.URL.Path, "/2") {
+			fields = append(fields, map[string]interface{}{"fieldId": "customfield_10016", "name": "Story Points", "required": true})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"startAt": 0, "total": len(fields), "fields": fields})
+	case r.URL.Path == "/rest/api/3/field":
+		json.NewEncoder(w).Encode([]FieldDefinition{
+			{ID: "customfield_10016", Name: "Story Points", Custom: true, Schema: FieldSchema{Type: SchemaNumber}},
+		})
+	default:
+		s.editServer.ServeHTTP(w, r)
+	}
+}
+
+func TestClient_MissingFieldsForType(t *testing.T) {
+	server := httptest.NewServer(&typeServer{})
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+
+	missing, err := client.MissingFieldsForType(ctx, "JMD-1", "story")
+	if err != nil {
+		t.Fatalf("MissingFieldsForType(Story) failed: %v", err)
+	}
+	if len(missing) != 1 || missing[0] != (domain.RequiredField{ID: "customfield_10016", Name: "Story Points"}) {
+		t.Errorf("MissingFieldsForType(Story) = %v, want Story Points", missing)
+	}
+
+	if missing, err := client.MissingFieldsForType(ctx, "JMD-1", "Task"); err != nil || len(missing) != 0 {
+		t.Errorf("MissingFieldsForType(Task) = %v, %v, want none", missing, err)
+	}
+	if _, err := client.MissingFieldsForType(ctx, "JMD-1", "Sub-task"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("MissingFieldsForType(Sub-task) error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := client.MissingFieldsForType(ctx, "JMD-1", "Epic"); !errors.Is(err, domain.ErrInvalidFieldValue) {
+		t.Errorf("MissingFieldsForType(Epic) error = %v, want ErrInvalidFieldValue", err)
+	}
+}
+
+func TestClient_ChangeIssueType(t *testing.T) {
+	state := &typeServer{editServer{updated: time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)}}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+
+	var missing *domain.MissingFieldsError
+	if err := client.ChangeIssueType(ctx, "JMD-1", "Story", nil); !errors.As(err, &missing) {
+		t.Fatalf("ChangeIssueType without story points error = %v, want MissingFieldsError", err)
+	}
+	if len(state.edits) != 0 {
+		t.Fatalf("ChangeIssueType wrote %d edits without the required fields", len(state.edits))
+	}
+
+	edits := []FieldEdit{{FieldID: "customfield_10016", Value: domain.NewFieldValue("5")}}
+	if err := client.ChangeIssueType(ctx, "JMD-1", "Story", edits); err != nil {
+		t.Fatalf("ChangeIssueType failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(state.edits))
+	}
+	got, _ := json.Marshal(state.edits[0].Fields)
+	if want := `{"customfield_10016":5,"issuetype":{"id":"2"}}`; string(got) != want {
+		t.Errorf("edit fields = %s, want %s", got, want)
+	}
+}
+
+func TestClient_UpdateTicket_IssueType(t *testing.T) {
+	state := &typeServer{editServer{updated: time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)}}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+
+	ticket, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket failed: %v", err)
+	}
+
+	// A Story needs story points the ticket does not have, so nothing is written
+	ticket.IssueType = "Story"
+	var missing *domain.MissingFieldsError
+	if _, err := client.UpdateTicket(ctx, ticket); !errors.As(err, &missing) {
+		t.Fatalf("UpdateTicket to Story error = %v, want MissingFieldsError", err)
+	}
+	if len(state.edits) != 0 {
+		t.Fatalf("UpdateTicket wrote %d edits without the required fields", len(state.edits))
+	}
+
+	ticket.IssueType = "Task"
+	if _, err := client.UpdateTicket(ctx, ticket); err != nil {
+		t.Fatalf("UpdateTicket to Task failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(state.edits))
+	}
+	got, _ := json.Marshal(state.edits[0].Fields)
+	if want := `{"issuetype":{"name":"Task"}}`; string(got) != want {
+		t.Errorf("edit fields = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,228 @@
+package jiratest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// jqlQuery is a parsed JQL query: clauses that must all match, and a sort order.
+type jqlQuery struct {
+	clauses []jqlClause
+	orderBy string
+	desc    bool
+}
+
+// jqlClause is one condition of a query.
+type jqlClause struct {
+	field  string
+	op     string
+	values []string
+}
+
+var (
+	// andPattern separates the clauses of a query
+	andPattern = regexp.MustCompile(`(?i)\s+AND\s+`)
+
+	// orderByPattern splits off the ORDER BY part of a query
+	orderByPattern = regexp.MustCompile(`(?i)\s*\bORDER\s+BY\s+`)
+
+	// clausePattern matches field, operator, and value of a clause
+	clausePattern = regexp.MustCompile(`(?i)^(\w+)\s*(=|!=|>=|<=|>|<|\bin\b|\bnot\s+in\b)\s*(.+)$`)
+)
+
+// jqlTimeLayouts are the date formats JQL accepts in comparisons.
+var jqlTimeLayouts = []string{"2006-01-02 15:04", "2006/01/02 15:04", "2006-01-02", "2006/01/02"}
+
+// parseJQL parses the subset of JQL the fake evaluates: clauses joined by AND that
+// compare project, key, status, issuetype, priority, assignee, or labels with =, !=, in,
+// or not in, and created or updated with >, >=, <, or <=, optionally followed by ORDER BY
+// key, created, or updated. Anything else is an error, so tests notice when they rely
+// on JQL the fake cannot evaluate.
+func parseJQL(jql string) (*jqlQuery, error) {
+	query := &jqlQuery{}
+
+	parts := orderByPattern.Split(strings.TrimSpace(jql), 2)
+	if len(parts) == 2 {
+		fields := strings.Fields(parts[1])
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("jiratest cannot evaluate ORDER BY %s", parts[1])
+		}
+		query.orderBy = strings.ToLower(fields[0])
+		switch query.orderBy {
+		case "key", "created", "updated":
+		default:
+			return nil, fmt.Errorf("jiratest cannot order by %s", fields[0])
+		}
+		if len(fields) == 2 {
+			query.desc = strings.EqualFold(fields[1], "DESC")
+		}
+	}
+
+	if strings.TrimSpace(parts[0]) == "" {
+		return query, nil
+	}
+	for _, text := range andPattern.Split(parts[0], -1) {
+		clause, err := parseClause(strings.TrimSpace(text))
+		if err != nil {
+			return nil, err
+		}
+		query.clauses = append(query.clauses, clause)
+	}
+	return query, nil
+}
+
+// parseClause parses one comparison of a query.
+func parseClause(text string) (jqlClause, error) {
+	m := clausePattern.FindStringSubmatch(text)
+	if m == nil {
+		return jqlClause{}, fmt.Errorf("jiratest cannot evaluate the JQL clause %q", text)
+	}
+
+	clause := jqlClause{
+		field: strings.ToLower(m[1]),
+		op:    strings.Join(strings.Fields(strings.ToLower(m[2])), " "),
+	}
+	value := strings.TrimSpace(m[3])
+	if clause.op == "in" || clause.op == "not in" {
+		if !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+			return jqlClause{}, fmt.Errorf("jiratest cannot evaluate the JQL clause %q", text)
+		}
+		for _, v := range strings.Split(value[1:len(value)-1], ",") {
+			clause.values = append(clause.values, unquote(v))
+		}
+	} else {
+		clause.values = []string{unquote(value)}
+	}
+
+	switch clause.field {
+	case "project", "key", "issuekey", "status", "issuetype", "type", "priority", "assignee", "labels":
+		if clause.op != "=" && clause.op != "!=" && clause.op != "in" && clause.op != "not in" {
+			return jqlClause{}, fmt.Errorf("jiratest cannot compare %s with %s", clause.field, clause.op)
+		}
+	case "created", "updated":
+		if clause.op == "in" || clause.op == "not in" {
+			return jqlClause{}, fmt.Errorf("jiratest cannot compare %s with %s", clause.field, clause.op)
+		}
+		if _, err := parseJQLTime(clause.values[0]); err != nil {
+			return jqlClause{}, err
+		}
+	default:
+		return jqlClause{}, fmt.Errorf("jiratest cannot evaluate the JQL field %s", m[1])
+	}
+	return clause, nil
+}
+
+// unquote trims whitespace and surrounding quotes from a JQL value.
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// parseJQLTime parses a date or date and time in one of the formats JQL accepts.
+func parseJQLTime(value string) (time.Time, error) {
+	for _, layout := range jqlTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("jiratest cannot parse the date %q", value)
+}
+
+// apply returns the issues matching the query, in its order.
+func (q *jqlQuery) apply(issues []*Issue) []*Issue {
+	matches := make([]*Issue, 0, len(issues))
+	for _, issue := range issues {
+		if q.matches(issue) {
+			matches = append(matches, issue)
+		}
+	}
+
+	if q.orderBy != "" && q.orderBy != "key" {
+		sort.SliceStable(matches, func(i, j int) bool {
+			a, b := matches[i].Updated, matches[j].Updated
+			if q.orderBy == "created" {
+				a, b = matches[i].Created, matches[j].Created
+			}
+			return a.Before(b)
+		})
+	}
+	if q.desc {
+		for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+			matches[i], matches[j] = matches[j], matches[i]
+		}
+	}
+	return matches
+}
+
+// matches reports whether an issue matches every clause.
+func (q *jqlQuery) matches(issue *Issue) bool {
+	for _, clause := range q.clauses {
+		if !clause.matches(issue) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether an issue matches the clause.
+func (c jqlClause) matches(issue *Issue) bool {
+	switch c.field {
+	case "created", "updated":
+		t := issue.Updated
+		if c.field == "created" {
+			t = issue.Created
+		}
+		bound, _ := parseJQLTime(c.values[0])
+		switch c.op {
+		case ">":
+			return t.After(bound)
+		case ">=":
+			return !t.Before(bound)
+		case "<":
+			return t.Before(bound)
+		case "<=":
+			return !t.After(bound)
+		case "=":
+			return t.Equal(bound)
+		default:
+			return !t.Equal(bound)
+		}
+	}
+
+	var actual []string
+	switch c.field {
+	case "project":
+		actual = []string{issue.ProjectKey()}
+	case "key", "issuekey":
+		actual = []string{issue.Key}
+	case "status":
+		actual = []string{issue.Status}
+	case "issuetype", "type":
+		actual = []string{issue.IssueType}
+	case "priority":
+		actual = []string{issue.Priority}
+	case "assignee":
+		actual = []string{issue.Assignee}
+	case "labels":
+		actual = issue.Labels
+	}
+
+	found := false
+	for _, a := range actual {
+		for _, v := range c.values {
+			if strings.EqualFold(a, v) {
+				found = true
+			}
+		}
+	}
+	if c.op == "!=" || c.op == "not in" {
+		return !found
+	}
+	return found
+}
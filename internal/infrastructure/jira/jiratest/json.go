@@ -0,0 +1,263 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// namedJSON is a Jira field object identified by name (status, issue type, priority).
+type namedJSON struct {
+	Name string `json:"name"`
+}
+
+// userJSON is a Jira user reference.
+type userJSON struct {
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+// accountJSON is a user as the user directory endpoints return it.
+type accountJSON struct {
+	AccountID    string `json:"accountId"`
+	AccountType  string `json:"accountType"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	Active       bool   `json:"active"`
+}
+
+// issueJSON is an issue as the REST API returns it.
+type issueJSON struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string     `json:"summary"`
+		Description *adfNode   `json:"description"`
+		Status      *namedJSON `json:"status,omitempty"`
+		IssueType   *namedJSON `json:"issuetype,omitempty"`
+		Priority    *namedJSON `json:"priority,omitempty"`
+		Assignee    *userJSON  `json:"assignee"`
+		Reporter    *userJSON  `json:"reporter"`
+		Labels      []string   `json:"labels"`
+		Created     string     `json:"created"`
+		Updated     string     `json:"updated"`
+	} `json:"fields"`
+}
+
+// commentJSON is a comment as the REST API returns it.
+type commentJSON struct {
+	ID         string          `json:"id"`
+	Author     *userJSON       `json:"author"`
+	Body       *adfNode        `json:"body"`
+	Created    string          `json:"created"`
+	Updated    string          `json:"updated"`
+	Visibility *visibilityJSON `json:"visibility,omitempty"`
+
+	// JSDPublic is only returned, as false, for Service Management internal notes
+	JSDPublic *bool `json:"jsdPublic,omitempty"`
+}
+
+// visibilityJSON restricts a comment to a role or group.
+type visibilityJSON struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// changelogJSON is a changelog entry as the REST API returns it.
+type changelogJSON struct {
+	ID      string              `json:"id"`
+	Author  *userJSON           `json:"author"`
+	Created string              `json:"created"`
+	Items   []changelogItemJSON `json:"items"`
+}
+
+// changelogItemJSON is a field changed by a changelog entry.
+type changelogItemJSON struct {
+	Field      string `json:"field"`
+	FieldType  string `json:"fieldtype"`
+	FieldID    string `json:"fieldId"`
+	FromString string `json:"fromString"`
+	ToString   string `json:"toString"`
+}
+
+// toIssueJSON converts a stored issue to its REST representation.
+func toIssueJSON(issue *Issue) issueJSON {
+	var out issueJSON
+	out.ID = issue.ID
+	out.Key = issue.Key
+	out.Fields.Summary = issue.Summary
+	out.Fields.Description = textToADF(issue.Description)
+	out.Fields.Status = named(issue.Status)
+	out.Fields.IssueType = named(issue.IssueType)
+	out.Fields.Priority = named(issue.Priority)
+	out.Fields.Assignee = userRef(issue.Assignee)
+	out.Fields.Reporter = userRef(issue.Reporter)
+	out.Fields.Labels = append([]string{}, issue.Labels...)
+	out.Fields.Created = formatTime(issue.Created)
+	out.Fields.Updated = formatTime(issue.Updated)
+	return out
+}
+
+// toCommentJSON converts a stored comment to its REST representation.
+func toCommentJSON(comment Comment) commentJSON {
+	out := commentJSON{
+		ID:      comment.ID,
+		Author:  userRef(comment.Author),
+		Body:    textToADF(comment.Body),
+		Created: formatTime(comment.Created),
+		Updated: formatTime(comment.Created),
+	}
+	if comment.VisibilityType != "" {
+		out.Visibility = &visibilityJSON{Type: comment.VisibilityType, Value: comment.VisibilityValue}
+	}
+	if comment.Internal {
+		public := false
+		out.JSDPublic = &public
+	}
+	return out
+}
+
+// toChangelogJSON converts a status change to its REST representation.
+func toChangelogJSON(change StatusChange) changelogJSON {
+	return changelogJSON{
+		ID:      change.ID,
+		Author:  userRef(change.Author),
+		Created: formatTime(change.At),
+		Items: []changelogItemJSON{{
+			Field:      "status",
+			FieldType:  "jira",
+			FieldID:    "status",
+			FromString: change.From,
+			ToString:   change.To,
+		}},
+	}
+}
+
+// named returns a named field, or nil for an empty name.
+func named(name string) *namedJSON {
+	if name == "" {
+		return nil
+	}
+	return &namedJSON{Name: name}
+}
+
+// toAccountJSON converts a directory user to its REST representation.
+func toAccountJSON(user User) accountJSON {
+	return accountJSON{
+		AccountID:    user.AccountID,
+		AccountType:  "atlassian",
+		DisplayName:  user.DisplayName,
+		EmailAddress: user.Email,
+		Active:       user.Active,
+	}
+}
+
+// userRef returns a user reference: email addresses are shown as the email, anything
+// else as a display name. Empty names yield nil (unassigned).
+func userRef(name string) *userJSON {
+	if name == "" {
+		return nil
+	}
+	if strings.Contains(name, "@") {
+		return &userJSON{DisplayName: name, EmailAddress: name}
+	}
+	return &userJSON{DisplayName: name}
+}
+
+// formatTime formats a timestamp as Jira does, or "" for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(timeLayout)
+}
+
+// adfNode is a node of an Atlassian Document Format document.
+type adfNode struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Content []adfNode              `json:"content,omitempty"`
+}
+
+// textToADF converts plain text to an ADF document of one paragraph per blank-line
+// separated block, or nil for blank text.
+func textToADF(text string) *adfNode {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	doc := &adfNode{Type: "doc", Version: 1}
+	for _, block := range strings.Split(text, "\n\n") {
+		paragraph := adfNode{Type: "paragraph"}
+		for i, line := range strings.Split(strings.Trim(block, "\n"), "\n") {
+			if i > 0 {
+				paragraph.Content = append(paragraph.Content, adfNode{Type: "hardBreak"})
+			}
+			if line != "" {
+				paragraph.Content = append(paragraph.Content, adfNode{Type: "text", Text: line})
+			}
+		}
+		doc.Content = append(doc.Content, paragraph)
+	}
+	return doc
+}
+
+// adfText flattens an ADF document (or a plain JSON string) to text, with blank lines
+// between paragraphs.
+func adfText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+
+	var doc adfNode
+	if json.Unmarshal(raw, &doc) != nil {
+		return ""
+	}
+
+	var paragraphs []string
+	for _, block := range doc.Content {
+		var sb strings.Builder
+		writeADF(&sb, block)
+		paragraphs = append(paragraphs, sb.String())
+	}
+	return strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+}
+
+// writeADF appends the text of an ADF node and its children. Task lists become
+// markdown task list items, one per line.
+func writeADF(sb *strings.Builder, node adfNode) {
+	switch node.Type {
+	case "text":
+		sb.WriteString(node.Text)
+	case "hardBreak":
+		sb.WriteString("\n")
+	case "taskList":
+		for i, item := range node.Content {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			writeADF(sb, item)
+		}
+	case "taskItem":
+		if node.Attrs["state"] == "DONE" {
+			sb.WriteString("- [x] ")
+		} else {
+			sb.WriteString("- [ ] ")
+		}
+		for _, child := range node.Content {
+			writeADF(sb, child)
+		}
+	default:
+		for _, child := range node.Content {
+			writeADF(sb, child)
+		}
+	}
+}
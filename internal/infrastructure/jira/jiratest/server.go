@@ -0,0 +1,1141 @@
+// Package jiratest provides a fake Jira Cloud server for testing code that talks to Jira
+// through the jira package, in the spirit of net/http/httptest.
+//
+// The fake keeps issues and their comments in memory and implements the parts of the
+// REST API jiramd uses: fetching, creating, and editing issues, listing and adding
+// comments, searching with token pagination, moving issues through a workflow and
+// listing their status changelog, saved filters, the user directory, project metadata
+// (issue types, components, statuses, priorities, and select field options), the fields
+// of issues' edit screens, and the account, project, and permission lookups. It can require credentials and simulate
+// rate limiting.
+//
+//	server := jiratest.NewServer()
+//	defer server.Close()
+//
+//	server.AddIssue(jiratest.Issue{Key: "JMD-1", Summary: "First"})
+//	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default account of the fake; credentials are only checked after RequireAuth.
+const (
+	Email       = "tester@example.com"
+	Token       = "test-token"
+	DisplayName = "Test User"
+)
+
+// DefaultPageSize is the most issues returned per search page unless PageSize is changed.
+const DefaultPageSize = 50
+
+// DefaultWorkflow is the statuses issues move between unless SetWorkflow is called.
+var DefaultWorkflow = []string{"To Do", "In Progress", "Done"}
+
+// DefaultIssueTypes are the issue types of every project, besides those of its issues.
+var DefaultIssueTypes = []string{"Bug", "Story", "Task"}
+
+// DefaultPriorities are the priorities the server lists.
+var DefaultPriorities = []string{"Highest", "High", "Medium", "Low", "Lowest"}
+
+// timeLayout is the timestamp format of Jira issue fields.
+const timeLayout = "2006-01-02T15:04:05.000-0700"
+
+// Issue is an issue stored by the fake.
+type Issue struct {
+	// ID is Jira's numeric issue ID, assigned when the issue is added if empty
+	ID string
+
+	Key         string
+	Summary     string
+	Description string
+	Status      string
+	IssueType   string
+	Priority    string
+	Assignee    string
+	Reporter    string
+	Labels      []string
+
+	// Created and Updated default to the server's clock when the issue is added; every
+	// edit moves Updated forward
+	Created time.Time
+	Updated time.Time
+
+	Comments []Comment
+
+	// History is the issue's status changelog, oldest first; transitions append to it
+	History []StatusChange
+}
+
+// ProjectKey returns the project part of the issue key.
+func (i Issue) ProjectKey() string {
+	project, _, _ := strings.Cut(i.Key, "-")
+	return project
+}
+
+// Comment is a comment on an issue.
+type Comment struct {
+	ID      string
+	Author  string
+	Body    string
+	Created time.Time
+
+	// VisibilityType ("role" or "group") and VisibilityValue restrict who can see the
+	// comment; both are empty for public comments
+	VisibilityType  string
+	VisibilityValue string
+
+	// Internal marks a Service Management internal note
+	Internal bool
+}
+
+// StatusChange is an entry of an issue's changelog moving it between statuses.
+type StatusChange struct {
+	ID       string
+	From, To string
+	Author   string
+	At       time.Time
+}
+
+// User is an account in the fake's user directory.
+type User struct {
+	AccountID   string
+	DisplayName string
+
+	// Email is returned only when set, as for profiles that make it visible
+	Email string
+
+	Active bool
+}
+
+// Filter is a saved filter; its JQL is limited to what search supports.
+type Filter struct {
+	ID   string
+	Name string
+	JQL  string
+}
+
+// Request is a request received by the fake.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+// Server is a fake Jira Cloud server. Its methods are safe for concurrent use.
+type Server struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	issues   map[string]*Issue
+	projects map[string]string
+	filters  map[string]Filter
+	users    map[string]User
+	nextID   int
+
+	// components are the components of each project
+	components map[string][]string
+
+	// fieldOptions are the options of each project's select custom fields, by field ID
+	fieldOptions map[string]map[string][]string
+
+	// assignable are the account IDs of the users assignable in each project (every
+	// active user for projects not listed)
+	assignable map[string][]string
+
+	// hiddenFields are the fields missing from the edit screen of each project's issues
+	hiddenFields map[string][]string
+
+	lastTime time.Time
+	requests []Request
+
+	// authEmail and authToken are the required credentials (empty for none)
+	authEmail string
+	authToken string
+
+	// rateLimited is how many of the next requests are rejected with 429
+	rateLimited int
+	retryAfter  time.Duration
+
+	// pageSize caps the issues per search page
+	pageSize int
+
+	// workflow is the statuses issues can be transitioned to, from any other status
+	workflow []string
+
+	// now is the clock for created and updated timestamps
+	now func() time.Time
+}
+
+// NewServer starts a fake Jira server with no issues. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		issues:   make(map[string]*Issue),
+		projects: make(map[string]string),
+		filters:  make(map[string]Filter),
+		users:    make(map[string]User),
+
+		components:   make(map[string][]string),
+		fieldOptions: make(map[string]map[string][]string),
+		assignable:   make(map[string][]string),
+		hiddenFields: make(map[string][]string),
+		nextID:       10000,
+		pageSize:     DefaultPageSize,
+		workflow:     DefaultWorkflow,
+		now:          time.Now,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the base URL of the server, for jira.NewClient.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetClock replaces the clock used for created and updated timestamps.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// SetPageSize sets the most issues returned per search page (DefaultPageSize when n <= 0).
+func (s *Server) SetPageSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n <= 0 {
+		n = DefaultPageSize
+	}
+	s.pageSize = n
+}
+
+// SetWorkflow sets the statuses issues can be transitioned to; every status can be
+// reached from every other one.
+func (s *Server) SetWorkflow(statuses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workflow = append([]string(nil), statuses...)
+}
+
+// RequireAuth makes the server answer 401 to requests without these basic credentials.
+func (s *Server) RequireAuth(email, token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authEmail = email
+	s.authToken = token
+}
+
+// RateLimit makes the server reject the next n requests with 429 Too Many Requests,
+// asking clients to retry after retryAfter (whole seconds).
+func (s *Server) RateLimit(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimited = n
+	s.retryAfter = retryAfter
+}
+
+// AddProject makes a project visible; projects of added issues are visible too.
+func (s *Server) AddProject(key, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.projects[key] = name
+}
+
+// SetComponents sets the components of a project.
+func (s *Server) SetComponents(projectKey string, components ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.components[projectKey] = append([]string(nil), components...)
+}
+
+// SetFieldOptions sets the options of a select custom field (e.g. customfield_10001)
+// on the create screen of every issue type of a project.
+func (s *Server) SetFieldOptions(projectKey, fieldID string, options ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fieldOptions[projectKey] == nil {
+		s.fieldOptions[projectKey] = make(map[string][]string)
+	}
+	s.fieldOptions[projectKey][fieldID] = append([]string(nil), options...)
+}
+
+// SetHiddenFields removes fields (e.g. priority) from the edit screen of a project's
+// issues: they are left out of the edit metadata and edits setting them are rejected.
+func (s *Server) SetHiddenFields(projectKey string, fields ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hiddenFields[projectKey] = append([]string(nil), fields...)
+}
+
+// AddFilter stores a saved filter, replacing any with the same ID.
+func (s *Server) AddFilter(filter Filter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filters[filter.ID] = filter
+}
+
+// AddUser adds a user to the directory, replacing any with the same account ID.
+func (s *Server) AddUser(user User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.AccountID] = user
+}
+
+// SetAssignable restricts the users assignable in a project to the active ones among
+// accountIDs. By default every active user is assignable.
+func (s *Server) SetAssignable(projectKey string, accountIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assignable[projectKey] = append([]string(nil), accountIDs...)
+}
+
+// AddIssue stores an issue, replacing any with the same key.
+func (s *Server) AddIssue(issue Issue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addIssue(issue)
+}
+
+// addIssue stores an issue, filling in its ID and timestamps. Callers hold mu.
+func (s *Server) addIssue(issue Issue) *Issue {
+	if issue.ID == "" {
+		s.nextID++
+		issue.ID = strconv.Itoa(s.nextID)
+	}
+	if issue.Created.IsZero() {
+		issue.Created = s.tick()
+	}
+	if issue.Updated.IsZero() {
+		issue.Updated = issue.Created
+	}
+	issue.Labels = append([]string(nil), issue.Labels...)
+	issue.Comments = append([]Comment(nil), issue.Comments...)
+	issue.History = append([]StatusChange(nil), issue.History...)
+
+	if _, ok := s.projects[issue.ProjectKey()]; !ok {
+		s.projects[issue.ProjectKey()] = issue.ProjectKey()
+	}
+	s.issues[issue.Key] = &issue
+	return &issue
+}
+
+// Issue returns a copy of the stored issue with the given key.
+func (s *Server) Issue(key string) (Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issue, ok := s.issues[key]
+	if !ok {
+		return Issue{}, false
+	}
+	return copyIssue(issue), true
+}
+
+// Issues returns copies of all stored issues, ordered by key.
+func (s *Server) Issues() []Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issues := make([]Issue, 0, len(s.issues))
+	for _, issue := range s.sortedIssues() {
+		issues = append(issues, copyIssue(issue))
+	}
+	return issues
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// copyIssue returns a copy of issue that shares no slices with it.
+func copyIssue(issue *Issue) Issue {
+	c := *issue
+	c.Labels = append([]string(nil), issue.Labels...)
+	c.Comments = append([]Comment(nil), issue.Comments...)
+	c.History = append([]StatusChange(nil), issue.History...)
+	return c
+}
+
+// tick returns the current time at Jira's millisecond precision, always later than the
+// previous tick so every edit changes an issue's version. Callers hold mu.
+func (s *Server) tick() time.Time {
+	t := s.now().Truncate(time.Millisecond)
+	if !t.After(s.lastTime) {
+		t = s.lastTime.Add(time.Millisecond)
+	}
+	s.lastTime = t
+	return t
+}
+
+// sortedIssues returns the stored issues ordered by project and issue number. Callers
+// hold mu.
+func (s *Server) sortedIssues() []*Issue {
+	issues := make([]*Issue, 0, len(s.issues))
+	for _, issue := range s.issues {
+		issues = append(issues, issue)
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		pi, ni := splitKey(issues[i].Key)
+		pj, nj := splitKey(issues[j].Key)
+		if pi != pj {
+			return pi < pj
+		}
+		return ni < nj
+	})
+	return issues
+}
+
+// splitKey splits an issue key into its project and number.
+func splitKey(key string) (string, int) {
+	project, number, _ := strings.Cut(key, "-")
+	n, _ := strconv.Atoi(number)
+	return project, n
+}
+
+// Routes with a path parameter.
+var (
+	issuePath    = regexp.MustCompile(`^/rest/api/3/issue/([^/]+)$`)
+	commentPath  = regexp.MustCompile(`^/rest/api/3/issue/([^/]+)/comment$`)
+	editMetaPath = regexp.MustCompile(`^/rest/api/3/issue/([^/]+)/editmeta$`)
+	projectPath  = regexp.MustCompile(`^/rest/api/3/project/([^/]+)$`)
+	filterPath   = regexp.MustCompile(`^/rest/api/3/filter/([^/]+)$`)
+
+	projectStatusesPath = regexp.MustCompile(`^/rest/api/3/project/([^/]+)/statuses$`)
+	createMetaPath      = regexp.MustCompile(`^/rest/api/3/issue/createmeta/([^/]+)/issuetypes/([^/]+)$`)
+
+	transitionPath = regexp.MustCompile(`^/rest/api/3/issue/([^/]+)/transitions$`)
+	changelogPath  = regexp.MustCompile(`^/rest/api/3/issue/([^/]+)/changelog$`)
+)
+
+// serveHTTP records the request, applies authentication and rate limiting, and routes it.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery})
+
+	if s.rateLimited > 0 {
+		s.rateLimited--
+		w.Header().Set("Retry-After", strconv.Itoa(int(s.retryAfter/time.Second)))
+		writeError(w, http.StatusTooManyRequests, "Rate limit exceeded.")
+		return
+	}
+
+	if r.URL.Path == "/status" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if s.authEmail != "" || s.authToken != "" {
+		if email, token, ok := r.BasicAuth(); !ok || email != s.authEmail || token != s.authToken {
+			writeError(w, http.StatusUnauthorized, "Client must be authenticated to access this resource.")
+			return
+		}
+	}
+
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodPost && path == "/rest/api/3/search/jql":
+		s.search(w, r)
+	case r.Method == http.MethodPost && path == "/rest/api/3/issue/bulk":
+		s.bulkCreate(w, r)
+	case r.Method == http.MethodPost && path == "/rest/api/3/issue":
+		s.create(w, r)
+	case r.Method == http.MethodGet && path == "/rest/api/3/myself":
+		writeJSON(w, http.StatusOK, map[string]string{"accountId": "fake-account", "displayName": DisplayName, "emailAddress": Email})
+	case r.Method == http.MethodGet && path == "/rest/api/3/user/search":
+		s.searchUsers(w, r)
+	case r.Method == http.MethodGet && path == "/rest/api/3/user/assignable/search":
+		s.searchAssignableUsers(w, r)
+	case r.Method == http.MethodGet && path == "/rest/api/3/user":
+		s.getUser(w, r)
+	case r.Method == http.MethodGet && path == "/rest/api/3/mypermissions":
+		s.myPermissions(w, r)
+	case r.Method == http.MethodGet && path == "/rest/api/3/priority":
+		s.listPriorities(w)
+	case r.Method == http.MethodGet && projectStatusesPath.MatchString(path):
+		s.listProjectStatuses(w, projectStatusesPath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodGet && createMetaPath.MatchString(path):
+		match := createMetaPath.FindStringSubmatch(path)
+		s.createMeta(w, match[1], match[2])
+	case r.Method == http.MethodGet && projectPath.MatchString(path):
+		s.getProject(w, projectPath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodGet && filterPath.MatchString(path):
+		s.getFilter(w, filterPath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodGet && issuePath.MatchString(path):
+		s.getIssue(w, issuePath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodPut && issuePath.MatchString(path):
+		s.editIssue(w, r, issuePath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodGet && editMetaPath.MatchString(path):
+		s.editMeta(w, editMetaPath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodGet && commentPath.MatchString(path):
+		s.listComments(w, r, commentPath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodPost && commentPath.MatchString(path):
+		s.addComment(w, r, commentPath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodGet && transitionPath.MatchString(path):
+		s.listTransitions(w, transitionPath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodPost && transitionPath.MatchString(path):
+		s.transition(w, r, transitionPath.FindStringSubmatch(path)[1])
+	case r.Method == http.MethodGet && changelogPath.MatchString(path):
+		s.listChangelog(w, r, changelogPath.FindStringSubmatch(path)[1])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("jiratest does not implement %s %s", r.Method, path))
+	}
+}
+
+// search answers POST /rest/api/3/search/jql. The next page token is the offset of the
+// page's first issue.
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		JQL           string `json:"jql"`
+		MaxResults    int    `json:"maxResults"`
+		NextPageToken string `json:"nextPageToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+
+	query, err := parseJQL(req.JQL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	offset := 0
+	if req.NextPageToken != "" {
+		if offset, err = strconv.Atoi(req.NextPageToken); err != nil || offset < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid nextPageToken.")
+			return
+		}
+	}
+	limit := s.pageSize
+	if req.MaxResults > 0 && req.MaxResults < limit {
+		limit = req.MaxResults
+	}
+
+	matches := query.apply(s.sortedIssues())
+	end := min(offset+limit, len(matches))
+	page := make([]issueJSON, 0, max(end-offset, 0))
+	for _, issue := range matches[min(offset, len(matches)):end] {
+		page = append(page, toIssueJSON(issue))
+	}
+
+	resp := map[string]interface{}{"issues": page, "isLast": end >= len(matches)}
+	if end < len(matches) {
+		resp["nextPageToken"] = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// createFields are the fields of an issue in create requests.
+type createFields struct {
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+	Summary     string          `json:"summary"`
+	IssueType   *namedJSON      `json:"issuetype"`
+	Description json.RawMessage `json:"description"`
+	Priority    *namedJSON      `json:"priority"`
+	Labels      []string        `json:"labels"`
+}
+
+// createIssue validates fields and stores the new issue. Callers hold mu.
+func (s *Server) createIssue(fields createFields) (*Issue, map[string]string) {
+	errs := make(map[string]string)
+	if fields.Project.Key == "" {
+		errs["project"] = "Specify a valid project ID or key"
+	}
+	if strings.TrimSpace(fields.Summary) == "" {
+		errs["summary"] = "You must specify a summary of the issue."
+	}
+	if fields.IssueType == nil || fields.IssueType.Name == "" {
+		errs["issuetype"] = "Specify an issue type"
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	number := 0
+	for _, issue := range s.issues {
+		if project, n := splitKey(issue.Key); project == fields.Project.Key && n > number {
+			number = n
+		}
+	}
+
+	issue := Issue{
+		Key:         fmt.Sprintf("%s-%d", fields.Project.Key, number+1),
+		Summary:     fields.Summary,
+		Description: adfText(fields.Description),
+		Status:      "To Do",
+		IssueType:   fields.IssueType.Name,
+		Reporter:    Email,
+		Labels:      fields.Labels,
+	}
+	if fields.Priority != nil {
+		issue.Priority = fields.Priority.Name
+	}
+	return s.addIssue(issue), nil
+}
+
+// create answers POST /rest/api/3/issue.
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Fields createFields `json:"fields"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+
+	issue, errs := s.createIssue(req.Fields)
+	if errs != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{}, "errors": errs})
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": issue.ID, "key": issue.Key})
+}
+
+// bulkCreate answers POST /rest/api/3/issue/bulk, creating the valid issues and
+// reporting the invalid ones by index.
+func (s *Server) bulkCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		IssueUpdates []struct {
+			Fields createFields `json:"fields"`
+		} `json:"issueUpdates"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+
+	created := make([]map[string]string, 0, len(req.IssueUpdates))
+	failed := make([]map[string]interface{}, 0)
+	for i, update := range req.IssueUpdates {
+		issue, errs := s.createIssue(update.Fields)
+		if errs != nil {
+			failed = append(failed, map[string]interface{}{
+				"status":              http.StatusBadRequest,
+				"elementErrors":       map[string]interface{}{"errorMessages": []string{}, "errors": errs},
+				"failedElementNumber": i,
+			})
+			continue
+		}
+		created = append(created, map[string]string{"id": issue.ID, "key": issue.Key})
+	}
+
+	status := http.StatusCreated
+	if len(created) == 0 && len(failed) > 0 {
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, map[string]interface{}{"issues": created, "errors": failed})
+}
+
+// getIssue answers GET /rest/api/3/issue/{key}.
+func (s *Server) getIssue(w http.ResponseWriter, key string) {
+	issue, ok := s.issues[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	writeJSON(w, http.StatusOK, toIssueJSON(issue))
+}
+
+// editableFields are the fields editIssue can set.
+var editableFields = []string{"summary", "description", "issuetype", "priority", "labels"}
+
+// editMeta answers GET /rest/api/3/issue/{key}/editmeta with the fields of the issue's
+// edit screen.
+func (s *Server) editMeta(w http.ResponseWriter, key string) {
+	if _, ok := s.issues[key]; !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	project, _ := splitKey(key)
+	fields := make(map[string]interface{})
+	for _, field := range editableFields {
+		if !slices.Contains(s.hiddenFields[project], field) {
+			fields[field] = map[string]interface{}{"required": field == "summary", "key": field}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"fields": fields})
+}
+
+// editIssue answers PUT /rest/api/3/issue/{key}, applying the edited fields and moving
+// the issue's updated timestamp forward.
+func (s *Server) editIssue(w http.ResponseWriter, r *http.Request, key string) {
+	issue, ok := s.issues[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	var req struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+
+	edited := *issue
+	errs := make(map[string]string)
+	project, _ := splitKey(key)
+	for field, value := range req.Fields {
+		var err error
+		if slices.Contains(s.hiddenFields[project], field) {
+			errs[field] = fmt.Sprintf("Field '%s' cannot be set. It is not on the appropriate screen, or unknown.", field)
+			continue
+		}
+		switch field {
+		case "summary":
+			err = json.Unmarshal(value, &edited.Summary)
+		case "description":
+			edited.Description = adfText(value)
+		case "issuetype":
+			// Issue types are identified by name, which is also their ID
+			var ref struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			}
+			err = json.Unmarshal(value, &ref)
+			edited.IssueType = ref.Name
+			if edited.IssueType == "" {
+				edited.IssueType = ref.ID
+			}
+		case "priority":
+			var named namedJSON
+			err = json.Unmarshal(value, &named)
+			edited.Priority = named.Name
+		case "labels":
+			err = json.Unmarshal(value, &edited.Labels)
+		default:
+			errs[field] = fmt.Sprintf("Field '%s' cannot be set. It is not on the appropriate screen, or unknown.", field)
+		}
+		if err != nil {
+			errs[field] = "Invalid value."
+		}
+	}
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{}, "errors": errs})
+		return
+	}
+
+	edited.Updated = s.tick()
+	*issue = edited
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// transitionID returns the ID of the transition to the i-th workflow status.
+func transitionID(i int) string {
+	return strconv.Itoa((i+1)*10 + 1)
+}
+
+// listTransitions answers GET /rest/api/3/issue/{key}/transitions with a transition to
+// every workflow status but the issue's current one.
+func (s *Server) listTransitions(w http.ResponseWriter, key string) {
+	issue, ok := s.issues[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	transitions := make([]map[string]interface{}, 0, len(s.workflow))
+	for i, status := range s.workflow {
+		if status == issue.Status {
+			continue
+		}
+		transitions = append(transitions, map[string]interface{}{
+			"id":   transitionID(i),
+			"name": status,
+			"to":   namedJSON{Name: status},
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"transitions": transitions})
+}
+
+// transition answers POST /rest/api/3/issue/{key}/transitions, moving the issue to the
+// transition's status and its updated timestamp forward.
+func (s *Server) transition(w http.ResponseWriter, r *http.Request, key string) {
+	issue, ok := s.issues[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	var req struct {
+		Transition struct {
+			ID string `json:"id"`
+		} `json:"transition"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+
+	for i, status := range s.workflow {
+		if transitionID(i) == req.Transition.ID && status != issue.Status {
+			s.nextID++
+			issue.Updated = s.tick()
+			issue.History = append(issue.History, StatusChange{
+				ID:     strconv.Itoa(s.nextID),
+				From:   issue.Status,
+				To:     status,
+				Author: Email,
+				At:     issue.Updated,
+			})
+			issue.Status = status
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusBadRequest, "Transition id '"+req.Transition.ID+"' is not valid for this issue.")
+}
+
+// listChangelog answers GET /rest/api/3/issue/{key}/changelog with the issue's status
+// changes, paged by startAt and maxResults.
+func (s *Server) listChangelog(w http.ResponseWriter, r *http.Request, key string) {
+	issue, ok := s.issues[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+	maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+	if maxResults <= 0 || maxResults > s.pageSize {
+		maxResults = s.pageSize
+	}
+	startAt = min(max(startAt, 0), len(issue.History))
+	end := min(startAt+maxResults, len(issue.History))
+
+	values := make([]changelogJSON, 0, end-startAt)
+	for _, change := range issue.History[startAt:end] {
+		values = append(values, toChangelogJSON(change))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(issue.History),
+		"isLast":     end == len(issue.History),
+		"values":     values,
+	})
+}
+
+// listComments answers GET /rest/api/3/issue/{key}/comment, paged by startAt and
+// maxResults.
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request, key string) {
+	issue, ok := s.issues[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+	maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+	if maxResults <= 0 || maxResults > s.pageSize {
+		maxResults = s.pageSize
+	}
+	startAt = min(max(startAt, 0), len(issue.Comments))
+	end := min(startAt+maxResults, len(issue.Comments))
+
+	comments := make([]commentJSON, 0, end-startAt)
+	for _, comment := range issue.Comments[startAt:end] {
+		comments = append(comments, toCommentJSON(comment))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(issue.Comments),
+		"comments":   comments,
+	})
+}
+
+// addComment answers POST /rest/api/3/issue/{key}/comment.
+func (s *Server) addComment(w http.ResponseWriter, r *http.Request, key string) {
+	issue, ok := s.issues[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	var req struct {
+		Body       json.RawMessage `json:"body"`
+		Visibility *visibilityJSON `json:"visibility"`
+		Properties []struct {
+			Key   string `json:"key"`
+			Value struct {
+				Internal bool `json:"internal"`
+			} `json:"value"`
+		} `json:"properties"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	body := adfText(req.Body)
+	if body == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{}, "errors": map[string]string{"comment": "Comment body can not be empty!"}})
+		return
+	}
+
+	s.nextID++
+	comment := Comment{ID: strconv.Itoa(s.nextID), Author: Email, Body: body, Created: s.tick()}
+	if v := req.Visibility; v != nil {
+		if (v.Type != "role" && v.Type != "group") || v.Value == "" {
+			writeError(w, http.StatusBadRequest, "Visibility must be a role or group with a value.")
+			return
+		}
+		comment.VisibilityType, comment.VisibilityValue = v.Type, v.Value
+	}
+	for _, property := range req.Properties {
+		if property.Key == "sd.public.comment" {
+			comment.Internal = property.Value.Internal
+		}
+	}
+	issue.Comments = append(issue.Comments, comment)
+	issue.Updated = comment.Created
+	writeJSON(w, http.StatusCreated, toCommentJSON(comment))
+}
+
+// getProject answers GET /rest/api/3/project/{key}, listing the project's issue types
+// (DefaultIssueTypes and those of its issues, identified by name) and components.
+func (s *Server) getProject(w http.ResponseWriter, key string) {
+	name, ok := s.projects[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", key))
+		return
+	}
+
+	issueTypes := make([]map[string]string, 0)
+	for _, issueType := range s.issueTypes(key) {
+		issueTypes = append(issueTypes, map[string]string{"id": issueType, "name": issueType})
+	}
+	components := make([]namedJSON, 0, len(s.components[key]))
+	for _, component := range s.components[key] {
+		components = append(components, namedJSON{Name: component})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"key":        key,
+		"name":       name,
+		"issueTypes": issueTypes,
+		"components": components,
+	})
+}
+
+// createMeta answers GET /rest/api/3/issue/createmeta/{key}/issuetypes/{id} with the
+// select custom fields of the project, in one page.
+func (s *Server) createMeta(w http.ResponseWriter, key, issueTypeID string) {
+	if _, ok := s.projects[key]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", key))
+		return
+	}
+	known := false
+	for _, issueType := range s.issueTypes(key) {
+		known = known || issueType == issueTypeID
+	}
+	if !known {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No issue type could be found with id '%s'.", issueTypeID))
+		return
+	}
+
+	fieldIDs := make([]string, 0, len(s.fieldOptions[key]))
+	for fieldID := range s.fieldOptions[key] {
+		fieldIDs = append(fieldIDs, fieldID)
+	}
+	sort.Strings(fieldIDs)
+	fields := make([]map[string]interface{}, 0, len(fieldIDs))
+	for _, fieldID := range fieldIDs {
+		allowed := make([]map[string]string, 0)
+		for _, option := range s.fieldOptions[key][fieldID] {
+			allowed = append(allowed, map[string]string{"value": option})
+		}
+		fields = append(fields, map[string]interface{}{"fieldId": fieldID, "allowedValues": allowed})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    0,
+		"maxResults": len(fields),
+		"total":      len(fields),
+		"fields":     fields,
+	})
+}
+
+// listProjectStatuses answers GET /rest/api/3/project/{key}/statuses: every issue type
+// of the project shares the workflow.
+func (s *Server) listProjectStatuses(w http.ResponseWriter, key string) {
+	if _, ok := s.projects[key]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", key))
+		return
+	}
+
+	statuses := make([]namedJSON, 0, len(s.workflow))
+	for _, status := range s.workflow {
+		statuses = append(statuses, namedJSON{Name: status})
+	}
+	workflows := make([]map[string]interface{}, 0)
+	for _, issueType := range s.issueTypes(key) {
+		workflows = append(workflows, map[string]interface{}{"name": issueType, "statuses": statuses})
+	}
+	writeJSON(w, http.StatusOK, workflows)
+}
+
+// listPriorities answers GET /rest/api/3/priority.
+func (s *Server) listPriorities(w http.ResponseWriter) {
+	priorities := make([]namedJSON, 0, len(DefaultPriorities))
+	for _, priority := range DefaultPriorities {
+		priorities = append(priorities, namedJSON{Name: priority})
+	}
+	writeJSON(w, http.StatusOK, priorities)
+}
+
+// issueTypes returns DefaultIssueTypes and the issue types of the project's issues,
+// sorted. Callers hold mu.
+func (s *Server) issueTypes(projectKey string) []string {
+	seen := make(map[string]bool)
+	issueTypes := make([]string, 0, len(DefaultIssueTypes))
+	add := func(issueType string) {
+		if issueType != "" && !seen[issueType] {
+			seen[issueType] = true
+			issueTypes = append(issueTypes, issueType)
+		}
+	}
+	for _, issueType := range DefaultIssueTypes {
+		add(issueType)
+	}
+	for _, issue := range s.issues {
+		if issue.ProjectKey() == projectKey {
+			add(issue.IssueType)
+		}
+	}
+	sort.Strings(issueTypes)
+	return issueTypes
+}
+
+// getFilter answers GET /rest/api/3/filter/{id}.
+func (s *Server) getFilter(w http.ResponseWriter, id string) {
+	filter, ok := s.filters[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("The selected filter is not available to you, perhaps it has been deleted or had its permissions changed. (%s)", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id": filter.ID, "name": filter.Name, "jql": filter.JQL})
+}
+
+// searchUsers answers GET /rest/api/3/user/search, matching the query against the start
+// of each user's email and of each word of their display name, ignoring case.
+func (s *Server) searchUsers(w http.ResponseWriter, r *http.Request) {
+	s.writeUserSearch(w, r, func(User) bool { return true })
+}
+
+// searchAssignableUsers answers GET /rest/api/3/user/assignable/search, matching like
+// searchUsers among the users assignable in the project (see SetAssignable).
+func (s *Server) searchAssignableUsers(w http.ResponseWriter, r *http.Request) {
+	projectKey := r.URL.Query().Get("project")
+	if _, ok := s.projects[projectKey]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", projectKey))
+		return
+	}
+
+	restricted, ok := s.assignable[projectKey]
+	s.writeUserSearch(w, r, func(user User) bool {
+		if !user.Active {
+			return false
+		}
+		for _, accountID := range restricted {
+			if accountID == user.AccountID {
+				return true
+			}
+		}
+		return !ok
+	})
+}
+
+// writeUserSearch answers a user search with the users passing include whose email or
+// display name words start with the query, ordered by display name.
+func (s *Server) writeUserSearch(w http.ResponseWriter, r *http.Request, include func(User) bool) {
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("query")))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "The query parameter is required.")
+		return
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("maxResults"))
+	if err != nil || limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	matches := make([]User, 0)
+	for _, user := range s.users {
+		if !include(user) {
+			continue
+		}
+		words := append([]string{strings.ToLower(user.Email)}, strings.Fields(strings.ToLower(user.DisplayName))...)
+		for _, word := range words {
+			if strings.HasPrefix(word, query) {
+				matches = append(matches, user)
+				break
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].DisplayName < matches[j].DisplayName })
+
+	page := make([]accountJSON, 0, len(matches))
+	for _, user := range matches[:min(limit, len(matches))] {
+		page = append(page, toAccountJSON(user))
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// getUser answers GET /rest/api/3/user?accountId=.
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	accountID := r.URL.Query().Get("accountId")
+	user, ok := s.users[accountID]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Specified user does not exist or you do not have required permissions (%s).", accountID))
+		return
+	}
+	writeJSON(w, http.StatusOK, toAccountJSON(user))
+}
+
+// myPermissions answers GET /rest/api/3/mypermissions, granting every permission asked for.
+func (s *Server) myPermissions(w http.ResponseWriter, r *http.Request) {
+	permissions := make(map[string]interface{})
+	for _, key := range strings.Split(r.URL.Query().Get("permissions"), ",") {
+		if key != "" {
+			permissions[key] = map[string]interface{}{"key": key, "havePermission": true}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"permissions": permissions})
+}
+
+// writeJSON writes v as a JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a Jira error body with a single message.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"errorMessages": []string{message}, "errors": map[string]string{}})
+}
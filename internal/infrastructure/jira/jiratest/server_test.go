@@ -0,0 +1,559 @@
+package jiratest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/infrastructure/jira"
+	"github.com/esfisher/jiramd/internal/infrastructure/jira/jiratest"
+)
+
+func TestServer_SearchPaginates(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.SetPageSize(2)
+
+	for i := 1; i <= 5; i++ {
+		server.AddIssue(jiratest.Issue{Key: fmt.Sprintf("JMD-%d", i), Summary: "Ticket", Status: "To Do", IssueType: "Task"})
+	}
+	server.AddIssue(jiratest.Issue{Key: "OPS-1", Summary: "Elsewhere", IssueType: "Task"})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	tickets, err := client.SearchTickets(context.Background(), `project = "JMD" ORDER BY key DESC`, 0)
+	if err != nil {
+		t.Fatalf("SearchTickets() error = %v", err)
+	}
+
+	var keys []string
+	for _, ticket := range tickets {
+		keys = append(keys, ticket.Key.String())
+	}
+	if got, want := fmt.Sprint(keys), "[JMD-5 JMD-4 JMD-3 JMD-2 JMD-1]"; got != want {
+		t.Errorf("keys = %s, want %s", got, want)
+	}
+
+	searches := 0
+	for _, r := range server.Requests() {
+		if r.Path == "/rest/api/3/search/jql" {
+			searches++
+		}
+	}
+	if searches != 3 {
+		t.Errorf("search requests = %d, want 3 pages", searches)
+	}
+}
+
+func TestServer_SearchRejectsUnsupportedJQL(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	_, err := client.SearchTickets(context.Background(), "sprint in openSprints()", 0)
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("SearchTickets() error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestServer_CreateAndUpdate(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.AddIssue(jiratest.Issue{Key: "JMD-7", Summary: "Existing", IssueType: "Task"})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	ctx := context.Background()
+
+	keys, failures, err := client.CreateTickets(ctx, []*domain.TicketDraft{
+		{ProjectKey: "JMD", IssueType: "Bug", Summary: "New bug", Labels: []string{"backend"}},
+		{ProjectKey: "JMD", IssueType: "Bug"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTickets() error = %v", err)
+	}
+	if keys[0].String() != "JMD-8" || failures[0] != nil {
+		t.Errorf("first draft = %v, %v; want JMD-8", keys[0], failures[0])
+	}
+	if !errors.Is(failures[1], domain.ErrInvalidFieldValue) {
+		t.Errorf("draft without summary: %v, want ErrInvalidFieldValue", failures[1])
+	}
+
+	ticket, err := client.GetTicket(ctx, "JMD-8")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	ticket.Summary = "Renamed bug"
+	updated, err := client.UpdateTicket(ctx, ticket)
+	if err != nil {
+		t.Fatalf("UpdateTicket() error = %v", err)
+	}
+	if updated.Summary != "Renamed bug" || updated.Version() == ticket.Version() {
+		t.Errorf("updated = %q at %s, want the new summary at a new version", updated.Summary, updated.Version())
+	}
+
+	if issue, _ := server.Issue("JMD-8"); issue.Summary != "Renamed bug" {
+		t.Errorf("stored summary = %q", issue.Summary)
+	}
+
+	// Editing the stale copy again is a conflict
+	ticket.Summary = "Stale edit"
+	if _, err := client.UpdateTicket(ctx, ticket); !errors.Is(err, domain.ErrSyncConflict) {
+		t.Errorf("stale UpdateTicket() error = %v, want ErrSyncConflict", err)
+	}
+}
+
+func TestServer_HiddenFields(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.AddIssue(jiratest.Issue{Key: "JMD-7", Summary: "Existing", IssueType: "Task", Priority: "Medium"})
+	server.SetHiddenFields("JMD", "priority")
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token).WithLogger(slog.New(slog.DiscardHandler))
+	ctx := context.Background()
+	ticket, err := client.GetTicket(ctx, "JMD-7")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	ticket.Summary = "Renamed"
+	ticket.Priority = "High"
+	if _, err := client.UpdateTicket(ctx, ticket); err != nil {
+		t.Fatalf("UpdateTicket() error = %v", err)
+	}
+
+	issue, _ := server.Issue("JMD-7")
+	if issue.Summary != "Renamed" || issue.Priority != "Medium" {
+		t.Errorf("stored summary %q and priority %q, want the new summary and the hidden priority unchanged", issue.Summary, issue.Priority)
+	}
+}
+
+func TestServer_TaskListDescription(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.AddIssue(jiratest.Issue{Key: "JMD-7", Summary: "Checklist", IssueType: "Task"})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	ctx := context.Background()
+	ticket, err := client.GetTicket(ctx, "JMD-7")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	ticket.Description = "- [x] write tests\n- [ ] ship it"
+	if _, err := client.UpdateTicket(ctx, ticket); err != nil {
+		t.Fatalf("UpdateTicket() error = %v", err)
+	}
+
+	if issue, _ := server.Issue("JMD-7"); issue.Description != ticket.Description {
+		t.Errorf("stored description = %q, want %q", issue.Description, ticket.Description)
+	}
+	got, err := client.GetTicket(ctx, "JMD-7")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if got.Description != ticket.Description {
+		t.Errorf("Description = %q, want %q", got.Description, ticket.Description)
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.AddIssue(jiratest.Issue{Key: "JMD-1", Summary: "Ticket", IssueType: "Task"})
+	server.RateLimit(2, 0)
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	if _, err := client.GetTicket(context.Background(), "JMD-1"); err != nil {
+		t.Fatalf("GetTicket() error = %v, want success after retries", err)
+	}
+	if got := len(server.Requests()); got != 3 {
+		t.Errorf("requests = %d, want 2 rate-limited and 1 served", got)
+	}
+
+	server.RateLimit(10, 0)
+	if _, err := client.GetTicket(context.Background(), "JMD-1"); !errors.Is(err, domain.ErrRateLimited) {
+		t.Errorf("GetTicket() error = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestServer_RequireAuth(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.AddIssue(jiratest.Issue{Key: "JMD-1", Summary: "Ticket", IssueType: "Task"})
+	server.RequireAuth(jiratest.Email, jiratest.Token)
+
+	_, err := jira.NewClient(server.URL(), jiratest.Email, "expired").GetTicket(context.Background(), "JMD-1")
+	if !domain.IsAuthFailure(err) {
+		t.Errorf("GetTicket() error = %v, want an auth failure", err)
+	}
+
+	if _, err := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token).GetTicket(context.Background(), "JMD-1"); err != nil {
+		t.Errorf("GetTicket() with valid credentials error = %v", err)
+	}
+}
+
+func TestServer_SelfTestPasses(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.AddProject("JMD", "jiramd")
+
+	report := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token).SelfTest(context.Background(), "JMD")
+	if failed := report.Failed(); failed != nil {
+		t.Errorf("check %s failed: %v", failed.Name, failed.Err)
+	}
+}
+
+func TestServer_UpdatedFilter(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	server.SetClock(func() time.Time { return now })
+	server.AddIssue(jiratest.Issue{Key: "JMD-1", Summary: "Old", IssueType: "Task"})
+	now = now.Add(48 * time.Hour)
+	server.AddIssue(jiratest.Issue{Key: "JMD-2", Summary: "New", IssueType: "Task"})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	tickets, err := client.SearchTickets(context.Background(), `project = JMD AND updated >= "2026-03-02 00:00"`, 0)
+	if err != nil {
+		t.Fatalf("SearchTickets() error = %v", err)
+	}
+	if len(tickets) != 1 || tickets[0].Key.String() != "JMD-2" {
+		t.Errorf("tickets = %v, want only JMD-2", tickets)
+	}
+}
+
+func TestServer_TransitionWithStatusMap(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.SetWorkflow("Offen", "In Bearbeitung", "Erledigt")
+	server.AddIssue(jiratest.Issue{Key: "JMD-1", Summary: "Localized", Status: "Offen", IssueType: "Task"})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token).WithStatusMap(domain.StatusMap{
+		Names:   map[string]string{"Offen": "To Do", "In Bearbeitung": "In Progress", "Erledigt": "Done"},
+		Aliases: map[string]string{"wip": "In Progress"},
+	})
+	ctx := context.Background()
+
+	ticket, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if ticket.Status != "To Do" {
+		t.Errorf("pulled Status = %q, want the local name To Do", ticket.Status)
+	}
+
+	if err := client.TransitionTicket(ctx, "JMD-1", "WIP"); err != nil {
+		t.Fatalf("TransitionTicket() error = %v", err)
+	}
+	if issue, _ := server.Issue("JMD-1"); issue.Status != "In Bearbeitung" {
+		t.Errorf("Jira status = %q, want In Bearbeitung", issue.Status)
+	}
+
+	err = client.TransitionTicket(ctx, "JMD-1", "In Review")
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("TransitionTicket() to an unknown status error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestServer_CommentVisibility(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.SetPageSize(2)
+	server.AddIssue(jiratest.Issue{Key: "JMD-1", Summary: "Commented", IssueType: "Task", Comments: []jiratest.Comment{
+		{ID: "1", Author: "alice@example.com", Body: "Public", Created: time.Now()},
+		{ID: "2", Author: "bob@example.com", Body: "Devs only", Created: time.Now(), VisibilityType: "role", VisibilityValue: "Developers"},
+	}})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	ctx := context.Background()
+	key, _ := domain.NewTicketKey("JMD-1")
+
+	internal := &domain.Comment{TicketKey: key, Body: "Agents only", Visibility: domain.CommentVisibility{Internal: true}}
+	posted, err := client.AddComment(ctx, "JMD-1", internal)
+	if err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if posted.ID == "" || posted.Visibility != internal.Visibility {
+		t.Errorf("AddComment() = %+v, want an ID and the internal visibility", posted)
+	}
+
+	comments, err := client.FetchComments(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FetchComments() error = %v", err)
+	}
+	want := []domain.CommentVisibility{
+		{},
+		{Type: domain.VisibilityRole, Value: "Developers"},
+		{Internal: true},
+	}
+	if len(comments) != len(want) {
+		t.Fatalf("FetchComments() returned %d comments across pages, want %d", len(comments), len(want))
+	}
+	for i, comment := range comments {
+		if comment.Visibility != want[i] {
+			t.Errorf("comment %s visibility = %+v, want %+v", comment.ID, comment.Visibility, want[i])
+		}
+	}
+
+	restricted := &domain.Comment{TicketKey: key, Body: "Nobody", Visibility: domain.CommentVisibility{Type: "team", Value: "x"}}
+	if _, err := client.AddComment(ctx, "JMD-1", restricted); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("AddComment() with an unknown visibility type error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestServer_CommentsStreamByPage(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.SetPageSize(2)
+	issue := jiratest.Issue{Key: "JMD-1", Summary: "Busy", IssueType: "Task"}
+	for i := 1; i <= 5; i++ {
+		issue.Comments = append(issue.Comments, jiratest.Comment{ID: fmt.Sprint(i), Author: "alice@example.com", Body: "Note", Created: time.Now()})
+	}
+	server.AddIssue(issue)
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	ctx := context.Background()
+
+	var ids []string
+	if err := client.ForEachComment(ctx, "JMD-1", func(comment *domain.Comment) error {
+		ids = append(ids, comment.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEachComment() error = %v", err)
+	}
+	if got, want := fmt.Sprint(ids), "[1 2 3 4 5]"; got != want {
+		t.Errorf("ForEachComment() streamed %s, want %s", got, want)
+	}
+
+	// Stopping early fetches no further pages
+	stop := errors.New("stop")
+	before := len(server.Requests())
+	err := client.ForEachComment(ctx, "JMD-1", func(comment *domain.Comment) error {
+		if comment.ID == "2" {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("ForEachComment() error = %v, want %v", err, stop)
+	}
+	if pages := len(server.Requests()) - before; pages != 1 {
+		t.Errorf("ForEachComment() fetched %d pages before stopping, want 1", pages)
+	}
+
+	if err := client.ForEachComment(ctx, "JMD-404", func(*domain.Comment) error { return nil }); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("ForEachComment(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_CountComments(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.SetPageSize(2)
+	issue := jiratest.Issue{Key: "JMD-1", Summary: "Busy", IssueType: "Task"}
+	for i := 1; i <= 5; i++ {
+		issue.Comments = append(issue.Comments, jiratest.Comment{ID: fmt.Sprint(i), Author: "alice@example.com", Body: "Note", Created: time.Now()})
+	}
+	server.AddIssue(issue)
+	server.AddIssue(jiratest.Issue{Key: "JMD-2", Summary: "Quiet", IssueType: "Task"})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	ctx := context.Background()
+
+	before := len(server.Requests())
+	if count, err := client.CountComments(ctx, "JMD-1"); err != nil || count != 5 {
+		t.Errorf("CountComments() = %d, %v; want 5", count, err)
+	}
+	if requests := len(server.Requests()) - before; requests != 1 {
+		t.Errorf("CountComments() made %d requests, want 1", requests)
+	}
+	if count, err := client.CountComments(ctx, "JMD-2"); err != nil || count != 0 {
+		t.Errorf("CountComments(no comments) = %d, %v; want 0", count, err)
+	}
+	if _, err := client.CountComments(ctx, "JMD-404"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("CountComments(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_StatusHistory(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.SetPageSize(1)
+	server.SetWorkflow("Offen", "In Bearbeitung", "Erledigt")
+	server.AddIssue(jiratest.Issue{Key: "JMD-1", Summary: "Moved", Status: "Offen", IssueType: "Task"})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token).WithStatusMap(domain.StatusMap{
+		Names: map[string]string{"Offen": "To Do", "In Bearbeitung": "In Progress", "Erledigt": "Done"},
+	})
+	ctx := context.Background()
+
+	for _, status := range []string{"In Progress", "Done"} {
+		if err := client.TransitionTicket(ctx, "JMD-1", status); err != nil {
+			t.Fatalf("TransitionTicket(%s) error = %v", status, err)
+		}
+	}
+
+	history, err := client.FetchStatusHistory(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FetchStatusHistory() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("FetchStatusHistory() returned %d changes across pages, want 2", len(history))
+	}
+	if history[0].From != "To Do" || history[0].To != "In Progress" || history[1].To != "Done" {
+		t.Errorf("FetchStatusHistory() = %+v, want To Do -> In Progress -> Done under local names", history)
+	}
+	if history[0].Author != jiratest.Email || !history[0].At.Before(history[1].At) {
+		t.Errorf("FetchStatusHistory() = %+v, want authored changes oldest first", history)
+	}
+
+	if _, err := client.FetchStatusHistory(ctx, "JMD-9"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("FetchStatusHistory() of a missing ticket error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_SavedFilter(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.AddIssue(jiratest.Issue{Key: "JMD-1", Summary: "Open", Status: "To Do", IssueType: "Task"})
+	server.AddIssue(jiratest.Issue{Key: "JMD-2", Summary: "Closed", Status: "Done", IssueType: "Task"})
+	server.AddFilter(jiratest.Filter{ID: "10042", Name: "Open work", JQL: `project = "JMD" AND status = "To Do"`})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	ctx := context.Background()
+
+	filter, err := client.GetFilter(ctx, "10042")
+	if err != nil {
+		t.Fatalf("GetFilter() error = %v", err)
+	}
+	if filter.ID != "10042" || filter.Name != "Open work" {
+		t.Errorf("GetFilter() = %+v, want filter 10042 named Open work", filter)
+	}
+
+	tickets, err := client.SearchTickets(ctx, filter.JQL, 0)
+	if err != nil {
+		t.Fatalf("SearchTickets(filter JQL) error = %v", err)
+	}
+	if len(tickets) != 1 || tickets[0].Key.String() != "JMD-1" {
+		t.Errorf("SearchTickets(filter JQL) = %d tickets, want JMD-1 only", len(tickets))
+	}
+
+	if _, err := client.GetFilter(ctx, "99"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetFilter() of a missing filter error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_UserDirectory(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.AddUser(jiratest.User{AccountID: "u1", DisplayName: "Alice Smith", Email: "alice@example.com", Active: true})
+	server.AddUser(jiratest.User{AccountID: "u2", DisplayName: "Bob Alison", Active: true})
+	server.AddUser(jiratest.User{AccountID: "u3", DisplayName: "Carol Jones", Email: "carol@example.com"})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	ctx := context.Background()
+
+	users, err := client.SearchUsers(ctx, "Ali")
+	if err != nil {
+		t.Fatalf("SearchUsers() error = %v", err)
+	}
+	if len(users) != 2 || users[0].Name() != "alice@example.com" || users[1].Name() != "Bob Alison" {
+		t.Errorf("SearchUsers(Ali) = %+v, want Alice by email and Bob by display name", users)
+	}
+	if _, err := client.SearchUsers(ctx, " "); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("SearchUsers(empty) error = %v, want ErrInvalidInput", err)
+	}
+
+	user, err := client.GetUser(ctx, "u3")
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	if user.DisplayName != "Carol Jones" || user.Active {
+		t.Errorf("GetUser() = %+v, want the deactivated Carol Jones", user)
+	}
+	if _, err := client.GetUser(ctx, "u9"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetUser() of a missing user error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_AssignableUsers(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.AddProject("JMD", "Jira Markdown")
+	server.AddProject("OPS", "Operations")
+	server.AddUser(jiratest.User{AccountID: "u1", DisplayName: "Alice Smith", Email: "alice@example.com", Active: true})
+	server.AddUser(jiratest.User{AccountID: "u2", DisplayName: "Alicia Jones", Email: "alicia@example.com", Active: true})
+	server.AddUser(jiratest.User{AccountID: "u3", DisplayName: "Alina Old", Email: "alina@example.com"})
+	server.SetAssignable("OPS", "u2", "u3")
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token)
+	ctx := context.Background()
+
+	users, err := client.SearchAssignableUsers(ctx, "JMD", "ali")
+	if err != nil {
+		t.Fatalf("SearchAssignableUsers() error = %v", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("SearchAssignableUsers(JMD) = %+v, want the two active users", users)
+	}
+
+	users, err = client.SearchAssignableUsers(ctx, "OPS", "ali")
+	if err != nil {
+		t.Fatalf("SearchAssignableUsers() error = %v", err)
+	}
+	if len(users) != 1 || users[0].AccountID != "u2" {
+		t.Errorf("SearchAssignableUsers(OPS) = %+v, want only Alicia", users)
+	}
+
+	if _, err := client.SearchAssignableUsers(ctx, "NOPE", "ali"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SearchAssignableUsers() in a missing project error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_ProjectMetadata(t *testing.T) {
+	server := jiratest.NewServer()
+	defer server.Close()
+	server.SetWorkflow("Offen", "Erledigt")
+	server.SetComponents("JMD", "CLI", "Daemon")
+	server.SetFieldOptions("JMD", "customfield_10001", "Red", "Blue")
+	server.AddIssue(jiratest.Issue{Key: "JMD-1", Summary: "Spike", Status: "Offen", IssueType: "Epic"})
+
+	client := jira.NewClient(server.URL(), jiratest.Email, jiratest.Token).WithStatusMap(domain.StatusMap{
+		Names: map[string]string{"Offen": "To Do", "Erledigt": "Done"},
+	})
+	ctx := context.Background()
+
+	metadata, err := client.FetchProjectMetadata(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("FetchProjectMetadata() error = %v", err)
+	}
+	if got, want := fmt.Sprint(metadata.IssueTypes), "[Bug Epic Story Task]"; got != want {
+		t.Errorf("IssueTypes = %s, want %s", got, want)
+	}
+	if got, want := fmt.Sprint(metadata.Statuses), "[Done To Do]"; got != want {
+		t.Errorf("Statuses = %s, want %s under local names", got, want)
+	}
+	if got, want := fmt.Sprint(metadata.Components), "[CLI Daemon]"; got != want {
+		t.Errorf("Components = %s, want %s", got, want)
+	}
+	if got, want := fmt.Sprint(metadata.Priorities), fmt.Sprint(jiratest.DefaultPriorities); got != want {
+		t.Errorf("Priorities = %s, want %s in Jira's order", got, want)
+	}
+	if got, want := fmt.Sprint(metadata.FieldOptions), "map[customfield_10001:[Red Blue]]"; got != want {
+		t.Errorf("FieldOptions = %s, want %s", got, want)
+	}
+
+	targets, err := client.AvailableTransitions(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("AvailableTransitions() error = %v", err)
+	}
+	if got, want := fmt.Sprint(targets), "[Done]"; got != want {
+		t.Errorf("AvailableTransitions() = %s, want %s", got, want)
+	}
+
+	if _, err := client.FetchProjectMetadata(ctx, "OPS"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("FetchProjectMetadata() of a missing project error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,119 @@
+package jira
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket bounding the requests sent to Jira, shared by every
+// client and goroutine that uses it so parallel workers cannot stampede Jira. When Jira
+// rate limits a request, the whole bucket waits out the Retry-After delay, not just the
+// worker that got the 429. It is safe for concurrent use.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	// rate is the tokens added per second, and burst the most the bucket holds
+	rate  float64
+	burst float64
+
+	// tokens is what the bucket held at last, when it was last refilled
+	tokens float64
+	last   time.Time
+
+	// pausedUntil holds every request back until Jira's Retry-After delay ends
+	pausedUntil time.Time
+
+	// now is the clock, replaced in tests
+	now func() time.Time
+}
+
+// NewRateLimiter creates a limiter allowing requestsPerSecond on average, and bursts of
+// as many requests as are allowed in a second (at least one). It starts full.
+func NewRateLimiter(requestsPerSecond float64) *RateLimiter {
+	burst := math.Max(1, math.Ceil(requestsPerSecond))
+	return &RateLimiter{rate: requestsPerSecond, burst: burst, tokens: burst, now: time.Now}
+}
+
+// Wait blocks until a request may be sent, returning ctx.Err() early if ctx is done
+// first. A nil limiter never waits.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token if one is available, or returns how long to wait before trying
+// again.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Pause holds every request back for d, as Jira asked after rate limiting one, and
+// empties the bucket so requests resume at the limited rate instead of in a burst. A nil
+// limiter ignores it.
+func (l *RateLimiter) Pause(d time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := l.now().Add(d)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.tokens = 0
+	l.last = l.pausedUntil
+}
+
+// sharedLimiters are the limiters of the clients created from config, by site and user,
+// which Jira rate limits by; clients of the same account share one.
+var sharedLimiters = struct {
+	sync.Mutex
+	byAccount map[string]*RateLimiter
+}{byAccount: make(map[string]*RateLimiter)}
+
+// sharedRateLimiter returns the process-wide limiter of the account, created allowing
+// requestsPerSecond the first time, or nil if requestsPerSecond is not positive.
+func sharedRateLimiter(baseURL, email string, requestsPerSecond float64) *RateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	sharedLimiters.Lock()
+	defer sharedLimiters.Unlock()
+
+	account := baseURL + "\x00" + email
+	limiter, ok := sharedLimiters.byAccount[account]
+	if !ok {
+		limiter = NewRateLimiter(requestsPerSecond)
+		sharedLimiters.byAccount[account] = limiter
+	}
+	return limiter
+}
@@ -0,0 +1,108 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(2)
+	limiter.now = func() time.Time { return now }
+
+	// Starts with a burst of a second's worth of requests
+	for i := 0; i < 2; i++ {
+		if delay := limiter.reserve(); delay != 0 {
+			t.Fatalf("reserve() %d = %v, want no wait within the burst", i, delay)
+		}
+	}
+	if delay := limiter.reserve(); delay != 500*time.Millisecond {
+		t.Errorf("reserve() = %v, want 500ms for the next token", delay)
+	}
+	now = now.Add(500 * time.Millisecond)
+	if delay := limiter.reserve(); delay != 0 {
+		t.Errorf("reserve() = %v after refilling, want no wait", delay)
+	}
+
+	// A pause holds every request back, then resumes at the limited rate
+	limiter.Pause(3 * time.Second)
+	if delay := limiter.reserve(); delay != 3*time.Second {
+		t.Errorf("reserve() = %v while paused, want 3s", delay)
+	}
+	now = now.Add(3 * time.Second)
+	if delay := limiter.reserve(); delay != 500*time.Millisecond {
+		t.Errorf("reserve() = %v after the pause, want 500ms rather than a burst", delay)
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	var nilLimiter *RateLimiter
+	if err := nilLimiter.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() on a nil limiter error = %v", err)
+	}
+
+	limiter := NewRateLimiter(0.001)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() on an empty bucket error = %v, want the context's", err)
+	}
+}
+
+func TestClient_SharedRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"key":"JMD-1","fields":{"summary":"One"}}`))
+	}))
+	defer server.Close()
+
+	cfg := domain.JiraConfig{BaseURL: server.URL, Email: "limited@example.com", Token: "secret", HTTP: domain.HTTPConfig{RateLimit: 20}}
+	first, err := NewClientFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewClientFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.limiter == nil || first.limiter != second.limiter {
+		t.Fatalf("clients of the same account have limiters %p and %p, want one shared", first.limiter, second.limiter)
+	}
+
+	// 20 requests pass in the first burst; 5 more need a quarter of a second
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		client := first
+		if i%2 == 1 {
+			client = second
+		}
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			if _, err := client.GetTicket(context.Background(), key); err != nil {
+				t.Errorf("GetTicket() error = %v", err)
+			}
+		}(fmt.Sprintf("JMD-%d", i+1))
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("25 requests took %v, want the shared limit of 20 per second to hold them back", elapsed)
+	}
+	if requests.Load() != 25 {
+		t.Errorf("server got %d requests, want 25", requests.Load())
+	}
+}
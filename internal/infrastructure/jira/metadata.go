@@ -0,0 +1,158 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// projectMetadataResponse is the body of GET /rest/api/3/project/{key}, which lists the
+// project's issue types and components besides its name.
+type projectMetadataResponse struct {
+	Key        string          `json:"key"`
+	Name       string          `json:"name"`
+	IssueTypes []issueTypeInfo `json:"issueTypes"`
+	Components []namedField    `json:"components"`
+}
+
+// issueTypeInfo is an issue type of a project, whose ID the create metadata is requested by.
+type issueTypeInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Subtask bool   `json:"subtask"`
+}
+
+// createMetaPageSize is the most fields requested per create metadata page.
+const createMetaPageSize = 200
+
+// createMetaPage is a page of GET /rest/api/3/issue/createmeta/{key}/issuetypes/{id}:
+// the fields of an issue type's create screen.
+type createMetaPage struct {
+	StartAt int               `json:"startAt"`
+	Total   int               `json:"total"`
+	Fields  []createMetaField `json:"fields"`
+}
+
+// createMetaField is a field of a create screen, with the values it allows if it is a
+// select field.
+type createMetaField struct {
+	FieldID         string `json:"fieldId"`
+	Name            string `json:"name"`
+	Required        bool   `json:"required"`
+	HasDefaultValue bool   `json:"hasDefaultValue"`
+	AllowedValues   []struct {
+		Value string `json:"value"`
+	} `json:"allowedValues"`
+}
+
+// issueTypeStatuses is an entry of GET /rest/api/3/project/{key}/statuses: the statuses
+// of one issue type's workflow.
+type issueTypeStatuses struct {
+	Name     string       `json:"name"`
+	Statuses []namedField `json:"statuses"`
+}
+
+// FetchProjectMetadata returns the issue types, priorities and statuses (under their
+// local names, see WithPriorityMaps and WithStatusMap), components, and select custom
+// field options of a project.
+// Transitions and FetchedAt are left for the caller: Jira only lists transitions per
+// ticket (see AvailableTransitions).
+// Returns ErrNotFound if the project doesn't exist or isn't visible to the user.
+func (c *Client) FetchProjectMetadata(ctx context.Context, projectKey string) (*domain.ProjectMetadata, error) {
+	path := "/rest/api/3/project/" + url.PathEscape(projectKey)
+
+	var project projectMetadataResponse
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &project); err != nil {
+		return nil, err
+	}
+	var workflows []issueTypeStatuses
+	if err := c.doRequest(ctx, http.MethodGet, path+"/statuses", nil, &workflows); err != nil {
+		return nil, err
+	}
+	var priorities []namedField
+	if err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/priority", nil, &priorities); err != nil {
+		return nil, err
+	}
+
+	metadata := &domain.ProjectMetadata{
+		ProjectKey:   project.Key,
+		Name:         project.Name,
+		Priorities:   make([]string, 0, len(priorities)),
+		Components:   fieldNames(project.Components),
+		FieldOptions: make(map[string][]string),
+		Transitions:  make(map[string][]string),
+	}
+	priorityMap := c.priorityMap(projectKey)
+	for _, priority := range priorities {
+		metadata.Priorities = append(metadata.Priorities, priorityMap.ToLocal(priority.Name))
+	}
+	for _, issueType := range project.IssueTypes {
+		metadata.IssueTypes = append(metadata.IssueTypes, issueType.Name)
+		if err := c.collectFieldOptions(ctx, projectKey, issueType.ID, metadata.FieldOptions); err != nil {
+			return nil, err
+		}
+	}
+	for _, workflow := range workflows {
+		for _, status := range workflow.Statuses {
+			metadata.Statuses = append(metadata.Statuses, c.statuses.ToLocal(status.Name))
+		}
+	}
+	metadata.Normalize()
+	return metadata, nil
+}
+
+// collectFieldOptions adds the allowed values of the select custom fields on an issue
+// type's create screen to options, keyed by field ID. Fields shared by several issue
+// types collect the values of all of them.
+func (c *Client) collectFieldOptions(ctx context.Context, projectKey, issueTypeID string, options map[string][]string) error {
+	fields, err := c.createMetaFields(ctx, projectKey, issueTypeID)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if !strings.HasPrefix(field.FieldID, "customfield_") {
+			continue
+		}
+		for _, allowed := range field.AllowedValues {
+			if allowed.Value != "" {
+				options[field.FieldID] = append(options[field.FieldID], allowed.Value)
+			}
+		}
+	}
+	return nil
+}
+
+// createMetaFields returns the fields of an issue type's create screen in a project.
+func (c *Client) createMetaFields(ctx context.Context, projectKey, issueTypeID string) ([]createMetaField, error) {
+	path := fmt.Sprintf("/rest/api/3/issue/createmeta/%s/issuetypes/%s", url.PathEscape(projectKey), url.PathEscape(issueTypeID))
+	var fields []createMetaField
+	for startAt := 0; ; {
+		params := url.Values{
+			"startAt":    {fmt.Sprint(startAt)},
+			"maxResults": {fmt.Sprint(createMetaPageSize)},
+		}
+		var page createMetaPage
+		if err := c.doRequest(ctx, http.MethodGet, path+"?"+params.Encode(), nil, &page); err != nil {
+			return nil, fmt.Errorf("failed to fetch create metadata of issue type %s: %w", issueTypeID, err)
+		}
+		fields = append(fields, page.Fields...)
+
+		startAt += len(page.Fields)
+		if len(page.Fields) == 0 || startAt >= page.Total {
+			return fields, nil
+		}
+	}
+}
+
+// fieldNames returns the names of named fields.
+func fieldNames(fields []namedField) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
@@ -0,0 +1,35 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// myPermissionsResponse is the response of the mypermissions API, keyed by permission key.
+type myPermissionsResponse struct {
+	Permissions map[string]struct {
+		HavePermission bool `json:"havePermission"`
+	} `json:"permissions"`
+}
+
+// MyPermissions reports which of the given permissions (e.g. "EDIT_ISSUES") the
+// authenticated user has in the project. Permissions Jira does not know are reported
+// as not granted.
+func (c *Client) MyPermissions(ctx context.Context, projectKey string, permissions []string) (map[string]bool, error) {
+	query := url.Values{}
+	query.Set("projectKey", projectKey)
+	query.Set("permissions", strings.Join(permissions, ","))
+
+	var body myPermissionsResponse
+	if err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/mypermissions?"+query.Encode(), nil, &body); err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission] = body.Permissions[permission].HavePermission
+	}
+	return granted, nil
+}
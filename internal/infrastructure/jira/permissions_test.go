@@ -0,0 +1,42 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_MyPermissions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/mypermissions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("projectKey"); got != "JMD" {
+			t.Errorf("projectKey = %q, want JMD", got)
+		}
+		if got := r.URL.Query().Get("permissions"); got != "BROWSE_PROJECTS,EDIT_ISSUES,UNKNOWN" {
+			t.Errorf("permissions = %q", got)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"permissions": map[string]interface{}{
+				"BROWSE_PROJECTS": map[string]interface{}{"key": "BROWSE_PROJECTS", "havePermission": true},
+				"EDIT_ISSUES":     map[string]interface{}{"key": "EDIT_ISSUES", "havePermission": false},
+			},
+		})
+	}))
+	defer server.Close()
+
+	got, err := NewClient(server.URL, "me@example.com", "secret").
+		MyPermissions(context.Background(), "JMD", []string{"BROWSE_PROJECTS", "EDIT_ISSUES", "UNKNOWN"})
+	if err != nil {
+		t.Fatalf("MyPermissions failed: %v", err)
+	}
+
+	want := map[string]bool{"BROWSE_PROJECTS": true, "EDIT_ISSUES": false, "UNKNOWN": false}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MyPermissions() = %v, want %v", got, want)
+	}
+}
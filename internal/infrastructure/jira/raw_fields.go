@@ -0,0 +1,100 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// unrecordedFields are the fields left out of the raw fields although toTicket does not
+// map them: comments are synced on their own, and lastViewed changes whenever someone
+// opens the issue, which would rewrite the file without any change to the ticket.
+var unrecordedFields = []string{"comment", "lastViewed"}
+
+// requestedFields returns the fields requested for every issue: the ones toTicket maps,
+// or all of them when raw fields are enabled.
+func (c *Client) requestedFields() []string {
+	if c.rawFields {
+		return []string{"*all"}
+	}
+	return issueFields
+}
+
+// unmappedFields decodes the fields of an issue that toTicket does not map, keyed by
+// field name. Fields whose name is unknown, or shared with another field, are keyed by
+// ID instead. Fields without a value are left out.
+func (c *Client) unmappedFields(ctx context.Context, i *issue) (map[string]interface{}, error) {
+	if len(i.rawFields) == 0 {
+		return nil, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(i.rawFields, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode fields: %w", err)
+	}
+
+	skip := make(map[string]bool, len(issueFields)+len(unrecordedFields))
+	for _, id := range append(append([]string(nil), issueFields...), unrecordedFields...) {
+		skip[id] = true
+	}
+
+	raw := make(map[string]interface{})
+	for id, value := range fields {
+		if skip[id] {
+			continue
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(value, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to decode field %s: %w", id, err)
+		}
+		if isEmptyRaw(decoded) {
+			continue
+		}
+		raw[id] = decoded
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	definitions, err := c.fieldDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return nameFields(raw, definitions), nil
+}
+
+// nameFields rekeys raw field values by field name where the name is known and no other
+// field of raw has it.
+func nameFields(raw map[string]interface{}, definitions map[string]FieldDefinition) map[string]interface{} {
+	uses := make(map[string]int, len(raw))
+	for id := range raw {
+		if name := definitions[id].Name; name != "" {
+			uses[name]++
+		}
+	}
+
+	named := make(map[string]interface{}, len(raw))
+	for id, value := range raw {
+		name := definitions[id].Name
+		if name == "" || uses[name] > 1 {
+			name = id
+		}
+		named[name] = value
+	}
+	return named
+}
+
+// isEmptyRaw returns true if a decoded field value shows nothing in Jira: null, an empty
+// string, or an empty list or object.
+func isEmptyRaw(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestClient_RawFields(t *testing.T) {
+	var requestedFields []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/field":
+			json.NewEncoder(w).Encode([]FieldDefinition{
+				{ID: "customfield_1", Name: "Team", Custom: true},
+				{ID: "customfield_2", Name: "Notes", Custom: true},
+				{ID: "customfield_3", Name: "Notes", Custom: true},
+				{ID: "duedate", Name: "Due date"},
+			})
+		case "/rest/api/3/search/jql":
+			var req searchRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			requestedFields = req.Fields
+
+			issue := issueJSON("JMD-1", "One")
+			fields := issue["fields"].(map[string]interface{})
+			fields["customfield_1"] = map[string]interface{}{"value": "Platform"}
+			fields["customfield_2"] = "first"
+			fields["customfield_3"] = "second"
+			fields["customfield_4"] = 3
+			fields["duedate"] = "2026-11-01"
+			fields["environment"] = nil
+			fields["components"] = []interface{}{}
+			fields["lastViewed"] = "2026-10-15T08:00:00.000+0000"
+			fields["comment"] = map[string]interface{}{"comments": []interface{}{}, "total": 0}
+			json.NewEncoder(w).Encode(map[string]interface{}{"issues": []interface{}{issue}, "isLast": true})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	tickets, err := client.SearchTickets(context.Background(), "project = JMD", 0)
+	if err != nil {
+		t.Fatalf("SearchTickets() error = %v", err)
+	}
+	if raw := tickets[0].JiraFields(); raw != nil {
+		t.Errorf("JiraFields() = %v, want none unless enabled", raw)
+	}
+	if !reflect.DeepEqual(requestedFields, issueFields) {
+		t.Errorf("requested fields %v, want only the mapped ones", requestedFields)
+	}
+
+	tickets, err = client.WithRawFields(true).SearchTickets(context.Background(), "project = JMD", 0)
+	if err != nil {
+		t.Fatalf("SearchTickets() error = %v", err)
+	}
+	if !reflect.DeepEqual(requestedFields, []string{"*all"}) {
+		t.Errorf("requested fields %v, want all", requestedFields)
+	}
+	want := map[string]interface{}{
+		"Team":          map[string]interface{}{"value": "Platform"},
+		"customfield_2": "first",
+		"customfield_3": "second",
+		"customfield_4": float64(3),
+		"Due date":      "2026-11-01",
+	}
+	got := tickets[0]
+	if !reflect.DeepEqual(got.JiraFields(), want) {
+		t.Errorf("JiraFields() = %v, want %v", got.JiraFields(), want)
+	}
+	if !got.HasFixVersion("1.4.0") || got.Status != "In Progress" {
+		t.Errorf("mapped fields changed: %v %q", got.FixVersions(), got.Status)
+	}
+	if _, ok := got.CustomFields["customfield_1"]; ok {
+		t.Errorf("CustomFields = %v, want raw fields only under %s", got.CustomFields, domain.JiraFieldsField)
+	}
+}
@@ -0,0 +1,88 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// searchPageSize is the number of issues requested per search page (Jira's maximum is 100).
+const searchPageSize = 100
+
+// searchRequest is the body of POST /rest/api/3/search/jql.
+type searchRequest struct {
+	JQL           string   `json:"jql"`
+	Fields        []string `json:"fields"`
+	MaxResults    int      `json:"maxResults"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+}
+
+// searchResponse is one page of search results.
+type searchResponse struct {
+	Issues        []issue `json:"issues"`
+	NextPageToken string  `json:"nextPageToken"`
+	IsLast        bool    `json:"isLast"`
+}
+
+// SearchTickets runs a JQL query and returns the matching tickets in Jira's order.
+// At most limit tickets are returned; a limit of zero or less returns every match.
+// Returns ErrInvalidInput if Jira rejects the query.
+func (c *Client) SearchTickets(ctx context.Context, jql string, limit int) ([]*domain.Ticket, error) {
+	tickets := make([]*domain.Ticket, 0)
+	err := c.searchTickets(ctx, jql, limit, func(ticket *domain.Ticket) error {
+		tickets = append(tickets, ticket)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// ForEachTicket runs a JQL query and calls fn with each matching ticket in Jira's order,
+// one result page at a time, so only a page of tickets is held in memory however many
+// match. If fn returns an error, iteration stops and ForEachTicket returns that error.
+// Returns ErrInvalidInput if Jira rejects the query.
+func (c *Client) ForEachTicket(ctx context.Context, jql string, fn func(ticket *domain.Ticket) error) error {
+	return c.searchTickets(ctx, jql, 0, fn)
+}
+
+// searchTickets pages through the results of a JQL query, calling fn with each of at
+// most limit tickets (every match when limit <= 0).
+func (c *Client) searchTickets(ctx context.Context, jql string, limit int, fn func(ticket *domain.Ticket) error) error {
+	req := searchRequest{JQL: strings.TrimSpace(jql), Fields: c.requestedFields()}
+	seen := 0
+
+	for {
+		req.MaxResults = searchPageSize
+		if limit > 0 && limit-seen < searchPageSize {
+			req.MaxResults = limit - seen
+		}
+
+		var page searchResponse
+		if err := c.doRequest(ctx, http.MethodPost, "/rest/api/3/search/jql", req, &page); err != nil {
+			return err
+		}
+
+		for i := range page.Issues {
+			ticket, err := c.toTicket(ctx, &page.Issues[i])
+			if err != nil {
+				return err
+			}
+			if err := fn(ticket); err != nil {
+				return err
+			}
+			seen++
+		}
+
+		if page.IsLast || page.NextPageToken == "" || len(page.Issues) == 0 {
+			return nil
+		}
+		if limit > 0 && seen >= limit {
+			return nil
+		}
+		req.NextPageToken = page.NextPageToken
+	}
+}
@@ -0,0 +1,276 @@
+package jira
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Self-test checks, in the order they run.
+const (
+	CheckDNS     = "dns"
+	CheckTLS     = "tls"
+	CheckAuth    = "auth"
+	CheckProject = "project"
+	CheckJQL     = "jql"
+)
+
+// SelfTestCheck is the outcome of one self-test check.
+type SelfTestCheck struct {
+	// Name is the check, one of the Check constants
+	Name string
+
+	// Detail describes what the check found when it passed
+	Detail string
+
+	// Err is why the check failed (nil if it passed)
+	Err error
+
+	// Hint suggests how to fix the failure
+	Hint string
+}
+
+// SelfTestReport is the outcome of a self-test. Checks stop at the first failure, since
+// every later check would fail for the same reason.
+type SelfTestReport struct {
+	Checks []SelfTestCheck
+}
+
+// Failed returns the check that failed, or nil if all passed.
+func (r *SelfTestReport) Failed() *SelfTestCheck {
+	for i := range r.Checks {
+		if r.Checks[i].Err != nil {
+			return &r.Checks[i]
+		}
+	}
+	return nil
+}
+
+// myselfResponse is the subset of GET /rest/api/3/myself jiramd reads.
+type myselfResponse struct {
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// SelfTest checks step by step that Jira can be used for projectKey: that the host in
+// the base URL resolves, that a TLS connection can be made, that the credentials are
+// accepted, that the project is visible, and that it can be searched. Each failure
+// comes with a hint for fixing it.
+func (c *Client) SelfTest(ctx context.Context, projectKey string) *SelfTestReport {
+	report := &SelfTestReport{}
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) (string, error)
+	}{
+		{CheckDNS, c.checkDNS},
+		{CheckTLS, c.checkTLS},
+		{CheckAuth, c.checkAuth},
+		{CheckProject, func(ctx context.Context) (string, error) { return c.checkProject(ctx, projectKey) }},
+		{CheckJQL, func(ctx context.Context) (string, error) { return c.checkJQL(ctx, projectKey) }},
+	}
+
+	for _, check := range checks {
+		detail, err := check.run(ctx)
+		result := SelfTestCheck{Name: check.name, Detail: detail, Err: err}
+		if err != nil {
+			result.Hint = selfTestHint(check.name, projectKey, err)
+		}
+		report.Checks = append(report.Checks, result)
+		if err != nil {
+			break
+		}
+	}
+	return report
+}
+
+// checkDNS resolves the host of the base URL, unless requests go through a proxy that
+// resolves it instead.
+func (c *Client) checkDNS(ctx context.Context) (string, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("%w: invalid base URL %q", domain.ErrConfig, c.baseURL)
+	}
+
+	if proxy := c.proxyFor(u); proxy != nil {
+		return fmt.Sprintf("%s is resolved by the proxy %s", u.Hostname(), proxy.Host), nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, u.Hostname())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s resolves to %s", u.Hostname(), strings.Join(addrs, ", ")), nil
+}
+
+// proxyFor returns the proxy requests to u are sent through, or nil if there is none.
+func (c *Client) proxyFor(u *url.URL) *url.URL {
+	transport, ok := c.readClient.Transport.(*http.Transport)
+	if !ok {
+		transport, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if !ok || transport.Proxy == nil {
+		return nil
+	}
+	proxy, err := transport.Proxy(&http.Request{URL: u})
+	if err != nil {
+		return nil
+	}
+	return proxy
+}
+
+// checkTLS connects to Jira without credentials; any HTTP response means the connection
+// and TLS handshake work.
+func (c *Client) checkTLS(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL+"/status", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.readClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if resp.TLS == nil {
+		return "connected without TLS", nil
+	}
+	detail := "connected with " + tls.VersionName(resp.TLS.Version)
+	if certs := resp.TLS.PeerCertificates; len(certs) > 0 {
+		detail += fmt.Sprintf(", certificate for %s issued by %s", certs[0].Subject.CommonName, certs[0].Issuer.CommonName)
+	}
+	return detail, nil
+}
+
+// checkAuth asks Jira who the credentials belong to.
+func (c *Client) checkAuth(ctx context.Context) (string, error) {
+	var body myselfResponse
+	if err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/myself", nil, &body); err != nil {
+		return "", err
+	}
+	if body.EmailAddress == "" {
+		return "authenticated as " + body.DisplayName, nil
+	}
+	return fmt.Sprintf("authenticated as %s <%s>", body.DisplayName, body.EmailAddress), nil
+}
+
+// checkProject fetches the project.
+func (c *Client) checkProject(ctx context.Context, projectKey string) (string, error) {
+	project, err := c.GetProject(ctx, projectKey)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("project %s (%s) is visible", project.Key, project.Name), nil
+}
+
+// checkJQL runs a search for a single ticket of the project.
+func (c *Client) checkJQL(ctx context.Context, projectKey string) (string, error) {
+	tickets, err := c.SearchTickets(ctx, selfTestJQL(projectKey), 1)
+	if err != nil {
+		return "", err
+	}
+	if len(tickets) == 0 {
+		return "search works; the project has no tickets yet", nil
+	}
+	return "search works; latest ticket is " + tickets[0].Key.String(), nil
+}
+
+// selfTestJQL is the query run by the jql check.
+func selfTestJQL(projectKey string) string {
+	return fmt.Sprintf("project = %q ORDER BY updated DESC", projectKey)
+}
+
+// selfTestHint suggests how to fix err, the failure of the named check.
+func selfTestHint(check, projectKey string, err error) string {
+	if hint := connectionHint(err); hint != "" {
+		return hint
+	}
+
+	var jiraErr *domain.JiraError
+	status := 0
+	if errors.As(err, &jiraErr) {
+		status = jiraErr.Status
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrConfig):
+		return "Set jira.base_url to your Jira site, e.g. https://your-team.atlassian.net."
+	case status == http.StatusUnauthorized:
+		return "Jira rejected the credentials. Check that jira.email is the address of the account " +
+			"that owns jira.token, and that the token has not expired or been revoked " +
+			"(create a new one at https://id.atlassian.com/manage-profile/security/api-tokens)."
+	case status == http.StatusForbidden && check == CheckAuth:
+		return "The account is not allowed to use the API. Log in to Jira in a browser once to clear " +
+			"a login CAPTCHA, or ask a Jira admin whether API access is restricted."
+	case errors.Is(err, domain.ErrNotFound) && check == CheckProject,
+		errors.Is(err, domain.ErrUnauthorized) && check == CheckProject:
+		return fmt.Sprintf("Project %s does not exist or the account cannot browse it. Check jira.project, "+
+			"and ask a Jira admin for the Browse Projects permission (see jiramd check-permissions).", projectKey)
+	case errors.Is(err, domain.ErrNotFound):
+		return "Jira has no REST API at jira.base_url. Check that it is the root URL of your Jira site, " +
+			"without a path such as /jira or /browse."
+	case errors.Is(err, domain.ErrInvalidInput) && check == CheckJQL:
+		return fmt.Sprintf("Jira rejected the query %s; check that jira.project is a project key, not its name.", selfTestJQL(projectKey))
+	case errors.Is(err, domain.ErrRateLimited):
+		return "Jira is rate limiting this account. Wait a few minutes before starting again."
+	case errors.Is(err, domain.ErrUnavailable):
+		return "Jira is unavailable. Check https://status.atlassian.com and try again later."
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "The self-test was interrupted before Jira answered."
+	default:
+		return "Check the Jira settings in the config file."
+	}
+}
+
+// connectionHint suggests how to fix a failure to reach Jira, or returns "" if err is
+// not a connection failure.
+func connectionHint(err error) string {
+	var (
+		dnsErr       *net.DNSError
+		unknownCA    x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidCert  x509.CertificateInvalidError
+		recordErr    tls.RecordHeaderError
+		urlErr       *url.Error
+		netErr       net.Error
+		proxyConnect bool
+	)
+	if errors.As(err, &urlErr) {
+		var opErr *net.OpError
+		proxyConnect = errors.As(urlErr.Err, &opErr) && opErr.Op == "proxyconnect"
+	}
+
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return fmt.Sprintf("The host %s does not exist. Check jira.base_url for typos.", dnsErr.Name)
+	case errors.As(err, &dnsErr):
+		return "The DNS server could not be reached. Check the network connection and VPN."
+	case proxyConnect:
+		return "The proxy could not be reached. Check jira.http.proxy, or the HTTPS_PROXY environment variable."
+	case errors.As(err, &unknownCA):
+		return "Jira's certificate is signed by an unknown authority, usually because a proxy inspects TLS " +
+			"traffic. Set jira.http.ca_file to your organization's CA bundle."
+	case errors.As(err, &hostnameErr):
+		return "Jira's certificate does not match the host. Check that jira.base_url uses the site's own host name."
+	case errors.As(err, &invalidCert):
+		return "Jira's certificate is not valid; if it is reported as expired, check the system clock."
+	case errors.As(err, &recordErr):
+		return "The server did not answer with TLS. Check the scheme and port of jira.base_url."
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "The connection was refused. Check the port in jira.base_url and any firewall or proxy."
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "Connecting to Jira timed out. Check the network connection, VPN, and jira.http.proxy, " +
+			"or raise jira.http.connect_timeout."
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,141 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// selfTestServer serves the endpoints the self-test calls; status overrides the status
+// code returned for a path.
+func selfTestServer(t *testing.T, status map[string]int) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code, ok := status[r.URL.Path]; ok {
+			w.WriteHeader(code)
+			fmt.Fprint(w, `{"errorMessages":["denied"]}`)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/status":
+			w.WriteHeader(http.StatusOK)
+		case "/rest/api/3/myself":
+			fmt.Fprint(w, `{"displayName":"Alice","emailAddress":"alice@example.com"}`)
+		case "/rest/api/3/project/JMD":
+			fmt.Fprint(w, `{"key":"JMD","name":"jiramd"}`)
+		case "/rest/api/3/search/jql":
+			fmt.Fprint(w, `{"issues":[],"isLast":true}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClient_SelfTest(t *testing.T) {
+	server := selfTestServer(t, nil)
+	defer server.Close()
+
+	report := NewClient(server.URL, "alice@example.com", "secret").SelfTest(context.Background(), "JMD")
+	if failed := report.Failed(); failed != nil {
+		t.Fatalf("check %s failed: %v", failed.Name, failed.Err)
+	}
+
+	var names []string
+	for _, check := range report.Checks {
+		names = append(names, check.Name)
+	}
+	if got, want := strings.Join(names, ","), "dns,tls,auth,project,jql"; got != want {
+		t.Errorf("checks = %s, want %s", got, want)
+	}
+	if got := report.Checks[2].Detail; got != "authenticated as Alice <alice@example.com>" {
+		t.Errorf("auth detail = %q", got)
+	}
+}
+
+func TestClient_SelfTest_Failures(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    map[string]int
+		wantCheck string
+		wantHint  string
+	}{
+		{
+			name:      "rejected credentials",
+			status:    map[string]int{"/rest/api/3/myself": http.StatusUnauthorized},
+			wantCheck: CheckAuth,
+			wantHint:  "jira.email",
+		},
+		{
+			name:      "invisible project",
+			status:    map[string]int{"/rest/api/3/project/JMD": http.StatusNotFound},
+			wantCheck: CheckProject,
+			wantHint:  "Browse Projects",
+		},
+		{
+			name:      "rejected query",
+			status:    map[string]int{"/rest/api/3/search/jql": http.StatusBadRequest},
+			wantCheck: CheckJQL,
+			wantHint:  "project key",
+		},
+		{
+			name:      "unavailable",
+			status:    map[string]int{"/rest/api/3/myself": http.StatusServiceUnavailable},
+			wantCheck: CheckAuth,
+			wantHint:  "status.atlassian.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := selfTestServer(t, tt.status)
+			defer server.Close()
+
+			report := NewClient(server.URL, "alice@example.com", "secret").SelfTest(context.Background(), "JMD")
+			failed := report.Failed()
+			if failed == nil {
+				t.Fatal("self-test passed, want a failure")
+			}
+			if failed.Name != tt.wantCheck {
+				t.Errorf("failed check = %s, want %s", failed.Name, tt.wantCheck)
+			}
+			if !strings.Contains(failed.Hint, tt.wantHint) {
+				t.Errorf("hint = %q, want it to mention %q", failed.Hint, tt.wantHint)
+			}
+			if last := report.Checks[len(report.Checks)-1]; last.Name != tt.wantCheck {
+				t.Errorf("checks continued after the failure up to %s", last.Name)
+			}
+		})
+	}
+}
+
+func TestClient_SelfTest_UnknownCertificateAuthority(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	report := NewClient(server.URL, "alice@example.com", "secret").SelfTest(context.Background(), "JMD")
+	failed := report.Failed()
+	if failed == nil || failed.Name != CheckTLS {
+		t.Fatalf("failed check = %+v, want tls", failed)
+	}
+	if !strings.Contains(failed.Hint, "jira.http.ca_file") {
+		t.Errorf("hint = %q, want it to mention jira.http.ca_file", failed.Hint)
+	}
+}
+
+func TestConnectionHint(t *testing.T) {
+	notFound := &net.DNSError{Err: "no such host", Name: "jira.exmaple.net", IsNotFound: true}
+	if hint := connectionHint(fmt.Errorf("lookup: %w", notFound)); !strings.Contains(hint, "jira.exmaple.net does not exist") {
+		t.Errorf("unknown host hint = %q", hint)
+	}
+
+	if hint := connectionHint(fmt.Errorf("not a network error")); hint != "" {
+		t.Errorf("hint for a non-network error = %q, want none", hint)
+	}
+}
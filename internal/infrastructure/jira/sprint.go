@@ -0,0 +1,105 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// agilePageSize is the number of results requested per Jira Software API page (its maximum is 50).
+const agilePageSize = 50
+
+// sprintJSON is a sprint as returned by the Jira Software (agile) API.
+type sprintJSON struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	State         string `json:"state"`
+	OriginBoardID int    `json:"originBoardId"`
+	StartDate     string `json:"startDate"`
+	EndDate       string `json:"endDate"`
+}
+
+// sprintPage is one page of GET /rest/agile/1.0/board/{boardId}/sprint.
+type sprintPage struct {
+	IsLast bool         `json:"isLast"`
+	Values []sprintJSON `json:"values"`
+}
+
+// sprintIssuePage is one page of GET /rest/agile/1.0/sprint/{sprintId}/issue.
+type sprintIssuePage struct {
+	Total  int `json:"total"`
+	Issues []struct {
+		Key string `json:"key"`
+	} `json:"issues"`
+}
+
+// toSprint maps an agile API sprint to a domain sprint. Unparsable dates are left zero.
+func (s sprintJSON) toSprint() *domain.Sprint {
+	sprint := &domain.Sprint{
+		ID:      s.ID,
+		Name:    s.Name,
+		State:   domain.SprintState(s.State),
+		BoardID: s.OriginBoardID,
+	}
+	if start, err := time.Parse(time.RFC3339, s.StartDate); err == nil {
+		sprint.Start = start.UTC()
+	}
+	if end, err := time.Parse(time.RFC3339, s.EndDate); err == nil {
+		sprint.End = end.UTC()
+	}
+	return sprint
+}
+
+// ActiveSprints returns the sprints in progress on a Jira Software board, oldest first.
+// Returns ErrNotFound if the board does not exist or is not visible to the user.
+func (c *Client) ActiveSprints(ctx context.Context, boardID int) ([]*domain.Sprint, error) {
+	sprints := make([]*domain.Sprint, 0)
+	for startAt := 0; ; {
+		path := fmt.Sprintf("/rest/agile/1.0/board/%d/sprint?state=active&startAt=%d&maxResults=%d",
+			boardID, startAt, agilePageSize)
+
+		var page sprintPage
+		if err := c.doRequest(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, sprint := range page.Values {
+			sprints = append(sprints, sprint.toSprint())
+		}
+
+		if page.IsLast || len(page.Values) == 0 {
+			return sprints, nil
+		}
+		startAt += len(page.Values)
+	}
+}
+
+// SprintTicketKeys returns the keys of every ticket in a sprint.
+// Returns ErrNotFound if the sprint does not exist.
+func (c *Client) SprintTicketKeys(ctx context.Context, sprintID int) ([]string, error) {
+	keys := make([]string, 0)
+	for startAt := 0; ; {
+		query := url.Values{
+			"fields":     {"key"},
+			"startAt":    {fmt.Sprint(startAt)},
+			"maxResults": {fmt.Sprint(agilePageSize)},
+		}
+		path := fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue?%s", sprintID, query.Encode())
+
+		var page sprintIssuePage
+		if err := c.doRequest(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, issue := range page.Issues {
+			keys = append(keys, issue.Key)
+		}
+
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			return keys, nil
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestClient_ActiveSprints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/board/42/sprint" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("state"); got != "active" {
+			t.Errorf("state = %q, want active", got)
+		}
+
+		resp := map[string]interface{}{"isLast": true}
+		if r.URL.Query().Get("startAt") == "0" {
+			resp = map[string]interface{}{
+				"isLast": false,
+				"values": []interface{}{map[string]interface{}{
+					"id": 12, "name": "JMD Sprint 12", "state": "active", "originBoardId": 42,
+					"startDate": "2026-10-05T09:00:00.000Z", "endDate": "2026-10-19T09:00:00.000Z",
+				}},
+			}
+		} else {
+			resp["values"] = []interface{}{map[string]interface{}{
+				"id": 13, "name": "Hotfixes", "state": "active", "originBoardId": 42,
+			}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	sprints, err := NewClient(server.URL, "me@example.com", "secret").ActiveSprints(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("ActiveSprints failed: %v", err)
+	}
+
+	want := []*domain.Sprint{
+		{
+			ID: 12, Name: "JMD Sprint 12", State: domain.SprintActive, BoardID: 42,
+			Start: time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC),
+			End:   time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{ID: 13, Name: "Hotfixes", State: domain.SprintActive, BoardID: 42},
+	}
+	if !reflect.DeepEqual(sprints, want) {
+		t.Errorf("ActiveSprints() = %+v, want %+v", sprints, want)
+	}
+}
+
+func TestClient_SprintTicketKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/sprint/12/issue" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+
+		issues := []interface{}{map[string]interface{}{"key": "JMD-1"}, map[string]interface{}{"key": "JMD-2"}}
+		if r.URL.Query().Get("startAt") != "0" {
+			issues = []interface{}{map[string]interface{}{"key": "OPS-7"}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"total": 3, "issues": issues})
+	}))
+	defer server.Close()
+
+	keys, err := NewClient(server.URL, "me@example.com", "secret").SprintTicketKeys(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("SprintTicketKeys failed: %v", err)
+	}
+	if want := []string{"JMD-1", "JMD-2", "OPS-7"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("SprintTicketKeys() = %v, want %v", keys, want)
+	}
+}
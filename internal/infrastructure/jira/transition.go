@@ -0,0 +1,88 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// transition is a workflow transition available on an issue.
+type transition struct {
+	ID   string     `json:"id"`
+	Name string     `json:"name"`
+	To   namedField `json:"to"`
+}
+
+// transitionsResponse is the body of GET /rest/api/3/issue/{key}/transitions.
+type transitionsResponse struct {
+	Transitions []transition `json:"transitions"`
+}
+
+// transitionRequest is the body of POST /rest/api/3/issue/{key}/transitions.
+type transitionRequest struct {
+	Transition struct {
+		ID string `json:"id"`
+	} `json:"transition"`
+}
+
+// TransitionTicket moves a ticket to status, given by its local name or an alias (see
+// WithStatusMap), through the workflow transition leading to it.
+// Returns ErrInvalidInput if the ticket's workflow has no transition to the status from
+// its current one, and ErrNotFound if the ticket doesn't exist.
+func (c *Client) TransitionTicket(ctx context.Context, key, status string) error {
+	target := c.statuses.ToJira(status)
+	available, err := c.transitions(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	var req transitionRequest
+	names := make([]string, 0, len(available.Transitions))
+	for _, t := range available.Transitions {
+		if strings.EqualFold(t.To.Name, target) {
+			req.Transition.ID = t.ID
+			break
+		}
+		names = append(names, c.statuses.ToLocal(t.To.Name))
+	}
+	if req.Transition.ID == "" {
+		return fmt.Errorf("%w: %s cannot move to %q from its current status (available: %s)",
+			domain.ErrInvalidInput, key, status, strings.Join(names, ", "))
+	}
+
+	return c.doRequest(ctx, http.MethodPost, transitionsPath(key), req, nil)
+}
+
+// AvailableTransitions returns the statuses a ticket can be moved to from its current
+// one, under their local names.
+// Returns ErrNotFound if the ticket doesn't exist.
+func (c *Client) AvailableTransitions(ctx context.Context, key string) ([]string, error) {
+	available, err := c.transitions(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]string, 0, len(available.Transitions))
+	for _, t := range available.Transitions {
+		statuses = append(statuses, c.statuses.ToLocal(t.To.Name))
+	}
+	return statuses, nil
+}
+
+// transitions lists the workflow transitions available on an issue.
+func (c *Client) transitions(ctx context.Context, key string) (*transitionsResponse, error) {
+	var available transitionsResponse
+	if err := c.doRequest(ctx, http.MethodGet, transitionsPath(key), nil, &available); err != nil {
+		return nil, err
+	}
+	return &available, nil
+}
+
+// transitionsPath returns the transitions endpoint of an issue.
+func transitionsPath(key string) string {
+	return "/rest/api/3/issue/" + url.PathEscape(key) + "/transitions"
+}
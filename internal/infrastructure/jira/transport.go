@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// NewTransport builds the HTTP transport for connecting to Jira with cfg: its proxy (or
+// the HTTPS_PROXY, HTTP_PROXY, and NO_PROXY environment variables), connect timeout,
+// extra trusted certificate authorities, and client certificate.
+// Returns domain.ErrInvalidInput when a certificate file cannot be loaded.
+func NewTransport(cfg domain.HTTPConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = domain.DefaultConnectTimeout
+	}
+	dialer := &net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}
+	transport.DialContext = dialer.DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+
+	if cfg.Proxy != "" {
+		proxy, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid proxy URL: %v", domain.ErrInvalidInput, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CAFile != "" {
+		pool, err := certPool(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertFile != "" || cfg.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to load client certificate: %v", domain.ErrInvalidInput, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// certPool returns the system certificate pool with the certificates of the PEM bundle
+// at path added.
+func certPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read CA bundle: %v", domain.ErrInvalidInput, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		// Not every platform exposes its trust store; trust only the bundle there
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: CA bundle %s contains no PEM certificates", domain.ErrInvalidInput, path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,130 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// searchHandler answers every request with an empty search page.
+var searchHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]interface{}{"issues": []interface{}{}, "isLast": true})
+})
+
+func TestNewClientFromConfig_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(searchHandler)
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatalf("write CA bundle: %v", err)
+	}
+
+	jiraConfig := domain.JiraConfig{BaseURL: server.URL, Email: "me@example.com", Token: "secret"}
+
+	// The server's certificate is not trusted by default
+	client, err := NewClientFromConfig(jiraConfig)
+	if err != nil {
+		t.Fatalf("NewClientFromConfig failed: %v", err)
+	}
+	if _, err := client.SearchTickets(context.Background(), "project = JMD", 0); err == nil {
+		t.Fatal("expected a certificate error without the CA bundle")
+	}
+
+	jiraConfig.HTTP.CAFile = caFile
+	client, err = NewClientFromConfig(jiraConfig)
+	if err != nil {
+		t.Fatalf("NewClientFromConfig failed: %v", err)
+	}
+	if _, err := client.SearchTickets(context.Background(), "project = JMD", 0); err != nil {
+		t.Fatalf("SearchTickets with the CA bundle failed: %v", err)
+	}
+}
+
+func TestNewClientFromConfig_Proxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		searchHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	client, err := NewClientFromConfig(domain.JiraConfig{
+		BaseURL: "http://jira.invalid",
+		Email:   "me@example.com",
+		Token:   "secret",
+		HTTP:    domain.HTTPConfig{Proxy: proxy.URL},
+	})
+	if err != nil {
+		t.Fatalf("NewClientFromConfig failed: %v", err)
+	}
+	if _, err := client.SearchTickets(context.Background(), "project = JMD", 0); err != nil {
+		t.Fatalf("SearchTickets failed: %v", err)
+	}
+
+	if len(proxied) != 1 || proxied[0] != "http://jira.invalid/rest/api/3/search/jql" {
+		t.Errorf("proxied requests = %v, want the search sent through the proxy", proxied)
+	}
+}
+
+func TestNewTransport_InvalidCertificates(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  domain.HTTPConfig
+	}{
+		{"missing CA bundle", domain.HTTPConfig{CAFile: filepath.Join(dir, "missing.pem")}},
+		{"CA bundle without certificates", domain.HTTPConfig{CAFile: notPEM}},
+		{"invalid client certificate", domain.HTTPConfig{ClientCertFile: notPEM, ClientKeyFile: notPEM}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTransport(tt.cfg); !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("NewTransport() error = %v, want %v", err, domain.ErrInvalidInput)
+			}
+		})
+	}
+}
+
+func TestClient_TimeoutPerOperation(t *testing.T) {
+	client, err := NewClientFromConfig(domain.JiraConfig{
+		BaseURL: "https://example.atlassian.net",
+		HTTP:    domain.HTTPConfig{ReadTimeout: time.Minute, WriteTimeout: 2 * time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("NewClientFromConfig failed: %v", err)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		want   time.Duration
+	}{
+		{http.MethodGet, "/rest/api/3/issue/JMD-1", time.Minute},
+		{http.MethodPost, "/rest/api/3/search/jql", time.Minute},
+		{http.MethodPut, "/rest/api/3/issue/JMD-1", 2 * time.Minute},
+		{http.MethodPost, "/rest/api/3/issue/bulk", 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := client.httpClient(tt.method, tt.path).Timeout; got != tt.want {
+			t.Errorf("timeout of %s %s = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// editRequest is the body of PUT /rest/api/3/issue/{key}.
+// Fields holds only the fields being changed, so Jira leaves every other field alone.
+// Update holds add and remove operations on labels, which keep the labels they do not
+// name, so labels added in Jira meanwhile are not dropped.
+type editRequest struct {
+	Fields map[string]interface{}              `json:"fields,omitempty"`
+	Update map[string][]map[string]interface{} `json:"update,omitempty"`
+}
+
+// empty reports whether the edit changes nothing.
+func (r *editRequest) empty() bool {
+	return len(r.Fields) == 0 && len(r.Update) == 0
+}
+
+// editLabels adds operations adding the labels add and removing the labels remove.
+func (r *editRequest) editLabels(add, remove []string) {
+	var ops []map[string]interface{}
+	for _, label := range add {
+		ops = append(ops, map[string]interface{}{"add": label})
+	}
+	for _, label := range remove {
+		ops = append(ops, map[string]interface{}{"remove": label})
+	}
+	if len(ops) == 0 {
+		return
+	}
+	if r.Update == nil {
+		r.Update = make(map[string][]map[string]interface{})
+	}
+	r.Update["labels"] = append(r.Update["labels"], ops...)
+}
+
+// editableFields are the fields UpdateTicket writes. Status changes need a transition
+// and assignees an account ID, so neither is edited here.
+var editableFields = []string{"summary", "description", "issuetype", "priority", "labels"}
+
+// newEditRequest builds an edit of the given fields from their values in base, the
+// revision ticket was edited from, to their values in ticket. Labels are edited by
+// adding and removing the labels that differ (see domain.DiffLabels), and the priority
+// is sent under its Jira name in priorities.
+func newEditRequest(ticket, base *domain.Ticket, fields []string, priorities domain.PriorityMap) editRequest {
+	req := editRequest{Fields: make(map[string]interface{}, len(fields))}
+	for _, field := range fields {
+		switch field {
+		case "summary":
+			req.Fields[field] = ticket.Summary
+		case "description":
+			// A nil document clears the description
+			req.Fields[field] = textToADF(ticket.Description)
+		case "issuetype":
+			if ticket.IssueType != "" {
+				req.Fields[field] = namedField{Name: ticket.IssueType}
+			}
+		case "priority":
+			if ticket.Priority != "" {
+				req.Fields[field] = namedField{Name: priorities.ToJira(ticket.Priority)}
+			}
+		case "labels":
+			req.editLabels(domain.DiffLabels(base.Labels, ticket.Labels))
+		}
+	}
+	return req
+}
+
+// UpdateTicket writes the ticket's summary, description, issue type, priority, and
+// labels to Jira and returns the ticket as Jira now has it. Only fields that differ from
+// the revision the local edit was made on are sent, so fields edited in Jira are left
+// alone; if none differ, nothing is written. Labels are sent as the labels added and
+// removed locally, never as the whole list.
+//
+// An issue type change is checked first (see MissingFieldsForType): UpdateTicket returns
+// a *domain.MissingFieldsError without writing anything when the new type requires
+// fields the ticket has no value for, which ChangeIssueType can set along with the type.
+//
+// The Jira REST API has no conditional edit, so UpdateTicket fetches the ticket first
+// and compares its version with ticket.Version(), the revision the local edit was made
+// on. If Jira changed since then it returns ErrConflict (also matching ErrSyncConflict)
+// without writing anything, so the change goes through conflict resolution instead of
+// overwriting someone else's edit.
+//
+// Changed fields the user may not edit, such as fields hidden from the ticket's edit
+// screen, are left out with a warning rather than failing the whole edit.
+//
+// Fields configured as local_only (see WithFieldDirections) are never sent. If a
+// jira_to_local field was edited locally, UpdateTicket returns ErrReadOnlyField without
+// writing anything, so the edit is flagged for the user to revert instead of pushed.
+// An edit made in Jira between the check and the write is still overwritten; callers
+// hold the ticket lock, so that window is a single round trip.
+// Returns ErrInvalidInput if the ticket was never pulled from Jira.
+func (c *Client) UpdateTicket(ctx context.Context, ticket *domain.Ticket) (*domain.Ticket, error) {
+	key := ticket.Key.String()
+	remote, err := c.checkVersion(ctx, ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	// The remote ticket is still at the revision the local edit was made on, so it is
+	// the snapshot to diff against
+	changed := ticket.ChangedFields(remote)
+	if c.fieldDirections != nil {
+		var readOnly []string
+		changed, readOnly = c.fieldDirections(ticket.Key.ProjectKey()).Pushable(changed)
+		if len(readOnly) > 0 {
+			return nil, fmt.Errorf("%w; revert the local edit to push %s", domain.ReadOnlyFieldError(ticket.Key, readOnly), key)
+		}
+	}
+
+	written, err := c.writeChanges(ctx, ticket, remote, changed)
+	if err != nil || !written {
+		return remote, err
+	}
+
+	// The edit bumps Jira's updated timestamp, which becomes the new version
+	return c.fetchFreshTicket(ctx, key)
+}
+
+// writeChanges writes the fields of ticket named in changed, which differ from remote,
+// the revision Jira has, in one edit, and reports whether anything was written. Fields
+// UpdateTicket does not write (see editableFields) and fields the user may not edit
+// are left out. An issue type change is checked as in UpdateTicket.
+func (c *Client) writeChanges(ctx context.Context, ticket, remote *domain.Ticket, changed []string) (bool, error) {
+	key := ticket.Key.String()
+	var fields []string
+	for _, field := range changed {
+		if slices.Contains(editableFields, field) {
+			fields = append(fields, field)
+		}
+	}
+	fields, err := c.dropUneditable(ctx, key, fields)
+	if err != nil {
+		return false, err
+	}
+	if slices.Contains(fields, "issuetype") && ticket.IssueType != "" {
+		change, err := c.planTypeChange(ctx, key, ticket.IssueType)
+		if err != nil {
+			return false, err
+		}
+		if err := change.check(key, nil); err != nil {
+			return false, err
+		}
+	}
+	req := newEditRequest(ticket, remote, fields, c.priorityMap(ticket.Key.ProjectKey()))
+	if req.empty() {
+		return false, nil
+	}
+
+	path := "/rest/api/3/issue/" + url.PathEscape(key)
+	if err := c.doRequest(ctx, http.MethodPut, path, req, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkVersion fetches a ticket from Jira and returns it, or ErrConflict (also matching
+// ErrSyncConflict) if Jira changed it since ticket.Version(), the revision a local edit
+// was made on. Returns ErrInvalidInput if the ticket was never pulled from Jira.
+func (c *Client) checkVersion(ctx context.Context, ticket *domain.Ticket) (*domain.Ticket, error) {
+	key := ticket.Key.String()
+	base := ticket.Version()
+	if base == "" {
+		return nil, fmt.Errorf("%w: ticket %s has no Jira version to update from", domain.ErrInvalidInput, key)
+	}
+
+	remote, err := c.fetchFreshTicket(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if remote.Version() != base {
+		return nil, fmt.Errorf("%w: %w: %s was updated in Jira at %s, after the local copy (%s)",
+			domain.ErrConflict, domain.ErrSyncConflict, key, remote.Version(), base)
+	}
+	return remote, nil
+}
+
+// UpdateLabels adds the labels add to a ticket and removes the labels remove from it in
+// one edit, leaving its other labels alone, so labels added or removed in Jira by
+// others are kept. Labels are deduplicated; a label in both lists is added. Nothing is
+// written when both lists are empty, or when the user may not edit the ticket's labels
+// (with a warning, as in UpdateFields).
+func (c *Client) UpdateLabels(ctx context.Context, key string, add, remove []string) error {
+	add = domain.NormalizeLabels(add)
+	remove = slices.DeleteFunc(domain.NormalizeLabels(remove), func(label string) bool {
+		return slices.Contains(add, label)
+	})
+
+	var req editRequest
+	req.editLabels(add, remove)
+	if req.empty() {
+		return nil
+	}
+	editable, err := c.dropUneditable(ctx, key, []string{"labels"})
+	if err != nil || len(editable) == 0 {
+		return err
+	}
+	return c.doRequest(ctx, http.MethodPut, "/rest/api/3/issue/"+url.PathEscape(key), req, nil)
+}
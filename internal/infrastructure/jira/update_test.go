@@ -0,0 +1,308 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// editServer serves one issue whose updated timestamp advances on every edit.
+type editServer struct {
+	updated time.Time
+	edits   []editRequest
+
+	// hidden are the fields missing from the issue's edit screen
+	hidden []string
+}
+
+func (s *editServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/JMD-1/editmeta" {
+		fields := make(map[string]interface{})
+		for _, field := range editableFields {
+			if !slices.Contains(s.hidden, field) {
+				fields[field] = map[string]interface{}{"required": field == "summary"}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"fields": fields})
+		return
+	}
+	if r.URL.Path != "/rest/api/3/issue/JMD-1" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		body := issueJSON("JMD-1", "Remote summary")
+		body["fields"].(map[string]interface{})["updated"] = s.updated.Format(jiraTimeLayout)
+		json.NewEncoder(w).Encode(body)
+	case http.MethodPut:
+		var req editRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.edits = append(s.edits, req)
+		s.updated = s.updated.Add(time.Minute)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestClient_UpdateTicket(t *testing.T) {
+	pulled := time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)
+	state := &editServer{updated: pulled}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+
+	ticket, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket failed: %v", err)
+	}
+	if ticket.Summary != "Remote summary" || !ticket.Updated.Equal(pulled) {
+		t.Fatalf("GetTicket() = %+v", ticket)
+	}
+
+	// Unchanged tickets are not written
+	if _, err := client.UpdateTicket(ctx, ticket); err != nil {
+		t.Fatalf("UpdateTicket without changes failed: %v", err)
+	}
+	if len(state.edits) != 0 {
+		t.Fatalf("unchanged ticket wrote %d edits", len(state.edits))
+	}
+
+	ticket.Summary = "Local summary"
+	ticket.Priority = "Low"
+	updated, err := client.UpdateTicket(ctx, ticket)
+	if err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(state.edits))
+	}
+	fields := state.edits[0].Fields
+	if len(fields) != 2 || fields["summary"] != "Local summary" || fields["priority"].(map[string]interface{})["name"] != "Low" {
+		t.Errorf("edit fields = %v, want only the changed summary and priority", fields)
+	}
+	if !updated.Updated.Equal(pulled.Add(time.Minute)) {
+		t.Errorf("returned Updated = %v, want Jira's new timestamp", updated.Updated)
+	}
+
+	// The local copy is now behind Jira, so a second write must not overwrite it
+	_, err = client.UpdateTicket(ctx, ticket)
+	if !errors.Is(err, domain.ErrConflict) || !errors.Is(err, domain.ErrSyncConflict) {
+		t.Errorf("UpdateTicket on stale ticket error = %v, want ErrConflict and ErrSyncConflict", err)
+	}
+	if len(state.edits) != 1 {
+		t.Errorf("stale update wrote to Jira: %d edits", len(state.edits))
+	}
+
+	// Pushing on top of the returned revision succeeds again
+	updated.Summary = "Second edit"
+	if _, err := client.UpdateTicket(ctx, updated); err != nil {
+		t.Errorf("UpdateTicket on current ticket failed: %v", err)
+	}
+
+	never := domain.NewTicket(ticket.Key, "Never pulled", time.Time{}, time.Time{})
+	if _, err := client.UpdateTicket(ctx, never); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("UpdateTicket without version error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestClient_UpdateTicket_FieldDirections(t *testing.T) {
+	state := &editServer{updated: time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	sync := domain.SyncConfig{
+		FieldDirections: domain.FieldDirections{"description": domain.SyncLocalOnly},
+		ProjectFieldDirections: map[string]domain.FieldDirections{
+			"JMD": {"labels": domain.SyncJiraToLocal},
+		},
+	}
+	client := NewClient(server.URL, "me@example.com", "secret").WithFieldDirections(sync.FieldDirectionsFor)
+	ctx := context.Background()
+
+	ticket, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket failed: %v", err)
+	}
+
+	// A read-only field edited locally is flagged and nothing is written
+	remoteLabels := ticket.Labels
+	ticket.Summary = "Local summary"
+	ticket.Labels = []string{"local"}
+	if _, err := client.UpdateTicket(ctx, ticket); !errors.Is(err, domain.ErrReadOnlyField) {
+		t.Fatalf("UpdateTicket with edited labels error = %v, want ErrReadOnlyField", err)
+	}
+	if len(state.edits) != 0 {
+		t.Fatalf("read-only edit wrote %d edits", len(state.edits))
+	}
+
+	// Local-only fields stay local
+	ticket.Labels = remoteLabels
+	ticket.Description = "Private notes"
+	if _, err := client.UpdateTicket(ctx, ticket); err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(state.edits))
+	}
+	if fields := state.edits[0].Fields; len(fields) != 1 || fields["summary"] != "Local summary" {
+		t.Errorf("edit fields = %v, want only the summary", fields)
+	}
+}
+
+func TestClient_UpdateTicket_UneditableFields(t *testing.T) {
+	state := &editServer{updated: time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC), hidden: []string{"priority"}}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret").WithLogger(slog.New(slog.DiscardHandler))
+	ctx := context.Background()
+
+	ticket, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket failed: %v", err)
+	}
+
+	// A field hidden from the edit screen is dropped rather than failing the edit
+	ticket.Summary = "Local summary"
+	ticket.Priority = "Low"
+	if _, err := client.UpdateTicket(ctx, ticket); err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(state.edits))
+	}
+	if fields := state.edits[0].Fields; len(fields) != 1 || fields["summary"] != "Local summary" {
+		t.Errorf("edit fields = %v, want only the editable summary", fields)
+	}
+
+	// Nothing is written when no changed field is editable
+	updated, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket failed: %v", err)
+	}
+	updated.Priority = "Low"
+	if _, err := client.UpdateTicket(ctx, updated); err != nil {
+		t.Fatalf("UpdateTicket of an uneditable field failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Errorf("uneditable edit wrote to Jira: %d edits", len(state.edits))
+	}
+}
+
+func TestClient_UpdateTicket_Labels(t *testing.T) {
+	state := &editServer{updated: time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+
+	ticket, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket failed: %v", err)
+	}
+	if !slices.Equal(ticket.Labels, []string{"backend"}) {
+		t.Fatalf("GetTicket() labels = %v, want [backend]", ticket.Labels)
+	}
+
+	// Reordered and repeated labels are not an edit
+	ticket.Labels = []string{"backend", "backend"}
+	if _, err := client.UpdateTicket(ctx, ticket); err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	if len(state.edits) != 0 {
+		t.Fatalf("repeated label wrote %d edits", len(state.edits))
+	}
+
+	// Edited labels are pushed as adds and removes, never as the whole list
+	ticket.Labels = []string{"api", "ui", "api"}
+	if _, err := client.UpdateTicket(ctx, ticket); err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(state.edits))
+	}
+	edit := state.edits[0]
+	if _, ok := edit.Fields["labels"]; ok {
+		t.Errorf("edit fields = %v, want labels not replaced", edit.Fields)
+	}
+	got, _ := json.Marshal(edit.Update["labels"])
+	if want := `[{"add":"api"},{"add":"ui"},{"remove":"backend"}]`; string(got) != want {
+		t.Errorf("labels update = %s, want %s", got, want)
+	}
+}
+
+func TestClient_UpdateTicket_PriorityMap(t *testing.T) {
+	state := &editServer{updated: time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	priorities := map[string]domain.PriorityMap{"JMD": {"P0": "Highest", "P1": "High"}}
+	client := NewClient(server.URL, "me@example.com", "secret").
+		WithPriorityMaps(func(projectKey string) domain.PriorityMap { return priorities[projectKey] })
+	ctx := context.Background()
+
+	ticket, err := client.GetTicket(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicket failed: %v", err)
+	}
+	if ticket.Priority != "P1" {
+		t.Fatalf("GetTicket() priority = %q, want P1", ticket.Priority)
+	}
+
+	ticket.Priority = "p0"
+	if _, err := client.UpdateTicket(ctx, ticket); err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(state.edits))
+	}
+	got, _ := json.Marshal(state.edits[0].Fields["priority"])
+	if want := `{"name":"Highest"}`; string(got) != want {
+		t.Errorf("priority field = %s, want %s", got, want)
+	}
+}
+
+func TestClient_UpdateLabels(t *testing.T) {
+	state := &editServer{updated: time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC)}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	client := NewClient(server.URL, "me@example.com", "secret")
+	ctx := context.Background()
+
+	if err := client.UpdateLabels(ctx, "JMD-1", []string{"api", " api", "ui"}, []string{"old", "ui"}); err != nil {
+		t.Fatalf("UpdateLabels failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(state.edits))
+	}
+	got, _ := json.Marshal(state.edits[0])
+	if want := `{"update":{"labels":[{"add":"api"},{"add":"ui"},{"remove":"old"}]}}`; string(got) != want {
+		t.Errorf("edit = %s, want %s", got, want)
+	}
+
+	// Nothing to change writes nothing
+	if err := client.UpdateLabels(ctx, "JMD-1", nil, []string{" "}); err != nil {
+		t.Fatalf("UpdateLabels without labels failed: %v", err)
+	}
+	if len(state.edits) != 1 {
+		t.Errorf("empty label edit wrote to Jira: %d edits", len(state.edits))
+	}
+}
@@ -0,0 +1,96 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// userSearchLimit is the most users a user search returns; completion never needs more.
+const userSearchLimit = 50
+
+// toUser maps a Jira user to a domain user, or nil if there is none.
+func (u *user) toUser() *domain.User {
+	if u == nil {
+		return nil
+	}
+	return &domain.User{
+		AccountID:   u.AccountID,
+		DisplayName: u.DisplayName,
+		Email:       u.EmailAddress,
+		Active:      u.Active,
+		AvatarURL:   u.AvatarURLs[avatarSize],
+	}
+}
+
+// SearchUsers returns at most 50 users whose name or email starts with query, in Jira's
+// order. Users whose profile hides their email have an empty Email.
+// Returns ErrInvalidInput if query is empty.
+func (c *Client) SearchUsers(ctx context.Context, query string) ([]*domain.User, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: user search query is empty", domain.ErrInvalidInput)
+	}
+
+	params := url.Values{
+		"query":      {query},
+		"maxResults": {fmt.Sprint(userSearchLimit)},
+	}
+	var found []user
+	if err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/user/search?"+params.Encode(), nil, &found); err != nil {
+		return nil, err
+	}
+
+	users := make([]*domain.User, 0, len(found))
+	for i := range found {
+		users = append(users, found[i].toUser())
+	}
+	return users, nil
+}
+
+// SearchAssignableUsers returns at most 50 users whose name or email starts with query
+// and who can be assigned tickets in a project, in Jira's order.
+// Returns ErrInvalidInput if query is empty, and ErrNotFound if the project doesn't
+// exist or isn't visible to the user.
+func (c *Client) SearchAssignableUsers(ctx context.Context, projectKey, query string) ([]*domain.User, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: user search query is empty", domain.ErrInvalidInput)
+	}
+
+	params := url.Values{
+		"project":    {projectKey},
+		"query":      {query},
+		"maxResults": {fmt.Sprint(userSearchLimit)},
+	}
+	var found []user
+	if err := c.doRequest(ctx, http.MethodGet, "/rest/api/3/user/assignable/search?"+params.Encode(), nil, &found); err != nil {
+		return nil, err
+	}
+
+	users := make([]*domain.User, 0, len(found))
+	for i := range found {
+		users = append(users, found[i].toUser())
+	}
+	return users, nil
+}
+
+// GetUser returns the user with an account ID.
+// Returns ErrEmptyKey for an empty account ID, and ErrNotFound if no user has it.
+func (c *Client) GetUser(ctx context.Context, accountID string) (*domain.User, error) {
+	accountID = strings.TrimSpace(accountID)
+	if accountID == "" {
+		return nil, fmt.Errorf("%w: account ID cannot be empty", domain.ErrEmptyKey)
+	}
+
+	var found user
+	path := "/rest/api/3/user?" + url.Values{"accountId": {accountID}}.Encode()
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &found); err != nil {
+		return nil, err
+	}
+	return found.toUser(), nil
+}
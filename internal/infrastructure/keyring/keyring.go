@@ -0,0 +1,94 @@
+// Package keyring sources the key that encrypts the state database at rest, and the
+// bearer token of the control API, from the operating system's credential store (macOS
+// Keychain, Windows Credential Manager, or the Secret Service on Linux), so neither sits
+// next to the database file.
+package keyring
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Service is the keyring service name under which jiramd stores its keys.
+const Service = "jiramd"
+
+// KeyEnvVar names the environment variable that supplies the key (base64-encoded)
+// instead of the keyring, for headless servers without a credential store.
+const KeyEnvVar = "JIRAMD_ENCRYPTION_KEY"
+
+// APITokenEnvVar names the environment variable that supplies the control API token
+// instead of the keyring, for headless servers without a credential store.
+const APITokenEnvVar = "JIRAMD_CONTROL_TOKEN"
+
+// apiTokenSize is the number of random bytes in a generated control API token.
+const apiTokenSize = 32
+
+// EncryptionKey returns the size-byte database encryption key stored for account
+// (typically the database path). On first use a random key is generated and stored
+// in the keyring. $JIRAMD_ENCRYPTION_KEY takes precedence over the keyring.
+func EncryptionKey(account string, size int) ([]byte, error) {
+	if encoded := os.Getenv(KeyEnvVar); encoded != "" {
+		return decodeKey(encoded, size, KeyEnvVar)
+	}
+
+	encoded, err := secret(account, size, "encryption key", KeyEnvVar)
+	if err != nil {
+		return nil, err
+	}
+	return decodeKey(encoded, size, "keyring entry")
+}
+
+// APIToken returns the bearer token of the control API of the daemon whose database is
+// dbPath, which its clients send in the Authorization header. On first use a random
+// token is generated and stored in the keyring. $JIRAMD_CONTROL_TOKEN takes precedence
+// over the keyring.
+func APIToken(dbPath string) (string, error) {
+	if token := os.Getenv(APITokenEnvVar); token != "" {
+		return token, nil
+	}
+	return secret("api-token:"+dbPath, apiTokenSize, "control API token", APITokenEnvVar)
+}
+
+// secret returns the base64-encoded secret stored for account, generating and storing
+// size random bytes on first use. what names the secret in errors, and envVar the
+// variable that supplies it on systems without a keyring.
+func secret(account string, size int, what, envVar string) (string, error) {
+	encoded, err := gokeyring.Get(Service, account)
+	if err == nil {
+		return encoded, nil
+	}
+	if !errors.Is(err, gokeyring.ErrNotFound) {
+		return "", fmt.Errorf("%w: failed to read %s from keyring (set %s on systems without one): %v",
+			domain.ErrConfig, what, envVar, err)
+	}
+
+	value := make([]byte, size)
+	if _, err := rand.Read(value); err != nil {
+		return "", fmt.Errorf("failed to generate %s: %w", what, err)
+	}
+	encoded = base64.StdEncoding.EncodeToString(value)
+	if err := gokeyring.Set(Service, account, encoded); err != nil {
+		return "", fmt.Errorf("%w: failed to store %s in keyring: %v", domain.ErrConfig, what, err)
+	}
+
+	return encoded, nil
+}
+
+// decodeKey decodes a base64 key and checks its length.
+func decodeKey(encoded string, size int, source string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not valid base64: %v", domain.ErrConfig, source, err)
+	}
+	if len(key) != size {
+		return nil, fmt.Errorf("%w: %s must decode to %d bytes, got %d", domain.ErrConfig, source, size, len(key))
+	}
+	return key, nil
+}
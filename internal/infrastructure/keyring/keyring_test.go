@@ -0,0 +1,102 @@
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestEncryptionKey_GeneratesAndReuses(t *testing.T) {
+	gokeyring.MockInit()
+	t.Setenv(KeyEnvVar, "")
+
+	first, err := EncryptionKey("/tmp/jiramd.db", 32)
+	if err != nil {
+		t.Fatalf("EncryptionKey() error = %v", err)
+	}
+	if len(first) != 32 {
+		t.Fatalf("key length = %d, want 32", len(first))
+	}
+
+	second, err := EncryptionKey("/tmp/jiramd.db", 32)
+	if err != nil {
+		t.Fatalf("second EncryptionKey() error = %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("EncryptionKey() generated a new key instead of reusing the stored one")
+	}
+
+	other, err := EncryptionKey("/tmp/other.db", 32)
+	if err != nil {
+		t.Fatalf("EncryptionKey(other) error = %v", err)
+	}
+	if bytes.Equal(first, other) {
+		t.Error("different databases share a key")
+	}
+}
+
+func TestEncryptionKey_FromEnvironment(t *testing.T) {
+	gokeyring.MockInitWithError(errors.New("no keyring"))
+
+	want := bytes.Repeat([]byte{7}, 32)
+	t.Setenv(KeyEnvVar, base64.StdEncoding.EncodeToString(want))
+
+	got, err := EncryptionKey("/tmp/jiramd.db", 32)
+	if err != nil {
+		t.Fatalf("EncryptionKey() error = %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("EncryptionKey() did not use the key from the environment")
+	}
+
+	t.Setenv(KeyEnvVar, base64.StdEncoding.EncodeToString([]byte("short")))
+	if _, err := EncryptionKey("/tmp/jiramd.db", 32); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("EncryptionKey(short key) error = %v, want ErrConfig", err)
+	}
+
+	t.Setenv(KeyEnvVar, "")
+	if _, err := EncryptionKey("/tmp/jiramd.db", 32); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("EncryptionKey(no keyring) error = %v, want ErrConfig", err)
+	}
+}
+
+func TestAPIToken(t *testing.T) {
+	gokeyring.MockInit()
+	t.Setenv(APITokenEnvVar, "")
+
+	first, err := APIToken("/tmp/jiramd.db")
+	if err != nil {
+		t.Fatalf("APIToken() error = %v", err)
+	}
+	if raw, err := base64.StdEncoding.DecodeString(first); err != nil || len(raw) != apiTokenSize {
+		t.Errorf("APIToken() = %q, want %d random bytes in base64", first, apiTokenSize)
+	}
+	if second, err := APIToken("/tmp/jiramd.db"); err != nil || second != first {
+		t.Errorf("APIToken() = %q, %v; want the stored token reused", second, err)
+	}
+
+	// The token is not the database's encryption key
+	key, err := EncryptionKey("/tmp/jiramd.db", apiTokenSize)
+	if err != nil {
+		t.Fatalf("EncryptionKey() error = %v", err)
+	}
+	if base64.StdEncoding.EncodeToString(key) == first {
+		t.Error("APIToken() shares the keyring entry of the encryption key")
+	}
+
+	t.Setenv(APITokenEnvVar, "from-env")
+	if got, err := APIToken("/tmp/jiramd.db"); err != nil || got != "from-env" {
+		t.Errorf("APIToken() = %q, %v; want the token from the environment", got, err)
+	}
+
+	gokeyring.MockInitWithError(errors.New("no keyring"))
+	t.Setenv(APITokenEnvVar, "")
+	if _, err := APIToken("/tmp/jiramd.db"); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("APIToken(no keyring) error = %v, want ErrConfig", err)
+	}
+}
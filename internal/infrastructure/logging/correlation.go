@@ -0,0 +1,50 @@
+// Package logging provides slog handlers shared by the CLI and the daemon.
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// CorrelationKey is the attribute key of the correlation ID in log records.
+const CorrelationKey = "correlation_id"
+
+// CorrelationHandler adds the correlation ID carried by the context of a record (see
+// domain.CorrelationID) to the record, so every line logged about a sync cycle or a
+// queued operation can be found by its ID. Records logged without a context, or with
+// one carrying no ID, are passed through unchanged.
+type CorrelationHandler struct {
+	next slog.Handler
+}
+
+// NewCorrelationHandler creates a handler adding correlation IDs to records before
+// passing them to next.
+func NewCorrelationHandler(next slog.Handler) *CorrelationHandler {
+	return &CorrelationHandler{next: next}
+}
+
+// Enabled reports whether next handles records at level.
+func (h *CorrelationHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the correlation ID of ctx to r and passes it to next.
+func (h *CorrelationHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := domain.CorrelationIDFrom(ctx); id != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String(CorrelationKey, id.String()))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler adding correlation IDs to the records of next with attrs.
+func (h *CorrelationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &CorrelationHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler adding correlation IDs to the records of next with group.
+func (h *CorrelationHandler) WithGroup(name string) slog.Handler {
+	return &CorrelationHandler{next: h.next.WithGroup(name)}
+}
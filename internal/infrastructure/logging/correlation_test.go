@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestCorrelationHandler(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(NewCorrelationHandler(slog.NewTextHandler(&out, nil))).With("component", "sync")
+
+	ctx := domain.WithCorrelationID(context.Background(), "0123456789abcdef")
+	logger.InfoContext(ctx, "synced project", "project", "JMD")
+	logger.Info("started")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logged %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], "component=sync") || !strings.HasSuffix(lines[0], "project=JMD correlation_id=0123456789abcdef") {
+		t.Errorf("line = %q, want the attributes followed by the correlation ID", lines[0])
+	}
+	if strings.Contains(lines[1], CorrelationKey) {
+		t.Errorf("line = %q, want no correlation ID without one in the context", lines[1])
+	}
+}
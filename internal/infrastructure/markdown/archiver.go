@@ -0,0 +1,92 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Archiver moves the markdown files of tickets out of the synced directory into an
+// archive directory, keeping their path relative to the synced directory.
+type Archiver struct {
+	markdownDir string
+	archiveDir  string
+}
+
+// NewArchiver creates an archiver for the ticket files under markdownDir. archiveDir may
+// be inside markdownDir; files already archived are never looked at again.
+func NewArchiver(markdownDir, archiveDir string) *Archiver {
+	return &Archiver{
+		markdownDir: filepath.Clean(markdownDir),
+		archiveDir:  filepath.Clean(archiveDir),
+	}
+}
+
+// Archive moves the ticket's file (<KEY>.md, anywhere under the markdown directory) to
+// the archive directory, replacing an earlier archived copy. A ticket without a file is
+// not an error.
+func (a *Archiver) Archive(ctx context.Context, key domain.TicketKey) error {
+	path, err := a.find(ctx, key.String()+".md")
+	if err != nil || path == "" {
+		return err
+	}
+
+	rel, err := filepath.Rel(a.markdownDir, path)
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", path, err)
+	}
+	target := filepath.Join(a.archiveDir, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	if err := os.Rename(path, target); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", path, err)
+	}
+	return nil
+}
+
+// errFound stops the directory walk once the file is found.
+var errFound = errors.New("found")
+
+// find returns the path of the file named name under the markdown directory, outside
+// the archive directory, or "" if there is none.
+func (a *Archiver) find(ctx context.Context, name string) (string, error) {
+	return findFile(ctx, a.markdownDir, a.archiveDir, name)
+}
+
+// findFile returns the path of the first file named name under dir, skipping the files
+// under skipDir (empty skips nothing), or "" if there is none.
+func findFile(ctx context.Context, dir, skipDir, name string) (string, error) {
+	var found string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if skipDir != "" && path == skipDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() == name {
+			found = path
+			return errFound
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errFound) {
+		return "", fmt.Errorf("failed to find %s: %w", name, err)
+	}
+	return found, nil
+}
@@ -0,0 +1,60 @@
+package markdown
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestArchiver_Archive(t *testing.T) {
+	dir := t.TempDir()
+	archiveDir := filepath.Join(dir, "archive")
+	path := filepath.Join(dir, "PROJ", "PROJ-1.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# PROJ-1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archiver := NewArchiver(dir, archiveDir)
+	key := ticketKey(t, "PROJ-1")
+	if err := archiver.Archive(context.Background(), key); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file still at %s (err = %v)", path, err)
+	}
+	if got := readFile(t, filepath.Join(archiveDir, "PROJ", "PROJ-1.md")); got != "# PROJ-1\n" {
+		t.Errorf("archived content = %q", got)
+	}
+
+	// Already archived, and tickets that never had a file, are not errors
+	if err := archiver.Archive(context.Background(), key); err != nil {
+		t.Errorf("Archive() of archived ticket error = %v", err)
+	}
+	if err := archiver.Archive(context.Background(), ticketKey(t, "PROJ-2")); err != nil {
+		t.Errorf("Archive() of ticket without file error = %v", err)
+	}
+}
+
+func TestArchiver_Archive_MissingMarkdownDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+	archiver := NewArchiver(dir, filepath.Join(dir, "archive"))
+	if err := archiver.Archive(context.Background(), ticketKey(t, "PROJ-1")); err != nil {
+		t.Errorf("Archive() error = %v", err)
+	}
+}
+
+func ticketKey(t *testing.T, key string) domain.TicketKey {
+	t.Helper()
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ticketKey
+}
@@ -0,0 +1,148 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// EditedByKey is the frontmatter key naming who last edited a ticket file, set by the
+// people sharing a markdown directory. jiramd keeps it when rewriting files.
+const EditedByKey = "edited_by"
+
+// ChangeAuthors tells who made the local changes to ticket files, when several people
+// edit the markdown directory, from the sources of domain.SharedVaultConfig.
+type ChangeAuthors struct {
+	files     *TicketFiles
+	stateRepo repository.StateRepository
+	sources   []domain.AuthorSource
+	logger    *slog.Logger
+
+	// git runs git in a directory and returns its trimmed output (overridable in tests)
+	git func(ctx context.Context, dir string, args ...string) (string, error)
+}
+
+// NewChangeAuthors creates a ChangeAuthors reading the authors of the ticket files
+// located through files, at the paths recorded in stateRepo, from sources in order.
+func NewChangeAuthors(files *TicketFiles, stateRepo repository.StateRepository, sources []domain.AuthorSource, logger *slog.Logger) *ChangeAuthors {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ChangeAuthors{
+		files:     files,
+		stateRepo: stateRepo,
+		sources:   sources,
+		logger:    logger,
+		git:       runGit,
+	}
+}
+
+// ChangeAuthor returns the author of the latest local change to a ticket's file from
+// the first source that names one, or "" when none does, the ticket has no file, or
+// the sources cannot be read. Read failures are logged.
+func (a *ChangeAuthors) ChangeAuthor(ctx context.Context, key domain.TicketKey) string {
+	if len(a.sources) == 0 || key.IsZero() {
+		return ""
+	}
+	path, err := a.locate(ctx, key)
+	if err != nil {
+		a.logger.Warn("failed to locate ticket file for its author", "ticket_key", key.String(), "error", err)
+		return ""
+	}
+	if path == "" {
+		return ""
+	}
+
+	for _, source := range a.sources {
+		var author string
+		switch source {
+		case domain.AuthorFromFrontmatter:
+			author, err = a.frontmatterAuthor(path)
+		case domain.AuthorFromGit:
+			author, err = a.gitAuthor(ctx, path)
+		}
+		if err != nil {
+			a.logger.Warn("failed to read the author of a ticket file",
+				"ticket_key", key.String(), "source", source, "error", err)
+			continue
+		}
+		if author != "" {
+			return author
+		}
+	}
+	return ""
+}
+
+// locate returns the path of a ticket's file, or "" if it has none.
+func (a *ChangeAuthors) locate(ctx context.Context, key domain.TicketKey) (string, error) {
+	var recorded string
+	state, err := a.stateRepo.GetTicketState(ctx, key.String())
+	switch {
+	case err == nil:
+		recorded = state.FilePath
+	case !errors.Is(err, domain.ErrNotFound):
+		return "", fmt.Errorf("failed to get sync state of %s: %w", key, err)
+	}
+	return a.files.Locate(ctx, key, recorded)
+}
+
+// frontmatterAuthor returns the edited_by value of the file at path.
+func (a *ChangeAuthors) frontmatterAuthor(path string) (string, error) {
+	content, err := readOptional(path)
+	if err != nil || content == nil {
+		return "", err
+	}
+	sidecar, err := readOptional(SidecarPath(path))
+	if err != nil {
+		return "", err
+	}
+	frontmatter, _, err := a.files.parser.codec.Decode(content, sidecar)
+	if err != nil {
+		return "", fmt.Errorf("failed to read frontmatter of %s: %w", path, err)
+	}
+	return strings.TrimSpace(frontmatterString(frontmatter, EditedByKey)), nil
+}
+
+// gitAuthor returns the git user while the file at path has uncommitted edits, and
+// otherwise the author of its last commit. Files outside a git work tree have none.
+func (a *ChangeAuthors) gitAuthor(ctx context.Context, path string) (string, error) {
+	dir, name := filepath.Split(path)
+	if inside, err := a.git(ctx, dir, "rev-parse", "--is-inside-work-tree"); err != nil || inside != "true" {
+		return "", nil
+	}
+
+	status, err := a.git(ctx, dir, "status", "--porcelain", "--", name)
+	if err != nil {
+		return "", err
+	}
+	if status != "" {
+		// Edited since the last commit, or never committed: the edit is the local user's
+		user, err := a.git(ctx, dir, "config", "user.name")
+		if err != nil {
+			// An unset user.name exits with status 1
+			return "", nil
+		}
+		return user, nil
+	}
+	return a.git(ctx, dir, "log", "-1", "--format=%an", "--", name)
+}
+
+// runGit runs git in dir and returns its output without surrounding whitespace.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
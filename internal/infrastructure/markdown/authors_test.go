@@ -0,0 +1,71 @@
+package markdown
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository/fakes"
+)
+
+func TestChangeAuthors(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		if _, err := runGit(ctx, dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "JMD-1.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "--quiet")
+	git("config", "user.name", "Alice")
+	git("config", "user.email", "alice@example.com")
+	write("---\nkey: JMD-1\nsummary: Login\nedited_by: Bob\n---\n")
+	git("add", "JMD-1.md")
+	git("-c", "user.name=Carol", "commit", "--quiet", "-m", "Edit JMD-1")
+
+	key := ticketKey(t, "JMD-1")
+	authors := func(sources ...domain.AuthorSource) *ChangeAuthors {
+		return NewChangeAuthors(NewTicketFiles(dir, NewParser()), fakes.NewStateRepository(), sources, nil)
+	}
+
+	if got := authors(domain.AuthorFromFrontmatter, domain.AuthorFromGit).ChangeAuthor(ctx, key); got != "Bob" {
+		t.Errorf("ChangeAuthor(frontmatter, git) = %q, want the edited_by value Bob", got)
+	}
+	if got := authors(domain.AuthorFromGit).ChangeAuthor(ctx, key); got != "Carol" {
+		t.Errorf("ChangeAuthor(git) = %q, want the last commit's author Carol", got)
+	}
+
+	write("---\nkey: JMD-1\nsummary: Login page\n---\n")
+	if got := authors(domain.AuthorFromFrontmatter, domain.AuthorFromGit).ChangeAuthor(ctx, key); got != "Alice" {
+		t.Errorf("ChangeAuthor() of an uncommitted edit = %q, want the git user Alice", got)
+	}
+
+	if got := authors().ChangeAuthor(ctx, key); got != "" {
+		t.Errorf("ChangeAuthor() without sources = %q, want \"\"", got)
+	}
+	if got := authors(domain.AuthorFromGit).ChangeAuthor(ctx, ticketKey(t, "JMD-2")); got != "" {
+		t.Errorf("ChangeAuthor() of a ticket without a file = %q, want \"\"", got)
+	}
+
+	outside := NewChangeAuthors(NewTicketFiles(t.TempDir(), NewParser()), fakes.NewStateRepository(), []domain.AuthorSource{domain.AuthorFromGit}, nil)
+	if err := os.WriteFile(filepath.Join(outside.files.markdownDir, "JMD-1.md"), []byte("# JMD-1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := outside.ChangeAuthor(ctx, key); got != "" {
+		t.Errorf("ChangeAuthor() outside a git work tree = %q, want \"\"", got)
+	}
+}
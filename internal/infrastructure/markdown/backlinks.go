@@ -0,0 +1,167 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Markers around the generated "Referenced by" section of a ticket file, which is
+// replaced on every update and, like the metadata section, never edited by hand.
+const (
+	backlinksStart = "<!-- jiramd-backlinks-start -->"
+	backlinksEnd   = "<!-- jiramd-backlinks-end -->"
+)
+
+// Backlink is a ticket listed in another ticket's "Referenced by" section.
+type Backlink struct {
+	Key     string
+	Summary string
+
+	// Href is the link to the referencing ticket's file, relative to the linking file
+	Href string
+}
+
+// SetBacklinks returns content with its "Referenced by" section listing backlinks:
+// replaced if content has one, appended otherwise, and removed when there are none.
+func SetBacklinks(content []byte, backlinks []Backlink) []byte {
+	var section bytes.Buffer
+	if len(backlinks) > 0 {
+		section.WriteString(backlinksStart + "\n## Referenced by\n\n")
+		for _, link := range backlinks {
+			fmt.Fprintf(&section, "- [%s](%s) %s\n", link.Key, link.Href, strings.TrimSpace(link.Summary))
+		}
+		section.WriteString(backlinksEnd + "\n")
+	}
+
+	start := bytes.Index(content, []byte(backlinksStart))
+	end := bytes.Index(content, []byte(backlinksEnd))
+	if start >= 0 && end > start {
+		end += len(backlinksEnd)
+		if end < len(content) && content[end] == '\n' {
+			end++
+		}
+		before := content[:start]
+		if section.Len() == 0 {
+			// Drop the blank line the section was appended after
+			before = bytes.TrimRight(before, "\n")
+			if len(before) > 0 {
+				before = append(before, '\n')
+			}
+		}
+		return append(append(append([]byte(nil), before...), section.Bytes()...), content[end:]...)
+	}
+
+	if section.Len() == 0 {
+		return content
+	}
+	updated := append([]byte(nil), bytes.TrimRight(content, "\n")...)
+	if len(updated) > 0 {
+		updated = append(updated, "\n\n"...)
+	}
+	return append(updated, section.Bytes()...)
+}
+
+// BacklinkWriter keeps the "Referenced by" sections of the ticket files (<KEY>.md)
+// under a markdown directory up to date.
+type BacklinkWriter struct {
+	markdownDir string
+	skipDir     string
+}
+
+// NewBacklinkWriter creates a writer for the ticket files under markdownDir, leaving
+// the files under skipDir (e.g., the archive of tickets no longer synced; may be empty)
+// alone.
+func NewBacklinkWriter(markdownDir, skipDir string) *BacklinkWriter {
+	writer := &BacklinkWriter{markdownDir: filepath.Clean(markdownDir)}
+	if skipDir != "" {
+		writer.skipDir = filepath.Clean(skipDir)
+	}
+	return writer
+}
+
+// WriteBacklinks sets the "Referenced by" section of every ticket file to the tickets
+// that reference it in backlinks, rewriting only the files whose section changed. Files
+// are read and written in parallel.
+func (w *BacklinkWriter) WriteBacklinks(ctx context.Context, backlinks domain.Backlinks) error {
+	files, err := findTicketFiles(ctx, w.markdownDir, w.skipDir)
+	if err != nil {
+		return err
+	}
+	keys := make([]domain.TicketKey, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+
+	return forEachFile(ctx, len(keys), func(i int) error {
+		key := keys[i]
+		path := files[key]
+		links := make([]Backlink, 0, len(backlinks[key]))
+		for _, referrer := range backlinks[key] {
+			href := referrer.Key.String() + ".md"
+			if target, ok := files[referrer.Key]; ok {
+				if rel, err := filepath.Rel(filepath.Dir(path), target); err == nil {
+					href = filepath.ToSlash(rel)
+				}
+			}
+			links = append(links, Backlink{Key: referrer.Key.String(), Summary: referrer.Summary, Href: href})
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		updated := SetBacklinks(content, links)
+		if bytes.Equal(updated, content) {
+			return nil
+		}
+
+		if err := writeFileAtomic(path, updated, filePermOf(path)); err != nil {
+			return fmt.Errorf("failed to write backlinks of %s: %w", key, err)
+		}
+		return nil
+	})
+}
+
+// findTicketFiles returns the path of every ticket file (<KEY>.md) under dir by key,
+// skipping the files under skipDir (empty skips nothing).
+func findTicketFiles(ctx context.Context, dir, skipDir string) (map[domain.TicketKey]string, error) {
+	files := make(map[domain.TicketKey]string)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if skipDir != "" && path == skipDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name, ok := strings.CutSuffix(entry.Name(), ".md")
+		if !ok {
+			return nil
+		}
+		if key, err := domain.NewTicketKey(name); err == nil {
+			files[key] = path
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ticket files: %w", err)
+	}
+	return files, nil
+}
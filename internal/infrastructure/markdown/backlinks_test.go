@@ -0,0 +1,80 @@
+package markdown
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestSetBacklinks(t *testing.T) {
+	links := []Backlink{{Key: "PROJ-2", Summary: "Login page", Href: "PROJ-2.md"}}
+	section := backlinksStart + "\n## Referenced by\n\n- [PROJ-2](PROJ-2.md) Login page\n" + backlinksEnd + "\n"
+
+	tests := []struct {
+		name    string
+		content string
+		links   []Backlink
+		want    string
+	}{
+		{"appended", "# PROJ-1\n\nBody\n", links, "# PROJ-1\n\nBody\n\n" + section},
+		{"replaced", "# PROJ-1\n\n" + backlinksStart + "\nold\n" + backlinksEnd + "\n", links, "# PROJ-1\n\n" + section},
+		{"removed", "# PROJ-1\n\n" + section, nil, "# PROJ-1\n"},
+		{"none", "# PROJ-1\n", nil, "# PROJ-1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(SetBacklinks([]byte(tt.content), tt.links)); got != tt.want {
+				t.Errorf("SetBacklinks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBacklinkWriter_WriteBacklinks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		filepath.Join(dir, "PROJ", "PROJ-1.md"):            "# PROJ-1\n",
+		filepath.Join(dir, "OPS", "OPS-3.md"):              "# OPS-3\n",
+		filepath.Join(dir, "archive", "PROJ", "PROJ-9.md"): "# PROJ-9\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	referrer := &domain.Ticket{Key: ticketKey(t, "OPS-3"), Summary: "Outage"}
+	backlinks := domain.Backlinks{
+		ticketKey(t, "PROJ-1"): {referrer},
+		ticketKey(t, "PROJ-9"): {referrer},
+	}
+	writer := NewBacklinkWriter(dir, filepath.Join(dir, "archive"))
+	if err := writer.WriteBacklinks(context.Background(), backlinks); err != nil {
+		t.Fatalf("WriteBacklinks() error = %v", err)
+	}
+
+	want := "# PROJ-1\n\n" + backlinksStart + "\n## Referenced by\n\n- [OPS-3](../OPS/OPS-3.md) Outage\n" + backlinksEnd + "\n"
+	if got := readFile(t, filepath.Join(dir, "PROJ", "PROJ-1.md")); got != want {
+		t.Errorf("PROJ-1.md = %q, want %q", got, want)
+	}
+	if got := readFile(t, filepath.Join(dir, "OPS", "OPS-3.md")); got != "# OPS-3\n" {
+		t.Errorf("OPS-3.md = %q, want it unchanged", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "archive", "PROJ", "PROJ-9.md")); got != "# PROJ-9\n" {
+		t.Errorf("archived PROJ-9.md = %q, want it unchanged", got)
+	}
+
+	// Once nothing references the ticket, its section goes away
+	if err := writer.WriteBacklinks(context.Background(), nil); err != nil {
+		t.Fatalf("WriteBacklinks() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "PROJ", "PROJ-1.md")); got != "# PROJ-1\n" {
+		t.Errorf("PROJ-1.md = %q, want section removed", got)
+	}
+}
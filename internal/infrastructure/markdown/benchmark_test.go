@@ -0,0 +1,166 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// vaultSize is the number of tickets in the benchmark vaults, the size of the large
+// projects the writers are tuned for.
+const vaultSize = 5000
+
+// benchmarkTicket returns a ticket with the fields, comments, and history of a typical
+// ticket in a large project.
+func benchmarkTicket(b *testing.B, n int) *domain.Ticket {
+	b.Helper()
+
+	key, err := domain.NewTicketKey(fmt.Sprintf("JMD-%d", n))
+	if err != nil {
+		b.Fatal(err)
+	}
+	created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC).Add(time.Duration(n) * time.Minute)
+	ticket := domain.NewTicket(key, fmt.Sprintf("Ticket %d: fix the login page", n), created, created.Add(48*time.Hour))
+	ticket.Description = strings.Repeat("Users cannot log in with SSO after the upgrade.\n\n", 20)
+	ticket.Status = "In Progress"
+	ticket.IssueType = "Bug"
+	ticket.Priority = "High"
+	ticket.Assignee = fmt.Sprintf("user%d@example.com", n%50)
+	ticket.Reporter = "reporter@example.com"
+	ticket.Labels = []string{"auth", "sso"}
+	ticket.CustomFields[domain.SprintsField] = domain.NewFieldValue([]string{fmt.Sprintf("JMD Sprint %d", n%20)})
+	ticket.CustomFields["team"] = domain.NewFieldValue("Platform")
+	for i := 0; i < 5; i++ {
+		ticket.Comments = append(ticket.Comments, &domain.Comment{
+			ID:      fmt.Sprintf("%d-%d", n, i),
+			Author:  "reviewer@example.com",
+			Body:    "Reproduced on staging; the redirect drops the session cookie.",
+			Created: created.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	ticket.StatusHistory = []domain.StatusChange{
+		{ID: "1", From: "To Do", To: "In Progress", At: created.Add(time.Hour)},
+		{ID: "2", From: "In Progress", To: "In Review", At: created.Add(5 * time.Hour)},
+		{ID: "3", From: "In Review", To: "In Progress", At: created.Add(8 * time.Hour)},
+	}
+	return ticket
+}
+
+// benchmarkVault writes the files of vaultSize tickets under a temporary directory and
+// returns it with the tickets.
+func benchmarkVault(b *testing.B) (string, []*domain.Ticket) {
+	b.Helper()
+
+	dir := b.TempDir()
+	parser := NewParser()
+	tickets := make([]*domain.Ticket, 0, vaultSize)
+	for n := 1; n <= vaultSize; n++ {
+		ticket := benchmarkTicket(b, n)
+		content, _, err := parser.GenerateTicket(context.Background(), ticket)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, ticket.Key.String()+".md"), content, 0644); err != nil {
+			b.Fatal(err)
+		}
+		tickets = append(tickets, ticket)
+	}
+	return dir, tickets
+}
+
+func BenchmarkParser_GenerateTicket(b *testing.B) {
+	for _, flavor := range []domain.MarkdownFlavor{domain.MarkdownFlavorPlain, domain.MarkdownFlavorObsidian} {
+		b.Run(string(flavor), func(b *testing.B) {
+			parser := NewParser().WithFlavor(flavor)
+			ticket := benchmarkTicket(b, 1)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, _, err := parser.GenerateTicket(ctx, ticket); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParser_RewriteTicket(b *testing.B) {
+	parser := NewParser()
+	ticket := benchmarkTicket(b, 1)
+	ctx := context.Background()
+	content, _, err := parser.GenerateTicket(ctx, ticket)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, _, err := parser.RewriteTicket(ctx, ticket, content, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIndexWriter_WriteIndexes(b *testing.B) {
+	dir, tickets := benchmarkVault(b)
+	indexes := domain.BuildIndexes(tickets, domain.IndexOptions{BySprint: true, ByAssignee: true})
+	writer := NewIndexWriter(dir, "")
+	ctx := context.Background()
+
+	// Unchanged indexes are compared and left alone, as on most syncs
+	b.Run("unchanged", func(b *testing.B) {
+		if _, err := writer.WriteIndexes(ctx, indexes); err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := writer.WriteIndexes(ctx, indexes); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// Every index is rewritten, as on the first sync
+	b.Run("rewritten", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			b.StopTimer()
+			if err := os.RemoveAll(filepath.Join(dir, IndexDir)); err != nil {
+				b.Fatal(err)
+			}
+			b.StartTimer()
+			if _, err := writer.WriteIndexes(ctx, indexes); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkBacklinkWriter_WriteBacklinks(b *testing.B) {
+	dir, tickets := benchmarkVault(b)
+	backlinks := make(domain.Backlinks)
+	for i, ticket := range tickets {
+		referrer := tickets[(i+1)%len(tickets)]
+		backlinks[ticket.Key] = []*domain.Ticket{referrer}
+	}
+	writer := NewBacklinkWriter(dir, "")
+	ctx := context.Background()
+	if err := writer.WriteBacklinks(ctx, backlinks); err != nil {
+		b.Fatal(err)
+	}
+
+	// Every file is read and compared, and none rewritten, as on most syncs
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := writer.WriteBacklinks(ctx, backlinks); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package markdown
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// commentsHeading opens the comments section of a ticket file.
+const commentsHeading = "## Comments"
+
+// CommentSource streams the comments of a ticket, oldest first, one page at a time
+// (e.g. the Jira client).
+type CommentSource interface {
+	ForEachComment(ctx context.Context, ticketKey string, fn func(comment *domain.Comment) error) error
+}
+
+// WriteTicket writes a ticket's markdown file to w like GenerateTicket, with the comments
+// streamed from comments instead of ticket.Comments, so the file of a ticket with
+// thousands of comments is written without holding them all: each comment is rendered
+// as it arrives, or with a comment limit, only the most recent are held until the end.
+// The comments are streamed before the frontmatter is written, so what streaming them
+// adds to the ticket (e.g. the profiles of their authors) is in it.
+// Returns the content of the file's frontmatter sidecar, or nil when the frontmatter is
+// in the file (see SidecarPath). If streaming the comments fails, nothing is written to w.
+func (p *Parser) WriteTicket(ctx context.Context, w io.Writer, ticket *domain.Ticket, comments CommentSource) ([]byte, error) {
+	return p.writeTicket(ctx, w, ticket, nil, nil, comments)
+}
+
+// RewriteTicketTo writes a ticket's markdown file to w like WriteTicket, over its current
+// content and sidecar (nil if it has none), keeping what RewriteTicket keeps: the
+// frontmatter keys and the notes around the managed zone of the description users added.
+// Returns ErrInvalidInput if the current frontmatter does not parse, or a zone of the
+// description is never closed.
+func (p *Parser) RewriteTicketTo(ctx context.Context, w io.Writer, ticket *domain.Ticket, content, sidecar []byte, comments CommentSource) ([]byte, error) {
+	return p.writeTicket(ctx, w, ticket, content, sidecar, comments)
+}
+
+// writeTicket writes a ticket's markdown file to w with the comments streamed from
+// comments, over content and sidecar when content is not nil.
+func (p *Parser) writeTicket(ctx context.Context, w io.Writer, ticket *domain.Ticket, content, sidecar []byte, comments CommentSource) ([]byte, error) {
+	section := getBodyBuffer()
+	defer bodyBuffers.Put(section)
+	var (
+		limit  = p.limitsFor(ticket).MaxComments
+		recent = make([]*domain.Comment, 0, limit)
+		total  = 0
+	)
+	err := comments.ForEachComment(ctx, ticket.Key.String(), func(comment *domain.Comment) error {
+		total++
+		if limit > 0 {
+			// Only the most recent are written, once it is known which they are
+			if len(recent) < limit {
+				recent = append(recent, comment)
+			} else {
+				recent[(total-1)%limit] = comment
+			}
+			return nil
+		}
+
+		if total == 1 {
+			section.WriteString(commentsHeading + "\n\n")
+		}
+		p.writeComment(section, ticket, comment)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write comments of %s: %w", ticket.Key, err)
+	}
+	if limit > 0 {
+		// recent wrapped around: its oldest comment follows the newest
+		oldest := total % limit
+		if total <= limit {
+			oldest = 0
+		}
+		ordered := append(append(make([]*domain.Comment, 0, len(recent)), recent[oldest:]...), recent[:oldest]...)
+		p.writeComments(section, ticket, ordered, total-len(recent))
+	}
+
+	head := getBodyBuffer()
+	defer bodyBuffers.Put(head)
+	p.writeHead(head, ticket)
+	frontmatter, body := ticketFrontmatter(ticket), head.Bytes()
+	if content != nil {
+		existing, existingBody, err := p.codec.Decode(content, sidecar)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read frontmatter of %s: %w", ticket.Key, err)
+		}
+		if body, err = keepLocalZones(body, existingBody); err != nil {
+			return nil, fmt.Errorf("failed to read description of %s: %w", ticket.Key, err)
+		}
+		frontmatter = MergeFrontmatter(existing, frontmatter, managedKeys)
+	}
+	encoded, sidecar, err := p.encodeBody(ticket, frontmatter, body)
+	if err != nil {
+		return nil, err
+	}
+
+	out := bufio.NewWriter(w)
+	out.Write(encoded)
+	out.Write(section.Bytes())
+	section.Reset()
+	p.writeTail(section, ticket)
+	out.Write(section.Bytes())
+	if err := out.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", ticket.Key, err)
+	}
+	return sidecar, nil
+}
+
+// writeOlderComments writes the note standing in for the older comments of a ticket that
+// are left out of its file, if any are.
+func writeOlderComments(buf *bytes.Buffer, key domain.TicketKey, older int) {
+	switch {
+	case older == 1:
+		fmt.Fprintf(buf, "*Truncated: 1 older comment in Jira. Run `jiramd expand %s` to show it.*\n\n", key)
+	case older > 1:
+		fmt.Fprintf(buf, "*Truncated: %d older comments in Jira. Run `jiramd expand %s` to show them.*\n\n", older, key)
+	}
+}
+
+// keepComments returns generated, the body of a ticket generated without its comments
+// loaded, with the comments section of existing, the body it replaces, so comments that
+// were not fetched again are kept as they were written. Returns generated if existing
+// has no comments section.
+func keepComments(generated, existing []byte) ([]byte, error) {
+	from := 0
+	if section, err := findDescription(existing); err != nil {
+		return nil, err
+	} else if section.found {
+		from = section.end
+	}
+	start := bytes.Index(existing[from:], []byte(commentsHeading+"\n"))
+	if start < 0 {
+		return generated, nil
+	}
+	start += from
+	end := bytes.Index(existing[start:], []byte(metadataStart))
+	at := bytes.Index(generated, []byte(metadataStart))
+	if end < 0 || at < 0 {
+		return generated, nil
+	}
+	end += start
+
+	merged := make([]byte, 0, len(generated)+end-start)
+	merged = append(merged, generated[:at]...)
+	merged = append(merged, existing[start:end]...)
+	return append(merged, generated[at:]...), nil
+}
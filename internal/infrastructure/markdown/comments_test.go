@@ -0,0 +1,168 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// commentList streams a fixed list of comments, failing with err once they run out.
+type commentList struct {
+	comments []*domain.Comment
+	err      error
+}
+
+func (l commentList) ForEachComment(ctx context.Context, ticketKey string, fn func(comment *domain.Comment) error) error {
+	for _, comment := range l.comments {
+		if err := fn(comment); err != nil {
+			return err
+		}
+	}
+	return l.err
+}
+
+// commentedTicket returns a ticket with n comments, numbered from 1, oldest first.
+func commentedTicket(t *testing.T, n int) *domain.Ticket {
+	t.Helper()
+
+	created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	ticket := domain.NewTicket(ticketKey(t, "JMD-1"), "Long discussion", created, created.Add(time.Hour))
+	ticket.Status = "In Progress"
+	for i := 1; i <= n; i++ {
+		ticket.Comments = append(ticket.Comments, &domain.Comment{
+			ID:      fmt.Sprint(i),
+			Author:  "alice@example.com",
+			Body:    fmt.Sprintf("Comment number %d", i),
+			Created: created.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return ticket
+}
+
+func TestParser_WriteTicket(t *testing.T) {
+	ctx := context.Background()
+	for _, count := range []int{0, 1, 5} {
+		for _, limit := range []int{0, 1, 2, 5, 7} {
+			t.Run(fmt.Sprintf("%d comments limited to %d", count, limit), func(t *testing.T) {
+				parser := NewParser().WithCommentLimit(limit)
+				ticket := commentedTicket(t, count)
+
+				// Streamed files are the files generated with every comment loaded
+				want, _, err := parser.GenerateTicket(ctx, ticket)
+				if err != nil {
+					t.Fatalf("GenerateTicket() error = %v", err)
+				}
+				streamed := *ticket
+				streamed.Comments = nil
+				var got bytes.Buffer
+				sidecar, err := parser.WriteTicket(ctx, &got, &streamed, commentList{comments: ticket.Comments})
+				if err != nil {
+					t.Fatalf("WriteTicket() error = %v", err)
+				}
+				if got.String() != string(want) || sidecar != nil {
+					t.Errorf("WriteTicket() =\n%s\nwant\n%s", got.String(), want)
+				}
+			})
+		}
+	}
+}
+
+func TestParser_WithCommentLimit(t *testing.T) {
+	content, _, err := NewParser().WithCommentLimit(2).GenerateTicket(context.Background(), commentedTicket(t, 5))
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	file := string(content)
+
+	if !strings.Contains(file, "## Comments\n\n*Truncated: 3 older comments in Jira. Run `jiramd expand JMD-1` to show them.*\n\n### alice@example.com") {
+		t.Errorf("file does not note the older comments before the recent ones:\n%s", file)
+	}
+	for i := 1; i <= 5; i++ {
+		if got, want := strings.Contains(file, fmt.Sprintf("Comment number %d\n", i)), i > 3; got != want {
+			t.Errorf("comment %d written = %v, want %v", i, got, want)
+		}
+	}
+
+	content, _, err = NewParser().WithCommentLimit(4).GenerateTicket(context.Background(), commentedTicket(t, 5))
+	if err != nil || !strings.Contains(string(content), "*Truncated: 1 older comment in Jira. Run `jiramd expand JMD-1` to show it.*") {
+		t.Errorf("GenerateTicket() = %s, %v; want a note for 1 older comment", content, err)
+	}
+}
+
+func TestParser_WriteTicket_SourceError(t *testing.T) {
+	failure := errors.New("connection reset")
+	ticket := commentedTicket(t, 3)
+
+	var out bytes.Buffer
+	_, err := NewParser().WriteTicket(context.Background(), &out, ticket, commentList{comments: ticket.Comments, err: failure})
+	if !errors.Is(err, failure) {
+		t.Errorf("WriteTicket() error = %v, want %v", err, failure)
+	}
+}
+
+func TestParser_WriteTicket_Sidecar(t *testing.T) {
+	parser := NewParser().WithFrontmatter(jsonSidecarCodec{})
+	ticket := commentedTicket(t, 2)
+	ctx := context.Background()
+
+	want, wantSidecar, err := parser.GenerateTicket(ctx, ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	var got bytes.Buffer
+	sidecar, err := parser.WriteTicket(ctx, &got, ticket, commentList{comments: ticket.Comments})
+	if err != nil {
+		t.Fatalf("WriteTicket() error = %v", err)
+	}
+	if got.String() != string(want) || string(sidecar) != string(wantSidecar) {
+		t.Errorf("WriteTicket() = %q with sidecar %q, want %q with %q", got.String(), sidecar, want, wantSidecar)
+	}
+}
+
+func TestParser_RewriteTicket_UnloadedComments(t *testing.T) {
+	ctx := context.Background()
+	parser := NewParser().WithCommentLimit(2)
+	ticket := commentedTicket(t, 3)
+	ticket.Description = "## Comments\n\nA heading in the description"
+
+	content, _, err := parser.GenerateTicket(ctx, ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+
+	// A pull that did not fetch the comments again keeps them as written
+	unloaded := *ticket
+	unloaded.Comments = nil
+	unloaded.Status = "Done"
+	rewritten, _, err := parser.RewriteTicket(ctx, &unloaded, content, nil)
+	if err != nil {
+		t.Fatalf("RewriteTicket() error = %v", err)
+	}
+	want, _, err := parser.GenerateTicket(ctx, &domain.Ticket{
+		Key: ticket.Key, Summary: ticket.Summary, Description: ticket.Description, Status: "Done",
+		Created: ticket.Created, Updated: ticket.Updated, CustomFields: ticket.CustomFields, Comments: ticket.Comments,
+	})
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	if string(rewritten) != string(want) {
+		t.Errorf("RewriteTicket() =\n%s\nwant\n%s", rewritten, want)
+	}
+
+	// Loaded comments replace the section, even when there are none left
+	loaded := unloaded
+	loaded.Comments = []*domain.Comment{}
+	rewritten, _, err = parser.RewriteTicket(ctx, &loaded, content, nil)
+	if err != nil {
+		t.Fatalf("RewriteTicket() error = %v", err)
+	}
+	if strings.Contains(string(rewritten), "Comment number") || strings.Contains(string(rewritten), "older comment") {
+		t.Errorf("RewriteTicket() kept deleted comments:\n%s", rewritten)
+	}
+}
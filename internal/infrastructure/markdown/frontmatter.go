@@ -0,0 +1,521 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Frontmatter is the metadata of a ticket file, kept in key order so rewriting a file
+// does not reshuffle it.
+//
+// Values are strings, bools, int64s, float64s, lists ([]interface{}), nested
+// frontmatter (*Frontmatter), or nil; Set converts other values to one of these.
+type Frontmatter struct {
+	keys   []string
+	values map[string]interface{}
+
+	// yamlNodes are the key and value nodes of keys read from YAML and not set since,
+	// which are written back as they were read, comments and style included
+	yamlNodes map[string][2]*yaml.Node
+}
+
+// NewFrontmatter creates an empty frontmatter.
+func NewFrontmatter() *Frontmatter {
+	return &Frontmatter{values: make(map[string]interface{})}
+}
+
+// Set sets the value of key, appending key if it is not set yet.
+func (f *Frontmatter) Set(key string, value interface{}) {
+	if _, ok := f.values[key]; !ok {
+		f.keys = append(f.keys, key)
+	}
+	f.values[key] = normalizeValue(value)
+	delete(f.yamlNodes, key)
+}
+
+// Get returns the value of key, and whether it is set.
+func (f *Frontmatter) Get(key string) (interface{}, bool) {
+	value, ok := f.values[key]
+	return value, ok
+}
+
+// Delete removes key.
+func (f *Frontmatter) Delete(key string) {
+	if _, ok := f.values[key]; !ok {
+		return
+	}
+	delete(f.values, key)
+	delete(f.yamlNodes, key)
+	for i, k := range f.keys {
+		if k == key {
+			f.keys = append(f.keys[:i:i], f.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns the keys in order.
+func (f *Frontmatter) Keys() []string {
+	return append([]string(nil), f.keys...)
+}
+
+// Len returns the number of keys.
+func (f *Frontmatter) Len() int {
+	return len(f.keys)
+}
+
+// Order sorts the keys listed in order to the front, in that order. The other keys
+// follow in their current order.
+func (f *Frontmatter) Order(order []string) {
+	rank := make(map[string]int, len(order))
+	for i, key := range order {
+		if _, ok := rank[key]; !ok {
+			rank[key] = i
+		}
+	}
+	sort.SliceStable(f.keys, func(i, j int) bool {
+		ri, iok := rank[f.keys[i]]
+		rj, jok := rank[f.keys[j]]
+		switch {
+		case iok && jok:
+			return ri < rj
+		default:
+			return iok && !jok
+		}
+	})
+}
+
+// MergeFrontmatter returns generated followed by the keys of existing that are not in
+// managed, the keys jiramd writes, in their existing order and exactly as they were
+// read, so keys a user added survive a rewrite. Managed keys that generated no longer
+// has are dropped.
+func MergeFrontmatter(existing, generated *Frontmatter, managed []string) *Frontmatter {
+	isManaged := make(map[string]bool, len(managed))
+	for _, key := range managed {
+		isManaged[key] = true
+	}
+
+	merged := NewFrontmatter()
+	for _, key := range generated.keys {
+		merged.copyKey(generated, key)
+	}
+	for _, key := range existing.keys {
+		if _, ok := merged.values[key]; !ok && !isManaged[key] {
+			merged.copyKey(existing, key)
+		}
+	}
+	return merged
+}
+
+// copyKey sets key to its value in from, keeping the nodes it was read from.
+func (f *Frontmatter) copyKey(from *Frontmatter, key string) {
+	f.Set(key, from.values[key])
+	if nodes, ok := from.yamlNodes[key]; ok {
+		if f.yamlNodes == nil {
+			f.yamlNodes = make(map[string][2]*yaml.Node)
+		}
+		f.yamlNodes[key] = nodes
+	}
+}
+
+// normalizeValue converts a value to one of the types Frontmatter holds. Maps become
+// nested frontmatter in key order.
+func normalizeValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case nil, string, bool, int64, float64, *Frontmatter:
+		return value
+	case int:
+		return int64(value)
+	case int32:
+		return int64(value)
+	case float32:
+		return float64(value)
+	case []string:
+		list := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			list = append(list, item)
+		}
+		return list
+	case []interface{}:
+		list := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			list = append(list, normalizeValue(item))
+		}
+		return list
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		nested := NewFrontmatter()
+		for _, key := range keys {
+			nested.Set(key, value[key])
+		}
+		return nested
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// FrontmatterCodec reads and writes the frontmatter of ticket files.
+type FrontmatterCodec interface {
+	// Encode returns the content of a ticket file with frontmatter and body, and the
+	// content of its sidecar file (nil when the frontmatter is in the file itself).
+	// The content is a copy: body may be reused once Encode returns
+	Encode(frontmatter *Frontmatter, body []byte) (content, sidecar []byte, err error)
+
+	// Decode splits a ticket file, and its sidecar (nil when there is none), into
+	// frontmatter and body
+	Decode(content, sidecar []byte) (*Frontmatter, []byte, error)
+}
+
+// NewFrontmatterCodec returns the codec of a frontmatter format (empty means YAML).
+// Returns ErrInvalidInput for an unknown format.
+func NewFrontmatterCodec(format domain.FrontmatterFormat) (FrontmatterCodec, error) {
+	switch format {
+	case "", domain.FrontmatterYAML:
+		return yamlCodec{}, nil
+	case domain.FrontmatterTOML:
+		return tomlCodec{}, nil
+	case domain.FrontmatterJSON:
+		return jsonSidecarCodec{}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown frontmatter format %q", domain.ErrInvalidInput, format)
+}
+
+// SidecarPath returns the path of the JSON sidecar of a ticket file.
+func SidecarPath(path string) string {
+	return strings.TrimSuffix(path, ".md") + ".json"
+}
+
+// yamlCodec stores frontmatter as a "---" delimited YAML block.
+type yamlCodec struct{}
+
+// Encode implements FrontmatterCodec.
+func (yamlCodec) Encode(frontmatter *Frontmatter, body []byte) ([]byte, []byte, error) {
+	encoded, err := yaml.Marshal(toYAMLNode(frontmatter))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	return joinFrontmatter("---\n", encoded, "---\n", body), nil, nil
+}
+
+// Decode implements FrontmatterCodec.
+func (yamlCodec) Decode(content, sidecar []byte) (*Frontmatter, []byte, error) {
+	block, body, err := splitFrontmatter(bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n")))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	body = trimSeparator(body)
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(block, &document); err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid frontmatter: %v", domain.ErrInvalidInput, err)
+	}
+	if len(document.Content) == 0 {
+		return NewFrontmatter(), body, nil
+	}
+	value, err := fromYAMLNode(document.Content[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid frontmatter: %v", domain.ErrInvalidInput, err)
+	}
+	frontmatter, ok := value.(*Frontmatter)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: frontmatter must be a mapping", domain.ErrInvalidInput)
+	}
+
+	root := document.Content[0]
+	frontmatter.yamlNodes = make(map[string][2]*yaml.Node)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		// Aliases would dangle once the key holding their anchor is rewritten
+		if !hasAlias(root.Content[i+1]) {
+			frontmatter.yamlNodes[root.Content[i].Value] = [2]*yaml.Node{root.Content[i], root.Content[i+1]}
+		}
+	}
+	return frontmatter, body, nil
+}
+
+// hasAlias returns true if node or any node in it is an alias.
+func hasAlias(node *yaml.Node) bool {
+	if node.Kind == yaml.AliasNode {
+		return true
+	}
+	for _, child := range node.Content {
+		if hasAlias(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// toYAMLNode converts a frontmatter value to a YAML node, keeping the key order.
+func toYAMLNode(value interface{}) *yaml.Node {
+	switch value := value.(type) {
+	case *Frontmatter:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range value.keys {
+			if nodes, ok := value.yamlNodes[key]; ok {
+				node.Content = append(node.Content, nodes[0], nodes[1])
+				continue
+			}
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+				toYAMLNode(value.values[key]))
+		}
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range value {
+			node.Content = append(node.Content, toYAMLNode(item))
+		}
+		return node
+	default:
+		if node, ok := scalarNode(value); ok {
+			return node
+		}
+		var node yaml.Node
+		if err := node.Encode(value); err != nil {
+			// Only the types of normalizeValue reach here, and they all encode
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(value)}
+		}
+		return &node
+	}
+}
+
+// scalarNode returns the YAML node Node.Encode returns for value, and whether value is
+// one of the common scalars it can be built for directly. Node.Encode runs a whole
+// encoder and parser per value, which dominated writing ticket files.
+func scalarNode(value interface{}) (*yaml.Node, bool) {
+	switch value := value.(type) {
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, true
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value)}, true
+	case int64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(value, 10)}, true
+	case string:
+		// Strings YAML 1.1 would read as something else are quoted by Node.Encode but
+		// not by the encoder, so they take the slow path
+		if !utf8.ValidString(value) || yaml11Bools[value] || base60Float.MatchString(value) {
+			return nil, false
+		}
+		// Tagged as a string, the encoder quotes values that would read as another type
+		// and writes multi-line values as literal blocks, as Node.Encode does
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+		if strings.Contains(value, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+		return node, true
+	}
+	return nil, false
+}
+
+// yaml11Bools are the booleans of YAML 1.1 that are strings in YAML 1.2.
+var yaml11Bools = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true,
+	"n": true, "N": true, "no": true, "No": true, "NO": true,
+	"on": true, "On": true, "ON": true,
+	"off": true, "Off": true, "OFF": true,
+}
+
+// base60Float matches the sexagesimal numbers of YAML 1.1 (e.g. 1:20).
+var base60Float = regexp.MustCompile(`^[-+]?[0-9][0-9_]*(?::[0-5]?[0-9])+(?:\.[0-9_]*)?$`)
+
+// fromYAMLNode converts a YAML node to a frontmatter value. Timestamps are kept as
+// written rather than parsed.
+func fromYAMLNode(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return fromYAMLNode(node.Alias)
+	case yaml.MappingNode:
+		frontmatter := NewFrontmatter()
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := fromYAMLNode(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			frontmatter.Set(node.Content[i].Value, value)
+		}
+		return frontmatter, nil
+	case yaml.SequenceNode:
+		list := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := fromYAMLNode(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	}
+
+	if node.Tag == "!!str" || node.Tag == "!!timestamp" {
+		return node.Value, nil
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, fmt.Errorf("line %d: %v", node.Line, err)
+	}
+	return normalizeValue(value), nil
+}
+
+// jsonSidecarCodec stores frontmatter in a JSON file next to the ticket file, leaving
+// the markdown as is.
+type jsonSidecarCodec struct{}
+
+// Encode implements FrontmatterCodec.
+func (jsonSidecarCodec) Encode(frontmatter *Frontmatter, body []byte) ([]byte, []byte, error) {
+	var compact bytes.Buffer
+	if err := writeJSON(&compact, frontmatter); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	var sidecar bytes.Buffer
+	if err := json.Indent(&sidecar, compact.Bytes(), "", "  "); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	sidecar.WriteByte('\n')
+	return append([]byte(nil), body...), sidecar.Bytes(), nil
+}
+
+// Decode implements FrontmatterCodec. A file without a sidecar has empty frontmatter.
+func (jsonSidecarCodec) Decode(content, sidecar []byte) (*Frontmatter, []byte, error) {
+	if len(bytes.TrimSpace(sidecar)) == 0 {
+		return NewFrontmatter(), content, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(sidecar))
+	decoder.UseNumber()
+	value, err := readJSON(decoder)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid frontmatter sidecar: %v", domain.ErrInvalidInput, err)
+	}
+	frontmatter, ok := value.(*Frontmatter)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: frontmatter sidecar must be a JSON object", domain.ErrInvalidInput)
+	}
+	return frontmatter, content, nil
+}
+
+// writeJSON writes a frontmatter value as compact JSON, keeping the key order.
+func writeJSON(buf *bytes.Buffer, value interface{}) error {
+	switch value := value.(type) {
+	case *Frontmatter:
+		buf.WriteByte('{')
+		for i, key := range value.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encoded, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+			buf.Write(encoded)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, value.values[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(encoded)
+	}
+	return nil
+}
+
+// readJSON reads the next JSON value from decoder as a frontmatter value, keeping the
+// key order of objects.
+func readJSON(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if errors.Is(err, io.EOF) {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '{':
+			frontmatter := NewFrontmatter()
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := readJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+				frontmatter.Set(key.(string), value)
+			}
+			_, err := decoder.Token()
+			return frontmatter, err
+		case '[':
+			list := make([]interface{}, 0)
+			for decoder.More() {
+				value, err := readJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			_, err := decoder.Token()
+			return list, err
+		}
+		return nil, fmt.Errorf("unexpected %v", token)
+	case json.Number:
+		if n, err := strconv.ParseInt(token.String(), 10, 64); err == nil {
+			return n, nil
+		}
+		return token.Float64()
+	default:
+		return token, nil
+	}
+}
+
+// trimSeparator removes the blank line joinFrontmatter puts between frontmatter and body.
+func trimSeparator(body []byte) []byte {
+	return bytes.TrimPrefix(body, []byte("\n"))
+}
+
+// joinFrontmatter returns a ticket file with a delimited frontmatter block before body.
+func joinFrontmatter(open string, block []byte, close string, body []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(open) + len(block) + len(close) + 1 + len(body))
+	buf.WriteString(open)
+	buf.Write(block)
+	buf.WriteString(close)
+	if len(body) > 0 {
+		buf.WriteByte('\n')
+		buf.Write(body)
+	}
+	return buf.Bytes()
+}
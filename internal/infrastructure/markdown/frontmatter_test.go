@@ -0,0 +1,262 @@
+package markdown
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func testFrontmatter() *Frontmatter {
+	fields := NewFrontmatter()
+	fields.Set("story_points", 5)
+	fields.Set("estimate", 1.5)
+	fields.Set("team name", `Core "Platform"`)
+
+	frontmatter := NewFrontmatter()
+	frontmatter.Set("key", "JMD-7")
+	frontmatter.Set("summary", "Line one\nline two")
+	frontmatter.Set("labels", []string{"a", "b"})
+	frontmatter.Set("flagged", true)
+	frontmatter.Set("updated", "2024-03-02T10:30:00Z")
+	frontmatter.Set("fields", fields)
+	return frontmatter
+}
+
+func TestFrontmatterCodec_RoundTrip(t *testing.T) {
+	body := []byte("# JMD-7: Title\n\nBody\n")
+	for _, format := range []domain.FrontmatterFormat{domain.FrontmatterYAML, domain.FrontmatterTOML, domain.FrontmatterJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			codec, err := NewFrontmatterCodec(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			content, sidecar, err := codec.Encode(testFrontmatter(), body)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if (sidecar != nil) != (format == domain.FrontmatterJSON) {
+				t.Errorf("Encode() sidecar = %q", sidecar)
+			}
+
+			frontmatter, gotBody, err := codec.Decode(content, sidecar)
+			if err != nil {
+				t.Fatalf("Decode() error = %v\n%s", err, content)
+			}
+			if string(gotBody) != string(body) {
+				t.Errorf("Decode() body = %q, want %q", gotBody, body)
+			}
+			frontmatter.yamlNodes = nil // only kept to write unchanged keys back as read
+			if !reflect.DeepEqual(frontmatter, testFrontmatter()) {
+				t.Errorf("Decode() frontmatter = %+v, want %+v", frontmatter, testFrontmatter())
+			}
+		})
+	}
+}
+
+func TestTOMLCodec_Encode(t *testing.T) {
+	content, _, err := tomlCodec{}.Encode(testFrontmatter(), []byte("Body\n"))
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	want := `+++
+key = "JMD-7"
+summary = "Line one\nline two"
+labels = ["a", "b"]
+flagged = true
+updated = "2024-03-02T10:30:00Z"
+
+[fields]
+story_points = 5
+estimate = 1.5
+"team name" = "Core \"Platform\""
++++
+
+Body
+`
+	if string(content) != want {
+		t.Errorf("Encode() =\n%s\nwant\n%s", content, want)
+	}
+}
+
+func TestTOMLCodec_Decode(t *testing.T) {
+	content := "+++\r\n" +
+		"# Written by hand\r\n" +
+		"title = 'C:\\notes'  # literal\r\n" +
+		"date = 2024-03-01T09:00:00Z\r\n" +
+		"count = 1_000\r\n" +
+		"tags = [\r\n  \"x\",\r\n  \"y\", # trailing comma\r\n]\r\n" +
+		"author = { name = \"Ann\", email = \"ann@example.com\" }\r\n" +
+		"site.theme = \"dark\"\r\n" +
+		"+++\r\n" +
+		"Body\r\n"
+
+	frontmatter, body, err := tomlCodec{}.Decode([]byte(content), nil)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if string(body) != "Body\n" {
+		t.Errorf("body = %q", body)
+	}
+
+	want := map[string]interface{}{
+		"title": `C:\notes`,
+		"date":  "2024-03-01T09:00:00Z",
+		"count": int64(1000),
+		"tags":  []interface{}{"x", "y"},
+	}
+	for key, value := range want {
+		if got, _ := frontmatter.Get(key); !reflect.DeepEqual(got, value) {
+			t.Errorf("%s = %#v, want %#v", key, got, value)
+		}
+	}
+	if got := strings.Join(frontmatter.Keys(), ","); got != "title,date,count,tags,author,site" {
+		t.Errorf("Keys() = %s", got)
+	}
+	author, _ := frontmatter.Get("author")
+	if email, _ := author.(*Frontmatter).Get("email"); email != "ann@example.com" {
+		t.Errorf("author.email = %v", email)
+	}
+	site, _ := frontmatter.Get("site")
+	if theme, _ := site.(*Frontmatter).Get("theme"); theme != "dark" {
+		t.Errorf("site.theme = %v", theme)
+	}
+}
+
+func TestFrontmatterCodec_Decode_Invalid(t *testing.T) {
+	tests := []struct {
+		format  domain.FrontmatterFormat
+		content string
+		sidecar string
+	}{
+		{domain.FrontmatterYAML, "---\nkey: [unclosed\n---\n", ""},
+		{domain.FrontmatterYAML, "---\n- a list\n---\n", ""},
+		{domain.FrontmatterTOML, "+++\nkey = \"unclosed\n+++\n", ""},
+		{domain.FrontmatterTOML, "+++\nkey = 1\n", ""},
+		{domain.FrontmatterTOML, "+++\n[[items]]\n+++\n", ""},
+		{domain.FrontmatterJSON, "Body\n", "[1, 2]"},
+		{domain.FrontmatterJSON, "Body\n", "{\"key\": "},
+	}
+	for _, tt := range tests {
+		codec, err := NewFrontmatterCodec(tt.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sidecar []byte
+		if tt.sidecar != "" {
+			sidecar = []byte(tt.sidecar)
+		}
+		if _, _, err := codec.Decode([]byte(tt.content), sidecar); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s Decode(%q, %q) error = %v, want ErrInvalidInput", tt.format, tt.content, tt.sidecar, err)
+		}
+	}
+}
+
+func TestScalarNode(t *testing.T) {
+	values := []interface{}{
+		nil, true, false, int64(0), int64(-42),
+		"", "plain", "JMD-7", " padded ", "trailing: colon", "# not a comment", "- item",
+		"true", "null", "~", "12", "1.5", "0x1F", ".inf", "2024-01-02T09:30:00Z", "2024-01-02",
+		"yes", "No", "off", "1:20", "-3:25:45.5", `Core "Platform"`, "it's", "Line one\nline two",
+		"trailing newline\n", "tab\tseparated", "emoji ✓", "&anchor", "*alias", "!tag", "@at", "`tick`",
+		"a very long line " + strings.Repeat("that goes on and on ", 10),
+	}
+	for _, value := range values {
+		var want yaml.Node
+		if err := want.Encode(value); err != nil {
+			t.Fatalf("Encode(%#v) error = %v", value, err)
+		}
+		got, ok := scalarNode(value)
+		if !ok {
+			continue
+		}
+
+		// Compare as mapping values and list items, where the encoder writes them
+		for _, wrap := range []func(*yaml.Node) *yaml.Node{
+			func(node *yaml.Node) *yaml.Node {
+				return &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Value: "key"}, node}}
+			},
+			func(node *yaml.Node) *yaml.Node {
+				return &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{node}}
+			},
+		} {
+			wantYAML, err := yaml.Marshal(wrap(&want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotYAML, err := yaml.Marshal(wrap(got))
+			if err != nil {
+				t.Fatalf("Marshal(scalarNode(%#v)) error = %v", value, err)
+			}
+			if string(gotYAML) != string(wantYAML) {
+				t.Errorf("scalarNode(%#v) encodes as %q, want %q", value, gotYAML, wantYAML)
+			}
+		}
+	}
+}
+
+func TestFrontmatter_Delete(t *testing.T) {
+	frontmatter := testFrontmatter()
+	frontmatter.Delete("labels")
+	frontmatter.Delete("missing")
+	if got := strings.Join(frontmatter.Keys(), ","); got != "key,summary,flagged,updated,fields" {
+		t.Errorf("Keys() = %s", got)
+	}
+	if _, ok := frontmatter.Get("labels"); ok {
+		t.Error("labels still set")
+	}
+}
+
+func TestFrontmatter_Order(t *testing.T) {
+	frontmatter := testFrontmatter()
+	frontmatter.Order([]string{"updated", "missing", "key", "updated"})
+	if got := strings.Join(frontmatter.Keys(), ","); got != "updated,key,summary,labels,flagged,fields" {
+		t.Errorf("Keys() = %s", got)
+	}
+}
+
+func TestMergeFrontmatter(t *testing.T) {
+	content := "---\n" +
+		"key: JMD-7\n" +
+		"aliases: [checkout, payments]  # for search\n" +
+		"status: To Do\n" +
+		"fields:\n    story_points: 3\n" +
+		"# reviewed by the team\n" +
+		"reviewed: 2024-01-05\n" +
+		"---\n"
+	existing, _, err := yamlCodec{}.Decode([]byte(content), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated := NewFrontmatter()
+	generated.Set("key", "JMD-7")
+	generated.Set("status", "Done")
+
+	merged := MergeFrontmatter(existing, generated, []string{"key", "status", "fields"})
+	got, _, err := yamlCodec{}.Encode(merged, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "---\n" +
+		"key: JMD-7\n" +
+		"status: Done\n" +
+		"aliases: [checkout, payments] # for search\n" +
+		"# reviewed by the team\n" +
+		"reviewed: 2024-01-05\n" +
+		"---\n"
+	if string(got) != want {
+		t.Errorf("merged frontmatter =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSidecarPath(t *testing.T) {
+	if got := SidecarPath("tickets/JMD/JMD-7.md"); got != "tickets/JMD/JMD-7.json" {
+		t.Errorf("SidecarPath() = %s", got)
+	}
+}
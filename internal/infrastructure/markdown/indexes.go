@@ -0,0 +1,181 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// IndexDir is the directory under the markdown directory that index files are written to.
+const IndexDir = "indexes"
+
+// indexTitles are the headings of the index files of each group, before the name.
+var indexTitles = map[string]string{
+	domain.IndexGroupSprint:   "Sprint",
+	domain.IndexGroupAssignee: "Assignee",
+	domain.IndexGroupFilter:   "Filter",
+}
+
+// IndexWriter writes the per-sprint and per-assignee index files under a markdown
+// directory (e.g. indexes/assignee/alice.md), linking to the ticket files there.
+type IndexWriter struct {
+	markdownDir string
+	skipDir     string
+}
+
+// NewIndexWriter creates a writer for the indexes of the ticket files under markdownDir,
+// never linking to the files under skipDir (e.g., the archive of tickets no longer
+// synced; may be empty).
+func NewIndexWriter(markdownDir, skipDir string) *IndexWriter {
+	writer := &IndexWriter{markdownDir: filepath.Clean(markdownDir)}
+	if skipDir != "" {
+		writer.skipDir = filepath.Clean(skipDir)
+	}
+	return writer
+}
+
+// WriteIndexes writes indexes under the index directory, rewriting only the files whose
+// content changed, so a sync that changed a few tickets only touches the indexes listing
+// them. Indexes marked Keep are left as they are. Index files of sprints, assignees, and
+// filters that are no longer listed are removed. Index files are written in parallel.
+// Returns the number of files written and removed.
+func (w *IndexWriter) WriteIndexes(ctx context.Context, indexes []*domain.TicketIndex) (int, error) {
+	files, err := findTicketFiles(ctx, w.markdownDir, w.skipDir)
+	if err != nil {
+		return 0, err
+	}
+	root := filepath.Join(w.markdownDir, IndexDir)
+
+	keep := make(map[string]bool, len(indexes))
+	for _, index := range indexes {
+		keep[filepath.Join(root, filepath.FromSlash(index.Path()))] = true
+	}
+
+	var changed atomic.Int64
+	err = forEachFile(ctx, len(indexes), func(i int) error {
+		index := indexes[i]
+		if index.Keep {
+			return nil
+		}
+		path := filepath.Join(root, filepath.FromSlash(index.Path()))
+		content := w.renderIndex(index, filepath.Dir(path), files)
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+			return nil
+		}
+		if err := writeFileAtomic(path, content, filePermOf(path)); err != nil {
+			return fmt.Errorf("failed to write index %s: %w", index.Path(), err)
+		}
+		changed.Add(1)
+		return nil
+	})
+	if err != nil {
+		return int(changed.Load()), err
+	}
+
+	total := int(changed.Load())
+	for group := range indexTitles {
+		removed, err := removeStaleIndexes(filepath.Join(root, group), keep)
+		total += removed
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// renderIndex returns the content of an index file in dir, linking to the ticket files
+// in files (or where they would be written if missing).
+func (w *IndexWriter) renderIndex(index *domain.TicketIndex, dir string, files map[domain.TicketKey]string) []byte {
+	var b bytes.Buffer
+	b.Grow(256 + 128*len(index.Tickets))
+	fmt.Fprintf(&b, "# %s: %s\n\n", indexTitles[index.Group], index.Name)
+	b.WriteString("*This file is generated by jiramd from the ticket cache. Edits are overwritten.*\n\n")
+
+	b.WriteString("| Ticket | Summary | Status | Assignee | Priority |\n|---|---|---|---|---|\n")
+	// Most tickets share a directory, so the relative path to each is only worked out once
+	relDirs := make(map[string]string)
+	for _, ticket := range index.Tickets {
+		target, ok := files[ticket.Key]
+		if !ok {
+			target = filepath.Join(w.markdownDir, ticket.Key.String()+".md")
+		}
+		href := ticket.Key.String() + ".md"
+		targetDir := filepath.Dir(target)
+		relDir, ok := relDirs[targetDir]
+		if !ok {
+			if rel, err := filepath.Rel(dir, targetDir); err == nil {
+				relDir = filepath.ToSlash(rel)
+			}
+			relDirs[targetDir] = relDir
+		}
+		if relDir != "" {
+			href = path.Join(relDir, filepath.Base(target))
+		}
+		fmt.Fprintf(&b, "| [%s](%s) | %s | %s | %s | %s |\n", ticket.Key, href,
+			indexCell(ticket.Summary), indexCell(ticket.Status), indexCell(ticket.Assignee), indexCell(ticket.Priority))
+	}
+
+	if len(index.Tickets) == 1 {
+		b.WriteString("\n1 ticket\n")
+	} else {
+		fmt.Fprintf(&b, "\n%d tickets\n", len(index.Tickets))
+	}
+	return b.Bytes()
+}
+
+// removeStaleIndexes removes the index files in dir that are not in keep, returning how
+// many were removed. A missing dir has nothing to remove.
+func removeStaleIndexes(dir string, keep map[string]bool) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to list indexes: %w", err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") || keep[path] {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, fmt.Errorf("failed to remove stale index %s: %w", path, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// indexCell escapes a value for a markdown table cell, on one line.
+func indexCell(value string) string {
+	if isCellSafe(value) {
+		return value
+	}
+	value = strings.Join(strings.Fields(value), " ")
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+// isCellSafe returns true if value is already on one line with single spaces and no
+// pipes, as most values are, so indexCell can return it as it is.
+func isCellSafe(value string) bool {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '|' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f' || c >= utf8.RuneSelf:
+			return false
+		case c == ' ' && (i == 0 || i == len(value)-1 || value[i+1] == ' '):
+			return false
+		}
+	}
+	return true
+}
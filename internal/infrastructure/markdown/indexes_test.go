@@ -0,0 +1,100 @@
+package markdown
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestIndexWriter_WriteIndexes(t *testing.T) {
+	dir := t.TempDir()
+	ticketFile := filepath.Join(dir, "JMD", "JMD-1.md")
+	if err := os.MkdirAll(filepath.Dir(ticketFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ticketFile, []byte("# JMD-1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first := &domain.Ticket{Key: ticketKey(t, "JMD-1"), Summary: "Login | signup", Status: "In Progress", Assignee: "alice@example.com"}
+	second := &domain.Ticket{Key: ticketKey(t, "JMD-2"), Summary: "Logout", Status: "To Do", Assignee: "bob@example.com"}
+	first.CustomFields = map[string]domain.FieldValue{domain.SprintsField: domain.NewFieldValue([]string{"JMD Sprint 12"})}
+
+	writer := NewIndexWriter(dir, "")
+	options := domain.IndexOptions{BySprint: true, ByAssignee: true}
+	ctx := context.Background()
+
+	written, err := writer.WriteIndexes(ctx, domain.BuildIndexes([]*domain.Ticket{first, second}, options))
+	if err != nil {
+		t.Fatalf("WriteIndexes() error = %v", err)
+	}
+	if written != 3 {
+		t.Errorf("WriteIndexes() changed %d files, want 3", written)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, IndexDir, "assignee", "alice.md"))
+	if err != nil {
+		t.Fatalf("assignee index not written: %v", err)
+	}
+	want := "# Assignee: alice@example.com\n\n" +
+		"*This file is generated by jiramd from the ticket cache. Edits are overwritten.*\n\n" +
+		"| Ticket | Summary | Status | Assignee | Priority |\n|---|---|---|---|---|\n" +
+		"| [JMD-1](../../JMD/JMD-1.md) | Login \\| signup | In Progress | alice@example.com |  |\n" +
+		"\n1 ticket\n"
+	if string(content) != want {
+		t.Errorf("assignee index =\n%s\nwant\n%s", content, want)
+	}
+	sprint, err := os.ReadFile(filepath.Join(dir, IndexDir, "sprint", "jmd-sprint-12.md"))
+	if err != nil || !strings.HasPrefix(string(sprint), "# Sprint: JMD Sprint 12\n") {
+		t.Errorf("sprint index = %q, %v", sprint, err)
+	}
+
+	// Nothing changed: nothing is rewritten
+	written, err = writer.WriteIndexes(ctx, domain.BuildIndexes([]*domain.Ticket{first, second}, options))
+	if err != nil || written != 0 {
+		t.Errorf("WriteIndexes() again = %d, %v, want no files changed", written, err)
+	}
+
+	// Bob's ticket moves to Alice: only her index changes, and Bob's is removed
+	second.Assignee = "alice@example.com"
+	written, err = writer.WriteIndexes(ctx, domain.BuildIndexes([]*domain.Ticket{first, second}, options))
+	if err != nil || written != 2 {
+		t.Errorf("WriteIndexes() after a reassignment = %d, %v, want 2 files changed", written, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, IndexDir, "assignee", "bob.md")); !os.IsNotExist(err) {
+		t.Errorf("stale assignee index was not removed: %v", err)
+	}
+}
+
+func TestIndexWriter_WriteIndexes_KeepsFilterIndex(t *testing.T) {
+	dir := t.TempDir()
+	writer := NewIndexWriter(dir, "")
+	ctx := context.Background()
+	config := domain.FilterIndex{ID: "10042", Name: "Team board"}
+	ticket := &domain.Ticket{Key: ticketKey(t, "OPS-7"), Summary: "Outage", Status: "To Do"}
+
+	filter := &domain.SavedFilter{ID: "10042", Name: "Ops", JQL: "project = OPS"}
+	if _, err := writer.WriteIndexes(ctx, []*domain.TicketIndex{domain.NewFilterIndex(config, filter, []*domain.Ticket{ticket})}); err != nil {
+		t.Fatalf("WriteIndexes() error = %v", err)
+	}
+	path := filepath.Join(dir, IndexDir, "filter", "team-board.md")
+	before, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(before), "[OPS-7](../../OPS-7.md)") {
+		t.Fatalf("filter index = %q, %v, want OPS-7 listed", before, err)
+	}
+
+	// The filter could not be run: its index is left as it was
+	kept := domain.NewFilterIndex(config, nil, nil)
+	kept.Keep = true
+	written, err := writer.WriteIndexes(ctx, []*domain.TicketIndex{kept})
+	if err != nil || written != 0 {
+		t.Errorf("WriteIndexes() of a kept index = %d, %v, want no files changed", written, err)
+	}
+	if after, err := os.ReadFile(path); err != nil || string(after) != string(before) {
+		t.Errorf("kept filter index = %q, %v, want it unchanged", after, err)
+	}
+}
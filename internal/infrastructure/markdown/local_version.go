@@ -0,0 +1,54 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// LocalVersionWriter saves copies of ticket files with local changes under the local
+// versions directory of the markdown directory (domain.LocalVersionsDir), before a pull
+// overwrites them.
+type LocalVersionWriter struct {
+	markdownDir string
+	now         func() time.Time
+}
+
+// NewLocalVersionWriter creates a writer for the ticket files under markdownDir.
+func NewLocalVersionWriter(markdownDir string) *LocalVersionWriter {
+	return &LocalVersionWriter{
+		markdownDir: filepath.Clean(markdownDir),
+		now:         time.Now,
+	}
+}
+
+// SaveLocalVersion copies the file at path, the file of the ticket key, to the local
+// versions directory and returns the record of the copy, for a pull about to overwrite
+// the file with the ticket's jiraVersion. The copy is synced to disk before it returns,
+// so it survives a crash during the overwrite. A missing file has nothing to save and
+// returns nil.
+func (w *LocalVersionWriter) SaveLocalVersion(ctx context.Context, key domain.TicketKey, path, jiraVersion string) (*domain.LocalVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local version of %s: %w", key, err)
+	}
+
+	savedAt := w.now().UTC()
+	target := filepath.Join(w.markdownDir, filepath.FromSlash(domain.LocalVersionPath(key, savedAt)))
+	if err := writeFileAtomic(target, content, filePermOf(path)); err != nil {
+		return nil, fmt.Errorf("failed to save local version of %s: %w", key, err)
+	}
+	return domain.NewLocalVersion(key, target, content, jiraVersion, savedAt), nil
+}
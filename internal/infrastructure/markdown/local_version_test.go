@@ -0,0 +1,51 @@
+package markdown
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLocalVersionWriter_SaveLocalVersion(t *testing.T) {
+	dir := t.TempDir()
+	key := ticketKey(t, "JMD-1")
+	path := filepath.Join(dir, "JMD", "JMD-1.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# JMD-1: local edits\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	writer := NewLocalVersionWriter(dir)
+	writer.now = func() time.Time { return time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	version, err := writer.SaveLocalVersion(ctx, key, path, "2024-01-02T08:30:00Z")
+	if err != nil {
+		t.Fatalf("SaveLocalVersion() error = %v", err)
+	}
+	want := filepath.Join(dir, ".jiramd", "local-versions", "JMD-1.20240102T090000.000Z.md")
+	if version.Path != want || version.JiraVersion != "2024-01-02T08:30:00Z" || version.TicketKey != key {
+		t.Errorf("SaveLocalVersion() = %+v, want the copy at %s", version, want)
+	}
+	saved, err := os.ReadFile(want)
+	if err != nil || string(saved) != "# JMD-1: local edits\n" {
+		t.Errorf("saved copy = %q, %v", saved, err)
+	}
+	if info, err := os.Stat(want); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("saved copy mode = %v, %v; want the file's 0600", info.Mode().Perm(), err)
+	}
+
+	// The copy is not taken for a ticket file
+	files, err := findTicketFiles(ctx, dir, "")
+	if err != nil || len(files) != 1 || files[key] != path {
+		t.Errorf("findTicketFiles() = %v, %v; want only %s", files, err, path)
+	}
+
+	if version, err := writer.SaveLocalVersion(ctx, key, filepath.Join(dir, "JMD-2.md"), ""); err != nil || version != nil {
+		t.Errorf("SaveLocalVersion(missing) = %+v, %v; want nil", version, err)
+	}
+}
@@ -0,0 +1,119 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// AttachmentDir is the directory under the markdown directory that the media referenced
+// by tickets is downloaded to, one directory per ticket (e.g. attachments/JMD-1/shot.png).
+const AttachmentDir = "attachments"
+
+// AttachmentSource downloads the content of Jira attachments.
+type AttachmentSource interface {
+	DownloadAttachment(ctx context.Context, attachment domain.Attachment, w io.Writer) error
+}
+
+// mediaFileName returns the name a ticket's attachment is saved under: its filename with
+// path separators replaced, so a file can never be written outside the ticket's directory.
+func mediaFileName(filename string) string {
+	name := strings.NewReplacer("/", "_", `\`, "_").Replace(filename)
+	if name == "." || name == ".." {
+		name = strings.Repeat("_", len(name))
+	}
+	return name
+}
+
+// mediaLink returns the link to a ticket's attachment from a ticket file, with dir the
+// attachment directory relative to the file.
+func mediaLink(dir string, key domain.TicketKey, filename string) string {
+	return dir + "/" + url.PathEscape(key.String()) + "/" + url.PathEscape(mediaFileName(filename))
+}
+
+// linkMedia returns text with the media references to the ticket's attachments (e.g.
+// !shot.png|thumbnail!) rewritten as markdown image links to the downloaded files under
+// dir, keeping the reference as the alt text so unlinkMedia can restore it. References to
+// files the ticket doesn't have are left as they are.
+func linkMedia(text, dir string, ticket *domain.Ticket) string {
+	return domain.ReplaceMediaRefs(text, func(name, ref string) (string, bool) {
+		attachment, ok := ticket.Attachment(name)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("![%s](%s)", ref, mediaLink(dir, ticket.Key, attachment.Filename)), true
+	})
+}
+
+// unlinkMedia reverses linkMedia, so a rewritten description reads back the way Jira
+// holds it. Links from files in subdirectories, which climb to dir (e.g.
+// ../attachments), are reversed too.
+func unlinkMedia(text, dir string, key domain.TicketKey) string {
+	pattern := regexp.MustCompile(`!\[([^\]\n]*)\]\((?:\.\./)*` + regexp.QuoteMeta(dir+"/"+url.PathEscape(key.String())+"/") + `[^)\s]*\)`)
+	return pattern.ReplaceAllString(text, "!$1!")
+}
+
+// MediaWriter downloads the Jira attachments ticket files reference under a markdown
+// directory, so the files render offline.
+type MediaWriter struct {
+	markdownDir string
+	source      AttachmentSource
+}
+
+// NewMediaWriter creates a writer downloading attachments from source to the attachment
+// directory under markdownDir.
+func NewMediaWriter(markdownDir string, source AttachmentSource) *MediaWriter {
+	return &MediaWriter{markdownDir: filepath.Clean(markdownDir), source: source}
+}
+
+// Path returns the path a ticket's attachment is downloaded to.
+func (w *MediaWriter) Path(key domain.TicketKey, attachment domain.Attachment) string {
+	return filepath.Join(w.markdownDir, AttachmentDir, key.String(), mediaFileName(attachment.Filename))
+}
+
+// WriteMedia downloads the attachments referenced by the ticket's description and
+// comments that are missing, or whose size differs from Jira's because they were replaced
+// or a download was cut short. References to files the ticket doesn't have are skipped.
+// Returns the number of files downloaded.
+func (w *MediaWriter) WriteMedia(ctx context.Context, ticket *domain.Ticket) (int, error) {
+	texts := []string{ticket.Description}
+	for _, comment := range ticket.Comments {
+		texts = append(texts, comment.Body)
+	}
+
+	downloaded := 0
+	seen := make(map[string]bool)
+	for _, name := range domain.MediaRefs(strings.Join(texts, "\n")) {
+		attachment, ok := ticket.Attachment(name)
+		if !ok || seen[attachment.ID] {
+			continue
+		}
+		seen[attachment.ID] = true
+
+		path := w.Path(ticket.Key, attachment)
+		if info, err := os.Stat(path); err == nil && info.Size() == attachment.Size {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return downloaded, err
+		}
+
+		var content bytes.Buffer
+		if err := w.source.DownloadAttachment(ctx, attachment, &content); err != nil {
+			return downloaded, fmt.Errorf("failed to download %s of %s: %w", attachment.Filename, ticket.Key, err)
+		}
+		if err := writeFileAtomic(path, content.Bytes(), filePermOf(path)); err != nil {
+			return downloaded, fmt.Errorf("failed to write %s of %s: %w", attachment.Filename, ticket.Key, err)
+		}
+		downloaded++
+	}
+	return downloaded, nil
+}
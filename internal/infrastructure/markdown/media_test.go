@@ -0,0 +1,151 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// fakeAttachments serves attachment content by ID and counts downloads.
+type fakeAttachments struct {
+	content   map[string]string
+	downloads int
+}
+
+func (f *fakeAttachments) DownloadAttachment(ctx context.Context, attachment domain.Attachment, w io.Writer) error {
+	content, ok := f.content[attachment.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	f.downloads++
+	_, err := io.WriteString(w, content)
+	return err
+}
+
+func mediaTicket(t *testing.T) *domain.Ticket {
+	t.Helper()
+	ticket := &domain.Ticket{
+		Key:         ticketKey(t, "JMD-4"),
+		Summary:     "Broken layout",
+		Description: "See !shot one.png|thumbnail! and !missing.png!, not !attachment!.",
+		Comments:    []*domain.Comment{{Author: "bob", Body: "Also !logs/run.txt!"}},
+	}
+	ticket.CustomFields = map[string]domain.FieldValue{domain.AttachmentsField: domain.AttachmentsValue([]domain.Attachment{
+		{ID: "10", Filename: "shot one.png", MimeType: "image/png", Size: 4},
+		{ID: "11", Filename: "logs/run.txt", MimeType: "text/plain", Size: 3},
+		{ID: "12", Filename: "unused.pdf", Size: 1},
+	})}
+	return ticket
+}
+
+func TestParser_AttachmentLinks(t *testing.T) {
+	ticket := mediaTicket(t)
+	parser := NewParser().WithAttachmentLinks(AttachmentDir)
+	content, _, err := parser.GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	for _, want := range []string{
+		"See ![shot one.png|thumbnail](attachments/JMD-4/shot%20one.png) and !missing.png!, not !attachment!.",
+		"Also ![logs/run.txt](attachments/JMD-4/logs_run.txt)",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("GenerateTicket() = %s, want it to contain %q", content, want)
+		}
+	}
+
+	parsed, err := parser.ParseTicket(context.Background(), content, nil)
+	if err != nil {
+		t.Fatalf("ParseTicket() error = %v", err)
+	}
+	if parsed.Description != ticket.Description {
+		t.Errorf("ParseTicket() description = %q, want %q", parsed.Description, ticket.Description)
+	}
+
+	plain, _, err := NewParser().GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	if !strings.Contains(string(plain), ticket.Description) {
+		t.Errorf("GenerateTicket() without attachment links = %s, want the references as they are", plain)
+	}
+}
+
+func TestTicketFiles_AttachmentLinks(t *testing.T) {
+	dir := t.TempDir()
+	ticket := mediaTicket(t)
+	parser := NewParser().WithAttachmentLinks(AttachmentDir)
+	files := NewTicketFiles(dir, parser)
+	ctx := context.Background()
+
+	// Files in subdirectories link to the attachment directory at the top
+	uow := NewUnitOfWork(&txStateRepository{}, nil)
+	if err := files.Stage(ctx, uow, ticket, "watchlist/JMD-4.md"); err != nil {
+		t.Fatalf("Stage() error = %v", err)
+	}
+	if err := uow.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	content := readFile(t, filepath.Join(dir, "watchlist", "JMD-4.md"))
+	if want := "![shot one.png|thumbnail](../attachments/JMD-4/shot%20one.png)"; !strings.Contains(content, want) {
+		t.Errorf("ticket file = %s, want it to contain %q", content, want)
+	}
+
+	parsed, err := parser.ParseTicket(ctx, []byte(content), nil)
+	if err != nil {
+		t.Fatalf("ParseTicket() error = %v", err)
+	}
+	if parsed.Description != ticket.Description {
+		t.Errorf("ParseTicket() description = %q, want %q", parsed.Description, ticket.Description)
+	}
+}
+
+func TestMediaWriter_WriteMedia(t *testing.T) {
+	dir := t.TempDir()
+	source := &fakeAttachments{content: map[string]string{"10": "png!", "11": "log"}}
+	writer := NewMediaWriter(dir, source)
+	ticket := mediaTicket(t)
+	ctx := context.Background()
+
+	written, err := writer.WriteMedia(ctx, ticket)
+	if err != nil {
+		t.Fatalf("WriteMedia() error = %v", err)
+	}
+	if written != 2 {
+		t.Errorf("WriteMedia() downloaded %d files, want 2", written)
+	}
+	for name, want := range map[string]string{"shot one.png": "png!", "logs_run.txt": "log"} {
+		got, err := os.ReadFile(filepath.Join(dir, AttachmentDir, "JMD-4", name))
+		if err != nil || string(got) != want {
+			t.Errorf("attachment %s = %q (%v), want %q", name, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, AttachmentDir, "JMD-4", "unused.pdf")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unreferenced attachment downloaded: %v", err)
+	}
+
+	// Files already downloaded are kept; a replaced one has another size
+	source.content["10"] = "a larger png"
+	ticket.CustomFields[domain.AttachmentsField] = domain.AttachmentsValue([]domain.Attachment{
+		{ID: "10", Filename: "shot one.png", Size: 12},
+		{ID: "11", Filename: "logs/run.txt", Size: 3},
+	})
+	if written, err := writer.WriteMedia(ctx, ticket); err != nil || written != 1 {
+		t.Errorf("WriteMedia() again = %d, %v, want 1 file downloaded", written, err)
+	}
+	if source.downloads != 3 {
+		t.Errorf("downloads = %d, want 3", source.downloads)
+	}
+
+	delete(source.content, "11")
+	os.Remove(writer.Path(ticket.Key, domain.Attachment{Filename: "logs/run.txt"}))
+	if _, err := writer.WriteMedia(ctx, ticket); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("WriteMedia() of a deleted attachment error = %v, want ErrNotFound", err)
+	}
+}
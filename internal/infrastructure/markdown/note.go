@@ -0,0 +1,245 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Note is a plain markdown file, such as an existing team note, that can become a new ticket.
+type Note struct {
+	// Path is the file the note was read from
+	Path string
+
+	// Title is the text of the first H1 heading, or the frontmatter title,
+	// or the file name when the note has neither
+	Title string
+
+	// Body is the markdown after the frontmatter, without the title heading
+	Body string
+
+	// Labels come from the frontmatter labels and tags lists
+	Labels []string
+
+	// IssueType is the frontmatter type (optional)
+	IssueType string
+
+	// Priority is the frontmatter priority (optional)
+	Priority string
+
+	// Key is the frontmatter key; notes with a key are already Jira tickets
+	Key string
+}
+
+// noteFrontmatter is the YAML frontmatter recognized in notes. Unknown keys are ignored.
+type noteFrontmatter struct {
+	Title     string     `yaml:"title"`
+	Labels    stringList `yaml:"labels"`
+	Tags      stringList `yaml:"tags"`
+	Type      string     `yaml:"type"`
+	IssueType string     `yaml:"issue_type"`
+	Priority  string     `yaml:"priority"`
+	Key       string     `yaml:"key"`
+}
+
+// stringList accepts either a YAML sequence or a comma-separated string.
+type stringList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (l *stringList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.SequenceNode:
+		var items []string
+		if err := value.Decode(&items); err != nil {
+			return err
+		}
+		*l = items
+	case yaml.ScalarNode:
+		for _, item := range strings.Split(value.Value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*l = append(*l, item)
+			}
+		}
+	default:
+		return fmt.Errorf("line %d: expected a list or a comma-separated string", value.Line)
+	}
+	return nil
+}
+
+// ParseNote parses a markdown note. path is only used for the fallback title and errors.
+// Returns ErrInvalidInput if the frontmatter is not valid YAML.
+func ParseNote(path string, content []byte) (*Note, error) {
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+
+	frontmatter, body, err := splitFrontmatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", domain.ErrInvalidInput, path, err)
+	}
+
+	var meta noteFrontmatter
+	if len(frontmatter) > 0 {
+		if err := yaml.Unmarshal(frontmatter, &meta); err != nil {
+			return nil, fmt.Errorf("%w: %s: invalid frontmatter: %v", domain.ErrInvalidInput, path, err)
+		}
+	}
+
+	title, text := extractTitle(body)
+	if title == "" {
+		title = strings.TrimSpace(meta.Title)
+	}
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	issueType := meta.IssueType
+	if issueType == "" {
+		issueType = meta.Type
+	}
+
+	return &Note{
+		Path:      path,
+		Title:     title,
+		Body:      strings.TrimSpace(text),
+		Labels:    normalizeLabels(append(meta.Labels, meta.Tags...)),
+		IssueType: strings.TrimSpace(issueType),
+		Priority:  strings.TrimSpace(meta.Priority),
+		Key:       strings.TrimSpace(meta.Key),
+	}, nil
+}
+
+// Draft converts the note into a ticket draft for projectKey.
+// defaultIssueType is used when the note has no type.
+func (n *Note) Draft(projectKey, defaultIssueType string) *domain.TicketDraft {
+	issueType := n.IssueType
+	if issueType == "" {
+		issueType = defaultIssueType
+	}
+	return &domain.TicketDraft{
+		ProjectKey:  projectKey,
+		Summary:     n.Title,
+		IssueType:   issueType,
+		Description: n.Body,
+		Priority:    n.Priority,
+		Labels:      n.Labels,
+		Source:      n.Path,
+	}
+}
+
+// ReadNotes reads every .md file under dir, in path order.
+// Hidden files and directories (such as .git or .obsidian) are skipped.
+func ReadNotes(dir string) ([]*Note, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(path), ".md") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: directory %s does not exist", domain.ErrNotFound, dir)
+		}
+		return nil, fmt.Errorf("failed to list notes in %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	notes := make([]*Note, 0, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read note: %w", err)
+		}
+		note, err := ParseNote(path, content)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, nil
+}
+
+// splitFrontmatter separates a leading "---" delimited YAML block from the markdown body.
+func splitFrontmatter(content []byte) (frontmatter, body []byte, err error) {
+	if !bytes.HasPrefix(content, []byte("---\n")) {
+		return nil, content, nil
+	}
+
+	rest := content[len("---\n"):]
+	for offset := 0; offset < len(rest); {
+		end := bytes.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end >= 0 {
+			line = rest[offset : offset+end]
+		}
+		if trimmed := string(bytes.TrimRight(line, " \t")); trimmed == "---" || trimmed == "..." {
+			if end < 0 {
+				return rest[:offset], nil, nil
+			}
+			return rest[:offset], rest[offset+end+1:], nil
+		}
+		if end < 0 {
+			break
+		}
+		offset += end + 1
+	}
+	return nil, nil, fmt.Errorf("frontmatter is not closed with ---")
+}
+
+// extractTitle returns the text of the first H1 heading outside code blocks
+// and the body with that heading removed.
+func extractTitle(body []byte) (string, string) {
+	lines := strings.Split(string(body), "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if strings.HasPrefix(trimmed, "# ") {
+			title := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(trimmed[2:]), "#"))
+			rest := append(append([]string(nil), lines[:i]...), lines[i+1:]...)
+			return title, strings.Join(rest, "\n")
+		}
+	}
+	return "", string(body)
+}
+
+// normalizeLabels makes labels valid for Jira: a leading # is dropped, spaces become
+// dashes, and empty or duplicate labels are removed.
+func normalizeLabels(labels []string) []string {
+	seen := make(map[string]bool, len(labels))
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(label), "#")), "-")
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	return normalized
+}
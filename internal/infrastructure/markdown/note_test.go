@@ -0,0 +1,153 @@
+package markdown
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestParseNote(t *testing.T) {
+	content := "---\r\n" +
+		"labels: [backend, payments]\r\n" +
+		"tags:\r\n  - \"#needs review\"\r\n  - backend\r\n" +
+		"type: Bug\r\n" +
+		"priority: High\r\n" +
+		"author: someone\r\n" +
+		"---\r\n" +
+		"Intro line\r\n" +
+		"\r\n" +
+		"# Payment timeout on checkout #\r\n" +
+		"\r\n" +
+		"The gateway times out.\r\n"
+
+	note, err := ParseNote("notes/payments.md", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseNote failed: %v", err)
+	}
+
+	if note.Title != "Payment timeout on checkout" {
+		t.Errorf("Title = %q", note.Title)
+	}
+	if note.Body != "Intro line\n\n\nThe gateway times out." {
+		t.Errorf("Body = %q", note.Body)
+	}
+	if strings.Join(note.Labels, ",") != "backend,payments,needs-review" {
+		t.Errorf("Labels = %v", note.Labels)
+	}
+	if note.IssueType != "Bug" || note.Priority != "High" || note.Key != "" {
+		t.Errorf("IssueType = %q, Priority = %q, Key = %q", note.IssueType, note.Priority, note.Key)
+	}
+}
+
+func TestParseNote_TitleFallbacks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"h1", "# From heading\nbody", "From heading"},
+		{"frontmatter title", "---\ntitle: From frontmatter\n---\nbody", "From frontmatter"},
+		{"h1 wins over frontmatter", "---\ntitle: From frontmatter\n---\n# From heading", "From heading"},
+		{"file name", "body only", "retro-notes"},
+		{"h2 is not a title", "## Section\nbody", "retro-notes"},
+		{"h1 in code block ignored", "```\n# comment\n```\n# Real title", "Real title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note, err := ParseNote("docs/retro-notes.md", []byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseNote failed: %v", err)
+			}
+			if note.Title != tt.want {
+				t.Errorf("Title = %q, want %q", note.Title, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNote_CommaSeparatedLabelsAndKey(t *testing.T) {
+	note, err := ParseNote("a.md", []byte("---\nlabels: one, two words\nkey: JMD-7\n---\n# A"))
+	if err != nil {
+		t.Fatalf("ParseNote failed: %v", err)
+	}
+	if strings.Join(note.Labels, ",") != "one,two-words" {
+		t.Errorf("Labels = %v", note.Labels)
+	}
+	if note.Key != "JMD-7" {
+		t.Errorf("Key = %q", note.Key)
+	}
+}
+
+func TestParseNote_InvalidFrontmatter(t *testing.T) {
+	for _, content := range []string{
+		"---\nlabels: [unclosed\n---\n# A",
+		"---\ntitle: never closed\n# A",
+		"---\nlabels:\n  nested: map\n---\n# A",
+	} {
+		if _, err := ParseNote("a.md", []byte(content)); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("ParseNote(%q): expected ErrInvalidInput, got %v", content, err)
+		}
+	}
+}
+
+func TestNote_Draft(t *testing.T) {
+	note := &Note{Path: "a.md", Title: "A", Body: "text", Labels: []string{"x"}, Priority: "Low"}
+
+	draft := note.Draft("JMD", "Task")
+	if draft.ProjectKey != "JMD" || draft.Summary != "A" || draft.Description != "text" || draft.Source != "a.md" {
+		t.Errorf("draft = %+v", draft)
+	}
+	if draft.IssueType != "Task" {
+		t.Errorf("IssueType = %q, want default", draft.IssueType)
+	}
+
+	note.IssueType = "Bug"
+	if got := note.Draft("JMD", "Task").IssueType; got != "Bug" {
+		t.Errorf("IssueType = %q, want note type", got)
+	}
+}
+
+func TestReadNotes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.md":               "# B",
+		"a.md":               "# A",
+		"sub/c.MD":           "# C",
+		"readme.txt":         "not a note",
+		".obsidian/x.md":     "# hidden dir",
+		"sub/.draft.md":      "# hidden file",
+		"sub/deeper/d.md":    "# D",
+		"sub/deeper/e.md.bk": "# backup",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	notes, err := ReadNotes(dir)
+	if err != nil {
+		t.Fatalf("ReadNotes failed: %v", err)
+	}
+
+	var titles []string
+	for _, note := range notes {
+		titles = append(titles, note.Title)
+	}
+	if strings.Join(titles, ",") != "A,B,C,D" {
+		t.Errorf("titles = %v", titles)
+	}
+
+	if _, err := ReadNotes(filepath.Join(dir, "missing")); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("missing dir: expected ErrNotFound, got %v", err)
+	}
+}
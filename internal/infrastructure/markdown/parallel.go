@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"context"
+	"sync"
+)
+
+// fileWorkers is how many files the writers read and write at once. Writing is mostly
+// waiting on the disk, so a few more workers than CPUs keep it busy.
+const fileWorkers = 8
+
+// forEachFile calls fn with each of 0..n-1 from up to fileWorkers goroutines. Once fn
+// returns an error or ctx is done, no further calls are started; calls already started
+// finish, and the first error is returned.
+func forEachFile(ctx context.Context, n int, fn func(i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	next := make(chan int)
+	for w := 0; w < min(fileWorkers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if err := fn(i); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
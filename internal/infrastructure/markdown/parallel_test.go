@@ -0,0 +1,49 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEachFile(t *testing.T) {
+	ctx := context.Background()
+
+	var calls [100]atomic.Int32
+	if err := forEachFile(ctx, len(calls), func(i int) error {
+		calls[i].Add(1)
+		return nil
+	}); err != nil {
+		t.Fatalf("forEachFile() error = %v", err)
+	}
+	for i := range calls {
+		if n := calls[i].Load(); n != 1 {
+			t.Errorf("fn(%d) called %d times, want once", i, n)
+		}
+	}
+
+	if err := forEachFile(ctx, 0, func(int) error { return errors.New("called") }); err != nil {
+		t.Errorf("forEachFile(0) error = %v, want nil", err)
+	}
+
+	// The first error stops further calls
+	failure := errors.New("disk full")
+	var started atomic.Int32
+	err := forEachFile(ctx, 1000, func(i int) error {
+		started.Add(1)
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("forEachFile() error = %v, want %v", err, failure)
+	}
+	if n := started.Load(); n > 2*fileWorkers {
+		t.Errorf("forEachFile() made %d calls after the first error, want it to stop", n)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := forEachFile(canceled, 1000, func(int) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("forEachFile(canceled) error = %v, want context.Canceled", err)
+	}
+}
@@ -3,32 +3,478 @@
 package markdown
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/esfisher/jiramd/internal/domain"
 )
 
+// Markers around the metadata section of a ticket file, which is generated from Jira
+// and never edited by hand.
+const (
+	metadataStart = "<!-- jiramd-metadata-start -->"
+	metadataEnd   = "<!-- jiramd-metadata-end -->"
+)
+
+// managedKeys are the frontmatter keys jiramd writes, in their default order.
+var managedKeys = []string{
+	SchemaKey, "key", "summary", "status", "type", "priority", "assignee", "reporter",
+	"labels", "created", "updated", "fields", domain.JiraFieldsField, domain.AuthorsField,
+}
+
 // Parser handles parsing markdown files into domain entities.
 type Parser struct {
-	// TODO: Add template engine and configuration
+	// flavor is the markdown dialect generated files are written in
+	flavor domain.MarkdownFlavor
+
+	// codec writes the frontmatter of generated files
+	codec FrontmatterCodec
+
+	// keyOrder is the order of frontmatter keys
+	keyOrder []string
+
+	// display formats the timestamps in the body; frontmatter keeps them in UTC
+	display domain.DisplayConfig
+
+	// attachmentDir is the attachment directory relative to ticket files, or "" to
+	// leave media references as Jira writes them
+	attachmentDir string
+
+	// limits returns how much of the tickets of a project is written (nil writes all)
+	limits func(projectKey string) domain.ContentLimits
 }
 
-// NewParser creates a new markdown parser.
+// NewParser creates a new markdown parser generating plain markdown with YAML frontmatter.
 func NewParser() *Parser {
-	return &Parser{}
+	return &Parser{flavor: domain.MarkdownFlavorPlain, codec: yamlCodec{}, keyOrder: managedKeys}
+}
+
+// WithFlavor sets the markdown dialect generated files are written in (empty keeps
+// plain markdown). The obsidian flavor also writes the ticket's fields as Dataview
+// inline fields.
+func (p *Parser) WithFlavor(flavor domain.MarkdownFlavor) *Parser {
+	if flavor != "" {
+		p.flavor = flavor
+	}
+	return p
+}
+
+// WithFrontmatter sets how the frontmatter of generated files is written (nil keeps YAML).
+func (p *Parser) WithFrontmatter(codec FrontmatterCodec) *Parser {
+	if codec != nil {
+		p.codec = codec
+	}
+	return p
+}
+
+// WithKeyOrder sets the order of frontmatter keys (empty keeps the default order). Keys
+// that are not listed follow the listed ones: jiramd's keys in their default order,
+// then the keys users added in the order they were added.
+func (p *Parser) WithKeyOrder(order []string) *Parser {
+	if len(order) > 0 {
+		p.keyOrder = append(append([]string(nil), order...), managedKeys...)
+	}
+	return p
+}
+
+// WithDisplay sets how timestamps in the body of generated files are shown (the zero
+// value shows them in RFC 3339 UTC). Frontmatter always holds them in RFC 3339 UTC.
+func (p *Parser) WithDisplay(display domain.DisplayConfig) *Parser {
+	p.display = display
+	return p
+}
+
+// WithCommentLimit writes only the most recent n comments of every ticket, like
+// WithContentLimits with the same comment limit for every project. Zero or less writes
+// every comment.
+func (p *Parser) WithCommentLimit(n int) *Parser {
+	limits := domain.ContentLimits{MaxComments: max(n, 0)}
+	p.limits = func(string) domain.ContentLimits { return limits }
+	return p
+}
+
+// WithContentLimits summarizes the files of tickets with huge descriptions or comment
+// threads: limits returns, for a ticket's project, how many characters of its
+// description are written, followed by a note that it is truncated, and how many of its
+// most recent comments, after a note saying how many older ones are in Jira. Both notes
+// point to jiramd expand, which writes a file in full. nil writes every ticket in full.
+func (p *Parser) WithContentLimits(limits func(projectKey string) domain.ContentLimits) *Parser {
+	p.limits = limits
+	return p
+}
+
+// limitsFor returns how much of ticket is written.
+func (p *Parser) limitsFor(ticket *domain.Ticket) domain.ContentLimits {
+	if p.limits == nil {
+		return domain.ContentLimits{}
+	}
+	limits := p.limits(ticket.Key.ProjectKey())
+	limits.MaxComments = max(limits.MaxComments, 0)
+	limits.MaxDescriptionLength = max(limits.MaxDescriptionLength, 0)
+	return limits
+}
+
+// WithAttachmentLinks rewrites the media references of descriptions and comments to the
+// ticket's attachments (e.g. !shot.png!) as image links to the files a MediaWriter
+// downloads, with dir the attachment directory relative to ticket files (e.g.
+// AttachmentDir for files at the top of the markdown directory). Empty leaves the
+// references as they are.
+func (p *Parser) WithAttachmentLinks(dir string) *Parser {
+	p.attachmentDir = strings.TrimSuffix(filepath.ToSlash(dir), "/")
+	return p
+}
+
+// ParseTicket parses a ticket file and its frontmatter sidecar (nil if it has none) back
+// into a ticket: the fields from its frontmatter, upgraded to the current schema, with
+// labels listed twice read once (see domain.NormalizeLabels), and the description from
+// the managed zone of its description section, with attachment links turned back into
+// media references. A description cut short by WithContentLimits
+// is read without its truncation note and marked DescriptionTruncated, so it is never
+// pushed. Local zones and the generated sections (time in status, comments, metadata)
+// are not read.
+// Returns ErrInvalidInput if the frontmatter does not parse or holds invalid values, or
+// a zone of the description is never closed.
+func (p *Parser) ParseTicket(ctx context.Context, content, sidecar []byte) (*domain.Ticket, error) {
+	frontmatter, body, err := p.codec.Decode(content, sidecar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read frontmatter: %w", err)
+	}
+	key, err := domain.NewTicketKey(frontmatterString(frontmatter, "key"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid key in frontmatter: %v", domain.ErrInvalidInput, err)
+	}
+	if _, err := MigrateFrontmatter(frontmatter, key); err != nil {
+		return nil, err
+	}
+
+	var times [2]time.Time
+	for i, name := range []string{"created", "updated"} {
+		value := frontmatterString(frontmatter, name)
+		if value == "" {
+			continue
+		}
+		if times[i], err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, fmt.Errorf("%w: %s of %s is not an RFC 3339 timestamp: %q", domain.ErrInvalidInput, name, key, value)
+		}
+	}
+
+	ticket := domain.NewTicket(key, frontmatterString(frontmatter, "summary"), times[0], times[1])
+	ticket.Status = frontmatterString(frontmatter, "status")
+	ticket.IssueType = frontmatterString(frontmatter, "type")
+	ticket.Priority = frontmatterString(frontmatter, "priority")
+	ticket.Assignee = frontmatterString(frontmatter, "assignee")
+	ticket.Reporter = frontmatterString(frontmatter, "reporter")
+	if labels, ok := frontmatter.Get("labels"); ok {
+		list, _ := plainValue(labels).([]interface{})
+		for _, label := range list {
+			ticket.Labels = append(ticket.Labels, fmt.Sprint(label))
+		}
+		ticket.Labels = domain.NormalizeLabels(ticket.Labels)
+	}
+	if fields, ok := frontmatter.Get("fields"); ok {
+		if fields, ok := fields.(*Frontmatter); ok {
+			for _, name := range fields.Keys() {
+				value, _ := fields.Get(name)
+				ticket.CustomFields[name] = domain.NewFieldValue(plainValue(value))
+			}
+		}
+	}
+	for _, name := range []string{domain.JiraFieldsField, domain.AuthorsField} {
+		if raw, ok := frontmatter.Get(name); ok && raw != nil {
+			ticket.CustomFields[name] = domain.NewFieldValue(plainValue(raw))
+		}
+	}
+
+	if ticket.Description, err = readDescription(body); err != nil {
+		return nil, fmt.Errorf("failed to read description of %s: %w", key, err)
+	}
+	ticket.Description, ticket.DescriptionTruncated = cutTruncationNote(ticket.Description)
+	if p.attachmentDir != "" {
+		ticket.Description = unlinkMedia(ticket.Description, p.attachmentDir, key)
+	}
+	return ticket, nil
+}
+
+// frontmatterString returns the value of key as a string, or "" if it is not set.
+func frontmatterString(frontmatter *Frontmatter, key string) string {
+	value, _ := frontmatter.Get(key)
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	}
+	return fmt.Sprint(value)
 }
 
-// ParseTicket parses a markdown file into a Ticket entity.
-// This is a placeholder for the actual implementation.
-func (p *Parser) ParseTicket(ctx context.Context, content []byte) (*domain.Ticket, error) {
-	// TODO: Implement markdown parsing logic
-	return nil, fmt.Errorf("markdown.Parser.ParseTicket not implemented")
+// plainValue converts a frontmatter value to the values JSON decodes to, with nested
+// frontmatter as maps, as ticket fields hold them.
+func plainValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case *Frontmatter:
+		plain := make(map[string]interface{}, value.Len())
+		for _, key := range value.Keys() {
+			item, _ := value.Get(key)
+			plain[key] = plainValue(item)
+		}
+		return plain
+	case []interface{}:
+		plain := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			plain = append(plain, plainValue(item))
+		}
+		return plain
+	}
+	return value
+}
+
+// inlineField is a ticket field listed in the body of a generated ticket file.
+type inlineField struct {
+	// name is the Dataview field name, the same as the frontmatter key
+	name string
+
+	// label is the field's name in plain markdown
+	label string
+	value string
+}
+
+// GenerateTicket generates a markdown file from a Ticket entity: frontmatter followed
+// by the layout of templates/ticket.tmpl. sidecar is the content of the file's
+// frontmatter sidecar, or nil when the frontmatter is in the file (see SidecarPath).
+func (p *Parser) GenerateTicket(ctx context.Context, ticket *domain.Ticket) (content, sidecar []byte, err error) {
+	return p.encode(ticket, ticketFrontmatter(ticket))
+}
+
+// RewriteTicket generates a ticket's markdown file like GenerateTicket, keeping the
+// frontmatter keys users added to its current content and sidecar (nil if it has none),
+// and everything around the managed zone of its description section: local zones and
+// any other notes there. Only the managed zone gets the ticket's description. When the
+// ticket's comments were not loaded (Comments is nil), the comments section of content
+// is kept as it is, so a pull that found no comment changes leaves it alone.
+// Returns ErrInvalidInput if the current frontmatter does not parse, or a zone of the
+// description is never closed, rather than dropping what they hold.
+func (p *Parser) RewriteTicket(ctx context.Context, ticket *domain.Ticket, content, sidecar []byte) ([]byte, []byte, error) {
+	existing, body, err := p.codec.Decode(content, sidecar)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read frontmatter of %s: %w", ticket.Key, err)
+	}
+	generated := getBodyBuffer()
+	defer bodyBuffers.Put(generated)
+	p.generateBody(generated, ticket)
+	merged, err := keepLocalZones(generated.Bytes(), body)
+	if err == nil && ticket.Comments == nil {
+		merged, err = keepComments(merged, body)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read description of %s: %w", ticket.Key, err)
+	}
+	return p.encodeBody(ticket, MergeFrontmatter(existing, ticketFrontmatter(ticket), managedKeys), merged)
+}
+
+// encode returns a ticket's file with frontmatter, sorted in the configured key order,
+// and its sidecar.
+func (p *Parser) encode(ticket *domain.Ticket, frontmatter *Frontmatter) ([]byte, []byte, error) {
+	body := getBodyBuffer()
+	defer bodyBuffers.Put(body)
+	p.generateBody(body, ticket)
+	return p.encodeBody(ticket, frontmatter, body.Bytes())
+}
+
+// encodeBody returns a ticket's file with frontmatter, sorted in the configured key
+// order, followed by body, and its sidecar.
+func (p *Parser) encodeBody(ticket *domain.Ticket, frontmatter *Frontmatter, body []byte) ([]byte, []byte, error) {
+	frontmatter.Order(p.keyOrder)
+	content, sidecar, err := p.codec.Encode(frontmatter, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate %s: %w", ticket.Key, err)
+	}
+	return content, sidecar, nil
+}
+
+// bodyBuffers holds the buffers ticket bodies are generated in, reused from file to file
+// since the codecs copy the body into the content they return.
+var bodyBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// getBodyBuffer returns an empty buffer from bodyBuffers.
+func getBodyBuffer() *bytes.Buffer {
+	buf := bodyBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// generateBody writes the markdown of a ticket's file after the frontmatter to body.
+func (p *Parser) generateBody(body *bytes.Buffer, ticket *domain.Ticket) {
+	p.writeHead(body, ticket)
+	comments, older := ticket.Comments, 0
+	if limit := p.limitsFor(ticket).MaxComments; limit > 0 && len(comments) > limit {
+		comments, older = comments[len(comments)-limit:], len(comments)-limit
+	}
+	p.writeComments(body, ticket, comments, older)
+	p.writeTail(body, ticket)
+}
+
+// writeHead writes the part of a ticket's body before its comments: the title, fields,
+// description, and time in status.
+func (p *Parser) writeHead(body *bytes.Buffer, ticket *domain.Ticket) {
+	fmt.Fprintf(body, "# %s: %s\n\n", ticket.Key, ticket.Summary)
+	for _, field := range summaryFields(ticket) {
+		p.writeField(body, "", field)
+	}
+
+	body.WriteString("\n" + descriptionHeading + "\n\n" + managedStart + "\n")
+	description, left := truncateText(strings.TrimSpace(ticket.Description), p.limitsFor(ticket).MaxDescriptionLength)
+	if description != "" {
+		body.WriteString(p.linkMedia(description, ticket))
+		body.WriteByte('\n')
+	}
+	if left > 0 {
+		writeTruncationNote(body, ticket.Key, left)
+	}
+	body.WriteString(managedEnd + "\n\n" + localStart + "\n" + localEnd + "\n\n")
+
+	if len(ticket.StatusHistory) > 0 {
+		p.writeTimeInStatus(body, ticket)
+	}
+}
+
+// writeComments writes the comments section of a ticket's body with comments, after a
+// note that older comments are only in Jira, or nothing if there are none.
+func (p *Parser) writeComments(body *bytes.Buffer, ticket *domain.Ticket, comments []*domain.Comment, older int) {
+	if len(comments) == 0 && older == 0 {
+		return
+	}
+	body.WriteString(commentsHeading + "\n\n")
+	writeOlderComments(body, ticket.Key, older)
+	for _, comment := range comments {
+		p.writeComment(body, ticket, comment)
+	}
+}
+
+// writeTail writes the part of a ticket's body after its comments: the metadata section
+// and the footer.
+func (p *Parser) writeTail(body *bytes.Buffer, ticket *domain.Ticket) {
+	body.WriteString(metadataStart + "\n## Metadata\n\n")
+	p.writeField(body, "- ", inlineField{name: "created", label: "Created", value: p.display.FormatTime(ticket.Created)})
+	p.writeField(body, "- ", inlineField{name: "updated", label: "Updated", value: p.display.FormatTime(ticket.Updated)})
+	body.WriteString(metadataEnd + "\n\n")
+
+	body.WriteString("---\n*This file is managed by jiramd. Do not edit the metadata section.*\n")
+}
+
+// writeTimeInStatus writes a table of the time a ticket spent in each status. The
+// current status only counts its earlier visits and says since when the ticket is in it,
+// so the file doesn't change until the ticket moves again.
+func (p *Parser) writeTimeInStatus(buf *bytes.Buffer, ticket *domain.Ticket) {
+	periods := ticket.StatusPeriods()
+	buf.WriteString("## Time in Status\n\n| Status | Time | Visits |\n|---|---:|---:|\n")
+	for _, spent := range ticket.TimeInStatus(periods[len(periods)-1].Start) {
+		status := strings.ReplaceAll(spent.Status, "|", `\|`)
+		if !spent.Since.IsZero() {
+			status = fmt.Sprintf("**%s** (since %s)", status, p.display.FormatTime(spent.Since))
+		}
+		fmt.Fprintf(buf, "| %s | %s | %d |\n", status, domain.FormatElapsed(spent.Duration), spent.Visits)
+	}
+	buf.WriteString("\n")
+}
+
+// writeComment writes a comment under a heading naming its author and time, with a
+// badge saying who can see it unless it is public.
+func (p *Parser) writeComment(buf *bytes.Buffer, ticket *domain.Ticket, comment *domain.Comment) {
+	fmt.Fprintf(buf, "### %s, %s", comment.Author, p.display.FormatTime(comment.Created))
+	if label := comment.Visibility.Label(); label != "" {
+		fmt.Fprintf(buf, " `%s`", label)
+	}
+	buf.WriteString("\n\n")
+	if text := strings.TrimSpace(comment.Body); text != "" {
+		buf.WriteString(p.linkMedia(text, ticket))
+		buf.WriteString("\n\n")
+	}
+}
+
+// linkMedia returns text with its media references linked to the ticket's downloaded
+// attachments when attachment links are enabled.
+func (p *Parser) linkMedia(text string, ticket *domain.Ticket) string {
+	if p.attachmentDir == "" {
+		return text
+	}
+	return linkMedia(text, p.attachmentDir, ticket)
+}
+
+// writeField writes a field on its own line, as a Dataview inline field in the obsidian
+// flavor and in bold otherwise.
+func (p *Parser) writeField(buf *bytes.Buffer, prefix string, field inlineField) {
+	if p.flavor == domain.MarkdownFlavorObsidian {
+		fmt.Fprintf(buf, "%s%s:: %s\n", prefix, field.name, field.value)
+		return
+	}
+	fmt.Fprintf(buf, "%s**%s:** %s\n", prefix, field.label, field.value)
+}
+
+// summaryFields returns the fields listed under a ticket's title.
+func summaryFields(ticket *domain.Ticket) []inlineField {
+	return []inlineField{
+		{name: "status", label: "Status", value: ticket.Status},
+		{name: "type", label: "Type", value: ticket.IssueType},
+		{name: "priority", label: "Priority", value: ticket.Priority},
+		{name: "assignee", label: "Assignee", value: ticket.Assignee},
+		{name: "reporter", label: "Reporter", value: ticket.Reporter},
+		{name: "labels", label: "Labels", value: strings.Join(ticket.Labels, ", ")},
+	}
+}
+
+// ticketFrontmatter returns the frontmatter of a ticket's file, on the current schema.
+// Custom fields without a value are left out. The raw values of unmapped Jira fields and
+// the profiles of the ticket's people go under their own keys after the custom fields,
+// keeping the mapped fields first.
+func ticketFrontmatter(ticket *domain.Ticket) *Frontmatter {
+	frontmatter := NewFrontmatter()
+	frontmatter.Set(SchemaKey, SchemaVersion)
+	frontmatter.Set("key", ticket.Key.String())
+	frontmatter.Set("summary", ticket.Summary)
+	frontmatter.Set("status", ticket.Status)
+	frontmatter.Set("type", ticket.IssueType)
+	frontmatter.Set("priority", ticket.Priority)
+	frontmatter.Set("assignee", ticket.Assignee)
+	frontmatter.Set("reporter", ticket.Reporter)
+	frontmatter.Set("labels", append([]string{}, ticket.Labels...))
+	frontmatter.Set("created", formatTimestamp(ticket.Created))
+	frontmatter.Set("updated", formatTimestamp(ticket.Updated))
+
+	names := make([]string, 0, len(ticket.CustomFields))
+	for name, value := range ticket.CustomFields {
+		if !value.IsZero() && name != domain.JiraFieldsField && name != domain.AuthorsField {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		fields := NewFrontmatter()
+		for _, name := range names {
+			fields.Set(name, ticket.CustomFields[name].Raw())
+		}
+		frontmatter.Set("fields", fields)
+	}
+	if raw := ticket.JiraFields(); len(raw) > 0 {
+		frontmatter.Set(domain.JiraFieldsField, raw)
+	}
+	if authors, ok := ticket.CustomFields[domain.AuthorsField]; ok && !authors.IsZero() {
+		frontmatter.Set(domain.AuthorsField, authors.Raw())
+	}
+	return frontmatter
 }
 
-// GenerateTicket generates a markdown file from a Ticket entity.
-// This is a placeholder for the actual implementation.
-func (p *Parser) GenerateTicket(ctx context.Context, ticket *domain.Ticket) ([]byte, error) {
-	// TODO: Implement markdown generation logic
-	return nil, fmt.Errorf("markdown.Parser.GenerateTicket not implemented")
+// formatTimestamp formats a ticket timestamp for a generated file, or "" if it is not set.
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
 }
@@ -0,0 +1,475 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestParser_GenerateTicket(t *testing.T) {
+	ticket := &domain.Ticket{
+		Key:         ticketKey(t, "JMD-7"),
+		Summary:     "Render inline fields",
+		Description: "Dataview needs them.\n",
+		Status:      "In Progress",
+		IssueType:   "Story",
+		Priority:    "High",
+		Assignee:    "alice@example.com",
+		Reporter:    "bob@example.com",
+		Labels:      []string{"obsidian", "markdown"},
+		Created:     time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		Updated:     time.Date(2024, 3, 2, 10, 30, 0, 0, time.UTC),
+		CustomFields: map[string]domain.FieldValue{
+			domain.FixVersionsField: domain.NewFieldValue([]string{"1.2"}),
+			"story_points":          domain.NewFieldValue(nil),
+		},
+	}
+
+	content, sidecar, err := NewParser().GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	want := `---
+jiramd_schema: 1
+key: JMD-7
+summary: Render inline fields
+status: In Progress
+type: Story
+priority: High
+assignee: alice@example.com
+reporter: bob@example.com
+labels:
+    - obsidian
+    - markdown
+created: "2024-03-01T09:00:00Z"
+updated: "2024-03-02T10:30:00Z"
+fields:
+    fix_versions:
+        - "1.2"
+---
+
+# JMD-7: Render inline fields
+
+**Status:** In Progress
+**Type:** Story
+**Priority:** High
+**Assignee:** alice@example.com
+**Reporter:** bob@example.com
+**Labels:** obsidian, markdown
+
+## Description
+
+<!-- jiramd-managed-start -->
+Dataview needs them.
+<!-- jiramd-managed-end -->
+
+<!-- jiramd-local-start -->
+<!-- jiramd-local-end -->
+
+<!-- jiramd-metadata-start -->
+## Metadata
+
+- **Created:** 2024-03-01T09:00:00Z
+- **Updated:** 2024-03-02T10:30:00Z
+<!-- jiramd-metadata-end -->
+
+---
+*This file is managed by jiramd. Do not edit the metadata section.*
+`
+	if string(content) != want || sidecar != nil {
+		t.Errorf("GenerateTicket() =\n%s\nsidecar %q, want\n%s", content, sidecar, want)
+	}
+
+	content, _, err = NewParser().WithFlavor(domain.MarkdownFlavorObsidian).GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	for _, line := range []string{
+		"status:: In Progress\ntype:: Story\n",
+		"labels:: obsidian, markdown\n",
+		"- created:: 2024-03-01T09:00:00Z\n",
+		"status: In Progress\n",
+	} {
+		if !strings.Contains(string(content), line) {
+			t.Errorf("obsidian flavor output is missing %q:\n%s", line, content)
+		}
+	}
+	if strings.Contains(string(content), "**Status:**") {
+		t.Errorf("obsidian flavor output has plain fields:\n%s", content)
+	}
+
+	berlin := time.FixedZone("CET", 60*60)
+	display := domain.DisplayConfig{Location: berlin, DateFormat: "02 Jan 2006 15:04 MST"}
+	content, _, err = NewParser().WithDisplay(display).GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	for _, line := range []string{
+		"created: \"2024-03-01T09:00:00Z\"\n",
+		"- **Updated:** 02 Mar 2024 11:30 CET\n",
+	} {
+		if !strings.Contains(string(content), line) {
+			t.Errorf("output with display settings is missing %q:\n%s", line, content)
+		}
+	}
+}
+
+func TestParser_GenerateTicket_JiraFields(t *testing.T) {
+	ticket := domain.NewTicket(ticketKey(t, "JMD-8"), "Keep every field", time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+	ticket.CustomFields[domain.FixVersionsField] = domain.NewFieldValue([]string{"1.2"})
+	ticket.CustomFields[domain.JiraFieldsField] = domain.NewFieldValue(map[string]interface{}{
+		"Team":    map[string]interface{}{"value": "Platform", "id": "10100"},
+		"duedate": "2024-04-01",
+	})
+
+	content, _, err := NewParser().GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	want := `fields:
+    fix_versions:
+        - "1.2"
+jira_fields:
+    Team:
+        id: "10100"
+        value: Platform
+    duedate: "2024-04-01"
+---
+`
+	if !strings.Contains(string(content), want) {
+		t.Errorf("GenerateTicket() =\n%s\nwant the raw fields after the mapped ones:\n%s", content, want)
+	}
+}
+
+func TestParser_GenerateTicket_Authors(t *testing.T) {
+	ticket := domain.NewTicket(ticketKey(t, "JMD-9"), "Who's who", time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+	ticket.Assignee = "alice@example.com"
+	ticket.AddAuthors(&domain.User{DisplayName: "Alice Smith", Email: "alice@example.com", AvatarURL: "https://avatars.example.com/alice.png"})
+
+	parser := NewParser()
+	content, _, err := parser.GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	want := `authors:
+    alice@example.com:
+        avatar_url: https://avatars.example.com/alice.png
+        email: alice@example.com
+        name: Alice Smith
+---
+`
+	if !strings.Contains(string(content), want) || strings.Contains(string(content), "fields:") {
+		t.Errorf("GenerateTicket() =\n%s\nwant the authors under their own key:\n%s", content, want)
+	}
+
+	parsed, err := parser.ParseTicket(context.Background(), content, nil)
+	if err != nil {
+		t.Fatalf("ParseTicket() error = %v", err)
+	}
+	if alice, ok := parsed.Author(parsed.Assignee); !ok || alice.DisplayName != "Alice Smith" || alice.AvatarURL != "https://avatars.example.com/alice.png" {
+		t.Errorf("ParseTicket() author = %+v, %v", alice, ok)
+	}
+}
+
+func TestParser_GenerateTicket_Comments(t *testing.T) {
+	key := ticketKey(t, "JMD-7")
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := &domain.Ticket{Key: key, Summary: "Commented", Created: at, Updated: at}
+	ticket.Comments = []*domain.Comment{
+		{ID: "1", TicketKey: key, Author: "alice@example.com", Body: "Looks good", Created: at},
+		{ID: "2", TicketKey: key, Author: "bob@example.com", Body: "Root cause is the cache key", Created: at.Add(time.Hour),
+			Visibility: domain.CommentVisibility{Type: domain.VisibilityRole, Value: "Developers"}},
+		{ID: "3", TicketKey: key, Author: "carol@example.com", Body: "Customer is on the legacy plan", Created: at.Add(2 * time.Hour),
+			Visibility: domain.CommentVisibility{Internal: true}},
+	}
+
+	content, _, err := NewParser().GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	want := `## Comments
+
+### alice@example.com, 2024-03-01T09:00:00Z
+
+Looks good
+
+### bob@example.com, 2024-03-01T10:00:00Z ` + "`Role: Developers`" + `
+
+Root cause is the cache key
+
+### carol@example.com, 2024-03-01T11:00:00Z ` + "`Internal note`" + `
+
+Customer is on the legacy plan
+
+<!-- jiramd-metadata-start -->`
+	if !strings.Contains(string(content), want) {
+		t.Errorf("GenerateTicket() =\n%s\nwant it to contain\n%s", content, want)
+	}
+}
+
+func TestParser_GenerateTicket_TimeInStatus(t *testing.T) {
+	key := ticketKey(t, "JMD-8")
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := &domain.Ticket{Key: key, Summary: "Moved around", Status: "In Progress", Created: at, Updated: at}
+	ticket.StatusHistory = []domain.StatusChange{
+		{From: "To Do", To: "In Progress", At: at.Add(2 * time.Hour)},
+		{From: "In Progress", To: "In Review", At: at.Add(26 * time.Hour)},
+		{From: "In Review", To: "In Progress", At: at.Add(29*time.Hour + 30*time.Minute)},
+	}
+
+	content, _, err := NewParser().GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	want := `## Time in Status
+
+| Status | Time | Visits |
+|---|---:|---:|
+| To Do | 2h | 1 |
+| **In Progress** (since 2024-03-02T14:30:00Z) | 1d | 2 |
+| In Review | 3h 30m | 1 |
+
+<!-- jiramd-metadata-start -->`
+	if !strings.Contains(string(content), want) {
+		t.Errorf("GenerateTicket() =\n%s\nwant it to contain\n%s", content, want)
+	}
+}
+
+func TestParser_RewriteTicket(t *testing.T) {
+	ticket := &domain.Ticket{Key: ticketKey(t, "JMD-7"), Summary: "Stable keys", Status: "Done"}
+	parser := NewParser().WithKeyOrder([]string{"status", "aliases"})
+
+	content, _, err := parser.GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	if !strings.HasPrefix(string(content), "---\nstatus: Done\njiramd_schema: 1\nkey: JMD-7\nsummary: Stable keys\n") {
+		t.Errorf("GenerateTicket() did not follow the key order:\n%s", content)
+	}
+
+	// A user adds keys; a rewrite keeps them, and the output is stable
+	edited := strings.Replace(string(content), "key: JMD-7\n", "key: JMD-7\nproject_notes: see wiki\naliases: [stable]\n", 1)
+	rewritten, _, err := parser.RewriteTicket(context.Background(), ticket, []byte(edited), nil)
+	if err != nil {
+		t.Fatalf("RewriteTicket() error = %v", err)
+	}
+	if !strings.HasPrefix(string(rewritten), "---\nstatus: Done\naliases: [stable]\njiramd_schema: 1\nkey: JMD-7\n") {
+		t.Errorf("RewriteTicket() did not order user keys:\n%s", rewritten)
+	}
+	if !strings.Contains(string(rewritten), "updated: \"\"\nproject_notes: see wiki\n---\n") {
+		t.Errorf("RewriteTicket() did not keep user keys after jiramd's:\n%s", rewritten)
+	}
+	again, _, err := parser.RewriteTicket(context.Background(), ticket, rewritten, nil)
+	if err != nil {
+		t.Fatalf("RewriteTicket() error = %v", err)
+	}
+	if string(again) != string(rewritten) {
+		t.Errorf("RewriteTicket() is not stable:\n%s\nthen\n%s", rewritten, again)
+	}
+
+	if _, _, err := parser.RewriteTicket(context.Background(), ticket, []byte("---\nkey: [\n---\n"), nil); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("RewriteTicket() of invalid frontmatter error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestParser_ParseTicket(t *testing.T) {
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := domain.NewTicket(ticketKey(t, "JMD-9"), "Read it back", at, at.Add(time.Hour))
+	ticket.Description = "First line\n\n## Not a section heading in Jira"
+	ticket.Status = "In Review"
+	ticket.IssueType = "Bug"
+	ticket.Assignee = "alice@example.com"
+	ticket.Labels = []string{"backend", "api"}
+	ticket.CustomFields[domain.FixVersionsField] = domain.NewFieldValue([]string{"1.2"})
+	ticket.CustomFields[domain.JiraFieldsField] = domain.NewFieldValue(map[string]interface{}{
+		"Team": map[string]interface{}{"value": "Platform"},
+	})
+
+	for _, format := range []domain.FrontmatterFormat{domain.FrontmatterYAML, domain.FrontmatterTOML, domain.FrontmatterJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			codec, err := NewFrontmatterCodec(format)
+			if err != nil {
+				t.Fatalf("NewFrontmatterCodec() error = %v", err)
+			}
+			parser := NewParser().WithFrontmatter(codec)
+			content, sidecar, err := parser.GenerateTicket(context.Background(), ticket)
+			if err != nil {
+				t.Fatalf("GenerateTicket() error = %v", err)
+			}
+
+			got, err := parser.ParseTicket(context.Background(), content, sidecar)
+			if err != nil {
+				t.Fatalf("ParseTicket() error = %v", err)
+			}
+			if changed := got.ChangedFields(ticket); len(changed) > 0 {
+				t.Errorf("ParseTicket() changed %v:\n%+v", changed, got)
+			}
+			if got.Description != ticket.Description || got.IssueType != "Bug" || !got.Created.Equal(at) {
+				t.Errorf("ParseTicket() = %q %q %v", got.Description, got.IssueType, got.Created)
+			}
+		})
+	}
+}
+
+func TestParser_WithContentLimits(t *testing.T) {
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	limits := map[string]domain.ContentLimits{
+		"OPS": {MaxDescriptionLength: 20, MaxComments: 1},
+	}
+	parser := NewParser().WithContentLimits(func(projectKey string) domain.ContentLimits { return limits[projectKey] })
+
+	summarized := domain.NewTicket(ticketKey(t, "OPS-4"), "Huge", at, at)
+	summarized.Description = "The outage started at noon and lasted for hours."
+	summarized.Comments = []*domain.Comment{
+		{ID: "1", Author: "bob", Body: "First", Created: at},
+		{ID: "2", Author: "bob", Body: "Second", Created: at.Add(time.Minute)},
+	}
+	content, sidecar, err := parser.GenerateTicket(context.Background(), summarized)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	file := string(content)
+	for _, want := range []string{
+		managedStart + "\nThe outage started\n" + truncationMarker +
+			"\n*Truncated: 30 more characters in Jira. Run `jiramd expand OPS-4` to show them.*\n" + managedEnd,
+		"*Truncated: 1 older comment in Jira. Run `jiramd expand OPS-4` to show it.*",
+	} {
+		if !strings.Contains(file, want) {
+			t.Errorf("file does not contain %q:\n%s", want, file)
+		}
+	}
+	if strings.Contains(file, "First") || !strings.Contains(file, "Second") {
+		t.Errorf("file does not hold only the latest comment:\n%s", file)
+	}
+
+	got, err := parser.ParseTicket(context.Background(), content, sidecar)
+	if err != nil {
+		t.Fatalf("ParseTicket() error = %v", err)
+	}
+	if got.Description != "The outage started" || !got.DescriptionTruncated {
+		t.Errorf("ParseTicket() description = %q truncated %v, want the start marked truncated", got.Description, got.DescriptionTruncated)
+	}
+	if changed := got.ChangedFields(summarized); len(changed) > 0 {
+		t.Errorf("ParseTicket() changed %v, want the truncated description never pushed", changed)
+	}
+
+	// Projects without limits are written in full
+	full := domain.NewTicket(ticketKey(t, "JMD-4"), "Huge", at, at)
+	full.Description = summarized.Description
+	content, _, err = parser.GenerateTicket(context.Background(), full)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	if !strings.Contains(string(content), full.Description) || strings.Contains(string(content), truncationMarker) {
+		t.Errorf("file of an unlimited project is summarized:\n%s", content)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		want     string
+		wantLeft int
+	}{
+		{name: "no limit", text: "short text", limit: 0, want: "short text"},
+		{name: "within limit", text: "short text", limit: 10, want: "short text"},
+		{name: "cut at a word", text: "one two three", limit: 9, want: "one two", wantLeft: 6},
+		{name: "cut in a long word", text: "abcdefghij", limit: 4, want: "abcd", wantLeft: 6},
+		{name: "counts characters", text: "äöü äöü", limit: 5, want: "äöü", wantLeft: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, left := truncateText(tt.text, tt.limit)
+			if got != tt.want || left != tt.wantLeft {
+				t.Errorf("truncateText() = %q, %d, want %q, %d", got, left, tt.want, tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestParser_ParseTicket_Legacy(t *testing.T) {
+	content := "---\nissue_type: Task\nkey: JMD-3\nsummary: Old file\n---\n\n# JMD-3: Old file\n\n## Description\n\nWritten before zones.\n\n## Comments\n\n### bob, 2024-03-01T09:00:00Z\n\nHi\n"
+	got, err := NewParser().ParseTicket(context.Background(), []byte(content), nil)
+	if err != nil {
+		t.Fatalf("ParseTicket() error = %v", err)
+	}
+	if got.Key.String() != "JMD-3" || got.IssueType != "Task" || got.Description != "Written before zones." {
+		t.Errorf("ParseTicket() = %s %q %q", got.Key, got.IssueType, got.Description)
+	}
+
+	labeled := "---\nkey: JMD-3\nlabels: [api, \" api\", ui, api]\n---\n"
+	got, err = NewParser().ParseTicket(context.Background(), []byte(labeled), nil)
+	if err != nil || strings.Join(got.Labels, ",") != "api,ui" {
+		t.Errorf("ParseTicket() labels = %v, %v; want [api ui] read once", got.Labels, err)
+	}
+
+	for name, content := range map[string]string{
+		"no key":            "---\nsummary: Lost\n---\n",
+		"bad timestamp":     "---\nkey: JMD-3\ncreated: yesterday\n---\n",
+		"unterminated zone": "---\nkey: JMD-3\n---\n\n## Description\n\n<!-- jiramd-local-start -->\nnotes\n",
+	} {
+		if _, err := NewParser().ParseTicket(context.Background(), []byte(content), nil); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("ParseTicket() of %s error = %v, want ErrInvalidInput", name, err)
+		}
+	}
+}
+
+func TestParser_RewriteTicket_LocalZones(t *testing.T) {
+	ticket := &domain.Ticket{Key: ticketKey(t, "JMD-7"), Summary: "Mixed ownership", Description: "From Jira."}
+	parser := NewParser()
+	content, _, err := parser.GenerateTicket(context.Background(), ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+
+	// The user writes notes in the local zone, with a heading of their own, and adds a
+	// local zone before the managed one
+	notes := "<!-- jiramd-local-start -->\n## My notes\n\n- [ ] ask about retries\n<!-- jiramd-local-end -->\n"
+	edited := strings.Replace(string(content), "<!-- jiramd-local-start -->\n<!-- jiramd-local-end -->\n", notes, 1)
+	edited = strings.Replace(edited, "<!-- jiramd-managed-start -->", "<!-- jiramd-local-start -->\nContext first\n<!-- jiramd-local-end -->\n\n<!-- jiramd-managed-start -->", 1)
+
+	ticket.Description = "Changed in Jira."
+	rewritten, _, err := parser.RewriteTicket(context.Background(), ticket, []byte(edited), nil)
+	if err != nil {
+		t.Fatalf("RewriteTicket() error = %v", err)
+	}
+	want := `## Description
+
+<!-- jiramd-local-start -->
+Context first
+<!-- jiramd-local-end -->
+
+<!-- jiramd-managed-start -->
+Changed in Jira.
+<!-- jiramd-managed-end -->
+
+<!-- jiramd-local-start -->
+## My notes
+
+- [ ] ask about retries
+<!-- jiramd-local-end -->
+
+<!-- jiramd-metadata-start -->`
+	if !strings.Contains(string(rewritten), want) {
+		t.Errorf("RewriteTicket() =\n%s\nwant it to contain\n%s", rewritten, want)
+	}
+
+	parsed, err := parser.ParseTicket(context.Background(), rewritten, nil)
+	if err != nil {
+		t.Fatalf("ParseTicket() error = %v", err)
+	}
+	if parsed.Description != "Changed in Jira." {
+		t.Errorf("ParseTicket() description = %q, want only the managed zone", parsed.Description)
+	}
+
+	broken := strings.Replace(edited, "<!-- jiramd-local-end -->\n\n<!-- jiramd-metadata-start -->", "\n<!-- jiramd-metadata-start -->", 1)
+	if _, _, err := parser.RewriteTicket(context.Background(), ticket, []byte(broken), nil); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("RewriteTicket() of an unclosed zone error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,36 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ReportWriter writes report files into a reports directory.
+type ReportWriter struct {
+	dir string
+}
+
+// NewReportWriter creates a writer for the report files in dir.
+func NewReportWriter(dir string) *ReportWriter {
+	return &ReportWriter{dir: filepath.Clean(dir)}
+}
+
+// WriteReport writes the report file name with content, unless it already has that
+// content, so reports that did not change keep their modification time. Returns whether
+// the file was written.
+func (w *ReportWriter) WriteReport(ctx context.Context, name string, content []byte) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	path := filepath.Join(w.dir, name)
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return false, nil
+	}
+	if err := writeFileAtomic(path, content, filePermOf(path)); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,33 @@
+package markdown
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestReportWriter_WriteReport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	writer := NewReportWriter(dir)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		content string
+		want    bool
+	}{
+		{content: "## Aging WIP\n", want: true},
+		{content: "## Aging WIP\n", want: false},
+		{content: "## Aging WIP (updated)\n", want: true},
+	} {
+		changed, err := writer.WriteReport(ctx, "aging-wip.md", []byte(tt.content))
+		if err != nil {
+			t.Fatalf("WriteReport() error = %v", err)
+		}
+		if changed != tt.want {
+			t.Errorf("WriteReport(%q) changed = %v, want %v", tt.content, changed, tt.want)
+		}
+		if got := readFile(t, filepath.Join(dir, "aging-wip.md")); got != tt.content {
+			t.Errorf("report = %q, want %q", got, tt.content)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// SchemaKey is the frontmatter key holding the schema version a ticket file was written with.
+const SchemaKey = "jiramd_schema"
+
+// SchemaVersion is the frontmatter schema of the ticket files jiramd writes. Changing
+// the frontmatter in a way older files do not match (renamed keys, new required keys)
+// bumps it, with a migration from the previous version.
+const SchemaVersion = 1
+
+// migration upgrades the frontmatter of a ticket file from one schema version to the next.
+type migration struct {
+	// from is the schema version migrated from, to from+1
+	from int
+
+	// migrate updates the frontmatter of the ticket file of key
+	migrate func(frontmatter *Frontmatter, key domain.TicketKey)
+}
+
+// migrations upgrade every schema version before SchemaVersion, in order.
+var migrations = []migration{
+	{
+		// Files from before schema versioning may name the issue type issue_type (as
+		// notes do) and may lack the key, which is now required
+		from: 0,
+		migrate: func(frontmatter *Frontmatter, key domain.TicketKey) {
+			renameKey(frontmatter, "issue_type", "type")
+			if value, ok := frontmatter.Get("key"); !ok || value == "" {
+				frontmatter.Set("key", key.String())
+			}
+		},
+	},
+}
+
+// renameKey renames old to new in place, keeping its value as written, unless new is
+// already set.
+func renameKey(frontmatter *Frontmatter, old, new string) {
+	value, ok := frontmatter.Get(old)
+	if !ok {
+		return
+	}
+	if _, exists := frontmatter.Get(new); exists {
+		return
+	}
+	for i, key := range frontmatter.keys {
+		if key == old {
+			frontmatter.keys[i] = new
+		}
+	}
+	delete(frontmatter.values, old)
+	frontmatter.values[new] = value
+	if nodes, ok := frontmatter.yamlNodes[old]; ok {
+		keyNode := *nodes[0]
+		keyNode.Value = new
+		frontmatter.yamlNodes[new] = [2]*yaml.Node{&keyNode, nodes[1]}
+		delete(frontmatter.yamlNodes, old)
+	}
+}
+
+// schemaVersion returns the schema version of frontmatter; files without one predate
+// versioning and are version 0.
+func schemaVersion(frontmatter *Frontmatter) (int, error) {
+	value, ok := frontmatter.Get(SchemaKey)
+	if !ok || value == nil {
+		return 0, nil
+	}
+	switch value := value.(type) {
+	case int64:
+		return int(value), nil
+	case float64:
+		if value == float64(int(value)) {
+			return int(value), nil
+		}
+	case string:
+		if version, err := strconv.Atoi(value); err == nil {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s must be a whole number, got %v", domain.ErrInvalidInput, SchemaKey, value)
+}
+
+// MigrateFrontmatter upgrades the frontmatter of the ticket file of key to
+// SchemaVersion, returning the version it had. Returns ErrInvalidInput if the file was
+// written by a newer jiramd.
+func MigrateFrontmatter(frontmatter *Frontmatter, key domain.TicketKey) (int, error) {
+	version, err := schemaVersion(frontmatter)
+	if err != nil {
+		return 0, err
+	}
+	if version > SchemaVersion {
+		return version, fmt.Errorf("%w: schema version %d is newer than this jiramd supports (%d); upgrade jiramd",
+			domain.ErrInvalidInput, version, SchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.from >= version && m.from < SchemaVersion {
+			m.migrate(frontmatter, key)
+		}
+	}
+	if version < SchemaVersion {
+		frontmatter.Set(SchemaKey, SchemaVersion)
+		frontmatter.Order([]string{SchemaKey})
+	}
+	return version, nil
+}
+
+// MigratedFile is a ticket file on an older schema.
+type MigratedFile struct {
+	Path string
+
+	// From is the schema version the file was on
+	From int
+
+	// Err is why the file could not be upgraded, or nil if it was
+	Err error
+}
+
+// FileMigrator upgrades the frontmatter of the ticket files under a markdown directory
+// to the current schema, so files written by older versions keep working.
+type FileMigrator struct {
+	markdownDir string
+	codec       FrontmatterCodec
+}
+
+// NewFileMigrator creates a migrator for the ticket files under markdownDir, whose
+// frontmatter is stored with codec.
+func NewFileMigrator(markdownDir string, codec FrontmatterCodec) *FileMigrator {
+	return &FileMigrator{markdownDir: markdownDir, codec: codec}
+}
+
+// Migrate upgrades every ticket file that is on an older schema, leaving the body and
+// the keys users added as they are, and returns those files in path order. With dryRun,
+// it only returns the files that would be upgraded.
+//
+// Files that cannot be read or upgraded, including files from a newer jiramd, are
+// returned with their error and do not stop the others.
+func (m *FileMigrator) Migrate(ctx context.Context, dryRun bool) ([]MigratedFile, error) {
+	files, err := findTicketFiles(ctx, m.markdownDir, "")
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]domain.TicketKey, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return files[keys[i]] < files[keys[j]] })
+
+	migrated := make([]MigratedFile, 0)
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return migrated, err
+		}
+		path := files[key]
+		from, changed, err := m.migrateFile(key, path, dryRun)
+		if changed || err != nil {
+			migrated = append(migrated, MigratedFile{Path: path, From: from, Err: err})
+		}
+	}
+	return migrated, nil
+}
+
+// migrateFile upgrades one ticket file, returning the version it had and whether it
+// needed upgrading.
+func (m *FileMigrator) migrateFile(key domain.TicketKey, path string, dryRun bool) (int, bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false, err
+	}
+	sidecarPath := SidecarPath(path)
+	sidecar, err := os.ReadFile(sidecarPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, false, err
+	}
+
+	frontmatter, body, err := m.codec.Decode(content, sidecar)
+	if err != nil {
+		return 0, false, err
+	}
+	from, err := MigrateFrontmatter(frontmatter, key)
+	if err != nil || from == SchemaVersion {
+		return from, false, err
+	}
+	if dryRun {
+		return from, true, nil
+	}
+
+	content, sidecar, err = m.codec.Encode(frontmatter, body)
+	if err != nil {
+		return from, false, err
+	}
+	if err := writeFileAtomic(path, content, filePermOf(path)); err != nil {
+		return from, false, err
+	}
+	if sidecar != nil {
+		if err := writeFileAtomic(sidecarPath, sidecar, filePermOf(path)); err != nil {
+			return from, false, err
+		}
+	}
+	return from, true, nil
+}
+
+// filePermOf returns the permission of an existing file, or filePerm if it cannot be read.
+func filePermOf(path string) fs.FileMode {
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode().Perm()
+	}
+	return filePerm
+}
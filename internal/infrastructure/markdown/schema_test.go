@@ -0,0 +1,93 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestMigrateFrontmatter(t *testing.T) {
+	frontmatter := NewFrontmatter()
+	frontmatter.Set("summary", "Old file")
+	frontmatter.Set("issue_type", "Bug")
+	frontmatter.Set("aliases", []string{"old"})
+
+	from, err := MigrateFrontmatter(frontmatter, ticketKey(t, "JMD-3"))
+	if err != nil {
+		t.Fatalf("MigrateFrontmatter() error = %v", err)
+	}
+	if from != 0 {
+		t.Errorf("from = %d, want 0", from)
+	}
+	if got := strings.Join(frontmatter.Keys(), ","); got != "jiramd_schema,summary,type,aliases,key" {
+		t.Errorf("Keys() = %s", got)
+	}
+	if issueType, _ := frontmatter.Get("type"); issueType != "Bug" {
+		t.Errorf("type = %v", issueType)
+	}
+	if key, _ := frontmatter.Get("key"); key != "JMD-3" {
+		t.Errorf("key = %v", key)
+	}
+
+	// Current files are left alone
+	if from, err := MigrateFrontmatter(frontmatter, ticketKey(t, "JMD-3")); err != nil || from != SchemaVersion {
+		t.Errorf("MigrateFrontmatter() of current file = %d, %v", from, err)
+	}
+
+	newer := NewFrontmatter()
+	newer.Set(SchemaKey, SchemaVersion+1)
+	if _, err := MigrateFrontmatter(newer, ticketKey(t, "JMD-3")); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("MigrateFrontmatter() of newer file error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestFileMigrator_Migrate(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "JMD", "JMD-1.md")
+	currentPath := filepath.Join(dir, "JMD", "JMD-2.md")
+	brokenPath := filepath.Join(dir, "JMD", "JMD-3.md")
+	files := map[string]string{
+		oldPath:                               "---\nissue_type: Story # from the importer\nnotes: keep me\n---\n\n# JMD-1: Old\n",
+		currentPath:                           "---\njiramd_schema: 1\nkey: JMD-2\n---\n\n# JMD-2: Current\n",
+		brokenPath:                            "---\njiramd_schema: 99\n---\n",
+		filepath.Join(dir, "JMD", "notes.md"): "# Not a ticket\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrator := NewFileMigrator(dir, yamlCodec{})
+	migrated, err := migrator.Migrate(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(migrated) != 2 ||
+		migrated[0].Path != oldPath || migrated[0].From != 0 || migrated[0].Err != nil ||
+		migrated[1].Path != brokenPath || !errors.Is(migrated[1].Err, domain.ErrInvalidInput) {
+		t.Fatalf("Migrate() dry run = %+v", migrated)
+	}
+	if got := readFile(t, oldPath); got != files[oldPath] {
+		t.Errorf("dry run changed %s:\n%s", oldPath, got)
+	}
+
+	if _, err := migrator.Migrate(context.Background(), false); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	want := "---\njiramd_schema: 1\ntype: Story # from the importer\nnotes: keep me\nkey: JMD-1\n---\n\n# JMD-1: Old\n"
+	if got := readFile(t, oldPath); got != want {
+		t.Errorf("migrated %s =\n%s\nwant\n%s", oldPath, got, want)
+	}
+	if got := readFile(t, currentPath); got != files[currentPath] {
+		t.Errorf("current file changed:\n%s", got)
+	}
+}
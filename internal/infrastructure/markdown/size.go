@@ -0,0 +1,45 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// LargeFile is a markdown file larger than a size limit.
+type LargeFile struct {
+	Path string
+	Size int64
+}
+
+// FindLargeFiles returns the markdown files under dir larger than limit bytes, in path
+// order. A missing dir has none.
+func FindLargeFiles(dir string, limit int64) ([]LargeFile, error) {
+	var large []LargeFile
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > limit {
+			large = append(large, LargeFile{Path: path, Size: info.Size()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	return large, nil
+}
@@ -0,0 +1,44 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindLargeFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]int{
+		"PROJ-1.md":           10,
+		"PROJ/PROJ-2.md":      200,
+		"archive/PROJ-3.md":   300,
+		"attachments/big.png": 1000,
+	}
+	for name, size := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	large, err := FindLargeFiles(dir, 100)
+	if err != nil {
+		t.Fatalf("FindLargeFiles() error = %v", err)
+	}
+	want := []LargeFile{
+		{Path: filepath.Join(dir, "PROJ", "PROJ-2.md"), Size: 200},
+		{Path: filepath.Join(dir, "archive", "PROJ-3.md"), Size: 300},
+	}
+	if !reflect.DeepEqual(large, want) {
+		t.Errorf("FindLargeFiles() = %v, want %v", large, want)
+	}
+
+	if large, err := FindLargeFiles(filepath.Join(dir, "missing"), 100); err != nil || large != nil {
+		t.Errorf("FindLargeFiles(missing) = %v, %v; want nil, nil", large, err)
+	}
+}
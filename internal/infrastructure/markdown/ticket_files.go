@@ -0,0 +1,157 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// TicketFiles locates and writes the markdown files of single tickets, for commands that
+// work on one ticket at a time. Paths are recorded relative to the markdown directory,
+// with forward slashes, so they stay valid when the directory is moved.
+type TicketFiles struct {
+	markdownDir string
+	parser      *Parser
+}
+
+// NewTicketFiles creates a TicketFiles for the ticket files under markdownDir, generated
+// with parser.
+func NewTicketFiles(markdownDir string, parser *Parser) *TicketFiles {
+	return &TicketFiles{
+		markdownDir: filepath.Clean(markdownDir),
+		parser:      parser,
+	}
+}
+
+// Locate returns the path of a ticket's file: the recorded path (relative to the
+// markdown directory) while the file is still there, otherwise the first <KEY>.md found
+// under the markdown directory, e.g. after the file was moved. Returns "" if the ticket
+// has no file.
+func (f *TicketFiles) Locate(ctx context.Context, key domain.TicketKey, recorded string) (string, error) {
+	if recorded != "" {
+		path := filepath.Join(f.markdownDir, filepath.FromSlash(recorded))
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to check %s: %w", path, err)
+		}
+	}
+	return findFile(ctx, f.markdownDir, "", key.String()+".md")
+}
+
+// Rel returns the path to record for the ticket file at path, relative to the markdown
+// directory with forward slashes.
+func (f *TicketFiles) Rel(path string) (string, error) {
+	rel, err := filepath.Rel(f.markdownDir, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// parserAt returns the parser generating the file at the recorded path, with its
+// attachment links relative to the file's directory.
+func (f *TicketFiles) parserAt(recorded string) *Parser {
+	depth := strings.Count(path.Clean(recorded), "/")
+	if f.parser.attachmentDir == "" || depth == 0 {
+		return f.parser
+	}
+	parser := *f.parser
+	parser.attachmentDir = strings.Repeat("../", depth) + parser.attachmentDir
+	return &parser
+}
+
+// Stage stages writing ticket's file at the recorded path (relative to the markdown
+// directory), with its frontmatter sidecar when the format has one. An existing file is
+// rewritten, keeping the notes users added to it; otherwise a new file is generated.
+func (f *TicketFiles) Stage(ctx context.Context, uow repository.UnitOfWork, ticket *domain.Ticket, recorded string) error {
+	path := filepath.Join(f.markdownDir, filepath.FromSlash(recorded))
+
+	existing, err := readOptional(path)
+	if err != nil {
+		return err
+	}
+	var content, sidecar []byte
+	if existing == nil {
+		content, sidecar, err = f.parserAt(recorded).GenerateTicket(ctx, ticket)
+	} else {
+		var existingSidecar []byte
+		if existingSidecar, err = readOptional(SidecarPath(path)); err != nil {
+			return err
+		}
+		content, sidecar, err = f.parserAt(recorded).RewriteTicket(ctx, ticket, existing, existingSidecar)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate %s: %w", ticket.Key, err)
+	}
+
+	uow.WriteFile(path, content)
+	if sidecar != nil {
+		uow.WriteFile(SidecarPath(path), sidecar)
+	}
+	return nil
+}
+
+// StageStreamed stages writing ticket's file like Stage, with the comments each streams
+// instead of ticket.Comments (see Parser.WriteTicket), so tickets with thousands of
+// comments are written without holding them all.
+func (f *TicketFiles) StageStreamed(ctx context.Context, uow repository.UnitOfWork, ticket *domain.Ticket, recorded string, each func(fn func(comment *domain.Comment) error) error) error {
+	path := filepath.Join(f.markdownDir, filepath.FromSlash(recorded))
+
+	existing, err := readOptional(path)
+	if err != nil {
+		return err
+	}
+	var (
+		content bytes.Buffer
+		sidecar []byte
+	)
+	if existing == nil {
+		sidecar, err = f.parserAt(recorded).WriteTicket(ctx, &content, ticket, streamedComments(each))
+	} else {
+		var existingSidecar []byte
+		if existingSidecar, err = readOptional(SidecarPath(path)); err != nil {
+			return err
+		}
+		sidecar, err = f.parserAt(recorded).RewriteTicketTo(ctx, &content, ticket, existing, existingSidecar, streamedComments(each))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate %s: %w", ticket.Key, err)
+	}
+
+	uow.WriteFile(path, content.Bytes())
+	if sidecar != nil {
+		uow.WriteFile(SidecarPath(path), sidecar)
+	}
+	return nil
+}
+
+// streamedComments is a CommentSource for the ticket whose comments it streams.
+type streamedComments func(fn func(comment *domain.Comment) error) error
+
+func (s streamedComments) ForEachComment(ctx context.Context, ticketKey string, fn func(comment *domain.Comment) error) error {
+	return s(fn)
+}
+
+// readOptional returns the content of the file at path, or nil if there is none.
+func readOptional(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return content, nil
+}
@@ -0,0 +1,133 @@
+package markdown
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestTicketFiles_Locate(t *testing.T) {
+	dir := t.TempDir()
+	key := ticketKey(t, "JMD-1")
+	moved := filepath.Join(dir, "Sprint 4", "JMD-1.md")
+	if err := os.MkdirAll(filepath.Dir(moved), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(moved, []byte("# JMD-1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := NewTicketFiles(dir, NewParser())
+	ctx := context.Background()
+
+	for _, recorded := range []string{"Sprint 4/JMD-1.md", "JMD-1.md", ""} {
+		if got, err := files.Locate(ctx, key, recorded); err != nil || got != moved {
+			t.Errorf("Locate(%q) = %q, %v; want %s", recorded, got, err, moved)
+		}
+	}
+	if got, err := files.Locate(ctx, ticketKey(t, "JMD-2"), "JMD-2.md"); err != nil || got != "" {
+		t.Errorf("Locate(missing) = %q, %v; want none", got, err)
+	}
+}
+
+func TestTicketFiles_Stage(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	ticket := domain.NewTicket(ticketKey(t, "OPS-1"), "Pulled on demand", at, at)
+
+	files := NewTicketFiles(dir, NewParser().WithFrontmatter(jsonSidecarCodec{}))
+	ctx := context.Background()
+	stage := func() {
+		t.Helper()
+		uow := NewUnitOfWork(&txStateRepository{}, nil)
+		if err := files.Stage(ctx, uow, ticket, "adhoc/OPS-1.md"); err != nil {
+			t.Fatalf("Stage() error = %v", err)
+		}
+		if err := uow.Commit(ctx); err != nil {
+			t.Fatalf("Commit() error = %v", err)
+		}
+	}
+
+	stage()
+	path := filepath.Join(dir, "adhoc", "OPS-1.md")
+	if content := readFile(t, path); !strings.Contains(content, "Pulled on demand") {
+		t.Errorf("ticket file = %q, want the summary", content)
+	}
+	sidecarPath := filepath.Join(dir, "adhoc", "OPS-1.json")
+	sidecar := readFile(t, sidecarPath)
+	if !strings.Contains(sidecar, `"key"`) {
+		t.Errorf("sidecar = %q, want the frontmatter", sidecar)
+	}
+	if rel, err := files.Rel(path); err != nil || rel != "adhoc/OPS-1.md" {
+		t.Errorf("Rel() = %q, %v; want adhoc/OPS-1.md", rel, err)
+	}
+
+	// Pulling again rewrites the file, keeping the keys the user added
+	edited := strings.Replace(sidecar, `"key"`, `"my_notes": "ask ops","key"`, 1)
+	if err := os.WriteFile(sidecarPath, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ticket.Summary = "Renamed in Jira"
+	stage()
+	if content := readFile(t, path); !strings.Contains(content, "Renamed in Jira") {
+		t.Errorf("ticket file = %q, want the new summary", content)
+	}
+	if sidecar := readFile(t, sidecarPath); !strings.Contains(sidecar, "ask ops") {
+		t.Errorf("sidecar = %q, want the user's key kept", sidecar)
+	}
+}
+
+func TestTicketFiles_StageStreamed(t *testing.T) {
+	dir := t.TempDir()
+	parser := NewParser().WithCommentLimit(2)
+	files := NewTicketFiles(dir, parser)
+	ticket := commentedTicket(t, 3)
+	ctx := context.Background()
+	stage := func(ticket *domain.Ticket) {
+		t.Helper()
+		uow := NewUnitOfWork(&txStateRepository{}, nil)
+		each := commentList{comments: ticket.Comments}
+		streamed := *ticket
+		streamed.Comments = nil
+		err := files.StageStreamed(ctx, uow, &streamed, "JMD-1.md", func(fn func(comment *domain.Comment) error) error {
+			return each.ForEachComment(ctx, "JMD-1", fn)
+		})
+		if err != nil {
+			t.Fatalf("StageStreamed() error = %v", err)
+		}
+		if err := uow.Commit(ctx); err != nil {
+			t.Fatalf("Commit() error = %v", err)
+		}
+	}
+
+	// A new file is the file generated with every comment loaded
+	stage(ticket)
+	path := filepath.Join(dir, "JMD-1.md")
+	want, _, err := parser.GenerateTicket(ctx, ticket)
+	if err != nil {
+		t.Fatalf("GenerateTicket() error = %v", err)
+	}
+	if got := readFile(t, path); got != string(want) {
+		t.Errorf("new file =\n%s\nwant\n%s", got, want)
+	}
+
+	// An existing file is rewritten keeping the user's notes
+	edited := strings.Replace(string(want), localStart+"\n", localStart+"\nAsk ops first\n", 1)
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ticket = commentedTicket(t, 4)
+	want, _, err = parser.RewriteTicket(ctx, ticket, []byte(edited), nil)
+	if err != nil {
+		t.Fatalf("RewriteTicket() error = %v", err)
+	}
+	stage(ticket)
+	if got := readFile(t, path); got != string(want) || !strings.Contains(got, "Ask ops first") {
+		t.Errorf("rewritten file =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,546 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// bareKeyPattern matches TOML keys that need no quotes.
+var bareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlCodec stores frontmatter as a "+++" delimited TOML block, as Hugo and Zola expect.
+//
+// It reads the TOML it writes and what is commonly written by hand: key/value pairs,
+// tables, dotted keys, strings, numbers, booleans, arrays, and inline tables. Dates
+// are kept as written; multi-line strings and arrays of tables are not supported.
+type tomlCodec struct{}
+
+// Encode implements FrontmatterCodec. Nested frontmatter is written as tables after the
+// other keys, as TOML requires; nil values are left out, since TOML has no null.
+func (tomlCodec) Encode(frontmatter *Frontmatter, body []byte) ([]byte, []byte, error) {
+	var buf bytes.Buffer
+	if err := writeTOMLTable(&buf, nil, frontmatter); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	return joinFrontmatter("+++\n", buf.Bytes(), "+++\n", body), nil, nil
+}
+
+// Decode implements FrontmatterCodec.
+func (tomlCodec) Decode(content, sidecar []byte) (*Frontmatter, []byte, error) {
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(content, []byte("+++\n")) {
+		return NewFrontmatter(), content, nil
+	}
+
+	rest := content[len("+++\n"):]
+	var block, body []byte
+	switch end := bytes.Index(rest, []byte("\n+++")); {
+	case bytes.HasPrefix(rest, []byte("+++")):
+		body = rest[len("+++"):]
+	case end >= 0:
+		block, body = rest[:end+1], rest[end+len("\n+++"):]
+	default:
+		return nil, nil, fmt.Errorf("%w: frontmatter is not closed by +++", domain.ErrInvalidInput)
+	}
+	body = trimSeparator(bytes.TrimPrefix(body, []byte("\n")))
+
+	parser := &tomlParser{src: string(block), line: 1}
+	frontmatter, err := parser.parse()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid frontmatter: line %d: %v", domain.ErrInvalidInput, parser.line, err)
+	}
+	return frontmatter, body, nil
+}
+
+// writeTOMLTable writes the keys of a table named by path (nil for the root), followed
+// by its nested tables.
+func writeTOMLTable(buf *bytes.Buffer, path []string, table *Frontmatter) error {
+	var nested []string
+	for _, key := range table.keys {
+		value := table.values[key]
+		switch value.(type) {
+		case nil:
+			continue
+		case *Frontmatter:
+			nested = append(nested, key)
+			continue
+		}
+		buf.WriteString(tomlKey(key) + " = ")
+		if err := writeTOMLValue(buf, value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		buf.WriteByte('\n')
+	}
+
+	for _, key := range nested {
+		tablePath := append(append([]string(nil), path...), key)
+		keys := make([]string, 0, len(tablePath))
+		for _, part := range tablePath {
+			keys = append(keys, tomlKey(part))
+		}
+		fmt.Fprintf(buf, "\n[%s]\n", strings.Join(keys, "."))
+		if err := writeTOMLTable(buf, tablePath, table.values[key].(*Frontmatter)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTOMLValue writes a value inline. Nested frontmatter in lists is written as
+// inline tables.
+func writeTOMLValue(buf *bytes.Buffer, value interface{}) error {
+	switch value := value.(type) {
+	case string:
+		buf.WriteString(quoteTOML(value))
+	case bool:
+		buf.WriteString(strconv.FormatBool(value))
+	case int64:
+		buf.WriteString(strconv.FormatInt(value, 10))
+	case float64:
+		buf.WriteString(formatTOMLFloat(value))
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if item == nil {
+				return fmt.Errorf("lists cannot hold null values in TOML")
+			}
+			if err := writeTOMLValue(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case *Frontmatter:
+		buf.WriteByte('{')
+		first := true
+		for _, key := range value.keys {
+			if value.values[key] == nil {
+				continue
+			}
+			if !first {
+				buf.WriteString(", ")
+			}
+			first = false
+			buf.WriteString(tomlKey(key) + " = ")
+			if err := writeTOMLValue(buf, value.values[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported value %v", value)
+	}
+	return nil
+}
+
+// tomlKey returns key bare if it can be, and quoted otherwise.
+func tomlKey(key string) string {
+	if bareKeyPattern.MatchString(key) {
+		return key
+	}
+	return quoteTOML(key)
+}
+
+// quoteTOML returns s as a TOML basic string.
+func quoteTOML(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// formatTOMLFloat formats f so it reads back as a float rather than an integer.
+func formatTOMLFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// tomlParser parses the supported subset of TOML into frontmatter.
+type tomlParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+// parse parses the whole document.
+func (p *tomlParser) parse() (*Frontmatter, error) {
+	root := NewFrontmatter()
+	table := root
+	for {
+		p.skipSpace(true)
+		if p.atEnd() {
+			return root, nil
+		}
+
+		if p.peek() == '[' {
+			p.pos++
+			if p.peek() == '[' {
+				return nil, fmt.Errorf("arrays of tables are not supported")
+			}
+			path, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace(false)
+			if p.peek() != ']' {
+				return nil, fmt.Errorf("expected ] after table name")
+			}
+			p.pos++
+			if table, err = subTable(root, path); err != nil {
+				return nil, err
+			}
+		} else {
+			path, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace(false)
+			if p.peek() != '=' {
+				return nil, fmt.Errorf("expected = after key %s", strings.Join(path, "."))
+			}
+			p.pos++
+			p.skipSpace(false)
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			parent, err := subTable(table, path[:len(path)-1])
+			if err != nil {
+				return nil, err
+			}
+			parent.Set(path[len(path)-1], value)
+		}
+
+		p.skipSpace(false)
+		if !p.atEnd() && p.peek() != '\n' {
+			return nil, fmt.Errorf("unexpected %q at end of line", p.peek())
+		}
+	}
+}
+
+// subTable returns the table at path under table, creating missing tables.
+func subTable(table *Frontmatter, path []string) (*Frontmatter, error) {
+	for _, key := range path {
+		value, ok := table.Get(key)
+		if !ok {
+			nested := NewFrontmatter()
+			table.Set(key, nested)
+			table = nested
+			continue
+		}
+		nested, ok := value.(*Frontmatter)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a table", key)
+		}
+		table = nested
+	}
+	return table, nil
+}
+
+// parseKey parses a possibly dotted key.
+func (p *tomlParser) parseKey() ([]string, error) {
+	var path []string
+	for {
+		p.skipSpace(false)
+		var part string
+		switch p.peek() {
+		case '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			part = s
+		case '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			part = s
+		default:
+			start := p.pos
+			for !p.atEnd() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, fmt.Errorf("expected a key")
+			}
+			part = p.src[start:p.pos]
+		}
+		path = append(path, part)
+
+		p.skipSpace(false)
+		if p.peek() != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+// parseValue parses the value at the current position.
+func (p *tomlParser) parseValue() (interface{}, error) {
+	switch p.peek() {
+	case '"':
+		if strings.HasPrefix(p.src[p.pos:], `"""`) {
+			return nil, fmt.Errorf("multi-line strings are not supported")
+		}
+		return p.parseBasicString()
+	case '\'':
+		if strings.HasPrefix(p.src[p.pos:], `'''`) {
+			return nil, fmt.Errorf("multi-line strings are not supported")
+		}
+		return p.parseLiteralString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	}
+
+	start := p.pos
+	for !p.atEnd() && !strings.ContainsRune(",]}#\n", rune(p.peek())) {
+		p.pos++
+	}
+	token := strings.TrimSpace(p.src[start:p.pos])
+	// A date-time may have a space between date and time; keep it with its date
+	p.pos = start + len(strings.TrimRight(p.src[start:p.pos], " \t"))
+
+	switch token {
+	case "":
+		return nil, fmt.Errorf("expected a value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+	number := strings.ReplaceAll(token, "_", "")
+	if n, err := strconv.ParseInt(number, 0, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, nil
+	}
+	if token[0] >= '0' && token[0] <= '9' {
+		// Dates and times are kept as written
+		return token, nil
+	}
+	return nil, fmt.Errorf("invalid value %q", token)
+}
+
+// parseArray parses an array, which may span lines.
+func (p *tomlParser) parseArray() ([]interface{}, error) {
+	p.pos++ // [
+	list := make([]interface{}, 0)
+	for {
+		p.skipSpace(true)
+		if p.peek() == ']' {
+			p.pos++
+			return list, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+
+		p.skipSpace(true)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, fmt.Errorf("expected , or ] in array")
+		}
+	}
+}
+
+// parseInlineTable parses an inline table, which must be on one line.
+func (p *tomlParser) parseInlineTable() (*Frontmatter, error) {
+	p.pos++ // {
+	table := NewFrontmatter()
+	p.skipSpace(false)
+	if p.peek() == '}' {
+		p.pos++
+		return table, nil
+	}
+	for {
+		path, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace(false)
+		if p.peek() != '=' {
+			return nil, fmt.Errorf("expected = in inline table")
+		}
+		p.pos++
+		p.skipSpace(false)
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		parent, err := subTable(table, path[:len(path)-1])
+		if err != nil {
+			return nil, err
+		}
+		parent.Set(path[len(path)-1], value)
+
+		p.skipSpace(false)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return table, nil
+		default:
+			return nil, fmt.Errorf("expected , or } in inline table")
+		}
+	}
+}
+
+// parseBasicString parses a double-quoted string with escapes.
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++ // "
+	var b strings.Builder
+	for {
+		if p.atEnd() || p.peek() == '\n' {
+			return "", fmt.Errorf("unterminated string")
+		}
+		c := p.peek()
+		p.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.atEnd() {
+				return "", fmt.Errorf("unterminated string")
+			}
+			escape := p.peek()
+			p.pos++
+			switch escape {
+			case 'b':
+				b.WriteByte('\b')
+			case 't':
+				b.WriteByte('\t')
+			case 'n':
+				b.WriteByte('\n')
+			case 'f':
+				b.WriteByte('\f')
+			case 'r':
+				b.WriteByte('\r')
+			case '"', '\\':
+				b.WriteByte(escape)
+			case 'u', 'U':
+				size := 4
+				if escape == 'U' {
+					size = 8
+				}
+				if p.pos+size > len(p.src) {
+					return "", fmt.Errorf("invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+				if err != nil || !utf8.ValidRune(rune(code)) {
+					return "", fmt.Errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(code))
+				p.pos += size
+			default:
+				return "", fmt.Errorf("invalid escape \\%c", escape)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+// parseLiteralString parses a single-quoted string, which has no escapes.
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++ // '
+	end := strings.IndexAny(p.src[p.pos:], "'\n")
+	if end < 0 || p.src[p.pos+end] != '\'' {
+		return "", fmt.Errorf("unterminated string")
+	}
+	s := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+// skipSpace skips spaces, tabs, and comments, and newlines too if newlines is set.
+func (p *tomlParser) skipSpace(newlines bool) {
+	for !p.atEnd() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && newlines:
+			p.pos++
+			p.line++
+		case c == '#':
+			for !p.atEnd() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the byte at the current position, or 0 at the end.
+func (p *tomlParser) peek() byte {
+	if p.atEnd() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// atEnd returns true if the whole document was read.
+func (p *tomlParser) atEnd() bool {
+	return p.pos >= len(p.src)
+}
+
+// isBareKeyChar returns true if c may appear in a bare key.
+func isBareKeyChar(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
@@ -0,0 +1,227 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// filePerm is the permission of ticket files created by a UnitOfWork.
+const filePerm = 0644
+
+// fileChange is one staged file write (content != nil) or removal.
+type fileChange struct {
+	path    string
+	content []byte
+}
+
+// fileBackup is the content of a file before a UnitOfWork changed it.
+type fileBackup struct {
+	path    string
+	existed bool
+	content []byte
+	mode    fs.FileMode
+}
+
+// UnitOfWork implements repository.UnitOfWork for markdown files on the local file system.
+//
+// Commit runs the staged state changes in a transaction first, then writes the files
+// (each atomically, through a temporary file renamed into place), then commits the
+// transaction. The original content of every file is kept in memory until the commit
+// succeeds, so if writing a file or committing the transaction fails the files are
+// put back the way they were.
+type UnitOfWork struct {
+	stateRepo repository.StateRepository
+	logger    *slog.Logger
+
+	files  []fileChange
+	states []func(ctx context.Context) error
+}
+
+// NewUnitOfWork creates an empty unit of work whose state changes run in a stateRepo transaction.
+func NewUnitOfWork(stateRepo repository.StateRepository, logger *slog.Logger) *UnitOfWork {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &UnitOfWork{
+		stateRepo: stateRepo,
+		logger:    logger,
+	}
+}
+
+// Verify that UnitOfWork implements the repository.UnitOfWork interface
+var _ repository.UnitOfWork = (*UnitOfWork)(nil)
+
+// WriteFile stages replacing the file at path with content.
+// Implements repository.UnitOfWork.WriteFile.
+func (u *UnitOfWork) WriteFile(path string, content []byte) {
+	if content == nil {
+		content = []byte{}
+	}
+	u.files = append(u.files, fileChange{path: path, content: content})
+}
+
+// RemoveFile stages removing the file at path.
+// Implements repository.UnitOfWork.RemoveFile.
+func (u *UnitOfWork) RemoveFile(path string) {
+	u.files = append(u.files, fileChange{path: path})
+}
+
+// Stage stages a state change.
+// Implements repository.UnitOfWork.Stage.
+func (u *UnitOfWork) Stage(fn func(ctx context.Context) error) {
+	u.states = append(u.states, fn)
+}
+
+// Commit applies the staged state changes and files together.
+// Implements repository.UnitOfWork.Commit.
+func (u *UnitOfWork) Commit(ctx context.Context) (err error) {
+	txCtx, err := u.stateRepo.BeginTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin state transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if rbErr := u.stateRepo.Rollback(txCtx); rbErr != nil {
+				err = errors.Join(err, rbErr)
+			}
+		}
+	}()
+
+	for _, fn := range u.states {
+		if err := fn(txCtx); err != nil {
+			return err
+		}
+	}
+
+	backups, err := u.applyFiles()
+	if err != nil {
+		return errors.Join(err, u.restore(backups))
+	}
+
+	if err := u.stateRepo.Commit(txCtx); err != nil {
+		// The transaction is finished either way; only the files need undoing
+		committed = true
+		return errors.Join(fmt.Errorf("failed to commit state: %w", err), u.restore(backups))
+	}
+	committed = true
+
+	u.logger.Debug("committed unit of work", "files", len(u.files), "state_changes", len(u.states))
+	return nil
+}
+
+// applyFiles writes the staged files in order and returns a backup of each file it
+// changed, including when it fails part-way.
+func (u *UnitOfWork) applyFiles() ([]fileBackup, error) {
+	backups := make([]fileBackup, 0, len(u.files))
+	for _, change := range u.files {
+		backup, err := backupFile(change.path)
+		if err != nil {
+			return backups, err
+		}
+
+		if change.content == nil {
+			err = os.Remove(change.path)
+			if errors.Is(err, fs.ErrNotExist) {
+				err = nil
+			}
+		} else {
+			err = writeFileAtomic(change.path, change.content, filePerm)
+		}
+		if err != nil {
+			return backups, fmt.Errorf("failed to update %s: %w", change.path, err)
+		}
+
+		backups = append(backups, backup)
+	}
+	return backups, nil
+}
+
+// restore undoes file changes, newest first, so a file changed twice ends up with its
+// original content.
+func (u *UnitOfWork) restore(backups []fileBackup) error {
+	var errs []error
+	for i := len(backups) - 1; i >= 0; i-- {
+		backup := backups[i]
+
+		var err error
+		if backup.existed {
+			err = writeFileAtomic(backup.path, backup.content, backup.mode)
+		} else {
+			err = os.Remove(backup.path)
+			if errors.Is(err, fs.ErrNotExist) {
+				err = nil
+			}
+		}
+		if err != nil {
+			u.logger.Error("failed to restore file after failed unit of work",
+				"path", backup.path,
+				"error", err)
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", backup.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backupFile reads the current content of path, if it exists.
+func backupFile(path string) (fileBackup, error) {
+	backup := fileBackup{path: path}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return backup, nil
+	}
+	if err != nil {
+		return backup, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return backup, fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	backup.existed = true
+	backup.content = content
+	backup.mode = info.Mode().Perm()
+	return backup, nil
+}
+
+// writeFileAtomic replaces path with content by writing a temporary file in the same
+// directory and renaming it into place, so readers (and editors) never see a partial file.
+func writeFileAtomic(path string, content []byte, perm fs.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once renamed
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
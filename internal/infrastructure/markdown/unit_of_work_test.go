@@ -0,0 +1,171 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// txStateRepository is a StateRepository that only records transaction outcomes.
+// Methods other than the transaction methods are not used by UnitOfWork.
+type txStateRepository struct {
+	repository.StateRepository
+
+	commitErr  error
+	committed  bool
+	rolledBack bool
+}
+
+func (r *txStateRepository) BeginTransaction(ctx context.Context) (context.Context, error) {
+	return ctx, nil
+}
+
+func (r *txStateRepository) Commit(ctx context.Context) error {
+	if r.commitErr != nil {
+		return r.commitErr
+	}
+	r.committed = true
+	return nil
+}
+
+func (r *txStateRepository) Rollback(ctx context.Context) error {
+	r.rolledBack = true
+	return nil
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(content)
+}
+
+func TestUnitOfWork_Commit(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "JMD-1.md")
+	removed := filepath.Join(dir, "JMD-2.md")
+	created := filepath.Join(dir, "sub", "JMD-3.md")
+	for _, path := range []string{existing, removed} {
+		if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	stateRepo := &txStateRepository{}
+	uow := NewUnitOfWork(stateRepo, nil)
+
+	staged := 0
+	uow.Stage(func(ctx context.Context) error {
+		staged++
+		return nil
+	})
+	uow.WriteFile(existing, []byte("new"))
+	uow.RemoveFile(removed)
+	uow.WriteFile(created, []byte("created"))
+
+	// Nothing happens before Commit
+	if staged != 0 || readFile(t, existing) != "old" {
+		t.Fatal("unit of work applied changes before Commit")
+	}
+
+	if err := uow.Commit(context.Background()); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	if staged != 1 || !stateRepo.committed || stateRepo.rolledBack {
+		t.Errorf("staged = %d, committed = %v, rolled back = %v", staged, stateRepo.committed, stateRepo.rolledBack)
+	}
+	if got := readFile(t, existing); got != "new" {
+		t.Errorf("%s = %q, want new", existing, got)
+	}
+	if got := readFile(t, created); got != "created" {
+		t.Errorf("%s = %q, want created", created, got)
+	}
+	if _, err := os.Stat(removed); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("%s still exists", removed)
+	}
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("directory has %d entries, want JMD-1.md and sub", len(entries))
+	}
+}
+
+func TestUnitOfWork_StateFailureLeavesFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "JMD-1.md")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	stateRepo := &txStateRepository{}
+	uow := NewUnitOfWork(stateRepo, nil)
+
+	stateErr := errors.New("state unavailable")
+	uow.WriteFile(path, []byte("new"))
+	uow.Stage(func(ctx context.Context) error { return stateErr })
+
+	if err := uow.Commit(context.Background()); !errors.Is(err, stateErr) {
+		t.Fatalf("Commit error = %v, want %v", err, stateErr)
+	}
+	if !stateRepo.rolledBack || stateRepo.committed {
+		t.Errorf("committed = %v, rolled back = %v, want rollback", stateRepo.committed, stateRepo.rolledBack)
+	}
+	if got := readFile(t, path); got != "old" {
+		t.Errorf("file = %q, want it untouched", got)
+	}
+}
+
+func TestUnitOfWork_CommitFailureRestoresFiles(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "JMD-1.md")
+	removed := filepath.Join(dir, "JMD-2.md")
+	created := filepath.Join(dir, "JMD-3.md")
+	if err := os.WriteFile(existing, []byte("old"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(removed, []byte("removed"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	commitErr := errors.New("database is locked")
+	uow := NewUnitOfWork(&txStateRepository{commitErr: commitErr}, nil)
+
+	// Writing the same file twice must still restore the original content
+	uow.WriteFile(existing, []byte("first"))
+	uow.WriteFile(existing, []byte("second"))
+	uow.RemoveFile(removed)
+	uow.WriteFile(created, []byte("created"))
+
+	if err := uow.Commit(context.Background()); !errors.Is(err, commitErr) {
+		t.Fatalf("Commit error = %v, want %v", err, commitErr)
+	}
+
+	if got := readFile(t, existing); got != "old" {
+		t.Errorf("%s = %q, want restored content", existing, got)
+	}
+	info, err := os.Stat(existing)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("restored mode = %v, want 0600", info.Mode().Perm())
+	}
+	if got := readFile(t, removed); got != "removed" {
+		t.Errorf("%s = %q, want restored content", removed, got)
+	}
+	if _, err := os.Stat(created); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("%s was not removed on rollback", created)
+	}
+}
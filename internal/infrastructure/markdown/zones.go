@@ -0,0 +1,178 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// descriptionHeading opens the description section of a ticket file.
+const descriptionHeading = "## Description"
+
+// Markers around the zones of the description section. The managed zone holds the
+// ticket's description and is synced with Jira; local zones hold notes that never
+// leave the machine and are kept as they are when the file is rewritten.
+const (
+	managedStart = "<!-- jiramd-managed-start -->"
+	managedEnd   = "<!-- jiramd-managed-end -->"
+	localStart   = "<!-- jiramd-local-start -->"
+	localEnd     = "<!-- jiramd-local-end -->"
+)
+
+// truncationMarker ends a description cut short by a content limit, inside the managed
+// zone and before the note saying so: the description is read back up to it, and marked
+// truncated so it is never pushed over the whole description in Jira.
+const truncationMarker = "<!-- jiramd-truncated -->"
+
+// descriptionSection locates the description section in the body of a ticket file, as
+// byte offsets into the body.
+type descriptionSection struct {
+	// found is false if the body has no description heading
+	found bool
+
+	// start and end bound the section, from after its heading to the next section
+	start, end int
+
+	// managed is true if the section has a managed zone, whose content is bounded by
+	// managedFrom and managedTo; files written before zones have none
+	managed                bool
+	managedFrom, managedTo int
+}
+
+// findDescription locates the description section of body. The section ends at the next
+// "## " heading or the metadata section outside any zone, so local notes may have
+// headings of their own. Only the first managed zone counts; later ones are left alone
+// like local zones.
+// Returns ErrInvalidInput if a zone is never closed, rather than guessing where it ends.
+func findDescription(body []byte) (descriptionSection, error) {
+	var section descriptionSection
+	var opened, closing string
+	zoneFrom := 0
+
+	for offset := 0; offset < len(body); {
+		next := len(body)
+		if i := bytes.IndexByte(body[offset:], '\n'); i >= 0 {
+			next = offset + i + 1
+		}
+		line := strings.TrimSpace(string(body[offset:next]))
+
+		switch {
+		case !section.found:
+			if line == descriptionHeading {
+				section.found = true
+				section.start = next
+			}
+		case closing != "":
+			if line == closing {
+				if closing == managedEnd && !section.managed {
+					section.managed = true
+					section.managedFrom, section.managedTo = zoneFrom, offset
+				}
+				closing = ""
+			}
+		case line == managedStart:
+			opened, closing, zoneFrom = line, managedEnd, next
+		case line == localStart:
+			opened, closing = line, localEnd
+		case strings.HasPrefix(line, "## ") || line == metadataStart:
+			section.end = offset
+			return section, nil
+		}
+		offset = next
+	}
+
+	if closing != "" {
+		return section, fmt.Errorf("%w: %s in the description is never closed with %s", domain.ErrInvalidInput, opened, closing)
+	}
+	section.end = len(body)
+	return section, nil
+}
+
+// readDescription returns the description held by the body of a ticket file: the content
+// of the managed zone, or the whole description section of files written before zones.
+// Returns "" if the body has no description section.
+func readDescription(body []byte) (string, error) {
+	section, err := findDescription(body)
+	if err != nil || !section.found {
+		return "", err
+	}
+	if section.managed {
+		return strings.TrimSpace(string(body[section.managedFrom:section.managedTo])), nil
+	}
+	return strings.TrimSpace(string(body[section.start:section.end])), nil
+}
+
+// keepLocalZones returns generated, a freshly generated body, with the description
+// section of existing, the body it replaces: everything the user wrote around the
+// managed zone is kept, and the managed zone gets the generated description. Files
+// written before zones have nothing to keep and are replaced by generated.
+// Returns ErrInvalidInput if a zone of existing is never closed, rather than dropping
+// the notes it holds.
+func keepLocalZones(generated, existing []byte) ([]byte, error) {
+	old, err := findDescription(existing)
+	if err != nil {
+		return nil, err
+	}
+	if !old.managed {
+		return generated, nil
+	}
+	fresh, err := findDescription(generated)
+	if err != nil || !fresh.managed {
+		return generated, err
+	}
+
+	var merged bytes.Buffer
+	merged.Write(generated[:fresh.start])
+	merged.Write(existing[old.start:old.managedFrom])
+	merged.Write(generated[fresh.managedFrom:fresh.managedTo])
+	merged.Write(existing[old.managedTo:old.end])
+	merged.Write(generated[fresh.end:])
+	return merged.Bytes(), nil
+}
+
+// truncateText returns the first limit characters of text, cut back to the end of a word
+// when one ends in their second half, and how many characters it leaves out. A limit of
+// zero or less keeps all of text.
+func truncateText(text string, limit int) (string, int) {
+	total := utf8.RuneCountInString(text)
+	if limit <= 0 || total <= limit {
+		return text, 0
+	}
+
+	end, n := 0, 0
+	for i := range text {
+		if n == limit {
+			end = i
+			break
+		}
+		n++
+	}
+	kept := text[:end]
+	if space := strings.LastIndexFunc(kept, unicode.IsSpace); space > 0 && space >= len(kept)/2 {
+		kept = kept[:space]
+	}
+	kept = strings.TrimRightFunc(kept, unicode.IsSpace)
+	return kept, total - utf8.RuneCountInString(kept)
+}
+
+// writeTruncationNote writes the marker and note ending a description that left
+// characters out.
+func writeTruncationNote(body *bytes.Buffer, key domain.TicketKey, left int) {
+	body.WriteString(truncationMarker + "\n")
+	fmt.Fprintf(body, "*Truncated: %d more characters in Jira. Run `jiramd expand %s` to show them.*\n", left, key)
+}
+
+// cutTruncationNote returns description, the content of a managed zone, without the
+// truncation marker and note ending it, and whether it had them.
+func cutTruncationNote(description string) (string, bool) {
+	// Only a marker on a line of its own counts
+	at := strings.Index("\n"+description, "\n"+truncationMarker)
+	if at < 0 {
+		return description, false
+	}
+	return strings.TrimSpace(description[:at]), true
+}
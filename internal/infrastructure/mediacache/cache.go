@@ -0,0 +1,281 @@
+// Package mediacache caches media downloaded from Jira on disk by content, so syncing
+// image-heavy tickets again, or into another vault, doesn't download it again.
+package mediacache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// Directories under the cache directory. Content is stored once per distinct content
+// under blobs, named by its SHA-256; keys maps each cache key to the content it holds.
+const (
+	blobDir = "blobs"
+	keyDir  = "keys"
+	tmpDir  = "tmp"
+)
+
+// Cache is a content-addressed cache of downloaded media, kept under a size limit by
+// evicting the least recently used content. It is safe for concurrent use, also by
+// several processes sharing the directory: files are only ever replaced by renames, and
+// content evicted by another process reads as a miss.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	// mu serializes evictions within the process
+	mu sync.Mutex
+}
+
+// New creates a cache in dir, kept under maxSize bytes (zero means no limit).
+func New(dir string, maxSize int64) *Cache {
+	return &Cache{dir: filepath.Clean(dir), maxSize: maxSize}
+}
+
+// Fetch writes the content cached under key to w. On a miss, fetch writes the content
+// to the cache first; if it fails, nothing is cached and its error is returned.
+func (c *Cache) Fetch(key string, w io.Writer, fetch func(w io.Writer) error) error {
+	if ok, err := c.get(key, w); ok || err != nil {
+		return err
+	}
+	if err := c.put(key, fetch); err != nil {
+		return err
+	}
+	if ok, err := c.get(key, w); !ok && err == nil {
+		// Evicted by another process before it could be read
+		return fmt.Errorf("media cache entry %s was evicted while being read", key)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// get writes the content cached under key to w, and marks it used. Returns false if
+// nothing is cached under key.
+func (c *Cache) get(key string, w io.Writer) (bool, error) {
+	keyPath := c.keyPath(key)
+	hash, err := os.ReadFile(keyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to read media cache: %w", err)
+	}
+
+	blobPath := c.blobPath(string(hash))
+	blob, err := os.Open(blobPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		// The content was evicted; forget the key too
+		os.Remove(keyPath)
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to read media cache: %w", err)
+	}
+	defer blob.Close()
+
+	now := time.Now()
+	_ = os.Chtimes(blobPath, now, now)
+	if _, err := io.Copy(w, blob); err != nil {
+		return false, fmt.Errorf("failed to read media cache: %w", err)
+	}
+	return true, nil
+}
+
+// put caches the content fetch writes under key, then evicts content until the cache is
+// under its size limit.
+func (c *Cache) put(key string, fetch func(w io.Writer) error) error {
+	tmp, err := c.createTemp()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	hash := sha256.New()
+	err = fetch(io.MultiWriter(tmp, hash))
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write media cache: %w", closeErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	blobPath := c.blobPath(sum)
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return fmt.Errorf("failed to write media cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), blobPath); err != nil {
+		return fmt.Errorf("failed to write media cache: %w", err)
+	}
+
+	keyFile, err := c.createTemp()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(keyFile.Name())
+	_, err = keyFile.WriteString(sum)
+	if closeErr := keyFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(keyFile.Name(), c.keyPath(key))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write media cache: %w", err)
+	}
+
+	return c.evict(blobPath)
+}
+
+// createTemp creates a file to write an entry to before renaming it into place.
+func (c *Cache) createTemp() (*os.File, error) {
+	for _, dir := range []string{tmpDir, keyDir} {
+		if err := os.MkdirAll(filepath.Join(c.dir, dir), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create media cache: %w", err)
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Join(c.dir, tmpDir), "entry-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create media cache entry: %w", err)
+	}
+	return tmp, nil
+}
+
+// blobInfo is a cached content file, for eviction.
+type blobInfo struct {
+	path string
+	size int64
+	used time.Time
+}
+
+// evict removes the least recently used content until the cache is under its size
+// limit, never keep (the content just written, even if larger than the limit on its
+// own), and then the keys of the content removed.
+func (c *Cache) evict(keep string) error {
+	if c.maxSize <= 0 {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var blobs []blobInfo
+	var total int64
+	err := filepath.WalkDir(filepath.Join(c.dir, blobDir), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// Removed by another process since it was listed
+			return nil
+		}
+		blobs = append(blobs, blobInfo{path: path, size: info.Size(), used: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list media cache: %w", err)
+	}
+	if total <= c.maxSize {
+		return nil
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].used.Before(blobs[j].used) })
+	removed := false
+	for _, blob := range blobs {
+		if total <= c.maxSize {
+			break
+		}
+		if blob.path == keep {
+			continue
+		}
+		if err := os.Remove(blob.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to evict from media cache: %w", err)
+		}
+		total -= blob.size
+		removed = true
+	}
+	if removed {
+		c.removeStaleKeys()
+	}
+	return nil
+}
+
+// removeStaleKeys removes the keys whose content was evicted. Keys that can't be read are
+// left for get to find.
+func (c *Cache) removeStaleKeys() {
+	entries, err := os.ReadDir(filepath.Join(c.dir, keyDir))
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		path := filepath.Join(c.dir, keyDir, entry.Name())
+		hash, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(c.blobPath(string(hash))); errors.Is(err, fs.ErrNotExist) {
+			os.Remove(path)
+		}
+	}
+}
+
+// keyPath returns the file recording the content cached under key.
+func (c *Cache) keyPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, keyDir, hex.EncodeToString(sum[:]))
+}
+
+// blobPath returns the file of the content with the given SHA-256, spread over
+// subdirectories by its first byte so no directory grows too large.
+func (c *Cache) blobPath(hash string) string {
+	hash = strings.TrimSpace(hash)
+	if len(hash) < 2 || strings.ContainsAny(hash, `/\.`) {
+		// Not written by put; points nowhere, so reads miss
+		return filepath.Join(c.dir, blobDir, "invalid")
+	}
+	return filepath.Join(c.dir, blobDir, hash[:2], hash)
+}
+
+// AttachmentSource downloads the content of Jira attachments.
+type AttachmentSource interface {
+	DownloadAttachment(ctx context.Context, attachment domain.Attachment, w io.Writer) error
+}
+
+// Attachments returns source with its downloads cached by attachment ID, which is safe
+// because Jira never changes an attachment's content: replacing a file attaches a new one.
+func (c *Cache) Attachments(source AttachmentSource) AttachmentSource {
+	return cachedAttachments{cache: c, source: source}
+}
+
+// cachedAttachments is an AttachmentSource that downloads through a Cache.
+type cachedAttachments struct {
+	cache  *Cache
+	source AttachmentSource
+}
+
+// DownloadAttachment writes the attachment's content to w from the cache, downloading it
+// from the source on a miss.
+func (a cachedAttachments) DownloadAttachment(ctx context.Context, attachment domain.Attachment, w io.Writer) error {
+	return a.cache.Fetch("attachment/"+attachment.ID, w, func(w io.Writer) error {
+		return a.source.DownloadAttachment(ctx, attachment, w)
+	})
+}
@@ -0,0 +1,162 @@
+package mediacache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// fetchString returns a fetch writing content, counting its calls in calls.
+func fetchString(content string, calls *int) func(io.Writer) error {
+	return func(w io.Writer) error {
+		*calls++
+		_, err := io.WriteString(w, content)
+		return err
+	}
+}
+
+func fetchKey(t *testing.T, cache *Cache, key, content string, calls *int) string {
+	t.Helper()
+	var got bytes.Buffer
+	if err := cache.Fetch(key, &got, fetchString(content, calls)); err != nil {
+		t.Fatalf("Fetch(%s) error = %v", key, err)
+	}
+	return got.String()
+}
+
+// blobCount returns the number of content files in the cache.
+func blobCount(t *testing.T, dir string) int {
+	t.Helper()
+	count := 0
+	filepath.WalkDir(filepath.Join(dir, blobDir), func(path string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+func TestCache_Fetch(t *testing.T) {
+	dir := t.TempDir()
+	cache := New(dir, 0)
+	calls := 0
+
+	if got := fetchKey(t, cache, "a", "shared image", &calls); got != "shared image" {
+		t.Errorf("Fetch() wrote %q on a miss", got)
+	}
+	if got := fetchKey(t, cache, "a", "never fetched", &calls); got != "shared image" {
+		t.Errorf("Fetch() wrote %q on a hit", got)
+	}
+	// Another key with the same content is stored once
+	fetchKey(t, cache, "b", "shared image", &calls)
+	if calls != 2 {
+		t.Errorf("fetched %d times, want 2", calls)
+	}
+	if n := blobCount(t, dir); n != 1 {
+		t.Errorf("cache holds %d files, want the shared content once", n)
+	}
+
+	// A failed fetch caches nothing
+	failed := errors.New("connection reset")
+	err := cache.Fetch("c", io.Discard, func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("Fetch() error = %v, want the fetch error", err)
+	}
+	if got := fetchKey(t, cache, "c", "complete", &calls); got != "complete" {
+		t.Errorf("Fetch() after a failed fetch wrote %q", got)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(dir, tmpDir)); len(entries) != 0 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestCache_Evict(t *testing.T) {
+	dir := t.TempDir()
+	cache := New(dir, 10)
+	calls := 0
+
+	fetchKey(t, cache, "old", "1234", &calls)
+	fetchKey(t, cache, "used", "5678", &calls)
+	// Make "old" the least recently used, even on file systems with coarse timestamps
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(cache.blobPath(sha("1234")), past, past)
+	fetchKey(t, cache, "new", "abcd", &calls)
+
+	if n := blobCount(t, dir); n != 2 {
+		t.Errorf("cache holds %d files, want 2 under the limit", n)
+	}
+	before := calls
+	fetchKey(t, cache, "used", "", &calls)
+	fetchKey(t, cache, "new", "", &calls)
+	if calls != before {
+		t.Errorf("recently used content was evicted")
+	}
+	if _, err := os.Stat(cache.keyPath("old")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("key of evicted content kept: %v", err)
+	}
+	if got := fetchKey(t, cache, "old", "1234", &calls); got != "1234" || calls != before+1 {
+		t.Errorf("Fetch() of evicted content = %q after %d fetches", got, calls-before)
+	}
+
+	// Content larger than the limit is still kept until something else is fetched
+	fetchKey(t, cache, "huge", strings.Repeat("x", 20), &calls)
+	if n := blobCount(t, dir); n != 1 {
+		t.Errorf("cache holds %d files, want only the one just fetched", n)
+	}
+}
+
+func TestCache_Attachments(t *testing.T) {
+	source := &countingSource{}
+	attachments := New(t.TempDir(), 0).Attachments(source)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		var got bytes.Buffer
+		if err := attachments.DownloadAttachment(ctx, domain.Attachment{ID: "10", Filename: "shot.png"}, &got); err != nil {
+			t.Fatalf("DownloadAttachment() error = %v", err)
+		}
+		if got.String() != "content of 10" {
+			t.Errorf("DownloadAttachment() wrote %q", got.String())
+		}
+	}
+	if source.downloads != 1 {
+		t.Errorf("downloaded %d times, want once", source.downloads)
+	}
+	if err := attachments.DownloadAttachment(ctx, domain.Attachment{ID: "gone"}, io.Discard); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DownloadAttachment() of a deleted attachment error = %v, want ErrNotFound", err)
+	}
+}
+
+// countingSource serves attachments other than "gone", counting downloads.
+type countingSource struct {
+	downloads int
+}
+
+func (s *countingSource) DownloadAttachment(ctx context.Context, attachment domain.Attachment, w io.Writer) error {
+	if attachment.ID == "gone" {
+		return domain.ErrNotFound
+	}
+	s.downloads++
+	_, err := io.WriteString(w, "content of "+attachment.ID)
+	return err
+}
+
+// sha returns the SHA-256 of content, as blobs are named.
+func sha(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
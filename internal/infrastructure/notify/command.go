@@ -0,0 +1,64 @@
+// Package notify delivers alerts to the user by running a configured shell command,
+// e.g. notify-send, a chat webhook via curl, or a mail command.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/application/auth"
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// commandTimeout bounds how long an alert command may run.
+const commandTimeout = 30 * time.Second
+
+// Alert events, passed to the command in JIRAMD_EVENT.
+const (
+	EventAuthFailed    = "auth_failed"
+	EventAuthRecovered = "auth_recovered"
+)
+
+// Command alerts the user by running a shell command with the alert in its environment:
+// JIRAMD_EVENT holds the event and JIRAMD_MESSAGE a human-readable description.
+type Command struct {
+	command string
+}
+
+// NewCommand creates a notifier running command with sh -c.
+func NewCommand(command string) *Command {
+	return &Command{command: command}
+}
+
+// Verify that Command implements the auth.Notifier interface
+var _ auth.Notifier = (*Command)(nil)
+
+// AuthFailed runs the command for credentials Jira keeps rejecting.
+// Implements auth.Notifier.AuthFailed.
+func (c *Command) AuthFailed(ctx context.Context, status domain.AuthStatus) error {
+	message := fmt.Sprintf("jiramd: AUTH FAILED - Jira rejected the configured credentials %d times since %s; pushes are paused. Last error: %s",
+		status.ConsecutiveFailures, status.FailingSince.Format(time.RFC3339), status.LastError)
+	return c.run(ctx, EventAuthFailed, message)
+}
+
+// AuthRecovered runs the command for credentials that work again.
+// Implements auth.Notifier.AuthRecovered.
+func (c *Command) AuthRecovered(ctx context.Context) error {
+	return c.run(ctx, EventAuthRecovered, "jiramd: Jira accepts the configured credentials again; pushes resume")
+}
+
+// run runs the command for one alert.
+func (c *Command) run(ctx context.Context, event, message string) error {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.command)
+	cmd.Env = append(os.Environ(), "JIRAMD_EVENT="+event, "JIRAMD_MESSAGE="+message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("alert command failed: %w: %s", err, output)
+	}
+	return nil
+}
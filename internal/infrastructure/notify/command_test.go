@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "alerts")
+	notifier := NewCommand(`printf '%s|%s\n' "$JIRAMD_EVENT" "$JIRAMD_MESSAGE" >> ` + out)
+
+	status := domain.AuthStatus{
+		ConsecutiveFailures: 3,
+		FailingSince:        time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		LastError:           "unauthorized: token expired",
+	}
+	if err := notifier.AuthFailed(context.Background(), status); err != nil {
+		t.Fatalf("AuthFailed failed: %v", err)
+	}
+	if err := notifier.AuthRecovered(context.Background()); err != nil {
+		t.Fatalf("AuthRecovered failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read alerts: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("alerts = %q, want 2 lines", data)
+	}
+	if !strings.HasPrefix(lines[0], EventAuthFailed+"|jiramd: AUTH FAILED") || !strings.Contains(lines[0], "token expired") {
+		t.Errorf("failure alert = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], EventAuthRecovered+"|") {
+		t.Errorf("recovery alert = %q", lines[1])
+	}
+}
+
+func TestCommand_Failure(t *testing.T) {
+	err := NewCommand("echo no notifier installed >&2; exit 3").AuthRecovered(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no notifier installed") {
+		t.Errorf("AuthRecovered() error = %v, want the command's output", err)
+	}
+}
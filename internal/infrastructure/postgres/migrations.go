@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Migration represents a database schema migration.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+var (
+	//go:embed migrations/001_state.sql
+	migration001 string
+
+	//go:embed migrations/002_content_hash_version.sql
+	migration002 string
+
+	//go:embed migrations/003_ticket_file_paths.sql
+	migration003 string
+
+	//go:embed migrations/004_pull_only_tickets.sql
+	migration004 string
+
+	//go:embed migrations/005_watched_tickets.sql
+	migration005 string
+)
+
+// migrations contains all available migrations in order.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "state",
+		SQL:     migration001,
+	},
+	{
+		Version: 2,
+		Name:    "content_hash_version",
+		SQL:     migration002,
+	},
+	{
+		Version: 3,
+		Name:    "ticket_file_paths",
+		SQL:     migration003,
+	},
+	{
+		Version: 4,
+		Name:    "pull_only_tickets",
+		SQL:     migration004,
+	},
+	{
+		Version: 5,
+		Name:    "watched_tickets",
+		SQL:     migration005,
+	},
+}
+
+// migrationLockID is the advisory lock key held while migrating, so instances
+// starting at the same time against a shared database migrate one at a time.
+const migrationLockID = 0x6a69726d64 // "jirmd"
+
+// ErrMigrationChecksumMismatch is returned at startup when a migration that was already
+// applied to the database differs from the migration embedded in the binary.
+var ErrMigrationChecksumMismatch = errors.New("applied migration does not match embedded migration")
+
+// Checksum returns the SHA-256 of the migration SQL in hex.
+// Line endings are normalized so a checkout with CRLF line endings has the same checksum.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(strings.ReplaceAll(m.SQL, "\r\n", "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// MigrationManager handles database schema migrations.
+type MigrationManager struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewMigrationManager creates a new migration manager.
+func NewMigrationManager(db *sql.DB, logger *slog.Logger) *MigrationManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &MigrationManager{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Migrate applies all pending migrations in a single transaction holding an advisory
+// lock, verifying the checksums of applied migrations first.
+// Returns the current schema version after migration.
+func (m *MigrationManager) Migrate(ctx context.Context) (int, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return 0, fmt.Errorf("failed to lock schema: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version INTEGER PRIMARY KEY,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`); err != nil {
+		return 0, fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+
+	applied, err := appliedChecksums(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	currentVersion := 0
+	for _, migration := range migrations {
+		checksum, ok := applied[migration.Version]
+		if ok {
+			if checksum != migration.Checksum() {
+				return 0, fmt.Errorf("%w: migration %d (%s)", ErrMigrationChecksumMismatch, migration.Version, migration.Name)
+			}
+			currentVersion = migration.Version
+			continue
+		}
+
+		// The migrations share one transaction, so stopping here rolls all of them back
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("migrations interrupted: %w", err)
+		}
+
+		m.logger.Info("applying migration",
+			"version", migration.Version,
+			"name", migration.Name)
+
+		if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+			return 0, fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_version (version, checksum) VALUES ($1, $2)`,
+			migration.Version, migration.Checksum()); err != nil {
+			return 0, fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+		currentVersion = migration.Version
+	}
+
+	for version := range applied {
+		if version > currentVersion {
+			m.logger.Warn("database has migrations unknown to this version of jiramd", "version", version)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit migrations: %w", err)
+	}
+	return currentVersion, nil
+}
+
+// appliedChecksums returns the checksum recorded for each applied migration version.
+func appliedChecksums(ctx context.Context, tx *sql.Tx) (map[int]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT version, checksum FROM schema_version`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema version: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("failed to scan schema version: %w", err)
+		}
+		applied[version] = checksum
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schema versions: %w", err)
+	}
+	return applied, nil
+}
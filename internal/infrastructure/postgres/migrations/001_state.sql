@@ -0,0 +1,46 @@
+-- Migration 001: Sync state
+-- The PostgreSQL state backend holds the same ticket and project sync state as the
+-- SQLite state tables, so several jiramd instances can share one state store.
+-- Zero timestamps are stored as NULL.
+
+CREATE TABLE IF NOT EXISTS ticket_sync_state (
+    ticket_key TEXT PRIMARY KEY,
+    project_key TEXT NOT NULL DEFAULT '',
+    last_synced TIMESTAMPTZ,
+    last_modified_local TIMESTAMPTZ,
+    last_modified_jira TIMESTAMPTZ,
+    is_dirty BOOLEAN NOT NULL DEFAULT FALSE,
+    conflict_detected BOOLEAN NOT NULL DEFAULT FALSE,
+    content_hash TEXT NOT NULL DEFAULT '',
+    jira_version TEXT NOT NULL DEFAULT '',
+    deleted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_project
+    ON ticket_sync_state(project_key, ticket_key);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_dirty
+    ON ticket_sync_state(is_dirty)
+    WHERE is_dirty;
+
+CREATE INDEX IF NOT EXISTS idx_ticket_conflict
+    ON ticket_sync_state(conflict_detected)
+    WHERE conflict_detected;
+
+CREATE INDEX IF NOT EXISTS idx_ticket_modified_local
+    ON ticket_sync_state(last_modified_local);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_deleted
+    ON ticket_sync_state(deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS project_sync_state (
+    project_key TEXT PRIMARY KEY,
+    last_full_sync TIMESTAMPTZ,
+    last_incremental_sync TIMESTAMPTZ,
+    ticket_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,5 @@
+-- Migration 003: Ticket file paths
+-- Records where each ticket's markdown file was last written, so commands working on
+-- one ticket find its file without walking the markdown directory.
+
+ALTER TABLE ticket_sync_state ADD COLUMN IF NOT EXISTS file_path TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- Migration 004: Pull-only tickets
+-- Marks tickets pulled ad hoc from outside the sync scope, which jiramd refreshes on
+-- request but never pushes.
+
+ALTER TABLE ticket_sync_state ADD COLUMN IF NOT EXISTS pull_only BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+-- Migration 005: Watched tickets
+-- Marks the tickets of the watch list, which every sync pulls whether or not their
+-- projects are synced, so those dropped from the list can be found and removed.
+
+ALTER TABLE ticket_sync_state ADD COLUMN IF NOT EXISTS watched BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_ticket_watched
+    ON ticket_sync_state(watched)
+    WHERE watched;
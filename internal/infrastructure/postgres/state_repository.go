@@ -0,0 +1,628 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// contextKey is a custom type for context keys to avoid collisions.
+type contextKey string
+
+const (
+	// txContextKey is the context key for storing transaction.
+	txContextKey contextKey = "postgres_tx"
+)
+
+// StateRepository implements repository.StateRepository using PostgreSQL.
+// Unlike the SQLite implementation it is safe for many processes to share.
+type StateRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewStateRepository creates a new PostgreSQL-backed StateRepository.
+// The database connection must be initialized and migrations applied before use.
+func NewStateRepository(db *sql.DB, logger *slog.Logger) *StateRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &StateRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Verify that StateRepository implements the repository.StateRepository interface
+var _ repository.StateRepository = (*StateRepository)(nil)
+
+// ticketStateColumns lists the columns read by every ticket state query, in scan order.
+const ticketStateColumns = `ticket_key, project_key, last_synced, last_modified_local, ` +
+	`last_modified_jira, is_dirty, conflict_detected, content_hash, content_hash_version, ` +
+	`jira_version, file_path, pull_only, watched, deleted_at`
+
+// SaveTicketState persists the synchronization state of a ticket.
+// Implements repository.StateRepository.SaveTicketState.
+func (r *StateRepository) SaveTicketState(ctx context.Context, state *repository.TicketSyncState) error {
+	if state == nil {
+		return fmt.Errorf("%w: state cannot be nil", domain.ErrInvalidInput)
+	}
+	if state.TicketKey == "" {
+		return fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+	if state.ProjectKey == "" {
+		state.ProjectKey, _, _ = strings.Cut(state.TicketKey, "-")
+	}
+
+	query := `
+		INSERT INTO ticket_sync_state (
+			ticket_key,
+			project_key,
+			last_synced,
+			last_modified_local,
+			last_modified_jira,
+			is_dirty,
+			conflict_detected,
+			content_hash,
+			content_hash_version,
+			jira_version,
+			file_path,
+			pull_only,
+			watched,
+			deleted_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (ticket_key) DO UPDATE SET
+			project_key = excluded.project_key,
+			last_synced = excluded.last_synced,
+			last_modified_local = excluded.last_modified_local,
+			last_modified_jira = excluded.last_modified_jira,
+			is_dirty = excluded.is_dirty,
+			conflict_detected = excluded.conflict_detected,
+			content_hash = excluded.content_hash,
+			content_hash_version = excluded.content_hash_version,
+			jira_version = excluded.jira_version,
+			file_path = excluded.file_path,
+			pull_only = excluded.pull_only,
+			watched = excluded.watched,
+			deleted_at = excluded.deleted_at,
+			updated_at = now()
+	`
+
+	_, err := r.executor(ctx).ExecContext(ctx, query,
+		state.TicketKey,
+		state.ProjectKey,
+		nullTime(state.LastSynced),
+		nullTime(state.LastModifiedLocal),
+		nullTime(state.LastModifiedJira),
+		state.IsDirty,
+		state.ConflictDetected,
+		state.ContentHash,
+		state.HashVersion,
+		state.JiraVersion,
+		state.FilePath,
+		state.PullOnly,
+		state.Watched,
+		nullTime(state.DeletedAt),
+	)
+	if err != nil {
+		r.logger.Error("failed to save ticket state",
+			"ticket_key", state.TicketKey,
+			"error", err)
+		return fmt.Errorf("failed to save ticket state: %w", mapError(err))
+	}
+
+	r.logger.Debug("saved ticket state",
+		"ticket_key", state.TicketKey,
+		"is_dirty", state.IsDirty,
+		"conflict_detected", state.ConflictDetected)
+
+	return nil
+}
+
+// GetTicketState retrieves the synchronization state of a ticket.
+// Implements repository.StateRepository.GetTicketState.
+func (r *StateRepository) GetTicketState(ctx context.Context, ticketKey string) (*repository.TicketSyncState, error) {
+	if ticketKey == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	query := `SELECT ` + ticketStateColumns + ` FROM ticket_sync_state WHERE ticket_key = $1`
+
+	state, err := scanTicketState(r.executor(ctx).QueryRowContext(ctx, query, ticketKey))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: ticket state not found for key %s", domain.ErrNotFound, ticketKey)
+		}
+		r.logger.Error("failed to get ticket state",
+			"ticket_key", ticketKey,
+			"error", err)
+		return nil, fmt.Errorf("failed to get ticket state: %w", mapError(err))
+	}
+
+	return state, nil
+}
+
+// GetTicketsModifiedSince retrieves all tickets with local modifications after the given time.
+// Implements repository.StateRepository.GetTicketsModifiedSince.
+func (r *StateRepository) GetTicketsModifiedSince(ctx context.Context, since time.Time) ([]*repository.TicketSyncState, error) {
+	return r.queryTicketStates(ctx, "tickets modified since", `
+		WHERE last_modified_local > $1
+		ORDER BY last_modified_local DESC
+	`, since.UTC())
+}
+
+// GetDirtyTickets retrieves all tickets marked as dirty.
+// Implements repository.StateRepository.GetDirtyTickets.
+func (r *StateRepository) GetDirtyTickets(ctx context.Context) ([]*repository.TicketSyncState, error) {
+	return r.queryTicketStates(ctx, "dirty tickets", `
+		WHERE is_dirty
+		ORDER BY last_modified_local DESC NULLS LAST
+	`)
+}
+
+// GetConflictedTickets retrieves all tickets with detected conflicts.
+// Implements repository.StateRepository.GetConflictedTickets.
+func (r *StateRepository) GetConflictedTickets(ctx context.Context) ([]*repository.TicketSyncState, error) {
+	return r.queryTicketStates(ctx, "conflicted tickets", `
+		WHERE conflict_detected
+		ORDER BY last_modified_local DESC NULLS LAST
+	`)
+}
+
+// GetWatchedTickets retrieves the states of all watched tickets, in key order.
+// Implements repository.StateRepository.GetWatchedTickets.
+func (r *StateRepository) GetWatchedTickets(ctx context.Context) ([]*repository.TicketSyncState, error) {
+	return r.queryTicketStates(ctx, "watched tickets", `
+		WHERE watched
+		ORDER BY ticket_key
+	`)
+}
+
+// GetTicketStatesByProject retrieves the states of all tickets in a project.
+// Implements repository.StateRepository.GetTicketStatesByProject.
+func (r *StateRepository) GetTicketStatesByProject(ctx context.Context, projectKey string) ([]*repository.TicketSyncState, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	// Keys share the project prefix, so shorter keys have lower issue numbers
+	return r.queryTicketStates(ctx, "project ticket states", `
+		WHERE project_key = $1
+		ORDER BY length(ticket_key), ticket_key
+	`, projectKey)
+}
+
+// ListTicketKeys returns the keys of all tracked tickets that start with prefix, in key order.
+// An empty prefix returns every key. This backs shell completion and is not part of
+// repository.StateRepository.
+func (r *StateRepository) ListTicketKeys(ctx context.Context, prefix string) ([]string, error) {
+	// left() avoids treating % and _ in the prefix as LIKE wildcards
+	query := `
+		SELECT ticket_key
+		FROM ticket_sync_state
+		WHERE left(ticket_key, length($1)) = $1
+		ORDER BY ticket_key
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query, prefix)
+	if err != nil {
+		r.logger.Error("failed to query ticket keys", "error", err)
+		return nil, fmt.Errorf("failed to query ticket keys: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan ticket key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ticket keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// DeleteTicketState removes the synchronization state for a ticket.
+// Implements repository.StateRepository.DeleteTicketState.
+func (r *StateRepository) DeleteTicketState(ctx context.Context, ticketKey string) error {
+	if ticketKey == "" {
+		return fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	result, err := r.executor(ctx).ExecContext(ctx, `DELETE FROM ticket_sync_state WHERE ticket_key = $1`, ticketKey)
+	if err != nil {
+		r.logger.Error("failed to delete ticket state",
+			"ticket_key", ticketKey,
+			"error", err)
+		return fmt.Errorf("failed to delete ticket state: %w", mapError(err))
+	}
+
+	if err := requireRowsAffected(result, "ticket state not found for key "+ticketKey); err != nil {
+		return err
+	}
+
+	r.logger.Debug("deleted ticket state", "ticket_key", ticketKey)
+	return nil
+}
+
+// PruneTombstones removes ticket states that were tombstoned before the given time.
+// Implements repository.StateRepository.PruneTombstones.
+func (r *StateRepository) PruneTombstones(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.executor(ctx).ExecContext(ctx,
+		`DELETE FROM ticket_sync_state WHERE deleted_at IS NOT NULL AND deleted_at < $1`, before.UTC())
+	if err != nil {
+		r.logger.Error("failed to prune ticket tombstones",
+			"before", before,
+			"error", err)
+		return 0, fmt.Errorf("failed to prune ticket tombstones: %w", mapError(err))
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.Debug("pruned ticket tombstones", "before", before, "count", pruned)
+	return int(pruned), nil
+}
+
+// SaveProjectState persists the synchronization state of a project.
+// Implements repository.StateRepository.SaveProjectState.
+func (r *StateRepository) SaveProjectState(ctx context.Context, state *repository.ProjectSyncState) error {
+	if state == nil {
+		return fmt.Errorf("%w: state cannot be nil", domain.ErrInvalidInput)
+	}
+	if state.ProjectKey == "" {
+		return fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	query := `
+		INSERT INTO project_sync_state (
+			project_key,
+			last_full_sync,
+			last_incremental_sync,
+			ticket_count
+		) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_key) DO UPDATE SET
+			last_full_sync = excluded.last_full_sync,
+			last_incremental_sync = excluded.last_incremental_sync,
+			ticket_count = excluded.ticket_count,
+			updated_at = now()
+	`
+
+	_, err := r.executor(ctx).ExecContext(ctx, query,
+		state.ProjectKey,
+		nullTime(state.LastFullSync),
+		nullTime(state.LastIncrementalSync),
+		state.TicketCount,
+	)
+	if err != nil {
+		r.logger.Error("failed to save project state",
+			"project_key", state.ProjectKey,
+			"error", err)
+		return fmt.Errorf("failed to save project state: %w", mapError(err))
+	}
+
+	r.logger.Debug("saved project state",
+		"project_key", state.ProjectKey,
+		"ticket_count", state.TicketCount)
+
+	return nil
+}
+
+// GetProjectState retrieves the synchronization state of a project.
+// Implements repository.StateRepository.GetProjectState.
+func (r *StateRepository) GetProjectState(ctx context.Context, projectKey string) (*repository.ProjectSyncState, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	query := `
+		SELECT project_key, last_full_sync, last_incremental_sync, ticket_count
+		FROM project_sync_state
+		WHERE project_key = $1
+	`
+
+	state, err := scanProjectState(r.executor(ctx).QueryRowContext(ctx, query, projectKey))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: project state not found for key %s", domain.ErrNotFound, projectKey)
+		}
+		r.logger.Error("failed to get project state",
+			"project_key", projectKey,
+			"error", err)
+		return nil, fmt.Errorf("failed to get project state: %w", mapError(err))
+	}
+
+	return state, nil
+}
+
+// GetAllProjectStates retrieves all project states.
+// Implements repository.StateRepository.GetAllProjectStates.
+func (r *StateRepository) GetAllProjectStates(ctx context.Context) ([]*repository.ProjectSyncState, error) {
+	query := `
+		SELECT project_key, last_full_sync, last_incremental_sync, ticket_count
+		FROM project_sync_state
+		ORDER BY project_key
+	`
+
+	rows, err := r.executor(ctx).QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to query all project states", "error", err)
+		return nil, fmt.Errorf("failed to query all project states: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	var states []*repository.ProjectSyncState
+	for rows.Next() {
+		state, err := scanProjectState(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project state: %w", err)
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate project states: %w", err)
+	}
+
+	return states, nil
+}
+
+// DeleteProjectState removes the synchronization state for a project and its tickets.
+// Implements repository.StateRepository.DeleteProjectState.
+func (r *StateRepository) DeleteProjectState(ctx context.Context, projectKey string) error {
+	if projectKey == "" {
+		return fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := r.executor(ctx)
+
+	// Delete in transaction if not already in one
+	inTransaction := transactionFromContext(ctx) != nil
+	if !inTransaction {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		exec = tx
+	}
+
+	if _, err := exec.ExecContext(ctx, `DELETE FROM ticket_sync_state WHERE project_key = $1`, projectKey); err != nil {
+		r.logger.Error("failed to delete project ticket states",
+			"project_key", projectKey,
+			"error", err)
+		return fmt.Errorf("failed to delete project ticket states: %w", mapError(err))
+	}
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM project_sync_state WHERE project_key = $1`, projectKey)
+	if err != nil {
+		r.logger.Error("failed to delete project state",
+			"project_key", projectKey,
+			"error", err)
+		return fmt.Errorf("failed to delete project state: %w", mapError(err))
+	}
+
+	if err := requireRowsAffected(result, "project state not found for key "+projectKey); err != nil {
+		return err
+	}
+
+	// Commit if we started the transaction
+	if !inTransaction {
+		if err := exec.(*sql.Tx).Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", mapError(err))
+		}
+	}
+
+	r.logger.Debug("deleted project state", "project_key", projectKey)
+	return nil
+}
+
+// BeginTransaction starts a new transaction.
+// Implements repository.StateRepository.BeginTransaction.
+func (r *StateRepository) BeginTransaction(ctx context.Context) (context.Context, error) {
+	if transactionFromContext(ctx) != nil {
+		return nil, fmt.Errorf("%w: transaction already active", domain.ErrInvalidInput)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("failed to begin transaction", "error", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	r.logger.Debug("transaction started")
+	return context.WithValue(ctx, txContextKey, tx), nil
+}
+
+// Commit commits the current transaction.
+// Implements repository.StateRepository.Commit.
+func (r *StateRepository) Commit(ctx context.Context) error {
+	tx := transactionFromContext(ctx)
+	if tx == nil {
+		return fmt.Errorf("%w: no active transaction", domain.ErrInvalidInput)
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("failed to commit transaction", "error", err)
+		return fmt.Errorf("failed to commit transaction: %w", mapError(err))
+	}
+
+	r.logger.Debug("transaction committed")
+	return nil
+}
+
+// Rollback rolls back the current transaction.
+// Implements repository.StateRepository.Rollback.
+func (r *StateRepository) Rollback(ctx context.Context) error {
+	tx := transactionFromContext(ctx)
+	if tx == nil {
+		return fmt.Errorf("%w: no active transaction", domain.ErrInvalidInput)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		r.logger.Error("failed to rollback transaction", "error", err)
+		return fmt.Errorf("failed to rollback transaction: %w", err)
+	}
+
+	r.logger.Debug("transaction rolled back")
+	return nil
+}
+
+// Helper functions
+
+// queryTicketStates runs a SELECT over ticket_sync_state with the given WHERE and ORDER BY clauses.
+func (r *StateRepository) queryTicketStates(ctx context.Context, what, clauses string, args ...interface{}) ([]*repository.TicketSyncState, error) {
+	rows, err := r.executor(ctx).QueryContext(ctx, `SELECT `+ticketStateColumns+` FROM ticket_sync_state `+clauses, args...)
+	if err != nil {
+		r.logger.Error("failed to query "+what, "error", err)
+		return nil, fmt.Errorf("failed to query %s: %w", what, mapError(err))
+	}
+	defer rows.Close()
+
+	var states []*repository.TicketSyncState
+	for rows.Next() {
+		state, err := scanTicketState(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ticket state: %w", err)
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate ticket states: %w", err)
+	}
+
+	return states, nil
+}
+
+// executor returns the transaction in ctx, or the connection pool when there is none.
+func (r *StateRepository) executor(ctx context.Context) executor {
+	if tx := transactionFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// transactionFromContext extracts the transaction started by StateRepository.BeginTransaction.
+func transactionFromContext(ctx context.Context) *sql.Tx {
+	if tx, ok := ctx.Value(txContextKey).(*sql.Tx); ok {
+		return tx
+	}
+	return nil
+}
+
+// executor is an interface that both *sql.DB and *sql.Tx implement.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTicketState reads one ticket state in ticketStateColumns order.
+func scanTicketState(row rowScanner) (*repository.TicketSyncState, error) {
+	var state repository.TicketSyncState
+	var lastSynced, lastModifiedLocal, lastModifiedJira, deletedAt sql.NullTime
+
+	if err := row.Scan(
+		&state.TicketKey,
+		&state.ProjectKey,
+		&lastSynced,
+		&lastModifiedLocal,
+		&lastModifiedJira,
+		&state.IsDirty,
+		&state.ConflictDetected,
+		&state.ContentHash,
+		&state.HashVersion,
+		&state.JiraVersion,
+		&state.FilePath,
+		&state.PullOnly,
+		&state.Watched,
+		&deletedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	state.LastSynced = timeOf(lastSynced)
+	state.LastModifiedLocal = timeOf(lastModifiedLocal)
+	state.LastModifiedJira = timeOf(lastModifiedJira)
+	state.DeletedAt = timeOf(deletedAt)
+
+	return &state, nil
+}
+
+// scanProjectState reads one project state row.
+func scanProjectState(row rowScanner) (*repository.ProjectSyncState, error) {
+	var state repository.ProjectSyncState
+	var lastFullSync, lastIncrementalSync sql.NullTime
+
+	if err := row.Scan(&state.ProjectKey, &lastFullSync, &lastIncrementalSync, &state.TicketCount); err != nil {
+		return nil, err
+	}
+
+	state.LastFullSync = timeOf(lastFullSync)
+	state.LastIncrementalSync = timeOf(lastIncrementalSync)
+
+	return &state, nil
+}
+
+// nullTime stores the zero time as NULL and everything else in UTC.
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC()
+}
+
+// timeOf converts a nullable column back to a UTC time, with NULL as the zero time.
+func timeOf(t sql.NullTime) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+	return t.Time.UTC()
+}
+
+// requireRowsAffected returns ErrNotFound (described by what) if result affected no rows.
+func requireRowsAffected(result sql.Result, what string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, what)
+	}
+	return nil
+}
+
+// mapError maps PostgreSQL errors caused by concurrent writers to domain.ErrConflict,
+// so callers can retry; other errors are returned unchanged.
+func mapError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", "40P01": // serialization_failure, deadlock_detected
+			return fmt.Errorf("%w: %v", domain.ErrConflict, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,202 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// setupTestDB connects to the database named by JIRAMD_TEST_POSTGRES_DSN, migrates it,
+// and empties the state tables. Tests are skipped when the variable is not set.
+func setupTestDB(t *testing.T) *Database {
+	t.Helper()
+
+	dsn := os.Getenv("JIRAMD_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("JIRAMD_TEST_POSTGRES_DSN not set")
+	}
+
+	ctx := context.Background()
+	db, err := NewDatabase(ctx, DefaultConfig(dsn), nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Migrate(ctx); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if _, err := db.DB().ExecContext(ctx, `TRUNCATE ticket_sync_state, project_sync_state`); err != nil {
+		t.Fatalf("failed to truncate state tables: %v", err)
+	}
+
+	return db
+}
+
+func TestIntegration_MigrateIsIdempotent(t *testing.T) {
+	db := setupTestDB(t)
+
+	version, err := NewMigrationManager(db.DB(), nil).Migrate(context.Background())
+	if err != nil {
+		t.Fatalf("second Migrate() error = %v", err)
+	}
+	if version != len(migrations) {
+		t.Errorf("version = %d, want %d", version, len(migrations))
+	}
+}
+
+func TestIntegration_TicketStateRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	state := &repository.TicketSyncState{
+		TicketKey:         "JMD-10",
+		LastSynced:        now,
+		LastModifiedLocal: now.Add(time.Minute),
+		IsDirty:           true,
+		ContentHash:       "abc123",
+		JiraVersion:       "v1",
+		FilePath:          "JMD-10.md",
+		PullOnly:          true,
+		Watched:           true,
+	}
+
+	if err := repo.SaveTicketState(ctx, state); err != nil {
+		t.Fatalf("SaveTicketState() error = %v", err)
+	}
+
+	got, err := repo.GetTicketState(ctx, "JMD-10")
+	if err != nil {
+		t.Fatalf("GetTicketState() error = %v", err)
+	}
+
+	if got.ProjectKey != "JMD" {
+		t.Errorf("ProjectKey = %q, want JMD", got.ProjectKey)
+	}
+	if !got.LastSynced.Equal(state.LastSynced) || !got.LastModifiedLocal.Equal(state.LastModifiedLocal) {
+		t.Errorf("timestamps = %v, %v, want %v, %v", got.LastSynced, got.LastModifiedLocal, state.LastSynced, state.LastModifiedLocal)
+	}
+	if !got.LastModifiedJira.IsZero() || !got.DeletedAt.IsZero() {
+		t.Errorf("unset timestamps should be zero, got %v, %v", got.LastModifiedJira, got.DeletedAt)
+	}
+	if !got.IsDirty || got.ContentHash != "abc123" || got.JiraVersion != "v1" || got.FilePath != "JMD-10.md" || !got.PullOnly || !got.Watched {
+		t.Errorf("got %+v", got)
+	}
+
+	if _, err := repo.GetTicketState(ctx, "JMD-99"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetTicketState(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIntegration_ProjectQueries(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	for _, key := range []string{"JMD-10", "JMD-9", "JMD-100", "OTHER-1"} {
+		if err := repo.SaveTicketState(ctx, &repository.TicketSyncState{TicketKey: key}); err != nil {
+			t.Fatalf("SaveTicketState(%s) error = %v", key, err)
+		}
+	}
+	if err := repo.SaveProjectState(ctx, &repository.ProjectSyncState{ProjectKey: "JMD", TicketCount: 3}); err != nil {
+		t.Fatalf("SaveProjectState() error = %v", err)
+	}
+
+	states, err := repo.GetTicketStatesByProject(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("GetTicketStatesByProject() error = %v", err)
+	}
+	want := []string{"JMD-9", "JMD-10", "JMD-100"}
+	if len(states) != len(want) {
+		t.Fatalf("got %d states, want %d", len(states), len(want))
+	}
+	for i, state := range states {
+		if state.TicketKey != want[i] {
+			t.Errorf("states[%d] = %s, want %s", i, state.TicketKey, want[i])
+		}
+	}
+
+	keys, err := repo.ListTicketKeys(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("ListTicketKeys() error = %v", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("ListTicketKeys(JMD-1) = %v, want JMD-10 and JMD-100", keys)
+	}
+
+	if err := repo.DeleteProjectState(ctx, "JMD"); err != nil {
+		t.Fatalf("DeleteProjectState() error = %v", err)
+	}
+	if _, err := repo.GetTicketState(ctx, "OTHER-1"); err != nil {
+		t.Errorf("other project's state was deleted: %v", err)
+	}
+	if _, err := repo.GetTicketState(ctx, "JMD-9"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetTicketState(JMD-9) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIntegration_TransactionRollback(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	txCtx, err := repo.BeginTransaction(ctx)
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	if err := repo.SaveTicketState(txCtx, &repository.TicketSyncState{TicketKey: "JMD-1"}); err != nil {
+		t.Fatalf("SaveTicketState() error = %v", err)
+	}
+	if err := repo.Rollback(txCtx); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if _, err := repo.GetTicketState(ctx, "JMD-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetTicketState() after rollback error = %v, want ErrNotFound", err)
+	}
+	if err := repo.Commit(ctx); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Commit() without transaction error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestIntegration_ConcurrentWriters(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	// Each writer gets its own repository, as separate jiramd instances would
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			repo := NewStateRepository(db.DB(), nil)
+			errs <- repo.SaveTicketState(ctx, &repository.TicketSyncState{
+				TicketKey: "JMD-1",
+				IsDirty:   i%2 == 0,
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent SaveTicketState() error = %v", err)
+		}
+	}
+
+	if _, err := NewStateRepository(db.DB(), nil).GetTicketState(ctx, "JMD-1"); err != nil {
+		t.Errorf("GetTicketState() error = %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+// Package progress provides implementations of the application progress.Progress interface.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/application/progress"
+)
+
+const (
+	// barWidth is the number of cells in a progress bar.
+	barWidth = 30
+
+	// barRedrawInterval limits how often a bar is redrawn, so fast operations do not
+	// spend their time writing to the terminal.
+	barRedrawInterval = 100 * time.Millisecond
+)
+
+// Bar is a progress bar redrawn in place on a terminal, for interactive CLI commands.
+// Tasks with an unknown total show a running count instead of a bar.
+type Bar struct {
+	mu sync.Mutex
+	w  io.Writer
+
+	task     string
+	total    int
+	done     int
+	lastDraw time.Time
+	width    int
+
+	// now is the clock used to limit redraws (overridable in tests)
+	now func() time.Time
+}
+
+// NewBar creates a progress bar that draws on w, which should be a terminal.
+func NewBar(w io.Writer) *Bar {
+	return &Bar{w: w, now: time.Now}
+}
+
+// Verify that Bar implements the progress.Progress interface
+var _ progress.Progress = (*Bar)(nil)
+
+// Start begins a task and draws its empty bar.
+// Implements progress.Progress.Start.
+func (b *Bar) Start(task string, total int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.task = task
+	b.total = total
+	b.done = 0
+	b.width = 0
+	b.draw()
+}
+
+// Advance moves the bar forward, redrawing it at most every barRedrawInterval.
+// Implements progress.Progress.Advance.
+func (b *Bar) Advance(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.done += n
+	if b.now().Sub(b.lastDraw) >= barRedrawInterval || (b.total > 0 && b.done >= b.total) {
+		b.draw()
+	}
+}
+
+// Finish draws the final state of the bar and moves to the next line.
+// Implements progress.Progress.Finish.
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.draw()
+	fmt.Fprintln(b.w)
+}
+
+// draw rewrites the current line with the bar.
+func (b *Bar) draw() {
+	var line string
+	if b.total > 0 {
+		done := min(b.done, b.total)
+		filled := done * barWidth / b.total
+		line = fmt.Sprintf("%s [%s%s] %d/%d %3d%%",
+			b.task,
+			strings.Repeat("#", filled),
+			strings.Repeat("-", barWidth-filled),
+			done, b.total, done*100/b.total)
+	} else {
+		line = fmt.Sprintf("%s ... %d", b.task, b.done)
+	}
+
+	// Pad over the rest of a longer previous line
+	padding := max(b.width-len(line), 0)
+	fmt.Fprintf(b.w, "\r%s%s", line, strings.Repeat(" ", padding))
+	b.width = len(line)
+	b.lastDraw = b.now()
+}
@@ -0,0 +1,92 @@
+package progress
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/application/progress"
+)
+
+// DefaultLogInterval is how often a Logger reports a running task when no interval is given.
+const DefaultLogInterval = 15 * time.Second
+
+// Logger reports progress as log records, for the daemon where there is no terminal:
+// one record when a task starts, one at most every interval while it runs, and one
+// when it finishes.
+type Logger struct {
+	mu       sync.Mutex
+	logger   *slog.Logger
+	interval time.Duration
+
+	task    string
+	total   int
+	done    int
+	started time.Time
+	lastLog time.Time
+
+	// now is the clock used for intervals and durations (overridable in tests)
+	now func() time.Time
+}
+
+// NewLogger creates a progress reporter that logs to logger every interval
+// (DefaultLogInterval when interval <= 0).
+func NewLogger(logger *slog.Logger, interval time.Duration) *Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if interval <= 0 {
+		interval = DefaultLogInterval
+	}
+	return &Logger{logger: logger, interval: interval, now: time.Now}
+}
+
+// Verify that Logger implements the progress.Progress interface
+var _ progress.Progress = (*Logger)(nil)
+
+// Start logs the start of a task.
+// Implements progress.Progress.Start.
+func (l *Logger) Start(task string, total int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.task = task
+	l.total = total
+	l.done = 0
+	l.started = l.now()
+	l.lastLog = l.started
+
+	l.logger.Info("task started", "task", task, "total", total)
+}
+
+// Advance logs the task's progress if interval has passed since the last record.
+// Implements progress.Progress.Advance.
+func (l *Logger) Advance(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.done += n
+	now := l.now()
+	if now.Sub(l.lastLog) < l.interval {
+		return
+	}
+	l.lastLog = now
+
+	l.logger.Info("task progress",
+		"task", l.task,
+		"done", l.done,
+		"total", l.total,
+		"elapsed", now.Sub(l.started).Round(time.Second))
+}
+
+// Finish logs the end of the task.
+// Implements progress.Progress.Finish.
+func (l *Logger) Finish() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.logger.Info("task finished",
+		"task", l.task,
+		"done", l.done,
+		"duration", l.now().Sub(l.started).Round(time.Millisecond))
+}
@@ -0,0 +1,82 @@
+package progress
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBar(t *testing.T) {
+	var out bytes.Buffer
+	bar := NewBar(&out)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	bar.now = func() time.Time { return now }
+
+	bar.Start("Pulling JMD", 4)
+	if !strings.HasSuffix(out.String(), "Pulling JMD ["+strings.Repeat("-", barWidth)+"] 0/4   0%") {
+		t.Errorf("start = %q", out.String())
+	}
+
+	// Updates within the redraw interval are not drawn
+	out.Reset()
+	bar.Advance(1)
+	if out.Len() != 0 {
+		t.Errorf("bar redrawn too soon: %q", out.String())
+	}
+
+	now = now.Add(barRedrawInterval)
+	bar.Advance(1)
+	if !strings.Contains(out.String(), "2/4  50%") {
+		t.Errorf("advance = %q, want 2/4 50%%", out.String())
+	}
+
+	// Completion is always drawn
+	out.Reset()
+	bar.Advance(2)
+	if !strings.Contains(out.String(), "["+strings.Repeat("#", barWidth)+"] 4/4 100%") {
+		t.Errorf("complete = %q", out.String())
+	}
+
+	out.Reset()
+	bar.Finish()
+	if !strings.HasSuffix(out.String(), "\n") {
+		t.Errorf("Finish() = %q, want trailing newline", out.String())
+	}
+
+	// Unknown totals show a count, padded over the longer previous line
+	out.Reset()
+	bar.Start("Scanning", 0)
+	bar.Advance(7)
+	bar.Finish()
+	if !strings.Contains(out.String(), "\rScanning ... 7") {
+		t.Errorf("unknown total = %q", out.String())
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(slog.New(slog.NewTextHandler(&out, nil)), time.Minute)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	logger.now = func() time.Time { return now }
+
+	logger.Start("full sync JMD", 100)
+	logger.Advance(10)
+	if got := strings.Count(out.String(), "task progress"); got != 0 {
+		t.Errorf("logged %d progress records before the interval", got)
+	}
+
+	now = now.Add(time.Minute)
+	logger.Advance(10)
+	if !strings.Contains(out.String(), "task progress") || !strings.Contains(out.String(), "done=20") {
+		t.Errorf("progress not logged after the interval: %s", out.String())
+	}
+
+	logger.Finish()
+	if !strings.Contains(out.String(), "task finished") || !strings.Contains(out.String(), "duration=1m0s") {
+		t.Errorf("finish not logged: %s", out.String())
+	}
+}
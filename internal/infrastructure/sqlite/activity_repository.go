@@ -0,0 +1,151 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// ActivityRepository implements repository.ActivityRepository using SQLite.
+// Summaries and changes carry ticket content, so they are encrypted at rest like the
+// ticket cache.
+type ActivityRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+	cipher *Cipher
+}
+
+// NewActivityRepository creates a new SQLite-backed activity log.
+// The database connection must be initialized and migrations applied before use.
+func NewActivityRepository(db *sql.DB, logger *slog.Logger) *ActivityRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ActivityRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithCipher makes the repository encrypt summaries and changes at rest.
+func (r *ActivityRepository) WithCipher(c *Cipher) *ActivityRepository {
+	r.cipher = c
+	return r
+}
+
+// Verify that ActivityRepository implements the repository interface
+var _ repository.ActivityRepository = (*ActivityRepository)(nil)
+
+// RecordActivity appends an activity to the log and sets its ID.
+// Implements repository.ActivityRepository.RecordActivity.
+func (r *ActivityRepository) RecordActivity(ctx context.Context, activity *domain.Activity) error {
+	if activity == nil {
+		return fmt.Errorf("%w: activity cannot be nil", domain.ErrInvalidInput)
+	}
+	if err := activity.Validate(); err != nil {
+		return err
+	}
+
+	summary, err := r.cipher.seal(activity.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt activity summary: %w", err)
+	}
+	change, err := r.cipher.seal(activity.Change)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt activity change: %w", err)
+	}
+
+	exec := executorFor(ctx, r.db)
+	result, err := exec.ExecContext(ctx, `
+		INSERT INTO activity (
+			ticket_key,
+			summary,
+			direction,
+			change,
+			occurred_at
+		) VALUES (?, ?, ?, ?, ?)
+	`,
+		activity.TicketKey.String(),
+		summary,
+		string(activity.Direction),
+		change,
+		formatTimestamp(activity.At),
+	)
+	if err != nil {
+		r.logger.Error("failed to record activity", "ticket_key", activity.TicketKey.String(), "error", err)
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get activity id: %w", err)
+	}
+	activity.ID = id
+	return nil
+}
+
+// FindActivities retrieves the activities at or after from and before to, oldest first.
+// Implements repository.ActivityRepository.FindActivities.
+func (r *ActivityRepository) FindActivities(ctx context.Context, from, to time.Time) ([]*domain.Activity, error) {
+	exec := executorFor(ctx, r.db)
+	rows, err := exec.QueryContext(ctx, `
+		SELECT id, ticket_key, summary, direction, change, occurred_at FROM activity
+		WHERE occurred_at >= ? AND occurred_at < ?
+		ORDER BY occurred_at, id
+	`, formatTimestamp(from), formatTimestamp(to))
+	if err != nil {
+		r.logger.Error("failed to query activity", "error", err)
+		return nil, fmt.Errorf("failed to query activity: %w", err)
+	}
+	defer rows.Close()
+
+	activities := make([]*domain.Activity, 0)
+	for rows.Next() {
+		activity := &domain.Activity{}
+		var ticketKey, direction, occurredAt string
+		if err := rows.Scan(&activity.ID, &ticketKey, &activity.Summary, &direction, &activity.Change, &occurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan activity: %w", err)
+		}
+		if activity.TicketKey, err = domain.NewTicketKey(ticketKey); err != nil {
+			return nil, fmt.Errorf("failed to parse ticket key of activity %d: %w", activity.ID, err)
+		}
+		if err := r.cipher.openAll(&activity.Summary, &activity.Change); err != nil {
+			return nil, err
+		}
+		activity.Direction = domain.ActivityDirection(direction)
+		activity.At = parseTimestamp(occurredAt)
+		activities = append(activities, activity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate activity: %w", err)
+	}
+
+	return activities, nil
+}
+
+// PruneActivities removes activities before the given time.
+// Implements repository.ActivityRepository.PruneActivities.
+func (r *ActivityRepository) PruneActivities(ctx context.Context, before time.Time) (int, error) {
+	exec := executorFor(ctx, r.db)
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM activity WHERE occurred_at < ?`, formatTimestamp(before))
+	if err != nil {
+		r.logger.Error("failed to prune activity", "before", before, "error", err)
+		return 0, fmt.Errorf("failed to prune activity: %w", err)
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.Debug("pruned activity", "before", before, "count", pruned)
+	return int(pruned), nil
+}
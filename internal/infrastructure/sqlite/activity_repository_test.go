@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestActivityRepository_RecordAndFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewActivityRepository(db.DB(), nil).WithCipher(newTestCipher(t, 1))
+	ctx := context.Background()
+
+	key, _ := domain.NewTicketKey("JMD-1")
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	activity := func(direction domain.ActivityDirection, change string, at time.Time) *domain.Activity {
+		return &domain.Activity{TicketKey: key, Summary: "Login", Direction: direction, Change: change, At: at}
+	}
+	pushed := activity(domain.ActivityPushed, "comment added", day.Add(10*time.Hour))
+	pulled := activity(domain.ActivityPulled, "status: To Do → Done", day.Add(9*time.Hour))
+	yesterday := activity(domain.ActivityPulled, "new ticket", day.Add(-time.Hour))
+	for _, a := range []*domain.Activity{pushed, pulled, yesterday} {
+		if err := repo.RecordActivity(ctx, a); err != nil {
+			t.Fatalf("RecordActivity() error = %v", err)
+		}
+		if a.ID == 0 {
+			t.Errorf("RecordActivity() did not set the ID of %q", a.Change)
+		}
+	}
+
+	// Summaries and changes are encrypted at rest
+	var stored string
+	if err := db.DB().QueryRowContext(ctx, `SELECT change FROM activity WHERE id = ?`, pulled.ID).Scan(&stored); err != nil {
+		t.Fatalf("query change failed: %v", err)
+	}
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		t.Errorf("stored change = %q, want encrypted", stored)
+	}
+
+	found, err := repo.FindActivities(ctx, day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("FindActivities() error = %v", err)
+	}
+	if want := []*domain.Activity{pulled, pushed}; !reflect.DeepEqual(found, want) {
+		t.Errorf("FindActivities() = %+v, want the day's activities oldest first", found)
+	}
+
+	pruned, err := repo.PruneActivities(ctx, day)
+	if err != nil || pruned != 1 {
+		t.Errorf("PruneActivities() = %d, %v; want 1", pruned, err)
+	}
+
+	if err := repo.RecordActivity(ctx, &domain.Activity{Direction: domain.ActivityPulled, At: day}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("RecordActivity(no key) error = %v, want ErrInvalidInput", err)
+	}
+}
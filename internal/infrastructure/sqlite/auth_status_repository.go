@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// AuthStatusRepository implements repository.AuthStatusRepository using SQLite.
+type AuthStatusRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewAuthStatusRepository creates a new SQLite-backed authentication status store.
+// The database connection must be initialized and migrations applied before use.
+func NewAuthStatusRepository(db *sql.DB, logger *slog.Logger) *AuthStatusRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AuthStatusRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Verify that AuthStatusRepository implements the repository interface
+var _ repository.AuthStatusRepository = (*AuthStatusRepository)(nil)
+
+// GetAuthStatus returns the stored status, or the zero value if none was saved.
+// Implements repository.AuthStatusRepository.GetAuthStatus.
+func (r *AuthStatusRepository) GetAuthStatus(ctx context.Context) (domain.AuthStatus, error) {
+	var status domain.AuthStatus
+	var failingSince, lastFailureAt string
+
+	err := executorFor(ctx, r.db).QueryRowContext(ctx, `
+		SELECT consecutive_failures, COALESCE(failing_since, ''), COALESCE(last_failure_at, ''), last_error
+		FROM auth_status
+		WHERE id = 1
+	`).Scan(&status.ConsecutiveFailures, &failingSince, &lastFailureAt, &status.LastError)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.AuthStatus{}, nil
+	}
+	if err != nil {
+		return domain.AuthStatus{}, fmt.Errorf("failed to get auth status: %w", err)
+	}
+
+	status.FailingSince = parseTimestamp(failingSince)
+	status.LastFailureAt = parseTimestamp(lastFailureAt)
+	return status, nil
+}
+
+// SaveAuthStatus replaces the stored status.
+// Implements repository.AuthStatusRepository.SaveAuthStatus.
+func (r *AuthStatusRepository) SaveAuthStatus(ctx context.Context, status domain.AuthStatus) error {
+	_, err := executorFor(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO auth_status (id, consecutive_failures, failing_since, last_failure_at, last_error)
+		VALUES (1, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			consecutive_failures = excluded.consecutive_failures,
+			failing_since = excluded.failing_since,
+			last_failure_at = excluded.last_failure_at,
+			last_error = excluded.last_error
+	`,
+		status.ConsecutiveFailures,
+		formatTimestampNullable(status.FailingSince),
+		formatTimestampNullable(status.LastFailureAt),
+		status.LastError,
+	)
+	if err != nil {
+		r.logger.Error("failed to save auth status", "error", err)
+		return fmt.Errorf("failed to save auth status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestAuthStatusRepository(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewAuthStatusRepository(db.DB(), nil)
+
+	got, err := repo.GetAuthStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetAuthStatus failed: %v", err)
+	}
+	if got != (domain.AuthStatus{}) {
+		t.Errorf("GetAuthStatus() before any save = %+v, want zero", got)
+	}
+
+	failed := domain.AuthStatus{
+		ConsecutiveFailures: 3,
+		FailingSince:        time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		LastFailureAt:       time.Date(2025, 6, 1, 12, 5, 0, 0, time.UTC),
+		LastError:           "unauthorized: token expired",
+	}
+	if err := repo.SaveAuthStatus(ctx, failed); err != nil {
+		t.Fatalf("SaveAuthStatus failed: %v", err)
+	}
+	got, err = repo.GetAuthStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetAuthStatus failed: %v", err)
+	}
+	if got.ConsecutiveFailures != 3 || !got.FailingSince.Equal(failed.FailingSince) || !got.LastFailureAt.Equal(failed.LastFailureAt) || got.LastError != failed.LastError {
+		t.Errorf("GetAuthStatus() = %+v, want %+v", got, failed)
+	}
+
+	// Saving again replaces the status
+	if err := repo.SaveAuthStatus(ctx, domain.AuthStatus{}); err != nil {
+		t.Fatalf("SaveAuthStatus failed: %v", err)
+	}
+	got, err = repo.GetAuthStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetAuthStatus failed: %v", err)
+	}
+	if got != (domain.AuthStatus{}) {
+		t.Errorf("GetAuthStatus() after reset = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,224 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// KeySize is the length in bytes of the key used to encrypt columns at rest (AES-256).
+const KeySize = 32
+
+// encryptedPrefix marks a column value sealed by a Cipher. Values without it are
+// plaintext written before encryption was enabled.
+const encryptedPrefix = "enc1:"
+
+// Cipher encrypts sensitive column values (ticket summaries, descriptions, custom
+// fields, comment bodies, and queued payloads) with AES-256-GCM before they are
+// written to the database file. Keys, timestamps, and other metadata stay in
+// plaintext so they can still be indexed and queried.
+//
+// A nil *Cipher stores values unchanged, which is how the repositories behave when
+// encryption is not configured.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a Cipher from a KeySize-byte key.
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: encryption key must be %d bytes, got %d", domain.ErrConfig, KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// seal encrypts a column value. Empty values are stored as-is.
+func (c *Cipher) seal(value string) (string, error) {
+	if c == nil || value == "" {
+		return value, nil
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts a column value written by seal. Plaintext values are returned unchanged,
+// so rows written before encryption was enabled stay readable.
+func (c *Cipher) open(value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return value, nil
+	}
+	if c == nil {
+		return "", fmt.Errorf("%w: the database contains encrypted data; enable storage.encrypt", domain.ErrConfig)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to decrypt value; the encryption key does not match the database", domain.ErrConfig)
+	}
+	return string(plaintext), nil
+}
+
+// openAll decrypts each of values in place.
+func (c *Cipher) openAll(values ...*string) error {
+	for _, value := range values {
+		opened, err := c.open(*value)
+		if err != nil {
+			return err
+		}
+		*value = opened
+	}
+	return nil
+}
+
+// encryptedColumns lists the sensitive columns of each table, with the table's key column.
+var encryptedColumns = []struct {
+	table   string
+	key     string
+	columns []string
+}{
+	{table: "tickets", key: "ticket_key", columns: []string{"summary", "description", "custom_fields"}},
+	{table: "comments", key: "comment_id", columns: []string{"body"}},
+	{table: "pending_operations", key: "id", columns: []string{"payload"}},
+	{table: "inbox", key: "event_id", columns: []string{"payload"}},
+	{table: "activity", key: "id", columns: []string{"summary", "change"}},
+	{table: "fetched_tickets", key: "ticket_key", columns: []string{"ticket"}},
+}
+
+// EncryptPlaintext encrypts sensitive values that are still stored in plaintext, e.g.
+// because the database was created before encryption was enabled. It returns the number
+// of rows rewritten; when any were, the database is vacuumed so the plaintext does not
+// linger in freed pages. It does nothing when the database has no Cipher.
+func (d *Database) EncryptPlaintext(ctx context.Context) (int, error) {
+	if d.config.Cipher == nil {
+		return 0, nil
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rewritten := 0
+	for _, table := range encryptedColumns {
+		n, err := d.encryptTable(ctx, tx, table.table, table.key, table.columns)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt %s: %w", table.table, err)
+		}
+		rewritten += n
+	}
+
+	if rewritten == 0 {
+		return 0, nil
+	}
+
+	// The search triggers replaced the documents of rewritten rows; merge the index
+	// so its old segments are dropped too
+	if _, err := tx.ExecContext(ctx, `INSERT INTO ticket_search (ticket_search) VALUES ('optimize')`); err != nil {
+		return 0, fmt.Errorf("failed to optimize search index: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if err := d.Vacuum(ctx); err != nil {
+		return rewritten, err
+	}
+	if _, err := d.db.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return rewritten, fmt.Errorf("failed to checkpoint write-ahead log: %w", err)
+	}
+
+	d.logger.Info("encrypted plaintext rows", "rows", rewritten)
+	return rewritten, nil
+}
+
+// encryptTable seals the plaintext values of columns in every row of table.
+func (d *Database) encryptTable(ctx context.Context, tx executor, table, key string, columns []string) (int, error) {
+	plaintext := make([]string, len(columns))
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		plaintext[i] = fmt.Sprintf("(%s <> '' AND substr(%s, 1, %d) <> '%s')", column, column, len(encryptedPrefix), encryptedPrefix)
+		assignments[i] = column + " = ?"
+	}
+
+	query := fmt.Sprintf(`SELECT %s, %s FROM %s WHERE %s`,
+		key, strings.Join(columns, ", "), table, strings.Join(plaintext, " OR "))
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	// Read everything before writing; the connection pool has a single connection
+	var updates [][]interface{}
+	for rows.Next() {
+		var id interface{}
+		values := make([]string, len(columns))
+		dest := []interface{}{&id}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		args := make([]interface{}, 0, len(columns)+1)
+		for _, value := range values {
+			if strings.HasPrefix(value, encryptedPrefix) {
+				args = append(args, value)
+				continue
+			}
+			sealed, err := d.config.Cipher.seal(value)
+			if err != nil {
+				rows.Close()
+				return 0, err
+			}
+			args = append(args, sealed)
+		}
+		updates = append(updates, append(args, id))
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+
+	update := fmt.Sprintf(`UPDATE %s SET %s WHERE %s = ?`, table, strings.Join(assignments, ", "), key)
+	for _, args := range updates {
+		if _, err := tx.ExecContext(ctx, update, args...); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(updates), nil
+}
@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func newTestCipher(t *testing.T, fill byte) *Cipher {
+	t.Helper()
+
+	c, err := NewCipher(bytes.Repeat([]byte{fill}, KeySize))
+	if err != nil {
+		t.Fatalf("NewCipher failed: %v", err)
+	}
+	return c
+}
+
+func TestCipher_SealOpen(t *testing.T) {
+	c := newTestCipher(t, 1)
+
+	sealed, err := c.seal("Confidential summary")
+	if err != nil {
+		t.Fatalf("seal failed: %v", err)
+	}
+	if !strings.HasPrefix(sealed, encryptedPrefix) || strings.Contains(sealed, "Confidential") {
+		t.Errorf("seal() = %q, want prefixed ciphertext", sealed)
+	}
+
+	opened, err := c.open(sealed)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	if opened != "Confidential summary" {
+		t.Errorf("open() = %q, want original value", opened)
+	}
+
+	// Plaintext from before encryption was enabled is read unchanged
+	if opened, err := c.open("legacy"); err != nil || opened != "legacy" {
+		t.Errorf("open(plaintext) = %q, %v", opened, err)
+	}
+
+	if sealed, _ := c.seal(""); sealed != "" {
+		t.Errorf("seal(\"\") = %q, want empty", sealed)
+	}
+
+	if _, err := newTestCipher(t, 2).open(sealed); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("open with wrong key error = %v, want ErrConfig", err)
+	}
+
+	var none *Cipher
+	if _, err := none.open(sealed); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("open without cipher error = %v, want ErrConfig", err)
+	}
+
+	if _, err := NewCipher([]byte("short")); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("NewCipher(short key) error = %v, want ErrConfig", err)
+	}
+}
+
+func TestTicketRepository_WithCipher(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	c := newTestCipher(t, 1)
+	repo := NewTicketRepository(db.DB(), nil).WithCipher(c)
+	ctx := context.Background()
+
+	ticket := newTestTicket(t, "JMD-1", "Secret launch plan")
+	ticket.Description = "Ships in March"
+	if err := repo.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	var summary, description string
+	if err := db.DB().QueryRow(`SELECT summary, description FROM tickets WHERE ticket_key = 'JMD-1'`).Scan(&summary, &description); err != nil {
+		t.Fatalf("failed to read raw row: %v", err)
+	}
+	if strings.Contains(summary, "Secret") || strings.Contains(description, "March") {
+		t.Errorf("ticket stored in plaintext: %q, %q", summary, description)
+	}
+
+	got, err := repo.FindByKey(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindByKey failed: %v", err)
+	}
+	if got.Summary != ticket.Summary || got.Description != ticket.Description {
+		t.Errorf("FindByKey() = %q, %q, want decrypted values", got.Summary, got.Description)
+	}
+
+	if _, err := NewTicketRepository(db.DB(), nil).FindByKey(ctx, "JMD-1"); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("FindByKey without cipher error = %v, want ErrConfig", err)
+	}
+
+	if _, err := NewSearchRepository(db.DB(), nil).WithCipher(c).Search(ctx, "launch", 10); !errors.Is(err, domain.ErrConfig) {
+		t.Errorf("Search with cipher error = %v, want ErrConfig", err)
+	}
+}
+
+func TestDatabase_EncryptPlaintext(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	ticket := newTestTicket(t, "JMD-1", "Secret launch plan")
+	if err := NewTicketRepository(db.DB(), nil).Save(ctx, ticket); err != nil {
+		t.Fatalf("Save ticket failed: %v", err)
+	}
+	comment := &domain.Comment{ID: "10001", TicketKey: ticket.Key, Author: "alice", Body: "Budget is tight", Created: ticket.Created, Updated: ticket.Updated}
+	if err := NewCommentRepository(db.DB(), nil).Save(ctx, comment); err != nil {
+		t.Fatalf("Save comment failed: %v", err)
+	}
+	fetched := fetchedTestTicket(t, time.Date(2024, 1, 3, 8, 0, 0, 0, time.UTC))
+	if err := NewFetchedTicketRepository(db.DB(), nil).SaveFetchedTicket(ctx, fetched); err != nil {
+		t.Fatalf("SaveFetchedTicket failed: %v", err)
+	}
+
+	c := newTestCipher(t, 1)
+	db.config.Cipher = c
+
+	rewritten, err := db.EncryptPlaintext(ctx)
+	if err != nil {
+		t.Fatalf("EncryptPlaintext failed: %v", err)
+	}
+	if rewritten != 3 {
+		t.Errorf("EncryptPlaintext() = %d, want 3", rewritten)
+	}
+
+	// Already encrypted rows are left alone
+	if rewritten, err := db.EncryptPlaintext(ctx); err != nil || rewritten != 0 {
+		t.Errorf("second EncryptPlaintext() = %d, %v, want 0", rewritten, err)
+	}
+
+	var indexed int
+	if err := db.DB().QueryRow(`SELECT count(*) FROM ticket_search WHERE ticket_search MATCH 'launch OR budget'`).Scan(&indexed); err != nil {
+		t.Fatalf("failed to query search index: %v", err)
+	}
+	if indexed != 0 {
+		t.Errorf("search index still holds plaintext of %d tickets", indexed)
+	}
+
+	got, err := NewCommentRepository(db.DB(), nil).WithCipher(c).FindByID(ctx, "10001")
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if got.Body != comment.Body {
+		t.Errorf("Body = %q, want %q", got.Body, comment.Body)
+	}
+
+	var stored string
+	if err := db.DB().QueryRow(`SELECT ticket FROM fetched_tickets WHERE ticket_key = 'JMD-1'`).Scan(&stored); err != nil {
+		t.Fatalf("failed to read fetched ticket: %v", err)
+	}
+	if strings.Contains(stored, fetched.Description) {
+		t.Errorf("fetched ticket is still stored in plaintext: %s", stored)
+	}
+	if got, err := NewFetchedTicketRepository(db.DB(), nil).WithCipher(c).FindFetchedTicket(ctx, "JMD-1", fetched.Updated); err != nil || got.Description != fetched.Description {
+		t.Errorf("FindFetchedTicket() = %+v, %v", got, err)
+	}
+}
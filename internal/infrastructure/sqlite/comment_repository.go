@@ -0,0 +1,219 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// CommentRepository implements repository.CommentRepository using SQLite.
+// Comments are cached alongside tickets and included in the full-text search index.
+type CommentRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+	cipher *Cipher
+}
+
+// NewCommentRepository creates a new SQLite-based comment repository.
+// The database connection must be initialized and migrations applied before use.
+func NewCommentRepository(db *sql.DB, logger *slog.Logger) *CommentRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CommentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithCipher makes the repository encrypt comment bodies at rest.
+func (r *CommentRepository) WithCipher(c *Cipher) *CommentRepository {
+	r.cipher = c
+	return r
+}
+
+// Verify that CommentRepository implements the repository.CommentRepository interface
+var _ repository.CommentRepository = (*CommentRepository)(nil)
+
+// commentColumns lists the columns read by every comment query, in scan order.
+const commentColumns = `comment_id, ticket_key, author, body, created, updated, visibility`
+
+// Save persists a comment to SQLite storage, replacing any cached copy.
+// Implements repository.CommentRepository.Save.
+func (r *CommentRepository) Save(ctx context.Context, comment *domain.Comment) error {
+	if comment == nil {
+		return fmt.Errorf("%w: comment cannot be nil", domain.ErrInvalidInput)
+	}
+	if err := comment.Validate(); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO comments (
+			comment_id,
+			ticket_key,
+			author,
+			body,
+			created,
+			updated,
+			visibility
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(comment_id) DO UPDATE SET
+			ticket_key = excluded.ticket_key,
+			author = excluded.author,
+			body = excluded.body,
+			created = excluded.created,
+			updated = excluded.updated,
+			visibility = excluded.visibility
+	`
+
+	body, err := r.cipher.seal(comment.Body)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt comment: %w", err)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	ticketKey := comment.TicketKey.String()
+	_, err = exec.ExecContext(ctx, query,
+		comment.ID,
+		ticketKey,
+		comment.Author,
+		body,
+		formatTimestamp(comment.Created),
+		formatTimestamp(comment.Updated),
+		comment.Visibility.String(),
+	)
+	if err != nil {
+		r.logger.Error("failed to save comment",
+			"comment_id", comment.ID,
+			"ticket_key", ticketKey,
+			"error", err)
+		return fmt.Errorf("failed to save comment: %w", err)
+	}
+
+	r.logger.Debug("saved comment", "comment_id", comment.ID, "ticket_key", ticketKey)
+	return nil
+}
+
+// FindByTicketKey retrieves all cached comments of a ticket, oldest first.
+// Implements repository.CommentRepository.FindByTicketKey.
+func (r *CommentRepository) FindByTicketKey(ctx context.Context, ticketKey string) ([]*domain.Comment, error) {
+	if ticketKey == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE ticket_key = ? ORDER BY created, comment_id`
+
+	rows, err := exec.QueryContext(ctx, query, ticketKey)
+	if err != nil {
+		r.logger.Error("failed to query comments",
+			"ticket_key", ticketKey,
+			"error", err)
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := make([]*domain.Comment, 0)
+	for rows.Next() {
+		comment, err := scanComment(rows, r.cipher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// FindByID retrieves a cached comment by its Jira ID.
+// Implements repository.CommentRepository.FindByID.
+func (r *CommentRepository) FindByID(ctx context.Context, id string) (*domain.Comment, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("%w: comment ID cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE comment_id = ?`
+
+	comment, err := scanComment(exec.QueryRowContext(ctx, query, id), r.cipher)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: comment %s is not in the local cache", domain.ErrNotFound, id)
+		}
+		r.logger.Error("failed to get comment",
+			"comment_id", id,
+			"error", err)
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// Delete removes a cached comment from SQLite storage.
+// Implements repository.CommentRepository.Delete.
+func (r *CommentRepository) Delete(ctx context.Context, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("%w: comment ID cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM comments WHERE comment_id = ?`, id)
+	if err != nil {
+		r.logger.Error("failed to delete comment",
+			"comment_id", id,
+			"error", err)
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	if err := requireRowsAffected(result, "comment "+id); err != nil {
+		return err
+	}
+
+	r.logger.Debug("deleted comment", "comment_id", id)
+	return nil
+}
+
+// scanComment reads one comment in commentColumns order, decrypting the body with c.
+func scanComment(row rowScanner, c *Cipher) (*domain.Comment, error) {
+	var (
+		key, created, updated, visibility string
+		comment                           domain.Comment
+	)
+
+	if err := row.Scan(&comment.ID, &key, &comment.Author, &comment.Body, &created, &updated, &visibility); err != nil {
+		return nil, err
+	}
+
+	if err := c.openAll(&comment.Body); err != nil {
+		return nil, fmt.Errorf("failed to decrypt comment %s: %w", comment.ID, err)
+	}
+
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cached ticket key: %w", err)
+	}
+	comment.TicketKey = ticketKey
+	comment.Created = parseTimestamp(created)
+	comment.Updated = parseTimestamp(updated)
+	if comment.Visibility, err = domain.ParseCommentVisibility(visibility); err != nil {
+		return nil, fmt.Errorf("invalid cached visibility of comment %s: %w", comment.ID, err)
+	}
+
+	return &comment, nil
+}
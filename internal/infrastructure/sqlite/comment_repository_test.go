@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestCommentRepository_SaveAndFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	first := newTestComment(t, "10001", "JMD-1", "First")
+	second := newTestComment(t, "10002", "JMD-1", "Second")
+	second.Created = first.Created.Add(1e9)
+	second.Visibility = domain.CommentVisibility{Type: domain.VisibilityRole, Value: "Developers", Internal: true}
+	other := newTestComment(t, "10003", "JMD-2", "Elsewhere")
+
+	for _, c := range []*domain.Comment{second, first, other} {
+		if err := repo.Save(ctx, c); err != nil {
+			t.Fatalf("Save(%s) failed: %v", c.ID, err)
+		}
+	}
+
+	got, err := repo.FindByID(ctx, "10001")
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if got.TicketKey != first.TicketKey || got.Author != "alice" || got.Body != "First" {
+		t.Errorf("comment mismatch: %+v", got)
+	}
+	if !got.Created.Equal(first.Created) || !got.Updated.Equal(first.Updated) {
+		t.Errorf("timestamps: got %v/%v, want %v/%v", got.Created, got.Updated, first.Created, first.Updated)
+	}
+
+	comments, err := repo.FindByTicketKey(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindByTicketKey failed: %v", err)
+	}
+	if len(comments) != 2 || comments[0].ID != "10001" || comments[1].ID != "10002" {
+		t.Fatalf("expected comments 10001, 10002 oldest first, got %d comments", len(comments))
+	}
+	if !comments[0].Visibility.IsPublic() || comments[1].Visibility != second.Visibility {
+		t.Errorf("visibility: got %+v/%+v, want public/%+v", comments[0].Visibility, comments[1].Visibility, second.Visibility)
+	}
+
+	none, err := repo.FindByTicketKey(ctx, "JMD-9")
+	if err != nil {
+		t.Fatalf("FindByTicketKey failed: %v", err)
+	}
+	if none == nil || len(none) != 0 {
+		t.Errorf("expected empty slice, got %v", none)
+	}
+}
+
+func TestCommentRepository_SaveReplaces(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	comment := newTestComment(t, "10001", "JMD-1", "Draft")
+	if err := repo.Save(ctx, comment); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	comment.Body = "Edited"
+	if err := repo.Save(ctx, comment); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := repo.FindByID(ctx, "10001")
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if got.Body != "Edited" {
+		t.Errorf("Body: got %q, want %q", got.Body, "Edited")
+	}
+}
+
+func TestCommentRepository_Errors(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	if err := repo.Save(ctx, nil); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Save(nil): expected ErrInvalidInput, got %v", err)
+	}
+	if _, err := repo.FindByID(ctx, "404"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("FindByID: expected ErrNotFound, got %v", err)
+	}
+	if err := repo.Delete(ctx, "404"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Delete: expected ErrNotFound, got %v", err)
+	}
+	if _, err := repo.FindByTicketKey(ctx, ""); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("FindByTicketKey(\"\"): expected ErrEmptyKey, got %v", err)
+	}
+}
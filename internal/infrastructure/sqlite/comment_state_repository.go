@@ -0,0 +1,139 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// CommentStateRepository implements repository.CommentStateRepository using SQLite.
+// States are removed with their ticket when it leaves the cache.
+type CommentStateRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewCommentStateRepository creates a new SQLite-based comment state repository.
+// The database connection must be initialized and migrations applied before use.
+func NewCommentStateRepository(db *sql.DB, logger *slog.Logger) *CommentStateRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CommentStateRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Verify that CommentStateRepository implements the repository.CommentStateRepository interface
+var _ repository.CommentStateRepository = (*CommentStateRepository)(nil)
+
+// ReplaceCommentStates replaces the recorded comment states of a ticket, in one transaction.
+// Implements repository.CommentStateRepository.ReplaceCommentStates.
+func (r *CommentStateRepository) ReplaceCommentStates(ctx context.Context, ticketKey string, states []domain.CommentState) error {
+	if strings.TrimSpace(ticketKey) == "" {
+		return fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	// Replace in transaction if not already in one
+	inTransaction := transactionFromContext(ctx) != nil
+	if !inTransaction {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		exec = tx
+	}
+
+	if _, err := exec.ExecContext(ctx, `DELETE FROM comment_states WHERE ticket_key = ?`, ticketKey); err != nil {
+		r.logger.Error("failed to clear comment states", "ticket_key", ticketKey, "error", err)
+		return fmt.Errorf("failed to clear comment states: %w", err)
+	}
+
+	query := `
+		INSERT INTO comment_states (
+			ticket_key,
+			comment_id,
+			seq,
+			updated_at,
+			content_hash
+		) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(ticket_key, comment_id) DO UPDATE SET
+			seq = excluded.seq,
+			updated_at = excluded.updated_at,
+			content_hash = excluded.content_hash
+	`
+	for i, state := range states {
+		if strings.TrimSpace(state.ID) == "" {
+			return fmt.Errorf("%w: comment ID cannot be empty", domain.ErrInvalidInput)
+		}
+		_, err := exec.ExecContext(ctx, query,
+			ticketKey,
+			state.ID,
+			i,
+			formatTimestamp(state.Updated),
+			state.Hash,
+		)
+		if err != nil {
+			r.logger.Error("failed to save comment state", "ticket_key", ticketKey, "comment_id", state.ID, "error", err)
+			return fmt.Errorf("failed to save comment state: %w", err)
+		}
+	}
+
+	// Commit if we started the transaction
+	if !inTransaction {
+		if err := exec.(*sql.Tx).Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	r.logger.Debug("saved comment states", "ticket_key", ticketKey, "comments", len(states))
+	return nil
+}
+
+// FindCommentStates retrieves the recorded comment states of a ticket, in order.
+// Implements repository.CommentStateRepository.FindCommentStates.
+func (r *CommentStateRepository) FindCommentStates(ctx context.Context, ticketKey string) ([]domain.CommentState, error) {
+	if strings.TrimSpace(ticketKey) == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+	rows, err := exec.QueryContext(ctx, `
+		SELECT comment_id, updated_at, content_hash FROM comment_states
+		WHERE ticket_key = ? ORDER BY seq
+	`, ticketKey)
+	if err != nil {
+		r.logger.Error("failed to query comment states", "ticket_key", ticketKey, "error", err)
+		return nil, fmt.Errorf("failed to query comment states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make([]domain.CommentState, 0)
+	for rows.Next() {
+		var (
+			state   domain.CommentState
+			updated string
+		)
+		if err := rows.Scan(&state.ID, &updated, &state.Hash); err != nil {
+			return nil, fmt.Errorf("failed to scan comment state: %w", err)
+		}
+		state.Updated = parseTimestamp(updated)
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate comment states: %w", err)
+	}
+
+	return states, nil
+}
@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestCommentStateRepository_ReplaceAndFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewCommentStateRepository(db.DB(), nil)
+	ctx := context.Background()
+	at := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+
+	states := []domain.CommentState{
+		{ID: "10002", Updated: at.Add(time.Hour), Hash: "b"},
+		{ID: "10001", Updated: at, Hash: "a"},
+	}
+	if err := repo.ReplaceCommentStates(ctx, "JMD-1", states); err != nil {
+		t.Fatalf("ReplaceCommentStates failed: %v", err)
+	}
+
+	got, err := repo.FindCommentStates(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindCommentStates failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, states) {
+		t.Errorf("FindCommentStates() = %+v, want %+v in recorded order", got, states)
+	}
+
+	// Replacing drops comments deleted since
+	if err := repo.ReplaceCommentStates(ctx, "JMD-1", states[1:]); err != nil {
+		t.Fatalf("ReplaceCommentStates failed: %v", err)
+	}
+	got, err = repo.FindCommentStates(ctx, "JMD-1")
+	if err != nil || len(got) != 1 || got[0].ID != "10001" {
+		t.Errorf("FindCommentStates() = %+v, %v; want only 10001", got, err)
+	}
+
+	none, err := repo.FindCommentStates(ctx, "JMD-9")
+	if err != nil {
+		t.Fatalf("FindCommentStates failed: %v", err)
+	}
+	if none == nil || len(none) != 0 {
+		t.Errorf("expected empty slice, got %v", none)
+	}
+
+	if err := repo.ReplaceCommentStates(ctx, " ", nil); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("ReplaceCommentStates(empty key) error = %v, want ErrEmptyKey", err)
+	}
+	if err := repo.ReplaceCommentStates(ctx, "JMD-1", []domain.CommentState{{Updated: at}}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("ReplaceCommentStates(no ID) error = %v, want ErrInvalidInput", err)
+	}
+	if got, _ := repo.FindCommentStates(ctx, "JMD-1"); len(got) != 1 {
+		t.Errorf("failed replace changed the states: %+v", got)
+	}
+}
+
+func TestCommentStateRepository_DeletedWithTicket(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tickets := NewTicketRepository(db.DB(), nil)
+	repo := NewCommentStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	ticket := newTestTicket(t, "JMD-1", "Comments")
+	if err := tickets.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	state := domain.CommentState{ID: "10001", Updated: ticket.Created, Hash: "a"}
+	if err := repo.ReplaceCommentStates(ctx, "JMD-1", []domain.CommentState{state}); err != nil {
+		t.Fatalf("ReplaceCommentStates failed: %v", err)
+	}
+
+	if err := tickets.Delete(ctx, "JMD-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	got, err := repo.FindCommentStates(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindCommentStates failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected the comment states to be deleted with the ticket, got %+v", got)
+	}
+}
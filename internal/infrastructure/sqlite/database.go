@@ -26,6 +26,9 @@ type DatabaseConfig struct {
 
 	// BusyTimeout is how long to wait for a lock before returning SQLITE_BUSY
 	BusyTimeout time.Duration
+
+	// Cipher encrypts sensitive columns at rest (nil stores them in plaintext)
+	Cipher *Cipher
 }
 
 // DefaultConfig returns the default database configuration.
@@ -120,6 +123,12 @@ func (d *Database) DB() *sql.DB {
 	return d.db
 }
 
+// Cipher returns the cipher that repositories use for sensitive columns, or nil
+// when encryption at rest is not enabled.
+func (d *Database) Cipher() *Cipher {
+	return d.config.Cipher
+}
+
 // Close closes the database connection.
 func (d *Database) Close() error {
 	if d.db != nil {
@@ -149,6 +158,16 @@ func (d *Database) Health(ctx context.Context) error {
 	return nil
 }
 
+// Vacuum rebuilds the database file, returning the space freed by deleted rows to the
+// file system. SQLite otherwise only reuses freed pages, so the file never shrinks.
+func (d *Database) Vacuum(ctx context.Context) error {
+	if _, err := d.db.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("vacuum failed: %w", err)
+	}
+	d.logger.Info("vacuumed database")
+	return nil
+}
+
 // Stats returns database statistics.
 func (d *Database) Stats() sql.DBStats {
 	return d.db.Stats()
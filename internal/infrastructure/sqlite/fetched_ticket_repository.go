@@ -0,0 +1,216 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// FetchedTicketRepository implements repository.FetchedTicketRepository using SQLite.
+// Each ticket is stored as one JSON document with its comments and status history, next
+// to the Jira revision it was fetched at, since it is always read and replaced as a whole.
+type FetchedTicketRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+	cipher *Cipher
+}
+
+// NewFetchedTicketRepository creates a new SQLite-based fetched ticket repository.
+// The database connection must be initialized and migrations applied before use.
+func NewFetchedTicketRepository(db *sql.DB, logger *slog.Logger) *FetchedTicketRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &FetchedTicketRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithCipher makes the repository encrypt the cached tickets at rest.
+func (r *FetchedTicketRepository) WithCipher(c *Cipher) *FetchedTicketRepository {
+	r.cipher = c
+	return r
+}
+
+// Verify that FetchedTicketRepository implements the repository.FetchedTicketRepository interface
+var _ repository.FetchedTicketRepository = (*FetchedTicketRepository)(nil)
+
+// fetchedTicketDocument is the JSON stored in the ticket column.
+type fetchedTicketDocument struct {
+	Summary      string                 `json:"summary"`
+	Description  string                 `json:"description"`
+	Status       string                 `json:"status"`
+	IssueType    string                 `json:"issue_type"`
+	Priority     string                 `json:"priority"`
+	Assignee     string                 `json:"assignee"`
+	Reporter     string                 `json:"reporter"`
+	Labels       []string               `json:"labels"`
+	Created      time.Time              `json:"created"`
+	Updated      time.Time              `json:"updated"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+
+	// Comments and StatusHistory are nil when the ticket was fetched without them
+	Comments      []fetchedComment      `json:"comments"`
+	StatusHistory []domain.StatusChange `json:"status_history"`
+}
+
+// fetchedComment is a comment in a fetchedTicketDocument.
+type fetchedComment struct {
+	ID         string                   `json:"id"`
+	Author     string                   `json:"author"`
+	Body       string                   `json:"body"`
+	Created    time.Time                `json:"created"`
+	Updated    time.Time                `json:"updated"`
+	Visibility domain.CommentVisibility `json:"visibility"`
+
+	AuthorProfile *domain.User `json:"author_profile,omitempty"`
+}
+
+// SaveFetchedTicket inserts or replaces the cached copy of a ticket fetched from Jira.
+// Implements repository.FetchedTicketRepository.SaveFetchedTicket.
+func (r *FetchedTicketRepository) SaveFetchedTicket(ctx context.Context, ticket *domain.Ticket) error {
+	if ticket == nil {
+		return fmt.Errorf("%w: ticket cannot be nil", domain.ErrInvalidInput)
+	}
+	if strings.TrimSpace(ticket.Key.String()) == "" {
+		return fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+	if ticket.Updated.IsZero() {
+		return fmt.Errorf("%w: ticket %s has no updated timestamp", domain.ErrInvalidInput, ticket.Key)
+	}
+
+	document := fetchedTicketDocument{
+		Summary:     ticket.Summary,
+		Description: ticket.Description,
+		Status:      ticket.Status,
+		IssueType:   ticket.IssueType,
+		Priority:    ticket.Priority,
+		Assignee:    ticket.Assignee,
+		Reporter:    ticket.Reporter,
+		Labels:      ticket.Labels,
+		Created:     ticket.Created,
+		Updated:     ticket.Updated,
+
+		StatusHistory: ticket.StatusHistory,
+	}
+	if len(ticket.CustomFields) > 0 {
+		document.CustomFields = make(map[string]interface{}, len(ticket.CustomFields))
+		for name, value := range ticket.CustomFields {
+			document.CustomFields[name] = value.Raw()
+		}
+	}
+	if ticket.Comments != nil {
+		document.Comments = make([]fetchedComment, 0, len(ticket.Comments))
+		for _, comment := range ticket.Comments {
+			document.Comments = append(document.Comments, fetchedComment{
+				ID:            comment.ID,
+				Author:        comment.Author,
+				Body:          comment.Body,
+				Created:       comment.Created,
+				Updated:       comment.Updated,
+				Visibility:    comment.Visibility,
+				AuthorProfile: comment.AuthorProfile,
+			})
+		}
+	}
+
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to encode fetched ticket: %w", err)
+	}
+	sealed, err := r.cipher.seal(string(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt fetched ticket: %w", err)
+	}
+
+	exec := executorFor(ctx, r.db)
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO fetched_tickets (ticket_key, jira_updated, ticket, fetched_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(ticket_key) DO UPDATE SET
+			jira_updated = excluded.jira_updated,
+			ticket = excluded.ticket,
+			fetched_at = excluded.fetched_at
+	`, ticket.Key.String(), ticket.Version(), sealed, formatTimestamp(time.Now()))
+	if err != nil {
+		r.logger.Error("failed to save fetched ticket", "ticket_key", ticket.Key.String(), "error", err)
+		return fmt.Errorf("failed to save fetched ticket: %w", err)
+	}
+
+	r.logger.Debug("saved fetched ticket", "ticket_key", ticket.Key.String(), "jira_updated", ticket.Version())
+	return nil
+}
+
+// FindFetchedTicket retrieves the cached copy of a ticket fetched at the revision updated.
+// Implements repository.FetchedTicketRepository.FindFetchedTicket.
+func (r *FetchedTicketRepository) FindFetchedTicket(ctx context.Context, key string, updated time.Time) (*domain.Ticket, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, err
+	}
+	version := (&domain.Ticket{Updated: updated}).Version()
+
+	var encoded string
+	exec := executorFor(ctx, r.db)
+	err = exec.QueryRowContext(ctx, `
+		SELECT ticket FROM fetched_tickets WHERE ticket_key = ? AND jira_updated = ?
+	`, key, version).Scan(&encoded)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: ticket %s is not cached at revision %s", domain.ErrNotFound, key, version)
+	}
+	if err != nil {
+		r.logger.Error("failed to find fetched ticket", "ticket_key", key, "error", err)
+		return nil, fmt.Errorf("failed to find fetched ticket: %w", err)
+	}
+
+	if err := r.cipher.openAll(&encoded); err != nil {
+		return nil, fmt.Errorf("failed to decrypt fetched ticket %s: %w", key, err)
+	}
+	var document fetchedTicketDocument
+	if err := json.Unmarshal([]byte(encoded), &document); err != nil {
+		return nil, fmt.Errorf("failed to decode fetched ticket %s: %w", key, err)
+	}
+
+	ticket := domain.NewTicket(ticketKey, document.Summary, document.Created, document.Updated)
+	ticket.Description = document.Description
+	ticket.Status = document.Status
+	ticket.IssueType = document.IssueType
+	ticket.Priority = document.Priority
+	ticket.Assignee = document.Assignee
+	ticket.Reporter = document.Reporter
+	if document.Labels != nil {
+		ticket.Labels = document.Labels
+	}
+	for name, value := range document.CustomFields {
+		ticket.CustomFields[name] = domain.NewFieldValue(value)
+	}
+	ticket.StatusHistory = document.StatusHistory
+	if document.Comments != nil {
+		ticket.Comments = make([]*domain.Comment, 0, len(document.Comments))
+		for _, comment := range document.Comments {
+			ticket.Comments = append(ticket.Comments, &domain.Comment{
+				ID:            comment.ID,
+				TicketKey:     ticketKey,
+				Author:        comment.Author,
+				Body:          comment.Body,
+				Created:       comment.Created,
+				Updated:       comment.Updated,
+				Visibility:    comment.Visibility,
+				AuthorProfile: comment.AuthorProfile,
+			})
+		}
+	}
+	return ticket, nil
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// fetchedTestTicket returns a ticket as fetched from Jira, with comments and history.
+func fetchedTestTicket(t *testing.T, updated time.Time) *domain.Ticket {
+	t.Helper()
+
+	key, err := domain.NewTicketKey("JMD-1")
+	if err != nil {
+		t.Fatalf("NewTicketKey failed: %v", err)
+	}
+	ticket := domain.NewTicket(key, "Fix login", time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), updated)
+	ticket.Description = "Users cannot log in"
+	ticket.Status = "In Progress"
+	ticket.Assignee = "alice@example.com"
+	ticket.Labels = []string{"auth"}
+	ticket.CustomFields["team"] = domain.NewFieldValue("Platform")
+	ticket.Comments = []*domain.Comment{{
+		ID:            "10001",
+		TicketKey:     key,
+		Author:        "bob@example.com",
+		Body:          "Looking into it",
+		Created:       time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+		Updated:       time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+		Visibility:    domain.CommentVisibility{Type: domain.VisibilityRole, Value: "Developers"},
+		AuthorProfile: &domain.User{AccountID: "acc-bob", DisplayName: "Bob", Email: "bob@example.com", Active: true},
+	}}
+	ticket.StatusHistory = []domain.StatusChange{
+		{ID: "1", From: "To Do", To: "In Progress", Author: "alice@example.com", At: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+	}
+	return ticket
+}
+
+func TestFetchedTicketRepository_SaveAndFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFetchedTicketRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	updated := time.Date(2024, 1, 3, 8, 15, 30, 123000000, time.UTC)
+	ticket := fetchedTestTicket(t, updated)
+	if err := repo.SaveFetchedTicket(ctx, ticket); err != nil {
+		t.Fatalf("SaveFetchedTicket failed: %v", err)
+	}
+
+	got, err := repo.FindFetchedTicket(ctx, "JMD-1", updated)
+	if err != nil {
+		t.Fatalf("FindFetchedTicket failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, ticket) {
+		t.Errorf("FindFetchedTicket() = %+v, want %+v", got, ticket)
+	}
+
+	// Another revision is a miss, even an earlier one
+	for _, other := range []time.Time{updated.Add(time.Millisecond), updated.Add(-time.Hour)} {
+		if _, err := repo.FindFetchedTicket(ctx, "JMD-1", other); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("FindFetchedTicket(%v) error = %v, want ErrNotFound", other, err)
+		}
+	}
+	if _, err := repo.FindFetchedTicket(ctx, "JMD-2", updated); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("FindFetchedTicket(uncached) error = %v, want ErrNotFound", err)
+	}
+
+	// Fetching a newer revision replaces the entry
+	newer := fetchedTestTicket(t, updated.Add(time.Hour))
+	newer.Status = "Done"
+	newer.Comments = nil
+	if err := repo.SaveFetchedTicket(ctx, newer); err != nil {
+		t.Fatalf("SaveFetchedTicket failed: %v", err)
+	}
+	if _, err := repo.FindFetchedTicket(ctx, "JMD-1", updated); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("FindFetchedTicket(replaced revision) error = %v, want ErrNotFound", err)
+	}
+	got, err = repo.FindFetchedTicket(ctx, "JMD-1", newer.Updated)
+	if err != nil || got.Status != "Done" || got.Comments != nil {
+		t.Errorf("FindFetchedTicket(newer) = %+v, %v; want Done without comments loaded", got, err)
+	}
+}
+
+func TestFetchedTicketRepository_Invalid(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFetchedTicketRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	if err := repo.SaveFetchedTicket(ctx, nil); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("SaveFetchedTicket(nil) error = %v, want ErrInvalidInput", err)
+	}
+	if err := repo.SaveFetchedTicket(ctx, fetchedTestTicket(t, time.Time{})); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("SaveFetchedTicket(no updated) error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := repo.FindFetchedTicket(ctx, "", time.Now()); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("FindFetchedTicket(\"\") error = %v, want ErrEmptyKey", err)
+	}
+}
+
+func TestFetchedTicketRepository_Encrypted(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewFetchedTicketRepository(db.DB(), nil).WithCipher(newTestCipher(t, 1))
+	ctx := context.Background()
+
+	updated := time.Date(2024, 1, 3, 8, 0, 0, 0, time.UTC)
+	if err := repo.SaveFetchedTicket(ctx, fetchedTestTicket(t, updated)); err != nil {
+		t.Fatalf("SaveFetchedTicket failed: %v", err)
+	}
+
+	var stored string
+	if err := db.DB().QueryRow(`SELECT ticket FROM fetched_tickets WHERE ticket_key = 'JMD-1'`).Scan(&stored); err != nil {
+		t.Fatalf("failed to read stored ticket: %v", err)
+	}
+	if strings.Contains(stored, "Users cannot log in") {
+		t.Errorf("stored ticket is not encrypted: %s", stored)
+	}
+
+	got, err := repo.FindFetchedTicket(ctx, "JMD-1", updated)
+	if err != nil || got.Description != "Users cannot log in" {
+		t.Errorf("FindFetchedTicket() = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,196 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// InboxRepository implements repository.InboxRepository using SQLite.
+// Payloads carry ticket content, so they are encrypted at rest like the ticket cache.
+type InboxRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+	cipher *Cipher
+}
+
+// NewInboxRepository creates a new SQLite-based inbox repository.
+// The database connection must be initialized and migrations applied before use.
+func NewInboxRepository(db *sql.DB, logger *slog.Logger) *InboxRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &InboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithCipher makes the repository encrypt event payloads at rest.
+func (r *InboxRepository) WithCipher(c *Cipher) *InboxRepository {
+	r.cipher = c
+	return r
+}
+
+// Verify that InboxRepository implements the repository.InboxRepository interface
+var _ repository.InboxRepository = (*InboxRepository)(nil)
+
+// AddInboxEvent stores a received event unless an event with its ID is already stored.
+// Implements repository.InboxRepository.AddInboxEvent.
+func (r *InboxRepository) AddInboxEvent(ctx context.Context, event *domain.InboxEvent) (bool, error) {
+	if event == nil {
+		return false, fmt.Errorf("%w: inbox event cannot be nil", domain.ErrInvalidInput)
+	}
+	if err := event.Validate(); err != nil {
+		return false, err
+	}
+
+	payload, err := r.cipher.seal(string(event.Payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to encrypt inbox event payload: %w", err)
+	}
+
+	exec := executorFor(ctx, r.db)
+	result, err := exec.ExecContext(ctx, `
+		INSERT INTO inbox (
+			event_id,
+			event_type,
+			ticket_key,
+			payload,
+			received_at
+		) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(event_id) DO NOTHING
+	`,
+		event.ID,
+		event.Type,
+		event.TicketKey.String(),
+		payload,
+		formatTimestamp(event.ReceivedAt),
+	)
+	if err != nil {
+		r.logger.Error("failed to add inbox event", "event_id", event.ID, "error", err)
+		return false, fmt.Errorf("failed to add inbox event: %w", err)
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.Debug("received inbox event", "event_id", event.ID, "type", event.Type, "duplicate", added == 0)
+	return added > 0, nil
+}
+
+// FindPendingInboxEvents retrieves up to limit unprocessed events, oldest first.
+// Implements repository.InboxRepository.FindPendingInboxEvents.
+func (r *InboxRepository) FindPendingInboxEvents(ctx context.Context, limit int) ([]*domain.InboxEvent, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", domain.ErrInvalidInput)
+	}
+
+	exec := executorFor(ctx, r.db)
+	rows, err := exec.QueryContext(ctx, `
+		SELECT event_id, event_type, ticket_key, payload, received_at FROM inbox
+		WHERE processed_at IS NULL
+		ORDER BY received_at, rowid
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		r.logger.Error("failed to query pending inbox events", "error", err)
+		return nil, fmt.Errorf("failed to query pending inbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*domain.InboxEvent, 0)
+	for rows.Next() {
+		event := &domain.InboxEvent{}
+		var ticketKey, payload, receivedAt string
+		if err := rows.Scan(&event.ID, &event.Type, &ticketKey, &payload, &receivedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan inbox event: %w", err)
+		}
+		if ticketKey != "" {
+			if event.TicketKey, err = domain.NewTicketKey(ticketKey); err != nil {
+				return nil, fmt.Errorf("failed to parse ticket key of inbox event %s: %w", event.ID, err)
+			}
+		}
+		if err := r.cipher.openAll(&payload); err != nil {
+			return nil, err
+		}
+		event.Payload = []byte(payload)
+		event.ReceivedAt = parseTimestamp(receivedAt)
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate inbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkInboxEventsProcessed records that the events with the given IDs were processed at
+// at, in one transaction.
+// Implements repository.InboxRepository.MarkInboxEventsProcessed.
+func (r *InboxRepository) MarkInboxEventsProcessed(ctx context.Context, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	// Update in transaction if not already in one
+	inTransaction := transactionFromContext(ctx) != nil
+	if !inTransaction {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		exec = tx
+	}
+
+	for _, id := range ids {
+		_, err := exec.ExecContext(ctx, `UPDATE inbox SET processed_at = ? WHERE event_id = ?`, formatTimestamp(at), id)
+		if err != nil {
+			r.logger.Error("failed to mark inbox event processed", "event_id", id, "error", err)
+			return fmt.Errorf("failed to mark inbox event processed: %w", err)
+		}
+	}
+
+	// Commit if we started the transaction
+	if !inTransaction {
+		if err := exec.(*sql.Tx).Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	r.logger.Debug("marked inbox events processed", "events", len(ids))
+	return nil
+}
+
+// PruneInboxEvents removes events processed before the given time.
+// Implements repository.InboxRepository.PruneInboxEvents.
+func (r *InboxRepository) PruneInboxEvents(ctx context.Context, before time.Time) (int, error) {
+	exec := executorFor(ctx, r.db)
+
+	result, err := exec.ExecContext(ctx,
+		`DELETE FROM inbox WHERE processed_at IS NOT NULL AND processed_at < ?`, formatTimestamp(before))
+	if err != nil {
+		r.logger.Error("failed to prune inbox events", "before", before, "error", err)
+		return 0, fmt.Errorf("failed to prune inbox events: %w", err)
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.Debug("pruned inbox events", "before", before, "count", pruned)
+	return int(pruned), nil
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestInboxRepository_AddAndProcess(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewInboxRepository(db.DB(), nil).WithCipher(newTestCipher(t, 1))
+	ctx := context.Background()
+
+	key, _ := domain.NewTicketKey("JMD-1")
+	at := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	updated := domain.NewInboxEvent("delivery-1", "jira:issue_updated", key, []byte(`{"issue":{"key":"JMD-1"}}`), at)
+	sprint := domain.NewInboxEvent("delivery-2", "sprint_started", domain.TicketKey{}, []byte(`{}`), at.Add(time.Second))
+	for _, event := range []*domain.InboxEvent{sprint, updated} {
+		added, err := repo.AddInboxEvent(ctx, event)
+		if err != nil || !added {
+			t.Fatalf("AddInboxEvent(%s) = %v, %v; want added", event.ID, added, err)
+		}
+	}
+
+	// A redelivery is recognized by its ID
+	if added, err := repo.AddInboxEvent(ctx, updated); err != nil || added {
+		t.Errorf("AddInboxEvent(redelivery) = %v, %v; want not added", added, err)
+	}
+
+	// The payload is encrypted at rest
+	var stored string
+	if err := db.DB().QueryRowContext(ctx, `SELECT payload FROM inbox WHERE event_id = ?`, updated.ID).Scan(&stored); err != nil {
+		t.Fatalf("query payload failed: %v", err)
+	}
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		t.Errorf("stored payload = %q, want encrypted", stored)
+	}
+
+	pending, err := repo.FindPendingInboxEvents(ctx, 10)
+	if err != nil {
+		t.Fatalf("FindPendingInboxEvents failed: %v", err)
+	}
+	if !reflect.DeepEqual(pending, []*domain.InboxEvent{updated, sprint}) {
+		t.Errorf("FindPendingInboxEvents() = %+v, want oldest first", pending)
+	}
+	if limited, err := repo.FindPendingInboxEvents(ctx, 1); err != nil || len(limited) != 1 {
+		t.Errorf("FindPendingInboxEvents(1) = %d events, %v; want 1", len(limited), err)
+	}
+
+	processedAt := at.Add(time.Minute)
+	if err := repo.MarkInboxEventsProcessed(ctx, []string{updated.ID}, processedAt); err != nil {
+		t.Fatalf("MarkInboxEventsProcessed failed: %v", err)
+	}
+	if pending, err := repo.FindPendingInboxEvents(ctx, 10); err != nil || len(pending) != 1 || pending[0].ID != sprint.ID {
+		t.Errorf("FindPendingInboxEvents() after processing = %+v, %v; want only %s", pending, err, sprint.ID)
+	}
+
+	// Processed events still deduplicate until pruned
+	if added, err := repo.AddInboxEvent(ctx, updated); err != nil || added {
+		t.Errorf("AddInboxEvent(processed redelivery) = %v, %v; want not added", added, err)
+	}
+	if pruned, err := repo.PruneInboxEvents(ctx, processedAt); err != nil || pruned != 0 {
+		t.Errorf("PruneInboxEvents(at processing) = %d, %v; want 0", pruned, err)
+	}
+	if pruned, err := repo.PruneInboxEvents(ctx, processedAt.Add(time.Second)); err != nil || pruned != 1 {
+		t.Errorf("PruneInboxEvents(after processing) = %d, %v; want 1", pruned, err)
+	}
+	if added, err := repo.AddInboxEvent(ctx, updated); err != nil || !added {
+		t.Errorf("AddInboxEvent(after prune) = %v, %v; want added", added, err)
+	}
+}
+
+func TestInboxRepository_InvalidInput(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewInboxRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	if _, err := repo.AddInboxEvent(ctx, nil); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("AddInboxEvent(nil) error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := repo.AddInboxEvent(ctx, &domain.InboxEvent{ID: "1"}); !errors.Is(err, domain.ErrInvalidTimestamp) {
+		t.Errorf("AddInboxEvent(no receipt time) error = %v, want ErrInvalidTimestamp", err)
+	}
+	if _, err := repo.FindPendingInboxEvents(ctx, 0); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("FindPendingInboxEvents(0) error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// LocalVersionRepository implements repository.LocalVersionRepository using SQLite.
+// Records are kept when their ticket leaves the cache, since the copies they point to
+// are the only trace of the local changes.
+type LocalVersionRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewLocalVersionRepository creates a new SQLite-based local version repository.
+// The database connection must be initialized and migrations applied before use.
+func NewLocalVersionRepository(db *sql.DB, logger *slog.Logger) *LocalVersionRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LocalVersionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Verify that LocalVersionRepository implements the repository.LocalVersionRepository interface
+var _ repository.LocalVersionRepository = (*LocalVersionRepository)(nil)
+
+// SaveLocalVersion records a local version saved before a pull overwrote its file.
+// Implements repository.LocalVersionRepository.SaveLocalVersion.
+func (r *LocalVersionRepository) SaveLocalVersion(ctx context.Context, version *domain.LocalVersion) error {
+	if version == nil {
+		return fmt.Errorf("%w: local version cannot be nil", domain.ErrInvalidInput)
+	}
+	if err := version.Validate(); err != nil {
+		return err
+	}
+
+	exec := executorFor(ctx, r.db)
+	_, err := exec.ExecContext(ctx, `
+		INSERT INTO local_versions (
+			ticket_key,
+			path,
+			content_hash,
+			jira_version,
+			saved_at
+		) VALUES (?, ?, ?, ?, ?)
+	`,
+		version.TicketKey.String(),
+		version.Path,
+		version.ContentHash,
+		version.JiraVersion,
+		formatTimestamp(version.SavedAt),
+	)
+	if err != nil {
+		r.logger.Error("failed to save local version", "ticket_key", version.TicketKey.String(), "error", err)
+		return fmt.Errorf("failed to save local version: %w", err)
+	}
+
+	r.logger.Debug("saved local version", "ticket_key", version.TicketKey.String(), "path", version.Path)
+	return nil
+}
+
+// FindLocalVersions retrieves the local versions saved of a ticket, newest first.
+// Implements repository.LocalVersionRepository.FindLocalVersions.
+func (r *LocalVersionRepository) FindLocalVersions(ctx context.Context, ticketKey string) ([]*domain.LocalVersion, error) {
+	if strings.TrimSpace(ticketKey) == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+	key, err := domain.NewTicketKey(ticketKey)
+	if err != nil {
+		return nil, err
+	}
+
+	exec := executorFor(ctx, r.db)
+	rows, err := exec.QueryContext(ctx, `
+		SELECT path, content_hash, jira_version, saved_at FROM local_versions
+		WHERE ticket_key = ? ORDER BY saved_at DESC, id DESC
+	`, ticketKey)
+	if err != nil {
+		r.logger.Error("failed to query local versions", "ticket_key", ticketKey, "error", err)
+		return nil, fmt.Errorf("failed to query local versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]*domain.LocalVersion, 0)
+	for rows.Next() {
+		version := &domain.LocalVersion{TicketKey: key}
+		var savedAt string
+		if err := rows.Scan(&version.Path, &version.ContentHash, &version.JiraVersion, &savedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan local version: %w", err)
+		}
+		version.SavedAt = parseTimestamp(savedAt)
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate local versions: %w", err)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestLocalVersionRepository_SaveAndFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tickets := NewTicketRepository(db.DB(), nil)
+	repo := NewLocalVersionRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	ticket := newTestTicket(t, "JMD-1", "Overwritten")
+	if err := tickets.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	at := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	older := domain.NewLocalVersion(ticket.Key, "a.md", []byte("first"), "2024-01-02T08:00:00Z", at)
+	newer := domain.NewLocalVersion(ticket.Key, "b.md", []byte("second"), "2024-01-03T08:00:00Z", at.Add(24*time.Hour))
+	for _, version := range []*domain.LocalVersion{older, newer} {
+		if err := repo.SaveLocalVersion(ctx, version); err != nil {
+			t.Fatalf("SaveLocalVersion failed: %v", err)
+		}
+	}
+
+	got, err := repo.FindLocalVersions(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindLocalVersions failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, []*domain.LocalVersion{newer, older}) {
+		t.Errorf("FindLocalVersions() = %+v, want newest first", got)
+	}
+
+	// The record outlives the ticket
+	if err := tickets.Delete(ctx, "JMD-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if got, err := repo.FindLocalVersions(ctx, "JMD-1"); err != nil || len(got) != 2 {
+		t.Errorf("FindLocalVersions() after delete = %d versions, %v; want 2", len(got), err)
+	}
+
+	none, err := repo.FindLocalVersions(ctx, "JMD-9")
+	if err != nil {
+		t.Fatalf("FindLocalVersions failed: %v", err)
+	}
+	if none == nil || len(none) != 0 {
+		t.Errorf("expected empty slice, got %v", none)
+	}
+}
+
+func TestLocalVersionRepository_Invalid(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewLocalVersionRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	if err := repo.SaveLocalVersion(ctx, nil); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("SaveLocalVersion(nil) error = %v, want ErrInvalidInput", err)
+	}
+	if err := repo.SaveLocalVersion(ctx, &domain.LocalVersion{SavedAt: time.Now()}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("SaveLocalVersion(no key) error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := repo.FindLocalVersions(ctx, ""); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("FindLocalVersions(\"\") error = %v, want ErrEmptyKey", err)
+	}
+}
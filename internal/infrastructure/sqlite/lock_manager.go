@@ -0,0 +1,157 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// DefaultLockLease is how long a ticket lock is held before it expires, in case
+// the process holding it crashes without releasing it.
+const DefaultLockLease = 2 * time.Minute
+
+// lockPollInterval is how often a blocked Lock call retries.
+const lockPollInterval = 50 * time.Millisecond
+
+// LockManager implements repository.LockManager with leases in the ticket_locks table.
+// Because the leases live in the database file, they serialize every goroutine and
+// process that shares it: the daemon's watcher and poller as well as CLI commands.
+//
+// A lock is held for at most the lease duration; work on a single ticket is expected
+// to finish well within it.
+type LockManager struct {
+	db     *sql.DB
+	logger *slog.Logger
+	lease  time.Duration
+
+	// now is the clock used for lease expiry (overridable in tests)
+	now func() time.Time
+}
+
+// NewLockManager creates a new SQLite-based lock manager with DefaultLockLease.
+// The database connection must be initialized and migrations applied before use.
+func NewLockManager(db *sql.DB, logger *slog.Logger) *LockManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LockManager{
+		db:     db,
+		logger: logger,
+		lease:  DefaultLockLease,
+		now:    time.Now,
+	}
+}
+
+// Verify that LockManager implements the repository.LockManager interface
+var _ repository.LockManager = (*LockManager)(nil)
+
+// Lock blocks until it holds the lock on ticketKey or ctx is done.
+// Implements repository.LockManager.Lock.
+func (m *LockManager) Lock(ctx context.Context, ticketKey string) (func() error, error) {
+	if strings.TrimSpace(ticketKey) == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+	// The lease must be visible to other connections immediately, and the caller's
+	// transaction holds the only connection
+	if transactionFromContext(ctx) != nil {
+		return nil, fmt.Errorf("%w: cannot lock ticket %s inside a transaction", domain.ErrInvalidInput, ticketKey)
+	}
+
+	owner, err := newLockOwner()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		acquired, err := m.tryLock(ctx, ticketKey, owner)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			m.logger.Debug("locked ticket", "ticket_key", ticketKey)
+			return m.unlocker(ctx, ticketKey, owner), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// tryLock takes the lease on ticketKey if it is free or expired.
+func (m *LockManager) tryLock(ctx context.Context, ticketKey, owner string) (bool, error) {
+	now := m.now()
+
+	query := `
+		INSERT INTO ticket_locks (ticket_key, owner, expires_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(ticket_key) DO UPDATE SET
+			owner = excluded.owner,
+			expires_at = excluded.expires_at
+		WHERE ticket_locks.expires_at <= ?
+	`
+
+	result, err := m.db.ExecContext(ctx, query,
+		ticketKey,
+		owner,
+		formatTimestamp(now.Add(m.lease)),
+		formatTimestamp(now),
+	)
+	if err != nil {
+		m.logger.Error("failed to lock ticket",
+			"ticket_key", ticketKey,
+			"error", err)
+		return false, fmt.Errorf("failed to lock ticket %s: %w", ticketKey, err)
+	}
+
+	acquired, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return acquired == 1, nil
+}
+
+// unlocker returns the function that releases the lease taken by owner.
+// Releasing is idempotent and still works after ctx is cancelled.
+func (m *LockManager) unlocker(ctx context.Context, ticketKey, owner string) func() error {
+	ctx = context.WithoutCancel(ctx)
+
+	var once sync.Once
+	var err error
+	return func() error {
+		once.Do(func() {
+			// A lease that expired and was taken over belongs to its new owner
+			_, err = m.db.ExecContext(ctx, `DELETE FROM ticket_locks WHERE ticket_key = ? AND owner = ?`, ticketKey, owner)
+			if err != nil {
+				m.logger.Error("failed to unlock ticket",
+					"ticket_key", ticketKey,
+					"error", err)
+				err = fmt.Errorf("failed to unlock ticket %s: %w", ticketKey, err)
+				return
+			}
+			m.logger.Debug("unlocked ticket", "ticket_key", ticketKey)
+		})
+		return err
+	}
+}
+
+// newLockOwner returns a random token identifying one Lock call, so two holders in the
+// same process exclude each other just like holders in different processes.
+func newLockOwner() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate lock owner: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestLockManager_SerializesHolders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	locks := NewLockManager(db.DB(), nil)
+	ctx := context.Background()
+
+	var (
+		mu      sync.Mutex
+		holders int
+		maxSeen int
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := locks.Lock(ctx, "JMD-1")
+			if err != nil {
+				t.Errorf("Lock failed: %v", err)
+				return
+			}
+
+			mu.Lock()
+			holders++
+			if holders > maxSeen {
+				maxSeen = holders
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			holders--
+			mu.Unlock()
+
+			if err := unlock(); err != nil {
+				t.Errorf("unlock failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxSeen != 1 {
+		t.Errorf("%d holders held the lock at once, want 1", maxSeen)
+	}
+}
+
+func TestLockManager_OtherTicketsAndTimeout(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	locks := NewLockManager(db.DB(), nil)
+	ctx := context.Background()
+
+	unlock, err := locks.Lock(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	// Other tickets are independent
+	unlockOther, err := locks.Lock(ctx, "JMD-2")
+	if err != nil {
+		t.Fatalf("Lock(JMD-2) failed: %v", err)
+	}
+	unlockOther()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := locks.Lock(timeoutCtx, "JMD-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Lock on held ticket error = %v, want DeadlineExceeded", err)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("second unlock error = %v, want nil", err)
+	}
+
+	relock, err := locks.Lock(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("Lock after unlock failed: %v", err)
+	}
+	relock()
+}
+
+func TestLockManager_ExpiredLease(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	locks := NewLockManager(db.DB(), nil)
+	ctx := context.Background()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	locks.now = func() time.Time { return now }
+
+	staleUnlock, err := locks.Lock(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	// The holder crashed; once the lease runs out the ticket can be locked again
+	now = now.Add(DefaultLockLease)
+	unlock, err := locks.Lock(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("Lock after expiry failed: %v", err)
+	}
+
+	// A late unlock from the previous holder must not release the new lease
+	if err := staleUnlock(); err != nil {
+		t.Fatalf("stale unlock failed: %v", err)
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := locks.Lock(timeoutCtx, "JMD-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Lock after stale unlock error = %v, want DeadlineExceeded", err)
+	}
+
+	unlock()
+}
+
+func TestLockManager_Errors(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	locks := NewLockManager(db.DB(), nil)
+	ctx := context.Background()
+
+	if _, err := locks.Lock(ctx, ""); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("Lock(\"\") error = %v, want ErrEmptyKey", err)
+	}
+
+	stateRepo := NewStateRepository(db.DB(), nil)
+	txCtx, err := stateRepo.BeginTransaction(ctx)
+	if err != nil {
+		t.Fatalf("BeginTransaction failed: %v", err)
+	}
+	defer stateRepo.Rollback(txCtx)
+
+	if _, err := locks.Lock(txCtx, "JMD-1"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Lock in transaction error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -3,10 +3,14 @@ package sqlite
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	_ "embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 )
 
 // Migration represents a database schema migration.
@@ -19,6 +23,81 @@ type Migration struct {
 var (
 	//go:embed migrations/001_initial_schema.sql
 	migration001 string
+
+	//go:embed migrations/002_ticket_cache.sql
+	migration002 string
+
+	//go:embed migrations/003_search_index.sql
+	migration003 string
+
+	//go:embed migrations/004_search_triggers.sql
+	migration004 string
+
+	//go:embed migrations/005_migration_checksums.sql
+	migration005 string
+
+	//go:embed migrations/006_retention.sql
+	migration006 string
+
+	//go:embed migrations/007_ticket_state_project.sql
+	migration007 string
+
+	//go:embed migrations/008_ticket_state_versions.sql
+	migration008 string
+
+	//go:embed migrations/009_ticket_locks.sql
+	migration009 string
+
+	//go:embed migrations/010_operation_retries.sql
+	migration010 string
+
+	//go:embed migrations/011_auth_status.sql
+	migration011 string
+
+	//go:embed migrations/012_content_hash_version.sql
+	migration012 string
+
+	//go:embed migrations/013_comment_visibility.sql
+	migration013 string
+
+	//go:embed migrations/014_status_history.sql
+	migration014 string
+
+	//go:embed migrations/015_user_directory.sql
+	migration015 string
+
+	//go:embed migrations/016_project_metadata.sql
+	migration016 string
+
+	//go:embed migrations/017_fetched_tickets.sql
+	migration017 string
+
+	//go:embed migrations/018_comment_states.sql
+	migration018 string
+
+	//go:embed migrations/019_local_versions.sql
+	migration019 string
+
+	//go:embed migrations/020_inbox.sql
+	migration020 string
+
+	//go:embed migrations/021_ticket_file_paths.sql
+	migration021 string
+
+	//go:embed migrations/022_pull_only_tickets.sql
+	migration022 string
+
+	//go:embed migrations/023_watched_tickets.sql
+	migration023 string
+
+	//go:embed migrations/024_activity.sql
+	migration024 string
+
+	//go:embed migrations/025_operation_authors.sql
+	migration025 string
+
+	//go:embed migrations/026_operation_correlation.sql
+	migration026 string
 )
 
 // migrations contains all available migrations in order.
@@ -28,6 +107,142 @@ var migrations = []Migration{
 		Name:    "initial_schema",
 		SQL:     migration001,
 	},
+	{
+		Version: 2,
+		Name:    "ticket_cache",
+		SQL:     migration002,
+	},
+	{
+		Version: 3,
+		Name:    "search_index",
+		SQL:     migration003,
+	},
+	{
+		Version: 4,
+		Name:    "search_triggers",
+		SQL:     migration004,
+	},
+	{
+		Version: 5,
+		Name:    "migration_checksums",
+		SQL:     migration005,
+	},
+	{
+		Version: 6,
+		Name:    "retention",
+		SQL:     migration006,
+	},
+	{
+		Version: 7,
+		Name:    "ticket_state_project",
+		SQL:     migration007,
+	},
+	{
+		Version: 8,
+		Name:    "ticket_state_versions",
+		SQL:     migration008,
+	},
+	{
+		Version: 9,
+		Name:    "ticket_locks",
+		SQL:     migration009,
+	},
+	{
+		Version: 10,
+		Name:    "operation_retries",
+		SQL:     migration010,
+	},
+	{
+		Version: 11,
+		Name:    "auth_status",
+		SQL:     migration011,
+	},
+	{
+		Version: 12,
+		Name:    "content_hash_version",
+		SQL:     migration012,
+	},
+	{
+		Version: 13,
+		Name:    "comment_visibility",
+		SQL:     migration013,
+	},
+	{
+		Version: 14,
+		Name:    "status_history",
+		SQL:     migration014,
+	},
+	{
+		Version: 15,
+		Name:    "user_directory",
+		SQL:     migration015,
+	},
+	{
+		Version: 16,
+		Name:    "project_metadata",
+		SQL:     migration016,
+	},
+	{
+		Version: 17,
+		Name:    "fetched_tickets",
+		SQL:     migration017,
+	},
+	{
+		Version: 18,
+		Name:    "comment_states",
+		SQL:     migration018,
+	},
+	{
+		Version: 19,
+		Name:    "local_versions",
+		SQL:     migration019,
+	},
+	{
+		Version: 20,
+		Name:    "inbox",
+		SQL:     migration020,
+	},
+	{
+		Version: 21,
+		Name:    "ticket_file_paths",
+		SQL:     migration021,
+	},
+	{
+		Version: 22,
+		Name:    "pull_only_tickets",
+		SQL:     migration022,
+	},
+	{
+		Version: 23,
+		Name:    "watched_tickets",
+		SQL:     migration023,
+	},
+	{
+		Version: 24,
+		Name:    "activity",
+		SQL:     migration024,
+	},
+	{
+		Version: 25,
+		Name:    "operation_authors",
+		SQL:     migration025,
+	},
+	{
+		Version: 26,
+		Name:    "operation_correlation",
+		SQL:     migration026,
+	},
+}
+
+// ErrMigrationChecksumMismatch is returned at startup when a migration that was already
+// applied to the database differs from the migration embedded in the binary.
+var ErrMigrationChecksumMismatch = errors.New("applied migration does not match embedded migration")
+
+// Checksum returns the SHA-256 of the migration SQL in hex.
+// Line endings are normalized so a checkout with CRLF line endings has the same checksum.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(strings.ReplaceAll(m.SQL, "\r\n", "\n")))
+	return hex.EncodeToString(sum[:])
 }
 
 // MigrationManager handles database schema migrations.
@@ -49,6 +264,8 @@ func NewMigrationManager(db *sql.DB, logger *slog.Logger) *MigrationManager {
 
 // Migrate applies all pending migrations.
 // Migrations are applied in a transaction and rolled back on error.
+// Before applying anything, the checksums recorded for applied migrations are verified
+// against the embedded SQL; a mismatch returns ErrMigrationChecksumMismatch.
 // Returns the current schema version after migration.
 func (m *MigrationManager) Migrate(ctx context.Context) (int, error) {
 	m.logger.Info("starting database migrations")
@@ -61,6 +278,10 @@ func (m *MigrationManager) Migrate(ctx context.Context) (int, error) {
 
 	m.logger.Info("current schema version", "version", currentVersion)
 
+	if err := m.verifyChecksums(ctx); err != nil {
+		return currentVersion, err
+	}
+
 	// Apply pending migrations
 	appliedCount := 0
 	for _, migration := range migrations {
@@ -71,6 +292,12 @@ func (m *MigrationManager) Migrate(ctx context.Context) (int, error) {
 			continue
 		}
 
+		// Each migration is applied atomically, so stopping between them leaves the
+		// database at a consistent version to resume from
+		if err := ctx.Err(); err != nil {
+			return currentVersion, fmt.Errorf("migrations interrupted at version %d: %w", currentVersion, err)
+		}
+
 		m.logger.Info("applying migration",
 			"version", migration.Version,
 			"name", migration.Name)
@@ -84,6 +311,10 @@ func (m *MigrationManager) Migrate(ctx context.Context) (int, error) {
 		currentVersion = migration.Version
 	}
 
+	if err := m.backfillChecksums(ctx); err != nil {
+		return currentVersion, err
+	}
+
 	if appliedCount > 0 {
 		m.logger.Info("migrations completed",
 			"applied_count", appliedCount,
@@ -140,6 +371,20 @@ func (m *MigrationManager) applyMigration(ctx context.Context, migration Migrati
 		return fmt.Errorf("failed to execute migration SQL: %w", err)
 	}
 
+	// Record the checksum once the schema has a column for it (migration 5)
+	hasChecksums, err := hasChecksumColumn(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if hasChecksums {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE schema_version SET checksum = ? WHERE version = ?`,
+			migration.Checksum(), migration.Version,
+		); err != nil {
+			return fmt.Errorf("failed to record migration checksum: %w", err)
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit migration: %w", err)
@@ -148,6 +393,85 @@ func (m *MigrationManager) applyMigration(ctx context.Context, migration Migrati
 	return nil
 }
 
+// verifyChecksums checks that every applied migration with a recorded checksum
+// still matches the embedded migration of the same version.
+func (m *MigrationManager) verifyChecksums(ctx context.Context) error {
+	hasChecksums, err := hasChecksumColumn(ctx, m.db)
+	if err != nil || !hasChecksums {
+		return err
+	}
+
+	embedded := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		embedded[migration.Version] = migration
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, checksum FROM schema_version ORDER BY version`)
+	if err != nil {
+		return fmt.Errorf("failed to query migration checksums: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return fmt.Errorf("failed to scan migration checksum: %w", err)
+		}
+
+		migration, ok := embedded[version]
+		if !ok {
+			m.logger.Warn("database has a migration this version of jiramd does not know about",
+				"version", version)
+			continue
+		}
+		if checksum == "" {
+			continue // applied before checksums were recorded; backfilled after migrating
+		}
+		if checksum != migration.Checksum() {
+			return fmt.Errorf("%w: migration %d (%s) was edited after it was applied "+
+				"(applied %.12s, embedded %.12s); restore the original SQL and add a new migration instead",
+				ErrMigrationChecksumMismatch, version, migration.Name, checksum, migration.Checksum())
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate migration checksums: %w", err)
+	}
+	return nil
+}
+
+// backfillChecksums records the embedded checksum for applied migrations that have none,
+// which are those applied before migration 5 added the checksum column.
+func (m *MigrationManager) backfillChecksums(ctx context.Context) error {
+	hasChecksums, err := hasChecksumColumn(ctx, m.db)
+	if err != nil || !hasChecksums {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if _, err := m.db.ExecContext(ctx,
+			`UPDATE schema_version SET checksum = ? WHERE version = ? AND checksum = ''`,
+			migration.Checksum(), migration.Version,
+		); err != nil {
+			return fmt.Errorf("failed to backfill checksum of migration %d: %w", migration.Version, err)
+		}
+	}
+	return nil
+}
+
+// hasChecksumColumn reports whether schema_version has the checksum column added by migration 5.
+func hasChecksumColumn(ctx context.Context, exec executor) (bool, error) {
+	var count int
+	err := exec.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pragma_table_info('schema_version') WHERE name = 'checksum'`,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check schema_version columns: %w", err)
+	}
+	return count > 0, nil
+}
+
 // Reset drops all tables and reapplies all migrations.
 // WARNING: This will delete all data. Use only for testing.
 func (m *MigrationManager) Reset(ctx context.Context) error {
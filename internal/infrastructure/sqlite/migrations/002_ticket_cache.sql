@@ -0,0 +1,45 @@
+-- Migration 002: Local ticket cache and push queue
+-- Stores ticket content for offline CLI access and queues local changes for Jira
+
+-- Cached ticket content (labels and custom_fields are JSON encoded)
+CREATE TABLE IF NOT EXISTS tickets (
+    ticket_key TEXT PRIMARY KEY,
+    project_key TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    issue_type TEXT NOT NULL DEFAULT '',
+    priority TEXT NOT NULL DEFAULT '',
+    assignee TEXT NOT NULL DEFAULT '',
+    reporter TEXT NOT NULL DEFAULT '',
+    labels TEXT NOT NULL DEFAULT '[]',
+    custom_fields TEXT NOT NULL DEFAULT '{}',
+    created TIMESTAMP NOT NULL,
+    updated TIMESTAMP NOT NULL,
+    cached_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tickets_project
+    ON tickets(project_key);
+
+-- Local changes waiting to be pushed to Jira, applied in id order
+CREATE TABLE IF NOT EXISTS pending_operations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_key TEXT NOT NULL,
+    ticket_key TEXT, -- NULL for ticket creation until Jira assigns a key
+    operation TEXT NOT NULL,
+    payload TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_operations_project
+    ON pending_operations(project_key, id);
+
+CREATE INDEX IF NOT EXISTS idx_pending_operations_ticket
+    ON pending_operations(ticket_key)
+    WHERE ticket_key IS NOT NULL;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (2);
@@ -0,0 +1,32 @@
+-- Migration 003: Comment cache and full-text search index
+-- Lets tickets be found by their content without any Jira API calls
+
+-- Cached ticket comments
+CREATE TABLE IF NOT EXISTS comments (
+    comment_id TEXT PRIMARY KEY,
+    ticket_key TEXT NOT NULL,
+    author TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    created TIMESTAMP NOT NULL,
+    updated TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_ticket
+    ON comments(ticket_key, created);
+
+-- One document per cached ticket; comments holds the bodies of all its comments
+CREATE VIRTUAL TABLE IF NOT EXISTS ticket_search USING fts5(
+    ticket_key UNINDEXED,
+    summary,
+    description,
+    comments,
+    tokenize = 'porter unicode61'
+);
+
+-- Index tickets cached before this migration
+INSERT INTO ticket_search (ticket_key, summary, description, comments)
+SELECT ticket_key, summary, description, ''
+FROM tickets;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (3);
@@ -0,0 +1,102 @@
+-- Migration 004: Keep the full-text search index in sync with ticket content
+-- Triggers rebuild a ticket's ticket_search document whenever the ticket or
+-- one of its comments changes, so every writer keeps the index current
+
+CREATE TRIGGER IF NOT EXISTS tickets_search_insert
+AFTER INSERT ON tickets
+BEGIN
+    INSERT INTO ticket_search (ticket_key, summary, description, comments)
+    VALUES (
+        new.ticket_key,
+        new.summary,
+        new.description,
+        COALESCE((
+            SELECT group_concat(body, char(10))
+            FROM comments
+            WHERE ticket_key = new.ticket_key
+        ), '')
+    );
+END;
+
+-- Also fires for INSERT ... ON CONFLICT DO UPDATE
+CREATE TRIGGER IF NOT EXISTS tickets_search_update
+AFTER UPDATE OF ticket_key, summary, description ON tickets
+BEGIN
+    DELETE FROM ticket_search WHERE ticket_key = old.ticket_key;
+    INSERT INTO ticket_search (ticket_key, summary, description, comments)
+    VALUES (
+        new.ticket_key,
+        new.summary,
+        new.description,
+        COALESCE((
+            SELECT group_concat(body, char(10))
+            FROM comments
+            WHERE ticket_key = new.ticket_key
+        ), '')
+    );
+END;
+
+CREATE TRIGGER IF NOT EXISTS tickets_search_delete
+AFTER DELETE ON tickets
+BEGIN
+    DELETE FROM ticket_search WHERE ticket_key = old.ticket_key;
+END;
+
+-- Comment triggers rebuild the document of the ticket the comment belongs to.
+-- Comments of tickets that are not cached are stored but not indexed.
+CREATE TRIGGER IF NOT EXISTS comments_search_insert
+AFTER INSERT ON comments
+BEGIN
+    DELETE FROM ticket_search WHERE ticket_key = new.ticket_key;
+    INSERT INTO ticket_search (ticket_key, summary, description, comments)
+    SELECT
+        t.ticket_key,
+        t.summary,
+        t.description,
+        COALESCE((
+            SELECT group_concat(c.body, char(10))
+            FROM comments c
+            WHERE c.ticket_key = t.ticket_key
+        ), '')
+    FROM tickets t
+    WHERE t.ticket_key = new.ticket_key;
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_search_update
+AFTER UPDATE OF ticket_key, body ON comments
+BEGIN
+    DELETE FROM ticket_search WHERE ticket_key IN (old.ticket_key, new.ticket_key);
+    INSERT INTO ticket_search (ticket_key, summary, description, comments)
+    SELECT
+        t.ticket_key,
+        t.summary,
+        t.description,
+        COALESCE((
+            SELECT group_concat(c.body, char(10))
+            FROM comments c
+            WHERE c.ticket_key = t.ticket_key
+        ), '')
+    FROM tickets t
+    WHERE t.ticket_key IN (old.ticket_key, new.ticket_key);
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_search_delete
+AFTER DELETE ON comments
+BEGIN
+    DELETE FROM ticket_search WHERE ticket_key = old.ticket_key;
+    INSERT INTO ticket_search (ticket_key, summary, description, comments)
+    SELECT
+        t.ticket_key,
+        t.summary,
+        t.description,
+        COALESCE((
+            SELECT group_concat(c.body, char(10))
+            FROM comments c
+            WHERE c.ticket_key = t.ticket_key
+        ), '')
+    FROM tickets t
+    WHERE t.ticket_key = old.ticket_key;
+END;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (4);
@@ -0,0 +1,9 @@
+-- Migration 005: Migration checksums
+-- Records a SHA-256 of each applied migration's SQL so edits to shipped
+-- migrations are detected at startup. Rows applied before this migration are
+-- backfilled with the checksum of the embedded SQL by the migration manager.
+
+ALTER TABLE schema_version ADD COLUMN checksum TEXT NOT NULL DEFAULT '';
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (5);
@@ -0,0 +1,38 @@
+-- Migration 006: Retention of completed and historical state
+-- Keeps completed push operations, tombstones of deleted tickets, and a history
+-- of sync runs so they can be inspected, then pruned by `jiramd gc` once they
+-- are older than storage.retention.
+
+-- Completed operations stay in the queue (completed_at set) until pruned
+ALTER TABLE pending_operations ADD COLUMN completed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_pending_operations_completed
+    ON pending_operations(completed_at)
+    WHERE completed_at IS NOT NULL;
+
+-- Tickets deleted from Jira keep a tombstone (deleted_at set) until pruned,
+-- so a stale local file is not pushed back as a new ticket
+ALTER TABLE ticket_sync_state ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_ticket_deleted
+    ON ticket_sync_state(deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+-- One row per finished sync run
+CREATE TABLE IF NOT EXISTS sync_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_key TEXT NOT NULL,
+    full_sync BOOLEAN NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    conflicts INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_history_finished
+    ON sync_history(finished_at);
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (6);
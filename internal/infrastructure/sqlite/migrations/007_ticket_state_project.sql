@@ -0,0 +1,16 @@
+-- Migration 007: Project key of ticket sync state
+-- Stores the project of each ticket state explicitly so states can be listed
+-- and deleted per project without matching on the ticket key prefix.
+
+ALTER TABLE ticket_sync_state ADD COLUMN project_key TEXT NOT NULL DEFAULT '';
+
+-- Backfill from the ticket key (PROJECT-123)
+UPDATE ticket_sync_state
+SET project_key = substr(ticket_key, 1, instr(ticket_key, '-') - 1)
+WHERE instr(ticket_key, '-') > 0;
+
+CREATE INDEX IF NOT EXISTS idx_ticket_project
+    ON ticket_sync_state(project_key, ticket_key);
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (7);
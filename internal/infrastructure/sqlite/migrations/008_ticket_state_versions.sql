@@ -0,0 +1,10 @@
+-- Migration 008: Content hash and Jira version of ticket sync state
+-- Records what was last synced so conflicts are detected by comparing content
+-- hashes and Jira versions instead of clock timestamps.
+
+ALTER TABLE ticket_sync_state ADD COLUMN content_hash TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE ticket_sync_state ADD COLUMN jira_version TEXT NOT NULL DEFAULT '';
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (8);
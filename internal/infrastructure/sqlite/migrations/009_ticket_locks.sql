@@ -0,0 +1,12 @@
+-- Migration 009: Per-ticket locks
+-- A row is a lease on one ticket held by one lock owner. Leases expire so a
+-- process that crashes while holding a lock cannot block the ticket forever.
+
+CREATE TABLE IF NOT EXISTS ticket_locks (
+    ticket_key TEXT PRIMARY KEY,
+    owner TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (9);
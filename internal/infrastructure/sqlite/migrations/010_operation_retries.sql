@@ -0,0 +1,9 @@
+-- Migration 010: Pending operation retry state
+-- last_attempt_at drives the retry backoff; failed marks operations that hit an
+-- error the retry policy does not retry, so they are not attempted again.
+
+ALTER TABLE pending_operations ADD COLUMN last_attempt_at TIMESTAMP;
+ALTER TABLE pending_operations ADD COLUMN failed INTEGER NOT NULL DEFAULT 0;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (10);
@@ -0,0 +1,14 @@
+-- Migration 011: Jira authentication status
+-- A single row tracking consecutive authentication failures, so every process
+-- sharing the database sees when the Jira credentials stop working.
+
+CREATE TABLE IF NOT EXISTS auth_status (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    failing_since TIMESTAMP,
+    last_failure_at TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT ''
+);
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (11);
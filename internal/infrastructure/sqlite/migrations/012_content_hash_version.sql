@@ -0,0 +1,8 @@
+-- Migration 012: Content hash algorithm of ticket sync state
+-- Content hashes moved from MD5 to SHA-256. Existing rows keep version 0, meaning
+-- MD5, and are upgraded on the next sync that finds their content unchanged.
+
+ALTER TABLE ticket_sync_state ADD COLUMN content_hash_version INTEGER NOT NULL DEFAULT 0;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (12);
@@ -0,0 +1,8 @@
+-- Migration 013: Comment visibility
+-- Who can see a cached comment, as written by domain.CommentVisibility.String:
+-- empty for public comments, e.g. "role:Developers" or "internal" otherwise.
+
+ALTER TABLE comments ADD COLUMN visibility TEXT NOT NULL DEFAULT '';
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (13);
@@ -0,0 +1,23 @@
+-- Migration 014: Status history
+-- The status changelog of cached tickets, for time in status and cycle time reports.
+
+CREATE TABLE IF NOT EXISTS status_changes (
+    ticket_key TEXT NOT NULL,
+    seq INTEGER NOT NULL, -- position in the ticket's changelog, oldest first
+    change_id TEXT NOT NULL DEFAULT '',
+    from_status TEXT NOT NULL DEFAULT '',
+    to_status TEXT NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (ticket_key, seq)
+);
+
+-- A ticket's changelog goes with it when it leaves the cache
+CREATE TRIGGER IF NOT EXISTS tickets_status_changes_delete
+AFTER DELETE ON tickets
+BEGIN
+    DELETE FROM status_changes WHERE ticket_key = old.ticket_key;
+END;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (14);
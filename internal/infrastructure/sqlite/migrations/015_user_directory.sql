@@ -0,0 +1,19 @@
+-- Migration 015: User directory
+-- Jira users cached for assignee completion and mention resolution, and the user
+-- searches that found them, so both can be refreshed once they are older than a TTL.
+
+CREATE TABLE IF NOT EXISTS users (
+    account_id TEXT PRIMARY KEY,
+    display_name TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    active INTEGER NOT NULL DEFAULT 1,
+    fetched_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_searches (
+    query TEXT PRIMARY KEY, -- normalized with domain.NormalizeUserQuery
+    searched_at TIMESTAMP NOT NULL
+);
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (15);
@@ -0,0 +1,13 @@
+-- Migration 016: Project metadata
+-- The issue types, priorities, statuses, components, and transitions of each project,
+-- cached so edits can be validated offline and refreshed once older than a TTL.
+
+CREATE TABLE IF NOT EXISTS project_metadata (
+    project_key TEXT PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    metadata TEXT NOT NULL, -- JSON: issue_types, priorities, statuses, components, transitions
+    fetched_at TIMESTAMP NOT NULL
+);
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (16);
@@ -0,0 +1,14 @@
+-- Migration 017: Fetched tickets
+-- Tickets as they were last fetched from Jira, with their comments and status history,
+-- keyed by the Jira revision they were fetched at, so full syncs can skip fetching the
+-- details of tickets that did not change.
+
+CREATE TABLE IF NOT EXISTS fetched_tickets (
+    ticket_key TEXT PRIMARY KEY,
+    jira_updated TEXT NOT NULL, -- the ticket's Version: Jira's updated timestamp, RFC 3339
+    ticket TEXT NOT NULL, -- JSON: the mapped ticket with comments and status history
+    fetched_at TIMESTAMP NOT NULL
+);
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (17);
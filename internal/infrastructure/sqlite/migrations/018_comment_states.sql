@@ -0,0 +1,22 @@
+-- Migration 018: Comment states
+-- The ID, updated timestamp, and content hash of each comment as last pulled, so pulls
+-- only fetch comments that may have changed and only rewrite the blocks that did.
+
+CREATE TABLE IF NOT EXISTS comment_states (
+    ticket_key TEXT NOT NULL,
+    comment_id TEXT NOT NULL,
+    seq INTEGER NOT NULL, -- position in the ticket's comments, oldest first
+    updated_at TIMESTAMP NOT NULL,
+    content_hash TEXT NOT NULL,
+    PRIMARY KEY (ticket_key, comment_id)
+);
+
+-- A ticket's comment states go with it when it leaves the cache
+CREATE TRIGGER IF NOT EXISTS tickets_comment_states_delete
+AFTER DELETE ON tickets
+BEGIN
+    DELETE FROM comment_states WHERE ticket_key = old.ticket_key;
+END;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (18);
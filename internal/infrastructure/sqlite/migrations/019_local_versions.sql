@@ -0,0 +1,18 @@
+-- Migration 019: Local versions
+-- Copies of ticket files with local changes, saved before a pull overwrote them with the
+-- Jira version. Rows outlive their ticket so the copies can always be found.
+
+CREATE TABLE IF NOT EXISTS local_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ticket_key TEXT NOT NULL,
+    path TEXT NOT NULL, -- where the copy was saved
+    content_hash TEXT NOT NULL, -- SHA-256 of the copy
+    jira_version TEXT NOT NULL DEFAULT '', -- the Jira revision the file was overwritten with
+    saved_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_local_versions_ticket
+    ON local_versions(ticket_key, saved_at);
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (19);
@@ -0,0 +1,21 @@
+-- Migration 020: Inbox
+-- Webhook events, persisted as they are received and processed asynchronously. Processed
+-- events are kept for the retention period so redeliveries are recognized by their ID.
+
+CREATE TABLE IF NOT EXISTS inbox (
+    event_id TEXT PRIMARY KEY, -- delivery ID, shared by redeliveries
+    event_type TEXT NOT NULL DEFAULT '',
+    ticket_key TEXT NOT NULL DEFAULT '', -- empty for events about no ticket
+    payload TEXT NOT NULL DEFAULT '', -- delivered JSON body
+    received_at TIMESTAMP NOT NULL,
+    processed_at TIMESTAMP -- NULL while pending
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_pending
+    ON inbox(received_at) WHERE processed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_inbox_processed
+    ON inbox(processed_at) WHERE processed_at IS NOT NULL;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (20);
@@ -0,0 +1,8 @@
+-- Migration 021: Ticket file paths
+-- Records where each ticket's markdown file was last written, so commands working on
+-- one ticket find its file without walking the markdown directory.
+
+ALTER TABLE ticket_sync_state ADD COLUMN file_path TEXT NOT NULL DEFAULT '';
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (21);
@@ -0,0 +1,8 @@
+-- Migration 022: Pull-only tickets
+-- Marks tickets pulled ad hoc from outside the sync scope, which jiramd refreshes on
+-- request but never pushes.
+
+ALTER TABLE ticket_sync_state ADD COLUMN pull_only INTEGER NOT NULL DEFAULT 0;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (22);
@@ -0,0 +1,12 @@
+-- Migration 023: Watched tickets
+-- Marks the tickets of the watch list, which every sync pulls whether or not their
+-- projects are synced, so those dropped from the list can be found and removed.
+
+ALTER TABLE ticket_sync_state ADD COLUMN watched INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_ticket_watched
+    ON ticket_sync_state(watched)
+    WHERE watched = 1;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (23);
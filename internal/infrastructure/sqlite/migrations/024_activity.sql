@@ -0,0 +1,17 @@
+-- Migration 024: Activity
+-- The changes pulled from and pushed to Jira, one row per changed field, from which the
+-- daily digests are written. Rows are kept for the retention period.
+
+CREATE TABLE IF NOT EXISTS activity (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ticket_key TEXT NOT NULL,
+    summary TEXT NOT NULL DEFAULT '', -- the ticket's summary at the time
+    direction TEXT NOT NULL, -- pulled or pushed
+    change TEXT NOT NULL DEFAULT '', -- one-line description of the change
+    occurred_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_occurred_at ON activity(occurred_at);
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (24);
@@ -0,0 +1,12 @@
+-- Migration 025: Operation authors
+-- Records who made each local change, for markdown directories edited by several people,
+-- so pushes and their history can be attributed.
+
+ALTER TABLE pending_operations ADD COLUMN author TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_pending_operations_completed
+    ON pending_operations(ticket_key, completed_at)
+    WHERE completed_at IS NOT NULL;
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (25);
@@ -0,0 +1,8 @@
+-- Migration 026: Operation correlation IDs
+-- Records the correlation ID of each queued operation, so the change that queued it and
+-- the Jira requests that push it can be traced together in the logs.
+
+ALTER TABLE pending_operations ADD COLUMN correlation_id TEXT NOT NULL DEFAULT '';
+
+-- Record migration application
+INSERT INTO schema_version (version) VALUES (26);
@@ -0,0 +1,181 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// appliedChecksums returns the checksum recorded for each applied migration version.
+func appliedChecksums(t *testing.T, db *Database) map[int]string {
+	t.Helper()
+
+	rows, err := db.DB().Query(`SELECT version, checksum FROM schema_version`)
+	if err != nil {
+		t.Fatalf("query schema_version: %v", err)
+	}
+	defer rows.Close()
+
+	checksums := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			t.Fatalf("scan schema_version: %v", err)
+		}
+		checksums[version] = checksum
+	}
+	return checksums
+}
+
+func TestMigrate_RecordsChecksums(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	checksums := appliedChecksums(t, db)
+	if len(checksums) != len(migrations) {
+		t.Fatalf("recorded %d migrations, want %d", len(checksums), len(migrations))
+	}
+	for _, migration := range migrations {
+		if got := checksums[migration.Version]; got != migration.Checksum() {
+			t.Errorf("migration %d checksum = %q, want %q", migration.Version, got, migration.Checksum())
+		}
+	}
+}
+
+func TestMigrate_BackfillsMissingChecksums(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Databases migrated before checksums existed have none recorded
+	if _, err := db.DB().Exec(`UPDATE schema_version SET checksum = '' WHERE version < 5`); err != nil {
+		t.Fatalf("clear checksums: %v", err)
+	}
+
+	if err := db.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	for version, checksum := range appliedChecksums(t, db) {
+		if checksum == "" {
+			t.Errorf("migration %d checksum was not backfilled", version)
+		}
+	}
+}
+
+func TestMigrate_StopsWhenContextIsCancelled(t *testing.T) {
+	db, err := NewDatabase(DatabaseConfig{Path: ":memory:", MaxOpenConns: 1, BusyTimeout: 5 * time.Second}, nil)
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := db.Migrate(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Migrate() error = %v, want %v", err, context.Canceled)
+	}
+
+	// The interrupted migration can be resumed
+	if err := db.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate failed after an interrupted run: %v", err)
+	}
+	if got := len(appliedChecksums(t, db)); got != len(migrations) {
+		t.Errorf("applied %d migrations, want %d", got, len(migrations))
+	}
+}
+
+func TestMigrate_DetectsEditedMigration(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	original := migrations[1].SQL
+	migrations[1].SQL = original + "\n-- edited after release\n"
+	defer func() { migrations[1].SQL = original }()
+
+	err := db.Migrate(context.Background())
+	if !errors.Is(err, ErrMigrationChecksumMismatch) {
+		t.Fatalf("expected ErrMigrationChecksumMismatch, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "migration 2 (ticket_cache)") {
+		t.Errorf("error should name the migration: %v", err)
+	}
+}
+
+func TestMigrate_BackfillsTicketStateProjectKey(t *testing.T) {
+	db, err := NewDatabase(DatabaseConfig{Path: ":memory:", MaxOpenConns: 1, BusyTimeout: 5 * time.Second}, nil)
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	// Migrate to the schema before ticket states had a project key
+	all := migrations
+	migrations = all[:6]
+	err = db.Migrate(ctx)
+	migrations = all
+	if err != nil {
+		t.Fatalf("Migrate to version 6 failed: %v", err)
+	}
+
+	if _, err := db.DB().Exec(`
+		INSERT INTO ticket_sync_state (ticket_key, last_synced, last_modified_local, last_modified_jira)
+		VALUES ('JMD-1', '2025-01-01 00:00:00', '2025-01-01 00:00:00', '2025-01-01 00:00:00')
+	`); err != nil {
+		t.Fatalf("insert ticket state: %v", err)
+	}
+
+	if err := db.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	state, err := NewStateRepository(db.DB(), nil).GetTicketState(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicketState failed: %v", err)
+	}
+	if state.ProjectKey != "JMD" {
+		t.Errorf("ProjectKey = %q, want %q", state.ProjectKey, "JMD")
+	}
+}
+
+func TestMigration_ChecksumIgnoresLineEndings(t *testing.T) {
+	unix := Migration{SQL: "SELECT 1;\nSELECT 2;\n"}
+	windows := Migration{SQL: "SELECT 1;\r\nSELECT 2;\r\n"}
+
+	if unix.Checksum() != windows.Checksum() {
+		t.Error("checksum should not depend on line endings")
+	}
+	if unix.Checksum() == (Migration{SQL: "SELECT 3;\n"}).Checksum() {
+		t.Error("different SQL should have different checksums")
+	}
+}
+
+func TestMigrationManager_Reset(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := NewTicketRepository(db.DB(), nil).Save(ctx, newTestTicket(t, "JMD-1", "Payment timeout")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if err := NewMigrationManager(db.DB(), nil).Reset(ctx); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+
+	tickets, err := NewTicketRepository(db.DB(), nil).FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(tickets) != 0 {
+		t.Errorf("expected no tickets after reset, got %d", len(tickets))
+	}
+	if len(appliedChecksums(t, db)) != len(migrations) {
+		t.Error("migrations were not reapplied after reset")
+	}
+}
@@ -0,0 +1,313 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// PendingOperationRepository implements repository.PendingOperationRepository using SQLite.
+type PendingOperationRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+	cipher *Cipher
+}
+
+// NewPendingOperationRepository creates a new SQLite-backed push queue.
+// The database connection must be initialized and migrations applied before use.
+func NewPendingOperationRepository(db *sql.DB, logger *slog.Logger) *PendingOperationRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PendingOperationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithCipher makes the repository encrypt operation payloads at rest.
+func (r *PendingOperationRepository) WithCipher(c *Cipher) *PendingOperationRepository {
+	r.cipher = c
+	return r
+}
+
+// Verify that PendingOperationRepository implements the repository interface
+var _ repository.PendingOperationRepository = (*PendingOperationRepository)(nil)
+
+// Enqueue adds an operation to the end of the queue and sets its ID.
+// Implements repository.PendingOperationRepository.Enqueue.
+func (r *PendingOperationRepository) Enqueue(ctx context.Context, op *domain.PendingOperation) error {
+	if op == nil {
+		return fmt.Errorf("%w: operation cannot be nil", domain.ErrInvalidInput)
+	}
+	if strings.TrimSpace(op.ProjectKey) == "" {
+		return fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	payload, err := r.cipher.seal(op.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt operation payload: %w", err)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	query := `
+		INSERT INTO pending_operations (
+			project_key,
+			ticket_key,
+			operation,
+			payload,
+			created_at,
+			attempts,
+			last_error,
+			last_attempt_at,
+			failed,
+			author,
+			correlation_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := exec.ExecContext(ctx, query,
+		op.ProjectKey,
+		nullableTicketKey(op.TicketKey),
+		string(op.Operation),
+		payload,
+		formatTimestamp(op.CreatedAt.Time()),
+		op.Attempts,
+		op.LastError,
+		formatTimestampNullable(op.LastAttemptAt.Time()),
+		op.Failed,
+		op.Author,
+		string(op.CorrelationID),
+	)
+	if err != nil {
+		r.logger.Error("failed to enqueue operation",
+			"project_key", op.ProjectKey,
+			"ticket_key", op.TicketKey.String(),
+			"operation", op.Operation,
+			"error", err)
+		return fmt.Errorf("failed to enqueue operation: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get operation id: %w", err)
+	}
+	op.ID = id
+
+	r.logger.Debug("enqueued operation",
+		"id", op.ID,
+		"ticket_key", op.TicketKey.String(),
+		"operation", op.Operation)
+
+	return nil
+}
+
+// FindByProject retrieves all queued (not completed) operations for a project, oldest first.
+// Implements repository.PendingOperationRepository.FindByProject.
+func (r *PendingOperationRepository) FindByProject(ctx context.Context, projectKey string) ([]*domain.PendingOperation, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	return r.query(ctx, `WHERE project_key = ? AND completed_at IS NULL`, projectKey)
+}
+
+// FindByTicketKey retrieves all queued (not completed) operations for a ticket, oldest first.
+// Implements repository.PendingOperationRepository.FindByTicketKey.
+func (r *PendingOperationRepository) FindByTicketKey(ctx context.Context, ticketKey string) ([]*domain.PendingOperation, error) {
+	if ticketKey == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	return r.query(ctx, `WHERE ticket_key = ? AND completed_at IS NULL`, ticketKey)
+}
+
+// FindCompletedByTicketKey retrieves the operations of a ticket that were applied to
+// Jira and not yet pruned, most recently completed first.
+// Implements repository.PendingOperationRepository.FindCompletedByTicketKey.
+func (r *PendingOperationRepository) FindCompletedByTicketKey(ctx context.Context, ticketKey string, limit int) ([]*domain.PendingOperation, error) {
+	if ticketKey == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	where := `WHERE ticket_key = ? AND completed_at IS NOT NULL ORDER BY completed_at DESC, id DESC`
+	if limit > 0 {
+		return r.query(ctx, where+` LIMIT ?`, ticketKey, limit)
+	}
+	return r.query(ctx, where, ticketKey)
+}
+
+// Update persists the attempt count, last error, retry state, and completion time of an operation.
+// Implements repository.PendingOperationRepository.Update.
+func (r *PendingOperationRepository) Update(ctx context.Context, op *domain.PendingOperation) error {
+	if op == nil {
+		return fmt.Errorf("%w: operation cannot be nil", domain.ErrInvalidInput)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	result, err := exec.ExecContext(ctx, `
+		UPDATE pending_operations
+		SET attempts = ?, last_error = ?, last_attempt_at = ?, failed = ?, completed_at = ?
+		WHERE id = ?
+	`,
+		op.Attempts,
+		op.LastError,
+		formatTimestampNullable(op.LastAttemptAt.Time()),
+		op.Failed,
+		formatTimestampNullable(op.CompletedAt.Time()),
+		op.ID,
+	)
+	if err != nil {
+		r.logger.Error("failed to update operation",
+			"id", op.ID,
+			"error", err)
+		return fmt.Errorf("failed to update operation: %w", err)
+	}
+
+	return requireRowsAffected(result, fmt.Sprintf("pending operation %d", op.ID))
+}
+
+// Delete removes an operation from the queue.
+// Implements repository.PendingOperationRepository.Delete.
+func (r *PendingOperationRepository) Delete(ctx context.Context, id int64) error {
+	exec := executorFor(ctx, r.db)
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM pending_operations WHERE id = ?`, id)
+	if err != nil {
+		r.logger.Error("failed to delete operation",
+			"id", id,
+			"error", err)
+		return fmt.Errorf("failed to delete operation: %w", err)
+	}
+
+	if err := requireRowsAffected(result, fmt.Sprintf("pending operation %d", id)); err != nil {
+		return err
+	}
+
+	r.logger.Debug("deleted operation", "id", id)
+	return nil
+}
+
+// PruneCompleted removes operations completed before the given time.
+// Implements repository.PendingOperationRepository.PruneCompleted.
+func (r *PendingOperationRepository) PruneCompleted(ctx context.Context, before time.Time) (int, error) {
+	exec := executorFor(ctx, r.db)
+
+	query := `DELETE FROM pending_operations WHERE completed_at IS NOT NULL AND completed_at < ?`
+
+	result, err := exec.ExecContext(ctx, query, formatTimestamp(before))
+	if err != nil {
+		r.logger.Error("failed to prune completed operations",
+			"before", before,
+			"error", err)
+		return 0, fmt.Errorf("failed to prune completed operations: %w", err)
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.Debug("pruned completed operations", "before", before, "count", pruned)
+	return int(pruned), nil
+}
+
+// query runs a SELECT over pending_operations with the given WHERE clause, in queue
+// order unless the clause orders them itself.
+func (r *PendingOperationRepository) query(ctx context.Context, where string, args ...interface{}) ([]*domain.PendingOperation, error) {
+	exec := executorFor(ctx, r.db)
+
+	query := `
+		SELECT
+			id,
+			project_key,
+			COALESCE(ticket_key, ''),
+			operation,
+			payload,
+			created_at,
+			attempts,
+			last_error,
+			COALESCE(last_attempt_at, ''),
+			failed,
+			COALESCE(completed_at, ''),
+			author,
+			correlation_id
+		FROM pending_operations
+	` + where
+	if !strings.Contains(where, "ORDER BY") {
+		query += ` ORDER BY id`
+	}
+
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to query pending operations", "error", err)
+		return nil, fmt.Errorf("failed to query pending operations: %w", err)
+	}
+	defer rows.Close()
+
+	var ops []*domain.PendingOperation
+	for rows.Next() {
+		var op domain.PendingOperation
+		var ticketKey, operation, createdAt, lastAttemptAt, completedAt, correlationID string
+
+		if err := rows.Scan(
+			&op.ID,
+			&op.ProjectKey,
+			&ticketKey,
+			&operation,
+			&op.Payload,
+			&createdAt,
+			&op.Attempts,
+			&op.LastError,
+			&lastAttemptAt,
+			&op.Failed,
+			&completedAt,
+			&op.Author,
+			&correlationID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan pending operation: %w", err)
+		}
+
+		if err := r.cipher.openAll(&op.Payload); err != nil {
+			return nil, fmt.Errorf("failed to decrypt pending operation %d: %w", op.ID, err)
+		}
+
+		if ticketKey != "" {
+			key, err := domain.NewTicketKey(ticketKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ticket key in pending operation %d: %w", op.ID, err)
+			}
+			op.TicketKey = key
+		}
+		op.Operation = domain.OperationType(operation)
+		op.CreatedAt = domain.NewSyncTimestamp(parseTimestamp(createdAt))
+		op.LastAttemptAt = domain.NewSyncTimestamp(parseTimestamp(lastAttemptAt))
+		op.CompletedAt = domain.NewSyncTimestamp(parseTimestamp(completedAt))
+		op.CorrelationID = domain.CorrelationID(correlationID)
+
+		ops = append(ops, &op)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pending operations: %w", err)
+	}
+
+	return ops, nil
+}
+
+// nullableTicketKey stores the zero TicketKey of ticket creations as NULL.
+func nullableTicketKey(key domain.TicketKey) interface{} {
+	if key.IsZero() {
+		return nil
+	}
+	return key.String()
+}
@@ -0,0 +1,223 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestPendingOperationRepository_EnqueueAndFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPendingOperationRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	key, _ := domain.NewTicketKey("JMD-1")
+	transition, _ := domain.NewPendingOperation("JMD", key, domain.OpPushStatus, `{"status":"Done"}`)
+	create, _ := domain.NewPendingOperation("JMD", domain.TicketKey{}, domain.OpCreateTicket, `{"summary":"New"}`)
+	other, _ := domain.NewPendingOperation("OPS", key, domain.OpPushField, `{}`)
+
+	for _, op := range []*domain.PendingOperation{transition, create, other} {
+		if err := repo.Enqueue(ctx, op); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+		if op.ID == 0 {
+			t.Error("Enqueue did not set ID")
+		}
+	}
+
+	ops, err := repo.FindByProject(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("FindByProject failed: %v", err)
+	}
+	if len(ops) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(ops))
+	}
+
+	// Operations come back in queue order
+	if ops[0].ID != transition.ID || ops[1].ID != create.ID {
+		t.Errorf("unexpected order: %d, %d", ops[0].ID, ops[1].ID)
+	}
+	if ops[0].TicketKey != key || ops[0].Operation != domain.OpPushStatus || ops[0].Payload != `{"status":"Done"}` {
+		t.Errorf("transition round trip mismatch: %+v", ops[0])
+	}
+	if !ops[1].TicketKey.IsZero() || ops[1].Operation != domain.OpCreateTicket {
+		t.Errorf("create round trip mismatch: %+v", ops[1])
+	}
+	if ops[0].CreatedAt.IsZero() {
+		t.Error("CreatedAt should not be zero")
+	}
+	if ops[0].CorrelationID == "" || ops[0].CorrelationID != transition.CorrelationID {
+		t.Errorf("correlation ID round trip mismatch: got %q, want %q", ops[0].CorrelationID, transition.CorrelationID)
+	}
+
+	byTicket, err := repo.FindByTicketKey(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindByTicketKey failed: %v", err)
+	}
+	if len(byTicket) != 2 {
+		t.Errorf("expected 2 operations for JMD-1, got %d", len(byTicket))
+	}
+}
+
+func TestPendingOperationRepository_UpdateAndDelete(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPendingOperationRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	key, _ := domain.NewTicketKey("JMD-1")
+	op, _ := domain.NewPendingOperation("JMD", key, domain.OpPushStatus, `{}`)
+	if err := repo.Enqueue(ctx, op); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	op.RecordAttempt(errors.New("jira unavailable"))
+	if err := repo.Update(ctx, op); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	ops, err := repo.FindByProject(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("FindByProject failed: %v", err)
+	}
+	if ops[0].Attempts != 1 || ops[0].LastError != "jira unavailable" {
+		t.Errorf("attempt not persisted: %+v", ops[0])
+	}
+
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	op.RecordFailure(fmt.Errorf("%w: field is required", domain.ErrInvalidInput), at, domain.DefaultRetryPolicy())
+	if err := repo.Update(ctx, op); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	ops, err = repo.FindByProject(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("FindByProject failed: %v", err)
+	}
+	if !ops[0].Failed || !ops[0].LastAttemptAt.Time().Equal(at) {
+		t.Errorf("retry state not persisted: failed = %v, last attempt = %v", ops[0].Failed, ops[0].LastAttemptAt.Time())
+	}
+
+	if err := repo.Delete(ctx, op.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.Delete(ctx, op.ID); !domain.IsNotFoundError(err) {
+		t.Errorf("expected ErrNotFound deleting twice, got: %v", err)
+	}
+	if err := repo.Update(ctx, op); !domain.IsNotFoundError(err) {
+		t.Errorf("expected ErrNotFound updating deleted operation, got: %v", err)
+	}
+}
+
+func TestPendingOperationRepository_CompleteAndPrune(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPendingOperationRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	key, _ := domain.NewTicketKey("JMD-1")
+
+	old, _ := domain.NewPendingOperation("JMD", key, domain.OpPushStatus, `{}`)
+	recent, _ := domain.NewPendingOperation("JMD", key, domain.OpPushField, `{}`)
+	queued, _ := domain.NewPendingOperation("JMD", key, domain.OpPostComment, `{}`)
+	recent.Author = "Alice"
+	for _, op := range []*domain.PendingOperation{old, recent, queued} {
+		if err := repo.Enqueue(ctx, op); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	old.Complete(now.Add(-100 * 24 * time.Hour))
+	recent.Complete(now.Add(-time.Hour))
+	for _, op := range []*domain.PendingOperation{old, recent} {
+		if err := repo.Update(ctx, op); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+
+	// Completed operations leave the queue
+	ops, err := repo.FindByTicketKey(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindByTicketKey failed: %v", err)
+	}
+	if len(ops) != 1 || ops[0].ID != queued.ID {
+		t.Fatalf("expected only the queued operation, got %d operations", len(ops))
+	}
+
+	// Completed operations are the push history, most recent first
+	pushed, err := repo.FindCompletedByTicketKey(ctx, "JMD-1", 0)
+	if err != nil {
+		t.Fatalf("FindCompletedByTicketKey failed: %v", err)
+	}
+	if len(pushed) != 2 || pushed[0].ID != recent.ID || pushed[1].ID != old.ID {
+		t.Fatalf("expected the recent then the old operation, got %d operations", len(pushed))
+	}
+	if pushed[0].Author != "Alice" || !pushed[0].CompletedAt.Time().Equal(now.Add(-time.Hour)) {
+		t.Errorf("pushed round trip mismatch: author = %q, completed at = %v", pushed[0].Author, pushed[0].CompletedAt.Time())
+	}
+	if limited, err := repo.FindCompletedByTicketKey(ctx, "JMD-1", 1); err != nil || len(limited) != 1 {
+		t.Errorf("FindCompletedByTicketKey(limit 1) = %d operations, %v; want 1", len(limited), err)
+	}
+
+	pruned, err := repo.PruneCompleted(ctx, now.Add(-90*24*time.Hour))
+	if err != nil {
+		t.Fatalf("PruneCompleted failed: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("PruneCompleted removed %d operations, want 1", pruned)
+	}
+
+	// The recently completed operation is kept until it ages out
+	if err := repo.Delete(ctx, recent.ID); err != nil {
+		t.Errorf("expected recently completed operation to be kept: %v", err)
+	}
+	if err := repo.Delete(ctx, old.ID); !domain.IsNotFoundError(err) {
+		t.Errorf("expected pruned operation to be gone, got: %v", err)
+	}
+
+	// Queued operations are never pruned
+	if pruned, err := repo.PruneCompleted(ctx, now.Add(time.Hour)); err != nil || pruned != 0 {
+		t.Errorf("PruneCompleted() = %d, %v; want 0, nil", pruned, err)
+	}
+}
+
+func TestPendingOperationRepository_SharesTransaction(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	stateRepo := NewStateRepository(db.DB(), nil)
+	repo := NewPendingOperationRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	txCtx, err := stateRepo.BeginTransaction(ctx)
+	if err != nil {
+		t.Fatalf("BeginTransaction failed: %v", err)
+	}
+
+	key, _ := domain.NewTicketKey("JMD-1")
+	op, _ := domain.NewPendingOperation("JMD", key, domain.OpPushStatus, `{}`)
+	if err := repo.Enqueue(txCtx, op); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	if err := stateRepo.Rollback(txCtx); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+
+	ops, err := repo.FindByProject(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("FindByProject failed: %v", err)
+	}
+	if len(ops) != 0 {
+		t.Errorf("expected rolled back queue to be empty, got %d operations", len(ops))
+	}
+}
@@ -0,0 +1,125 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// ProjectMetadataRepository implements repository.ProjectMetadataRepository using SQLite.
+// The lists and transitions of a project are stored together as one JSON document,
+// since they are always read and replaced as a whole.
+type ProjectMetadataRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewProjectMetadataRepository creates a new SQLite-based project metadata repository.
+// The database connection must be initialized and migrations applied before use.
+func NewProjectMetadataRepository(db *sql.DB, logger *slog.Logger) *ProjectMetadataRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ProjectMetadataRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Verify that ProjectMetadataRepository implements the repository.ProjectMetadataRepository interface
+var _ repository.ProjectMetadataRepository = (*ProjectMetadataRepository)(nil)
+
+// metadataDocument is the JSON stored in the metadata column.
+type metadataDocument struct {
+	IssueTypes  []string            `json:"issue_types"`
+	Priorities  []string            `json:"priorities"`
+	Statuses    []string            `json:"statuses"`
+	Components  []string            `json:"components"`
+	Transitions map[string][]string `json:"transitions"`
+
+	FieldOptions map[string][]string `json:"field_options,omitempty"`
+}
+
+// SaveProjectMetadata inserts or replaces the cached metadata of a project.
+// Implements repository.ProjectMetadataRepository.SaveProjectMetadata.
+func (r *ProjectMetadataRepository) SaveProjectMetadata(ctx context.Context, metadata *domain.ProjectMetadata) error {
+	if strings.TrimSpace(metadata.ProjectKey) == "" {
+		return fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	document, err := json.Marshal(metadataDocument{
+		IssueTypes:  metadata.IssueTypes,
+		Priorities:  metadata.Priorities,
+		Statuses:    metadata.Statuses,
+		Components:  metadata.Components,
+		Transitions: metadata.Transitions,
+
+		FieldOptions: metadata.FieldOptions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode project metadata: %w", err)
+	}
+
+	exec := executorFor(ctx, r.db)
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO project_metadata (project_key, name, metadata, fetched_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(project_key) DO UPDATE SET
+			name = excluded.name,
+			metadata = excluded.metadata,
+			fetched_at = excluded.fetched_at
+	`, metadata.ProjectKey, metadata.Name, string(document), formatTimestamp(metadata.FetchedAt))
+	if err != nil {
+		r.logger.Error("failed to save project metadata", "project_key", metadata.ProjectKey, "error", err)
+		return fmt.Errorf("failed to save project metadata: %w", err)
+	}
+
+	r.logger.Debug("saved project metadata", "project_key", metadata.ProjectKey)
+	return nil
+}
+
+// FindProjectMetadata retrieves the cached metadata of a project.
+// Implements repository.ProjectMetadataRepository.FindProjectMetadata.
+func (r *ProjectMetadataRepository) FindProjectMetadata(ctx context.Context, projectKey string) (*domain.ProjectMetadata, error) {
+	if strings.TrimSpace(projectKey) == "" {
+		return nil, fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	var (
+		metadata  = &domain.ProjectMetadata{ProjectKey: projectKey}
+		document  string
+		fetchedAt string
+	)
+	exec := executorFor(ctx, r.db)
+	err := exec.QueryRowContext(ctx, `
+		SELECT name, metadata, fetched_at FROM project_metadata WHERE project_key = ?
+	`, projectKey).Scan(&metadata.Name, &document, &fetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: no metadata cached for project %s", domain.ErrNotFound, projectKey)
+	}
+	if err != nil {
+		r.logger.Error("failed to find project metadata", "project_key", projectKey, "error", err)
+		return nil, fmt.Errorf("failed to find project metadata: %w", err)
+	}
+
+	var decoded metadataDocument
+	if err := json.Unmarshal([]byte(document), &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode project metadata: %w", err)
+	}
+	metadata.IssueTypes = decoded.IssueTypes
+	metadata.Priorities = decoded.Priorities
+	metadata.Statuses = decoded.Statuses
+	metadata.Components = decoded.Components
+	metadata.Transitions = decoded.Transitions
+	metadata.FieldOptions = decoded.FieldOptions
+	metadata.FetchedAt = parseTimestamp(fetchedAt)
+	metadata.Normalize()
+	return metadata, nil
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestProjectMetadataRepository_SaveAndFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewProjectMetadataRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	metadata := &domain.ProjectMetadata{
+		ProjectKey: "JMD",
+		Name:       "Jira Markdown",
+		IssueTypes: []string{"Bug", "Task"},
+		Priorities: []string{"High", "Low"},
+		Statuses:   []string{"Done", "To Do"},
+		Components: []string{"CLI"},
+		Transitions: map[string][]string{
+			"To Do": {"Done"},
+		},
+		FieldOptions: map[string][]string{
+			"customfield_10001": {"Red", "Blue"},
+		},
+		FetchedAt: time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC),
+	}
+	if err := repo.SaveProjectMetadata(ctx, metadata); err != nil {
+		t.Fatalf("SaveProjectMetadata failed: %v", err)
+	}
+
+	got, err := repo.FindProjectMetadata(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("FindProjectMetadata failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, metadata) {
+		t.Errorf("FindProjectMetadata() = %+v, want %+v", got, metadata)
+	}
+
+	// Refreshing replaces the whole entry
+	refreshed := &domain.ProjectMetadata{ProjectKey: "JMD", Name: "Renamed", FetchedAt: metadata.FetchedAt.Add(time.Hour)}
+	if err := repo.SaveProjectMetadata(ctx, refreshed); err != nil {
+		t.Fatalf("SaveProjectMetadata failed: %v", err)
+	}
+	got, err = repo.FindProjectMetadata(ctx, "JMD")
+	if err != nil || got.Name != "Renamed" || len(got.IssueTypes) != 0 || !got.FetchedAt.Equal(refreshed.FetchedAt) {
+		t.Errorf("FindProjectMetadata() after a refresh = %+v, %v", got, err)
+	}
+
+	if _, err := repo.FindProjectMetadata(ctx, "OPS"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("FindProjectMetadata(uncached) error = %v, want ErrNotFound", err)
+	}
+	if err := repo.SaveProjectMetadata(ctx, &domain.ProjectMetadata{}); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("SaveProjectMetadata(empty key) error = %v, want ErrEmptyKey", err)
+	}
+}
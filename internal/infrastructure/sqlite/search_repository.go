@@ -0,0 +1,235 @@
+// Package sqlite provides SQLite-based repository implementations.
+// This infrastructure layer implements domain repository interfaces using SQLite.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// SearchRepository implements the domain SearchRepository interface using an SQLite FTS5 index.
+// The ticket_search table holds one document per cached ticket. Triggers on the tickets
+// and comments tables (migration 004) rebuild a ticket's document whenever it changes.
+type SearchRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+	cipher *Cipher
+}
+
+// NewSearchRepository creates a new SQLite-based search repository.
+// The database connection must be initialized and migrations applied before use.
+func NewSearchRepository(db *sql.DB, logger *slog.Logger) *SearchRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SearchRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithCipher tells the repository that ticket content is encrypted at rest. The index then
+// only holds ciphertext, so Search reports that full-text search is unavailable.
+func (r *SearchRepository) WithCipher(c *Cipher) *SearchRepository {
+	r.cipher = c
+	return r
+}
+
+// Verify that SearchRepository implements the repository.SearchRepository interface
+var _ repository.SearchRepository = (*SearchRepository)(nil)
+
+// Search runs a full-text search over cached ticket summaries, descriptions, and comments.
+// Implements repository.SearchRepository.Search.
+//
+// Matches in the summary weigh more than matches in the description, which weigh
+// more than matches in comments.
+func (r *SearchRepository) Search(ctx context.Context, text string, limit int) ([]*repository.SearchHit, error) {
+	if r.cipher != nil {
+		return nil, fmt.Errorf("%w: full-text search is not available when storage.encrypt is enabled", domain.ErrConfig)
+	}
+
+	match, err := ftsQuery(text)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = -1 // no limit
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	query := `
+		SELECT
+			t.ticket_key,
+			t.summary,
+			t.status,
+			snippet(ticket_search, -1, '[', ']', '...', 12),
+			bm25(ticket_search, 0.0, 10.0, 5.0, 1.0) AS score
+		FROM ticket_search
+		JOIN tickets t ON t.ticket_key = ticket_search.ticket_key
+		WHERE ticket_search MATCH ?
+		ORDER BY score, t.ticket_key
+		LIMIT ?
+	`
+
+	rows, err := exec.QueryContext(ctx, query, match, limit)
+	if err != nil {
+		r.logger.Error("failed to search tickets",
+			"query", match,
+			"error", err)
+		return nil, fmt.Errorf("failed to search tickets: %w", err)
+	}
+	defer rows.Close()
+
+	hits := make([]*repository.SearchHit, 0)
+	for rows.Next() {
+		var hit repository.SearchHit
+		if err := rows.Scan(&hit.TicketKey, &hit.Summary, &hit.Status, &hit.Snippet, &hit.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan search hit: %w", err)
+		}
+		// bm25 is negative with the best match lowest; flip it so higher is better
+		hit.Score = -hit.Score
+		hits = append(hits, &hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search hits: %w", err)
+	}
+
+	return hits, nil
+}
+
+// Rebuild discards the search index and rebuilds it from every cached ticket and comment.
+// Implements repository.SearchRepository.Rebuild.
+func (r *SearchRepository) Rebuild(ctx context.Context) (int, error) {
+	exec := executorFor(ctx, r.db)
+
+	// Rebuild in transaction if not already in one
+	inTransaction := transactionFromContext(ctx) != nil
+	if !inTransaction {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		exec = tx
+	}
+
+	if _, err := exec.ExecContext(ctx, `DELETE FROM ticket_search`); err != nil {
+		r.logger.Error("failed to clear search index", "error", err)
+		return 0, fmt.Errorf("failed to clear search index: %w", err)
+	}
+
+	query := `
+		INSERT INTO ticket_search (ticket_key, summary, description, comments)
+		SELECT
+			t.ticket_key,
+			t.summary,
+			t.description,
+			COALESCE((
+				SELECT group_concat(c.body, char(10))
+				FROM comments c
+				WHERE c.ticket_key = t.ticket_key
+			), '')
+		FROM tickets t
+	`
+	result, err := exec.ExecContext(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to rebuild search index", "error", err)
+		return 0, fmt.Errorf("failed to rebuild search index: %w", err)
+	}
+
+	indexed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// Merge the index b-trees left behind by the rebuild
+	if _, err := exec.ExecContext(ctx, `INSERT INTO ticket_search (ticket_search) VALUES ('optimize')`); err != nil {
+		return 0, fmt.Errorf("failed to optimize search index: %w", err)
+	}
+
+	// Commit if we started the transaction
+	if !inTransaction {
+		if err := exec.(*sql.Tx).Commit(); err != nil {
+			return 0, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	r.logger.Info("rebuilt search index", "tickets", indexed)
+	return int(indexed), nil
+}
+
+// ftsQuery converts free search text into an FTS5 query matching documents that
+// contain every term. Terms are quoted so FTS5 syntax characters in the text are
+// searched for literally; "double quoted phrases" stay together and a trailing *
+// on a term becomes a prefix match.
+func ftsQuery(text string) (string, error) {
+	var terms []string
+	for _, token := range splitSearchText(text) {
+		prefix := !token.phrase && strings.HasSuffix(token.text, "*")
+		word := strings.TrimRight(token.text, "*")
+		if strings.IndexFunc(word, isSearchable) < 0 {
+			continue
+		}
+
+		term := `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 0 {
+		return "", fmt.Errorf("%w: search text %q has no searchable terms", domain.ErrInvalidInput, text)
+	}
+	return strings.Join(terms, " "), nil
+}
+
+// searchToken is a word or quoted phrase of search text.
+type searchToken struct {
+	text   string
+	phrase bool
+}
+
+// splitSearchText splits search text on whitespace, keeping double-quoted phrases together.
+// An unterminated quote runs to the end of the text.
+func splitSearchText(text string) []searchToken {
+	var tokens []searchToken
+	var current strings.Builder
+	inPhrase := false
+
+	flush := func(phrase bool) {
+		if current.Len() > 0 {
+			tokens = append(tokens, searchToken{text: current.String(), phrase: phrase})
+			current.Reset()
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			flush(inPhrase)
+			inPhrase = !inPhrase
+		case unicode.IsSpace(r) && !inPhrase:
+			flush(false)
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush(inPhrase)
+
+	return tokens
+}
+
+// isSearchable reports whether r can be part of an indexed token.
+func isSearchable(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
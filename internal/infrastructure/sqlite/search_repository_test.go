@@ -0,0 +1,344 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// newTestComment creates a valid comment for repository tests.
+func newTestComment(t *testing.T, id, ticketKey, body string) *domain.Comment {
+	t.Helper()
+
+	key, err := domain.NewTicketKey(ticketKey)
+	if err != nil {
+		t.Fatalf("NewTicketKey(%q) failed: %v", ticketKey, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	comment, err := domain.NewComment(id, key, "alice", body, now, now)
+	if err != nil {
+		t.Fatalf("NewComment failed: %v", err)
+	}
+	return comment
+}
+
+// searchKeys returns the ticket keys of a search in rank order.
+func searchKeys(t *testing.T, repo *SearchRepository, text string) []string {
+	t.Helper()
+
+	hits, err := repo.Search(context.Background(), text, 0)
+	if err != nil {
+		t.Fatalf("Search(%q) failed: %v", text, err)
+	}
+
+	keys := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		keys = append(keys, hit.TicketKey)
+	}
+	return keys
+}
+
+func TestSearchRepository_Search(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tickets := NewTicketRepository(db.DB(), nil)
+	comments := NewCommentRepository(db.DB(), nil)
+	search := NewSearchRepository(db.DB(), nil)
+
+	payment := newTestTicket(t, "JMD-1", "Payment timeout on checkout")
+	payment.Description = "The gateway times out after 30 seconds"
+	retries := newTestTicket(t, "JMD-2", "Retry failed webhooks")
+	retries.Description = "Webhooks are dropped when the payment provider is slow"
+	other := newTestTicket(t, "JMD-3", "Update onboarding docs")
+
+	for _, ticket := range []*domain.Ticket{payment, retries, other} {
+		if err := tickets.Save(ctx, ticket); err != nil {
+			t.Fatalf("Save(%s) failed: %v", ticket.Key, err)
+		}
+	}
+	if err := comments.Save(ctx, newTestComment(t, "10001", "JMD-3", "Mention the payment timeout runbook")); err != nil {
+		t.Fatalf("Save comment failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"all terms required", "payment timeout", []string{"JMD-1", "JMD-3"}},
+		{"summary ranks above description", "payment", []string{"JMD-1", "JMD-2", "JMD-3"}},
+		{"comments are indexed", "runbook", []string{"JMD-3"}},
+		{"stemming", "webhook", []string{"JMD-2"}},
+		{"prefix", "onboard*", []string{"JMD-3"}},
+		{"phrase", `"provider is slow"`, []string{"JMD-2"}},
+		{"phrase not present", `"slow provider"`, []string{}},
+		{"syntax characters are literal", "payment: (timeout^", []string{"JMD-1", "JMD-3"}},
+		{"no match", "kubernetes", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := searchKeys(t, search, tt.text)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Search(%q): got %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRepository_SearchHitDetails(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tickets := NewTicketRepository(db.DB(), nil)
+	search := NewSearchRepository(db.DB(), nil)
+
+	ticket := newTestTicket(t, "JMD-1", "Payment timeout on checkout")
+	if err := tickets.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	hits, err := search.Search(ctx, "timeout", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("expected 1 hit, got %d", len(hits))
+	}
+
+	hit := hits[0]
+	if hit.Summary != ticket.Summary || hit.Status != "To Do" {
+		t.Errorf("hit fields: got summary %q, status %q", hit.Summary, hit.Status)
+	}
+	if !strings.Contains(hit.Snippet, "[timeout]") {
+		t.Errorf("Snippet should highlight the match, got %q", hit.Snippet)
+	}
+	if hit.Score <= 0 {
+		t.Errorf("Score should be positive, got %f", hit.Score)
+	}
+}
+
+func TestSearchRepository_Limit(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tickets := NewTicketRepository(db.DB(), nil)
+	search := NewSearchRepository(db.DB(), nil)
+
+	for _, key := range []string{"JMD-1", "JMD-2", "JMD-3"} {
+		if err := tickets.Save(ctx, newTestTicket(t, key, "Flaky test")); err != nil {
+			t.Fatalf("Save(%s) failed: %v", key, err)
+		}
+	}
+
+	hits, err := search.Search(ctx, "flaky", 2)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Errorf("expected 2 hits, got %d", len(hits))
+	}
+}
+
+func TestSearchRepository_IndexFollowsCache(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tickets := NewTicketRepository(db.DB(), nil)
+	comments := NewCommentRepository(db.DB(), nil)
+	search := NewSearchRepository(db.DB(), nil)
+
+	ticket := newTestTicket(t, "JMD-1", "Payment timeout")
+	if err := tickets.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Re-pulling replaces the document rather than adding a second one
+	ticket.Summary = "Checkout latency"
+	if err := tickets.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if got := searchKeys(t, search, "payment"); len(got) != 0 {
+		t.Errorf("old summary still indexed: %v", got)
+	}
+	if got := searchKeys(t, search, "latency"); len(got) != 1 {
+		t.Errorf("new summary not indexed exactly once: %v", got)
+	}
+
+	ticket.Description = "Caused by slow DNS lookups"
+	if err := tickets.Update(ctx, ticket); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got := searchKeys(t, search, "dns"); len(got) != 1 {
+		t.Errorf("updated description not indexed: %v", got)
+	}
+
+	if err := comments.Save(ctx, newTestComment(t, "10001", "JMD-1", "Reproduced on staging")); err != nil {
+		t.Fatalf("Save comment failed: %v", err)
+	}
+	if got := searchKeys(t, search, "staging dns"); len(got) != 1 {
+		t.Errorf("comment not indexed with ticket content: %v", got)
+	}
+
+	if err := comments.Delete(ctx, "10001"); err != nil {
+		t.Fatalf("Delete comment failed: %v", err)
+	}
+	if got := searchKeys(t, search, "staging"); len(got) != 0 {
+		t.Errorf("deleted comment still indexed: %v", got)
+	}
+
+	if err := tickets.Delete(ctx, "JMD-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if got := searchKeys(t, search, "latency"); len(got) != 0 {
+		t.Errorf("deleted ticket still indexed: %v", got)
+	}
+}
+
+func TestSearchRepository_RollbackLeavesIndexUnchanged(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	stateRepo := NewStateRepository(db.DB(), nil)
+	tickets := NewTicketRepository(db.DB(), nil)
+	search := NewSearchRepository(db.DB(), nil)
+
+	txCtx, err := stateRepo.BeginTransaction(context.Background())
+	if err != nil {
+		t.Fatalf("BeginTransaction failed: %v", err)
+	}
+	if err := tickets.Save(txCtx, newTestTicket(t, "JMD-1", "Payment timeout")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := stateRepo.Rollback(txCtx); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+
+	if got := searchKeys(t, search, "payment"); len(got) != 0 {
+		t.Errorf("rolled back ticket is indexed: %v", got)
+	}
+}
+
+func TestSearchRepository_InvalidQuery(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	search := NewSearchRepository(db.DB(), nil)
+
+	for _, text := range []string{"", "   ", `""`, "- * ()"} {
+		if _, err := search.Search(context.Background(), text, 10); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("Search(%q): expected ErrInvalidInput, got %v", text, err)
+		}
+	}
+}
+
+func TestFTSQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"payment timeout", `"payment" "timeout"`},
+		{`"payment gateway" retr*`, `"payment gateway" "retr"*`},
+		{`say "hi`, `"say" "hi"`},
+		{`a"b`, `"a" "b"`},
+		{"NOT OR AND", `"NOT" "OR" "AND"`},
+		{"col:value", `"col:value"`},
+		{"- timeout", `"timeout"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ftsQuery(tt.text)
+			if err != nil {
+				t.Fatalf("ftsQuery(%q) failed: %v", tt.text, err)
+			}
+			if got != tt.want {
+				t.Errorf("ftsQuery(%q): got %s, want %s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRepository_CommentsCachedBeforeTicket(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tickets := NewTicketRepository(db.DB(), nil)
+	comments := NewCommentRepository(db.DB(), nil)
+	search := NewSearchRepository(db.DB(), nil)
+
+	if err := comments.Save(ctx, newTestComment(t, "10001", "JMD-1", "Reproduced on staging")); err != nil {
+		t.Fatalf("Save comment failed: %v", err)
+	}
+	if got := searchKeys(t, search, "staging"); len(got) != 0 {
+		t.Errorf("comment of uncached ticket is indexed: %v", got)
+	}
+
+	if err := tickets.Save(ctx, newTestTicket(t, "JMD-1", "Payment timeout")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if got := searchKeys(t, search, "staging payment"); len(got) != 1 {
+		t.Errorf("ticket indexed without its comments: %v", got)
+	}
+}
+
+func TestSearchRepository_Rebuild(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tickets := NewTicketRepository(db.DB(), nil)
+	comments := NewCommentRepository(db.DB(), nil)
+	search := NewSearchRepository(db.DB(), nil)
+
+	for _, key := range []string{"JMD-1", "JMD-2"} {
+		if err := tickets.Save(ctx, newTestTicket(t, key, "Payment timeout")); err != nil {
+			t.Fatalf("Save(%s) failed: %v", key, err)
+		}
+	}
+	if err := comments.Save(ctx, newTestComment(t, "10001", "JMD-2", "Reproduced on staging")); err != nil {
+		t.Fatalf("Save comment failed: %v", err)
+	}
+
+	// Simulate a bulk import that bypassed the index
+	if _, err := db.DB().ExecContext(ctx, `DELETE FROM ticket_search`); err != nil {
+		t.Fatalf("clearing index failed: %v", err)
+	}
+	if got := searchKeys(t, search, "payment"); len(got) != 0 {
+		t.Fatalf("expected empty index, got %v", got)
+	}
+
+	indexed, err := search.Rebuild(ctx)
+	if err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+	if indexed != 2 {
+		t.Errorf("Rebuild: indexed %d tickets, want 2", indexed)
+	}
+
+	if got := searchKeys(t, search, "payment"); strings.Join(got, ",") != "JMD-1,JMD-2" {
+		t.Errorf("after rebuild: got %v", got)
+	}
+	if got := searchKeys(t, search, "staging"); strings.Join(got, ",") != "JMD-2" {
+		t.Errorf("comments not rebuilt: got %v", got)
+	}
+
+	// Rebuilding again must not duplicate documents
+	if _, err := search.Rebuild(ctx); err != nil {
+		t.Fatalf("second Rebuild failed: %v", err)
+	}
+	if got := searchKeys(t, search, "payment"); len(got) != 2 {
+		t.Errorf("after second rebuild: got %v", got)
+	}
+}
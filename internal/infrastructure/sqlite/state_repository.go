@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/esfisher/jiramd/internal/domain"
@@ -48,35 +49,62 @@ func (r *StateRepository) SaveTicketState(ctx context.Context, state *repository
 	if state.TicketKey == "" {
 		return fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
 	}
+	if state.ProjectKey == "" {
+		state.ProjectKey = projectKeyOf(state.TicketKey)
+	}
 
 	exec := r.getExecutor(ctx)
 
 	query := `
 		INSERT INTO ticket_sync_state (
 			ticket_key,
+			project_key,
 			last_synced,
 			last_modified_local,
 			last_modified_jira,
 			is_dirty,
 			conflict_detected,
+			content_hash,
+			content_hash_version,
+			jira_version,
+			file_path,
+			pull_only,
+			watched,
+			deleted_at,
 			updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 		ON CONFLICT(ticket_key) DO UPDATE SET
+			project_key = excluded.project_key,
 			last_synced = excluded.last_synced,
 			last_modified_local = excluded.last_modified_local,
 			last_modified_jira = excluded.last_modified_jira,
 			is_dirty = excluded.is_dirty,
 			conflict_detected = excluded.conflict_detected,
+			content_hash = excluded.content_hash,
+			content_hash_version = excluded.content_hash_version,
+			jira_version = excluded.jira_version,
+			file_path = excluded.file_path,
+			pull_only = excluded.pull_only,
+			watched = excluded.watched,
+			deleted_at = excluded.deleted_at,
 			updated_at = CURRENT_TIMESTAMP
 	`
 
 	_, err := exec.ExecContext(ctx, query,
 		state.TicketKey,
+		state.ProjectKey,
 		formatTimestamp(state.LastSynced),
 		formatTimestamp(state.LastModifiedLocal),
 		formatTimestamp(state.LastModifiedJira),
 		state.IsDirty,
 		state.ConflictDetected,
+		state.ContentHash,
+		state.HashVersion,
+		state.JiraVersion,
+		state.FilePath,
+		state.PullOnly,
+		state.Watched,
+		formatTimestampNullable(state.DeletedAt),
 	)
 	if err != nil {
 		r.logger.Error("failed to save ticket state",
@@ -103,28 +131,12 @@ func (r *StateRepository) GetTicketState(ctx context.Context, ticketKey string)
 	exec := r.getExecutor(ctx)
 
 	query := `
-		SELECT
-			ticket_key,
-			last_synced,
-			last_modified_local,
-			last_modified_jira,
-			is_dirty,
-			conflict_detected
+		SELECT ` + ticketStateColumns + `
 		FROM ticket_sync_state
 		WHERE ticket_key = ?
 	`
 
-	var state repository.TicketSyncState
-	var lastSynced, lastModifiedLocal, lastModifiedJira string
-
-	err := exec.QueryRowContext(ctx, query, ticketKey).Scan(
-		&state.TicketKey,
-		&lastSynced,
-		&lastModifiedLocal,
-		&lastModifiedJira,
-		&state.IsDirty,
-		&state.ConflictDetected,
-	)
+	state, err := scanTicketState(exec.QueryRowContext(ctx, query, ticketKey))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%w: ticket state not found for key %s", domain.ErrNotFound, ticketKey)
@@ -135,12 +147,7 @@ func (r *StateRepository) GetTicketState(ctx context.Context, ticketKey string)
 		return nil, fmt.Errorf("failed to get ticket state: %w", err)
 	}
 
-	// Parse timestamps
-	state.LastSynced = parseTimestamp(lastSynced)
-	state.LastModifiedLocal = parseTimestamp(lastModifiedLocal)
-	state.LastModifiedJira = parseTimestamp(lastModifiedJira)
-
-	return &state, nil
+	return state, nil
 }
 
 // GetTicketsModifiedSince retrieves all tickets with local modifications after the given time.
@@ -149,13 +156,7 @@ func (r *StateRepository) GetTicketsModifiedSince(ctx context.Context, since tim
 	exec := r.getExecutor(ctx)
 
 	query := `
-		SELECT
-			ticket_key,
-			last_synced,
-			last_modified_local,
-			last_modified_jira,
-			is_dirty,
-			conflict_detected
+		SELECT ` + ticketStateColumns + `
 		FROM ticket_sync_state
 		WHERE last_modified_local > ?
 		ORDER BY last_modified_local DESC
@@ -179,13 +180,7 @@ func (r *StateRepository) GetDirtyTickets(ctx context.Context) ([]*repository.Ti
 	exec := r.getExecutor(ctx)
 
 	query := `
-		SELECT
-			ticket_key,
-			last_synced,
-			last_modified_local,
-			last_modified_jira,
-			is_dirty,
-			conflict_detected
+		SELECT ` + ticketStateColumns + `
 		FROM ticket_sync_state
 		WHERE is_dirty = 1
 		ORDER BY last_modified_local DESC
@@ -207,13 +202,7 @@ func (r *StateRepository) GetConflictedTickets(ctx context.Context) ([]*reposito
 	exec := r.getExecutor(ctx)
 
 	query := `
-		SELECT
-			ticket_key,
-			last_synced,
-			last_modified_local,
-			last_modified_jira,
-			is_dirty,
-			conflict_detected
+		SELECT ` + ticketStateColumns + `
 		FROM ticket_sync_state
 		WHERE conflict_detected = 1
 		ORDER BY last_modified_local DESC
@@ -229,6 +218,94 @@ func (r *StateRepository) GetConflictedTickets(ctx context.Context) ([]*reposito
 	return r.scanTicketStates(rows)
 }
 
+// GetWatchedTickets retrieves the states of all watched tickets, in key order.
+// Implements repository.StateRepository.GetWatchedTickets.
+func (r *StateRepository) GetWatchedTickets(ctx context.Context) ([]*repository.TicketSyncState, error) {
+	exec := r.getExecutor(ctx)
+
+	query := `
+		SELECT ` + ticketStateColumns + `
+		FROM ticket_sync_state
+		WHERE watched = 1
+		ORDER BY ticket_key
+	`
+
+	rows, err := exec.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to query watched tickets", "error", err)
+		return nil, fmt.Errorf("failed to query watched tickets: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTicketStates(rows)
+}
+
+// GetTicketStatesByProject retrieves the states of all tickets in a project.
+// Implements repository.StateRepository.GetTicketStatesByProject.
+func (r *StateRepository) GetTicketStatesByProject(ctx context.Context, projectKey string) ([]*repository.TicketSyncState, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := r.getExecutor(ctx)
+
+	// Order by issue number so JMD-9 comes before JMD-10
+	query := `
+		SELECT ` + ticketStateColumns + `
+		FROM ticket_sync_state
+		WHERE project_key = ?
+		ORDER BY CAST(substr(ticket_key, length(project_key) + 2) AS INTEGER), ticket_key
+	`
+
+	rows, err := exec.QueryContext(ctx, query, projectKey)
+	if err != nil {
+		r.logger.Error("failed to query project ticket states",
+			"project_key", projectKey,
+			"error", err)
+		return nil, fmt.Errorf("failed to query project ticket states: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanTicketStates(rows)
+}
+
+// ListTicketKeys returns the keys of all tracked tickets that start with prefix, in key order.
+// An empty prefix returns every key. This backs shell completion and is not part of
+// repository.StateRepository.
+func (r *StateRepository) ListTicketKeys(ctx context.Context, prefix string) ([]string, error) {
+	exec := r.getExecutor(ctx)
+
+	// substr avoids treating % and _ in the prefix as LIKE wildcards
+	query := `
+		SELECT ticket_key
+		FROM ticket_sync_state
+		WHERE substr(ticket_key, 1, length(?)) = ?
+		ORDER BY ticket_key
+	`
+
+	rows, err := exec.QueryContext(ctx, query, prefix, prefix)
+	if err != nil {
+		r.logger.Error("failed to query ticket keys", "error", err)
+		return nil, fmt.Errorf("failed to query ticket keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan ticket key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ticket keys: %w", err)
+	}
+
+	return keys, nil
+}
+
 // DeleteTicketState removes the synchronization state for a ticket.
 // Implements repository.StateRepository.DeleteTicketState.
 func (r *StateRepository) DeleteTicketState(ctx context.Context, ticketKey string) error {
@@ -261,6 +338,30 @@ func (r *StateRepository) DeleteTicketState(ctx context.Context, ticketKey strin
 	return nil
 }
 
+// PruneTombstones removes ticket states that were tombstoned before the given time.
+// Implements repository.StateRepository.PruneTombstones.
+func (r *StateRepository) PruneTombstones(ctx context.Context, before time.Time) (int, error) {
+	exec := r.getExecutor(ctx)
+
+	query := `DELETE FROM ticket_sync_state WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+
+	result, err := exec.ExecContext(ctx, query, formatTimestamp(before))
+	if err != nil {
+		r.logger.Error("failed to prune ticket tombstones",
+			"before", before,
+			"error", err)
+		return 0, fmt.Errorf("failed to prune ticket tombstones: %w", err)
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.Debug("pruned ticket tombstones", "before", before, "count", pruned)
+	return int(pruned), nil
+}
+
 // SaveProjectState persists the synchronization state of a project.
 // Implements repository.StateRepository.SaveProjectState.
 func (r *StateRepository) SaveProjectState(ctx context.Context, state *repository.ProjectSyncState) error {
@@ -432,8 +533,7 @@ func (r *StateRepository) DeleteProjectState(ctx context.Context, projectKey str
 	}
 
 	// Delete all ticket states for this project first
-	// Note: This assumes ticket keys start with project key (e.g., "JMD-123")
-	deleteTicketsQuery := `DELETE FROM ticket_sync_state WHERE ticket_key LIKE ? || '-%'`
+	deleteTicketsQuery := `DELETE FROM ticket_sync_state WHERE project_key = ?`
 	if _, err := exec.ExecContext(ctx, deleteTicketsQuery, projectKey); err != nil {
 		r.logger.Error("failed to delete project ticket states",
 			"project_key", projectKey,
@@ -526,10 +626,7 @@ func (r *StateRepository) Rollback(ctx context.Context) error {
 
 // getTransaction extracts transaction from context.
 func (r *StateRepository) getTransaction(ctx context.Context) *sql.Tx {
-	if tx, ok := ctx.Value(txContextKey).(*sql.Tx); ok {
-		return tx
-	}
-	return nil
+	return transactionFromContext(ctx)
 }
 
 // isInTransaction checks if context has an active transaction.
@@ -539,10 +636,24 @@ func (r *StateRepository) isInTransaction(ctx context.Context) bool {
 
 // getExecutor returns the appropriate executor (transaction or database).
 func (r *StateRepository) getExecutor(ctx context.Context) executor {
-	if tx := r.getTransaction(ctx); tx != nil {
+	return executorFor(ctx, r.db)
+}
+
+// transactionFromContext extracts the transaction started by StateRepository.BeginTransaction.
+// Every repository in this package uses it, so they all join the same transaction.
+func transactionFromContext(ctx context.Context) *sql.Tx {
+	if tx, ok := ctx.Value(txContextKey).(*sql.Tx); ok {
+		return tx
+	}
+	return nil
+}
+
+// executorFor returns the transaction in ctx, or db when there is none.
+func executorFor(ctx context.Context, db *sql.DB) executor {
+	if tx := transactionFromContext(ctx); tx != nil {
 		return tx
 	}
-	return r.db
+	return db
 }
 
 // executor is an interface that both *sql.DB and *sql.Tx implement.
@@ -557,26 +668,11 @@ func (r *StateRepository) scanTicketStates(rows *sql.Rows) ([]*repository.Ticket
 	var states []*repository.TicketSyncState
 
 	for rows.Next() {
-		var state repository.TicketSyncState
-		var lastSynced, lastModifiedLocal, lastModifiedJira string
-
-		if err := rows.Scan(
-			&state.TicketKey,
-			&lastSynced,
-			&lastModifiedLocal,
-			&lastModifiedJira,
-			&state.IsDirty,
-			&state.ConflictDetected,
-		); err != nil {
+		state, err := scanTicketState(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan ticket state: %w", err)
 		}
-
-		// Parse timestamps
-		state.LastSynced = parseTimestamp(lastSynced)
-		state.LastModifiedLocal = parseTimestamp(lastModifiedLocal)
-		state.LastModifiedJira = parseTimestamp(lastModifiedJira)
-
-		states = append(states, &state)
+		states = append(states, state)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -586,6 +682,53 @@ func (r *StateRepository) scanTicketStates(rows *sql.Rows) ([]*repository.Ticket
 	return states, nil
 }
 
+// ticketStateColumns lists the columns read by every ticket state query, in scan order.
+const ticketStateColumns = `ticket_key, project_key, last_synced, last_modified_local, ` +
+	`last_modified_jira, is_dirty, conflict_detected, content_hash, content_hash_version, ` +
+	`jira_version, file_path, pull_only, watched, deleted_at`
+
+// scanTicketState reads one ticket state in ticketStateColumns order.
+func scanTicketState(row rowScanner) (*repository.TicketSyncState, error) {
+	var state repository.TicketSyncState
+	var lastSynced, lastModifiedLocal, lastModifiedJira string
+	var deletedAt sql.NullString
+
+	if err := row.Scan(
+		&state.TicketKey,
+		&state.ProjectKey,
+		&lastSynced,
+		&lastModifiedLocal,
+		&lastModifiedJira,
+		&state.IsDirty,
+		&state.ConflictDetected,
+		&state.ContentHash,
+		&state.HashVersion,
+		&state.JiraVersion,
+		&state.FilePath,
+		&state.PullOnly,
+		&state.Watched,
+		&deletedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	// Parse timestamps
+	state.LastSynced = parseTimestamp(lastSynced)
+	state.LastModifiedLocal = parseTimestamp(lastModifiedLocal)
+	state.LastModifiedJira = parseTimestamp(lastModifiedJira)
+	if deletedAt.Valid {
+		state.DeletedAt = parseTimestamp(deletedAt.String)
+	}
+
+	return &state, nil
+}
+
+// projectKeyOf returns the project portion of a ticket key (e.g., "JMD" from "JMD-123").
+func projectKeyOf(ticketKey string) string {
+	projectKey, _, _ := strings.Cut(ticketKey, "-")
+	return projectKey
+}
+
 // formatTimestamp converts time.Time to SQLite timestamp string.
 func formatTimestamp(t time.Time) string {
 	if t.IsZero() {
@@ -602,6 +745,16 @@ func formatTimestampNullable(t time.Time) interface{} {
 	return formatTimestamp(t)
 }
 
+// normalizeParsedTimestamp converts a parsed timestamp to UTC, mapping the epoch that
+// formatTimestamp stores for zero times back to the zero time. The driver returns
+// TIMESTAMP columns in RFC3339, so the sentinel does not always match as a string.
+func normalizeParsedTimestamp(t time.Time) time.Time {
+	if t.Unix() == 0 {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
 // parseTimestamp converts SQLite timestamp string to time.Time.
 func parseTimestamp(s string) time.Time {
 	if s == "" || s == "1970-01-01 00:00:00" {
@@ -611,19 +764,19 @@ func parseTimestamp(s string) time.Time {
 	// Try RFC3339 format first (what SQLite may return)
 	t, err := time.Parse(time.RFC3339, s)
 	if err == nil {
-		return t.UTC()
+		return normalizeParsedTimestamp(t)
 	}
 
 	// Try parsing with milliseconds
 	t, err = time.Parse("2006-01-02 15:04:05.000", s)
 	if err == nil {
-		return t.UTC()
+		return normalizeParsedTimestamp(t)
 	}
 
 	// Fall back to seconds precision
 	t, err = time.Parse("2006-01-02 15:04:05", s)
 	if err == nil {
-		return t.UTC()
+		return normalizeParsedTimestamp(t)
 	}
 
 	// Log warning and return zero time
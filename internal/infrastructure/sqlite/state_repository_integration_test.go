@@ -411,15 +411,16 @@ func TestIntegration_MigrationIdempotence(t *testing.T) {
 			t.Fatalf("second migration failed: %v", err)
 		}
 
-		// Verify schema version is still 1
+		// Verify schema version is still the latest migration
+		latest := migrations[len(migrations)-1].Version
 		var version int
 		err = db.DB().QueryRowContext(ctx, "SELECT MAX(version) FROM schema_version").Scan(&version)
 		if err != nil {
 			t.Fatalf("failed to query version: %v", err)
 		}
 
-		if version != 1 {
-			t.Errorf("expected version 1, got %d", version)
+		if version != latest {
+			t.Errorf("expected version %d, got %d", latest, version)
 		}
 	}
 }
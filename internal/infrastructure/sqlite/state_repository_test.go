@@ -2,6 +2,8 @@ package sqlite
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -416,6 +418,260 @@ func TestStateRepository_DeleteTicketState_NotFound(t *testing.T) {
 	}
 }
 
+func TestStateRepository_ContentHashAndVersion(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	key, _ := domain.NewTicketKey("JMD-1")
+	updated := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	ticket := domain.NewTicket(key, "Summary", updated, updated)
+
+	state := &repository.TicketSyncState{TicketKey: "JMD-1", IsDirty: true, FilePath: "sprint/JMD-1.md", PullOnly: true}
+	state.RecordSynced(ticket, updated.Add(time.Minute))
+	if err := repo.SaveTicketState(ctx, state); err != nil {
+		t.Fatalf("SaveTicketState failed: %v", err)
+	}
+
+	got, err := repo.GetTicketState(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicketState failed: %v", err)
+	}
+	if got.ContentHash != ticket.ContentHash() {
+		t.Errorf("ContentHash = %q, want %q", got.ContentHash, ticket.ContentHash())
+	}
+	if got.HashVersion != domain.CurrentHashVersion {
+		t.Errorf("HashVersion = %d, want %d", got.HashVersion, domain.CurrentHashVersion)
+	}
+	if got.JiraVersion != ticket.Version() {
+		t.Errorf("JiraVersion = %q, want %q", got.JiraVersion, ticket.Version())
+	}
+	if got.FilePath != "sprint/JMD-1.md" {
+		t.Errorf("FilePath = %q, want sprint/JMD-1.md", got.FilePath)
+	}
+	if !got.PullOnly {
+		t.Error("PullOnly = false, want true")
+	}
+	if got.IsDirty || got.RemoteChanged(ticket.Version()) || got.LocalChanged(ticket) {
+		t.Errorf("reloaded state should match the synced ticket: %+v", got)
+	}
+
+	// Saving again without a hash clears it
+	got.ContentHash = ""
+	if err := repo.SaveTicketState(ctx, got); err != nil {
+		t.Fatalf("SaveTicketState failed: %v", err)
+	}
+	got, err = repo.GetTicketState(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicketState failed: %v", err)
+	}
+	if got.ContentHash != "" || got.JiraVersion != ticket.Version() {
+		t.Errorf("ContentHash = %q, JiraVersion = %q after update", got.ContentHash, got.JiraVersion)
+	}
+}
+
+func TestStateRepository_PruneTombstones(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	states := []*repository.TicketSyncState{
+		{TicketKey: "JMD-1", LastSynced: now, DeletedAt: now.Add(-100 * 24 * time.Hour)},
+		{TicketKey: "JMD-2", LastSynced: now, DeletedAt: now.Add(-time.Hour)},
+		{TicketKey: "JMD-3", LastSynced: now},
+	}
+	for _, state := range states {
+		if err := repo.SaveTicketState(ctx, state); err != nil {
+			t.Fatalf("SaveTicketState failed: %v", err)
+		}
+	}
+
+	tombstone, err := repo.GetTicketState(ctx, "JMD-2")
+	if err != nil {
+		t.Fatalf("GetTicketState failed: %v", err)
+	}
+	if !tombstone.IsTombstone() || !tombstone.DeletedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("DeletedAt = %v, want %v", tombstone.DeletedAt, now.Add(-time.Hour))
+	}
+
+	pruned, err := repo.PruneTombstones(ctx, now.Add(-90*24*time.Hour))
+	if err != nil {
+		t.Fatalf("PruneTombstones failed: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("PruneTombstones removed %d states, want 1", pruned)
+	}
+
+	if _, err := repo.GetTicketState(ctx, "JMD-1"); !domain.IsNotFoundError(err) {
+		t.Errorf("expected old tombstone to be pruned, got: %v", err)
+	}
+	for _, key := range []string{"JMD-2", "JMD-3"} {
+		if _, err := repo.GetTicketState(ctx, key); err != nil {
+			t.Errorf("expected %s to be kept: %v", key, err)
+		}
+	}
+
+	// Live ticket states are never pruned
+	if pruned, err := repo.PruneTombstones(ctx, now.Add(time.Hour)); err != nil || pruned != 1 {
+		t.Errorf("PruneTombstones() = %d, %v; want 1, nil", pruned, err)
+	}
+	if _, err := repo.GetTicketState(ctx, "JMD-3"); err != nil {
+		t.Errorf("expected live state to be kept: %v", err)
+	}
+}
+
+func TestStateRepository_GetWatchedTickets(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	for _, state := range []*repository.TicketSyncState{
+		{TicketKey: "OPS-7", Watched: true, FilePath: "watchlist/OPS-7.md"},
+		{TicketKey: "JMD-1"},
+		{TicketKey: "JMD-2", Watched: true},
+	} {
+		if err := repo.SaveTicketState(ctx, state); err != nil {
+			t.Fatalf("failed to save ticket %s: %v", state.TicketKey, err)
+		}
+	}
+
+	watched, err := repo.GetWatchedTickets(ctx)
+	if err != nil {
+		t.Fatalf("GetWatchedTickets failed: %v", err)
+	}
+	var keys []string
+	for _, state := range watched {
+		if !state.Watched {
+			t.Errorf("%s is not watched", state.TicketKey)
+		}
+		keys = append(keys, state.TicketKey)
+	}
+	if strings.Join(keys, ",") != "JMD-2,OPS-7" {
+		t.Errorf("watched tickets = %v, want JMD-2 and OPS-7 in key order", keys)
+	}
+}
+
+func TestStateRepository_GetTicketStatesByProject(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for _, key := range []string{"JMD-10", "JMD-9", "JMDX-1", "OPS-1"} {
+		if err := repo.SaveTicketState(ctx, &repository.TicketSyncState{TicketKey: key, LastSynced: now}); err != nil {
+			t.Fatalf("SaveTicketState failed: %v", err)
+		}
+	}
+
+	states, err := repo.GetTicketStatesByProject(ctx, "JMD")
+	if err != nil {
+		t.Fatalf("GetTicketStatesByProject failed: %v", err)
+	}
+
+	var keys []string
+	for _, state := range states {
+		keys = append(keys, state.TicketKey)
+		if state.ProjectKey != "JMD" {
+			t.Errorf("%s ProjectKey = %q, want %q", state.TicketKey, state.ProjectKey, "JMD")
+		}
+	}
+	if strings.Join(keys, ",") != "JMD-9,JMD-10" {
+		t.Errorf("keys = %v, want [JMD-9 JMD-10]", keys)
+	}
+
+	states, err = repo.GetTicketStatesByProject(ctx, "NONE")
+	if err != nil {
+		t.Fatalf("GetTicketStatesByProject failed: %v", err)
+	}
+	if len(states) != 0 {
+		t.Errorf("expected no states for unknown project, got %d", len(states))
+	}
+
+	if _, err := repo.GetTicketStatesByProject(ctx, ""); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("expected ErrEmptyKey, got: %v", err)
+	}
+}
+
+func TestStateRepository_DeleteProjectState_KeepsOtherProjects(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if err := repo.SaveProjectState(ctx, &repository.ProjectSyncState{ProjectKey: "DEL"}); err != nil {
+		t.Fatalf("SaveProjectState failed: %v", err)
+	}
+	for _, key := range []string{"DEL-1", "DELX-1"} {
+		if err := repo.SaveTicketState(ctx, &repository.TicketSyncState{TicketKey: key, LastSynced: now}); err != nil {
+			t.Fatalf("SaveTicketState failed: %v", err)
+		}
+	}
+
+	if err := repo.DeleteProjectState(ctx, "DEL"); err != nil {
+		t.Fatalf("DeleteProjectState failed: %v", err)
+	}
+
+	if _, err := repo.GetTicketState(ctx, "DEL-1"); !domain.IsNotFoundError(err) {
+		t.Errorf("expected DEL-1 to be deleted, got: %v", err)
+	}
+	if _, err := repo.GetTicketState(ctx, "DELX-1"); err != nil {
+		t.Errorf("expected DELX-1 to be kept: %v", err)
+	}
+}
+
+func TestStateRepository_ListTicketKeys(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for _, key := range []string{"JMD-2", "JMD-10", "OPS-1", "JMD-1"} {
+		state := &repository.TicketSyncState{TicketKey: key, LastSynced: now}
+		if err := repo.SaveTicketState(ctx, state); err != nil {
+			t.Fatalf("failed to save ticket %s: %v", key, err)
+		}
+	}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"", []string{"JMD-1", "JMD-10", "JMD-2", "OPS-1"}},
+		{"JMD-1", []string{"JMD-1", "JMD-10"}},
+		{"OPS", []string{"OPS-1"}},
+		{"JMD_", nil},
+		{"XYZ", nil},
+	}
+
+	for _, tt := range tests {
+		keys, err := repo.ListTicketKeys(ctx, tt.prefix)
+		if err != nil {
+			t.Fatalf("ListTicketKeys(%q) failed: %v", tt.prefix, err)
+		}
+		if len(keys) != len(tt.want) {
+			t.Fatalf("ListTicketKeys(%q) = %v, want %v", tt.prefix, keys, tt.want)
+		}
+		for i := range keys {
+			if keys[i] != tt.want[i] {
+				t.Errorf("ListTicketKeys(%q)[%d] = %s, want %s", tt.prefix, i, keys[i], tt.want[i])
+			}
+		}
+	}
+}
+
 func TestStateRepository_SaveAndGetProjectState(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -711,3 +967,35 @@ func TestStateRepository_ValidationErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestStateRepository_ZeroTimestampsRoundTrip(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewStateRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	// Only LastModifiedLocal is set; the other timestamps are stored as the epoch sentinel
+	state := &repository.TicketSyncState{
+		TicketKey:         "JMD-1",
+		LastModifiedLocal: time.Now().UTC().Truncate(time.Millisecond),
+		IsDirty:           true,
+	}
+	if err := repo.SaveTicketState(ctx, state); err != nil {
+		t.Fatalf("SaveTicketState failed: %v", err)
+	}
+
+	got, err := repo.GetTicketState(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("GetTicketState failed: %v", err)
+	}
+	if !got.LastSynced.IsZero() {
+		t.Errorf("LastSynced: got %v, want zero", got.LastSynced)
+	}
+	if !got.LastModifiedJira.IsZero() {
+		t.Errorf("LastModifiedJira: got %v, want zero", got.LastModifiedJira)
+	}
+	if got.LastModifiedLocal.IsZero() {
+		t.Error("LastModifiedLocal should not be zero")
+	}
+}
@@ -0,0 +1,160 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// StatusHistoryRepository implements repository.StatusHistoryRepository using SQLite.
+// Changelogs are removed with their ticket when it leaves the cache.
+type StatusHistoryRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewStatusHistoryRepository creates a new SQLite-based status history repository.
+// The database connection must be initialized and migrations applied before use.
+func NewStatusHistoryRepository(db *sql.DB, logger *slog.Logger) *StatusHistoryRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &StatusHistoryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Verify that StatusHistoryRepository implements the repository.StatusHistoryRepository interface
+var _ repository.StatusHistoryRepository = (*StatusHistoryRepository)(nil)
+
+// statusChangeColumns lists the columns read by every status change query, in scan order.
+const statusChangeColumns = `ticket_key, change_id, from_status, to_status, author, changed_at`
+
+// ReplaceStatusHistory replaces the cached changelog of a ticket, in one transaction.
+// Implements repository.StatusHistoryRepository.ReplaceStatusHistory.
+func (r *StatusHistoryRepository) ReplaceStatusHistory(ctx context.Context, ticketKey string, history []domain.StatusChange) error {
+	if strings.TrimSpace(ticketKey) == "" {
+		return fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	// Replace in transaction if not already in one
+	inTransaction := transactionFromContext(ctx) != nil
+	if !inTransaction {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		exec = tx
+	}
+
+	if _, err := exec.ExecContext(ctx, `DELETE FROM status_changes WHERE ticket_key = ?`, ticketKey); err != nil {
+		r.logger.Error("failed to clear status history", "ticket_key", ticketKey, "error", err)
+		return fmt.Errorf("failed to clear status history: %w", err)
+	}
+
+	sorted := append([]domain.StatusChange(nil), history...)
+	domain.SortStatusHistory(sorted)
+
+	query := `
+		INSERT INTO status_changes (
+			ticket_key,
+			seq,
+			change_id,
+			from_status,
+			to_status,
+			author,
+			changed_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	for i, change := range sorted {
+		_, err := exec.ExecContext(ctx, query,
+			ticketKey,
+			i,
+			change.ID,
+			change.From,
+			change.To,
+			change.Author,
+			formatTimestamp(change.At),
+		)
+		if err != nil {
+			r.logger.Error("failed to save status change", "ticket_key", ticketKey, "error", err)
+			return fmt.Errorf("failed to save status change: %w", err)
+		}
+	}
+
+	// Commit if we started the transaction
+	if !inTransaction {
+		if err := exec.(*sql.Tx).Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	r.logger.Debug("saved status history", "ticket_key", ticketKey, "changes", len(sorted))
+	return nil
+}
+
+// FindStatusHistory retrieves the cached changelog of a ticket, oldest first.
+// Implements repository.StatusHistoryRepository.FindStatusHistory.
+func (r *StatusHistoryRepository) FindStatusHistory(ctx context.Context, ticketKey string) ([]domain.StatusChange, error) {
+	if strings.TrimSpace(ticketKey) == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	history, err := r.query(ctx, `WHERE ticket_key = ?`, ticketKey)
+	if err != nil {
+		return nil, err
+	}
+	if changes, ok := history[ticketKey]; ok {
+		return changes, nil
+	}
+	return make([]domain.StatusChange, 0), nil
+}
+
+// FindAllStatusHistory retrieves the cached changelog of every ticket, oldest first.
+// Implements repository.StatusHistoryRepository.FindAllStatusHistory.
+func (r *StatusHistoryRepository) FindAllStatusHistory(ctx context.Context) (map[string][]domain.StatusChange, error) {
+	return r.query(ctx, ``)
+}
+
+// query reads the status changes matching where, grouped by ticket key.
+func (r *StatusHistoryRepository) query(ctx context.Context, where string, args ...interface{}) (map[string][]domain.StatusChange, error) {
+	exec := executorFor(ctx, r.db)
+
+	query := `SELECT ` + statusChangeColumns + ` FROM status_changes ` + where + ` ORDER BY ticket_key, seq`
+
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to query status history", "error", err)
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make(map[string][]domain.StatusChange)
+	for rows.Next() {
+		var (
+			key, changedAt string
+			change         domain.StatusChange
+		)
+		if err := rows.Scan(&key, &change.ID, &change.From, &change.To, &change.Author, &changedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		change.At = parseTimestamp(changedAt)
+		history[key] = append(history[key], change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate status history: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestStatusHistoryRepository_ReplaceAndFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewStatusHistoryRepository(db.DB(), nil)
+	ctx := context.Background()
+	at := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+
+	history := []domain.StatusChange{
+		{ID: "201", From: "In Progress", To: "Done", Author: "bob", At: at.Add(2 * time.Hour)},
+		{ID: "200", From: "To Do", To: "In Progress", Author: "alice", At: at},
+	}
+	if err := repo.ReplaceStatusHistory(ctx, "JMD-1", history); err != nil {
+		t.Fatalf("ReplaceStatusHistory failed: %v", err)
+	}
+	if err := repo.ReplaceStatusHistory(ctx, "JMD-2", history[:1]); err != nil {
+		t.Fatalf("ReplaceStatusHistory failed: %v", err)
+	}
+
+	got, err := repo.FindStatusHistory(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindStatusHistory failed: %v", err)
+	}
+	if len(got) != 2 || got[0] != history[1] || got[1] != history[0] {
+		t.Errorf("FindStatusHistory() = %+v, want oldest first", got)
+	}
+
+	// Replacing drops changes no longer in the changelog
+	if err := repo.ReplaceStatusHistory(ctx, "JMD-1", history[1:]); err != nil {
+		t.Fatalf("ReplaceStatusHistory failed: %v", err)
+	}
+	all, err := repo.FindAllStatusHistory(ctx)
+	if err != nil {
+		t.Fatalf("FindAllStatusHistory failed: %v", err)
+	}
+	if len(all) != 2 || len(all["JMD-1"]) != 1 || all["JMD-1"][0].ID != "200" || len(all["JMD-2"]) != 1 {
+		t.Errorf("FindAllStatusHistory() = %+v", all)
+	}
+
+	none, err := repo.FindStatusHistory(ctx, "JMD-9")
+	if err != nil {
+		t.Fatalf("FindStatusHistory failed: %v", err)
+	}
+	if none == nil || len(none) != 0 {
+		t.Errorf("expected empty slice, got %v", none)
+	}
+
+	if err := repo.ReplaceStatusHistory(ctx, " ", nil); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("ReplaceStatusHistory(empty key) error = %v, want ErrEmptyKey", err)
+	}
+}
+
+func TestStatusHistoryRepository_DeletedWithTicket(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tickets := NewTicketRepository(db.DB(), nil)
+	repo := NewStatusHistoryRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	ticket := newTestTicket(t, "JMD-1", "Changelog")
+	if err := tickets.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	change := domain.StatusChange{ID: "200", From: "To Do", To: "Done", At: ticket.Created.Add(time.Hour)}
+	if err := repo.ReplaceStatusHistory(ctx, "JMD-1", []domain.StatusChange{change}); err != nil {
+		t.Fatalf("ReplaceStatusHistory failed: %v", err)
+	}
+
+	if err := tickets.Delete(ctx, "JMD-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	got, err := repo.FindStatusHistory(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindStatusHistory failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected the changelog to be deleted with the ticket, got %+v", got)
+	}
+}
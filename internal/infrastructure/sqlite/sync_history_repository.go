@@ -0,0 +1,172 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// SyncHistoryRepository implements repository.SyncHistoryRepository using SQLite.
+type SyncHistoryRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewSyncHistoryRepository creates a new SQLite-backed sync history.
+// The database connection must be initialized and migrations applied before use.
+func NewSyncHistoryRepository(db *sql.DB, logger *slog.Logger) *SyncHistoryRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SyncHistoryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Verify that SyncHistoryRepository implements the repository interface
+var _ repository.SyncHistoryRepository = (*SyncHistoryRepository)(nil)
+
+// Record appends an entry to the history and sets its ID.
+// Implements repository.SyncHistoryRepository.Record.
+func (r *SyncHistoryRepository) Record(ctx context.Context, entry *repository.SyncHistoryEntry) error {
+	if entry == nil {
+		return fmt.Errorf("%w: history entry cannot be nil", domain.ErrInvalidInput)
+	}
+	if strings.TrimSpace(entry.ProjectKey) == "" {
+		return fmt.Errorf("%w: project key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	query := `
+		INSERT INTO sync_history (
+			project_key,
+			full_sync,
+			started_at,
+			finished_at,
+			succeeded,
+			failed,
+			conflicts,
+			error
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := exec.ExecContext(ctx, query,
+		entry.ProjectKey,
+		entry.Full,
+		formatTimestamp(entry.StartedAt),
+		formatTimestamp(entry.FinishedAt),
+		entry.Succeeded,
+		entry.Failed,
+		entry.Conflicts,
+		entry.Error,
+	)
+	if err != nil {
+		r.logger.Error("failed to record sync history",
+			"project_key", entry.ProjectKey,
+			"error", err)
+		return fmt.Errorf("failed to record sync history: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get history entry id: %w", err)
+	}
+	entry.ID = id
+
+	return nil
+}
+
+// Recent retrieves at most limit entries, most recently finished first.
+// Implements repository.SyncHistoryRepository.Recent.
+func (r *SyncHistoryRepository) Recent(ctx context.Context, limit int) ([]*repository.SyncHistoryEntry, error) {
+	if limit <= 0 {
+		limit = -1 // no limit
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	query := `
+		SELECT
+			id,
+			project_key,
+			full_sync,
+			started_at,
+			finished_at,
+			succeeded,
+			failed,
+			conflicts,
+			error
+		FROM sync_history
+		ORDER BY finished_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := exec.QueryContext(ctx, query, limit)
+	if err != nil {
+		r.logger.Error("failed to query sync history", "error", err)
+		return nil, fmt.Errorf("failed to query sync history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*repository.SyncHistoryEntry, 0)
+	for rows.Next() {
+		var entry repository.SyncHistoryEntry
+		var startedAt, finishedAt string
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.ProjectKey,
+			&entry.Full,
+			&startedAt,
+			&finishedAt,
+			&entry.Succeeded,
+			&entry.Failed,
+			&entry.Conflicts,
+			&entry.Error,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan sync history entry: %w", err)
+		}
+
+		entry.StartedAt = parseTimestamp(startedAt)
+		entry.FinishedAt = parseTimestamp(finishedAt)
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sync history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// PruneBefore removes entries of runs that finished before the given time.
+// Implements repository.SyncHistoryRepository.PruneBefore.
+func (r *SyncHistoryRepository) PruneBefore(ctx context.Context, before time.Time) (int, error) {
+	exec := executorFor(ctx, r.db)
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM sync_history WHERE finished_at < ?`, formatTimestamp(before))
+	if err != nil {
+		r.logger.Error("failed to prune sync history",
+			"before", before,
+			"error", err)
+		return 0, fmt.Errorf("failed to prune sync history: %w", err)
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.Debug("pruned sync history", "before", before, "count", pruned)
+	return int(pruned), nil
+}
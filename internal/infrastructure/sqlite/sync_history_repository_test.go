@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+func TestSyncHistoryRepository_RecordAndRecent(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSyncHistoryRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	first := &repository.SyncHistoryEntry{
+		ProjectKey: "JMD",
+		StartedAt:  now.Add(-2 * time.Hour),
+		FinishedAt: now.Add(-2*time.Hour + time.Minute),
+		Succeeded:  3,
+	}
+	second := &repository.SyncHistoryEntry{
+		ProjectKey: "JMD",
+		Full:       true,
+		StartedAt:  now.Add(-time.Hour),
+		FinishedAt: now.Add(-time.Hour + time.Minute),
+		Succeeded:  10,
+		Failed:     1,
+		Conflicts:  2,
+		Error:      "jira unavailable",
+	}
+	for _, entry := range []*repository.SyncHistoryEntry{first, second} {
+		if err := repo.Record(ctx, entry); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		if entry.ID == 0 {
+			t.Error("Record did not set ID")
+		}
+	}
+
+	entries, err := repo.Recent(ctx, 0)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	// Most recently finished first, with every field round tripped
+	got := entries[0]
+	if got.ID != second.ID || !got.Full || got.Succeeded != 10 || got.Failed != 1 || got.Conflicts != 2 || got.Error != "jira unavailable" {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+	if !got.StartedAt.Equal(second.StartedAt) || !got.FinishedAt.Equal(second.FinishedAt) {
+		t.Errorf("timestamps = %v - %v, want %v - %v", got.StartedAt, got.FinishedAt, second.StartedAt, second.FinishedAt)
+	}
+
+	limited, err := repo.Recent(ctx, 1)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if len(limited) != 1 || limited[0].ID != second.ID {
+		t.Errorf("Recent(1) = %d entries, want the latest only", len(limited))
+	}
+}
+
+func TestSyncHistoryRepository_Record_Invalid(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSyncHistoryRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	if err := repo.Record(ctx, nil); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for nil entry, got: %v", err)
+	}
+	if err := repo.Record(ctx, &repository.SyncHistoryEntry{}); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("expected ErrEmptyKey for missing project key, got: %v", err)
+	}
+}
+
+func TestSyncHistoryRepository_PruneBefore(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSyncHistoryRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, age := range []time.Duration{200 * 24 * time.Hour, 100 * 24 * time.Hour, time.Hour} {
+		entry := &repository.SyncHistoryEntry{
+			ProjectKey: "JMD",
+			StartedAt:  now.Add(-age),
+			FinishedAt: now.Add(-age),
+		}
+		if err := repo.Record(ctx, entry); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	pruned, err := repo.PruneBefore(ctx, now.Add(-90*24*time.Hour))
+	if err != nil {
+		t.Fatalf("PruneBefore failed: %v", err)
+	}
+	if pruned != 2 {
+		t.Errorf("PruneBefore removed %d entries, want 2", pruned)
+	}
+
+	entries, err := repo.Recent(ctx, 0)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if len(entries) != 1 || !entries[0].FinishedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("expected only the recent entry to remain, got %d entries", len(entries))
+	}
+}
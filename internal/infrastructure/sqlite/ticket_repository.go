@@ -4,56 +4,356 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/esfisher/jiramd/internal/domain"
 	"github.com/esfisher/jiramd/internal/domain/repository"
 )
 
 // TicketRepository implements the domain TicketRepository interface using SQLite.
+// It is the local ticket cache that lets CLI commands work without contacting Jira.
 type TicketRepository struct {
-	// TODO: Add database connection field
+	db     *sql.DB
+	logger *slog.Logger
+	cipher *Cipher
 }
 
 // NewTicketRepository creates a new SQLite-based ticket repository.
-func NewTicketRepository() *TicketRepository {
-	return &TicketRepository{}
+// The database connection must be initialized and migrations applied before use.
+func NewTicketRepository(db *sql.DB, logger *slog.Logger) *TicketRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &TicketRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithCipher makes the repository encrypt summaries, descriptions, and custom fields at rest.
+func (r *TicketRepository) WithCipher(c *Cipher) *TicketRepository {
+	r.cipher = c
+	return r
 }
 
 // Verify that TicketRepository implements the repository.TicketRepository interface
 var _ repository.TicketRepository = (*TicketRepository)(nil)
 
+// ticketColumns lists the columns read by every ticket query, in scan order.
+const ticketColumns = `
+	ticket_key,
+	summary,
+	description,
+	status,
+	issue_type,
+	priority,
+	assignee,
+	reporter,
+	labels,
+	custom_fields,
+	created,
+	updated
+`
+
 // Save persists a ticket to SQLite storage.
-// This is a placeholder for the actual implementation.
+// Creates the ticket if it is not cached yet, replaces the cached copy otherwise.
 func (r *TicketRepository) Save(ctx context.Context, ticket *domain.Ticket) error {
-	// TODO: Implement SQLite save logic
-	return fmt.Errorf("sqlite.TicketRepository.Save not implemented")
+	args, err := r.ticketArgs(ticket)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tickets (
+			ticket_key,
+			project_key,
+			summary,
+			description,
+			status,
+			issue_type,
+			priority,
+			assignee,
+			reporter,
+			labels,
+			custom_fields,
+			created,
+			updated,
+			cached_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(ticket_key) DO UPDATE SET
+			project_key = excluded.project_key,
+			summary = excluded.summary,
+			description = excluded.description,
+			status = excluded.status,
+			issue_type = excluded.issue_type,
+			priority = excluded.priority,
+			assignee = excluded.assignee,
+			reporter = excluded.reporter,
+			labels = excluded.labels,
+			custom_fields = excluded.custom_fields,
+			created = excluded.created,
+			updated = excluded.updated,
+			cached_at = CURRENT_TIMESTAMP
+	`
+
+	exec := executorFor(ctx, r.db)
+	if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+		r.logger.Error("failed to save ticket",
+			"ticket_key", ticket.Key.String(),
+			"error", err)
+		return fmt.Errorf("failed to save ticket: %w", err)
+	}
+
+	r.logger.Debug("saved ticket", "ticket_key", ticket.Key.String())
+	return nil
 }
 
 // FindByKey retrieves a ticket by its key from SQLite.
-// This is a placeholder for the actual implementation.
+// Returns ErrNotFound if the ticket is not cached.
 func (r *TicketRepository) FindByKey(ctx context.Context, key string) (*domain.Ticket, error) {
-	// TODO: Implement SQLite query logic
-	return nil, domain.ErrNotFound
+	if key == "" {
+		return nil, fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	query := `SELECT ` + ticketColumns + ` FROM tickets WHERE ticket_key = ?`
+
+	ticket, err := scanTicket(exec.QueryRowContext(ctx, query, key), r.cipher)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: ticket %s is not in the local cache", domain.ErrNotFound, key)
+		}
+		r.logger.Error("failed to get ticket",
+			"ticket_key", key,
+			"error", err)
+		return nil, fmt.Errorf("failed to get ticket: %w", err)
+	}
+
+	return ticket, nil
 }
 
-// FindAll retrieves all tickets from SQLite.
-// This is a placeholder for the actual implementation.
+// FindAll retrieves all cached tickets from SQLite, ordered by key.
 func (r *TicketRepository) FindAll(ctx context.Context) ([]*domain.Ticket, error) {
-	// TODO: Implement SQLite query logic
-	return nil, fmt.Errorf("sqlite.TicketRepository.FindAll not implemented")
+	exec := executorFor(ctx, r.db)
+
+	query := `SELECT ` + ticketColumns + ` FROM tickets ORDER BY ticket_key`
+
+	rows, err := exec.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to query tickets", "error", err)
+		return nil, fmt.Errorf("failed to query tickets: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []*domain.Ticket
+	for rows.Next() {
+		ticket, err := scanTicket(rows, r.cipher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tickets: %w", err)
+	}
+
+	return tickets, nil
 }
 
 // Delete removes a ticket from SQLite storage.
-// This is a placeholder for the actual implementation.
+// Returns ErrNotFound if the ticket is not cached.
 func (r *TicketRepository) Delete(ctx context.Context, key string) error {
-	// TODO: Implement SQLite delete logic
-	return fmt.Errorf("sqlite.TicketRepository.Delete not implemented")
+	if key == "" {
+		return fmt.Errorf("%w: ticket key cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM tickets WHERE ticket_key = ?`, key)
+	if err != nil {
+		r.logger.Error("failed to delete ticket",
+			"ticket_key", key,
+			"error", err)
+		return fmt.Errorf("failed to delete ticket: %w", err)
+	}
+
+	if err := requireRowsAffected(result, "ticket "+key); err != nil {
+		return err
+	}
+
+	r.logger.Debug("deleted ticket", "ticket_key", key)
+	return nil
 }
 
 // Update updates an existing ticket in SQLite.
-// This is a placeholder for the actual implementation.
+// Returns ErrNotFound if the ticket is not cached.
 func (r *TicketRepository) Update(ctx context.Context, ticket *domain.Ticket) error {
-	// TODO: Implement SQLite update logic
-	return fmt.Errorf("sqlite.TicketRepository.Update not implemented")
+	args, err := r.ticketArgs(ticket)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE tickets SET
+			project_key = ?,
+			summary = ?,
+			description = ?,
+			status = ?,
+			issue_type = ?,
+			priority = ?,
+			assignee = ?,
+			reporter = ?,
+			labels = ?,
+			custom_fields = ?,
+			created = ?,
+			updated = ?,
+			cached_at = CURRENT_TIMESTAMP
+		WHERE ticket_key = ?
+	`
+
+	exec := executorFor(ctx, r.db)
+
+	// ticketArgs leads with the key; UPDATE needs it last
+	result, err := exec.ExecContext(ctx, query, append(args[1:], args[0])...)
+	if err != nil {
+		r.logger.Error("failed to update ticket",
+			"ticket_key", ticket.Key.String(),
+			"error", err)
+		return fmt.Errorf("failed to update ticket: %w", err)
+	}
+
+	if err := requireRowsAffected(result, "ticket "+ticket.Key.String()); err != nil {
+		return err
+	}
+
+	r.logger.Debug("updated ticket", "ticket_key", ticket.Key.String())
+	return nil
+}
+
+// ticketArgs validates a ticket and converts it to column values in insert order.
+func (r *TicketRepository) ticketArgs(ticket *domain.Ticket) ([]interface{}, error) {
+	if ticket == nil {
+		return nil, fmt.Errorf("%w: ticket cannot be nil", domain.ErrInvalidInput)
+	}
+	if err := ticket.Validate(); err != nil {
+		return nil, err
+	}
+
+	labels := ticket.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	labelsJSON, err := json.Marshal(labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode labels: %w", err)
+	}
+
+	customFields := make(map[string]interface{}, len(ticket.CustomFields))
+	for name, value := range ticket.CustomFields {
+		customFields[name] = value.Raw()
+	}
+	customFieldsJSON, err := json.Marshal(customFields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode custom fields: %w", err)
+	}
+
+	sealed := []string{ticket.Summary, ticket.Description, string(customFieldsJSON)}
+	for i, value := range sealed {
+		if sealed[i], err = r.cipher.seal(value); err != nil {
+			return nil, fmt.Errorf("failed to encrypt ticket: %w", err)
+		}
+	}
+
+	return []interface{}{
+		ticket.Key.String(),
+		ticket.Key.ProjectKey(),
+		sealed[0],
+		sealed[1],
+		ticket.Status,
+		ticket.IssueType,
+		ticket.Priority,
+		ticket.Assignee,
+		ticket.Reporter,
+		string(labelsJSON),
+		sealed[2],
+		formatTimestamp(ticket.Created),
+		formatTimestamp(ticket.Updated),
+	}, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTicket reads one ticket in ticketColumns order, decrypting sensitive columns with c.
+func scanTicket(row rowScanner, c *Cipher) (*domain.Ticket, error) {
+	var (
+		key, labelsJSON, customFieldsJSON string
+		created, updated                  string
+		ticket                            domain.Ticket
+	)
+
+	if err := row.Scan(
+		&key,
+		&ticket.Summary,
+		&ticket.Description,
+		&ticket.Status,
+		&ticket.IssueType,
+		&ticket.Priority,
+		&ticket.Assignee,
+		&ticket.Reporter,
+		&labelsJSON,
+		&customFieldsJSON,
+		&created,
+		&updated,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := c.openAll(&ticket.Summary, &ticket.Description, &customFieldsJSON); err != nil {
+		return nil, fmt.Errorf("failed to decrypt ticket %s: %w", key, err)
+	}
+
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cached ticket key: %w", err)
+	}
+	ticket.Key = ticketKey
+	ticket.Created = parseTimestamp(created)
+	ticket.Updated = parseTimestamp(updated)
+
+	ticket.Labels = make([]string, 0)
+	if err := json.Unmarshal([]byte(labelsJSON), &ticket.Labels); err != nil {
+		return nil, fmt.Errorf("failed to decode labels of %s: %w", key, err)
+	}
+
+	var customFields map[string]interface{}
+	if err := json.Unmarshal([]byte(customFieldsJSON), &customFields); err != nil {
+		return nil, fmt.Errorf("failed to decode custom fields of %s: %w", key, err)
+	}
+	ticket.CustomFields = make(map[string]domain.FieldValue, len(customFields))
+	for name, value := range customFields {
+		ticket.CustomFields[name] = domain.NewFieldValue(value)
+	}
+
+	return &ticket, nil
+}
+
+// requireRowsAffected returns ErrNotFound when a statement matched no rows.
+func requireRowsAffected(result sql.Result, what string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, what)
+	}
+	return nil
 }
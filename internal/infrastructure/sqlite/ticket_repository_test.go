@@ -0,0 +1,181 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+// newTestTicket creates a valid ticket for repository tests.
+func newTestTicket(t *testing.T, key, summary string) *domain.Ticket {
+	t.Helper()
+
+	ticketKey, err := domain.NewTicketKey(key)
+	if err != nil {
+		t.Fatalf("NewTicketKey(%q) failed: %v", key, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	ticket := domain.NewTicket(ticketKey, summary, now.Add(-time.Hour), now)
+	ticket.Status = "To Do"
+	ticket.IssueType = "Story"
+	return ticket
+}
+
+func TestTicketRepository_SaveAndFindByKey(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTicketRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	ticket := newTestTicket(t, "JMD-1", "Cache tickets locally")
+	ticket.Description = "Store tickets in SQLite"
+	ticket.Assignee = "alice"
+	ticket.Labels = []string{"backend", "cache"}
+	ticket.CustomFields["story_points"] = domain.NewFieldValue(float64(3))
+
+	if err := repo.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := repo.FindByKey(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindByKey failed: %v", err)
+	}
+
+	if got.Key != ticket.Key {
+		t.Errorf("Key: got %s, want %s", got.Key, ticket.Key)
+	}
+	if got.Summary != ticket.Summary || got.Description != ticket.Description {
+		t.Errorf("content mismatch: got %q/%q", got.Summary, got.Description)
+	}
+	if got.Status != "To Do" || got.IssueType != "Story" || got.Assignee != "alice" {
+		t.Errorf("fields mismatch: status %q, type %q, assignee %q", got.Status, got.IssueType, got.Assignee)
+	}
+	if len(got.Labels) != 2 || got.Labels[0] != "backend" || got.Labels[1] != "cache" {
+		t.Errorf("Labels: got %v", got.Labels)
+	}
+	if v := got.CustomFields["story_points"].Raw(); v != float64(3) {
+		t.Errorf("story_points: got %v", v)
+	}
+	if !got.Created.Equal(ticket.Created) || !got.Updated.Equal(ticket.Updated) {
+		t.Errorf("timestamps mismatch: got %v/%v, want %v/%v", got.Created, got.Updated, ticket.Created, ticket.Updated)
+	}
+	if got.ContentHash() != ticket.ContentHash() {
+		t.Error("ContentHash changed after round trip")
+	}
+}
+
+func TestTicketRepository_SaveReplaces(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTicketRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	ticket := newTestTicket(t, "JMD-1", "Original")
+	if err := repo.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	ticket.Summary = "Replaced"
+	if err := repo.Save(ctx, ticket); err != nil {
+		t.Fatalf("second Save failed: %v", err)
+	}
+
+	got, err := repo.FindByKey(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindByKey failed: %v", err)
+	}
+	if got.Summary != "Replaced" {
+		t.Errorf("Summary: got %q, want Replaced", got.Summary)
+	}
+}
+
+func TestTicketRepository_Update(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTicketRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	ticket := newTestTicket(t, "JMD-1", "Update me")
+
+	// Updating a ticket that is not cached fails
+	if err := repo.Update(ctx, ticket); !domain.IsNotFoundError(err) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+
+	if err := repo.Save(ctx, ticket); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	ticket.Status = "In Review"
+	if err := repo.Update(ctx, ticket); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	got, err := repo.FindByKey(ctx, "JMD-1")
+	if err != nil {
+		t.Fatalf("FindByKey failed: %v", err)
+	}
+	if got.Status != "In Review" {
+		t.Errorf("Status: got %q, want In Review", got.Status)
+	}
+}
+
+func TestTicketRepository_FindAllAndDelete(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTicketRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	for _, key := range []string{"JMD-2", "JMD-1"} {
+		if err := repo.Save(ctx, newTestTicket(t, key, "Ticket "+key)); err != nil {
+			t.Fatalf("Save(%s) failed: %v", key, err)
+		}
+	}
+
+	tickets, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(tickets) != 2 || tickets[0].Key.String() != "JMD-1" {
+		t.Fatalf("FindAll: got %d tickets, want JMD-1 first", len(tickets))
+	}
+
+	if err := repo.Delete(ctx, "JMD-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.FindByKey(ctx, "JMD-1"); !domain.IsNotFoundError(err) {
+		t.Errorf("expected ErrNotFound after delete, got: %v", err)
+	}
+	if err := repo.Delete(ctx, "JMD-1"); !domain.IsNotFoundError(err) {
+		t.Errorf("expected ErrNotFound deleting twice, got: %v", err)
+	}
+}
+
+func TestTicketRepository_ValidationErrors(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewTicketRepository(db.DB(), nil)
+	ctx := context.Background()
+
+	if err := repo.Save(ctx, nil); !domain.IsError(err, domain.ErrInvalidInput) {
+		t.Errorf("Save(nil): expected ErrInvalidInput, got: %v", err)
+	}
+
+	invalid := newTestTicket(t, "JMD-1", "")
+	if err := repo.Save(ctx, invalid); !domain.IsError(err, domain.ErrInvalidInput) {
+		t.Errorf("Save without summary: expected ErrInvalidInput, got: %v", err)
+	}
+
+	if _, err := repo.FindByKey(ctx, ""); !domain.IsError(err, domain.ErrEmptyKey) {
+		t.Errorf("FindByKey(\"\"): expected ErrEmptyKey, got: %v", err)
+	}
+}
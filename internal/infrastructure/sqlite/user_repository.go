@@ -0,0 +1,205 @@
+// Package sqlite provides SQLite-based implementations of repository interfaces.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+	"github.com/esfisher/jiramd/internal/domain/repository"
+)
+
+// UserRepository implements repository.UserRepository using SQLite.
+// The directory holds the users jiramd has looked up, usually a few hundred at most, so
+// searches read it whole and match in Go with domain.User.Matches rather than relying
+// on SQLite's ASCII-only case folding.
+type UserRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewUserRepository creates a new SQLite-based user directory repository.
+// The database connection must be initialized and migrations applied before use.
+func NewUserRepository(db *sql.DB, logger *slog.Logger) *UserRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &UserRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Verify that UserRepository implements the repository.UserRepository interface
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+// userColumns lists the columns read by every user query, in scan order.
+const userColumns = `account_id, display_name, email, active, fetched_at`
+
+// SaveUsers inserts or replaces users, in one transaction.
+// Implements repository.UserRepository.SaveUsers.
+func (r *UserRepository) SaveUsers(ctx context.Context, users []*domain.User, fetchedAt time.Time) error {
+	for _, user := range users {
+		if strings.TrimSpace(user.AccountID) == "" {
+			return fmt.Errorf("%w: account ID cannot be empty", domain.ErrEmptyKey)
+		}
+	}
+
+	exec := executorFor(ctx, r.db)
+
+	// Save in transaction if not already in one
+	inTransaction := transactionFromContext(ctx) != nil
+	if !inTransaction {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		exec = tx
+	}
+
+	query := `
+		INSERT INTO users (
+			account_id,
+			display_name,
+			email,
+			active,
+			fetched_at
+		) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(account_id) DO UPDATE SET
+			display_name = excluded.display_name,
+			email = excluded.email,
+			active = excluded.active,
+			fetched_at = excluded.fetched_at
+	`
+	for _, user := range users {
+		_, err := exec.ExecContext(ctx, query,
+			user.AccountID,
+			user.DisplayName,
+			user.Email,
+			user.Active,
+			formatTimestamp(fetchedAt),
+		)
+		if err != nil {
+			r.logger.Error("failed to save user", "account_id", user.AccountID, "error", err)
+			return fmt.Errorf("failed to save user: %w", err)
+		}
+	}
+
+	// Commit if we started the transaction
+	if !inTransaction {
+		if err := exec.(*sql.Tx).Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	r.logger.Debug("saved users", "count", len(users))
+	return nil
+}
+
+// FindUser retrieves a cached user and when it was fetched.
+// Implements repository.UserRepository.FindUser.
+func (r *UserRepository) FindUser(ctx context.Context, accountID string) (*domain.User, time.Time, error) {
+	if strings.TrimSpace(accountID) == "" {
+		return nil, time.Time{}, fmt.Errorf("%w: account ID cannot be empty", domain.ErrEmptyKey)
+	}
+
+	exec := executorFor(ctx, r.db)
+	row := exec.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE account_id = ?`, accountID)
+
+	user, fetchedAt, err := scanUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, fmt.Errorf("%w: user %s", domain.ErrNotFound, accountID)
+	}
+	if err != nil {
+		r.logger.Error("failed to find user", "account_id", accountID, "error", err)
+		return nil, time.Time{}, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, fetchedAt, nil
+}
+
+// SearchUsers retrieves the cached users matching query, ordered by display name.
+// Implements repository.UserRepository.SearchUsers.
+func (r *UserRepository) SearchUsers(ctx context.Context, query string) ([]*domain.User, error) {
+	exec := executorFor(ctx, r.db)
+
+	rows, err := exec.QueryContext(ctx, `SELECT `+userColumns+` FROM users`)
+	if err != nil {
+		r.logger.Error("failed to query users", "error", err)
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*domain.User, 0)
+	for rows.Next() {
+		user, _, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		if user.Matches(query) {
+			users = append(users, user)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		if a, b := strings.ToLower(users[i].DisplayName), strings.ToLower(users[j].DisplayName); a != b {
+			return a < b
+		}
+		return users[i].AccountID < users[j].AccountID
+	})
+	return users, nil
+}
+
+// RecordUserSearch records when query was last searched in Jira.
+// Implements repository.UserRepository.RecordUserSearch.
+func (r *UserRepository) RecordUserSearch(ctx context.Context, query string, searchedAt time.Time) error {
+	exec := executorFor(ctx, r.db)
+
+	_, err := exec.ExecContext(ctx, `
+		INSERT INTO user_searches (query, searched_at) VALUES (?, ?)
+		ON CONFLICT(query) DO UPDATE SET searched_at = excluded.searched_at
+	`, domain.NormalizeUserQuery(query), formatTimestamp(searchedAt))
+	if err != nil {
+		r.logger.Error("failed to record user search", "error", err)
+		return fmt.Errorf("failed to record user search: %w", err)
+	}
+	return nil
+}
+
+// UserSearchedAt returns when query was last searched in Jira, or the zero time.
+// Implements repository.UserRepository.UserSearchedAt.
+func (r *UserRepository) UserSearchedAt(ctx context.Context, query string) (time.Time, error) {
+	exec := executorFor(ctx, r.db)
+
+	var searchedAt string
+	err := exec.QueryRowContext(ctx, `SELECT searched_at FROM user_searches WHERE query = ?`,
+		domain.NormalizeUserQuery(query)).Scan(&searchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query user search: %w", err)
+	}
+	return parseTimestamp(searchedAt), nil
+}
+
+// scanUser reads a row of userColumns.
+func scanUser(row rowScanner) (*domain.User, time.Time, error) {
+	var (
+		user      domain.User
+		fetchedAt string
+	)
+	if err := row.Scan(&user.AccountID, &user.DisplayName, &user.Email, &user.Active, &fetchedAt); err != nil {
+		return nil, time.Time{}, err
+	}
+	return &user, parseTimestamp(fetchedAt), nil
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/esfisher/jiramd/internal/domain"
+)
+
+func TestUserRepository_SaveFindAndSearch(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db.DB(), nil)
+	ctx := context.Background()
+	fetched := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+
+	users := []*domain.User{
+		{AccountID: "u2", DisplayName: "bob Jones", Email: "bob@example.com", Active: true},
+		{AccountID: "u1", DisplayName: "Alice Smith", Email: "alice@example.com", Active: true},
+		{AccountID: "u3", DisplayName: "Alan Former", Active: false},
+	}
+	if err := repo.SaveUsers(ctx, users, fetched); err != nil {
+		t.Fatalf("SaveUsers failed: %v", err)
+	}
+
+	user, fetchedAt, err := repo.FindUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("FindUser failed: %v", err)
+	}
+	if *user != *users[1] || !fetchedAt.Equal(fetched) {
+		t.Errorf("FindUser() = %+v fetched %v, want %+v fetched %v", user, fetchedAt, users[1], fetched)
+	}
+
+	// Saving again replaces the user and its fetch time
+	renamed := &domain.User{AccountID: "u1", DisplayName: "Alice Brown", Email: "alice@example.com", Active: true}
+	if err := repo.SaveUsers(ctx, []*domain.User{renamed}, fetched.Add(time.Hour)); err != nil {
+		t.Fatalf("SaveUsers failed: %v", err)
+	}
+	user, fetchedAt, err = repo.FindUser(ctx, "u1")
+	if err != nil || user.DisplayName != "Alice Brown" || !fetchedAt.Equal(fetched.Add(time.Hour)) {
+		t.Errorf("FindUser() after a save = %+v fetched %v, %v", user, fetchedAt, err)
+	}
+
+	found, err := repo.SearchUsers(ctx, "AL")
+	if err != nil {
+		t.Fatalf("SearchUsers failed: %v", err)
+	}
+	if len(found) != 2 || found[0].AccountID != "u3" || found[1].AccountID != "u1" {
+		t.Errorf("SearchUsers(AL) = %+v, want Alan then Alice", found)
+	}
+	all, err := repo.SearchUsers(ctx, "")
+	if err != nil || len(all) != 3 || all[2].AccountID != "u2" {
+		t.Errorf("SearchUsers(\"\") = %+v, %v, want every user by display name ignoring case", all, err)
+	}
+
+	if _, _, err := repo.FindUser(ctx, "u9"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("FindUser(missing) error = %v, want ErrNotFound", err)
+	}
+	if err := repo.SaveUsers(ctx, []*domain.User{{DisplayName: "No ID"}}, fetched); !errors.Is(err, domain.ErrEmptyKey) {
+		t.Errorf("SaveUsers(empty account ID) error = %v, want ErrEmptyKey", err)
+	}
+}
+
+func TestUserRepository_UserSearches(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewUserRepository(db.DB(), nil)
+	ctx := context.Background()
+	searched := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+
+	at, err := repo.UserSearchedAt(ctx, "ali")
+	if err != nil || !at.IsZero() {
+		t.Errorf("UserSearchedAt() before any search = %v, %v, want zero", at, err)
+	}
+
+	if err := repo.RecordUserSearch(ctx, " Ali ", searched); err != nil {
+		t.Fatalf("RecordUserSearch failed: %v", err)
+	}
+	if err := repo.RecordUserSearch(ctx, "ali", searched.Add(time.Hour)); err != nil {
+		t.Fatalf("RecordUserSearch failed: %v", err)
+	}
+	at, err = repo.UserSearchedAt(ctx, "ALI")
+	if err != nil || !at.Equal(searched.Add(time.Hour)) {
+		t.Errorf("UserSearchedAt() = %v, %v, want the latest search of the normalized query", at, err)
+	}
+}